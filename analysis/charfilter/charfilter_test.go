@@ -0,0 +1,193 @@
+package charfilter
+
+import (
+	"fmt"
+	"github.com/balzaczyy/golucene/analysis/pattern"
+	. "github.com/balzaczyy/golucene/core/analysis"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func readAll(t *testing.T, in io.RuneReader) string {
+	var b strings.Builder
+	for {
+		ch, _, err := in.ReadRune()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		b.WriteRune(ch)
+	}
+	return b.String()
+}
+
+/* Returns the whitespace separated tokens of the filtered input, as "term[start-end]". */
+func tokens(t *testing.T, in io.RuneReader) []string {
+	ts := pattern.NewPatternTokenizer(in, regexp.MustCompile(`\s+`), -1)
+	termAtt := ts.Attributes().Add("CharTermAttribute").(CharTermAttribute)
+	offsetAtt := ts.Attributes().Add("OffsetAttribute").(OffsetAttribute)
+	if err := ts.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	var ans []string
+	for {
+		ok, err := ts.IncrementToken()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			break
+		}
+		ans = append(ans, fmt.Sprintf("%v[%v-%v]", string(termAtt.Buffer()[:termAtt.Length()]),
+			offsetAtt.StartOffset(), offsetAtt.EndOffset()))
+	}
+	if err := ts.End(); err != nil {
+		t.Fatal(err)
+	}
+	if err := ts.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return ans
+}
+
+func TestHTMLStripCharFilter(t *testing.T) {
+	for _, c := range []struct {
+		input, expected string
+	}{
+		{`<a href="#">This</a> is a <b>test</b>`, "This is a test"},
+		{"one<br/>two<BR>three", "one\ntwo\nthree"},
+		{"<p>hello</p><div class='x > y'>world</div>", "\nhello\n\nworld\n"},
+		{"&lt;b&gt; &amp; &AMP; &#65;&#x42;&#X43; caf&eacute; &euro;", "<b> & & ABC café €"},
+		{"&nbsp;&unknown; &amp &#; &#xZZ;", "\u00a0&unknown; &amp &#; &#xZZ;"},
+		{"a<!-- <b>comment</b> -->b", "ab"},
+		{"<!DOCTYPE html><?xml version=\"1.0\"?>text", "text"},
+		{"one<script type=\"text/javascript\">if (a < b) { x = '</p>'; }</script>two", "one\ntwo"},
+		{"one<STYLE>p { color: red }</Style>two", "one\ntwo"},
+		{"<![CDATA[x < y & z]]>", "x < y & z"},
+		{"a < b, c<d and 5<6>", "a < b, c<d and 5<6>"},
+		{"unclosed <b tag", "unclosed <b tag"},
+		{"unterminated <!-- comment", "unterminated <!-- comment"},
+	} {
+		if got := readAll(t, NewHTMLStripCharFilter(strings.NewReader(c.input))); got != c.expected {
+			t.Errorf("%q: expected %q, but was %q", c.input, c.expected, got)
+		}
+	}
+}
+
+func TestHTMLStripCharFilterEscapedTags(t *testing.T) {
+	input := "<a href='#'>link</a> <b>bold</b> <P>para</P>"
+	filter := NewHTMLStripCharFilterWithEscapedTags(strings.NewReader(input), map[string]bool{"B": true, "p": true})
+	if got, expected := readAll(t, filter), "link <b>bold</b> <P>para</P>"; got != expected {
+		t.Errorf("expected %q, but was %q", expected, got)
+	}
+}
+
+func TestHTMLStripCharFilterOffsets(t *testing.T) {
+	for _, c := range []struct {
+		input    string
+		expected []string
+	}{
+		{"<b>hello</b> w&amp;x", []string{"hello[3-12]", "w&x[13-20]"}},
+		{"one<br>two", []string{"one[0-3]", "two[7-10]"}},
+		{"<p>caf&eacute;</p>", []string{"café[3-14]"}},
+		{"x <!-- c --> y", []string{"x[0-1]", "y[13-14]"}},
+	} {
+		got := tokens(t, NewHTMLStripCharFilter(strings.NewReader(c.input)))
+		if fmt.Sprint(got) != fmt.Sprint(c.expected) {
+			t.Errorf("%q: expected %v, but was %v", c.input, c.expected, got)
+		}
+	}
+}
+
+func TestCharFilterChain(t *testing.T) {
+	// the offsets are corrected through both filters
+	input := "<i>aa bb</i>"
+	filter := pattern.NewPatternReplaceCharFilter(regexp.MustCompile(`aa\s+bb`), "aabb",
+		NewHTMLStripCharFilter(strings.NewReader(input)))
+	if got, expected := fmt.Sprint(tokens(t, filter)), "[aabb[3-12]]"; got != expected {
+		t.Errorf("expected %v, but was %v", expected, got)
+	}
+
+	var f CharFilterService = filter
+	if got := f.CorrectOffset(0); got != 3 {
+		t.Errorf("expected 3, but was %v", got)
+	}
+}
+
+func newNormalizeCharMap(t *testing.T) *NormalizeCharMap {
+	builder := NewNormalizeCharMapBuilder()
+	builder.Add("aa", "a")
+	builder.Add("bbb", "b")
+	builder.Add("cccc", "cc")
+	builder.Add("h", "i")
+	builder.Add("j", "jj")
+	builder.Add("k", "kkk")
+	builder.Add("ll", "llll")
+	builder.Add("empty", "")
+	builder.Add("&", "and")
+	normMap, err := builder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return normMap
+}
+
+func TestMappingCharFilter(t *testing.T) {
+	normMap := newNormalizeCharMap(t)
+	for _, c := range []struct {
+		input    string
+		expected []string
+	}{
+		{"x", []string{"x[0-1]"}},
+		{"h", []string{"i[0-1]"}},
+		{"j", []string{"jj[0-1]"}},
+		{"k", []string{"kkk[0-1]"}},
+		{"ll", []string{"llll[0-2]"}},
+		{"aa", []string{"a[0-2]"}},
+		{"bbb", []string{"b[0-3]"}},
+		{"cccc", []string{"cc[0-4]"}},
+		{"empty", nil},
+		{"R&D", []string{"RandD[0-3]"}},
+		{"h i j k ll cccc bbb aa", []string{"i[0-1]", "i[2-3]", "jj[4-5]", "kkk[6-7]",
+			"llll[8-10]", "cc[11-15]", "b[16-19]", "a[20-22]"}},
+	} {
+		got := tokens(t, NewMappingCharFilter(normMap, strings.NewReader(c.input)))
+		if fmt.Sprint(got) != fmt.Sprint(c.expected) {
+			t.Errorf("%q: expected %v, but was %v", c.input, c.expected, got)
+		}
+	}
+
+	// the offsets are corrected through both filters
+	got := tokens(t, NewMappingCharFilter(normMap,
+		NewMappingCharFilter(normMap, strings.NewReader("aaaa ll h"))))
+	if expected := []string{"a[0-4]", "llllllll[5-7]", "i[8-9]"}; fmt.Sprint(got) != fmt.Sprint(expected) {
+		t.Errorf("expected %v, but was %v", expected, got)
+	}
+}
+
+func TestNormalizeCharMapBuilder(t *testing.T) {
+	normMap, err := NewNormalizeCharMapBuilder().Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := readAll(t, NewMappingCharFilter(normMap, strings.NewReader("unchanged"))); got != "unchanged" {
+		t.Errorf("expected unchanged, but was %v", got)
+	}
+
+	for _, match := range []string{"", "a"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected a panic adding %q", match)
+				}
+			}()
+			builder := NewNormalizeCharMapBuilder()
+			builder.Add("a", "b")
+			builder.Add(match, "c")
+		}()
+	}
+}
@@ -0,0 +1,44 @@
+package charfilter
+
+// Names of the character entity references recognized by
+// HTMLStripCharFilter, mapped to the characters they stand for.
+var entities = func() map[string]rune {
+	ans := map[string]rune{
+		"quot": '"', "amp": '&', "apos": '\'', "lt": '<', "gt": '>',
+		"QUOT": '"', "AMP": '&', "LT": '<', "GT": '>',
+
+		"OElig": 0x0152, "oelig": 0x0153, "Scaron": 0x0160, "scaron": 0x0161,
+		"Yuml": 0x0178, "fnof": 0x0192, "circ": 0x02C6, "tilde": 0x02DC,
+		"ensp": 0x2002, "emsp": 0x2003, "thinsp": 0x2009, "zwnj": 0x200C,
+		"zwj": 0x200D, "lrm": 0x200E, "rlm": 0x200F, "ndash": 0x2013,
+		"mdash": 0x2014, "lsquo": 0x2018, "rsquo": 0x2019, "sbquo": 0x201A,
+		"ldquo": 0x201C, "rdquo": 0x201D, "bdquo": 0x201E, "dagger": 0x2020,
+		"Dagger": 0x2021, "bull": 0x2022, "hellip": 0x2026, "permil": 0x2030,
+		"prime": 0x2032, "Prime": 0x2033, "lsaquo": 0x2039, "rsaquo": 0x203A,
+		"oline": 0x203E, "frasl": 0x2044, "euro": 0x20AC, "trade": 0x2122,
+		"larr": 0x2190, "uarr": 0x2191, "rarr": 0x2192, "darr": 0x2193,
+		"harr": 0x2194, "minus": 0x2212, "infin": 0x221E, "ne": 0x2260,
+		"le": 0x2264, "ge": 0x2265,
+	}
+	// the ISO 8859-1 characters, from U+00A0 to U+00FF
+	for i, name := range []string{
+		"nbsp", "iexcl", "cent", "pound", "curren", "yen", "brvbar", "sect",
+		"uml", "copy", "ordf", "laquo", "not", "shy", "reg", "macr",
+		"deg", "plusmn", "sup2", "sup3", "acute", "micro", "para", "middot",
+		"cedil", "sup1", "ordm", "raquo", "frac14", "frac12", "frac34", "iquest",
+		"Agrave", "Aacute", "Acirc", "Atilde", "Auml", "Aring", "AElig", "Ccedil",
+		"Egrave", "Eacute", "Ecirc", "Euml", "Igrave", "Iacute", "Icirc", "Iuml",
+		"ETH", "Ntilde", "Ograve", "Oacute", "Ocirc", "Otilde", "Ouml", "times",
+		"Oslash", "Ugrave", "Uacute", "Ucirc", "Uuml", "Yacute", "THORN", "szlig",
+		"agrave", "aacute", "acirc", "atilde", "auml", "aring", "aelig", "ccedil",
+		"egrave", "eacute", "ecirc", "euml", "igrave", "iacute", "icirc", "iuml",
+		"eth", "ntilde", "ograve", "oacute", "ocirc", "otilde", "ouml", "divide",
+		"oslash", "ugrave", "uacute", "ucirc", "uuml", "yacute", "thorn", "yuml",
+	} {
+		ans[name] = rune(0xA0 + i)
+	}
+	return ans
+}()
+
+// The longest entity name, to bound the look-ahead for ';'.
+const maxEntityNameLength = 8
@@ -0,0 +1,172 @@
+package charfilter
+
+import (
+	"bufio"
+	"fmt"
+	. "github.com/balzaczyy/golucene/analysis/util"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+func init() {
+	RegisterCharFilterFactory("htmlStrip", func(args map[string]string) (CharFilterFactory, error) {
+		f, err := NewHTMLStripCharFilterFactory(args)
+		if err != nil {
+			return nil, err
+		}
+		return f, nil
+	})
+	RegisterCharFilterFactory("mapping", func(args map[string]string) (CharFilterFactory, error) {
+		f, err := NewMappingCharFilterFactory(args)
+		if err != nil {
+			return nil, err
+		}
+		return f, nil
+	})
+}
+
+// charfilter/HTMLStripCharFilterFactory.java
+
+/* Factory for HTMLStripCharFilter, with the optional comma-separated list of "escapedTags". */
+type HTMLStripCharFilterFactory struct {
+	*AbstractAnalysisFactory
+	escapedTags map[string]bool
+}
+
+func NewHTMLStripCharFilterFactory(args map[string]string) (*HTMLStripCharFilterFactory, error) {
+	ans := &HTMLStripCharFilterFactory{AbstractAnalysisFactory: NewAbstractAnalysisFactory(args)}
+	if tags := ans.GetSet("escapedTags"); tags != nil {
+		ans.escapedTags = make(map[string]bool)
+		for _, tag := range tags {
+			ans.escapedTags[tag] = true
+		}
+	}
+	if err := ans.Validate(); err != nil {
+		return nil, err
+	}
+	return ans, nil
+}
+
+func (f *HTMLStripCharFilterFactory) Create(input io.RuneReader) io.RuneReader {
+	return NewHTMLStripCharFilterWithEscapedTags(input, f.escapedTags)
+}
+
+// charfilter/MappingCharFilterFactory.java
+
+var mappingRulePattern = regexp.MustCompile(`"(.*)"\s*=>\s*"(.*)"\s*$`)
+
+/*
+Factory for MappingCharFilter. The "mapping" parameter is a
+comma-separated list of files holding one rule per line, like
+
+	"&" => "and"
+	"é" => "e"
+
+Blank lines and lines starting with "#" are ignored. The strings may
+use the escapes \\, \", \n, \t, \r, \b, \f and \uXXXX.
+*/
+type MappingCharFilterFactory struct {
+	*AbstractAnalysisFactory
+	normMap *NormalizeCharMap
+}
+
+func NewMappingCharFilterFactory(args map[string]string) (*MappingCharFilterFactory, error) {
+	ans := &MappingCharFilterFactory{AbstractAnalysisFactory: NewAbstractAnalysisFactory(args)}
+	files := ans.GetSet("mapping")
+	if err := ans.Validate(); err != nil {
+		return nil, err
+	}
+	builder := NewNormalizeCharMapBuilder()
+	for _, file := range files {
+		if err := parseMappingRules(file, builder); err != nil {
+			return nil, err
+		}
+	}
+	var err error
+	if ans.normMap, err = builder.Build(); err != nil {
+		return nil, err
+	}
+	return ans, nil
+}
+
+func parseMappingRules(file string, builder *NormalizeCharMapBuilder) error {
+	in, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		rule := strings.TrimSpace(scanner.Text())
+		if rule == "" || strings.HasPrefix(rule, "#") {
+			continue
+		}
+		m := mappingRulePattern.FindStringSubmatch(rule)
+		if m == nil {
+			return fmt.Errorf("Invalid Mapping Rule : [%v], file = %v", rule, file)
+		}
+		match, err := parseMappingString(m[1])
+		if err != nil {
+			return fmt.Errorf("Invalid Mapping Rule : [%v], file = %v: %v", rule, file, err)
+		}
+		replacement, err := parseMappingString(m[2])
+		if err != nil {
+			return fmt.Errorf("Invalid Mapping Rule : [%v], file = %v: %v", rule, file, err)
+		}
+		if match == "" {
+			return fmt.Errorf("Invalid Mapping Rule : [%v], file = %v: cannot match the empty string", rule, file)
+		}
+		builder.Add(match, replacement)
+	}
+	return scanner.Err()
+}
+
+func parseMappingString(s string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c != '\\' {
+			b.WriteByte(c)
+			continue
+		}
+		if i++; i >= len(s) {
+			return "", fmt.Errorf("Invalid escaped char in [%v]", s)
+		}
+		switch c = s[i]; c {
+		case '\\':
+			b.WriteByte('\\')
+		case '"':
+			b.WriteByte('"')
+		case 'n':
+			b.WriteByte('\n')
+		case 't':
+			b.WriteByte('\t')
+		case 'r':
+			b.WriteByte('\r')
+		case 'b':
+			b.WriteByte('\b')
+		case 'f':
+			b.WriteByte('\f')
+		case 'u':
+			if i+5 > len(s) {
+				return "", fmt.Errorf("Invalid escaped char in [%v]", s)
+			}
+			ch, err := strconv.ParseUint(s[i+1:i+5], 16, 16)
+			if err != nil {
+				return "", fmt.Errorf("Invalid escaped char in [%v]", s)
+			}
+			b.WriteRune(rune(ch))
+			i += 4
+		default:
+			return "", fmt.Errorf("Invalid escaped char in [%v]", s)
+		}
+	}
+	return b.String(), nil
+}
+
+func (f *MappingCharFilterFactory) Create(input io.RuneReader) io.RuneReader {
+	return NewMappingCharFilter(f.normMap, input)
+}
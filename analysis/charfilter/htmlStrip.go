@@ -0,0 +1,321 @@
+package charfilter
+
+import (
+	. "github.com/balzaczyy/golucene/core/analysis"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// charfilter/HTMLStripCharFilter.java
+
+/* Replacement for the tags of block-level elements, and for script and style elements. */
+const BLOCK_LEVEL_REPLACEMENT = '\n'
+
+// Elements whose tags are replaced with a newline, so that the text
+// on either side of them doesn't run together.
+var blockLevelElements = map[string]bool{
+	"address": true, "article": true, "aside": true, "audio": true,
+	"blockquote": true, "body": true, "br": true, "canvas": true,
+	"caption": true, "center": true, "dd": true, "div": true, "dl": true,
+	"dt": true, "fieldset": true, "figcaption": true, "figure": true,
+	"footer": true, "form": true, "h1": true, "h2": true, "h3": true,
+	"h4": true, "h5": true, "h6": true, "head": true, "header": true,
+	"hgroup": true, "hr": true, "html": true, "li": true, "main": true,
+	"nav": true, "noscript": true, "ol": true, "option": true,
+	"output": true, "p": true, "pre": true, "section": true,
+	"table": true, "tbody": true, "td": true, "tfoot": true, "th": true,
+	"thead": true, "title": true, "tr": true, "ul": true, "video": true,
+}
+
+/*
+A CharFilter that wraps another Reader and attempts to strip out HTML
+constructs:
+
+	- Tags of inline elements are removed; those of block-level
+	  elements and <br> are replaced with a newline.
+	- Comments, processing instructions and declarations such as
+	  <!DOCTYPE> are removed.
+	- <script> and <style> elements are removed along with their
+	  contents, and replaced with a newline.
+	- The contents of CDATA sections are kept, without the markers.
+	- Character entity references, named or numeric, are decoded.
+
+Anything that doesn't parse as markup, like a '<' not followed by a
+tag name or an unknown entity, is passed through unchanged. Offsets
+are corrected to point into the original markup, so that highlights
+can be placed around the matching text.
+*/
+type HTMLStripCharFilter struct {
+	*BaseCharFilter
+	escapedTags map[string]bool
+
+	output []rune
+	pos    int
+	read   bool
+}
+
+/* Creates a new HTMLStripCharFilter over the provided reader. */
+func NewHTMLStripCharFilter(in io.RuneReader) *HTMLStripCharFilter {
+	return NewHTMLStripCharFilterWithEscapedTags(in, nil)
+}
+
+/*
+Creates a new HTMLStripCharFilter over the provided reader, with the
+specified start and end tags left in the output. Tag names are
+matched case-insensitively.
+*/
+func NewHTMLStripCharFilterWithEscapedTags(in io.RuneReader, escapedTags map[string]bool) *HTMLStripCharFilter {
+	ans := &HTMLStripCharFilter{
+		BaseCharFilter: NewBaseCharFilter(in),
+		escapedTags:    make(map[string]bool),
+	}
+	for tag, ok := range escapedTags {
+		if ok {
+			ans.escapedTags[strings.ToLower(tag)] = true
+		}
+	}
+	return ans
+}
+
+func (f *HTMLStripCharFilter) ReadRune() (rune, int, error) {
+	if !f.read {
+		if err := f.fill(); err != nil {
+			return 0, 0, err
+		}
+		f.read = true
+	}
+	if f.pos >= len(f.output) {
+		return 0, 0, io.EOF
+	}
+	ch := f.output[f.pos]
+	f.pos++
+	return ch, 1, nil
+}
+
+func (f *HTMLStripCharFilter) fill() error {
+	var input []rune
+	for {
+		ch, _, err := f.Input.ReadRune()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		input = append(input, ch)
+	}
+	f.strip(input)
+	return nil
+}
+
+func (f *HTMLStripCharFilter) strip(in []rune) {
+	for i := 0; i < len(in); {
+		var n int
+		switch in[i] {
+		case '<':
+			n = f.markup(in, i)
+		case '&':
+			if length, ch, ok := entity(in, i); ok {
+				f.replace(length, ch)
+				n = length
+			}
+		}
+		if n == 0 { // not markup: pass it through
+			f.output = append(f.output, in[i])
+			n = 1
+		}
+		i += n
+	}
+}
+
+/*
+Appends the replacement of inputLength characters of the input,
+recording the offset correction it needs: offsets after the
+replacement point after the replaced input.
+*/
+func (f *HTMLStripCharFilter) replace(inputLength int, replacement ...rune) {
+	f.output = append(f.output, replacement...)
+	if diff := inputLength - len(replacement); diff != 0 {
+		f.AddOffCorrectMap(len(f.output), f.LastCumulativeDiff()+diff)
+	}
+}
+
+/*
+Handles the markup starting with '<' at in[i], and returns the number
+of characters consumed, or 0 if it isn't markup.
+*/
+func (f *HTMLStripCharFilter) markup(in []rune, i int) int {
+	switch {
+	case hasPrefix(in, i, "<!--"):
+		if end := index(in, i+4, "-->"); end >= 0 {
+			f.replace(end + 3 - i)
+			return end + 3 - i
+		}
+		return 0
+	case hasPrefix(in, i, "<![CDATA["):
+		end := index(in, i+9, "]]>")
+		if end < 0 {
+			return 0
+		}
+		f.replace(9)
+		f.output = append(f.output, in[i+9:end]...)
+		f.replace(3)
+		return end + 3 - i
+	case hasPrefix(in, i, "<!") || hasPrefix(in, i, "<?"):
+		if end := index(in, i+2, ">"); end >= 0 {
+			f.replace(end + 1 - i)
+			return end + 1 - i
+		}
+		return 0
+	}
+
+	name, closing, end := parseTag(in, i)
+	if end < 0 {
+		return 0
+	}
+	switch {
+	case f.escapedTags[name]:
+		f.output = append(f.output, in[i:end]...)
+	case !closing && (name == "script" || name == "style"):
+		// remove the contents up to the end tag, or the end of input
+		end = len(in)
+		for j := index(in, i, "</"); j >= 0; j = index(in, j+2, "</") {
+			if endName, endClosing, endTag := parseTag(in, j); endClosing && endName == name && endTag >= 0 {
+				end = endTag
+				break
+			}
+		}
+		f.replace(end-i, BLOCK_LEVEL_REPLACEMENT)
+	case blockLevelElements[name]:
+		f.replace(end-i, BLOCK_LEVEL_REPLACEMENT)
+	default:
+		f.replace(end - i)
+	}
+	return end - i
+}
+
+/*
+Parses the start or end tag at in[i], returning its lowercased name,
+whether it is an end tag, and the offset just after its '>', or -1 if
+there is no tag at in[i].
+*/
+func parseTag(in []rune, i int) (name string, closing bool, end int) {
+	j := i + 1
+	if j < len(in) && in[j] == '/' {
+		closing = true
+		j++
+	}
+	start := j
+	for j < len(in) && (isLetter(in[j]) || j > start && isNameChar(in[j])) {
+		j++
+	}
+	if j == start {
+		return "", false, -1
+	}
+	name = strings.ToLower(string(in[start:j]))
+	// skip attributes, minding quoted values
+	var quote rune
+	for ; j < len(in); j++ {
+		switch ch := in[j]; {
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			}
+		case ch == '"' || ch == '\'':
+			quote = ch
+		case ch == '<':
+			return "", false, -1
+		case ch == '>':
+			return name, closing, j + 1
+		}
+	}
+	return "", false, -1
+}
+
+func isLetter(ch rune) bool {
+	return ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z'
+}
+
+func isNameChar(ch rune) bool {
+	return isLetter(ch) || ch >= '0' && ch <= '9' || ch == ':' || ch == '_' || ch == '-'
+}
+
+/*
+Decodes the character entity reference at in[i], returning its length
+and the character it stands for.
+*/
+func entity(in []rune, i int) (length int, ch rune, ok bool) {
+	j := i + 1
+	if j < len(in) && in[j] == '#' {
+		j++
+		base := 10
+		if j < len(in) && (in[j] == 'x' || in[j] == 'X') {
+			base = 16
+			j++
+		}
+		start := j
+		var value int64
+		for ; j < len(in); j++ {
+			d := digit(in[j], base)
+			if d < 0 {
+				break
+			}
+			if value = value*int64(base) + int64(d); value > unicode.MaxRune {
+				return 0, 0, false
+			}
+		}
+		if j == start || j >= len(in) || in[j] != ';' {
+			return 0, 0, false
+		}
+		ch = rune(value)
+		if ch == 0 || ch >= 0xD800 && ch <= 0xDFFF {
+			return 0, 0, false
+		}
+		return j + 1 - i, ch, true
+	}
+
+	start := j
+	for j < len(in) && j-start < maxEntityNameLength && (isLetter(in[j]) || in[j] >= '0' && in[j] <= '9') {
+		j++
+	}
+	if j >= len(in) || in[j] != ';' {
+		return 0, 0, false
+	}
+	if ch, ok = entities[string(in[start:j])]; !ok {
+		return 0, 0, false
+	}
+	return j + 1 - i, ch, true
+}
+
+func digit(ch rune, base int) int {
+	switch {
+	case ch >= '0' && ch <= '9':
+		return int(ch - '0')
+	case base == 16 && ch >= 'a' && ch <= 'f':
+		return int(ch-'a') + 10
+	case base == 16 && ch >= 'A' && ch <= 'F':
+		return int(ch-'A') + 10
+	}
+	return -1
+}
+
+/* Reports whether in[i:] starts with prefix, ignoring ASCII case. */
+func hasPrefix(in []rune, i int, prefix string) bool {
+	for _, ch := range prefix {
+		if i >= len(in) || unicode.ToLower(in[i]) != unicode.ToLower(ch) {
+			return false
+		}
+		i++
+	}
+	return true
+}
+
+/* Returns the offset of the first occurrence of s in in[from:], or -1. */
+func index(in []rune, from int, s string) int {
+	for i := from; i < len(in); i++ {
+		if hasPrefix(in, i, s) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,129 @@
+package charfilter
+
+import (
+	. "github.com/balzaczyy/golucene/core/analysis"
+	"github.com/balzaczyy/golucene/core/util/fst"
+	"io"
+)
+
+// charfilter/MappingCharFilter.java
+
+/*
+Simplistic CharFilter that applies the mappings contained in a
+NormalizeCharMap to the character stream, and correcting the
+resulting changes to the offsets. Matching is greedy (longest pattern
+matching at a given point wins). Replacement is allowed to be the
+empty string.
+*/
+type MappingCharFilter struct {
+	*BaseCharFilter
+	normMap    *NormalizeCharMap
+	fstReader  fst.BytesReader
+	scratchArc *fst.Arc
+
+	output []rune
+	pos    int
+	read   bool
+}
+
+/* Default constructor that takes a Reader. */
+func NewMappingCharFilter(normMap *NormalizeCharMap, in io.RuneReader) *MappingCharFilter {
+	ans := &MappingCharFilter{
+		BaseCharFilter: NewBaseCharFilter(in),
+		normMap:        normMap,
+	}
+	if normMap.fst != nil {
+		ans.fstReader = normMap.fst.BytesReader()
+		ans.scratchArc = new(fst.Arc)
+	}
+	return ans
+}
+
+func (f *MappingCharFilter) ReadRune() (rune, int, error) {
+	if !f.read {
+		if err := f.fill(); err != nil {
+			return 0, 0, err
+		}
+		f.read = true
+	}
+	if f.pos >= len(f.output) {
+		return 0, 0, io.EOF
+	}
+	ch := f.output[f.pos]
+	f.pos++
+	return ch, 1, nil
+}
+
+func (f *MappingCharFilter) fill() error {
+	var input []rune
+	for {
+		ch, _, err := f.Input.ReadRune()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		input = append(input, ch)
+	}
+	if f.normMap.fst == nil {
+		f.output = input
+		return nil
+	}
+
+	for inputOff := 0; inputOff < len(input); {
+		lastMatchLen, lastMatch, err := f.match(input[inputOff:])
+		if err != nil {
+			return err
+		}
+		if lastMatchLen == 0 {
+			f.output = append(f.output, input[inputOff])
+			inputOff++
+			continue
+		}
+
+		inputOff += lastMatchLen
+		replacement := []rune(string(lastMatch))
+		if diff := lastMatchLen - len(replacement); diff != 0 {
+			prevCumulativeDiff := f.LastCumulativeDiff()
+			if diff > 0 {
+				// Replacement is shorter than matched input:
+				f.AddOffCorrectMap(inputOff-diff-prevCumulativeDiff, prevCumulativeDiff+diff)
+			} else {
+				// Replacement is longer than matched input: remap the
+				// "extra" chars all back to the same input offset:
+				outputStart := inputOff - prevCumulativeDiff
+				for extraIdx := 0; extraIdx < -diff; extraIdx++ {
+					f.AddOffCorrectMap(outputStart+extraIdx, prevCumulativeDiff-extraIdx-1)
+				}
+			}
+		}
+		f.output = append(f.output, replacement...)
+	}
+	return nil
+}
+
+/*
+Finds the longest match starting at input[0], returning its length (0
+if nothing matches) and its replacement.
+*/
+func (f *MappingCharFilter) match(input []rune) (lastMatchLen int, lastMatch []byte, err error) {
+	fst_ := f.normMap.fst
+	outputs := fst_.Outputs()
+	arc := fst_.FirstArc(f.scratchArc)
+	output := outputs.NoOutput()
+	for lookahead, ch := range input {
+		found, err := fst_.FindTargetArc(int(ch), arc, arc, f.fstReader)
+		if err != nil {
+			return 0, nil, err
+		} else if found == nil {
+			break
+		}
+		output = outputs.Add(output, arc.Output)
+		if arc.IsFinal() {
+			lastMatchLen = lookahead + 1
+			// an empty replacement is NoOutput
+			lastMatch, _ = outputs.Add(output, arc.NextFinalOutput).([]byte)
+		}
+	}
+	return
+}
@@ -0,0 +1,89 @@
+package charfilter
+
+import (
+	"fmt"
+	"github.com/balzaczyy/golucene/core/util"
+	"github.com/balzaczyy/golucene/core/util/fst"
+	"github.com/balzaczyy/golucene/core/util/packed"
+	"math"
+	"sort"
+)
+
+// charfilter/NormalizeCharMap.java
+
+/* Holds a map of string input to string output, to be used with MappingCharFilter. Use the NormalizeCharMapBuilder to create this. */
+type NormalizeCharMap struct {
+	// map<input, UTF-8 encoded output>; nil if there is no mapping
+	fst *fst.FST
+}
+
+/*
+Builds a NormalizeCharMap.
+
+Call Add() until you have added all the mappings, then call Build()
+to get a NormalizeCharMap.
+*/
+type NormalizeCharMapBuilder struct {
+	pendingPairs map[string]string
+}
+
+func NewNormalizeCharMapBuilder() *NormalizeCharMapBuilder {
+	return &NormalizeCharMapBuilder{make(map[string]string)}
+}
+
+/*
+Records a replacement to be applied to the input stream. Whenever
+match occurs in the input, it will be replaced with
+replacement.
+
+It panics if match is empty, or was already added.
+*/
+func (b *NormalizeCharMapBuilder) Add(match, replacement string) {
+	if match == "" {
+		panic("cannot match the empty string")
+	}
+	if _, ok := b.pendingPairs[match]; ok {
+		panic(fmt.Sprintf("match \"%v\" was already added", match))
+	}
+	b.pendingPairs[match] = replacement
+}
+
+/* Builds the NormalizeCharMap; call this once you are done calling Add(). */
+func (b *NormalizeCharMapBuilder) Build() (*NormalizeCharMap, error) {
+	if len(b.pendingPairs) == 0 {
+		return &NormalizeCharMap{}, nil
+	}
+
+	outputs := fst.ByteSequenceOutputsSingleton()
+	builder := fst.NewBuilder(fst.INPUT_TYPE_BYTE4, 0, 0, true, true,
+		math.MaxInt32, outputs, false, packed.PackedInts.COMPACT, true, 15)
+	scratch := util.NewIntsRefBuilder()
+
+	// the FST needs its inputs in code point order, which is also
+	// the byte order of UTF-8 strings
+	matches := make([]string, 0, len(b.pendingPairs))
+	for match := range b.pendingPairs {
+		matches = append(matches, match)
+	}
+	sort.Strings(matches)
+
+	for _, match := range matches {
+		scratch.Clear()
+		for _, ch := range match {
+			scratch.Append(int(ch))
+		}
+		output := outputs.NoOutput()
+		if replacement := b.pendingPairs[match]; replacement != "" {
+			output = []byte(replacement)
+		}
+		if err := builder.Add(scratch.Get(), output); err != nil {
+			return nil, err
+		}
+	}
+	f, err := builder.Finish()
+	if err != nil {
+		return nil, err
+	}
+	b.pendingPairs = make(map[string]string)
+	return &NormalizeCharMap{f}, nil
+}
@@ -0,0 +1,56 @@
+package cjk
+
+import (
+	. "github.com/balzaczyy/golucene/analysis/core"
+	std "github.com/balzaczyy/golucene/analysis/standard"
+	. "github.com/balzaczyy/golucene/analysis/util"
+	. "github.com/balzaczyy/golucene/core/analysis"
+	"io"
+)
+
+// cjk/CJKAnalyzer.java
+
+/* The default set of stopwords used by CJKAnalyzer: english words, as CJK text is split into bigrams. */
+var DEFAULT_STOPWORD_SET = map[string]bool{
+	"a": true, "and": true, "are": true, "as": true, "at": true,
+	"be": true, "but": true, "by": true, "for": true, "if": true,
+	"in": true, "into": true, "is": true, "it": true, "no": true,
+	"not": true, "of": true, "on": true, "or": true, "s": true,
+	"such": true, "t": true, "that": true, "the": true, "their": true,
+	"then": true, "there": true, "these": true, "they": true,
+	"this": true, "to": true, "was": true, "will": true, "with": true,
+	"www": true,
+}
+
+/*
+An Analyzer that tokenizes text with StandardTokenizer, normalizes
+content with CJKWidthFilter, folds case with LowerCaseFilter, forms
+bigrams of CJK with CJKBigramFilter, and filters stopwords with
+StopFilter.
+*/
+type CJKAnalyzer struct {
+	*StopwordAnalyzerBase
+}
+
+/* Builds an analyzer which removes words in DEFAULT_STOPWORD_SET. */
+func NewCJKAnalyzer() *CJKAnalyzer {
+	return NewCJKAnalyzerWithStopWords(DEFAULT_STOPWORD_SET)
+}
+
+/* Builds an analyzer with the given stop words. */
+func NewCJKAnalyzerWithStopWords(stopwords map[string]bool) *CJKAnalyzer {
+	ans := &CJKAnalyzer{NewStopwordAnalyzerBaseWithStopWords(stopwords)}
+	ans.Spi = ans
+	return ans
+}
+
+func (a *CJKAnalyzer) CreateComponents(fieldName string, reader io.RuneReader) *TokenStreamComponents {
+	version := a.Version()
+	source := std.NewStandardTokenizer(version, reader)
+	// run the widthfilter first before bigramming, it sometimes combines characters.
+	var result TokenStream = NewCJKWidthFilter(source)
+	result = NewLowerCaseFilter(version, result)
+	result = NewCJKBigramFilter(result)
+	result = NewStopFilter(version, result, a.StopwordSet())
+	return NewTokenStreamComponents(source, result)
+}
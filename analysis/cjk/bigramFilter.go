@@ -0,0 +1,266 @@
+package cjk
+
+import (
+	std "github.com/balzaczyy/golucene/analysis/standard"
+	. "github.com/balzaczyy/golucene/core/analysis"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"github.com/balzaczyy/golucene/core/util"
+)
+
+// cjk/CJKBigramFilter.java
+
+// configuration flags
+const (
+	// bigram flag for Han Ideographs
+	HAN = 1
+	// bigram flag for Hiragana
+	HIRAGANA = 2
+	// bigram flag for Katakana
+	KATAKANA = 4
+	// bigram flag for Hangul
+	HANGUL = 8
+)
+
+const (
+	// when we emit a bigram, it's then marked with this type
+	DOUBLE_TYPE = "<DOUBLE>"
+	// when we emit a unigram, it's then marked with this type
+	SINGLE_TYPE = "<SINGLE>"
+)
+
+/*
+Forms bigrams of CJK terms that are generated from StandardTokenizer
+or ICUTokenizer.
+
+CJK types are set by these tokenizers, but you can also use
+NewCJKBigramFilterWithFlags() to explicitly control which of the CJK
+scripts are turned into bigrams.
+
+By default, when a CJK character has no adjacent characters to form a
+bigram, it is output in unigram form. If you want to always output
+both unigrams and bigrams, set the outputUnigrams flag. This can be
+used for a combined unigram+bigram approach.
+
+In all cases, all non-CJK input is passed thru unmodified.
+*/
+type CJKBigramFilter struct {
+	*TokenFilter
+	input TokenStream
+
+	doHan, doHiragana, doKatakana, doHangul bool
+	outputUnigrams                          bool
+
+	termAtt   CharTermAttribute
+	typeAtt   TypeAttribute
+	offsetAtt OffsetAttribute
+	posIncAtt PositionIncrementAttribute
+	posLenAtt PositionLengthAttribute
+
+	// the run of adjacent CJK characters being buffered
+	buffer                 []rune
+	startOffset, endOffset []int
+	// state of the first token of the run, and its position increment
+	runState  *util.AttributeState
+	runPosInc int
+	// grams of the completed run, ready to be emitted
+	pending []cjkGram
+	// a non-CJK token read past the end of the run
+	loneState *util.AttributeState
+	exhausted bool
+}
+
+type cjkGram struct {
+	state                  *util.AttributeState
+	term                   []rune
+	startOffset, endOffset int
+	posInc                 int
+}
+
+/* Calls NewCJKBigramFilterWithFlags(in, HAN|HIRAGANA|KATAKANA|HANGUL) */
+func NewCJKBigramFilter(in TokenStream) *CJKBigramFilter {
+	return NewCJKBigramFilterWithFlags(in, HAN|HIRAGANA|KATAKANA|HANGUL)
+}
+
+/*
+Create a new CJKBigramFilter, specifying which writing systems should
+be bigrammed, and whether or not unigrams should also be output.
+flags: OR'ed set from HAN, HIRAGANA, KATAKANA, HANGUL
+*/
+func NewCJKBigramFilterWithFlags(in TokenStream, flags int) *CJKBigramFilter {
+	return NewCJKBigramFilterWithUnigrams(in, flags, false)
+}
+
+/*
+Create a new CJKBigramFilter, specifying which writing systems should
+be bigrammed, and whether or not unigrams should also be output.
+*/
+func NewCJKBigramFilterWithUnigrams(in TokenStream, flags int, outputUnigrams bool) *CJKBigramFilter {
+	ans := &CJKBigramFilter{
+		TokenFilter:    NewTokenFilter(in),
+		input:          in,
+		doHan:          flags&HAN != 0,
+		doHiragana:     flags&HIRAGANA != 0,
+		doKatakana:     flags&KATAKANA != 0,
+		doHangul:       flags&HANGUL != 0,
+		outputUnigrams: outputUnigrams,
+	}
+	ans.termAtt = ans.Attributes().Add("CharTermAttribute").(CharTermAttribute)
+	ans.typeAtt = ans.Attributes().Add("TypeAttribute").(TypeAttribute)
+	ans.offsetAtt = ans.Attributes().Add("OffsetAttribute").(OffsetAttribute)
+	ans.posIncAtt = ans.Attributes().Add("PositionIncrementAttribute").(PositionIncrementAttribute)
+	ans.posLenAtt = ans.Attributes().Add("PositionLengthAttribute").(PositionLengthAttribute)
+	return ans
+}
+
+func (f *CJKBigramFilter) IncrementToken() (bool, error) {
+	for {
+		if len(f.pending) > 0 {
+			f.emitGram()
+			return true, nil
+		}
+		if f.loneState != nil {
+			// the non-CJK token that ended the run
+			f.Attributes().RestoreState(f.loneState)
+			f.loneState = nil
+			return true, nil
+		}
+		if f.exhausted {
+			return false, nil
+		}
+
+		ok, err := f.input.IncrementToken()
+		if err != nil {
+			return false, err
+		}
+		if !ok {
+			f.exhausted = true
+			f.flush()
+			continue
+		}
+
+		if !f.isCJK(f.typeAtt.Type()) {
+			// a non-CJK token: emit the buffered run first
+			f.flush()
+			f.loneState = f.Attributes().CaptureState()
+			continue
+		}
+		if len(f.buffer) > 0 && (f.offsetAtt.StartOffset() != f.endOffset[len(f.endOffset)-1] ||
+			f.posIncAtt.PositionIncrement() != 1) {
+			// not adjacent: the run is complete
+			f.flush()
+		}
+		f.refill()
+	}
+}
+
+func (f *CJKBigramFilter) isCJK(typ string) bool {
+	switch typ {
+	case std.TOKEN_TYPES[std.IDEOGRAPHIC]:
+		return f.doHan
+	case std.TOKEN_TYPES[std.HIRAGANA]:
+		return f.doHiragana
+	case std.TOKEN_TYPES[std.KATAKANA]:
+		return f.doKatakana
+	case std.TOKEN_TYPES[std.HANGUL]:
+		return f.doHangul
+	}
+	return false
+}
+
+/* Appends the characters of the current CJK token to the run. */
+func (f *CJKBigramFilter) refill() {
+	if len(f.buffer) == 0 {
+		f.runState = f.Attributes().CaptureState()
+		f.runPosInc = f.posIncAtt.PositionIncrement()
+	}
+	text := f.termAtt.Buffer()[:f.termAtt.Length()]
+	start, end := f.offsetAtt.StartOffset(), f.offsetAtt.EndOffset()
+	// if length by start + end offsets doesn't match the term text
+	// then assume this is a synonym and don't adjust the offsets.
+	hasIllegalOffsets := start+len(text) != end
+	for i, ch := range text {
+		f.buffer = append(f.buffer, ch)
+		if hasIllegalOffsets {
+			f.startOffset = append(f.startOffset, start)
+			f.endOffset = append(f.endOffset, end)
+		} else {
+			f.startOffset = append(f.startOffset, start+i)
+			f.endOffset = append(f.endOffset, start+i+1)
+		}
+	}
+}
+
+/* Turns the buffered run into grams to emit, and empties it. */
+func (f *CJKBigramFilter) flush() {
+	n := len(f.buffer)
+	addGram := func(start, length, posInc int) {
+		f.pending = append(f.pending, cjkGram{
+			state:       f.runState,
+			term:        append([]rune(nil), f.buffer[start:start+length]...),
+			startOffset: f.startOffset[start],
+			endOffset:   f.endOffset[start+length-1],
+			posInc:      posInc,
+		})
+	}
+	switch {
+	case n == 0:
+		return
+	case n == 1:
+		addGram(0, 1, f.runPosInc)
+	case f.outputUnigrams:
+		for i := 0; i < n; i++ {
+			posInc := 1
+			if i == 0 {
+				posInc = f.runPosInc
+			}
+			addGram(i, 1, posInc)
+			if i < n-1 {
+				addGram(i, 2, 0)
+			}
+		}
+	default:
+		for i := 0; i < n-1; i++ {
+			posInc := 1
+			if i == 0 {
+				posInc = f.runPosInc
+			}
+			addGram(i, 2, posInc)
+		}
+	}
+	f.buffer = f.buffer[:0]
+	f.startOffset = f.startOffset[:0]
+	f.endOffset = f.endOffset[:0]
+}
+
+func (f *CJKBigramFilter) emitGram() {
+	gram := f.pending[0]
+	f.pending = f.pending[1:]
+
+	f.Attributes().RestoreState(gram.state)
+	f.termAtt.CopyBuffer(gram.term)
+	f.offsetAtt.SetOffset(gram.startOffset, gram.endOffset)
+	f.posIncAtt.SetPositionIncrement(gram.posInc)
+	if len(gram.term) == 2 {
+		f.typeAtt.SetType(DOUBLE_TYPE)
+		if f.outputUnigrams {
+			f.posLenAtt.SetPositionLength(2)
+		} else {
+			f.posLenAtt.SetPositionLength(1)
+		}
+	} else {
+		f.typeAtt.SetType(SINGLE_TYPE)
+		f.posLenAtt.SetPositionLength(1)
+	}
+}
+
+func (f *CJKBigramFilter) Reset() error {
+	if err := f.TokenFilter.Reset(); err != nil {
+		return err
+	}
+	f.buffer, f.startOffset, f.endOffset = nil, nil, nil
+	f.runState = nil
+	f.pending = nil
+	f.loneState = nil
+	f.exhausted = false
+	return nil
+}
@@ -0,0 +1,76 @@
+package cjk
+
+import (
+	"fmt"
+	std "github.com/balzaczyy/golucene/analysis/standard"
+	. "github.com/balzaczyy/golucene/core/analysis"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"github.com/balzaczyy/golucene/core/util"
+	"strings"
+	"testing"
+)
+
+func tokens(t *testing.T, ts TokenStream) []string {
+	termAtt := ts.Attributes().Add("CharTermAttribute").(CharTermAttribute)
+	offsetAtt := ts.Attributes().Add("OffsetAttribute").(OffsetAttribute)
+	typeAtt := ts.Attributes().Add("TypeAttribute").(TypeAttribute)
+	posIncAtt := ts.Attributes().Add("PositionIncrementAttribute").(PositionIncrementAttribute)
+	if err := ts.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	var ans []string
+	for {
+		ok, err := ts.IncrementToken()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			break
+		}
+		ans = append(ans, fmt.Sprintf("%v/%v-%v/%v/%v", string(termAtt.Buffer()[:termAtt.Length()]),
+			offsetAtt.StartOffset(), offsetAtt.EndOffset(), typeAtt.Type(), posIncAtt.PositionIncrement()))
+	}
+	if err := ts.End(); err != nil {
+		t.Fatal(err)
+	}
+	if err := ts.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return ans
+}
+
+func assertTokens(t *testing.T, ts TokenStream, expected ...string) {
+	if got := tokens(t, ts); fmt.Sprint(got) != fmt.Sprint(expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestWidthFilter(t *testing.T) {
+	ts := NewCJKWidthFilter(std.NewStandardTokenizer(util.VERSION_LATEST, strings.NewReader("Ｔｅｓｔ １２３４ ｶﾞｷﾞｸﾞ ﾊﾟ")))
+	assertTokens(t, ts, "Test/0-4/<ALPHANUM>/1", "1234/5-9/<NUM>/1",
+		"ガギグ/10-16/<KATAKANA>/1", "パ/17-19/<KATAKANA>/1")
+}
+
+func TestBigramFilter(t *testing.T) {
+	ts := NewCJKBigramFilter(std.NewStandardTokenizer(util.VERSION_LATEST, strings.NewReader("多くの学生が試験に落ちた。 abc 一")))
+	assertTokens(t, ts, "多く/0-2/<DOUBLE>/1", "くの/1-3/<DOUBLE>/1", "の学/2-4/<DOUBLE>/1",
+		"学生/3-5/<DOUBLE>/1", "生が/4-6/<DOUBLE>/1", "が試/5-7/<DOUBLE>/1", "試験/6-8/<DOUBLE>/1",
+		"験に/7-9/<DOUBLE>/1", "に落/8-10/<DOUBLE>/1", "落ち/9-11/<DOUBLE>/1", "ちた/10-12/<DOUBLE>/1",
+		"abc/14-17/<ALPHANUM>/1", "一/18-19/<SINGLE>/1")
+
+	ts = NewCJKBigramFilterWithUnigrams(std.NewStandardTokenizer(util.VERSION_LATEST, strings.NewReader("学生が")), HAN, true)
+	assertTokens(t, ts, "学/0-1/<SINGLE>/1", "学生/0-2/<DOUBLE>/0", "生/1-2/<SINGLE>/1",
+		"が/2-3/<HIRAGANA>/1")
+
+	ts = NewCJKBigramFilter(std.NewStandardTokenizer(util.VERSION_LATEST, strings.NewReader("한국어 사전")))
+	assertTokens(t, ts, "한국/0-2/<DOUBLE>/1", "국어/1-3/<DOUBLE>/1", "사전/4-6/<DOUBLE>/1")
+}
+
+func TestCJKAnalyzer(t *testing.T) {
+	ts, err := NewCJKAnalyzer().TokenStreamForString("field", "The ＱＵＩＣＫ 東京都 ｶﾀｶﾅ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertTokens(t, ts, "quick/4-9/<ALPHANUM>/2", "東京/10-12/<DOUBLE>/1", "京都/11-13/<DOUBLE>/1",
+		"カタ/14-16/<DOUBLE>/1", "タカ/15-17/<DOUBLE>/1", "カナ/16-18/<DOUBLE>/1")
+}
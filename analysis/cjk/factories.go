@@ -0,0 +1,73 @@
+package cjk
+
+import (
+	. "github.com/balzaczyy/golucene/analysis/util"
+	. "github.com/balzaczyy/golucene/core/analysis"
+)
+
+func init() {
+	RegisterTokenFilterFactory("cjkBigram", func(args map[string]string) (TokenFilterFactory, error) {
+		f, err := NewCJKBigramFilterFactory(args)
+		if err != nil {
+			return nil, err
+		}
+		return f, nil
+	})
+	RegisterTokenFilterFactory("cjkWidth", func(args map[string]string) (TokenFilterFactory, error) {
+		f, err := NewCJKWidthFilterFactory(args)
+		if err != nil {
+			return nil, err
+		}
+		return f, nil
+	})
+}
+
+// cjk/CJKBigramFilterFactory.java
+
+/*
+Factory for CJKBigramFilter. The scripts to bigram are turned off
+with "han", "hiragana", "katakana" or "hangul" set to false, and
+"outputUnigrams" (false by default) emits the unigrams too.
+*/
+type CJKBigramFilterFactory struct {
+	*AbstractAnalysisFactory
+	flags          int
+	outputUnigrams bool
+}
+
+func NewCJKBigramFilterFactory(args map[string]string) (*CJKBigramFilterFactory, error) {
+	ans := &CJKBigramFilterFactory{AbstractAnalysisFactory: NewAbstractAnalysisFactory(args)}
+	for name, flag := range map[string]int{"han": HAN, "hiragana": HIRAGANA, "katakana": KATAKANA, "hangul": HANGUL} {
+		if ans.GetBool(name, true) {
+			ans.flags |= flag
+		}
+	}
+	ans.outputUnigrams = ans.GetBool("outputUnigrams", false)
+	if err := ans.Validate(); err != nil {
+		return nil, err
+	}
+	return ans, nil
+}
+
+func (f *CJKBigramFilterFactory) Create(input TokenStream) TokenStream {
+	return NewCJKBigramFilterWithUnigrams(input, f.flags, f.outputUnigrams)
+}
+
+// cjk/CJKWidthFilterFactory.java
+
+/* Factory for CJKWidthFilter. */
+type CJKWidthFilterFactory struct {
+	*AbstractAnalysisFactory
+}
+
+func NewCJKWidthFilterFactory(args map[string]string) (*CJKWidthFilterFactory, error) {
+	ans := &CJKWidthFilterFactory{NewAbstractAnalysisFactory(args)}
+	if err := ans.Validate(); err != nil {
+		return nil, err
+	}
+	return ans, nil
+}
+
+func (f *CJKWidthFilterFactory) Create(input TokenStream) TokenStream {
+	return NewCJKWidthFilter(input)
+}
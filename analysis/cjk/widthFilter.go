@@ -0,0 +1,108 @@
+package cjk
+
+import (
+	. "github.com/balzaczyy/golucene/core/analysis"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+)
+
+// cjk/CJKWidthFilter.java
+
+/*
+A TokenFilter that normalizes CJK width differences:
+
+	- Folds fullwidth ASCII variants into the equivalent basic latin
+	- Folds halfwidth Katakana variants into the equivalent kana
+
+NOTE: this filter can be viewed as a (practical) subset of NFKC/NFKD
+Unicode normalization. See the ICU analysis package for full
+normalization.
+*/
+type CJKWidthFilter struct {
+	*TokenFilter
+	input   TokenStream
+	termAtt CharTermAttribute
+}
+
+func NewCJKWidthFilter(in TokenStream) *CJKWidthFilter {
+	ans := &CJKWidthFilter{
+		TokenFilter: NewTokenFilter(in),
+		input:       in,
+	}
+	ans.termAtt = ans.Attributes().Add("CharTermAttribute").(CharTermAttribute)
+	return ans
+}
+
+/*
+halfwidth kana mappings: 0xFF65-0xFF9F
+
+note: 0xFF9E and 0xFF9F are only mapped to 0x3099 and 0x309A as a
+fallback when they cannot properly combine with a preceding
+character into a composed form.
+*/
+var kanaNorm = []rune{
+	0x30fb, 0x30f2, 0x30a1, 0x30a3, 0x30a5, 0x30a7, 0x30a9, 0x30e3, 0x30e5,
+	0x30e7, 0x30c3, 0x30fc, 0x30a2, 0x30a4, 0x30a6, 0x30a8, 0x30aa, 0x30ab,
+	0x30ad, 0x30af, 0x30b1, 0x30b3, 0x30b5, 0x30b7, 0x30b9, 0x30bb, 0x30bd,
+	0x30bf, 0x30c1, 0x30c4, 0x30c6, 0x30c8, 0x30ca, 0x30cb, 0x30cc, 0x30cd,
+	0x30ce, 0x30cf, 0x30d2, 0x30d5, 0x30d8, 0x30db, 0x30de, 0x30df, 0x30e0,
+	0x30e1, 0x30e2, 0x30e4, 0x30e6, 0x30e8, 0x30e9, 0x30ea, 0x30eb, 0x30ec,
+	0x30ed, 0x30ef, 0x30f3, 0x3099, 0x309a,
+}
+
+/* kana combining diffs: the voiced forms of kana */
+var kanaCombineVoiced = map[rune]rune{
+	0x30a6: 0x30f4, 0x30ab: 0x30ac, 0x30ad: 0x30ae, 0x30af: 0x30b0,
+	0x30b1: 0x30b2, 0x30b3: 0x30b4, 0x30b5: 0x30b6, 0x30b7: 0x30b8,
+	0x30b9: 0x30ba, 0x30bb: 0x30bc, 0x30bd: 0x30be, 0x30bf: 0x30c0,
+	0x30c1: 0x30c2, 0x30c4: 0x30c5, 0x30c6: 0x30c7, 0x30c8: 0x30c9,
+	0x30cf: 0x30d0, 0x30d2: 0x30d3, 0x30d5: 0x30d6, 0x30d8: 0x30d9,
+	0x30db: 0x30dc, 0x30ef: 0x30f7, 0x30f0: 0x30f8, 0x30f1: 0x30f9,
+	0x30f2: 0x30fa, 0x30fd: 0x30fe,
+}
+
+/* kana combining diffs: the semi-voiced forms of kana */
+var kanaCombineHalfVoiced = map[rune]rune{
+	0x30cf: 0x30d1, 0x30d2: 0x30d4, 0x30d5: 0x30d7, 0x30d8: 0x30da,
+	0x30db: 0x30dd,
+}
+
+func (f *CJKWidthFilter) IncrementToken() (bool, error) {
+	ok, err := f.input.IncrementToken()
+	if err != nil || !ok {
+		return false, err
+	}
+	text := f.termAtt.Buffer()[:f.termAtt.Length()]
+	length := len(text)
+	for i := 0; i < length; i++ {
+		ch := text[i]
+		if ch >= 0xFF01 && ch <= 0xFF5E {
+			// Fullwidth ASCII variants
+			text[i] = ch - 0xFEE0
+		} else if ch >= 0xFF65 && ch <= 0xFF9F {
+			// Halfwidth Katakana variants
+			if (ch == 0xFF9E || ch == 0xFF9F) && i > 0 && combine(text, i, ch) {
+				copy(text[i:], text[i+1:length])
+				length--
+				i--
+			} else {
+				text[i] = kanaNorm[ch-0xFF65]
+			}
+		}
+	}
+	f.termAtt.SetLength(length)
+	return true, nil
+}
+
+/* returns true if we successfully combined the voice mark */
+func combine(text []rune, pos int, ch rune) bool {
+	prev := text[pos-1]
+	table := kanaCombineVoiced
+	if ch == 0xFF9F {
+		table = kanaCombineHalfVoiced
+	}
+	if combined, ok := table[prev]; ok {
+		text[pos-1] = combined
+		return true
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+package smart
+
+import (
+	. "github.com/balzaczyy/golucene/analysis/core"
+	"github.com/balzaczyy/golucene/analysis/en"
+	. "github.com/balzaczyy/golucene/analysis/util"
+	. "github.com/balzaczyy/golucene/core/analysis"
+	"io"
+)
+
+// cn/smart/SmartChineseAnalyzer.java
+
+/*
+The default set of stopwords used by SmartChineseAnalyzer: the
+punctuation, including COMMON_DELIMITER which replaces punctuation
+in the output of HMMChineseTokenizer.
+*/
+var DEFAULT_STOPWORD_SET = map[string]bool{
+	",": true, ".": true, "`": true, "-": true, "_": true, "=": true,
+	"?": true, "'": true, "|": true, "\"": true, "(": true, ")": true,
+	"{": true, "}": true, "[": true, "]": true, "<": true, ">": true,
+	"*": true, "#": true, "&": true, "^": true, "$": true, "@": true,
+	"!": true, "~": true, ":": true, ";": true, "+": true, "/": true,
+	"\\": true, "《": true, "》": true, "—": true, "－": true, "，": true,
+	"。": true, "、": true, "：": true, "；": true, "！": true, "·": true,
+	"？": true, "“": true, "”": true, "）": true, "（": true, "【": true,
+	"】": true, "［": true, "］": true, "●": true,
+}
+
+/*
+SmartChineseAnalyzer is an analyzer for Chinese or mixed
+Chinese-English text. The analyzer uses probabilistic knowledge to
+find the optimal word segmentation for Simplified Chinese text. The
+text is first broken into sentences, then each sentence is segmented
+into words.
+
+Segmentation is based upon the Hidden Markov Model. A large training
+corpus was used to calculate Chinese word frequency probability.
+
+This analyzer requires a dictionary to provide statistical data.
+GoLucene embeds a small dictionary of frequent words; the characters
+of other words are segmented as single character words.
+
+The included dictionary data is from ICTCLAS1.0. Thanks to ICTCLAS
+for their hard work, and for contributing the data under the Apache
+2 License!
+*/
+type SmartChineseAnalyzer struct {
+	*StopwordAnalyzerBase
+}
+
+/* Create a new SmartChineseAnalyzer, using the default stopword list. */
+func NewSmartChineseAnalyzer() *SmartChineseAnalyzer {
+	return NewSmartChineseAnalyzerWithStopWords(DEFAULT_STOPWORD_SET)
+}
+
+/*
+Create a new SmartChineseAnalyzer, using the provided set of
+stopwords. Note: the set should include punctuation, unless you
+want to index punctuation!
+*/
+func NewSmartChineseAnalyzerWithStopWords(stopwords map[string]bool) *SmartChineseAnalyzer {
+	ans := &SmartChineseAnalyzer{NewStopwordAnalyzerBaseWithStopWords(stopwords)}
+	ans.Spi = ans
+	return ans
+}
+
+func (a *SmartChineseAnalyzer) CreateComponents(fieldName string, reader io.RuneReader) *TokenStreamComponents {
+	tokenizer := NewHMMChineseTokenizer(reader)
+	// The porter stemming is too strict, this is not a bug, this is a feature:)
+	var result TokenStream = en.NewPorter2StemFilter(tokenizer)
+	if len(a.StopwordSet()) > 0 {
+		result = NewStopFilter(a.Version(), result, a.StopwordSet())
+	}
+	return NewTokenStreamComponents(tokenizer, result)
+}
@@ -0,0 +1,74 @@
+package smart
+
+import (
+	"unicode"
+)
+
+// cn/smart/CharType.java
+
+/* Internal SmartChineseAnalyzer character type constants. */
+const (
+	// Punctuation Characters
+	CHAR_DELIMITER = iota
+	// Letters
+	CHAR_LETTER
+	// Numeric Digits
+	CHAR_DIGIT
+	// Han Ideographs
+	CHAR_HANZI
+	// Characters that act as a space
+	CHAR_SPACE_LIKE
+	// Full-Width letters
+	CHAR_FULLWIDTH_LETTER
+	// Full-Width alphanumeric characters
+	CHAR_FULLWIDTH_DIGIT
+	// Other (not fitting any of the other categories)
+	CHAR_OTHER
+)
+
+// cn/smart/WordType.java
+
+/* Internal SmartChineseAnalyzer token type constants. */
+const (
+	// Start of a Sentence
+	WORD_SENTENCE_BEGIN = iota
+	// End of a Sentence
+	WORD_SENTENCE_END
+	// Chinese Word
+	WORD_CHINESE_WORD
+	// ASCII String
+	WORD_STRING
+	// ASCII Alphanumeric
+	WORD_NUMBER
+	// Punctuation Symbol
+	WORD_DELIMITER
+	// Full-Width String
+	WORD_FULLWIDTH_STRING
+	// Full-Width Alphanumeric
+	WORD_FULLWIDTH_NUMBER
+)
+
+// cn/smart/Utility.java
+
+/* Returns the character type of ch, one of the CHAR_* constants. */
+func charType(ch rune) int {
+	switch {
+	case ch >= 0x4E00 && ch <= 0x9FA5, unicode.Is(unicode.Han, ch):
+		return CHAR_HANZI
+	case ch >= 'a' && ch <= 'z', ch >= 'A' && ch <= 'Z':
+		return CHAR_LETTER
+	case ch >= '0' && ch <= '9':
+		return CHAR_DIGIT
+	case ch == ' ', ch == '\t', ch == '\r', ch == '\n', ch == '　':
+		return CHAR_SPACE_LIKE
+	case ch >= 0xFF10 && ch <= 0xFF19:
+		return CHAR_FULLWIDTH_DIGIT
+	case ch >= 0xFF21 && ch <= 0xFF3A, ch >= 0xFF41 && ch <= 0xFF5A:
+		return CHAR_FULLWIDTH_LETTER
+	case unicode.IsPunct(ch), unicode.IsSymbol(ch):
+		return CHAR_DELIMITER
+	case unicode.IsSpace(ch):
+		return CHAR_SPACE_LIKE
+	}
+	return CHAR_OTHER
+}
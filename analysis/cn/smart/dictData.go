@@ -0,0 +1,86 @@
+package smart
+
+// cn/smart/hhmm/coredict.mem
+
+/*
+Frequencies of the words of the built-in dictionary, as pairs of a
+word and its count in the training corpus. The special entries
+始##始 and 末##末 stand for the beginning and end of a sentence,
+未##串 for ASCII strings, 未##数 for numbers and 未##标 for
+punctuation.
+*/
+const coreWords = `
+始##始 50610 末##末 50610 未##串 4086 未##数 21813 未##标 90000
+
+的 318825 了 98765 是 72830 在 61932 和 51220 有 43506 我 39282
+不 38190 这 30288 人 29015 中 28350 他 27650 为 27012 上 25880
+个 24501 国 22530 们 22103 也 21567 到 20988 说 19877 就 19450
+大 18990 要 18561 以 18230 地 17900 对 17411 会 16890 你 16512
+年 16200 出 15840 将 15303 着 14980 与 14620 而 14388 可 13920
+生 13702 都 13540 来 13210 等 12980 时 12820 被 12660 所 12300
+她 11950 还 11830 之 11600 于 11420 把 10980 又 10530 从 10450
+得 10010 很 9887 去 9754 过 9630 下 9512 后 9433 能 9320 使 9100
+自 8960 并 8854 多 8700 月 8650 日 8600 家 8400 新 8100
+里 7900 天 7800 给 7650 让 7512 向 7400 或 7100 只 6980
+最 6850 比 6720 才 6600 呢 6400 吗 6300 吧 6200 啊 6000 该 5900
+其 5800 此 5700 些 5600 每 5500 各 5400
+小 6500 学 6400 水 5000 心 4900 手 4800 事 4700 文 4600 工 4500
+方 4400 面 4300 作 4200 发 4100 长 4050 用 4000 开 3950 动 3900
+看 3850 想 3800 做 3750 买 3600 卖 3200 书 3100 车 3050 路 3000
+走 2950 吃 2900 喝 2600 写 2550 读 2500 听 2450 问 2400 见 2350
+装 1000 命 300 服 800 民 900 共 700 华 600 研 200 究 200 源 150
+道 2700 具 400 京 500 北 800 海 1200 江 900 河 850 山 1100 花 950
+美 1500 好 7000 高 5200 老 3400 少 3300 早 2100 晚 1900
+
+我们 17800 你们 4300 他们 14200 她们 1800 它们 2300 自己 9800
+大家 4100 什么 6400 怎么 3900 这个 6100 那个 2800 这些 4000
+那些 1900 这样 4800 那样 1300 因为 5600 所以 5200 但是 6000
+如果 4700 虽然 2000 而且 3300 或者 2500 然后 2200 已经 8400
+可以 9100 应该 3600 需要 4200 能够 2400 正在 3100 现在 6200
+今天 3100 明天 1900 昨天 1500 时候 4600 时间 4100 以后 2900
+以前 2100 之后 2600 之前 1800 今年 3000 去年 2500 明年 1300
+中国 21500 中国人 3100 中华 1200 人民 11000 共和国 1600
+中华人民共和国 1900 国家 9800 政府 6400 社会 7200 经济 9900
+发展 11800 建设 6600 工作 10200 问题 8900 情况 5200 方面 5800
+世界 5900 北京 5400 上海 3900 城市 3100 地区 4100 历史 2700
+文化 4200 教育 3300 科学 3700 技术 5100 研究 5000 研究生 500
+学生 3900 学校 3600 大学 3900 老师 1900 先生 2100 朋友 2200
+生命 1500 生活 4800 起源 400 生产 5600 生物 900 命运 700
+道具 300 和服 50 服装 500 服务 3800 衣服 700 购买 1400
+商品 1700 市场 5900 公司 7100 企业 6900 产品 3800 价格 2900
+计算机 1700 电脑 1100 软件 1500 网络 2700 信息 3900 数据 2100
+系统 3800 程序 1300 语言 1500 汉语 600 中文 900 英语 700
+分词 120 词典 300 搜索 800 引擎 400 索引 200 文本 500 文档 400
+分析 3500 处理 2700 方法 3200 结果 3400 模型 800
+电话 900 手机 1100 电视 1400 报纸 600 新闻 1800 记者 2000
+银行 2100 医院 1300 医生 900 病人 500 身体 1200 健康 900
+天气 500 下雨 200 阳光 300 空气 500 环境 2900 保护 2800
+美国 4300 日本 2600 英国 1300 法国 900 德国 900 俄罗斯 700
+香港 1700 台湾 1500 广州 900 深圳 800 南京 700 长江 900
+长江大桥 100 大桥 300 市长 700 南京市 300 南京市长 30
+喜欢 1500 希望 3000 认为 4800 知道 3900 觉得 2200 表示 7200
+进行 9600 提高 4100 加强 3900 实现 3700 成为 4000 开始 4400
+继续 2600 完成 2500 参加 2300 举行 2800 通过 5600 关于 2600
+根据 2900 由于 2900 为了 3300 对于 2300 作为 3200 以及 3400
+重要 4900 主要 4500 一般 1600 特别 2100 非常 2300 比较 1800
+一些 4000 一个 9800 一种 2600 一样 1300 一起 2000 一直 1700
+没有 10600 不是 5200 就是 4800 还是 3100 只是 1400 也是 1500
+东西 1100 地方 2800 事情 1900 国际 5600 全国 4400 各种 2200
+吃饭 500 睡觉 400 学习 3700 工人 1100 农民 1600 人们 3800
+孩子 2700 父亲 1200 母亲 1400 家庭 1500 家里 800 女人 700
+男人 600 公园 400 汽车 1500 火车 700 飞机 900 自行车 300
+`
+
+// cn/smart/hhmm/bigramdict.mem
+
+/*
+Frequencies of adjacent word pairs, written as the two words joined
+by '@', and their count in the training corpus.
+*/
+const coreBigrams = `
+始##始@我 3400 始##始@他 2800 始##始@中国 900 我@是 1900
+我@购买 40 购买@了 60 和@服装 30 了@道具 5 研究@生命 20 生命@起源 40
+我@喜欢 300 是@中国人 120 的@研究 300 在@北京 400 在@中国 900
+中国@的 2700 人民@的 1900 发展@的 1800 了@一个 1500 是@一个 1300
+未##标@末##末 38000 未##串@未##串 200 未##数@年 900
+`
@@ -0,0 +1,85 @@
+package smart
+
+import (
+	"strconv"
+	"strings"
+)
+
+// cn/smart/hhmm/AbstractDictionary.java
+
+const (
+	// Maximum frequency of the words of the dictionary
+	MAX_FREQUENCE = 2079997 + 80000
+
+	// the special entries of the dictionary
+	SENTENCE_BEGIN = "始##始"
+	SENTENCE_END   = "末##末"
+	STRING_WORD    = "未##串"
+	NUMBER_WORD    = "未##数"
+	DELIMITER_WORD = "未##标"
+
+	// the delimiter of the two words of a bigram
+	WORD_SEGMENT_CHAR = '@'
+	// the delimiter emitted in place of punctuation
+	COMMON_DELIMITER = ","
+)
+
+// cn/smart/hhmm/WordDictionary.java
+
+/*
+WordDictionary holds the frequency of every word known to the
+segmenter, and the frequency of the known pairs of adjacent words
+(the BigramDictionary of the original implementation).
+*/
+type WordDictionary struct {
+	words   map[string]int
+	bigrams map[string]int
+	// length in runes of the longest word
+	maxLength int
+}
+
+/* Returns the dictionary built from the embedded data. */
+func newWordDictionary(words, bigrams string) *WordDictionary {
+	ans := &WordDictionary{
+		words:   make(map[string]int),
+		bigrams: make(map[string]int),
+	}
+	parseFrequencies(words, func(word string, freq int) {
+		ans.words[word] = freq
+		if n := len([]rune(word)); n > ans.maxLength {
+			ans.maxLength = n
+		}
+	})
+	parseFrequencies(bigrams, func(pair string, freq int) {
+		ans.bigrams[pair] = freq
+	})
+	return ans
+}
+
+func parseFrequencies(data string, f func(string, int)) {
+	fields := strings.Fields(data)
+	assert2(len(fields)%2 == 0, "odd number of dictionary fields")
+	for i := 0; i < len(fields); i += 2 {
+		freq, err := strconv.Atoi(fields[i+1])
+		assert2(err == nil, "invalid frequency of %v: %v", fields[i], err)
+		f(fields[i], freq)
+	}
+}
+
+/* Returns the frequency of the word, or 0 if it is unknown. */
+func (d *WordDictionary) Frequency(word string) int {
+	return d.words[word]
+}
+
+/* Returns true if the word is in the dictionary. */
+func (d *WordDictionary) Contains(word string) bool {
+	_, ok := d.words[word]
+	return ok
+}
+
+/* Returns the frequency of the word pair, or 0 if it is unknown. */
+func (d *WordDictionary) BigramFrequency(left, right string) int {
+	return d.bigrams[left+string(WORD_SEGMENT_CHAR)+right]
+}
+
+var coreDictionary = newWordDictionary(coreWords, coreBigrams)
@@ -0,0 +1,252 @@
+package smart
+
+import (
+	"fmt"
+	"math"
+	"unicode"
+)
+
+// cn/smart/hhmm/SegToken.java
+
+/* SegToken is a word of the sentence, as a node of the segmentation graph. */
+type SegToken struct {
+	// Characters of the word
+	CharArray []rune
+	// start and end offsets of the word in the sentence
+	StartOffset, EndOffset int
+	// one of the WORD_* constants
+	WordType int
+	// frequency of the word in the dictionary
+	Weight int
+}
+
+/* Returns the name of the word in the dictionary. */
+func (t *SegToken) key() string {
+	switch t.WordType {
+	case WORD_SENTENCE_BEGIN:
+		return SENTENCE_BEGIN
+	case WORD_SENTENCE_END:
+		return SENTENCE_END
+	case WORD_STRING, WORD_FULLWIDTH_STRING:
+		return STRING_WORD
+	case WORD_NUMBER, WORD_FULLWIDTH_NUMBER:
+		return NUMBER_WORD
+	case WORD_DELIMITER:
+		return DELIMITER_WORD
+	}
+	return string(t.CharArray)
+}
+
+func (t *SegToken) String() string {
+	return fmt.Sprintf("%v[%v-%v]", string(t.CharArray), t.StartOffset, t.EndOffset)
+}
+
+// cn/smart/hhmm/HHMMSegmenter.java
+
+// weight of the unigram frequency when smoothing the bigram probability
+const smoothing = 0.1
+
+// probability of a word pair that was never seen
+const tinyProbability = 1.0 / MAX_FREQUENCE
+
+/*
+Finds the optimal segmentation of a sentence into Chinese words.
+
+All the words of the dictionary found in the sentence, and the runs
+of letters, digits and punctuation, form a graph of the possible
+segmentations. The segmenter picks the path that maximizes the
+product of the probabilities of adjacent word pairs, smoothed with
+the unigram frequencies, as in a first order Hidden Markov Model.
+*/
+type HHMMSegmenter struct {
+	dict *WordDictionary
+}
+
+/* Returns a segmenter using the built-in dictionary. */
+func NewHHMMSegmenter() *HHMMSegmenter {
+	return &HHMMSegmenter{coreDictionary}
+}
+
+/*
+Returns the words of the sentence, in order, with their offsets
+relative to the sentence. Spaces are not part of any word.
+*/
+func (s *HHMMSegmenter) Process(sentence []rune) []*SegToken {
+	graph := s.createSegGraph(sentence)
+	return s.shortestPath(graph, len(sentence))
+}
+
+/*
+Builds the graph of the sentence: graph[i] holds the words that
+start at offset i.
+*/
+func (s *HHMMSegmenter) createSegGraph(sentence []rune) [][]*SegToken {
+	graph := make([][]*SegToken, len(sentence))
+	for i := 0; i < len(sentence); {
+		typ := charType(sentence[i])
+		switch typ {
+		case CHAR_SPACE_LIKE:
+			i++
+		case CHAR_HANZI:
+			// the character itself, and every dictionary word starting with it
+			single := string(sentence[i])
+			graph[i] = append(graph[i], &SegToken{sentence[i : i+1], i, i + 1,
+				WORD_CHINESE_WORD, s.dict.Frequency(single)})
+			for j := i + 2; j <= len(sentence) && j-i <= s.dict.maxLength; j++ {
+				if charType(sentence[j-1]) != CHAR_HANZI {
+					break
+				}
+				if word := string(sentence[i:j]); s.dict.Contains(word) {
+					graph[i] = append(graph[i], &SegToken{sentence[i:j], i, j,
+						WORD_CHINESE_WORD, s.dict.Frequency(word)})
+				}
+			}
+			i++
+		case CHAR_LETTER, CHAR_DIGIT, CHAR_FULLWIDTH_LETTER, CHAR_FULLWIDTH_DIGIT:
+			// a run of letters and digits is a single word
+			j := i + 1
+			for j < len(sentence) && isAlphanumeric(charType(sentence[j])) {
+				j++
+			}
+			token := &SegToken{CharArray: sentence[i:j], StartOffset: i, EndOffset: j}
+			token.WordType = alphanumericType(sentence[i:j])
+			token.Weight = s.dict.Frequency(token.key())
+			graph[i] = append(graph[i], token)
+			i = j
+		case CHAR_DELIMITER:
+			graph[i] = append(graph[i], &SegToken{sentence[i : i+1], i, i + 1,
+				WORD_DELIMITER, s.dict.Frequency(DELIMITER_WORD)})
+			i++
+		default:
+			graph[i] = append(graph[i], &SegToken{sentence[i : i+1], i, i + 1,
+				WORD_STRING, s.dict.Frequency(STRING_WORD)})
+			i++
+		}
+	}
+	return graph
+}
+
+func isAlphanumeric(typ int) bool {
+	return typ == CHAR_LETTER || typ == CHAR_DIGIT ||
+		typ == CHAR_FULLWIDTH_LETTER || typ == CHAR_FULLWIDTH_DIGIT
+}
+
+/* Returns the word type of a run of letters and digits. */
+func alphanumericType(run []rune) int {
+	number, fullwidth := true, false
+	for _, ch := range run {
+		switch charType(ch) {
+		case CHAR_LETTER:
+			number = false
+		case CHAR_FULLWIDTH_LETTER:
+			number, fullwidth = false, true
+		case CHAR_FULLWIDTH_DIGIT:
+			fullwidth = true
+		}
+	}
+	switch {
+	case number && fullwidth:
+		return WORD_FULLWIDTH_NUMBER
+	case number:
+		return WORD_NUMBER
+	case fullwidth:
+		return WORD_FULLWIDTH_STRING
+	}
+	return WORD_STRING
+}
+
+/* Returns the cost of the transition from the word left to the word right. */
+func (s *HHMMSegmenter) weight(left, right *SegToken) float64 {
+	oneWordFreq := float64(left.Weight)
+	wordPairFreq := float64(s.dict.BigramFrequency(left.key(), right.key()))
+	return -math.Log(smoothing*(1.0+oneWordFreq)/MAX_FREQUENCE +
+		(1.0-smoothing)*((1.0-tinyProbability)*wordPairFreq/(1.0+oneWordFreq)+tinyProbability))
+}
+
+/* Returns the least cost path through the graph of a sentence of length n. */
+func (s *HHMMSegmenter) shortestPath(graph [][]*SegToken, n int) []*SegToken {
+	begin := &SegToken{WordType: WORD_SENTENCE_BEGIN, Weight: s.dict.Frequency(SENTENCE_BEGIN)}
+	end := &SegToken{StartOffset: n, EndOffset: n, WordType: WORD_SENTENCE_END}
+
+	// the best path reaching each word, keyed by the word
+	type node struct {
+		cost float64
+		prev *SegToken
+	}
+	best := map[*SegToken]*node{begin: {}}
+
+	// the words ending at each offset, from which the words starting
+	// at the next non-space offset are reached
+	ending := make([][]*SegToken, n+1)
+	ending[0] = []*SegToken{begin}
+	var pending []*SegToken
+	for i := 0; i <= n; i++ {
+		pending = append(pending, ending[i]...)
+		var starting []*SegToken
+		if i == n {
+			starting = []*SegToken{end}
+		} else {
+			starting = graph[i]
+		}
+		if len(starting) == 0 {
+			continue // inside a space or a multi-character word
+		}
+		for _, token := range starting {
+			for _, prev := range pending {
+				cost := best[prev].cost + s.weight(prev, token)
+				if b, ok := best[token]; !ok || cost < b.cost {
+					best[token] = &node{cost, prev}
+				}
+			}
+			if token != end {
+				ending[token.EndOffset] = append(ending[token.EndOffset], token)
+			}
+		}
+		pending = nil
+	}
+
+	var path []*SegToken
+	for token := best[end].prev; token != begin; token = best[token].prev {
+		path = append(path, token)
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// cn/smart/hhmm/SegTokenFilter.java
+
+/*
+Normalizes a word for indexing: full-width letters and digits are
+converted to half-width, letters are lower cased, and punctuation is
+replaced by COMMON_DELIMITER.
+*/
+func (s *HHMMSegmenter) convert(token *SegToken) []rune {
+	switch token.WordType {
+	case WORD_FULLWIDTH_NUMBER, WORD_FULLWIDTH_STRING:
+		ans := make([]rune, len(token.CharArray))
+		for i, ch := range token.CharArray {
+			if ch >= 0xFF01 && ch <= 0xFF5E {
+				ch -= 0xFEE0
+			}
+			ans[i] = unicode.ToLower(ch)
+		}
+		return ans
+	case WORD_STRING, WORD_NUMBER:
+		ans := make([]rune, len(token.CharArray))
+		for i, ch := range token.CharArray {
+			ans[i] = unicode.ToLower(ch)
+		}
+		return ans
+	case WORD_DELIMITER:
+		return []rune(COMMON_DELIMITER)
+	}
+	return token.CharArray
+}
+
+func assert2(ok bool, msg string, args ...interface{}) {
+	if !ok {
+		panic(fmt.Sprintf(msg, args...))
+	}
+}
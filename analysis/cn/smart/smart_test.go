@@ -0,0 +1,89 @@
+package smart
+
+import (
+	"fmt"
+	. "github.com/balzaczyy/golucene/core/analysis"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"strings"
+	"testing"
+)
+
+func tokens(t *testing.T, ts TokenStream) []string {
+	termAtt := ts.Attributes().Add("CharTermAttribute").(CharTermAttribute)
+	offsetAtt := ts.Attributes().Add("OffsetAttribute").(OffsetAttribute)
+	if err := ts.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	var ans []string
+	for {
+		ok, err := ts.IncrementToken()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			break
+		}
+		ans = append(ans, fmt.Sprintf("%v[%v-%v]", string(termAtt.Buffer()[:termAtt.Length()]),
+			offsetAtt.StartOffset(), offsetAtt.EndOffset()))
+	}
+	if err := ts.End(); err != nil {
+		t.Fatal(err)
+	}
+	if err := ts.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return ans
+}
+
+func assertTokens(t *testing.T, ts TokenStream, expected ...string) {
+	if got := tokens(t, ts); fmt.Sprint(got) != fmt.Sprint(expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func segment(text string) string {
+	var words []string
+	for _, token := range NewHHMMSegmenter().Process([]rune(text)) {
+		words = append(words, string(token.CharArray))
+	}
+	return strings.Join(words, " ")
+}
+
+func TestSegmenter(t *testing.T) {
+	for text, expected := range map[string]string{
+		"我购买了道具和服装。":   "我 购买 了 道具 和 服装 。",
+		"研究生命起源":       "研究 生命 起源",
+		"我是中国人":        "我 是 中国人",
+		"中华人民共和国":      "中华人民共和国",
+		"他们在北京学习汉语":    "他们 在 北京 学习 汉语",
+		"我喜欢Lucene和搜索": "我 喜欢 Lucene 和 搜索",
+		"2008年 奥运":     "2008 年 奥 运",
+	} {
+		if got := segment(text); got != expected {
+			t.Errorf("%v: expected %v, got %v", text, expected, got)
+		}
+	}
+}
+
+func TestTokenizer(t *testing.T) {
+	assertTokens(t, NewHMMChineseTokenizer(strings.NewReader("我购买了道具和服装。")),
+		"我[0-1]", "购买[1-3]", "了[3-4]", "道具[4-6]", "和[6-7]", "服装[7-9]", ",[9-10]")
+	// full-width letters and digits are folded, and sentences segmented apart
+	assertTokens(t, NewHMMChineseTokenizer(strings.NewReader("我是中国人！Ｔｅｓｔ １２３")),
+		"我[0-1]", "是[1-2]", "中国人[2-5]", ",[5-6]", "test[6-10]", "123[11-14]")
+}
+
+func TestSmartChineseAnalyzer(t *testing.T) {
+	ts, err := NewSmartChineseAnalyzer().TokenStreamForString("field", "我购买了道具和服装。")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertTokens(t, ts, "我[0-1]", "购买[1-3]", "了[3-4]", "道具[4-6]", "和[6-7]", "服装[7-9]")
+
+	ts, err = NewSmartChineseAnalyzer().TokenStreamForString("field", "我购买 Tests 了道具和服装")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertTokens(t, ts, "我[0-1]", "购买[1-3]", "test[4-9]", "了[10-11]",
+		"道具[11-13]", "和[13-14]", "服装[14-16]")
+}
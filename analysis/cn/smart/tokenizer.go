@@ -0,0 +1,116 @@
+package smart
+
+import (
+	. "github.com/balzaczyy/golucene/core/analysis"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"io"
+)
+
+// cn/smart/HMMChineseTokenizer.java
+
+/*
+Tokenizer for Chinese or mixed Chinese-English text.
+
+The text is first broken into sentences, and each sentence is then
+segmented into words with HHMMSegmenter. Letters are lower cased,
+full-width letters and digits are converted to half-width, and
+punctuation is emitted as COMMON_DELIMITER, so that it can be
+removed with a stop filter.
+*/
+type HMMChineseTokenizer struct {
+	*Tokenizer
+	segmenter *HHMMSegmenter
+
+	// the whole input, read on the first token
+	text []rune
+	read bool
+	// the words still to be emitted, with offsets in the text
+	pending []*SegToken
+
+	termAtt   CharTermAttribute
+	offsetAtt OffsetAttribute
+	typeAtt   TypeAttribute
+}
+
+func NewHMMChineseTokenizer(input io.RuneReader) *HMMChineseTokenizer {
+	ans := &HMMChineseTokenizer{
+		Tokenizer: NewTokenizer(input),
+		segmenter: NewHHMMSegmenter(),
+	}
+	ans.termAtt = ans.Attributes().Add("CharTermAttribute").(CharTermAttribute)
+	ans.offsetAtt = ans.Attributes().Add("OffsetAttribute").(OffsetAttribute)
+	ans.typeAtt = ans.Attributes().Add("TypeAttribute").(TypeAttribute)
+	return ans
+}
+
+func (t *HMMChineseTokenizer) IncrementToken() (bool, error) {
+	t.Attributes().Clear()
+	if !t.read {
+		if err := t.readInput(); err != nil {
+			return false, err
+		}
+	}
+	if len(t.pending) == 0 {
+		return false, nil
+	}
+
+	token := t.pending[0]
+	t.pending = t.pending[1:]
+	t.termAtt.CopyBuffer(t.segmenter.convert(token))
+	t.offsetAtt.SetOffset(t.CorrectOffset(token.StartOffset), t.CorrectOffset(token.EndOffset))
+	t.typeAtt.SetType("word")
+	return true, nil
+}
+
+func (t *HMMChineseTokenizer) readInput() error {
+	for {
+		ch, _, err := t.Input.ReadRune()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		t.text = append(t.text, ch)
+	}
+	t.read = true
+
+	start := 0
+	for i, ch := range t.text {
+		if isSentenceEnd(ch) || i == len(t.text)-1 {
+			for _, token := range t.segmenter.Process(t.text[start : i+1]) {
+				token.StartOffset += start
+				token.EndOffset += start
+				t.pending = append(t.pending, token)
+			}
+			start = i + 1
+		}
+	}
+	return nil
+}
+
+/* Returns true if ch ends a sentence. */
+func isSentenceEnd(ch rune) bool {
+	switch ch {
+	case '。', '！', '？', '!', '?', '；', ';', '\n', '…':
+		return true
+	}
+	return false
+}
+
+func (t *HMMChineseTokenizer) End() error {
+	if err := t.Tokenizer.End(); err != nil {
+		return err
+	}
+	// set final offset
+	finalOffset := t.CorrectOffset(len(t.text))
+	t.offsetAtt.SetOffset(finalOffset, finalOffset)
+	return nil
+}
+
+func (t *HMMChineseTokenizer) Reset() error {
+	if err := t.Tokenizer.Reset(); err != nil {
+		return err
+	}
+	t.text, t.read, t.pending = nil, false, nil
+	return nil
+}
@@ -0,0 +1,201 @@
+package compound
+
+import (
+	. "github.com/balzaczyy/golucene/analysis/util"
+	. "github.com/balzaczyy/golucene/core/analysis"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"github.com/balzaczyy/golucene/core/util"
+)
+
+// compound/CompoundWordTokenFilterBase.java
+
+const (
+	// The default for minimal word length that gets decomposed
+	DEFAULT_MIN_WORD_SIZE = 5
+	// The default for minimal length of subwords that get propagated to the output of this filter
+	DEFAULT_MIN_SUBWORD_SIZE = 2
+	// The default for maximal length of subwords that get propagated to the output of this filter
+	DEFAULT_MAX_SUBWORD_SIZE = 15
+)
+
+type CompoundWordTokenFilterSPI interface {
+	// Decomposes the current term into the tokens to emit after it,
+	// with AddToken().
+	Decompose()
+}
+
+/*
+Base class for decomposition token filters. A compound word is
+emitted as is, followed by its parts at the same position.
+*/
+type CompoundWordTokenFilterBase struct {
+	*TokenFilter
+	spi              CompoundWordTokenFilterSPI
+	input            TokenStream
+	Dictionary       *CharArraySet
+	tokens           []compoundToken
+	MinWordSize      int
+	MinSubwordSize   int
+	MaxSubwordSize   int
+	OnlyLongestMatch bool
+
+	TermAtt   CharTermAttribute
+	offsetAtt OffsetAttribute
+	posIncAtt PositionIncrementAttribute
+
+	current *util.AttributeState
+}
+
+func NewCompoundWordTokenFilterBase(spi CompoundWordTokenFilterSPI, input TokenStream,
+	dictionary *CharArraySet, minWordSize, minSubwordSize, maxSubwordSize int,
+	onlyLongestMatch bool) *CompoundWordTokenFilterBase {
+
+	if minWordSize < 0 {
+		panic("minWordSize cannot be negative")
+	}
+	if minSubwordSize < 0 {
+		panic("minSubwordSize cannot be negative")
+	}
+	if maxSubwordSize < 0 {
+		panic("maxSubwordSize cannot be negative")
+	}
+	ans := &CompoundWordTokenFilterBase{
+		TokenFilter:      NewTokenFilter(input),
+		spi:              spi,
+		input:            input,
+		Dictionary:       dictionary,
+		MinWordSize:      minWordSize,
+		MinSubwordSize:   minSubwordSize,
+		MaxSubwordSize:   maxSubwordSize,
+		OnlyLongestMatch: onlyLongestMatch,
+	}
+	ans.TermAtt = ans.Attributes().Add("CharTermAttribute").(CharTermAttribute)
+	ans.offsetAtt = ans.Attributes().Add("OffsetAttribute").(OffsetAttribute)
+	ans.posIncAtt = ans.Attributes().Add("PositionIncrementAttribute").(PositionIncrementAttribute)
+	return ans
+}
+
+func (f *CompoundWordTokenFilterBase) IncrementToken() (bool, error) {
+	if len(f.tokens) > 0 {
+		assert(f.current != nil)
+		token := f.tokens[0]
+		f.tokens = f.tokens[1:]
+		f.Attributes().RestoreState(f.current) // keep all other attributes untouched
+		f.TermAtt.CopyBuffer(token.text)
+		f.offsetAtt.SetOffset(token.startOffset, token.endOffset)
+		f.posIncAtt.SetPositionIncrement(0)
+		return true, nil
+	}
+
+	f.current = nil // not really needed, but for safety
+	ok, err := f.input.IncrementToken()
+	if err != nil || !ok {
+		return false, err
+	}
+	// Only words longer than minWordSize get processed
+	if f.TermAtt.Length() >= f.MinWordSize {
+		f.spi.Decompose()
+		// only capture the state if we really need it for producing new tokens
+		if len(f.tokens) > 0 {
+			f.current = f.Attributes().CaptureState()
+		}
+	}
+	// return original token:
+	return true, nil
+}
+
+/* Adds the part [offset, offset+length) of the current term to the tokens to emit. */
+func (f *CompoundWordTokenFilterBase) AddToken(offset, length int) {
+	text := make([]rune, length)
+	copy(text, f.TermAtt.Buffer()[offset:offset+length])
+	startOffset := f.offsetAtt.StartOffset()
+	endOffset := f.offsetAtt.EndOffset()
+	if endOffset-startOffset == f.TermAtt.Length() {
+		// the term was not modified: the part has its own offsets
+		startOffset += offset
+		endOffset = startOffset + length
+	}
+	// else: the term was modified by a previous filter (like a
+	// stemmer), so its offsets can't be split: the part keeps them
+	f.tokens = append(f.tokens, compoundToken{text, startOffset, endOffset})
+}
+
+func (f *CompoundWordTokenFilterBase) Reset() error {
+	f.tokens = nil
+	f.current = nil
+	return f.TokenFilter.Reset()
+}
+
+/* Helper type to hold decompounded token information. */
+type compoundToken struct {
+	text                   []rune
+	startOffset, endOffset int
+}
+
+func assert(ok bool) {
+	if !ok {
+		panic("assert fail")
+	}
+}
+
+// compound/DictionaryCompoundWordTokenFilter.java
+
+/*
+A TokenFilter that decomposes compound words found in many Germanic
+languages.
+
+"Donaudampfschiff" becomes Donau, dampf, schiff so that you can find
+"Donaudampfschiff" even when you only enter "schiff". It uses a
+brute-force algorithm to achieve this.
+*/
+type DictionaryCompoundWordTokenFilter struct {
+	*CompoundWordTokenFilterBase
+}
+
+/*
+Creates a new DictionaryCompoundWordTokenFilter with the default
+sizes. The dictionary holds the words to look for, and must not be
+nil.
+*/
+func NewDictionaryCompoundWordTokenFilter(input TokenStream, dictionary *CharArraySet) *DictionaryCompoundWordTokenFilter {
+	return NewDictionaryCompoundWordTokenFilterWithSizes(input, dictionary,
+		DEFAULT_MIN_WORD_SIZE, DEFAULT_MIN_SUBWORD_SIZE, DEFAULT_MAX_SUBWORD_SIZE, false)
+}
+
+/*
+Creates a new DictionaryCompoundWordTokenFilter. Only words longer
+than minWordSize get decomposed, into subwords from minSubwordSize to
+maxSubwordSize long. If onlyLongestMatch is true, only the longest
+subword starting at each position is emitted.
+*/
+func NewDictionaryCompoundWordTokenFilterWithSizes(input TokenStream, dictionary *CharArraySet,
+	minWordSize, minSubwordSize, maxSubwordSize int, onlyLongestMatch bool) *DictionaryCompoundWordTokenFilter {
+
+	if dictionary == nil {
+		panic("dictionary cannot be null")
+	}
+	ans := new(DictionaryCompoundWordTokenFilter)
+	ans.CompoundWordTokenFilterBase = NewCompoundWordTokenFilterBase(ans, input, dictionary,
+		minWordSize, minSubwordSize, maxSubwordSize, onlyLongestMatch)
+	return ans
+}
+
+func (f *DictionaryCompoundWordTokenFilter) Decompose() {
+	length := f.TermAtt.Length()
+	buffer := f.TermAtt.Buffer()
+	for i := 0; i <= length-f.MinSubwordSize; i++ {
+		longestMatch := -1
+		for j := f.MinSubwordSize; j <= f.MaxSubwordSize && i+j <= length; j++ {
+			if f.Dictionary.Contains(buffer[i : i+j]) {
+				if f.OnlyLongestMatch {
+					longestMatch = j
+				} else {
+					f.AddToken(i, j)
+				}
+			}
+		}
+		if longestMatch > 0 {
+			f.AddToken(i, longestMatch)
+		}
+	}
+}
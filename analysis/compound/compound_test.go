@@ -0,0 +1,96 @@
+package compound
+
+import (
+	"fmt"
+	std "github.com/balzaczyy/golucene/analysis/standard"
+	. "github.com/balzaczyy/golucene/analysis/util"
+	. "github.com/balzaczyy/golucene/core/analysis"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"github.com/balzaczyy/golucene/core/util"
+	"strings"
+	"testing"
+)
+
+func assertTokens(t *testing.T, ts TokenStream, expected string) {
+	termAtt := ts.Attributes().Add("CharTermAttribute").(CharTermAttribute)
+	offsetAtt := ts.Attributes().Add("OffsetAttribute").(OffsetAttribute)
+	posIncAtt := ts.Attributes().Add("PositionIncrementAttribute").(PositionIncrementAttribute)
+	if err := ts.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	var tokens []string
+	for {
+		ok, err := ts.IncrementToken()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			break
+		}
+		tokens = append(tokens, fmt.Sprintf("%v[%v-%v]/%v", string(termAtt.Buffer()[:termAtt.Length()]),
+			offsetAtt.StartOffset(), offsetAtt.EndOffset(), posIncAtt.PositionIncrement()))
+	}
+	ts.End()
+	ts.Close()
+	if got := strings.Join(tokens, " "); got != expected {
+		t.Errorf("expected %v, but was %v", expected, got)
+	}
+}
+
+func tokenizer(text string) TokenStream {
+	return std.NewStandardTokenizer(util.VERSION_LATEST, strings.NewReader(text))
+}
+
+func TestDictionaryCompoundWordTokenFilter(t *testing.T) {
+	dict := NewCharArraySet(false, "Rind", "Fleisch", "Draht", "Schere", "Gesetz",
+		"Aufgabe", "Überwachung", "Donau", "dampf", "schiff", "Schiff", "fahrt", "fahr")
+	assertTokens(t, NewDictionaryCompoundWordTokenFilter(tokenizer("Rindfleisch Donaudampfschifffahrt"), dict),
+		"Rindfleisch[0-11]/1 Rind[0-4]/0 "+
+			"Donaudampfschifffahrt[12-33]/1 Donau[12-17]/0 dampf[17-22]/0 schiff[22-28]/0 fahr[28-32]/0 fahrt[28-33]/0")
+
+	// only the longest match
+	assertTokens(t, NewDictionaryCompoundWordTokenFilterWithSizes(tokenizer("Donaudampfschifffahrt"), dict,
+		DEFAULT_MIN_WORD_SIZE, DEFAULT_MIN_SUBWORD_SIZE, DEFAULT_MAX_SUBWORD_SIZE, true),
+		"Donaudampfschifffahrt[0-21]/1 Donau[0-5]/0 dampf[5-10]/0 schiff[10-16]/0 fahrt[16-21]/0")
+
+	// short words are left alone
+	assertTokens(t, NewDictionaryCompoundWordTokenFilter(tokenizer("Rind"), NewCharArraySet(false, "Ri")),
+		"Rind[0-4]/1")
+}
+
+const patterns = `<?xml version="1.0" encoding="utf-8"?>
+<hyphenation-info>
+<hyphen-char value="-"/>
+<hyphen-min before="2" after="2"/>
+</hyphenation-info>
+<classes>
+aA bB cC dD eE fF gG hH iI jJ kK lL mM nN oO pP qQ rR sS tT uU vV wW xX yY zZ
+</classes>
+<exceptions>
+bas-ket-ball
+</exceptions>
+<patterns>
+d1s t1s 1fl 1fa
+</patterns>
+`
+
+func TestHyphenationCompoundWordTokenFilter(t *testing.T) {
+	hyphenator, err := GetHyphenationTree(strings.NewReader(patterns))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h := hyphenator.Hyphenate([]rune("Rindfleisch"), 1, 1); h == nil || fmt.Sprint(h.HyphenationPoints()) != "[0 4 11]" {
+		t.Errorf("expected [0 4 11], but was %v", h)
+	}
+	if h := hyphenator.Hyphenate([]rune("basketball"), 1, 1); h == nil || fmt.Sprint(h.HyphenationPoints()) != "[0 3 6 10]" {
+		t.Errorf("expected [0 3 6 10], but was %v", h)
+	}
+
+	dict := NewCharArraySet(false, "Rind", "fleisch", "Draht", "schere", "Gesetz", "Aufgabe")
+	assertTokens(t, NewHyphenationCompoundWordTokenFilter(tokenizer("Rindfleisch Drahtschere"), hyphenator, dict),
+		"Rindfleisch[0-11]/1 Rind[0-4]/0 fleisch[4-11]/0 Drahtschere[12-23]/1 Draht[12-17]/0 schere[17-23]/0")
+
+	// without dictionary, all the parts are emitted
+	assertTokens(t, NewHyphenationCompoundWordTokenFilter(tokenizer("basketball"), hyphenator, nil),
+		"basketball[0-10]/1 bas[0-3]/0 basket[0-6]/0 basketball[0-10]/0 ket[3-6]/0 ketball[3-10]/0 ball[6-10]/0")
+}
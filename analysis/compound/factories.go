@@ -0,0 +1,108 @@
+package compound
+
+import (
+	"errors"
+	"github.com/balzaczyy/golucene/analysis/compound/hyphenation"
+	. "github.com/balzaczyy/golucene/analysis/util"
+	. "github.com/balzaczyy/golucene/core/analysis"
+	"os"
+)
+
+func init() {
+	RegisterTokenFilterFactory("dictionaryCompoundWord", func(args map[string]string) (TokenFilterFactory, error) {
+		f, err := NewDictionaryCompoundWordTokenFilterFactory(args)
+		if err != nil {
+			return nil, err
+		}
+		return f, nil
+	})
+	RegisterTokenFilterFactory("hyphenationCompoundWord", func(args map[string]string) (TokenFilterFactory, error) {
+		f, err := NewHyphenationCompoundWordTokenFilterFactory(args)
+		if err != nil {
+			return nil, err
+		}
+		return f, nil
+	})
+}
+
+// compound/DictionaryCompoundWordTokenFilterFactory.java
+
+/*
+Factory for DictionaryCompoundWordTokenFilter. It takes the required
+"dictionary" word files, and the optional "minWordSize",
+"minSubwordSize", "maxSubwordSize" and "onlyLongestMatch".
+*/
+type DictionaryCompoundWordTokenFilterFactory struct {
+	*AbstractAnalysisFactory
+	dictionary       *CharArraySet
+	minWordSize      int
+	minSubwordSize   int
+	maxSubwordSize   int
+	onlyLongestMatch bool
+}
+
+func NewDictionaryCompoundWordTokenFilterFactory(args map[string]string) (*DictionaryCompoundWordTokenFilterFactory, error) {
+	ans := &DictionaryCompoundWordTokenFilterFactory{AbstractAnalysisFactory: NewAbstractAnalysisFactory(args)}
+	ans.dictionary = ans.GetWordSet("dictionary", "wordset", false)
+	ans.minWordSize = ans.GetInt("minWordSize", DEFAULT_MIN_WORD_SIZE)
+	ans.minSubwordSize = ans.GetInt("minSubwordSize", DEFAULT_MIN_SUBWORD_SIZE)
+	ans.maxSubwordSize = ans.GetInt("maxSubwordSize", DEFAULT_MAX_SUBWORD_SIZE)
+	ans.onlyLongestMatch = ans.GetBool("onlyLongestMatch", true)
+	if err := ans.Validate(); err != nil {
+		return nil, err
+	}
+	if ans.dictionary == nil {
+		return nil, errors.New("Configuration Error: missing parameter 'dictionary'")
+	}
+	return ans, nil
+}
+
+func (f *DictionaryCompoundWordTokenFilterFactory) Create(input TokenStream) TokenStream {
+	return NewDictionaryCompoundWordTokenFilterWithSizes(input, f.dictionary,
+		f.minWordSize, f.minSubwordSize, f.maxSubwordSize, f.onlyLongestMatch)
+}
+
+// compound/HyphenationCompoundWordTokenFilterFactory.java
+
+/*
+Factory for HyphenationCompoundWordTokenFilter. It takes the required
+"hyphenator" XML patterns file, the optional "dictionary" word files,
+and the optional "minWordSize", "minSubwordSize", "maxSubwordSize"
+and "onlyLongestMatch".
+*/
+type HyphenationCompoundWordTokenFilterFactory struct {
+	*AbstractAnalysisFactory
+	dictionary       *CharArraySet
+	hyphenator       *hyphenation.HyphenationTree
+	minWordSize      int
+	minSubwordSize   int
+	maxSubwordSize   int
+	onlyLongestMatch bool
+}
+
+func NewHyphenationCompoundWordTokenFilterFactory(args map[string]string) (*HyphenationCompoundWordTokenFilterFactory, error) {
+	ans := &HyphenationCompoundWordTokenFilterFactory{AbstractAnalysisFactory: NewAbstractAnalysisFactory(args)}
+	ans.dictionary = ans.GetWordSet("dictionary", "wordset", false)
+	hyphenator := ans.Require("hyphenator")
+	ans.minWordSize = ans.GetInt("minWordSize", DEFAULT_MIN_WORD_SIZE)
+	ans.minSubwordSize = ans.GetInt("minSubwordSize", DEFAULT_MIN_SUBWORD_SIZE)
+	ans.maxSubwordSize = ans.GetInt("maxSubwordSize", DEFAULT_MAX_SUBWORD_SIZE)
+	ans.onlyLongestMatch = ans.GetBool("onlyLongestMatch", false)
+	if err := ans.Validate(); err != nil {
+		return nil, err
+	}
+	in, err := os.Open(hyphenator)
+	if err != nil {
+		return nil, err
+	}
+	defer in.Close()
+	if ans.hyphenator, err = GetHyphenationTree(in); err != nil {
+		return nil, err
+	}
+	return ans, nil
+}
+
+func (f *HyphenationCompoundWordTokenFilterFactory) Create(input TokenStream) TokenStream {
+	return NewHyphenationCompoundWordTokenFilterWithSizes(input, f.hyphenator, f.dictionary,
+		f.minWordSize, f.minSubwordSize, f.maxSubwordSize, f.onlyLongestMatch)
+}
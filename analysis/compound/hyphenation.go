@@ -0,0 +1,109 @@
+package compound
+
+import (
+	"github.com/balzaczyy/golucene/analysis/compound/hyphenation"
+	. "github.com/balzaczyy/golucene/analysis/util"
+	. "github.com/balzaczyy/golucene/core/analysis"
+	"io"
+)
+
+// compound/HyphenationCompoundWordTokenFilter.java
+
+/*
+A TokenFilter that decomposes compound words found in many Germanic
+languages.
+
+"Donaudampfschiff" becomes Donau, dampf, schiff so that you can find
+"Donaudampfschiff" even when you only enter "schiff". It uses a
+hyphenation grammar and a word dictionary to achieve this: only the
+parts between hyphenation points are candidates.
+*/
+type HyphenationCompoundWordTokenFilter struct {
+	*CompoundWordTokenFilterBase
+	hyphenator *hyphenation.HyphenationTree
+}
+
+/*
+Creates a new HyphenationCompoundWordTokenFilter with the default
+sizes. If dictionary is nil, all the parts between hyphenation points
+are emitted.
+*/
+func NewHyphenationCompoundWordTokenFilter(input TokenStream, hyphenator *hyphenation.HyphenationTree,
+	dictionary *CharArraySet) *HyphenationCompoundWordTokenFilter {
+
+	return NewHyphenationCompoundWordTokenFilterWithSizes(input, hyphenator, dictionary,
+		DEFAULT_MIN_WORD_SIZE, DEFAULT_MIN_SUBWORD_SIZE, DEFAULT_MAX_SUBWORD_SIZE, false)
+}
+
+/*
+Creates a new HyphenationCompoundWordTokenFilter. Only words longer
+than minWordSize get decomposed, into subwords from minSubwordSize to
+maxSubwordSize long. If onlyLongestMatch is true, only the longest
+subword starting at each hyphenation point is emitted.
+*/
+func NewHyphenationCompoundWordTokenFilterWithSizes(input TokenStream,
+	hyphenator *hyphenation.HyphenationTree, dictionary *CharArraySet,
+	minWordSize, minSubwordSize, maxSubwordSize int, onlyLongestMatch bool) *HyphenationCompoundWordTokenFilter {
+
+	ans := &HyphenationCompoundWordTokenFilter{hyphenator: hyphenator}
+	ans.CompoundWordTokenFilterBase = NewCompoundWordTokenFilterBase(ans, input, dictionary,
+		minWordSize, minSubwordSize, maxSubwordSize, onlyLongestMatch)
+	return ans
+}
+
+/* Create a hyphenator tree from the XML patterns of r. */
+func GetHyphenationTree(r io.Reader) (*hyphenation.HyphenationTree, error) {
+	return hyphenation.LoadPatterns(r)
+}
+
+func (f *HyphenationCompoundWordTokenFilter) Decompose() {
+	buffer := f.TermAtt.Buffer()
+	// get the hyphenation points
+	hyphens := f.hyphenator.Hyphenate(buffer[:f.TermAtt.Length()], 1, 1)
+	// No hyphen points found -> exit
+	if hyphens == nil {
+		return
+	}
+
+	positions := hyphens.HyphenationPoints()
+	for i, start := range positions {
+		longestMatch := -1
+		for _, end := range positions[i+1:] {
+			partLength := end - start
+			// if the part is longer than maxSubwordSize we are done with
+			// this round
+			if partLength > f.MaxSubwordSize {
+				break
+			}
+			// we only put subwords to the token stream that are longer
+			// than minPartSize
+			if partLength < f.MinSubwordSize {
+				// BOGUS/BROKEN/FUNKY/WACKO: somehow we have negative
+				// 'parts' according to the calculation above, and we rely
+				// upon minSubwordSize being >=0 to filter them out...
+				continue
+			}
+
+			// check the dictionary
+			if f.Dictionary == nil || f.Dictionary.Contains(buffer[start:start+partLength]) {
+				if f.OnlyLongestMatch {
+					longestMatch = partLength
+				} else {
+					f.AddToken(start, partLength)
+				}
+			} else if f.Dictionary.Contains(buffer[start : start+partLength-1]) {
+				// check the dictionary again with a word that is one
+				// character shorter to avoid problems with genitive 's
+				// characters and other binding characters
+				if f.OnlyLongestMatch {
+					longestMatch = partLength - 1
+				} else {
+					f.AddToken(start, partLength-1)
+				}
+			}
+		}
+		if longestMatch > 0 {
+			f.AddToken(start, longestMatch)
+		}
+	}
+}
@@ -0,0 +1,233 @@
+package hyphenation
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// compound/hyphenation/Hyphenation.java
+
+/* This type represents a hyphenated word. */
+type Hyphenation struct {
+	hyphenPoints []int
+}
+
+/* Returns the hyphenation points, including 0 and the word length. */
+func (h *Hyphenation) HyphenationPoints() []int {
+	return h.hyphenPoints
+}
+
+/* Returns the number of hyphenation points in the word. */
+func (h *Hyphenation) Length() int {
+	return len(h.hyphenPoints)
+}
+
+// compound/hyphenation/HyphenationTree.java
+
+/*
+This tree structure stores the hyphenation patterns in an efficient
+way for fast lookup. It provides the method to hyphenate a word,
+following Liang's algorithm as used by TeX.
+
+Patterns are loaded from the XML format of the FOP project (the
+hyphenation grammars of OFFO), like:
+
+	<hyphenation-info>
+	  <hyphen-char value="-"/>
+	  <hyphen-min before="2" after="2"/>
+	</hyphenation-info>
+	<classes>aA bB cC ...</classes>
+	<exceptions>ta-ble ...</exceptions>
+	<patterns>.ach4 .ad4der ...</patterns>
+*/
+type HyphenationTree struct {
+	// letters of the pattern -> the interletter values, one more
+	// than the letters
+	patterns map[string][]int
+	// the normalized letter of each letter of the classes; nil if no
+	// class is declared
+	classes map[rune]rune
+	// word -> its hyphenation points
+	exceptions map[string][]int
+	hyphenChar rune
+	maxPattern int
+}
+
+func newHyphenationTree() *HyphenationTree {
+	return &HyphenationTree{
+		patterns:   make(map[string][]int),
+		exceptions: make(map[string][]int),
+		hyphenChar: '-',
+	}
+}
+
+/* Read hyphenation patterns from an XML file. */
+func LoadPatterns(r io.Reader) (*HyphenationTree, error) {
+	ans := newHyphenationTree()
+	decoder := xml.NewDecoder(r)
+	decoder.Strict = false
+	var section string
+	var exceptions []string
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "classes", "exceptions", "patterns":
+				section = t.Name.Local
+			case "hyphen-char":
+				for _, attr := range t.Attr {
+					if attr.Name.Local == "value" && attr.Value != "" {
+						ans.hyphenChar = []rune(attr.Value)[0]
+					}
+				}
+			}
+		case xml.EndElement:
+			if t.Name.Local == section {
+				section = ""
+			}
+		case xml.CharData:
+			words := strings.Fields(string(t))
+			switch section {
+			case "classes":
+				for _, class := range words {
+					ans.addClass(class)
+				}
+			case "exceptions":
+				exceptions = append(exceptions, words...)
+			case "patterns":
+				for _, pattern := range words {
+					if err := ans.addPattern(pattern); err != nil {
+						return nil, err
+					}
+				}
+			}
+		}
+	}
+	for _, exception := range exceptions {
+		ans.addException(exception)
+	}
+	return ans, nil
+}
+
+/* All the letters of the class are normalized to its first letter. */
+func (t *HyphenationTree) addClass(class string) {
+	if t.classes == nil {
+		t.classes = make(map[rune]rune)
+	}
+	letters := []rune(class)
+	for _, ch := range letters {
+		t.classes[ch] = letters[0]
+	}
+}
+
+/* Adds a pattern like ".ach4": letters with the values in between. */
+func (t *HyphenationTree) addPattern(pattern string) error {
+	var letters []rune
+	values := []int{0}
+	for _, ch := range pattern {
+		if ch >= '0' && ch <= '9' {
+			v, _ := strconv.Atoi(string(ch))
+			values[len(values)-1] = v
+		} else {
+			letters = append(letters, t.normalize(ch))
+			values = append(values, 0)
+		}
+	}
+	if len(letters) == 0 {
+		return fmt.Errorf("Invalid hyphenation pattern: %v", pattern)
+	}
+	t.patterns[string(letters)] = values
+	if len(letters) > t.maxPattern {
+		t.maxPattern = len(letters)
+	}
+	return nil
+}
+
+/* Adds an exception like "ta-ble", hyphenated with the hyphen char. */
+func (t *HyphenationTree) addException(exception string) {
+	var letters []rune
+	var points []int
+	for _, ch := range exception {
+		if ch == t.hyphenChar {
+			points = append(points, len(letters))
+		} else {
+			letters = append(letters, t.normalize(ch))
+		}
+	}
+	t.exceptions[string(letters)] = points
+}
+
+/* Returns the normalized letter, or 0 if ch is not a letter of the classes. */
+func (t *HyphenationTree) normalize(ch rune) rune {
+	if ch == '.' {
+		return ch
+	}
+	if t.classes == nil {
+		return unicode.ToLower(ch)
+	}
+	return t.classes[ch]
+}
+
+/*
+Hyphenate word and return a Hyphenation object, or nil if the word
+can't be hyphenated. remainCharCount is the minimum number of
+characters allowed before the first hyphenation point, and
+pushCharCount the minimum number of characters allowed after the
+last one.
+*/
+func (t *HyphenationTree) Hyphenate(word []rune, remainCharCount, pushCharCount int) *Hyphenation {
+	length := len(word)
+	normalized := make([]rune, length)
+	for i, ch := range word {
+		if normalized[i] = t.normalize(ch); normalized[i] == 0 {
+			// the word contains a non-letter
+			return nil
+		}
+	}
+
+	var points []int
+	if exception, ok := t.exceptions[string(normalized)]; ok {
+		for _, point := range exception {
+			if point >= remainCharCount && point <= length-pushCharCount {
+				points = append(points, point)
+			}
+		}
+	} else {
+		// the word between the word boundaries
+		w := append(append([]rune{'.'}, normalized...), '.')
+		values := make([]int, len(w)+1)
+		for i := range w {
+			for j := i + 1; j <= len(w) && j-i <= t.maxPattern; j++ {
+				if pattern, ok := t.patterns[string(w[i:j])]; ok {
+					for k, v := range pattern {
+						if v > values[i+k] {
+							values[i+k] = v
+						}
+					}
+				}
+			}
+		}
+		// values[i+1] is the value before the i-th letter of the word
+		for i := remainCharCount; i <= length-pushCharCount; i++ {
+			if i > 0 && i < length && values[i+1]%2 == 1 {
+				points = append(points, i)
+			}
+		}
+	}
+	if len(points) == 0 {
+		return nil
+	}
+	// We add the synthetical hyphenation points at the beginning and
+	// end of the word
+	return &Hyphenation{append(append([]int{0}, points...), length)}
+}
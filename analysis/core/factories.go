@@ -0,0 +1,193 @@
+package core
+
+import (
+	"fmt"
+	. "github.com/balzaczyy/golucene/analysis/util"
+	. "github.com/balzaczyy/golucene/core/analysis"
+	"io"
+)
+
+func init() {
+	RegisterTokenFilterFactory("flattenGraph", func(args map[string]string) (TokenFilterFactory, error) {
+		f, err := NewFlattenGraphFilterFactory(args)
+		if err != nil {
+			return nil, err
+		}
+		return f, nil
+	})
+	RegisterTokenizerFactory("keyword", func(args map[string]string) (TokenizerFactory, error) {
+		f, err := NewKeywordTokenizerFactory(args)
+		if err != nil {
+			return nil, err
+		}
+		return f, nil
+	})
+	RegisterTokenFilterFactory("lowercase", func(args map[string]string) (TokenFilterFactory, error) {
+		f, err := NewLowerCaseFilterFactory(args)
+		if err != nil {
+			return nil, err
+		}
+		return f, nil
+	})
+	RegisterTokenFilterFactory("stop", func(args map[string]string) (TokenFilterFactory, error) {
+		f, err := NewStopFilterFactory(args)
+		if err != nil {
+			return nil, err
+		}
+		return f, nil
+	})
+	RegisterTokenFilterFactory("type", func(args map[string]string) (TokenFilterFactory, error) {
+		f, err := NewTypeTokenFilterFactory(args)
+		if err != nil {
+			return nil, err
+		}
+		return f, nil
+	})
+}
+
+// core/KeywordTokenizerFactory.java
+
+/* Factory for KeywordTokenizer. */
+type KeywordTokenizerFactory struct {
+	*AbstractAnalysisFactory
+}
+
+func NewKeywordTokenizerFactory(args map[string]string) (*KeywordTokenizerFactory, error) {
+	ans := &KeywordTokenizerFactory{NewAbstractAnalysisFactory(args)}
+	if err := ans.Validate(); err != nil {
+		return nil, err
+	}
+	return ans, nil
+}
+
+func (f *KeywordTokenizerFactory) Create(input io.RuneReader) TokenizerService {
+	return NewKeywordTokenizer(input)
+}
+
+// core/LowerCaseFilterFactory.java
+
+/* Factory for LowerCaseFilter. */
+type LowerCaseFilterFactory struct {
+	*AbstractAnalysisFactory
+}
+
+func NewLowerCaseFilterFactory(args map[string]string) (*LowerCaseFilterFactory, error) {
+	ans := &LowerCaseFilterFactory{NewAbstractAnalysisFactory(args)}
+	if err := ans.Validate(); err != nil {
+		return nil, err
+	}
+	return ans, nil
+}
+
+func (f *LowerCaseFilterFactory) Create(input TokenStream) TokenStream {
+	return NewLowerCaseFilter(f.LuceneMatchVersion(), input)
+}
+
+// core/StopFilterFactory.java
+
+/*
+Factory for StopFilter.
+
+All attributes are optional:
+
+	- ignoreCase defaults to false
+	- words should be the name of a stopwords file to parse, if not
+	  specified the factory will use ENGLISH_STOP_WORDS_SET
+	- format defines how the words file will be parsed, and defaults
+	  to "wordset". If words is not specified, then format must not be
+	  specified.
+
+The valid values for the format option are:
+
+	- wordset - This is the default format, which supports one word
+	  per line (including any intra-word whitespace) and allows
+	  whole line comments begining with the "#" character.
+	- snowball - This format allows for multiple words specified on
+	  each line, and trailing comments may be specified using the
+	  vertical line ("|").
+*/
+type StopFilterFactory struct {
+	*AbstractAnalysisFactory
+	stopWords *CharArraySet
+}
+
+func NewStopFilterFactory(args map[string]string) (*StopFilterFactory, error) {
+	ans := &StopFilterFactory{AbstractAnalysisFactory: NewAbstractAnalysisFactory(args)}
+	_, hasFormat := args["format"]
+	format := ans.GetOneOf("format", "wordset", "wordset", "snowball")
+	ignoreCase := ans.GetBool("ignoreCase", false)
+	ans.stopWords = ans.GetWordSet("words", format, ignoreCase)
+	if err := ans.Validate(); err != nil {
+		return nil, err
+	}
+	if ans.stopWords == nil {
+		if hasFormat {
+			return nil, fmt.Errorf("'format' can not be specified w/o an explicit 'words' file")
+		}
+		ans.stopWords = NewCharArraySetFromMap(ENGLISH_STOP_WORDS_SET, ignoreCase)
+	}
+	return ans, nil
+}
+
+func (f *StopFilterFactory) StopWords() *CharArraySet {
+	return f.stopWords
+}
+
+func (f *StopFilterFactory) Create(input TokenStream) TokenStream {
+	return NewStopFilterWithSet(f.LuceneMatchVersion(), input, f.stopWords)
+}
+
+// core/TypeTokenFilterFactory.java
+
+/*
+Factory for TypeTokenFilter. It requires "types", the comma-separated
+list of files holding one type per line, and takes the optional
+"useWhitelist" (false by default) to keep rather than remove the
+tokens of these types.
+*/
+type TypeTokenFilterFactory struct {
+	*AbstractAnalysisFactory
+	stopTypes    map[string]bool
+	useWhitelist bool
+}
+
+func NewTypeTokenFilterFactory(args map[string]string) (*TypeTokenFilterFactory, error) {
+	ans := &TypeTokenFilterFactory{AbstractAnalysisFactory: NewAbstractAnalysisFactory(args)}
+	stopTypes := ans.GetWordSet("types", "wordset", false)
+	ans.useWhitelist = ans.GetBool("useWhitelist", false)
+	if err := ans.Validate(); err != nil {
+		return nil, err
+	}
+	if stopTypes == nil {
+		return nil, fmt.Errorf("Configuration Error: missing parameter 'types'")
+	}
+	ans.stopTypes = stopTypes.ToMap()
+	return ans, nil
+}
+
+func (f *TypeTokenFilterFactory) StopTypes() map[string]bool {
+	return f.stopTypes
+}
+
+func (f *TypeTokenFilterFactory) Create(input TokenStream) TokenStream {
+	return NewTypeTokenFilterWithWhiteList(f.LuceneMatchVersion(), input, f.stopTypes, f.useWhitelist)
+}
+
+// core/FlattenGraphFilterFactory.java
+
+/* Factory for FlattenGraphFilter. */
+type FlattenGraphFilterFactory struct {
+	*AbstractAnalysisFactory
+}
+
+func NewFlattenGraphFilterFactory(args map[string]string) (*FlattenGraphFilterFactory, error) {
+	ans := &FlattenGraphFilterFactory{NewAbstractAnalysisFactory(args)}
+	if err := ans.Validate(); err != nil {
+		return nil, err
+	}
+	return ans, nil
+}
+
+func (f *FlattenGraphFilterFactory) Create(input TokenStream) TokenStream {
+	return NewFlattenGraphFilter(input)
+}
@@ -0,0 +1,338 @@
+package core
+
+import (
+	"fmt"
+	. "github.com/balzaczyy/golucene/core/analysis"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"github.com/balzaczyy/golucene/core/util"
+)
+
+// core/FlattenGraphFilter.java
+
+/*
+Converts an incoming graph token stream, such as one from
+SynonymGraphFilter, into a flat form so that all nodes form a single
+linear chain with no side paths. Every path through the graph touches
+every node. This is necessary when indexing a graph token stream,
+because the index does not save PositionLengthAttribute and so it
+cannot preserve the graph structure. However, at search time, query
+parsers can correctly handle the graph and this token filter should
+NOT be used.
+
+If the graph was not already flat to start, this is likely a lossy
+process, i.e. it will often cause the graph to accept token sequences
+it should not, and to reject token sequences it should not.
+
+However, when applying synonyms during indexing, this is necessary
+because Lucene already does not index a graph and so the indexing
+process is already lossy (it ignores the PositionLengthAttribute).
+*/
+type FlattenGraphFilter struct {
+	*TokenFilter
+	input TokenStream
+
+	// Gathers up merged input positions into output positions. Only
+	// used for the input positions still ahead of the output.
+	inputNodes  inputNodes
+	outputNodes outputNodes
+	// the highest output node allocated so far
+	maxOutputNode int
+
+	posIncAtt PositionIncrementAttribute
+	posLenAtt PositionLengthAttribute
+	offsetAtt OffsetAttribute
+
+	// Which input node the last seen token leaves from
+	inputFrom int
+	// We are currently releasing tokens leaving from this output node
+	outputFrom int
+	// Which output node the last returned token leaves from
+	lastOutputFrom int
+
+	done            bool
+	finalOffset     int
+	finalPosInc     int
+	lastStartOffset int
+}
+
+/* Holds all tokens leaving a given input position. */
+type inputNode struct {
+	// How many tokens left this node
+	tokens []*util.AttributeState
+	// Our input node, or -1 if we haven't been assigned yet
+	node int
+	// Maximum to input node for all tokens leaving here; we use this
+	// to know when we can freeze.
+	maxToNode int
+	// Where we currently map to; this changes (can only increase as we
+	// see more input tokens), until we are finished with this position.
+	outputNode int
+	// Which token (index into tokens) we will next output.
+	nextOut int
+}
+
+type inputNodes map[int]*inputNode
+
+func (m inputNodes) get(pos int) *inputNode {
+	ans, ok := m[pos]
+	if !ok {
+		ans = &inputNode{node: -1, maxToNode: -1, outputNode: -1}
+		m[pos] = ans
+	}
+	return ans
+}
+
+/* Gathers merged input positions into a single output position. */
+type outputNode struct {
+	// Which input nodes have been merged into this output node.
+	inputNodes []int
+	// Node ID for this output, or -1 if we haven't been assigned yet.
+	node int
+	// Which input node (index into inputNodes) we will next output.
+	nextOut int
+	// Start offset of tokens leaving this node.
+	startOffset int
+	// End offset of tokens arriving to this node.
+	endOffset int
+}
+
+type outputNodes map[int]*outputNode
+
+func (m outputNodes) remove(pos, inputNode int) {
+	out := m[pos]
+	for i, v := range out.inputNodes {
+		if v == inputNode {
+			out.inputNodes = append(out.inputNodes[:i], out.inputNodes[i+1:]...)
+			return
+		}
+	}
+	panic("input node is not merged into the output node")
+}
+
+func NewFlattenGraphFilter(in TokenStream) *FlattenGraphFilter {
+	ans := &FlattenGraphFilter{
+		TokenFilter: NewTokenFilter(in),
+		input:       in,
+	}
+	ans.posIncAtt = ans.Attributes().Add("PositionIncrementAttribute").(PositionIncrementAttribute)
+	ans.posLenAtt = ans.Attributes().Add("PositionLengthAttribute").(PositionLengthAttribute)
+	ans.offsetAtt = ans.Attributes().Add("OffsetAttribute").(OffsetAttribute)
+	ans.resetNodes()
+	return ans
+}
+
+func (f *FlattenGraphFilter) output(pos int) *outputNode {
+	ans, ok := f.outputNodes[pos]
+	if !ok {
+		ans = &outputNode{node: -1, startOffset: -1, endOffset: -1}
+		f.outputNodes[pos] = ans
+	}
+	if pos > f.maxOutputNode {
+		f.maxOutputNode = pos
+	}
+	return ans
+}
+
+/* Frees the output node at outputFrom and the input nodes before the ones merged into it. */
+func (f *FlattenGraphFilter) freeBefore(output *outputNode) {
+	for pos := range f.inputNodes {
+		if pos < output.inputNodes[0] {
+			delete(f.inputNodes, pos)
+		}
+	}
+	delete(f.outputNodes, f.outputFrom)
+	f.outputFrom++
+}
+
+func (f *FlattenGraphFilter) releaseBufferedToken() bool {
+	// We only need the loop (retry) if we have a hole (an output node
+	// that has no tokens leaving):
+	for f.outputFrom < f.maxOutputNode {
+		output := f.output(f.outputFrom)
+		if len(output.inputNodes) == 0 {
+			// No tokens arrived to this node, which happens for the first
+			// node after a hole:
+			f.outputFrom++
+			continue
+		}
+
+		maxToNode := -1
+		for _, id := range output.inputNodes {
+			if n := f.inputNodes.get(id); n.maxToNode > maxToNode {
+				maxToNode = n.maxToNode
+			}
+		}
+		if maxToNode > f.inputFrom && !f.done {
+			return false
+		}
+
+		in := f.inputNodes.get(output.inputNodes[output.nextOut])
+		if len(in.tokens) == 0 {
+			// Hole dest nodes are never merged since they are always
+			// assigned to a new output position:
+			assert2(len(output.inputNodes) == 1, "hole node merged with %v others", len(output.inputNodes)-1)
+			f.freeBefore(output)
+			continue
+		}
+
+		f.Attributes().RestoreState(in.tokens[in.nextOut])
+
+		// Correct posInc
+		f.posIncAtt.SetPositionIncrement(f.outputFrom - f.lastOutputFrom)
+		toInputNode := f.inputNodes.get(in.node + f.posLenAtt.PositionLength())
+
+		// Correct posLen
+		assert2(toInputNode.outputNode > f.outputFrom, "token arrives before it leaves")
+		f.posLenAtt.SetPositionLength(toInputNode.outputNode - f.outputFrom)
+		f.lastOutputFrom = f.outputFrom
+		in.nextOut++
+
+		// Correct offsets: they must not go backwards, which would
+		// otherwise happen if the replacement has more tokens than the
+		// input, and we must cope with broken incoming offsets:
+		startOffset := output.startOffset
+		if f.lastStartOffset > startOffset {
+			startOffset = f.lastStartOffset
+		}
+		endOffset := f.output(toInputNode.outputNode).endOffset
+		if startOffset > endOffset {
+			endOffset = startOffset
+		}
+		f.offsetAtt.SetOffset(startOffset, endOffset)
+		f.lastStartOffset = startOffset
+
+		if in.nextOut == len(in.tokens) {
+			output.nextOut++
+			if output.nextOut == len(output.inputNodes) {
+				f.freeBefore(output)
+			}
+		}
+		return true
+	}
+	return false
+}
+
+func (f *FlattenGraphFilter) IncrementToken() (bool, error) {
+	for {
+		if f.releaseBufferedToken() {
+			return true, nil
+		} else if f.done {
+			return false, nil
+		}
+
+		ok, err := f.input.IncrementToken()
+		if err != nil {
+			return false, err
+		}
+		if !ok {
+			if err = f.input.End(); err != nil {
+				return false, err
+			}
+			f.finalPosInc = f.posIncAtt.PositionIncrement()
+			f.finalOffset = f.offsetAtt.EndOffset()
+			f.done = true
+			// Don't return false here: we need to force release any
+			// buffered tokens now
+			continue
+		}
+
+		// Input node this token leaves from:
+		f.inputFrom += f.posIncAtt.PositionIncrement()
+		startOffset, endOffset := f.offsetAtt.StartOffset(), f.offsetAtt.EndOffset()
+
+		// Input node this token goes to:
+		inputTo := f.inputFrom + f.posLenAtt.PositionLength()
+
+		src := f.inputNodes.get(f.inputFrom)
+		if src.node == -1 {
+			// The "from" node of this token was never seen as a "to" node,
+			// which only happens if we just crossed a hole. We normally
+			// rely on the dependencies expressed by the arcs to assign
+			// outgoing node IDs, so we forcefully jump the output node ID:
+			src.node = f.inputFrom
+			src.outputNode = f.maxOutputNode + 1
+			outSrc := f.output(src.outputNode)
+			outSrc.node = src.outputNode
+			outSrc.inputNodes = append(outSrc.inputNodes, f.inputFrom)
+			outSrc.startOffset = startOffset
+		} else if outSrc := f.output(src.outputNode); startOffset > outSrc.startOffset {
+			// "shrink wrap" the offsets so the original tokens (with most
+			// restrictive offsets) win:
+			outSrc.startOffset = startOffset
+		}
+
+		// Buffer this token:
+		src.tokens = append(src.tokens, f.Attributes().CaptureState())
+		if inputTo > src.maxToNode {
+			src.maxToNode = inputTo
+		}
+
+		dest := f.inputNodes.get(inputTo)
+		if dest.node == -1 {
+			// Common case: first time a token is arriving to this input
+			// position:
+			dest.node = inputTo
+		}
+
+		// Always number output nodes sequentially:
+		if outputEndNode := src.outputNode + 1; outputEndNode > dest.outputNode {
+			if dest.outputNode != -1 {
+				f.outputNodes.remove(dest.outputNode, inputTo)
+			}
+			outDest := f.output(outputEndNode)
+			outDest.inputNodes = append(outDest.inputNodes, inputTo)
+			dest.outputNode = outputEndNode
+		}
+
+		// "shrink wrap" the offsets so the original tokens (with most
+		// restrictive offsets) win:
+		if outDest := f.output(dest.outputNode); outDest.endOffset == -1 || endOffset < outDest.endOffset {
+			outDest.endOffset = endOffset
+		}
+	}
+}
+
+func (f *FlattenGraphFilter) resetNodes() {
+	f.inputFrom = -1
+	f.inputNodes = make(inputNodes)
+	in := f.inputNodes.get(0)
+	in.node, in.outputNode = 0, 0
+
+	f.outputNodes = make(outputNodes)
+	f.maxOutputNode = 0
+	out := f.output(0)
+	out.node = 0
+	out.inputNodes = append(out.inputNodes, 0)
+	out.startOffset = 0
+
+	f.outputFrom = 0
+	f.lastOutputFrom = -1
+	f.done = false
+	f.finalPosInc, f.finalOffset = -1, -1
+	f.lastStartOffset = 0
+}
+
+func (f *FlattenGraphFilter) Reset() error {
+	if err := f.TokenFilter.Reset(); err != nil {
+		return err
+	}
+	f.resetNodes()
+	return nil
+}
+
+func (f *FlattenGraphFilter) End() error {
+	if !f.done {
+		return f.TokenFilter.End()
+	}
+	// the input was already ended by IncrementToken()
+	f.Attributes().Clear()
+	f.posIncAtt.SetPositionIncrement(f.finalPosInc)
+	f.offsetAtt.SetOffset(f.finalOffset, f.finalOffset)
+	return nil
+}
+
+func assert2(ok bool, msg string, args ...interface{}) {
+	if !ok {
+		panic(fmt.Sprintf(msg, args...))
+	}
+}
@@ -0,0 +1,77 @@
+package core_test
+
+import (
+	"fmt"
+	. "github.com/balzaczyy/golucene/analysis/core"
+	std "github.com/balzaczyy/golucene/analysis/standard"
+	"github.com/balzaczyy/golucene/analysis/synonym"
+	. "github.com/balzaczyy/golucene/core/analysis"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"github.com/balzaczyy/golucene/core/util"
+	"strings"
+	"testing"
+)
+
+/* Returns the tokens as term/posInc/posLen/offsets, separated by spaces. */
+func flatGraph(t *testing.T, ts TokenStream) string {
+	termAtt := ts.Attributes().Get("CharTermAttribute").(CharTermAttribute)
+	posIncAtt := ts.Attributes().Get("PositionIncrementAttribute").(PositionIncrementAttribute)
+	posLenAtt := ts.Attributes().Get("PositionLengthAttribute").(PositionLengthAttribute)
+	offsetAtt := ts.Attributes().Get("OffsetAttribute").(OffsetAttribute)
+	if err := ts.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	var tokens []string
+	for {
+		ok, err := ts.IncrementToken()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			break
+		}
+		tokens = append(tokens, fmt.Sprintf("%v/%v/%v/%v-%v", string(termAtt.Buffer()[:termAtt.Length()]),
+			posIncAtt.PositionIncrement(), posLenAtt.PositionLength(),
+			offsetAtt.StartOffset(), offsetAtt.EndOffset()))
+	}
+	if err := ts.End(); err != nil {
+		t.Fatal(err)
+	}
+	tokens = append(tokens, fmt.Sprintf("end/%v/%v", posIncAtt.PositionIncrement(), offsetAtt.EndOffset()))
+	if err := ts.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return strings.Join(tokens, " ")
+}
+
+func TestFlattenGraphFilter(t *testing.T) {
+	b := synonym.NewSynonymMapBuilder(true)
+	b.Add(synonym.Join("b", "c", "d"), synonym.Join("big", "cat"), true)
+	b.Add("wtf", synonym.Join("what", "the", "fudge"), true)
+	synonyms, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []struct{ text, expected string }{
+		// the synonym path is squashed onto the original tokens
+		{"z b c d z", "z/1/1/0-1 big/1/1/2-3 b/0/1/2-3 cat/1/2/4-7 c/0/1/4-5 d/1/1/6-7 z/1/1/8-9 end/0/9"},
+		// already flat
+		{"wtf happened", "what/1/1/0-3 wtf/0/3/0-3 the/1/1/0-3 fudge/1/1/0-3 happened/1/1/4-12 end/0/12"},
+		{"a b", "a/1/1/0-1 b/1/1/2-3 end/0/3"},
+	} {
+		ts := NewFlattenGraphFilter(synonym.NewSynonymGraphFilter(
+			std.NewStandardTokenizer(util.VERSION_LATEST, strings.NewReader(v.text)), synonyms, false))
+		if got := flatGraph(t, ts); got != v.expected {
+			t.Errorf("%q: expected %v, but was %v", v.text, v.expected, got)
+		}
+	}
+}
+
+/* Holes left by removed tokens shrink to a single position. */
+func TestFlattenGraphFilterHoles(t *testing.T) {
+	ts := NewFlattenGraphFilter(NewStopFilter(util.VERSION_LATEST,
+		std.NewStandardTokenizer(util.VERSION_LATEST, strings.NewReader("x of the b")), ENGLISH_STOP_WORDS_SET))
+	if got, expected := flatGraph(t, ts), "x/1/1/0-1 b/2/1/9-10 end/0/10"; got != expected {
+		t.Errorf("expected %v, but was %v", expected, got)
+	}
+}
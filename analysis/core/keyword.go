@@ -0,0 +1,96 @@
+package core
+
+import (
+	. "github.com/balzaczyy/golucene/core/analysis"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"io"
+)
+
+// core/KeywordTokenizer.java
+
+/* Default read buffer size */
+const DEFAULT_BUFFER_SIZE = 256
+
+/* Emits the entire input as a single token. */
+type KeywordTokenizer struct {
+	*Tokenizer
+	done        bool
+	finalOffset int
+	bufferSize  int
+	termAtt     CharTermAttribute
+	offsetAtt   OffsetAttribute
+}
+
+func NewKeywordTokenizer(input io.RuneReader) *KeywordTokenizer {
+	return NewKeywordTokenizerWithBufferSize(input, DEFAULT_BUFFER_SIZE)
+}
+
+func NewKeywordTokenizerWithBufferSize(input io.RuneReader, bufferSize int) *KeywordTokenizer {
+	if bufferSize <= 0 {
+		panic("bufferSize must be > 0")
+	}
+	ans := &KeywordTokenizer{
+		Tokenizer:  NewTokenizer(input),
+		bufferSize: bufferSize,
+	}
+	ans.termAtt = ans.Attributes().Add("CharTermAttribute").(CharTermAttribute)
+	ans.offsetAtt = ans.Attributes().Add("OffsetAttribute").(OffsetAttribute)
+	return ans
+}
+
+func (t *KeywordTokenizer) IncrementToken() (bool, error) {
+	if t.done {
+		return false, nil
+	}
+	t.Attributes().Clear()
+	t.done = true
+	buffer := make([]rune, 0, t.bufferSize)
+	for {
+		ch, _, err := t.Input.ReadRune()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return false, err
+		}
+		buffer = append(buffer, ch)
+	}
+	t.termAtt.CopyBuffer(buffer)
+	t.finalOffset = t.CorrectOffset(len(buffer))
+	t.offsetAtt.SetOffset(t.CorrectOffset(0), t.finalOffset)
+	return true, nil
+}
+
+func (t *KeywordTokenizer) End() error {
+	if err := t.Tokenizer.End(); err != nil {
+		return err
+	}
+	// set final offset
+	t.offsetAtt.SetOffset(t.finalOffset, t.finalOffset)
+	return nil
+}
+
+func (t *KeywordTokenizer) Reset() error {
+	if err := t.Tokenizer.Reset(); err != nil {
+		return err
+	}
+	t.done = false
+	return nil
+}
+
+// core/KeywordAnalyzer.java
+
+/* "Tokenizes" the entire stream as a single token. This is useful for data like zip codes, ids, and some product names. */
+type KeywordAnalyzer struct {
+	*AnalyzerImpl
+}
+
+func NewKeywordAnalyzer() *KeywordAnalyzer {
+	ans := &KeywordAnalyzer{NewAnalyzer()}
+	ans.Spi = ans
+	return ans
+}
+
+func (a *KeywordAnalyzer) CreateComponents(fieldName string, reader io.RuneReader) *TokenStreamComponents {
+	tokenizer := NewKeywordTokenizer(reader)
+	return NewTokenStreamComponents(tokenizer, tokenizer)
+}
@@ -0,0 +1,151 @@
+package core_test
+
+import (
+	"fmt"
+	. "github.com/balzaczyy/golucene/analysis/core"
+	"github.com/balzaczyy/golucene/analysis/miscellaneous"
+	. "github.com/balzaczyy/golucene/core/analysis"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"github.com/balzaczyy/golucene/core/document"
+	"github.com/balzaczyy/golucene/core/util"
+	"io"
+	"strings"
+	"testing"
+)
+
+func keywordTokens(t *testing.T, ts TokenStream) string {
+	termAtt := ts.Attributes().Add("CharTermAttribute").(CharTermAttribute)
+	offsetAtt := ts.Attributes().Add("OffsetAttribute").(OffsetAttribute)
+	if err := ts.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	var tokens []string
+	for {
+		ok, err := ts.IncrementToken()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			break
+		}
+		tokens = append(tokens, fmt.Sprintf("%v[%v-%v]",
+			string(termAtt.Buffer()[:termAtt.Length()]), offsetAtt.StartOffset(), offsetAtt.EndOffset()))
+	}
+	if err := ts.End(); err != nil {
+		t.Fatal(err)
+	}
+	tokens = append(tokens, fmt.Sprintf("end[%v]", offsetAtt.EndOffset()))
+	if err := ts.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return strings.Join(tokens, " ")
+}
+
+func TestKeywordTokenizer(t *testing.T) {
+	for _, c := range []struct {
+		input, expected string
+	}{
+		{"Hello World!", "Hello World![0-12] end[12]"},
+		{"über straße", "über straße[0-11] end[11]"},
+		{"", "[0-0] end[0]"},
+	} {
+		if got := keywordTokens(t, NewKeywordTokenizer(strings.NewReader(c.input))); got != c.expected {
+			t.Errorf("expected %v, but was %v", c.expected, got)
+		}
+	}
+
+	// a buffer smaller than the input still yields a single token
+	long := strings.Repeat("abc", 100)
+	expected := fmt.Sprintf("%v[0-300] end[300]", long)
+	if got := keywordTokens(t, NewKeywordTokenizerWithBufferSize(strings.NewReader(long), 2)); got != expected {
+		t.Errorf("expected %v, but was %v", expected, got)
+	}
+}
+
+func TestKeywordAnalyzer(t *testing.T) {
+	a := NewKeywordAnalyzer()
+	for _, text := range []string{"Q36", "Q37 and more"} {
+		ts, err := a.TokenStreamForString("partnum", text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := fmt.Sprintf("%v[0-%v] end[%v]", text, len(text), len(text))
+		if got := keywordTokens(t, ts); got != expected {
+			t.Errorf("expected %v, but was %v", expected, got)
+		}
+	}
+}
+
+func newFoldingNormalizer() Normalizer {
+	return NewCustomNormalizer(nil, []func(TokenStream) TokenStream{
+		func(in TokenStream) TokenStream { return NewLowerCaseFilter(util.VERSION_LATEST, in) },
+		func(in TokenStream) TokenStream { return miscellaneous.NewASCIIFoldingFilter(in) },
+	})
+}
+
+func TestCustomNormalizer(t *testing.T) {
+	n := newFoldingNormalizer()
+	for _, c := range []struct {
+		input, expected string
+	}{
+		{"Hello World", "hello world"},
+		{"Crème Brûlée", "creme brulee"},
+		{"", ""},
+	} {
+		got, err := n.Normalize("f", c.input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != c.expected {
+			t.Errorf("expected %v, but was %v", c.expected, got)
+		}
+	}
+
+	// char filters are applied before the token filters
+	n = NewCustomNormalizer([]func(io.RuneReader) io.RuneReader{
+		func(in io.RuneReader) io.RuneReader { return trimReader(in) },
+	}, nil)
+	if got, err := n.Normalize("f", "  Padded  "); err != nil {
+		t.Fatal(err)
+	} else if got != "Padded" {
+		t.Errorf("expected Padded, but was %v", got)
+	}
+
+	// the chain must produce exactly one token
+	n = NewCustomNormalizer(nil, []func(TokenStream) TokenStream{
+		func(in TokenStream) TokenStream {
+			return NewStopFilter(util.VERSION_LATEST, in, map[string]bool{"stop": true})
+		},
+	})
+	if _, err := n.Normalize("f", "stop"); err == nil {
+		t.Error("expected an error for a removed token")
+	}
+}
+
+/* Reads the input with leading and trailing spaces removed. */
+func trimReader(in io.RuneReader) io.RuneReader {
+	var b strings.Builder
+	for {
+		ch, _, err := in.ReadRune()
+		if err != nil {
+			break
+		}
+		b.WriteRune(ch)
+	}
+	return strings.NewReader(strings.TrimSpace(b.String()))
+}
+
+func TestNormalizedStringField(t *testing.T) {
+	ft := document.NewNormalizedStringFieldType(document.STORE_YES, newFoldingNormalizer())
+	f := document.NewFieldFromString("title", "Crème BRÛLÉE", ft)
+	ts, err := f.TokenStream(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, expected := keywordTokens(t, ts), "creme brulee[0-12] end[12]"; got != expected {
+		t.Errorf("expected %v, but was %v", expected, got)
+	}
+	if got := f.StringValue(); got != "Crème BRÛLÉE" {
+		t.Errorf("stored value should be unchanged, but was %v", got)
+	}
+}
@@ -12,6 +12,12 @@ import (
 /*
 Normalizes token text to lower case.
 
+Lower casing is locale-independent: every codepoint is mapped with its
+Unicode lower case mapping, including the ones that expand to several
+codepoints (see CharacterUtils.ToLowerCaseFull()). Use
+tr.TurkishLowerCaseFilter or el.GreekLowerCaseFilter for the case
+rules of those languages.
+
 You may specify the Version
 compatibility when creating LowerCaseFilter:
 
@@ -41,7 +47,10 @@ func (f *LowerCaseFilter) IncrementToken() (bool, error) {
 		return false, err
 	}
 	if ok {
-		f.charUtils.ToLowerCase(f.termAtt.Buffer()[:f.termAtt.Length()])
+		buffer := f.termAtt.Buffer()[:f.termAtt.Length()]
+		if lowered := f.charUtils.ToLowerCaseFull(buffer); len(lowered) != len(buffer) {
+			f.termAtt.CopyBuffer(lowered)
+		}
 		return true, nil
 	}
 	return false, nil
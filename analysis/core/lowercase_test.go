@@ -0,0 +1,35 @@
+package core_test
+
+import (
+	. "github.com/balzaczyy/golucene/analysis/core"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"github.com/balzaczyy/golucene/core/util"
+	"strings"
+	"testing"
+)
+
+func TestLowerCaseFilter(t *testing.T) {
+	for input, expected := range map[string]string{
+		"HELLO":    "hello",
+		"ĞÜŞÖÇ":    "ğüşöç",
+		"ǅ":        "ǆ",
+		"𐐀𐐁":       "𐐨𐐩", // supplementary characters
+		"İSTANBUL": "i̇stanbul",
+		"ΣΊΣΥΦΟΣ":  "σίσυφοσ",
+		"Straße ẞ": "straße ß",
+	} {
+		ts := NewLowerCaseFilter(util.VERSION_LATEST, NewKeywordTokenizer(strings.NewReader(input)))
+		termAtt := ts.Attributes().Get("CharTermAttribute").(CharTermAttribute)
+		if err := ts.Reset(); err != nil {
+			t.Fatal(err)
+		}
+		if ok, err := ts.IncrementToken(); err != nil || !ok {
+			t.Fatalf("%v: expected a token, but was %v, %v", input, ok, err)
+		}
+		if got := string(termAtt.Buffer()[:termAtt.Length()]); got != expected {
+			t.Errorf("%v: expected %q, but was %q", input, expected, got)
+		}
+		ts.End()
+		ts.Close()
+	}
+}
@@ -23,6 +23,10 @@ var ENGLISH_STOP_WORDS_SET = map[string]bool{
 /*
 Removes stop words from a token stream.
 
+Removed words leave a gap: the position increment of the next
+accepted token is increased by the increments of the skipped ones, so
+that phrase and span queries do not match across removed words.
+
 You may specify the Version
 compatibility when creating StopFilter:
 
@@ -31,7 +35,7 @@ compatibility when creating StopFilter:
 */
 type StopFilter struct {
 	*FilteringTokenFilter
-	stopWords map[string]bool
+	stopWords *CharArraySet
 	termAtt   CharTermAttribute
 }
 
@@ -42,14 +46,32 @@ that are named in the Set.
 func NewStopFilter(matchVersion util.Version,
 	in TokenStream, stopWords map[string]bool) *StopFilter {
 
+	return NewStopFilterWithSet(matchVersion, in, NewCharArraySetFromMap(stopWords, false))
+}
+
+/*
+Constructs a filter which removes words from the input TokenStream
+that are named in the CharArraySet. Case is ignored if the set
+ignores case.
+*/
+func NewStopFilterWithSet(matchVersion util.Version,
+	in TokenStream, stopWords *CharArraySet) *StopFilter {
+
 	ans := &StopFilter{stopWords: stopWords}
 	ans.FilteringTokenFilter = NewFilteringTokenFilter(ans, matchVersion, in)
 	ans.termAtt = ans.Attributes().Add("CharTermAttribute").(CharTermAttribute)
 	return ans
 }
 
+/*
+Builds a CharArraySet from an array of stop words, appropriate for
+passing into the StopFilter constructor.
+*/
+func MakeStopSet(stopWords []string, ignoreCase bool) *CharArraySet {
+	return NewCharArraySet(ignoreCase, stopWords...)
+}
+
+/* Returns true if the current token is not a stop word. */
 func (f *StopFilter) Accept() bool {
-	term := string(f.termAtt.Buffer()[:f.termAtt.Length()])
-	_, ok := f.stopWords[term]
-	return !ok
+	return !f.stopWords.Contains(f.termAtt.Buffer()[:f.termAtt.Length()])
 }
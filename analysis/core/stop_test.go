@@ -0,0 +1,56 @@
+package core_test
+
+import (
+	"fmt"
+	. "github.com/balzaczyy/golucene/analysis/core"
+	std "github.com/balzaczyy/golucene/analysis/standard"
+	. "github.com/balzaczyy/golucene/core/analysis"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"github.com/balzaczyy/golucene/core/util"
+	"strings"
+	"testing"
+)
+
+func assertStopFilter(t *testing.T, ts TokenStream, expected string, finalPosIncr int) {
+	termAtt := ts.Attributes().Get("CharTermAttribute").(CharTermAttribute)
+	posIncrAtt := ts.Attributes().Get("PositionIncrementAttribute").(PositionIncrementAttribute)
+	if err := ts.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	var tokens []string
+	for {
+		ok, err := ts.IncrementToken()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			break
+		}
+		tokens = append(tokens, fmt.Sprintf("%v/%v",
+			string(termAtt.Buffer()[:termAtt.Length()]), posIncrAtt.PositionIncrement()))
+	}
+	if err := ts.End(); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(tokens, " "); got != expected {
+		t.Errorf("expected %v, but was %v", expected, got)
+	}
+	if got := posIncrAtt.PositionIncrement(); got != finalPosIncr {
+		t.Errorf("expected final position increment %v, but was %v", finalPosIncr, got)
+	}
+	ts.Close()
+}
+
+func TestStopFilter(t *testing.T) {
+	tokenizer := func(text string) TokenStream {
+		return std.NewStandardTokenizer(util.VERSION_LATEST, strings.NewReader(text))
+	}
+
+	stopWords := MakeStopSet([]string{"is", "the", "Time"}, false)
+	assertStopFilter(t, NewStopFilterWithSet(util.VERSION_LATEST, tokenizer("Now is The Time of the year"), stopWords),
+		"Now/1 The/2 of/2 year/2", 0)
+
+	stopWords = MakeStopSet([]string{"is", "the", "Time"}, true)
+	assertStopFilter(t, NewStopFilterWithSet(util.VERSION_LATEST, tokenizer("Now is The Time of the year of the"), stopWords),
+		"Now/1 of/4 year/2 of/1", 1)
+}
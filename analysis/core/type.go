@@ -0,0 +1,50 @@
+package core
+
+import (
+	. "github.com/balzaczyy/golucene/analysis/util"
+	. "github.com/balzaczyy/golucene/core/analysis"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"github.com/balzaczyy/golucene/core/util"
+)
+
+// core/TypeTokenFilter.java
+
+/*
+Removes tokens whose types appear in a set of blocked types from a
+token stream, or, when used as a whitelist, keeps only those tokens.
+The types are the ones set in TypeAttribute by the tokenizer, such as
+"<NUM>" or "<EMAIL>" for the UAX29URLEmailTokenizer.
+*/
+type TypeTokenFilter struct {
+	*FilteringTokenFilter
+	stopTypes    map[string]bool
+	useWhiteList bool
+	typeAtt      TypeAttribute
+}
+
+/* Create a new TypeTokenFilter that filters tokens out (blacklist). */
+func NewTypeTokenFilter(version util.Version, input TokenStream, stopTypes map[string]bool) *TypeTokenFilter {
+	return NewTypeTokenFilterWithWhiteList(version, input, stopTypes, false)
+}
+
+/*
+Create a new TypeTokenFilter. If useWhiteList is true, the stopTypes
+are the types to keep, otherwise the types to remove.
+*/
+func NewTypeTokenFilterWithWhiteList(version util.Version, input TokenStream,
+	stopTypes map[string]bool, useWhiteList bool) *TypeTokenFilter {
+
+	ans := &TypeTokenFilter{stopTypes: stopTypes, useWhiteList: useWhiteList}
+	ans.FilteringTokenFilter = NewFilteringTokenFilter(ans, version, input)
+	ans.typeAtt = ans.Attributes().Add("TypeAttribute").(TypeAttribute)
+	return ans
+}
+
+/*
+By default accept the token if its type is not a stop type. When the
+useWhiteList parameter is set to true then accept the token if its
+type is contained in the stopTypes.
+*/
+func (f *TypeTokenFilter) Accept() bool {
+	return f.useWhiteList == f.stopTypes[f.typeAtt.Type()]
+}
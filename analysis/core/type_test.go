@@ -0,0 +1,44 @@
+package core_test
+
+import (
+	. "github.com/balzaczyy/golucene/analysis/core"
+	std "github.com/balzaczyy/golucene/analysis/standard"
+	"github.com/balzaczyy/golucene/core/util"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTypeTokenFilter(t *testing.T) {
+	numbers := map[string]bool{"<NUM>": true}
+	ts := NewTypeTokenFilter(util.VERSION_LATEST, std.NewStandardTokenizer(util.VERSION_LATEST,
+		strings.NewReader("121 is palindrome, while 123 is not")), numbers)
+	assertStopFilter(t, ts, "is/2 palindrome/1 while/1 is/2 not/1", 0)
+
+	ts = NewTypeTokenFilterWithWhiteList(util.VERSION_LATEST, std.NewStandardTokenizer(util.VERSION_LATEST,
+		strings.NewReader("121 is palindrome, while 123 is not")), numbers, true)
+	assertStopFilter(t, ts, "121/1 123/4", 2)
+}
+
+func TestTypeTokenFilterFactory(t *testing.T) {
+	if _, err := NewTypeTokenFilterFactory(map[string]string{}); err == nil {
+		t.Error("expected an error for the missing types")
+	}
+	dir, err := ioutil.TempDir("", "types")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "types.txt")
+	if err = ioutil.WriteFile(file, []byte("# numbers only\n<NUM>\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	f, err := NewTypeTokenFilterFactory(map[string]string{"types": file, "useWhitelist": "true"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := f.Create(std.NewStandardTokenizer(util.VERSION_LATEST, strings.NewReader("a 1 b 2")))
+	assertStopFilter(t, ts, "1/2 2/2", 0)
+}
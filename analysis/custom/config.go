@@ -0,0 +1,67 @@
+package custom
+
+import (
+	"encoding/json"
+)
+
+/*
+The configuration of a CustomAnalyzer, which can be decoded from JSON
+or YAML, like
+
+	{
+		"charFilters": [{"type": "htmlStrip"}],
+		"tokenizer": {"type": "standard", "params": {"maxTokenLength": "255"}},
+		"tokenFilters": [
+			{"type": "lowercase"},
+			{"type": "stop", "params": {"ignoreCase": "true", "words": "stopwords.txt"}}
+		],
+		"positionIncrementGap": 100
+	}
+
+Components are looked up by type in the registries of TokenizerFactory,
+TokenFilterFactory and CharFilterFactory, and created with their
+params.
+*/
+type AnalyzerConfig struct {
+	CharFilters          []ComponentConfig `json:"charFilters,omitempty" yaml:"charFilters,omitempty"`
+	Tokenizer            ComponentConfig   `json:"tokenizer" yaml:"tokenizer"`
+	TokenFilters         []ComponentConfig `json:"tokenFilters,omitempty" yaml:"tokenFilters,omitempty"`
+	PositionIncrementGap *int              `json:"positionIncrementGap,omitempty" yaml:"positionIncrementGap,omitempty"`
+	OffsetGap            *int              `json:"offsetGap,omitempty" yaml:"offsetGap,omitempty"`
+}
+
+/* The registered name and the parameters of an analysis component. */
+type ComponentConfig struct {
+	Type   string            `json:"type" yaml:"type"`
+	Params map[string]string `json:"params,omitempty" yaml:"params,omitempty"`
+}
+
+/* Builds the analyzer described by the configuration. */
+func (c *AnalyzerConfig) Build() (*CustomAnalyzer, error) {
+	b := NewCustomAnalyzerBuilder()
+	for _, charFilter := range c.CharFilters {
+		b.AddCharFilterArgs(charFilter.Type, charFilter.Params)
+	}
+	if c.Tokenizer.Type != "" {
+		b.WithTokenizerArgs(c.Tokenizer.Type, c.Tokenizer.Params)
+	}
+	for _, tokenFilter := range c.TokenFilters {
+		b.AddTokenFilterArgs(tokenFilter.Type, tokenFilter.Params)
+	}
+	if c.PositionIncrementGap != nil {
+		b.WithPositionIncrementGap(*c.PositionIncrementGap)
+	}
+	if c.OffsetGap != nil {
+		b.WithOffsetGap(*c.OffsetGap)
+	}
+	return b.Build()
+}
+
+/* Builds the analyzer described by the JSON configuration, see AnalyzerConfig. */
+func NewCustomAnalyzerFromJSON(data []byte) (*CustomAnalyzer, error) {
+	var config AnalyzerConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+	return config.Build()
+}
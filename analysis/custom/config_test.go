@@ -0,0 +1,80 @@
+package custom
+
+import (
+	_ "github.com/balzaczyy/golucene/analysis/ngram"
+	_ "github.com/balzaczyy/golucene/analysis/shingle"
+	. "github.com/balzaczyy/golucene/analysis/util"
+	"testing"
+)
+
+func TestCustomAnalyzerFromJSON(t *testing.T) {
+	a, err := NewCustomAnalyzerFromJSON([]byte(`{
+		"charFilters": [{"type": "htmlStrip"}],
+		"tokenizer": {"type": "standard"},
+		"tokenFilters": [
+			{"type": "lowercase"},
+			{"type": "shingle", "params": {"outputUnigrams": "false", "tokenSeparator": "_"}}
+		],
+		"positionIncrementGap": 10
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "quick_brown[3-18] brown_fox[13-22]"
+	if got := tokens(t, a, "<b>Quick</b> Brown Fox"); got != expected {
+		t.Errorf("expected %v, but was %v", expected, got)
+	}
+	if gap := a.PositionIncrementGap("field"); gap != 10 {
+		t.Errorf("expected position increment gap 10, but was %v", gap)
+	}
+
+	config := &AnalyzerConfig{
+		Tokenizer:    ComponentConfig{Type: "keyword"},
+		TokenFilters: []ComponentConfig{{Type: "edgeNGram", Params: map[string]string{"maxGramSize": "3"}}},
+	}
+	if a, err = config.Build(); err != nil {
+		t.Fatal(err)
+	}
+	expected = "f[0-6] fo[0-6] foo[0-6]"
+	if got := tokens(t, a, "foobar"); got != expected {
+		t.Errorf("expected %v, but was %v", expected, got)
+	}
+
+	for _, data := range []string{
+		`{"tokenFilters": [{"type": "lowercase"}]}`,
+		`{"tokenizer": {"type": "whatever"}}`,
+		`{"tokenizer": {"type": "standard"}, "offsetGap": -1}`,
+		`{"tokenizer": {"type": "nGram", "params": {"minGramSize": "3", "maxGramSize": "2"}}}`,
+		`{"tokenizer": "standard"}`,
+	} {
+		if _, err := NewCustomAnalyzerFromJSON([]byte(data)); err == nil {
+			t.Errorf("%v: expected an error", data)
+		}
+	}
+}
+
+func TestAvailableFactories(t *testing.T) {
+	contains := func(names []string, name string) bool {
+		for _, n := range names {
+			if n == name {
+				return true
+			}
+		}
+		return false
+	}
+	for _, name := range []string{"standard", "keyword", "pattern", "ngram"} {
+		if !contains(AvailableTokenizers(), name) {
+			t.Errorf("expected tokenizer %v in %v", name, AvailableTokenizers())
+		}
+	}
+	for _, name := range []string{"lowercase", "stop", "shingle", "ngram", "edgengram"} {
+		if !contains(AvailableTokenFilters(), name) {
+			t.Errorf("expected token filter %v in %v", name, AvailableTokenFilters())
+		}
+	}
+	for _, name := range []string{"htmlstrip", "mapping", "patternreplace"} {
+		if !contains(AvailableCharFilters(), name) {
+			t.Errorf("expected char filter %v in %v", name, AvailableCharFilters())
+		}
+	}
+}
@@ -0,0 +1,236 @@
+package custom
+
+import (
+	"errors"
+	"fmt"
+	_ "github.com/balzaczyy/golucene/analysis/charfilter"
+	_ "github.com/balzaczyy/golucene/analysis/core"
+	_ "github.com/balzaczyy/golucene/analysis/miscellaneous"
+	_ "github.com/balzaczyy/golucene/analysis/pattern"
+	_ "github.com/balzaczyy/golucene/analysis/standard"
+	. "github.com/balzaczyy/golucene/analysis/util"
+	. "github.com/balzaczyy/golucene/core/analysis"
+	"io"
+)
+
+// custom/CustomAnalyzer.java
+
+/*
+A general-purpose Analyzer that can be created with a builder-style
+API. Under the hood it uses the factory classes TokenizerFactory,
+TokenFilterFactory, and CharFilterFactory, looked up by name.
+
+You can create an instance of this Analyzer using the builder:
+
+	analyzer, err := NewCustomAnalyzerBuilder().
+		WithTokenizer("standard").
+		AddTokenFilter("lowercase").
+		AddTokenFilter("stop", "ignoreCase", "false", "words", "stopwords.txt").
+		Build()
+
+or from an AnalyzerConfig decoded from JSON or YAML.
+
+The parameters passed to components are key-value pairs. The factories
+of this package's dependencies are always available; the ones of other
+packages are available once those packages are imported.
+*/
+type CustomAnalyzer struct {
+	*AnalyzerImpl
+	charFilters          []CharFilterFactory
+	tokenizer            TokenizerFactory
+	tokenFilters         []TokenFilterFactory
+	posIncGap, offsetGap int // -1 for the defaults
+}
+
+func (a *CustomAnalyzer) InitReader(fieldName string, reader io.RuneReader) io.RuneReader {
+	for _, charFilter := range a.charFilters {
+		reader = charFilter.Create(reader)
+	}
+	return reader
+}
+
+func (a *CustomAnalyzer) CreateComponents(fieldName string, reader io.RuneReader) *TokenStreamComponents {
+	tk := a.tokenizer.Create(reader)
+	var ts TokenStream = tk
+	for _, filter := range a.tokenFilters {
+		ts = filter.Create(ts)
+	}
+	return NewTokenStreamComponents(tk, ts)
+}
+
+func (a *CustomAnalyzer) PositionIncrementGap(fieldName string) int {
+	if a.posIncGap < 0 {
+		return a.AnalyzerImpl.PositionIncrementGap(fieldName)
+	}
+	return a.posIncGap
+}
+
+func (a *CustomAnalyzer) OffsetGap(fieldName string) int {
+	if a.offsetGap < 0 {
+		return a.AnalyzerImpl.OffsetGap(fieldName)
+	}
+	return a.offsetGap
+}
+
+/* Returns the list of char filters that are used in this analyzer. */
+func (a *CustomAnalyzer) CharFilterFactories() []CharFilterFactory {
+	return a.charFilters
+}
+
+/* Returns the tokenizer that is used in this analyzer. */
+func (a *CustomAnalyzer) TokenizerFactory() TokenizerFactory {
+	return a.tokenizer
+}
+
+/* Returns the list of token filters that are used in this analyzer. */
+func (a *CustomAnalyzer) TokenFilterFactories() []TokenFilterFactory {
+	return a.tokenFilters
+}
+
+// custom/CustomAnalyzer.java#Builder
+
+/*
+Builder for CustomAnalyzer. Components are added in order; the first
+error met, like an unknown component name or an invalid parameter, is
+reported by Build().
+*/
+type CustomAnalyzerBuilder struct {
+	charFilters          []CharFilterFactory
+	tokenizer            TokenizerFactory
+	tokenFilters         []TokenFilterFactory
+	posIncGap, offsetGap int
+	err                  error
+}
+
+func NewCustomAnalyzerBuilder() *CustomAnalyzerBuilder {
+	return &CustomAnalyzerBuilder{posIncGap: -1, offsetGap: -1}
+}
+
+/* Records the first error met building the analyzer. */
+func (b *CustomAnalyzerBuilder) fail(err error) *CustomAnalyzerBuilder {
+	if b.err == nil {
+		b.err = err
+	}
+	return b
+}
+
+func paramsToMap(params []string) (map[string]string, error) {
+	if len(params)%2 != 0 {
+		return nil, errors.New("Key-value pairs expected, so the number of params must be even.")
+	}
+	ans := make(map[string]string)
+	for i := 0; i < len(params); i += 2 {
+		if _, ok := ans[params[i]]; ok {
+			return nil, fmt.Errorf("Key '%v' is duplicate in parameter list.", params[i])
+		}
+		ans[params[i]] = params[i+1]
+	}
+	return ans, nil
+}
+
+/*
+Uses the given tokenizer, with the given key-value pairs of
+parameters.
+*/
+func (b *CustomAnalyzerBuilder) WithTokenizer(name string, params ...string) *CustomAnalyzerBuilder {
+	args, err := paramsToMap(params)
+	if err != nil {
+		return b.fail(err)
+	}
+	return b.WithTokenizerArgs(name, args)
+}
+
+/* Uses the given tokenizer, with the given parameters. */
+func (b *CustomAnalyzerBuilder) WithTokenizerArgs(name string, args map[string]string) *CustomAnalyzerBuilder {
+	if b.tokenizer != nil {
+		return b.fail(errors.New("Tokenizer already set."))
+	}
+	factory, err := NewTokenizerFactory(name, args)
+	if err != nil {
+		return b.fail(err)
+	}
+	b.tokenizer = factory
+	return b
+}
+
+/*
+Adds the given token filter, with the given key-value pairs of
+parameters.
+*/
+func (b *CustomAnalyzerBuilder) AddTokenFilter(name string, params ...string) *CustomAnalyzerBuilder {
+	args, err := paramsToMap(params)
+	if err != nil {
+		return b.fail(err)
+	}
+	return b.AddTokenFilterArgs(name, args)
+}
+
+/* Adds the given token filter, with the given parameters. */
+func (b *CustomAnalyzerBuilder) AddTokenFilterArgs(name string, args map[string]string) *CustomAnalyzerBuilder {
+	factory, err := NewTokenFilterFactory(name, args)
+	if err != nil {
+		return b.fail(err)
+	}
+	b.tokenFilters = append(b.tokenFilters, factory)
+	return b
+}
+
+/*
+Adds the given char filter, with the given key-value pairs of
+parameters.
+*/
+func (b *CustomAnalyzerBuilder) AddCharFilter(name string, params ...string) *CustomAnalyzerBuilder {
+	args, err := paramsToMap(params)
+	if err != nil {
+		return b.fail(err)
+	}
+	return b.AddCharFilterArgs(name, args)
+}
+
+/* Adds the given char filter, with the given parameters. */
+func (b *CustomAnalyzerBuilder) AddCharFilterArgs(name string, args map[string]string) *CustomAnalyzerBuilder {
+	factory, err := NewCharFilterFactory(name, args)
+	if err != nil {
+		return b.fail(err)
+	}
+	b.charFilters = append(b.charFilters, factory)
+	return b
+}
+
+/* Sets the position increment gap of the analyzer. */
+func (b *CustomAnalyzerBuilder) WithPositionIncrementGap(posIncGap int) *CustomAnalyzerBuilder {
+	if posIncGap < 0 {
+		return b.fail(errors.New("posIncGap must be >= 0"))
+	}
+	b.posIncGap = posIncGap
+	return b
+}
+
+/* Sets the offset gap of the analyzer. */
+func (b *CustomAnalyzerBuilder) WithOffsetGap(offsetGap int) *CustomAnalyzerBuilder {
+	if offsetGap < 0 {
+		return b.fail(errors.New("offsetGap must be >= 0"))
+	}
+	b.offsetGap = offsetGap
+	return b
+}
+
+/* Builds the analyzer, or returns the first error met configuring it. */
+func (b *CustomAnalyzerBuilder) Build() (*CustomAnalyzer, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	if b.tokenizer == nil {
+		return nil, errors.New("You have to set at least a tokenizer.")
+	}
+	ans := &CustomAnalyzer{
+		AnalyzerImpl: NewAnalyzer(),
+		charFilters:  b.charFilters,
+		tokenizer:    b.tokenizer,
+		tokenFilters: b.tokenFilters,
+		posIncGap:    b.posIncGap,
+		offsetGap:    b.offsetGap,
+	}
+	ans.Spi = ans
+	return ans, nil
+}
@@ -0,0 +1,107 @@
+package custom
+
+import (
+	"fmt"
+	. "github.com/balzaczyy/golucene/core/analysis"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"strings"
+	"testing"
+)
+
+func tokens(t *testing.T, a Analyzer, text string) string {
+	ts, err := a.TokenStreamForString("field", text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	termAtt := ts.Attributes().Add("CharTermAttribute").(CharTermAttribute)
+	offsetAtt := ts.Attributes().Add("OffsetAttribute").(OffsetAttribute)
+	if err = ts.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	var ans []string
+	for {
+		ok, err := ts.IncrementToken()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			break
+		}
+		ans = append(ans, fmt.Sprintf("%v[%v-%v]",
+			string(termAtt.Buffer()[:termAtt.Length()]), offsetAtt.StartOffset(), offsetAtt.EndOffset()))
+	}
+	if err = ts.End(); err != nil {
+		t.Fatal(err)
+	}
+	if err = ts.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return strings.Join(ans, " ")
+}
+
+func TestCustomAnalyzer(t *testing.T) {
+	a, err := NewCustomAnalyzerBuilder().
+		AddCharFilter("htmlStrip").
+		WithTokenizer("standard").
+		AddTokenFilter("lowercase").
+		AddTokenFilter("stop").
+		WithPositionIncrementGap(100).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "quick[11-16] fox[20-27]"
+	if got := tokens(t, a, "<b>The</b> Quick <i>Fox</i>"); got != expected {
+		t.Errorf("expected %v, but was %v", expected, got)
+	}
+	// components are reused
+	expected = "brown[0-5]"
+	if got := tokens(t, a, "Brown"); got != expected {
+		t.Errorf("expected %v, but was %v", expected, got)
+	}
+	if n := len(a.TokenFilterFactories()); n != 2 {
+		t.Errorf("expected 2 token filters, but was %v", n)
+	}
+	if gap := a.PositionIncrementGap("field"); gap != 100 {
+		t.Errorf("expected position increment gap 100, but was %v", gap)
+	}
+	if gap := a.OffsetGap("field"); gap != 1 {
+		t.Errorf("expected offset gap 1, but was %v", gap)
+	}
+}
+
+func TestCustomAnalyzerParams(t *testing.T) {
+	a, err := NewCustomAnalyzerBuilder().
+		WithTokenizer("pattern", "pattern", "-").
+		AddTokenFilter("patternReplace", "pattern", "[aeiou]", "replacement", "*").
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "f**[0-3] b*r[4-7]"
+	if got := tokens(t, a, "foo-bar"); got != expected {
+		t.Errorf("expected %v, but was %v", expected, got)
+	}
+}
+
+func TestCustomAnalyzerValidation(t *testing.T) {
+	for _, c := range []struct {
+		name    string
+		builder *CustomAnalyzerBuilder
+	}{
+		{"no tokenizer", NewCustomAnalyzerBuilder().AddTokenFilter("lowercase")},
+		{"two tokenizers", NewCustomAnalyzerBuilder().WithTokenizer("standard").WithTokenizer("keyword")},
+		{"unknown tokenizer", NewCustomAnalyzerBuilder().WithTokenizer("whatever")},
+		{"unknown filter", NewCustomAnalyzerBuilder().WithTokenizer("standard").AddTokenFilter("whatever")},
+		{"odd params", NewCustomAnalyzerBuilder().WithTokenizer("standard", "maxTokenLength")},
+		{"duplicate params", NewCustomAnalyzerBuilder().WithTokenizer("pattern", "pattern", "a", "pattern", "b")},
+		{"unknown param", NewCustomAnalyzerBuilder().WithTokenizer("standard", "foo", "bar")},
+		{"invalid int", NewCustomAnalyzerBuilder().WithTokenizer("standard", "maxTokenLength", "x")},
+		{"missing param", NewCustomAnalyzerBuilder().WithTokenizer("pattern")},
+		{"invalid gap", NewCustomAnalyzerBuilder().WithTokenizer("standard").WithOffsetGap(-1)},
+	} {
+		if _, err := c.builder.Build(); err == nil {
+			t.Errorf("%v: expected an error", c.name)
+		}
+	}
+}
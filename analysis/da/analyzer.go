@@ -0,0 +1,87 @@
+package da
+
+import (
+	. "github.com/balzaczyy/golucene/analysis/core"
+	. "github.com/balzaczyy/golucene/analysis/miscellaneous"
+	"github.com/balzaczyy/golucene/analysis/snowball"
+	std "github.com/balzaczyy/golucene/analysis/standard"
+	. "github.com/balzaczyy/golucene/analysis/util"
+	. "github.com/balzaczyy/golucene/core/analysis"
+	"io"
+)
+
+// da/DanishAnalyzer.java
+
+/* The default set of stopwords used by DanishAnalyzer, from the Snowball danish stop list. */
+var DEFAULT_STOPWORD_SET = map[string]bool{
+	"og": true, "i": true, "jeg": true, "det": true, "at": true,
+	"en": true, "den": true, "til": true, "er": true, "som": true,
+	"på": true, "de": true, "med": true, "han": true, "af": true,
+	"for": true, "ikke": true, "der": true, "var": true, "mig": true,
+	"sig": true, "men": true, "et": true, "har": true, "om": true,
+	"vi": true, "min": true, "havde": true, "ham": true, "hun": true,
+	"nu": true, "over": true, "da": true, "fra": true, "du": true,
+	"ud": true, "sin": true, "dem": true, "os": true, "op": true,
+	"man": true, "hans": true, "hvor": true, "eller": true, "hvad": true,
+	"skal": true, "selv": true, "her": true, "alle": true, "vil": true,
+	"blev": true, "kunne": true, "ind": true, "når": true, "være": true,
+	"dog": true, "noget": true, "ville": true, "jo": true, "deres": true,
+	"efter": true, "ned": true, "skulle": true, "denne": true,
+	"end": true, "dette": true, "mit": true, "også": true, "under": true,
+	"have": true, "dig": true, "anden": true, "hende": true, "mine": true,
+	"alt": true, "meget": true, "sit": true, "sine": true, "vor": true,
+	"mod": true, "disse": true, "hvis": true, "din": true, "nogle": true,
+	"hos": true, "blive": true, "mange": true, "ad": true, "bliver": true,
+	"hendes": true, "været": true, "thi": true, "jer": true,
+	"sådan": true,
+}
+
+/*
+Analyzer for Danish.
+
+Builds an analysis chain made of StandardTokenizer, StandardFilter,
+LowerCaseFilter, StopFilter, SetKeywordMarkerFilter if a stem
+exclusion set is provided, and SnowballFilter with the danish stemmer.
+*/
+type DanishAnalyzer struct {
+	*StopwordAnalyzerBase
+	stemExclusionSet map[string]bool
+}
+
+/* Builds an analyzer with the default stop words: DEFAULT_STOPWORD_SET. */
+func NewDanishAnalyzer() *DanishAnalyzer {
+	return NewDanishAnalyzerWithStopWords(DEFAULT_STOPWORD_SET)
+}
+
+/* Builds an analyzer with the given stop words. */
+func NewDanishAnalyzerWithStopWords(stopwords map[string]bool) *DanishAnalyzer {
+	return NewDanishAnalyzerWithStemExclusions(stopwords, nil)
+}
+
+/*
+Builds an analyzer with the given stop words. If a non-empty stem
+exclusion set is provided this analyzer will add a
+SetKeywordMarkerFilter before stemming.
+*/
+func NewDanishAnalyzerWithStemExclusions(stopwords, stemExclusionSet map[string]bool) *DanishAnalyzer {
+	ans := &DanishAnalyzer{stemExclusionSet: make(map[string]bool)}
+	for k, v := range stemExclusionSet {
+		ans.stemExclusionSet[k] = v
+	}
+	ans.StopwordAnalyzerBase = NewStopwordAnalyzerBaseWithStopWords(stopwords)
+	ans.Spi = ans
+	return ans
+}
+
+func (a *DanishAnalyzer) CreateComponents(fieldName string, reader io.RuneReader) *TokenStreamComponents {
+	version := a.Version()
+	source := std.NewStandardTokenizer(version, reader)
+	var result TokenStream = std.NewStandardFilter(version, source)
+	result = NewLowerCaseFilter(version, result)
+	result = NewStopFilter(version, result, a.StopwordSet())
+	if len(a.stemExclusionSet) > 0 {
+		result = NewSetKeywordMarkerFilter(result, a.stemExclusionSet)
+	}
+	result = snowball.NewSnowballFilter(result, snowball.DanishStemmer{})
+	return NewTokenStreamComponents(source, result)
+}
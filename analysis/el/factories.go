@@ -0,0 +1,35 @@
+package el
+
+import (
+	. "github.com/balzaczyy/golucene/analysis/util"
+	. "github.com/balzaczyy/golucene/core/analysis"
+)
+
+func init() {
+	RegisterTokenFilterFactory("greekLowercase", func(args map[string]string) (TokenFilterFactory, error) {
+		f, err := NewGreekLowerCaseFilterFactory(args)
+		if err != nil {
+			return nil, err
+		}
+		return f, nil
+	})
+}
+
+// el/GreekLowerCaseFilterFactory.java
+
+/* Factory for GreekLowerCaseFilter. */
+type GreekLowerCaseFilterFactory struct {
+	*AbstractAnalysisFactory
+}
+
+func NewGreekLowerCaseFilterFactory(args map[string]string) (*GreekLowerCaseFilterFactory, error) {
+	ans := &GreekLowerCaseFilterFactory{NewAbstractAnalysisFactory(args)}
+	if err := ans.Validate(); err != nil {
+		return nil, err
+	}
+	return ans, nil
+}
+
+func (f *GreekLowerCaseFilterFactory) Create(input TokenStream) TokenStream {
+	return NewGreekLowerCaseFilter(input)
+}
@@ -0,0 +1,82 @@
+package el
+
+import (
+	. "github.com/balzaczyy/golucene/core/analysis"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"unicode"
+)
+
+// el/GreekLowerCaseFilter.java
+
+/*
+Normalizes token text to lower case, removes some Greek diacritics,
+and standardizes final sigma to sigma, so that words match whether or
+not they were written with accents, or at the end of a word.
+*/
+type GreekLowerCaseFilter struct {
+	*TokenFilter
+	input   TokenStream
+	termAtt CharTermAttribute
+}
+
+/* Create a GreekLowerCaseFilter that normalizes Greek token text. */
+func NewGreekLowerCaseFilter(in TokenStream) *GreekLowerCaseFilter {
+	ans := &GreekLowerCaseFilter{
+		TokenFilter: NewTokenFilter(in),
+		input:       in,
+	}
+	ans.termAtt = ans.Attributes().Add("CharTermAttribute").(CharTermAttribute)
+	return ans
+}
+
+func (f *GreekLowerCaseFilter) IncrementToken() (bool, error) {
+	ok, err := f.input.IncrementToken()
+	if err != nil || !ok {
+		return false, err
+	}
+	buffer := f.termAtt.Buffer()[:f.termAtt.Length()]
+	for i, ch := range buffer {
+		buffer[i] = greekLowerCase(ch)
+	}
+	return true, nil
+}
+
+func greekLowerCase(ch rune) rune {
+	switch ch {
+	case 'ς': // small final sigma
+		return 'σ' // small sigma
+
+	// Some Greek characters contain diacritics.
+	// This filter removes these, converting to the lowercase base form.
+
+	case 'Ά', 'ά': // capital/small alpha with tonos
+		return 'α' // small alpha
+
+	case 'Έ', 'έ': // capital/small epsilon with tonos
+		return 'ε' // small epsilon
+
+	case 'Ή', 'ή': // capital/small eta with tonos
+		return 'η' // small eta
+
+	case 'Ί', 'Ϊ', 'ί', 'ϊ', 'ΐ': // iota with tonos or dialytika
+		return 'ι' // small iota
+
+	case 'Ύ', 'Ϋ', 'ύ', 'ϋ', 'ΰ': // upsilon with tonos or dialytika
+		return 'υ' // small upsilon
+
+	case 'Ό', 'ό': // capital/small omicron with tonos
+		return 'ο' // small omicron
+
+	case 'Ώ', 'ώ': // capital/small omega with tonos
+		return 'ω' // small omega
+
+	// The previous implementation did the conversion below.
+	// Only implemented for backwards compatibility with old indexes.
+
+	case '΢': // reserved
+		return 'ς' // small final sigma
+
+	default:
+		return unicode.ToLower(ch)
+	}
+}
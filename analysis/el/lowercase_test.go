@@ -0,0 +1,32 @@
+package el
+
+import (
+	"github.com/balzaczyy/golucene/analysis/core"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"strings"
+	"testing"
+)
+
+func TestGreekLowerCaseFilter(t *testing.T) {
+	for input, expected := range map[string]string{
+		"ΜΆΪΟΣ":   "μαιοσ",
+		"Μάϊος":   "μαιοσ",
+		"ΧΩΡΑ":    "χωρα",
+		"ΐΰώ":     "ιυω",
+		"Ελλάδας": "ελλαδασ",
+	} {
+		ts := NewGreekLowerCaseFilter(core.NewKeywordTokenizer(strings.NewReader(input)))
+		termAtt := ts.Attributes().Get("CharTermAttribute").(CharTermAttribute)
+		if err := ts.Reset(); err != nil {
+			t.Fatal(err)
+		}
+		if ok, err := ts.IncrementToken(); err != nil || !ok {
+			t.Fatalf("%v: expected a token, but was %v, %v", input, ok, err)
+		}
+		if got := string(termAtt.Buffer()[:termAtt.Length()]); got != expected {
+			t.Errorf("%v: expected %v, but was %v", input, expected, got)
+		}
+		ts.End()
+		ts.Close()
+	}
+}
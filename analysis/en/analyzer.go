@@ -0,0 +1,67 @@
+package en
+
+import (
+	. "github.com/balzaczyy/golucene/analysis/core"
+	. "github.com/balzaczyy/golucene/analysis/miscellaneous"
+	std "github.com/balzaczyy/golucene/analysis/standard"
+	. "github.com/balzaczyy/golucene/analysis/util"
+	. "github.com/balzaczyy/golucene/core/analysis"
+	"io"
+)
+
+// en/EnglishAnalyzer.java
+
+/* The default set of stopwords used by EnglishAnalyzer. */
+var DEFAULT_STOPWORD_SET = std.STOP_WORDS_SET
+
+/*
+Analyzer for English.
+
+Builds an analysis chain made of StandardTokenizer, StandardFilter,
+EnglishPossessiveFilter, LowerCaseFilter, StopFilter,
+SetKeywordMarkerFilter if a stem exclusion set is provided, and
+Porter2StemFilter.
+*/
+type EnglishAnalyzer struct {
+	*StopwordAnalyzerBase
+	stemExclusionSet map[string]bool
+}
+
+/* Builds an analyzer with the default stop words: DEFAULT_STOPWORD_SET. */
+func NewEnglishAnalyzer() *EnglishAnalyzer {
+	return NewEnglishAnalyzerWithStopWords(DEFAULT_STOPWORD_SET)
+}
+
+/* Builds an analyzer with the given stop words. */
+func NewEnglishAnalyzerWithStopWords(stopwords map[string]bool) *EnglishAnalyzer {
+	return NewEnglishAnalyzerWithStemExclusions(stopwords, nil)
+}
+
+/*
+Builds an analyzer with the given stop words. If a non-empty stem
+exclusion set is provided this analyzer will add a
+SetKeywordMarkerFilter before stemming.
+*/
+func NewEnglishAnalyzerWithStemExclusions(stopwords, stemExclusionSet map[string]bool) *EnglishAnalyzer {
+	ans := &EnglishAnalyzer{stemExclusionSet: make(map[string]bool)}
+	for k, v := range stemExclusionSet {
+		ans.stemExclusionSet[k] = v
+	}
+	ans.StopwordAnalyzerBase = NewStopwordAnalyzerBaseWithStopWords(stopwords)
+	ans.Spi = ans
+	return ans
+}
+
+func (a *EnglishAnalyzer) CreateComponents(fieldName string, reader io.RuneReader) *TokenStreamComponents {
+	version := a.Version()
+	source := std.NewStandardTokenizer(version, reader)
+	var result TokenStream = std.NewStandardFilter(version, source)
+	result = NewEnglishPossessiveFilter(result)
+	result = NewLowerCaseFilter(version, result)
+	result = NewStopFilter(version, result, a.StopwordSet())
+	if len(a.stemExclusionSet) > 0 {
+		result = NewSetKeywordMarkerFilter(result, a.stemExclusionSet)
+	}
+	result = NewPorter2StemFilter(result)
+	return NewTokenStreamComponents(source, result)
+}
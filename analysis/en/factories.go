@@ -0,0 +1,85 @@
+package en
+
+import (
+	. "github.com/balzaczyy/golucene/analysis/util"
+	. "github.com/balzaczyy/golucene/core/analysis"
+)
+
+func init() {
+	RegisterTokenFilterFactory("englishPossessive", func(args map[string]string) (TokenFilterFactory, error) {
+		f, err := NewEnglishPossessiveFilterFactory(args)
+		if err != nil {
+			return nil, err
+		}
+		return f, nil
+	})
+	RegisterTokenFilterFactory("kStem", func(args map[string]string) (TokenFilterFactory, error) {
+		f, err := NewKStemFilterFactory(args)
+		if err != nil {
+			return nil, err
+		}
+		return f, nil
+	})
+	RegisterTokenFilterFactory("porter2Stem", func(args map[string]string) (TokenFilterFactory, error) {
+		f, err := NewPorter2StemFilterFactory(args)
+		if err != nil {
+			return nil, err
+		}
+		return f, nil
+	})
+}
+
+// en/EnglishPossessiveFilterFactory.java
+
+/* Factory for EnglishPossessiveFilter. */
+type EnglishPossessiveFilterFactory struct {
+	*AbstractAnalysisFactory
+}
+
+func NewEnglishPossessiveFilterFactory(args map[string]string) (*EnglishPossessiveFilterFactory, error) {
+	ans := &EnglishPossessiveFilterFactory{NewAbstractAnalysisFactory(args)}
+	if err := ans.Validate(); err != nil {
+		return nil, err
+	}
+	return ans, nil
+}
+
+func (f *EnglishPossessiveFilterFactory) Create(input TokenStream) TokenStream {
+	return NewEnglishPossessiveFilter(input)
+}
+
+// en/KStemFilterFactory.java
+
+/* Factory for KStemFilter. */
+type KStemFilterFactory struct {
+	*AbstractAnalysisFactory
+}
+
+func NewKStemFilterFactory(args map[string]string) (*KStemFilterFactory, error) {
+	ans := &KStemFilterFactory{NewAbstractAnalysisFactory(args)}
+	if err := ans.Validate(); err != nil {
+		return nil, err
+	}
+	return ans, nil
+}
+
+func (f *KStemFilterFactory) Create(input TokenStream) TokenStream {
+	return NewKStemFilter(input)
+}
+
+/* Factory for Porter2StemFilter. */
+type Porter2StemFilterFactory struct {
+	*AbstractAnalysisFactory
+}
+
+func NewPorter2StemFilterFactory(args map[string]string) (*Porter2StemFilterFactory, error) {
+	ans := &Porter2StemFilterFactory{NewAbstractAnalysisFactory(args)}
+	if err := ans.Validate(); err != nil {
+		return nil, err
+	}
+	return ans, nil
+}
+
+func (f *Porter2StemFilterFactory) Create(input TokenStream) TokenStream {
+	return NewPorter2StemFilter(input)
+}
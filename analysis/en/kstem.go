@@ -0,0 +1,471 @@
+package en
+
+import (
+	. "github.com/balzaczyy/golucene/core/analysis"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"strings"
+)
+
+// en/KStemmer.java
+
+/*
+A light-weight English stemmer, based on the algorithm by Bob Krovetz.
+
+Inflectional endings (plurals, past tense and -ing forms) are always
+removed. Derivational endings (-ity, -ness, -ion, -er, -ly, -al, ...)
+are only removed when the resulting stem is a known word, which makes
+KStem much less aggressive than Porter: "organization" is reduced to
+"organize" rather than to "organ".
+
+Input must be lower case; words shorter than 3 characters or
+containing characters other than a-z are returned unchanged.
+*/
+type KStemmer struct{}
+
+// Irregular forms, and words whose stem the rules would get wrong.
+var kstemConflations = map[string]string{
+	"children": "child", "men": "man", "women": "woman", "feet": "foot",
+	"teeth": "tooth", "geese": "goose", "mice": "mouse", "lice": "louse",
+	"oxen": "ox", "people": "person", "indices": "index", "matrices": "matrix",
+	"vertices": "vertex", "criteria": "criterion", "phenomena": "phenomenon",
+	"data": "datum", "analyses": "analysis", "theses": "thesis",
+	"crises": "crisis", "axes": "axis", "leaves": "leaf", "lives": "life",
+	"knives": "knife", "wives": "wife", "wolves": "wolf", "halves": "half",
+	"shelves": "shelf", "selves": "self", "thieves": "thief",
+	"goes": "go", "does": "do", "freed": "free", "dying": "die",
+	"lying": "lie", "tying": "tie",
+	"went": "go", "gone": "go", "was": "be", "were": "be", "been": "be",
+	"ran": "run", "ate": "eat", "eaten": "eat", "began": "begin",
+	"begun": "begin", "broke": "break", "broken": "break", "chose": "choose",
+	"chosen": "choose", "drove": "drive", "driven": "drive", "flew": "fly",
+	"flown": "fly", "froze": "freeze", "frozen": "freeze", "gave": "give",
+	"given": "give", "knew": "know", "known": "know", "rode": "ride",
+	"ridden": "ride", "rose": "rise", "risen": "rise", "sang": "sing",
+	"sung": "sing", "spoke": "speak", "spoken": "speak", "stole": "steal",
+	"stolen": "steal", "swam": "swim", "swum": "swim", "took": "take",
+	"taken": "take", "threw": "throw", "thrown": "throw", "wore": "wear",
+	"worn": "wear", "wrote": "write", "written": "write", "better": "good",
+	"best": "good", "worse": "bad", "worst": "bad",
+}
+
+// Known words that derivational endings may be reduced to; words that
+// merely look inflected are listed so that they are kept intact.
+var kstemHeadwords = strings.Fields(`
+	able abuse accept access accident act action active adapt add adjust
+	admire admit adopt advertise advise affect agree allow alter amaze
+	amuse analyze announce annoy apply appoint approve argue arrange arrive
+	art assign assist attach attend attract avail avoid bake base beauty
+	behave believe bless bore build busy calculate calm care careful cause
+	celebrate certain champion change chemic child civil class classic
+	classify clear collect colony cookie combine comfort commerce commit
+	communicate community compare compete complete compute conclude
+	condition confuse connect consider construct consult continue
+	contribute control convert cool correct create creative critic culture
+	cure custom damage dark day deal decide declare decorate define
+	delight deliver demand depend describe design desire destroy detect
+	determine develop differ direct disappoint discover discuss dispose
+	distribute divide drink drive duty early easy edit educate effect
+	elect electric emotion employ encourage end endure enjoy enter equal
+	establish evolve examine excite exist expect experiment explain
+	explode express fair faith fame farm final firm fit flavor fly form
+	formal found free fresh friend fulfil function general gentle glory
+	govern grace grade great grow guide happy hard harm heal health help
+	history honest hope human humor hunt idea identify ignore illustrate
+	imagine improve include industry inform injure inspect inspire
+	install instruct intend interest introduce invent invest invite
+	judge just kind king know lead learn legal light like limit literal
+	live local logic lone love loyal magic manage manufacture mark market
+	master material mature measure meet member mention mind modern move
+	music mystery nation nature need neighbor nerve normal note notice
+	object observe occupy offend operate oppose option organ organize
+	original own paint part pay perfect perform permit person personal
+	photograph place plan play please poet polite politic popular
+	position possess possible power practice prefer prepare present
+	preserve press prevent print produce product profession profit
+	progress promote protect prove provide public publish pure purpose
+	qualify quick quiet racism rapid rational react read ready real
+	realize reason receive recognize record reduce refer reflect refuse
+	regular relate relax rely remark remove rent repair repeat replace
+	report represent require resist respect respond rest revise rich
+	rule sad safe satisfy save science season secure select sense serve
+	settle shape short sick sign silent simple sing sleep slow smooth
+	social soft solve speak special spirit state still strong student
+	succeed suggest supply support sure surprise sweet talk teach tend
+	tense test thank think total tour treat true trust type use usual
+	value vary violent visit vote walk want weak wealth weigh well wide
+	wild wise wonder work worry write young
+
+	bed bless blessed bring ceiling cling during evening feed fling
+	greed king morning need nothing red ring seed shed sled sling something
+	speed spring sting string swing thing wing
+	bias bus canvas chaos gas lens news series species status this thus
+	virus
+`)
+
+var kstemDict = func() map[string]string {
+	dict := make(map[string]string)
+	for _, word := range kstemHeadwords {
+		dict[word] = ""
+	}
+	for word, root := range kstemConflations {
+		dict[word] = root
+	}
+	return dict
+}()
+
+type kstem struct {
+	word  string
+	found bool
+}
+
+func (s KStemmer) Stem(term string) string {
+	if len(term) < 3 {
+		return term
+	}
+	for _, ch := range term {
+		if ch < 'a' || ch > 'z' {
+			return term
+		}
+	}
+	k := &kstem{word: term}
+	if !k.lookup(term) {
+		for _, step := range []func(){
+			k.plural, k.pastTense, k.aspect, k.ityEndings, k.nessEndings,
+			k.ionEndings, k.erAndOrEndings, k.lyEndings, k.alEndings,
+			k.iveEndings, k.izeEndings, k.mentEndings, k.bleEndings,
+			k.ismEndings, k.icEndings, k.ncyEndings, k.nceEndings,
+		} {
+			if step(); k.found {
+				break
+			}
+		}
+	}
+	if root := kstemDict[k.word]; root != "" {
+		return root
+	}
+	return k.word
+}
+
+/* Replaces the word with the given candidate if it is a known word. */
+func (k *kstem) lookup(candidate string) bool {
+	if _, ok := kstemDict[candidate]; ok {
+		k.word, k.found = candidate, true
+	}
+	return k.found
+}
+
+/* Replaces the word with the first known candidate, if any. */
+func (k *kstem) lookupAny(candidates ...string) bool {
+	for _, candidate := range candidates {
+		if len(candidate) > 1 && k.lookup(candidate) {
+			return true
+		}
+	}
+	return false
+}
+
+/* Returns the word without the given suffix, if it ends with it. */
+func (k *kstem) stem(suffix string) (string, bool) {
+	if strings.HasSuffix(k.word, suffix) && len(k.word) > len(suffix) {
+		return k.word[:len(k.word)-len(suffix)], true
+	}
+	return "", false
+}
+
+func isKStemVowel(word string, i int) bool {
+	switch word[i] {
+	case 'a', 'e', 'i', 'o', 'u':
+		return true
+	case 'y':
+		return i > 0 && !isKStemVowel(word, i-1)
+	}
+	return false
+}
+
+func kstemHasVowel(word string) bool {
+	for i := range word {
+		if isKStemVowel(word, i) {
+			return true
+		}
+	}
+	return false
+}
+
+/* Returns true if the word ends with a doubled consonant. */
+func kstemDoubleC(word string) bool {
+	n := len(word)
+	return n >= 2 && word[n-1] == word[n-2] && !isKStemVowel(word, n-1)
+}
+
+/*
+Returns true if the word has a single vowel group and ends
+consonant-vowel-consonant, the last consonant not being w, x or y;
+e.g. hop, but not visit or show.
+*/
+func kstemShortCVC(word string) bool {
+	n, groups := len(word), 0
+	for i := range word {
+		if isKStemVowel(word, i) && (i == 0 || !isKStemVowel(word, i-1)) {
+			groups++
+		}
+	}
+	return groups == 1 && n >= 3 && !isKStemVowel(word, n-3) &&
+		isKStemVowel(word, n-2) && !isKStemVowel(word, n-1) &&
+		strings.IndexByte("wxy", word[n-1]) < 0
+}
+
+/* Removes plural endings: ies, es and s. */
+func (k *kstem) plural() {
+	if stem, ok := k.stem("ies"); ok {
+		if len(stem) <= 1 {
+			k.word = stem + "ie"
+		} else if !k.lookupAny(stem+"y", stem+"ie") {
+			k.word = stem + "y"
+		}
+	} else if stem, ok := k.stem("es"); ok {
+		if k.lookupAny(k.word[:len(k.word)-1], stem) {
+			return
+		}
+		for _, ending := range []string{"ss", "ch", "sh", "x", "zz"} {
+			if strings.HasSuffix(stem, ending) {
+				k.word = stem
+				return
+			}
+		}
+		k.word = k.word[:len(k.word)-1]
+	} else if stem, ok := k.stem("s"); ok {
+		if strings.HasSuffix(stem, "s") || strings.HasSuffix(stem, "u") ||
+			strings.HasSuffix(stem, "i") {
+			return
+		}
+		if !k.lookup(stem) {
+			k.word = stem
+		}
+	}
+}
+
+/*
+Common handling of ed and ing endings: the stem must contain a vowel,
+and is restored to a known word or guessed from its shape.
+*/
+func (k *kstem) inflection(stem string) {
+	if !kstemHasVowel(stem) {
+		return
+	}
+	if k.lookupAny(stem, stem+"e") ||
+		kstemDoubleC(stem) && k.lookup(stem[:len(stem)-1]) {
+		return
+	}
+	switch n := len(stem); {
+	case kstemDoubleC(stem) && strings.IndexByte("lsz", stem[n-1]) < 0:
+		k.word = stem[:n-1]
+	case strings.HasSuffix(stem, "at") || strings.HasSuffix(stem, "bl") ||
+		strings.HasSuffix(stem, "iz") || kstemShortCVC(stem):
+		k.word = stem + "e"
+	default:
+		k.word = stem
+	}
+}
+
+/* Removes past tense endings: ied, eed and ed. */
+func (k *kstem) pastTense() {
+	if stem, ok := k.stem("ied"); ok {
+		if len(stem) <= 1 {
+			k.word = stem + "ie"
+		} else if !k.lookupAny(stem+"y", stem+"ie") {
+			k.word = stem + "y"
+		}
+	} else if stem, ok := k.stem("eed"); ok {
+		if kstemHasVowel(stem) {
+			k.word = stem + "ee"
+		}
+	} else if stem, ok := k.stem("ed"); ok {
+		k.inflection(stem)
+	}
+}
+
+/* Removes the ing ending. */
+func (k *kstem) aspect() {
+	stem, ok := k.stem("ing")
+	if !ok {
+		return
+	}
+	if n := len(stem); n <= 2 && stem[n-1] == 'y' {
+		// dying, lying, tying
+		k.word = stem[:n-1] + "ie"
+		return
+	}
+	k.inflection(stem)
+}
+
+func (k *kstem) ityEndings() {
+	if stem, ok := k.stem("ility"); ok && k.lookupAny(stem+"le", stem) {
+		return
+	}
+	if stem, ok := k.stem("ity"); ok {
+		k.lookupAny(stem, stem+"e")
+	}
+}
+
+/* Removes ness unconditionally, with a preceding i turned into y. */
+func (k *kstem) nessEndings() {
+	if stem, ok := k.stem("ness"); ok && len(stem) > 2 {
+		if stem[len(stem)-1] == 'i' {
+			stem = stem[:len(stem)-1] + "y"
+		}
+		k.word = stem
+		k.lookup(stem)
+	}
+}
+
+func (k *kstem) ionEndings() {
+	if stem, ok := k.stem("ication"); ok && k.lookupAny(stem+"y") {
+		return
+	}
+	if stem, ok := k.stem("ation"); ok && k.lookupAny(stem+"e", stem+"ate", stem) {
+		return
+	}
+	if stem, ok := k.stem("ion"); ok {
+		k.lookupAny(stem, stem+"e")
+	}
+}
+
+func (k *kstem) erAndOrEndings() {
+	if stem, ok := k.stem("ier"); ok && k.lookupAny(stem+"y") {
+		return
+	}
+	if stem, ok := k.stem("er"); ok {
+		if k.lookupAny(stem, stem+"e") ||
+			kstemDoubleC(stem) && k.lookup(stem[:len(stem)-1]) {
+			return
+		}
+	}
+	if stem, ok := k.stem("or"); ok {
+		k.lookupAny(stem, stem+"e")
+	}
+}
+
+func (k *kstem) lyEndings() {
+	if stem, ok := k.stem("ily"); ok && k.lookupAny(stem+"y") {
+		return
+	}
+	if stem, ok := k.stem("ally"); ok && k.lookupAny(stem+"al", stem) {
+		return
+	}
+	if stem, ok := k.stem("ly"); ok {
+		k.lookupAny(stem, stem+"le")
+	}
+}
+
+func (k *kstem) alEndings() {
+	if stem, ok := k.stem("ical"); ok && k.lookupAny(stem+"ic", stem+"y") {
+		return
+	}
+	if stem, ok := k.stem("al"); ok {
+		k.lookupAny(stem, stem+"e")
+	}
+}
+
+func (k *kstem) iveEndings() {
+	if stem, ok := k.stem("ative"); ok && k.lookupAny(stem+"ate", stem+"e", stem) {
+		return
+	}
+	if stem, ok := k.stem("ive"); ok {
+		k.lookupAny(stem, stem+"e")
+	}
+}
+
+func (k *kstem) izeEndings() {
+	if stem, ok := k.stem("ize"); ok {
+		k.lookupAny(stem, stem+"e", stem+"y")
+	}
+}
+
+func (k *kstem) mentEndings() {
+	if stem, ok := k.stem("ment"); ok {
+		k.lookupAny(stem)
+	}
+}
+
+func (k *kstem) bleEndings() {
+	for _, suffix := range []string{"able", "ible"} {
+		if stem, ok := k.stem(suffix); ok {
+			if k.lookupAny(stem, stem+"e") ||
+				kstemDoubleC(stem) && k.lookup(stem[:len(stem)-1]) {
+				return
+			}
+		}
+	}
+}
+
+func (k *kstem) ismEndings() {
+	if stem, ok := k.stem("ism"); ok {
+		k.lookupAny(stem, stem+"e")
+	}
+}
+
+func (k *kstem) icEndings() {
+	if stem, ok := k.stem("ic"); ok {
+		k.lookupAny(stem, stem+"e", stem+"y")
+	}
+}
+
+func (k *kstem) ncyEndings() {
+	if stem, ok := k.stem("ncy"); ok {
+		k.lookupAny(stem+"nt", stem+"nce")
+	}
+}
+
+func (k *kstem) nceEndings() {
+	for _, suffix := range []string{"ance", "ence"} {
+		if stem, ok := k.stem(suffix); ok && k.lookupAny(stem, stem+"e") {
+			return
+		}
+	}
+}
+
+// en/KStemFilter.java
+
+/*
+A high-performance kstem filter for English.
+
+See "Viewing Morphology as an Inference Process" (Krovetz, R., Proceedings
+of the Sixteenth Annual International ACM SIGIR Conference on Research
+and Development in Information Retrieval, 191-203, 1993).
+
+All terms must already be lowercased for this filter to work
+correctly. Tokens marked as keywords via the KeywordAttribute are left
+as is.
+*/
+type KStemFilter struct {
+	*TokenFilter
+	input       TokenStream
+	stemmer     KStemmer
+	termAtt     CharTermAttribute
+	keywordAttr KeywordAttribute
+}
+
+func NewKStemFilter(in TokenStream) *KStemFilter {
+	ans := &KStemFilter{
+		TokenFilter: NewTokenFilter(in),
+		input:       in,
+	}
+	ans.termAtt = ans.Attributes().Add("CharTermAttribute").(CharTermAttribute)
+	ans.keywordAttr = ans.Attributes().Add("KeywordAttribute").(KeywordAttribute)
+	return ans
+}
+
+/* Returns the next, stemmed, input Token. */
+func (f *KStemFilter) IncrementToken() (bool, error) {
+	ok, err := f.input.IncrementToken()
+	if err != nil || !ok {
+		return false, err
+	}
+	if !f.keywordAttr.IsKeyword() {
+		term := string(f.termAtt.Buffer()[:f.termAtt.Length()])
+		if stem := f.stemmer.Stem(term); stem != term {
+			f.termAtt.CopyBuffer([]rune(stem))
+		}
+	}
+	return true, nil
+}
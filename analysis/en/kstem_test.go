@@ -0,0 +1,28 @@
+package en
+
+import (
+	"testing"
+)
+
+func TestKStemmer(t *testing.T) {
+	for word, stem := range map[string]string{
+		// inflectional endings
+		"horses": "horse", "boxes": "box", "flies": "fly", "cookies": "cookie",
+		"ties": "tie", "jumped": "jump", "used": "use", "stopped": "stop",
+		"hoping": "hope", "hopping": "hop", "visiting": "visit", "agreed": "agree",
+		"applied": "apply", "cats": "cat",
+		// words that only look inflected
+		"need": "need", "bed": "bed", "thing": "thing", "bring": "bring",
+		"analysis": "analysis", "status": "status",
+		// derivational endings
+		"happiness": "happy", "organizations": "organize", "classification": "classify",
+		"quickly": "quick", "abilities": "able", "teacher": "teach",
+		"electrical": "electric", "creative": "creative",
+		// irregular forms, and words left alone
+		"children": "child", "went": "go", "ab": "ab", "x-ray": "x-ray",
+	} {
+		if got := (KStemmer{}).Stem(word); got != stem {
+			t.Errorf("%v: expected %v, but was %v", word, stem, got)
+		}
+	}
+}
@@ -0,0 +1,385 @@
+package en
+
+import (
+	. "github.com/balzaczyy/golucene/core/analysis"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"strings"
+)
+
+// tartarus/snowball/ext/EnglishStemmer.java
+
+/*
+The Porter2 stemming algorithm, also known as the Snowball "english"
+stemmer, an improved version of the original Porter stemmer.
+
+See http://snowball.tartarus.org/algorithms/english/stemmer.html
+
+The input is expected to be lower case.
+*/
+type Porter2Stemmer struct{}
+
+/* Words stemmed to irregular forms, or left untouched. */
+var porter2Exceptions1 = map[string]string{
+	"skis": "ski", "skies": "sky", "dying": "die", "lying": "lie",
+	"tying": "tie", "idly": "idl", "gently": "gentl", "ugly": "ugli",
+	"early": "earli", "only": "onli", "singly": "singl",
+	"sky": "sky", "news": "news", "howe": "howe", "atlas": "atlas",
+	"cosmos": "cosmos", "bias": "bias", "andes": "andes",
+}
+
+/* Words left as they are once step 1a is done. */
+var porter2Exceptions2 = map[string]bool{
+	"inning": true, "outing": true, "canning": true, "herring": true,
+	"earring": true, "proceed": true, "exceed": true, "succeed": true,
+}
+
+type porter2Rule struct {
+	suffix, replacement string
+}
+
+// suffixes are ordered longest first, the longest match wins
+var porter2Step2 = []porter2Rule{
+	{"ization", "ize"}, {"ational", "ate"}, {"fulness", "ful"},
+	{"ousness", "ous"}, {"iveness", "ive"}, {"tional", "tion"},
+	{"biliti", "ble"}, {"lessli", "less"}, {"entli", "ent"},
+	{"ation", "ate"}, {"alism", "al"}, {"aliti", "al"}, {"ousli", "ous"},
+	{"iviti", "ive"}, {"fulli", "ful"}, {"enci", "ence"}, {"anci", "ance"},
+	{"abli", "able"}, {"izer", "ize"}, {"ator", "ate"}, {"alli", "al"},
+	{"bli", "ble"}, {"ogi", "og"}, {"li", ""},
+}
+
+var porter2Step3 = []porter2Rule{
+	{"ational", "ate"}, {"tional", "tion"}, {"alize", "al"},
+	{"icate", "ic"}, {"iciti", "ic"}, {"ative", ""}, {"ical", "ic"},
+	{"ness", ""}, {"ful", ""},
+}
+
+var porter2Step4 = []string{
+	"ement", "ance", "ence", "able", "ible", "ment", "ant", "ent", "ism",
+	"ate", "iti", "ous", "ive", "ize", "ion", "al", "er", "ic",
+}
+
+/* Returns the stem of the given lower case word. */
+func (s Porter2Stemmer) Stem(word string) string {
+	if stem, ok := porter2Exceptions1[word]; ok {
+		return stem
+	}
+	p := &porter2{b: []rune(word)}
+	if len(p.b) <= 2 {
+		return word
+	}
+
+	p.prelude()
+	p.markRegions()
+	p.step0()
+	p.step1a()
+	if porter2Exceptions2[string(p.b)] {
+		return p.postlude()
+	}
+	p.step1b()
+	p.step1c()
+	p.step2()
+	p.step3()
+	p.step4()
+	p.step5()
+	return p.postlude()
+}
+
+type porter2 struct {
+	b      []rune
+	r1, r2 int
+}
+
+func isPorter2Vowel(ch rune) bool {
+	switch ch {
+	case 'a', 'e', 'i', 'o', 'u', 'y':
+		return true
+	}
+	return false
+}
+
+/*
+Removes an initial apostrophe, then marks an initial y, or a y after
+a vowel, as a consonant Y.
+*/
+func (p *porter2) prelude() {
+	if p.b[0] == '\'' {
+		p.b = p.b[1:]
+	}
+	for i, ch := range p.b {
+		if ch == 'y' && (i == 0 || isPorter2Vowel(p.b[i-1])) {
+			p.b[i] = 'Y'
+		}
+	}
+}
+
+func (p *porter2) postlude() string {
+	for i, ch := range p.b {
+		if ch == 'Y' {
+			p.b[i] = 'y'
+		}
+	}
+	return string(p.b)
+}
+
+/*
+R1 is the region after the first non-vowel following a vowel, or the
+end of the word; R2 is the same region within R1. Words beginning
+with gener, commun or arsen have R1 after those prefixes.
+*/
+func (p *porter2) markRegions() {
+	p.r1 = -1
+	for _, prefix := range []string{"gener", "commun", "arsen"} {
+		if strings.HasPrefix(string(p.b), prefix) {
+			p.r1 = len(prefix)
+		}
+	}
+	if p.r1 < 0 {
+		p.r1 = p.regionAfter(0)
+	}
+	p.r2 = p.regionAfter(p.r1)
+}
+
+func (p *porter2) regionAfter(start int) int {
+	for i := start + 1; i < len(p.b); i++ {
+		if isPorter2Vowel(p.b[i-1]) && !isPorter2Vowel(p.b[i]) {
+			return i + 1
+		}
+	}
+	return len(p.b)
+}
+
+func (p *porter2) hasSuffix(suffix string) bool {
+	if len(suffix) > len(p.b) {
+		return false
+	}
+	return string(p.b[len(p.b)-len(suffix):]) == suffix
+}
+
+/* Returns the first of the given suffixes the word ends with, or "". */
+func (p *porter2) longestSuffix(suffixes ...string) string {
+	for _, suffix := range suffixes {
+		if p.hasSuffix(suffix) {
+			return suffix
+		}
+	}
+	return ""
+}
+
+func (p *porter2) inR1(suffix string) bool {
+	return len(p.b)-len(suffix) >= p.r1
+}
+
+func (p *porter2) inR2(suffix string) bool {
+	return len(p.b)-len(suffix) >= p.r2
+}
+
+func (p *porter2) replace(suffix, replacement string) {
+	p.b = append(p.b[:len(p.b)-len(suffix)], []rune(replacement)...)
+}
+
+func (p *porter2) containsVowel(end int) bool {
+	for _, ch := range p.b[:end] {
+		if isPorter2Vowel(ch) {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+A short syllable is a vowel followed by a non-vowel other than w, x
+or Y and preceded by a non-vowel, or a vowel at the beginning of the
+word followed by a non-vowel.
+*/
+func (p *porter2) endsWithShortSyllable(end int) bool {
+	b := p.b
+	switch {
+	case end == 2:
+		return isPorter2Vowel(b[0]) && !isPorter2Vowel(b[1])
+	case end > 2:
+		last := b[end-1]
+		return !isPorter2Vowel(b[end-3]) && isPorter2Vowel(b[end-2]) &&
+			!isPorter2Vowel(last) && last != 'w' && last != 'x' && last != 'Y'
+	}
+	return false
+}
+
+/* A word is short if it ends in a short syllable, and R1 is null. */
+func (p *porter2) isShortWord() bool {
+	return p.r1 >= len(p.b) && p.endsWithShortSyllable(len(p.b))
+}
+
+func (p *porter2) endsWithDouble() bool {
+	for _, double := range []string{"bb", "dd", "ff", "gg", "mm", "nn", "pp", "rr", "tt"} {
+		if p.hasSuffix(double) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *porter2) step0() {
+	if suffix := p.longestSuffix("'s'", "'s", "'"); suffix != "" {
+		p.replace(suffix, "")
+	}
+}
+
+func (p *porter2) step1a() {
+	switch suffix := p.longestSuffix("sses", "ied", "ies", "us", "ss", "s"); suffix {
+	case "sses":
+		p.replace(suffix, "ss")
+	case "ied", "ies":
+		if len(p.b) > 4 {
+			p.replace(suffix, "i")
+		} else {
+			p.replace(suffix, "ie")
+		}
+	case "s":
+		// delete if the preceding part contains a vowel not immediately
+		// before the s
+		if p.containsVowel(len(p.b) - 2) {
+			p.replace(suffix, "")
+		}
+	}
+}
+
+func (p *porter2) step1b() {
+	switch suffix := p.longestSuffix("eedly", "ingly", "edly", "eed", "ing", "ed"); suffix {
+	case "":
+	case "eedly", "eed":
+		if p.inR1(suffix) {
+			p.replace(suffix, "ee")
+		}
+	default:
+		if !p.containsVowel(len(p.b) - len(suffix)) {
+			return
+		}
+		p.replace(suffix, "")
+		switch {
+		case p.hasSuffix("at"), p.hasSuffix("bl"), p.hasSuffix("iz"):
+			p.b = append(p.b, 'e')
+		case p.endsWithDouble():
+			p.b = p.b[:len(p.b)-1]
+		case p.isShortWord():
+			p.b = append(p.b, 'e')
+		}
+	}
+}
+
+/* Replaces suffix y or Y by i if preceded by a non-vowel which is not the first letter. */
+func (p *porter2) step1c() {
+	n := len(p.b)
+	if n > 2 && (p.b[n-1] == 'y' || p.b[n-1] == 'Y') && !isPorter2Vowel(p.b[n-2]) {
+		p.b[n-1] = 'i'
+	}
+}
+
+func (p *porter2) step2() {
+	for _, rule := range porter2Step2 {
+		if !p.hasSuffix(rule.suffix) {
+			continue
+		}
+		if p.inR1(rule.suffix) {
+			preceding := len(p.b) - len(rule.suffix) - 1
+			switch rule.suffix {
+			case "ogi":
+				if preceding >= 0 && p.b[preceding] == 'l' {
+					p.replace(rule.suffix, rule.replacement)
+				}
+			case "li":
+				if preceding >= 0 && strings.ContainsRune("cdeghkmnrt", p.b[preceding]) {
+					p.replace(rule.suffix, rule.replacement)
+				}
+			default:
+				p.replace(rule.suffix, rule.replacement)
+			}
+		}
+		return
+	}
+}
+
+func (p *porter2) step3() {
+	for _, rule := range porter2Step3 {
+		if !p.hasSuffix(rule.suffix) {
+			continue
+		}
+		if rule.suffix == "ative" && p.inR2(rule.suffix) ||
+			rule.suffix != "ative" && p.inR1(rule.suffix) {
+			p.replace(rule.suffix, rule.replacement)
+		}
+		return
+	}
+}
+
+func (p *porter2) step4() {
+	suffix := p.longestSuffix(porter2Step4...)
+	if suffix == "" || !p.inR2(suffix) {
+		return
+	}
+	if suffix == "ion" {
+		// delete only if preceded by s or t
+		if preceding := len(p.b) - 4; preceding < 0 ||
+			p.b[preceding] != 's' && p.b[preceding] != 't' {
+			return
+		}
+	}
+	p.replace(suffix, "")
+}
+
+func (p *porter2) step5() {
+	n := len(p.b)
+	switch {
+	case p.hasSuffix("e"):
+		if p.inR2("e") || p.inR1("e") && !p.endsWithShortSyllable(n-1) {
+			p.b = p.b[:n-1]
+		}
+	case p.hasSuffix("l"):
+		if p.inR2("l") && n >= 2 && p.b[n-2] == 'l' {
+			p.b = p.b[:n-1]
+		}
+	}
+}
+
+// en/PorterStemFilter.java
+
+/*
+Transforms the token stream as per the Porter2 stemming algorithm.
+
+Note: the input to the stemming filter must already be in lower case,
+so you will need to use LowerCaseFilter or LowerCaseTokenizer farther
+down the Tokenizer chain in order for this to work properly!
+
+Tokens marked as keywords via the KeywordAttribute are left as is.
+*/
+type Porter2StemFilter struct {
+	*TokenFilter
+	input       TokenStream
+	stemmer     Porter2Stemmer
+	termAtt     CharTermAttribute
+	keywordAttr KeywordAttribute
+}
+
+func NewPorter2StemFilter(in TokenStream) *Porter2StemFilter {
+	ans := &Porter2StemFilter{
+		TokenFilter: NewTokenFilter(in),
+		input:       in,
+	}
+	ans.termAtt = ans.Attributes().Add("CharTermAttribute").(CharTermAttribute)
+	ans.keywordAttr = ans.Attributes().Add("KeywordAttribute").(KeywordAttribute)
+	return ans
+}
+
+func (f *Porter2StemFilter) IncrementToken() (bool, error) {
+	ok, err := f.input.IncrementToken()
+	if err != nil || !ok {
+		return false, err
+	}
+	if !f.keywordAttr.IsKeyword() {
+		term := string(f.termAtt.Buffer()[:f.termAtt.Length()])
+		if stem := f.stemmer.Stem(term); stem != term {
+			f.termAtt.CopyBuffer([]rune(stem))
+		}
+	}
+	return true, nil
+}
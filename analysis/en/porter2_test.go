@@ -0,0 +1,62 @@
+package en
+
+import (
+	"fmt"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"testing"
+)
+
+func TestPorter2Stemmer(t *testing.T) {
+	for word, stem := range map[string]string{
+		"consign": "consign", "consigned": "consign", "consignment": "consign",
+		"consistency": "consist", "consistently": "consist", "consolation": "consol",
+		"consolatory": "consolatori", "consolidating": "consolid", "consolingly": "consol",
+		"conspicuously": "conspicu", "conspiracy": "conspiraci", "constables": "constabl",
+		"knackeries": "knackeri", "kneeling": "kneel", "knightly": "knight",
+		"knitting": "knit", "knives": "knive", "generously": "generous",
+		"running": "run", "hoped": "hope", "hopping": "hop", "caresses": "caress",
+		"cries": "cri", "ties": "tie", "gas": "gas", "gaps": "gap", "happiness": "happi",
+		"skies": "sky", "news": "news", "proceed": "proceed", "bleed": "bleed",
+		"youth": "youth", "saying": "say", "by": "by", "'tis": "tis", "dog's": "dog",
+		"luxuriating": "luxuri", "communication": "communic", "rational": "ration",
+	} {
+		if got := (Porter2Stemmer{}).Stem(word); got != stem {
+			t.Errorf("%v: expected %v, but was %v", word, stem, got)
+		}
+	}
+}
+
+func TestEnglishAnalyzer(t *testing.T) {
+	assertAnalyzesTo := func(a *EnglishAnalyzer, text string, expected ...string) {
+		ts, err := a.TokenStreamForString("field", text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		termAtt := ts.Attributes().Get("CharTermAttribute").(CharTermAttribute)
+		if err = ts.Reset(); err != nil {
+			t.Fatal(err)
+		}
+		var terms []string
+		for {
+			ok, err := ts.IncrementToken()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !ok {
+				break
+			}
+			terms = append(terms, string(termAtt.Buffer()[:termAtt.Length()]))
+		}
+		ts.End()
+		ts.Close()
+		if fmt.Sprint(terms) != fmt.Sprint(expected) {
+			t.Errorf("%q: expected %v, but was %v", text, expected, terms)
+		}
+	}
+
+	assertAnalyzesTo(NewEnglishAnalyzer(), "The books of Steven's Running Dogs",
+		"book", "steven", "run", "dog")
+	a := NewEnglishAnalyzerWithStemExclusions(DEFAULT_STOPWORD_SET, map[string]bool{"books": true})
+	assertAnalyzesTo(a, "The books of Steven's Running Dogs",
+		"books", "steven", "run", "dog")
+}
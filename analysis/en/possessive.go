@@ -0,0 +1,39 @@
+package en
+
+import (
+	. "github.com/balzaczyy/golucene/core/analysis"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+)
+
+// en/EnglishPossessiveFilter.java
+
+/* TokenFilter that removes possessives (trailing 's) from words. */
+type EnglishPossessiveFilter struct {
+	*TokenFilter
+	input   TokenStream
+	termAtt CharTermAttribute
+}
+
+func NewEnglishPossessiveFilter(in TokenStream) *EnglishPossessiveFilter {
+	ans := &EnglishPossessiveFilter{
+		TokenFilter: NewTokenFilter(in),
+		input:       in,
+	}
+	ans.termAtt = ans.Attributes().Add("CharTermAttribute").(CharTermAttribute)
+	return ans
+}
+
+func (f *EnglishPossessiveFilter) IncrementToken() (bool, error) {
+	ok, err := f.input.IncrementToken()
+	if err != nil || !ok {
+		return false, err
+	}
+
+	buffer, length := f.termAtt.Buffer(), f.termAtt.Length()
+	if length >= 2 &&
+		(buffer[length-2] == '\'' || buffer[length-2] == '’' || buffer[length-2] == '＇') &&
+		(buffer[length-1] == 's' || buffer[length-1] == 'S') {
+		f.termAtt.SetLength(length - 2) // Strip last 2 characters off
+	}
+	return true, nil
+}
@@ -0,0 +1,112 @@
+package es
+
+import (
+	. "github.com/balzaczyy/golucene/analysis/core"
+	. "github.com/balzaczyy/golucene/analysis/miscellaneous"
+	"github.com/balzaczyy/golucene/analysis/snowball"
+	std "github.com/balzaczyy/golucene/analysis/standard"
+	. "github.com/balzaczyy/golucene/analysis/util"
+	. "github.com/balzaczyy/golucene/core/analysis"
+	"io"
+)
+
+// es/SpanishAnalyzer.java
+
+/* The default set of stopwords used by SpanishAnalyzer, from the Snowball spanish stop list. */
+var DEFAULT_STOPWORD_SET = map[string]bool{
+	"de": true, "la": true, "que": true, "el": true, "en": true,
+	"y": true, "a": true, "los": true, "del": true, "se": true,
+	"las": true, "por": true, "un": true, "para": true, "con": true,
+	"no": true, "una": true, "su": true, "al": true, "lo": true,
+	"como": true, "más": true, "pero": true, "sus": true, "le": true,
+	"ya": true, "o": true, "este": true, "sí": true, "porque": true,
+	"esta": true, "entre": true, "cuando": true, "muy": true, "sin": true,
+	"sobre": true, "también": true, "me": true, "hasta": true,
+	"hay": true, "donde": true, "quien": true, "desde": true,
+	"todo": true, "nos": true, "durante": true, "todos": true,
+	"uno": true, "les": true, "ni": true, "contra": true, "otros": true,
+	"ese": true, "eso": true, "ante": true, "ellos": true, "e": true,
+	"esto": true, "mí": true, "antes": true, "algunos": true, "qué": true,
+	"unos": true, "yo": true, "otro": true, "otras": true, "otra": true,
+	"él": true, "tanto": true, "esa": true, "estos": true, "mucho": true,
+	"quienes": true, "nada": true, "muchos": true, "cual": true,
+	"poco": true, "ella": true, "estar": true, "estas": true,
+	"algunas": true, "algo": true, "nosotros": true, "mi": true,
+	"mis": true, "tú": true, "te": true, "ti": true, "tu": true,
+	"tus": true, "ellas": true, "nosotras": true, "vosotros": true,
+	"vosotras": true, "os": true, "mío": true, "mía": true, "míos": true,
+	"mías": true, "tuyo": true, "tuya": true, "tuyos": true,
+	"tuyas": true, "suyo": true, "suya": true, "suyos": true,
+	"suyas": true, "nuestro": true, "nuestra": true, "nuestros": true,
+	"nuestras": true, "vuestro": true, "vuestra": true, "vuestros": true,
+	"vuestras": true, "esos": true, "esas": true, "estoy": true,
+	"estás": true, "está": true, "estamos": true, "estáis": true,
+	"están": true, "esté": true, "estés": true, "estemos": true,
+	"estéis": true, "estén": true, "estaba": true, "estabas": true,
+	"estábamos": true, "estabais": true, "estaban": true, "estuve": true,
+	"estuvo": true, "estuvimos": true, "estuvieron": true, "he": true,
+	"has": true, "ha": true, "hemos": true, "habéis": true, "han": true,
+	"haya": true, "hayas": true, "hayamos": true, "hayáis": true,
+	"hayan": true, "había": true, "habías": true, "habíamos": true,
+	"habíais": true, "habían": true, "hube": true, "hubo": true,
+	"soy": true, "eres": true, "es": true, "somos": true, "sois": true,
+	"son": true, "sea": true, "seas": true, "seamos": true, "seáis": true,
+	"sean": true, "era": true, "eras": true, "éramos": true,
+	"erais": true, "eran": true, "fui": true, "fuiste": true, "fue": true,
+	"fuimos": true, "fuisteis": true, "fueron": true, "tengo": true,
+	"tienes": true, "tiene": true, "tenemos": true, "tenéis": true,
+	"tienen": true, "tenga": true, "tengas": true, "tengamos": true,
+	"tengáis": true, "tengan": true, "tenía": true, "tenías": true,
+	"teníamos": true, "teníais": true, "tenían": true, "tuve": true,
+	"tuvo": true, "tuvimos": true, "tuvieron": true,
+}
+
+/*
+Analyzer for Spanish.
+
+Builds an analysis chain made of StandardTokenizer, StandardFilter,
+LowerCaseFilter, StopFilter, SetKeywordMarkerFilter if a stem
+exclusion set is provided, and SnowballFilter with the spanish stemmer.
+*/
+type SpanishAnalyzer struct {
+	*StopwordAnalyzerBase
+	stemExclusionSet map[string]bool
+}
+
+/* Builds an analyzer with the default stop words: DEFAULT_STOPWORD_SET. */
+func NewSpanishAnalyzer() *SpanishAnalyzer {
+	return NewSpanishAnalyzerWithStopWords(DEFAULT_STOPWORD_SET)
+}
+
+/* Builds an analyzer with the given stop words. */
+func NewSpanishAnalyzerWithStopWords(stopwords map[string]bool) *SpanishAnalyzer {
+	return NewSpanishAnalyzerWithStemExclusions(stopwords, nil)
+}
+
+/*
+Builds an analyzer with the given stop words. If a non-empty stem
+exclusion set is provided this analyzer will add a
+SetKeywordMarkerFilter before stemming.
+*/
+func NewSpanishAnalyzerWithStemExclusions(stopwords, stemExclusionSet map[string]bool) *SpanishAnalyzer {
+	ans := &SpanishAnalyzer{stemExclusionSet: make(map[string]bool)}
+	for k, v := range stemExclusionSet {
+		ans.stemExclusionSet[k] = v
+	}
+	ans.StopwordAnalyzerBase = NewStopwordAnalyzerBaseWithStopWords(stopwords)
+	ans.Spi = ans
+	return ans
+}
+
+func (a *SpanishAnalyzer) CreateComponents(fieldName string, reader io.RuneReader) *TokenStreamComponents {
+	version := a.Version()
+	source := std.NewStandardTokenizer(version, reader)
+	var result TokenStream = std.NewStandardFilter(version, source)
+	result = NewLowerCaseFilter(version, result)
+	result = NewStopFilter(version, result, a.StopwordSet())
+	if len(a.stemExclusionSet) > 0 {
+		result = NewSetKeywordMarkerFilter(result, a.stemExclusionSet)
+	}
+	result = snowball.NewSnowballFilter(result, snowball.SpanishStemmer{})
+	return NewTokenStreamComponents(source, result)
+}
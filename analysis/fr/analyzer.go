@@ -0,0 +1,108 @@
+package fr
+
+import (
+	. "github.com/balzaczyy/golucene/analysis/core"
+	. "github.com/balzaczyy/golucene/analysis/miscellaneous"
+	"github.com/balzaczyy/golucene/analysis/snowball"
+	std "github.com/balzaczyy/golucene/analysis/standard"
+	. "github.com/balzaczyy/golucene/analysis/util"
+	. "github.com/balzaczyy/golucene/core/analysis"
+	"io"
+)
+
+// fr/FrenchAnalyzer.java
+
+/* The default set of stopwords used by FrenchAnalyzer, from the Snowball french stop list. */
+var DEFAULT_STOPWORD_SET = map[string]bool{
+	"au": true, "aux": true, "avec": true, "ce": true, "ces": true,
+	"dans": true, "de": true, "des": true, "du": true, "elle": true,
+	"en": true, "et": true, "eux": true, "il": true, "je": true,
+	"la": true, "le": true, "leur": true, "lui": true, "ma": true,
+	"mais": true, "me": true, "même": true, "mes": true, "moi": true,
+	"mon": true, "ne": true, "nos": true, "notre": true, "nous": true,
+	"on": true, "ou": true, "par": true, "pas": true, "pour": true,
+	"qu": true, "que": true, "qui": true, "sa": true, "se": true,
+	"ses": true, "son": true, "sur": true, "ta": true, "te": true,
+	"tes": true, "toi": true, "ton": true, "tu": true, "un": true,
+	"une": true, "vos": true, "votre": true, "vous": true, "c": true,
+	"d": true, "j": true, "l": true, "à": true, "m": true, "n": true,
+	"s": true, "t": true, "y": true, "été": true, "étée": true,
+	"étées": true, "étés": true, "étant": true, "suis": true, "es": true,
+	"est": true, "sommes": true, "êtes": true, "sont": true,
+	"serai": true, "seras": true, "sera": true, "serons": true,
+	"serez": true, "seront": true, "serais": true, "serait": true,
+	"serions": true, "seriez": true, "seraient": true, "étais": true,
+	"était": true, "étions": true, "étiez": true, "étaient": true,
+	"fus": true, "fut": true, "fûmes": true, "fûtes": true,
+	"furent": true, "sois": true, "soit": true, "soyons": true,
+	"soyez": true, "soient": true, "fusse": true, "fusses": true,
+	"fût": true, "fussions": true, "fussiez": true, "fussent": true,
+	"ayant": true, "eu": true, "eue": true, "eues": true, "eus": true,
+	"ai": true, "as": true, "avons": true, "avez": true, "ont": true,
+	"aurai": true, "auras": true, "aura": true, "aurons": true,
+	"aurez": true, "auront": true, "aurais": true, "aurait": true,
+	"aurions": true, "auriez": true, "auraient": true, "avais": true,
+	"avait": true, "avions": true, "aviez": true, "avaient": true,
+	"eut": true, "eûmes": true, "eûtes": true, "eurent": true,
+	"aie": true, "aies": true, "ait": true, "ayons": true, "ayez": true,
+	"aient": true, "eusse": true, "eusses": true, "eût": true,
+	"eussions": true, "eussiez": true, "eussent": true, "ceci": true,
+	"cela": true, "celà": true, "cet": true, "cette": true, "ici": true,
+	"ils": true, "les": true, "leurs": true, "quel": true, "quels": true,
+	"quelle": true, "quelles": true, "sans": true, "soi": true,
+}
+
+/* Default set of articles for ElisionFilter. */
+var DEFAULT_ARTICLES = NewCharArraySet(true,
+	"l", "m", "t", "qu", "n", "s", "j", "d", "c", "jusqu", "quoiqu", "lorsqu", "puisqu")
+
+/*
+Analyzer for French.
+
+Builds an analysis chain made of StandardTokenizer, StandardFilter,
+ElisionFilter, LowerCaseFilter, StopFilter, SetKeywordMarkerFilter if a stem
+exclusion set is provided, and SnowballFilter with the french stemmer.
+*/
+type FrenchAnalyzer struct {
+	*StopwordAnalyzerBase
+	stemExclusionSet map[string]bool
+}
+
+/* Builds an analyzer with the default stop words: DEFAULT_STOPWORD_SET. */
+func NewFrenchAnalyzer() *FrenchAnalyzer {
+	return NewFrenchAnalyzerWithStopWords(DEFAULT_STOPWORD_SET)
+}
+
+/* Builds an analyzer with the given stop words. */
+func NewFrenchAnalyzerWithStopWords(stopwords map[string]bool) *FrenchAnalyzer {
+	return NewFrenchAnalyzerWithStemExclusions(stopwords, nil)
+}
+
+/*
+Builds an analyzer with the given stop words. If a non-empty stem
+exclusion set is provided this analyzer will add a
+SetKeywordMarkerFilter before stemming.
+*/
+func NewFrenchAnalyzerWithStemExclusions(stopwords, stemExclusionSet map[string]bool) *FrenchAnalyzer {
+	ans := &FrenchAnalyzer{stemExclusionSet: make(map[string]bool)}
+	for k, v := range stemExclusionSet {
+		ans.stemExclusionSet[k] = v
+	}
+	ans.StopwordAnalyzerBase = NewStopwordAnalyzerBaseWithStopWords(stopwords)
+	ans.Spi = ans
+	return ans
+}
+
+func (a *FrenchAnalyzer) CreateComponents(fieldName string, reader io.RuneReader) *TokenStreamComponents {
+	version := a.Version()
+	source := std.NewStandardTokenizer(version, reader)
+	var result TokenStream = std.NewStandardFilter(version, source)
+	result = NewElisionFilter(result, DEFAULT_ARTICLES)
+	result = NewLowerCaseFilter(version, result)
+	result = NewStopFilter(version, result, a.StopwordSet())
+	if len(a.stemExclusionSet) > 0 {
+		result = NewSetKeywordMarkerFilter(result, a.stemExclusionSet)
+	}
+	result = snowball.NewSnowballFilter(result, snowball.FrenchStemmer{})
+	return NewTokenStreamComponents(source, result)
+}
@@ -0,0 +1,77 @@
+package fr
+
+import (
+	"fmt"
+	std "github.com/balzaczyy/golucene/analysis/standard"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"github.com/balzaczyy/golucene/core/util"
+	"strings"
+	"testing"
+)
+
+func TestFrenchAnalyzer(t *testing.T) {
+	assertAnalyzesTo := func(a *FrenchAnalyzer, text string, expected ...string) {
+		ts, err := a.TokenStreamForString("field", text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		termAtt := ts.Attributes().Get("CharTermAttribute").(CharTermAttribute)
+		if err = ts.Reset(); err != nil {
+			t.Fatal(err)
+		}
+		var terms []string
+		for {
+			ok, err := ts.IncrementToken()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !ok {
+				break
+			}
+			terms = append(terms, string(termAtt.Buffer()[:termAtt.Length()]))
+		}
+		ts.End()
+		ts.Close()
+		if fmt.Sprint(terms) != fmt.Sprint(expected) {
+			t.Errorf("%q: expected %v, but was %v", text, expected, terms)
+		}
+	}
+
+	assertAnalyzesTo(NewFrenchAnalyzer(), "Les chats mangeaient dans la cuisine",
+		"chat", "mang", "cuisin")
+	assertAnalyzesTo(NewFrenchAnalyzer(), "L'avion d’Air France jusqu'à l'aéroport",
+		"avion", "air", "franc", "aéroport")
+	a := NewFrenchAnalyzerWithStemExclusions(DEFAULT_STOPWORD_SET, map[string]bool{"chats": true})
+	assertAnalyzesTo(a, "Les chats mangeaient dans la cuisine",
+		"chats", "mang", "cuisin")
+}
+
+func TestElisionFilter(t *testing.T) {
+	f, err := NewElisionFilterFactory(map[string]string{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := f.Create(std.NewStandardTokenizer(util.VERSION_LATEST,
+		strings.NewReader("Plop, juste pour voir l'embrouille avec O'brian. M'enfin.")))
+	termAtt := ts.Attributes().Get("CharTermAttribute").(CharTermAttribute)
+	if err = ts.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	var terms []string
+	for {
+		ok, err := ts.IncrementToken()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			break
+		}
+		terms = append(terms, string(termAtt.Buffer()[:termAtt.Length()]))
+	}
+	ts.End()
+	ts.Close()
+	expected := "[Plop juste pour voir embrouille avec O'brian enfin]"
+	if got := fmt.Sprint(terms); got != expected {
+		t.Errorf("expected %v, but was %v", expected, got)
+	}
+}
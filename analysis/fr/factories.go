@@ -0,0 +1,46 @@
+package fr
+
+import (
+	. "github.com/balzaczyy/golucene/analysis/util"
+	. "github.com/balzaczyy/golucene/core/analysis"
+)
+
+func init() {
+	RegisterTokenFilterFactory("elision", func(args map[string]string) (TokenFilterFactory, error) {
+		f, err := NewElisionFilterFactory(args)
+		if err != nil {
+			return nil, err
+		}
+		return f, nil
+	})
+}
+
+// util/ElisionFilterFactory.java
+
+/*
+Factory for ElisionFilter. The "articles" parameter is an optional
+list of word files, and the articles in them are matched regardless
+of case when "ignoreCase" is true. DEFAULT_ARTICLES are used if no
+file is given.
+*/
+type ElisionFilterFactory struct {
+	*AbstractAnalysisFactory
+	articles *CharArraySet
+}
+
+func NewElisionFilterFactory(args map[string]string) (*ElisionFilterFactory, error) {
+	ans := &ElisionFilterFactory{AbstractAnalysisFactory: NewAbstractAnalysisFactory(args)}
+	ignoreCase := ans.GetBool("ignoreCase", false)
+	ans.articles = ans.GetWordSet("articles", "wordset", ignoreCase)
+	if err := ans.Validate(); err != nil {
+		return nil, err
+	}
+	if ans.articles == nil {
+		ans.articles = DEFAULT_ARTICLES
+	}
+	return ans, nil
+}
+
+func (f *ElisionFilterFactory) Create(input TokenStream) TokenStream {
+	return NewElisionFilter(input, f.articles)
+}
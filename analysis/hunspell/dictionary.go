@@ -0,0 +1,410 @@
+package hunspell
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// hunspell/Dictionary.java
+
+const (
+	ALIAS_KEY           = "AF"
+	PREFIX_KEY          = "PFX"
+	SUFFIX_KEY          = "SFX"
+	FLAG_KEY            = "FLAG"
+	NEEDAFFIX_KEY       = "NEEDAFFIX"
+	PSEUDOROOT_KEY      = "PSEUDOROOT"
+	IGNORE_KEY          = "IGNORE"
+	SET_KEY             = "SET"
+	NUM_FLAG_TYPE       = "num"
+	UTF8_FLAG_TYPE      = "UTF-8"
+	LONG_FLAG_TYPE      = "long"
+	DEFAULT_ENCODING    = "ISO8859-1"
+	DEFAULT_CONDITION   = "."
+	MAX_RECURSION_LEVEL = 3
+)
+
+/* An affix rule of the .aff file. */
+type affix struct {
+	flag         rune
+	strip        string
+	append       string
+	appendFlags  []rune // sorted continuation flags
+	condition    *regexp.Regexp
+	crossProduct bool
+}
+
+/*
+In-memory structure for the dictionary (.dic) and affix (.aff) data of
+a hunspell dictionary.
+
+Supported .aff directives are SET (UTF-8 and ISO8859-1), FLAG (UTF-8,
+long and num), AF, PFX, SFX, IGNORE and NEEDAFFIX/PSEUDOROOT; the
+others are ignored.
+*/
+type Dictionary struct {
+	// word -> the flag sets of its entries, each sorted
+	words    map[string][][]rune
+	prefixes map[string][]*affix // keyed by their append
+	suffixes map[string][]*affix // keyed by their append
+	// flags listed as continuation of some affix
+	continuations map[rune]bool
+	aliases       [][]rune
+	flagType      string
+	encoding      string
+	needaffix     rune
+	ignore        map[rune]bool
+	ignoreCase    bool
+}
+
+/*
+Creates a new Dictionary containing the information read from the
+provided affix and dictionary files. The readers are not closed.
+
+If ignoreCase is true, words are matched regardless of case.
+*/
+func NewDictionary(affixes io.Reader, dictionaries []io.Reader, ignoreCase bool) (*Dictionary, error) {
+	ans := &Dictionary{
+		words:         make(map[string][][]rune),
+		prefixes:      make(map[string][]*affix),
+		suffixes:      make(map[string][]*affix),
+		continuations: make(map[rune]bool),
+		flagType:      UTF8_FLAG_TYPE,
+		encoding:      DEFAULT_ENCODING,
+		ignoreCase:    ignoreCase,
+	}
+	if err := ans.readAffixFile(affixes); err != nil {
+		return nil, err
+	}
+	for _, dictionary := range dictionaries {
+		if err := ans.readDictionaryFile(dictionary); err != nil {
+			return nil, err
+		}
+	}
+	return ans, nil
+}
+
+/* Returns true if words are matched regardless of case. */
+func (d *Dictionary) IgnoreCase() bool {
+	return d.ignoreCase
+}
+
+/* Decodes the whole content of r with the encoding declared by SET. */
+func (d *Dictionary) decode(r io.Reader) ([]string, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var text string
+	switch strings.ToUpper(strings.Replace(d.encoding, "-", "", -1)) {
+	case "UTF8":
+		if !utf8.Valid(data) {
+			return nil, errors.New("Invalid UTF-8 content")
+		}
+		text = strings.TrimPrefix(string(data), "\ufeff")
+	case "ISO88591":
+		runes := make([]rune, len(data))
+		for i, b := range data {
+			runes[i] = rune(b)
+		}
+		text = string(runes)
+	default:
+		return nil, fmt.Errorf("Encoding %v is not supported yet", d.encoding)
+	}
+	return strings.Split(strings.Replace(text, "\r\n", "\n", -1), "\n"), nil
+}
+
+var encodingPattern = regexp.MustCompile(`^SET\s+(\S+)`)
+
+/*
+Reads the affix file, line by line. The encoding is looked for first,
+since it applies to the whole file.
+*/
+func (d *Dictionary) readAffixFile(r io.Reader) error {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	for scanner.Scan() {
+		if m := encodingPattern.FindStringSubmatch(strings.TrimPrefix(scanner.Text(), "\ufeff")); m != nil {
+			d.encoding = m[1]
+			break
+		}
+	}
+	lines, err := d.decode(strings.NewReader(string(data)))
+	if err != nil {
+		return err
+	}
+	for i := 0; i < len(lines); i++ {
+		fields := strings.Fields(lines[i])
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		switch fields[0] {
+		case FLAG_KEY:
+			if len(fields) < 2 {
+				return fmt.Errorf("Illegal FLAG specification: %v", lines[i])
+			}
+			switch fields[1] {
+			case UTF8_FLAG_TYPE, LONG_FLAG_TYPE, NUM_FLAG_TYPE:
+				d.flagType = fields[1]
+			default:
+				return fmt.Errorf("Unknown flag type: %v", fields[1])
+			}
+		case ALIAS_KEY:
+			count := 0
+			if len(fields) > 1 {
+				count, _ = strconv.Atoi(fields[1])
+			}
+			if count < 1 || i+count >= len(lines) {
+				return fmt.Errorf("Illegal AF specification: %v", lines[i])
+			}
+			d.aliases = make([][]rune, 0, count)
+			for _, line := range lines[i+1 : i+1+count] {
+				alias := strings.Fields(line)
+				if len(alias) < 2 || alias[0] != ALIAS_KEY {
+					return fmt.Errorf("Illegal AF specification: %v", line)
+				}
+				flags, err := d.parseFlags(alias[1])
+				if err != nil {
+					return err
+				}
+				d.aliases = append(d.aliases, flags)
+			}
+			i += count
+		case NEEDAFFIX_KEY, PSEUDOROOT_KEY:
+			if len(fields) < 2 {
+				return fmt.Errorf("Illegal %v specification: %v", fields[0], lines[i])
+			}
+			flags, err := d.parseFlags(fields[1])
+			if err != nil || len(flags) != 1 {
+				return fmt.Errorf("Illegal %v flag: %v", fields[0], fields[1])
+			}
+			d.needaffix = flags[0]
+		case IGNORE_KEY:
+			if len(fields) < 2 {
+				return fmt.Errorf("Illegal IGNORE specification: %v", lines[i])
+			}
+			d.ignore = make(map[rune]bool)
+			for _, ch := range fields[1] {
+				d.ignore[ch] = true
+			}
+		case PREFIX_KEY, SUFFIX_KEY:
+			n, err := d.parseAffix(fields[0] == PREFIX_KEY, fields, lines[i+1:])
+			if err != nil {
+				return err
+			}
+			i += n
+		}
+	}
+	return nil
+}
+
+/*
+Parses a PFX or SFX block whose header is given, returning the number
+of rule lines it consumed.
+*/
+func (d *Dictionary) parseAffix(isPrefix bool, header []string, lines []string) (int, error) {
+	if len(header) < 4 {
+		return 0, fmt.Errorf("Illegal affix header: %v", strings.Join(header, " "))
+	}
+	flags, err := d.parseFlags(header[1])
+	if err != nil || len(flags) != 1 {
+		return 0, fmt.Errorf("Illegal affix flag: %v", header[1])
+	}
+	crossProduct := header[2] == "Y"
+	count, err := strconv.Atoi(header[3])
+	if err != nil || count > len(lines) {
+		return 0, fmt.Errorf("Illegal affix count: %v", header[3])
+	}
+	for i := 0; i < count; i++ {
+		rule := strings.Fields(lines[i])
+		if len(rule) < 4 || rule[0] != header[0] || rule[1] != header[1] {
+			return 0, fmt.Errorf("The affix file contains a rule with less than four elements: %v", lines[i])
+		}
+		a := &affix{flag: flags[0], crossProduct: crossProduct}
+		if rule[2] != "0" {
+			a.strip = d.clean(rule[2])
+		}
+		affixArg := rule[3]
+		if idx := strings.IndexRune(affixArg, '/'); idx >= 0 {
+			flagPart := affixArg[idx+1:]
+			if a.appendFlags, err = d.lookupFlags(flagPart); err != nil {
+				return 0, err
+			}
+			for _, flag := range a.appendFlags {
+				d.continuations[flag] = true
+			}
+			affixArg = affixArg[:idx]
+		}
+		if affixArg != "0" {
+			a.append = d.clean(affixArg)
+		}
+		condition := DEFAULT_CONDITION
+		if len(rule) > 4 {
+			condition = rule[4]
+		}
+		if a.condition, err = compileCondition(condition, isPrefix); err != nil {
+			return 0, err
+		}
+		if isPrefix {
+			d.prefixes[a.append] = append(d.prefixes[a.append], a)
+		} else {
+			d.suffixes[a.append] = append(d.suffixes[a.append], a)
+		}
+	}
+	return count, nil
+}
+
+/*
+Turns an affix condition into a regular expression anchored to the
+start of the stripped word for prefixes, and to its end for suffixes.
+*/
+func compileCondition(condition string, isPrefix bool) (*regexp.Regexp, error) {
+	if condition == DEFAULT_CONDITION {
+		return nil, nil
+	}
+	var b strings.Builder
+	if isPrefix {
+		b.WriteString("^")
+	}
+	inBrackets := false
+	for _, ch := range condition {
+		switch {
+		case ch == '[' && !inBrackets:
+			inBrackets = true
+			b.WriteRune(ch)
+		case ch == ']' && inBrackets:
+			inBrackets = false
+			b.WriteRune(ch)
+		case ch == '^' && inBrackets:
+			b.WriteRune(ch)
+		case ch == '.' && !inBrackets:
+			b.WriteString("(?s:.)")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(ch)))
+		}
+	}
+	if !isPrefix {
+		b.WriteString("$")
+	}
+	ans, err := regexp.Compile(b.String())
+	if err != nil {
+		return nil, fmt.Errorf("Illegal affix condition %v: %v", condition, err)
+	}
+	return ans, nil
+}
+
+/* Parses the flags of a word or an affix, which may be an alias number. */
+func (d *Dictionary) lookupFlags(s string) ([]rune, error) {
+	if d.aliases != nil {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 || n > len(d.aliases) {
+			return nil, fmt.Errorf("Illegal flag alias: %v", s)
+		}
+		return d.aliases[n-1], nil
+	}
+	return d.parseFlags(s)
+}
+
+/* Parses the flags according to the FLAG type, and sorts them. */
+func (d *Dictionary) parseFlags(s string) ([]rune, error) {
+	var flags []rune
+	switch d.flagType {
+	case NUM_FLAG_TYPE:
+		for _, part := range strings.Split(s, ",") {
+			if part = strings.TrimSpace(part); part == "" {
+				continue
+			}
+			n, err := strconv.Atoi(part)
+			if err != nil {
+				return nil, fmt.Errorf("Illegal numeric flag: %v", part)
+			}
+			flags = append(flags, rune(n))
+		}
+	case LONG_FLAG_TYPE:
+		runes := []rune(s)
+		if len(runes)%2 != 0 {
+			return nil, fmt.Errorf("Invalid flags (should be even number of characters): %v", s)
+		}
+		for i := 0; i < len(runes); i += 2 {
+			flags = append(flags, runes[i]<<16|runes[i+1])
+		}
+	default:
+		flags = []rune(s)
+	}
+	sort.Slice(flags, func(i, j int) bool { return flags[i] < flags[j] })
+	return flags, nil
+}
+
+/* Reads the dictionary file: a line with the count of words, then one word/flags per line. */
+func (d *Dictionary) readDictionaryFile(r io.Reader) error {
+	lines, err := d.decode(r)
+	if err != nil {
+		return err
+	}
+	for i, line := range lines {
+		if i == 0 {
+			continue // the approximate number of words
+		}
+		if line = strings.TrimSpace(line); line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		// morphological fields follow a tab or a space
+		if end := strings.IndexAny(line, " \t"); end >= 0 {
+			line = line[:end]
+		}
+		word, flagPart := line, ""
+		for j := 0; j < len(line); j++ {
+			if line[j] == '\\' {
+				j++
+			} else if line[j] == '/' {
+				word, flagPart = line[:j], line[j+1:]
+				break
+			}
+		}
+		word = strings.Replace(word, "\\/", "/", -1)
+		var flags []rune
+		if flagPart != "" {
+			if flags, err = d.lookupFlags(flagPart); err != nil {
+				return err
+			}
+		}
+		key := d.clean(word)
+		d.words[key] = append(d.words[key], flags)
+	}
+	return nil
+}
+
+/* Removes the ignored characters, and lower cases if the dictionary ignores case. */
+func (d *Dictionary) clean(word string) string {
+	if d.ignore == nil && !d.ignoreCase {
+		return word
+	}
+	runes := make([]rune, 0, len(word))
+	for _, ch := range word {
+		if d.ignore[ch] {
+			continue
+		}
+		if d.ignoreCase {
+			ch = unicode.ToLower(ch)
+		}
+		runes = append(runes, ch)
+	}
+	return string(runes)
+}
+
+func hasFlag(flags []rune, flag rune) bool {
+	i := sort.Search(len(flags), func(i int) bool { return flags[i] >= flag })
+	return i < len(flags) && flags[i] == flag
+}
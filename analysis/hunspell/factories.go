@@ -0,0 +1,71 @@
+package hunspell
+
+import (
+	"errors"
+	. "github.com/balzaczyy/golucene/analysis/util"
+	. "github.com/balzaczyy/golucene/core/analysis"
+	"io"
+	"os"
+)
+
+func init() {
+	RegisterTokenFilterFactory("hunspellStem", func(args map[string]string) (TokenFilterFactory, error) {
+		f, err := NewHunspellStemFilterFactory(args)
+		if err != nil {
+			return nil, err
+		}
+		return f, nil
+	})
+}
+
+// hunspell/HunspellStemFilterFactory.java
+
+/*
+TokenFilterFactory that creates instances of HunspellStemFilter. It
+takes the "dictionary", a comma-separated list of .dic files, and the
+"affix" file. "ignoreCase" (false by default) matches the words
+regardless of case, and "longestOnly" (false by default) only keeps
+the longest stem of each word.
+*/
+type HunspellStemFilterFactory struct {
+	*AbstractAnalysisFactory
+	dictionary  *Dictionary
+	longestOnly bool
+}
+
+func NewHunspellStemFilterFactory(args map[string]string) (*HunspellStemFilterFactory, error) {
+	ans := &HunspellStemFilterFactory{AbstractAnalysisFactory: NewAbstractAnalysisFactory(args)}
+	dictionaryFiles := ans.GetSet("dictionary")
+	affixFile := ans.Require("affix")
+	ignoreCase := ans.GetBool("ignoreCase", false)
+	ans.longestOnly = ans.GetBool("longestOnly", false)
+	if err := ans.Validate(); err != nil {
+		return nil, err
+	}
+	if len(dictionaryFiles) == 0 {
+		return nil, errors.New("Configuration Error: missing parameter 'dictionary'")
+	}
+
+	affix, err := os.Open(affixFile)
+	if err != nil {
+		return nil, err
+	}
+	defer affix.Close()
+	var dictionaries []io.Reader
+	for _, file := range dictionaryFiles {
+		dictionary, err := os.Open(file)
+		if err != nil {
+			return nil, err
+		}
+		defer dictionary.Close()
+		dictionaries = append(dictionaries, dictionary)
+	}
+	if ans.dictionary, err = NewDictionary(affix, dictionaries, ignoreCase); err != nil {
+		return nil, err
+	}
+	return ans, nil
+}
+
+func (f *HunspellStemFilterFactory) Create(input TokenStream) TokenStream {
+	return NewHunspellStemFilterWithOptions(input, f.dictionary, true, f.longestOnly)
+}
@@ -0,0 +1,106 @@
+package hunspell
+
+import (
+	. "github.com/balzaczyy/golucene/core/analysis"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"github.com/balzaczyy/golucene/core/util"
+	"sort"
+)
+
+// hunspell/HunspellStemFilter.java
+
+/*
+TokenFilter that uses hunspell affix rules and words to stem tokens.
+Since hunspell supports a word having multiple stems, this filter can
+emit multiple tokens for each consumed token, at the same position.
+
+Tokens marked as keywords via the KeywordAttribute are left as is,
+and so are the tokens the dictionary knows no stem of.
+*/
+type HunspellStemFilter struct {
+	*TokenFilter
+	input       TokenStream
+	stemmer     *Stemmer
+	dedup       bool
+	longestOnly bool
+
+	buffer     [][]rune
+	savedState *util.AttributeState
+
+	termAtt    CharTermAttribute
+	posIncAtt  PositionIncrementAttribute
+	keywordAtt KeywordAttribute
+}
+
+/* Create a HunspellStemFilter outputting all possible stems, deduplicated. */
+func NewHunspellStemFilter(input TokenStream, dictionary *Dictionary) *HunspellStemFilter {
+	return NewHunspellStemFilterWithOptions(input, dictionary, true, false)
+}
+
+/*
+Creates a new HunspellStemFilter that will stem tokens from the given
+TokenStream using affix rules in the provided Dictionary.
+
+If dedup is true, duplicate stems are removed. If longestOnly is
+true, only the longest stem is output.
+*/
+func NewHunspellStemFilterWithOptions(input TokenStream, dictionary *Dictionary, dedup, longestOnly bool) *HunspellStemFilter {
+	ans := &HunspellStemFilter{
+		TokenFilter: NewTokenFilter(input),
+		input:       input,
+		stemmer:     NewStemmer(dictionary),
+		dedup:       dedup && !longestOnly, // don't waste time deduping longestOnly
+		longestOnly: longestOnly,
+	}
+	ans.termAtt = ans.Attributes().Add("CharTermAttribute").(CharTermAttribute)
+	ans.posIncAtt = ans.Attributes().Add("PositionIncrementAttribute").(PositionIncrementAttribute)
+	ans.keywordAtt = ans.Attributes().Add("KeywordAttribute").(KeywordAttribute)
+	return ans
+}
+
+func (f *HunspellStemFilter) IncrementToken() (bool, error) {
+	if len(f.buffer) > 0 {
+		nextStem := f.buffer[0]
+		f.buffer = f.buffer[1:]
+		f.Attributes().RestoreState(f.savedState)
+		f.posIncAtt.SetPositionIncrement(0)
+		f.termAtt.CopyBuffer(nextStem)
+		return true, nil
+	}
+
+	ok, err := f.input.IncrementToken()
+	if err != nil || !ok {
+		return false, err
+	}
+	if f.keywordAtt.IsKeyword() {
+		return true, nil
+	}
+
+	term := f.termAtt.Buffer()[:f.termAtt.Length()]
+	if f.dedup {
+		f.buffer = f.stemmer.UniqueStems(term)
+	} else {
+		f.buffer = f.stemmer.Stem(term)
+	}
+	if len(f.buffer) == 0 { // we do not know this word, return it unchanged
+		return true, nil
+	}
+	if f.longestOnly && len(f.buffer) > 1 {
+		sort.SliceStable(f.buffer, func(i, j int) bool { return len(f.buffer[i]) > len(f.buffer[j]) })
+		f.buffer = f.buffer[:1]
+	}
+
+	stem := f.buffer[0]
+	f.buffer = f.buffer[1:]
+	f.termAtt.CopyBuffer(stem)
+	if len(f.buffer) > 0 {
+		f.savedState = f.Attributes().CaptureState()
+	}
+	return true, nil
+}
+
+func (f *HunspellStemFilter) Reset() error {
+	f.buffer = nil
+	f.savedState = nil
+	return f.TokenFilter.Reset()
+}
@@ -0,0 +1,153 @@
+package hunspell
+
+import (
+	"fmt"
+	std "github.com/balzaczyy/golucene/analysis/standard"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"github.com/balzaczyy/golucene/core/util"
+	"io"
+	"sort"
+	"strings"
+	"testing"
+)
+
+const simpleAffix = `SET UTF-8
+TRY abcdefghijklmopqrstuvwxyz
+
+# plurals
+SFX S Y 2
+SFX S   0     s         [^sxy]
+SFX S   y     ies       [^aeiou]y
+
+# twofold suffixes: -able can be followed by S
+SFX X Y 1
+SFX X   0     able/S    .
+
+SFX D N 1
+SFX D   0     ed        [^e]
+
+PFX U Y 1
+PFX U   0     un        .
+
+PFX R N 1
+PFX R   0     re        .
+
+NEEDAFFIX N
+`
+
+const simpleDictionary = `9
+drink/XSU
+city/S
+lucene
+look/DR
+walk/SDU
+walk/R
+ness/N
+kindness/S
+drinks
+`
+
+func newSimpleDictionary(t *testing.T, ignoreCase bool) *Dictionary {
+	d, err := NewDictionary(strings.NewReader(simpleAffix),
+		[]io.Reader{strings.NewReader(simpleDictionary)}, ignoreCase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func TestStemmer(t *testing.T) {
+	stemmer := NewStemmer(newSimpleDictionary(t, false))
+	for word, expected := range map[string]string{
+		"lucene":      "[lucene]",
+		"drinks":      "[drink drinks]",
+		"drinkable":   "[drink]",
+		"drinkables":  "[drink]",
+		"undrinkable": "[drink]",
+		"cities":      "[city]",
+		"looked":      "[look]",
+		"relooked":    "[]", // R does not combine
+		"rewalk":      "[walk]",
+		"unwalks":     "[walk]",
+		"unwalked":    "[]", // D does not combine
+		"ness":        "[]", // needs an affix
+		"kindnesses":  "[]",
+		"kindness":    "[kindness]",
+		"Drinks":      "[]",
+		"xyz":         "[]",
+	} {
+		var stems []string
+		for _, stem := range stemmer.UniqueStems([]rune(word)) {
+			stems = append(stems, string(stem))
+		}
+		sort.Strings(stems)
+		if got := fmt.Sprint(stems); got != expected {
+			t.Errorf("%v: expected %v, but was %v", word, expected, got)
+		}
+	}
+
+	stemmer = NewStemmer(newSimpleDictionary(t, true))
+	if got := stemmer.UniqueStems([]rune("Cities")); len(got) != 1 || string(got[0]) != "city" {
+		t.Errorf("expected [city], but was %q", got)
+	}
+}
+
+func TestDictionaryFlags(t *testing.T) {
+	for _, c := range []struct{ affix, dictionary string }{
+		{"FLAG long\nSFX Aa Y 1\nSFX Aa 0 s .\n", "1\nwalk/AaBb\n"},
+		{"FLAG num\nSFX 1001 Y 1\nSFX 1001 0 s .\n", "1\nwalk/1001,17\n"},
+		{"AF 2\nAF BA\nAF C\nSFX A Y 1\nSFX A 0 s .\n", "1\nwalk/1\n"},
+		{"SET ISO8859-1\nSFX A Y 1\nSFX A 0 s .\n", "1\nwalk/A\n"},
+	} {
+		d, err := NewDictionary(strings.NewReader(c.affix), []io.Reader{strings.NewReader(c.dictionary)}, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := NewStemmer(d).Stem([]rune("walks")); len(got) != 1 || string(got[0]) != "walk" {
+			t.Errorf("%q: expected [walk], but was %q", c.affix, got)
+		}
+	}
+
+	if _, err := NewDictionary(strings.NewReader("FLAG long\nSFX A Y 1\nSFX A 0 s .\n"), nil, false); err == nil {
+		t.Error("expected an error for an odd long flag")
+	}
+	if _, err := NewDictionary(strings.NewReader("SFX A Y 2\nSFX A 0 s .\n"), nil, false); err == nil {
+		t.Error("expected an error for a truncated affix block")
+	}
+}
+
+func TestHunspellStemFilter(t *testing.T) {
+	d := newSimpleDictionary(t, true)
+	for _, c := range []struct {
+		longestOnly bool
+		expected    string
+	}{
+		{false, "lucene/1 drink/1 Unknowns/1 drinks/1 drink/0"},
+		{true, "lucene/1 drink/1 Unknowns/1 drinks/1"},
+	} {
+		ts := NewHunspellStemFilterWithOptions(std.NewStandardTokenizer(util.VERSION_LATEST,
+			strings.NewReader("lucene undrinkables Unknowns drinks")), d, true, c.longestOnly)
+		termAtt := ts.Attributes().Get("CharTermAttribute").(CharTermAttribute)
+		posIncAtt := ts.Attributes().Get("PositionIncrementAttribute").(PositionIncrementAttribute)
+		if err := ts.Reset(); err != nil {
+			t.Fatal(err)
+		}
+		var tokens []string
+		for {
+			ok, err := ts.IncrementToken()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !ok {
+				break
+			}
+			tokens = append(tokens, fmt.Sprintf("%v/%v",
+				string(termAtt.Buffer()[:termAtt.Length()]), posIncAtt.PositionIncrement()))
+		}
+		ts.End()
+		ts.Close()
+		if got := strings.Join(tokens, " "); got != c.expected {
+			t.Errorf("longestOnly=%v: expected %v, but was %v", c.longestOnly, c.expected, got)
+		}
+	}
+}
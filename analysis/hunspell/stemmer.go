@@ -0,0 +1,147 @@
+package hunspell
+
+// hunspell/Stemmer.java
+
+/*
+Stemmer uses the affix rules declared in the Dictionary to generate
+one or more stems for a word. It conforms to the algorithm in the
+original hunspell algorithm, including recursive suffix stripping.
+*/
+type Stemmer struct {
+	dictionary *Dictionary
+}
+
+/* Constructs a new Stemmer which will use the provided Dictionary to create its stems. */
+func NewStemmer(dictionary *Dictionary) *Stemmer {
+	return &Stemmer{dictionary}
+}
+
+/* Find the stem(s) of the provided word. */
+func (s *Stemmer) Stem(word []rune) [][]rune {
+	w := s.dictionary.clean(string(word))
+	var stems [][]rune
+	if s.isWord(w) {
+		stems = append(stems, []rune(w))
+	}
+	return append(stems, s.stem(w, 0, false, 0, true, true)...)
+}
+
+/* Find the unique stem(s) of the provided word, in the order they were first found. */
+func (s *Stemmer) UniqueStems(word []rune) [][]rune {
+	stems := s.Stem(word)
+	if len(stems) < 2 {
+		return stems
+	}
+	seen := make(map[string]bool)
+	ans := make([][]rune, 0, len(stems))
+	for _, stem := range stems {
+		if key := string(stem); !seen[key] {
+			seen[key] = true
+			ans = append(ans, stem)
+		}
+	}
+	return ans
+}
+
+/* Returns true if the word is in the dictionary as a standalone word. */
+func (s *Stemmer) isWord(word string) bool {
+	for _, flags := range s.dictionary.words[word] {
+		if s.dictionary.needaffix == 0 || !hasFlag(flags, s.dictionary.needaffix) {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+Generates a list of stems for the provided word, stripping one affix
+and recursing for the next.
+
+prevFlag is the flag of the affix stripped before, if any, and
+prevIsPrefix tells its kind. Two suffixes are chained through the
+continuation flags of the inner one, while a prefix and a suffix are
+combined if both allow cross products.
+*/
+func (s *Stemmer) stem(word string, prevFlag rune, prevIsPrefix bool, recursionDepth int,
+	doPrefix, doSuffix bool) [][]rune {
+
+	var stems [][]rune
+	runes := []rune(word)
+	if doPrefix {
+		for i := 0; i <= len(runes); i++ {
+			for _, a := range s.dictionary.prefixes[string(runes[:i])] {
+				if prevFlag != 0 && !a.crossProduct {
+					continue
+				}
+				strippedWord := a.strip + string(runes[i:])
+				if a.condition != nil && !a.condition.MatchString(strippedWord) {
+					continue
+				}
+				stems = append(stems, s.applyAffix(strippedWord, a, prevFlag, prevIsPrefix, true, recursionDepth)...)
+			}
+		}
+	}
+	if doSuffix {
+		for i := len(runes); i >= 0; i-- {
+			for _, a := range s.dictionary.suffixes[string(runes[i:])] {
+				if prevFlag != 0 {
+					if prevIsPrefix && !a.crossProduct {
+						continue
+					}
+					if !prevIsPrefix && !hasFlag(a.appendFlags, prevFlag) {
+						continue
+					}
+				}
+				strippedWord := string(runes[:i]) + a.strip
+				if a.condition != nil && !a.condition.MatchString(strippedWord) {
+					continue
+				}
+				stems = append(stems, s.applyAffix(strippedWord, a, prevFlag, prevIsPrefix, false, recursionDepth)...)
+			}
+		}
+	}
+	return stems
+}
+
+/*
+Applies the affix rule to the given word, producing a list of stems
+if any are found.
+*/
+func (s *Stemmer) applyAffix(strippedWord string, a *affix, prevFlag rune, prevIsPrefix, isPrefix bool,
+	recursionDepth int) [][]rune {
+
+	var stems [][]rune
+	for _, flags := range s.dictionary.words[strippedWord] {
+		if !hasFlag(flags, a.flag) {
+			continue
+		}
+		// a suffix chained after another is a continuation: only the
+		// inner one must be allowed by the word; a cross product
+		// needs both the prefix and the suffix to be allowed
+		if prevFlag != 0 && prevIsPrefix != isPrefix && !hasFlag(flags, prevFlag) &&
+			!hasFlag(a.appendFlags, prevFlag) {
+			continue
+		}
+		stems = append(stems, []rune(strippedWord))
+		break
+	}
+
+	if recursionDepth+1 >= MAX_RECURSION_LEVEL {
+		return stems
+	}
+	if isPrefix {
+		if a.crossProduct && prevFlag == 0 {
+			stems = append(stems, s.stem(strippedWord, a.flag, true, recursionDepth+1, false, true)...)
+		}
+	} else if prevFlag == 0 || !prevIsPrefix {
+		// at most two suffixes, then a prefix
+		if prevFlag == 0 && s.dictionary.continuations[a.flag] {
+			stems = append(stems, s.stem(strippedWord, a.flag, false, recursionDepth+1, false, true)...)
+		}
+		if a.crossProduct {
+			stems = append(stems, s.stem(strippedWord, a.flag, false, recursionDepth+1, true, false)...)
+		}
+	}
+	return stems
+}
+
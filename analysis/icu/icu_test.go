@@ -0,0 +1,152 @@
+package icu
+
+import (
+	"fmt"
+	"github.com/balzaczyy/golucene/analysis/standard"
+	. "github.com/balzaczyy/golucene/core/analysis"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"github.com/balzaczyy/golucene/core/util"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	for _, v := range [][2]string{
+		{"This is a test", "this is a test"},
+		{"Ruß", "russ"},
+		{"ΜΆΪΟΣ", "μάϊοσ"},
+		{"Μάϊος", "μάϊοσ"},
+		{"𐐖", "𐐾"},
+		{"ﴳﴺﰧ", "طمطمطم"},
+		{"१२ड़", "१२ड़"},
+		{"ﬁ", "fi"},
+		{"Ｈｅｌｌｏ１２３", "hello123"},
+		{"é", "é"},
+		{"ậ", "ậ"},
+		{"a‍b", "ab"},
+		{"각", "각"},
+		{"ｶﾞ", "ガ"},
+	} {
+		if got := string(NFKC_CASEFOLD.Normalize([]rune(v[0]))); got != v[1] {
+			t.Errorf("Normalize(%q): expected %q, got %q", v[0], v[1], got)
+		}
+	}
+	if !NFKC_CASEFOLD.IsNormalized([]rune("résumé")) {
+		t.Error("résumé should be normalized")
+	}
+	if NFKC_CASEFOLD.IsNormalized([]rune("Résumé")) {
+		t.Error("Résumé should not be normalized")
+	}
+}
+
+func TestFold(t *testing.T) {
+	for _, v := range [][2]string{
+		{"Résumé", "resume"},
+		{"ΜΆΪΟΣ", "μαιοσ"},
+		{"٣٤٥", "345"},
+		{"१२", "12"},
+		{"ｶﾞ", "ガ"},
+		{"שָׁלוֹם", "שלום"},
+	} {
+		if got := string(UTR30_FOLDING.Normalize([]rune(v[0]))); got != v[1] {
+			t.Errorf("Fold(%q): expected %q, got %q", v[0], v[1], got)
+		}
+	}
+}
+
+func assertTokenStream(t *testing.T, ts TokenStream, expected []string, offsets [][2]int) {
+	termAtt := ts.Attributes().Add("CharTermAttribute").(CharTermAttribute)
+	offsetAtt := ts.Attributes().Add("OffsetAttribute").(OffsetAttribute)
+	if err := ts.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	var gotOffsets [][2]int
+	for {
+		ok, err := ts.IncrementToken()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			break
+		}
+		got = append(got, string(termAtt.Buffer()[:termAtt.Length()]))
+		gotOffsets = append(gotOffsets, [2]int{offsetAtt.StartOffset(), offsetAtt.EndOffset()})
+	}
+	if err := ts.End(); err != nil {
+		t.Fatal(err)
+	}
+	if err := ts.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(got) != fmt.Sprint(expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+	if offsets != nil && fmt.Sprint(gotOffsets) != fmt.Sprint(offsets) {
+		t.Errorf("expected offsets %v, got %v", offsets, gotOffsets)
+	}
+}
+
+func TestNormalizer2Filter(t *testing.T) {
+	ts := NewICUNormalizer2Filter(standard.NewStandardTokenizer(util.VERSION_LATEST, strings.NewReader("This is a ＴＥＳＴ Ruß ﬁle")))
+	assertTokenStream(t, ts, []string{"this", "is", "a", "test", "russ", "file"}, nil)
+
+	ts = NewICUFoldingFilter(standard.NewStandardTokenizer(util.VERSION_LATEST, strings.NewReader("Résumé Ｃａｆé ٣٤")))
+	assertTokenStream(t, ts, []string{"resume", "cafe", "34"}, nil)
+}
+
+func TestNormalizer2CharFilter(t *testing.T) {
+	filter := NewICUNormalizer2CharFilter(strings.NewReader("ﬁx ét"))
+	var text []rune
+	for {
+		ch, _, err := filter.ReadRune()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		text = append(text, ch)
+	}
+	if string(text) != "fix ét" {
+		t.Fatalf("expected %q, got %q", "fix ét", string(text))
+	}
+	for off, expected := range []int{0, 1, 1, 2, 3, 5, 6} {
+		if got := filter.CorrectOffset(off); got != expected {
+			t.Errorf("CorrectOffset(%v): expected %v, got %v", off, expected, got)
+		}
+	}
+
+	ts := NewICUTokenizer(NewICUNormalizer2CharFilter(strings.NewReader("ﬁx Ｃａｆé")))
+	assertTokenStream(t, ts, []string{"fix", "café"}, [][2]int{{0, 2}, {3, 7}})
+}
+
+func TestTokenizer(t *testing.T) {
+	ts := NewICUTokenizer(strings.NewReader("Testтест, 123 αβγ 漢字"))
+	scriptAtt := ts.Attributes().Get("ScriptAttribute").(ScriptAttribute)
+	typeAtt := ts.Attributes().Add("TypeAttribute").(TypeAttribute)
+	if err := ts.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for {
+		ok, err := ts.IncrementToken()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			break
+		}
+		got = append(got, fmt.Sprintf("%v/%v/%v", string(ts.termAtt.Buffer()[:ts.termAtt.Length()]), typeAtt.Type(), scriptAtt.Script()))
+	}
+	ts.End()
+	ts.Close()
+	expected := []string{"Test/<ALPHANUM>/Latin", "тест/<ALPHANUM>/Cyrillic",
+		"123/<NUM>/Cyrillic", "αβγ/<ALPHANUM>/Greek", "漢/<IDEOGRAPHIC>/Han", "字/<IDEOGRAPHIC>/Han"}
+	if fmt.Sprint(got) != fmt.Sprint(expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+
+	ts = NewICUTokenizer(strings.NewReader("Testтест"))
+	assertTokenStream(t, ts, []string{"Test", "тест"}, [][2]int{{0, 4}, {4, 8}})
+}
@@ -0,0 +1,228 @@
+package icu
+
+import (
+	"sort"
+)
+
+// com/ibm/icu/text/Normalizer2.java
+
+/*
+Unicode normalization functionality for standard Unicode normalization
+or for using custom mapping tables.
+
+Only the composed NFKC_Casefold normalization used by the analysis
+components is provided: compatibility decomposition, case folding and
+removal of default ignorable code points, followed by canonical
+composition. The tables are generated from the Unicode character
+database.
+*/
+type Normalizer2 interface {
+	// Returns the normalized form of the source text.
+	Normalize(src []rune) []rune
+	// Tests if the text is normalized.
+	IsNormalized(src []rune) bool
+}
+
+/* The NFKC_Casefold normalizer, as in ICU's Normalizer2.getNFKCCasefoldInstance(). */
+var NFKC_CASEFOLD Normalizer2 = &normalizer2{}
+
+/*
+The normalizer behind ICUFoldingFilter: NFKC_Casefold, plus removal of
+diacritics and folding of native digits to ASCII.
+*/
+var UTR30_FOLDING Normalizer2 = &normalizer2{fold: true}
+
+type normalizer2 struct {
+	// whether to apply the additional foldings of UTR30_FOLDING
+	fold bool
+}
+
+func (n *normalizer2) Normalize(src []rune) []rune {
+	ans := make([]rune, 0, len(src))
+	for _, ch := range src {
+		if m, ok := nfkcCasefoldMappings[ch]; ok {
+			for _, c := range m {
+				ans = n.appendFolded(ans, c)
+			}
+		} else {
+			ans = n.appendFolded(ans, ch)
+		}
+	}
+	canonicalOrder(ans)
+	return compose(ans)
+}
+
+func (n *normalizer2) IsNormalized(src []rune) bool {
+	for _, ch := range src {
+		if ch >= 0x80 || 'A' <= ch && ch <= 'Z' {
+			normalized := n.Normalize(src)
+			if len(normalized) != len(src) {
+				return false
+			}
+			for i, c := range normalized {
+				if c != src[i] {
+					return false
+				}
+			}
+			return true
+		}
+	}
+	return true // lowercased ASCII is always normalized
+}
+
+/* Appends a decomposed rune, applying the extra foldings if needed. */
+func (n *normalizer2) appendFolded(buf []rune, ch rune) []rune {
+	if n.fold {
+		if isFoldedDiacritic(ch) {
+			return buf
+		}
+		if ch >= 0x80 {
+			if zero := decimalZero(ch); zero >= 0 {
+				ch = '0' + ch - zero
+			}
+		}
+	}
+	return append(buf, ch)
+}
+
+/*
+Diacritics removed by folding: the combining diacritical marks, and
+Hebrew and Arabic points. Other combining marks, such as Indic vowel
+signs, are kept.
+*/
+func isFoldedDiacritic(ch rune) bool {
+	switch {
+	case 0x0300 <= ch && ch <= 0x036F,
+		0x1AB0 <= ch && ch <= 0x1AFF,
+		0x1DC0 <= ch && ch <= 0x1DFF,
+		0x20D0 <= ch && ch <= 0x20FF,
+		0xFE20 <= ch && ch <= 0xFE2F:
+		return true
+	case 0x0591 <= ch && ch <= 0x05C7:
+		return ch != 0x05BE && ch != 0x05C0 && ch != 0x05C3 && ch != 0x05C6
+	case 0x064B <= ch && ch <= 0x065F, ch == 0x0670:
+		return true
+	}
+	return false
+}
+
+/* Returns the zero of the decimal digit range holding ch, or -1. */
+func decimalZero(ch rune) rune {
+	i := sort.Search(len(decimalZeros), func(i int) bool { return decimalZeros[i] > ch }) - 1
+	if i >= 0 && ch-decimalZeros[i] < 10 {
+		return decimalZeros[i]
+	}
+	return -1
+}
+
+/* Returns the canonical combining class of ch. */
+func combiningClass(ch rune) int {
+	if ch < 0x300 {
+		return 0
+	}
+	i := sort.Search(len(combiningClasses), func(i int) bool { return combiningClasses[i].hi >= ch })
+	if i < len(combiningClasses) && combiningClasses[i].lo <= ch {
+		return int(combiningClasses[i].ccc)
+	}
+	return 0
+}
+
+/* Sorts each sequence of combining marks by combining class, keeping the relative order of equal classes. */
+func canonicalOrder(s []rune) {
+	for i := 1; i < len(s); i++ {
+		cc := combiningClass(s[i])
+		if cc == 0 {
+			continue
+		}
+		for j := i; j > 0; j-- {
+			prev := combiningClass(s[j-1])
+			if prev <= cc {
+				break
+			}
+			s[j-1], s[j] = s[j], s[j-1]
+		}
+	}
+}
+
+const (
+	hangulSBase  = 0xAC00
+	hangulLBase  = 0x1100
+	hangulVBase  = 0x1161
+	hangulTBase  = 0x11A7
+	hangulLCount = 19
+	hangulVCount = 21
+	hangulTCount = 28
+	hangulNCount = hangulVCount * hangulTCount
+	hangulSCount = hangulLCount * hangulNCount
+)
+
+/* Returns the primary composite of a starter and a following rune, if any. */
+func composePair(a, b rune) (rune, bool) {
+	if hangulLBase <= a && a < hangulLBase+hangulLCount &&
+		hangulVBase <= b && b < hangulVBase+hangulVCount {
+		return hangulSBase + ((a-hangulLBase)*hangulVCount+b-hangulVBase)*hangulTCount, true
+	}
+	if hangulSBase <= a && a < hangulSBase+hangulSCount && (a-hangulSBase)%hangulTCount == 0 &&
+		hangulTBase < b && b < hangulTBase+hangulTCount {
+		return a + b - hangulTBase, true
+	}
+	c, ok := canonicalCompositions[[2]rune{a, b}]
+	return c, ok
+}
+
+/* Canonically composes the decomposed and reordered text in place. */
+func compose(s []rune) []rune {
+	if len(s) == 0 {
+		return s
+	}
+	starter, lastCC := -1, 0
+	if lastCC = combiningClass(s[0]); lastCC == 0 {
+		starter = 0
+	}
+	w := 1
+	for _, ch := range s[1:] {
+		cc := combiningClass(ch)
+		// ch combines with the last starter unless a rune in between
+		// blocks it
+		if starter >= 0 && (lastCC == 0 || lastCC < cc) {
+			if c, ok := composePair(s[starter], ch); ok {
+				s[starter] = c
+				continue
+			}
+		}
+		if cc == 0 {
+			starter = w
+		}
+		lastCC = cc
+		s[w] = ch
+		w++
+	}
+	return s[:w]
+}
+
+var compositionSeconds = func() map[rune]bool {
+	ans := make(map[rune]bool)
+	for pair := range canonicalCompositions {
+		ans[pair[1]] = true
+	}
+	return ans
+}()
+
+/*
+Returns true if normalization never interacts across a boundary
+before ch, i.e. text can be normalized piecewise at ch.
+*/
+func hasBoundaryBefore(ch rune) bool {
+	first := ch
+	if m, ok := nfkcCasefoldMappings[ch]; ok {
+		if m == "" {
+			return false // removed, so its neighbours may meet
+		}
+		first = []rune(m)[0]
+	}
+	if hangulVBase <= first && first < hangulVBase+hangulVCount ||
+		hangulTBase < first && first < hangulTBase+hangulTCount {
+		return false
+	}
+	return combiningClass(first) == 0 && !compositionSeconds[first]
+}
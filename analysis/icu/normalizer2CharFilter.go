@@ -0,0 +1,94 @@
+package icu
+
+import (
+	. "github.com/balzaczyy/golucene/core/analysis"
+	"io"
+)
+
+// icu/ICUNormalizer2CharFilter.java
+
+/*
+Normalize token text with ICU's Normalizer2, before tokenization, so
+that the tokenizer sees normalized text while offsets still point
+into the original input.
+
+The input is normalized piecewise, between runes where normalization
+can't interact across the boundary; offsets inside a piece that grew
+map to its end in the original input.
+*/
+type ICUNormalizer2CharFilter struct {
+	*BaseCharFilter
+	normalizer Normalizer2
+
+	output []rune
+	pos    int
+	read   bool
+}
+
+/* Create a new ICUNormalizer2CharFilter that combines NFKC normalization, Case Folding, and removes Default Ignorables (NFKC_Casefold) */
+func NewICUNormalizer2CharFilter(in io.RuneReader) *ICUNormalizer2CharFilter {
+	return NewICUNormalizer2CharFilterWith(in, NFKC_CASEFOLD)
+}
+
+/* Create a new ICUNormalizer2CharFilter with the specified Normalizer2 */
+func NewICUNormalizer2CharFilterWith(in io.RuneReader, normalizer Normalizer2) *ICUNormalizer2CharFilter {
+	return &ICUNormalizer2CharFilter{BaseCharFilter: NewBaseCharFilter(in), normalizer: normalizer}
+}
+
+func (f *ICUNormalizer2CharFilter) ReadRune() (rune, int, error) {
+	if !f.read {
+		if err := f.normalizeInput(); err != nil {
+			return 0, 0, err
+		}
+		f.read = true
+	}
+	if f.pos >= len(f.output) {
+		return 0, 0, io.EOF
+	}
+	ch := f.output[f.pos]
+	f.pos++
+	return ch, 1, nil
+}
+
+func (f *ICUNormalizer2CharFilter) normalizeInput() error {
+	var input []rune
+	for {
+		ch, _, err := f.Input.ReadRune()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		input = append(input, ch)
+	}
+
+	start := 0
+	for end := 1; end <= len(input); end++ {
+		if end < len(input) && !hasBoundaryBefore(input[end]) {
+			continue
+		}
+		f.appendNormalized(input[start:end], start)
+		start = end
+	}
+	return nil
+}
+
+/* Appends the normalized piece, recording the offset corrections it needs. */
+func (f *ICUNormalizer2CharFilter) appendNormalized(piece []rune, inputStart int) {
+	outputStart := len(f.output)
+	f.output = append(f.output, f.normalizer.Normalize(piece)...)
+	for off := outputStart + 1; off <= len(f.output); off++ {
+		inputOff := inputStart + len(piece)
+		if off-outputStart < len(piece) && off < len(f.output) {
+			inputOff = inputStart + off - outputStart
+		}
+		f.addOffCorrectMap(off, inputOff-off)
+	}
+}
+
+/* Records a correction, skipping those that don't change the cumulative difference. */
+func (f *ICUNormalizer2CharFilter) addOffCorrectMap(off, cumulativeDiff int) {
+	if cumulativeDiff != f.LastCumulativeDiff() {
+		f.AddOffCorrectMap(off, cumulativeDiff)
+	}
+}
@@ -0,0 +1,118 @@
+package icu
+
+import (
+	. "github.com/balzaczyy/golucene/core/analysis"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+)
+
+// icu/ICUNormalizer2Filter.java
+
+/*
+Normalize token text with ICU's Normalizer2.
+
+With this filter, you can normalize text in the following ways:
+
+	- NFKC Normalization, Case Folding, and removing Ignorables (the
+	default)
+	- Using a standard Normalization mode (NFC, NFD, NFKC, NFKD)
+	- Based on rules from a custom normalization mapping.
+
+If you use the defaults, this filter is a simple way to standardize
+Unicode text in a language-independent way for search:
+
+	- The case folding that it does can be seen as a replacement for
+	LowerCaseFilter: For example, it handles cases such as the Greek
+	sigma, so that "Μάϊος" and "ΜΆΪΟΣ" will match correctly.
+	- The normalization will standardizes different forms of the same
+	character in Unicode. For example, CJK full-width numbers will be
+	standardized to their ASCII forms.
+	- Ignorables such as Zero-Width Joiner and Variation Selectors are
+	removed. These are typically modifier characters that affect
+	display.
+
+GoLucene only provides the NFKC_CASEFOLD and UTR30_FOLDING normalizers
+for now; other modes can be plugged in as Normalizer2.
+*/
+type ICUNormalizer2Filter struct {
+	*TokenFilter
+	input      TokenStream
+	normalizer Normalizer2
+	termAtt    CharTermAttribute
+}
+
+/* Create a new Normalizer2Filter that combines NFKC normalization, Case Folding, and removes Default Ignorables (NFKC_Casefold) */
+func NewICUNormalizer2Filter(in TokenStream) *ICUNormalizer2Filter {
+	return NewICUNormalizer2FilterWith(in, NFKC_CASEFOLD)
+}
+
+/* Create a new Normalizer2Filter with the specified Normalizer2 */
+func NewICUNormalizer2FilterWith(in TokenStream, normalizer Normalizer2) *ICUNormalizer2Filter {
+	ans := &ICUNormalizer2Filter{
+		TokenFilter: NewTokenFilter(in),
+		input:       in,
+		normalizer:  normalizer,
+	}
+	ans.termAtt = ans.Attributes().Add("CharTermAttribute").(CharTermAttribute)
+	return ans
+}
+
+func (f *ICUNormalizer2Filter) IncrementToken() (bool, error) {
+	ok, err := f.input.IncrementToken()
+	if err != nil || !ok {
+		return false, err
+	}
+	buffer := f.termAtt.Buffer()[:f.termAtt.Length()]
+	if !f.normalizer.IsNormalized(buffer) {
+		f.termAtt.CopyBuffer(f.normalizer.Normalize(buffer))
+	}
+	return true, nil
+}
+
+// icu/ICUFoldingFilter.java
+
+/*
+A TokenFilter that applies search term folding to Unicode text,
+applying foldings from UTR#30 Character Foldings.
+
+This filter applies the following foldings from the report to
+unicode text:
+
+	- Accent removal
+	- Case folding
+	- Canonical duplicates folding
+	- Dashes folding
+	- Diacritic removal (including stroke, hook, descender)
+	- Greek letterforms folding
+	- Han Radical folding
+	- Hebrew Alternates folding
+	- Jamo folding
+	- Letterforms folding
+	- Math symbol folding
+	- Multigraph Expansions: All
+	- Native digit folding
+	- No-break folding
+	- Overline folding
+	- Positional forms folding
+	- Small forms folding
+	- Space folding
+	- Spacing Accents folding
+	- Subscript folding
+	- Superscript folding
+	- Suzhou Numeral folding
+	- Symbol folding
+	- Underline folding
+	- Vertical forms folding
+	- Width folding
+
+Additionally, Default Ignorables are removed, and text is normalized
+to NFKC. All foldings, case folding, and normalization mappings are
+applied recursively to ensure a fully folded and normalized result.
+
+NOTE: GoLucene covers the foldings that follow from NFKC_Casefold
+(compatibility, width, case, positional and letterforms), plus
+diacritic removal and native digit folding; stroke and hook removal
+is left to ASCIIFoldingFilter.
+*/
+func NewICUFoldingFilter(in TokenStream) *ICUNormalizer2Filter {
+	return NewICUNormalizer2FilterWith(in, UTR30_FOLDING)
+}
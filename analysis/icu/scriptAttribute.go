@@ -0,0 +1,54 @@
+package icu
+
+import (
+	"github.com/balzaczyy/golucene/core/util"
+)
+
+func init() {
+	util.RegisterAttributeImpl(func() util.AttributeImpl { return newScriptAttributeImpl() })
+}
+
+// icu/tokenattributes/ScriptAttribute.java
+
+/*
+This attribute stores the Unicode script of the token, as the name of
+its table in unicode.Scripts, such as "Latin" or "Han". The default
+value is "Common".
+*/
+type ScriptAttribute interface {
+	util.Attribute
+	// Get the script of the token.
+	Script() string
+	// Set the script of the token.
+	SetScript(string)
+}
+
+// icu/tokenattributes/ScriptAttributeImpl.java
+
+/* Implementation of ScriptAttribute that stores the script name. */
+type ScriptAttributeImpl struct {
+	script string
+}
+
+func newScriptAttributeImpl() *ScriptAttributeImpl {
+	return &ScriptAttributeImpl{script: "Common"}
+}
+
+func (a *ScriptAttributeImpl) Interfaces() []string    { return []string{"ScriptAttribute"} }
+func (a *ScriptAttributeImpl) Script() string          { return a.script }
+func (a *ScriptAttributeImpl) SetScript(script string) { a.script = script }
+func (a *ScriptAttributeImpl) Clear()                  { a.script = "Common" }
+
+func (a *ScriptAttributeImpl) Clone() util.AttributeImpl {
+	return &ScriptAttributeImpl{
+		script: a.script,
+	}
+}
+
+func (a *ScriptAttributeImpl) CopyTo(target util.AttributeImpl) {
+	target.(ScriptAttribute).SetScript(a.script)
+}
+
+func (a *ScriptAttributeImpl) ReflectWith(reflector util.AttributeReflector) {
+	reflector("ScriptAttribute", "script", a.script)
+}
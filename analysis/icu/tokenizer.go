@@ -0,0 +1,230 @@
+package icu
+
+import (
+	"github.com/balzaczyy/golucene/analysis/standard"
+	. "github.com/balzaczyy/golucene/core/analysis"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"github.com/balzaczyy/golucene/core/util"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// icu/segmentation/ICUTokenizer.java
+
+/*
+Breaks text into words according to UAX #29: Unicode Text
+Segmentation (http://www.unicode.org/reports/tr29/)
+
+Words are broken across script boundaries, then segmented according
+to the BreakIterator and typing provided by the standard tokenizer.
+Each token is marked with its script in a ScriptAttribute.
+
+Unlike ICU, GoLucene has no dictionary based segmentation: Southeast
+Asian scripts such as Thai come out as whole runs, and Han text one
+ideograph per token.
+*/
+type ICUTokenizer struct {
+	*Tokenizer
+
+	// the whole input, read on the first token
+	text []rune
+	read bool
+	// script runs of the input still to be segmented
+	runs []scriptRun
+	// the run being segmented, and its tokenizer
+	run       scriptRun
+	breaker   *standard.StandardTokenizer
+	breaking  bool
+	brTermAtt CharTermAttribute
+	brOffAtt  OffsetAttribute
+	brPosAtt  PositionIncrementAttribute
+	brTypeAtt TypeAttribute
+
+	termAtt    CharTermAttribute
+	offsetAtt  OffsetAttribute
+	posIncrAtt PositionIncrementAttribute
+	typeAtt    TypeAttribute
+	scriptAtt  ScriptAttribute
+}
+
+type scriptRun struct {
+	start, end int
+	script     string
+}
+
+/* Construct a new ICUTokenizer that breaks text into words from the given Reader. */
+func NewICUTokenizer(input io.RuneReader) *ICUTokenizer {
+	ans := &ICUTokenizer{
+		Tokenizer: NewTokenizer(input),
+		breaker:   standard.NewStandardTokenizer(util.VERSION_LATEST, strings.NewReader("")),
+	}
+	ans.brTermAtt = ans.breaker.Attributes().Add("CharTermAttribute").(CharTermAttribute)
+	ans.brOffAtt = ans.breaker.Attributes().Add("OffsetAttribute").(OffsetAttribute)
+	ans.brPosAtt = ans.breaker.Attributes().Add("PositionIncrementAttribute").(PositionIncrementAttribute)
+	ans.brTypeAtt = ans.breaker.Attributes().Add("TypeAttribute").(TypeAttribute)
+
+	ans.termAtt = ans.Attributes().Add("CharTermAttribute").(CharTermAttribute)
+	ans.offsetAtt = ans.Attributes().Add("OffsetAttribute").(OffsetAttribute)
+	ans.posIncrAtt = ans.Attributes().Add("PositionIncrementAttribute").(PositionIncrementAttribute)
+	ans.typeAtt = ans.Attributes().Add("TypeAttribute").(TypeAttribute)
+	ans.scriptAtt = ans.Attributes().Add("ScriptAttribute").(ScriptAttribute)
+	return ans
+}
+
+func (t *ICUTokenizer) IncrementToken() (bool, error) {
+	t.Attributes().Clear()
+	if !t.read {
+		if err := t.readInput(); err != nil {
+			return false, err
+		}
+	}
+
+	for {
+		if !t.breaking {
+			if len(t.runs) == 0 {
+				return false, nil
+			}
+			if err := t.nextRun(); err != nil {
+				return false, err
+			}
+		}
+
+		ok, err := t.breaker.IncrementToken()
+		if err != nil {
+			return false, err
+		}
+		if !ok {
+			if err := t.endRun(); err != nil {
+				return false, err
+			}
+			continue
+		}
+
+		t.termAtt.CopyBuffer(t.brTermAtt.Buffer()[:t.brTermAtt.Length()])
+		t.offsetAtt.SetOffset(t.CorrectOffset(t.run.start+t.brOffAtt.StartOffset()),
+			t.CorrectOffset(t.run.start+t.brOffAtt.EndOffset()))
+		t.posIncrAtt.SetPositionIncrement(t.brPosAtt.PositionIncrement())
+		t.typeAtt.SetType(t.brTypeAtt.Type())
+		t.scriptAtt.SetScript(t.run.script)
+		return true, nil
+	}
+}
+
+func (t *ICUTokenizer) readInput() error {
+	for {
+		ch, _, err := t.Input.ReadRune()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		t.text = append(t.text, ch)
+	}
+	t.runs = scriptRuns(t.text)
+	t.read = true
+	return nil
+}
+
+/* Starts segmenting the next script run. */
+func (t *ICUTokenizer) nextRun() error {
+	t.run, t.runs = t.runs[0], t.runs[1:]
+	text := string(t.text[t.run.start:t.run.end])
+	if err := t.breaker.SetReader(strings.NewReader(text)); err != nil {
+		return err
+	}
+	if err := t.breaker.Reset(); err != nil {
+		return err
+	}
+	t.breaking = true
+	return nil
+}
+
+func (t *ICUTokenizer) endRun() error {
+	t.breaking = false
+	if err := t.breaker.End(); err != nil {
+		return err
+	}
+	return t.breaker.Close()
+}
+
+func (t *ICUTokenizer) End() error {
+	if err := t.Tokenizer.End(); err != nil {
+		return err
+	}
+	// set final offset
+	finalOffset := t.CorrectOffset(len(t.text))
+	t.offsetAtt.SetOffset(finalOffset, finalOffset)
+	return nil
+}
+
+func (t *ICUTokenizer) Close() error {
+	if t.breaking {
+		if err := t.endRun(); err != nil {
+			return err
+		}
+	}
+	return t.Tokenizer.Close()
+}
+
+func (t *ICUTokenizer) Reset() error {
+	if err := t.Tokenizer.Reset(); err != nil {
+		return err
+	}
+	if t.breaking {
+		if err := t.endRun(); err != nil {
+			return err
+		}
+	}
+	t.text, t.read = nil, false
+	t.runs = nil
+	return nil
+}
+
+// icu/segmentation/ScriptIterator.java
+
+/*
+Splits text into runs of the same script. Characters of the Common and
+Inherited scripts, such as punctuation, digits and combining marks,
+join the run before them, or the first run if they lead the text.
+*/
+func scriptRuns(text []rune) []scriptRun {
+	var runs []scriptRun
+	start, script := 0, ""
+	for i, ch := range text {
+		s := scriptOf(ch)
+		if s == "Common" || s == "Inherited" || s == script {
+			continue
+		}
+		if script != "" {
+			runs = append(runs, scriptRun{start, i, script})
+			start = i
+		}
+		script = s
+	}
+	if len(text) > 0 {
+		if script == "" {
+			script = "Common"
+		}
+		runs = append(runs, scriptRun{start, len(text), script})
+	}
+	return runs
+}
+
+var frequentScripts = []string{"Latin", "Common", "Inherited", "Han",
+	"Cyrillic", "Greek", "Arabic", "Hebrew", "Hiragana", "Katakana", "Hangul"}
+
+/* Returns the name of the script of ch in unicode.Scripts. */
+func scriptOf(ch rune) string {
+	for _, name := range frequentScripts {
+		if unicode.Is(unicode.Scripts[name], ch) {
+			return name
+		}
+	}
+	for name, table := range unicode.Scripts {
+		if unicode.Is(table, ch) {
+			return name
+		}
+	}
+	return "Common"
+}
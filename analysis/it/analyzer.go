@@ -0,0 +1,107 @@
+package it
+
+import (
+	. "github.com/balzaczyy/golucene/analysis/core"
+	. "github.com/balzaczyy/golucene/analysis/miscellaneous"
+	"github.com/balzaczyy/golucene/analysis/snowball"
+	std "github.com/balzaczyy/golucene/analysis/standard"
+	. "github.com/balzaczyy/golucene/analysis/util"
+	. "github.com/balzaczyy/golucene/core/analysis"
+	"io"
+)
+
+// it/ItalianAnalyzer.java
+
+/* The default set of stopwords used by ItalianAnalyzer, from the Snowball italian stop list. */
+var DEFAULT_STOPWORD_SET = map[string]bool{
+	"ad": true, "al": true, "allo": true, "ai": true, "agli": true,
+	"all": true, "agl": true, "alla": true, "alle": true, "con": true,
+	"col": true, "coi": true, "da": true, "dal": true, "dallo": true,
+	"dai": true, "dagli": true, "dall": true, "dagl": true, "dalla": true,
+	"dalle": true, "di": true, "del": true, "dello": true, "dei": true,
+	"degli": true, "dell": true, "degl": true, "della": true,
+	"delle": true, "in": true, "nel": true, "nello": true, "nei": true,
+	"negli": true, "nell": true, "negl": true, "nella": true,
+	"nelle": true, "su": true, "sul": true, "sullo": true, "sui": true,
+	"sugli": true, "sull": true, "sugl": true, "sulla": true,
+	"sulle": true, "per": true, "tra": true, "contro": true, "io": true,
+	"tu": true, "lui": true, "lei": true, "noi": true, "voi": true,
+	"loro": true, "mio": true, "mia": true, "miei": true, "mie": true,
+	"tuo": true, "tua": true, "tuoi": true, "tue": true, "suo": true,
+	"sua": true, "suoi": true, "sue": true, "nostro": true,
+	"nostra": true, "nostri": true, "nostre": true, "vostro": true,
+	"vostra": true, "vostri": true, "vostre": true, "mi": true,
+	"ti": true, "ci": true, "vi": true, "lo": true, "la": true,
+	"li": true, "le": true, "gli": true, "ne": true, "il": true,
+	"un": true, "uno": true, "una": true, "ma": true, "ed": true,
+	"se": true, "perché": true, "anche": true, "come": true, "dov": true,
+	"dove": true, "che": true, "chi": true, "cui": true, "non": true,
+	"più": true, "quale": true, "quanto": true, "quanti": true,
+	"quanta": true, "quante": true, "quello": true, "quelli": true,
+	"quella": true, "quelle": true, "questo": true, "questi": true,
+	"questa": true, "queste": true, "si": true, "tutto": true,
+	"tutti": true, "a": true, "c": true, "e": true, "i": true, "l": true,
+	"o": true, "ho": true, "hai": true, "ha": true, "abbiamo": true,
+	"avete": true, "hanno": true, "abbia": true, "abbiate": true,
+	"abbiano": true, "avevo": true, "avevi": true, "aveva": true,
+	"avevamo": true, "avevate": true, "avevano": true, "ebbi": true,
+	"avesti": true, "ebbe": true, "avemmo": true, "aveste": true,
+	"ebbero": true, "sono": true, "sei": true, "è": true, "siamo": true,
+	"siete": true, "sia": true, "siate": true, "siano": true,
+	"sarò": true, "sarai": true, "sarà": true, "saremo": true,
+	"sarete": true, "saranno": true, "ero": true, "eri": true,
+	"era": true, "eravamo": true, "eravate": true, "erano": true,
+	"fui": true, "fosti": true, "fu": true, "fummo": true, "foste": true,
+	"furono": true, "stato": true, "stata": true, "stati": true,
+	"state": true,
+}
+
+/*
+Analyzer for Italian.
+
+Builds an analysis chain made of StandardTokenizer, StandardFilter,
+LowerCaseFilter, StopFilter, SetKeywordMarkerFilter if a stem
+exclusion set is provided, and SnowballFilter with the italian stemmer.
+*/
+type ItalianAnalyzer struct {
+	*StopwordAnalyzerBase
+	stemExclusionSet map[string]bool
+}
+
+/* Builds an analyzer with the default stop words: DEFAULT_STOPWORD_SET. */
+func NewItalianAnalyzer() *ItalianAnalyzer {
+	return NewItalianAnalyzerWithStopWords(DEFAULT_STOPWORD_SET)
+}
+
+/* Builds an analyzer with the given stop words. */
+func NewItalianAnalyzerWithStopWords(stopwords map[string]bool) *ItalianAnalyzer {
+	return NewItalianAnalyzerWithStemExclusions(stopwords, nil)
+}
+
+/*
+Builds an analyzer with the given stop words. If a non-empty stem
+exclusion set is provided this analyzer will add a
+SetKeywordMarkerFilter before stemming.
+*/
+func NewItalianAnalyzerWithStemExclusions(stopwords, stemExclusionSet map[string]bool) *ItalianAnalyzer {
+	ans := &ItalianAnalyzer{stemExclusionSet: make(map[string]bool)}
+	for k, v := range stemExclusionSet {
+		ans.stemExclusionSet[k] = v
+	}
+	ans.StopwordAnalyzerBase = NewStopwordAnalyzerBaseWithStopWords(stopwords)
+	ans.Spi = ans
+	return ans
+}
+
+func (a *ItalianAnalyzer) CreateComponents(fieldName string, reader io.RuneReader) *TokenStreamComponents {
+	version := a.Version()
+	source := std.NewStandardTokenizer(version, reader)
+	var result TokenStream = std.NewStandardFilter(version, source)
+	result = NewLowerCaseFilter(version, result)
+	result = NewStopFilter(version, result, a.StopwordSet())
+	if len(a.stemExclusionSet) > 0 {
+		result = NewSetKeywordMarkerFilter(result, a.stemExclusionSet)
+	}
+	result = snowball.NewSnowballFilter(result, snowball.ItalianStemmer{})
+	return NewTokenStreamComponents(source, result)
+}
@@ -0,0 +1,85 @@
+package ja
+
+import (
+	"github.com/balzaczyy/golucene/analysis/cjk"
+	. "github.com/balzaczyy/golucene/analysis/core"
+	. "github.com/balzaczyy/golucene/analysis/util"
+	. "github.com/balzaczyy/golucene/core/analysis"
+	"io"
+	"strings"
+)
+
+// ja/JapaneseAnalyzer.java
+
+/* The default set of stopwords used by JapaneseAnalyzer. */
+var DEFAULT_STOP_SET = wordSet(`
+	の に は を た が で て と し れ さ ある いる も する から な こと として い や
+	れる など なっ ない この ため その あっ よう また もの という あり まで られ
+	なる へ か だ これ によって により おり より による ず なり られる において
+	ば なかっ なく しかし について せ だっ その後 できる それ う ので なお のみ
+	でき き つ における および いう さらに でも ら たり その他 に関する たち ます
+	ん なら に対して 特に せる 及び これら とき では にて ほか ながら うち そして
+	とともに ただし かつて それぞれ または お ほど ものの に対する ほとんど
+	と共に といった です とも ところ ここ`)
+
+/* The default set of part-of-speech tags removed by JapaneseAnalyzer. */
+var DEFAULT_STOP_TAGS = wordSet(`
+	接続詞 助詞 助詞-格助詞 助詞-格助詞-一般 助詞-格助詞-引用 助詞-格助詞-連語
+	助詞-接続助詞 助詞-係助詞 助詞-副助詞 助詞-間投助詞 助詞-並立助詞 助詞-終助詞
+	助詞-副助詞／並立助詞／終助詞 助詞-連体化 助詞-副詞化 助詞-特殊 助動詞 記号
+	記号-一般 記号-読点 記号-句点 記号-空白 記号-括弧開 記号-括弧閉 その他-間投
+	フィラー 非言語音`)
+
+func wordSet(words string) map[string]bool {
+	ans := make(map[string]bool)
+	for _, word := range strings.Fields(words) {
+		ans[word] = true
+	}
+	return ans
+}
+
+/*
+Analyzer for Japanese that uses morphological analysis.
+
+Builds an analysis chain made of JapaneseTokenizer,
+JapaneseBaseFormFilter, JapanesePartOfSpeechStopFilter,
+CJKWidthFilter, StopFilter, JapaneseKatakanaStemFilter and
+LowerCaseFilter.
+*/
+type JapaneseAnalyzer struct {
+	*StopwordAnalyzerBase
+	mode           Mode
+	stopTags       map[string]bool
+	userDictionary *UserDictionary
+}
+
+/* Builds an analyzer in SEARCH mode with the default stop words and tags. */
+func NewJapaneseAnalyzer() *JapaneseAnalyzer {
+	return NewJapaneseAnalyzerWith(nil, DEFAULT_MODE, DEFAULT_STOP_SET, DEFAULT_STOP_TAGS)
+}
+
+/* Builds an analyzer with the given user dictionary (may be nil), mode, stop words and stop tags. */
+func NewJapaneseAnalyzerWith(userDictionary *UserDictionary, mode Mode,
+	stopwords, stopTags map[string]bool) *JapaneseAnalyzer {
+
+	ans := &JapaneseAnalyzer{
+		StopwordAnalyzerBase: NewStopwordAnalyzerBaseWithStopWords(stopwords),
+		mode:                 mode,
+		stopTags:             stopTags,
+		userDictionary:       userDictionary,
+	}
+	ans.Spi = ans
+	return ans
+}
+
+func (a *JapaneseAnalyzer) CreateComponents(fieldName string, reader io.RuneReader) *TokenStreamComponents {
+	version := a.Version()
+	tokenizer := NewJapaneseTokenizer(reader, a.userDictionary, true, a.mode)
+	var stream TokenStream = NewJapaneseBaseFormFilter(tokenizer)
+	stream = NewJapanesePartOfSpeechStopFilter(version, stream, a.stopTags)
+	stream = cjk.NewCJKWidthFilter(stream)
+	stream = NewStopFilter(version, stream, a.StopwordSet())
+	stream = NewJapaneseKatakanaStemFilter(stream)
+	stream = NewLowerCaseFilter(version, stream)
+	return NewTokenStreamComponents(tokenizer, stream)
+}
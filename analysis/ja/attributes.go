@@ -0,0 +1,132 @@
+package ja
+
+import (
+	"github.com/balzaczyy/golucene/core/util"
+)
+
+func init() {
+	util.RegisterAttributeImpl(func() util.AttributeImpl { return newMorphologyAttributeImpl() })
+}
+
+// ja/tokenattributes/PartOfSpeechAttribute.java
+
+/* Attribute for Token.PartOfSpeech(). */
+type PartOfSpeechAttribute interface {
+	util.Attribute
+	// Returns the part of speech, such as "名詞-一般", or "" if unknown.
+	PartOfSpeech() string
+	SetPartOfSpeech(string)
+}
+
+// ja/tokenattributes/BaseFormAttribute.java
+
+/*
+Attribute for Token.BaseForm().
+
+Note: depending on part of speech, this value may not be applicable,
+and will be "".
+*/
+type BaseFormAttribute interface {
+	util.Attribute
+	// Returns the base form of an inflected word, or "" if the word is
+	// not inflected.
+	BaseForm() string
+	SetBaseForm(string)
+}
+
+// ja/tokenattributes/ReadingAttribute.java
+
+/*
+Attribute for Kuromoji reading data.
+
+Note: in some cases this value may not be applicable, and will be "".
+*/
+type ReadingAttribute interface {
+	util.Attribute
+	// Returns the reading of the token in katakana.
+	Reading() string
+	// Returns the pronunciation of the token in katakana.
+	Pronunciation() string
+	SetReading(reading, pronunciation string)
+}
+
+// ja/tokenattributes/InflectionAttribute.java
+
+/* Attribute for Kuromoji inflection data. */
+type InflectionAttribute interface {
+	util.Attribute
+	// Returns the inflection type, such as "五段・カ行イ音便".
+	InflectionType() string
+	// Returns the inflection form, such as "連用形".
+	InflectionForm() string
+	SetInflection(inflectionType, inflectionForm string)
+}
+
+/*
+Implementation of the Japanese attributes: holds the morphological
+data of the current token, as set by JapaneseTokenizer.
+*/
+type MorphologyAttributeImpl struct {
+	partOfSpeech   string
+	baseForm       string
+	reading        string
+	pronunciation  string
+	inflectionType string
+	inflectionForm string
+}
+
+func newMorphologyAttributeImpl() *MorphologyAttributeImpl {
+	return new(MorphologyAttributeImpl)
+}
+
+func (a *MorphologyAttributeImpl) Interfaces() []string {
+	return []string{"PartOfSpeechAttribute", "BaseFormAttribute",
+		"ReadingAttribute", "InflectionAttribute"}
+}
+
+func (a *MorphologyAttributeImpl) PartOfSpeech() string       { return a.partOfSpeech }
+func (a *MorphologyAttributeImpl) SetPartOfSpeech(pos string) { a.partOfSpeech = pos }
+func (a *MorphologyAttributeImpl) BaseForm() string           { return a.baseForm }
+func (a *MorphologyAttributeImpl) SetBaseForm(form string)    { a.baseForm = form }
+func (a *MorphologyAttributeImpl) Reading() string            { return a.reading }
+func (a *MorphologyAttributeImpl) Pronunciation() string      { return a.pronunciation }
+func (a *MorphologyAttributeImpl) InflectionType() string     { return a.inflectionType }
+func (a *MorphologyAttributeImpl) InflectionForm() string     { return a.inflectionForm }
+
+func (a *MorphologyAttributeImpl) SetReading(reading, pronunciation string) {
+	a.reading, a.pronunciation = reading, pronunciation
+}
+
+func (a *MorphologyAttributeImpl) SetInflection(inflectionType, inflectionForm string) {
+	a.inflectionType, a.inflectionForm = inflectionType, inflectionForm
+}
+
+func (a *MorphologyAttributeImpl) Clear() {
+	*a = MorphologyAttributeImpl{}
+}
+
+func (a *MorphologyAttributeImpl) Clone() util.AttributeImpl {
+	ans := *a
+	return &ans
+}
+
+func (a *MorphologyAttributeImpl) CopyTo(target util.AttributeImpl) {
+	*(target.(*MorphologyAttributeImpl)) = *a
+}
+
+func (a *MorphologyAttributeImpl) ReflectWith(reflector util.AttributeReflector) {
+	reflector("PartOfSpeechAttribute", "partOfSpeech", a.partOfSpeech)
+	reflector("BaseFormAttribute", "baseForm", a.baseForm)
+	reflector("ReadingAttribute", "reading", a.reading)
+	reflector("ReadingAttribute", "pronunciation", a.pronunciation)
+	reflector("InflectionAttribute", "inflectionType", a.inflectionType)
+	reflector("InflectionAttribute", "inflectionForm", a.inflectionForm)
+}
+
+/*
+Adds the Japanese attributes to the attribute source if missing, so
+that filters work on any stream, and returns them.
+*/
+func morphologyAttribute(as *util.AttributeSource) *MorphologyAttributeImpl {
+	return as.Add("PartOfSpeechAttribute").(*MorphologyAttributeImpl)
+}
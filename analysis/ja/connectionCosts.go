@@ -0,0 +1,114 @@
+package ja
+
+import (
+	"strings"
+)
+
+// ja/dict/ConnectionCosts.java
+
+const (
+	// cost of a likely connection
+	connectionLikely = -500
+	// cost of a connection the grammar doesn't allow
+	connectionIllegal = 10000
+)
+
+/*
+Returns the cost of connecting two adjacent morphemes; left is nil at
+the beginning of the text, and right is nil at its end.
+
+Instead of the connection matrix of a full dictionary, the costs come
+from a few grammar rules: inflected forms that need a continuation,
+and the forms that auxiliary verbs and conjunctive particles attach
+to.
+*/
+func connectionCost(left, right *morpheme) int {
+	if left == nil {
+		if right != nil && (right.posGroup() == "助詞" || right.posGroup() == "助動詞") {
+			return connectionIllegal / 2
+		}
+		return 0
+	}
+	if right == nil {
+		if needsContinuation(left) {
+			return connectionIllegal
+		}
+		return 0
+	}
+
+	leftGroup, rightGroup := left.posGroup(), right.posGroup()
+	switch {
+	case rightGroup == "助動詞":
+		if attachesTo(right, left) {
+			return connectionLikely
+		}
+		return connectionIllegal
+	case right.partOfSpeech == "助詞-接続助詞" && (right.surface == "て" || right.surface == "で"):
+		switch left.inflectionForm {
+		case "連用形", "連用タ接続", "連用テ接続":
+			if right.surface == "で" && left.inflectionType != "" && !strings.HasPrefix(left.inflectionType, "五段") {
+				return connectionIllegal
+			}
+			return connectionLikely
+		}
+		return connectionIllegal
+	case right.partOfSpeech == "助詞-接続助詞" && right.surface == "ば":
+		if left.inflectionForm == "仮定形" {
+			return connectionLikely
+		}
+		return connectionIllegal
+	case needsContinuation(left):
+		return connectionIllegal
+	case leftGroup == "接頭詞" && rightGroup != "名詞":
+		return connectionIllegal
+	case strings.Contains(right.partOfSpeech, "接尾") && leftGroup != "名詞":
+		return connectionIllegal
+	case rightGroup == "名詞" && strings.Contains(right.partOfSpeech, "非自立") && leftGroup == "名詞":
+		return connectionIllegal / 2
+	case leftGroup == "名詞" && rightGroup == "名詞":
+		// prefer the compounds of the dictionary
+		return 500
+	case leftGroup == "助詞" && rightGroup == "助詞":
+		return 1000
+	case (leftGroup == "動詞" || leftGroup == "形容詞") && left.inflectionForm == "連用形" && rightGroup == "名詞":
+		return 1000
+	}
+	return 0
+}
+
+/* Returns true if the inflected form of m can't end a phrase. */
+func needsContinuation(m *morpheme) bool {
+	switch m.inflectionForm {
+	case "未然形", "未然ウ接続", "連用タ接続", "仮定形", "ガル接続", "体言接続特殊":
+		return true
+	}
+	return false
+}
+
+/* Returns true if the auxiliary verb aux can follow the morpheme left. */
+func attachesTo(aux, left *morpheme) bool {
+	leftGroup := left.posGroup()
+	inflectable := leftGroup == "動詞" || leftGroup == "形容詞" || leftGroup == "助動詞"
+	switch aux.inflectionType {
+	case "特殊・タ":
+		return inflectable && (left.inflectionForm == "連用タ接続" ||
+			left.inflectionForm == "連用形" && !strings.HasPrefix(left.inflectionType, "五段"))
+	case "特殊・ダ", "特殊・デス":
+		return leftGroup == "名詞" || leftGroup == "助詞" ||
+			aux.inflectionType == "特殊・デス" && inflectable && left.inflectionForm == "基本形"
+	case "特殊・マス", "特殊・タイ":
+		return (leftGroup == "動詞" || left.inflectionType == "一段" && leftGroup == "助動詞") &&
+			left.inflectionForm == "連用形"
+	case "特殊・ナイ", "特殊・ヌ", "一段":
+		return inflectable && left.inflectionForm == "未然形"
+	case "不変化型":
+		switch aux.baseForm {
+		case "う":
+			return inflectable && left.inflectionForm == "未然ウ接続"
+		case "よう":
+			return inflectable && left.inflectionForm == "未然形" && !strings.HasPrefix(left.inflectionType, "五段")
+		}
+		return inflectable && (left.inflectionForm == "基本形" || left.inflectionForm == "未然形")
+	}
+	return true
+}
@@ -0,0 +1,262 @@
+package ja
+
+// ja/dict/TokenInfoDictionary.java
+
+/*
+The uninflected words of the system dictionary, in the IPADIC part of
+speech scheme: surface,part of speech,reading[,pronunciation]
+
+This is a small dictionary of frequent words, enough for the
+segmenter to handle common text; use a UserDictionary for domain
+vocabulary.
+*/
+const systemWords = `
+# particles
+が,助詞-格助詞-一般,ガ
+を,助詞-格助詞-一般,ヲ
+に,助詞-格助詞-一般,ニ
+へ,助詞-格助詞-一般,ヘ
+と,助詞-格助詞-一般,ト
+で,助詞-格助詞-一般,デ
+から,助詞-格助詞-一般,カラ
+より,助詞-格助詞-一般,ヨリ
+まで,助詞-副助詞,マデ
+の,助詞-連体化,ノ
+は,助詞-係助詞,ハ
+も,助詞-係助詞,モ
+こそ,助詞-係助詞,コソ
+しか,助詞-係助詞,シカ
+でも,助詞-副助詞,デモ
+さえ,助詞-副助詞,サエ
+だけ,助詞-副助詞,ダケ
+ばかり,助詞-副助詞,バカリ
+ほど,助詞-副助詞,ホド
+くらい,助詞-副助詞,クライ
+など,助詞-副助詞,ナド
+って,助詞-格助詞-連語,ッテ
+と,助詞-格助詞-引用,ト
+や,助詞-並立助詞,ヤ
+とか,助詞-並立助詞,トカ
+て,助詞-接続助詞,テ
+で,助詞-接続助詞,デ
+ば,助詞-接続助詞,バ
+が,助詞-接続助詞,ガ
+けど,助詞-接続助詞,ケド
+けれど,助詞-接続助詞,ケレド
+ので,助詞-接続助詞,ノデ
+のに,助詞-接続助詞,ノニ
+ながら,助詞-接続助詞,ナガラ
+し,助詞-接続助詞,シ
+か,助詞-副助詞／並立助詞／終助詞,カ
+ね,助詞-終助詞,ネ
+よ,助詞-終助詞,ヨ
+な,助詞-終助詞,ナ
+わ,助詞-終助詞,ワ
+ぞ,助詞-終助詞,ゾ
+# symbols
+。,記号-句点,。
+．,記号-句点,．
+、,記号-読点,、
+，,記号-読点,，
+・,記号-一般,・
+「,記号-括弧開,「
+」,記号-括弧閉,」
+『,記号-括弧開,『
+』,記号-括弧閉,』
+（,記号-括弧開,（
+）,記号-括弧閉,）
+！,記号-一般,！
+？,記号-一般,？
+…,記号-一般,…
+# pronouns
+私,名詞-代名詞-一般,ワタシ
+僕,名詞-代名詞-一般,ボク
+彼,名詞-代名詞-一般,カレ
+彼女,名詞-代名詞-一般,カノジョ
+これ,名詞-代名詞-一般,コレ
+それ,名詞-代名詞-一般,ソレ
+あれ,名詞-代名詞-一般,アレ
+どれ,名詞-代名詞-一般,ドレ
+ここ,名詞-代名詞-一般,ココ
+そこ,名詞-代名詞-一般,ソコ
+どこ,名詞-代名詞-一般,ドコ
+誰,名詞-代名詞-一般,ダレ
+何,名詞-代名詞-一般,ナニ
+# nouns
+多く,名詞-副詞可能,オオク
+今日,名詞-副詞可能,キョウ,キョー
+明日,名詞-副詞可能,アシタ
+昨日,名詞-副詞可能,キノウ,キノー
+今,名詞-副詞可能,イマ
+時,名詞-非自立-副詞可能,トキ
+こと,名詞-非自立-一般,コト
+もの,名詞-非自立-一般,モノ
+ため,名詞-非自立-副詞可能,タメ
+よう,名詞-非自立-助動詞語幹,ヨウ,ヨー
+人,名詞-一般,ヒト
+方,名詞-非自立-一般,ホウ,ホー
+学生,名詞-一般,ガクセイ,ガクセー
+先生,名詞-一般,センセイ,センセー
+学校,名詞-一般,ガッコウ,ガッコー
+大学,名詞-一般,ダイガク
+会社,名詞-一般,カイシャ
+試験,名詞-サ変接続,シケン
+勉強,名詞-サ変接続,ベンキョウ,ベンキョー
+仕事,名詞-サ変接続,シゴト
+旅行,名詞-サ変接続,リョコウ,リョコー
+検索,名詞-サ変接続,ケンサク
+解析,名詞-サ変接続,カイセキ
+処理,名詞-サ変接続,ショリ
+研究,名詞-サ変接続,ケンキュウ,ケンキュー
+電話,名詞-サ変接続,デンワ
+料理,名詞-サ変接続,リョウリ,リョーリ
+情報,名詞-一般,ジョウホウ,ジョーホー
+形態素,名詞-一般,ケイタイソ,ケータイソ
+言語,名詞-一般,ゲンゴ
+日本語,名詞-一般,ニホンゴ
+英語,名詞-一般,エイゴ,エーゴ
+文字,名詞-一般,モジ
+辞書,名詞-一般,ジショ
+本,名詞-一般,ホン
+車,名詞-一般,クルマ
+電車,名詞-一般,デンシャ
+駅,名詞-一般,エキ
+空港,名詞-一般,クウコウ,クーコー
+国際,名詞-一般,コクサイ
+天気,名詞-一般,テンキ
+雨,名詞-一般,アメ
+雪,名詞-一般,ユキ
+山,名詞-一般,ヤマ
+川,名詞-一般,カワ
+海,名詞-一般,ウミ
+水,名詞-一般,ミズ
+花,名詞-一般,ハナ
+犬,名詞-一般,イヌ
+猫,名詞-一般,ネコ
+家,名詞-一般,イエ
+部屋,名詞-一般,ヘヤ
+時間,名詞-副詞可能,ジカン
+年,名詞-一般,トシ
+友達,名詞-一般,トモダチ
+子供,名詞-一般,コドモ
+名前,名詞-一般,ナマエ
+世界,名詞-一般,セカイ
+問題,名詞-一般,モンダイ
+話,名詞-一般,ハナシ
+朝,名詞-副詞可能,アサ
+夜,名詞-副詞可能,ヨル
+お茶,名詞-一般,オチャ
+ご飯,名詞-一般,ゴハン
+日本,名詞-固有名詞-地域-国,ニッポン
+東京,名詞-固有名詞-地域-一般,トウキョウ,トーキョー
+京都,名詞-固有名詞-地域-一般,キョウト,キョート
+大阪,名詞-固有名詞-地域-一般,オオサカ,オーサカ
+関西,名詞-固有名詞-地域-一般,カンサイ
+関西国際空港,名詞-固有名詞-組織,カンサイコクサイクウコウ,カンサイコクサイクーコー
+都,名詞-接尾-地域,ト
+さん,名詞-接尾-人名,サン
+様,名詞-接尾-人名,サマ
+的,名詞-接尾-形容動詞語幹,テキ
+者,名詞-接尾-一般,シャ
+# others
+お,接頭詞-名詞接続,オ
+ご,接頭詞-名詞接続,ゴ
+とても,副詞-助詞類接続,トテモ
+すぐ,副詞-一般,スグ
+もう,副詞-一般,モウ,モー
+まだ,副詞-助詞類接続,マダ
+よく,副詞-一般,ヨク
+また,接続詞,マタ
+しかし,接続詞,シカシ
+そして,接続詞,ソシテ
+でも,接続詞,デモ
+この,連体詞,コノ
+その,連体詞,ソノ
+あの,連体詞,アノ
+どの,連体詞,ドノ
+はい,感動詞,ハイ
+いいえ,感動詞,イイエ
+`
+
+/*
+The inflected words of the system dictionary, from which all their
+forms are generated: base form,part of speech,reading,inflection type
+*/
+const systemInflectedWords = `
+# auxiliary verbs
+た,助動詞,タ,特殊・タ
+だ,助動詞,ダ,特殊・タ
+だ,助動詞,ダ,特殊・ダ
+です,助動詞,デス,特殊・デス
+ます,助動詞,マス,特殊・マス
+ない,助動詞,ナイ,特殊・ナイ
+たい,助動詞,タイ,特殊・タイ
+ぬ,助動詞,ヌ,特殊・ヌ
+れる,助動詞,レル,一段
+られる,助動詞,ラレル,一段
+せる,助動詞,セル,一段
+させる,助動詞,サセル,一段
+う,助動詞,ウ,不変化型
+よう,助動詞,ヨウ,不変化型
+まい,助動詞,マイ,不変化型
+# verbs
+する,動詞-自立,スル,サ変・スル
+来る,動詞-自立,クル,カ変・来ル
+くる,動詞-非自立,クル,カ変・来ル
+いる,動詞-非自立,イル,一段
+いる,動詞-自立,イル,一段
+ある,動詞-自立,アル,五段・ラ行
+なる,動詞-自立,ナル,五段・ラ行
+しまう,動詞-非自立,シマウ,五段・ワ行促音便
+落ちる,動詞-自立,オチル,一段
+食べる,動詞-自立,タベル,一段
+見る,動詞-自立,ミル,一段
+寝る,動詞-自立,ネル,一段
+起きる,動詞-自立,オキル,一段
+出る,動詞-自立,デル,一段
+入れる,動詞-自立,イレル,一段
+考える,動詞-自立,カンガエル,一段
+教える,動詞-自立,オシエル,一段
+始める,動詞-自立,ハジメル,一段
+書く,動詞-自立,カク,五段・カ行イ音便
+聞く,動詞-自立,キク,五段・カ行イ音便
+歩く,動詞-自立,アルク,五段・カ行イ音便
+行く,動詞-自立,イク,五段・カ行促音便
+泳ぐ,動詞-自立,オヨグ,五段・ガ行
+話す,動詞-自立,ハナス,五段・サ行
+出す,動詞-自立,ダス,五段・サ行
+待つ,動詞-自立,マツ,五段・タ行
+持つ,動詞-自立,モツ,五段・タ行
+立つ,動詞-自立,タツ,五段・タ行
+死ぬ,動詞-自立,シヌ,五段・ナ行
+遊ぶ,動詞-自立,アソブ,五段・バ行
+飲む,動詞-自立,ノム,五段・マ行
+読む,動詞-自立,ヨム,五段・マ行
+住む,動詞-自立,スム,五段・マ行
+帰る,動詞-自立,カエル,五段・ラ行
+作る,動詞-自立,ツクル,五段・ラ行
+取る,動詞-自立,トル,五段・ラ行
+分かる,動詞-自立,ワカル,五段・ラ行
+降る,動詞-自立,フル,五段・ラ行
+言う,動詞-自立,イウ,五段・ワ行促音便
+買う,動詞-自立,カウ,五段・ワ行促音便
+使う,動詞-自立,ツカウ,五段・ワ行促音便
+思う,動詞-自立,オモウ,五段・ワ行促音便
+会う,動詞-自立,アウ,五段・ワ行促音便
+# adjectives
+高い,形容詞-自立,タカイ,形容詞・アウオ段
+安い,形容詞-自立,ヤスイ,形容詞・アウオ段
+新しい,形容詞-自立,アタラシイ,形容詞・イ段
+古い,形容詞-自立,フルイ,形容詞・アウオ段
+大きい,形容詞-自立,オオキイ,形容詞・イ段
+小さい,形容詞-自立,チイサイ,形容詞・アウオ段
+良い,形容詞-自立,ヨイ,形容詞・アウオ段
+悪い,形容詞-自立,ワルイ,形容詞・アウオ段
+早い,形容詞-自立,ハヤイ,形容詞・アウオ段
+美しい,形容詞-自立,ウツクシイ,形容詞・イ段
+楽しい,形容詞-自立,タノシイ,形容詞・イ段
+多い,形容詞-自立,オオイ,形容詞・アウオ段
+少ない,形容詞-自立,スクナイ,形容詞・アウオ段
+長い,形容詞-自立,ナガイ,形容詞・アウオ段
+強い,形容詞-自立,ツヨイ,形容詞・アウオ段
+`
@@ -0,0 +1,355 @@
+package ja
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// ja/dict/Dictionary.java
+
+/* A morpheme of a dictionary: a word form with its morphological data. */
+type morpheme struct {
+	surface        string
+	partOfSpeech   string
+	inflectionType string
+	inflectionForm string
+	// the dictionary form of an inflected word, "" if not inflected
+	baseForm      string
+	reading       string
+	pronunciation string
+	// the cost of the word itself; lower is more likely
+	cost int
+	// true for words of the unknown word dictionary
+	unknown bool
+}
+
+/* Returns the first level of the part of speech, e.g. "名詞" for "名詞-一般". */
+func (m *morpheme) posGroup() string {
+	if i := strings.IndexByte(m.partOfSpeech, '-'); i >= 0 {
+		return m.partOfSpeech[:i]
+	}
+	return m.partOfSpeech
+}
+
+/* A dictionary maps surface forms to morphemes. */
+type dictionary struct {
+	words map[string][]*morpheme
+	// the length in runes of the longest surface form
+	maxLength int
+}
+
+func newDictionary() *dictionary {
+	return &dictionary{words: make(map[string][]*morpheme)}
+}
+
+func (d *dictionary) add(m *morpheme) {
+	d.words[m.surface] = append(d.words[m.surface], m)
+	if n := utf8.RuneCountInString(m.surface); n > d.maxLength {
+		d.maxLength = n
+	}
+}
+
+/* Calls fn with every morpheme whose surface form is a prefix of text. */
+func (d *dictionary) lookup(text []rune, fn func(m *morpheme, length int)) {
+	for length := 1; length <= d.maxLength && length <= len(text); length++ {
+		for _, m := range d.words[string(text[:length])] {
+			fn(m, length)
+		}
+	}
+}
+
+// ja/dict/TokenInfoDictionary.java
+
+/* Default word costs, by the first level of the part of speech. */
+var defaultCosts = map[string]int{
+	"名詞":  3000,
+	"動詞":  3500,
+	"形容詞": 3500,
+	"副詞":  3000,
+	"連体詞": 2500,
+	"接続詞": 2500,
+	"感動詞": 3000,
+	"接頭詞": 2500,
+	"助詞":  1000,
+	"助動詞": 1000,
+	"記号":  500,
+}
+
+func wordCost(partOfSpeech string, length int) int {
+	m := &morpheme{partOfSpeech: partOfSpeech}
+	cost, ok := defaultCosts[m.posGroup()]
+	if !ok {
+		cost = 3000
+	}
+	if strings.Contains(partOfSpeech, "接尾") {
+		cost = 2000
+	}
+	// longer words are more likely than their parts
+	return cost - 200*(length-1)
+}
+
+/* The inflected forms of a conjugation type: form, ending and its reading if not the ending itself. */
+type conjugation []struct{ form, ending, reading string }
+
+var godanRows = map[string]string{
+	// base, 未然形, 未然ウ接続, 連用形, 連用タ接続, 仮定形
+	"五段・カ行イ音便": "くかこきいけ",
+	"五段・カ行促音便": "くかこきっけ",
+	"五段・ガ行":    "ぐがごぎいげ",
+	"五段・サ行":    "すさそししせ",
+	"五段・タ行":    "つたとちって",
+	"五段・ナ行":    "ぬなのにんね",
+	"五段・バ行":    "ぶばぼびんべ",
+	"五段・マ行":    "むまもみんめ",
+	"五段・ラ行":    "るらろりっれ",
+	"五段・ワ行促音便": "うわおいっえ",
+}
+
+var conjugations = func() map[string]conjugation {
+	ans := map[string]conjugation{
+		"一段": {
+			{"基本形", "る", ""}, {"未然形", "", ""}, {"未然ウ接続", "よ", ""},
+			{"連用形", "", ""}, {"仮定形", "れ", ""}, {"命令ｒｏ", "ろ", ""},
+			{"命令ｙｏ", "よ", ""}, {"体言接続特殊", "ん", ""},
+		},
+		"サ変・スル": {
+			{"基本形", "する", ""}, {"未然形", "し", ""}, {"未然形", "さ", ""},
+			{"未然形", "せ", ""}, {"未然ウ接続", "しよ", ""}, {"連用形", "し", ""},
+			{"仮定形", "すれ", ""}, {"命令ｒｏ", "しろ", ""}, {"命令ｙｏ", "せよ", ""},
+		},
+		"カ変・来ル": {
+			{"基本形", "る", "クル"}, {"未然形", "", "コ"}, {"未然ウ接続", "よ", "コヨ"},
+			{"連用形", "", "キ"}, {"仮定形", "れ", "クレ"}, {"命令ｉ", "い", "コイ"},
+		},
+		"形容詞・イ段": {
+			{"基本形", "い", ""}, {"連用テ接続", "く", ""}, {"連用タ接続", "かっ", ""},
+			{"仮定形", "けれ", ""}, {"未然ウ接続", "かろ", ""}, {"体言接続", "き", ""},
+			{"ガル接続", "", ""},
+		},
+		"特殊・タ": {
+			{"基本形", "", ""}, {"仮定形", "ら", ""}, {"未然形", "ろ", ""},
+		},
+		"特殊・ダ": {
+			{"基本形", "だ", ""}, {"連用形", "で", ""}, {"連用タ接続", "だっ", ""},
+			{"体言接続", "な", ""}, {"仮定形", "なら", ""}, {"未然形", "だろ", ""},
+		},
+		"特殊・デス": {
+			{"基本形", "す", ""}, {"連用形", "し", ""}, {"未然形", "しょ", ""},
+		},
+		"特殊・マス": {
+			{"基本形", "す", ""}, {"連用形", "し", ""}, {"未然形", "せ", ""},
+			{"未然ウ接続", "しょ", ""}, {"仮定形", "すれ", ""},
+		},
+		"特殊・ヌ": {
+			{"基本形", "ぬ", ""}, {"基本形", "ん", ""}, {"連用形", "ず", ""},
+		},
+		"不変化型": {
+			{"基本形", "", ""},
+		},
+	}
+	ans["特殊・ナイ"] = ans["形容詞・イ段"]
+	ans["特殊・タイ"] = ans["形容詞・イ段"]
+	ans["形容詞・アウオ段"] = ans["形容詞・イ段"]
+	for typ, row := range godanRows {
+		r := []rune(row)
+		ans[typ] = conjugation{
+			{"基本形", string(r[0]), ""}, {"未然形", string(r[1]), ""},
+			{"未然ウ接続", string(r[2]), ""}, {"連用形", string(r[3]), ""},
+			{"連用タ接続", string(r[4]), ""}, {"仮定形", string(r[5]), ""},
+			{"命令ｅ", string(r[5]), ""},
+		}
+	}
+	return ans
+}()
+
+/* Returns the katakana form of hiragana text. */
+func toKatakana(s string) string {
+	return strings.Map(func(ch rune) rune {
+		if ch >= 0x3041 && ch <= 0x3096 {
+			return ch + 0x60
+		}
+		return ch
+	}, s)
+}
+
+/*
+Returns the pronunciation of a reading: the particles は, へ and を
+are pronounced ワ, エ and オ.
+*/
+func pronunciationOf(reading, partOfSpeech string) string {
+	if strings.HasPrefix(partOfSpeech, "助詞") {
+		switch reading {
+		case "ハ":
+			return "ワ"
+		case "ヘ":
+			return "エ"
+		case "ヲ":
+			return "オ"
+		}
+	}
+	return reading
+}
+
+/*
+Parses the system dictionary: lines of uninflected words
+"surface,part of speech,reading[,pronunciation]" and lines of
+inflected words "base form,part of speech,reading,inflection type".
+*/
+func parseDictionary(d *dictionary, words, inflectedWords string) {
+	for _, fields := range csvLines(words) {
+		surface, pos, reading := fields[0], fields[1], fields[2]
+		pronunciation := pronunciationOf(reading, pos)
+		if len(fields) > 3 {
+			pronunciation = fields[3]
+		}
+		d.add(&morpheme{
+			surface:       surface,
+			partOfSpeech:  pos,
+			reading:       reading,
+			pronunciation: pronunciation,
+			cost:          wordCost(pos, utf8.RuneCountInString(surface)),
+		})
+	}
+	for _, fields := range csvLines(inflectedWords) {
+		addInflections(d, fields[0], fields[1], fields[2], fields[3])
+	}
+}
+
+/* Adds all the inflected forms of a word to the dictionary. */
+func addInflections(d *dictionary, baseForm, pos, reading, inflectionType string) {
+	forms, ok := conjugations[inflectionType]
+	assert2(ok, "unknown inflection type %v", inflectionType)
+	base := forms[0]
+	stem := strings.TrimSuffix(baseForm, base.ending)
+	stemReading := reading
+	if base.reading != "" {
+		stemReading = strings.TrimSuffix(reading, base.reading)
+	} else {
+		stemReading = strings.TrimSuffix(reading, toKatakana(base.ending))
+	}
+	for _, f := range forms {
+		surface := stem + f.ending
+		if surface == "" {
+			continue
+		}
+		formReading := f.reading
+		if formReading == "" {
+			formReading = toKatakana(f.ending)
+		}
+		formReading = stemReading + formReading
+		d.add(&morpheme{
+			surface:        surface,
+			partOfSpeech:   pos,
+			inflectionType: inflectionType,
+			inflectionForm: f.form,
+			baseForm:       baseForm,
+			reading:        formReading,
+			pronunciation:  formReading,
+			cost:           wordCost(pos, utf8.RuneCountInString(surface)),
+		})
+	}
+}
+
+/* Splits text into the fields of its non-empty, non-comment lines. */
+func csvLines(text string) [][]string {
+	var ans [][]string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		ans = append(ans, strings.Split(line, ","))
+	}
+	return ans
+}
+
+/* The system dictionary, built from the embedded word lists. */
+var systemDictionary = func() *dictionary {
+	d := newDictionary()
+	parseDictionary(d, systemWords, systemInflectedWords)
+	return d
+}()
+
+// ja/dict/UserDictionary.java
+
+/* A user dictionary entry: a surface form with its own segmentation. */
+type userEntry struct {
+	surface  string
+	segments []*morpheme
+}
+
+/*
+Class for building a User Dictionary. This class allows for custom
+segmentation of phrases.
+
+The dictionary is in CSV format, one entry per line:
+
+	surface,segmentation,readings,part of speech
+
+where segmentation and readings are space separated, e.g.
+
+	関西国際空港,関西 国際 空港,カンサイ コクサイ クウコウ,カスタム名詞
+
+Lines starting with # are comments. User entries take precedence over
+the system dictionary.
+*/
+type UserDictionary struct {
+	entries   map[string]*userEntry
+	maxLength int
+}
+
+/* Parses a user dictionary. */
+func NewUserDictionary(text string) (*UserDictionary, error) {
+	ans := &UserDictionary{entries: make(map[string]*userEntry)}
+	for i, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		fields := strings.Split(line, ",")
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("Invalid user dictionary entry at line %v: expected 4 fields: %v", i+1, line)
+		}
+		surface, pos := fields[0], fields[3]
+		segments, readings := strings.Fields(fields[1]), strings.Fields(fields[2])
+		if len(segments) != len(readings) {
+			return nil, fmt.Errorf("Invalid user dictionary entry at line %v: segmentation and readings differ in length: %v", i+1, line)
+		}
+		if strings.Join(segments, "") != surface {
+			return nil, fmt.Errorf("Invalid user dictionary entry at line %v: segmentation doesn't match the surface form: %v", i+1, line)
+		}
+		entry := &userEntry{surface: surface}
+		for j, segment := range segments {
+			entry.segments = append(entry.segments, &morpheme{
+				surface:       segment,
+				partOfSpeech:  pos,
+				reading:       readings[j],
+				pronunciation: readings[j],
+			})
+		}
+		ans.entries[surface] = entry
+		if n := utf8.RuneCountInString(surface); n > ans.maxLength {
+			ans.maxLength = n
+		}
+	}
+	return ans, nil
+}
+
+/* Returns the longest entry whose surface form is a prefix of text. */
+func (d *UserDictionary) lookup(text []rune) *userEntry {
+	for length := d.maxLength; length > 0; length-- {
+		if length <= len(text) {
+			if e, ok := d.entries[string(text[:length])]; ok {
+				return e
+			}
+		}
+	}
+	return nil
+}
+
+func assert2(ok bool, msg string, args ...interface{}) {
+	if !ok {
+		panic(fmt.Sprintf(msg, args...))
+	}
+}
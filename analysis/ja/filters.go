@@ -0,0 +1,190 @@
+package ja
+
+import (
+	. "github.com/balzaczyy/golucene/analysis/util"
+	. "github.com/balzaczyy/golucene/core/analysis"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"github.com/balzaczyy/golucene/core/util"
+)
+
+// ja/JapanesePartOfSpeechStopFilter.java
+
+/* Removes tokens that match a set of part-of-speech tags. */
+type JapanesePartOfSpeechStopFilter struct {
+	*FilteringTokenFilter
+	stopTags map[string]bool
+	posAtt   PartOfSpeechAttribute
+}
+
+/* Create a new JapanesePartOfSpeechStopFilter. */
+func NewJapanesePartOfSpeechStopFilter(version util.Version, in TokenStream, stopTags map[string]bool) *JapanesePartOfSpeechStopFilter {
+	ans := &JapanesePartOfSpeechStopFilter{stopTags: stopTags}
+	ans.FilteringTokenFilter = NewFilteringTokenFilter(ans, version, in)
+	ans.posAtt = morphologyAttribute(ans.Attributes())
+	return ans
+}
+
+func (f *JapanesePartOfSpeechStopFilter) Accept() bool {
+	pos := f.posAtt.PartOfSpeech()
+	return pos == "" || !f.stopTags[pos]
+}
+
+// ja/JapaneseBaseFormFilter.java
+
+/*
+Replaces term text with the BaseFormAttribute.
+
+This acts as a lemmatizer for verbs and adjectives.
+
+To prevent terms from being stemmed use an instance of
+SetKeywordMarkerFilter or a custom TokenFilter that sets the
+KeywordAttribute before this TokenStream.
+*/
+type JapaneseBaseFormFilter struct {
+	*TokenFilter
+	input        TokenStream
+	termAtt      CharTermAttribute
+	basicFormAtt BaseFormAttribute
+	keywordAtt   KeywordAttribute
+}
+
+func NewJapaneseBaseFormFilter(in TokenStream) *JapaneseBaseFormFilter {
+	ans := &JapaneseBaseFormFilter{
+		TokenFilter: NewTokenFilter(in),
+		input:       in,
+	}
+	ans.termAtt = ans.Attributes().Add("CharTermAttribute").(CharTermAttribute)
+	ans.basicFormAtt = morphologyAttribute(ans.Attributes())
+	ans.keywordAtt = ans.Attributes().Add("KeywordAttribute").(KeywordAttribute)
+	return ans
+}
+
+func (f *JapaneseBaseFormFilter) IncrementToken() (bool, error) {
+	ok, err := f.input.IncrementToken()
+	if err != nil || !ok {
+		return false, err
+	}
+	if !f.keywordAtt.IsKeyword() {
+		if baseForm := f.basicFormAtt.BaseForm(); baseForm != "" {
+			f.termAtt.CopyBuffer([]rune(baseForm))
+		}
+	}
+	return true, nil
+}
+
+// ja/JapaneseReadingFormFilter.java
+
+/*
+A TokenFilter that replaces the term attribute with the reading of a
+token in either katakana or romaji form. The default reading form is
+katakana.
+*/
+type JapaneseReadingFormFilter struct {
+	*TokenFilter
+	input      TokenStream
+	useRomaji  bool
+	termAtt    CharTermAttribute
+	readingAtt ReadingAttribute
+}
+
+func NewJapaneseReadingFormFilter(in TokenStream) *JapaneseReadingFormFilter {
+	return NewJapaneseReadingFormFilterWithRomaji(in, false)
+}
+
+func NewJapaneseReadingFormFilterWithRomaji(in TokenStream, useRomaji bool) *JapaneseReadingFormFilter {
+	ans := &JapaneseReadingFormFilter{
+		TokenFilter: NewTokenFilter(in),
+		input:       in,
+		useRomaji:   useRomaji,
+	}
+	ans.termAtt = ans.Attributes().Add("CharTermAttribute").(CharTermAttribute)
+	ans.readingAtt = morphologyAttribute(ans.Attributes())
+	return ans
+}
+
+func (f *JapaneseReadingFormFilter) IncrementToken() (bool, error) {
+	ok, err := f.input.IncrementToken()
+	if err != nil || !ok {
+		return false, err
+	}
+	reading := f.readingAtt.Reading()
+	if f.useRomaji {
+		if reading == "" {
+			// if its an OOV term, just try the term text
+			reading = string(f.termAtt.Buffer()[:f.termAtt.Length()])
+		}
+		f.termAtt.CopyBuffer([]rune(GetRomanization(reading)))
+	} else if reading != "" {
+		// just replace the term text with the reading, if it exists
+		f.termAtt.CopyBuffer([]rune(reading))
+	}
+	return true, nil
+}
+
+// ja/JapaneseKatakanaStemFilter.java
+
+const DEFAULT_MINIMUM_LENGTH = 4
+
+/*
+A TokenFilter that normalizes common katakana spelling variations
+ending in a long sound character by removing this character (U+30FC).
+Only katakana words longer than a minimum length are stemmed (default
+is four).
+
+Note that only full-width katakana characters are supported. Please
+use a CJKWidthFilter to convert half-width katakana to full-width
+before using this filter.
+
+In order to prevent terms from being stemmed, use an instance of
+SetKeywordMarkerFilter or a custom TokenFilter that sets the
+KeywordAttribute before this TokenStream.
+*/
+type JapaneseKatakanaStemFilter struct {
+	*TokenFilter
+	input         TokenStream
+	minimumLength int
+	termAtt       CharTermAttribute
+	keywordAtt    KeywordAttribute
+}
+
+func NewJapaneseKatakanaStemFilter(in TokenStream) *JapaneseKatakanaStemFilter {
+	return NewJapaneseKatakanaStemFilterWithLength(in, DEFAULT_MINIMUM_LENGTH)
+}
+
+func NewJapaneseKatakanaStemFilterWithLength(in TokenStream, minimumLength int) *JapaneseKatakanaStemFilter {
+	ans := &JapaneseKatakanaStemFilter{
+		TokenFilter:   NewTokenFilter(in),
+		input:         in,
+		minimumLength: minimumLength,
+	}
+	ans.termAtt = ans.Attributes().Add("CharTermAttribute").(CharTermAttribute)
+	ans.keywordAtt = ans.Attributes().Add("KeywordAttribute").(KeywordAttribute)
+	return ans
+}
+
+func (f *JapaneseKatakanaStemFilter) IncrementToken() (bool, error) {
+	ok, err := f.input.IncrementToken()
+	if err != nil || !ok {
+		return false, err
+	}
+	if !f.keywordAtt.IsKeyword() {
+		f.termAtt.SetLength(f.stem(f.termAtt.Buffer()[:f.termAtt.Length()]))
+	}
+	return true, nil
+}
+
+func (f *JapaneseKatakanaStemFilter) stem(term []rune) int {
+	length := len(term)
+	if length < f.minimumLength {
+		return length
+	}
+	for _, ch := range term {
+		if ch < 0x30A0 || ch > 0x30FF {
+			return length // not katakana
+		}
+	}
+	if term[length-1] == 0x30FC { // HIRAGANA_KATAKANA_PROLONGED_SOUND_MARK
+		return length - 1
+	}
+	return length
+}
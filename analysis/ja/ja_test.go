@@ -0,0 +1,170 @@
+package ja
+
+import (
+	"fmt"
+	. "github.com/balzaczyy/golucene/core/analysis"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"github.com/balzaczyy/golucene/core/util"
+	"strings"
+	"testing"
+)
+
+func terms(t *testing.T, ts TokenStream) []string {
+	termAtt := ts.Attributes().Add("CharTermAttribute").(CharTermAttribute)
+	if err := ts.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	var ans []string
+	for {
+		ok, err := ts.IncrementToken()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			break
+		}
+		ans = append(ans, string(termAtt.Buffer()[:termAtt.Length()]))
+	}
+	if err := ts.End(); err != nil {
+		t.Fatal(err)
+	}
+	if err := ts.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return ans
+}
+
+func assertTerms(t *testing.T, ts TokenStream, expected ...string) {
+	if got := terms(t, ts); fmt.Sprint(got) != fmt.Sprint(expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func newTokenizer(text string, mode Mode) *JapaneseTokenizer {
+	return NewJapaneseTokenizer(strings.NewReader(text), nil, true, mode)
+}
+
+func TestTokenizer(t *testing.T) {
+	assertTerms(t, newTokenizer("多くの学生が試験に落ちた。", NORMAL),
+		"多く", "の", "学生", "が", "試験", "に", "落ち", "た")
+	assertTerms(t, newTokenizer("本を読んでいます", NORMAL), "本", "を", "読ん", "で", "い", "ます")
+	assertTerms(t, newTokenizer("昨日は雨が降らなかった", NORMAL),
+		"昨日", "は", "雨", "が", "降ら", "なかっ", "た")
+	assertTerms(t, newTokenizer("コンピュータで日本語の形態素解析をする", NORMAL),
+		"コンピュータ", "で", "日本語", "の", "形態素", "解析", "を", "する")
+
+	ts := NewJapaneseTokenizer(strings.NewReader("これは本です。"), nil, false, NORMAL)
+	assertTerms(t, ts, "これ", "は", "本", "です", "。")
+}
+
+func TestSearchMode(t *testing.T) {
+	assertTerms(t, newTokenizer("関西国際空港に行った", NORMAL), "関西国際空港", "に", "行っ", "た")
+
+	ts := newTokenizer("関西国際空港に行った", SEARCH)
+	posIncAtt := ts.Attributes().Add("PositionIncrementAttribute").(PositionIncrementAttribute)
+	posLenAtt := ts.Attributes().Add("PositionLengthAttribute").(PositionLengthAttribute)
+	termAtt := ts.Attributes().Add("CharTermAttribute").(CharTermAttribute)
+	if err := ts.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for {
+		ok, err := ts.IncrementToken()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			break
+		}
+		got = append(got, fmt.Sprintf("%v/%v/%v", string(termAtt.Buffer()[:termAtt.Length()]),
+			posIncAtt.PositionIncrement(), posLenAtt.PositionLength()))
+	}
+	ts.End()
+	ts.Close()
+	expected := []string{"関西/1/1", "関西国際空港/0/3", "国際/1/1", "空港/1/1", "に/1/1", "行っ/1/1", "た/1/1"}
+	if fmt.Sprint(got) != fmt.Sprint(expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+
+	assertTerms(t, newTokenizer("コンピュータ", EXTENDED), "コ", "ン", "ピ", "ュ", "ー", "タ")
+}
+
+func TestAttributes(t *testing.T) {
+	ts := newTokenizer("学生が試験に落ちた", NORMAL)
+	termAtt := ts.Attributes().Add("CharTermAttribute").(CharTermAttribute)
+	posAtt := ts.Attributes().Get("PartOfSpeechAttribute").(PartOfSpeechAttribute)
+	baseAtt := ts.Attributes().Get("BaseFormAttribute").(BaseFormAttribute)
+	readingAtt := ts.Attributes().Get("ReadingAttribute").(ReadingAttribute)
+	inflAtt := ts.Attributes().Get("InflectionAttribute").(InflectionAttribute)
+	if err := ts.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for {
+		ok, err := ts.IncrementToken()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			break
+		}
+		got = append(got, fmt.Sprintf("%v:%v:%v:%v:%v:%v", string(termAtt.Buffer()[:termAtt.Length()]),
+			posAtt.PartOfSpeech(), baseAtt.BaseForm(), readingAtt.Reading(),
+			readingAtt.Pronunciation(), inflAtt.InflectionForm()))
+	}
+	ts.End()
+	ts.Close()
+	expected := []string{
+		"学生:名詞-一般::ガクセイ:ガクセー:",
+		"が:助詞-格助詞-一般::ガ:ガ:",
+		"試験:名詞-サ変接続::シケン:シケン:",
+		"に:助詞-格助詞-一般::ニ:ニ:",
+		"落ち:動詞-自立:落ちる:オチ:オチ:連用形",
+		"た:助動詞::タ:タ:基本形",
+	}
+	if fmt.Sprint(got) != fmt.Sprint(expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestUserDictionary(t *testing.T) {
+	dict, err := NewUserDictionary(`
+# custom segmentation
+日本経済新聞,日本 経済 新聞,ニホン ケイザイ シンブン,カスタム名詞
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := NewJapaneseTokenizer(strings.NewReader("日本経済新聞を読む"), dict, true, SEARCH)
+	assertTerms(t, ts, "日本", "経済", "新聞", "を", "読む")
+
+	if _, err = NewUserDictionary("日本経済新聞,日本 経済,ニホン ケイザイ,カスタム名詞"); err == nil {
+		t.Error("expected error for a segmentation not matching the surface form")
+	}
+}
+
+func TestFilters(t *testing.T) {
+	ts := NewJapaneseBaseFormFilter(newTokenizer("それはまだ実験段階にあります", NORMAL))
+	assertTerms(t, ts, "それ", "は", "まだ", "実験", "段階", "に", "ある", "ます")
+
+	ts2 := NewJapaneseReadingFormFilter(newTokenizer("今日は雨が降った", NORMAL))
+	assertTerms(t, ts2, "キョウ", "ハ", "アメ", "ガ", "フッ", "タ")
+
+	ts2 = NewJapaneseReadingFormFilterWithRomaji(newTokenizer("東京に行きたい", NORMAL), true)
+	assertTerms(t, ts2, "toukyou", "ni", "iki", "tai")
+
+	ts3 := NewJapanesePartOfSpeechStopFilter(util.VERSION_LATEST, newTokenizer("私は本を読んだ", NORMAL), DEFAULT_STOP_TAGS)
+	assertTerms(t, ts3, "私", "本", "読ん")
+
+	if got := GetRomanization("ガッコウ ニッチャ シャシン"); got != "gakkou nitcha shashin" {
+		t.Errorf("unexpected romanization %v", got)
+	}
+}
+
+func TestJapaneseAnalyzer(t *testing.T) {
+	ts, err := NewJapaneseAnalyzer().TokenStreamForString("field", "多くの学生が試験に落ちた。ｺﾝﾋﾟｭｰﾀｰを使う")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertTerms(t, ts, "多く", "学生", "試験", "落ちる", "コンピュータ", "使う")
+}
@@ -0,0 +1,90 @@
+package ja
+
+import (
+	"strings"
+)
+
+// ja/util/ToStringUtil.java
+
+var romajiDigraphs = map[string]string{
+	"キャ": "kya", "キュ": "kyu", "キョ": "kyo", "ギャ": "gya", "ギュ": "gyu", "ギョ": "gyo",
+	"シャ": "sha", "シュ": "shu", "ショ": "sho", "シェ": "she", "ジャ": "ja", "ジュ": "ju",
+	"ジョ": "jo", "ジェ": "je", "チャ": "cha", "チュ": "chu", "チョ": "cho", "チェ": "che",
+	"ヂャ": "ja", "ヂュ": "ju", "ヂョ": "jo", "ニャ": "nya", "ニュ": "nyu", "ニョ": "nyo",
+	"ヒャ": "hya", "ヒュ": "hyu", "ヒョ": "hyo", "ビャ": "bya", "ビュ": "byu", "ビョ": "byo",
+	"ピャ": "pya", "ピュ": "pyu", "ピョ": "pyo", "ミャ": "mya", "ミュ": "myu", "ミョ": "myo",
+	"リャ": "rya", "リュ": "ryu", "リョ": "ryo", "ファ": "fa", "フィ": "fi", "フェ": "fe",
+	"フォ": "fo", "ティ": "ti", "ディ": "di", "デュ": "dyu", "ウィ": "wi", "ウェ": "we",
+	"ウォ": "wo", "ヴァ": "va", "ヴィ": "vi", "ヴェ": "ve", "ヴォ": "vo", "ツァ": "tsa",
+}
+
+var romajiMonographs = map[rune]string{
+	'ア': "a", 'イ': "i", 'ウ': "u", 'エ': "e", 'オ': "o",
+	'カ': "ka", 'キ': "ki", 'ク': "ku", 'ケ': "ke", 'コ': "ko",
+	'ガ': "ga", 'ギ': "gi", 'グ': "gu", 'ゲ': "ge", 'ゴ': "go",
+	'サ': "sa", 'シ': "shi", 'ス': "su", 'セ': "se", 'ソ': "so",
+	'ザ': "za", 'ジ': "ji", 'ズ': "zu", 'ゼ': "ze", 'ゾ': "zo",
+	'タ': "ta", 'チ': "chi", 'ツ': "tsu", 'テ': "te", 'ト': "to",
+	'ダ': "da", 'ヂ': "ji", 'ヅ': "zu", 'デ': "de", 'ド': "do",
+	'ナ': "na", 'ニ': "ni", 'ヌ': "nu", 'ネ': "ne", 'ノ': "no",
+	'ハ': "ha", 'ヒ': "hi", 'フ': "fu", 'ヘ': "he", 'ホ': "ho",
+	'バ': "ba", 'ビ': "bi", 'ブ': "bu", 'ベ': "be", 'ボ': "bo",
+	'パ': "pa", 'ピ': "pi", 'プ': "pu", 'ペ': "pe", 'ポ': "po",
+	'マ': "ma", 'ミ': "mi", 'ム': "mu", 'メ': "me", 'モ': "mo",
+	'ヤ': "ya", 'ユ': "yu", 'ヨ': "yo",
+	'ラ': "ra", 'リ': "ri", 'ル': "ru", 'レ': "re", 'ロ': "ro",
+	'ワ': "wa", 'ヰ': "i", 'ヱ': "e", 'ヲ': "o", 'ン': "n", 'ヴ': "vu",
+	'ァ': "a", 'ィ': "i", 'ゥ': "u", 'ェ': "e", 'ォ': "o",
+	'ャ': "ya", 'ュ': "yu", 'ョ': "yo", 'ヮ': "wa",
+}
+
+/*
+Romanize katakana with modified hepburn. Characters other than
+katakana are kept as is, and the long sound mark is dropped.
+*/
+func GetRomanization(s string) string {
+	var b strings.Builder
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		ch := runes[i]
+		if ch == 'ー' {
+			continue
+		}
+		if ch == 'ッ' {
+			// sokuon: double the next consonant, "tch" before ch
+			if next := romajiAt(runes, i+1); next != "" && !strings.ContainsAny(next[:1], "aiueon") {
+				if strings.HasPrefix(next, "ch") {
+					b.WriteByte('t')
+				} else {
+					b.WriteByte(next[0])
+				}
+			}
+			continue
+		}
+		if i+1 < len(runes) {
+			if r, ok := romajiDigraphs[string(runes[i:i+2])]; ok {
+				b.WriteString(r)
+				i++
+				continue
+			}
+		}
+		if r, ok := romajiMonographs[ch]; ok {
+			b.WriteString(r)
+		} else {
+			b.WriteRune(ch)
+		}
+	}
+	return b.String()
+}
+
+func romajiAt(runes []rune, i int) string {
+	if i >= len(runes) {
+		return ""
+	}
+	if i+1 < len(runes) {
+		if r, ok := romajiDigraphs[string(runes[i:i+2])]; ok {
+			return r
+		}
+	}
+	return romajiMonographs[runes[i]]
+}
@@ -0,0 +1,466 @@
+package ja
+
+import (
+	. "github.com/balzaczyy/golucene/core/analysis"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"io"
+	"math"
+	"unicode"
+)
+
+// ja/JapaneseTokenizer.java
+
+/* Tokenization mode: this determines how the tokenizer handles compound and unknown words. */
+type Mode int
+
+const (
+	// Ordinary segmentation: no decomposition for compounds
+	NORMAL Mode = iota
+	// Segmentation geared towards search: this includes a
+	// decompounding process for long nouns, also including the full
+	// compound token as a synonym.
+	SEARCH
+	// Extended mode outputs unigrams for unknown words.
+	EXTENDED
+)
+
+/* Default tokenization mode. Currently this is SEARCH. */
+const DEFAULT_MODE = SEARCH
+
+const (
+	SEARCH_MODE_KANJI_LENGTH  = 2
+	SEARCH_MODE_OTHER_LENGTH  = 7 // Must be >= SEARCH_MODE_KANJI_LENGTH
+	SEARCH_MODE_KANJI_PENALTY = 3000
+	SEARCH_MODE_OTHER_PENALTY = 1700
+)
+
+// cost of the words of the user dictionary, so that they always win
+const userWordCost = -100000
+
+/*
+Tokenizer for Japanese that uses morphological analysis.
+
+This tokenizer sets a number of additional attributes:
+
+	- BaseFormAttribute containing base form for inflected adjectives
+	and verbs.
+	- PartOfSpeechAttribute containing part-of-speech.
+	- ReadingAttribute containing reading and pronunciation.
+	- InflectionAttribute containing additional part-of-speech
+	information for inflected forms.
+
+This tokenizer uses a rolling Viterbi search to find the least cost
+segmentation (path) of the incoming characters. For tokens that
+appear to be compound (> length 2 for all Kanji, or > length 7 for
+non-Kanji), we see if there is a 2nd best segmentation of that token
+after applying penalties to the long tokens. If so, and the Mode is
+SEARCH, we output the alternate segmentation as well.
+
+GoLucene ships a small built-in dictionary of frequent words; other
+words are segmented by character class as unknown words, and a
+UserDictionary can supply domain vocabulary.
+*/
+type JapaneseTokenizer struct {
+	*Tokenizer
+
+	userDictionary     *UserDictionary
+	discardPunctuation bool
+	mode               Mode
+
+	// the whole input, read on the first token
+	text []rune
+	read bool
+	// the segmented tokens still to be emitted
+	pending []*jaToken
+
+	termAtt    CharTermAttribute
+	offsetAtt  OffsetAttribute
+	posIncAtt  PositionIncrementAttribute
+	posLenAtt  PositionLengthAttribute
+	morphology *MorphologyAttributeImpl
+}
+
+type jaToken struct {
+	m              *morpheme
+	start, end     int
+	posInc, posLen int
+}
+
+/*
+Create a new JapaneseTokenizer.
+
+userDictionary may be nil. If discardPunctuation is true,
+punctuation tokens are dropped from the output.
+*/
+func NewJapaneseTokenizer(input io.RuneReader, userDictionary *UserDictionary, discardPunctuation bool, mode Mode) *JapaneseTokenizer {
+	ans := &JapaneseTokenizer{
+		Tokenizer:          NewTokenizer(input),
+		userDictionary:     userDictionary,
+		discardPunctuation: discardPunctuation,
+		mode:               mode,
+	}
+	ans.termAtt = ans.Attributes().Add("CharTermAttribute").(CharTermAttribute)
+	ans.offsetAtt = ans.Attributes().Add("OffsetAttribute").(OffsetAttribute)
+	ans.posIncAtt = ans.Attributes().Add("PositionIncrementAttribute").(PositionIncrementAttribute)
+	ans.posLenAtt = ans.Attributes().Add("PositionLengthAttribute").(PositionLengthAttribute)
+	ans.morphology = morphologyAttribute(ans.Attributes())
+	return ans
+}
+
+func (t *JapaneseTokenizer) IncrementToken() (bool, error) {
+	t.Attributes().Clear()
+	if !t.read {
+		if err := t.readInput(); err != nil {
+			return false, err
+		}
+	}
+	if len(t.pending) == 0 {
+		return false, nil
+	}
+
+	token := t.pending[0]
+	t.pending = t.pending[1:]
+	m := token.m
+	t.termAtt.CopyBuffer(t.text[token.start:token.end])
+	t.offsetAtt.SetOffset(t.CorrectOffset(token.start), t.CorrectOffset(token.end))
+	t.posIncAtt.SetPositionIncrement(token.posInc)
+	t.posLenAtt.SetPositionLength(token.posLen)
+	t.morphology.SetPartOfSpeech(m.partOfSpeech)
+	if m.baseForm != m.surface {
+		t.morphology.SetBaseForm(m.baseForm)
+	}
+	t.morphology.SetReading(m.reading, m.pronunciation)
+	t.morphology.SetInflection(m.inflectionType, m.inflectionForm)
+	return true, nil
+}
+
+func (t *JapaneseTokenizer) readInput() error {
+	for {
+		ch, _, err := t.Input.ReadRune()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		t.text = append(t.text, ch)
+	}
+	t.read = true
+	t.pending = t.segment()
+	return nil
+}
+
+/* Segments the whole text into the tokens to emit. */
+func (t *JapaneseTokenizer) segment() []*jaToken {
+	path := t.viterbi(false)
+	if t.mode != NORMAL {
+		// decompound along the penalized path, and keep the compounds
+		// as synonyms spanning their parts
+		searchPath := t.viterbi(true)
+		compounds := make(map[int]*jaToken)
+		for _, token := range path {
+			compounds[token.start] = token
+		}
+		var merged []*jaToken
+		for i := 0; i < len(searchPath); i++ {
+			token := searchPath[i]
+			merged = append(merged, token)
+			compound, ok := compounds[token.start]
+			if !ok || compound.end == token.end {
+				continue
+			}
+			j := i
+			for j < len(searchPath) && searchPath[j].end < compound.end {
+				j++
+			}
+			if j < len(searchPath) && searchPath[j].end == compound.end {
+				merged = append(merged, &jaToken{m: compound.m, start: compound.start,
+					end: compound.end, posInc: 0, posLen: j - i + 1})
+			}
+		}
+		path = merged
+	}
+	if t.mode == EXTENDED {
+		path = t.unigramUnknownWords(path)
+	}
+
+	var ans []*jaToken
+	for _, token := range path {
+		if t.discardPunctuation && isPunctuation(t.text[token.start:token.end]) {
+			continue
+		}
+		ans = append(ans, token)
+	}
+	return ans
+}
+
+/* Splits unknown words into single character tokens. */
+func (t *JapaneseTokenizer) unigramUnknownWords(path []*jaToken) []*jaToken {
+	var ans []*jaToken
+	for _, token := range path {
+		if !token.m.unknown || token.end-token.start == 1 || token.posInc == 0 {
+			ans = append(ans, token)
+			continue
+		}
+		for i := token.start; i < token.end; i++ {
+			ans = append(ans, &jaToken{m: unknownMorpheme(t.text[i:i+1], characterClass(t.text[i])),
+				start: i, end: i + 1, posInc: 1, posLen: 1})
+		}
+	}
+	return ans
+}
+
+/* A node of the lattice: a word of the text, with the best path to its end. */
+type latticeNode struct {
+	start, end int
+	m          *morpheme
+	// the segmentation of a user dictionary word
+	user *userEntry
+	// cost of the best path up to and including this node
+	cost int
+	prev *latticeNode
+}
+
+/* The morpheme connecting to the left of the node. */
+func (n *latticeNode) left() *morpheme {
+	if n.user != nil {
+		return n.user.segments[0]
+	}
+	return n.m
+}
+
+/* The morpheme connecting to the right of the node. */
+func (n *latticeNode) right() *morpheme {
+	if n.user != nil {
+		return n.user.segments[len(n.user.segments)-1]
+	}
+	return n.m
+}
+
+/* Returns the least cost segmentation of the text, with penalties for long words if search is true. */
+func (t *JapaneseTokenizer) viterbi(search bool) []*jaToken {
+	text := t.text
+	n := len(text)
+	ends := make([][]*latticeNode, n+1)
+	ends[0] = []*latticeNode{{}} // beginning of text
+
+	for pos := 0; pos < n; pos++ {
+		if len(ends[pos]) == 0 {
+			continue
+		}
+		add := func(node *latticeNode, wordCost int) {
+			best := math.MaxInt32
+			for _, prev := range ends[pos] {
+				cost := prev.cost + connectionCost(prev.right(), node.left())
+				if cost < best {
+					best, node.prev = cost, prev
+				}
+			}
+			node.cost = best + wordCost
+			ends[node.end] = append(ends[node.end], node)
+		}
+
+		if t.userDictionary != nil {
+			if e := t.userDictionary.lookup(text[pos:]); e != nil {
+				end := pos + len([]rune(e.surface))
+				add(&latticeNode{start: pos, end: end, user: e}, userWordCost)
+				continue
+			}
+		}
+
+		anyMatches := false
+		systemDictionary.lookup(text[pos:], func(m *morpheme, length int) {
+			anyMatches = true
+			cost := m.cost
+			if search {
+				cost += computePenalty(text[pos : pos+length])
+			}
+			add(&latticeNode{start: pos, end: pos + length, m: m}, cost)
+		})
+
+		class := characterClass(text[pos])
+		def := unknownDefinitions[class]
+		if anyMatches && !def.invoke {
+			continue
+		}
+		if def.group {
+			length := 1
+			for pos+length < n && characterClass(text[pos+length]) == class {
+				length++
+			}
+			m := unknownMorpheme(text[pos:pos+length], class)
+			cost := m.cost
+			if search {
+				cost += computePenalty(text[pos : pos+length])
+			}
+			add(&latticeNode{start: pos, end: pos + length, m: m}, cost)
+		}
+		for length := 1; length <= def.length && pos+length <= n; length++ {
+			if length > 1 && characterClass(text[pos+length-1]) != class {
+				break
+			}
+			m := unknownMorpheme(text[pos:pos+length], class)
+			add(&latticeNode{start: pos, end: pos + length, m: m}, m.cost)
+		}
+	}
+
+	// end of text
+	var last *latticeNode
+	best := math.MaxInt32
+	for _, node := range ends[n] {
+		if node.m == nil && node.user == nil {
+			continue // empty text
+		}
+		if cost := node.cost + connectionCost(node.right(), nil); cost < best {
+			best, last = cost, node
+		}
+	}
+
+	var nodes []*latticeNode
+	for node := last; node != nil && (node.m != nil || node.user != nil); node = node.prev {
+		nodes = append(nodes, node)
+	}
+	var ans []*jaToken
+	for i := len(nodes) - 1; i >= 0; i-- {
+		node := nodes[i]
+		if node.user == nil {
+			ans = append(ans, &jaToken{m: node.m, start: node.start, end: node.end, posInc: 1, posLen: 1})
+			continue
+		}
+		start := node.start
+		for _, segment := range node.user.segments {
+			end := start + len([]rune(segment.surface))
+			ans = append(ans, &jaToken{m: segment, start: start, end: end, posInc: 1, posLen: 1})
+			start = end
+		}
+	}
+	return ans
+}
+
+/* Returns the search mode penalty of a long word. */
+func computePenalty(word []rune) int {
+	length := len(word)
+	if length > SEARCH_MODE_KANJI_LENGTH {
+		allKanji := true
+		for _, ch := range word {
+			if characterClass(ch) != KANJI {
+				allKanji = false
+				break
+			}
+		}
+		if allKanji {
+			return (length - SEARCH_MODE_KANJI_LENGTH) * SEARCH_MODE_KANJI_PENALTY
+		} else if length > SEARCH_MODE_OTHER_LENGTH {
+			return (length - SEARCH_MODE_OTHER_LENGTH) * SEARCH_MODE_OTHER_PENALTY
+		}
+	}
+	return 0
+}
+
+func isPunctuation(text []rune) bool {
+	for _, ch := range text {
+		if !(unicode.IsPunct(ch) || unicode.IsSymbol(ch) || unicode.IsSpace(ch) ||
+			unicode.IsControl(ch) || unicode.In(ch, unicode.Cf)) {
+			return false
+		}
+	}
+	return len(text) > 0
+}
+
+func (t *JapaneseTokenizer) End() error {
+	if err := t.Tokenizer.End(); err != nil {
+		return err
+	}
+	// set final offset
+	finalOffset := t.CorrectOffset(len(t.text))
+	t.offsetAtt.SetOffset(finalOffset, finalOffset)
+	return nil
+}
+
+func (t *JapaneseTokenizer) Reset() error {
+	if err := t.Tokenizer.Reset(); err != nil {
+		return err
+	}
+	t.text, t.read = nil, false
+	t.pending = nil
+	return nil
+}
+
+// ja/dict/CharacterDefinition.java
+
+/* Character classes of the unknown word dictionary. */
+const (
+	DEFAULT = iota
+	SPACE
+	KANJI
+	SYMBOL
+	NUMERIC
+	ALPHA
+	HIRAGANA
+	KATAKANA
+	KANJINUMERIC
+)
+
+func characterClass(ch rune) int {
+	switch {
+	case unicode.IsSpace(ch):
+		return SPACE
+	case ch == '〇' || ch == '一' || ch == '二' || ch == '三' || ch == '四' || ch == '五' ||
+		ch == '六' || ch == '七' || ch == '八' || ch == '九' || ch == '十' || ch == '百' ||
+		ch == '千' || ch == '万' || ch == '億' || ch == '兆':
+		return KANJINUMERIC
+	case unicode.Is(unicode.Han, ch) || ch == '々' || ch == '〆' || ch == 'ヶ':
+		return KANJI
+	case unicode.Is(unicode.Hiragana, ch):
+		return HIRAGANA
+	case unicode.Is(unicode.Katakana, ch) || ch == 'ー' || ch == 'ｰ' || ch == 'ﾞ' || ch == 'ﾟ':
+		return KATAKANA
+	case unicode.IsDigit(ch):
+		return NUMERIC
+	case unicode.IsLetter(ch):
+		return ALPHA
+	case unicode.IsPunct(ch) || unicode.IsSymbol(ch):
+		return SYMBOL
+	}
+	return DEFAULT
+}
+
+// ja/dict/UnknownDictionary.java
+
+/*
+How unknown words are formed for each character class: invoke adds
+them even when a dictionary word starts at the same position, group
+makes one word of a run of the class, and length adds the words of
+each length up to it.
+*/
+var unknownDefinitions = map[int]struct {
+	invoke, group bool
+	length        int
+	partOfSpeech  string
+	cost          int
+}{
+	DEFAULT:      {false, true, 0, "名詞-一般", 6000},
+	SPACE:        {false, true, 0, "記号-空白", 0},
+	KANJI:        {false, false, 2, "名詞-一般", 7500},
+	SYMBOL:       {true, true, 0, "記号-一般", 3000},
+	NUMERIC:      {true, true, 0, "名詞-数", 3000},
+	ALPHA:        {true, true, 0, "名詞-固有名詞-組織", 3000},
+	HIRAGANA:     {false, true, 0, "名詞-一般", 10000},
+	KATAKANA:     {true, true, 0, "名詞-一般", 4000},
+	KANJINUMERIC: {true, true, 0, "名詞-数", 3000},
+}
+
+/* Returns an unknown word morpheme for the text of the given character class. */
+func unknownMorpheme(text []rune, class int) *morpheme {
+	def := unknownDefinitions[class]
+	cost := def.cost
+	if !def.group || class == HIRAGANA {
+		// longer runs are less likely to be a single unknown word
+		cost += 1500 * (len(text) - 1)
+	}
+	return &morpheme{
+		surface:      string(text),
+		partOfSpeech: def.partOfSpeech,
+		cost:         cost,
+		unknown:      true,
+	}
+}
@@ -0,0 +1,57 @@
+package minhash
+
+import (
+	"errors"
+	. "github.com/balzaczyy/golucene/analysis/util"
+	. "github.com/balzaczyy/golucene/core/analysis"
+)
+
+func init() {
+	RegisterTokenFilterFactory("minHash", func(args map[string]string) (TokenFilterFactory, error) {
+		f, err := NewMinHashFilterFactory(args)
+		if err != nil {
+			return nil, err
+		}
+		return f, nil
+	})
+}
+
+// minhash/MinHashFilterFactory.java
+
+/*
+Factory for MinHashFilter, taking the "hashCount", "bucketCount",
+"hashSetSize" and whether to fill empty buckets "withRotation" (the
+default).
+*/
+type MinHashFilterFactory struct {
+	*AbstractAnalysisFactory
+	hashCount    int
+	bucketCount  int
+	hashSetSize  int
+	withRotation bool
+}
+
+func NewMinHashFilterFactory(args map[string]string) (*MinHashFilterFactory, error) {
+	ans := &MinHashFilterFactory{AbstractAnalysisFactory: NewAbstractAnalysisFactory(args)}
+	ans.hashCount = ans.GetInt("hashCount", DEFAULT_HASH_COUNT)
+	ans.bucketCount = ans.GetInt("bucketCount", DEFAULT_BUCKET_COUNT)
+	ans.hashSetSize = ans.GetInt("hashSetSize", DEFAULT_HASH_SET_SIZE)
+	ans.withRotation = ans.GetBool("withRotation", true)
+	if err := ans.Validate(); err != nil {
+		return nil, err
+	}
+	if ans.hashCount <= 0 {
+		return nil, errors.New("hashCount must be greater than zero")
+	}
+	if ans.bucketCount <= 0 {
+		return nil, errors.New("bucketCount must be greater than zero")
+	}
+	if ans.hashSetSize <= 0 {
+		return nil, errors.New("hashSetSize must be greater than zero")
+	}
+	return ans, nil
+}
+
+func (f *MinHashFilterFactory) Create(input TokenStream) TokenStream {
+	return NewMinHashFilter(input, f.hashCount, f.bucketCount, f.hashSetSize, f.withRotation)
+}
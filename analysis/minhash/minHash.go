@@ -0,0 +1,394 @@
+package minhash
+
+import (
+	"encoding/binary"
+	. "github.com/balzaczyy/golucene/core/analysis"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"github.com/balzaczyy/golucene/core/util"
+	"math/bits"
+	"unicode/utf16"
+)
+
+// minhash/MinHashFilter.java
+
+const (
+	DEFAULT_HASH_COUNT    = 1
+	DEFAULT_HASH_SET_SIZE = 1
+	DEFAULT_BUCKET_COUNT  = 512
+
+	MIN_HASH_TYPE = "MIN_HASH"
+
+	HASH_CACHE_SIZE = 512
+)
+
+var cachedIntHashes = func() []longPair {
+	ans := make([]longPair, HASH_CACHE_SIZE)
+	for i := range ans {
+		ans[i] = intHash(i)
+	}
+	return ans
+}()
+
+/*
+Generate min hash tokens from an incoming stream of tokens. The
+incoming tokens would typically be 5 word shingles.
+
+The number of hashes used and the number of minimum values for each
+hash can be set. You could have 1 hash and keep the 100 lowest
+values or 100 hashes and keep the lowest one for each. Hashes can
+also be bucketed in ranges over the 128-bit hash space.
+
+A 128-bit hash is used internally. 5 word shingles from 10^10 words
+generate 10^50 distinct shingles. This equates to approximately
+2^166, so 128 bits is not quite enough to hash all shingles without
+collision.
+
+When using different hashes 32 bits are used for the hash position
+leaving scope for 8.59e9 hashes.
+*/
+type MinHashFilter struct {
+	*TokenFilter
+	input TokenStream
+
+	minHashSets  [][]*fixedSizeHashSet
+	hashSetSize  int
+	bucketCount  int
+	hashCount    int
+	withRotation bool
+	bucketSize   uint64
+
+	requiresInitialisation bool
+	endState               *util.AttributeState
+	hashPosition           int
+	bucketPosition         int
+	endOffset              int
+	exhausted              bool
+
+	termAtt   CharTermAttribute
+	offsetAtt OffsetAttribute
+	typeAtt   TypeAttribute
+	posIncAtt PositionIncrementAttribute
+	posLenAtt PositionLengthAttribute
+}
+
+/*
+Create a MinHash filter. hashCount is the number of hashes to use,
+bucketCount the number of buckets to split the hash space into,
+hashSetSize the number of minimum hashes to keep per bucket, and
+withRotation whether empty buckets are filled with the hash of the
+next non-empty bucket, which only applies with a hashSetSize of 1.
+*/
+func NewMinHashFilter(input TokenStream, hashCount, bucketCount, hashSetSize int, withRotation bool) *MinHashFilter {
+	if hashCount <= 0 {
+		panic("hashCount must be greater than zero")
+	}
+	if bucketCount <= 0 {
+		panic("bucketCount must be greater than zero")
+	}
+	if hashSetSize <= 0 {
+		panic("hashSetSize must be greater than zero")
+	}
+	ans := &MinHashFilter{
+		TokenFilter:  NewTokenFilter(input),
+		input:        input,
+		hashSetSize:  hashSetSize,
+		bucketCount:  bucketCount,
+		hashCount:    hashCount,
+		withRotation: withRotation && bucketCount > 1,
+		bucketSize:   (uint64(1) << 32) / uint64(bucketCount),
+	}
+	if (uint64(1)<<32)%uint64(bucketCount) != 0 {
+		ans.bucketSize++
+	}
+	ans.minHashSets = make([][]*fixedSizeHashSet, hashCount)
+	for i := range ans.minHashSets {
+		buckets := make([]*fixedSizeHashSet, bucketCount)
+		for j := range buckets {
+			buckets[j] = &fixedSizeHashSet{capacity: hashSetSize}
+		}
+		ans.minHashSets[i] = buckets
+	}
+	ans.termAtt = ans.Attributes().Add("CharTermAttribute").(CharTermAttribute)
+	ans.offsetAtt = ans.Attributes().Add("OffsetAttribute").(OffsetAttribute)
+	ans.typeAtt = ans.Attributes().Add("TypeAttribute").(TypeAttribute)
+	ans.posIncAtt = ans.Attributes().Add("PositionIncrementAttribute").(PositionIncrementAttribute)
+	ans.posLenAtt = ans.Attributes().Add("PositionLengthAttribute").(PositionLengthAttribute)
+	ans.doReset()
+	return ans
+}
+
+func (f *MinHashFilter) IncrementToken() (bool, error) {
+	// Pull the underlying stream of tokens
+	// Hash each token found
+	// Generate the required number of variants of this hash
+	// Keep the minimum hash value found so far of each variant
+
+	positionIncrement := 0
+	if f.requiresInitialisation {
+		f.requiresInitialisation = false
+		found := false
+		// First time through so we pull and hash everything
+		for {
+			ok, err := f.input.IncrementToken()
+			if err != nil {
+				return false, err
+			}
+			if !ok {
+				break
+			}
+			found = true
+			hash := murmurhash3_x64_128(utf16LE(f.termAtt.Buffer()[:f.termAtt.Length()]), 0)
+			for i := 0; i < f.hashCount; i++ {
+				rehashed := combineOrdered(hash, getIntHash(i))
+				f.minHashSets[i][(rehashed.val2>>32)/f.bucketSize].add(rehashed)
+			}
+			f.endOffset = f.offsetAtt.EndOffset()
+		}
+		f.exhausted = true
+		if err := f.input.End(); err != nil {
+			return false, err
+		}
+		// We need the end state so an underlying shingle filter can have
+		// its state restored correctly.
+		f.endState = f.Attributes().CaptureState()
+		if !found {
+			return false, nil
+		}
+
+		positionIncrement = 1
+		// fix up any wrap around bucket values...
+		if f.withRotation && f.hashSetSize == 1 {
+			for _, buckets := range f.minHashSets {
+				for i, bucket := range buckets {
+					if len(bucket.hashes) > 0 {
+						continue
+					}
+					for offset := 1; offset < f.bucketCount; offset++ {
+						if next := buckets[(i+offset)%f.bucketCount]; len(next.hashes) > 0 {
+							bucket.add(next.hashes[0])
+							break
+						}
+					}
+				}
+			}
+		}
+	}
+
+	f.Attributes().Clear()
+
+	for ; f.hashPosition < f.hashCount; f.hashPosition++ {
+		for ; f.bucketPosition < f.bucketCount; f.bucketPosition++ {
+			hash, ok := f.minHashSets[f.hashPosition][f.bucketPosition].pollFirst()
+			if !ok {
+				continue
+			}
+			var term []rune
+			if f.hashCount > 1 {
+				term = appendChars(term, uint64(uint32(f.hashPosition)), 2)
+			}
+			term = appendChars(term, hash.val2, 4)
+			term = appendChars(term, hash.val1, 4)
+			f.termAtt.CopyBuffer(term)
+			f.posIncAtt.SetPositionIncrement(positionIncrement)
+			f.offsetAtt.SetOffset(0, f.endOffset)
+			f.typeAtt.SetType(MIN_HASH_TYPE)
+			f.posLenAtt.SetPositionLength(1)
+			return true, nil
+		}
+		f.bucketPosition = 0
+	}
+	return false, nil
+}
+
+func (f *MinHashFilter) End() error {
+	if !f.exhausted {
+		if err := f.input.End(); err != nil {
+			return err
+		}
+	}
+	if f.endState != nil {
+		f.Attributes().RestoreState(f.endState)
+	}
+	return nil
+}
+
+func (f *MinHashFilter) Reset() error {
+	if err := f.TokenFilter.Reset(); err != nil {
+		return err
+	}
+	f.doReset()
+	return nil
+}
+
+func (f *MinHashFilter) doReset() {
+	for _, buckets := range f.minHashSets {
+		for _, bucket := range buckets {
+			bucket.hashes = bucket.hashes[:0]
+		}
+	}
+	f.endState = nil
+	f.hashPosition = 0
+	f.bucketPosition = 0
+	f.requiresInitialisation = true
+	f.exhausted = false
+}
+
+/*
+Appends the n lowest 16-bit chars of x, highest first. Chars in the
+surrogate range cannot be encoded as UTF-8, so they are shifted
+above it to keep the terms distinct once indexed.
+*/
+func appendChars(term []rune, x uint64, n int) []rune {
+	for i := n - 1; i >= 0; i-- {
+		ch := rune(uint16(x >> uint(16*i)))
+		if ch >= 0xD800 {
+			ch += 0x800
+		}
+		term = append(term, ch)
+	}
+	return term
+}
+
+func utf16LE(term []rune) []byte {
+	chars := utf16.Encode(term)
+	ans := make([]byte, 2*len(chars))
+	for i, ch := range chars {
+		binary.LittleEndian.PutUint16(ans[2*i:], ch)
+	}
+	return ans
+}
+
+func getIntHash(i int) longPair {
+	if i < HASH_CACHE_SIZE {
+		return cachedIntHashes[i]
+	}
+	return intHash(i)
+}
+
+func intHash(i int) longPair {
+	var bytes [4]byte
+	binary.BigEndian.PutUint32(bytes[:], uint32(i))
+	return murmurhash3_x64_128(bytes[:], 0)
+}
+
+/* A 128-bit hash, ordered by val2 and then val1. */
+type longPair struct {
+	val1, val2 uint64
+}
+
+func (p longPair) less(other longPair) bool {
+	return p.val2 < other.val2 || p.val2 == other.val2 && p.val1 < other.val1
+}
+
+func combineOrdered(hashCodes ...longPair) (result longPair) {
+	for _, hashCode := range hashCodes {
+		result.val1 = result.val1*37 + hashCode.val1
+		result.val2 = result.val2*37 + hashCode.val2
+	}
+	return
+}
+
+/* A sorted set keeping only the smallest capacity hashes added to it. */
+type fixedSizeHashSet struct {
+	capacity int
+	hashes   []longPair
+}
+
+func (s *fixedSizeHashSet) add(hash longPair) bool {
+	n := len(s.hashes)
+	if n >= s.capacity && !hash.less(s.hashes[n-1]) {
+		return false
+	}
+	i := 0
+	for i < n && s.hashes[i].less(hash) {
+		i++
+	}
+	if i < n && s.hashes[i] == hash {
+		return false
+	}
+	if n >= s.capacity {
+		s.hashes = s.hashes[:n-1]
+	}
+	s.hashes = append(s.hashes, longPair{})
+	copy(s.hashes[i+1:], s.hashes[i:])
+	s.hashes[i] = hash
+	return true
+}
+
+func (s *fixedSizeHashSet) pollFirst() (longPair, bool) {
+	if len(s.hashes) == 0 {
+		return longPair{}, false
+	}
+	ans := s.hashes[0]
+	s.hashes = s.hashes[1:]
+	return ans, true
+}
+
+/* Returns the MurmurHash3_x64_128 hash. */
+func murmurhash3_x64_128(key []byte, seed uint32) longPair {
+	const c1, c2 = uint64(0x87c37b91114253d5), uint64(0x4cf5ad432745937f)
+	h1, h2 := uint64(seed), uint64(seed)
+
+	roundedEnd := len(key) & 0xfffffff0 // round down to 16 byte block
+	for i := 0; i < roundedEnd; i += 16 {
+		k1 := binary.LittleEndian.Uint64(key[i:])
+		k2 := binary.LittleEndian.Uint64(key[i+8:])
+		k1 *= c1
+		k1 = bits.RotateLeft64(k1, 31)
+		k1 *= c2
+		h1 ^= k1
+		h1 = bits.RotateLeft64(h1, 27)
+		h1 += h2
+		h1 = h1*5 + 0x52dce729
+		k2 *= c2
+		k2 = bits.RotateLeft64(k2, 33)
+		k2 *= c1
+		h2 ^= k2
+		h2 = bits.RotateLeft64(h2, 31)
+		h2 += h1
+		h2 = h2*5 + 0x38495ab5
+	}
+
+	// tail
+	var k1, k2 uint64
+	tail := key[roundedEnd:]
+	for i := len(tail) - 1; i >= 0; i-- {
+		if i >= 8 {
+			k2 ^= uint64(tail[i]) << uint(8*(i-8))
+		} else {
+			k1 ^= uint64(tail[i]) << uint(8*i)
+		}
+	}
+	if len(tail) > 8 {
+		k2 *= c2
+		k2 = bits.RotateLeft64(k2, 33)
+		k2 *= c1
+		h2 ^= k2
+	}
+	if len(tail) > 0 {
+		k1 *= c1
+		k1 = bits.RotateLeft64(k1, 31)
+		k1 *= c2
+		h1 ^= k1
+	}
+
+	// finalization
+	h1 ^= uint64(len(key))
+	h2 ^= uint64(len(key))
+	h1 += h2
+	h2 += h1
+	h1 = fmix64(h1)
+	h2 = fmix64(h2)
+	h1 += h2
+	h2 += h1
+	return longPair{h1, h2}
+}
+
+func fmix64(k uint64) uint64 {
+	k ^= k >> 33
+	k *= 0xff51afd7ed558ccd
+	k ^= k >> 33
+	k *= 0xc4ceb9fe1a85ec53
+	k ^= k >> 33
+	return k
+}
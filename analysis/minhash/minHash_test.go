@@ -0,0 +1,185 @@
+package minhash
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"github.com/balzaczyy/golucene/analysis/pattern"
+	"github.com/balzaczyy/golucene/analysis/shingle"
+	. "github.com/balzaczyy/golucene/core/analysis"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestMurmurHash3(t *testing.T) {
+	for text, expected := range map[string]string{
+		"":      "00000000000000000000000000000000",
+		"hello": "029bbd41b3a7d8cb191dae486a901e5b",
+		"The quick brown fox jumps over the lazy dog": "6c1b07bc7bbc4be347939ac4a93c437a",
+	} {
+		hash := murmurhash3_x64_128([]byte(text), 0)
+		var digest [16]byte
+		binary.LittleEndian.PutUint64(digest[:], hash.val1)
+		binary.LittleEndian.PutUint64(digest[8:], hash.val2)
+		if got := hex.EncodeToString(digest[:]); got != expected {
+			t.Errorf("%q: expected %v, but was %v", text, expected, got)
+		}
+	}
+}
+
+func whitespaceTokenizer(text string) TokenStream {
+	return pattern.NewPatternTokenizer(strings.NewReader(text), regexp.MustCompile(`\s+`), -1)
+}
+
+/* Returns the min hashes of ts, checking the attributes of each token. */
+func minHashes(t *testing.T, ts TokenStream, termLength, endOffset int) []string {
+	termAtt := ts.Attributes().Add("CharTermAttribute").(CharTermAttribute)
+	posIncAtt := ts.Attributes().Add("PositionIncrementAttribute").(PositionIncrementAttribute)
+	offsetAtt := ts.Attributes().Add("OffsetAttribute").(OffsetAttribute)
+	typeAtt := ts.Attributes().Add("TypeAttribute").(TypeAttribute)
+	if err := ts.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	var ans []string
+	for {
+		ok, err := ts.IncrementToken()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			break
+		}
+		if expected := 0; len(ans) == 0 {
+			expected = 1
+			if got := posIncAtt.PositionIncrement(); got != expected {
+				t.Errorf("expected position increment %v, but was %v", expected, got)
+			}
+		} else if got := posIncAtt.PositionIncrement(); got != expected {
+			t.Errorf("expected position increment %v, but was %v", expected, got)
+		}
+		if termAtt.Length() != termLength {
+			t.Errorf("expected term length %v, but was %v", termLength, termAtt.Length())
+		}
+		if offsetAtt.StartOffset() != 0 || offsetAtt.EndOffset() != endOffset {
+			t.Errorf("expected offsets 0-%v, but was %v-%v", endOffset,
+				offsetAtt.StartOffset(), offsetAtt.EndOffset())
+		}
+		if typeAtt.Type() != MIN_HASH_TYPE {
+			t.Errorf("expected type %v, but was %v", MIN_HASH_TYPE, typeAtt.Type())
+		}
+		ans = append(ans, string(termAtt.Buffer()[:termAtt.Length()]))
+	}
+	if err := ts.End(); err != nil {
+		t.Fatal(err)
+	}
+	if err := ts.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return ans
+}
+
+func TestMinHashFilter(t *testing.T) {
+	text := "woof woof woof woof woof"
+	ts := NewMinHashFilter(whitespaceTokenizer(text), 1, 1, 3, false)
+	if hashes := minHashes(t, ts, 8, len(text)); len(hashes) != 1 {
+		t.Errorf("expected 1 distinct hash, but was %v", len(hashes))
+	}
+
+	text = "the quick brown fox jumped over the lazy dog"
+	ts = NewMinHashFilter(whitespaceTokenizer(text), 1, 1, 100, false)
+	expected := minHashes(t, ts, 8, len(text))
+	if len(expected) != 8 {
+		t.Errorf("expected 8 distinct hashes, but was %v", len(expected))
+	}
+	for i := 1; i < len(expected); i++ {
+		if expected[i-1] >= expected[i] {
+			t.Errorf("expected sorted hashes, but was %q", expected)
+		}
+	}
+	ts = NewMinHashFilter(whitespaceTokenizer(text), 1, 1, 100, false)
+	if got := minHashes(t, ts, 8, len(text)); strings.Join(got, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected the same hashes for the same text")
+	}
+
+	ts = NewMinHashFilter(whitespaceTokenizer(text), 2, 1, 1, false)
+	if hashes := minHashes(t, ts, 10, len(text)); len(hashes) != 2 {
+		t.Errorf("expected 2 hashes, but was %v", len(hashes))
+	}
+
+	ts = NewMinHashFilter(whitespaceTokenizer(""), 1, 1, 1, false)
+	if hashes := minHashes(t, ts, 8, 0); len(hashes) != 0 {
+		t.Errorf("expected no hashes, but was %v", len(hashes))
+	}
+}
+
+func TestMinHashFilterWithRotation(t *testing.T) {
+	ts := NewMinHashFilter(whitespaceTokenizer("woof"), 1, 8, 1, false)
+	if hashes := minHashes(t, ts, 8, 4); len(hashes) != 1 {
+		t.Errorf("expected 1 hash, but was %v", len(hashes))
+	}
+	ts = NewMinHashFilter(whitespaceTokenizer("woof"), 1, 8, 1, true)
+	hashes := minHashes(t, ts, 8, 4)
+	if len(hashes) != 8 {
+		t.Errorf("expected 8 hashes, but was %v", len(hashes))
+	}
+	for _, hash := range hashes {
+		if hash != hashes[0] {
+			t.Errorf("expected the buckets filled with the same hash, but was %q", hashes)
+		}
+	}
+}
+
+func jaccard(a, b []string) float64 {
+	inA := make(map[string]bool)
+	for _, hash := range a {
+		inA[hash] = true
+	}
+	common := 0
+	for _, hash := range b {
+		if inA[hash] {
+			common++
+		}
+	}
+	return float64(common) / float64(len(a)+len(b)-common)
+}
+
+func TestNearDuplicates(t *testing.T) {
+	f, err := NewMinHashFilterFactory(map[string]string{"bucketCount": "1", "hashSetSize": "50"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	signature := func(text string) []string {
+		ts := f.Create(shingle.NewShingleFilterWithSizes(whitespaceTokenizer(text), 2, 2))
+		return minHashes(t, ts, 8, len(text))
+	}
+	words := strings.Fields(strings.Repeat("a b c d e f g h i j k l m n o p q r s t u v w x y z ", 4))
+	for i := range words {
+		words[i] += fmt.Sprint(i)
+	}
+	original := strings.Join(words, " ")
+	words[50] = "changed"
+	nearDuplicate := strings.Join(words, " ")
+	different := strings.Join(strings.Fields(strings.Repeat("lorem ipsum dolor sit amet ", 20)), " ")
+
+	if sim := jaccard(signature(original), signature(nearDuplicate)); sim < 0.8 {
+		t.Errorf("expected near duplicates to be similar, but was %v", sim)
+	}
+	if sim := jaccard(signature(original), signature(different)); sim > 0.1 {
+		t.Errorf("expected different texts to be dissimilar, but was %v", sim)
+	}
+}
+
+func TestMinHashFilterFactory(t *testing.T) {
+	for _, args := range []map[string]string{
+		{"hashCount": "0"},
+		{"bucketCount": "-1"},
+		{"hashSetSize": "0"},
+		{"foo": "bar"},
+	} {
+		if _, err := NewMinHashFilterFactory(args); err == nil {
+			t.Errorf("%v: expected an error", args)
+		}
+	}
+}
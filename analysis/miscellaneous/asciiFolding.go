@@ -0,0 +1,407 @@
+package miscellaneous
+
+import (
+	. "github.com/balzaczyy/golucene/core/analysis"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"github.com/balzaczyy/golucene/core/util"
+)
+
+// miscellaneous/ASCIIFoldingFilter.java
+
+/*
+This class converts alphabetic, numeric, and symbolic Unicode
+characters which are not in the first 127 ASCII characters (the
+"Basic Latin" Unicode block) into their ASCII equivalents, if one
+exists.
+
+Characters from the following Unicode blocks are converted; however,
+only those characters with reasonable ASCII alternatives are
+converted:
+
+	- C1 Controls and Latin-1 Supplement
+	- Latin Extended-A
+	- Latin Extended-B
+	- Latin Extended Additional
+	- Latin Extended-C
+	- Latin Extended-D
+	- IPA Extensions
+	- Phonetic Extensions
+	- Phonetic Extensions Supplement
+	- General Punctuation
+	- Superscripts and Subscripts
+	- Letterlike Symbols
+	- Number Forms
+	- Enclosed Alphanumerics
+	- Alphabetic Presentation Forms
+	- Halfwidth and Fullwidth Forms
+
+See: http://en.wikipedia.org/wiki/Latin_characters_in_Unicode
+
+For example, 'à' will be replaced by 'a'.
+*/
+type ASCIIFoldingFilter struct {
+	*TokenFilter
+	input            TokenStream
+	preserveOriginal bool
+	// the original token, to be emitted after the folded one
+	state *util.AttributeState
+
+	termAtt    CharTermAttribute
+	posIncrAtt PositionIncrementAttribute
+}
+
+/* Create a new ASCIIFoldingFilter. */
+func NewASCIIFoldingFilter(in TokenStream) *ASCIIFoldingFilter {
+	return NewASCIIFoldingFilterWithPreserveOriginal(in, false)
+}
+
+/*
+Create a new ASCIIFoldingFilter. If preserveOriginal is true, the
+original token is emitted after the folded one, at the same position,
+whenever folding changed it.
+*/
+func NewASCIIFoldingFilterWithPreserveOriginal(in TokenStream, preserveOriginal bool) *ASCIIFoldingFilter {
+	ans := &ASCIIFoldingFilter{
+		TokenFilter:      NewTokenFilter(in),
+		input:            in,
+		preserveOriginal: preserveOriginal,
+	}
+	ans.termAtt = ans.Attributes().Add("CharTermAttribute").(CharTermAttribute)
+	ans.posIncrAtt = ans.Attributes().Add("PositionIncrementAttribute").(PositionIncrementAttribute)
+	return ans
+}
+
+/* Does the filter preserve the original tokens? */
+func (f *ASCIIFoldingFilter) IsPreserveOriginal() bool {
+	return f.preserveOriginal
+}
+
+func (f *ASCIIFoldingFilter) IncrementToken() (bool, error) {
+	if f.state != nil {
+		f.Attributes().RestoreState(f.state)
+		f.posIncrAtt.SetPositionIncrement(0)
+		f.state = nil
+		return true, nil
+	}
+
+	ok, err := f.input.IncrementToken()
+	if err != nil || !ok {
+		return false, err
+	}
+	buffer := f.termAtt.Buffer()[:f.termAtt.Length()]
+	for _, ch := range buffer {
+		// If no characters actually require rewriting then we just
+		// return token as-is:
+		if ch >= 0x80 {
+			f.foldToASCII(buffer)
+			break
+		}
+	}
+	return true, nil
+}
+
+func (f *ASCIIFoldingFilter) Reset() error {
+	f.state = nil
+	return f.TokenFilter.Reset()
+}
+
+/* Converts characters above ASCII to their ASCII equivalents. */
+func (f *ASCIIFoldingFilter) foldToASCII(input []rune) {
+	output := FoldToASCII(input)
+	if string(output) == string(input) {
+		return
+	}
+	if f.preserveOriginal {
+		f.state = f.Attributes().CaptureState()
+	}
+	f.termAtt.CopyBuffer(output)
+}
+
+/*
+Converts characters above ASCII to their ASCII equivalents. For
+example, accents are removed from accented characters. Characters
+without an ASCII equivalent are kept as is.
+*/
+func FoldToASCII(input []rune) []rune {
+	output := make([]rune, 0, len(input))
+	for _, ch := range input {
+		if ch < 0x80 {
+			output = append(output, ch)
+		} else if folded, ok := asciiFoldings[ch]; ok {
+			output = append(output, []rune(folded)...)
+		} else {
+			output = append(output, ch)
+		}
+	}
+	return output
+}
+
+var asciiFoldings = func() map[rune]string {
+	ans := make(map[rune]string)
+	for _, folding := range asciiFoldingTable {
+		for _, ch := range folding.from {
+			ans[ch] = folding.to
+		}
+	}
+	return ans
+}()
+
+// ASCII replacements, and the characters folded into them
+var asciiFoldingTable = []struct{ to, from string }{
+	{"!", "¡！"},
+	{"!!", "‼"},
+	{"!?", "⁉"},
+	{"\"", "«»“”„‟″‶＂"},
+	{"#", "＃"},
+	{"$", "＄"},
+	{"%", "％"},
+	{"&", "＆"},
+	{"'", "‘’‚‛′‵＇"},
+	{"'''", "‴‷"},
+	{"''''", "⁗"},
+	{"(", "⁽₍（"},
+	{"(1)", "⑴"},
+	{"(10)", "⑽"},
+	{"(11)", "⑾"},
+	{"(12)", "⑿"},
+	{"(13)", "⒀"},
+	{"(14)", "⒁"},
+	{"(15)", "⒂"},
+	{"(16)", "⒃"},
+	{"(17)", "⒄"},
+	{"(18)", "⒅"},
+	{"(19)", "⒆"},
+	{"(2)", "⑵"},
+	{"(20)", "⒇"},
+	{"(3)", "⑶"},
+	{"(4)", "⑷"},
+	{"(5)", "⑸"},
+	{"(6)", "⑹"},
+	{"(7)", "⑺"},
+	{"(8)", "⑻"},
+	{"(9)", "⑼"},
+	{"(C)", "©"},
+	{"(R)", "®"},
+	{"(a)", "⒜"},
+	{"(b)", "⒝"},
+	{"(c)", "⒞"},
+	{"(d)", "⒟"},
+	{"(e)", "⒠"},
+	{"(f)", "⒡"},
+	{"(g)", "⒢"},
+	{"(h)", "⒣"},
+	{"(i)", "⒤"},
+	{"(j)", "⒥"},
+	{"(k)", "⒦"},
+	{"(l)", "⒧"},
+	{"(m)", "⒨"},
+	{"(n)", "⒩"},
+	{"(o)", "⒪"},
+	{"(p)", "⒫"},
+	{"(q)", "⒬"},
+	{"(r)", "⒭"},
+	{"(s)", "⒮"},
+	{"(t)", "⒯"},
+	{"(u)", "⒰"},
+	{"(v)", "⒱"},
+	{"(w)", "⒲"},
+	{"(x)", "⒳"},
+	{"(y)", "⒴"},
+	{"(z)", "⒵"},
+	{")", "⁾₎）"},
+	{"*", "⁎＊"},
+	{"+", "⁺₊＋"},
+	{",", "，"},
+	{"-", "¬‐‑‒–—―⁃－"},
+	{".", "․．"},
+	{"..", "‥"},
+	{"...", "…"},
+	{"/", "÷⁄／"},
+	{"0", "⁰₀⓪０"},
+	{"0/3", "↉"},
+	{"1", "¹₁①１"},
+	{"1.", "⒈"},
+	{"1/", "⅟"},
+	{"1/10", "⅒"},
+	{"1/2", "½"},
+	{"1/3", "⅓"},
+	{"1/4", "¼"},
+	{"1/5", "⅕"},
+	{"1/6", "⅙"},
+	{"1/7", "⅐"},
+	{"1/8", "⅛"},
+	{"1/9", "⅑"},
+	{"10", "⑩"},
+	{"10.", "⒑"},
+	{"11", "⑪"},
+	{"11.", "⒒"},
+	{"12", "⑫"},
+	{"12.", "⒓"},
+	{"13", "⑬"},
+	{"13.", "⒔"},
+	{"14", "⑭"},
+	{"14.", "⒕"},
+	{"15", "⑮"},
+	{"15.", "⒖"},
+	{"16", "⑯"},
+	{"16.", "⒗"},
+	{"17", "⑰"},
+	{"17.", "⒘"},
+	{"18", "⑱"},
+	{"18.", "⒙"},
+	{"19", "⑲"},
+	{"19.", "⒚"},
+	{"2", "²₂②２"},
+	{"2.", "⒉"},
+	{"2/3", "⅔"},
+	{"2/5", "⅖"},
+	{"20", "⑳"},
+	{"20.", "⒛"},
+	{"3", "³₃③３"},
+	{"3.", "⒊"},
+	{"3/4", "¾"},
+	{"3/5", "⅗"},
+	{"3/8", "⅜"},
+	{"4", "⁴₄④４"},
+	{"4.", "⒋"},
+	{"4/5", "⅘"},
+	{"5", "⁵₅⑤５"},
+	{"5.", "⒌"},
+	{"5/6", "⅚"},
+	{"5/8", "⅝"},
+	{"6", "⁶₆⑥６"},
+	{"6.", "⒍"},
+	{"7", "⁷₇⑦７"},
+	{"7.", "⒎"},
+	{"7/8", "⅞"},
+	{"8", "⁸₈⑧８"},
+	{"8.", "⒏"},
+	{"9", "⁹₉⑨９"},
+	{"9.", "⒐"},
+	{":", "："},
+	{";", "⁏；"},
+	{"<", "‹＜"},
+	{"=", "⁼₌＝"},
+	{">", "›＞"},
+	{"?", "¿？"},
+	{"?!", "⁈"},
+	{"??", "⁇"},
+	{"@", "＠"},
+	{"A", "ÀÁÂÃÄÅĀĂĄǍǞǠǺȀȂȦȺᴬḀẠẢẤẦẨẪẬẮẰẲẴẶÅⒶＡ"},
+	{"AE", "ÆǢǼᴭ"},
+	{"B", "ƁɃᴮḂḄḆℬⒷＢ"},
+	{"C", "ÇĆĈĊČƇȻḈℂℭⅭⒸꟲＣ"},
+	{"D", "ÐĎĐƊƋᴰḊḌḎḐḒⅅⅮⒹＤ"},
+	{"DZ", "ǄǱ"},
+	{"Dz", "ǅǲ"},
+	{"E", "ÈÉÊËĒĔĖĘĚȄȆȨɆᴱḔḖḘḚḜẸẺẼẾỀỂỄỆℰⒺＥ"},
+	{"F", "ƑḞℱⒻꟳＦ"},
+	{"FAX", "℻"},
+	{"G", "ĜĞĠĢƓǦǴᴳḠⒼＧ"},
+	{"H", "ĤĦȞᴴḢḤḦḨḪℋℌℍⒽꟸＨ"},
+	{"HV", "Ƕ"},
+	{"I", "ÌÍÎÏĨĪĬĮİƗǏȈȊᴵḬḮỈỊℐℑⅠⒾＩ"},
+	{"II", "Ⅱ"},
+	{"III", "Ⅲ"},
+	{"IJ", "Ĳ"},
+	{"IV", "Ⅳ"},
+	{"IX", "Ⅸ"},
+	{"J", "ĴɈᴶⒿＪ"},
+	{"K", "ĶƘǨᴷḰḲḴKⓀＫ"},
+	{"L", "ĹĻĽŁȽᴸḶḸḺḼℒⅬⓁＬ"},
+	{"LJ", "Ǉ"},
+	{"Lj", "ǈ"},
+	{"M", "ᴹḾṀṂℳⅯⓂＭ"},
+	{"N", "ÑŃŅŇŊƝǸᴺṄṆṈṊℕⓃＮ"},
+	{"NJ", "Ǌ"},
+	{"Nj", "ǋ"},
+	{"No", "№"},
+	{"O", "ÒÓÔÕÖØŌŎŐƠǑǪǬǾȌȎȪȬȮȰᴼṌṎṐṒỌỎỐỒỔỖỘỚỜỞỠỢⓄＯ"},
+	{"OE", "Œ"},
+	{"OU", "Ȣᴽ"},
+	{"P", "ƤᴾṔṖℙⓅＰ"},
+	{"Q", "ℚⓆꟴＱ"},
+	{"R", "ŔŖŘȐȒɌᴿṘṚṜṞℛℜℝⓇＲ"},
+	{"S", "ŚŜŞŠȘṠṢṤṦṨⓈＳ"},
+	{"SM", "℠"},
+	{"T", "ŢŤŦƬƮȚȾᵀṪṬṮṰⓉＴ"},
+	{"TEL", "℡"},
+	{"TH", "Þ"},
+	{"TM", "™"},
+	{"U", "ÙÚÛÜŨŪŬŮŰŲƯǓǕǗǙǛȔȖᵁṲṴṶṸṺỤỦỨỪỬỮỰⓊＵ"},
+	{"V", "ƲṼṾⅤⓋⱽＶ"},
+	{"VI", "Ⅵ"},
+	{"VII", "Ⅶ"},
+	{"VIII", "Ⅷ"},
+	{"W", "ŴᵂẀẂẄẆẈⓌＷ"},
+	{"X", "ẊẌⅩⓍＸ"},
+	{"XI", "Ⅺ"},
+	{"XII", "Ⅻ"},
+	{"Y", "ÝŶŸƳȲɎẎỲỴỶỸⓎＹ"},
+	{"Z", "ŹŻŽƵȤẐẒẔℤℨⓏＺ"},
+	{"[", "［"},
+	{"\\", "＼"},
+	{"]", "］"},
+	{"^", "‸＾"},
+	{"_", "‗＿"},
+	{"`", "｀"},
+	{"a", "ªàáâãäåāăąǎǟǡǻȁȃȧᵃḁạảấầẩẫậắằẳẵặₐⓐａ"},
+	{"a/c", "℀"},
+	{"a/s", "℁"},
+	{"ae", "æǣǽ"},
+	{"b", "ƀɓᵇḃḅḇⓑｂ"},
+	{"c", "çćĉċčƈȼɕᶜᶝḉⅽⓒｃ"},
+	{"c/o", "℅"},
+	{"c/u", "℆"},
+	{"d", "ðďđƌɖɗᵈᶞḋḍḏḑḓⅆⅾⓓｄ"},
+	{"dz", "ǆǳ"},
+	{"e", "èéêëēĕėęěȅȇȩɇᵉḕḗḙḛḝẹẻẽếềểễệₑℯⅇⓔｅ"},
+	{"f", "ƒᶠḟⓕｆ"},
+	{"ff", "ﬀ"},
+	{"ffi", "ﬃ"},
+	{"ffl", "ﬄ"},
+	{"fi", "ﬁ"},
+	{"fl", "ﬂ"},
+	{"g", "ĝğġģǧǵɠᵍḡℊⓖｇ"},
+	{"h", "ĥħȟɦḣḥḧḩḫẖₕℎℏⓗｈ"},
+	{"hv", "ƕ"},
+	{"i", "ìíîïĩīĭįıǐȉȋɨᵢᶤḭḯỉịⁱℹⅈⅰⓘｉ"},
+	{"ii", "ⅱ"},
+	{"iii", "ⅲ"},
+	{"ij", "ĳ"},
+	{"iv", "ⅳ"},
+	{"ix", "ⅸ"},
+	{"j", "ĵǰȷɉⅉⓙⱼｊ"},
+	{"k", "ķƙǩᵏḱḳḵₖⓚｋ"},
+	{"l", "ĺļľłƚȴɫɬɭᶩḷḹḻḽₗℓⅼⓛｌ"},
+	{"lj", "ǉ"},
+	{"m", "ɱᵐᶬḿṁṃₘⅿⓜｍ"},
+	{"n", "ñńņňŋƞǹȵɲɳᵑᶮᶯṅṇṉṋⁿₙⓝｎ"},
+	{"nj", "ǌ"},
+	{"o", "ºòóôõöøōŏőơǒǫǭǿȍȏȫȭȯȱᵒṍṏṑṓọỏốồổỗộớờởỡợₒℴⓞｏ"},
+	{"oe", "œꟹ"},
+	{"ou", "ȣ"},
+	{"p", "ƥᵖṕṗₚⓟｐ"},
+	{"q", "ĸⓠｑ"},
+	{"r", "ŕŗřȑȓɍɼɽɾᵣṙṛṝṟⓡｒ"},
+	{"s", "śŝşšſșȿʂᶳṡṣṥṧṩẛₛⓢｓ"},
+	{"ss", "ß"},
+	{"st", "ﬅﬆ"},
+	{"t", "ţťŧƭțȶʈᵗṫṭṯṱẗₜⓣｔ"},
+	{"th", "þ"},
+	{"u", "ùúûüũūŭůűųưǔǖǘǚǜȕȗᵘᵤṳṵṷṹṻụủứừửữựⓤｕ"},
+	{"v", "ᵛᵥṽṿⅴⓥｖ"},
+	{"vi", "ⅵ"},
+	{"vii", "ⅶ"},
+	{"viii", "ⅷ"},
+	{"w", "ŵẁẃẅẇẉẘⓦｗ"},
+	{"x", "×ẋẍₓⅹⓧｘ"},
+	{"xi", "ⅺ"},
+	{"xii", "ⅻ"},
+	{"y", "ýÿŷƴȳɏẏẙỳỵỷỹⓨｙ"},
+	{"z", "źżžƶȥɀʐʑᶻᶼᶽẑẓẕⓩｚ"},
+	{"{", "｛"},
+	{"|", "｜"},
+	{"}", "｝"},
+	{"~", "⁓～"},
+}
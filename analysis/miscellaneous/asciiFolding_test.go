@@ -0,0 +1,62 @@
+package miscellaneous
+
+import (
+	"fmt"
+	std "github.com/balzaczyy/golucene/analysis/standard"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"github.com/balzaczyy/golucene/core/util"
+	"strings"
+	"testing"
+)
+
+func TestFoldToASCII(t *testing.T) {
+	for input, expected := range map[string]string{
+		"Des mot clés À LA CHAÎNE": "Des mot cles A LA CHAINE",
+		"Ærøskøbing Straße":        "AEroskobing Strasse",
+		"Łódź ĳssel":               "Lodz ijssel",
+		"Việt Nam ﬁnance":          "Viet Nam finance",
+		"“quoted” – ½ ①":           "\"quoted\" - 1/2 1",
+		"ＡＢＣ１２３":                   "ABC123",
+		"日本語":                      "日本語",
+	} {
+		if got := string(FoldToASCII([]rune(input))); got != expected {
+			t.Errorf("%q: expected %q, but was %q", input, expected, got)
+		}
+	}
+}
+
+func TestASCIIFoldingFilter(t *testing.T) {
+	for _, v := range []struct {
+		preserveOriginal bool
+		expected         string
+	}{
+		{false, "Des/1 mot/1 cles/1 A/1 la/1 CHAINE/1"},
+		{true, "Des/1 mot/1 cles/1 clés/0 A/1 À/0 la/1 CHAINE/1 CHAÎNE/0"},
+	} {
+		ts := NewASCIIFoldingFilterWithPreserveOriginal(
+			std.NewStandardTokenizer(util.VERSION_LATEST, strings.NewReader("Des mot clés À la CHAÎNE")),
+			v.preserveOriginal)
+		termAtt := ts.Attributes().Get("CharTermAttribute").(CharTermAttribute)
+		posIncAtt := ts.Attributes().Get("PositionIncrementAttribute").(PositionIncrementAttribute)
+		if err := ts.Reset(); err != nil {
+			t.Fatal(err)
+		}
+		var tokens []string
+		for {
+			ok, err := ts.IncrementToken()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !ok {
+				break
+			}
+			tokens = append(tokens, fmt.Sprintf("%v/%v",
+				string(termAtt.Buffer()[:termAtt.Length()]), posIncAtt.PositionIncrement()))
+		}
+		ts.End()
+		ts.Close()
+		if got := strings.Join(tokens, " "); got != v.expected {
+			t.Errorf("preserveOriginal=%v: expected %v, but was %v", v.preserveOriginal, v.expected, got)
+		}
+	}
+}
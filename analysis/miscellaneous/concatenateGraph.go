@@ -0,0 +1,217 @@
+package miscellaneous
+
+import (
+	"bytes"
+	"errors"
+	. "github.com/balzaczyy/golucene/core/analysis"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"github.com/balzaczyy/golucene/core/util/automaton"
+)
+
+// miscellaneous/ConcatenateGraphFilter.java
+
+const (
+	// Represents the separation between tokens, if preserveSep is true.
+	SEP_LABEL = POS_SEP
+
+	DEFAULT_MAX_GRAPH_EXPANSIONS         = 10000
+	DEFAULT_PRESERVE_SEP                 = true
+	DEFAULT_PRESERVE_POSITION_INCREMENTS = true
+)
+
+/*
+Concatenates/Joins every incoming token with a separator into one
+output token for every path through the token stream (which is a
+graph). In simple cases this yields one token, but in the presence of
+any tokens with a zero positionIncrement (e.g. synonyms) it will be
+more. This filter uses the token bytes, position increment, and
+position length of the incoming stream. Other attributes are not
+used or manipulated.
+
+It consumes its input entirely on the first IncrementToken(), so it
+has its own attributes rather than sharing those of the input. The
+output tokens all span the whole input; every token after the first
+is stacked on it with a position increment of zero. The separator,
+SEP_LABEL, is escaped by doubling it when it occurs in a token.
+*/
+type ConcatenateGraphFilter struct {
+	*TokenStreamImpl
+	input                      TokenStream
+	preserveSep                bool
+	preservePositionIncrements bool
+	maxGraphExpansions         int
+
+	termAtt   CharTermAttribute
+	posIncAtt PositionIncrementAttribute
+	offsetAtt OffsetAttribute
+
+	finiteStrings [][]byte
+	upto          int
+	wasReset      bool
+	endOffset     int
+}
+
+/*
+Creates a ConcatenateGraphFilter with the default separator handling,
+position increments and maximum number of graph expansions.
+*/
+func NewConcatenateGraphFilter(input TokenStream) *ConcatenateGraphFilter {
+	return NewConcatenateGraphFilterWith(input, DEFAULT_PRESERVE_SEP,
+		DEFAULT_PRESERVE_POSITION_INCREMENTS, DEFAULT_MAX_GRAPH_EXPANSIONS)
+}
+
+/*
+Creates a ConcatenateGraphFilter. If preserveSep is false, the tokens
+are joined without a separator. If preservePositionIncrements is
+false, holes left by removed tokens are ignored. At most
+maxGraphExpansions paths of the graph are output.
+*/
+func NewConcatenateGraphFilterWith(input TokenStream, preserveSep, preservePositionIncrements bool,
+	maxGraphExpansions int) *ConcatenateGraphFilter {
+
+	ans := &ConcatenateGraphFilter{
+		TokenStreamImpl:            NewTokenStream(),
+		input:                      input,
+		preserveSep:                preserveSep,
+		preservePositionIncrements: preservePositionIncrements,
+		maxGraphExpansions:         maxGraphExpansions,
+	}
+	ans.termAtt = ans.Attributes().Add("CharTermAttribute").(CharTermAttribute)
+	ans.posIncAtt = ans.Attributes().Add("PositionIncrementAttribute").(PositionIncrementAttribute)
+	ans.offsetAtt = ans.Attributes().Add("OffsetAttribute").(OffsetAttribute)
+	return ans
+}
+
+func (f *ConcatenateGraphFilter) Reset() error {
+	f.wasReset = true
+	return f.TokenStreamImpl.Reset()
+}
+
+func (f *ConcatenateGraphFilter) IncrementToken() (bool, error) {
+	if f.finiteStrings == nil {
+		if !f.wasReset {
+			return false, errors.New("Reset() missing before IncrementToken()")
+		}
+		// lazy init/consume
+		a, err := f.ToAutomaton() // calls Reset(), IncrementToken() repeatedly, and End() on the input
+		if err != nil {
+			return false, err
+		}
+		f.finiteStrings = toFiniteStrings(a, f.maxGraphExpansions)
+		// we don't know the start offset, as ToAutomaton() doesn't capture it
+		f.endOffset = f.input.Attributes().Add("OffsetAttribute").(OffsetAttribute).EndOffset()
+	}
+	if f.upto >= len(f.finiteStrings) {
+		return false, nil
+	}
+
+	f.Attributes().Clear()
+	if f.upto > 0 {
+		f.posIncAtt.SetPositionIncrement(0) // stacked
+	}
+	f.offsetAtt.SetOffset(0, f.endOffset)
+	f.termAtt.CopyBuffer([]rune(string(f.finiteStrings[f.upto])))
+	f.upto++
+	return true, nil
+}
+
+func (f *ConcatenateGraphFilter) End() error {
+	if err := f.TokenStreamImpl.End(); err != nil {
+		return err
+	}
+	if f.finiteStrings == nil {
+		// we didn't start, so the input was not ended by ToAutomaton()
+		return f.input.End()
+	}
+	return nil
+}
+
+func (f *ConcatenateGraphFilter) Close() error {
+	f.finiteStrings, f.upto = nil, 0
+	f.wasReset = false
+	return f.input.Close()
+}
+
+/*
+Converts the input TokenStream to an automaton, whose transition
+labels are the UTF-8 bytes of the tokens, joined with SEP_LABEL if
+preserveSep is true. It consumes the input entirely.
+*/
+func (f *ConcatenateGraphFilter) ToAutomaton() (*automaton.Automaton, error) {
+	// Create corresponding automaton: labels are bytes from each
+	// analyzed token, with SEP_LABEL used as separator between tokens:
+	ts2a := NewTokenStreamToAutomaton()
+	if f.preserveSep {
+		// When we're not preserving sep, we don't steal the SEP_LABEL
+		// byte, so we don't need to do any escaping:
+		ts2a.SetChangeToken(escapeSep)
+	}
+	ts2a.SetPreservePositionIncrements(f.preservePositionIncrements)
+	a, err := ts2a.ToAutomaton(f.input)
+	if err != nil {
+		return nil, err
+	}
+	return automaton.Determinize(replaceSep(a, f.preserveSep)), nil
+}
+
+/* Escapes the SEP_LABEL byte so that it can't be confused with a token separator. */
+func escapeSep(in []byte) []byte {
+	if bytes.IndexByte(in, SEP_LABEL) < 0 {
+		return in
+	}
+	var out []byte
+	for _, b := range in {
+		if b == SEP_LABEL {
+			out = append(out, SEP_LABEL)
+		}
+		out = append(out, b)
+	}
+	return out
+}
+
+/* Keeps POS_SEP as SEP_LABEL, or folds it away if !preserveSep, and removes holes. */
+func replaceSep(a *automaton.Automaton, preserveSep bool) *automaton.Automaton {
+	result := automaton.NewAutomatonBuilder()
+
+	// Copy all states over
+	result.CopyStates(a)
+
+	// Go in reverse topo sort so we know we only have to make one
+	// pass:
+	t := automaton.NewTransition()
+	topoSortStates := automaton.TopoSortStates(a)
+	for i := len(topoSortStates) - 1; i >= 0; i-- {
+		state := topoSortStates[i]
+		count := a.InitTransition(state, t)
+		for j := 0; j < count; j++ {
+			a.NextTransition(t)
+			switch t.Min {
+			case POS_SEP:
+				if preserveSep {
+					result.AddTransition(state, t.Dest, SEP_LABEL)
+				} else {
+					result.AddEpsilon(state, t.Dest)
+				}
+			case HOLE:
+				// Just remove the hole: there will then be two SEP tokens
+				// next to each other, which will only match another hole.
+				result.AddEpsilon(state, t.Dest)
+			default:
+				result.AddTransitionRange(state, t.Dest, t.Min, t.Max)
+			}
+		}
+	}
+	return result.Finish()
+}
+
+func toFiniteStrings(a *automaton.Automaton, limit int) [][]byte {
+	paths := automaton.FiniteStrings(a, limit)
+	ans := make([][]byte, len(paths))
+	for i, path := range paths {
+		ans[i] = make([]byte, len(path))
+		for j, label := range path {
+			ans[i][j] = byte(label)
+		}
+	}
+	return ans
+}
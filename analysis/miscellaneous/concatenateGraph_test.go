@@ -0,0 +1,70 @@
+package miscellaneous
+
+import (
+	"github.com/balzaczyy/golucene/analysis/core"
+	"github.com/balzaczyy/golucene/analysis/synonym"
+	"github.com/balzaczyy/golucene/core/util"
+	"strings"
+	"testing"
+)
+
+/* Makes the separator readable. */
+func showSep(s string) string {
+	return strings.Replace(s, string(rune(SEP_LABEL)), "_", -1)
+}
+
+func TestConcatenateGraphFilter(t *testing.T) {
+	ts := NewConcatenateGraphFilter(tokenizer("mykeyword another"))
+	if got, expected := showSep(termsWithPosInc(t, ts)), "mykeyword_another/1"; got != expected {
+		t.Errorf("expected %v, but was %v", expected, got)
+	}
+
+	ts = NewConcatenateGraphFilterWith(tokenizer("mykeyword another"), false, true, DEFAULT_MAX_GRAPH_EXPANSIONS)
+	if got, expected := termsWithPosInc(t, ts), "mykeywordanother/1"; got != expected {
+		t.Errorf("expected %v, but was %v", expected, got)
+	}
+}
+
+func TestConcatenateGraphFilterWithSynonyms(t *testing.T) {
+	b := synonym.NewSynonymMapBuilder(true)
+	b.Add("mykeyword", "mysynonym", true)
+	synonyms, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := NewConcatenateGraphFilter(synonym.NewSynonymGraphFilter(tokenizer("mykeyword another"), synonyms, false))
+	got := showSep(termsWithPosInc(t, ts))
+	if expected := "mykeyword_another/1 mysynonym_another/0"; got != expected {
+		t.Errorf("expected %v, but was %v", expected, got)
+	}
+}
+
+func TestConcatenateGraphFilterHoles(t *testing.T) {
+	stopWords := map[string]bool{"the": true}
+	for _, c := range []struct {
+		preservePositionIncrements bool
+		expected                   string
+	}{
+		{true, "a__b/1"},
+		{false, "a_b/1"},
+	} {
+		ts := NewConcatenateGraphFilterWith(core.NewStopFilter(util.VERSION_LATEST, tokenizer("a the b"), stopWords),
+			true, c.preservePositionIncrements, DEFAULT_MAX_GRAPH_EXPANSIONS)
+		if got := showSep(termsWithPosInc(t, ts)); got != c.expected {
+			t.Errorf("expected %v, but was %v", c.expected, got)
+		}
+	}
+}
+
+func TestConcatenateGraphFilterFactory(t *testing.T) {
+	if _, err := NewConcatenateGraphFilterFactory(map[string]string{"maxGraphExpansions": "0"}); err == nil {
+		t.Error("expected an error for a non-positive maxGraphExpansions")
+	}
+	f, err := NewConcatenateGraphFilterFactory(map[string]string{"preserveSep": "false"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, expected := termsWithPosInc(t, f.Create(tokenizer("a b"))), "ab/1"; got != expected {
+		t.Errorf("expected %v, but was %v", expected, got)
+	}
+}
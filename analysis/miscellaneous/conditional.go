@@ -0,0 +1,275 @@
+package miscellaneous
+
+import (
+	. "github.com/balzaczyy/golucene/core/analysis"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"github.com/balzaczyy/golucene/core/util"
+)
+
+// miscellaneous/ConditionalTokenFilter.java
+
+type ConditionalTokenFilterSPI interface {
+	// Whether or not to execute the wrapped TokenFilter(s) for the
+	// current token.
+	ShouldFilter() (bool, error)
+}
+
+type conditionalTokenState int
+
+const (
+	conditionalReading conditionalTokenState = iota
+	conditionalPrebuffering
+	conditionalDelegating
+)
+
+/*
+Allows skipping TokenFilters based on the current set of attributes.
+
+To use, implement ShouldFilter() to return true when the wrapped
+TokenFilters should be applied to the current token, and pass a
+function creating the wrapped filter chain from its input. The
+wrapped filters see only the tokens to filter; the other tokens skip
+them unchanged.
+*/
+type ConditionalTokenFilter struct {
+	*TokenFilter
+	spi      ConditionalTokenFilterSPI
+	input    TokenStream
+	delegate TokenStream
+
+	state             conditionalTokenState
+	lastTokenFiltered bool
+	bufferedState     *util.AttributeState
+	exhausted         bool
+	adjustPosition    bool
+	endState          *util.AttributeState
+	endOffset         int
+
+	posIncAtt PositionIncrementAttribute
+	offsetAtt OffsetAttribute
+}
+
+/*
+Create a new ConditionalTokenFilter. The inputFactory creates the
+wrapped filter chain from the TokenStream of the tokens to filter.
+*/
+func NewConditionalTokenFilter(spi ConditionalTokenFilterSPI, input TokenStream,
+	inputFactory func(TokenStream) TokenStream) *ConditionalTokenFilter {
+
+	ans := &ConditionalTokenFilter{
+		TokenFilter: NewTokenFilter(input),
+		spi:         spi,
+		input:       input,
+	}
+	ans.delegate = inputFactory(newOneTimeWrapper(ans))
+	ans.posIncAtt = ans.Attributes().Add("PositionIncrementAttribute").(PositionIncrementAttribute)
+	ans.offsetAtt = ans.Attributes().Add("OffsetAttribute").(OffsetAttribute)
+	return ans
+}
+
+/*
+Feeds the wrapped filters with one token to filter at a time, sharing
+the attributes of the input.
+*/
+type oneTimeWrapper struct {
+	*TokenStreamImpl
+	f         *ConditionalTokenFilter
+	offsetAtt OffsetAttribute
+	posIncAtt PositionIncrementAttribute
+}
+
+func newOneTimeWrapper(f *ConditionalTokenFilter) *oneTimeWrapper {
+	ans := &oneTimeWrapper{
+		TokenStreamImpl: NewTokenStreamWith(f.input.Attributes()),
+		f:               f,
+	}
+	ans.offsetAtt = ans.Attributes().Add("OffsetAttribute").(OffsetAttribute)
+	ans.posIncAtt = ans.Attributes().Add("PositionIncrementAttribute").(PositionIncrementAttribute)
+	return ans
+}
+
+func (w *oneTimeWrapper) IncrementToken() (bool, error) {
+	f := w.f
+	if f.state == conditionalPrebuffering {
+		if w.posIncAtt.PositionIncrement() == 0 {
+			f.adjustPosition = true
+			w.posIncAtt.SetPositionIncrement(1)
+		}
+		f.state = conditionalDelegating
+		return true, nil
+	}
+	assert(f.state == conditionalDelegating)
+	ok, err := f.input.IncrementToken()
+	if err != nil {
+		return false, err
+	}
+	if ok {
+		if ok, err = f.spi.ShouldFilter(); ok || err != nil {
+			return ok, err
+		}
+		f.endOffset = w.offsetAtt.EndOffset()
+		f.bufferedState = w.Attributes().CaptureState()
+	} else {
+		f.exhausted = true
+	}
+	return false, nil
+}
+
+/* Clearing attributes etc is done by the parent stream, so it must be avoided here. */
+func (w *oneTimeWrapper) Reset() error {
+	return nil
+}
+
+/* Imitates Tokenizer.End(): clears the attributes and sets the final offset. */
+func (w *oneTimeWrapper) End() error {
+	f := w.f
+	if f.exhausted {
+		if f.endState == nil {
+			if err := f.input.End(); err != nil {
+				return err
+			}
+			f.endState = w.Attributes().CaptureState()
+		}
+		f.endOffset = w.offsetAtt.EndOffset()
+	}
+	if err := w.TokenStreamImpl.End(); err != nil {
+		return err
+	}
+	w.offsetAtt.SetOffset(f.endOffset, f.endOffset)
+	return nil
+}
+
+func (f *ConditionalTokenFilter) Reset() error {
+	if err := f.TokenFilter.Reset(); err != nil {
+		return err
+	}
+	if err := f.delegate.Reset(); err != nil {
+		return err
+	}
+	f.state = conditionalReading
+	f.lastTokenFiltered = false
+	f.bufferedState = nil
+	f.exhausted = false
+	f.adjustPosition = false
+	f.endOffset = -1
+	f.endState = nil
+	return nil
+}
+
+func (f *ConditionalTokenFilter) End() error {
+	if f.endState == nil {
+		if err := f.TokenFilter.End(); err != nil {
+			return err
+		}
+		f.endState = f.Attributes().CaptureState()
+	} else {
+		f.Attributes().RestoreState(f.endState)
+	}
+	f.endOffset = f.offsetAtt.EndOffset()
+	if f.lastTokenFiltered {
+		if err := f.delegate.End(); err != nil {
+			return err
+		}
+		f.endState = f.Attributes().CaptureState()
+	}
+	f.Attributes().Clear()
+	f.Attributes().RestoreState(f.endState)
+	return nil
+}
+
+func (f *ConditionalTokenFilter) Close() error {
+	if err := f.TokenFilter.Close(); err != nil {
+		return err
+	}
+	return f.delegate.Close()
+}
+
+func (f *ConditionalTokenFilter) IncrementToken() (bool, error) {
+	f.lastTokenFiltered = false
+	for {
+		switch f.state {
+		case conditionalReading:
+			if f.bufferedState != nil {
+				f.Attributes().RestoreState(f.bufferedState)
+				f.bufferedState = nil
+				f.lastTokenFiltered = false
+				return true, nil
+			}
+			if f.exhausted {
+				return false, nil
+			}
+			ok, err := f.input.IncrementToken()
+			if err != nil {
+				return false, err
+			}
+			if !ok {
+				f.exhausted = true
+				return false, nil
+			}
+			if ok, err = f.spi.ShouldFilter(); err != nil {
+				return false, err
+			} else if !ok {
+				return true, nil
+			}
+			f.lastTokenFiltered = true
+			f.state = conditionalPrebuffering
+			// we determine that the delegate has emitted all the tokens
+			// it can at the current position when the wrapper is called
+			// in the delegating state. To signal this back to the
+			// delegate, the wrapper returns false, so we now need to
+			// reset it to ensure that it can continue to emit more tokens
+			if err = f.delegate.Reset(); err != nil {
+				return false, err
+			}
+			if ok, err = f.delegate.IncrementToken(); err != nil {
+				return false, err
+			} else if !ok {
+				f.lastTokenFiltered = false
+				f.state = conditionalReading
+				return f.endDelegating()
+			}
+			f.state = conditionalDelegating
+			if f.adjustPosition {
+				f.posIncAtt.SetPositionIncrement(f.posIncAtt.PositionIncrement() - 1)
+			}
+			f.adjustPosition = false
+			return true, nil
+
+		case conditionalDelegating:
+			f.lastTokenFiltered = true
+			ok, err := f.delegate.IncrementToken()
+			if ok || err != nil {
+				return ok, err
+			}
+			// no more cached tokens
+			f.lastTokenFiltered = false
+			f.state = conditionalReading
+			return f.endDelegating()
+		}
+	}
+}
+
+func (f *ConditionalTokenFilter) endDelegating() (bool, error) {
+	if f.bufferedState == nil {
+		assert(f.exhausted)
+		return false, nil
+	}
+	if err := f.delegate.End(); err != nil {
+		return false, err
+	}
+	posInc := f.posIncAtt.PositionIncrement()
+	f.Attributes().RestoreState(f.bufferedState)
+	f.posIncAtt.SetPositionIncrement(f.posIncAtt.PositionIncrement() + posInc)
+	if f.adjustPosition {
+		f.posIncAtt.SetPositionIncrement(f.posIncAtt.PositionIncrement() - 1)
+		f.adjustPosition = false
+	}
+	f.bufferedState = nil
+	return true, nil
+}
+
+func assert(ok bool) {
+	if !ok {
+		panic("assert fail")
+	}
+}
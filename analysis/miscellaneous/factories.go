@@ -0,0 +1,465 @@
+package miscellaneous
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	. "github.com/balzaczyy/golucene/analysis/util"
+	. "github.com/balzaczyy/golucene/core/analysis"
+	"os"
+	"regexp"
+	"strings"
+)
+
+func init() {
+	RegisterTokenFilterFactory("asciiFolding", func(args map[string]string) (TokenFilterFactory, error) {
+		f, err := NewASCIIFoldingFilterFactory(args)
+		if err != nil {
+			return nil, err
+		}
+		return f, nil
+	})
+	RegisterTokenFilterFactory("limitTokenCount", func(args map[string]string) (TokenFilterFactory, error) {
+		f, err := NewLimitTokenCountFilterFactory(args)
+		if err != nil {
+			return nil, err
+		}
+		return f, nil
+	})
+	RegisterTokenFilterFactory("limitTokenPosition", func(args map[string]string) (TokenFilterFactory, error) {
+		f, err := NewLimitTokenPositionFilterFactory(args)
+		if err != nil {
+			return nil, err
+		}
+		return f, nil
+	})
+	RegisterTokenFilterFactory("length", func(args map[string]string) (TokenFilterFactory, error) {
+		f, err := NewLengthFilterFactory(args)
+		if err != nil {
+			return nil, err
+		}
+		return f, nil
+	})
+	RegisterTokenFilterFactory("truncate", func(args map[string]string) (TokenFilterFactory, error) {
+		f, err := NewTruncateTokenFilterFactory(args)
+		if err != nil {
+			return nil, err
+		}
+		return f, nil
+	})
+	RegisterTokenFilterFactory("keywordMarker", func(args map[string]string) (TokenFilterFactory, error) {
+		f, err := NewKeywordMarkerFilterFactory(args)
+		if err != nil {
+			return nil, err
+		}
+		return f, nil
+	})
+	RegisterTokenFilterFactory("stemmerOverride", func(args map[string]string) (TokenFilterFactory, error) {
+		f, err := NewStemmerOverrideFilterFactory(args)
+		if err != nil {
+			return nil, err
+		}
+		return f, nil
+	})
+	RegisterTokenFilterFactory("concatenateGraph", func(args map[string]string) (TokenFilterFactory, error) {
+		f, err := NewConcatenateGraphFilterFactory(args)
+		if err != nil {
+			return nil, err
+		}
+		return f, nil
+	})
+	RegisterTokenFilterFactory("fingerprint", func(args map[string]string) (TokenFilterFactory, error) {
+		f, err := NewFingerprintFilterFactory(args)
+		if err != nil {
+			return nil, err
+		}
+		return f, nil
+	})
+	RegisterTokenFilterFactory("protectedTerm", func(args map[string]string) (TokenFilterFactory, error) {
+		f, err := NewProtectedTermFilterFactory(args)
+		if err != nil {
+			return nil, err
+		}
+		return f, nil
+	})
+	RegisterTokenFilterFactory("typeAsSynonym", func(args map[string]string) (TokenFilterFactory, error) {
+		f, err := NewTypeAsSynonymFilterFactory(args)
+		if err != nil {
+			return nil, err
+		}
+		return f, nil
+	})
+}
+
+// miscellaneous/ASCIIFoldingFilterFactory.java
+
+/* Factory for ASCIIFoldingFilter. */
+type ASCIIFoldingFilterFactory struct {
+	*AbstractAnalysisFactory
+	preserveOriginal bool
+}
+
+func NewASCIIFoldingFilterFactory(args map[string]string) (*ASCIIFoldingFilterFactory, error) {
+	ans := &ASCIIFoldingFilterFactory{AbstractAnalysisFactory: NewAbstractAnalysisFactory(args)}
+	ans.preserveOriginal = ans.GetBool("preserveOriginal", false)
+	if err := ans.Validate(); err != nil {
+		return nil, err
+	}
+	return ans, nil
+}
+
+func (f *ASCIIFoldingFilterFactory) Create(input TokenStream) TokenStream {
+	return NewASCIIFoldingFilterWithPreserveOriginal(input, f.preserveOriginal)
+}
+
+// miscellaneous/LimitTokenCountFilterFactory.java
+
+/*
+Factory for LimitTokenCountFilter, taking the required
+"maxTokenCount" and the optional "consumeAllTokens" (false by
+default).
+*/
+type LimitTokenCountFilterFactory struct {
+	*AbstractAnalysisFactory
+	maxTokenCount    int
+	consumeAllTokens bool
+}
+
+func NewLimitTokenCountFilterFactory(args map[string]string) (*LimitTokenCountFilterFactory, error) {
+	ans := &LimitTokenCountFilterFactory{AbstractAnalysisFactory: NewAbstractAnalysisFactory(args)}
+	ans.maxTokenCount = ans.RequireInt("maxTokenCount")
+	ans.consumeAllTokens = ans.GetBool("consumeAllTokens", false)
+	if err := ans.Validate(); err != nil {
+		return nil, err
+	}
+	if ans.maxTokenCount < 1 {
+		return nil, errors.New("maxTokenCount must be greater than zero")
+	}
+	return ans, nil
+}
+
+func (f *LimitTokenCountFilterFactory) Create(input TokenStream) TokenStream {
+	return NewLimitTokenCountFilterWithConsume(input, f.maxTokenCount, f.consumeAllTokens)
+}
+
+// miscellaneous/LimitTokenPositionFilterFactory.java
+
+/*
+Factory for LimitTokenPositionFilter, taking the required
+"maxTokenPosition" and the optional "consumeAllTokens" (false by
+default).
+*/
+type LimitTokenPositionFilterFactory struct {
+	*AbstractAnalysisFactory
+	maxTokenPosition int
+	consumeAllTokens bool
+}
+
+func NewLimitTokenPositionFilterFactory(args map[string]string) (*LimitTokenPositionFilterFactory, error) {
+	ans := &LimitTokenPositionFilterFactory{AbstractAnalysisFactory: NewAbstractAnalysisFactory(args)}
+	ans.maxTokenPosition = ans.RequireInt("maxTokenPosition")
+	ans.consumeAllTokens = ans.GetBool("consumeAllTokens", false)
+	if err := ans.Validate(); err != nil {
+		return nil, err
+	}
+	if ans.maxTokenPosition < 1 {
+		return nil, errors.New("maxTokenPosition must be greater than zero")
+	}
+	return ans, nil
+}
+
+func (f *LimitTokenPositionFilterFactory) Create(input TokenStream) TokenStream {
+	return NewLimitTokenPositionFilterWithConsume(input, f.maxTokenPosition, f.consumeAllTokens)
+}
+
+// miscellaneous/LengthFilterFactory.java
+
+/* Factory for LengthFilter, taking the required "min" and "max" lengths. */
+type LengthFilterFactory struct {
+	*AbstractAnalysisFactory
+	min, max int
+}
+
+func NewLengthFilterFactory(args map[string]string) (*LengthFilterFactory, error) {
+	ans := &LengthFilterFactory{AbstractAnalysisFactory: NewAbstractAnalysisFactory(args)}
+	ans.min = ans.RequireInt("min")
+	ans.max = ans.RequireInt("max")
+	if err := ans.Validate(); err != nil {
+		return nil, err
+	}
+	if ans.min < 0 {
+		return nil, errors.New("minimum length must be greater than or equal to zero")
+	}
+	if ans.min > ans.max {
+		return nil, errors.New("minimum length must not be greater than maximum length")
+	}
+	return ans, nil
+}
+
+func (f *LengthFilterFactory) Create(input TokenStream) TokenStream {
+	return NewLengthFilter(f.LuceneMatchVersion(), input, f.min, f.max)
+}
+
+// miscellaneous/TruncateTokenFilterFactory.java
+
+/* Factory for TruncateTokenFilter, taking the "prefixLength" (5 by default). */
+type TruncateTokenFilterFactory struct {
+	*AbstractAnalysisFactory
+	prefixLength int
+}
+
+func NewTruncateTokenFilterFactory(args map[string]string) (*TruncateTokenFilterFactory, error) {
+	ans := &TruncateTokenFilterFactory{AbstractAnalysisFactory: NewAbstractAnalysisFactory(args)}
+	ans.prefixLength = ans.GetInt("prefixLength", 5)
+	if err := ans.Validate(); err != nil {
+		return nil, err
+	}
+	if ans.prefixLength < 1 {
+		return nil, fmt.Errorf("prefixLength parameter must be a positive number: %v", ans.prefixLength)
+	}
+	return ans, nil
+}
+
+func (f *TruncateTokenFilterFactory) Create(input TokenStream) TokenStream {
+	return NewTruncateTokenFilter(input, f.prefixLength)
+}
+
+// miscellaneous/KeywordMarkerFilterFactory.java
+
+/*
+Factory for KeywordMarkerFilter. Terms are protected from stemming if
+they are in one of the "protected" word files, or if they match the
+regular expression "pattern". "ignoreCase" applies to the word files.
+*/
+type KeywordMarkerFilterFactory struct {
+	*AbstractAnalysisFactory
+	protectedWords *CharArraySet
+	pattern        *regexp.Regexp
+}
+
+func NewKeywordMarkerFilterFactory(args map[string]string) (*KeywordMarkerFilterFactory, error) {
+	ans := &KeywordMarkerFilterFactory{AbstractAnalysisFactory: NewAbstractAnalysisFactory(args)}
+	ignoreCase := ans.GetBool("ignoreCase", false)
+	ans.protectedWords = ans.GetWordSet("protected", "wordset", ignoreCase)
+	if _, ok := args["pattern"]; ok {
+		ans.pattern = ans.RequirePattern("pattern")
+	}
+	if err := ans.Validate(); err != nil {
+		return nil, err
+	}
+	return ans, nil
+}
+
+func (f *KeywordMarkerFilterFactory) Create(input TokenStream) TokenStream {
+	if f.pattern != nil {
+		input = NewPatternKeywordMarkerFilter(input, f.pattern)
+	}
+	if f.protectedWords != nil {
+		input = NewSetKeywordMarkerFilterWithSet(input, f.protectedWords)
+	}
+	return input
+}
+
+// miscellaneous/ProtectedTermFilterFactory.java
+
+/*
+Factory for ProtectedTermFilter. The terms in the "protected" word
+files skip the filters listed in "wrappedFilters", a comma-separated
+list of registered token filter names. "ignoreCase" applies to the
+word files. Parameters of the wrapped filters are prefixed with the
+filter name and a dot, like "truncate.prefixLength".
+*/
+type ProtectedTermFilterFactory struct {
+	*AbstractAnalysisFactory
+	protectedTerms *CharArraySet
+	filters        []TokenFilterFactory
+}
+
+func NewProtectedTermFilterFactory(args map[string]string) (*ProtectedTermFilterFactory, error) {
+	ans := &ProtectedTermFilterFactory{AbstractAnalysisFactory: NewAbstractAnalysisFactory(args)}
+	ignoreCase := ans.GetBool("ignoreCase", false)
+	ans.protectedTerms = ans.GetWordSet("protected", "wordset", ignoreCase)
+	if ans.protectedTerms == nil {
+		ans.Require("protected")
+	}
+	names := ans.GetSet("wrappedFilters")
+	filterArgs := make(map[string]map[string]string)
+	for _, name := range names {
+		filterArgs[strings.ToLower(name)] = make(map[string]string)
+	}
+	for key := range args {
+		if i := strings.Index(key, "."); i > 0 {
+			if fargs, ok := filterArgs[strings.ToLower(key[:i])]; ok {
+				fargs[key[i+1:]] = ans.Get(key, "")
+			}
+		}
+	}
+	if err := ans.Validate(); err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return nil, errors.New("Configuration Error: missing parameter 'wrappedFilters'")
+	}
+	for _, name := range names {
+		f, err := NewTokenFilterFactory(name, filterArgs[strings.ToLower(name)])
+		if err != nil {
+			return nil, fmt.Errorf("wrapped filter '%v': %v", name, err)
+		}
+		ans.filters = append(ans.filters, f)
+	}
+	return ans, nil
+}
+
+func (f *ProtectedTermFilterFactory) Create(input TokenStream) TokenStream {
+	return NewProtectedTermFilter(f.protectedTerms, input, func(in TokenStream) TokenStream {
+		for _, filter := range f.filters {
+			in = filter.Create(in)
+		}
+		return in
+	})
+}
+
+// miscellaneous/StemmerOverrideFilterFactory.java
+
+/*
+Factory for StemmerOverrideFilter. The "dictionary" parameter is a
+comma-separated list of files holding one tab-separated "word stem"
+pair per line, and "ignoreCase" matches the words regardless of case.
+Lines starting with "#" are ignored.
+*/
+type StemmerOverrideFilterFactory struct {
+	*AbstractAnalysisFactory
+	dictionary *StemmerOverrideMap
+}
+
+func NewStemmerOverrideFilterFactory(args map[string]string) (*StemmerOverrideFilterFactory, error) {
+	ans := &StemmerOverrideFilterFactory{AbstractAnalysisFactory: NewAbstractAnalysisFactory(args)}
+	files := ans.GetSet("dictionary")
+	ignoreCase := ans.GetBool("ignoreCase", false)
+	if err := ans.Validate(); err != nil {
+		return nil, err
+	}
+	builder := NewStemmerOverrideMapBuilder(ignoreCase)
+	for _, file := range files {
+		if err := parseStemmerOverrides(file, builder); err != nil {
+			return nil, err
+		}
+	}
+	var err error
+	if ans.dictionary, err = builder.Build(); err != nil {
+		return nil, err
+	}
+	return ans, nil
+}
+
+func parseStemmerOverrides(file string, builder *StemmerOverrideMapBuilder) error {
+	in, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		mapping := strings.SplitN(line, "\t", 2)
+		if len(mapping) != 2 {
+			return fmt.Errorf("Invalid stemmer override : [%v], file = %v", line, file)
+		}
+		builder.Add(mapping[0], mapping[1])
+	}
+	return scanner.Err()
+}
+
+func (f *StemmerOverrideFilterFactory) Create(input TokenStream) TokenStream {
+	return NewStemmerOverrideFilter(input, f.dictionary)
+}
+
+// miscellaneous/TypeAsSynonymFilterFactory.java
+
+/* Factory for TypeAsSynonymFilter, with the optional "prefix" of the synonyms. */
+type TypeAsSynonymFilterFactory struct {
+	*AbstractAnalysisFactory
+	prefix string
+}
+
+func NewTypeAsSynonymFilterFactory(args map[string]string) (*TypeAsSynonymFilterFactory, error) {
+	ans := &TypeAsSynonymFilterFactory{AbstractAnalysisFactory: NewAbstractAnalysisFactory(args)}
+	ans.prefix = ans.Get("prefix", "")
+	if err := ans.Validate(); err != nil {
+		return nil, err
+	}
+	return ans, nil
+}
+
+func (f *TypeAsSynonymFilterFactory) Create(input TokenStream) TokenStream {
+	return NewTypeAsSynonymFilterWithPrefix(input, f.prefix)
+}
+
+// miscellaneous/ConcatenateGraphFilterFactory.java
+
+/*
+Factory for ConcatenateGraphFilter, with the optional "preserveSep"
+and "preservePositionIncrements" (both true by default), and
+"maxGraphExpansions" (10000 by default).
+*/
+type ConcatenateGraphFilterFactory struct {
+	*AbstractAnalysisFactory
+	preserveSep                bool
+	preservePositionIncrements bool
+	maxGraphExpansions         int
+}
+
+func NewConcatenateGraphFilterFactory(args map[string]string) (*ConcatenateGraphFilterFactory, error) {
+	ans := &ConcatenateGraphFilterFactory{AbstractAnalysisFactory: NewAbstractAnalysisFactory(args)}
+	ans.preserveSep = ans.GetBool("preserveSep", DEFAULT_PRESERVE_SEP)
+	ans.preservePositionIncrements = ans.GetBool("preservePositionIncrements", DEFAULT_PRESERVE_POSITION_INCREMENTS)
+	ans.maxGraphExpansions = ans.GetInt("maxGraphExpansions", DEFAULT_MAX_GRAPH_EXPANSIONS)
+	if err := ans.Validate(); err != nil {
+		return nil, err
+	}
+	if ans.maxGraphExpansions < 1 {
+		return nil, errors.New("maxGraphExpansions must be greater than zero")
+	}
+	return ans, nil
+}
+
+func (f *ConcatenateGraphFilterFactory) Create(input TokenStream) TokenStream {
+	return NewConcatenateGraphFilterWith(input, f.preserveSep, f.preservePositionIncrements, f.maxGraphExpansions)
+}
+
+// miscellaneous/FingerprintFilterFactory.java
+
+/*
+Factory for FingerprintFilter, taking the "maxOutputTokenSize" (1024
+by default) and the single character "separator" (a space by
+default).
+*/
+type FingerprintFilterFactory struct {
+	*AbstractAnalysisFactory
+	maxOutputTokenSize int
+	separator          rune
+}
+
+func NewFingerprintFilterFactory(args map[string]string) (*FingerprintFilterFactory, error) {
+	ans := &FingerprintFilterFactory{AbstractAnalysisFactory: NewAbstractAnalysisFactory(args)}
+	ans.maxOutputTokenSize = ans.GetInt("maxOutputTokenSize", DEFAULT_MAX_OUTPUT_TOKEN_SIZE)
+	separator := []rune(ans.Get("separator", string(DEFAULT_SEPARATOR)))
+	if err := ans.Validate(); err != nil {
+		return nil, err
+	}
+	if ans.maxOutputTokenSize < 1 {
+		return nil, errors.New("maxOutputTokenSize must be greater than zero")
+	}
+	if len(separator) != 1 {
+		return nil, errors.New("separator must be a single character")
+	}
+	ans.separator = separator[0]
+	return ans, nil
+}
+
+func (f *FingerprintFilterFactory) Create(input TokenStream) TokenStream {
+	return NewFingerprintFilterWith(input, f.maxOutputTokenSize, f.separator)
+}
@@ -0,0 +1,160 @@
+package miscellaneous
+
+import (
+	. "github.com/balzaczyy/golucene/core/analysis"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"github.com/balzaczyy/golucene/core/util"
+	"sort"
+)
+
+// miscellaneous/FingerprintFilter.java
+
+const (
+	DEFAULT_MAX_OUTPUT_TOKEN_SIZE = 1024
+	DEFAULT_SEPARATOR             = ' '
+	FINGERPRINT_TYPE              = "fingerprint"
+)
+
+/*
+Filter outputs a single token which is a concatenation of the sorted
+and de-duplicated set of input tokens. This can be useful for
+clustering/linking use cases.
+*/
+type FingerprintFilter struct {
+	*TokenFilter
+	input              TokenStream
+	maxOutputTokenSize int
+	separator          rune
+	inputEnded         bool
+	finalState         *util.AttributeState
+
+	termAtt   CharTermAttribute
+	offsetAtt OffsetAttribute
+	posIncAtt PositionIncrementAttribute
+	posLenAtt PositionLengthAttribute
+	typeAtt   TypeAttribute
+}
+
+/* Create a new FingerprintFilter with default settings. */
+func NewFingerprintFilter(input TokenStream) *FingerprintFilter {
+	return NewFingerprintFilterWith(input, DEFAULT_MAX_OUTPUT_TOKEN_SIZE, DEFAULT_SEPARATOR)
+}
+
+/*
+Create a new FingerprintFilter with control over all settings.
+maxOutputTokenSize is the maximum length of the fingerprint token
+output. If exceeded, no token is output. separator separates the
+unique terms in the fingerprint.
+*/
+func NewFingerprintFilterWith(input TokenStream, maxOutputTokenSize int, separator rune) *FingerprintFilter {
+	if maxOutputTokenSize < 1 {
+		panic("maxOutputTokenSize must be greater than zero")
+	}
+	ans := &FingerprintFilter{
+		TokenFilter:        NewTokenFilter(input),
+		input:              input,
+		maxOutputTokenSize: maxOutputTokenSize,
+		separator:          separator,
+	}
+	ans.termAtt = ans.Attributes().Add("CharTermAttribute").(CharTermAttribute)
+	ans.offsetAtt = ans.Attributes().Add("OffsetAttribute").(OffsetAttribute)
+	ans.posIncAtt = ans.Attributes().Add("PositionIncrementAttribute").(PositionIncrementAttribute)
+	ans.posLenAtt = ans.Attributes().Add("PositionLengthAttribute").(PositionLengthAttribute)
+	ans.typeAtt = ans.Attributes().Add("TypeAttribute").(TypeAttribute)
+	return ans
+}
+
+func (f *FingerprintFilter) IncrementToken() (bool, error) {
+	if f.inputEnded {
+		return false, nil
+	}
+	ok, err := f.buildSingleOutputToken()
+	if err != nil {
+		return false, err
+	}
+	f.finalState = f.Attributes().CaptureState()
+	return ok, nil
+}
+
+func (f *FingerprintFilter) buildSingleOutputToken() (bool, error) {
+	f.inputEnded = false
+	uniqueTerms := make(map[string]bool)
+	outputTokenSize := 0
+	for {
+		ok, err := f.input.IncrementToken()
+		if err != nil {
+			return false, err
+		}
+		if !ok {
+			break
+		}
+		if outputTokenSize > f.maxOutputTokenSize {
+			continue
+		}
+		term := f.termAtt.Buffer()[:f.termAtt.Length()]
+		if s := string(term); !uniqueTerms[s] {
+			if len(uniqueTerms) > 0 {
+				outputTokenSize++ // Add 1 for the separator char we will output
+			}
+			uniqueTerms[s] = true
+			outputTokenSize += len(term)
+		}
+	}
+	// Force end-of-stream operations to get the final state.
+	if err := f.input.End(); err != nil {
+		return false, err
+	}
+	f.inputEnded = true
+
+	// Gathering token details in case there are no terms to output
+	f.offsetAtt.SetOffset(0, f.offsetAtt.EndOffset())
+	f.posLenAtt.SetPositionLength(1)
+	f.posIncAtt.SetPositionIncrement(1)
+	f.typeAtt.SetType(FINGERPRINT_TYPE)
+
+	// No tokens gathered, or tokens gathered are too large - no entry
+	if len(uniqueTerms) < 1 || outputTokenSize > f.maxOutputTokenSize {
+		f.termAtt.SetLength(0)
+		return false, nil
+	}
+
+	// Sort the set of deduplicated tokens and combine
+	items := make([]string, 0, len(uniqueTerms))
+	for term := range uniqueTerms {
+		items = append(items, term)
+	}
+	sort.Strings(items)
+	fingerprint := make([]rune, 0, outputTokenSize)
+	for i, item := range items {
+		if i > 0 {
+			fingerprint = append(fingerprint, f.separator)
+		}
+		fingerprint = append(fingerprint, []rune(item)...)
+	}
+	f.termAtt.CopyBuffer(fingerprint)
+	return true, nil
+}
+
+func (f *FingerprintFilter) End() error {
+	if !f.inputEnded {
+		// Rare case - if an error occurs while building the output token
+		// we may not have called input.End() already
+		if err := f.input.End(); err != nil {
+			return err
+		}
+		f.inputEnded = true
+	}
+	if f.finalState != nil {
+		f.Attributes().RestoreState(f.finalState)
+	}
+	return nil
+}
+
+func (f *FingerprintFilter) Reset() error {
+	if err := f.TokenFilter.Reset(); err != nil {
+		return err
+	}
+	f.inputEnded = false
+	f.finalState = nil
+	return nil
+}
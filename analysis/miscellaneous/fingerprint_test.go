@@ -0,0 +1,45 @@
+package miscellaneous
+
+import (
+	"testing"
+)
+
+func TestFingerprintFilter(t *testing.T) {
+	for _, c := range []struct {
+		text, expected string
+		maxSize        int
+	}{
+		{"B A C A", "A B C/1", DEFAULT_MAX_OUTPUT_TOKEN_SIZE},
+		{"A", "A/1", DEFAULT_MAX_OUTPUT_TOKEN_SIZE},
+		{"", "", DEFAULT_MAX_OUTPUT_TOKEN_SIZE},
+		{"B A C", "A B C/1", 5},
+		{"B A C", "", 4},
+		{"A A A", "A/1", 1},
+	} {
+		ts := NewFingerprintFilterWith(tokenizer(c.text), c.maxSize, ' ')
+		if got := termsWithPosInc(t, ts); got != c.expected {
+			t.Errorf("%q/%v: expected %v, but was %v", c.text, c.maxSize, c.expected, got)
+		}
+	}
+}
+
+func TestFingerprintFilterFactory(t *testing.T) {
+	f, err := NewFingerprintFilterFactory(map[string]string{"separator": "_", "maxOutputTokenSize": "10"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := f.Create(tokenizer("B2 A1 C3 B2"))
+	if got, expected := termsWithPosInc(t, ts), "A1_B2_C3/1"; got != expected {
+		t.Errorf("expected %v, but was %v", expected, got)
+	}
+
+	for _, args := range []map[string]string{
+		{"separator": "--"},
+		{"maxOutputTokenSize": "0"},
+		{"foo": "bar"},
+	} {
+		if _, err := NewFingerprintFilterFactory(args); err == nil {
+			t.Errorf("%v: expected an error", args)
+		}
+	}
+}
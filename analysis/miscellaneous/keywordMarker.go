@@ -0,0 +1,116 @@
+package miscellaneous
+
+import (
+	. "github.com/balzaczyy/golucene/analysis/util"
+	. "github.com/balzaczyy/golucene/core/analysis"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"regexp"
+)
+
+// miscellaneous/KeywordMarkerFilter.java
+
+type KeywordMarkerFilterSPI interface {
+	// Returns true if the current token is a keyword, otherwise false
+	IsKeyword() bool
+}
+
+/*
+Marks terms as keywords via the KeywordAttribute.
+
+This is an abstract class; subclasses must implement IsKeyword().
+*/
+type KeywordMarkerFilter struct {
+	*TokenFilter
+	spi         KeywordMarkerFilterSPI
+	input       TokenStream
+	keywordAttr KeywordAttribute
+}
+
+/* Creates a new KeywordMarkerFilter */
+func NewKeywordMarkerFilter(spi KeywordMarkerFilterSPI, in TokenStream) *KeywordMarkerFilter {
+	ans := &KeywordMarkerFilter{
+		TokenFilter: NewTokenFilter(in),
+		spi:         spi,
+		input:       in,
+	}
+	ans.keywordAttr = ans.Attributes().Add("KeywordAttribute").(KeywordAttribute)
+	return ans
+}
+
+func (f *KeywordMarkerFilter) IncrementToken() (bool, error) {
+	ok, err := f.input.IncrementToken()
+	if err != nil || !ok {
+		return false, err
+	}
+	if f.spi.IsKeyword() {
+		f.keywordAttr.SetKeyword(true)
+	}
+	return true, nil
+}
+
+// miscellaneous/SetKeywordMarkerFilter.java
+
+/*
+Marks terms as keywords via the KeywordAttribute. Each token
+contained in the provided set is marked as a keyword by setting
+KeywordAttribute.SetKeyword(true).
+*/
+type SetKeywordMarkerFilter struct {
+	*KeywordMarkerFilter
+	keywordSet *CharArraySet
+	termAtt    CharTermAttribute
+}
+
+/*
+Create a new SetKeywordMarkerFilter, that marks the current token as
+a keyword if the tokens term buffer is contained in the given set via
+the KeywordAttribute.
+*/
+func NewSetKeywordMarkerFilter(in TokenStream, keywordSet map[string]bool) *SetKeywordMarkerFilter {
+	return NewSetKeywordMarkerFilterWithSet(in, NewCharArraySetFromMap(keywordSet, false))
+}
+
+/*
+Create a new SetKeywordMarkerFilter with a CharArraySet, matching the
+keywords regardless of case if the set ignores case.
+*/
+func NewSetKeywordMarkerFilterWithSet(in TokenStream, keywordSet *CharArraySet) *SetKeywordMarkerFilter {
+	ans := &SetKeywordMarkerFilter{keywordSet: keywordSet}
+	ans.KeywordMarkerFilter = NewKeywordMarkerFilter(ans, in)
+	ans.termAtt = ans.Attributes().Add("CharTermAttribute").(CharTermAttribute)
+	return ans
+}
+
+func (f *SetKeywordMarkerFilter) IsKeyword() bool {
+	return f.keywordSet.Contains(f.termAtt.Buffer()[:f.termAtt.Length()])
+}
+
+// miscellaneous/PatternKeywordMarkerFilter.java
+
+/*
+Marks terms as keywords via the KeywordAttribute if they match the
+given regular expression as a whole.
+*/
+type PatternKeywordMarkerFilter struct {
+	*KeywordMarkerFilter
+	pattern *regexp.Regexp
+	termAtt CharTermAttribute
+}
+
+/*
+Create a new PatternKeywordMarkerFilter, that marks the current token
+as a keyword if the tokens term buffer matches the provided pattern
+via the KeywordAttribute.
+*/
+func NewPatternKeywordMarkerFilter(in TokenStream, pattern *regexp.Regexp) *PatternKeywordMarkerFilter {
+	ans := &PatternKeywordMarkerFilter{pattern: pattern}
+	ans.KeywordMarkerFilter = NewKeywordMarkerFilter(ans, in)
+	ans.termAtt = ans.Attributes().Add("CharTermAttribute").(CharTermAttribute)
+	return ans
+}
+
+func (f *PatternKeywordMarkerFilter) IsKeyword() bool {
+	term := string(f.termAtt.Buffer()[:f.termAtt.Length()])
+	loc := f.pattern.FindStringIndex(term)
+	return loc != nil && loc[0] == 0 && loc[1] == len(term)
+}
@@ -0,0 +1,85 @@
+package miscellaneous
+
+import (
+	"fmt"
+	. "github.com/balzaczyy/golucene/analysis/util"
+	. "github.com/balzaczyy/golucene/core/analysis"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"github.com/balzaczyy/golucene/core/util"
+)
+
+// miscellaneous/LengthFilter.java
+
+/*
+Removes words that are too long or too short from the stream.
+
+Note: Length is calculated as the number of runes.
+*/
+type LengthFilter struct {
+	*FilteringTokenFilter
+	min, max int
+	termAtt  CharTermAttribute
+}
+
+/*
+Create a new LengthFilter. This will filter out tokens whose
+CharTermAttribute is either too short (Length() < min) or too long
+(Length() > max).
+*/
+func NewLengthFilter(version util.Version, in TokenStream, min, max int) *LengthFilter {
+	if min < 0 {
+		panic("minimum length must be greater than or equal to zero")
+	}
+	if min > max {
+		panic("minimum length must not be greater than maximum length")
+	}
+	ans := &LengthFilter{min: min, max: max}
+	ans.FilteringTokenFilter = NewFilteringTokenFilter(ans, version, in)
+	ans.termAtt = ans.Attributes().Add("CharTermAttribute").(CharTermAttribute)
+	return ans
+}
+
+func (f *LengthFilter) Accept() bool {
+	length := f.termAtt.Length()
+	return length >= f.min && length <= f.max
+}
+
+// miscellaneous/TruncateTokenFilter.java
+
+/*
+A token filter for truncating the terms into a specific length.
+Fixed prefix truncation, as a stemming method, produces good results
+on Turkish language. Tokens marked as keywords are left untouched.
+*/
+type TruncateTokenFilter struct {
+	*TokenFilter
+	input       TokenStream
+	length      int
+	termAtt     CharTermAttribute
+	keywordAttr KeywordAttribute
+}
+
+func NewTruncateTokenFilter(in TokenStream, length int) *TruncateTokenFilter {
+	if length < 1 {
+		panic(fmt.Sprintf("length parameter must be a positive number: %v", length))
+	}
+	ans := &TruncateTokenFilter{
+		TokenFilter: NewTokenFilter(in),
+		input:       in,
+		length:      length,
+	}
+	ans.termAtt = ans.Attributes().Add("CharTermAttribute").(CharTermAttribute)
+	ans.keywordAttr = ans.Attributes().Add("KeywordAttribute").(KeywordAttribute)
+	return ans
+}
+
+func (f *TruncateTokenFilter) IncrementToken() (bool, error) {
+	ok, err := f.input.IncrementToken()
+	if err != nil || !ok {
+		return false, err
+	}
+	if !f.keywordAttr.IsKeyword() && f.termAtt.Length() > f.length {
+		f.termAtt.SetLength(f.length)
+	}
+	return true, nil
+}
@@ -0,0 +1,45 @@
+package miscellaneous
+
+import (
+	"github.com/balzaczyy/golucene/core/util"
+	"testing"
+)
+
+func TestLengthFilter(t *testing.T) {
+	ts := NewLengthFilter(util.VERSION_LATEST, tokenizer("short toolong evenmuchlongertext a ab toolong foo"), 2, 6)
+	if got, expected := termsWithPosInc(t, ts), "short/1 ab/4 foo/2"; got != expected {
+		t.Errorf("expected %v, but was %v", expected, got)
+	}
+
+	if _, err := NewLengthFilterFactory(map[string]string{"min": "5", "max": "4"}); err == nil {
+		t.Error("expected an error for min > max")
+	}
+	if _, err := NewLengthFilterFactory(map[string]string{"min": "1"}); err == nil {
+		t.Error("expected an error for the missing max")
+	}
+}
+
+func TestTruncateTokenFilter(t *testing.T) {
+	ts := NewTruncateTokenFilter(tokenizer("abcdefg 1234567 ABCDEFG abcde abc 12345 123"), 5)
+	if got, expected := termsWithPosInc(t, ts), "abcde/1 12345/1 ABCDE/1 abcde/1 abc/1 12345/1 123/1"; got != expected {
+		t.Errorf("expected %v, but was %v", expected, got)
+	}
+
+	// keywords are left untouched
+	ts = NewTruncateTokenFilter(NewSetKeywordMarkerFilter(tokenizer("keywords stemmed"),
+		map[string]bool{"keywords": true}), 3)
+	if got, expected := termsWithPosInc(t, ts), "keywords/1 ste/1"; got != expected {
+		t.Errorf("expected %v, but was %v", expected, got)
+	}
+
+	f, err := NewTruncateTokenFilterFactory(map[string]string{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, expected := termsWithPosInc(t, f.Create(tokenizer("truncated"))), "trunc/1"; got != expected {
+		t.Errorf("expected %v, but was %v", expected, got)
+	}
+	if _, err := NewTruncateTokenFilterFactory(map[string]string{"prefixLength": "0"}); err == nil {
+		t.Error("expected an error for a non-positive prefixLength")
+	}
+}
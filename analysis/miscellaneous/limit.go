@@ -0,0 +1,171 @@
+package miscellaneous
+
+import (
+	. "github.com/balzaczyy/golucene/core/analysis"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+)
+
+// miscellaneous/LimitTokenCountFilter.java
+
+/*
+This TokenFilter limits the number of tokens while indexing. It is a
+replacement for the maximum field length setting inside IndexWriter.
+
+By default, this filter ignores any tokens in the wrapped TokenStream
+once the limit has been reached, which can result in Reset() being
+called prior to IncrementToken() returning false. For most
+TokenStream implementations this should be acceptable, and faster
+then consuming the full stream. If you are wrapping a TokenStream
+which requires that the full stream of tokens be exhausted in order
+to function properly, use the consumeAllTokens option.
+*/
+type LimitTokenCountFilter struct {
+	*TokenFilter
+	input            TokenStream
+	maxTokenCount    int
+	consumeAllTokens bool
+	tokenCount       int
+	exhausted        bool
+}
+
+/*
+Build a filter that only accepts tokens up to a maximum number. This
+filter will not consume any tokens beyond the maxTokenCount limit.
+*/
+func NewLimitTokenCountFilter(in TokenStream, maxTokenCount int) *LimitTokenCountFilter {
+	return NewLimitTokenCountFilterWithConsume(in, maxTokenCount, false)
+}
+
+/*
+Build a filter that limits the maximum number of tokens per field.
+If consumeAllTokens is true, all tokens of the input are consumed
+even if maxTokenCount is reached.
+*/
+func NewLimitTokenCountFilterWithConsume(in TokenStream, maxTokenCount int, consumeAllTokens bool) *LimitTokenCountFilter {
+	if maxTokenCount < 1 {
+		panic("maxTokenCount must be greater than zero")
+	}
+	return &LimitTokenCountFilter{
+		TokenFilter:      NewTokenFilter(in),
+		input:            in,
+		maxTokenCount:    maxTokenCount,
+		consumeAllTokens: consumeAllTokens,
+	}
+}
+
+func (f *LimitTokenCountFilter) IncrementToken() (bool, error) {
+	if f.exhausted {
+		return false, nil
+	}
+	if f.tokenCount < f.maxTokenCount {
+		ok, err := f.input.IncrementToken()
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			f.tokenCount++
+			return true, nil
+		}
+		f.exhausted = true
+		return false, nil
+	}
+	for f.consumeAllTokens {
+		ok, err := f.input.IncrementToken()
+		if err != nil {
+			return false, err
+		}
+		if !ok {
+			break
+		}
+	}
+	f.exhausted = true
+	return false, nil
+}
+
+func (f *LimitTokenCountFilter) Reset() error {
+	f.tokenCount = 0
+	f.exhausted = false
+	return f.TokenFilter.Reset()
+}
+
+// miscellaneous/LimitTokenPositionFilter.java
+
+/*
+This TokenFilter limits its emitted tokens to those with positions
+that are not greater than the configured limit.
+
+By default, this filter ignores any tokens in the wrapped TokenStream
+once the limit has been exceeded, which can result in Reset() being
+called prior to IncrementToken() returning false. Use the
+consumeAllTokens option if the wrapped TokenStream must be exhausted.
+*/
+type LimitTokenPositionFilter struct {
+	*TokenFilter
+	input            TokenStream
+	maxTokenPosition int
+	consumeAllTokens bool
+	tokenPosition    int
+	exhausted        bool
+	posIncAtt        PositionIncrementAttribute
+}
+
+/*
+Build a filter that only accepts tokens up to and including the given
+maximum position. This filter will not consume any tokens with
+position greater than the maxTokenPosition limit.
+*/
+func NewLimitTokenPositionFilter(in TokenStream, maxTokenPosition int) *LimitTokenPositionFilter {
+	return NewLimitTokenPositionFilterWithConsume(in, maxTokenPosition, false)
+}
+
+/*
+Build a filter that limits the maximum position of tokens to emit.
+If consumeAllTokens is true, all tokens of the input are consumed
+even if maxTokenPosition is exceeded.
+*/
+func NewLimitTokenPositionFilterWithConsume(in TokenStream, maxTokenPosition int, consumeAllTokens bool) *LimitTokenPositionFilter {
+	if maxTokenPosition < 1 {
+		panic("maxTokenPosition must be greater than zero")
+	}
+	ans := &LimitTokenPositionFilter{
+		TokenFilter:      NewTokenFilter(in),
+		input:            in,
+		maxTokenPosition: maxTokenPosition,
+		consumeAllTokens: consumeAllTokens,
+	}
+	ans.posIncAtt = ans.Attributes().Add("PositionIncrementAttribute").(PositionIncrementAttribute)
+	return ans
+}
+
+func (f *LimitTokenPositionFilter) IncrementToken() (bool, error) {
+	if f.exhausted {
+		return false, nil
+	}
+	ok, err := f.input.IncrementToken()
+	if err != nil {
+		return false, err
+	}
+	if !ok {
+		f.exhausted = true
+		return false, nil
+	}
+	f.tokenPosition += f.posIncAtt.PositionIncrement()
+	if f.tokenPosition <= f.maxTokenPosition {
+		return true, nil
+	}
+	for f.consumeAllTokens {
+		if ok, err = f.input.IncrementToken(); err != nil {
+			return false, err
+		} else if !ok {
+			break
+		}
+	}
+	f.exhausted = true
+	return false, nil
+}
+
+func (f *LimitTokenPositionFilter) Reset() error {
+	f.tokenPosition = 0
+	f.exhausted = false
+	return f.TokenFilter.Reset()
+}
@@ -0,0 +1,123 @@
+package miscellaneous
+
+import (
+	"fmt"
+	"github.com/balzaczyy/golucene/analysis/core"
+	std "github.com/balzaczyy/golucene/analysis/standard"
+	. "github.com/balzaczyy/golucene/core/analysis"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"github.com/balzaczyy/golucene/core/util"
+	"strings"
+	"testing"
+)
+
+/* Returns the terms of ts with their position increments, like "a/1 b/2". */
+func termsWithPosInc(t *testing.T, ts TokenStream) string {
+	termAtt := ts.Attributes().Add("CharTermAttribute").(CharTermAttribute)
+	posIncAtt := ts.Attributes().Add("PositionIncrementAttribute").(PositionIncrementAttribute)
+	if err := ts.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	var tokens []string
+	for {
+		ok, err := ts.IncrementToken()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			break
+		}
+		tokens = append(tokens, fmt.Sprintf("%v/%v",
+			string(termAtt.Buffer()[:termAtt.Length()]), posIncAtt.PositionIncrement()))
+	}
+	if err := ts.End(); err != nil {
+		t.Fatal(err)
+	}
+	if err := ts.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return strings.Join(tokens, " ")
+}
+
+/* Counts the tokens pulled from its input. */
+type countingFilter struct {
+	*TokenFilter
+	input TokenStream
+	count int
+}
+
+func newCountingFilter(in TokenStream) *countingFilter {
+	return &countingFilter{TokenFilter: NewTokenFilter(in), input: in}
+}
+
+func (f *countingFilter) IncrementToken() (bool, error) {
+	ok, err := f.input.IncrementToken()
+	if ok {
+		f.count++
+	}
+	return ok, err
+}
+
+func TestLimitTokenCountFilter(t *testing.T) {
+	for _, consumeAll := range []bool{false, true} {
+		counter := newCountingFilter(tokenizer("A1 B2 C3 D4 E5 F6"))
+		ts := NewLimitTokenCountFilterWithConsume(counter, 3, consumeAll)
+		if got, expected := termsWithPosInc(t, ts), "A1/1 B2/1 C3/1"; got != expected {
+			t.Errorf("expected %v, but was %v", expected, got)
+		}
+		expected := 3
+		if consumeAll {
+			expected = 6
+		}
+		if counter.count != expected {
+			t.Errorf("consumeAllTokens=%v: expected %v tokens consumed, but was %v",
+				consumeAll, expected, counter.count)
+		}
+	}
+
+	ts := NewLimitTokenCountFilter(tokenizer("A1 B2"), 3)
+	if got, expected := termsWithPosInc(t, ts), "A1/1 B2/1"; got != expected {
+		t.Errorf("expected %v, but was %v", expected, got)
+	}
+}
+
+func TestLimitTokenPositionFilter(t *testing.T) {
+	stopWords := map[string]bool{"the": true, "of": true}
+	for _, consumeAll := range []bool{false, true} {
+		counter := newCountingFilter(core.NewStopFilter(util.VERSION_LATEST,
+			tokenizer("one of the two three four"), stopWords))
+		ts := NewLimitTokenPositionFilterWithConsume(counter, 4, consumeAll)
+		if got, expected := termsWithPosInc(t, ts), "one/1 two/3"; got != expected {
+			t.Errorf("expected %v, but was %v", expected, got)
+		}
+		expected := 3
+		if consumeAll {
+			expected = 4
+		}
+		if counter.count != expected {
+			t.Errorf("consumeAllTokens=%v: expected %v tokens consumed, but was %v",
+				consumeAll, expected, counter.count)
+		}
+	}
+}
+
+func TestLimitTokenFilterFactories(t *testing.T) {
+	if _, err := NewLimitTokenCountFilterFactory(map[string]string{}); err == nil {
+		t.Error("expected an error for the missing maxTokenCount")
+	}
+	if _, err := NewLimitTokenPositionFilterFactory(map[string]string{"maxTokenPosition": "0"}); err == nil {
+		t.Error("expected an error for a non-positive maxTokenPosition")
+	}
+	f, err := NewLimitTokenCountFilterFactory(map[string]string{"maxTokenCount": "1", "consumeAllTokens": "true"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := f.Create(tokenizer("A1 B2"))
+	if got, expected := termsWithPosInc(t, ts), "A1/1"; got != expected {
+		t.Errorf("expected %v, but was %v", expected, got)
+	}
+}
+
+func tokenizer(text string) TokenStream {
+	return std.NewStandardTokenizer(util.VERSION_LATEST, strings.NewReader(text))
+}
@@ -0,0 +1,36 @@
+package miscellaneous
+
+import (
+	. "github.com/balzaczyy/golucene/analysis/util"
+	. "github.com/balzaczyy/golucene/core/analysis"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+)
+
+// miscellaneous/ProtectedTermFilter.java
+
+/*
+A ConditionalTokenFilter that only applies its wrapped filters to
+terms that are not contained in the protected set.
+*/
+type ProtectedTermFilter struct {
+	*ConditionalTokenFilter
+	protectedTerms *CharArraySet
+	termAtt        CharTermAttribute
+}
+
+/*
+Creates a new ProtectedTermFilter, applying the filters created by
+inputFactory to the terms which are not in protectedTerms.
+*/
+func NewProtectedTermFilter(protectedTerms *CharArraySet, input TokenStream,
+	inputFactory func(TokenStream) TokenStream) *ProtectedTermFilter {
+
+	ans := &ProtectedTermFilter{protectedTerms: protectedTerms}
+	ans.ConditionalTokenFilter = NewConditionalTokenFilter(ans, input, inputFactory)
+	ans.termAtt = ans.Attributes().Add("CharTermAttribute").(CharTermAttribute)
+	return ans
+}
+
+func (f *ProtectedTermFilter) ShouldFilter() (bool, error) {
+	return !f.protectedTerms.Contains(f.termAtt.Buffer()[:f.termAtt.Length()]), nil
+}
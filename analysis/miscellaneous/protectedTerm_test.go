@@ -0,0 +1,61 @@
+package miscellaneous
+
+import (
+	. "github.com/balzaczyy/golucene/analysis/util"
+	. "github.com/balzaczyy/golucene/core/analysis"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestProtectedTermFilter(t *testing.T) {
+	protected := NewCharArraySetFromMap(map[string]bool{"Bob": true, "David": true}, false)
+	ts := NewProtectedTermFilter(protected, tokenizer("Alice Bob Clara David"),
+		func(in TokenStream) TokenStream { return NewTruncateTokenFilter(in, 2) })
+	if got, expected := termsWithPosInc(t, ts), "Al/1 Bob/1 Cl/1 David/1"; got != expected {
+		t.Errorf("expected %v, but was %v", expected, got)
+	}
+
+	// wrapped filters may emit more than one token
+	ts = NewProtectedTermFilter(protected, tokenizer("Alice Bob Clara"),
+		func(in TokenStream) TokenStream { return NewTypeAsSynonymFilter(in) })
+	if got, expected := termsWithPosInc(t, ts),
+		"Alice/1 <ALPHANUM>/0 Bob/1 Clara/1 <ALPHANUM>/0"; got != expected {
+		t.Errorf("expected %v, but was %v", expected, got)
+	}
+}
+
+func TestProtectedTermFilterFactory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "protectedTerm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	protected := filepath.Join(dir, "protected.txt")
+	if err = ioutil.WriteFile(protected, []byte("# protected\nfoobar\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	f, err := NewTokenFilterFactory("protectedTerm", map[string]string{
+		"protected": protected, "ignoreCase": "true",
+		"wrappedFilters": "lowercase,truncate", "truncate.prefixLength": "4"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := f.Create(tokenizer("FooBar Quixotic Somewhat"))
+	if got, expected := termsWithPosInc(t, ts), "FooBar/1 quix/1 some/1"; got != expected {
+		t.Errorf("expected %v, but was %v", expected, got)
+	}
+
+	for _, args := range []map[string]string{
+		{"wrappedFilters": "lowercase"},
+		{"protected": protected},
+		{"protected": protected, "wrappedFilters": "whatever"},
+		{"protected": protected, "wrappedFilters": "truncate", "truncate.foo": "bar"},
+		{"protected": protected, "wrappedFilters": "lowercase", "truncate.prefixLength": "4"},
+	} {
+		if _, err := NewProtectedTermFilterFactory(args); err == nil {
+			t.Errorf("%v: expected an error", args)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package miscellaneous
+
+import (
+	. "github.com/balzaczyy/golucene/core/analysis"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"github.com/balzaczyy/golucene/core/util"
+	"github.com/balzaczyy/golucene/core/util/fst"
+	"github.com/balzaczyy/golucene/core/util/packed"
+	"math"
+	"sort"
+	"unicode"
+)
+
+// miscellaneous/StemmerOverrideFilter.java
+
+/*
+Provides the ability to override any KeywordAttribute aware stemmer
+with custom dictionary-based stemming.
+*/
+type StemmerOverrideFilter struct {
+	*TokenFilter
+	input      TokenStream
+	stemmerMap *StemmerOverrideMap
+	termAtt    CharTermAttribute
+	keywordAtt KeywordAttribute
+	fstReader  fst.BytesReader
+	scratchArc *fst.Arc
+}
+
+/*
+Create a new StemmerOverrideFilter, performing dictionary-based
+stemming with the provided dictionary.
+
+Any dictionary-stemmed terms will be marked with KeywordAttribute so
+that they will not be stemmed with stemmers down the chain.
+*/
+func NewStemmerOverrideFilter(input TokenStream, stemmerOverrideMap *StemmerOverrideMap) *StemmerOverrideFilter {
+	ans := &StemmerOverrideFilter{
+		TokenFilter: NewTokenFilter(input),
+		input:       input,
+		stemmerMap:  stemmerOverrideMap,
+		scratchArc:  new(fst.Arc),
+	}
+	ans.fstReader = stemmerOverrideMap.BytesReader()
+	ans.termAtt = ans.Attributes().Add("CharTermAttribute").(CharTermAttribute)
+	ans.keywordAtt = ans.Attributes().Add("KeywordAttribute").(KeywordAttribute)
+	return ans
+}
+
+func (f *StemmerOverrideFilter) IncrementToken() (bool, error) {
+	ok, err := f.input.IncrementToken()
+	if err != nil || !ok {
+		return false, err
+	}
+	if f.fstReader == nil {
+		// No overrides
+		return true, nil
+	}
+	if !f.keywordAtt.IsKeyword() { // don't muck with already-keyworded terms
+		stem, err := f.stemmerMap.Get(f.termAtt.Buffer()[:f.termAtt.Length()], f.scratchArc, f.fstReader)
+		if err != nil {
+			return false, err
+		}
+		if stem != nil {
+			f.termAtt.CopyBuffer([]rune(string(stem)))
+			f.keywordAtt.SetKeyword(true)
+		}
+	}
+	return true, nil
+}
+
+// miscellaneous/StemmerOverrideFilter.java#StemmerOverrideMap
+
+/* A read-only 4-byte FST backed map that allows fast case-insensitive key value lookups for StemmerOverrideFilter. */
+type StemmerOverrideMap struct {
+	// map<input, UTF-8 encoded stem>; nil if there is no override
+	fst        *fst.FST
+	ignoreCase bool
+}
+
+/* Returns a BytesReader to pass to the Get() method, or nil if the map is empty. */
+func (m *StemmerOverrideMap) BytesReader() fst.BytesReader {
+	if m.fst == nil {
+		return nil
+	}
+	return m.fst.BytesReader()
+}
+
+/* Returns the UTF-8 encoded stem of the given input, or nil if it has no override. */
+func (m *StemmerOverrideMap) Get(buffer []rune, scratchArc *fst.Arc, fstReader fst.BytesReader) ([]byte, error) {
+	outputs := m.fst.Outputs()
+	arc := m.fst.FirstArc(scratchArc)
+	output := outputs.NoOutput()
+	for _, ch := range buffer {
+		if m.ignoreCase {
+			ch = unicode.ToLower(ch)
+		}
+		found, err := m.fst.FindTargetArc(int(ch), arc, arc, fstReader)
+		if err != nil || found == nil {
+			return nil, err
+		}
+		output = outputs.Add(output, arc.Output)
+	}
+	if !arc.IsFinal() {
+		return nil, nil
+	}
+	stem, _ := outputs.Add(output, arc.NextFinalOutput).([]byte)
+	if stem == nil {
+		// an empty stem is NoOutput
+		stem = []byte{}
+	}
+	return stem, nil
+}
+
+// miscellaneous/StemmerOverrideFilter.java#Builder
+
+/* This builder builds an FST for the StemmerOverrideFilter. */
+type StemmerOverrideMapBuilder struct {
+	ignoreCase bool
+	pending    map[string]string
+}
+
+/* Creates a new StemmerOverrideMapBuilder, whose inputs are lower cased if ignoreCase is true. */
+func NewStemmerOverrideMapBuilder(ignoreCase bool) *StemmerOverrideMapBuilder {
+	return &StemmerOverrideMapBuilder{ignoreCase, make(map[string]string)}
+}
+
+/*
+Adds an input string and its stemmer override output to this builder.
+Returns false iff the input has already been added to this builder,
+in which case the first output is kept.
+*/
+func (b *StemmerOverrideMapBuilder) Add(input, output string) bool {
+	if b.ignoreCase {
+		runes := []rune(input)
+		for i, ch := range runes {
+			runes[i] = unicode.ToLower(ch)
+		}
+		input = string(runes)
+	}
+	if _, ok := b.pending[input]; ok {
+		return false
+	}
+	b.pending[input] = output
+	return true
+}
+
+/* Returns a StemmerOverrideMap to be used with the StemmerOverrideFilter. */
+func (b *StemmerOverrideMapBuilder) Build() (*StemmerOverrideMap, error) {
+	if len(b.pending) == 0 {
+		return &StemmerOverrideMap{ignoreCase: b.ignoreCase}, nil
+	}
+
+	outputs := fst.ByteSequenceOutputsSingleton()
+	builder := fst.NewBuilder(fst.INPUT_TYPE_BYTE4, 0, 0, true, true,
+		math.MaxInt32, outputs, false, packed.PackedInts.COMPACT, true, 15)
+	scratch := util.NewIntsRefBuilder()
+
+	// the FST needs its inputs in code point order, which is also
+	// the byte order of UTF-8 strings
+	inputs := make([]string, 0, len(b.pending))
+	for input := range b.pending {
+		inputs = append(inputs, input)
+	}
+	sort.Strings(inputs)
+
+	for _, input := range inputs {
+		scratch.Clear()
+		for _, ch := range input {
+			scratch.Append(int(ch))
+		}
+		output := outputs.NoOutput()
+		if stem := b.pending[input]; stem != "" {
+			output = []byte(stem)
+		}
+		if err := builder.Add(scratch.Get(), output); err != nil {
+			return nil, err
+		}
+	}
+	f, err := builder.Finish()
+	if err != nil {
+		return nil, err
+	}
+	return &StemmerOverrideMap{f, b.ignoreCase}, nil
+}
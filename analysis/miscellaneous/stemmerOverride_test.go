@@ -0,0 +1,105 @@
+package miscellaneous
+
+import (
+	. "github.com/balzaczyy/golucene/core/analysis"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+/* A naive stemmer stripping the trailing "s" and "ed" of the terms not marked as keywords. */
+type naiveStemFilter struct {
+	*TokenFilter
+	input      TokenStream
+	termAtt    CharTermAttribute
+	keywordAtt KeywordAttribute
+}
+
+func newNaiveStemFilter(in TokenStream) *naiveStemFilter {
+	ans := &naiveStemFilter{TokenFilter: NewTokenFilter(in), input: in}
+	ans.termAtt = ans.Attributes().Add("CharTermAttribute").(CharTermAttribute)
+	ans.keywordAtt = ans.Attributes().Add("KeywordAttribute").(KeywordAttribute)
+	return ans
+}
+
+func (f *naiveStemFilter) IncrementToken() (bool, error) {
+	ok, err := f.input.IncrementToken()
+	if err != nil || !ok || f.keywordAtt.IsKeyword() {
+		return ok, err
+	}
+	term := string(f.termAtt.Buffer()[:f.termAtt.Length()])
+	if strings.HasSuffix(term, "s") {
+		f.termAtt.SetLength(f.termAtt.Length() - 1)
+	} else if strings.HasSuffix(term, "ed") {
+		f.termAtt.SetLength(f.termAtt.Length() - 2)
+	}
+	return true, nil
+}
+
+func TestStemmerOverrideFilter(t *testing.T) {
+	builder := NewStemmerOverrideMapBuilder(true)
+	builder.Add("booked", "books")
+	builder.Add("Running", "run")
+	if builder.Add("BOOKED", "book") {
+		t.Error("expected the duplicate input to be rejected")
+	}
+	builder.Add("gone", "")
+	m, err := builder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := newNaiveStemFilter(NewStemmerOverrideFilter(
+		tokenizer("Booked running gone looked book"), m))
+	if got, expected := termsWithPosInc(t, ts), "books/1 run/1 /1 look/1 book/1"; got != expected {
+		t.Errorf("expected %v, but was %v", expected, got)
+	}
+
+	// an empty map overrides nothing
+	m, err = NewStemmerOverrideMapBuilder(false).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, expected := termsWithPosInc(t, NewStemmerOverrideFilter(tokenizer("Booked"), m)), "Booked/1"; got != expected {
+		t.Errorf("expected %v, but was %v", expected, got)
+	}
+}
+
+func TestKeywordMarkerFilters(t *testing.T) {
+	ts := newNaiveStemFilter(NewPatternKeywordMarkerFilter(
+		tokenizer("cats running Cats"), regexp.MustCompile("[a-z]+ing")))
+	if got, expected := termsWithPosInc(t, ts), "cat/1 running/1 Cat/1"; got != expected {
+		t.Errorf("expected %v, but was %v", expected, got)
+	}
+
+	dir, err := ioutil.TempDir("", "keywordMarker")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	protected := filepath.Join(dir, "protected.txt")
+	dictionary := filepath.Join(dir, "dictionary.txt")
+	if err = ioutil.WriteFile(protected, []byte("# protected\ncats\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err = ioutil.WriteFile(dictionary, []byte("# overrides\ndogs\tcanine\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	marker, err := NewKeywordMarkerFilterFactory(map[string]string{
+		"protected": protected, "ignoreCase": "true", "pattern": "[a-z]+ing"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	override, err := NewStemmerOverrideFilterFactory(map[string]string{"dictionary": dictionary})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts = newNaiveStemFilter(override.Create(marker.Create(
+		tokenizer("Cats running dogs birds"))))
+	if got, expected := termsWithPosInc(t, ts), "Cats/1 running/1 canine/1 bird/1"; got != expected {
+		t.Errorf("expected %v, but was %v", expected, got)
+	}
+}
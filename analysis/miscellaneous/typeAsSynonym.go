@@ -0,0 +1,64 @@
+package miscellaneous
+
+import (
+	. "github.com/balzaczyy/golucene/core/analysis"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"github.com/balzaczyy/golucene/core/util"
+)
+
+// miscellaneous/TypeAsSynonymFilter.java
+
+/*
+Adds the TypeAttribute.Type() as a synonym, i.e. another token at the
+same position, optionally with a specified prefix prepended. This
+makes the types assigned by the tokenizer, such as "<NUM>" or
+"<EMAIL>", searchable.
+*/
+type TypeAsSynonymFilter struct {
+	*TokenFilter
+	input      TokenStream
+	prefix     string
+	termAtt    CharTermAttribute
+	typeAtt    TypeAttribute
+	posIncrAtt PositionIncrementAttribute
+	savedToken *util.AttributeState
+}
+
+func NewTypeAsSynonymFilter(input TokenStream) *TypeAsSynonymFilter {
+	return NewTypeAsSynonymFilterWithPrefix(input, "")
+}
+
+/* The prefix is prepended to the types, like "_type_" for "_type_<NUM>". */
+func NewTypeAsSynonymFilterWithPrefix(input TokenStream, prefix string) *TypeAsSynonymFilter {
+	ans := &TypeAsSynonymFilter{
+		TokenFilter: NewTokenFilter(input),
+		input:       input,
+		prefix:      prefix,
+	}
+	ans.termAtt = ans.Attributes().Add("CharTermAttribute").(CharTermAttribute)
+	ans.typeAtt = ans.Attributes().Add("TypeAttribute").(TypeAttribute)
+	ans.posIncrAtt = ans.Attributes().Add("PositionIncrementAttribute").(PositionIncrementAttribute)
+	return ans
+}
+
+func (f *TypeAsSynonymFilter) IncrementToken() (bool, error) {
+	if f.savedToken != nil {
+		// Emit last token's type at the same position
+		f.Attributes().RestoreState(f.savedToken)
+		f.savedToken = nil
+		f.termAtt.CopyBuffer([]rune(f.prefix + f.typeAtt.Type()))
+		f.posIncrAtt.SetPositionIncrement(0)
+		return true, nil
+	}
+	ok, err := f.input.IncrementToken()
+	if ok {
+		// Now pending token type to emit
+		f.savedToken = f.Attributes().CaptureState()
+	}
+	return ok, err
+}
+
+func (f *TypeAsSynonymFilter) Reset() error {
+	f.savedToken = nil
+	return f.TokenFilter.Reset()
+}
@@ -0,0 +1,19 @@
+package miscellaneous
+
+import (
+	"testing"
+)
+
+func TestTypeAsSynonymFilter(t *testing.T) {
+	ts := NewTypeAsSynonymFilter(tokenizer("Route 66"))
+	if got, expected := termsWithPosInc(t, ts), "Route/1 <ALPHANUM>/0 66/1 <NUM>/0"; got != expected {
+		t.Errorf("expected %v, but was %v", expected, got)
+	}
+	f, err := NewTypeAsSynonymFilterFactory(map[string]string{"prefix": "_type_"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, expected := termsWithPosInc(t, f.Create(tokenizer("66"))), "66/1 _type_<NUM>/0"; got != expected {
+		t.Errorf("expected %v, but was %v", expected, got)
+	}
+}
@@ -0,0 +1,117 @@
+package ngram
+
+import (
+	"errors"
+	. "github.com/balzaczyy/golucene/analysis/util"
+	. "github.com/balzaczyy/golucene/core/analysis"
+	"io"
+)
+
+func init() {
+	RegisterTokenizerFactory("nGram", func(args map[string]string) (TokenizerFactory, error) {
+		f, err := NewNGramTokenizerFactory(args)
+		if err != nil {
+			return nil, err
+		}
+		return f, nil
+	})
+	RegisterTokenFilterFactory("nGram", func(args map[string]string) (TokenFilterFactory, error) {
+		f, err := NewNGramFilterFactory(args)
+		if err != nil {
+			return nil, err
+		}
+		return f, nil
+	})
+	RegisterTokenFilterFactory("edgeNGram", func(args map[string]string) (TokenFilterFactory, error) {
+		f, err := NewEdgeNGramFilterFactory(args)
+		if err != nil {
+			return nil, err
+		}
+		return f, nil
+	})
+}
+
+func checkGramSizes(minGram, maxGram int) error {
+	if minGram < 1 {
+		return errors.New("minGram must be greater than zero")
+	}
+	if minGram > maxGram {
+		return errors.New("minGram must not be greater than maxGram")
+	}
+	return nil
+}
+
+// ngram/NGramTokenizerFactory.java
+
+/* Factory for NGramTokenizer, taking the "minGramSize" (1 by default) and "maxGramSize" (2 by default). */
+type NGramTokenizerFactory struct {
+	*AbstractAnalysisFactory
+	minGramSize, maxGramSize int
+}
+
+func NewNGramTokenizerFactory(args map[string]string) (*NGramTokenizerFactory, error) {
+	ans := &NGramTokenizerFactory{AbstractAnalysisFactory: NewAbstractAnalysisFactory(args)}
+	ans.minGramSize = ans.GetInt("minGramSize", DEFAULT_MIN_NGRAM_SIZE)
+	ans.maxGramSize = ans.GetInt("maxGramSize", DEFAULT_MAX_NGRAM_SIZE)
+	if err := ans.Validate(); err != nil {
+		return nil, err
+	}
+	if err := checkGramSizes(ans.minGramSize, ans.maxGramSize); err != nil {
+		return nil, err
+	}
+	return ans, nil
+}
+
+func (f *NGramTokenizerFactory) Create(input io.RuneReader) TokenizerService {
+	return NewNGramTokenizer(f.LuceneMatchVersion(), input, f.minGramSize, f.maxGramSize)
+}
+
+// ngram/NGramFilterFactory.java
+
+/* Factory for NGramTokenFilter, taking the "minGramSize" (1 by default) and "maxGramSize" (2 by default). */
+type NGramFilterFactory struct {
+	*AbstractAnalysisFactory
+	minGramSize, maxGramSize int
+}
+
+func NewNGramFilterFactory(args map[string]string) (*NGramFilterFactory, error) {
+	ans := &NGramFilterFactory{AbstractAnalysisFactory: NewAbstractAnalysisFactory(args)}
+	ans.minGramSize = ans.GetInt("minGramSize", DEFAULT_MIN_NGRAM_SIZE)
+	ans.maxGramSize = ans.GetInt("maxGramSize", DEFAULT_MAX_NGRAM_SIZE)
+	if err := ans.Validate(); err != nil {
+		return nil, err
+	}
+	if err := checkGramSizes(ans.minGramSize, ans.maxGramSize); err != nil {
+		return nil, err
+	}
+	return ans, nil
+}
+
+func (f *NGramFilterFactory) Create(input TokenStream) TokenStream {
+	return NewNGramTokenFilter(f.LuceneMatchVersion(), input, f.minGramSize, f.maxGramSize)
+}
+
+// ngram/EdgeNGramFilterFactory.java
+
+/* Factory for EdgeNGramTokenFilter, taking the "minGramSize" and "maxGramSize" (both 1 by default). */
+type EdgeNGramFilterFactory struct {
+	*AbstractAnalysisFactory
+	minGramSize, maxGramSize int
+}
+
+func NewEdgeNGramFilterFactory(args map[string]string) (*EdgeNGramFilterFactory, error) {
+	ans := &EdgeNGramFilterFactory{AbstractAnalysisFactory: NewAbstractAnalysisFactory(args)}
+	ans.minGramSize = ans.GetInt("minGramSize", DEFAULT_MIN_GRAM_SIZE)
+	ans.maxGramSize = ans.GetInt("maxGramSize", DEFAULT_MAX_GRAM_SIZE)
+	if err := ans.Validate(); err != nil {
+		return nil, err
+	}
+	if err := checkGramSizes(ans.minGramSize, ans.maxGramSize); err != nil {
+		return nil, err
+	}
+	return ans, nil
+}
+
+func (f *EdgeNGramFilterFactory) Create(input TokenStream) TokenStream {
+	return NewEdgeNGramTokenFilter(f.LuceneMatchVersion(), input, f.minGramSize, f.maxGramSize)
+}
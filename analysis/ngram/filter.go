@@ -0,0 +1,174 @@
+package ngram
+
+import (
+	. "github.com/balzaczyy/golucene/core/analysis"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"github.com/balzaczyy/golucene/core/util"
+)
+
+// ngram/NGramTokenFilter.java
+
+/*
+Tokenizes the input into n-grams of the given size(s).
+
+The n-grams of a token are emitted sorted by start offset, then by
+size. They all keep the offsets of the original token, and all but
+the first have a position increment of 0, so that they stack at the
+position of the token. Tokens shorter than minGram are dropped.
+*/
+type NGramTokenFilter struct {
+	*TokenFilter
+	input            TokenStream
+	minGram, maxGram int
+
+	// current token, or nil to read the next one
+	curTerm   []rune
+	curState  *util.AttributeState
+	curPos    int
+	gramSize  int
+	curPosInc int
+
+	termAtt    CharTermAttribute
+	posIncrAtt PositionIncrementAttribute
+}
+
+/* Creates NGramTokenFilter with given min and max n-grams. */
+func NewNGramTokenFilter(version util.Version, in TokenStream, minGram, maxGram int) *NGramTokenFilter {
+	if minGram < 1 {
+		panic("minGram must be greater than zero")
+	}
+	if minGram > maxGram {
+		panic("minGram must not be greater than maxGram")
+	}
+	ans := &NGramTokenFilter{
+		TokenFilter: NewTokenFilter(in),
+		input:       in,
+		minGram:     minGram,
+		maxGram:     maxGram,
+	}
+	ans.termAtt = ans.Attributes().Add("CharTermAttribute").(CharTermAttribute)
+	ans.posIncrAtt = ans.Attributes().Add("PositionIncrementAttribute").(PositionIncrementAttribute)
+	return ans
+}
+
+/* Creates NGramTokenFilter with default min and max n-grams. */
+func NewDefaultNGramTokenFilter(version util.Version, in TokenStream) *NGramTokenFilter {
+	return NewNGramTokenFilter(version, in, DEFAULT_MIN_NGRAM_SIZE, DEFAULT_MAX_NGRAM_SIZE)
+}
+
+/* Returns the next token in the stream, or false at EOS. */
+func (f *NGramTokenFilter) IncrementToken() (bool, error) {
+	for {
+		if f.curTerm == nil {
+			ok, err := f.input.IncrementToken()
+			if err != nil || !ok {
+				return false, err
+			}
+			f.curTerm = make([]rune, f.termAtt.Length())
+			copy(f.curTerm, f.termAtt.Buffer())
+			f.curState = f.Attributes().CaptureState()
+			f.curPos, f.gramSize = 0, f.minGram
+			f.curPosInc = f.posIncrAtt.PositionIncrement()
+		}
+
+		if f.gramSize > f.maxGram || f.curPos+f.gramSize > len(f.curTerm) {
+			f.curPos++
+			f.gramSize = f.minGram
+		}
+		if f.curPos+f.gramSize <= len(f.curTerm) {
+			f.Attributes().RestoreState(f.curState)
+			f.termAtt.CopyBuffer(f.curTerm[f.curPos : f.curPos+f.gramSize])
+			f.posIncrAtt.SetPositionIncrement(f.curPosInc)
+			f.curPosInc = 0
+			f.gramSize++
+			return true, nil
+		}
+		f.curTerm = nil
+	}
+}
+
+func (f *NGramTokenFilter) Reset() error {
+	f.curTerm = nil
+	return f.TokenFilter.Reset()
+}
+
+// ngram/EdgeNGramTokenFilter.java
+
+const (
+	DEFAULT_MIN_GRAM_SIZE = 1
+	DEFAULT_MAX_GRAM_SIZE = 1
+)
+
+/*
+Tokenizes the given token into n-grams of given size(s), from the
+front of the token: "abc" with minGram=1 and maxGram=3 gives "a",
+"ab", "abc".
+
+As with NGramTokenFilter, the n-grams keep the offsets of the
+original token and stack at its position.
+*/
+type EdgeNGramTokenFilter struct {
+	*TokenFilter
+	input            TokenStream
+	minGram, maxGram int
+
+	// current token, or nil to read the next one
+	curTerm   []rune
+	curState  *util.AttributeState
+	gramSize  int
+	curPosInc int
+
+	termAtt    CharTermAttribute
+	posIncrAtt PositionIncrementAttribute
+}
+
+/* Creates EdgeNGramTokenFilter that can generate n-grams in the sizes of the given range */
+func NewEdgeNGramTokenFilter(version util.Version, in TokenStream, minGram, maxGram int) *EdgeNGramTokenFilter {
+	if minGram < 1 {
+		panic("minGram must be greater than zero")
+	}
+	if minGram > maxGram {
+		panic("minGram must not be greater than maxGram")
+	}
+	ans := &EdgeNGramTokenFilter{
+		TokenFilter: NewTokenFilter(in),
+		input:       in,
+		minGram:     minGram,
+		maxGram:     maxGram,
+	}
+	ans.termAtt = ans.Attributes().Add("CharTermAttribute").(CharTermAttribute)
+	ans.posIncrAtt = ans.Attributes().Add("PositionIncrementAttribute").(PositionIncrementAttribute)
+	return ans
+}
+
+func (f *EdgeNGramTokenFilter) IncrementToken() (bool, error) {
+	for {
+		if f.curTerm == nil {
+			ok, err := f.input.IncrementToken()
+			if err != nil || !ok {
+				return false, err
+			}
+			f.curTerm = make([]rune, f.termAtt.Length())
+			copy(f.curTerm, f.termAtt.Buffer())
+			f.curState = f.Attributes().CaptureState()
+			f.gramSize = f.minGram
+			f.curPosInc = f.posIncrAtt.PositionIncrement()
+		}
+
+		if f.gramSize <= f.maxGram && f.gramSize <= len(f.curTerm) {
+			// grow gramSize up to the token length
+			f.Attributes().RestoreState(f.curState)
+			f.termAtt.CopyBuffer(f.curTerm[:f.gramSize])
+			f.posIncrAtt.SetPositionIncrement(f.curPosInc)
+			f.curPosInc = 0
+			f.gramSize++
+			return true, nil
+		}
+		f.curTerm = nil
+	}
+}
+
+func (f *EdgeNGramTokenFilter) Reset() error {
+	f.curTerm = nil
+	return f.TokenFilter.Reset()
+}
@@ -0,0 +1,84 @@
+package ngram
+
+import (
+	"fmt"
+	std "github.com/balzaczyy/golucene/analysis/standard"
+	. "github.com/balzaczyy/golucene/core/analysis"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"github.com/balzaczyy/golucene/core/util"
+	"strings"
+	"testing"
+)
+
+/* Returns the tokens as term/posInc/offsets, then the final offset. */
+func grams(t *testing.T, ts TokenStream) string {
+	termAtt := ts.Attributes().Get("CharTermAttribute").(CharTermAttribute)
+	posIncAtt := ts.Attributes().Get("PositionIncrementAttribute").(PositionIncrementAttribute)
+	offsetAtt := ts.Attributes().Get("OffsetAttribute").(OffsetAttribute)
+	if err := ts.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	var tokens []string
+	for {
+		ok, err := ts.IncrementToken()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			break
+		}
+		tokens = append(tokens, fmt.Sprintf("%v/%v/%v-%v", string(termAtt.Buffer()[:termAtt.Length()]),
+			posIncAtt.PositionIncrement(), offsetAtt.StartOffset(), offsetAtt.EndOffset()))
+	}
+	if err := ts.End(); err != nil {
+		t.Fatal(err)
+	}
+	tokens = append(tokens, fmt.Sprintf("(%v)", offsetAtt.EndOffset()))
+	if err := ts.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return strings.Join(tokens, " ")
+}
+
+func TestNGramTokenizer(t *testing.T) {
+	for _, v := range []struct {
+		text             string
+		minGram, maxGram int
+		expected         string
+	}{
+		{"abcde", 1, 2, "a/1/0-1 ab/1/0-2 b/1/1-2 bc/1/1-3 c/1/2-3 cd/1/2-4 d/1/3-4 de/1/3-5 e/1/4-5 (5)"},
+		{"abcde", 3, 3, "abc/1/0-3 bcd/1/1-4 cde/1/2-5 (5)"},
+		{"abcde", 6, 7, "(5)"},
+		{"日本語", 2, 2, "日本/1/0-2 本語/1/1-3 (3)"},
+	} {
+		ts := NewNGramTokenizer(util.VERSION_LATEST, strings.NewReader(v.text), v.minGram, v.maxGram)
+		if got := grams(t, ts); got != v.expected {
+			t.Errorf("%q %v-%v: expected %v, but was %v", v.text, v.minGram, v.maxGram, v.expected, got)
+		}
+	}
+}
+
+func tokenizer(text string) TokenStream {
+	return std.NewStandardTokenizer(util.VERSION_LATEST, strings.NewReader(text))
+}
+
+func TestNGramTokenFilter(t *testing.T) {
+	ts := NewNGramTokenFilter(util.VERSION_LATEST, tokenizer("abc de f"), 1, 2)
+	if got, expected := grams(t, ts),
+		"a/1/0-3 ab/0/0-3 b/0/0-3 bc/0/0-3 c/0/0-3 d/1/4-6 de/0/4-6 e/0/4-6 f/1/7-8 (8)"; got != expected {
+		t.Errorf("expected %v, but was %v", expected, got)
+	}
+
+	ts = NewNGramTokenFilter(util.VERSION_LATEST, tokenizer("abc de f"), 3, 3)
+	if got, expected := grams(t, ts), "abc/1/0-3 (8)"; got != expected {
+		t.Errorf("expected %v, but was %v", expected, got)
+	}
+}
+
+func TestEdgeNGramTokenFilter(t *testing.T) {
+	ts := NewEdgeNGramTokenFilter(util.VERSION_LATEST, tokenizer("abcde fg"), 1, 3)
+	if got, expected := grams(t, ts),
+		"a/1/0-5 ab/0/0-5 abc/0/0-5 f/1/6-8 fg/0/6-8 (8)"; got != expected {
+		t.Errorf("expected %v, but was %v", expected, got)
+	}
+}
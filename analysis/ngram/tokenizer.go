@@ -0,0 +1,122 @@
+package ngram
+
+import (
+	. "github.com/balzaczyy/golucene/core/analysis"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"github.com/balzaczyy/golucene/core/util"
+	"io"
+)
+
+// ngram/NGramTokenizer.java
+
+const (
+	DEFAULT_MIN_NGRAM_SIZE = 1
+	DEFAULT_MAX_NGRAM_SIZE = 2
+)
+
+/*
+Tokenizes the input into n-grams of the given size(s).
+
+On the contrary to NGramTokenFilter, this tokenizer emits n-grams
+sorted by start offset, then by size, each with its own offsets and a
+position increment of 1. For example, "abcde" with minGram=1 and
+maxGram=2 is tokenized into:
+
+	Term          a  ab  b  bc  c  cd  d  de  e
+	Position incr 1  1   1  1   1  1   1  1   1
+	Offsets       0-1 0-2 1-2 1-3 2-3 2-4 3-4 3-5 4-5
+
+Offsets are counted in runes.
+*/
+type NGramTokenizer struct {
+	*Tokenizer
+
+	minGram, maxGram int
+
+	// the whole input, read on the first call to IncrementToken()
+	buffer []rune
+	read   bool
+	// start and size of the next gram
+	pos, gramSize int
+
+	termAtt    CharTermAttribute
+	offsetAtt  OffsetAttribute
+	posIncrAtt PositionIncrementAttribute
+	posLenAtt  PositionLengthAttribute
+}
+
+/* Creates NGramTokenizer with given min and max n-grams. */
+func NewNGramTokenizer(version util.Version, input io.RuneReader, minGram, maxGram int) *NGramTokenizer {
+	if minGram < 1 {
+		panic("minGram must be greater than zero")
+	}
+	if minGram > maxGram {
+		panic("minGram must not be greater than maxGram")
+	}
+	ans := &NGramTokenizer{
+		Tokenizer: NewTokenizer(input),
+		minGram:   minGram,
+		maxGram:   maxGram,
+	}
+	ans.termAtt = ans.Attributes().Add("CharTermAttribute").(CharTermAttribute)
+	ans.offsetAtt = ans.Attributes().Add("OffsetAttribute").(OffsetAttribute)
+	ans.posIncrAtt = ans.Attributes().Add("PositionIncrementAttribute").(PositionIncrementAttribute)
+	ans.posLenAtt = ans.Attributes().Add("PositionLengthAttribute").(PositionLengthAttribute)
+	return ans
+}
+
+/* Creates NGramTokenizer with default min and max n-grams. */
+func NewDefaultNGramTokenizer(version util.Version, input io.RuneReader) *NGramTokenizer {
+	return NewNGramTokenizer(version, input, DEFAULT_MIN_NGRAM_SIZE, DEFAULT_MAX_NGRAM_SIZE)
+}
+
+func (t *NGramTokenizer) IncrementToken() (bool, error) {
+	t.Attributes().Clear()
+	if !t.read {
+		for {
+			ch, _, err := t.Input.ReadRune()
+			if err == io.EOF {
+				break
+			} else if err != nil {
+				return false, err
+			}
+			t.buffer = append(t.buffer, ch)
+		}
+		t.read = true
+	}
+
+	if t.gramSize > t.maxGram || t.pos+t.gramSize > len(t.buffer) {
+		t.pos++
+		t.gramSize = t.minGram
+	}
+	if t.pos+t.gramSize > len(t.buffer) {
+		return false, nil
+	}
+
+	t.termAtt.CopyBuffer(t.buffer[t.pos : t.pos+t.gramSize])
+	t.posIncrAtt.SetPositionIncrement(1)
+	t.posLenAtt.SetPositionLength(1)
+	t.offsetAtt.SetOffset(t.CorrectOffset(t.pos), t.CorrectOffset(t.pos+t.gramSize))
+	t.gramSize++
+	return true, nil
+}
+
+func (t *NGramTokenizer) End() error {
+	if err := t.Tokenizer.End(); err != nil {
+		return err
+	}
+	// set final offset
+	finalOffset := t.CorrectOffset(len(t.buffer))
+	t.offsetAtt.SetOffset(finalOffset, finalOffset)
+	return nil
+}
+
+func (t *NGramTokenizer) Reset() error {
+	if err := t.Tokenizer.Reset(); err != nil {
+		return err
+	}
+	t.buffer = t.buffer[:0]
+	t.read = false
+	t.pos, t.gramSize = 0, t.minGram
+	return nil
+}
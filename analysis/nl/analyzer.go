@@ -0,0 +1,88 @@
+package nl
+
+import (
+	. "github.com/balzaczyy/golucene/analysis/core"
+	. "github.com/balzaczyy/golucene/analysis/miscellaneous"
+	"github.com/balzaczyy/golucene/analysis/snowball"
+	std "github.com/balzaczyy/golucene/analysis/standard"
+	. "github.com/balzaczyy/golucene/analysis/util"
+	. "github.com/balzaczyy/golucene/core/analysis"
+	"io"
+)
+
+// nl/DutchAnalyzer.java
+
+/* The default set of stopwords used by DutchAnalyzer, from the Snowball dutch stop list. */
+var DEFAULT_STOPWORD_SET = map[string]bool{
+	"de": true, "en": true, "van": true, "ik": true, "te": true,
+	"dat": true, "die": true, "in": true, "een": true, "hij": true,
+	"het": true, "niet": true, "zijn": true, "is": true, "was": true,
+	"op": true, "aan": true, "met": true, "als": true, "voor": true,
+	"had": true, "er": true, "maar": true, "om": true, "hem": true,
+	"dan": true, "zou": true, "of": true, "wat": true, "mijn": true,
+	"men": true, "dit": true, "zo": true, "door": true, "over": true,
+	"ze": true, "zich": true, "bij": true, "ook": true, "tot": true,
+	"je": true, "mij": true, "uit": true, "der": true, "daar": true,
+	"haar": true, "naar": true, "heb": true, "hoe": true, "heeft": true,
+	"hebben": true, "deze": true, "u": true, "want": true, "nog": true,
+	"zal": true, "me": true, "zij": true, "nu": true, "ge": true,
+	"geen": true, "omdat": true, "iets": true, "worden": true,
+	"toch": true, "al": true, "waren": true, "veel": true, "meer": true,
+	"doen": true, "toen": true, "moet": true, "ben": true, "zonder": true,
+	"kan": true, "hun": true, "dus": true, "alles": true, "onder": true,
+	"ja": true, "eens": true, "hier": true, "wie": true, "werd": true,
+	"altijd": true, "doch": true, "wordt": true, "wezen": true,
+	"kunnen": true, "ons": true, "zelf": true, "tegen": true, "na": true,
+	"reeds": true, "wil": true, "kon": true, "niets": true, "uw": true,
+	"iemand": true, "geweest": true, "andere": true,
+}
+
+/*
+Analyzer for Dutch.
+
+Builds an analysis chain made of StandardTokenizer, StandardFilter,
+LowerCaseFilter, StopFilter, SetKeywordMarkerFilter if a stem
+exclusion set is provided, and SnowballFilter with the dutch stemmer.
+*/
+type DutchAnalyzer struct {
+	*StopwordAnalyzerBase
+	stemExclusionSet map[string]bool
+}
+
+/* Builds an analyzer with the default stop words: DEFAULT_STOPWORD_SET. */
+func NewDutchAnalyzer() *DutchAnalyzer {
+	return NewDutchAnalyzerWithStopWords(DEFAULT_STOPWORD_SET)
+}
+
+/* Builds an analyzer with the given stop words. */
+func NewDutchAnalyzerWithStopWords(stopwords map[string]bool) *DutchAnalyzer {
+	return NewDutchAnalyzerWithStemExclusions(stopwords, nil)
+}
+
+/*
+Builds an analyzer with the given stop words. If a non-empty stem
+exclusion set is provided this analyzer will add a
+SetKeywordMarkerFilter before stemming.
+*/
+func NewDutchAnalyzerWithStemExclusions(stopwords, stemExclusionSet map[string]bool) *DutchAnalyzer {
+	ans := &DutchAnalyzer{stemExclusionSet: make(map[string]bool)}
+	for k, v := range stemExclusionSet {
+		ans.stemExclusionSet[k] = v
+	}
+	ans.StopwordAnalyzerBase = NewStopwordAnalyzerBaseWithStopWords(stopwords)
+	ans.Spi = ans
+	return ans
+}
+
+func (a *DutchAnalyzer) CreateComponents(fieldName string, reader io.RuneReader) *TokenStreamComponents {
+	version := a.Version()
+	source := std.NewStandardTokenizer(version, reader)
+	var result TokenStream = std.NewStandardFilter(version, source)
+	result = NewLowerCaseFilter(version, result)
+	result = NewStopFilter(version, result, a.StopwordSet())
+	if len(a.stemExclusionSet) > 0 {
+		result = NewSetKeywordMarkerFilter(result, a.stemExclusionSet)
+	}
+	result = snowball.NewSnowballFilter(result, snowball.DutchStemmer{})
+	return NewTokenStreamComponents(source, result)
+}
@@ -0,0 +1,104 @@
+package no
+
+import (
+	. "github.com/balzaczyy/golucene/analysis/core"
+	. "github.com/balzaczyy/golucene/analysis/miscellaneous"
+	"github.com/balzaczyy/golucene/analysis/snowball"
+	std "github.com/balzaczyy/golucene/analysis/standard"
+	. "github.com/balzaczyy/golucene/analysis/util"
+	. "github.com/balzaczyy/golucene/core/analysis"
+	"io"
+)
+
+// no/NorwegianAnalyzer.java
+
+/* The default set of stopwords used by NorwegianAnalyzer, from the Snowball norwegian stop list. */
+var DEFAULT_STOPWORD_SET = map[string]bool{
+	"og": true, "i": true, "jeg": true, "det": true, "at": true,
+	"en": true, "et": true, "den": true, "til": true, "er": true,
+	"som": true, "på": true, "de": true, "med": true, "han": true,
+	"av": true, "ikke": true, "ikkje": true, "der": true, "så": true,
+	"var": true, "meg": true, "seg": true, "men": true, "ett": true,
+	"har": true, "om": true, "vi": true, "min": true, "mitt": true,
+	"ha": true, "hadde": true, "hun": true, "nå": true, "over": true,
+	"da": true, "ved": true, "fra": true, "du": true, "ut": true,
+	"sin": true, "dem": true, "oss": true, "opp": true, "man": true,
+	"kan": true, "hans": true, "hvor": true, "eller": true, "hva": true,
+	"skal": true, "selv": true, "sjøl": true, "her": true, "alle": true,
+	"vil": true, "bli": true, "ble": true, "blei": true, "blitt": true,
+	"kunne": true, "inn": true, "når": true, "være": true, "kom": true,
+	"noen": true, "noe": true, "ville": true, "dere": true, "deres": true,
+	"kun": true, "ja": true, "etter": true, "ned": true, "skulle": true,
+	"denne": true, "for": true, "deg": true, "si": true, "sine": true,
+	"sitt": true, "mot": true, "å": true, "meget": true, "hvorfor": true,
+	"dette": true, "disse": true, "uten": true, "hvordan": true,
+	"ingen": true, "din": true, "ditt": true, "blir": true, "samme": true,
+	"hvilken": true, "hvilke": true, "sånn": true, "inni": true,
+	"mellom": true, "vår": true, "hver": true, "hvem": true, "vors": true,
+	"hvis": true, "både": true, "bare": true, "enn": true, "fordi": true,
+	"før": true, "mange": true, "også": true, "slik": true, "vært": true,
+	"båe": true, "begge": true, "siden": true, "dykk": true,
+	"dykkar": true, "dei": true, "deira": true, "deires": true,
+	"deim": true, "di": true, "då": true, "eg": true, "ein": true,
+	"eit": true, "eitt": true, "elles": true, "honom": true, "hjå": true,
+	"ho": true, "hoe": true, "henne": true, "hennar": true,
+	"hennes": true, "hoss": true, "hossen": true, "ingi": true,
+	"inkje": true, "korleis": true, "korso": true, "kva": true,
+	"kvar": true, "kvarhelst": true, "kven": true, "kvi": true,
+	"kvifor": true, "me": true, "medan": true, "mi": true, "mine": true,
+	"mykje": true, "no": true, "nokon": true, "noka": true, "nokor": true,
+	"noko": true, "nokre": true, "sia": true, "sidan": true, "so": true,
+	"somt": true, "somme": true, "um": true, "upp": true, "vere": true,
+	"vore": true, "verte": true, "vort": true, "varte": true,
+	"vart": true,
+}
+
+/*
+Analyzer for Norwegian.
+
+Builds an analysis chain made of StandardTokenizer, StandardFilter,
+LowerCaseFilter, StopFilter, SetKeywordMarkerFilter if a stem
+exclusion set is provided, and SnowballFilter with the norwegian stemmer.
+*/
+type NorwegianAnalyzer struct {
+	*StopwordAnalyzerBase
+	stemExclusionSet map[string]bool
+}
+
+/* Builds an analyzer with the default stop words: DEFAULT_STOPWORD_SET. */
+func NewNorwegianAnalyzer() *NorwegianAnalyzer {
+	return NewNorwegianAnalyzerWithStopWords(DEFAULT_STOPWORD_SET)
+}
+
+/* Builds an analyzer with the given stop words. */
+func NewNorwegianAnalyzerWithStopWords(stopwords map[string]bool) *NorwegianAnalyzer {
+	return NewNorwegianAnalyzerWithStemExclusions(stopwords, nil)
+}
+
+/*
+Builds an analyzer with the given stop words. If a non-empty stem
+exclusion set is provided this analyzer will add a
+SetKeywordMarkerFilter before stemming.
+*/
+func NewNorwegianAnalyzerWithStemExclusions(stopwords, stemExclusionSet map[string]bool) *NorwegianAnalyzer {
+	ans := &NorwegianAnalyzer{stemExclusionSet: make(map[string]bool)}
+	for k, v := range stemExclusionSet {
+		ans.stemExclusionSet[k] = v
+	}
+	ans.StopwordAnalyzerBase = NewStopwordAnalyzerBaseWithStopWords(stopwords)
+	ans.Spi = ans
+	return ans
+}
+
+func (a *NorwegianAnalyzer) CreateComponents(fieldName string, reader io.RuneReader) *TokenStreamComponents {
+	version := a.Version()
+	source := std.NewStandardTokenizer(version, reader)
+	var result TokenStream = std.NewStandardFilter(version, source)
+	result = NewLowerCaseFilter(version, result)
+	result = NewStopFilter(version, result, a.StopwordSet())
+	if len(a.stemExclusionSet) > 0 {
+		result = NewSetKeywordMarkerFilter(result, a.stemExclusionSet)
+	}
+	result = snowball.NewSnowballFilter(result, snowball.NorwegianStemmer{})
+	return NewTokenStreamComponents(source, result)
+}
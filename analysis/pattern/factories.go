@@ -0,0 +1,120 @@
+package pattern
+
+import (
+	"fmt"
+	. "github.com/balzaczyy/golucene/analysis/util"
+	. "github.com/balzaczyy/golucene/core/analysis"
+	"io"
+	"regexp"
+)
+
+func init() {
+	RegisterTokenizerFactory("pattern", func(args map[string]string) (TokenizerFactory, error) {
+		f, err := NewPatternTokenizerFactory(args)
+		if err != nil {
+			return nil, err
+		}
+		return f, nil
+	})
+	RegisterTokenFilterFactory("patternReplace", func(args map[string]string) (TokenFilterFactory, error) {
+		f, err := NewPatternReplaceFilterFactory(args)
+		if err != nil {
+			return nil, err
+		}
+		return f, nil
+	})
+	RegisterCharFilterFactory("patternReplace", func(args map[string]string) (CharFilterFactory, error) {
+		f, err := NewPatternReplaceCharFilterFactory(args)
+		if err != nil {
+			return nil, err
+		}
+		return f, nil
+	})
+}
+
+// pattern/PatternTokenizerFactory.java
+
+/*
+Factory for PatternTokenizer. This tokenizer uses regex pattern
+matching to construct distinct tokens for the input stream. It takes
+two arguments: "pattern" and "group".
+
+	- "pattern" is the regular expression.
+	- "group" says which group to extract into tokens, -1 (the
+	  default) to split on the pattern.
+*/
+type PatternTokenizerFactory struct {
+	*AbstractAnalysisFactory
+	pattern *regexp.Regexp
+	group   int
+}
+
+func NewPatternTokenizerFactory(args map[string]string) (*PatternTokenizerFactory, error) {
+	ans := &PatternTokenizerFactory{AbstractAnalysisFactory: NewAbstractAnalysisFactory(args)}
+	ans.pattern = ans.RequirePattern("pattern")
+	ans.group = ans.GetInt("group", -1)
+	if err := ans.Validate(); err != nil {
+		return nil, err
+	}
+	if ans.group > ans.pattern.NumSubexp() {
+		return nil, fmt.Errorf("invalid group specified: pattern only has: %v capturing groups",
+			ans.pattern.NumSubexp())
+	}
+	return ans, nil
+}
+
+func (f *PatternTokenizerFactory) Create(input io.RuneReader) TokenizerService {
+	return NewPatternTokenizer(input, f.pattern, f.group)
+}
+
+// pattern/PatternReplaceFilterFactory.java
+
+/*
+Factory for PatternReplaceFilter. It takes the "pattern", the
+"replacement" (empty by default), and whether to "replace" "all" (the
+default) or only the "first" occurrence.
+*/
+type PatternReplaceFilterFactory struct {
+	*AbstractAnalysisFactory
+	pattern     *regexp.Regexp
+	replacement string
+	replaceAll  bool
+}
+
+func NewPatternReplaceFilterFactory(args map[string]string) (*PatternReplaceFilterFactory, error) {
+	ans := &PatternReplaceFilterFactory{AbstractAnalysisFactory: NewAbstractAnalysisFactory(args)}
+	ans.pattern = ans.RequirePattern("pattern")
+	ans.replacement = ans.Get("replacement", "")
+	ans.replaceAll = ans.GetOneOf("replace", "all", "all", "first") == "all"
+	if err := ans.Validate(); err != nil {
+		return nil, err
+	}
+	return ans, nil
+}
+
+func (f *PatternReplaceFilterFactory) Create(input TokenStream) TokenStream {
+	return NewPatternReplaceFilter(input, f.pattern, f.replacement, f.replaceAll)
+}
+
+// pattern/PatternReplaceCharFilterFactory.java
+
+/* Factory for PatternReplaceCharFilter, taking the "pattern" and the "replacement" (empty by default). */
+type PatternReplaceCharFilterFactory struct {
+	*AbstractAnalysisFactory
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+func NewPatternReplaceCharFilterFactory(args map[string]string) (*PatternReplaceCharFilterFactory, error) {
+	ans := &PatternReplaceCharFilterFactory{AbstractAnalysisFactory: NewAbstractAnalysisFactory(args)}
+	ans.pattern = ans.RequirePattern("pattern")
+	ans.replacement = ans.Get("replacement", "")
+	if err := ans.Validate(); err != nil {
+		return nil, err
+	}
+	return ans, nil
+}
+
+func (f *PatternReplaceCharFilterFactory) Create(input io.RuneReader) io.RuneReader {
+	return NewPatternReplaceCharFilter(f.pattern, f.replacement, input)
+}
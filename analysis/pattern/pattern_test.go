@@ -0,0 +1,126 @@
+package pattern
+
+import (
+	"fmt"
+	. "github.com/balzaczyy/golucene/core/analysis"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+/* Returns the tokens of the stream, as "term[start-end]". */
+func tokens(t *testing.T, ts TokenStream) []string {
+	termAtt := ts.Attributes().Add("CharTermAttribute").(CharTermAttribute)
+	offsetAtt := ts.Attributes().Add("OffsetAttribute").(OffsetAttribute)
+	if err := ts.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	var ans []string
+	for {
+		ok, err := ts.IncrementToken()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			break
+		}
+		ans = append(ans, fmt.Sprintf("%v[%v-%v]", string(termAtt.Buffer()[:termAtt.Length()]),
+			offsetAtt.StartOffset(), offsetAtt.EndOffset()))
+	}
+	if err := ts.End(); err != nil {
+		t.Fatal(err)
+	}
+	if err := ts.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return ans
+}
+
+func assertTokens(t *testing.T, ts TokenStream, expected ...string) {
+	if got := tokens(t, ts); fmt.Sprint(got) != fmt.Sprint(expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+/* Splits the input on whitespace. */
+func whitespace(input io.RuneReader) *PatternTokenizer {
+	return NewPatternTokenizer(input, regexp.MustCompile(`\s+`), -1)
+}
+
+func TestPatternTokenizerSplit(t *testing.T) {
+	for _, c := range []struct {
+		pattern, input string
+		expected       []string
+	}{
+		{"--", "aaa--bbb--ccc", []string{"aaa[0-3]", "bbb[5-8]", "ccc[10-13]"}},
+		{":", "aaa:bbb:ccc", []string{"aaa[0-3]", "bbb[4-7]", "ccc[8-11]"}},
+		{`\s+`, "aaa   bbb \t\tccc  ", []string{"aaa[0-3]", "bbb[6-9]", "ccc[12-15]"}},
+		{":", "boo:and:foo", []string{"boo[0-3]", "and[4-7]", "foo[8-11]"}},
+		{"o", "boo:and:foo", []string{"b[0-1]", ":and:f[3-9]"}},
+		{`\s+`, "über straße", []string{"über[0-4]", "straße[5-11]"}},
+	} {
+		assertTokens(t, NewPatternTokenizer(strings.NewReader(c.input), regexp.MustCompile(c.pattern), -1),
+			c.expected...)
+	}
+}
+
+func TestPatternTokenizerGroup(t *testing.T) {
+	input := "aaa 'bbb' 'ccc'"
+	assertTokens(t, NewPatternTokenizer(strings.NewReader(input), regexp.MustCompile(`'([^']+)'`), 0),
+		"'bbb'[4-9]", "'ccc'[10-15]")
+	assertTokens(t, NewPatternTokenizer(strings.NewReader(input), regexp.MustCompile(`'([^']+)'`), 1),
+		"bbb[5-8]", "ccc[11-14]")
+	assertTokens(t, NewPatternTokenizer(strings.NewReader(input), regexp.MustCompile(`^`), 0))
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for a missing group")
+		}
+	}()
+	NewPatternTokenizer(strings.NewReader(input), regexp.MustCompile(`'([^']+)'`), 2)
+}
+
+func TestPatternReplaceFilter(t *testing.T) {
+	assertTokens(t, NewPatternReplaceFilter(whitespace(strings.NewReader("aabfooaabfooabfoob ab caaaaaaaaab")),
+		regexp.MustCompile(`a*b`), "-", false),
+		"-fooaabfooabfoob[0-18]", "-[19-21]", "c-[22-33]")
+	assertTokens(t, NewPatternReplaceFilter(whitespace(strings.NewReader("aabfooaabfooabfoob ab caaaaaaaaab")),
+		regexp.MustCompile(`a*b`), "-", true),
+		"-foo-foo-foo-[0-18]", "-[19-21]", "c-[22-33]")
+	assertTokens(t, NewPatternReplaceFilter(whitespace(strings.NewReader("aabfooaabfooabfoob ab caaaaaaaaab")),
+		regexp.MustCompile(`(a*)b`), "${1}Z", false),
+		"aaZfooaabfooabfoob[0-18]", "aZ[19-21]", "caaaaaaaaaZ[22-33]")
+	assertTokens(t, NewPatternReplaceFilter(whitespace(strings.NewReader("aabfooaabfooabfoob ab caaaaaaaaab")),
+		regexp.MustCompile(`a*b`), "", true),
+		"foofoofoo[0-18]", "[19-21]", "c[22-33]")
+}
+
+func TestPatternReplaceCharFilter(t *testing.T) {
+	for _, c := range []struct {
+		input, pattern, replacement string
+		expected                    []string
+	}{
+		// nothing changes
+		{"this is test.", `(aa)\s+(bb)\s+(cc)`, "$1$2$3",
+			[]string{"this[0-4]", "is[5-7]", "test.[8-13]"}},
+		// one block, one match, same length
+		{"aa bb cc", `(aa)\s+(bb)\s+(cc)`, "$1#$2#$3", []string{"aa#bb#cc[0-8]"}},
+		// one block, one match, longer
+		{"aa bb cc dd", `(aa)\s+(bb)\s+(cc)`, "$1##$2###$3", []string{"aa##bb###cc[0-8]", "dd[9-11]"}},
+		// one block, two matches, longer
+		{" a  a", "a", "aa", []string{"aa[1-2]", "aa[4-5]"}},
+		// one block, one match, shorter
+		{"aa  bb   cc dd", `(aa)\s+(bb)\s+(cc)`, "$1#$2", []string{"aa#bb[0-11]", "dd[12-14]"}},
+		// one block, several matches
+		{"  aa bb cc --- aa bb aa   bb   cc", `(aa)\s+(bb)\s+(cc)`, "$1  $2  $3",
+			[]string{"aa[2-4]", "bb[6-8]", "cc[9-10]", "---[11-14]", "aa[15-17]", "bb[18-20]",
+				"aa[21-23]", "bb[25-27]", "cc[29-33]"}},
+		// matches that remove everything
+		{"aa bb", `\w+`, "", nil},
+	} {
+		filter := NewPatternReplaceCharFilter(regexp.MustCompile(c.pattern), c.replacement, strings.NewReader(c.input))
+		assertTokens(t, whitespace(filter), c.expected...)
+	}
+}
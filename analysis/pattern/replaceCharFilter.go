@@ -0,0 +1,118 @@
+package pattern
+
+import (
+	. "github.com/balzaczyy/golucene/core/analysis"
+	"io"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// pattern/PatternReplaceCharFilter.java
+
+/*
+CharFilter that uses a regular expression for the target of replace
+string. The pattern match will be done in each "block" in char
+stream.
+
+ex1) source="aa  bb aa bb", pattern="(aa)\\s+(bb)" replacement="$1#$2"
+output="aa#bb aa#bb"
+
+NOTE: If you produce a phrase that has different length to source
+string and the field is used for highlighting for a term of the
+phrase, you will face a trouble.
+
+ex2) source="aa123bb", pattern="(aa)\\d+(bb)" replacement="$1 $2"
+output="aa bb" and you want to search bb and highlight it, you will
+get highlight snippet="aa1<em>23bb</em>"
+*/
+type PatternReplaceCharFilter struct {
+	*BaseCharFilter
+	pattern     *regexp.Regexp
+	replacement string
+
+	output []rune
+	pos    int
+	read   bool
+}
+
+/*
+Creates a PatternReplaceCharFilter replacing the matches of pattern
+in the input. The replacement is expanded as by Regexp.Expand().
+*/
+func NewPatternReplaceCharFilter(pattern *regexp.Regexp, replacement string, in io.RuneReader) *PatternReplaceCharFilter {
+	return &PatternReplaceCharFilter{
+		BaseCharFilter: NewBaseCharFilter(in),
+		pattern:        pattern,
+		replacement:    replacement,
+	}
+}
+
+func (f *PatternReplaceCharFilter) ReadRune() (rune, int, error) {
+	if !f.read {
+		if err := f.fill(); err != nil {
+			return 0, 0, err
+		}
+		f.read = true
+	}
+	if f.pos >= len(f.output) {
+		return 0, 0, io.EOF
+	}
+	ch := f.output[f.pos]
+	f.pos++
+	return ch, 1, nil
+}
+
+func (f *PatternReplaceCharFilter) fill() error {
+	var b strings.Builder
+	for {
+		ch, _, err := f.Input.ReadRune()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		b.WriteRune(ch)
+	}
+	f.output = []rune(f.processPattern(b.String()))
+	return nil
+}
+
+/* Replace pattern in input and mark correction offsets. */
+func (f *PatternReplaceCharFilter) processPattern(input string) string {
+	var cumulativeOutput []byte
+	outputLength := 0 // in runes
+	cumulative := 0
+	lastMatchEnd := 0
+	for _, m := range f.pattern.FindAllStringSubmatchIndex(input, -1) {
+		groupSize := utf8.RuneCountInString(input[m[0]:m[1]])
+		skipped := input[lastMatchEnd:m[0]]
+		lastMatchEnd = m[1]
+
+		cumulativeOutput = append(cumulativeOutput, skipped...)
+		lengthBeforeReplacement := outputLength + utf8.RuneCountInString(skipped)
+		replaced := f.pattern.ExpandString(nil, f.replacement, input, m)
+		cumulativeOutput = append(cumulativeOutput, replaced...)
+		replacementSize := utf8.RuneCount(replaced)
+		outputLength = lengthBeforeReplacement + replacementSize
+
+		if replacementSize < groupSize {
+			// The replacement is smaller. Add the 'backskip' to the next
+			// index after the replacement (this is possibly after the
+			// end of string, but it's fine -- it just means the last
+			// character of the replaced block doesn't reach the end of
+			// the original string.
+			cumulative += groupSize - replacementSize
+			f.AddOffCorrectMap(lengthBeforeReplacement+replacementSize, cumulative)
+		} else {
+			// The replacement is larger. Every new index needs to point
+			// to the last element of the original group (if any).
+			for i := groupSize; i < replacementSize; i++ {
+				cumulative--
+				f.AddOffCorrectMap(lengthBeforeReplacement+i, cumulative)
+			}
+		}
+	}
+	// Append the remaining output, no further changes to indices.
+	return string(append(cumulativeOutput, input[lastMatchEnd:]...))
+}
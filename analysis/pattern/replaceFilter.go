@@ -0,0 +1,70 @@
+package pattern
+
+import (
+	. "github.com/balzaczyy/golucene/core/analysis"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"regexp"
+)
+
+// pattern/PatternReplaceFilter.java
+
+/*
+A TokenFilter which applies a Pattern to each token in the stream,
+replacing match occurrences with the specified replacement string.
+
+Note: Depending on the input and the pattern used and the input
+TokenStream, this TokenFilter may produce Tokens whose text is the
+empty string.
+*/
+type PatternReplaceFilter struct {
+	*TokenFilter
+	input       TokenStream
+	pattern     *regexp.Regexp
+	replacement string
+	all         bool
+	termAtt     CharTermAttribute
+}
+
+/*
+Constructs an instance to replace either the first, or all
+occurrences.
+
+The replacement is expanded as by Regexp.Expand(): "$1" or "${1}"
+insert the text of the first group, and "${name}" the one of a named
+group.
+*/
+func NewPatternReplaceFilter(in TokenStream, pattern *regexp.Regexp, replacement string, all bool) *PatternReplaceFilter {
+	ans := &PatternReplaceFilter{
+		TokenFilter: NewTokenFilter(in),
+		input:       in,
+		pattern:     pattern,
+		replacement: replacement,
+		all:         all,
+	}
+	ans.termAtt = ans.Attributes().Add("CharTermAttribute").(CharTermAttribute)
+	return ans
+}
+
+func (f *PatternReplaceFilter) IncrementToken() (bool, error) {
+	ok, err := f.input.IncrementToken()
+	if err != nil || !ok {
+		return false, err
+	}
+
+	term := string(f.termAtt.Buffer()[:f.termAtt.Length()])
+	var transformed string
+	if f.all {
+		transformed = f.pattern.ReplaceAllString(term, f.replacement)
+	} else {
+		m := f.pattern.FindStringSubmatchIndex(term)
+		if m == nil {
+			return true, nil
+		}
+		dst := append([]byte(term[:m[0]]), f.pattern.ExpandString(nil, f.replacement, term, m)...)
+		transformed = string(append(dst, term[m[1]:]...))
+	}
+	if transformed != term {
+		f.termAtt.CopyBuffer([]rune(transformed))
+	}
+	return true, nil
+}
@@ -0,0 +1,170 @@
+package pattern
+
+import (
+	"fmt"
+	. "github.com/balzaczyy/golucene/core/analysis"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"io"
+	"math"
+	"regexp"
+	"strings"
+)
+
+// pattern/PatternTokenizer.java
+
+/*
+This tokenizer uses regex pattern matching to construct distinct
+tokens for the input stream. It takes two arguments: "pattern" and
+"group".
+
+	- "pattern" is the regular expression.
+	- "group" says which group to extract into tokens.
+
+group=-1 (the default) is equivalent to "split". In this case, the
+tokens will be equivalent to the output from (without empty tokens):
+strings.Split() or Regexp.Split().
+
+Using group >= 0 selects the matching group as the token. For
+example, if you have:
+
+	pattern = '([^']+)'
+	group = 0
+	input = aaa 'bbb' 'ccc'
+
+the output will be two tokens: 'bbb' and 'ccc' (including the '
+marks). With the same input but using group=1, the output would be:
+bbb and ccc (no ' marks)
+
+NOTE: This Tokenizer does not output tokens that are of zero length.
+*/
+type PatternTokenizer struct {
+	*Tokenizer
+	pattern *regexp.Regexp
+	group   int
+
+	// the whole input, read on the first token
+	str  string
+	read bool
+	// rune offset of each byte offset of str
+	runeOffsets []int
+	// the matches of the pattern still to be processed
+	matches [][]int
+	// byte offset of the end of the last match
+	index int
+
+	termAtt   CharTermAttribute
+	offsetAtt OffsetAttribute
+}
+
+/* creates a new PatternTokenizer returning tokens from group (-1 for split functionality) */
+func NewPatternTokenizer(input io.RuneReader, pattern *regexp.Regexp, group int) *PatternTokenizer {
+	if group > pattern.NumSubexp() {
+		panic(fmt.Sprintf("invalid group specified: pattern only has: %v capturing groups",
+			pattern.NumSubexp()))
+	}
+	ans := &PatternTokenizer{
+		Tokenizer: NewTokenizer(input),
+		pattern:   pattern,
+		group:     group,
+	}
+	ans.termAtt = ans.Attributes().Add("CharTermAttribute").(CharTermAttribute)
+	ans.offsetAtt = ans.Attributes().Add("OffsetAttribute").(OffsetAttribute)
+	return ans
+}
+
+func (t *PatternTokenizer) IncrementToken() (bool, error) {
+	if !t.read {
+		if err := t.fillBuffer(); err != nil {
+			return false, err
+		}
+	}
+	if t.index >= len(t.str) {
+		return false, nil
+	}
+	t.Attributes().Clear()
+
+	if t.group >= 0 {
+		// match a specific group
+		for len(t.matches) > 0 {
+			m := t.matches[0]
+			t.matches = t.matches[1:]
+			start, end := m[2*t.group], m[2*t.group+1]
+			if start == end {
+				continue
+			}
+			t.index = m[1]
+			t.setToken(start, end)
+			return true, nil
+		}
+		t.index = math.MaxInt32 // mark exhausted
+		return false, nil
+	}
+
+	// String.split() functionality
+	for len(t.matches) > 0 {
+		m := t.matches[0]
+		t.matches = t.matches[1:]
+		if m[0]-t.index > 0 {
+			// found a non-zero-length token
+			t.setToken(t.index, m[0])
+			t.index = m[1]
+			return true, nil
+		}
+		t.index = m[1]
+	}
+	if len(t.str)-t.index == 0 {
+		t.index = math.MaxInt32
+		return false, nil
+	}
+	t.setToken(t.index, len(t.str))
+	t.index = math.MaxInt32 // mark exhausted
+	return true, nil
+}
+
+func (t *PatternTokenizer) setToken(start, end int) {
+	t.termAtt.CopyBuffer([]rune(t.str[start:end]))
+	t.offsetAtt.SetOffset(t.CorrectOffset(t.runeOffsets[start]), t.CorrectOffset(t.runeOffsets[end]))
+}
+
+func (t *PatternTokenizer) fillBuffer() error {
+	var b strings.Builder
+	for {
+		ch, _, err := t.Input.ReadRune()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		b.WriteRune(ch)
+	}
+	t.str, t.read, t.index = b.String(), true, 0
+	t.matches = t.pattern.FindAllStringSubmatchIndex(t.str, -1)
+	t.runeOffsets = make([]int, len(t.str)+1)
+	n := 0
+	for i := range t.str {
+		t.runeOffsets[i] = n
+		n++
+	}
+	t.runeOffsets[len(t.str)] = n
+	return nil
+}
+
+func (t *PatternTokenizer) End() error {
+	if err := t.Tokenizer.End(); err != nil {
+		return err
+	}
+	ofs := 0
+	if t.read {
+		ofs = t.CorrectOffset(t.runeOffsets[len(t.str)])
+	}
+	t.offsetAtt.SetOffset(ofs, ofs)
+	return nil
+}
+
+func (t *PatternTokenizer) Reset() error {
+	if err := t.Tokenizer.Reset(); err != nil {
+		return err
+	}
+	t.str, t.read, t.runeOffsets, t.matches, t.index = "", false, nil, nil, 0
+	return nil
+}
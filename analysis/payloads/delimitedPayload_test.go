@@ -0,0 +1,99 @@
+package payloads
+
+import (
+	"fmt"
+	"github.com/balzaczyy/golucene/analysis/pattern"
+	. "github.com/balzaczyy/golucene/core/analysis"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func whitespaceTokenizer(text string) TokenStream {
+	return pattern.NewPatternTokenizer(strings.NewReader(text), regexp.MustCompile(`\s+`), -1)
+}
+
+/* Returns the terms of ts with their payloads decoded by decode. */
+func termsWithPayloads(t *testing.T, ts TokenStream, decode func([]byte) string) string {
+	termAtt := ts.Attributes().Add("CharTermAttribute").(CharTermAttribute)
+	payAtt := ts.Attributes().Add("PayloadAttribute").(PayloadAttribute)
+	if err := ts.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	var tokens []string
+	for {
+		ok, err := ts.IncrementToken()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			break
+		}
+		term := string(termAtt.Buffer()[:termAtt.Length()])
+		if payload := payAtt.Payload(); payload != nil {
+			term += "|" + decode(payload)
+		}
+		tokens = append(tokens, term)
+	}
+	if err := ts.End(); err != nil {
+		t.Fatal(err)
+	}
+	if err := ts.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return strings.Join(tokens, " ")
+}
+
+func TestDelimitedPayloadTokenFilter(t *testing.T) {
+	for _, c := range []struct {
+		encoder   PayloadEncoder
+		delimiter rune
+		text      string
+		decode    func([]byte) string
+		expected  string
+	}{
+		{FloatEncoder{}, '|', "the|0.1 quick|2.5 fox",
+			func(b []byte) string { return fmt.Sprint(DecodeFloat(b, 0)) }, "the|0.1 quick|2.5 fox"},
+		{IntegerEncoder{}, '^', "the^1 quick^-7 fox^42",
+			func(b []byte) string { return fmt.Sprint(DecodeInt(b, 0)) }, "the|1 quick|-7 fox|42"},
+		{IdentityEncoder{}, '|', "the|NP quick|ADJ fox|名詞 jumps",
+			func(b []byte) string { return string(b) }, "the|NP quick|ADJ fox|名詞 jumps"},
+	} {
+		ts := NewDelimitedPayloadTokenFilter(whitespaceTokenizer(c.text), c.delimiter, c.encoder)
+		if got := termsWithPayloads(t, ts, c.decode); got != c.expected {
+			t.Errorf("expected %v, but was %v", c.expected, got)
+		}
+	}
+}
+
+func TestDelimitedPayloadTokenFilterInvalidPayload(t *testing.T) {
+	ts := NewDelimitedPayloadTokenFilter(whitespaceTokenizer("the|x"), '|', FloatEncoder{})
+	if err := ts.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ts.IncrementToken(); err == nil {
+		t.Error("expected an error for the invalid float")
+	}
+}
+
+func TestDelimitedPayloadTokenFilterFactory(t *testing.T) {
+	for _, args := range []map[string]string{
+		{},
+		{"encoder": "double"},
+		{"encoder": "float", "delimiter": "||"},
+	} {
+		if _, err := NewDelimitedPayloadTokenFilterFactory(args); err == nil {
+			t.Errorf("%v: expected an error", args)
+		}
+	}
+	f, err := NewDelimitedPayloadTokenFilterFactory(map[string]string{"encoder": "integer", "delimiter": "#"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := termsWithPayloads(t, f.Create(whitespaceTokenizer("a#3 b")),
+		func(b []byte) string { return fmt.Sprint(DecodeInt(b, 0)) })
+	if expected := "a|3 b"; got != expected {
+		t.Errorf("expected %v, but was %v", expected, got)
+	}
+}
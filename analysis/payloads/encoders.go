@@ -0,0 +1,79 @@
+package payloads
+
+import (
+	"encoding/binary"
+	"math"
+	"strconv"
+)
+
+// payloads/PayloadEncoder.java
+
+/*
+Mainly for use with the DelimitedPayloadTokenFilter, converts char
+buffers to payload bytes.
+
+NOTE: This interface is subject to change.
+*/
+type PayloadEncoder interface {
+	// Convert the runes into the payload bytes.
+	Encode(buffer []rune) ([]byte, error)
+}
+
+// payloads/FloatEncoder.java
+
+/* Encode a string as a 32-bit float, see EncodeFloat(). */
+type FloatEncoder struct{}
+
+func (e FloatEncoder) Encode(buffer []rune) ([]byte, error) {
+	f, err := strconv.ParseFloat(string(buffer), 32)
+	if err != nil {
+		return nil, err
+	}
+	return EncodeFloat(float32(f)), nil
+}
+
+// payloads/IntegerEncoder.java
+
+/* Encode a string as a 32-bit integer, see EncodeInt(). */
+type IntegerEncoder struct{}
+
+func (e IntegerEncoder) Encode(buffer []rune) ([]byte, error) {
+	n, err := strconv.ParseInt(string(buffer), 10, 32)
+	if err != nil {
+		return nil, err
+	}
+	return EncodeInt(int32(n)), nil
+}
+
+// payloads/IdentityEncoder.java
+
+/* Does nothing other than convert the runes to their UTF-8 bytes. */
+type IdentityEncoder struct{}
+
+func (e IdentityEncoder) Encode(buffer []rune) ([]byte, error) {
+	return []byte(string(buffer)), nil
+}
+
+// payloads/PayloadHelper.java
+
+/* Encodes the float as the 4 big-endian bytes of its IEEE 754 bits. */
+func EncodeFloat(payload float32) []byte {
+	return EncodeInt(int32(math.Float32bits(payload)))
+}
+
+/* Decodes the float encoded by EncodeFloat() at the given offset. */
+func DecodeFloat(bytes []byte, offset int) float32 {
+	return math.Float32frombits(uint32(DecodeInt(bytes, offset)))
+}
+
+/* Encodes the int as 4 big-endian bytes. */
+func EncodeInt(payload int32) []byte {
+	ans := make([]byte, 4)
+	binary.BigEndian.PutUint32(ans, uint32(payload))
+	return ans
+}
+
+/* Decodes the int encoded by EncodeInt() at the given offset. */
+func DecodeInt(bytes []byte, offset int) int32 {
+	return int32(binary.BigEndian.Uint32(bytes[offset:]))
+}
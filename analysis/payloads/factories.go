@@ -0,0 +1,85 @@
+package payloads
+
+import (
+	"fmt"
+	. "github.com/balzaczyy/golucene/analysis/util"
+	. "github.com/balzaczyy/golucene/core/analysis"
+	"unicode/utf8"
+)
+
+func init() {
+	RegisterTokenFilterFactory("delimitedPayload", func(args map[string]string) (TokenFilterFactory, error) {
+		f, err := NewDelimitedPayloadTokenFilterFactory(args)
+		if err != nil {
+			return nil, err
+		}
+		return f, nil
+	})
+	RegisterTokenFilterFactory("typeAsPayload", func(args map[string]string) (TokenFilterFactory, error) {
+		f, err := NewTypeAsPayloadTokenFilterFactory(args)
+		if err != nil {
+			return nil, err
+		}
+		return f, nil
+	})
+}
+
+// payloads/DelimitedPayloadTokenFilterFactory.java
+
+/*
+Factory for DelimitedPayloadTokenFilter. It requires the "encoder",
+one of "float", "integer" or "identity", and takes the optional
+single character "delimiter" ("|" by default).
+*/
+type DelimitedPayloadTokenFilterFactory struct {
+	*AbstractAnalysisFactory
+	encoder   PayloadEncoder
+	delimiter rune
+}
+
+func NewDelimitedPayloadTokenFilterFactory(args map[string]string) (*DelimitedPayloadTokenFilterFactory, error) {
+	ans := &DelimitedPayloadTokenFilterFactory{AbstractAnalysisFactory: NewAbstractAnalysisFactory(args)}
+	encoder := ans.Require("encoder")
+	delimiter := ans.Get("delimiter", string(DEFAULT_DELIMITER))
+	if err := ans.Validate(); err != nil {
+		return nil, err
+	}
+	switch encoder {
+	case "float":
+		ans.encoder = FloatEncoder{}
+	case "integer":
+		ans.encoder = IntegerEncoder{}
+	case "identity":
+		ans.encoder = IdentityEncoder{}
+	default:
+		return nil, fmt.Errorf("Unknown encoder: %v", encoder)
+	}
+	if utf8.RuneCountInString(delimiter) != 1 {
+		return nil, fmt.Errorf("Delimiter must be one character only")
+	}
+	ans.delimiter, _ = utf8.DecodeRuneInString(delimiter)
+	return ans, nil
+}
+
+func (f *DelimitedPayloadTokenFilterFactory) Create(input TokenStream) TokenStream {
+	return NewDelimitedPayloadTokenFilter(input, f.delimiter, f.encoder)
+}
+
+// payloads/TypeAsPayloadTokenFilterFactory.java
+
+/* Factory for TypeAsPayloadTokenFilter. */
+type TypeAsPayloadTokenFilterFactory struct {
+	*AbstractAnalysisFactory
+}
+
+func NewTypeAsPayloadTokenFilterFactory(args map[string]string) (*TypeAsPayloadTokenFilterFactory, error) {
+	ans := &TypeAsPayloadTokenFilterFactory{NewAbstractAnalysisFactory(args)}
+	if err := ans.Validate(); err != nil {
+		return nil, err
+	}
+	return ans, nil
+}
+
+func (f *TypeAsPayloadTokenFilterFactory) Create(input TokenStream) TokenStream {
+	return NewTypeAsPayloadTokenFilter(input)
+}
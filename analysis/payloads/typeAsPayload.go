@@ -0,0 +1,39 @@
+package payloads
+
+import (
+	. "github.com/balzaczyy/golucene/core/analysis"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+)
+
+// payloads/TypeAsPayloadTokenFilter.java
+
+/*
+Makes the TypeAttribute a payload. Encodes the type using its UTF-8
+bytes, and leaves the payload alone for tokens without a type.
+*/
+type TypeAsPayloadTokenFilter struct {
+	*TokenFilter
+	input   TokenStream
+	payAtt  PayloadAttribute
+	typeAtt TypeAttribute
+}
+
+func NewTypeAsPayloadTokenFilter(input TokenStream) *TypeAsPayloadTokenFilter {
+	ans := &TypeAsPayloadTokenFilter{
+		TokenFilter: NewTokenFilter(input),
+		input:       input,
+	}
+	ans.payAtt = ans.Attributes().Add("PayloadAttribute").(PayloadAttribute)
+	ans.typeAtt = ans.Attributes().Add("TypeAttribute").(TypeAttribute)
+	return ans
+}
+
+func (f *TypeAsPayloadTokenFilter) IncrementToken() (bool, error) {
+	ok, err := f.input.IncrementToken()
+	if ok {
+		if typ := f.typeAtt.Type(); typ != "" {
+			f.payAtt.SetPayload([]byte(typ))
+		}
+	}
+	return ok, err
+}
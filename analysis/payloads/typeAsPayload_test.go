@@ -0,0 +1,20 @@
+package payloads
+
+import (
+	std "github.com/balzaczyy/golucene/analysis/standard"
+	"github.com/balzaczyy/golucene/core/util"
+	"strings"
+	"testing"
+)
+
+func TestTypeAsPayloadTokenFilter(t *testing.T) {
+	f, err := NewTypeAsPayloadTokenFilterFactory(map[string]string{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := f.Create(std.NewStandardTokenizer(util.VERSION_LATEST, strings.NewReader("Route 66")))
+	got := termsWithPayloads(t, ts, func(b []byte) string { return string(b) })
+	if expected := "Route|<ALPHANUM> 66|<NUM>"; got != expected {
+		t.Errorf("expected %v, but was %v", expected, got)
+	}
+}
@@ -0,0 +1,344 @@
+package phonetic
+
+import (
+	"regexp"
+	"strings"
+)
+
+// codec/language/bm/NameType.java
+
+/* Supported types of names. */
+type NameType int
+
+const (
+	// Ashkenazi family names
+	ASHKENAZI NameType = iota
+	// Generic names and words
+	GENERIC
+	// Sephardic family names
+	SEPHARDIC
+)
+
+// codec/language/bm/RuleType.java
+
+/* Types of rule. */
+type RuleType int
+
+const (
+	// Approximate rules, which will lead to the largest number of phonetic interpretations.
+	APPROX RuleType = iota
+	// Exact rules, which will lead to a minimum number of phonetic interpretations.
+	EXACT
+)
+
+// codec/language/bm/Rule.java
+
+/*
+A phoneme rule: the pattern is replaced by one of the phonemes when
+the text before it matches the left context, and the text after it
+the right context.
+*/
+type bmRule struct {
+	pattern     string
+	left, right *regexp.Regexp
+	phonemes    []string
+}
+
+/*
+Parses rules written as "pattern left right phonemes", where the
+contexts are regular expressions ("" for any) and the alternative
+phonemes are separated by '|'.
+*/
+func parseRules(rules [][4]string) map[byte][]*bmRule {
+	ans := make(map[byte][]*bmRule)
+	for _, r := range rules {
+		rule := &bmRule{pattern: r[0], phonemes: strings.Split(r[3], "|")}
+		if r[1] != "" {
+			rule.left = regexp.MustCompile("(?:" + r[1] + ")$")
+		}
+		if r[2] != "" {
+			rule.right = regexp.MustCompile("^(?:" + r[2] + ")")
+		}
+		ans[r[0][0]] = append(ans[r[0][0]], rule)
+	}
+	return ans
+}
+
+/* Returns true if the rule applies at offset i of the text. */
+func (r *bmRule) matches(text string, i int) bool {
+	if !strings.HasPrefix(text[i:], r.pattern) {
+		return false
+	}
+	if r.left != nil && !r.left.MatchString(text[:i]) {
+		return false
+	}
+	return r.right == nil || r.right.MatchString(text[i+len(r.pattern):])
+}
+
+/*
+The main rules, shared by all the name types: they turn the letters
+of a name into phonemes, with alternatives where the spelling is
+ambiguous across languages.
+*/
+var mainRules = parseRules([][4]string{
+	{"tsch", "", "", "tS"},
+	{"tch", "", "", "tS"},
+	{"tz", "", "", "ts"},
+	{"th", "", "", "t"},
+	{"sch", "", "", "S|sk"},
+	{"sh", "", "", "S"},
+	{"sz", "", "", "s|S"},
+	{"ch", "", "", "tS|x"},
+	{"ck", "", "", "k"},
+	{"cz", "", "", "tS"},
+	{"c", "", "[eiy]", "ts|s"},
+	{"c", "", "", "k"},
+	{"ph", "", "", "f"},
+	{"gh", "", "", "g"},
+	{"g", "", "[eiy]", "g|dZ"},
+	{"kh", "", "", "x"},
+	{"zh", "", "", "Z"},
+	{"dz", "", "", "dz"},
+	{"qu", "", "", "kv|k"},
+	{"q", "", "", "k"},
+	{"x", "", "", "ks"},
+	{"w", "", "", "v"},
+	{"j", "", "", "dZ|j"},
+	{"h", "^", "", "h"},
+	{"h", "[aeiou]", "[aeiou]", "h"},
+	{"h", "", "", ""},
+	{"y", "", "[aeiou]", "j"},
+	{"y", "", "", "i"},
+	{"ou", "", "", "u"},
+	{"oo", "", "", "u"},
+	{"ee", "", "", "i"},
+	{"ie", "", "", "i"},
+	{"ei", "", "", "aj"},
+	{"ey", "", "", "aj"},
+	{"ay", "", "", "aj"},
+	{"ai", "", "", "aj|e"},
+	{"ae", "", "", "e"},
+	{"oe", "", "", "e"},
+	{"ä", "", "", "e"},
+	{"ö", "", "", "e"},
+	{"ü", "", "", "i|u"},
+	{"ß", "", "", "s"},
+})
+
+/*
+The final approximate rules, which fold the phonemes that are hard to
+tell apart: they are applied to every alternative after the main
+rules.
+*/
+var approxRules = parseRules([][4]string{
+	{"S", "", "", "s"},
+	{"Z", "", "", "z"},
+	{"dt", "", "", "t"},
+	{"e", "", "", "i"},
+	{"o", "", "", "u"},
+})
+
+/* The prefixes of names that are also encoded without the prefix. */
+var namePrefixes = map[NameType][]string{
+	ASHKENAZI: {"bar", "ben", "da", "de", "van", "von"},
+	GENERIC: {"da", "dal", "de", "del", "dela", "de la", "della", "des", "di", "do",
+		"dos", "du", "van", "von"},
+	SEPHARDIC: {"al", "el", "da", "dal", "de", "del", "dela", "de la", "della", "des",
+		"di", "do", "dos", "du", "van", "von"},
+}
+
+// codec/language/bm/PhoneticEngine.java
+
+/* The default maximum number of phonemes an input is encoded to. */
+const DEFAULT_MAX_PHONEMES = 20
+
+/*
+Converts words into potential phonetic representations, following the
+Beider-Morse Phonetic Matching algorithm (BMPM).
+
+The words are encoded with a compact set of rules for the Latin
+script, rather than the per-language rules of the original
+algorithm. Each ambiguous spelling gives an alternative phonetic
+representation, and the encoding holds all of them, separated by
+'|'. Names made of several words are encoded as "(...)-(...)".
+*/
+type PhoneticEngine struct {
+	nameType    NameType
+	ruleType    RuleType
+	concat      bool
+	maxPhonemes int
+}
+
+/* Generates a new, fully-configured phonetic engine. */
+func NewPhoneticEngine(nameType NameType, ruleType RuleType, concat bool) *PhoneticEngine {
+	return NewPhoneticEngineWithMaxPhonemes(nameType, ruleType, concat, DEFAULT_MAX_PHONEMES)
+}
+
+/*
+Generates a new, fully-configured phonetic engine, which produces at
+most maxPhonemes alternatives for an input.
+*/
+func NewPhoneticEngineWithMaxPhonemes(nameType NameType, ruleType RuleType, concat bool, maxPhonemes int) *PhoneticEngine {
+	return &PhoneticEngine{nameType, ruleType, concat, maxPhonemes}
+}
+
+func (e *PhoneticEngine) NameType() NameType { return e.nameType }
+func (e *PhoneticEngine) RuleType() RuleType { return e.ruleType }
+func (e *PhoneticEngine) IsConcat() bool     { return e.concat }
+
+/* Encodes a string to its phonetic representation. */
+func (e *PhoneticEngine) Encode(input string) string {
+	input = strings.TrimSpace(strings.ToLower(input))
+
+	if e.nameType == GENERIC {
+		if strings.HasPrefix(input, "d'") { // check for d'
+			remainder := input[2:]
+			combined := "d" + remainder
+			return "(" + e.Encode(remainder) + ")-(" + e.Encode(combined) + ")"
+		}
+		for _, l := range namePrefixes[e.nameType] {
+			// handle generic prefixes
+			if strings.HasPrefix(input, l+" ") {
+				// check for any prefix in the words list
+				remainder := input[len(l)+1:] // input without the prefix
+				combined := l + remainder     // input with prefix without space
+				return "(" + e.Encode(remainder) + ")-(" + e.Encode(combined) + ")"
+			}
+		}
+	}
+
+	words := strings.Fields(input)
+	var words2 []string
+	switch e.nameType {
+	case SEPHARDIC:
+		for _, word := range words {
+			parts := strings.Split(word, "'")
+			words2 = append(words2, parts[len(parts)-1])
+		}
+		words2 = removePrefixes(words2, namePrefixes[e.nameType])
+	case ASHKENAZI:
+		words2 = removePrefixes(words, namePrefixes[e.nameType])
+	default:
+		words2 = words
+	}
+
+	switch {
+	case len(words2) == 0:
+		return ""
+	case e.concat:
+		// concat mode enabled
+		return e.encodeWord(strings.Join(words2, ""))
+	case len(words2) == 1:
+		// not a multi-word name
+		return e.encodeWord(words2[0])
+	}
+	var result []string
+	for _, word := range words2 {
+		result = append(result, e.encodeWord(word))
+	}
+	return strings.Join(result, "-")
+}
+
+func removePrefixes(words, prefixes []string) []string {
+	var ans []string
+outer:
+	for _, word := range words {
+		for _, prefix := range prefixes {
+			if word == prefix {
+				continue outer
+			}
+		}
+		ans = append(ans, word)
+	}
+	return ans
+}
+
+/* Encodes a single word, returning its alternatives separated by '|'. */
+func (e *PhoneticEngine) encodeWord(word string) string {
+	phonemes := e.applyRules(mainRules, []string{word})
+	if e.ruleType == APPROX {
+		phonemes = e.applyRules(approxRules, phonemes)
+	}
+	for i, phoneme := range phonemes {
+		phonemes[i] = squeeze(phoneme)
+	}
+	return strings.Join(dedup(phonemes), "|")
+}
+
+/*
+Rewrites each text with the rules, from left to right; the letters
+no rule matches are kept as is.
+*/
+func (e *PhoneticEngine) applyRules(rules map[byte][]*bmRule, texts []string) []string {
+	var ans []string
+	for _, text := range texts {
+		alternatives := []string{""}
+		for i := 0; i < len(text); {
+			phonemes, length := []string{text[i : i+1]}, 1
+			if r := matchRule(rules, text, i); r != nil {
+				phonemes, length = r.phonemes, len(r.pattern)
+			} else if n := runeLength(text[i:]); n > 1 {
+				phonemes, length = []string{text[i : i+n]}, n
+			}
+			var next []string
+			for _, alternative := range alternatives {
+				for _, phoneme := range phonemes {
+					if len(next) < e.maxPhonemes {
+						next = append(next, alternative+phoneme)
+					}
+				}
+			}
+			alternatives = dedup(next)
+			i += length
+		}
+		ans = append(ans, alternatives...)
+	}
+	if len(ans) > e.maxPhonemes {
+		ans = ans[:e.maxPhonemes]
+	}
+	return dedup(ans)
+}
+
+func matchRule(rules map[byte][]*bmRule, text string, i int) *bmRule {
+	for _, r := range rules[text[i]] {
+		if r.matches(text, i) {
+			return r
+		}
+	}
+	return nil
+}
+
+func runeLength(s string) int {
+	for i := range s {
+		if i > 0 {
+			return i
+		}
+	}
+	return len(s)
+}
+
+/* Removes the repeated phonemes, as in "ss" or "tt". */
+func squeeze(s string) string {
+	var b strings.Builder
+	var last rune = -1
+	for _, ch := range s {
+		if ch != last {
+			b.WriteRune(ch)
+		}
+		last = ch
+	}
+	return b.String()
+}
+
+/* Removes the duplicates, keeping the first occurrences. */
+func dedup(values []string) []string {
+	seen := make(map[string]bool)
+	ans := values[:0]
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			ans = append(ans, v)
+		}
+	}
+	return ans
+}
@@ -0,0 +1,785 @@
+package phonetic
+
+import (
+	"strings"
+)
+
+// codec/language/DoubleMetaphone.java
+
+// Prefixes when present which are not pronounced
+var silentStart = []string{"GN", "KN", "PN", "WR", "PS"}
+
+var (
+	l_r_n_m_b_h_f_v_w_space          = []string{"L", "R", "N", "M", "B", "H", "F", "V", "W", " "}
+	es_ep_eb_el_ey_ib_il_in_ie_ei_er = []string{"ES", "EP", "EB", "EL", "EY", "IB", "IL", "IN", "IE", "EI", "ER"}
+	l_t_k_s_n_m_b_z                  = []string{"L", "T", "K", "S", "N", "M", "B", "Z"}
+)
+
+/*
+Encodes a string into a double metaphone value. This implementation
+is based on the algorithm by Lawrence Philips.
+
+The maximum code length is mutable with SetMaxCodeLen(), and is not
+synchronized.
+*/
+type DoubleMetaphone struct {
+	// Maximum length of an encoding, default is 4
+	maxCodeLen int
+}
+
+/* Creates an instance of this DoubleMetaphone encoder. */
+func NewDoubleMetaphone() *DoubleMetaphone {
+	return &DoubleMetaphone{4}
+}
+
+/* Returns the maxCodeLen. */
+func (dm *DoubleMetaphone) MaxCodeLen() int {
+	return dm.maxCodeLen
+}
+
+/* Sets the maxCodeLen. */
+func (dm *DoubleMetaphone) SetMaxCodeLen(maxCodeLen int) {
+	dm.maxCodeLen = maxCodeLen
+}
+
+/* Encode the value using DoubleMetaphone: returns its primary encoding. */
+func (dm *DoubleMetaphone) Encode(value string) string {
+	return dm.DoubleMetaphone(value, false)
+}
+
+/*
+Encode a value with Double Metaphone, optionally using the alternate
+encoding. Returns "" if the value is blank.
+*/
+func (dm *DoubleMetaphone) DoubleMetaphone(s string, alternate bool) string {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	if s == "" {
+		return ""
+	}
+	value := []rune(s)
+
+	enc := &doubleMetaphoneEncoding{value: value, slavoGermanic: isSlavoGermanic(s)}
+	result := &doubleMetaphoneResult{maxLength: dm.maxCodeLen}
+	index := 0
+	if isSilentStart(s) {
+		index = 1
+	}
+
+	for !result.isComplete() && index <= len(value)-1 {
+		switch value[index] {
+		case 'A', 'E', 'I', 'O', 'U', 'Y':
+			index = enc.handleAEIOUY(result, index)
+		case 'B':
+			result.append('P')
+			if enc.charAt(index+1) == 'B' {
+				index += 2
+			} else {
+				index++
+			}
+		case 'Ç':
+			// A C with a Cedilla
+			result.append('S')
+			index++
+		case 'C':
+			index = enc.handleC(result, index)
+		case 'D':
+			index = enc.handleD(result, index)
+		case 'F':
+			result.append('F')
+			if enc.charAt(index+1) == 'F' {
+				index += 2
+			} else {
+				index++
+			}
+		case 'G':
+			index = enc.handleG(result, index)
+		case 'H':
+			index = enc.handleH(result, index)
+		case 'J':
+			index = enc.handleJ(result, index)
+		case 'K':
+			result.append('K')
+			if enc.charAt(index+1) == 'K' {
+				index += 2
+			} else {
+				index++
+			}
+		case 'L':
+			index = enc.handleL(result, index)
+		case 'M':
+			result.append('M')
+			if enc.conditionM0(index) {
+				index += 2
+			} else {
+				index++
+			}
+		case 'N':
+			result.append('N')
+			if enc.charAt(index+1) == 'N' {
+				index += 2
+			} else {
+				index++
+			}
+		case 'Ñ':
+			// N with a tilde (spanish ene)
+			result.append('N')
+			index++
+		case 'P':
+			index = enc.handleP(result, index)
+		case 'Q':
+			result.append('K')
+			if enc.charAt(index+1) == 'Q' {
+				index += 2
+			} else {
+				index++
+			}
+		case 'R':
+			index = enc.handleR(result, index)
+		case 'S':
+			index = enc.handleS(result, index)
+		case 'T':
+			index = enc.handleT(result, index)
+		case 'V':
+			result.append('F')
+			if enc.charAt(index+1) == 'V' {
+				index += 2
+			} else {
+				index++
+			}
+		case 'W':
+			index = enc.handleW(result, index)
+		case 'X':
+			index = enc.handleX(result, index)
+		case 'Z':
+			index = enc.handleZ(result, index)
+		default:
+			index++
+		}
+	}
+
+	if alternate {
+		return result.alternate.String()
+	}
+	return result.primary.String()
+}
+
+/* Check if the Double Metaphone values of two strings are equal. */
+func (dm *DoubleMetaphone) IsDoubleMetaphoneEqual(value1, value2 string, alternate bool) bool {
+	return dm.DoubleMetaphone(value1, alternate) == dm.DoubleMetaphone(value2, alternate)
+}
+
+/* The value being encoded, with the helpers of the rules. */
+type doubleMetaphoneEncoding struct {
+	value         []rune
+	slavoGermanic bool
+}
+
+/* Handles 'A', 'E', 'I', 'O', 'U', and 'Y' cases. */
+func (e *doubleMetaphoneEncoding) handleAEIOUY(result *doubleMetaphoneResult, index int) int {
+	if index == 0 {
+		result.append('A')
+	}
+	return index + 1
+}
+
+/* Handles 'C' cases. */
+func (e *doubleMetaphoneEncoding) handleC(result *doubleMetaphoneResult, index int) int {
+	switch {
+	case e.conditionC0(index): // very confusing condition
+		result.append('K')
+		index += 2
+	case index == 0 && e.contains(index, 6, "CAESAR"):
+		result.append('S')
+		index += 2
+	case e.contains(index, 2, "CH"):
+		index = e.handleCH(result, index)
+	case e.contains(index, 2, "CZ") && !e.contains(index-2, 4, "WICZ"):
+		// "Czerny"
+		result.appendBoth('S', 'X')
+		index += 2
+	case e.contains(index+1, 3, "CIA"):
+		// "focaccia"
+		result.append('X')
+		index += 3
+	case e.contains(index, 2, "CC") && !(index == 1 && e.charAt(0) == 'M'):
+		// double "cc" but not "McClelland"
+		return e.handleCC(result, index)
+	case e.contains(index, 2, "CK", "CG", "CQ"):
+		result.append('K')
+		index += 2
+	case e.contains(index, 2, "CI", "CE", "CY"):
+		// Italian vs. English
+		if e.contains(index, 3, "CIO", "CIE", "CIA") {
+			result.appendBoth('S', 'X')
+		} else {
+			result.append('S')
+		}
+		index += 2
+	default:
+		result.append('K')
+		if e.contains(index+1, 2, " C", " Q", " G") {
+			// Mac Caffrey, Mac Gregor
+			index += 3
+		} else if e.contains(index+1, 1, "C", "K", "Q") && !e.contains(index+1, 2, "CE", "CI") {
+			index += 2
+		} else {
+			index++
+		}
+	}
+	return index
+}
+
+/* Handles 'CC' cases. */
+func (e *doubleMetaphoneEncoding) handleCC(result *doubleMetaphoneResult, index int) int {
+	if e.contains(index+2, 1, "I", "E", "H") && !e.contains(index+2, 2, "HU") {
+		// "bellocchio" but not "bacchus"
+		if (index == 1 && e.charAt(index-1) == 'A') || e.contains(index-1, 5, "UCCEE", "UCCES") {
+			// "accident", "accede", "succeed"
+			result.appendString("KS")
+		} else {
+			// "bacci", "bertucci", other Italian
+			result.append('X')
+		}
+		index += 3
+	} else { // Pierce's rule
+		result.append('K')
+		index += 2
+	}
+	return index
+}
+
+/* Handles 'CH' cases. */
+func (e *doubleMetaphoneEncoding) handleCH(result *doubleMetaphoneResult, index int) int {
+	switch {
+	case index > 0 && e.contains(index, 4, "CHAE"): // Michael
+		result.appendBoth('K', 'X')
+	case e.conditionCH0(index):
+		// Greek roots ("chemistry", "chorus", etc.)
+		result.append('K')
+	case e.conditionCH1(index):
+		// Germanic, Greek, or otherwise 'ch' for 'kh' sound
+		result.append('K')
+	case index > 0:
+		if e.contains(0, 2, "MC") {
+			result.append('K')
+		} else {
+			result.appendBoth('X', 'K')
+		}
+	default:
+		result.append('X')
+	}
+	return index + 2
+}
+
+/* Handles 'D' cases. */
+func (e *doubleMetaphoneEncoding) handleD(result *doubleMetaphoneResult, index int) int {
+	switch {
+	case e.contains(index, 2, "DG"):
+		// "Edge"
+		if e.contains(index+2, 1, "I", "E", "Y") {
+			result.append('J')
+			index += 3
+		} else {
+			// "Edgar"
+			result.appendString("TK")
+			index += 2
+		}
+	case e.contains(index, 2, "DT", "DD"):
+		result.append('T')
+		index += 2
+	default:
+		result.append('T')
+		index++
+	}
+	return index
+}
+
+/* Handles 'G' cases. */
+func (e *doubleMetaphoneEncoding) handleG(result *doubleMetaphoneResult, index int) int {
+	switch {
+	case e.charAt(index+1) == 'H':
+		index = e.handleGH(result, index)
+	case e.charAt(index+1) == 'N':
+		if index == 1 && isVowel(e.charAt(0)) && !e.slavoGermanic {
+			result.appendStrings("KN", "N")
+		} else if !e.contains(index+2, 2, "EY") && e.charAt(index+1) != 'Y' && !e.slavoGermanic {
+			result.appendStrings("N", "KN")
+		} else {
+			result.appendString("KN")
+		}
+		index += 2
+	case e.contains(index+1, 2, "LI") && !e.slavoGermanic:
+		result.appendStrings("KL", "L")
+		index += 2
+	case index == 0 && (e.charAt(index+1) == 'Y' || e.contains(index+1, 2, es_ep_eb_el_ey_ib_il_in_ie_ei_er...)):
+		// -ges-, -gep-, -gel-, -gie- at beginning
+		result.appendBoth('K', 'J')
+		index += 2
+	case (e.contains(index+1, 2, "ER") || e.charAt(index+1) == 'Y') &&
+		!e.contains(0, 6, "DANGER", "RANGER", "MANGER") &&
+		!e.contains(index-1, 1, "E", "I") &&
+		!e.contains(index-1, 3, "RGY", "OGY"):
+		// -ger-, -gy-
+		result.appendBoth('K', 'J')
+		index += 2
+	case e.contains(index+1, 1, "E", "I", "Y") || e.contains(index-1, 4, "AGGI", "OGGI"):
+		// Italian "biaggi"
+		if e.contains(0, 4, "VAN ", "VON ") || e.contains(0, 3, "SCH") || e.contains(index+1, 2, "ET") {
+			// obvious germanic
+			result.append('K')
+		} else if e.contains(index+1, 3, "IER") {
+			result.append('J')
+		} else {
+			result.appendBoth('J', 'K')
+		}
+		index += 2
+	case e.charAt(index+1) == 'G':
+		index += 2
+		result.append('K')
+	default:
+		index++
+		result.append('K')
+	}
+	return index
+}
+
+/* Handles 'GH' cases. */
+func (e *doubleMetaphoneEncoding) handleGH(result *doubleMetaphoneResult, index int) int {
+	switch {
+	case index > 0 && !isVowel(e.charAt(index-1)):
+		result.append('K')
+	case index == 0:
+		if e.charAt(index+2) == 'I' {
+			result.append('J')
+		} else {
+			result.append('K')
+		}
+	case (index > 1 && e.contains(index-2, 1, "B", "H", "D")) ||
+		(index > 2 && e.contains(index-3, 1, "B", "H", "D")) ||
+		(index > 3 && e.contains(index-4, 1, "B", "H")):
+		// Parker's rule (with some further refinements) - "hugh"
+	default:
+		if index > 2 && e.charAt(index-1) == 'U' && e.contains(index-3, 1, "C", "G", "L", "R", "T") {
+			// "laugh", "McLaughlin", "cough", "gough", "rough", "tough"
+			result.append('F')
+		} else if index > 0 && e.charAt(index-1) != 'I' {
+			result.append('K')
+		}
+	}
+	return index + 2
+}
+
+/* Handles 'H' cases. */
+func (e *doubleMetaphoneEncoding) handleH(result *doubleMetaphoneResult, index int) int {
+	// only keep if first & before vowel or between 2 vowels
+	if (index == 0 || isVowel(e.charAt(index-1))) && isVowel(e.charAt(index+1)) {
+		result.append('H')
+		index += 2
+		// also takes care of "HH"
+	} else {
+		index++
+	}
+	return index
+}
+
+/* Handles 'J' cases. */
+func (e *doubleMetaphoneEncoding) handleJ(result *doubleMetaphoneResult, index int) int {
+	if e.contains(index, 4, "JOSE") || e.contains(0, 4, "SAN ") {
+		// obvious Spanish, "Jose", "San Jacinto"
+		if (index == 0 && e.charAt(index+4) == ' ') || len(e.value) == 4 || e.contains(0, 4, "SAN ") {
+			result.append('H')
+		} else {
+			result.appendBoth('J', 'H')
+		}
+		return index + 1
+	}
+
+	if index == 0 && !e.contains(index, 4, "JOSE") {
+		result.appendBoth('J', 'A')
+	} else if isVowel(e.charAt(index-1)) && !e.slavoGermanic &&
+		(e.charAt(index+1) == 'A' || e.charAt(index+1) == 'O') {
+		result.appendBoth('J', 'H')
+	} else if index == len(e.value)-1 {
+		result.appendBoth('J', ' ')
+	} else if !e.contains(index+1, 1, l_t_k_s_n_m_b_z...) && !e.contains(index-1, 1, "S", "K", "L") {
+		result.append('J')
+	}
+
+	if e.charAt(index+1) == 'J' {
+		return index + 2
+	}
+	return index + 1
+}
+
+/* Handles 'L' cases. */
+func (e *doubleMetaphoneEncoding) handleL(result *doubleMetaphoneResult, index int) int {
+	if e.charAt(index+1) == 'L' {
+		if e.conditionL0(index) {
+			result.appendPrimary('L')
+		} else {
+			result.append('L')
+		}
+		return index + 2
+	}
+	result.append('L')
+	return index + 1
+}
+
+/* Handles 'P' cases. */
+func (e *doubleMetaphoneEncoding) handleP(result *doubleMetaphoneResult, index int) int {
+	if e.charAt(index+1) == 'H' {
+		result.append('F')
+		return index + 2
+	}
+	result.append('P')
+	if e.contains(index+1, 1, "P", "B") {
+		return index + 2
+	}
+	return index + 1
+}
+
+/* Handles 'R' cases. */
+func (e *doubleMetaphoneEncoding) handleR(result *doubleMetaphoneResult, index int) int {
+	if index == len(e.value)-1 && !e.slavoGermanic &&
+		e.contains(index-2, 2, "IE") && !e.contains(index-4, 2, "ME", "MA") {
+		result.appendAlternate('R')
+	} else {
+		result.append('R')
+	}
+	if e.charAt(index+1) == 'R' {
+		return index + 2
+	}
+	return index + 1
+}
+
+/* Handles 'S' cases. */
+func (e *doubleMetaphoneEncoding) handleS(result *doubleMetaphoneResult, index int) int {
+	switch {
+	case e.contains(index-1, 3, "ISL", "YSL"):
+		// special cases "island", "isle", "carlisle", "carlysle"
+		index++
+	case index == 0 && e.contains(index, 5, "SUGAR"):
+		// special case "sugar-"
+		result.appendBoth('X', 'S')
+		index++
+	case e.contains(index, 2, "SH"):
+		if e.contains(index+1, 4, "HEIM", "HOEK", "HOLM", "HOLZ") {
+			// germanic
+			result.append('S')
+		} else {
+			result.append('X')
+		}
+		index += 2
+	case e.contains(index, 3, "SIO", "SIA") || e.contains(index, 4, "SIAN"):
+		// Italian and Armenian
+		if e.slavoGermanic {
+			result.append('S')
+		} else {
+			result.appendBoth('S', 'X')
+		}
+		index += 3
+	case (index == 0 && e.contains(index+1, 1, "M", "N", "L", "W")) || e.contains(index+1, 1, "Z"):
+		// german & anglicisations, e.g. "smith" match "schmidt",
+		// "snider" match "schneider"; also, -sz- in slavic language
+		// although in hungarian it is pronounced "s"
+		result.appendBoth('S', 'X')
+		if e.contains(index+1, 1, "Z") {
+			index += 2
+		} else {
+			index++
+		}
+	case e.contains(index, 2, "SC"):
+		index = e.handleSC(result, index)
+	default:
+		if index == len(e.value)-1 && e.contains(index-2, 2, "AI", "OI") {
+			// french e.g. "resnais", "artois"
+			result.appendAlternate('S')
+		} else {
+			result.append('S')
+		}
+		if e.contains(index+1, 1, "S", "Z") {
+			index += 2
+		} else {
+			index++
+		}
+	}
+	return index
+}
+
+/* Handles 'SC' cases. */
+func (e *doubleMetaphoneEncoding) handleSC(result *doubleMetaphoneResult, index int) int {
+	switch {
+	case e.charAt(index+2) == 'H':
+		// Schlesinger's rule
+		if e.contains(index+3, 2, "OO", "ER", "EN", "UY", "ED", "EM") {
+			// Dutch origin, e.g. "school", "schooner"
+			if e.contains(index+3, 2, "ER", "EN") {
+				// "schermerhorn", "schenker"
+				result.appendStrings("X", "SK")
+			} else {
+				result.appendString("SK")
+			}
+		} else if index == 0 && !isVowel(e.charAt(3)) && e.charAt(3) != 'W' {
+			result.appendBoth('X', 'S')
+		} else {
+			result.append('X')
+		}
+	case e.contains(index+2, 1, "I", "E", "Y"):
+		result.append('S')
+	default:
+		result.appendString("SK")
+	}
+	return index + 3
+}
+
+/* Handles 'T' cases. */
+func (e *doubleMetaphoneEncoding) handleT(result *doubleMetaphoneResult, index int) int {
+	switch {
+	case e.contains(index, 4, "TION"):
+		result.append('X')
+		index += 3
+	case e.contains(index, 3, "TIA", "TCH"):
+		result.append('X')
+		index += 3
+	case e.contains(index, 2, "TH") || e.contains(index, 3, "TTH"):
+		if e.contains(index+2, 2, "OM", "AM") ||
+			// special case "thomas", "thames" or germanic
+			e.contains(0, 4, "VAN ", "VON ") || e.contains(0, 3, "SCH") {
+			result.append('T')
+		} else {
+			result.appendBoth('0', 'T')
+		}
+		index += 2
+	default:
+		result.append('T')
+		if e.contains(index+1, 1, "T", "D") {
+			index += 2
+		} else {
+			index++
+		}
+	}
+	return index
+}
+
+/* Handles 'W' cases. */
+func (e *doubleMetaphoneEncoding) handleW(result *doubleMetaphoneResult, index int) int {
+	switch {
+	case e.contains(index, 2, "WR"):
+		// can also be in middle of word
+		result.append('R')
+		index += 2
+	case index == 0 && (isVowel(e.charAt(index+1)) || e.contains(index, 2, "WH")):
+		if isVowel(e.charAt(index + 1)) {
+			// Wasserman should match Vasserman
+			result.appendBoth('A', 'F')
+		} else {
+			// need Uomo to match Womo
+			result.append('A')
+		}
+		index++
+	case (index == len(e.value)-1 && isVowel(e.charAt(index-1))) ||
+		e.contains(index-1, 5, "EWSKI", "EWSKY", "OWSKI", "OWSKY") ||
+		e.contains(0, 3, "SCH"):
+		// Arnow should match Arnoff
+		result.appendAlternate('F')
+		index++
+	case e.contains(index, 4, "WICZ", "WITZ"):
+		// Polish e.g. "filipowicz"
+		result.appendStrings("TS", "FX")
+		index += 4
+	default:
+		index++
+	}
+	return index
+}
+
+/* Handles 'X' cases. */
+func (e *doubleMetaphoneEncoding) handleX(result *doubleMetaphoneResult, index int) int {
+	if index == 0 {
+		result.append('S')
+		return index + 1
+	}
+	if !(index == len(e.value)-1 &&
+		(e.contains(index-3, 3, "IAU", "EAU") || e.contains(index-2, 2, "AU", "OU"))) {
+		// French e.g. breaux
+		result.appendString("KS")
+	}
+	if e.contains(index+1, 1, "C", "X") {
+		return index + 2
+	}
+	return index + 1
+}
+
+/* Handles 'Z' cases. */
+func (e *doubleMetaphoneEncoding) handleZ(result *doubleMetaphoneResult, index int) int {
+	if e.charAt(index+1) == 'H' {
+		// Chinese pinyin e.g. "zhao" or Angelina "Zhang"
+		result.append('J')
+		return index + 2
+	}
+	if e.contains(index+1, 2, "ZO", "ZI", "ZA") ||
+		(e.slavoGermanic && index > 0 && e.charAt(index-1) != 'T') {
+		result.appendStrings("S", "TS")
+	} else {
+		result.append('S')
+	}
+	if e.charAt(index+1) == 'Z' {
+		return index + 2
+	}
+	return index + 1
+}
+
+/* Complex condition 0 for 'C'. */
+func (e *doubleMetaphoneEncoding) conditionC0(index int) bool {
+	switch {
+	case e.contains(index, 4, "CHIA"):
+		return true
+	case index <= 1:
+		return false
+	case isVowel(e.charAt(index - 2)):
+		return false
+	case !e.contains(index-1, 3, "ACH"):
+		return false
+	}
+	c := e.charAt(index + 2)
+	return (c != 'I' && c != 'E') || e.contains(index-2, 6, "BACHER", "MACHER")
+}
+
+/* Complex condition 0 for 'CH'. */
+func (e *doubleMetaphoneEncoding) conditionCH0(index int) bool {
+	switch {
+	case index != 0:
+		return false
+	case !e.contains(index+1, 5, "HARAC", "HARIS") && !e.contains(index+1, 3, "HOR", "HYM", "HIA", "HEM"):
+		return false
+	case e.contains(0, 5, "CHORE"):
+		return false
+	}
+	return true
+}
+
+/* Complex condition 1 for 'CH'. */
+func (e *doubleMetaphoneEncoding) conditionCH1(index int) bool {
+	return e.contains(0, 4, "VAN ", "VON ") || e.contains(0, 3, "SCH") ||
+		e.contains(index-2, 6, "ORCHES", "ARCHIT", "ORCHID") ||
+		e.contains(index+2, 1, "T", "S") ||
+		((e.contains(index-1, 1, "A", "O", "U", "E") || index == 0) &&
+			(e.contains(index+2, 1, l_r_n_m_b_h_f_v_w_space...) || index+1 == len(e.value)-1))
+}
+
+/* Complex condition 0 for 'L'. */
+func (e *doubleMetaphoneEncoding) conditionL0(index int) bool {
+	n := len(e.value)
+	if index == n-3 && e.contains(index-1, 4, "ILLO", "ILLA", "ALLE") {
+		return true
+	}
+	return (e.contains(n-2, 2, "AS", "OS") || e.contains(n-1, 1, "A", "O")) &&
+		e.contains(index-1, 4, "ALLE")
+}
+
+/* Complex condition 0 for 'M'. */
+func (e *doubleMetaphoneEncoding) conditionM0(index int) bool {
+	if e.charAt(index+1) == 'M' {
+		return true
+	}
+	return e.contains(index-1, 3, "UMB") &&
+		(index+1 == len(e.value)-1 || e.contains(index+2, 2, "ER"))
+}
+
+/*
+Gets the character at index index if available, otherwise it returns
+0 so that there is some sort of a default.
+*/
+func (e *doubleMetaphoneEncoding) charAt(index int) rune {
+	if index < 0 || index >= len(e.value) {
+		return 0
+	}
+	return e.value[index]
+}
+
+/*
+Determines whether the value contains any of the criteria starting at
+index start and matching up to length length.
+*/
+func (e *doubleMetaphoneEncoding) contains(start, length int, criteria ...string) bool {
+	if start < 0 || start+length > len(e.value) {
+		return false
+	}
+	target := string(e.value[start : start+length])
+	for _, element := range criteria {
+		if target == element {
+			return true
+		}
+	}
+	return false
+}
+
+/* Determines whether or not a value is of slavo-germanic origin. */
+func isSlavoGermanic(value string) bool {
+	return strings.Contains(value, "W") || strings.Contains(value, "K") ||
+		strings.Contains(value, "CZ") || strings.Contains(value, "WITZ")
+}
+
+/* Determines whether or not a character is a vowel or not. */
+func isVowel(ch rune) bool {
+	return strings.ContainsRune("AEIOUY", ch)
+}
+
+/* Determines whether or not the value starts with a silent letter. */
+func isSilentStart(value string) bool {
+	for _, element := range silentStart {
+		if strings.HasPrefix(value, element) {
+			return true
+		}
+	}
+	return false
+}
+
+/* Holds the results of the double metaphone algorithm. */
+type doubleMetaphoneResult struct {
+	primary, alternate strings.Builder
+	maxLength          int
+}
+
+func (r *doubleMetaphoneResult) append(value rune) {
+	r.appendPrimary(value)
+	r.appendAlternate(value)
+}
+
+func (r *doubleMetaphoneResult) appendBoth(primary, alternate rune) {
+	r.appendPrimary(primary)
+	r.appendAlternate(alternate)
+}
+
+func (r *doubleMetaphoneResult) appendPrimary(value rune) {
+	if r.primary.Len() < r.maxLength {
+		r.primary.WriteRune(value)
+	}
+}
+
+func (r *doubleMetaphoneResult) appendAlternate(value rune) {
+	if r.alternate.Len() < r.maxLength {
+		r.alternate.WriteRune(value)
+	}
+}
+
+func (r *doubleMetaphoneResult) appendString(value string) {
+	r.appendStrings(value, value)
+}
+
+func (r *doubleMetaphoneResult) appendStrings(primary, alternate string) {
+	appendTruncated(&r.primary, primary, r.maxLength)
+	appendTruncated(&r.alternate, alternate, r.maxLength)
+}
+
+func appendTruncated(b *strings.Builder, value string, maxLength int) {
+	if addChars := maxLength - b.Len(); len(value) <= addChars {
+		b.WriteString(value)
+	} else if addChars > 0 {
+		b.WriteString(value[:addChars])
+	}
+}
+
+func (r *doubleMetaphoneResult) isComplete() bool {
+	return r.primary.Len() >= r.maxLength && r.alternate.Len() >= r.maxLength
+}
@@ -0,0 +1,108 @@
+package phonetic
+
+import (
+	"errors"
+	"fmt"
+	. "github.com/balzaczyy/golucene/analysis/util"
+	. "github.com/balzaczyy/golucene/core/analysis"
+	"strings"
+)
+
+func init() {
+	RegisterTokenFilterFactory("phonetic", func(args map[string]string) (TokenFilterFactory, error) {
+		f, err := NewPhoneticFilterFactory(args)
+		if err != nil {
+			return nil, err
+		}
+		return f, nil
+	})
+	RegisterTokenFilterFactory("doubleMetaphone", func(args map[string]string) (TokenFilterFactory, error) {
+		f, err := NewDoubleMetaphoneFilterFactory(args)
+		if err != nil {
+			return nil, err
+		}
+		return f, nil
+	})
+}
+
+// phonetic/PhoneticFilterFactory.java
+
+/*
+Factory for PhoneticFilter. The required "encoder" is one of
+"DoubleMetaphone", "Soundex" or "RefinedSoundex" (case-insensitive),
+"inject" (true by default) adds the encoded forms as synonyms instead
+of replacing the terms, and "maxCodeLength" limits the length of the
+DoubleMetaphone codes.
+*/
+type PhoneticFilterFactory struct {
+	*AbstractAnalysisFactory
+	newEncoder func() Encoder
+	inject     bool
+}
+
+func NewPhoneticFilterFactory(args map[string]string) (*PhoneticFilterFactory, error) {
+	ans := &PhoneticFilterFactory{AbstractAnalysisFactory: NewAbstractAnalysisFactory(args)}
+	ans.inject = ans.GetBool("inject", true)
+	name := ans.Require("encoder")
+	_, hasMaxCodeLength := args["maxCodeLength"]
+	maxCodeLength := ans.GetInt("maxCodeLength", 4)
+	if err := ans.Validate(); err != nil {
+		return nil, err
+	}
+	if maxCodeLength < 1 {
+		return nil, errors.New("maxCodeLength must be greater than zero")
+	}
+	switch strings.ToLower(name) {
+	case "doublemetaphone":
+		ans.newEncoder = func() Encoder {
+			encoder := NewDoubleMetaphone()
+			encoder.SetMaxCodeLen(maxCodeLength)
+			return encoder
+		}
+		return ans, nil
+	case "soundex":
+		ans.newEncoder = func() Encoder { return NewSoundex() }
+	case "refinedsoundex":
+		ans.newEncoder = func() Encoder { return NewRefinedSoundex() }
+	default:
+		return nil, fmt.Errorf("Unknown encoder: %v", name)
+	}
+	if hasMaxCodeLength {
+		return nil, fmt.Errorf("Encoder %v does not support maxCodeLength", name)
+	}
+	return ans, nil
+}
+
+func (f *PhoneticFilterFactory) Create(input TokenStream) TokenStream {
+	return NewPhoneticFilter(input, f.newEncoder(), f.inject)
+}
+
+// phonetic/DoubleMetaphoneFilterFactory.java
+
+/*
+Factory for DoubleMetaphoneFilter, taking whether to "inject" the
+codes as synonyms (true by default) and the "maxCodeLength" (4 by
+default).
+*/
+type DoubleMetaphoneFilterFactory struct {
+	*AbstractAnalysisFactory
+	inject        bool
+	maxCodeLength int
+}
+
+func NewDoubleMetaphoneFilterFactory(args map[string]string) (*DoubleMetaphoneFilterFactory, error) {
+	ans := &DoubleMetaphoneFilterFactory{AbstractAnalysisFactory: NewAbstractAnalysisFactory(args)}
+	ans.inject = ans.GetBool("inject", true)
+	ans.maxCodeLength = ans.GetInt("maxCodeLength", 4)
+	if err := ans.Validate(); err != nil {
+		return nil, err
+	}
+	if ans.maxCodeLength < 1 {
+		return nil, errors.New("maxCodeLength must be greater than zero")
+	}
+	return ans, nil
+}
+
+func (f *DoubleMetaphoneFilterFactory) Create(input TokenStream) TokenStream {
+	return NewDoubleMetaphoneFilter(input, f.maxCodeLength, f.inject)
+}
@@ -0,0 +1,257 @@
+package phonetic
+
+import (
+	. "github.com/balzaczyy/golucene/core/analysis"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"github.com/balzaczyy/golucene/core/util"
+	"regexp"
+)
+
+// phonetic/PhoneticFilter.java
+
+/*
+Create tokens for phonetic matches.
+
+If inject is true, the phonetic code is added as a new token at the
+same position as the original one; otherwise the original token is
+replaced.
+*/
+type PhoneticFilter struct {
+	*TokenFilter
+	input   TokenStream
+	inject  bool // true if phonetic tokens should be added, false if they should replace
+	encoder Encoder
+	// the original token, to be emitted after its phonetic code
+	save *util.AttributeState
+
+	termAtt CharTermAttribute
+	posAtt  PositionIncrementAttribute
+}
+
+/*
+Creates a PhoneticFilter with the specified encoder, and either
+adding encoded forms as synonyms (inject=true) or replacing them.
+*/
+func NewPhoneticFilter(in TokenStream, encoder Encoder, inject bool) *PhoneticFilter {
+	ans := &PhoneticFilter{
+		TokenFilter: NewTokenFilter(in),
+		input:       in,
+		inject:      inject,
+		encoder:     encoder,
+	}
+	ans.termAtt = ans.Attributes().Add("CharTermAttribute").(CharTermAttribute)
+	ans.posAtt = ans.Attributes().Add("PositionIncrementAttribute").(PositionIncrementAttribute)
+	return ans
+}
+
+func (f *PhoneticFilter) IncrementToken() (bool, error) {
+	if f.save != nil {
+		f.Attributes().RestoreState(f.save)
+		f.save = nil
+		return true, nil
+	}
+
+	ok, err := f.input.IncrementToken()
+	if err != nil || !ok {
+		return false, err
+	}
+
+	// pass through zero-length terms
+	if f.termAtt.Length() == 0 {
+		return true, nil
+	}
+
+	value := string(f.termAtt.Buffer()[:f.termAtt.Length()])
+	phonetic := f.encoder.Encode(value)
+	if phonetic == "" || phonetic == value {
+		return true, nil // just use the direct text
+	}
+
+	if !f.inject {
+		// just modify this token
+		f.termAtt.CopyBuffer([]rune(phonetic))
+		return true, nil
+	}
+
+	// We need to return both the original and the phonetic tokens; we
+	// return the phonetic alternative first, which saves a state
+	// capture.
+	origOffset := f.posAtt.PositionIncrement()
+	f.posAtt.SetPositionIncrement(0)
+	f.save = f.Attributes().CaptureState()
+	f.posAtt.SetPositionIncrement(origOffset)
+	f.termAtt.CopyBuffer([]rune(phonetic))
+	return true, nil
+}
+
+func (f *PhoneticFilter) Reset() error {
+	f.save = nil
+	return f.TokenFilter.Reset()
+}
+
+// phonetic/DoubleMetaphoneFilter.java
+
+/* Filter for DoubleMetaphone (supporting secondary codes). */
+type DoubleMetaphoneFilter struct {
+	*TokenFilter
+	input   TokenStream
+	inject  bool
+	encoder *DoubleMetaphone
+	// the tokens still to be emitted
+	remainingTokens []*util.AttributeState
+
+	termAtt CharTermAttribute
+	posAtt  PositionIncrementAttribute
+}
+
+/*
+Creates a DoubleMetaphoneFilter with the specified maximum code
+length, and either adding encoded forms as synonyms (inject=true) or
+replacing them.
+*/
+func NewDoubleMetaphoneFilter(in TokenStream, maxCodeLength int, inject bool) *DoubleMetaphoneFilter {
+	ans := &DoubleMetaphoneFilter{
+		TokenFilter: NewTokenFilter(in),
+		input:       in,
+		inject:      inject,
+		encoder:     NewDoubleMetaphone(),
+	}
+	ans.encoder.SetMaxCodeLen(maxCodeLength)
+	ans.termAtt = ans.Attributes().Add("CharTermAttribute").(CharTermAttribute)
+	ans.posAtt = ans.Attributes().Add("PositionIncrementAttribute").(PositionIncrementAttribute)
+	return ans
+}
+
+func (f *DoubleMetaphoneFilter) IncrementToken() (bool, error) {
+	for {
+		if len(f.remainingTokens) > 0 {
+			// clearAttributes();  // not currently necessary
+			f.Attributes().RestoreState(f.remainingTokens[0])
+			f.remainingTokens = f.remainingTokens[1:]
+			return true, nil
+		}
+
+		ok, err := f.input.IncrementToken()
+		if err != nil || !ok {
+			return false, err
+		}
+
+		if f.termAtt.Length() == 0 {
+			return true, nil // pass through zero length terms
+		}
+
+		firstAlternativeIncrement := f.posAtt.PositionIncrement()
+		if f.inject {
+			firstAlternativeIncrement = 0
+		}
+
+		v := string(f.termAtt.Buffer()[:f.termAtt.Length()])
+		primaryPhoneticValue := f.encoder.DoubleMetaphone(v, false)
+		alternatePhoneticValue := f.encoder.DoubleMetaphone(v, true)
+
+		// a flag to lazily save state if needed... this avoids a
+		// save/restore when only one token will be generated.
+		saveState := f.inject
+
+		if primaryPhoneticValue != "" && primaryPhoneticValue != v {
+			if saveState {
+				f.remainingTokens = append(f.remainingTokens, f.Attributes().CaptureState())
+			}
+			f.posAtt.SetPositionIncrement(firstAlternativeIncrement)
+			firstAlternativeIncrement = 0
+			f.termAtt.CopyBuffer([]rune(primaryPhoneticValue))
+			saveState = true
+		}
+
+		if alternatePhoneticValue != "" && alternatePhoneticValue != primaryPhoneticValue &&
+			primaryPhoneticValue != v {
+			if saveState {
+				f.remainingTokens = append(f.remainingTokens, f.Attributes().CaptureState())
+				saveState = false
+			}
+			f.posAtt.SetPositionIncrement(firstAlternativeIncrement)
+			f.termAtt.CopyBuffer([]rune(alternatePhoneticValue))
+			saveState = true
+		}
+
+		// Just one token to return, so no need to capture/restore any
+		// state, simply return it.
+		if len(f.remainingTokens) == 0 {
+			return true, nil
+		}
+
+		if saveState {
+			f.remainingTokens = append(f.remainingTokens, f.Attributes().CaptureState())
+		}
+	}
+}
+
+func (f *DoubleMetaphoneFilter) Reset() error {
+	f.remainingTokens = nil
+	return f.TokenFilter.Reset()
+}
+
+// phonetic/BeiderMorseFilter.java
+
+// the alternatives of a Beider-Morse encoding
+var bmAlternative = regexp.MustCompile(`[^()|\-]+`)
+
+/*
+TokenFilter for Beider-Morse phonetic encoding.
+
+Each alternative phonetic representation of a token is emitted as a
+token at the same position.
+*/
+type BeiderMorseFilter struct {
+	*TokenFilter
+	input  TokenStream
+	engine *PhoneticEngine
+	// the alternatives of the current token still to be emitted
+	alternatives []string
+	state        *util.AttributeState
+
+	termAtt CharTermAttribute
+	posAtt  PositionIncrementAttribute
+}
+
+/*
+Create a new BeiderMorseFilter, using the given PhoneticEngine to
+encode the terms.
+*/
+func NewBeiderMorseFilter(in TokenStream, engine *PhoneticEngine) *BeiderMorseFilter {
+	ans := &BeiderMorseFilter{
+		TokenFilter: NewTokenFilter(in),
+		input:       in,
+		engine:      engine,
+	}
+	ans.termAtt = ans.Attributes().Add("CharTermAttribute").(CharTermAttribute)
+	ans.posAtt = ans.Attributes().Add("PositionIncrementAttribute").(PositionIncrementAttribute)
+	return ans
+}
+
+func (f *BeiderMorseFilter) IncrementToken() (bool, error) {
+	if len(f.alternatives) > 0 {
+		f.Attributes().RestoreState(f.state)
+		f.termAtt.CopyBuffer([]rune(f.alternatives[0]))
+		f.alternatives = f.alternatives[1:]
+		f.posAtt.SetPositionIncrement(0)
+		return true, nil
+	}
+
+	ok, err := f.input.IncrementToken()
+	if err != nil || !ok {
+		return false, err
+	}
+	encoded := f.engine.Encode(string(f.termAtt.Buffer()[:f.termAtt.Length()]))
+	f.state = f.Attributes().CaptureState()
+	if alternatives := bmAlternative.FindAllString(encoded, -1); len(alternatives) > 0 {
+		f.termAtt.CopyBuffer([]rune(alternatives[0]))
+		f.alternatives = alternatives[1:]
+	}
+	return true, nil
+}
+
+func (f *BeiderMorseFilter) Reset() error {
+	f.alternatives, f.state = nil, nil
+	return f.TokenFilter.Reset()
+}
@@ -0,0 +1,149 @@
+package phonetic
+
+import (
+	"fmt"
+	std "github.com/balzaczyy/golucene/analysis/standard"
+	. "github.com/balzaczyy/golucene/core/analysis"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"github.com/balzaczyy/golucene/core/util"
+	"strings"
+	"testing"
+)
+
+/* Returns the terms of the stream, as "term/posInc". */
+func terms(t *testing.T, ts TokenStream) []string {
+	termAtt := ts.Attributes().Add("CharTermAttribute").(CharTermAttribute)
+	posAtt := ts.Attributes().Add("PositionIncrementAttribute").(PositionIncrementAttribute)
+	if err := ts.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	var ans []string
+	for {
+		ok, err := ts.IncrementToken()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			break
+		}
+		ans = append(ans, fmt.Sprintf("%v/%v", string(termAtt.Buffer()[:termAtt.Length()]),
+			posAtt.PositionIncrement()))
+	}
+	if err := ts.End(); err != nil {
+		t.Fatal(err)
+	}
+	if err := ts.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return ans
+}
+
+func assertTerms(t *testing.T, ts TokenStream, expected ...string) {
+	if got := terms(t, ts); fmt.Sprint(got) != fmt.Sprint(expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func tokenizer(text string) TokenStream {
+	return std.NewStandardTokenizer(util.VERSION_LATEST, strings.NewReader(text))
+}
+
+func TestSoundex(t *testing.T) {
+	soundex, refined := NewSoundex(), NewRefinedSoundex()
+	for _, c := range [][3]string{
+		{"Robert", "R163", "R901096"},
+		{"Rupert", "R163", "R901096"},
+		{"Ashcraft", "A261", "A03039026"},
+		{"Tymczak", "T522", "T6083503"},
+		{"Pfister", "P236", "P1203609"},
+		{"testing", "T235", "T6036084"},
+		{"jumped", "J513", "J408106"},
+		{"", "", ""},
+	} {
+		if got := soundex.Encode(c[0]); got != c[1] {
+			t.Errorf("soundex %v: expected %v, got %v", c[0], c[1], got)
+		}
+		if got := refined.Encode(c[0]); got != c[2] {
+			t.Errorf("refined soundex %v: expected %v, got %v", c[0], c[2], got)
+		}
+	}
+}
+
+func TestDoubleMetaphone(t *testing.T) {
+	dm := NewDoubleMetaphone()
+	for _, c := range [][3]string{
+		{"Thompson", "TMPS", "TMPS"},
+		{"Smith", "SM0", "XMT"},
+		{"Schmidt", "XMT", "SMT"},
+		{"Jose", "HS", "HS"},
+		{"Arnow", "ARN", "ARNF"},
+		{"aubrey", "APR", "APR"},
+		{"Wasserman", "ASRM", "FSRM"},
+		{"Kuczewski", "KSSK", "KXFS"},
+		{"international", "ANTR", "ANTR"},
+		{"caesar", "SSR", "SSR"},
+		{"laugh", "LF", "LF"},
+		{"  ", "", ""},
+	} {
+		if got := dm.DoubleMetaphone(c[0], false); got != c[1] {
+			t.Errorf("%v: expected primary %v, got %v", c[0], c[1], got)
+		}
+		if got := dm.DoubleMetaphone(c[0], true); got != c[2] {
+			t.Errorf("%v: expected alternate %v, got %v", c[0], c[2], got)
+		}
+	}
+	if !dm.IsDoubleMetaphoneEqual("Smith", "Smyth", false) {
+		t.Error("Smith and Smyth should have the same encoding")
+	}
+}
+
+func TestPhoneticFilter(t *testing.T) {
+	assertTerms(t, NewPhoneticFilter(tokenizer("Robert Rupert"), NewSoundex(), false),
+		"R163/1", "R163/1")
+	assertTerms(t, NewPhoneticFilter(tokenizer("Robert Rupert"), NewSoundex(), true),
+		"R163/1", "Robert/0", "R163/1", "Rupert/0")
+	assertTerms(t, NewPhoneticFilter(tokenizer("aaa bbb"), NewDoubleMetaphone(), true),
+		"A/1", "aaa/0", "PP/1", "bbb/0")
+}
+
+func TestDoubleMetaphoneFilter(t *testing.T) {
+	assertTerms(t, NewDoubleMetaphoneFilter(tokenizer("international"), 4, false), "ANTR/1")
+	assertTerms(t, NewDoubleMetaphoneFilter(tokenizer("international"), 4, true),
+		"international/1", "ANTR/0")
+	assertTerms(t, NewDoubleMetaphoneFilter(tokenizer("Kuczewski"), 4, false), "KSSK/1", "KXFS/0")
+	assertTerms(t, NewDoubleMetaphoneFilter(tokenizer("Kuczewski"), 4, true),
+		"Kuczewski/1", "KSSK/0", "KXFS/0")
+	// codes longer than the maximum length are truncated
+	assertTerms(t, NewDoubleMetaphoneFilter(tokenizer("international"), 8, false), "ANTRNXNL/1")
+	// a term which encodes to itself is kept once
+	assertTerms(t, NewDoubleMetaphoneFilter(tokenizer("12345 #$%@#^%&"), 8, false), "12345/1")
+}
+
+func TestBeiderMorse(t *testing.T) {
+	exact := NewPhoneticEngine(GENERIC, EXACT, true)
+	for input, expected := range map[string]string{
+		"Schmidt":     "Smidt|skmidt",
+		"Angelo":      "angelo|andZelo",
+		"Cecile":      "tsetsile|tsesile|setsile|sesile",
+		"d'Angelo":    "(angelo|andZelo)-(dangelo|dandZelo)",
+		"van Helsing": "(helsing)-(vanelsing)",
+	} {
+		if got := exact.Encode(input); got != expected {
+			t.Errorf("%v: expected %v, got %v", input, expected, got)
+		}
+	}
+
+	approx := NewPhoneticEngine(GENERIC, APPROX, true)
+	if approx.Encode("Schmidt") != "smit|skmit" || approx.Encode("Smith") != "smit" {
+		t.Errorf("Schmidt and Smith should match: %v, %v", approx.Encode("Schmidt"), approx.Encode("Smith"))
+	}
+	if got := NewPhoneticEngine(ASHKENAZI, EXACT, false).Encode("ben Wasserman"); got != "vaserman" {
+		t.Errorf("expected the prefix to be dropped, got %v", got)
+	}
+	if got := NewPhoneticEngine(GENERIC, EXACT, false).Encode("Kim Jones"); got != "kim-dZones|jones" {
+		t.Errorf("expected each word to be encoded, got %v", got)
+	}
+
+	assertTerms(t, NewBeiderMorseFilter(tokenizer("Angelo Schmidt"), exact),
+		"angelo/1", "andZelo/0", "Smidt/1", "skmidt/0")
+}
@@ -0,0 +1,140 @@
+package phonetic
+
+import (
+	"strings"
+	"unicode"
+)
+
+// codec/StringEncoder.java
+
+/* Encoder encodes a string into its phonetic code. */
+type Encoder interface {
+	// Encodes a string, returning "" if nothing can be encoded.
+	Encode(string) string
+}
+
+// codec/language/Soundex.java
+
+/*
+This is the mapping of the US English alphabet: the code of each
+letter of the alphabet, from 'A' to 'Z', where '0' means the letter
+is not encoded.
+*/
+const US_ENGLISH_MAPPING = "01230120022455012623010202"
+
+/*
+Encodes a string into a Soundex value. Soundex is an encoding used to
+relate similar names, but can also be used as a general purpose
+scheme to find word with similar phonemes.
+*/
+type Soundex struct {
+	mapping string
+	// Whether 'H' and 'W' are ignored instead of separating letters
+	// of the same code, as in American Soundex.
+	specialCaseHW bool
+}
+
+/* Creates an instance using US_ENGLISH_MAPPING. */
+func NewSoundex() *Soundex {
+	return &Soundex{US_ENGLISH_MAPPING, true}
+}
+
+/*
+Creates a soundex instance using the given mapping. This constructor
+can be used to provide an internationalized mapping for a non-Western
+character set; the mapping holds the code of each letter from 'A'.
+*/
+func NewSoundexWithMapping(mapping string) *Soundex {
+	return &Soundex{mapping, false}
+}
+
+/*
+Retrieves the Soundex code for a given string: the first letter
+followed by three digits, such as "R163" for "Robert".
+*/
+func (s *Soundex) Encode(str string) string {
+	str = cleanLetters(str)
+	if str == "" {
+		return str
+	}
+	out := []byte{'0', '0', '0', '0'}
+	count := 0
+	first := str[0]
+	out[count] = first
+	count++
+	lastDigit := s.mapChar(first)
+	for i := 1; i < len(str) && count < len(out); i++ {
+		ch := str[i]
+		if s.specialCaseHW && (ch == 'H' || ch == 'W') {
+			continue
+		}
+		digit := s.mapChar(ch)
+		if digit != '0' && digit != lastDigit {
+			out[count] = digit
+			count++
+		}
+		lastDigit = digit
+	}
+	return string(out)
+}
+
+func (s *Soundex) mapChar(ch byte) byte {
+	index := int(ch - 'A')
+	if index < 0 || index >= len(s.mapping) {
+		return '0'
+	}
+	return s.mapping[index]
+}
+
+// codec/language/RefinedSoundex.java
+
+/* The refined soundex code of each letter from 'A' to 'Z'. */
+const US_ENGLISH_REFINED_MAPPING = "01360240043788015936020505"
+
+/*
+Encodes a string into a Refined Soundex value. A refined soundex code
+is optimized for spell checking words: unlike Soundex, the code has
+no fixed length and every letter is encoded.
+*/
+type RefinedSoundex struct {
+	mapping string
+}
+
+/* Creates an instance using US_ENGLISH_REFINED_MAPPING. */
+func NewRefinedSoundex() *RefinedSoundex {
+	return &RefinedSoundex{US_ENGLISH_REFINED_MAPPING}
+}
+
+/* Retrieves the Refined Soundex code for a given string. */
+func (s *RefinedSoundex) Encode(str string) string {
+	str = cleanLetters(str)
+	if str == "" {
+		return str
+	}
+	var b strings.Builder
+	b.WriteByte(str[0])
+	var last byte = '*'
+	for i := 0; i < len(str); i++ {
+		current := s.mapping[str[i]-'A']
+		if current == last {
+			continue
+		} else if current != 0 {
+			b.WriteByte(current)
+		}
+		last = current
+	}
+	return b.String()
+}
+
+// codec/language/SoundexUtils.java
+
+/* Upper cases the string, keeping only the letters from 'A' to 'Z'. */
+func cleanLetters(str string) string {
+	var b strings.Builder
+	for _, ch := range str {
+		if ch = unicode.ToUpper(ch); ch >= 'A' && ch <= 'Z' {
+			b.WriteRune(ch)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,97 @@
+package pt
+
+import (
+	. "github.com/balzaczyy/golucene/analysis/core"
+	. "github.com/balzaczyy/golucene/analysis/miscellaneous"
+	"github.com/balzaczyy/golucene/analysis/snowball"
+	std "github.com/balzaczyy/golucene/analysis/standard"
+	. "github.com/balzaczyy/golucene/analysis/util"
+	. "github.com/balzaczyy/golucene/core/analysis"
+	"io"
+)
+
+// pt/PortugueseAnalyzer.java
+
+/* The default set of stopwords used by PortugueseAnalyzer, from the Snowball portuguese stop list. */
+var DEFAULT_STOPWORD_SET = map[string]bool{
+	"de": true, "a": true, "o": true, "que": true, "e": true, "do": true,
+	"da": true, "em": true, "um": true, "para": true, "com": true,
+	"não": true, "uma": true, "os": true, "no": true, "se": true,
+	"na": true, "por": true, "mais": true, "as": true, "dos": true,
+	"como": true, "mas": true, "ao": true, "ele": true, "das": true,
+	"à": true, "seu": true, "sua": true, "ou": true, "quando": true,
+	"muito": true, "nos": true, "já": true, "eu": true, "também": true,
+	"só": true, "pelo": true, "pela": true, "até": true, "isso": true,
+	"ela": true, "entre": true, "depois": true, "sem": true,
+	"mesmo": true, "aos": true, "seus": true, "quem": true, "nas": true,
+	"me": true, "esse": true, "eles": true, "você": true, "essa": true,
+	"num": true, "nem": true, "suas": true, "meu": true, "às": true,
+	"minha": true, "numa": true, "pelos": true, "elas": true,
+	"qual": true, "nós": true, "lhe": true, "deles": true, "essas": true,
+	"esses": true, "pelas": true, "este": true, "dele": true, "tu": true,
+	"te": true, "vocês": true, "vos": true, "lhes": true, "meus": true,
+	"minhas": true, "teu": true, "tua": true, "teus": true, "tuas": true,
+	"nosso": true, "nossa": true, "nossos": true, "nossas": true,
+	"dela": true, "delas": true, "esta": true, "estes": true,
+	"estas": true, "aquele": true, "aquela": true, "aqueles": true,
+	"aquelas": true, "isto": true, "aquilo": true, "estou": true,
+	"está": true, "estamos": true, "estão": true, "estive": true,
+	"esteve": true, "estivemos": true, "estiveram": true, "estava": true,
+	"estávamos": true, "estavam": true, "hei": true, "há": true,
+	"havemos": true, "hão": true, "houve": true, "sou": true,
+	"somos": true, "são": true, "era": true, "éramos": true, "eram": true,
+	"fui": true, "foi": true, "fomos": true, "foram": true, "seja": true,
+	"sejam": true, "tenho": true, "tem": true, "temos": true, "têm": true,
+	"tinha": true, "tínhamos": true, "tinham": true, "tive": true,
+	"teve": true, "tivemos": true, "tiveram": true,
+}
+
+/*
+Analyzer for Portuguese.
+
+Builds an analysis chain made of StandardTokenizer, StandardFilter,
+LowerCaseFilter, StopFilter, SetKeywordMarkerFilter if a stem
+exclusion set is provided, and SnowballFilter with the portuguese stemmer.
+*/
+type PortugueseAnalyzer struct {
+	*StopwordAnalyzerBase
+	stemExclusionSet map[string]bool
+}
+
+/* Builds an analyzer with the default stop words: DEFAULT_STOPWORD_SET. */
+func NewPortugueseAnalyzer() *PortugueseAnalyzer {
+	return NewPortugueseAnalyzerWithStopWords(DEFAULT_STOPWORD_SET)
+}
+
+/* Builds an analyzer with the given stop words. */
+func NewPortugueseAnalyzerWithStopWords(stopwords map[string]bool) *PortugueseAnalyzer {
+	return NewPortugueseAnalyzerWithStemExclusions(stopwords, nil)
+}
+
+/*
+Builds an analyzer with the given stop words. If a non-empty stem
+exclusion set is provided this analyzer will add a
+SetKeywordMarkerFilter before stemming.
+*/
+func NewPortugueseAnalyzerWithStemExclusions(stopwords, stemExclusionSet map[string]bool) *PortugueseAnalyzer {
+	ans := &PortugueseAnalyzer{stemExclusionSet: make(map[string]bool)}
+	for k, v := range stemExclusionSet {
+		ans.stemExclusionSet[k] = v
+	}
+	ans.StopwordAnalyzerBase = NewStopwordAnalyzerBaseWithStopWords(stopwords)
+	ans.Spi = ans
+	return ans
+}
+
+func (a *PortugueseAnalyzer) CreateComponents(fieldName string, reader io.RuneReader) *TokenStreamComponents {
+	version := a.Version()
+	source := std.NewStandardTokenizer(version, reader)
+	var result TokenStream = std.NewStandardFilter(version, source)
+	result = NewLowerCaseFilter(version, result)
+	result = NewStopFilter(version, result, a.StopwordSet())
+	if len(a.stemExclusionSet) > 0 {
+		result = NewSetKeywordMarkerFilter(result, a.stemExclusionSet)
+	}
+	result = snowball.NewSnowballFilter(result, snowball.PortugueseStemmer{})
+	return NewTokenStreamComponents(source, result)
+}
@@ -0,0 +1,42 @@
+package reverse
+
+import (
+	. "github.com/balzaczyy/golucene/analysis/util"
+	. "github.com/balzaczyy/golucene/core/analysis"
+)
+
+func init() {
+	RegisterTokenFilterFactory("reverseString", func(args map[string]string) (TokenFilterFactory, error) {
+		f, err := NewReverseStringFilterFactory(args)
+		if err != nil {
+			return nil, err
+		}
+		return f, nil
+	})
+}
+
+// reverse/ReverseStringFilterFactory.java
+
+/*
+Factory for ReverseStringFilter. The optional "withMarker" (false by
+default) prepends START_OF_HEADING_MARKER to the reversed tokens.
+*/
+type ReverseStringFilterFactory struct {
+	*AbstractAnalysisFactory
+	marker rune
+}
+
+func NewReverseStringFilterFactory(args map[string]string) (*ReverseStringFilterFactory, error) {
+	ans := &ReverseStringFilterFactory{AbstractAnalysisFactory: NewAbstractAnalysisFactory(args)}
+	if ans.GetBool("withMarker", false) {
+		ans.marker = START_OF_HEADING_MARKER
+	}
+	if err := ans.Validate(); err != nil {
+		return nil, err
+	}
+	return ans, nil
+}
+
+func (f *ReverseStringFilterFactory) Create(input TokenStream) TokenStream {
+	return NewReverseStringFilterWithMarker(input, f.marker)
+}
@@ -0,0 +1,125 @@
+package reverse
+
+import (
+	. "github.com/balzaczyy/golucene/core/analysis"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"strings"
+)
+
+// reverse/ReverseStringFilter.java
+
+const (
+	// Example marker character: U+0001 (START OF HEADING)
+	START_OF_HEADING_MARKER = '\u0001'
+	// Example marker character: U+001F (INFORMATION SEPARATOR ONE)
+	INFORMATION_SEPARATOR_MARKER = '\u001F'
+	// Example marker character: U+EC00 (PRIVATE USE AREA: EC00)
+	PUA_EC00_MARKER = '\uEC00'
+	// Example marker character: U+200F (RIGHT-TO-LEFT MARK)
+	RTL_DIRECTION_MARKER = '\u200F'
+	// no marker
+	NO_MARKER = rune(0)
+)
+
+/*
+Reverse token string, for example "country" => "yrtnuoc".
+
+If marker is supplied, then tokens will be also prepended by that
+character. For example, with a marker of U+0001, "country" =>
+"\u0001yrtnuoc". This is useful when implementing efficient
+leading wildcards search: index the field both as is and reversed
+with a marker, then rewrite the leading wildcard queries into prefix
+queries on the reversed terms with ReverseWildcard(). The marker
+keeps the reversed terms apart from the original ones.
+*/
+type ReverseStringFilter struct {
+	*TokenFilter
+	input   TokenStream
+	marker  rune
+	termAtt CharTermAttribute
+}
+
+/* Create a new ReverseStringFilter that reverses all tokens in the supplied TokenStream. */
+func NewReverseStringFilter(in TokenStream) *ReverseStringFilter {
+	return NewReverseStringFilterWithMarker(in, NO_MARKER)
+}
+
+/*
+Create a new ReverseStringFilter that reverses and marks all tokens
+in the supplied TokenStream. The reversed tokens are prepended by the
+marker, unless it is NO_MARKER.
+*/
+func NewReverseStringFilterWithMarker(in TokenStream, marker rune) *ReverseStringFilter {
+	ans := &ReverseStringFilter{
+		TokenFilter: NewTokenFilter(in),
+		input:       in,
+		marker:      marker,
+	}
+	ans.termAtt = ans.Attributes().Add("CharTermAttribute").(CharTermAttribute)
+	return ans
+}
+
+func (f *ReverseStringFilter) IncrementToken() (bool, error) {
+	ok, err := f.input.IncrementToken()
+	if err != nil || !ok {
+		return false, err
+	}
+	length := f.termAtt.Length()
+	if f.marker != NO_MARKER {
+		f.termAtt.CopyBuffer(append([]rune{f.marker}, f.termAtt.Buffer()[:length]...))
+		Reverse(f.termAtt.Buffer()[1 : length+1])
+	} else {
+		Reverse(f.termAtt.Buffer()[:length])
+	}
+	return true, nil
+}
+
+/* Reverses the given runes in place. */
+func Reverse(buffer []rune) {
+	for i, j := 0, len(buffer)-1; i < j; i, j = i+1, j-1 {
+		buffer[i], buffer[j] = buffer[j], buffer[i]
+	}
+}
+
+/* Returns the reversed string, like "country" => "yrtnuoc". */
+func ReverseString(s string) string {
+	runes := []rune(s)
+	Reverse(runes)
+	return string(runes)
+}
+
+/*
+Rewrites a wildcard pattern with a leading wildcard, like "*tion" or
+"?ation", into the pattern to match against the terms indexed by a
+ReverseStringFilter with the given marker: "*tion" becomes
+"\u0001noit*", a prefix pattern. It returns false if the pattern has
+no leading wildcard, or a wildcard at both ends, for which the
+original field is the better bet. The wildcards are '*' and '?', and
+'\\' escapes them.
+*/
+func ReverseWildcard(pattern string, marker rune) (string, bool) {
+	runes := []rune(pattern)
+	if len(runes) == 0 || (runes[0] != '*' && runes[0] != '?') {
+		return "", false
+	}
+	if last := len(runes) - 1; last > 0 && (runes[last] == '*' || runes[last] == '?') &&
+		runes[last-1] != '\\' {
+		return "", false
+	}
+	// reverse the pattern, keeping the escapes in front of what
+	// they escape
+	var b strings.Builder
+	if marker != NO_MARKER {
+		b.WriteRune(marker)
+	}
+	for i := len(runes) - 1; i >= 0; i-- {
+		if i > 0 && runes[i-1] == '\\' {
+			b.WriteRune('\\')
+			b.WriteRune(runes[i])
+			i--
+		} else {
+			b.WriteRune(runes[i])
+		}
+	}
+	return b.String(), true
+}
@@ -0,0 +1,56 @@
+package reverse
+
+import (
+	"fmt"
+	std "github.com/balzaczyy/golucene/analysis/standard"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"github.com/balzaczyy/golucene/core/util"
+	"strings"
+	"testing"
+)
+
+func TestReverseStringFilter(t *testing.T) {
+	for marker, expected := range map[rune]string{
+		NO_MARKER:               "[oD evah dooG 𐐁𐐀]",
+		START_OF_HEADING_MARKER: "[\u0001oD \u0001evah \u0001dooG \u0001𐐁𐐀]",
+	} {
+		ts := NewReverseStringFilterWithMarker(std.NewStandardTokenizer(util.VERSION_LATEST,
+			strings.NewReader("Do have Good 𐐀𐐁")), marker)
+		termAtt := ts.Attributes().Get("CharTermAttribute").(CharTermAttribute)
+		if err := ts.Reset(); err != nil {
+			t.Fatal(err)
+		}
+		var terms []string
+		for {
+			ok, err := ts.IncrementToken()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !ok {
+				break
+			}
+			terms = append(terms, string(termAtt.Buffer()[:termAtt.Length()]))
+		}
+		ts.End()
+		ts.Close()
+		if got := fmt.Sprint(terms); got != expected {
+			t.Errorf("marker %q: expected %q, but was %q", marker, expected, got)
+		}
+	}
+}
+
+func TestReverseWildcard(t *testing.T) {
+	for pattern, expected := range map[string]string{
+		"*tion":    "\u0001noit*",
+		"?ation":   "\u0001noita?",
+		"*a\\*b":   "\u0001b\\*a*",
+		"*tion*":   "",
+		"tion*":    "",
+		"*tion\\*": "\u0001\\*noit*",
+	} {
+		got, ok := ReverseWildcard(pattern, START_OF_HEADING_MARKER)
+		if ok != (expected != "") || got != expected {
+			t.Errorf("%q: expected %q, but was %q", pattern, expected, got)
+		}
+	}
+}
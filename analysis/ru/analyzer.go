@@ -0,0 +1,98 @@
+package ru
+
+import (
+	. "github.com/balzaczyy/golucene/analysis/core"
+	. "github.com/balzaczyy/golucene/analysis/miscellaneous"
+	"github.com/balzaczyy/golucene/analysis/snowball"
+	std "github.com/balzaczyy/golucene/analysis/standard"
+	. "github.com/balzaczyy/golucene/analysis/util"
+	. "github.com/balzaczyy/golucene/core/analysis"
+	"io"
+)
+
+// ru/RussianAnalyzer.java
+
+/* The default set of stopwords used by RussianAnalyzer, from the Snowball russian stop list. */
+var DEFAULT_STOPWORD_SET = map[string]bool{
+	"и": true, "в": true, "во": true, "не": true, "что": true, "он": true,
+	"на": true, "я": true, "с": true, "со": true, "как": true, "а": true,
+	"то": true, "все": true, "она": true, "так": true, "его": true,
+	"но": true, "да": true, "ты": true, "к": true, "у": true, "же": true,
+	"вы": true, "за": true, "бы": true, "по": true, "только": true,
+	"ее": true, "мне": true, "было": true, "вот": true, "от": true,
+	"меня": true, "еще": true, "нет": true, "о": true, "из": true,
+	"ему": true, "теперь": true, "когда": true, "даже": true, "ну": true,
+	"вдруг": true, "ли": true, "если": true, "уже": true, "или": true,
+	"ни": true, "быть": true, "был": true, "него": true, "до": true,
+	"вас": true, "нибудь": true, "опять": true, "уж": true, "вам": true,
+	"ведь": true, "там": true, "потом": true, "себя": true,
+	"ничего": true, "ей": true, "может": true, "они": true, "тут": true,
+	"где": true, "есть": true, "надо": true, "ней": true, "для": true,
+	"мы": true, "тебя": true, "их": true, "чем": true, "была": true,
+	"сам": true, "чтоб": true, "без": true, "будто": true, "чего": true,
+	"раз": true, "тоже": true, "себе": true, "под": true, "будет": true,
+	"ж": true, "тогда": true, "кто": true, "этот": true, "того": true,
+	"потому": true, "этого": true, "какой": true, "совсем": true,
+	"ним": true, "здесь": true, "этом": true, "один": true, "почти": true,
+	"мой": true, "тем": true, "чтобы": true, "нее": true, "сейчас": true,
+	"были": true, "куда": true, "зачем": true, "всех": true,
+	"никогда": true, "можно": true, "при": true, "наконец": true,
+	"два": true, "об": true, "другой": true, "хоть": true, "после": true,
+	"над": true, "больше": true, "тот": true, "через": true, "эти": true,
+	"нас": true, "про": true, "всего": true, "них": true, "какая": true,
+	"много": true, "разве": true, "три": true, "эту": true, "моя": true,
+	"впрочем": true, "хорошо": true, "свою": true, "этой": true,
+	"перед": true, "иногда": true, "лучше": true, "чуть": true,
+	"том": true, "нельзя": true, "такой": true, "им": true, "более": true,
+	"всегда": true, "конечно": true, "всю": true, "между": true,
+}
+
+/*
+Analyzer for Russian.
+
+Builds an analysis chain made of StandardTokenizer, StandardFilter,
+LowerCaseFilter, StopFilter, SetKeywordMarkerFilter if a stem
+exclusion set is provided, and SnowballFilter with the russian stemmer.
+*/
+type RussianAnalyzer struct {
+	*StopwordAnalyzerBase
+	stemExclusionSet map[string]bool
+}
+
+/* Builds an analyzer with the default stop words: DEFAULT_STOPWORD_SET. */
+func NewRussianAnalyzer() *RussianAnalyzer {
+	return NewRussianAnalyzerWithStopWords(DEFAULT_STOPWORD_SET)
+}
+
+/* Builds an analyzer with the given stop words. */
+func NewRussianAnalyzerWithStopWords(stopwords map[string]bool) *RussianAnalyzer {
+	return NewRussianAnalyzerWithStemExclusions(stopwords, nil)
+}
+
+/*
+Builds an analyzer with the given stop words. If a non-empty stem
+exclusion set is provided this analyzer will add a
+SetKeywordMarkerFilter before stemming.
+*/
+func NewRussianAnalyzerWithStemExclusions(stopwords, stemExclusionSet map[string]bool) *RussianAnalyzer {
+	ans := &RussianAnalyzer{stemExclusionSet: make(map[string]bool)}
+	for k, v := range stemExclusionSet {
+		ans.stemExclusionSet[k] = v
+	}
+	ans.StopwordAnalyzerBase = NewStopwordAnalyzerBaseWithStopWords(stopwords)
+	ans.Spi = ans
+	return ans
+}
+
+func (a *RussianAnalyzer) CreateComponents(fieldName string, reader io.RuneReader) *TokenStreamComponents {
+	version := a.Version()
+	source := std.NewStandardTokenizer(version, reader)
+	var result TokenStream = std.NewStandardFilter(version, source)
+	result = NewLowerCaseFilter(version, result)
+	result = NewStopFilter(version, result, a.StopwordSet())
+	if len(a.stemExclusionSet) > 0 {
+		result = NewSetKeywordMarkerFilter(result, a.stemExclusionSet)
+	}
+	result = snowball.NewSnowballFilter(result, snowball.RussianStemmer{})
+	return NewTokenStreamComponents(source, result)
+}
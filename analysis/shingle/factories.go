@@ -0,0 +1,68 @@
+package shingle
+
+import (
+	"errors"
+	. "github.com/balzaczyy/golucene/analysis/util"
+	. "github.com/balzaczyy/golucene/core/analysis"
+)
+
+func init() {
+	RegisterTokenFilterFactory("shingle", func(args map[string]string) (TokenFilterFactory, error) {
+		f, err := NewShingleFilterFactory(args)
+		if err != nil {
+			return nil, err
+		}
+		return f, nil
+	})
+}
+
+// shingle/ShingleFilterFactory.java
+
+/*
+Factory for ShingleFilter, taking the "minShingleSize" and
+"maxShingleSize" (both 2 by default), whether to "outputUnigrams"
+(true by default) and "outputUnigramsIfNoShingles" (false by
+default), the "tokenSeparator" (a space by default) and the
+"fillerToken" ("_" by default).
+*/
+type ShingleFilterFactory struct {
+	*AbstractAnalysisFactory
+	minShingleSize             int
+	maxShingleSize             int
+	outputUnigrams             bool
+	outputUnigramsIfNoShingles bool
+	tokenSeparator             string
+	fillerToken                string
+}
+
+func NewShingleFilterFactory(args map[string]string) (*ShingleFilterFactory, error) {
+	ans := &ShingleFilterFactory{AbstractAnalysisFactory: NewAbstractAnalysisFactory(args)}
+	ans.maxShingleSize = ans.GetInt("maxShingleSize", DEFAULT_MAX_SHINGLE_SIZE)
+	ans.minShingleSize = ans.GetInt("minShingleSize", DEFAULT_MIN_SHINGLE_SIZE)
+	ans.outputUnigrams = ans.GetBool("outputUnigrams", true)
+	ans.outputUnigramsIfNoShingles = ans.GetBool("outputUnigramsIfNoShingles", false)
+	ans.tokenSeparator = ans.Get("tokenSeparator", DEFAULT_TOKEN_SEPARATOR)
+	ans.fillerToken = ans.Get("fillerToken", DEFAULT_FILLER_TOKEN)
+	if err := ans.Validate(); err != nil {
+		return nil, err
+	}
+	if ans.maxShingleSize < 2 {
+		return nil, errors.New("Invalid maxShingleSize: must be >= 2")
+	}
+	if ans.minShingleSize < 2 {
+		return nil, errors.New("Invalid minShingleSize: must be >= 2")
+	}
+	if ans.minShingleSize > ans.maxShingleSize {
+		return nil, errors.New("Invalid minShingleSize: must be <= maxShingleSize")
+	}
+	return ans, nil
+}
+
+func (f *ShingleFilterFactory) Create(input TokenStream) TokenStream {
+	ans := NewShingleFilterWithSizes(input, f.minShingleSize, f.maxShingleSize)
+	ans.SetOutputUnigrams(f.outputUnigrams)
+	ans.SetOutputUnigramsIfNoShingles(f.outputUnigramsIfNoShingles)
+	ans.SetTokenSeparator(f.tokenSeparator)
+	ans.SetFillerToken(f.fillerToken)
+	return ans
+}
@@ -0,0 +1,271 @@
+package shingle
+
+import (
+	. "github.com/balzaczyy/golucene/core/analysis"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"github.com/balzaczyy/golucene/core/util"
+	"strings"
+)
+
+// shingle/ShingleFilter.java
+
+const (
+	// default maximum shingle size is 2.
+	DEFAULT_MAX_SHINGLE_SIZE = 2
+	// default minimum shingle size is 2.
+	DEFAULT_MIN_SHINGLE_SIZE = 2
+	// default token type attribute value is "shingle"
+	DEFAULT_TOKEN_TYPE = "shingle"
+	// The default string to use when joining adjacent tokens to form a shingle
+	DEFAULT_TOKEN_SEPARATOR = " "
+	// The default string to insert for position increments > 1
+	DEFAULT_FILLER_TOKEN = "_"
+)
+
+/*
+A ShingleFilter constructs shingles (token n-grams) from a token
+stream. In other words, it creates combinations of tokens as a single
+token.
+
+For example, the sentence "please divide this sentence into shingles"
+might be tokenized into shingles "please divide", "divide this", "this
+sentence", "sentence into", and "into shingles".
+
+This filter handles position increments > 1 by inserting filler
+tokens (tokens with termtext "_"). It does not handle a position
+increment of 0.
+
+Unigrams are emitted first at each position, followed by the shingles
+starting there in increasing size; shingles have a position length
+equal to the number of tokens they are made of.
+*/
+type ShingleFilter struct {
+	*TokenFilter
+	input TokenStream
+
+	minShingleSize             int
+	maxShingleSize             int
+	tokenType                  string
+	tokenSeparator             string
+	fillerToken                string
+	outputUnigrams             bool
+	outputUnigramsIfNoShingles bool
+
+	termAtt    CharTermAttribute
+	offsetAtt  OffsetAttribute
+	posIncrAtt PositionIncrementAttribute
+	posLenAtt  PositionLengthAttribute
+	typeAtt    TypeAttribute
+
+	// input tokens from the current position on
+	window    []*shingleToken
+	exhausted bool
+	// next size to emit at the current position, 0 to move on
+	size int
+	// positions passed since the last emitted token
+	pendingPosInc int
+	// true if the whole stream is too short for a single shingle
+	noShingles, noShinglesDecided bool
+}
+
+type shingleToken struct {
+	// nil for a filler token
+	state                  *util.AttributeState
+	term                   string
+	startOffset, endOffset int
+}
+
+/* Constructs a ShingleFilter with the default shingle sizes: 2 */
+func NewShingleFilter(in TokenStream) *ShingleFilter {
+	return NewShingleFilterWithSizes(in, DEFAULT_MIN_SHINGLE_SIZE, DEFAULT_MAX_SHINGLE_SIZE)
+}
+
+/* Constructs a ShingleFilter with the specified shingle size from the TokenStream input */
+func NewShingleFilterWithSizes(in TokenStream, minShingleSize, maxShingleSize int) *ShingleFilter {
+	ans := &ShingleFilter{
+		TokenFilter:    NewTokenFilter(in),
+		input:          in,
+		tokenType:      DEFAULT_TOKEN_TYPE,
+		tokenSeparator: DEFAULT_TOKEN_SEPARATOR,
+		fillerToken:    DEFAULT_FILLER_TOKEN,
+		outputUnigrams: true,
+	}
+	ans.SetMaxShingleSize(maxShingleSize)
+	ans.SetMinShingleSize(minShingleSize)
+	ans.termAtt = ans.Attributes().Add("CharTermAttribute").(CharTermAttribute)
+	ans.offsetAtt = ans.Attributes().Add("OffsetAttribute").(OffsetAttribute)
+	ans.posIncrAtt = ans.Attributes().Add("PositionIncrementAttribute").(PositionIncrementAttribute)
+	ans.posLenAtt = ans.Attributes().Add("PositionLengthAttribute").(PositionLengthAttribute)
+	ans.typeAtt = ans.Attributes().Add("TypeAttribute").(TypeAttribute)
+	return ans
+}
+
+/* Set the type of the shingle tokens produced by this filter. (default: "shingle") */
+func (f *ShingleFilter) SetTokenType(tokenType string) {
+	f.tokenType = tokenType
+}
+
+/* Shall the output stream contain the input tokens (unigrams) as well as shingles? (default: true.) */
+func (f *ShingleFilter) SetOutputUnigrams(outputUnigrams bool) {
+	f.outputUnigrams = outputUnigrams
+}
+
+/*
+Shall we override the behavior of outputUnigrams==false for those
+times when no shingles are available (because there are fewer than
+minShingleSize tokens in the input stream)? (default: false.)
+
+Note that if outputUnigrams==true, then unigrams are always output,
+regardless of whether any shingles are available.
+*/
+func (f *ShingleFilter) SetOutputUnigramsIfNoShingles(outputUnigramsIfNoShingles bool) {
+	f.outputUnigramsIfNoShingles = outputUnigramsIfNoShingles
+}
+
+/* Set the max shingle size (default: 2) */
+func (f *ShingleFilter) SetMaxShingleSize(maxShingleSize int) {
+	if maxShingleSize < 2 {
+		panic("Max shingle size must be >= 2")
+	}
+	f.maxShingleSize = maxShingleSize
+}
+
+/*
+Set the min shingle size (default: 2).
+
+This method requires that the passed in minShingleSize is not greater
+than maxShingleSize, so make sure that maxShingleSize is set before
+calling this method.
+*/
+func (f *ShingleFilter) SetMinShingleSize(minShingleSize int) {
+	if minShingleSize < 2 {
+		panic("Min shingle size must be >= 2")
+	}
+	if minShingleSize > f.maxShingleSize {
+		panic("Min shingle size must be <= max shingle size")
+	}
+	f.minShingleSize = minShingleSize
+}
+
+/* Sets the string to use when joining adjacent tokens to form a shingle */
+func (f *ShingleFilter) SetTokenSeparator(tokenSeparator string) {
+	f.tokenSeparator = tokenSeparator
+}
+
+/* Sets the string to insert for each position at which there is no token (i.e., when position increment is greater than one). */
+func (f *ShingleFilter) SetFillerToken(fillerToken string) {
+	f.fillerToken = fillerToken
+}
+
+func (f *ShingleFilter) IncrementToken() (bool, error) {
+	for {
+		if f.size == 0 {
+			// start a new position
+			if err := f.fill(); err != nil {
+				return false, err
+			}
+			if len(f.window) == 0 {
+				return false, nil
+			}
+			f.size = 1
+			f.pendingPosInc++
+		}
+
+		size := f.size
+		if size > f.maxShingleSize || size > len(f.window) {
+			// done with this position
+			f.window = f.window[1:]
+			f.size = 0
+			continue
+		}
+		f.size++
+
+		if size == 1 {
+			if f.window[0].state == nil || !f.outputUnigrams && !f.noShingles {
+				continue
+			}
+			f.Attributes().RestoreState(f.window[0].state)
+			f.setPosition(1)
+			return true, nil
+		}
+		if size >= f.minShingleSize {
+			f.emitShingle(size)
+			return true, nil
+		}
+	}
+}
+
+func (f *ShingleFilter) setPosition(posLen int) {
+	f.posIncrAtt.SetPositionIncrement(f.pendingPosInc)
+	f.posLenAtt.SetPositionLength(posLen)
+	f.pendingPosInc = 0
+}
+
+/* Sets the attributes to the shingle of the first size tokens of the window. */
+func (f *ShingleFilter) emitShingle(size int) {
+	tokens := f.window[:size]
+	terms := make([]string, size)
+	restored := false
+	for i, token := range tokens {
+		if token.state != nil && !restored {
+			// attributes other than those we set come from the first real token
+			f.Attributes().RestoreState(token.state)
+			restored = true
+		}
+		terms[i] = token.term
+	}
+	if !restored {
+		f.Attributes().Clear()
+	}
+	f.termAtt.CopyBuffer([]rune(strings.Join(terms, f.tokenSeparator)))
+	f.offsetAtt.SetOffset(tokens[0].startOffset, tokens[size-1].endOffset)
+	f.typeAtt.SetType(f.tokenType)
+	f.setPosition(size)
+}
+
+/* Fills the window up to maxShingleSize tokens, inserting fillers for holes. */
+func (f *ShingleFilter) fill() error {
+	for len(f.window) < f.maxShingleSize && !f.exhausted {
+		ok, err := f.input.IncrementToken()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			f.exhausted = true
+			break
+		}
+
+		startOffset := f.offsetAtt.StartOffset()
+		for i := 1; i < f.posIncrAtt.PositionIncrement(); i++ {
+			f.window = append(f.window, &shingleToken{
+				term:        f.fillerToken,
+				startOffset: startOffset,
+				endOffset:   startOffset,
+			})
+		}
+		f.window = append(f.window, &shingleToken{
+			state:       f.Attributes().CaptureState(),
+			term:        string(f.termAtt.Buffer()[:f.termAtt.Length()]),
+			startOffset: startOffset,
+			endOffset:   f.offsetAtt.EndOffset(),
+		})
+	}
+	if !f.noShinglesDecided {
+		// fewer tokens than the window size means the input is exhausted
+		f.noShingles = f.outputUnigramsIfNoShingles && len(f.window) < f.minShingleSize
+		f.noShinglesDecided = true
+	}
+	return nil
+}
+
+func (f *ShingleFilter) Reset() error {
+	if err := f.TokenFilter.Reset(); err != nil {
+		return err
+	}
+	f.window = nil
+	f.exhausted = false
+	f.size = 0
+	f.pendingPosInc = 0
+	f.noShingles, f.noShinglesDecided = false, false
+	return nil
+}
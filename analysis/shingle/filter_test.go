@@ -0,0 +1,97 @@
+package shingle
+
+import (
+	"fmt"
+	"github.com/balzaczyy/golucene/analysis/core"
+	std "github.com/balzaczyy/golucene/analysis/standard"
+	. "github.com/balzaczyy/golucene/core/analysis"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"github.com/balzaczyy/golucene/core/util"
+	"strings"
+	"testing"
+)
+
+func shingles(t *testing.T, f *ShingleFilter) string {
+	termAtt := f.Attributes().Get("CharTermAttribute").(CharTermAttribute)
+	posIncAtt := f.Attributes().Get("PositionIncrementAttribute").(PositionIncrementAttribute)
+	posLenAtt := f.Attributes().Get("PositionLengthAttribute").(PositionLengthAttribute)
+	offsetAtt := f.Attributes().Get("OffsetAttribute").(OffsetAttribute)
+	typeAtt := f.Attributes().Get("TypeAttribute").(TypeAttribute)
+	if err := f.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	var tokens []string
+	for {
+		ok, err := f.IncrementToken()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			break
+		}
+		token := fmt.Sprintf("%v/%v/%v/%v-%v", string(termAtt.Buffer()[:termAtt.Length()]),
+			posIncAtt.PositionIncrement(), posLenAtt.PositionLength(),
+			offsetAtt.StartOffset(), offsetAtt.EndOffset())
+		if typeAtt.Type() == DEFAULT_TOKEN_TYPE {
+			token += "/s"
+		}
+		tokens = append(tokens, token)
+	}
+	if err := f.End(); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return strings.Join(tokens, " ")
+}
+
+func tokenizer(text string) TokenStream {
+	return std.NewStandardTokenizer(util.VERSION_LATEST, strings.NewReader(text))
+}
+
+func TestShingleFilter(t *testing.T) {
+	f := NewShingleFilter(tokenizer("please divide this"))
+	if got, expected := shingles(t, f),
+		"please/1/1/0-6 please divide/0/2/0-13/s divide/1/1/7-13 divide this/0/2/7-18/s this/1/1/14-18"; got != expected {
+		t.Errorf("expected %v, but was %v", expected, got)
+	}
+
+	f = NewShingleFilterWithSizes(tokenizer("a b c d"), 2, 3)
+	f.SetOutputUnigrams(false)
+	f.SetTokenSeparator("_")
+	if got, expected := shingles(t, f),
+		"a_b/1/2/0-3/s a_b_c/0/3/0-5/s b_c/1/2/2-5/s b_c_d/0/3/2-7/s c_d/1/2/4-7/s"; got != expected {
+		t.Errorf("expected %v, but was %v", expected, got)
+	}
+
+	f = NewShingleFilterWithSizes(tokenizer("a b c"), 3, 3)
+	f.SetOutputUnigrams(false)
+	if got, expected := shingles(t, f), "a b c/1/3/0-5/s"; got != expected {
+		t.Errorf("expected %v, but was %v", expected, got)
+	}
+
+	// too short for any shingle
+	f = NewShingleFilterWithSizes(tokenizer("a b"), 3, 3)
+	f.SetOutputUnigrams(false)
+	if got := shingles(t, f); got != "" {
+		t.Errorf("expected no tokens, but was %v", got)
+	}
+	f = NewShingleFilterWithSizes(tokenizer("a b"), 3, 3)
+	f.SetOutputUnigrams(false)
+	f.SetOutputUnigramsIfNoShingles(true)
+	if got, expected := shingles(t, f), "a/1/1/0-1 b/1/1/2-3"; got != expected {
+		t.Errorf("expected %v, but was %v", expected, got)
+	}
+}
+
+func TestShingleFilterWithHoles(t *testing.T) {
+	stop := core.NewStopFilter(util.VERSION_LATEST, tokenizer("please divide this sentence"),
+		map[string]bool{"this": true})
+	f := NewShingleFilter(stop)
+	if got, expected := shingles(t, f),
+		"please/1/1/0-6 please divide/0/2/0-13/s divide/1/1/7-13 divide _/0/2/7-19/s "+
+			"_ sentence/1/2/19-27/s sentence/1/1/19-27"; got != expected {
+		t.Errorf("expected %v, but was %v", expected, got)
+	}
+}
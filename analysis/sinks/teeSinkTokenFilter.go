@@ -0,0 +1,208 @@
+package sinks
+
+import (
+	"errors"
+	. "github.com/balzaczyy/golucene/core/analysis"
+	"github.com/balzaczyy/golucene/core/util"
+)
+
+// sinks/TeeSinkTokenFilter.java
+
+/*
+This TokenFilter provides the ability to set aside attribute states
+that have already been analyzed. This is useful in situations where
+multiple fields share many common analysis steps and then go their
+separate ways.
+
+It is also useful for doing things like entity extraction or proper
+noun analysis as part of the analysis workflow and saving off those
+tokens for use in another field:
+
+	source1 := NewTeeSinkTokenFilter(NewStandardTokenizer(version, reader1))
+	sink1 := source1.NewSinkTokenStream()
+	sink2 := source1.NewSinkTokenStream()
+
+	source2 := NewTeeSinkTokenFilter(NewStandardTokenizer(version, reader2))
+	source2.AddSinkTokenStream(sink1)
+	source2.AddSinkTokenStream(sink2)
+
+	final1 := NewLowerCaseFilter(version, source1)
+	final2 := source2
+	final3 := NewEntityDetect(sink1)
+	final4 := NewURLDetect(sink2)
+
+In this example, sink1 and sink2 will both get tokens from both
+reader1 and reader2 after whitespace tokenizer and now we can further
+wrap any of these in extra analysis, and more "sources" can be
+inserted if desired. The tee must be consumed before its sinks;
+ConsumeAllTokens() can be used to fill the sinks up front.
+
+The states are captured with every attribute of the tee, including
+the ones registered by other packages, so the sinks replay them
+exactly.
+*/
+type TeeSinkTokenFilter struct {
+	*TokenFilter
+	input TokenStream
+	sinks []*SinkTokenStream
+}
+
+/* Instantiates a new TeeSinkTokenFilter. */
+func NewTeeSinkTokenFilter(input TokenStream) *TeeSinkTokenFilter {
+	return &TeeSinkTokenFilter{
+		TokenFilter: NewTokenFilter(input),
+		input:       input,
+	}
+}
+
+/* Returns a new SinkTokenStream that receives all tokens consumed by this stream. */
+func (f *TeeSinkTokenFilter) NewSinkTokenStream() *SinkTokenStream {
+	return f.NewSinkTokenStreamWithFilter(ACCEPT_ALL_FILTER)
+}
+
+/*
+Returns a new SinkTokenStream that receives all tokens consumed by
+this stream that pass the supplied filter.
+*/
+func (f *TeeSinkTokenFilter) NewSinkTokenStreamWithFilter(filter SinkFilter) *SinkTokenStream {
+	sink := &SinkTokenStream{
+		TokenStreamImpl: NewTokenStreamWith(f.Attributes().CloneAttributes()),
+		filter:          filter,
+	}
+	f.sinks = append(f.sinks, sink)
+	return sink
+}
+
+/*
+Adds a SinkTokenStream created by another TeeSinkTokenFilter to this
+one. The supplied stream will also receive all consumed tokens. This
+method can be used to pass tokens from two different tees to one sink.
+*/
+func (f *TeeSinkTokenFilter) AddSinkTokenStream(sink *SinkTokenStream) error {
+	// check that sink has correct factory
+	if f.Attributes().Factory() != sink.Attributes().Factory() {
+		return errors.New("The supplied sink is not compatible to this tee")
+	}
+	// add eventually missing attribute impls to the existing sink
+	for _, att := range f.Attributes().CloneAttributes().AttributeImpls() {
+		sink.Attributes().AddImpl(att)
+	}
+	f.sinks = append(f.sinks, sink)
+	return nil
+}
+
+/*
+TeeSinkTokenFilter passes all tokens to the added sinks when itself
+is consumed. To be sure that all tokens from the input stream are
+passed to the sinks, you can call this method. This instance is
+exhausted after this, but all sinks are instantly available.
+*/
+func (f *TeeSinkTokenFilter) ConsumeAllTokens() error {
+	for {
+		ok, err := f.IncrementToken()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+	}
+}
+
+func (f *TeeSinkTokenFilter) IncrementToken() (bool, error) {
+	ok, err := f.input.IncrementToken()
+	if !ok || err != nil {
+		return ok, err
+	}
+	// capture state lazily - maybe no SinkFilter accepts this state
+	var state *util.AttributeState
+	for _, sink := range f.sinks {
+		if sink.filter.Accept(f.Attributes()) {
+			if state == nil {
+				state = f.Attributes().CaptureState()
+			}
+			if err = sink.addState(state); err != nil {
+				return false, err
+			}
+		}
+	}
+	return true, nil
+}
+
+func (f *TeeSinkTokenFilter) End() error {
+	if err := f.TokenFilter.End(); err != nil {
+		return err
+	}
+	finalState := f.Attributes().CaptureState()
+	for _, sink := range f.sinks {
+		sink.finalState = finalState
+	}
+	return nil
+}
+
+func (f *TeeSinkTokenFilter) Reset() error {
+	for _, sink := range f.sinks {
+		if err := sink.filter.Reset(); err != nil {
+			return err
+		}
+	}
+	return f.TokenFilter.Reset()
+}
+
+/* A filter that decides which AttributeSource states to store in the sink. */
+type SinkFilter interface {
+	// Returns true, iff the current state of the passed-in
+	// AttributeSource shall be stored in the sink.
+	Accept(source *util.AttributeSource) bool
+	// Called by TeeSinkTokenFilter.Reset(). This method does nothing by
+	// default and can optionally be overridden.
+	Reset() error
+}
+
+type acceptAllFilter struct{}
+
+func (f acceptAllFilter) Accept(source *util.AttributeSource) bool { return true }
+func (f acceptAllFilter) Reset() error                             { return nil }
+
+/* A SinkFilter that accepts every token. */
+var ACCEPT_ALL_FILTER SinkFilter = acceptAllFilter{}
+
+/* TokenStream output from a tee with optional filtering. */
+type SinkTokenStream struct {
+	*TokenStreamImpl
+	cachedStates []*util.AttributeState
+	finalState   *util.AttributeState
+	filter       SinkFilter
+	it           int
+	consuming    bool
+}
+
+func (s *SinkTokenStream) addState(state *util.AttributeState) error {
+	if s.consuming {
+		return errors.New("The tee must be consumed before sinks are consumed.")
+	}
+	s.cachedStates = append(s.cachedStates, state)
+	return nil
+}
+
+func (s *SinkTokenStream) IncrementToken() (bool, error) {
+	s.consuming = true
+	if s.it >= len(s.cachedStates) {
+		return false, nil
+	}
+	s.Attributes().RestoreState(s.cachedStates[s.it])
+	s.it++
+	return true, nil
+}
+
+func (s *SinkTokenStream) End() error {
+	if s.finalState != nil {
+		s.Attributes().RestoreState(s.finalState)
+	}
+	return nil
+}
+
+func (s *SinkTokenStream) Reset() error {
+	s.it = 0
+	return nil
+}
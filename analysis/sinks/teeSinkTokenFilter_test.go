@@ -0,0 +1,161 @@
+package sinks
+
+import (
+	"fmt"
+	std "github.com/balzaczyy/golucene/analysis/standard"
+	. "github.com/balzaczyy/golucene/core/analysis"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"github.com/balzaczyy/golucene/core/util"
+	"strings"
+	"testing"
+)
+
+/* A user-defined attribute, only known to this test. */
+type colorAttribute interface {
+	Color() string
+	SetColor(string)
+}
+
+type colorAttributeImpl struct {
+	color string
+}
+
+func init() {
+	util.RegisterAttributeImpl(func() util.AttributeImpl { return new(colorAttributeImpl) })
+}
+
+func (a *colorAttributeImpl) Interfaces() []string      { return []string{"colorAttribute"} }
+func (a *colorAttributeImpl) Color() string             { return a.color }
+func (a *colorAttributeImpl) SetColor(color string)     { a.color = color }
+func (a *colorAttributeImpl) Clear()                    { a.color = "" }
+func (a *colorAttributeImpl) Clone() util.AttributeImpl { return &colorAttributeImpl{a.color} }
+func (a *colorAttributeImpl) CopyTo(target util.AttributeImpl) {
+	target.(colorAttribute).SetColor(a.color)
+}
+
+func (a *colorAttributeImpl) ReflectWith(reflector util.AttributeReflector) {
+	reflector("colorAttribute", "color", a.color)
+}
+
+/* Colors numbers red and everything else blue. */
+type colorFilter struct {
+	*TokenFilter
+	input    TokenStream
+	typeAtt  TypeAttribute
+	colorAtt colorAttribute
+}
+
+func newColorFilter(in TokenStream) *colorFilter {
+	ans := &colorFilter{TokenFilter: NewTokenFilter(in), input: in}
+	ans.typeAtt = ans.Attributes().Add("TypeAttribute").(TypeAttribute)
+	ans.colorAtt = ans.Attributes().Add("colorAttribute").(colorAttribute)
+	return ans
+}
+
+func (f *colorFilter) IncrementToken() (bool, error) {
+	ok, err := f.input.IncrementToken()
+	if ok {
+		if f.typeAtt.Type() == "<NUM>" {
+			f.colorAtt.SetColor("red")
+		} else {
+			f.colorAtt.SetColor("blue")
+		}
+	}
+	return ok, err
+}
+
+func consume(t *testing.T, ts TokenStream, reset bool) string {
+	termAtt := ts.Attributes().Add("CharTermAttribute").(CharTermAttribute)
+	colorAtt := ts.Attributes().Add("colorAttribute").(colorAttribute)
+	if reset {
+		if err := ts.Reset(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var ans []string
+	for {
+		ok, err := ts.IncrementToken()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			break
+		}
+		ans = append(ans, fmt.Sprintf("%v/%v",
+			string(termAtt.Buffer()[:termAtt.Length()]), colorAtt.Color()))
+	}
+	if err := ts.End(); err != nil {
+		t.Fatal(err)
+	}
+	return strings.Join(ans, " ")
+}
+
+func TestTeeSinkTokenFilter(t *testing.T) {
+	tee := NewTeeSinkTokenFilter(newColorFilter(std.NewStandardTokenizer(
+		util.VERSION_LATEST, strings.NewReader("The 2 quick foxes 3"))))
+	all := tee.NewSinkTokenStream()
+	numbers := tee.NewSinkTokenStreamWithFilter(NewTokenTypeSinkFilter("<NUM>"))
+
+	expected := "The/blue 2/red quick/blue foxes/blue 3/red"
+	if got := consume(t, tee, true); got != expected {
+		t.Errorf("expected %v, but was %v", expected, got)
+	}
+	if got := consume(t, all, false); got != expected {
+		t.Errorf("expected %v, but was %v", expected, got)
+	}
+	expected = "2/red 3/red"
+	if got := consume(t, numbers, false); got != expected {
+		t.Errorf("expected %v, but was %v", expected, got)
+	}
+	// sinks can be replayed
+	if got := consume(t, numbers, true); got != expected {
+		t.Errorf("expected %v, but was %v", expected, got)
+	}
+	offsetAtt := all.Attributes().Get("OffsetAttribute").(OffsetAttribute)
+	if offsetAtt.EndOffset() != 19 {
+		t.Errorf("expected final offset 19, but was %v", offsetAtt.EndOffset())
+	}
+}
+
+func TestAddSinkTokenStream(t *testing.T) {
+	tee1 := NewTeeSinkTokenFilter(std.NewStandardTokenizer(
+		util.VERSION_LATEST, strings.NewReader("one two")))
+	sink := tee1.NewSinkTokenStream()
+	tee2 := NewTeeSinkTokenFilter(newColorFilter(std.NewStandardTokenizer(
+		util.VERSION_LATEST, strings.NewReader("three"))))
+	if err := tee2.AddSinkTokenStream(sink); err != nil {
+		t.Fatal(err)
+	}
+	for _, tee := range []*TeeSinkTokenFilter{tee1, tee2} {
+		if err := tee.Reset(); err != nil {
+			t.Fatal(err)
+		}
+		if err := tee.ConsumeAllTokens(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expected := "one/ two/ three/blue"
+	if got := consume(t, sink, false); got != expected {
+		t.Errorf("expected %v, but was %v", expected, got)
+	}
+}
+
+func TestAttributeSourceReflection(t *testing.T) {
+	ts := newColorFilter(std.NewStandardTokenizer(util.VERSION_LATEST, strings.NewReader("x")))
+	if err := ts.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ts.IncrementToken(); err != nil {
+		t.Fatal(err)
+	}
+	clone := ts.Attributes().CloneAttributes()
+	ts.Attributes().Clear()
+	if s := clone.ReflectAsString(true); !strings.Contains(s, "colorAttribute#color=blue") ||
+		!strings.Contains(s, "CharTermAttribute#term=x") {
+		t.Errorf("unexpected reflection: %v", s)
+	}
+	clone.CopyTo(ts.Attributes())
+	if color := ts.Attributes().Get("colorAttribute").(colorAttribute).Color(); color != "blue" {
+		t.Errorf("expected blue, but was %v", color)
+	}
+}
@@ -0,0 +1,24 @@
+package sinks
+
+import (
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"github.com/balzaczyy/golucene/core/util"
+)
+
+// sinks/TokenTypeSinkFilter.java
+
+/* Adds a token to the sink if it has a specific type. */
+type TokenTypeSinkFilter struct {
+	typeToMatch string
+}
+
+func NewTokenTypeSinkFilter(typeToMatch string) *TokenTypeSinkFilter {
+	return &TokenTypeSinkFilter{typeToMatch}
+}
+
+func (f *TokenTypeSinkFilter) Accept(source *util.AttributeSource) bool {
+	// check to see if this is a Category
+	return source.Add("TypeAttribute").(TypeAttribute).Type() == f.typeToMatch
+}
+
+func (f *TokenTypeSinkFilter) Reset() error { return nil }
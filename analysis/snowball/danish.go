@@ -0,0 +1,65 @@
+package snowball
+
+// tartarus/snowball/ext/DanishStemmer.java
+
+/*
+The Snowball "danish" stemmer.
+
+See http://snowball.tartarus.org/algorithms/danish/stemmer.html
+*/
+type DanishStemmer struct{}
+
+var isDanishVowel = vowels("aeiouyæåø")
+
+var danishMainSuffixes = []string{
+	"hed", "ethed", "ered", "e", "erede", "ende", "erende", "ene", "erne",
+	"ere", "en", "heden", "eren", "er", "heder", "erer", "heds", "es",
+	"endes", "erendes", "enes", "ernes", "eres", "ens", "hedens", "erens",
+	"ers", "ets", "erets", "et", "eret", "s",
+}
+
+var danishConsonantPairs = []string{"gd", "dt", "gt", "kt"}
+
+func (s DanishStemmer) Stem(word string) string {
+	w := &program{[]rune(word)}
+	r1 := scandinavianR1(w, isDanishVowel)
+
+	// step 1
+	switch suffix := w.find(r1, danishMainSuffixes); suffix {
+	case "":
+	case "s":
+		if w.precededBy(suffix, "abcdfghjklmnoprtvyzå") {
+			w.delete(suffix)
+		}
+	default:
+		w.delete(suffix)
+	}
+
+	// step 2
+	danishConsonantPair(w, r1)
+
+	// step 3
+	if w.ends("igst") {
+		w.delete("st")
+	}
+	switch suffix := w.find(r1, []string{"ig", "lig", "elig", "els", "løst"}); suffix {
+	case "ig", "lig", "elig", "els":
+		w.delete(suffix)
+		danishConsonantPair(w, r1)
+	case "løst":
+		w.delete("t")
+	}
+
+	// step 4: undouble a final double consonant in R1
+	if n := len(w.b); n >= 2 && n-1 >= r1 && w.b[n-1] == w.b[n-2] && !isDanishVowel(w.b[n-1]) {
+		w.b = w.b[:n-1]
+	}
+	return w.String()
+}
+
+/* If the word ends gd, dt, gt or kt in R1, deletes the last letter. */
+func danishConsonantPair(w *program, r1 int) {
+	if w.find(r1, danishConsonantPairs) != "" {
+		w.b = w.b[:len(w.b)-1]
+	}
+}
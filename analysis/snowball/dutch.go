@@ -0,0 +1,184 @@
+package snowball
+
+// tartarus/snowball/ext/DutchStemmer.java
+
+/*
+The Snowball "dutch" stemmer.
+
+See http://snowball.tartarus.org/algorithms/dutch/stemmer.html
+*/
+type DutchStemmer struct{}
+
+var isDutchVowel = vowels("aeiouyè")
+
+type dutch struct {
+	program
+	r1, r2 int
+	// whether step 2 removed an e
+	eFound bool
+}
+
+func (s DutchStemmer) Stem(word string) string {
+	w := &dutch{program: program{[]rune(word)}}
+	w.prelude()
+	w.r1 = w.region(0, isDutchVowel)
+	// the region before R1 contains at least 3 letters
+	if w.r1 < 3 {
+		w.r1 = 3
+	}
+	w.r2 = w.region(w.region(0, isDutchVowel), isDutchVowel)
+
+	w.step1()
+	w.step2()
+	w.step3a()
+	w.step3b()
+	w.step4()
+
+	w.translate("IY", "iy")
+	return w.String()
+}
+
+/*
+Removes umlauts and acute accents, then marks an initial y, a y after
+a vowel, and i between vowels as consonants by putting them in upper
+case.
+*/
+func (w *dutch) prelude() {
+	w.translate("äëïöüáéíóú", "aeiouaeiou")
+	b := w.b
+	for i, ch := range b {
+		prevVowel := i > 0 && isDutchVowel(b[i-1])
+		switch {
+		case ch == 'y' && (i == 0 || prevVowel):
+			b[i] = 'Y'
+		case ch == 'i' && prevVowel && i+1 < len(b) && isDutchVowel(b[i+1]):
+			b[i] = 'I'
+		}
+	}
+}
+
+func (w *dutch) inR1(suffix string) bool {
+	return w.start(suffix) >= w.r1
+}
+
+func (w *dutch) inR2(suffix string) bool {
+	return w.start(suffix) >= w.r2
+}
+
+/* If the word ends kk, dd or tt, removes the last letter. */
+func (w *dutch) undouble() {
+	if w.find(0, []string{"kk", "dd", "tt"}) != "" {
+		w.b = w.b[:len(w.b)-1]
+	}
+}
+
+/*
+Deletes en in R1 preceded by a valid en-ending (a non-vowel, not
+preceded by gem) and undoubles.
+*/
+func (w *dutch) enEnding(suffix string) bool {
+	start := w.start(suffix)
+	if start < w.r1 || start == 0 || isDutchVowel(w.b[start-1]) ||
+		(&program{w.b[:start]}).ends("gem") {
+		return false
+	}
+	w.delete(suffix)
+	w.undouble()
+	return true
+}
+
+/*
+Step 1: replaces heden by heid, deletes en or ene preceded by a valid
+en-ending, and s or se preceded by a valid s-ending (a non-vowel
+other than j), in R1.
+*/
+func (w *dutch) step1() {
+	switch suffix := w.find(0, []string{"heden", "en", "ene", "s", "se"}); suffix {
+	case "heden":
+		if w.inR1(suffix) {
+			w.replace(suffix, "heid")
+		}
+	case "en", "ene":
+		w.enEnding(suffix)
+	case "s", "se":
+		if preceding := w.at(w.start(suffix) - 1); w.inR1(suffix) &&
+			preceding != 0 && !isDutchVowel(preceding) && preceding != 'j' {
+			w.delete(suffix)
+		}
+	}
+}
+
+/* Step 2: deletes e in R1 preceded by a non-vowel, and undoubles. */
+func (w *dutch) step2() bool {
+	w.eFound = false
+	if !w.ends("e") || !w.inR1("e") {
+		return false
+	}
+	if preceding := w.at(w.start("e") - 1); preceding == 0 || isDutchVowel(preceding) {
+		return false
+	}
+	w.delete("e")
+	w.eFound = true
+	w.undouble()
+	return true
+}
+
+/*
+Step 3a: deletes heid in R2 not preceded by c, and a preceding en as
+in step 1.
+*/
+func (w *dutch) step3a() {
+	if w.ends("heid") && w.inR2("heid") && !w.precededBy("heid", "c") {
+		w.delete("heid")
+		if w.ends("en") {
+			w.enEnding("en")
+		}
+	}
+}
+
+/* Step 3b: d-suffixes in R2. */
+func (w *dutch) step3b() {
+	suffix := w.find(0, []string{"end", "ing", "ig", "lijk", "baar", "bar"})
+	if suffix == "" || !w.inR2(suffix) {
+		return
+	}
+	switch suffix {
+	case "end", "ing":
+		w.delete(suffix)
+		if w.ends("ig") && w.inR2("ig") && !w.precededBy("ig", "e") {
+			w.delete("ig")
+		} else {
+			w.undouble()
+		}
+	case "ig":
+		if !w.precededBy(suffix, "e") {
+			w.delete(suffix)
+		}
+	case "lijk":
+		w.delete(suffix)
+		w.step2()
+	case "baar":
+		w.delete(suffix)
+	case "bar":
+		if w.eFound {
+			w.delete(suffix)
+		}
+	}
+}
+
+/*
+Step 4: if the word ends CVD, where C is a non-vowel, D is a
+non-vowel other than I, and V is double a, e, o or u, removes one of
+the vowels from V.
+*/
+func (w *dutch) step4() {
+	n := len(w.b)
+	if n < 4 {
+		return
+	}
+	c, v1, v2, d := w.b[n-4], w.b[n-3], w.b[n-2], w.b[n-1]
+	if !isDutchVowel(c) && v1 == v2 && (v1 == 'a' || v1 == 'e' || v1 == 'o' || v1 == 'u') &&
+		!isDutchVowel(d) && d != 'I' {
+		w.b = append(w.b[:n-2], d)
+	}
+}
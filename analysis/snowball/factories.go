@@ -0,0 +1,52 @@
+package snowball
+
+import (
+	"fmt"
+	. "github.com/balzaczyy/golucene/analysis/miscellaneous"
+	. "github.com/balzaczyy/golucene/analysis/util"
+	. "github.com/balzaczyy/golucene/core/analysis"
+)
+
+func init() {
+	RegisterTokenFilterFactory("snowballPorter", func(args map[string]string) (TokenFilterFactory, error) {
+		f, err := NewSnowballPorterFilterFactory(args)
+		if err != nil {
+			return nil, err
+		}
+		return f, nil
+	})
+}
+
+// snowball/SnowballPorterFilterFactory.java
+
+/*
+Factory for SnowballFilter, with configurable "language" ("English"
+by default), one of the STEMMERS. The terms in the "protected" word
+files are not stemmed.
+*/
+type SnowballPorterFilterFactory struct {
+	*AbstractAnalysisFactory
+	stemmer   Stemmer
+	protWords *CharArraySet
+}
+
+func NewSnowballPorterFilterFactory(args map[string]string) (*SnowballPorterFilterFactory, error) {
+	ans := &SnowballPorterFilterFactory{AbstractAnalysisFactory: NewAbstractAnalysisFactory(args)}
+	language := ans.Get("language", "English")
+	ans.protWords = ans.GetWordSet("protected", "wordset", false)
+	if err := ans.Validate(); err != nil {
+		return nil, err
+	}
+	var ok bool
+	if ans.stemmer, ok = STEMMERS[language]; !ok {
+		return nil, fmt.Errorf("Invalid stemmer class specified: %v", language)
+	}
+	return ans, nil
+}
+
+func (f *SnowballPorterFilterFactory) Create(input TokenStream) TokenStream {
+	if f.protWords != nil {
+		input = NewSetKeywordMarkerFilterWithSet(input, f.protWords)
+	}
+	return NewSnowballFilter(input, f.stemmer)
+}
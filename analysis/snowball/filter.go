@@ -0,0 +1,81 @@
+package snowball
+
+import (
+	"fmt"
+	"github.com/balzaczyy/golucene/analysis/en"
+	. "github.com/balzaczyy/golucene/core/analysis"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+)
+
+/* The available stemmers, by Snowball language name. */
+var STEMMERS = map[string]Stemmer{
+	"Danish":     DanishStemmer{},
+	"Dutch":      DutchStemmer{},
+	"English":    en.Porter2Stemmer{},
+	"French":     FrenchStemmer{},
+	"German":     GermanStemmer{},
+	"Italian":    ItalianStemmer{},
+	"Norwegian":  NorwegianStemmer{},
+	"Portuguese": PortugueseStemmer{},
+	"Russian":    RussianStemmer{},
+	"Spanish":    SpanishStemmer{},
+	"Swedish":    SwedishStemmer{},
+}
+
+// snowball/SnowballFilter.java
+
+/*
+A filter that stems words using a Snowball-generated stemmer.
+
+Note: the input to the stemming filter must already be in lower case,
+so you will need to use LowerCaseFilter or LowerCaseTokenizer farther
+down the Tokenizer chain in order for this to work properly!
+
+Tokens marked as keywords via the KeywordAttribute are left as is.
+*/
+type SnowballFilter struct {
+	*TokenFilter
+	input       TokenStream
+	stemmer     Stemmer
+	termAtt     CharTermAttribute
+	keywordAttr KeywordAttribute
+}
+
+func NewSnowballFilter(in TokenStream, stemmer Stemmer) *SnowballFilter {
+	ans := &SnowballFilter{
+		TokenFilter: NewTokenFilter(in),
+		input:       in,
+		stemmer:     stemmer,
+	}
+	ans.termAtt = ans.Attributes().Add("CharTermAttribute").(CharTermAttribute)
+	ans.keywordAttr = ans.Attributes().Add("KeywordAttribute").(KeywordAttribute)
+	return ans
+}
+
+/*
+Construct the named stemming filter. Available stemmers are listed in
+STEMMERS. The name of a stemmer is the part of the class name before
+"Stemmer", e.g., the stemmer in EnglishStemmer is named "English".
+*/
+func NewSnowballFilterForLanguage(in TokenStream, name string) *SnowballFilter {
+	stemmer, ok := STEMMERS[name]
+	if !ok {
+		panic(fmt.Sprintf("Invalid stemmer class specified: %v", name))
+	}
+	return NewSnowballFilter(in, stemmer)
+}
+
+/* Returns the next input Token, after being stemmed */
+func (f *SnowballFilter) IncrementToken() (bool, error) {
+	ok, err := f.input.IncrementToken()
+	if err != nil || !ok {
+		return false, err
+	}
+	if !f.keywordAttr.IsKeyword() {
+		term := string(f.termAtt.Buffer()[:f.termAtt.Length()])
+		if stem := f.stemmer.Stem(term); stem != term {
+			f.termAtt.CopyBuffer([]rune(stem))
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,350 @@
+package snowball
+
+// tartarus/snowball/ext/FrenchStemmer.java
+
+/*
+The Snowball "french" stemmer.
+
+See http://snowball.tartarus.org/algorithms/french/stemmer.html
+*/
+type FrenchStemmer struct{}
+
+var isFrenchVowel = vowels("aeiouyâàëéêèïîôûù")
+
+var frenchStandardSuffixes = []string{
+	"ance", "iqUe", "isme", "able", "iste", "eux", "ances", "iqUes",
+	"ismes", "ables", "istes", "atrice", "ateur", "ation", "atrices",
+	"ateurs", "ations", "logie", "logies", "usion", "ution", "usions",
+	"utions", "ence", "ences", "ement", "ements", "ité", "ités", "if",
+	"ive", "ifs", "ives", "eaux", "aux", "euse", "euses", "issement",
+	"issements", "amment", "emment", "ment", "ments",
+}
+
+var frenchIVerbSuffixes = []string{
+	"îmes", "ît", "îtes", "i", "ie", "ies", "ir", "ira", "irai",
+	"iraIent", "irais", "irait", "iras", "irent", "irez", "iriez",
+	"irions", "irons", "iront", "is", "issaIent", "issais", "issait",
+	"issant", "issante", "issantes", "issants", "isse", "issent",
+	"isses", "issez", "issiez", "issions", "issons", "it",
+}
+
+var frenchVerbSuffixes = []string{
+	"ions", "é", "ée", "ées", "és", "èrent", "er", "era", "erai",
+	"eraIent", "erais", "erait", "eras", "erez", "eriez", "erions",
+	"erons", "eront", "ez", "iez", "âmes", "ât", "âtes", "a", "ai",
+	"aIent", "ais", "ait", "ant", "ante", "antes", "ants", "as", "asse",
+	"assent", "asses", "assiez", "assions",
+}
+
+type french struct {
+	program
+	rv, r1, r2 int
+}
+
+func (s FrenchStemmer) Stem(word string) string {
+	w := &french{program: program{[]rune(word)}}
+	w.prelude()
+	w.markRegions()
+
+	if w.standardSuffix() || w.iVerbSuffix() || w.verbSuffix() {
+		switch {
+		case w.ends("Y"):
+			w.replace("Y", "i")
+		case w.ends("ç"):
+			w.replace("ç", "c")
+		}
+	} else {
+		w.residualSuffix()
+	}
+	w.unDouble()
+	w.unAccent()
+
+	w.translate("IUY", "iuy")
+	return w.String()
+}
+
+/*
+Marks u or i between vowels, y preceded or followed by a vowel, and u
+after q as consonants, by putting them in upper case.
+*/
+func (w *french) prelude() {
+	b := w.b
+	for i, ch := range b {
+		prevVowel := i > 0 && isFrenchVowel(b[i-1])
+		nextVowel := i+1 < len(b) && isFrenchVowel(b[i+1])
+		switch {
+		case prevVowel && nextVowel && ch == 'u':
+			b[i] = 'U'
+		case prevVowel && nextVowel && ch == 'i':
+			b[i] = 'I'
+		case ch == 'y' && (prevVowel || nextVowel):
+			b[i] = 'Y'
+		case ch == 'u' && i > 0 && b[i-1] == 'q':
+			b[i] = 'U'
+		}
+	}
+}
+
+/*
+RV is the region after the third letter if the word begins with two
+vowels, or one of par, col and tap; otherwise the region after the
+first vowel not at the beginning of the word.
+*/
+func (w *french) markRegions() {
+	n := len(w.b)
+	w.rv = n
+	switch {
+	case n >= 3 && (isFrenchVowel(w.b[0]) && isFrenchVowel(w.b[1]) ||
+		string(w.b[:3]) == "par" || string(w.b[:3]) == "col" || string(w.b[:3]) == "tap"):
+		w.rv = 3
+	default:
+		for i := 1; i < n; i++ {
+			if isFrenchVowel(w.b[i]) {
+				w.rv = i + 1
+				break
+			}
+		}
+	}
+	w.r1 = w.region(0, isFrenchVowel)
+	w.r2 = w.region(w.r1, isFrenchVowel)
+}
+
+/* Deletes the suffix if it is in R2, or replaces it otherwise. */
+func (w *french) deleteInR2Or(suffix, replacement string) {
+	if w.start(suffix) >= w.r2 {
+		w.delete(suffix)
+	} else {
+		w.replace(suffix, replacement)
+	}
+}
+
+/*
+Step 1: standard suffix removal. Returns true if a suffix was removed
+and the verb suffixes should not be tried.
+*/
+func (w *french) standardSuffix() bool {
+	suffix := w.find(0, frenchStandardSuffixes)
+	start := w.start(suffix)
+	switch suffix {
+	case "":
+		return false
+
+	case "ance", "iqUe", "isme", "able", "iste", "eux", "ances",
+		"iqUes", "ismes", "ables", "istes":
+		if start < w.r2 {
+			return false
+		}
+		w.delete(suffix)
+
+	case "atrice", "ateur", "ation", "atrices", "ateurs", "ations":
+		if start < w.r2 {
+			return false
+		}
+		w.delete(suffix)
+		if w.ends("ic") {
+			w.deleteInR2Or("ic", "iqU")
+		}
+
+	case "logie", "logies":
+		if start < w.r2 {
+			return false
+		}
+		w.replace(suffix, "log")
+
+	case "usion", "ution", "usions", "utions":
+		if start < w.r2 {
+			return false
+		}
+		w.replace(suffix, "u")
+
+	case "ence", "ences":
+		if start < w.r2 {
+			return false
+		}
+		w.replace(suffix, "ent")
+
+	case "ement", "ements":
+		if start < w.rv {
+			return false
+		}
+		w.delete(suffix)
+		switch preceding := w.find(0, []string{"iv", "eus", "abl", "iqU", "ièr", "Ièr"}); preceding {
+		case "iv":
+			if w.start("iv") >= w.r2 {
+				w.delete("iv")
+				if w.ends("at") && w.start("at") >= w.r2 {
+					w.delete("at")
+				}
+			}
+		case "eus":
+			if w.start("eus") >= w.r2 {
+				w.delete("eus")
+			} else if w.start("eus") >= w.r1 {
+				w.replace("eus", "eux")
+			}
+		case "abl", "iqU":
+			if w.start(preceding) >= w.r2 {
+				w.delete(preceding)
+			}
+		case "ièr", "Ièr":
+			if w.start(preceding) >= w.rv {
+				w.replace(preceding, "i")
+			}
+		}
+
+	case "ité", "ités":
+		if start < w.r2 {
+			return false
+		}
+		w.delete(suffix)
+		switch preceding := w.find(0, []string{"abil", "ic", "iv"}); preceding {
+		case "abil":
+			w.deleteInR2Or("abil", "abl")
+		case "ic":
+			w.deleteInR2Or("ic", "iqU")
+		case "iv":
+			if w.start("iv") >= w.r2 {
+				w.delete("iv")
+			}
+		}
+
+	case "if", "ive", "ifs", "ives":
+		if start < w.r2 {
+			return false
+		}
+		w.delete(suffix)
+		if w.ends("at") && w.start("at") >= w.r2 {
+			w.delete("at")
+			if w.ends("ic") {
+				w.deleteInR2Or("ic", "iqU")
+			}
+		}
+
+	case "eaux":
+		w.replace(suffix, "eau")
+
+	case "aux":
+		if start < w.r1 {
+			return false
+		}
+		w.replace(suffix, "al")
+
+	case "euse", "euses":
+		if start >= w.r2 {
+			w.delete(suffix)
+		} else if start >= w.r1 {
+			w.replace(suffix, "eux")
+		} else {
+			return false
+		}
+
+	case "issement", "issements":
+		if start < w.r1 || isFrenchVowel(w.at(start-1)) {
+			return false
+		}
+		w.delete(suffix)
+
+	// the following endings are removed, but the verb suffixes are
+	// still looked for
+	case "amment":
+		if start >= w.rv {
+			w.replace(suffix, "ant")
+		}
+		return false
+
+	case "emment":
+		if start >= w.rv {
+			w.replace(suffix, "ent")
+		}
+		return false
+
+	case "ment", "ments":
+		if start-1 >= w.rv && isFrenchVowel(w.at(start-1)) {
+			w.delete(suffix)
+		}
+		return false
+	}
+	return true
+}
+
+/*
+Step 2a: verb suffixes beginning i, deleted if preceded by a
+non-vowel in RV.
+*/
+func (w *french) iVerbSuffix() bool {
+	suffix := w.find(w.rv, frenchIVerbSuffixes)
+	if suffix == "" {
+		return false
+	}
+	if preceding := w.start(suffix) - 1; preceding < w.rv || isFrenchVowel(w.at(preceding)) {
+		return false
+	}
+	w.delete(suffix)
+	return true
+}
+
+/* Step 2b: other verb suffixes in RV. */
+func (w *french) verbSuffix() bool {
+	suffix := w.find(w.rv, frenchVerbSuffixes)
+	switch suffix {
+	case "":
+		return false
+	case "ions":
+		if w.start(suffix) < w.r2 {
+			return false
+		}
+		w.delete(suffix)
+	case "âmes", "ât", "âtes", "a", "ai", "aIent", "ais", "ait", "ant",
+		"ante", "antes", "ants", "as", "asse", "assent", "asses",
+		"assiez", "assions":
+		w.delete(suffix)
+		if w.ends("e") && w.start("e") >= w.rv {
+			w.delete("e")
+		}
+	default:
+		w.delete(suffix)
+	}
+	return true
+}
+
+/* Step 4: residual suffixes, if steps 1 and 2 did nothing. */
+func (w *french) residualSuffix() {
+	if w.ends("s") && !w.precededBy("s", "aiouès") {
+		w.delete("s")
+	}
+	switch suffix := w.find(w.rv, []string{"ion", "ier", "ière", "Ier", "Ière", "e", "ë"}); suffix {
+	case "ion":
+		if w.start(suffix) >= w.r2 && w.start(suffix) > w.rv && w.precededBy(suffix, "st") {
+			w.delete(suffix)
+		}
+	case "ier", "ière", "Ier", "Ière":
+		w.replace(suffix, "i")
+	case "e":
+		w.delete(suffix)
+	case "ë":
+		if w.start(suffix)-2 >= w.rv && w.ends("guë") {
+			w.delete(suffix)
+		}
+	}
+}
+
+/* Step 5: if the word ends enn, onn, ett, ell or eill, deletes the last letter. */
+func (w *french) unDouble() {
+	if w.find(0, []string{"enn", "onn", "ett", "ell", "eill"}) != "" {
+		w.b = w.b[:len(w.b)-1]
+	}
+}
+
+/*
+Step 6: if the word ends with one or more non-vowels preceded by é or
+è, replaces it with e.
+*/
+func (w *french) unAccent() {
+	i := len(w.b) - 1
+	for i >= 0 && !isFrenchVowel(w.b[i]) {
+		i--
+	}
+	if i >= 0 && i < len(w.b)-1 && (w.b[i] == 'é' || w.b[i] == 'è') {
+		w.b[i] = 'e'
+	}
+}
@@ -0,0 +1,125 @@
+package snowball
+
+import (
+	"strings"
+)
+
+// tartarus/snowball/ext/GermanStemmer.java
+
+/*
+The Snowball "german" stemmer.
+
+See http://snowball.tartarus.org/algorithms/german/stemmer.html
+*/
+type GermanStemmer struct{}
+
+var isGermanVowel = vowels("aeiouyäöü")
+
+type german struct {
+	program
+	r1, r2 int
+}
+
+func (s GermanStemmer) Stem(word string) string {
+	w := &german{program: program{[]rune(strings.Replace(word, "ß", "ss", -1))}}
+	w.prelude()
+	w.r1 = w.region(0, isGermanVowel)
+	// the region before R1 contains at least 3 letters
+	if w.r1 < 3 {
+		w.r1 = 3
+	}
+	w.r2 = w.region(w.region(0, isGermanVowel), isGermanVowel)
+
+	w.step1()
+	w.step2()
+	w.step3()
+
+	w.translate("UYäöü", "uyaou")
+	return w.String()
+}
+
+/* Marks u and y between vowels as consonants by putting them in upper case. */
+func (w *german) prelude() {
+	b := w.b
+	for i, ch := range b {
+		if (ch == 'u' || ch == 'y') && i > 0 && i+1 < len(b) &&
+			isGermanVowel(b[i-1]) && isGermanVowel(b[i+1]) {
+			b[i] = ch - 'a' + 'A'
+		}
+	}
+}
+
+func (w *german) inR1(suffix string) bool {
+	return w.start(suffix) >= w.r1
+}
+
+func (w *german) inR2(suffix string) bool {
+	return w.start(suffix) >= w.r2
+}
+
+/*
+Step 1: deletes em, ern, er, e, en or es in R1 (and the s of a
+preceding niss), or s preceded by a valid s-ending in R1.
+*/
+func (w *german) step1() {
+	suffix := w.find(0, []string{"em", "ern", "er", "e", "en", "es", "s"})
+	if suffix == "" || !w.inR1(suffix) {
+		return
+	}
+	switch suffix {
+	case "e", "en", "es":
+		w.delete(suffix)
+		if w.ends("niss") {
+			w.delete("s")
+		}
+	case "s":
+		if w.precededBy(suffix, "bdfghklmnrt") {
+			w.delete(suffix)
+		}
+	default:
+		w.delete(suffix)
+	}
+}
+
+/*
+Step 2: deletes en, er or est in R1, or st in R1 preceded by a valid
+st-ending, itself preceded by at least 3 letters.
+*/
+func (w *german) step2() {
+	suffix := w.find(0, []string{"en", "er", "est", "st"})
+	if suffix == "" || !w.inR1(suffix) {
+		return
+	}
+	if suffix != "st" || w.precededBy(suffix, "bdfghklmnt") && w.start(suffix) >= 4 {
+		w.delete(suffix)
+	}
+}
+
+/* Step 3: d-suffixes in R2. */
+func (w *german) step3() {
+	suffix := w.find(0, []string{"end", "ung", "ig", "ik", "isch", "lich", "heit", "keit"})
+	if suffix == "" || !w.inR2(suffix) {
+		return
+	}
+	switch suffix {
+	case "end", "ung":
+		w.delete(suffix)
+		if w.ends("ig") && w.inR2("ig") && !w.precededBy("ig", "e") {
+			w.delete("ig")
+		}
+	case "ig", "ik", "isch":
+		if !w.precededBy(suffix, "e") {
+			w.delete(suffix)
+		}
+	case "lich", "heit":
+		w.delete(suffix)
+		if preceding := w.find(0, []string{"er", "en"}); preceding != "" && w.inR1(preceding) {
+			w.delete(preceding)
+		}
+	case "keit":
+		w.delete(suffix)
+		if preceding := w.find(0, []string{"lich", "ig"}); preceding != "" && w.inR2(preceding) {
+			w.delete(preceding)
+		}
+	}
+}
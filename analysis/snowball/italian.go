@@ -0,0 +1,208 @@
+package snowball
+
+// tartarus/snowball/ext/ItalianStemmer.java
+
+/*
+The Snowball "italian" stemmer.
+
+See http://snowball.tartarus.org/algorithms/italian/stemmer.html
+*/
+type ItalianStemmer struct{}
+
+var isItalianVowel = vowels("aeiouàèìòù")
+
+var italianPronouns = []string{
+	"ci", "gli", "la", "le", "li", "lo", "mi", "ne", "si", "ti", "vi",
+	"sene", "gliela", "gliele", "glieli", "glielo", "gliene", "mela",
+	"mele", "meli", "melo", "mene", "tela", "tele", "teli", "telo",
+	"tene", "cela", "cele", "celi", "celo", "cene", "vela", "vele",
+	"veli", "velo", "vene",
+}
+
+var italianStandardSuffixes = []string{
+	"anza", "anze", "ico", "ici", "ica", "ice", "iche", "ichi", "ismo",
+	"ismi", "abile", "abili", "ibile", "ibili", "ista", "iste", "isti",
+	"istà", "istè", "istì", "oso", "osi", "osa", "ose", "mente",
+	"atrice", "atrici", "ante", "anti", "azione", "azioni", "atore",
+	"atori", "logia", "logie", "uzione", "uzioni", "usione", "usioni",
+	"enza", "enze", "amento", "amenti", "imento", "imenti", "amente",
+	"ità", "ivo", "ivi", "iva", "ive",
+}
+
+var italianVerbSuffixes = []string{
+	"ammo", "ando", "ano", "are", "arono", "asse", "assero", "assi",
+	"assimo", "ata", "ate", "ati", "ato", "ava", "avamo", "avano",
+	"avate", "avi", "avo", "emmo", "enda", "ende", "endi", "endo", "erà",
+	"erai", "eranno", "ere", "erebbe", "erebbero", "erei", "eremmo",
+	"eremo", "ereste", "eresti", "erete", "erò", "erono", "essero", "ete",
+	"eva", "evamo", "evano", "evate", "evi", "evo", "iamo", "immo", "irà",
+	"irai", "iranno", "ire", "irebbe", "irebbero", "irei", "iremmo",
+	"iremo", "ireste", "iresti", "irete", "irò", "irono", "isca",
+	"iscano", "isce", "isci", "isco", "iscono", "issero", "ita", "ite",
+	"iti", "ito", "iva", "ivamo", "ivano", "ivate", "ivi", "ivo", "ar",
+	"ir",
+}
+
+type italian struct {
+	program
+	rv, r1, r2 int
+}
+
+func (s ItalianStemmer) Stem(word string) string {
+	w := &italian{program: program{[]rune(word)}}
+	w.prelude()
+	w.rv = w.romanceRV(isItalianVowel)
+	w.r1 = w.region(0, isItalianVowel)
+	w.r2 = w.region(w.r1, isItalianVowel)
+
+	w.attachedPronoun()
+	if !w.standardSuffix() {
+		w.verbSuffix()
+	}
+	w.vowelSuffix()
+
+	w.translate("IU", "iu")
+	return w.String()
+}
+
+/*
+Replaces acute accents by grave accents, and marks u after q, and u
+or i between vowels, as consonants by putting them in upper case.
+*/
+func (w *italian) prelude() {
+	w.translate("áéíóú", "àèìòù")
+	b := w.b
+	for i, ch := range b {
+		switch {
+		case ch == 'u' && i > 0 && b[i-1] == 'q':
+			b[i] = 'U'
+		case (ch == 'u' || ch == 'i') && i > 0 && i+1 < len(b) &&
+			isItalianVowel(b[i-1]) && isItalianVowel(b[i+1]):
+			b[i] = ch - 'a' + 'A'
+		}
+	}
+}
+
+/*
+Step 0: removes an attached pronoun following ando or endo, or
+replaces it by e following ar, er or ir, in RV.
+*/
+func (w *italian) attachedPronoun() {
+	pronoun := w.find(0, italianPronouns)
+	if pronoun == "" {
+		return
+	}
+	rest := &program{w.b[:w.start(pronoun)]}
+	switch rest.find(w.rv, []string{"ando", "endo", "ar", "er", "ir"}) {
+	case "ando", "endo":
+		w.delete(pronoun)
+	case "ar", "er", "ir":
+		w.replace(pronoun, "e")
+	}
+}
+
+/* Returns true if the given suffix is in R2, and deletes it if so. */
+func (w *italian) deleteInR2(suffix string) bool {
+	if w.ends(suffix) && w.start(suffix) >= w.r2 {
+		w.delete(suffix)
+		return true
+	}
+	return false
+}
+
+/* Step 1: standard suffix removal. Returns true if a suffix was removed. */
+func (w *italian) standardSuffix() bool {
+	suffix := w.find(0, italianStandardSuffixes)
+	start := w.start(suffix)
+	switch suffix {
+	case "":
+		return false
+
+	case "azione", "azioni", "atore", "atori":
+		if !w.deleteInR2(suffix) {
+			return false
+		}
+		w.deleteInR2("ic")
+
+	case "logia", "logie":
+		if start < w.r2 {
+			return false
+		}
+		w.replace(suffix, "log")
+
+	case "uzione", "uzioni", "usione", "usioni":
+		if start < w.r2 {
+			return false
+		}
+		w.replace(suffix, "u")
+
+	case "enza", "enze":
+		if start < w.r2 {
+			return false
+		}
+		w.replace(suffix, "ente")
+
+	case "amento", "amenti", "imento", "imenti":
+		if start < w.rv {
+			return false
+		}
+		w.delete(suffix)
+
+	case "amente":
+		if start < w.r1 {
+			return false
+		}
+		w.delete(suffix)
+		switch preceding := w.find(0, []string{"iv", "os", "ic", "abil"}); preceding {
+		case "iv":
+			if w.deleteInR2("iv") {
+				w.deleteInR2("at")
+			}
+		case "os", "ic", "abil":
+			w.deleteInR2(preceding)
+		}
+
+	case "ità":
+		if !w.deleteInR2(suffix) {
+			return false
+		}
+		if preceding := w.find(0, []string{"abil", "ic", "iv"}); preceding != "" {
+			w.deleteInR2(preceding)
+		}
+
+	case "ivo", "ivi", "iva", "ive":
+		if !w.deleteInR2(suffix) {
+			return false
+		}
+		if w.deleteInR2("at") {
+			w.deleteInR2("ic")
+		}
+
+	default:
+		return w.deleteInR2(suffix)
+	}
+	return true
+}
+
+/* Step 2: verb suffixes in RV. */
+func (w *italian) verbSuffix() {
+	if suffix := w.find(w.rv, italianVerbSuffixes); suffix != "" {
+		w.delete(suffix)
+	}
+}
+
+/*
+Step 3a: deletes a final a, e, i, o, à, è, ì or ò in RV, and a
+preceding i in RV. Step 3b: replaces a final ch or gh by c or g in RV.
+*/
+func (w *italian) vowelSuffix() {
+	if suffix := w.find(w.rv, []string{"a", "e", "i", "o", "à", "è", "ì", "ò"}); suffix != "" {
+		w.delete(suffix)
+		if w.find(w.rv, []string{"i"}) != "" {
+			w.delete("i")
+		}
+	}
+	if suffix := w.find(w.rv, []string{"ch", "gh"}); suffix != "" {
+		w.delete("h")
+	}
+}
@@ -0,0 +1,52 @@
+package snowball
+
+// tartarus/snowball/ext/NorwegianStemmer.java
+
+/*
+The Snowball "norwegian" stemmer.
+
+See http://snowball.tartarus.org/algorithms/norwegian/stemmer.html
+*/
+type NorwegianStemmer struct{}
+
+var isNorwegianVowel = vowels("aeiouyæåø")
+
+var norwegianMainSuffixes = []string{
+	"a", "e", "ede", "ande", "ende", "ane", "ene", "hetene", "en", "heten",
+	"ar", "er", "heter", "as", "es", "edes", "endes", "enes", "hetenes",
+	"ens", "hetens", "ers", "ets", "et", "het", "ast", "s", "erte", "ert",
+}
+
+func (s NorwegianStemmer) Stem(word string) string {
+	w := &program{[]rune(word)}
+	r1 := scandinavianR1(w, isNorwegianVowel)
+
+	// step 1
+	switch suffix := w.find(r1, norwegianMainSuffixes); suffix {
+	case "":
+	case "s":
+		// a valid s-ending is one of bcdfghjlmnoprtvyz, or k not
+		// preceded by a vowel
+		preceding := w.start(suffix) - 1
+		if w.precededBy(suffix, "bcdfghjlmnoprtvyz") ||
+			w.at(preceding) == 'k' && preceding > 0 && !isNorwegianVowel(w.at(preceding-1)) {
+			w.delete(suffix)
+		}
+	case "erte", "ert":
+		w.replace(suffix, "er")
+	default:
+		w.delete(suffix)
+	}
+
+	// step 2
+	if w.find(r1, []string{"dt", "vt"}) != "" {
+		w.b = w.b[:len(w.b)-1]
+	}
+
+	// step 3
+	if suffix := w.find(r1, []string{"leg", "eleg", "ig", "eig", "lig", "elig",
+		"els", "lov", "elov", "slov", "hetslov"}); suffix != "" {
+		w.delete(suffix)
+	}
+	return w.String()
+}
@@ -0,0 +1,186 @@
+package snowball
+
+import (
+	"strings"
+)
+
+// tartarus/snowball/ext/PortugueseStemmer.java
+
+/*
+The Snowball "portuguese" stemmer.
+
+See http://snowball.tartarus.org/algorithms/portuguese/stemmer.html
+*/
+type PortugueseStemmer struct{}
+
+var isPortugueseVowel = vowels("aeiouáéíóúâêô")
+
+var portugueseStandardSuffixes = []string{
+	"eza", "ezas", "ico", "ica", "icos", "icas", "ismo", "ismos", "ável",
+	"ível", "ista", "istas", "oso", "osa", "osos", "osas", "amento",
+	"amentos", "imento", "imentos", "adora", "ador", "aça~o", "adoras",
+	"adores", "aço~es", "ante", "antes", "ância", "logia", "logias",
+	"uça~o", "uço~es", "ência", "ências", "amente", "mente", "idade",
+	"idades", "iva", "ivo", "ivas", "ivos", "ira", "iras",
+}
+
+var portugueseVerbSuffixes = []string{
+	"ada", "ida", "ia", "aria", "eria", "iria", "ará", "ara", "erá", "era",
+	"irá", "ava", "asse", "esse", "isse", "aste", "este", "iste", "ei",
+	"arei", "erei", "irei", "am", "iam", "ariam", "eriam", "iriam", "aram",
+	"eram", "iram", "avam", "em", "arem", "erem", "irem", "assem", "essem",
+	"issem", "ado", "ido", "ando", "endo", "indo", "ara~o", "era~o",
+	"ira~o", "ar", "er", "ir", "as", "adas", "idas", "ias", "arias",
+	"erias", "irias", "arás", "aras", "erás", "eras", "irás", "avas", "es",
+	"ardes", "erdes", "irdes", "ares", "eres", "ires", "asses", "esses",
+	"isses", "astes", "estes", "istes", "is", "ais", "eis", "íeis",
+	"aríeis", "eríeis", "iríeis", "áreis", "areis", "éreis", "ereis",
+	"íreis", "ireis", "ásseis", "ésseis", "ísseis", "áveis", "ados",
+	"idos", "ámos", "amos", "íamos", "aríamos", "eríamos", "iríamos",
+	"áramos", "éramos", "íramos", "ávamos", "emos", "aremos", "eremos",
+	"iremos", "ássemos", "êssemos", "íssemos", "imos", "armos", "ermos",
+	"irmos", "eu", "iu", "ou", "ira", "iras",
+}
+
+type portuguese struct {
+	program
+	rv, r1, r2 int
+}
+
+func (s PortugueseStemmer) Stem(word string) string {
+	// nasalised vowels are written as a vowel followed by ~
+	word = strings.NewReplacer("ã", "a~", "õ", "o~").Replace(word)
+	w := &portuguese{program: program{[]rune(word)}}
+	w.rv = w.romanceRV(isPortugueseVowel)
+	w.r1 = w.region(0, isPortugueseVowel)
+	w.r2 = w.region(w.r1, isPortugueseVowel)
+
+	if w.standardSuffix() || w.verbSuffix() {
+		// step 3
+		if w.ends("ci") && w.start("i") >= w.rv {
+			w.delete("i")
+		}
+	} else {
+		w.residualSuffix()
+	}
+	w.residualForm()
+
+	return strings.NewReplacer("a~", "ã", "o~", "õ").Replace(w.String())
+}
+
+/* Returns true if the given suffix is in R2, and deletes it if so. */
+func (w *portuguese) deleteInR2(suffix string) bool {
+	if w.ends(suffix) && w.start(suffix) >= w.r2 {
+		w.delete(suffix)
+		return true
+	}
+	return false
+}
+
+/* Step 1: standard suffix removal. Returns true if a suffix was removed. */
+func (w *portuguese) standardSuffix() bool {
+	suffix := w.find(0, portugueseStandardSuffixes)
+	start := w.start(suffix)
+	switch suffix {
+	case "":
+		return false
+
+	case "logia", "logias":
+		if start < w.r2 {
+			return false
+		}
+		w.replace(suffix, "log")
+
+	case "uça~o", "uço~es":
+		if start < w.r2 {
+			return false
+		}
+		w.replace(suffix, "u")
+
+	case "ência", "ências":
+		if start < w.r2 {
+			return false
+		}
+		w.replace(suffix, "ente")
+
+	case "amente":
+		if start < w.r1 {
+			return false
+		}
+		w.delete(suffix)
+		switch preceding := w.find(0, []string{"iv", "os", "ic", "ad"}); preceding {
+		case "iv":
+			if w.deleteInR2("iv") {
+				w.deleteInR2("at")
+			}
+		case "os", "ic", "ad":
+			w.deleteInR2(preceding)
+		}
+
+	case "mente":
+		if !w.deleteInR2(suffix) {
+			return false
+		}
+		if preceding := w.find(0, []string{"ante", "avel", "ível"}); preceding != "" {
+			w.deleteInR2(preceding)
+		}
+
+	case "idade", "idades":
+		if !w.deleteInR2(suffix) {
+			return false
+		}
+		if preceding := w.find(0, []string{"abil", "ic", "iv"}); preceding != "" {
+			w.deleteInR2(preceding)
+		}
+
+	case "iva", "ivo", "ivas", "ivos":
+		if !w.deleteInR2(suffix) {
+			return false
+		}
+		w.deleteInR2("at")
+
+	case "ira", "iras":
+		if start < w.rv || !w.precededBy(suffix, "e") {
+			return false
+		}
+		w.replace(suffix, "ir")
+
+	default:
+		return w.deleteInR2(suffix)
+	}
+	return true
+}
+
+/* Step 2: verb suffixes in RV. */
+func (w *portuguese) verbSuffix() bool {
+	suffix := w.find(w.rv, portugueseVerbSuffixes)
+	if suffix == "" {
+		return false
+	}
+	w.delete(suffix)
+	return true
+}
+
+/* Step 4: deletes a residual os, a, i, o, á, í or ó in RV. */
+func (w *portuguese) residualSuffix() {
+	if suffix := w.find(w.rv, []string{"os", "a", "i", "o", "á", "í", "ó"}); suffix != "" {
+		w.delete(suffix)
+	}
+}
+
+/*
+Step 5: deletes a final e, é or ê in RV, along with the u of a
+preceding gu or the i of a preceding ci in RV; or replaces a final ç
+by c.
+*/
+func (w *portuguese) residualForm() {
+	switch suffix := w.find(w.rv, []string{"e", "é", "ê"}); {
+	case suffix != "":
+		w.delete(suffix)
+		if (w.ends("gu") || w.ends("ci")) && len(w.b)-1 >= w.rv {
+			w.b = w.b[:len(w.b)-1]
+		}
+	case w.ends("ç"):
+		w.replace("ç", "c")
+	}
+}
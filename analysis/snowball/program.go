@@ -0,0 +1,147 @@
+package snowball
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// tartarus/snowball/SnowballProgram.java
+
+/*
+A stemmer implementing one of the Snowball stemming algorithms, see
+http://snowball.tartarus.org/
+
+The input is expected to be lower case.
+*/
+type Stemmer interface {
+	// Returns the stem of the given word.
+	Stem(word string) string
+}
+
+/*
+The word being stemmed, with the operations shared by the Snowball
+algorithms. Regions (R1, R2, RV) are kept as positions in the word:
+a suffix is in a region if it starts at or after its position.
+*/
+type program struct {
+	b []rune
+}
+
+func (p *program) String() string {
+	return string(p.b)
+}
+
+func (p *program) length() int {
+	return len(p.b)
+}
+
+/* Returns the character at position i, or 0 if out of range. */
+func (p *program) at(i int) rune {
+	if i < 0 || i >= len(p.b) {
+		return 0
+	}
+	return p.b[i]
+}
+
+/* Returns the position the given suffix would start at. */
+func (p *program) start(suffix string) int {
+	return len(p.b) - utf8.RuneCountInString(suffix)
+}
+
+func (p *program) ends(suffix string) bool {
+	start := p.start(suffix)
+	return start >= 0 && string(p.b[start:]) == suffix
+}
+
+/*
+Returns the longest of the given suffixes the word ends with, among
+those starting at or after limit, or "" if there is none.
+*/
+func (p *program) find(limit int, suffixes []string) string {
+	longest, start := "", len(p.b)+1
+	for _, suffix := range suffixes {
+		if s := p.start(suffix); s < start && s >= limit && p.ends(suffix) {
+			longest, start = suffix, s
+		}
+	}
+	return longest
+}
+
+/*
+Returns true if the character just before the given suffix is one of
+chars.
+*/
+func (p *program) precededBy(suffix, chars string) bool {
+	ch := p.at(p.start(suffix) - 1)
+	return ch != 0 && strings.ContainsRune(chars, ch)
+}
+
+func (p *program) replace(suffix, replacement string) {
+	p.b = append(p.b[:p.start(suffix)], []rune(replacement)...)
+}
+
+func (p *program) delete(suffix string) {
+	p.b = p.b[:p.start(suffix)]
+}
+
+/* Replaces each of the characters in from by its counterpart in to. */
+func (p *program) translate(from, to string) {
+	f, t := []rune(from), []rune(to)
+	for i, ch := range p.b {
+		for j, c := range f {
+			if ch == c {
+				p.b[i] = t[j]
+			}
+		}
+	}
+}
+
+/*
+Returns the position after the first non-vowel following a vowel,
+searching from start, or the end of the word. This is how R1 (from
+the start of the word) and R2 (from R1) are defined.
+*/
+func (p *program) region(start int, isVowel func(rune) bool) int {
+	for i := start + 1; i < len(p.b); i++ {
+		if isVowel(p.b[i-1]) && !isVowel(p.b[i]) {
+			return i + 1
+		}
+	}
+	return len(p.b)
+}
+
+/*
+RV as defined for Spanish, Portuguese and Italian: if the second
+letter is a consonant, the region after the next following vowel; if
+the first two letters are vowels, the region after the next
+consonant; otherwise the region after the third letter.
+*/
+func (p *program) romanceRV(isVowel func(rune) bool) int {
+	n := len(p.b)
+	if n < 3 {
+		return n
+	}
+	switch {
+	case !isVowel(p.b[1]):
+		for i := 2; i < n; i++ {
+			if isVowel(p.b[i]) {
+				return i + 1
+			}
+		}
+		return n
+	case isVowel(p.b[0]):
+		for i := 2; i < n; i++ {
+			if !isVowel(p.b[i]) {
+				return i + 1
+			}
+		}
+		return n
+	}
+	return 3
+}
+
+func vowels(chars string) func(rune) bool {
+	return func(ch rune) bool {
+		return strings.ContainsRune(chars, ch)
+	}
+}
@@ -0,0 +1,129 @@
+package snowball
+
+// tartarus/snowball/ext/RussianStemmer.java
+
+/*
+The Snowball "russian" stemmer.
+
+See http://snowball.tartarus.org/algorithms/russian/stemmer.html
+*/
+type RussianStemmer struct{}
+
+var isRussianVowel = vowels("аеиоуыэюя")
+
+// endings of the first groups must follow а or я
+var (
+	russianPerfectiveGerund1 = []string{"в", "вши", "вшись"}
+	russianPerfectiveGerund2 = []string{"ив", "ивши", "ившись", "ыв", "ывши", "ывшись"}
+	russianAdjective         = []string{
+		"ее", "ие", "ые", "ое", "ими", "ыми", "ей", "ий", "ый", "ой", "ем",
+		"им", "ым", "ом", "его", "ого", "ему", "ому", "их", "ых", "ую", "юю",
+		"ая", "яя", "ою", "ею",
+	}
+	russianParticiple1 = []string{"ем", "нн", "вш", "ющ", "щ"}
+	russianParticiple2 = []string{"ивш", "ывш", "ующ"}
+	russianReflexive   = []string{"ся", "сь"}
+	russianVerb1       = []string{
+		"ла", "на", "ете", "йте", "ли", "й", "л", "ем", "н", "ло", "но", "ет",
+		"ют", "ны", "ть", "ешь", "нно",
+	}
+	russianVerb2 = []string{
+		"ила", "ыла", "ена", "ейте", "уйте", "ите", "или", "ыли", "ей", "уй",
+		"ил", "ыл", "им", "ым", "ен", "ило", "ыло", "ено", "ят", "ует", "уют",
+		"ит", "ыт", "ены", "ить", "ыть", "ишь", "ую", "ю",
+	}
+	russianNoun = []string{
+		"а", "ев", "ов", "ие", "ье", "е", "иями", "ями", "ами", "еи", "ии",
+		"и", "ией", "ей", "ой", "ий", "й", "иям", "ям", "ием", "ем", "ам",
+		"ом", "о", "у", "ах", "иях", "ях", "ы", "ь", "ию", "ью", "ю", "ия",
+		"ья", "я",
+	}
+)
+
+type russian struct {
+	program
+	rv, r2 int
+}
+
+func (s RussianStemmer) Stem(word string) string {
+	w := &russian{program: program{[]rune(word)}}
+	w.translate("ё", "е")
+	w.rv = len(w.b)
+	for i, ch := range w.b {
+		if isRussianVowel(ch) {
+			w.rv = i + 1
+			break
+		}
+	}
+	w.r2 = w.region(w.region(0, isRussianVowel), isRussianVowel)
+
+	// step 1
+	if !w.deleteGrouped(russianPerfectiveGerund1, russianPerfectiveGerund2) {
+		if suffix := w.find(w.rv, russianReflexive); suffix != "" {
+			w.delete(suffix)
+		}
+		if !w.adjectival() && !w.deleteGrouped(russianVerb1, russianVerb2) {
+			if suffix := w.find(w.rv, russianNoun); suffix != "" {
+				w.delete(suffix)
+			}
+		}
+	}
+
+	// step 2
+	if w.find(w.rv, []string{"и"}) != "" {
+		w.delete("и")
+	}
+
+	// step 3: derivational endings in R2
+	if suffix := w.find(w.rv, []string{"ост", "ость"}); suffix != "" && w.start(suffix) >= w.r2 {
+		w.delete(suffix)
+	}
+
+	// step 4: undouble н, remove a superlative ending, or remove ь
+	switch suffix := w.find(w.rv, []string{"ейш", "ейше", "н", "ь"}); suffix {
+	case "ейш", "ейше":
+		w.delete(suffix)
+		if w.ends("нн") {
+			w.delete("н")
+		}
+	case "н":
+		if w.ends("нн") {
+			w.delete("н")
+		}
+	case "ь":
+		w.delete(suffix)
+	}
+	return w.String()
+}
+
+/*
+Deletes the longest ending of either group in RV; those of the first
+group only if they follow а or я in RV.
+*/
+func (w *russian) deleteGrouped(group1, group2 []string) bool {
+	suffix := w.find(w.rv, append(append([]string(nil), group1...), group2...))
+	if suffix == "" {
+		return false
+	}
+	for _, s := range group1 {
+		if s == suffix {
+			if w.start(suffix)-1 < w.rv || !w.precededBy(suffix, "ая") {
+				return false
+			}
+			break
+		}
+	}
+	w.delete(suffix)
+	return true
+}
+
+/* Deletes an adjective ending, along with a preceding participle ending. */
+func (w *russian) adjectival() bool {
+	suffix := w.find(w.rv, russianAdjective)
+	if suffix == "" {
+		return false
+	}
+	w.delete(suffix)
+	w.deleteGrouped(russianParticiple1, russianParticiple2)
+	return true
+}
@@ -0,0 +1,220 @@
+package snowball
+
+// tartarus/snowball/ext/SpanishStemmer.java
+
+/*
+The Snowball "spanish" stemmer.
+
+See http://snowball.tartarus.org/algorithms/spanish/stemmer.html
+*/
+type SpanishStemmer struct{}
+
+var isSpanishVowel = vowels("aeiouáéíóúü")
+
+var spanishPronouns = []string{
+	"me", "se", "sela", "selo", "selas", "selos", "la", "le", "lo",
+	"las", "les", "los", "nos",
+}
+
+var spanishStandardSuffixes = []string{
+	"anza", "anzas", "ico", "ica", "icos", "icas", "ismo", "ismos",
+	"able", "ables", "ible", "ibles", "ista", "istas", "oso", "osa",
+	"osos", "osas", "amiento", "amientos", "imiento", "imientos",
+	"adora", "ador", "ación", "adoras", "adores", "aciones", "ante",
+	"antes", "ancia", "ancias", "logía", "logías", "ución", "uciones",
+	"encia", "encias", "amente", "mente", "idad", "idades", "iva",
+	"ivo", "ivas", "ivos",
+}
+
+var spanishYVerbSuffixes = []string{
+	"ya", "ye", "yan", "yen", "yeron", "yendo", "yo", "yó", "yas", "yes",
+	"yais", "yamos",
+}
+
+var spanishVerbSuffixes = []string{
+	"en", "es", "éis", "emos",
+	"arían", "arías", "arán", "arás", "aríais", "aría", "aréis",
+	"aríamos", "aremos", "ará", "aré", "erían", "erías", "erán", "erás",
+	"eríais", "ería", "eréis", "eríamos", "eremos", "erá", "eré", "irían",
+	"irías", "irán", "irás", "iríais", "iría", "iréis", "iríamos",
+	"iremos", "irá", "iré", "aba", "ada", "ida", "ía", "ara", "iera", "ad",
+	"ed", "id", "ase", "iese", "aste", "iste", "an", "aban", "ían", "aran",
+	"ieran", "asen", "iesen", "aron", "ieron", "ado", "ido", "ando",
+	"iendo", "ió", "ar", "er", "ir", "as", "abas", "adas", "idas", "ías",
+	"aras", "ieras", "ases", "ieses", "ís", "áis", "abais", "íais",
+	"arais", "ierais", "aseis", "ieseis", "asteis", "isteis", "ados",
+	"idos", "amos", "ábamos", "íamos", "imos", "áramos", "iéramos",
+	"iésemos", "ásemos",
+}
+
+type spanish struct {
+	program
+	rv, r1, r2 int
+}
+
+func (s SpanishStemmer) Stem(word string) string {
+	w := &spanish{program: program{[]rune(word)}}
+	w.rv = w.romanceRV(isSpanishVowel)
+	w.r1 = w.region(0, isSpanishVowel)
+	w.r2 = w.region(w.r1, isSpanishVowel)
+
+	w.attachedPronoun()
+	if !w.standardSuffix() && !w.yVerbSuffix() {
+		w.verbSuffix()
+	}
+	w.residualSuffix()
+
+	w.translate("áéíóú", "aeiou")
+	return w.String()
+}
+
+/*
+Step 0: removes an attached pronoun following iéndo, ándo, ár, ér,
+ír (removing the accent too), ando, iendo, ar, er, ir, or yendo
+following u, in RV.
+*/
+func (w *spanish) attachedPronoun() {
+	pronoun := w.find(0, spanishPronouns)
+	if pronoun == "" {
+		return
+	}
+	rest := &program{w.b[:w.start(pronoun)]}
+	switch ending := rest.find(w.rv, []string{"iéndo", "ándo", "ár", "ér", "ír",
+		"ando", "iendo", "ar", "er", "ir", "yendo"}); ending {
+	case "iéndo", "ándo", "ár", "ér", "ír":
+		rest.replace(ending, map[string]string{
+			"iéndo": "iendo", "ándo": "ando", "ár": "ar", "ér": "er", "ír": "ir",
+		}[ending])
+		w.b = rest.b
+	case "ando", "iendo", "ar", "er", "ir":
+		w.b = rest.b
+	case "yendo":
+		if rest.ends("uyendo") {
+			w.b = rest.b
+		}
+	}
+}
+
+/* Returns true if the given suffix is in R2, and deletes it if so. */
+func (w *spanish) deleteInR2(suffix string) bool {
+	if w.ends(suffix) && w.start(suffix) >= w.r2 {
+		w.delete(suffix)
+		return true
+	}
+	return false
+}
+
+/* Step 1: standard suffix removal. Returns true if a suffix was removed. */
+func (w *spanish) standardSuffix() bool {
+	suffix := w.find(0, spanishStandardSuffixes)
+	start := w.start(suffix)
+	switch suffix {
+	case "":
+		return false
+
+	case "anza", "anzas", "ico", "ica", "icos", "icas", "ismo", "ismos",
+		"able", "ables", "ible", "ibles", "ista", "istas", "oso", "osa",
+		"osos", "osas", "amiento", "amientos", "imiento", "imientos":
+		return w.deleteInR2(suffix)
+
+	case "adora", "ador", "ación", "adoras", "adores", "aciones", "ante",
+		"antes", "ancia", "ancias":
+		if !w.deleteInR2(suffix) {
+			return false
+		}
+		w.deleteInR2("ic")
+
+	case "logía", "logías":
+		if start < w.r2 {
+			return false
+		}
+		w.replace(suffix, "log")
+
+	case "ución", "uciones":
+		if start < w.r2 {
+			return false
+		}
+		w.replace(suffix, "u")
+
+	case "encia", "encias":
+		if start < w.r2 {
+			return false
+		}
+		w.replace(suffix, "ente")
+
+	case "amente":
+		if start < w.r1 {
+			return false
+		}
+		w.delete(suffix)
+		switch preceding := w.find(0, []string{"iv", "os", "ic", "ad"}); preceding {
+		case "iv":
+			if w.deleteInR2("iv") {
+				w.deleteInR2("at")
+			}
+		case "os", "ic", "ad":
+			w.deleteInR2(preceding)
+		}
+
+	case "mente":
+		if !w.deleteInR2(suffix) {
+			return false
+		}
+		if preceding := w.find(0, []string{"ante", "able", "ible"}); preceding != "" {
+			w.deleteInR2(preceding)
+		}
+
+	case "idad", "idades":
+		if !w.deleteInR2(suffix) {
+			return false
+		}
+		if preceding := w.find(0, []string{"abil", "ic", "iv"}); preceding != "" {
+			w.deleteInR2(preceding)
+		}
+
+	case "iva", "ivo", "ivas", "ivos":
+		if !w.deleteInR2(suffix) {
+			return false
+		}
+		w.deleteInR2("at")
+	}
+	return true
+}
+
+/* Step 2a: verb suffixes beginning y in RV, deleted if preceded by u. */
+func (w *spanish) yVerbSuffix() bool {
+	suffix := w.find(w.rv, spanishYVerbSuffixes)
+	if suffix == "" || !w.precededBy(suffix, "u") {
+		return false
+	}
+	w.delete(suffix)
+	return true
+}
+
+/* Step 2b: other verb suffixes in RV. */
+func (w *spanish) verbSuffix() {
+	switch suffix := w.find(w.rv, spanishVerbSuffixes); suffix {
+	case "":
+	case "en", "es", "éis", "emos":
+		w.delete(suffix)
+		if w.ends("gu") {
+			w.delete("u")
+		}
+	default:
+		w.delete(suffix)
+	}
+}
+
+/* Step 3: residual suffixes in RV. */
+func (w *spanish) residualSuffix() {
+	switch suffix := w.find(w.rv, []string{"os", "a", "o", "á", "í", "ó", "e", "é"}); suffix {
+	case "":
+	case "e", "é":
+		w.delete(suffix)
+		if w.ends("gu") && w.start("u") >= w.rv {
+			w.delete("u")
+		}
+	default:
+		w.delete(suffix)
+	}
+}
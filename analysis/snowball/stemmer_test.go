@@ -0,0 +1,48 @@
+package snowball
+
+import (
+	"testing"
+)
+
+func assertStems(t *testing.T, language string, stems map[string]string) {
+	stemmer := STEMMERS[language]
+	for word, stem := range stems {
+		if got := stemmer.Stem(word); got != stem {
+			t.Errorf("%v %v: expected %v, but was %v", language, word, stem, got)
+		}
+	}
+}
+
+func TestSnowballStemmers(t *testing.T) {
+	assertStems(t, "French", map[string]string{
+		"continuellement": "continuel", "continuité": "continu",
+		"continuation": "continu", "continuer": "continu",
+		"abandonnée": "abandon", "majestueusement": "majestu", "chiens": "chien",
+	})
+	assertStems(t, "Spanish", map[string]string{
+		"chicas": "chic", "chico": "chic", "rápidamente": "rapid",
+		"ajustado": "ajust", "aprovechamiento": "aprovech", "deberían": "deb",
+	})
+	assertStems(t, "Italian", map[string]string{
+		"abbandonata": "abbandon", "abbandonate": "abbandon",
+	})
+	assertStems(t, "Portuguese", map[string]string{
+		"complicações": "complic",
+	})
+	assertStems(t, "German", map[string]string{
+		"häuser": "haus", "katzen": "katz", "laufen": "lauf",
+		"aufeinanderfolgenden": "aufeinanderfolg", "kategorisch": "kategor",
+	})
+	assertStems(t, "Dutch", map[string]string{
+		"kinderen": "kinder", "lichamelijk": "licham",
+	})
+	assertStems(t, "Russian", map[string]string{
+		"книга": "книг", "красивый": "красив", "читающий": "чита",
+	})
+	assertStems(t, "Swedish", map[string]string{
+		"klokhet": "klok", "jaktkarlarne": "jaktkarl",
+	})
+	assertStems(t, "Danish", map[string]string{"bilerne": "bil"})
+	assertStems(t, "Norwegian", map[string]string{"bilene": "bil"})
+	assertStems(t, "English", map[string]string{"running": "run"})
+}
@@ -0,0 +1,65 @@
+package snowball
+
+// tartarus/snowball/ext/SwedishStemmer.java
+
+/*
+The Snowball "swedish" stemmer.
+
+See http://snowball.tartarus.org/algorithms/swedish/stemmer.html
+*/
+type SwedishStemmer struct{}
+
+var isSwedishVowel = vowels("aeiouyäåö")
+
+var swedishMainSuffixes = []string{
+	"a", "arna", "erna", "heterna", "orna", "ad", "e", "ade", "ande",
+	"arne", "are", "aste", "en", "anden", "aren", "heten", "ern", "ar",
+	"er", "heter", "or", "as", "arnas", "ernas", "ornas", "es", "ades",
+	"andes", "ens", "arens", "hetens", "erns", "at", "andet", "het", "ast",
+	"s",
+}
+
+func (s SwedishStemmer) Stem(word string) string {
+	w := &program{[]rune(word)}
+	r1 := scandinavianR1(w, isSwedishVowel)
+
+	// step 1
+	switch suffix := w.find(r1, swedishMainSuffixes); suffix {
+	case "":
+	case "s":
+		if w.precededBy(suffix, "bcdfghjklmnoprtvy") {
+			w.delete(suffix)
+		}
+	default:
+		w.delete(suffix)
+	}
+
+	// step 2
+	if w.find(r1, []string{"dd", "gd", "nn", "dt", "gt", "kt", "tt"}) != "" {
+		w.b = w.b[:len(w.b)-1]
+	}
+
+	// step 3
+	switch suffix := w.find(r1, []string{"lig", "ig", "els", "löst", "fullt"}); suffix {
+	case "lig", "ig", "els":
+		w.delete(suffix)
+	case "löst", "fullt":
+		w.b = w.b[:len(w.b)-1]
+	}
+	return w.String()
+}
+
+/*
+R1 for the Scandinavian stemmers, adjusted so that the region before
+it contains at least 3 letters. These stemmers have no R2.
+*/
+func scandinavianR1(w *program, isVowel func(rune) bool) int {
+	r1 := w.region(0, isVowel)
+	if r1 < 3 {
+		r1 = 3
+	}
+	if r1 > len(w.b) {
+		r1 = len(w.b)
+	}
+	return r1
+}
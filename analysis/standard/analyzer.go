@@ -26,16 +26,12 @@ compatibility when creating StandardAnalyzer:
 */
 type StandardAnalyzer struct {
 	*StopwordAnalyzerBase
-	stopWordSet    map[string]bool
 	maxTokenLength int
 }
 
 /* Builds an analyzer with the given stop words. */
 func NewStandardAnalyzerWithStopWords(stopWords map[string]bool) *StandardAnalyzer {
-	ans := &StandardAnalyzer{
-		stopWordSet:    stopWords,
-		maxTokenLength: DEFAULT_MAX_TOKEN_LENGTH,
-	}
+	ans := &StandardAnalyzer{maxTokenLength: DEFAULT_MAX_TOKEN_LENGTH}
 	ans.StopwordAnalyzerBase = NewStopwordAnalyzerBaseWithStopWords(stopWords)
 	ans.Spi = ans
 	return ans
@@ -46,17 +42,30 @@ func NewStandardAnalyzer() *StandardAnalyzer {
 	return NewStandardAnalyzerWithStopWords(STOP_WORDS_SET)
 }
 
+/*
+Set maximum allowed token length. If a token is seen that exceeds
+this length then it is discarded. This setting only takes effect the
+next time TokenStreamForReader or TokenStreamForString is called.
+*/
+func (a *StandardAnalyzer) SetMaxTokenLength(length int) {
+	a.maxTokenLength = length
+}
+
+func (a *StandardAnalyzer) MaxTokenLength() int {
+	return a.maxTokenLength
+}
+
 func (a *StandardAnalyzer) CreateComponents(fieldName string, reader io.RuneReader) *TokenStreamComponents {
 	version := a.Version()
-	src := newStandardTokenizer(version, reader)
-	src.maxTokenLength = a.maxTokenLength
-	var tok TokenStream = newStandardFilter(version, src)
+	src := NewStandardTokenizer(version, reader)
+	src.SetMaxTokenLength(a.maxTokenLength)
+	var tok TokenStream = NewStandardFilter(version, src)
 	tok = NewLowerCaseFilter(version, tok)
-	tok = NewStopFilter(version, tok, a.stopWordSet)
+	tok = NewStopFilter(version, tok, a.StopwordSet())
 	ans := NewTokenStreamComponents(src, tok)
 	super := ans.SetReader
 	ans.SetReader = func(reader io.RuneReader) error {
-		src.maxTokenLength = a.maxTokenLength
+		src.SetMaxTokenLength(a.maxTokenLength)
 		return super(reader)
 	}
 	return ans
@@ -0,0 +1,58 @@
+package standard
+
+import (
+	"fmt"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"testing"
+)
+
+func analyze(t *testing.T, a *StandardAnalyzer, text string) []string {
+	ts, err := a.TokenStreamForString("field", text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	termAtt := ts.Attributes().Get("CharTermAttribute").(CharTermAttribute)
+	if err = ts.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	var terms []string
+	for {
+		ok, err := ts.IncrementToken()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			break
+		}
+		terms = append(terms, string(termAtt.Buffer()[:termAtt.Length()]))
+	}
+	if err = ts.End(); err != nil {
+		t.Fatal(err)
+	}
+	if err = ts.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return terms
+}
+
+func TestStandardAnalyzer(t *testing.T) {
+	a := NewStandardAnalyzer()
+	if terms := analyze(t, a, "The Quick brown FOX"); fmt.Sprint(terms) != "[quick brown fox]" {
+		t.Errorf("expected [quick brown fox], but was %v", terms)
+	}
+
+	a = NewStandardAnalyzerWithStopWords(map[string]bool{"foo": true})
+	a.SetMaxTokenLength(3)
+	if a.MaxTokenLength() != 3 {
+		t.Errorf("expected max token length 3, but was %v", a.MaxTokenLength())
+	}
+	if terms := analyze(t, a, "The foo quick fox"); fmt.Sprint(terms) != "[the fox]" {
+		t.Errorf("expected [the fox], but was %v", terms)
+	}
+
+	// the new length applies to reused components too
+	a.SetMaxTokenLength(DEFAULT_MAX_TOKEN_LENGTH)
+	if terms := analyze(t, a, "The foo quick fox"); fmt.Sprint(terms) != "[the quick fox]" {
+		t.Errorf("expected [the quick fox], but was %v", terms)
+	}
+}
@@ -0,0 +1,40 @@
+package standard
+
+import (
+	. "github.com/balzaczyy/golucene/analysis/util"
+	. "github.com/balzaczyy/golucene/core/analysis"
+	"io"
+)
+
+func init() {
+	RegisterTokenizerFactory("standard", func(args map[string]string) (TokenizerFactory, error) {
+		f, err := NewStandardTokenizerFactory(args)
+		if err != nil {
+			return nil, err
+		}
+		return f, nil
+	})
+}
+
+// standard/StandardTokenizerFactory.java
+
+/* Factory for StandardTokenizer. */
+type StandardTokenizerFactory struct {
+	*AbstractAnalysisFactory
+	maxTokenLength int
+}
+
+func NewStandardTokenizerFactory(args map[string]string) (*StandardTokenizerFactory, error) {
+	ans := &StandardTokenizerFactory{AbstractAnalysisFactory: NewAbstractAnalysisFactory(args)}
+	ans.maxTokenLength = ans.GetInt("maxTokenLength", DEFAULT_MAX_TOKEN_LENGTH)
+	if err := ans.Validate(); err != nil {
+		return nil, err
+	}
+	return ans, nil
+}
+
+func (f *StandardTokenizerFactory) Create(input io.RuneReader) TokenizerService {
+	tokenizer := NewStandardTokenizer(f.LuceneMatchVersion(), input)
+	tokenizer.SetMaxTokenLength(f.maxTokenLength)
+	return tokenizer
+}
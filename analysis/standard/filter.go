@@ -14,7 +14,7 @@ type StandardFilter struct {
 	input        TokenStream
 }
 
-func newStandardFilter(matchVersion util.Version, in TokenStream) *StandardFilter {
+func NewStandardFilter(matchVersion util.Version, in TokenStream) *StandardFilter {
 	return &StandardFilter{
 		TokenFilter:  NewTokenFilter(in),
 		matchVersion: matchVersion,
@@ -18,6 +18,7 @@ const (
 	HIRAGANA        = 11
 	KATAKANA        = 12
 	HANGUL          = 13
+	EMOJI           = 14
 )
 
 /* String token types that correspond to token type int constants */
@@ -36,10 +37,11 @@ var TOKEN_TYPES = []string{
 	"<HIRAGANA>",
 	"<KATAKANA>",
 	"<HANGUL>",
+	"<EMOJI>",
 }
 
 /*
-A grammar-based tokenizer constructed with JFlex.
+A grammar-based tokenizer.
 
 As of Lucene version 3.1, this class implements the Word Break rules
 from the Unicode Text Segmentation algorithm, as specified in Unicode
@@ -64,7 +66,7 @@ compatibility when creating StandardTokenizer:
 type StandardTokenizer struct {
 	*Tokenizer
 
-	// A private instance of the JFlex-constructed scanner
+	// A private instance of the word break scanner
 	scanner StandardTokenizerInterface
 
 	skippedPositions int
@@ -81,9 +83,9 @@ type StandardTokenizer struct {
 
 /*
 Creates a new instance of the StandardTokenizer. Attaches the input
-to the newly created JFlex scanner.
+to the newly created word break scanner.
 */
-func newStandardTokenizer(matchVersion util.Version, input io.RuneReader) *StandardTokenizer {
+func NewStandardTokenizer(matchVersion util.Version, input io.RuneReader) *StandardTokenizer {
 	ans := &StandardTokenizer{
 		Tokenizer:      NewTokenizer(input),
 		maxTokenLength: DEFAULT_MAX_TOKEN_LENGTH,
@@ -101,6 +103,18 @@ func (t *StandardTokenizer) init(matchVersion util.Version) {
 	t.scanner = newStandardTokenizerImpl(nil)
 }
 
+/* Set the max allowed token length. Any token longer than this is skipped. */
+func (t *StandardTokenizer) SetMaxTokenLength(length int) {
+	if length < 1 {
+		panic("maxTokenLength must be greater than zero")
+	}
+	t.maxTokenLength = length
+}
+
+func (t *StandardTokenizer) MaxTokenLength() int {
+	return t.maxTokenLength
+}
+
 func (t *StandardTokenizer) IncrementToken() (bool, error) {
 	t.Attributes().Clear()
 	t.skippedPositions = 0
@@ -3,6 +3,7 @@ package standard
 import (
 	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
 	"io"
+	"unicode"
 )
 
 // standard/StandardTokenizerImpl.java
@@ -10,311 +11,6 @@ import (
 /* initial size of the lookahead buffer */
 const ZZ_BUFFERSIZE = 255
 
-/* lexical states */
-const YYINITIAL = 0
-
-/*
-ZZ_LEXSTATE[l] is the state in the DFA for the lexical state l
-ZZ_LEXSTATE[l+1] is the state in the DFA for the lexical state l at the beginning of a line
-l is of the form l = 2*k, k a non negative integer
-*/
-var ZZ_LEXSTATE = [2]int{0, 0}
-
-/* Translates characters to character classes */
-var ZZ_CMAP_PACKED = []int{
-	042, 000, 001, 015, 004, 000, 001, 014, 004, 000, 001, 007, 001, 000, 001, 010, 001, 000, 012, 004,
-	001, 006, 001, 007, 005, 000, 032, 001, 004, 000, 001, 011, 001, 000, 032, 001, 057, 000, 001, 001,
-	002, 000, 001, 003, 007, 000, 001, 001, 001, 000, 001, 006, 002, 000, 001, 001, 005, 000, 027, 001,
-	001, 000, 037, 001, 001, 000, int('\u01ca'), 001, 004, 000, 014, 001, 005, 000, 001, 006, 010, 000, 005, 001,
-	007, 000, 001, 001, 001, 000, 001, 001, 021, 000, 0160, 003, 005, 001, 001, 000, 002, 001, 002, 000,
-	004, 001, 001, 007, 007, 000, 001, 001, 001, 006, 003, 001, 001, 000, 001, 001, 001, 000, 024, 001,
-	001, 000, 0123, 001, 001, 000, 0213, 001, 001, 000, 007, 003, 0236, 001, 011, 000, 046, 001, 002, 000,
-	001, 001, 007, 000, 047, 001, 001, 000, 001, 007, 007, 000, 055, 003, 001, 000, 001, 003, 001, 000,
-	002, 003, 001, 000, 002, 003, 001, 000, 001, 003, 010, 000, 033, 016, 005, 000, 003, 016, 001, 001,
-	001, 006, 013, 000, 005, 003, 007, 000, 002, 007, 002, 000, 013, 003, 001, 000, 001, 003, 003, 000,
-	053, 001, 025, 003, 012, 004, 001, 000, 001, 004, 001, 007, 001, 000, 002, 001, 001, 003, 0143, 001,
-	001, 000, 001, 001, 010, 003, 001, 000, 006, 003, 002, 001, 002, 003, 001, 000, 004, 003, 002, 001,
-	012, 004, 003, 001, 002, 000, 001, 001, 017, 000, 001, 003, 001, 001, 001, 003, 036, 001, 033, 003,
-	002, 000, 0131, 001, 013, 003, 001, 001, 016, 000, 012, 004, 041, 001, 011, 003, 002, 001, 002, 000,
-	001, 007, 001, 000, 001, 001, 005, 000, 026, 001, 004, 003, 001, 001, 011, 003, 001, 001, 003, 003,
-	001, 001, 005, 003, 022, 000, 031, 001, 003, 003, 0104, 000, 001, 001, 001, 000, 013, 001, 067, 000,
-	033, 003, 001, 000, 004, 003, 066, 001, 003, 003, 001, 001, 022, 003, 001, 001, 007, 003, 012, 001,
-	002, 003, 002, 000, 012, 004, 001, 000, 007, 001, 001, 000, 007, 001, 001, 000, 003, 003, 001, 000,
-	010, 001, 002, 000, 002, 001, 002, 000, 026, 001, 001, 000, 007, 001, 001, 000, 001, 001, 003, 000,
-	004, 001, 002, 000, 001, 003, 001, 001, 007, 003, 002, 000, 002, 003, 002, 000, 003, 003, 001, 001,
-	010, 000, 001, 003, 004, 000, 002, 001, 001, 000, 003, 001, 002, 003, 002, 000, 012, 004, 002, 001,
-	017, 000, 003, 003, 001, 000, 006, 001, 004, 000, 002, 001, 002, 000, 026, 001, 001, 000, 007, 001,
-	001, 000, 002, 001, 001, 000, 002, 001, 001, 000, 002, 001, 002, 000, 001, 003, 001, 000, 005, 003,
-	004, 000, 002, 003, 002, 000, 003, 003, 003, 000, 001, 003, 007, 000, 004, 001, 001, 000, 001, 001,
-	007, 000, 012, 004, 002, 003, 003, 001, 001, 003, 013, 000, 003, 003, 001, 000, 011, 001, 001, 000,
-	003, 001, 001, 000, 026, 001, 001, 000, 007, 001, 001, 000, 002, 001, 001, 000, 005, 001, 002, 000,
-	001, 003, 001, 001, 010, 003, 001, 000, 003, 003, 001, 000, 003, 003, 002, 000, 001, 001, 017, 000,
-	002, 001, 002, 003, 002, 000, 012, 004, 021, 000, 003, 003, 001, 000, 010, 001, 002, 000, 002, 001,
-	002, 000, 026, 001, 001, 000, 007, 001, 001, 000, 002, 001, 001, 000, 005, 001, 002, 000, 001, 003,
-	001, 001, 007, 003, 002, 000, 002, 003, 002, 000, 003, 003, 010, 000, 002, 003, 004, 000, 002, 001,
-	001, 000, 003, 001, 002, 003, 002, 000, 012, 004, 001, 000, 001, 001, 020, 000, 001, 003, 001, 001,
-	001, 000, 006, 001, 003, 000, 003, 001, 001, 000, 004, 001, 003, 000, 002, 001, 001, 000, 001, 001,
-	001, 000, 002, 001, 003, 000, 002, 001, 003, 000, 003, 001, 003, 000, 014, 001, 004, 000, 005, 003,
-	003, 000, 003, 003, 001, 000, 004, 003, 002, 000, 001, 001, 006, 000, 001, 003, 016, 000, 012, 004,
-	021, 000, 003, 003, 001, 000, 010, 001, 001, 000, 003, 001, 001, 000, 027, 001, 001, 000, 012, 001,
-	001, 000, 005, 001, 003, 000, 001, 001, 007, 003, 001, 000, 003, 003, 001, 000, 004, 003, 007, 000,
-	002, 003, 001, 000, 002, 001, 006, 000, 002, 001, 002, 003, 002, 000, 012, 004, 022, 000, 002, 003,
-	001, 000, 010, 001, 001, 000, 003, 001, 001, 000, 027, 001, 001, 000, 012, 001, 001, 000, 005, 001,
-	002, 000, 001, 003, 001, 001, 007, 003, 001, 000, 003, 003, 001, 000, 004, 003, 007, 000, 002, 003,
-	007, 000, 001, 001, 001, 000, 002, 001, 002, 003, 002, 000, 012, 004, 001, 000, 002, 001, 017, 000,
-	002, 003, 001, 000, 010, 001, 001, 000, 003, 001, 001, 000, 051, 001, 002, 000, 001, 001, 007, 003,
-	001, 000, 003, 003, 001, 000, 004, 003, 001, 001, 010, 000, 001, 003, 010, 000, 002, 001, 002, 003,
-	002, 000, 012, 004, 012, 000, 006, 001, 002, 000, 002, 003, 001, 000, 022, 001, 003, 000, 030, 001,
-	001, 000, 011, 001, 001, 000, 001, 001, 002, 000, 007, 001, 003, 000, 001, 003, 004, 000, 006, 003,
-	001, 000, 001, 003, 001, 000, 010, 003, 022, 000, 002, 003, 015, 000, 060, 020, 001, 021, 002, 020,
-	007, 021, 005, 000, 007, 020, 010, 021, 001, 000, 012, 004, 047, 000, 002, 020, 001, 000, 001, 020,
-	002, 000, 002, 020, 001, 000, 001, 020, 002, 000, 001, 020, 006, 000, 004, 020, 001, 000, 007, 020,
-	001, 000, 003, 020, 001, 000, 001, 020, 001, 000, 001, 020, 002, 000, 002, 020, 001, 000, 004, 020,
-	001, 021, 002, 020, 006, 021, 001, 000, 002, 021, 001, 020, 002, 000, 005, 020, 001, 000, 001, 020,
-	001, 000, 006, 021, 002, 000, 012, 004, 002, 000, 004, 020, 040, 000, 001, 001, 027, 000, 002, 003,
-	006, 000, 012, 004, 013, 000, 001, 003, 001, 000, 001, 003, 001, 000, 001, 003, 004, 000, 002, 003,
-	010, 001, 001, 000, 044, 001, 004, 000, 024, 003, 001, 000, 002, 003, 005, 001, 013, 003, 001, 000,
-	044, 003, 011, 000, 001, 003, 071, 000, 053, 020, 024, 021, 001, 020, 012, 004, 006, 000, 006, 020,
-	004, 021, 004, 020, 003, 021, 001, 020, 003, 021, 002, 020, 007, 021, 003, 020, 004, 021, 015, 020,
-	014, 021, 001, 020, 001, 021, 012, 004, 004, 021, 002, 020, 046, 001, 001, 000, 001, 001, 005, 000,
-	001, 001, 002, 000, 053, 001, 001, 000, 004, 001, int('\u0100'), 002, 0111, 001, 001, 000, 004, 001, 002, 000,
-	007, 001, 001, 000, 001, 001, 001, 000, 004, 001, 002, 000, 051, 001, 001, 000, 004, 001, 002, 000,
-	041, 001, 001, 000, 004, 001, 002, 000, 007, 001, 001, 000, 001, 001, 001, 000, 004, 001, 002, 000,
-	017, 001, 001, 000, 071, 001, 001, 000, 004, 001, 002, 000, 0103, 001, 002, 000, 003, 003, 040, 000,
-	020, 001, 020, 000, 0125, 001, 014, 000, int('\u026c'), 001, 002, 000, 021, 001, 001, 000, 032, 001, 005, 000,
-	0113, 001, 003, 000, 003, 001, 017, 000, 015, 001, 001, 000, 004, 001, 003, 003, 013, 000, 022, 001,
-	003, 003, 013, 000, 022, 001, 002, 003, 014, 000, 015, 001, 001, 000, 003, 001, 001, 000, 002, 003,
-	014, 000, 064, 020, 040, 021, 003, 000, 001, 020, 004, 000, 001, 020, 001, 021, 002, 000, 012, 004,
-	041, 000, 004, 003, 001, 000, 012, 004, 006, 000, 0130, 001, 010, 000, 051, 001, 001, 003, 001, 001,
-	005, 000, 0106, 001, 012, 000, 035, 001, 003, 000, 014, 003, 004, 000, 014, 003, 012, 000, 012, 004,
-	036, 020, 002, 000, 005, 020, 013, 000, 054, 020, 004, 000, 021, 021, 007, 020, 002, 021, 006, 000,
-	012, 004, 001, 020, 003, 000, 002, 020, 040, 000, 027, 001, 005, 003, 004, 000, 065, 020, 012, 021,
-	001, 000, 035, 021, 002, 000, 001, 003, 012, 004, 006, 000, 012, 004, 006, 000, 016, 020, 0122, 000,
-	005, 003, 057, 001, 021, 003, 007, 001, 004, 000, 012, 004, 021, 000, 011, 003, 014, 000, 003, 003,
-	036, 001, 015, 003, 002, 001, 012, 004, 054, 001, 016, 003, 014, 000, 044, 001, 024, 003, 010, 000,
-	012, 004, 003, 000, 003, 001, 012, 004, 044, 001, 0122, 000, 003, 003, 001, 000, 025, 003, 004, 001,
-	001, 003, 004, 001, 003, 003, 002, 001, 011, 000, 0300, 001, 047, 003, 025, 000, 004, 003, int('\u0116'), 001,
-	002, 000, 006, 001, 002, 000, 046, 001, 002, 000, 006, 001, 002, 000, 010, 001, 001, 000, 001, 001,
-	001, 000, 001, 001, 001, 000, 001, 001, 001, 000, 037, 001, 002, 000, 065, 001, 001, 000, 007, 001,
-	001, 000, 001, 001, 003, 000, 003, 001, 001, 000, 007, 001, 003, 000, 004, 001, 002, 000, 006, 001,
-	004, 000, 015, 001, 005, 000, 003, 001, 001, 000, 007, 001, 017, 000, 004, 003, 010, 000, 002, 010,
-	012, 000, 001, 010, 002, 000, 001, 006, 002, 000, 005, 003, 020, 000, 002, 011, 003, 000, 001, 007,
-	017, 000, 001, 011, 013, 000, 005, 003, 001, 000, 012, 003, 001, 000, 001, 001, 015, 000, 001, 001,
-	020, 000, 015, 001, 063, 000, 041, 003, 021, 000, 001, 001, 004, 000, 001, 001, 002, 000, 012, 001,
-	001, 000, 001, 001, 003, 000, 005, 001, 006, 000, 001, 001, 001, 000, 001, 001, 001, 000, 001, 001,
-	001, 000, 004, 001, 001, 000, 013, 001, 002, 000, 004, 001, 005, 000, 005, 001, 004, 000, 001, 001,
-	021, 000, 051, 001, int('\u032d'), 000, 064, 001, int('\u0716'), 000, 057, 001, 001, 000, 057, 001, 001, 000, 0205, 001,
-	006, 000, 004, 001, 003, 003, 002, 001, 014, 000, 046, 001, 001, 000, 001, 001, 005, 000, 001, 001,
-	002, 000, 070, 001, 007, 000, 001, 001, 017, 000, 001, 003, 027, 001, 011, 000, 007, 001, 001, 000,
-	007, 001, 001, 000, 007, 001, 001, 000, 007, 001, 001, 000, 007, 001, 001, 000, 007, 001, 001, 000,
-	007, 001, 001, 000, 007, 001, 001, 000, 040, 003, 057, 000, 001, 001, 0120, 000, 032, 012, 001, 000,
-	0131, 012, 014, 000, 0326, 012, 057, 000, 001, 001, 001, 000, 001, 012, 031, 000, 011, 012, 006, 003,
-	001, 000, 005, 005, 002, 000, 003, 012, 001, 001, 001, 001, 004, 000, 0126, 013, 002, 000, 002, 003,
-	002, 005, 003, 013, 0133, 005, 001, 000, 004, 005, 005, 000, 051, 001, 003, 000, 0136, 002, 021, 000,
-	033, 001, 065, 000, 020, 005, 0320, 000, 057, 005, 001, 000, 0130, 005, 0250, 000, int('\u19b6'), 012, 0112, 000,
-	int('\u51cd'), 012, 063, 000, int('\u048d'), 001, 0103, 000, 056, 001, 002, 000, int('\u010d'), 001, 003, 000, 020, 001, 012, 004,
-	002, 001, 024, 000, 057, 001, 004, 003, 001, 000, 012, 003, 001, 000, 031, 001, 007, 000, 001, 003,
-	0120, 001, 002, 003, 045, 000, 011, 001, 002, 000, 0147, 001, 002, 000, 004, 001, 001, 000, 004, 001,
-	014, 000, 013, 001, 0115, 000, 012, 001, 001, 003, 003, 001, 001, 003, 004, 001, 001, 003, 027, 001,
-	005, 003, 030, 000, 064, 001, 014, 000, 002, 003, 062, 001, 021, 003, 013, 000, 012, 004, 006, 000,
-	022, 003, 006, 001, 003, 000, 001, 001, 004, 000, 012, 004, 034, 001, 010, 003, 002, 000, 027, 001,
-	015, 003, 014, 000, 035, 002, 003, 000, 004, 003, 057, 001, 016, 003, 016, 000, 001, 001, 012, 004,
-	046, 000, 051, 001, 016, 003, 011, 000, 003, 001, 001, 003, 010, 001, 002, 003, 002, 000, 012, 004,
-	006, 000, 033, 020, 001, 021, 004, 000, 060, 020, 001, 021, 001, 020, 003, 021, 002, 020, 002, 021,
-	005, 020, 002, 021, 001, 020, 001, 021, 001, 020, 030, 000, 005, 020, 013, 001, 005, 003, 002, 000,
-	003, 001, 002, 003, 012, 000, 006, 001, 002, 000, 006, 001, 002, 000, 006, 001, 011, 000, 007, 001,
-	001, 000, 007, 001, 0221, 000, 043, 001, 010, 003, 001, 000, 002, 003, 002, 000, 012, 004, 006, 000,
-	int('\u2ba4'), 002, 014, 000, 027, 002, 004, 000, 061, 002, int('\u2104'), 000, int('\u016e'), 012, 002, 000, 0152, 012, 046, 000,
-	007, 001, 014, 000, 005, 001, 005, 000, 001, 016, 001, 003, 012, 016, 001, 000, 015, 016, 001, 000,
-	005, 016, 001, 000, 001, 016, 001, 000, 002, 016, 001, 000, 002, 016, 001, 000, 012, 016, 0142, 001,
-	041, 000, int('\u016b'), 001, 022, 000, 0100, 001, 002, 000, 066, 001, 050, 000, 014, 001, 004, 000, 020, 003,
-	001, 007, 002, 000, 001, 006, 001, 007, 013, 000, 007, 003, 014, 000, 002, 011, 030, 000, 003, 011,
-	001, 007, 001, 000, 001, 010, 001, 000, 001, 007, 001, 006, 032, 000, 005, 001, 001, 000, 0207, 001,
-	002, 000, 001, 003, 007, 000, 001, 010, 004, 000, 001, 007, 001, 000, 001, 010, 001, 000, 012, 004,
-	001, 006, 001, 007, 005, 000, 032, 001, 004, 000, 001, 011, 001, 000, 032, 001, 013, 000, 070, 005,
-	002, 003, 037, 002, 003, 000, 006, 002, 002, 000, 006, 002, 002, 000, 006, 002, 002, 000, 003, 002,
-	034, 000, 003, 003, 004, 000, 014, 001, 001, 000, 032, 001, 001, 000, 023, 001, 001, 000, 002, 001,
-	001, 000, 017, 001, 002, 000, 016, 001, 042, 000, 0173, 001, 0105, 000, 065, 001, 0210, 000, 001, 003,
-	0202, 000, 035, 001, 003, 000, 061, 001, 057, 000, 037, 001, 021, 000, 033, 001, 065, 000, 036, 001,
-	002, 000, 044, 001, 004, 000, 010, 001, 001, 000, 005, 001, 052, 000, 0236, 001, 002, 000, 012, 004,
-	int('\u0356'), 000, 006, 001, 002, 000, 001, 001, 001, 000, 054, 001, 001, 000, 002, 001, 003, 000, 001, 001,
-	002, 000, 027, 001, 0252, 000, 026, 001, 012, 000, 032, 001, 0106, 000, 070, 001, 006, 000, 002, 001,
-	0100, 000, 001, 001, 003, 003, 001, 000, 002, 003, 005, 000, 004, 003, 004, 001, 001, 000, 003, 001,
-	001, 000, 033, 001, 004, 000, 003, 003, 004, 000, 001, 003, 040, 000, 035, 001, 0203, 000, 066, 001,
-	012, 000, 026, 001, 012, 000, 023, 001, 0215, 000, 0111, 001, int('\u03b7'), 000, 003, 003, 065, 001, 017, 003,
-	037, 000, 012, 004, 020, 000, 003, 003, 055, 001, 013, 003, 002, 000, 001, 003, 022, 000, 031, 001,
-	007, 000, 012, 004, 006, 000, 003, 003, 044, 001, 016, 003, 001, 000, 012, 004, 0100, 000, 003, 003,
-	060, 001, 016, 003, 004, 001, 013, 000, 012, 004, int('\u04a6'), 000, 053, 001, 015, 003, 010, 000, 012, 004,
-	int('\u0936'), 000, int('\u036f'), 001, 0221, 000, 0143, 001, int('\u0b9d'), 000, int('\u042f'), 001, int('\u33d1'), 000, int('\u0239'), 001, int('\u04c7'), 000, 0105, 001,
-	013, 000, 001, 001, 056, 003, 020, 000, 004, 003, 015, 001, int('\u4060'), 000, 001, 005, 001, 013, int('\u2163'), 000,
-	005, 003, 003, 000, 026, 003, 002, 000, 007, 003, 036, 000, 004, 003, 0224, 000, 003, 003, int('\u01bb'), 000,
-	0125, 001, 001, 000, 0107, 001, 001, 000, 002, 001, 002, 000, 001, 001, 002, 000, 002, 001, 002, 000,
-	004, 001, 001, 000, 014, 001, 001, 000, 001, 001, 001, 000, 007, 001, 001, 000, 0101, 001, 001, 000,
-	004, 001, 002, 000, 010, 001, 001, 000, 007, 001, 001, 000, 034, 001, 001, 000, 004, 001, 001, 000,
-	005, 001, 001, 000, 001, 001, 003, 000, 007, 001, 001, 000, int('\u0154'), 001, 002, 000, 031, 001, 001, 000,
-	031, 001, 001, 000, 037, 001, 001, 000, 031, 001, 001, 000, 037, 001, 001, 000, 031, 001, 001, 000,
-	037, 001, 001, 000, 031, 001, 001, 000, 037, 001, 001, 000, 031, 001, 001, 000, 010, 001, 002, 000,
-	062, 004, int('\u1600'), 000, 004, 001, 001, 000, 033, 001, 001, 000, 002, 001, 001, 000, 001, 001, 002, 000,
-	001, 001, 001, 000, 012, 001, 001, 000, 004, 001, 001, 000, 001, 001, 001, 000, 001, 001, 006, 000,
-	001, 001, 004, 000, 001, 001, 001, 000, 001, 001, 001, 000, 001, 001, 001, 000, 003, 001, 001, 000,
-	002, 001, 001, 000, 001, 001, 002, 000, 001, 001, 001, 000, 001, 001, 001, 000, 001, 001, 001, 000,
-	001, 001, 001, 000, 001, 001, 001, 000, 002, 001, 001, 000, 001, 001, 002, 000, 004, 001, 001, 000,
-	007, 001, 001, 000, 004, 001, 001, 000, 004, 001, 001, 000, 001, 001, 001, 000, 012, 001, 001, 000,
-	021, 001, 005, 000, 003, 001, 001, 000, 005, 001, 001, 000, 021, 001, int('\u032a'), 000, 032, 017, 001, 013,
-	int('\u0dff'), 000, int('\ua6d7'), 012, 051, 000, int('\u1035'), 012, 013, 000, 0336, 012, int('\u3fe2'), 000, int('\u021e'), 012, int('\uffff'), 000, int('\uffff'), 000, int('\uffff'), 000, int('\uffff'), 000, int('\uffff'), 000, int('\uffff'), 000, int('\uffff'), 000, int('\uffff'), 000, int('\uffff'), 000, int('\uffff'), 000, int('\uffff'), 000, int('\u05ee'), 000,
-	001, 003, 036, 000, 0140, 003, 0200, 000, 0360, 003, int('\uffff'), 000, int('\uffff'), 000, int('\ufe12'), 0,
-}
-
-/* Translates characters to character classes */
-var ZZ_CMAP = zzUnpackCMap(ZZ_CMAP_PACKED)
-
-/* Unpacks the comressed character translation table */
-func zzUnpackCMap(packed []int) []rune {
-	m := make([]rune, 0x110000)
-	j := 0 // index in unpacked array
-	var count int
-	assert(len(packed) == 2836)
-	for i, value := range packed {
-		if i%2 == 0 {
-			count = value
-		} else {
-			m[j] = rune(value)
-			j++
-			count--
-			for count > 0 {
-				m[j] = rune(value)
-				j++
-				count--
-			}
-		}
-	}
-	return m
-}
-
-/* Translates DFA states to action switch labels. */
-var ZZ_ACTION = zzUnpackAction([]int{
-	001, 000, 001, 001, 001, 002, 001, 003, 001, 004, 001, 005, 001, 001, 001, 006,
-	001, 007, 001, 002, 001, 001, 001, 010, 001, 002, 001, 000, 001, 002, 001, 000,
-	001, 004, 001, 000, 002, 002, 002, 000, 001, 001, 001, 0,
-})
-
-func zzUnpackAction(packed []int) []int {
-	m := make([]int, 24)
-	j := 0
-	for i, l := 0, len(packed); i < l; i += 2 {
-		count, value := packed[i], packed[i+1]
-		m[j] = value
-		j++
-		count--
-		for count > 0 {
-			m[j] = value
-			j++
-			count--
-		}
-	}
-	return m
-}
-
-/* Translates a state to a row index in the transition table */
-var ZZ_ROWMAP = zzUnpackRowMap([]int{
-	000, 000, 000, 022, 000, 044, 000, 066, 000, 0110, 000, 0132, 000, 0154, 000, 176,
-	000, 0220, 000, 0242, 000, 0264, 000, 0306, 000, 0330, 000, 0352, 000, 0374, 000, int('\u010e'),
-	000, int('\u0120'), 000, 0154, 000, int('\u0132'), 000, int('\u0144'), 000, int('\u0156'), 000, 0264, 000, int('\u0168'), 000, int('\u017a'),
-})
-
-func zzUnpackRowMap(packed []int) []int {
-	m := make([]int, 24)
-	j := 0
-	for i, l := 0, len(packed); i < l; i += 2 {
-		high, low := packed[i]<<16, packed[i+1]
-		m[j] = high | low
-		j++
-	}
-	return m
-}
-
-/* The transition table of the DFA */
-var ZZ_TRANS = zzUnpackTrans([]int{
-	001, 002, 001, 003, 001, 004, 001, 002, 001, 005, 001, 006, 003, 002, 001, 007,
-	001, 010, 001, 011, 002, 002, 001, 012, 001, 013, 002, 014, 023, 000, 003, 003,
-	001, 015, 001, 000, 001, 016, 001, 000, 001, 016, 001, 017, 002, 000, 001, 016,
-	001, 000, 001, 012, 002, 000, 001, 003, 001, 000, 001, 003, 002, 004, 001, 015,
-	001, 000, 001, 016, 001, 000, 001, 016, 001, 017, 002, 000, 001, 016, 001, 000,
-	001, 012, 002, 000, 001, 004, 001, 000, 002, 003, 002, 005, 002, 000, 002, 020,
-	001, 021, 002, 000, 001, 020, 001, 000, 001, 012, 002, 000, 001, 005, 003, 000,
-	001, 006, 001, 000, 001, 006, 003, 000, 001, 017, 007, 000, 001, 006, 001, 000,
-	002, 003, 001, 022, 001, 005, 001, 023, 003, 000, 001, 022, 004, 000, 001, 012,
-	002, 000, 001, 022, 003, 000, 001, 010, 015, 000, 001, 010, 003, 000, 001, 011,
-	015, 000, 001, 011, 001, 000, 002, 003, 001, 012, 001, 015, 001, 000, 001, 016,
-	001, 000, 001, 016, 001, 017, 002, 000, 001, 024, 001, 025, 001, 012, 002, 000,
-	001, 012, 003, 000, 001, 026, 013, 000, 001, 027, 001, 000, 001, 026, 003, 000,
-	001, 014, 014, 000, 002, 014, 001, 000, 002, 003, 002, 015, 002, 000, 002, 030,
-	001, 017, 002, 000, 001, 030, 001, 000, 001, 012, 002, 000, 001, 015, 001, 000,
-	002, 003, 001, 016, 012, 000, 001, 003, 002, 000, 001, 016, 001, 000, 002, 003,
-	001, 017, 001, 015, 001, 023, 003, 000, 001, 017, 004, 000, 001, 012, 002, 000,
-	001, 017, 003, 000, 001, 020, 001, 005, 014, 000, 001, 020, 001, 000, 002, 003,
-	001, 021, 001, 005, 001, 023, 003, 000, 001, 021, 004, 000, 001, 012, 002, 000,
-	001, 021, 003, 000, 001, 023, 001, 000, 001, 023, 003, 000, 001, 017, 007, 000,
-	001, 023, 001, 000, 002, 003, 001, 024, 001, 015, 004, 000, 001, 017, 004, 000,
-	001, 012, 002, 000, 001, 024, 003, 000, 001, 025, 012, 000, 001, 024, 002, 000,
-	001, 025, 003, 000, 001, 027, 013, 000, 001, 027, 001, 000, 001, 027, 003, 000,
-	001, 030, 001, 015, 014, 000, 001, 030,
-})
-
-func zzUnpackTrans(packed []int) []int {
-	m := make([]int, 396)
-	j := 0
-	for i, l := 0, len(packed); i < l; i += 2 {
-		count, value := packed[i], packed[i+1]-1
-		m[j] = value
-		j++
-		count--
-		for count > 0 {
-			m[j] = value
-			j++
-			count--
-		}
-	}
-	return m
-}
-
-/* error codes */
-const (
-	ZZ_UNKNOWN_ERROR = 0
-	ZZ_NO_MATCH      = 1
-)
-
-/* error messages for the codes above */
-var ZZ_ERROR_MSG = [3]string{
-	"Unkown internal scanner error",
-	"Error: could not match input",
-	"Error: pushback value was too large",
-}
-
-/* ZZ_ATTRIBUTE[aState] contains the attributes of state aState */
-var ZZ_ATTRIBUTE = zzUnpackAttribute([]int{
-	001, 000, 001, 011, 013, 001, 001, 000, 001, 001, 001, 000, 001, 001, 001, 000,
-	002, 001, 002, 000, 001, 001, 001, 0,
-})
-
-func zzUnpackAttribute(packed []int) []int {
-	m := make([]int, 24)
-	j := 0
-	for i, l := 0, len(packed); i < l; i += 2 {
-		count, value := packed[i], packed[i+1]
-		m[j] = value
-		j++
-		count--
-		for count > 0 {
-			m[j] = value
-			j++
-			count--
-		}
-		i += 2
-	}
-	return m
-}
-
 const (
 	WORD_TYPE             = ALPHANUM
 	NUMERIC_TYPE          = NUM
@@ -323,6 +19,7 @@ const (
 	HIRAGANA_TYPE         = HIRAGANA
 	KATAKANA_TYPE         = KATAKANA
 	HANGUL_TYPE           = HANGUL
+	EMOJI_TYPE            = EMOJI
 )
 
 /*
@@ -334,130 +31,46 @@ Tokens produced are of the following types:
 	- <ALPHANUM>: A sequence of alphabetic and numeric characters
 	- <NUM>: A number
 	- <SOUTHEAST_ASIAN>: A sequence of characters from South and Southeast Asian languages, including Thai, Lao, Myanmar, and Khmer
-	- IDEOGRAPHIC>: A single CJKV ideographic character
+	- <IDEOGRAPHIC>: A single CJKV ideographic character
 	- <HIRAGANA>: A single hiragana character
+	- <KATAKANA>: A sequence of katakana characters
+	- <HANGUL>: A sequence of Hangul characters
+	- <EMOJI>: A sequence of Emoji characters
 
-Technically it should auto generated by JFlex but there is no GoFlex
-yet. So it's a line-by-line port.
+Lucene generates this scanner with JFlex. There is no GoFlex, so the
+word break rules (WB4-WB13b) are applied directly on full code points,
+with the Word_Break property derived from Go's unicode tables.
 */
 type StandardTokenizerImpl struct {
 	// the input device
-	zzReader io.RuneReader
-
-	// the current state of the DFA
-	zzState int
-
-	// the current lexical state
-	zzLexicalState int
-
-	// this buffer contains the current text to be matched and is the
-	// source of yytext() string
-	zzBuffer []rune
-
-	// the text position at the last accepting state
-	zzMarkedPos int
-
-	// the current text position in the buffer
-	zzCurrentPos int
-
-	// startRead marks the beginning of the yytext() string in the buffer
-	zzStartRead int
-
-	// endRead marks the last character in the buffer, that has been read from input
-	zzEndRead int
-
-	// number of newlines encountered up to the start of the matched text
-	yyline int
-
-	// the number of characters up to the start of the matched text
-	_yychar int
-
-	// the number of characters from the last newline up to the start of the matched text
-	yycolumn int
-
-	// zzAtBOL == true <=> the scanner is currently at the beginning of a line
-	zzAtBOL bool
-
-	// zzAtEOF == true <=> the scanner is at the EOF
-	zzAtEOF bool
-
-	// denotes if the user-EOF-code has already been executed
-	zzEOFDone bool
-
-	// The number of occupied positions in zzBuffer beyond zzEndRead.
-	// When a lead/high surrogate has been read from the input stream
-	// into the final zzBuffer position, this will have a value of 1;
-	// otherwise, it will have a value of 0.
-	zzFinalHighSurrogate int
+	reader io.RuneReader
+	// error reported by the input device, other than io.EOF
+	err error
+	// true once the input device is exhausted
+	eof bool
+
+	// the lookahead buffer, holding the current token and what follows
+	buffer []rune
+	// the number of characters preceding buffer[0]
+	offset int
+	// the current token is buffer[start:end]
+	start, end int
 }
 
 func newStandardTokenizerImpl(in io.RuneReader) *StandardTokenizerImpl {
 	return &StandardTokenizerImpl{
-		zzReader:       in,
-		zzLexicalState: YYINITIAL,
-		zzBuffer:       make([]rune, ZZ_BUFFERSIZE),
-		zzAtBOL:        true,
+		reader: in,
+		buffer: make([]rune, 0, ZZ_BUFFERSIZE),
 	}
 }
 
 func (t *StandardTokenizerImpl) yychar() int {
-	return t._yychar
+	return t.offset + t.start
 }
 
 /* Fills CharTermAttribute with the current token text. */
 func (t *StandardTokenizerImpl) text(tt CharTermAttribute) {
-	tt.CopyBuffer(t.zzBuffer[t.zzStartRead:t.zzMarkedPos])
-}
-
-/* Refills the input buffer. */
-func (t *StandardTokenizerImpl) zzRefill() (bool, error) {
-	// first: make room (if you can)
-	if t.zzStartRead > 0 {
-		t.zzEndRead += t.zzFinalHighSurrogate
-		t.zzFinalHighSurrogate = 0
-		copy(t.zzBuffer, t.zzBuffer[t.zzStartRead:t.zzEndRead])
-
-		// translate stored positions
-		t.zzEndRead -= t.zzStartRead
-		t.zzCurrentPos -= t.zzStartRead
-		t.zzMarkedPos -= t.zzStartRead
-		t.zzStartRead = 0
-	}
-
-	// fill the buffer with new input
-	var requested = len(t.zzBuffer) - t.zzEndRead - t.zzFinalHighSurrogate
-	var totalRead = 0
-	var err error
-	for totalRead < requested && err != io.EOF {
-		var numRead int
-		if numRead, err = readRunes(t.zzReader.(io.RuneReader),
-			t.zzBuffer[t.zzEndRead+totalRead:]); err != nil && err != io.EOF {
-			return false, err
-		}
-		totalRead += numRead
-	}
-
-	if totalRead > 0 {
-		t.zzEndRead += totalRead
-		if totalRead == requested { // possibly more input available
-			panic("niy")
-		}
-		return false, nil
-	}
-
-	assert(totalRead == 0 && err == io.EOF)
-	return true, nil
-}
-
-func readRunes(r io.RuneReader, buffer []rune) (int, error) {
-	for i, _ := range buffer {
-		ch, _, err := r.ReadRune()
-		if err != nil {
-			return i, err
-		}
-		buffer[i] = ch
-	}
-	return len(buffer), nil
+	tt.CopyBuffer(t.buffer[t.start:t.end])
 }
 
 /*
@@ -466,163 +79,432 @@ Does not close the old reader.
 
 All internal variables are reset, the old input stream
 cannot be reused (internal buffer is discarded and lost).
-Lexical state is set to ZZ_INITIAL.
 
 Internal scan buffer is resized down to its initial length, if it has grown.
 */
 func (t *StandardTokenizerImpl) yyreset(reader io.RuneReader) {
-	t.zzReader = reader
-	t.zzAtBOL = true
-	t.zzAtEOF = false
-	t.zzEOFDone = false
-	t.zzEndRead, t.zzStartRead = 0, 0
-	t.zzCurrentPos, t.zzMarkedPos = 0, 0
-	t.zzFinalHighSurrogate = 0
-	t.yyline, t._yychar, t.yycolumn = 0, 0, 0
-	t.zzLexicalState = YYINITIAL
-	if len(t.zzBuffer) > ZZ_BUFFERSIZE {
-		t.zzBuffer = make([]rune, ZZ_BUFFERSIZE)
+	t.reader = reader
+	t.err, t.eof = nil, false
+	t.offset, t.start, t.end = 0, 0, 0
+	if cap(t.buffer) > ZZ_BUFFERSIZE {
+		t.buffer = make([]rune, 0, ZZ_BUFFERSIZE)
 	}
+	t.buffer = t.buffer[:0]
 }
 
 /* Returns the length of the matched text region. */
 func (t *StandardTokenizerImpl) yylength() int {
-	return t.zzMarkedPos - t.zzStartRead
+	return t.end - t.start
 }
 
 /*
-Reports an error that occurred while scanning.
+Returns the character at position i of the buffer, reading more
+input as needed, or -1 if the input ends before it.
+*/
+func (t *StandardTokenizerImpl) charAt(i int) rune {
+	for i >= len(t.buffer) {
+		if t.eof || t.reader == nil {
+			return -1
+		}
+		ch, _, err := t.reader.ReadRune()
+		if err != nil {
+			if err != io.EOF {
+				t.err = err
+			}
+			t.eof = true
+			return -1
+		}
+		t.buffer = append(t.buffer, ch)
+	}
+	return t.buffer[i]
+}
 
-In a wellcormed scanner (no or only correct usage of yypushack(int)
-and a match-all fallback rule) this method will only be called with
-things that "can't possibly happen". If thismethod is called,
-something is seriously wrong (e.g. a JFlex bug producing a faulty
-scanner etc.).
+/* Returns the Word_Break value of the character at position i. */
+func (t *StandardTokenizerImpl) wordBreakAt(i int) wordBreak {
+	if ch := t.charAt(i); ch >= 0 {
+		return wordBreakOf(ch)
+	}
+	return wbEOF
+}
 
-Usual syntax/scanner level error handling should be done in error
-fallback rules.
+/*
+Skips Extend, Format and ZWJ characters starting from position i,
+which are ignored after any character but newlines (WB4).
 */
-func (t *StandardTokenizerImpl) zzScanError(errorCode int) {
-	var msg string
-	if errorCode >= 0 && errorCode < len(ZZ_ERROR_MSG) {
-		msg = ZZ_ERROR_MSG[errorCode]
-	} else {
-		msg = ZZ_ERROR_MSG[ZZ_UNKNOWN_ERROR]
+func (t *StandardTokenizerImpl) skipIgnorable(i int) int {
+	for {
+		switch t.wordBreakAt(i) {
+		case wbExtend, wbFormat, wbZWJ:
+			i++
+		default:
+			return i
+		}
 	}
-	panic(msg)
 }
 
 /*
-Resumes scanning until the next regular expression is matched, the
-end of input is encountered or an I/O-Error occurs.
+Resumes scanning until the next token is matched, the end of input
+is encountered or an I/O-Error occurs.
 */
 func (t *StandardTokenizerImpl) nextToken() (int, error) {
-	var zzInput, zzAction int
-
-	// cached fields:
-	var zzCurrentPosL, zzMarkedPosL int
-	zzEndReadL := t.zzEndRead
-	zzBufferL := t.zzBuffer
-	zzCMapL := ZZ_CMAP
-
-	zzTransL := ZZ_TRANS
-	zzRowMapL := ZZ_ROWMAP
-	zzAttrL := ZZ_ATTRIBUTE
+	// discard the previous token; its characters are never looked at again
+	if t.end > 0 {
+		n := copy(t.buffer, t.buffer[t.end:])
+		t.buffer = t.buffer[:n]
+		t.offset += t.end
+	}
+	t.start, t.end = 0, 0
 
 	for {
-		zzMarkedPosL = t.zzMarkedPos
-
-		t._yychar += zzMarkedPosL - t.zzStartRead
-
-		zzAction = -1
-
-		zzCurrentPosL = zzMarkedPosL
-		t.zzCurrentPos = zzMarkedPosL
-		t.zzStartRead = zzMarkedPosL
-
-		t.zzState = ZZ_LEXSTATE[t.zzLexicalState]
+		ch := t.charAt(t.start)
+		if ch < 0 {
+			t.end = t.start
+			if t.err != nil {
+				return YYEOF, t.err
+			}
+			return YYEOF, nil
+		}
+		if tokenType, end := t.matchToken(ch); end > t.start {
+			t.end = end
+			return tokenType, nil
+		}
+		// not numeric, word, ideographic, hiragana, SE Asian or emoji -- ignore it.
+		t.start++
+	}
+}
 
-		// set up zzAction for empty match case:
-		if zzAttributes := zzAttrL[t.zzState]; (zzAttributes & 1) == 1 {
-			zzAction = t.zzState
+/*
+Matches a token starting with ch at the current start position.
+Returns the token type and the end of the match, or the start
+position itself if no token starts here.
+*/
+func (t *StandardTokenizerImpl) matchToken(ch rune) (int, int) {
+	if end := t.matchEmoji(ch); end > t.start {
+		return EMOJI_TYPE, end
+	}
+	switch {
+	case isIdeographic(ch):
+		return IDEOGRAPHIC_TYPE, t.skipIgnorable(t.start + 1)
+	case isHiragana(ch):
+		return HIRAGANA_TYPE, t.skipIgnorable(t.start + 1)
+	case isSouthEastAsian(ch):
+		end := t.skipIgnorable(t.start + 1)
+		for ch = t.charAt(end); ch >= 0 && isSouthEastAsian(ch); ch = t.charAt(end) {
+			end = t.skipIgnorable(end + 1)
 		}
+		return SOUTH_EAST_ASIAN_TYPE, end
+	}
+	return t.matchWord()
+}
 
+/*
+Matches an emoji sequence: a keycap sequence, a pair of regional
+indicators (a flag), or an emoji with its modifiers, presentation
+selectors and tags, joined to further emoji with ZWJ.
+*/
+func (t *StandardTokenizerImpl) matchEmoji(ch rune) int {
+	i := t.start + 1
+	switch {
+	case isKeycapBase(ch):
+		if t.charAt(i) == 0xFE0F {
+			i++
+		}
+		if t.charAt(i) != 0x20E3 {
+			return t.start
+		}
+		return t.skipIgnorable(i + 1)
+	case isRegionalIndicator(ch):
+		if !isRegionalIndicator(t.charAt(i)) {
+			return t.start
+		}
+		return t.skipIgnorable(i + 1)
+	case isExtendedPictographic(ch):
+		// text presentation pictographs, e.g. '©', are emoji only when
+		// followed by a presentation selector, a modifier or ZWJ
+		if next := t.charAt(i); !isEmojiPresentation(ch) &&
+			next != 0xFE0F && next != 0x200D && !isEmojiModifier(next) {
+			return t.start
+		}
 		for {
-			if zzCurrentPosL < zzEndReadL {
-				zzInput = int(zzBufferL[zzCurrentPosL])
-				zzCurrentPosL++
-			} else if t.zzAtEOF {
-				zzInput = YYEOF
-				break
+			i = t.skipIgnorable(i)
+			// WB3c: ZWJ × \p{Extended_Pictographic}
+			if t.charAt(i-1) != 0x200D || !isExtendedPictographic(t.charAt(i)) {
+				return i
+			}
+			i++
+		}
+	}
+	return t.start
+}
+
+/* Matches a word or a number according to rules WB5-WB13b. */
+func (t *StandardTokenizerImpl) matchWord() (int, int) {
+	var hasLetter, hasHangul, hasKatakana, hasNumeric bool
+	mark := func(wb wordBreak, i int) {
+		switch wb {
+		case wbALetter:
+			if unicode.Is(unicode.Hangul, t.buffer[i]) {
+				hasHangul = true
 			} else {
-				// store back cached positions
-				t.zzCurrentPos = zzCurrentPosL
-				t.zzMarkedPos = zzMarkedPosL
-				eof, err := t.zzRefill()
-				if err != nil {
-					return 0, err
-				}
-				// get translated positions and possibly new buffer
-				zzCurrentPosL = t.zzCurrentPos
-				zzMarkedPosL = t.zzMarkedPos
-				zzBufferL = t.zzBuffer
-				zzEndReadL = t.zzEndRead
-				if eof {
-					zzInput = YYEOF
-					break
-				} else {
-					zzInput = int(zzBufferL[zzCurrentPosL])
-					zzCurrentPosL++
-				}
+				hasLetter = true
 			}
-			zzNext := zzTransL[zzRowMapL[t.zzState]+int(zzCMapL[zzInput])]
-			if zzNext == -1 {
-				break
+		case wbHebrewLetter:
+			hasLetter = true
+		case wbKatakana:
+			hasKatakana = true
+		case wbNumeric:
+			hasNumeric = true
+		}
+	}
+
+	prev := t.wordBreakAt(t.start)
+	if !prev.isWordPart() {
+		return 0, t.start
+	}
+	mark(prev, t.start)
+	end := t.skipIgnorable(t.start + 1)
+
+	for {
+		next := t.wordBreakAt(end)
+		switch {
+		case prev.isAHLetter() && next.isAHLetter(), // WB5
+			prev == wbNumeric && next == wbNumeric,      // WB8
+			prev.isAHLetter() && next == wbNumeric,      // WB9
+			prev == wbNumeric && next.isAHLetter(),      // WB10
+			prev == wbKatakana && next == wbKatakana,    // WB13
+			prev.isWordPart() && next == wbExtendNumLet, // WB13a
+			prev == wbExtendNumLet && next.isWordPart(): // WB13b
+			mark(next, end)
+			prev, end = next, t.skipIgnorable(end+1)
+			continue
+
+		case prev.isAHLetter() && (next == wbMidLetter || next.isMidNumLetQ()):
+			after := t.skipIgnorable(end + 1)
+			if wb := t.wordBreakAt(after); wb.isAHLetter() { // WB6, WB7
+				mark(wb, after)
+				prev, end = wb, t.skipIgnorable(after+1)
+				continue
 			}
-			t.zzState = zzNext
-
-			if zzAttributes := zzAttrL[t.zzState]; (zzAttributes & 1) == 1 {
-				zzAction = t.zzState
-				zzMarkedPosL = zzCurrentPosL
-				if (zzAttributes & 8) == 8 {
-					break
-				}
+			if prev == wbHebrewLetter && next == wbSingleQuote { // WB7a
+				end = after
 			}
-		}
 
-		// store back cached position
-		t.zzMarkedPos = zzMarkedPosL
+		case prev == wbHebrewLetter && next == wbDoubleQuote:
+			after := t.skipIgnorable(end + 1)
+			if t.wordBreakAt(after) == wbHebrewLetter { // WB7b, WB7c
+				prev, end = wbHebrewLetter, t.skipIgnorable(after+1)
+				continue
+			}
 
-		cond := zzAction
-		if zzAction >= 0 {
-			cond = ZZ_ACTION[zzAction]
-		}
-		switch cond {
-		case 1, 9, 10, 11, 12, 13, 14, 15, 16:
-			// break so we don't hit fall-through warning:
-			// not numeric, word, ideographic, hiragana, or SE Asian -- ignore it.
-		case 2:
-			return WORD_TYPE, nil
-		case 3:
-			return HANGUL_TYPE, nil
-		case 4:
-			return NUMERIC_TYPE, nil
-		case 5:
-			return KATAKANA_TYPE, nil
-		case 6:
-			return IDEOGRAPHIC_TYPE, nil
-		case 7:
-			return HIRAGANA_TYPE, nil
-		case 8:
-			return SOUTH_EAST_ASIAN_TYPE, nil
-		default:
-			if zzInput == YYEOF && t.zzStartRead == t.zzCurrentPos {
-				t.zzAtEOF = true
-				return YYEOF, nil
-			} else {
-				t.zzScanError(ZZ_NO_MATCH)
-				panic("should not be here")
+		case prev == wbNumeric && (next == wbMidNum || next.isMidNumLetQ()):
+			after := t.skipIgnorable(end + 1)
+			if t.wordBreakAt(after) == wbNumeric { // WB11, WB12
+				prev, end = wbNumeric, t.skipIgnorable(after+1)
+				continue
 			}
 		}
+		break
+	}
+
+	switch {
+	case hasLetter || hasHangul && (hasKatakana || hasNumeric) || hasKatakana && hasNumeric:
+		return WORD_TYPE, end
+	case hasHangul:
+		return HANGUL_TYPE, end
+	case hasKatakana:
+		return KATAKANA_TYPE, end
+	case hasNumeric:
+		return NUMERIC_TYPE, end
+	}
+	// a run of connector punctuation only, e.g. "___"
+	return 0, t.start
+}
+
+// Word_Break property values, see http://unicode.org/reports/tr29/
+
+type wordBreak int
+
+const (
+	wbEOF wordBreak = iota
+	wbOther
+	wbNewline
+	wbExtend
+	wbZWJ
+	wbRegionalIndicator
+	wbFormat
+	wbKatakana
+	wbHebrewLetter
+	wbALetter
+	wbSingleQuote
+	wbDoubleQuote
+	wbMidNumLet
+	wbMidLetter
+	wbMidNum
+	wbNumeric
+	wbExtendNumLet
+	wbWSegSpace
+)
+
+/* AHLetter = ALetter | Hebrew_Letter */
+func (wb wordBreak) isAHLetter() bool {
+	return wb == wbALetter || wb == wbHebrewLetter
+}
+
+/* MidNumLetQ = MidNumLet | Single_Quote */
+func (wb wordBreak) isMidNumLetQ() bool {
+	return wb == wbMidNumLet || wb == wbSingleQuote
+}
+
+func (wb wordBreak) isWordPart() bool {
+	switch wb {
+	case wbALetter, wbHebrewLetter, wbNumeric, wbKatakana, wbExtendNumLet:
+		return true
+	}
+	return false
+}
+
+func wordBreakOf(ch rune) wordBreak {
+	switch ch {
+	case '\r', '\n', 0x0B, 0x0C, 0x85, 0x2028, 0x2029:
+		return wbNewline
+	case 0x200D:
+		return wbZWJ
+	case 0x200C:
+		return wbExtend
+	case 0x200B:
+		return wbOther
+	case '\'':
+		return wbSingleQuote
+	case '"':
+		return wbDoubleQuote
+	case '.', 0x2018, 0x2019, 0x2024, 0xFE52, 0xFF07, 0xFF0E:
+		return wbMidNumLet
+	case ':', 0xB7, 0x0387, 0x055F, 0x05F4, 0x2027, 0xFE13, 0xFE55, 0xFF1A:
+		return wbMidLetter
+	case ',', ';', 0x037E, 0x0589, 0x060C, 0x060D, 0x066C, 0x07F8, 0x2044,
+		0xFE10, 0xFE14, 0xFE50, 0xFE54, 0xFF0C, 0xFF1B:
+		return wbMidNum
+	case 0x066B:
+		return wbNumeric
+	case 0x202F:
+		return wbExtendNumLet
+	case 0x3031, 0x3032, 0x3033, 0x3034, 0x3035, 0x309B, 0x309C, 0x30A0, 0x30FC, 0xFF70:
+		return wbKatakana
+	}
+	switch {
+	case isRegionalIndicator(ch):
+		return wbRegionalIndicator
+	case isEmojiModifier(ch), ch >= 0xE0020 && ch <= 0xE007F, ch == 0xFF9E, ch == 0xFF9F,
+		unicode.In(ch, unicode.Mn, unicode.Me, unicode.Mc):
+		return wbExtend
+	case unicode.Is(unicode.Cf, ch):
+		return wbFormat
+	case unicode.Is(unicode.Katakana, ch):
+		return wbKatakana
+	case unicode.Is(unicode.Hebrew, ch) && unicode.Is(unicode.Lo, ch):
+		return wbHebrewLetter
+	case unicode.Is(unicode.Nd, ch):
+		return wbNumeric
+	case isIdeographic(ch), isHiragana(ch), isSouthEastAsian(ch):
+		return wbOther
+	case unicode.IsLetter(ch), unicode.Is(unicode.Nl, ch), unicode.Is(unicode.Other_Alphabetic, ch):
+		return wbALetter
+	case unicode.Is(unicode.Pc, ch):
+		return wbExtendNumLet
+	case unicode.Is(unicode.Zs, ch):
+		return wbWSegSpace
+	}
+	return wbOther
+}
+
+func isIdeographic(ch rune) bool {
+	return unicode.Is(unicode.Ideographic, ch)
+}
+
+func isHiragana(ch rune) bool {
+	return unicode.Is(unicode.Hiragana, ch) && unicode.IsLetter(ch)
+}
+
+/* Letters of scripts with Line_Break=Complex_Context, written without spaces. */
+func isSouthEastAsian(ch rune) bool {
+	return unicode.In(ch, unicode.Thai, unicode.Lao, unicode.Myanmar, unicode.Khmer,
+		unicode.Tai_Le, unicode.New_Tai_Lue, unicode.Tai_Tham, unicode.Tai_Viet) &&
+		unicode.IsLetter(ch)
+}
+
+func isRegionalIndicator(ch rune) bool {
+	return ch >= 0x1F1E6 && ch <= 0x1F1FF
+}
+
+func isEmojiModifier(ch rune) bool {
+	return ch >= 0x1F3FB && ch <= 0x1F3FF
+}
+
+func isKeycapBase(ch rune) bool {
+	return ch >= '0' && ch <= '9' || ch == '#' || ch == '*'
+}
+
+var extendedPictographic = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{0x00A9, 0x00A9, 1}, {0x00AE, 0x00AE, 1}, {0x203C, 0x203C, 1},
+		{0x2049, 0x2049, 1}, {0x2122, 0x2122, 1}, {0x2139, 0x2139, 1},
+		{0x2194, 0x2199, 1}, {0x21A9, 0x21AA, 1}, {0x231A, 0x231B, 1},
+		{0x2328, 0x2328, 1}, {0x2388, 0x2388, 1}, {0x23CF, 0x23CF, 1},
+		{0x23E9, 0x23F3, 1}, {0x23F8, 0x23FA, 1}, {0x24C2, 0x24C2, 1},
+		{0x25AA, 0x25AB, 1}, {0x25B6, 0x25B6, 1}, {0x25C0, 0x25C0, 1},
+		{0x25FB, 0x25FE, 1}, {0x2600, 0x2605, 1}, {0x2607, 0x2612, 1},
+		{0x2614, 0x2685, 1}, {0x2690, 0x2705, 1}, {0x2708, 0x2712, 1},
+		{0x2714, 0x2714, 1}, {0x2716, 0x2716, 1}, {0x271D, 0x271D, 1},
+		{0x2721, 0x2721, 1}, {0x2728, 0x2728, 1}, {0x2733, 0x2734, 1},
+		{0x2744, 0x2744, 1}, {0x2747, 0x2747, 1}, {0x274C, 0x274C, 1},
+		{0x274E, 0x274E, 1}, {0x2753, 0x2755, 1}, {0x2757, 0x2757, 1},
+		{0x2763, 0x2767, 1}, {0x2795, 0x2797, 1}, {0x27A1, 0x27A1, 1},
+		{0x27B0, 0x27B0, 1}, {0x27BF, 0x27BF, 1}, {0x2934, 0x2935, 1},
+		{0x2B05, 0x2B07, 1}, {0x2B1B, 0x2B1C, 1}, {0x2B50, 0x2B50, 1},
+		{0x2B55, 0x2B55, 1}, {0x3030, 0x3030, 1}, {0x303D, 0x303D, 1},
+		{0x3297, 0x3297, 1}, {0x3299, 0x3299, 1},
+	},
+	R32: []unicode.Range32{
+		{0x1F000, 0x1F0FF, 1}, {0x1F10D, 0x1F10F, 1}, {0x1F12F, 0x1F12F, 1},
+		{0x1F16C, 0x1F171, 1}, {0x1F17E, 0x1F17F, 1}, {0x1F18E, 0x1F18E, 1},
+		{0x1F191, 0x1F19A, 1}, {0x1F1AD, 0x1F1E5, 1}, {0x1F201, 0x1F20F, 1},
+		{0x1F21A, 0x1F21A, 1}, {0x1F22F, 0x1F22F, 1}, {0x1F232, 0x1F23A, 1},
+		{0x1F23C, 0x1F23F, 1}, {0x1F249, 0x1F3FA, 1}, {0x1F400, 0x1F53D, 1},
+		{0x1F546, 0x1F64F, 1}, {0x1F680, 0x1F6FF, 1}, {0x1F774, 0x1F77F, 1},
+		{0x1F7D5, 0x1F7FF, 1}, {0x1F80C, 0x1F80F, 1}, {0x1F848, 0x1F84F, 1},
+		{0x1F85A, 0x1F85F, 1}, {0x1F888, 0x1F88F, 1}, {0x1F8AE, 0x1F8FF, 1},
+		{0x1F90C, 0x1F93A, 1}, {0x1F93C, 0x1F945, 1}, {0x1F947, 0x1FAFF, 1},
+		{0x1FC00, 0x1FFFD, 1},
+	},
+}
+
+func isExtendedPictographic(ch rune) bool {
+	return ch >= 0 && unicode.Is(extendedPictographic, ch)
+}
+
+/*
+Pictographs displayed as emoji by default. Emoji in the BMP mostly
+default to text presentation, so only those listed here qualify.
+*/
+var emojiPresentation = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{0x231A, 0x231B, 1}, {0x23E9, 0x23EC, 1}, {0x23F0, 0x23F0, 1},
+		{0x23F3, 0x23F3, 1}, {0x25FD, 0x25FE, 1}, {0x2614, 0x2615, 1},
+		{0x2648, 0x2653, 1}, {0x267F, 0x267F, 1}, {0x2693, 0x2693, 1},
+		{0x26A1, 0x26A1, 1}, {0x26AA, 0x26AB, 1}, {0x26BD, 0x26BE, 1},
+		{0x26C4, 0x26C5, 1}, {0x26CE, 0x26CE, 1}, {0x26D4, 0x26D4, 1},
+		{0x26EA, 0x26EA, 1}, {0x26F2, 0x26F3, 1}, {0x26F5, 0x26F5, 1},
+		{0x26FA, 0x26FA, 1}, {0x26FD, 0x26FD, 1}, {0x2705, 0x2705, 1},
+		{0x270A, 0x270B, 1}, {0x2728, 0x2728, 1}, {0x274C, 0x274C, 1},
+		{0x274E, 0x274E, 1}, {0x2753, 0x2755, 1}, {0x2757, 0x2757, 1},
+		{0x2795, 0x2797, 1}, {0x27B0, 0x27B0, 1}, {0x27BF, 0x27BF, 1},
+		{0x2B1B, 0x2B1C, 1}, {0x2B50, 0x2B50, 1}, {0x2B55, 0x2B55, 1},
+	},
+}
+
+func isEmojiPresentation(ch rune) bool {
+	if ch >= 0x1F000 {
+		return isExtendedPictographic(ch)
 	}
+	return unicode.Is(emojiPresentation, ch)
 }
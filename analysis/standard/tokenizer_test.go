@@ -0,0 +1,83 @@
+package standard
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func scan(t *testing.T, text string) []string {
+	scanner := newStandardTokenizerImpl(nil)
+	scanner.yyreset(strings.NewReader(text))
+	var tokens []string
+	for {
+		tokenType, err := scanner.nextToken()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tokenType == YYEOF {
+			break
+		}
+		runes := []rune(text)
+		start := scanner.yychar()
+		tokens = append(tokens, fmt.Sprintf("%v%v",
+			string(runes[start:start+scanner.yylength()]), TOKEN_TYPES[tokenType]))
+	}
+	if n := len([]rune(text)); scanner.yychar()+scanner.yylength() != n {
+		t.Errorf("expected final offset %v, but was %v", n, scanner.yychar()+scanner.yylength())
+	}
+	return tokens
+}
+
+func assertScan(t *testing.T, text string, expected ...string) {
+	if tokens := scan(t, text); fmt.Sprint(tokens) != fmt.Sprint(expected) {
+		t.Errorf("%q: expected %v, but was %v", text, expected, tokens)
+	}
+}
+
+func TestStandardTokenizerWordBreaks(t *testing.T) {
+	assertScan(t, "Hello, world! don't 1,234.56 U.S.A. foo_bar ___",
+		"Hello<ALPHANUM>", "world<ALPHANUM>", "don't<ALPHANUM>", "1,234.56<NUM>",
+		"U.S.A<ALPHANUM>", "foo_bar<ALPHANUM>")
+	assertScan(t, "a:b c--d 3a 1.2.3. naïve שׁ״ב",
+		"a:b<ALPHANUM>", "c<ALPHANUM>", "d<ALPHANUM>", "3a<ALPHANUM>",
+		"1.2.3<NUM>", "naïve<ALPHANUM>", "שׁ״ב<ALPHANUM>")
+}
+
+func TestStandardTokenizerScripts(t *testing.T) {
+	assertScan(t, "日本語 ひらがな カタカナ 한국어 ภาษาไทย 𠀀",
+		"日<IDEOGRAPHIC>", "本<IDEOGRAPHIC>", "語<IDEOGRAPHIC>",
+		"ひ<HIRAGANA>", "ら<HIRAGANA>", "が<HIRAGANA>", "な<HIRAGANA>",
+		"カタカナ<KATAKANA>", "한국어<HANGUL>", "ภาษาไทย<SOUTHEAST_ASIAN>",
+		"𠀀<IDEOGRAPHIC>")
+}
+
+func TestStandardTokenizerEmoji(t *testing.T) {
+	assertScan(t, "😀👍🏽 👨‍👩‍👧 🇺🇸 a😀b 1️⃣ © ❤️",
+		"😀<EMOJI>", "👍🏽<EMOJI>", "👨‍👩‍👧<EMOJI>", "🇺🇸<EMOJI>",
+		"a<ALPHANUM>", "😀<EMOJI>", "b<ALPHANUM>", "1️⃣<EMOJI>", "❤️<EMOJI>")
+}
+
+func TestStandardTokenizerLongToken(t *testing.T) {
+	// longer than the initial lookahead buffer, which grows to hold it
+	long := strings.Repeat("x", 3*ZZ_BUFFERSIZE+7)
+	assertScan(t, "a "+long+" b "+long+"1",
+		"a<ALPHANUM>", long+"<ALPHANUM>", "b<ALPHANUM>", long+"1<ALPHANUM>")
+
+	scanner := newStandardTokenizerImpl(strings.NewReader(long))
+	if tokenType, err := scanner.nextToken(); err != nil || tokenType != WORD_TYPE ||
+		scanner.yylength() != len(long) {
+		t.Fatalf("expected a word of %v characters, but was %v (%v)", len(long), scanner.yylength(), err)
+	}
+	if cap(scanner.buffer) <= ZZ_BUFFERSIZE {
+		t.Errorf("expected the buffer to grow, but its capacity was %v", cap(scanner.buffer))
+	}
+	// the grown buffer is released on reset
+	scanner.yyreset(strings.NewReader("c"))
+	if cap(scanner.buffer) != ZZ_BUFFERSIZE {
+		t.Errorf("expected the buffer to shrink back to %v, but was %v", ZZ_BUFFERSIZE, cap(scanner.buffer))
+	}
+	if tokenType, err := scanner.nextToken(); err != nil || tokenType != WORD_TYPE || scanner.yychar() != 0 {
+		t.Errorf("expected a word at 0 after reset, but was %v at %v (%v)", tokenType, scanner.yychar(), err)
+	}
+}
@@ -0,0 +1,92 @@
+package sv
+
+import (
+	. "github.com/balzaczyy/golucene/analysis/core"
+	. "github.com/balzaczyy/golucene/analysis/miscellaneous"
+	"github.com/balzaczyy/golucene/analysis/snowball"
+	std "github.com/balzaczyy/golucene/analysis/standard"
+	. "github.com/balzaczyy/golucene/analysis/util"
+	. "github.com/balzaczyy/golucene/core/analysis"
+	"io"
+)
+
+// sv/SwedishAnalyzer.java
+
+/* The default set of stopwords used by SwedishAnalyzer, from the Snowball swedish stop list. */
+var DEFAULT_STOPWORD_SET = map[string]bool{
+	"och": true, "det": true, "att": true, "i": true, "en": true,
+	"jag": true, "hon": true, "som": true, "han": true, "på": true,
+	"den": true, "med": true, "var": true, "sig": true, "för": true,
+	"så": true, "till": true, "är": true, "men": true, "ett": true,
+	"om": true, "hade": true, "de": true, "av": true, "icke": true,
+	"mig": true, "du": true, "henne": true, "då": true, "sin": true,
+	"nu": true, "har": true, "inte": true, "hans": true, "honom": true,
+	"skulle": true, "hennes": true, "där": true, "min": true, "man": true,
+	"ej": true, "vid": true, "kunde": true, "något": true, "från": true,
+	"ut": true, "när": true, "efter": true, "upp": true, "vi": true,
+	"dem": true, "vara": true, "vad": true, "över": true, "än": true,
+	"dig": true, "kan": true, "sina": true, "här": true, "ha": true,
+	"mot": true, "alla": true, "under": true, "någon": true,
+	"eller": true, "allt": true, "mycket": true, "sedan": true,
+	"ju": true, "denna": true, "själv": true, "detta": true, "åt": true,
+	"utan": true, "varit": true, "hur": true, "ingen": true, "mitt": true,
+	"ni": true, "bli": true, "blev": true, "oss": true, "din": true,
+	"dessa": true, "några": true, "deras": true, "blir": true,
+	"mina": true, "samma": true, "vilken": true, "er": true,
+	"sådan": true, "vår": true, "blivit": true, "dess": true,
+	"inom": true, "mellan": true, "sådant": true, "varför": true,
+	"varje": true, "vilka": true, "ditt": true, "vem": true,
+	"vilket": true, "sitta": true, "sådana": true, "vart": true,
+	"dina": true, "vars": true, "vårt": true, "våra": true, "ert": true,
+	"era": true, "vilkas": true,
+}
+
+/*
+Analyzer for Swedish.
+
+Builds an analysis chain made of StandardTokenizer, StandardFilter,
+LowerCaseFilter, StopFilter, SetKeywordMarkerFilter if a stem
+exclusion set is provided, and SnowballFilter with the swedish stemmer.
+*/
+type SwedishAnalyzer struct {
+	*StopwordAnalyzerBase
+	stemExclusionSet map[string]bool
+}
+
+/* Builds an analyzer with the default stop words: DEFAULT_STOPWORD_SET. */
+func NewSwedishAnalyzer() *SwedishAnalyzer {
+	return NewSwedishAnalyzerWithStopWords(DEFAULT_STOPWORD_SET)
+}
+
+/* Builds an analyzer with the given stop words. */
+func NewSwedishAnalyzerWithStopWords(stopwords map[string]bool) *SwedishAnalyzer {
+	return NewSwedishAnalyzerWithStemExclusions(stopwords, nil)
+}
+
+/*
+Builds an analyzer with the given stop words. If a non-empty stem
+exclusion set is provided this analyzer will add a
+SetKeywordMarkerFilter before stemming.
+*/
+func NewSwedishAnalyzerWithStemExclusions(stopwords, stemExclusionSet map[string]bool) *SwedishAnalyzer {
+	ans := &SwedishAnalyzer{stemExclusionSet: make(map[string]bool)}
+	for k, v := range stemExclusionSet {
+		ans.stemExclusionSet[k] = v
+	}
+	ans.StopwordAnalyzerBase = NewStopwordAnalyzerBaseWithStopWords(stopwords)
+	ans.Spi = ans
+	return ans
+}
+
+func (a *SwedishAnalyzer) CreateComponents(fieldName string, reader io.RuneReader) *TokenStreamComponents {
+	version := a.Version()
+	source := std.NewStandardTokenizer(version, reader)
+	var result TokenStream = std.NewStandardFilter(version, source)
+	result = NewLowerCaseFilter(version, result)
+	result = NewStopFilter(version, result, a.StopwordSet())
+	if len(a.stemExclusionSet) > 0 {
+		result = NewSetKeywordMarkerFilter(result, a.stemExclusionSet)
+	}
+	result = snowball.NewSnowballFilter(result, snowball.SwedishStemmer{})
+	return NewTokenStreamComponents(source, result)
+}
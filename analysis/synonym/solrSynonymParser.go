@@ -0,0 +1,168 @@
+package synonym
+
+import (
+	"bufio"
+	"fmt"
+	. "github.com/balzaczyy/golucene/core/analysis"
+	"io"
+	"strings"
+)
+
+// synonym/SolrSynonymParser.java
+
+/*
+Parser for the Solr synonyms format.
+
+	- Blank lines and lines starting with '#' are comments.
+	- Explicit mappings match any token sequence on the LHS of "=>"
+	and replace with all alternatives on the RHS. These types of
+	mappings ignore the expand parameter in the constructor.
+	Example:
+		i-pod, i pod => ipod
+	- Equivalent synonyms may be separated with commas and give no
+	explicit mapping. In this case the mapping behavior will be taken
+	from the expand parameter in the constructor. This allows the same
+	synonym file to be used in different synonym handling strategies.
+	Example:
+		ipod, i-pod, i pod
+	- Multiple synonym mapping entries are merged.
+	Example:
+		foo => foo bar
+		foo => baz
+		is equivalent to
+		foo => foo bar, baz
+*/
+type SolrSynonymParser struct {
+	*ParserBase
+	expand bool
+}
+
+func NewSolrSynonymParser(dedup, expand bool, analyzer Analyzer) *SolrSynonymParser {
+	return &SolrSynonymParser{NewParserBase(dedup, analyzer), expand}
+}
+
+func (p *SolrSynonymParser) Parse(in io.Reader) error {
+	scanner := bufio.NewScanner(in)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		if err := p.addLine(scanner.Text()); err != nil {
+			return fmt.Errorf("Invalid synonym rule at line %v: %v", lineNumber, err)
+		}
+	}
+	return scanner.Err()
+}
+
+func (p *SolrSynonymParser) addLine(line string) error {
+	if len(line) == 0 || line[0] == '#' {
+		return nil // ignore empty lines and comments
+	}
+
+	// TODO: we could process this more efficiently.
+	sides := split(line, "=>")
+	if len(sides) > 1 { // explicit mapping
+		if len(sides) != 2 {
+			return fmt.Errorf("more than one explicit mapping specified on the same line")
+		}
+		inputs, err := p.analyzeAll(split(sides[0], ","))
+		if err != nil {
+			return err
+		}
+		outputs, err := p.analyzeAll(split(sides[1], ","))
+		if err != nil {
+			return err
+		}
+		for _, input := range inputs {
+			for _, output := range outputs {
+				p.Add(input, output, false)
+			}
+		}
+		return nil
+	}
+
+	inputs, err := p.analyzeAll(split(line, ","))
+	if err != nil {
+		return err
+	}
+	if p.expand {
+		// all pairs
+		for i, input := range inputs {
+			for j, output := range inputs {
+				if i != j {
+					p.Add(input, output, true)
+				}
+			}
+		}
+	} else {
+		// all subsequent inputs map to first one; we also add inputs[0]
+		// here so that we "effectively" (because we remove the original
+		// input and add back a synonym with the same text) change that
+		// token's type to SYNONYM (matching legacy behavior):
+		for _, input := range inputs {
+			p.Add(input, inputs[0], false)
+		}
+	}
+	return nil
+}
+
+func (p *SolrSynonymParser) analyzeAll(texts []string) ([]string, error) {
+	ans := make([]string, len(texts))
+	for i, text := range texts {
+		var err error
+		if ans[i], err = p.Analyze(unescape(text)); err != nil {
+			return nil, err
+		}
+	}
+	return ans, nil
+}
+
+/*
+Splits s on the given separator, keeping backslash escapes and
+trimming the parts.
+*/
+func split(s, separator string) []string {
+	var list []string
+	var sb []byte
+	pos, end := 0, len(s)
+	for pos < end {
+		if strings.HasPrefix(s[pos:], separator) {
+			if len(sb) > 0 {
+				list = append(list, strings.TrimSpace(string(sb)))
+				sb = sb[:0]
+			}
+			pos += len(separator)
+			continue
+		}
+
+		ch := s[pos]
+		pos++
+		if ch == '\\' {
+			sb = append(sb, ch)
+			if pos >= end {
+				break // ERROR, or let it go?
+			}
+			ch = s[pos]
+			pos++
+		}
+		sb = append(sb, ch)
+	}
+	if len(sb) > 0 {
+		list = append(list, strings.TrimSpace(string(sb)))
+	}
+	return list
+}
+
+/* Removes backslash escapes. */
+func unescape(s string) string {
+	if strings.IndexByte(s, '\\') < 0 {
+		return s
+	}
+	sb := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		if ch == '\\' && i < len(s)-1 {
+			i++
+			ch = s[i]
+		}
+		sb = append(sb, ch)
+	}
+	return string(sb)
+}
@@ -0,0 +1,323 @@
+package synonym
+
+import (
+	. "github.com/balzaczyy/golucene/core/analysis"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"github.com/balzaczyy/golucene/core/util"
+	"github.com/balzaczyy/golucene/core/util/fst"
+	"strings"
+	"unicode"
+)
+
+// synonym/SynonymGraphFilter.java
+
+/* Token type for synonyms emitted by SynonymGraphFilter. */
+const TYPE_SYNONYM = "SYNONYM"
+
+/*
+Applies single- or multi-token synonyms from a SynonymMap to an
+incoming TokenStream, producing a fully correct graph output. This is
+a replacement for SynonymFilter, which produces incorrect graphs for
+multi-token synonyms.
+
+Matching is greedy: at each position the longest input phrase with
+synonyms is replaced. All synonym paths leave from the position of the
+first matched token and merge back after the last one; positions in
+between are allocated to the words of multi-word synonyms, and each
+token's PositionLengthAttribute spans to the node where its path
+continues. If the rule keeps the original, the original tokens are
+emitted as one more path, after the synonyms.
+
+However, if you use this during indexing, you must follow it with
+FlattenGraphFilter to squash tokens on top of one another like
+SynonymFilter, because the indexer can't directly consume a graph. To
+get fully correct positional queries when your synonym replacements
+are multiple tokens, you should instead apply synonyms using this
+TokenFilter at query time and translate the resulting graph to a
+TermAutomatonQuery e.g. using TokenStreamToTermAutomatonQuery.
+
+NOTE: this cannot consume an incoming graph; results will be
+undefined. Matching stops at holes and stacked tokens.
+*/
+type SynonymGraphFilter struct {
+	*TokenFilter
+	input      TokenStream
+	synonyms   *SynonymMap
+	ignoreCase bool
+
+	fstReader  fst.BytesReader
+	scratchArc *fst.Arc
+
+	termAtt    CharTermAttribute
+	posIncrAtt PositionIncrementAttribute
+	posLenAtt  PositionLengthAttribute
+	typeAtt    TypeAttribute
+	offsetAtt  OffsetAttribute
+
+	// input tokens pulled ahead for matching, but not yet consumed
+	lookahead []*bufferedInputToken
+	// tokens ready to be released, in node order
+	outputBuffer []*bufferedOutputToken
+	// true once the input has no more tokens
+	finished bool
+
+	// node of the last token we returned, and the node the next
+	// token will leave from
+	lastNodeOut, nextNodeOut int
+}
+
+type bufferedInputToken struct {
+	state                  *util.AttributeState
+	term                   []rune
+	startOffset, endOffset int
+	posInc                 int
+}
+
+type bufferedOutputToken struct {
+	// original token to restore, or nil for a synonym word
+	state *util.AttributeState
+	term  string
+	// offsets of a synonym word
+	startOffset, endOffset int
+	// where this token leaves from, and where it arrives to
+	startNode, endNode int
+}
+
+/*
+Apply previously built synonyms to incoming tokens. If ignoreCase is
+true, input is lowercased with unicode.ToLower() before matching; the
+synonym map must then hold lowercased inputs.
+*/
+func NewSynonymGraphFilter(in TokenStream, synonyms *SynonymMap, ignoreCase bool) *SynonymGraphFilter {
+	assert2(synonyms.Fst != nil, "fst must be non-null")
+	ans := &SynonymGraphFilter{
+		TokenFilter: NewTokenFilter(in),
+		input:       in,
+		synonyms:    synonyms,
+		ignoreCase:  ignoreCase,
+		fstReader:   synonyms.Fst.BytesReader(),
+		scratchArc:  new(fst.Arc),
+		lastNodeOut: -1,
+	}
+	ans.termAtt = ans.Attributes().Add("CharTermAttribute").(CharTermAttribute)
+	ans.posIncrAtt = ans.Attributes().Add("PositionIncrementAttribute").(PositionIncrementAttribute)
+	ans.posLenAtt = ans.Attributes().Add("PositionLengthAttribute").(PositionLengthAttribute)
+	ans.typeAtt = ans.Attributes().Add("TypeAttribute").(TypeAttribute)
+	ans.offsetAtt = ans.Attributes().Add("OffsetAttribute").(OffsetAttribute)
+	return ans
+}
+
+func (f *SynonymGraphFilter) IncrementToken() (bool, error) {
+	for len(f.outputBuffer) == 0 {
+		if ok, err := f.fill(1); !ok || err != nil {
+			return false, err
+		}
+
+		matchLength, output, err := f.match()
+		if err != nil {
+			return false, err
+		}
+		if matchLength == 0 {
+			// no synonyms here: pass the token through
+			f.releasePassThrough()
+			return true, nil
+		}
+		f.bufferOutputTokens(f.lookahead[:matchLength], output)
+		f.lookahead = f.lookahead[matchLength:]
+	}
+
+	token := f.outputBuffer[0]
+	f.outputBuffer = f.outputBuffer[1:]
+	if token.state != nil {
+		f.Attributes().RestoreState(token.state)
+	} else {
+		f.Attributes().Clear()
+		f.termAtt.CopyBuffer([]rune(token.term))
+		f.typeAtt.SetType(TYPE_SYNONYM)
+		f.offsetAtt.SetOffset(token.startOffset, token.endOffset)
+	}
+	f.posIncrAtt.SetPositionIncrement(token.startNode - f.lastNodeOut)
+	f.posLenAtt.SetPositionLength(token.endNode - token.startNode)
+	f.lastNodeOut = token.startNode
+	return true, nil
+}
+
+/* Returns the first lookahead token, adjusting its position for any preceding synonym graph. */
+func (f *SynonymGraphFilter) releasePassThrough() {
+	token := f.lookahead[0]
+	f.lookahead = f.lookahead[1:]
+	f.Attributes().RestoreState(token.state)
+
+	node := f.nodeFor(token)
+	f.posIncrAtt.SetPositionIncrement(node - f.lastNodeOut)
+	f.lastNodeOut = node
+	if node >= f.nextNodeOut {
+		f.nextNodeOut = node + 1
+	}
+}
+
+/*
+Returns the node an input token leaves from: the next free node,
+moved forward over holes, or back onto the last node if the token is
+stacked.
+*/
+func (f *SynonymGraphFilter) nodeFor(token *bufferedInputToken) int {
+	node := f.nextNodeOut + token.posInc - 1
+	if node < f.lastNodeOut {
+		node = f.lastNodeOut
+	}
+	return node
+}
+
+/* Pulls input tokens until the lookahead holds n tokens; returns false if the input ended first. */
+func (f *SynonymGraphFilter) fill(n int) (bool, error) {
+	for len(f.lookahead) < n && !f.finished {
+		ok, err := f.input.IncrementToken()
+		if err != nil {
+			return false, err
+		}
+		if !ok {
+			f.finished = true
+			break
+		}
+
+		term := make([]rune, f.termAtt.Length())
+		copy(term, f.termAtt.Buffer())
+		if f.ignoreCase {
+			for i, ch := range term {
+				term[i] = unicode.ToLower(ch)
+			}
+		}
+		f.lookahead = append(f.lookahead, &bufferedInputToken{
+			state:       f.Attributes().CaptureState(),
+			term:        term,
+			startOffset: f.offsetAtt.StartOffset(),
+			endOffset:   f.offsetAtt.EndOffset(),
+			posInc:      f.posIncrAtt.PositionIncrement(),
+		})
+	}
+	return len(f.lookahead) >= n, nil
+}
+
+/*
+Finds the longest input phrase with synonyms starting at the first
+lookahead token. Returns its number of tokens (0 if there is no match)
+and the FST output.
+*/
+func (f *SynonymGraphFilter) match() (matchLength int, matchOutput []byte, err error) {
+	fst_ := f.synonyms.Fst
+	outputs := fst_.Outputs()
+	arc := fst_.FirstArc(f.scratchArc)
+	output := outputs.NoOutput()
+
+	for pos := 0; ; pos++ {
+		ok, err := f.fill(pos + 1)
+		if err != nil {
+			return 0, nil, err
+		}
+		if !ok || pos > 0 && f.lookahead[pos].posInc != 1 {
+			break
+		}
+
+		if pos > 0 {
+			if found, err := fst_.FindTargetArc(WORD_SEPARATOR, arc, arc, f.fstReader); err != nil {
+				return 0, nil, err
+			} else if found == nil {
+				break
+			}
+			output = outputs.Add(output, arc.Output)
+		}
+
+		matched := true
+		for _, ch := range f.lookahead[pos].term {
+			if found, err := fst_.FindTargetArc(int(ch), arc, arc, f.fstReader); err != nil {
+				return 0, nil, err
+			} else if found == nil {
+				matched = false
+				break
+			}
+			output = outputs.Add(output, arc.Output)
+		}
+		if !matched {
+			break
+		}
+
+		if arc.IsFinal() {
+			matchLength = pos + 1
+			matchOutput = outputs.Add(output, arc.NextFinalOutput).([]byte)
+		}
+	}
+	return
+}
+
+/* Expands a matched input phrase into the synonym graph, buffering its tokens. */
+func (f *SynonymGraphFilter) bufferOutputTokens(matched []*bufferedInputToken, output []byte) {
+	keepOrig, ords := f.synonyms.decode(output)
+
+	var paths [][]*bufferedOutputToken
+	startOffset, endOffset := matched[0].startOffset, matched[len(matched)-1].endOffset
+	for _, ord := range ords {
+		var path []*bufferedOutputToken
+		for _, word := range strings.Split(f.synonyms.Words[ord], string(rune(WORD_SEPARATOR))) {
+			path = append(path, &bufferedOutputToken{
+				term:        word,
+				startOffset: startOffset,
+				endOffset:   endOffset,
+			})
+		}
+		paths = append(paths, path)
+	}
+	if keepOrig {
+		var path []*bufferedOutputToken
+		for _, token := range matched {
+			path = append(path, &bufferedOutputToken{state: token.state})
+		}
+		paths = append(paths, path)
+	}
+
+	// How many nodes along all paths; we need this to assign the node
+	// ID for the final end node where all paths merge back:
+	totalPathNodes := 0
+	for _, path := range paths {
+		totalPathNodes += len(path) - 1
+	}
+
+	startNode := f.nodeFor(matched[0])
+	endNode := startNode + totalPathNodes + 1
+
+	// First, fanout all tokens departing the start node for these new
+	// side paths, then the rest of each path in turn, so that tokens
+	// are released in node order:
+	newNodeCount := 0
+	var rest []*bufferedOutputToken
+	for _, path := range paths {
+		for i, token := range path {
+			if i == 0 {
+				token.startNode = startNode
+			} else {
+				newNodeCount++
+				token.startNode = startNode + newNodeCount
+				rest = append(rest, token)
+			}
+			if i == len(path)-1 {
+				token.endNode = endNode
+			} else {
+				token.endNode = startNode + newNodeCount + 1
+			}
+		}
+		f.outputBuffer = append(f.outputBuffer, path[0])
+	}
+	f.outputBuffer = append(f.outputBuffer, rest...)
+	f.nextNodeOut = endNode
+}
+
+func (f *SynonymGraphFilter) Reset() error {
+	if err := f.TokenFilter.Reset(); err != nil {
+		return err
+	}
+	f.lookahead = nil
+	f.outputBuffer = nil
+	f.finished = false
+	f.lastNodeOut, f.nextNodeOut = -1, 0
+	return nil
+}
@@ -0,0 +1,274 @@
+package synonym
+
+import (
+	"fmt"
+	. "github.com/balzaczyy/golucene/core/analysis"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"github.com/balzaczyy/golucene/core/store"
+	"github.com/balzaczyy/golucene/core/util"
+	"github.com/balzaczyy/golucene/core/util/fst"
+	"github.com/balzaczyy/golucene/core/util/packed"
+	"io"
+	"math"
+	"sort"
+	"strings"
+)
+
+// synonym/SynonymMap.java
+
+/* for multiword support, you must separate words with this separator */
+const WORD_SEPARATOR = 0
+
+/* A map of synonyms, keys and values are phrases. */
+type SynonymMap struct {
+	// map<input word, list<ord>>
+	Fst *fst.FST
+	// map<ord, outputword>
+	Words []string
+	// maxHorizontalContext: maximum context we need on the tokenstream
+	MaxHorizontalContext int
+}
+
+/*
+Decodes the output of the FST for an input phrase: whether the
+original tokens should be kept, and the ords of the output phrases.
+*/
+func (m *SynonymMap) decode(output []byte) (keepOrig bool, ords []int) {
+	in := store.NewByteArrayDataInput(output)
+	code, _ := in.ReadVInt()
+	keepOrig = code&1 == 0
+	ords = make([]int, code>>1)
+	for i := range ords {
+		ord, _ := in.ReadVInt()
+		ords[i] = int(ord)
+	}
+	return
+}
+
+/* Joins multiple words together into a single phrase, separated by WORD_SEPARATOR. */
+func Join(words ...string) string {
+	return strings.Join(words, string(rune(WORD_SEPARATOR)))
+}
+
+type synonymMapEntry struct {
+	includeOrig bool
+	// we could sort for better sharing ultimately, but it could confuse users
+	ords []int
+}
+
+/*
+Builds an FSTSynonymMap.
+
+Call Add() until you have added all the mappings, then call Build()
+to get an FSTSynonymMap.
+*/
+type SynonymMapBuilder struct {
+	workingSet           map[string]*synonymMapEntry
+	words                map[string]int
+	ords                 []string
+	maxHorizontalContext int
+	dedup                bool
+}
+
+/*
+If dedup is true then identical rules (same input, same output) will
+be added only once.
+*/
+func NewSynonymMapBuilder(dedup bool) *SynonymMapBuilder {
+	return &SynonymMapBuilder{
+		workingSet: make(map[string]*synonymMapEntry),
+		words:      make(map[string]int),
+		dedup:      dedup,
+	}
+}
+
+/* Only used for asserting! */
+func hasHoles(phrase string) bool {
+	sep := string(rune(WORD_SEPARATOR))
+	return strings.HasPrefix(phrase, sep) || strings.HasSuffix(phrase, sep) ||
+		strings.Contains(phrase, sep+sep)
+}
+
+func countWords(phrase string) int {
+	return strings.Count(phrase, string(rune(WORD_SEPARATOR))) + 1
+}
+
+/*
+Add a phrase->phrase synonym mapping. Phrases are character sequences
+where words are separated with character zero (WORD_SEPARATOR). Input
+and output must not be empty, and must not contain empty words.
+
+If includeOrig is true, the original tokens are emitted along with
+the synonyms.
+*/
+func (b *SynonymMapBuilder) Add(input, output string, includeOrig bool) {
+	assert2(input != "", "input must not be empty")
+	assert2(output != "", "output must not be empty")
+	assert2(!hasHoles(input), "input has holes: %q", input)
+	assert2(!hasHoles(output), "output has holes: %q", output)
+
+	numInputWords, numOutputWords := countWords(input), countWords(output)
+	if numInputWords > b.maxHorizontalContext {
+		b.maxHorizontalContext = numInputWords
+	}
+	if numOutputWords > b.maxHorizontalContext {
+		b.maxHorizontalContext = numOutputWords
+	}
+
+	ord, ok := b.words[output]
+	if !ok {
+		ord = len(b.ords)
+		b.words[output] = ord
+		b.ords = append(b.ords, output)
+	}
+
+	e, ok := b.workingSet[input]
+	if !ok {
+		e = new(synonymMapEntry)
+		b.workingSet[input] = e
+	}
+	e.ords = append(e.ords, ord)
+	e.includeOrig = e.includeOrig || includeOrig
+}
+
+/* Builds a SynonymMap and returns it. */
+func (b *SynonymMapBuilder) Build() (*SynonymMap, error) {
+	outputs := fst.ByteSequenceOutputsSingleton()
+	// TODO: are we using the best sharing options?
+	builder := fst.NewBuilder(fst.INPUT_TYPE_BYTE4, 0, 0, true, true,
+		math.MaxInt32, outputs, false, packed.PackedInts.COMPACT, true, 15)
+	scratchInts := util.NewIntsRefBuilder()
+
+	// sort the input keys: the FST needs its inputs in code point
+	// order, which is also the byte order of UTF-8 strings
+	keys := make([]string, 0, len(b.workingSet))
+	for input := range b.workingSet {
+		keys = append(keys, input)
+	}
+	sort.Strings(keys)
+
+	for _, input := range keys {
+		e := b.workingSet[input]
+		ords := e.ords
+		if b.dedup {
+			seen := make(map[int]bool, len(ords))
+			ords = make([]int, 0, len(e.ords))
+			for _, ord := range e.ords {
+				if !seen[ord] {
+					seen[ord] = true
+					ords = append(ords, ord)
+				}
+			}
+		}
+
+		// output size, assume the worst case
+		scratch := make([]byte, 5+5*len(ords))
+		out := store.NewByteArrayDataOutput(scratch)
+		code := len(ords) << 1
+		if !e.includeOrig {
+			code |= 1
+		}
+		if err := out.WriteVInt(int32(code)); err != nil {
+			return nil, err
+		}
+		for _, ord := range ords {
+			if err := out.WriteVInt(int32(ord)); err != nil {
+				return nil, err
+			}
+		}
+
+		scratchInts.Clear()
+		for _, ch := range input {
+			scratchInts.Append(int(ch))
+		}
+		if err := builder.Add(scratchInts.Get(), scratch[:out.Position()]); err != nil {
+			return nil, err
+		}
+	}
+
+	f, err := builder.Finish()
+	if err != nil {
+		return nil, err
+	}
+	return &SynonymMap{f, b.ords, b.maxHorizontalContext}, nil
+}
+
+// synonym/SynonymMap.java#Parser
+
+/* Abstraction for parsing synonym files. */
+type Parser interface {
+	// Parse the given input, adding synonyms to the inherited Builder.
+	Parse(in io.Reader) error
+	// Builds the SynonymMap from the parsed rules.
+	Build() (*SynonymMap, error)
+}
+
+/*
+Base for synonym parsers: a SynonymMapBuilder along with the analyzer
+used to normalize the words of the rules.
+*/
+type ParserBase struct {
+	*SynonymMapBuilder
+	analyzer Analyzer
+}
+
+func NewParserBase(dedup bool, analyzer Analyzer) *ParserBase {
+	return &ParserBase{NewSynonymMapBuilder(dedup), analyzer}
+}
+
+/*
+Sugar: analyzes the text with the analyzer and separates by
+WORD_SEPARATOR. Returns an error if the text is completely eliminated
+by the analyzer, or if the analyzer produces a stacked token or a
+hole.
+*/
+func (p *ParserBase) Analyze(text string) (ans string, err error) {
+	ts, err := p.analyzer.TokenStreamForString("", text)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if err2 := ts.Close(); err == nil {
+			err = err2
+		}
+	}()
+
+	termAtt := ts.Attributes().Add("CharTermAttribute").(CharTermAttribute)
+	posIncAtt := ts.Attributes().Add("PositionIncrementAttribute").(PositionIncrementAttribute)
+	if err = ts.Reset(); err != nil {
+		return "", err
+	}
+	var words []string
+	for {
+		ok, err := ts.IncrementToken()
+		if err != nil {
+			return "", err
+		}
+		if !ok {
+			break
+		}
+		length := termAtt.Length()
+		if length == 0 {
+			return "", fmt.Errorf("term: %v analyzed to a zero-length token", text)
+		}
+		if posIncAtt.PositionIncrement() != 1 {
+			return "", fmt.Errorf(
+				"term: %v analyzed to a token (%v) with position increment != 1 (got: %v)",
+				text, string(termAtt.Buffer()[:length]), posIncAtt.PositionIncrement())
+		}
+		words = append(words, string(termAtt.Buffer()[:length]))
+	}
+	if err = ts.End(); err != nil {
+		return "", err
+	}
+	if len(words) == 0 {
+		return "", fmt.Errorf("term: %v was completely eliminated by analyzer", text)
+	}
+	return Join(words...), nil
+}
+
+func assert2(ok bool, msg string, args ...interface{}) {
+	if !ok {
+		panic(fmt.Sprintf(msg, args...))
+	}
+}
@@ -0,0 +1,179 @@
+package synonym
+
+import (
+	"fmt"
+	"github.com/balzaczyy/golucene/analysis/core"
+	std "github.com/balzaczyy/golucene/analysis/standard"
+	. "github.com/balzaczyy/golucene/core/analysis"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"github.com/balzaczyy/golucene/core/util"
+	"strings"
+	"testing"
+)
+
+/* Returns the tokens as term/posInc/posLen[/offsets], separated by spaces. */
+func graph(t *testing.T, ts TokenStream, withOffsets bool) string {
+	termAtt := ts.Attributes().Get("CharTermAttribute").(CharTermAttribute)
+	posIncAtt := ts.Attributes().Get("PositionIncrementAttribute").(PositionIncrementAttribute)
+	posLenAtt := ts.Attributes().Get("PositionLengthAttribute").(PositionLengthAttribute)
+	offsetAtt := ts.Attributes().Get("OffsetAttribute").(OffsetAttribute)
+	if err := ts.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	var tokens []string
+	for {
+		ok, err := ts.IncrementToken()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			break
+		}
+		token := fmt.Sprintf("%v/%v/%v", string(termAtt.Buffer()[:termAtt.Length()]),
+			posIncAtt.PositionIncrement(), posLenAtt.PositionLength())
+		if withOffsets {
+			token += fmt.Sprintf("/%v-%v", offsetAtt.StartOffset(), offsetAtt.EndOffset())
+		}
+		tokens = append(tokens, token)
+	}
+	if err := ts.End(); err != nil {
+		t.Fatal(err)
+	}
+	if err := ts.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return strings.Join(tokens, " ")
+}
+
+func synonymGraph(t *testing.T, synonyms *SynonymMap, ignoreCase bool, text string) string {
+	var ts TokenStream = std.NewStandardTokenizer(util.VERSION_LATEST, strings.NewReader(text))
+	return graph(t, NewSynonymGraphFilter(ts, synonyms, ignoreCase), false)
+}
+
+func TestSynonymGraphFilter(t *testing.T) {
+	b := NewSynonymMapBuilder(true)
+	b.Add("a", "x", true)
+	b.Add(Join("b", "c"), "bc", false)
+	b.Add(Join("b", "c", "d"), Join("big", "cat"), true)
+	b.Add("wtf", Join("what", "the", "fudge"), false)
+	b.Add("wtf", "wtf", false)
+	b.Add("e", "e", false)
+	synonyms, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, v := range []struct{ text, expected string }{
+		{"z a y", "z/1/1 x/1/1 a/0/1 y/1/1"},
+		{"b c", "bc/1/1"},
+		{"b c e", "bc/1/1 e/1/1"},
+		// longest match wins, and the original path follows the synonym
+		{"z b c d z", "z/1/1 big/1/1 b/0/2 cat/1/3 c/1/1 d/1/1 z/1/1"},
+		{"wtf happened", "what/1/1 wtf/0/3 the/1/1 fudge/1/1 happened/1/1"},
+		{"b z", "b/1/1 z/1/1"},
+		{"B C", "B/1/1 C/1/1"},
+	} {
+		if got := synonymGraph(t, synonyms, false, v.text); got != v.expected {
+			t.Errorf("%q: expected %v, but was %v", v.text, v.expected, got)
+		}
+	}
+	if got := synonymGraph(t, synonyms, true, "B C"); got != "bc/1/1" {
+		t.Errorf("ignore case: %v", got)
+	}
+
+	// synonyms span the offsets of the whole match
+	ts := NewSynonymGraphFilter(
+		std.NewStandardTokenizer(util.VERSION_LATEST, strings.NewReader("x b c y")), synonyms, false)
+	if got := graph(t, ts, true); got != "x/1/1/0-1 bc/1/1/2-5 y/1/1/6-7" {
+		t.Errorf("offsets: %v", got)
+	}
+}
+
+func lowerCaseAnalyzer() Analyzer {
+	return std.NewStandardAnalyzerWithStopWords(nil)
+}
+
+func TestSolrSynonymParser(t *testing.T) {
+	rules := `# a comment
+i-pod, i pod => ipod
+sea biscuit, sea biscit => seabiscuit
+
+# equivalent synonyms
+Television, Televisions, TV, TVs
+foo => foo bar
+foo => baz
+a\=>a => b\=>b
+a\,a => b\,b
+`
+	p := NewSolrSynonymParser(true, true, lowerCaseAnalyzer())
+	if err := p.Parse(strings.NewReader(rules)); err != nil {
+		t.Fatal(err)
+	}
+	synonyms, err := p.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	analyze := func(text string) string {
+		var ts TokenStream = std.NewStandardTokenizer(util.VERSION_LATEST, strings.NewReader(text))
+		ts = core.NewLowerCaseFilter(util.VERSION_LATEST, ts)
+		return graph(t, NewSynonymGraphFilter(ts, synonyms, false), false)
+	}
+	for _, v := range []struct{ text, expected string }{
+		{"i pod", "ipod/1/1"},
+		{"i-pod", "ipod/1/1"},
+		{"sea biscit", "seabiscuit/1/1"},
+		{"tv", "television/1/1 televisions/0/1 tvs/0/1 tv/0/1"},
+		{"foo", "foo/1/1 baz/0/2 bar/1/1"},
+	} {
+		if got := analyze(v.text); got != v.expected {
+			t.Errorf("%q: expected %v, but was %v", v.text, v.expected, got)
+		}
+	}
+
+	p = NewSolrSynonymParser(true, false, lowerCaseAnalyzer())
+	if err := p.Parse(strings.NewReader("a, b, c")); err != nil {
+		t.Fatal(err)
+	}
+	if synonyms, err = p.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if got := analyze("c b"); got != "a/1/1 a/1/1" {
+		t.Errorf("no expansion: %v", got)
+	}
+
+	for _, rules := range []string{"a => b => c", "a, the => b"} {
+		p := NewSolrSynonymParser(true, true, std.NewStandardAnalyzer())
+		if err := p.Parse(strings.NewReader("x => y\n" + rules)); err == nil ||
+			!strings.HasPrefix(err.Error(), "Invalid synonym rule at line 2") {
+			t.Errorf("%q: expected error, but was %v", rules, err)
+		}
+	}
+}
+
+func TestWordnetSynonymParser(t *testing.T) {
+	rules := `s(100000001,1,'woods',n,1,0).
+s(100000001,2,'wood',n,1,0).
+s(100000001,3,'forest',n,1,0).
+s(100000002,1,'wolfish',n,1,0).
+s(100000002,2,'ravenous',n,1,0).
+s(100000003,1,'king''s evil',n,1,1).
+s(100000003,2,'king''s meany',n,1,1).
+`
+	p := NewWordnetSynonymParser(true, true, lowerCaseAnalyzer())
+	if err := p.Parse(strings.NewReader(rules)); err != nil {
+		t.Fatal(err)
+	}
+	synonyms, err := p.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []struct{ text, expected string }{
+		{"forest", "woods/1/1 wood/0/1 forest/0/1"},
+		{"ravenous", "wolfish/1/1 ravenous/0/1"},
+		{"king's evil", "king's/1/1 king's/0/2 meany/1/2 evil/1/1"},
+	} {
+		if got := synonymGraph(t, synonyms, false, v.text); got != v.expected {
+			t.Errorf("%q: expected %v, but was %v", v.text, v.expected, got)
+		}
+	}
+}
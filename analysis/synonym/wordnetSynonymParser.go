@@ -0,0 +1,86 @@
+package synonym
+
+import (
+	"bufio"
+	"fmt"
+	. "github.com/balzaczyy/golucene/core/analysis"
+	"io"
+	"strings"
+)
+
+// synonym/WordnetSynonymParser.java
+
+/*
+Parser for wordnet prolog format.
+
+See http://wordnet.princeton.edu/man/prologdb.5WN.html for a
+description of the format.
+*/
+type WordnetSynonymParser struct {
+	*ParserBase
+	expand bool
+}
+
+func NewWordnetSynonymParser(dedup, expand bool, analyzer Analyzer) *WordnetSynonymParser {
+	return &WordnetSynonymParser{NewParserBase(dedup, analyzer), expand}
+}
+
+func (p *WordnetSynonymParser) Parse(in io.Reader) error {
+	var synset []string
+	var lastSynSetID string
+	scanner := bufio.NewScanner(in)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := scanner.Text()
+		if len(line) < 11 {
+			return fmt.Errorf("Invalid synonym rule at line %v: %v", lineNumber, line)
+		}
+		synSetID := line[2:11]
+		if synSetID != lastSynSetID {
+			p.addSynset(synset)
+			synset = synset[:0]
+		}
+
+		synonym, err := p.parseSynonym(line)
+		if err != nil {
+			return fmt.Errorf("Invalid synonym rule at line %v: %v", lineNumber, err)
+		}
+		synset = append(synset, synonym)
+		lastSynSetID = synSetID
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	// final synset in the file
+	p.addSynset(synset)
+	return nil
+}
+
+func (p *WordnetSynonymParser) parseSynonym(line string) (string, error) {
+	start, end := strings.IndexByte(line, '\'')+1, strings.LastIndexByte(line, '\'')
+	if start <= 0 || end < start {
+		return "", fmt.Errorf("no quoted word in %v", line)
+	}
+	text := strings.Replace(line[start:end], "''", "'", -1)
+	return p.Analyze(text)
+}
+
+func (p *WordnetSynonymParser) addSynset(synset []string) {
+	if len(synset) <= 1 {
+		return // nothing to do
+	}
+
+	if p.expand {
+		for i, input := range synset {
+			for j, output := range synset {
+				if i != j {
+					p.Add(input, output, true)
+				}
+			}
+		}
+	} else {
+		for _, input := range synset {
+			p.Add(input, synset[0], false)
+		}
+	}
+}
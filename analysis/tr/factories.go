@@ -0,0 +1,35 @@
+package tr
+
+import (
+	. "github.com/balzaczyy/golucene/analysis/util"
+	. "github.com/balzaczyy/golucene/core/analysis"
+)
+
+func init() {
+	RegisterTokenFilterFactory("turkishLowercase", func(args map[string]string) (TokenFilterFactory, error) {
+		f, err := NewTurkishLowerCaseFilterFactory(args)
+		if err != nil {
+			return nil, err
+		}
+		return f, nil
+	})
+}
+
+// tr/TurkishLowerCaseFilterFactory.java
+
+/* Factory for TurkishLowerCaseFilter. */
+type TurkishLowerCaseFilterFactory struct {
+	*AbstractAnalysisFactory
+}
+
+func NewTurkishLowerCaseFilterFactory(args map[string]string) (*TurkishLowerCaseFilterFactory, error) {
+	ans := &TurkishLowerCaseFilterFactory{NewAbstractAnalysisFactory(args)}
+	if err := ans.Validate(); err != nil {
+		return nil, err
+	}
+	return ans, nil
+}
+
+func (f *TurkishLowerCaseFilterFactory) Create(input TokenStream) TokenStream {
+	return NewTurkishLowerCaseFilter(input)
+}
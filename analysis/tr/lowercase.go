@@ -0,0 +1,86 @@
+package tr
+
+import (
+	. "github.com/balzaczyy/golucene/core/analysis"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"unicode"
+)
+
+// tr/TurkishLowerCaseFilter.java
+
+const (
+	LATIN_CAPITAL_LETTER_I       = 'I'
+	LATIN_SMALL_LETTER_I         = 'i'
+	LATIN_SMALL_LETTER_DOTLESS_I = 'ı'
+	COMBINING_DOT_ABOVE          = '̇'
+)
+
+/*
+Normalizes Turkish token text to lower case.
+
+Turkish and Azeri have unique casing behavior for some characters.
+This filter applies Turkish lowercase rules. For more information, see
+http://en.wikipedia.org/wiki/Turkish_dotted_and_dotless_I
+*/
+type TurkishLowerCaseFilter struct {
+	*TokenFilter
+	input   TokenStream
+	termAtt CharTermAttribute
+}
+
+/* Create a new TurkishLowerCaseFilter, that normalizes Turkish token text to lower case. */
+func NewTurkishLowerCaseFilter(in TokenStream) *TurkishLowerCaseFilter {
+	ans := &TurkishLowerCaseFilter{
+		TokenFilter: NewTokenFilter(in),
+		input:       in,
+	}
+	ans.termAtt = ans.Attributes().Add("CharTermAttribute").(CharTermAttribute)
+	return ans
+}
+
+func (f *TurkishLowerCaseFilter) IncrementToken() (bool, error) {
+	ok, err := f.input.IncrementToken()
+	if err != nil || !ok {
+		return false, err
+	}
+	buffer := f.termAtt.Buffer()
+	length := f.termAtt.Length()
+	for i := 0; i < length; i++ {
+		ch := buffer[i]
+		if ch != LATIN_CAPITAL_LETTER_I {
+			buffer[i] = unicode.TurkishCase.ToLower(ch)
+			continue
+		}
+		if !isBeforeDot(buffer[i+1 : length]) {
+			buffer[i] = LATIN_SMALL_LETTER_DOTLESS_I
+			continue
+		}
+		// I followed by COMBINING_DOT_ABOVE is a dotted i: drop the dot
+		buffer[i] = LATIN_SMALL_LETTER_I
+		for j := i + 1; j < length; j++ {
+			if buffer[j] == COMBINING_DOT_ABOVE {
+				copy(buffer[j:], buffer[j+1:length])
+				length--
+				break
+			}
+		}
+	}
+	f.termAtt.SetLength(length)
+	return true, nil
+}
+
+/*
+Lookahead for a COMBINING_DOT_ABOVE, ignoring the other non-spacing
+marks in between.
+*/
+func isBeforeDot(s []rune) bool {
+	for _, ch := range s {
+		if ch == COMBINING_DOT_ABOVE {
+			return true
+		}
+		if !unicode.Is(unicode.Mn, ch) {
+			return false
+		}
+	}
+	return false
+}
@@ -0,0 +1,46 @@
+package tr
+
+import (
+	"fmt"
+	"github.com/balzaczyy/golucene/analysis/core"
+	. "github.com/balzaczyy/golucene/core/analysis"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"strings"
+	"testing"
+)
+
+func assertTerms(t *testing.T, ts TokenStream, expected ...string) {
+	termAtt := ts.Attributes().Add("CharTermAttribute").(CharTermAttribute)
+	if err := ts.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	var terms []string
+	for {
+		ok, err := ts.IncrementToken()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			break
+		}
+		terms = append(terms, string(termAtt.Buffer()[:termAtt.Length()]))
+	}
+	ts.End()
+	ts.Close()
+	if fmt.Sprint(terms) != fmt.Sprint(expected) {
+		t.Errorf("expected %q, but was %q", expected, terms)
+	}
+}
+
+func TestTurkishLowerCaseFilter(t *testing.T) {
+	for input, expected := range map[string]string{
+		"AĞACI":                "ağacı",
+		"\u0130STANBUL":        "istanbul",       // precomposed dotted I
+		"I\u0307STANBUL":       "istanbul",       // decomposed dotted I
+		"I\u0316\u0307STANBUL": "i\u0316stanbul", // the dot follows other marks
+		"\u0130\u0307":         "i\u0307",
+		"A\u0307":              "a\u0307",
+	} {
+		assertTerms(t, NewTurkishLowerCaseFilter(core.NewKeywordTokenizer(strings.NewReader(input))), expected)
+	}
+}
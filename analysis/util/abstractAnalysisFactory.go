@@ -0,0 +1,231 @@
+package util
+
+import (
+	"fmt"
+	"github.com/balzaczyy/golucene/core/util"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// util/AbstractAnalysisFactory.java
+
+const LUCENE_MATCH_VERSION_PARAM = "luceneMatchVersion"
+
+/*
+Abstract parent class for analysis factories TokenizerFactory,
+TokenFilterFactory and CharFilterFactory.
+
+The typical lifecycle for a factory consumer is:
+
+	- Create factory via its constructor (or via the registry)
+	- Factory consumes its parameters with Require() and Get*()
+	- Factory constructor returns Validate(), which reports missing,
+	  invalid and unknown parameters
+*/
+type AbstractAnalysisFactory struct {
+	originalArgs       map[string]string
+	args               map[string]string
+	luceneMatchVersion util.Version
+	err                error
+}
+
+/* Initialize this factory via a set of key-value pairs. */
+func NewAbstractAnalysisFactory(args map[string]string) *AbstractAnalysisFactory {
+	ans := &AbstractAnalysisFactory{
+		originalArgs:       make(map[string]string),
+		args:               make(map[string]string),
+		luceneMatchVersion: util.VERSION_LATEST,
+	}
+	for k, v := range args {
+		ans.originalArgs[k] = v
+		ans.args[k] = v
+	}
+	if v, ok := ans.args[LUCENE_MATCH_VERSION_PARAM]; ok {
+		delete(ans.args, LUCENE_MATCH_VERSION_PARAM)
+		version, err := util.ParseVersion(v)
+		if err != nil {
+			ans.fail("Invalid %v '%v': %v", LUCENE_MATCH_VERSION_PARAM, v, err)
+		}
+		ans.luceneMatchVersion = version
+	}
+	return ans
+}
+
+func (f *AbstractAnalysisFactory) OriginalArgs() map[string]string {
+	return f.originalArgs
+}
+
+func (f *AbstractAnalysisFactory) LuceneMatchVersion() util.Version {
+	return f.luceneMatchVersion
+}
+
+/* Records the first error met consuming the parameters. */
+func (f *AbstractAnalysisFactory) fail(format string, args ...interface{}) {
+	if f.err == nil {
+		f.err = fmt.Errorf(format, args...)
+	}
+}
+
+/* Consumes the parameter name, recording an error if it is missing. */
+func (f *AbstractAnalysisFactory) Require(name string) string {
+	v, ok := f.args[name]
+	if !ok {
+		f.fail("Configuration Error: missing parameter '%v'", name)
+		return ""
+	}
+	delete(f.args, name)
+	return v
+}
+
+/* Consumes the parameter name, returning defaultVal if it is missing. */
+func (f *AbstractAnalysisFactory) Get(name, defaultVal string) string {
+	v, ok := f.args[name]
+	if !ok {
+		return defaultVal
+	}
+	delete(f.args, name)
+	return v
+}
+
+/*
+Consumes the parameter name, which must be one of allowedValues,
+returning defaultVal if it is missing.
+*/
+func (f *AbstractAnalysisFactory) GetOneOf(name, defaultVal string, allowedValues ...string) string {
+	v := f.Get(name, defaultVal)
+	for _, allowed := range allowedValues {
+		if v == allowed {
+			return v
+		}
+	}
+	f.fail("Configuration Error: '%v' value must be one of %v", name, allowedValues)
+	return defaultVal
+}
+
+/* Consumes the integer parameter name, recording an error if it is missing or invalid. */
+func (f *AbstractAnalysisFactory) RequireInt(name string) int {
+	if _, ok := f.args[name]; !ok {
+		f.fail("Configuration Error: missing parameter '%v'", name)
+		return 0
+	}
+	return f.GetInt(name, 0)
+}
+
+func (f *AbstractAnalysisFactory) GetInt(name string, defaultVal int) int {
+	s, ok := f.args[name]
+	if !ok {
+		return defaultVal
+	}
+	delete(f.args, name)
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		f.fail("Configuration Error: '%v' must be an integer, was '%v'", name, s)
+		return defaultVal
+	}
+	return v
+}
+
+func (f *AbstractAnalysisFactory) GetBool(name string, defaultVal bool) bool {
+	s, ok := f.args[name]
+	if !ok {
+		return defaultVal
+	}
+	delete(f.args, name)
+	v, err := strconv.ParseBool(s)
+	if err != nil {
+		f.fail("Configuration Error: '%v' must be a boolean, was '%v'", name, s)
+		return defaultVal
+	}
+	return v
+}
+
+/* Consumes the regular expression parameter name, recording an error if it is missing or invalid. */
+func (f *AbstractAnalysisFactory) RequirePattern(name string) *regexp.Regexp {
+	s, ok := f.args[name]
+	if !ok {
+		f.fail("Configuration Error: missing parameter '%v'", name)
+		return nil
+	}
+	delete(f.args, name)
+	pattern, err := regexp.Compile(s)
+	if err != nil {
+		f.fail("Configuration Error: '%v' can not be parsed: %v", name, err)
+		return nil
+	}
+	return pattern
+}
+
+/* Consumes the parameter name as a list of values, separated by commas and/or whitespace. */
+func (f *AbstractAnalysisFactory) GetSet(name string) []string {
+	s, ok := f.args[name]
+	if !ok {
+		return nil
+	}
+	delete(f.args, name)
+	return splitList(s)
+}
+
+func splitList(s string) []string {
+	return strings.FieldsFunc(s, func(ch rune) bool {
+		return ch == ',' || ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r'
+	})
+}
+
+/*
+Consumes the parameter name as a comma-separated list of word files,
+returning the words they contain, or nil if the parameter is missing.
+The format is either "wordset", one word per line with "#" comments,
+or "snowball".
+*/
+func (f *AbstractAnalysisFactory) GetWordSet(name, format string, ignoreCase bool) *CharArraySet {
+	files := f.GetSet(name)
+	if files == nil {
+		return nil
+	}
+	words := NewCharArraySet(ignoreCase)
+	for _, file := range files {
+		if err := readWordFile(file, format, words); err != nil {
+			f.fail("Configuration Error: failed to read '%v': %v", file, err)
+			return nil
+		}
+	}
+	return words
+}
+
+func readWordFile(file, format string, words *CharArraySet) error {
+	in, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	switch format {
+	case "snowball":
+		_, err = GetSnowballWordSet(in, words)
+	default:
+		_, err = GetWordSetWithComment(in, "#", words)
+	}
+	return err
+}
+
+/*
+Returns the first error met consuming the parameters, or an error
+listing the parameters left unconsumed. Factory constructors return
+it once they consumed all the parameters they know of.
+*/
+func (f *AbstractAnalysisFactory) Validate() error {
+	if f.err != nil {
+		return f.err
+	}
+	if len(f.args) > 0 {
+		names := make([]string, 0, len(f.args))
+		for name := range f.args {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return fmt.Errorf("Unknown parameters: %v", names)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package util
+
+import (
+	"sort"
+	"unicode"
+)
+
+// util/CharArraySet.java
+
+/*
+A simple set of strings that can be looked up directly with the rune
+buffer of a CharTermAttribute, and that optionally ignores case.
+
+If ignoreCase is true, words are folded to lower case with
+unicode.ToLower() both when added and when looked up.
+*/
+type CharArraySet struct {
+	ignoreCase bool
+	words      map[string]bool
+}
+
+/* An empty, case-sensitive set. Must not be modified. */
+var EMPTY_SET = NewCharArraySet(false)
+
+/* Creates a set holding the given words. */
+func NewCharArraySet(ignoreCase bool, words ...string) *CharArraySet {
+	ans := &CharArraySet{ignoreCase, make(map[string]bool, len(words))}
+	for _, word := range words {
+		ans.Add(word)
+	}
+	return ans
+}
+
+/* Creates a set holding the keys of the given map whose value is true. */
+func NewCharArraySetFromMap(words map[string]bool, ignoreCase bool) *CharArraySet {
+	ans := &CharArraySet{ignoreCase, make(map[string]bool, len(words))}
+	for word, ok := range words {
+		if ok {
+			ans.Add(word)
+		}
+	}
+	return ans
+}
+
+func (s *CharArraySet) key(text []rune) string {
+	if !s.ignoreCase {
+		return string(text)
+	}
+	folded := make([]rune, len(text))
+	for i, ch := range text {
+		folded[i] = unicode.ToLower(ch)
+	}
+	return string(folded)
+}
+
+/* Returns true if this set ignores case. */
+func (s *CharArraySet) IgnoreCase() bool {
+	return s.ignoreCase
+}
+
+/* Returns true if the runes of text are in the set. */
+func (s *CharArraySet) Contains(text []rune) bool {
+	return s.words[s.key(text)]
+}
+
+/* Returns true if the string is in the set. */
+func (s *CharArraySet) ContainsString(text string) bool {
+	if !s.ignoreCase {
+		return s.words[text]
+	}
+	return s.Contains([]rune(text))
+}
+
+/* Adds the word to the set; returns false if it was already present. */
+func (s *CharArraySet) Add(text string) bool {
+	key := text
+	if s.ignoreCase {
+		key = s.key([]rune(text))
+	}
+	if s.words[key] {
+		return false
+	}
+	s.words[key] = true
+	return true
+}
+
+/* Returns the number of words in the set. */
+func (s *CharArraySet) Len() int {
+	return len(s.words)
+}
+
+/* Returns the words of the set, lower cased if case is ignored, in sorted order. */
+func (s *CharArraySet) Values() []string {
+	ans := make([]string, 0, len(s.words))
+	for word := range s.words {
+		ans = append(ans, word)
+	}
+	sort.Strings(ans)
+	return ans
+}
+
+/* Returns a copy of the words of the set, as used by the analyzers. */
+func (s *CharArraySet) ToMap() map[string]bool {
+	ans := make(map[string]bool, len(s.words))
+	for word := range s.words {
+		ans[word] = true
+	}
+	return ans
+}
+
+/* Returns a copy of this set. */
+func (s *CharArraySet) Copy() *CharArraySet {
+	return &CharArraySet{s.ignoreCase, s.ToMap()}
+}
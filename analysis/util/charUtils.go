@@ -29,3 +29,30 @@ func (cu *CharacterUtils) ToLowerCase(buffer []rune) {
 		buffer[i] = unicode.ToLower(v)
 	}
 }
+
+/*
+Converts each unicode codepoint to lowerCase like ToLowerCase(), but
+applies the unconditional mappings of Unicode's SpecialCasing.txt
+too, which lower case a codepoint to several: U+0130 LATIN CAPITAL
+LETTER I WITH DOT ABOVE becomes "i" followed by U+0307 COMBINING DOT
+ABOVE, so that the dot is not lost. Returns buffer, lowercased in
+place, if no codepoint expanded.
+*/
+func (cu *CharacterUtils) ToLowerCaseFull(buffer []rune) []rune {
+	for i, v := range buffer {
+		if v == '\u0130' {
+			ans := make([]rune, i, len(buffer)+1)
+			copy(ans, buffer[:i])
+			for _, v := range buffer[i:] {
+				if v == '\u0130' {
+					ans = append(ans, 'i', '\u0307')
+				} else {
+					ans = append(ans, unicode.ToLower(v))
+				}
+			}
+			return ans
+		}
+		buffer[i] = unicode.ToLower(v)
+	}
+	return buffer
+}
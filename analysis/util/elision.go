@@ -0,0 +1,55 @@
+package util
+
+import (
+	. "github.com/balzaczyy/golucene/core/analysis"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+)
+
+// util/ElisionFilter.java
+
+/*
+Removes elisions from a TokenStream. For example, "l'avion" (the
+plane) will be tokenized as "avion" (plane).
+
+The elided article is everything up to the first apostrophe, either
+' or ’, and is only removed if it is in the article set.
+*/
+type ElisionFilter struct {
+	*TokenFilter
+	input    TokenStream
+	articles *CharArraySet
+	termAtt  CharTermAttribute
+}
+
+/* Constructs an elision filter with a set of stop words. */
+func NewElisionFilter(input TokenStream, articles *CharArraySet) *ElisionFilter {
+	ans := &ElisionFilter{
+		TokenFilter: NewTokenFilter(input),
+		input:       input,
+		articles:    articles,
+	}
+	ans.termAtt = ans.Attributes().Add("CharTermAttribute").(CharTermAttribute)
+	return ans
+}
+
+/* Increments the TokenStream with a CharTermAttribute without elisioned start. */
+func (f *ElisionFilter) IncrementToken() (bool, error) {
+	ok, err := f.input.IncrementToken()
+	if err != nil || !ok {
+		return false, err
+	}
+	termBuffer := f.termAtt.Buffer()
+	termLength := f.termAtt.Length()
+	index := -1
+	for i, ch := range termBuffer[:termLength] {
+		if ch == '\'' || ch == '’' {
+			index = i
+			break
+		}
+	}
+	// An apostrophe has been found. If the prefix is an article strip it off.
+	if index >= 0 && f.articles.Contains(termBuffer[:index]) {
+		f.termAtt.SetLength(copy(termBuffer, termBuffer[index+1:termLength]))
+	}
+	return true, nil
+}
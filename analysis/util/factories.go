@@ -0,0 +1,146 @@
+package util
+
+import (
+	"fmt"
+	. "github.com/balzaczyy/golucene/core/analysis"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// util/TokenizerFactory.java
+
+/* Abstract parent class for analysis factories that create Tokenizer instances. */
+type TokenizerFactory interface {
+	// Creates a Tokenizer of the given input.
+	Create(input io.RuneReader) TokenizerService
+}
+
+// util/TokenFilterFactory.java
+
+/* Abstract parent class for analysis factories that create TokenFilter instances. */
+type TokenFilterFactory interface {
+	// Transform the specified input TokenStream.
+	Create(input TokenStream) TokenStream
+}
+
+// util/CharFilterFactory.java
+
+/* Abstract parent class for analysis factories that create CharFilter instances. */
+type CharFilterFactory interface {
+	// Wraps the given reader with a CharFilter.
+	Create(input io.RuneReader) io.RuneReader
+}
+
+// util/AnalysisSPILoader.java
+
+/*
+Registry of the analysis factories of one kind, keyed by name. Names
+are case-insensitive. Packages register their factories in init(),
+so a factory is available once its package is imported.
+*/
+type factoryRegistry struct {
+	kind string
+	sync.RWMutex
+	factories map[string]interface{}
+}
+
+func (r *factoryRegistry) register(name string, newFactory interface{}) {
+	r.Lock()
+	defer r.Unlock()
+	key := strings.ToLower(name)
+	if _, ok := r.factories[key]; ok {
+		panic(fmt.Sprintf("%v '%v' is already registered", r.kind, name))
+	}
+	r.factories[key] = newFactory
+}
+
+func (r *factoryRegistry) lookup(name string) (interface{}, error) {
+	r.RLock()
+	defer r.RUnlock()
+	if newFactory, ok := r.factories[strings.ToLower(name)]; ok {
+		return newFactory, nil
+	}
+	return nil, fmt.Errorf("A SPI class of type %v with name '%v' does not exist. "+
+		"You need to import the package which registers it. The current registry contains: %v",
+		r.kind, name, r.availableLocked())
+}
+
+func (r *factoryRegistry) available() []string {
+	r.RLock()
+	defer r.RUnlock()
+	return r.availableLocked()
+}
+
+func (r *factoryRegistry) availableLocked() []string {
+	names := make([]string, 0, len(r.factories))
+	for name := range r.factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+var (
+	tokenizerFactories   = &factoryRegistry{kind: "TokenizerFactory", factories: make(map[string]interface{})}
+	tokenFilterFactories = &factoryRegistry{kind: "TokenFilterFactory", factories: make(map[string]interface{})}
+	charFilterFactories  = &factoryRegistry{kind: "CharFilterFactory", factories: make(map[string]interface{})}
+)
+
+/* Registers the constructor of the TokenizerFactory with the given name. It panics if the name is taken. */
+func RegisterTokenizerFactory(name string, newFactory func(args map[string]string) (TokenizerFactory, error)) {
+	tokenizerFactories.register(name, newFactory)
+}
+
+/* Looks up a TokenizerFactory by name, and creates it with the given arguments. */
+func NewTokenizerFactory(name string, args map[string]string) (TokenizerFactory, error) {
+	newFactory, err := tokenizerFactories.lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	return newFactory.(func(map[string]string) (TokenizerFactory, error))(args)
+}
+
+/* Returns the names of the registered TokenizerFactory, in order. */
+func AvailableTokenizers() []string {
+	return tokenizerFactories.available()
+}
+
+/* Registers the constructor of the TokenFilterFactory with the given name. It panics if the name is taken. */
+func RegisterTokenFilterFactory(name string, newFactory func(args map[string]string) (TokenFilterFactory, error)) {
+	tokenFilterFactories.register(name, newFactory)
+}
+
+/* Looks up a TokenFilterFactory by name, and creates it with the given arguments. */
+func NewTokenFilterFactory(name string, args map[string]string) (TokenFilterFactory, error) {
+	newFactory, err := tokenFilterFactories.lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	return newFactory.(func(map[string]string) (TokenFilterFactory, error))(args)
+}
+
+/* Returns the names of the registered TokenFilterFactory, in order. */
+func AvailableTokenFilters() []string {
+	return tokenFilterFactories.available()
+}
+
+/* Registers the constructor of the CharFilterFactory with the given name. It panics if the name is taken. */
+func RegisterCharFilterFactory(name string, newFactory func(args map[string]string) (CharFilterFactory, error)) {
+	charFilterFactories.register(name, newFactory)
+}
+
+/* Looks up a CharFilterFactory by name, and creates it with the given arguments. */
+func NewCharFilterFactory(name string, args map[string]string) (CharFilterFactory, error) {
+	newFactory, err := charFilterFactories.lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	return newFactory.(func(map[string]string) (CharFilterFactory, error))(args)
+}
+
+/* Returns the names of the registered CharFilterFactory, in order. */
+func AvailableCharFilters() []string {
+	return charFilterFactories.available()
+}
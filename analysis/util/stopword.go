@@ -23,3 +23,8 @@ func NewStopwordAnalyzerBaseWithStopWords(stopwords map[string]bool) *StopwordAn
 	}
 	return ans
 }
+
+/* Returns the analyzer's stopword set or an empty set if the analyzer has no stopwords */
+func (a *StopwordAnalyzerBase) StopwordSet() map[string]bool {
+	return a.stopwords
+}
@@ -0,0 +1,80 @@
+package util
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// util/WordlistLoader.java
+
+/*
+Loader for text files that represent a list of stopwords.
+
+Files are read as UTF-8.
+*/
+
+/*
+Reads lines from a reader and adds every line as an entry to a
+CharArraySet (omitting leading and trailing whitespace). Every line of
+the reader should contain only one word. The words need to be in
+lowercase if you make use of an Analyzer which uses LowerCaseFilter
+(like StandardAnalyzer).
+
+If result is nil, a new case-sensitive set is created.
+*/
+func GetWordSet(reader io.Reader, result *CharArraySet) (*CharArraySet, error) {
+	return GetWordSetWithComment(reader, "", result)
+}
+
+/*
+Reads lines from a reader and adds every non-comment line as an entry
+to a CharArraySet (omitting leading and trailing whitespace). Every
+line of the reader should contain only one word. Lines starting with
+the given comment prefix are ignored; an empty prefix disables
+comments.
+*/
+func GetWordSetWithComment(reader io.Reader, comment string, result *CharArraySet) (*CharArraySet, error) {
+	if result == nil {
+		result = NewCharArraySet(false)
+	}
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if comment != "" && strings.HasPrefix(line, comment) {
+			continue
+		}
+		if word := strings.TrimSpace(line); word != "" {
+			result.Add(word)
+		}
+	}
+	return result, scanner.Err()
+}
+
+/*
+Reads stopwords from a stopword list in Snowball format.
+
+The snowball format is the following:
+
+	- Lines may contain multiple words separated by whitespace.
+	- The comment character is the vertical line (|).
+	- Lines may contain trailing comments.
+
+If result is nil, a new case-sensitive set is created.
+*/
+func GetSnowballWordSet(reader io.Reader, result *CharArraySet) (*CharArraySet, error) {
+	if result == nil {
+		result = NewCharArraySet(false)
+	}
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if comment := strings.IndexByte(line, '|'); comment >= 0 {
+			line = line[:comment]
+		}
+		for _, word := range strings.Fields(line) {
+			result.Add(word)
+		}
+	}
+	return result, scanner.Err()
+}
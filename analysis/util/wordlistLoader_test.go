@@ -0,0 +1,41 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestWordlistLoader(t *testing.T) {
+	s := "ONE\n  two \nthree\n\n#comment\n"
+	set, err := GetWordSet(strings.NewReader(s), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := fmt.Sprint(set.Values()); got != "[#comment ONE three two]" {
+		t.Errorf("plain: %v", got)
+	}
+
+	set, err = GetWordSetWithComment(strings.NewReader(s), "#", NewCharArraySet(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := fmt.Sprint(set.Values()); got != "[one three two]" {
+		t.Errorf("with comment: %v", got)
+	}
+	if !set.ContainsString("One") || !set.Contains([]rune("TWO")) || set.ContainsString("#comment") {
+		t.Error("ignore case lookup failed")
+	}
+
+	s = "|comment\n | another comment\n  ONE two |comment\nthree\n \t|comment\nfour five\n"
+	set, err = GetSnowballWordSet(strings.NewReader(s), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := fmt.Sprint(set.Values()); got != "[ONE five four three two]" {
+		t.Errorf("snowball: %v", got)
+	}
+	if set.ContainsString("one") {
+		t.Error("case sensitive set should not contain one")
+	}
+}
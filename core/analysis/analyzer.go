@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/balzaczyy/golucene/core/util"
 	"io"
+	"sync"
 )
 
 // analysis/Analyzer.java
@@ -17,8 +18,14 @@ In order to define what analysis is done, subclass must define their
 TokenStreamConents in CreateComponents(string, Reader). The components are
 then reused in each call to TokenStream(string, Reader).
 
-Also note that one should Clone() Analyzer for each Go routine if
-default ReuseStrategy is used.
+The components are pooled by the ReuseStrategy: a TokenStream
+returned by the Analyzer is exclusively owned by its consumer until it
+is closed, and then its components go back to the pool to be reused by
+the next call, from any goroutine. So an Analyzer can be shared by
+goroutines, and each of them reuses components across documents.
+
+To do so, the TokenStream returned wraps the sink of the components,
+so type assertions must be made on UnwrapTokenStream() of it.
 */
 type Analyzer interface {
 	TokenStreamForReader(string, io.RuneReader) (TokenStream, error)
@@ -50,17 +57,17 @@ type AnalyzerSPI interface {
 }
 
 type container struct {
-	value interface{}
+	sync.Mutex
+	value  interface{}
+	closed bool
 }
 
 type AnalyzerImpl struct {
 	Spi           AnalyzerSPI
 	reuseStrategy ReuseStrategy
 	version       util.Version
-	// Since Go doesn't have ThreadLocal alternatives, to share
-	// Analyzer, one must Clone() the Analyzer for each Go routine. It
-	// also means the performance may not be competitive compared to
-	// Lucene Java Analyzer.
+	// Since Go doesn't have ThreadLocal alternatives, the stored value
+	// is a pool of the components not in use, shared by goroutines.
 	storedValue *container
 }
 
@@ -76,7 +83,7 @@ func NewAnalyzerWithStrategy(reuseStrategy ReuseStrategy) *AnalyzerImpl {
 	ans := &AnalyzerImpl{
 		reuseStrategy: reuseStrategy,
 		version:       util.VERSION_LATEST,
-		storedValue:   &container{nil},
+		storedValue:   new(container),
 	}
 	ans.Spi = ans
 	return ans
@@ -88,15 +95,13 @@ func (a *AnalyzerImpl) CreateComponents(fieldName string, reader io.RuneReader)
 
 func (a *AnalyzerImpl) TokenStreamForReader(fieldName string, reader io.RuneReader) (TokenStream, error) {
 	components := a.reuseStrategy.ReusableComponents(a, fieldName)
-	r := a.InitReader(fieldName, reader)
+	r := a.Spi.InitReader(fieldName, reader)
 	if components == nil {
-		panic("not implemented yet")
-	} else {
-		if err := components.SetReader(r); err != nil {
-			return nil, err
-		}
+		components = a.Spi.CreateComponents(fieldName, r)
+	} else if err := components.SetReader(r); err != nil {
+		return nil, err
 	}
-	return components.TokenStream(), nil
+	return a.checkOut(fieldName, components), nil
 }
 
 func (a *AnalyzerImpl) TokenStreamForString(fieldName, text string) (TokenStream, error) {
@@ -108,18 +113,40 @@ func (a *AnalyzerImpl) TokenStreamForString(fieldName, text string) (TokenStream
 		strReader = components.reusableStringReader
 	}
 	strReader.setValue(text)
-	r := a.InitReader(fieldName, strReader)
+	r := a.Spi.InitReader(fieldName, strReader)
 	if components == nil {
 		components = a.Spi.CreateComponents(fieldName, r)
-		a.reuseStrategy.SetReusableComponents(a, fieldName, components)
-	} else {
-		err := components.SetReader(r)
-		if err != nil {
-			return nil, err
-		}
+	} else if err := components.SetReader(r); err != nil {
+		return nil, err
 	}
 	components.reusableStringReader = strReader
-	return components.TokenStream(), nil
+	return a.checkOut(fieldName, components), nil
+}
+
+/*
+Returns the sink of the components, which hands the components back
+to the ReuseStrategy once it is closed.
+*/
+func (a *AnalyzerImpl) checkOut(fieldName string, components *TokenStreamComponents) TokenStream {
+	if components.pooled == nil {
+		components.pooled = &pooledTokenStream{TokenStream: components.sink}
+	}
+	components.pooled.release = func() {
+		a.reuseStrategy.SetReusableComponents(a, fieldName, components)
+	}
+	return components.pooled
+}
+
+/*
+Frees persistent resources used by this Analyzer: the pooled
+components are dropped, and the ones still in use are not reused once
+closed. A closed Analyzer creates new components on every call.
+*/
+func (a *AnalyzerImpl) Close() error {
+	a.storedValue.Lock()
+	defer a.storedValue.Unlock()
+	a.storedValue.value, a.storedValue.closed = nil, true
+	return nil
 }
 
 func (a *AnalyzerImpl) InitReader(fieldName string, reader io.RuneReader) io.RuneReader {
@@ -160,6 +187,8 @@ type TokenStreamComponents struct {
 	sink TokenStream
 	// Internal cache only used by Analyzer.TokenStreamForString().
 	reusableStringReader *ReusableStringReader
+	// The sink handed to consumers while the components are in use.
+	pooled *pooledTokenStream
 	// Resets the encapculated components with the given reader. If the
 	// components canno be reset, an error should be returned.
 	SetReader func(io.RuneReader) error
@@ -178,32 +207,78 @@ func (cp *TokenStreamComponents) TokenStream() TokenStream {
 	return cp.sink
 }
 
+/*
+Returns the sink of the components behind a TokenStream returned by
+an Analyzer, which wraps it to hand the components back on Close().
+The returned TokenStream can be type asserted, but the one returned
+by the Analyzer must still be the one closed. Other TokenStreams are
+returned unchanged.
+*/
+func UnwrapTokenStream(ts TokenStream) TokenStream {
+	if pooled, ok := ts.(*pooledTokenStream); ok {
+		return pooled.TokenStream
+	}
+	return ts
+}
+
+/* The sink of checked out components, returning them to the pool on Close(). */
+type pooledTokenStream struct {
+	TokenStream
+	release func()
+}
+
+func (ts *pooledTokenStream) Close() error {
+	err := ts.TokenStream.Close()
+	if release := ts.release; release != nil {
+		// closing twice must not hand the components out twice
+		ts.release = nil
+		release()
+	}
+	return err
+}
+
 // L329
 
-// Strategy defining how TokenStreamComponents are reused per call to
-// TokenStream(string, io.Reader)
+/*
+Strategy defining how TokenStreamComponents are reused per call to
+TokenStream(string, io.Reader).
+
+Components are checked out of the strategy while a TokenStream is
+consumed, and handed back when it is closed, so that no two
+goroutines share them. At most MAX_POOLED_COMPONENTS components are
+kept per pool, so that a burst of concurrent use does not retain its
+components for the life of the Analyzer; extra ones are dropped.
+*/
 type ReuseStrategy interface {
-	// Gets the reusable TokenStreamComponents for the field with the
-	// given name.
+	// Takes reusable TokenStreamComponents for the field with the given
+	// name out of the pool, or returns nil if there is none.
 	ReusableComponents(*AnalyzerImpl, string) *TokenStreamComponents
 	// Stores the given TokenStreamComponents as the reusable
 	// components for the field with the given name.
 	SetReusableComponents(*AnalyzerImpl, string, *TokenStreamComponents)
 }
 
-type ReuseStrategyImpl struct {
-}
+// The maximum number of idle components a ReuseStrategy pools for
+// reuse, per field for PER_FIELD_REUSE_STRATEGY.
+const MAX_POOLED_COMPONENTS = 64
 
-/* Returns the currently stored value */
-func (rs *ReuseStrategyImpl) storedValue(a *AnalyzerImpl) interface{} {
-	assert2(a.storedValue != nil, "this Analyzer is closed")
-	return a.storedValue.value
+type ReuseStrategyImpl struct {
 }
 
-/* Set the stored value. */
-func (rs *ReuseStrategyImpl) setStoredValue(a *AnalyzerImpl, v interface{}) {
-	assert2(a.storedValue != nil, "this Analyzer is closed")
-	a.storedValue.value = v
+/*
+Calls f with the currently stored value while holding the lock of
+the Analyzer, and stores the value f returns. Nothing is stored once
+the Analyzer is closed.
+*/
+func (rs *ReuseStrategyImpl) updateStoredValue(a *AnalyzerImpl, f func(interface{}) interface{}) {
+	stored := a.storedValue
+	stored.Lock()
+	defer stored.Unlock()
+	if stored.closed {
+		// this Analyzer is closed: components are not reused anymore
+		return
+	}
+	stored.value = f(stored.value)
 }
 
 func assert2(ok bool, msg string, args ...interface{}) {
@@ -219,33 +294,60 @@ type GlobalReuseStrategy struct {
 	*ReuseStrategyImpl
 }
 
-func (rs *GlobalReuseStrategy) ReusableComponents(a *AnalyzerImpl, fieldName string) *TokenStreamComponents {
-	if ans := rs.storedValue(a); ans != nil {
-		return ans.(*TokenStreamComponents)
-	}
-	return nil
+func (rs *GlobalReuseStrategy) ReusableComponents(a *AnalyzerImpl, fieldName string) (ans *TokenStreamComponents) {
+	rs.updateStoredValue(a, func(v interface{}) interface{} {
+		pool, _ := v.([]*TokenStreamComponents)
+		if n := len(pool); n > 0 {
+			ans, pool = pool[n-1], pool[:n-1]
+		}
+		return pool
+	})
+	return
 }
 
 func (rs *GlobalReuseStrategy) SetReusableComponents(a *AnalyzerImpl, fieldName string, components *TokenStreamComponents) {
-	rs.setStoredValue(a, components)
+	rs.updateStoredValue(a, func(v interface{}) interface{} {
+		pool, _ := v.([]*TokenStreamComponents)
+		if len(pool) >= MAX_POOLED_COMPONENTS {
+			return pool
+		}
+		return append(pool, components)
+	})
 }
 
 // L423
 // A predefined ReuseStrategy that reuses components per-field by
 // maintaining a Map of TokenStreamComponent per field name.
-var PER_FIELD_REUSE_STRATEGY = &PerFieldReuseStrategy{}
+var PER_FIELD_REUSE_STRATEGY = new(PerFieldReuseStrategy)
 
 // Implementation of ReuseStrategy that reuses components per-field by
 // maintianing a Map of TokenStreamComponent per field name.
 type PerFieldReuseStrategy struct {
+	*ReuseStrategyImpl
 }
 
-func (rs *PerFieldReuseStrategy) ReusableComponents(a *AnalyzerImpl, fieldName string) *TokenStreamComponents {
-	panic("not implemented yet")
+func (rs *PerFieldReuseStrategy) ReusableComponents(a *AnalyzerImpl, fieldName string) (ans *TokenStreamComponents) {
+	rs.updateStoredValue(a, func(v interface{}) interface{} {
+		pools, _ := v.(map[string][]*TokenStreamComponents)
+		if pool := pools[fieldName]; len(pool) > 0 {
+			ans, pools[fieldName] = pool[len(pool)-1], pool[:len(pool)-1]
+		}
+		return pools
+	})
+	return
 }
 
 func (rs *PerFieldReuseStrategy) SetReusableComponents(a *AnalyzerImpl, fieldName string, components *TokenStreamComponents) {
-	panic("not implemneted yet")
+	rs.updateStoredValue(a, func(v interface{}) interface{} {
+		pools, _ := v.(map[string][]*TokenStreamComponents)
+		if pools == nil {
+			pools = make(map[string][]*TokenStreamComponents)
+		}
+		if pool := pools[fieldName]; len(pool) < MAX_POOLED_COMPONENTS {
+			pools[fieldName] = append(pool, components)
+		}
+		return pools
+	})
 }
 
 // analysis/ReusableStringReader.java
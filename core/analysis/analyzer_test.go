@@ -0,0 +1,219 @@
+package analysis
+
+import (
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"io"
+	"sync"
+	"testing"
+)
+
+/* Emits the whole input as a single token. */
+type wholeTokenizer struct {
+	*Tokenizer
+	termAtt CharTermAttribute
+	done    bool
+}
+
+func newWholeTokenizer(input io.RuneReader) *wholeTokenizer {
+	ans := &wholeTokenizer{Tokenizer: NewTokenizer(input)}
+	ans.termAtt = ans.Attributes().Add("CharTermAttribute").(CharTermAttribute)
+	return ans
+}
+
+func (t *wholeTokenizer) IncrementToken() (bool, error) {
+	if t.done {
+		return false, nil
+	}
+	t.Attributes().Clear()
+	t.done = true
+	var text []rune
+	for {
+		ch, _, err := t.Input.ReadRune()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return false, err
+		}
+		text = append(text, ch)
+	}
+	t.termAtt.CopyBuffer(text)
+	return true, nil
+}
+
+func (t *wholeTokenizer) Reset() error {
+	t.done = false
+	return t.Tokenizer.Reset()
+}
+
+type countingAnalyzer struct {
+	*AnalyzerImpl
+	sync.Mutex
+	created map[string]int
+}
+
+func newCountingAnalyzer(strategy ReuseStrategy) *countingAnalyzer {
+	ans := &countingAnalyzer{
+		AnalyzerImpl: NewAnalyzerWithStrategy(strategy),
+		created:      make(map[string]int),
+	}
+	ans.Spi = ans
+	return ans
+}
+
+func (a *countingAnalyzer) CreateComponents(fieldName string, reader io.RuneReader) *TokenStreamComponents {
+	a.Lock()
+	a.created[fieldName]++
+	a.Unlock()
+	tokenizer := newWholeTokenizer(reader)
+	return NewTokenStreamComponents(tokenizer, tokenizer)
+}
+
+func analyze(t *testing.T, a Analyzer, field, text string) string {
+	ts, err := a.TokenStreamForString(field, text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	termAtt := ts.Attributes().Get("CharTermAttribute").(CharTermAttribute)
+	if err = ts.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	var ans string
+	for {
+		ok, err := ts.IncrementToken()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			break
+		}
+		ans += string(termAtt.Buffer()[:termAtt.Length()])
+	}
+	if err = ts.End(); err != nil {
+		t.Fatal(err)
+	}
+	if err = ts.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return ans
+}
+
+func TestGlobalReuseStrategy(t *testing.T) {
+	a := newCountingAnalyzer(GLOBAL_REUSE_STRATEGY)
+	for _, field := range []string{"a", "b", "a"} {
+		if got := analyze(t, a, field, "text of "+field); got != "text of "+field {
+			t.Errorf("expected %v, but was %v", "text of "+field, got)
+		}
+	}
+	if a.created["a"] != 1 || a.created["b"] != 0 {
+		t.Errorf("expected the components of the first field to be reused, but was %v", a.created)
+	}
+
+	// components in use are not handed out twice
+	ts, err := a.TokenStreamForString("a", "x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := analyze(t, a, "a", "y"); got != "y" {
+		t.Errorf("expected y, but was %v", got)
+	}
+	if a.created["a"] != 2 {
+		t.Errorf("expected new components while the others are in use, but was %v", a.created)
+	}
+	if err = ts.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPerFieldReuseStrategy(t *testing.T) {
+	a := newCountingAnalyzer(PER_FIELD_REUSE_STRATEGY)
+	for _, field := range []string{"a", "b", "a", "b"} {
+		analyze(t, a, field, "text")
+	}
+	if a.created["a"] != 1 || a.created["b"] != 1 {
+		t.Errorf("expected the components to be reused per field, but was %v", a.created)
+	}
+}
+
+func TestReuseStrategyConcurrently(t *testing.T) {
+	a := newCountingAnalyzer(GLOBAL_REUSE_STRATEGY)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if got := analyze(t, a, "f", "some text"); got != "some text" {
+					t.Errorf("expected some text, but was %v", got)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if n := a.created["f"]; n < 1 || n > 4 {
+		t.Errorf("expected at most one components per goroutine, but was %v", n)
+	}
+}
+
+func TestClosedAnalyzer(t *testing.T) {
+	a := newCountingAnalyzer(GLOBAL_REUSE_STRATEGY)
+	analyze(t, a, "f", "x")
+	if err := a.Close(); err != nil {
+		t.Fatal(err)
+	}
+	analyze(t, a, "f", "y")
+	analyze(t, a, "f", "z")
+	if a.created["f"] != 3 {
+		t.Errorf("expected no reuse once closed, but was %v", a.created)
+	}
+}
+
+func TestCloseAnalyzerWhileAnalyzing(t *testing.T) {
+	a := newCountingAnalyzer(PER_FIELD_REUSE_STRATEGY)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				analyze(t, a, "f", "text")
+			}
+		}()
+	}
+	if err := a.Close(); err != nil {
+		t.Fatal(err)
+	}
+	wg.Wait()
+}
+
+func TestMaxPooledComponents(t *testing.T) {
+	a := newCountingAnalyzer(PER_FIELD_REUSE_STRATEGY)
+	streams := make([]TokenStream, MAX_POOLED_COMPONENTS+1)
+	for i := range streams {
+		var err error
+		if streams[i], err = a.TokenStreamForString("f", "x"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, ts := range streams {
+		if err := ts.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	pool := a.storedValue.value.(map[string][]*TokenStreamComponents)["f"]
+	if len(pool) != MAX_POOLED_COMPONENTS {
+		t.Errorf("expected %v pooled components, but was %v", MAX_POOLED_COMPONENTS, len(pool))
+	}
+}
+
+func TestUnwrapTokenStream(t *testing.T) {
+	a := newCountingAnalyzer(GLOBAL_REUSE_STRATEGY)
+	ts, err := a.TokenStreamForString("f", "x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+	if _, ok := UnwrapTokenStream(ts).(*wholeTokenizer); !ok {
+		t.Errorf("expected the sink of the components, but was %v", UnwrapTokenStream(ts))
+	}
+}
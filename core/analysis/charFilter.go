@@ -1,6 +1,120 @@
 package analysis
 
+import (
+	"io"
+	"sort"
+)
+
 type CharFilterService interface {
 	// Chains the corrected offset through the input CharFilter(s).
 	CorrectOffset(int) int
 }
+
+// analysis/CharFilter.java
+
+type CharFilterSPI interface {
+	// Subclasses override to correct the current offset.
+	Correct(currentOff int) int
+}
+
+/*
+Subclasses of CharFilter can be chained to filter a Reader. They can
+be used as Reader with additional offset correction. Tokenizers will
+automatically use CorrectOffset() if a CharFilter subclass is used.
+
+This class is abstract: at a minimum you must implement ReadRune(),
+transforming the input in some way from Input, and Correct() to
+adjust the offsets to match the originals.
+
+You can optionally provide more efficient implementations of
+additional methods like Close(), but this is not required.
+
+For examples and integration with Analyzer, see the analysis package
+documentation.
+*/
+type CharFilter struct {
+	Spi CharFilterSPI
+	// The underlying character-input stream.
+	Input io.RuneReader
+}
+
+/* Create a new CharFilter wrapping the provided reader. */
+func NewCharFilter(input io.RuneReader) *CharFilter {
+	assert2(input != nil, "input must not be nil")
+	return &CharFilter{Input: input}
+}
+
+/*
+Closes the underlying input stream.
+
+NOTE: The default implementation closes the input Reader, so be sure
+to call CharFilter.Close() when overriding this method.
+*/
+func (f *CharFilter) Close() error {
+	if v, ok := f.Input.(io.Closer); ok {
+		return v.Close()
+	}
+	return nil
+}
+
+/* Chains the corrected offset through the input CharFilter(s). */
+func (f *CharFilter) CorrectOffset(currentOff int) int {
+	corrected := f.Spi.Correct(currentOff)
+	if v, ok := f.Input.(CharFilterService); ok {
+		return v.CorrectOffset(corrected)
+	}
+	return corrected
+}
+
+// charfilter/BaseCharFilter.java
+
+/*
+Base utility class for implementing a CharFilter. You subclass this,
+and then record mappings by calling AddOffCorrectMap(), and then
+invoke the correct method to correct an offset.
+*/
+type BaseCharFilter struct {
+	*CharFilter
+	offsets, diffs []int
+}
+
+func NewBaseCharFilter(input io.RuneReader) *BaseCharFilter {
+	ans := &BaseCharFilter{CharFilter: NewCharFilter(input)}
+	ans.Spi = ans
+	return ans
+}
+
+/* Retrieve the corrected offset. */
+func (f *BaseCharFilter) Correct(currentOff int) int {
+	if i := sort.SearchInts(f.offsets, currentOff+1) - 1; i >= 0 {
+		return currentOff + f.diffs[i]
+	}
+	return currentOff
+}
+
+func (f *BaseCharFilter) LastCumulativeDiff() int {
+	if n := len(f.diffs); n > 0 {
+		return f.diffs[n-1]
+	}
+	return 0
+}
+
+/*
+Adds an offset correction mapping at the given output stream offset.
+
+Assumption: the offset given with each successive call to this method
+will not be smaller than the offset given at the previous invocation.
+*/
+func (f *BaseCharFilter) AddOffCorrectMap(off, cumulativeDiff int) {
+	n := len(f.offsets)
+	if n > 0 {
+		assert2(off >= f.offsets[n-1], "Offset #%v(%v) is less than the last recorded offset %v",
+			n, off, f.offsets[n-1])
+	}
+	if n == 0 || off != f.offsets[n-1] {
+		f.offsets = append(f.offsets, off)
+		f.diffs = append(f.diffs, cumulativeDiff)
+	} else { // Overwrite the diff at the last recorded offset
+		f.diffs[n-1] = cumulativeDiff
+	}
+}
@@ -0,0 +1,127 @@
+package analysis
+
+import (
+	"fmt"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"io"
+	"strings"
+)
+
+/*
+A Normalizer is an analyzer restricted to char filters and token
+filters: it does not split its input, but transforms the whole value
+into a single term. It is applied to non-tokenized (keyword) fields,
+so that exact matching can be made case or accent insensitive.
+*/
+type Normalizer interface {
+	// Returns the normalized form of text for the given field.
+	Normalize(fieldName, text string) (string, error)
+}
+
+/*
+A Normalizer built from a chain of char filters, applied first, and
+token filters, applied to the single token holding the whole value.
+
+The filters must neither split nor remove the token: normalizing
+fails if the chain does not produce exactly one token.
+*/
+type CustomNormalizer struct {
+	charFilters  []func(io.RuneReader) io.RuneReader
+	tokenFilters []func(TokenStream) TokenStream
+}
+
+/* Creates a normalizer applying the given char filters, then the given token filters. */
+func NewCustomNormalizer(charFilters []func(io.RuneReader) io.RuneReader,
+	tokenFilters []func(TokenStream) TokenStream) *CustomNormalizer {
+	return &CustomNormalizer{charFilters, tokenFilters}
+}
+
+func (n *CustomNormalizer) Normalize(fieldName, text string) (s string, err error) {
+	var reader io.RuneReader = strings.NewReader(text)
+	for _, f := range n.charFilters {
+		reader = f(reader)
+	}
+	var ts TokenStream = newSingleTokenStream(reader)
+	for _, f := range n.tokenFilters {
+		ts = f(ts)
+	}
+	defer func() {
+		if err2 := ts.Close(); err == nil {
+			err = err2
+		}
+	}()
+
+	termAtt := ts.Attributes().Add("CharTermAttribute").(CharTermAttribute)
+	if err = ts.Reset(); err != nil {
+		return "", err
+	}
+	ok, err := ts.IncrementToken()
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", fmt.Errorf("the normalization token stream for field '%v' produced no token", fieldName)
+	}
+	s = string(termAtt.Buffer()[:termAtt.Length()])
+	if ok, err = ts.IncrementToken(); err != nil {
+		return "", err
+	} else if ok {
+		return "", fmt.Errorf("the normalization token stream for field '%v' produced more than one token", fieldName)
+	}
+	return s, ts.End()
+}
+
+/* Emits the whole input as a single token, as KeywordTokenizer does. */
+type singleTokenStream struct {
+	*Tokenizer
+	done        bool
+	finalOffset int
+	termAtt     CharTermAttribute
+	offsetAtt   OffsetAttribute
+}
+
+func newSingleTokenStream(input io.RuneReader) *singleTokenStream {
+	ans := &singleTokenStream{Tokenizer: NewTokenizer(input)}
+	ans.termAtt = ans.Attributes().Add("CharTermAttribute").(CharTermAttribute)
+	ans.offsetAtt = ans.Attributes().Add("OffsetAttribute").(OffsetAttribute)
+	return ans
+}
+
+func (ts *singleTokenStream) IncrementToken() (bool, error) {
+	if ts.done {
+		return false, nil
+	}
+	ts.Attributes().Clear()
+	ts.done = true
+	var buf []rune
+	for {
+		ch, _, err := ts.Input.ReadRune()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return false, err
+		}
+		buf = append(buf, ch)
+	}
+	ts.termAtt.CopyBuffer(buf)
+	ts.finalOffset = ts.CorrectOffset(len(buf))
+	ts.offsetAtt.SetOffset(ts.CorrectOffset(0), ts.finalOffset)
+	return true, nil
+}
+
+func (ts *singleTokenStream) End() error {
+	if err := ts.Tokenizer.End(); err != nil {
+		return err
+	}
+	ts.offsetAtt.SetOffset(ts.finalOffset, ts.finalOffset)
+	return nil
+}
+
+func (ts *singleTokenStream) Reset() error {
+	if err := ts.Tokenizer.Reset(); err != nil {
+		return err
+	}
+	ts.done = false
+	ts.finalOffset = 0
+	return nil
+}
@@ -0,0 +1,213 @@
+package analysis
+
+import (
+	ta "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"github.com/balzaczyy/golucene/core/util/automaton"
+)
+
+// analysis/TokenStreamToAutomaton.java
+
+const (
+	// We create transition between two adjacent tokens.
+	POS_SEP = 0x001f
+	// We add this arc to represent a hole.
+	HOLE = 0x001e
+)
+
+/*
+Consumes a TokenStream and creates an Automaton where the transition
+labels are UTF8 bytes from the TermToBytesRefAttribute. Between
+tokens we insert POS_SEP and for holes we insert HOLE.
+*/
+type TokenStreamToAutomaton struct {
+	preservePositionIncrements bool
+	changeToken                func([]byte) []byte
+}
+
+func NewTokenStreamToAutomaton() *TokenStreamToAutomaton {
+	return &TokenStreamToAutomaton{
+		preservePositionIncrements: true,
+		changeToken:                func(in []byte) []byte { return in },
+	}
+}
+
+/*
+Whether to generate holes in the automaton for missing positions,
+true by default.
+*/
+func (ts2a *TokenStreamToAutomaton) SetPreservePositionIncrements(enablePositionIncrements bool) {
+	ts2a.preservePositionIncrements = enablePositionIncrements
+}
+
+/*
+Sets the function used to change the token's bytes before they are
+added to the automaton, e.g. to escape reserved labels.
+*/
+func (ts2a *TokenStreamToAutomaton) SetChangeToken(f func([]byte) []byte) {
+	ts2a.changeToken = f
+}
+
+type position struct {
+	// Any tokens that ended at our position arrive to this state:
+	arriving int
+	// Any tokens that start at our position leave from this state:
+	leaving int
+}
+
+type positions map[int]*position
+
+func (p positions) get(pos int) *position {
+	ans, ok := p[pos]
+	if !ok {
+		ans = &position{-1, -1}
+		p[pos] = ans
+	}
+	return ans
+}
+
+/*
+Pulls the graph (including PositionLengthAttribute) from the provided
+TokenStream, and creates the corresponding automaton where arcs are
+UTF8 bytes from each term.
+*/
+func (ts2a *TokenStreamToAutomaton) ToAutomaton(in TokenStream) (*automaton.Automaton, error) {
+	builder := automaton.NewAutomatonBuilder()
+	builder.CreateState()
+
+	termBytesAtt := in.Attributes().Add("TermToBytesRefAttribute").(ta.TermToBytesRefAttribute)
+	posIncAtt := in.Attributes().Add("PositionIncrementAttribute").(ta.PositionIncrementAttribute)
+	posLengthAtt := in.Attributes().Add("PositionLengthAttribute").(ta.PositionLengthAttribute)
+	offsetAtt := in.Attributes().Add("OffsetAttribute").(ta.OffsetAttribute)
+
+	if err := in.Reset(); err != nil {
+		return nil, err
+	}
+
+	// Only temporarily holds states ahead of our current position:
+	positions := make(positions)
+	pos, maxPos := -1, -1
+	var posData *position
+	maxOffset := 0
+	for {
+		ok, err := in.IncrementToken()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			break
+		}
+		posInc := posIncAtt.PositionIncrement()
+		if !ts2a.preservePositionIncrements && posInc > 1 {
+			posInc = 1
+		}
+		assert2(pos > -1 || posInc > 0, "first token must have posInc > 0")
+
+		if posInc > 0 {
+			// New node:
+			pos += posInc
+
+			posData = positions.get(pos)
+			assert2(posData.leaving == -1, "position %v left twice", pos)
+
+			if posData.arriving == -1 {
+				// No token ever arrived to this position
+				if pos == 0 {
+					// OK: this is the first token
+					posData.leaving = 0
+				} else {
+					// This means there's a hole (eg, StopFilter does this):
+					posData.leaving = builder.CreateState()
+					addHoles(builder, positions, pos)
+				}
+			} else {
+				posData.leaving = builder.CreateState()
+				builder.AddTransition(posData.arriving, posData.leaving, POS_SEP)
+				if posInc > 1 {
+					// A token spanned over a hole; add holes "under" it:
+					addHoles(builder, positions, pos)
+				}
+			}
+		}
+
+		endPos := pos + posLengthAtt.PositionLength()
+		if endPos > maxPos {
+			maxPos = endPos
+		}
+
+		termBytesAtt.FillBytesRef()
+		term := termBytesAtt.BytesRef()
+		termUTF8 := ts2a.changeToken(term.Bytes[term.Offset : term.Offset+term.Length])
+		endPosData := positions.get(endPos)
+		if endPosData.arriving == -1 {
+			endPosData.arriving = builder.CreateState()
+		}
+
+		state := posData.leaving
+		for i, b := range termUTF8 {
+			nextState := endPosData.arriving
+			if i < len(termUTF8)-1 {
+				nextState = builder.CreateState()
+			}
+			builder.AddTransition(state, nextState, int(b))
+			state = nextState
+		}
+
+		if offsetAtt.EndOffset() > maxOffset {
+			maxOffset = offsetAtt.EndOffset()
+		}
+	}
+
+	if err := in.End(); err != nil {
+		return nil, err
+	}
+	endState := -1
+	if offsetAtt.EndOffset() > maxOffset {
+		endState = builder.CreateState()
+		builder.SetAccept(endState, true)
+	}
+
+	for pos++; pos <= maxPos; pos++ {
+		posData = positions.get(pos)
+		if posData.arriving != -1 {
+			if endState != -1 {
+				builder.AddTransition(posData.arriving, endState, POS_SEP)
+			} else {
+				builder.SetAccept(posData.arriving, true)
+			}
+		}
+	}
+
+	return builder.Finish(), nil
+}
+
+func addHoles(builder *automaton.AutomatonBuilder, positions positions, pos int) {
+	posData := positions.get(pos)
+	prevPosData := positions.get(pos - 1)
+
+	for posData.arriving == -1 || prevPosData.leaving == -1 {
+		if posData.arriving == -1 {
+			posData.arriving = builder.CreateState()
+			builder.AddTransition(posData.arriving, posData.leaving, POS_SEP)
+		}
+
+		if prevPosData.leaving == -1 {
+			if pos == 1 {
+				prevPosData.leaving = 0
+			} else {
+				prevPosData.leaving = builder.CreateState()
+			}
+			if prevPosData.arriving != -1 {
+				builder.AddTransition(prevPosData.arriving, prevPosData.leaving, POS_SEP)
+			}
+		}
+
+		builder.AddTransition(prevPosData.leaving, posData.arriving, HOLE)
+
+		pos--
+		if pos <= 0 {
+			break
+		}
+		posData = prevPosData
+		prevPosData = positions.get(pos - 1)
+	}
+}
@@ -16,7 +16,15 @@ type CharTermAttribute interface {
 	//
 	// NOTE: the returned buffer may be larger than the valid Length().
 	Buffer() []rune
+	// Grows the termBuffer to at least size newSize, preserving the
+	// existing content.
+	ResizeBuffer(newSize int) []rune
 	Length() int
+	// Set number of valid characters (length of the term) in the
+	// termBuffer slice. Use this to truncate the termBuffer or to
+	// synchronize with external manipulation of the termBuffer.
+	// Note: to grow the size of the slice, use ResizeBuffer(int) first.
+	SetLength(length int) CharTermAttribute
 	// Appends teh specified string to this character sequence.
 	//
 	// The character of the string argument are appended, in order,
@@ -56,6 +64,17 @@ func (a *CharTermAttributeImpl) Buffer() []rune {
 	return a.termBuffer
 }
 
+func (a *CharTermAttributeImpl) ResizeBuffer(newSize int) []rune {
+	if len(a.termBuffer) < newSize {
+		// not big enough; create a new slice with slight over allocation
+		// and preserve content
+		newCharBuffer := make([]rune, util.Oversize(newSize, util.NUM_BYTES_CHAR))
+		copy(newCharBuffer, a.termBuffer)
+		a.termBuffer = newCharBuffer
+	}
+	return a.termBuffer
+}
+
 func (a *CharTermAttributeImpl) growTermBuffer(newSize int) {
 	if len(a.termBuffer) < newSize {
 		// not big enough: create a new slice with slight over allocation:
@@ -76,6 +95,13 @@ func (a *CharTermAttributeImpl) Length() int {
 	return a.termLength
 }
 
+func (a *CharTermAttributeImpl) SetLength(length int) CharTermAttribute {
+	assert2(length <= len(a.termBuffer),
+		"length %v exceeds the size of the termBuffer (%v)", length, len(a.termBuffer))
+	a.termLength = length
+	return a
+}
+
 func (a *CharTermAttributeImpl) AppendString(s string) CharTermAttribute {
 	if s == "" { // needed for Appendable compliance
 		return a.appendNil()
@@ -121,3 +147,9 @@ func (a *CharTermAttributeImpl) String() string {
 func (a *CharTermAttributeImpl) CopyTo(target util.AttributeImpl) {
 	target.(CharTermAttribute).CopyBuffer(a.termBuffer[:a.termLength])
 }
+
+func (a *CharTermAttributeImpl) ReflectWith(reflector util.AttributeReflector) {
+	reflector("CharTermAttribute", "term", a.String())
+	a.FillBytesRef()
+	reflector("TermToBytesRefAttribute", "bytes", a.BytesRef())
+}
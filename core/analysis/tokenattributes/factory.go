@@ -5,27 +5,32 @@ import (
 	"github.com/balzaczyy/golucene/core/util"
 )
 
+func init() {
+	util.RegisterAttributeImpl(newPositionIncrementAttributeImpl)
+	util.RegisterAttributeImpl(newPositionLengthAttributeImpl)
+	util.RegisterAttributeImpl(func() util.AttributeImpl { return newCharTermAttributeImpl() })
+	util.RegisterAttributeImpl(newOffsetAttributeImpl)
+	util.RegisterAttributeImpl(newTypeAttributeImpl)
+	util.RegisterAttributeImpl(newPayloadAttributeImpl)
+	util.RegisterAttributeImpl(newKeywordAttributeImpl)
+}
+
+/*
+Creates the AttributeImpl registered for the given Attribute with
+util.RegisterAttributeImpl(). The built-in token attributes are
+registered by this package; other packages register their own.
+*/
 type DefaultAttributeFactory struct{}
 
 func (fac *DefaultAttributeFactory) Create(name string) util.AttributeImpl {
-	switch name {
-	case "PositionIncrementAttribute":
-		return newPositionIncrementAttributeImpl()
-	case "CharTermAttribute":
-		return newCharTermAttributeImpl()
-	case "OffsetAttribute":
-		return newOffsetAttributeImpl()
-	case "TypeAttribute":
-		return newTypeAttributeImpl()
-	case "PayloadAttribute":
-		return newPayloadAttributeImpl()
+	if att := util.NewAttributeImpl(name); att != nil {
+		return att
 	}
-	panic(fmt.Sprintf("not supported yet: %v", name))
+	panic(fmt.Sprintf("no AttributeImpl registered for %v", name))
 }
 
 /*
 This is the default factory that creates AttributeImpls using the
-class name of the supplied Attribute interface class by appending
-Impl to it.
+constructors registered for the supplied Attribute interface name.
 */
 var DEFAULT_ATTRIBUTE_FACTORY = new(DefaultAttributeFactory)
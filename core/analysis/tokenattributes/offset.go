@@ -52,7 +52,7 @@ func (a *OffsetAttributeImpl) SetOffset(startOffset, endOffset int) {
 		"startOffset must be non-negative, and endOffset must be >= startOffset, startOffset=%v,endOffset=%v",
 		startOffset, endOffset)
 	a.startOffset = startOffset
-	a.endOffset = a.endOffset
+	a.endOffset = endOffset
 }
 
 func (a *OffsetAttributeImpl) EndOffset() int {
@@ -75,3 +75,8 @@ func (a *OffsetAttributeImpl) Clone() util.AttributeImpl {
 func (a *OffsetAttributeImpl) CopyTo(target util.AttributeImpl) {
 	target.(OffsetAttribute).SetOffset(a.startOffset, a.endOffset)
 }
+
+func (a *OffsetAttributeImpl) ReflectWith(reflector util.AttributeReflector) {
+	reflector("OffsetAttribute", "startOffset", a.startOffset)
+	reflector("OffsetAttribute", "endOffset", a.endOffset)
+}
@@ -44,6 +44,14 @@ func (a *PackedTokenAttributeImpl) PositionIncrement() int {
 	return a.positionIncrement
 }
 
+func (a *PackedTokenAttributeImpl) SetPositionLength(positionLength int) {
+	a.positionLength = positionLength
+}
+
+func (a *PackedTokenAttributeImpl) PositionLength() int {
+	return a.positionLength
+}
+
 func (a *PackedTokenAttributeImpl) StartOffset() int {
 	return a.startOffset
 }
@@ -60,6 +68,10 @@ func (a *PackedTokenAttributeImpl) SetOffset(startOffset, endOffset int) {
 	a.endOffset = endOffset
 }
 
+func (a *PackedTokenAttributeImpl) Type() string {
+	return a.typ
+}
+
 func (a *PackedTokenAttributeImpl) SetType(typ string) {
 	a.typ = typ
 }
@@ -98,3 +110,12 @@ func (a *PackedTokenAttributeImpl) CopyTo(target util.AttributeImpl) {
 		target.(TypeAttribute).SetType(a.typ)
 	}
 }
+
+func (a *PackedTokenAttributeImpl) ReflectWith(reflector util.AttributeReflector) {
+	a.CharTermAttributeImpl.ReflectWith(reflector)
+	reflector("OffsetAttribute", "startOffset", a.startOffset)
+	reflector("OffsetAttribute", "endOffset", a.endOffset)
+	reflector("PositionIncrementAttribute", "positionIncrement", a.positionIncrement)
+	reflector("PositionLengthAttribute", "positionLength", a.positionLength)
+	reflector("TypeAttribute", "type", a.typ)
+}
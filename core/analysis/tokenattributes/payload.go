@@ -47,3 +47,7 @@ func (a *PayloadAttributeImpl) Clone() util.AttributeImpl {
 func (a *PayloadAttributeImpl) CopyTo(target util.AttributeImpl) {
 	target.(PayloadAttribute).SetPayload(a.payload)
 }
+
+func (a *PayloadAttributeImpl) ReflectWith(reflector util.AttributeReflector) {
+	reflector("PayloadAttribute", "payload", a.payload)
+}
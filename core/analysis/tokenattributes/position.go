@@ -78,3 +78,7 @@ func (a *PositionIncrementAttributeImpl) Clone() util.AttributeImpl {
 func (a *PositionIncrementAttributeImpl) CopyTo(target util.AttributeImpl) {
 	target.(PositionIncrementAttribute).SetPositionIncrement(a.positionIncrement)
 }
+
+func (a *PositionIncrementAttributeImpl) ReflectWith(reflector util.AttributeReflector) {
+	reflector("PositionIncrementAttribute", "positionIncrement", a.positionIncrement)
+}
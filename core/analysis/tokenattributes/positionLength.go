@@ -4,7 +4,59 @@ import (
 	"github.com/balzaczyy/golucene/core/util"
 )
 
+/*
+Determines how many positions this token spans. Very few analyzer
+components actually produce this attribute, and indexing ignores it,
+but it's useful to express the graph structure naturally produced by
+decompounding, word splitting/joining, synonym filtering, etc.
+
+NOTE: this is optional, and most analyzers don't change the default
+value (1).
+*/
 type PositionLengthAttribute interface {
 	util.Attribute
 	SetPositionLength(int)
+	PositionLength() int
+}
+
+/* Default implementation of PositionLengthAttribute. */
+type PositionLengthAttributeImpl struct {
+	positionLength int
+}
+
+func newPositionLengthAttributeImpl() util.AttributeImpl {
+	return &PositionLengthAttributeImpl{
+		positionLength: 1,
+	}
+}
+
+func (a *PositionLengthAttributeImpl) Interfaces() []string {
+	return []string{"PositionLengthAttribute"}
+}
+
+func (a *PositionLengthAttributeImpl) SetPositionLength(positionLength int) {
+	assert2(positionLength >= 1, "Position length must be 1 or greater: got %v", positionLength)
+	a.positionLength = positionLength
+}
+
+func (a *PositionLengthAttributeImpl) PositionLength() int {
+	return a.positionLength
+}
+
+func (a *PositionLengthAttributeImpl) Clear() {
+	a.positionLength = 1
+}
+
+func (a *PositionLengthAttributeImpl) Clone() util.AttributeImpl {
+	return &PositionLengthAttributeImpl{
+		positionLength: a.positionLength,
+	}
+}
+
+func (a *PositionLengthAttributeImpl) CopyTo(target util.AttributeImpl) {
+	target.(PositionLengthAttribute).SetPositionLength(a.positionLength)
+}
+
+func (a *PositionLengthAttributeImpl) ReflectWith(reflector util.AttributeReflector) {
+	reflector("PositionLengthAttribute", "positionLength", a.positionLength)
 }
@@ -6,6 +6,12 @@ import (
 
 // analysis/Tokenizer.java
 
+/* A TokenStream whose input can be set with SetReader(), as the source of TokenStreamComponents. */
+type TokenizerService interface {
+	TokenStream
+	SetReader(io.RuneReader) error
+}
+
 /*
 A Tokenizer is a TokenStream whose input is a Reader.
 
@@ -144,7 +144,7 @@ func (f *segmentTermsEnumFrame) loadBlock() (err error) {
 	assert(f.entCount > 0)
 	f.isLastInFloor = (code & 1) != 0
 
-	assert2(f.arc == nil || !f.isLastInFloor || !f.isFloor,
+	assert2(f.arc == nil || f.isLastInFloor || f.isFloor,
 		"fp=%v arc=%v isFloor=%v isLastInFloor=%v",
 		f.fp, f.arc, f.isFloor, f.isLastInFloor)
 
@@ -243,11 +243,90 @@ func (f *segmentTermsEnumFrame) next() bool {
 
 // Decodes next entry; returns true if it's a sub-block
 func (f *segmentTermsEnumFrame) nextLeaf() bool {
-	panic("not implemented yet")
+	// fmt.Printf("  frame.next ord=%v nextEnt=%v entCount=%v\n", f.ord, f.nextEnt, f.entCount)
+	assert2(f.nextEnt != -1 && f.nextEnt < f.entCount,
+		"nextEnt=%v entCount=%v fp=%v", f.nextEnt, f.entCount, f.fp)
+	f.nextEnt++
+	f.suffix, _ = asInt(f.suffixesReader.ReadVInt()) // no error
+	f.startBytePos = f.suffixesReader.Position()
+	f.fillTerm()
+	f.suffixesReader.SkipBytes(int64(f.suffix))
+	// A normal term
+	f.ste.termExists = true
+	return false
 }
 
 func (f *segmentTermsEnumFrame) nextNonLeaf() bool {
-	panic("not implemented yet")
+	// fmt.Printf("  frame.next ord=%v nextEnt=%v entCount=%v\n", f.ord, f.nextEnt, f.entCount)
+	assert2(f.nextEnt != -1 && f.nextEnt < f.entCount,
+		"nextEnt=%v entCount=%v fp=%v", f.nextEnt, f.entCount, f.fp)
+	f.nextEnt++
+	code, _ := f.suffixesReader.ReadVInt() // no error
+	f.suffix = int(uint32(code) >> 1)
+	f.startBytePos = f.suffixesReader.Position()
+	f.fillTerm()
+	f.suffixesReader.SkipBytes(int64(f.suffix))
+	if (code & 1) == 0 {
+		// A normal term
+		f.ste.termExists = true
+		f.subCode = 0
+		f.state.TermBlockOrd++
+		return false
+	}
+	// A sub-block; make sub-FP absolute:
+	f.ste.termExists = false
+	f.subCode, _ = f.suffixesReader.ReadVLong() // no error
+	f.lastSubFP = f.fp - f.subCode
+	// fmt.Printf("    lastSubFP=%v\n", f.lastSubFP)
+	return true
+}
+
+/*
+Only for next(); for seek, we use scanToFloorFrame() to jump to the
+right floor block directly.
+*/
+func (f *segmentTermsEnumFrame) loadNextFloorBlock() error {
+	// fmt.Printf("    loadNextFloorBlock fp=%v fpEnd=%v\n", f.fp, f.fpEnd)
+	assert2(f.arc == nil || f.isFloor, "arc=%v isFloor=%v", f.arc, f.isFloor)
+	f.fp = f.fpEnd
+	f.nextEnt = -1
+	return f.loadBlock()
+}
+
+/*
+Used when we are popping back to a frame that was not yet scanned
+(or not scanned to the right entry) by next(): moves the frame to the
+entry pointing at the given sub-block.
+*/
+func (f *segmentTermsEnumFrame) scanToSubBlock(subFP int64) {
+	assert(!f.isLeafBlock)
+	// fmt.Printf("  scanToSubBlock fp=%v subFP=%v entCount=%v lastSubFP=%v\n",
+	// 	f.fp, subFP, f.entCount, f.lastSubFP)
+	if f.lastSubFP == subFP {
+		// fmt.Println("    already positioned")
+		return
+	}
+	assert2(subFP < f.fp, "fp=%v subFP=%v", f.fp, subFP)
+	targetSubCode := f.fp - subFP
+	for {
+		assert(f.nextEnt < f.entCount)
+		f.nextEnt++
+		code, _ := f.suffixesReader.ReadVInt() // no error
+		if f.isLeafBlock {
+			f.suffixesReader.SkipBytes(int64(code))
+		} else {
+			f.suffixesReader.SkipBytes(int64(uint32(code) >> 1))
+		}
+		if (code & 1) != 0 {
+			subCode, _ := f.suffixesReader.ReadVLong() // no error
+			if targetSubCode == subCode {
+				f.lastSubFP = subFP
+				return
+			}
+		} else {
+			f.state.TermBlockOrd++
+		}
+	}
 }
 
 // TODO: make this array'd so we can do bin search?
@@ -261,10 +340,10 @@ func (f *segmentTermsEnumFrame) scanToFloorFrame(target []byte) {
 	}
 
 	targetLabel := int(target[f.prefix])
-	fmt.Printf("    scanToFloorFrame fpOrig=%v targetLabel=%x vs nextFloorLabel=%x numFollowFloorBlocks=%v\n",
-		f.fpOrig, targetLabel, f.nextFloorLabel, f.numFollowFloorBlocks)
+	// fmt.Printf("    scanToFloorFrame fpOrig=%v targetLabel=%x vs nextFloorLabel=%x numFollowFloorBlocks=%v\n",
+	// 	f.fpOrig, targetLabel, f.nextFloorLabel, f.numFollowFloorBlocks)
 	if targetLabel < f.nextFloorLabel {
-		fmt.Println("      already on correct block")
+		// fmt.Println("      already on correct block")
 		return
 	}
 
@@ -283,16 +362,21 @@ func (f *segmentTermsEnumFrame) scanToFloorFrame(target []byte) {
 
 		if f.isLastInFloor {
 			f.nextFloorLabel = 256
-			fmt.Printf("        stop!  last block nextFloorLabel=%x\n", f.nextFloorLabel)
+			// fmt.Printf("        stop!  last block nextFloorLabel=%x\n", f.nextFloorLabel)
+			break
+		}
+		b, _ := f.floorDataReader.ReadByte() // ignore error
+		f.nextFloorLabel = int(b)
+		// fmt.Printf("        nextFloorLabel=%x\n", f.nextFloorLabel)
+		if targetLabel < f.nextFloorLabel {
+			// fmt.Println("        stop!")
 			break
-		} else {
-			panic("niy")
 		}
 	}
 
 	if newFP != f.fp {
 		// Force re-load of the block:
-		fmt.Printf("      force switch to fp=%v oldFP=%v\n", newFP, f.fp)
+		// fmt.Printf("      force switch to fp=%v oldFP=%v\n", newFP, f.fp)
 		f.nextEnt = -1
 		f.fp = newFP
 	} else {
@@ -481,7 +565,7 @@ func (f *segmentTermsEnumFrame) scanToTermLeaf(target []byte, exactOnly bool) (s
 	// to the foo* block, but the last term in this block
 	// was fooz (and, eg, first term in the next block will
 	// bee fop).
-	fmt.Println("      block end")
+	// fmt.Println("      block end")
 	if exactOnly {
 		f.fillTerm()
 	}
@@ -497,20 +581,23 @@ func (f *segmentTermsEnumFrame) scanToTermLeaf(target []byte, exactOnly bool) (s
 func (f *segmentTermsEnumFrame) scanToTermNonLeaf(target []byte,
 	exactOnly bool) (status SeekStatus, err error) {
 
-	fmt.Printf(
-		"    scanToTermNonLeaf: block fp=%v prefix=%v nextEnt=%v (of %v) target=%v term=%v",
-		f.fp, f.prefix, f.nextEnt, f.entCount, brToString(target), "" /*brToString(term)*/)
+	// fmt.Printf("    scanToTermNonLeaf: block fp=%v prefix=%v nextEnt=%v (of %v) target=%v term=%v\n",
+	// 	f.fp, f.prefix, f.nextEnt, f.entCount, brToString(target), f.ste.term)
 
 	assert(f.nextEnt != -1)
 
 	if f.nextEnt == f.entCount {
-		panic("not implemented yet")
+		if exactOnly {
+			f.fillTerm()
+			f.ste.termExists = f.subCode == 0
+		}
+		return SEEK_STATUS_END, nil
 	}
 
 	assert(f.prefixMatches(target))
 
 	// Loop over each entry (term or sub-block) in this block:
-	for {
+	for f.nextEnt < f.entCount {
 		f.nextEnt++
 
 		code, _ := f.suffixesReader.ReadVInt() // no error
@@ -536,7 +623,6 @@ func (f *segmentTermsEnumFrame) scanToTermNonLeaf(target []byte,
 
 		// Loop over bytes in the suffix, comparing to the target
 		bytePos := f.startBytePos
-		var toNextTerm, stopScan bool
 		for {
 			var cmp int
 			var stop bool
@@ -554,27 +640,33 @@ func (f *segmentTermsEnumFrame) scanToTermNonLeaf(target []byte,
 			if cmp < 0 {
 				// Current entry is still before the target;
 				// keep scanning
-
-				if f.nextEnt == f.entCount {
-					if exactOnly {
-						f.fillTerm()
-					}
-					// We are done scanning this block
-					stopScan = true
-					break
-				} else {
-					toNextTerm = true
-					break
-				}
+				break
 			} else if cmp > 0 {
 				// Done! Current entry is after target -- return NOT_FOUND:
 				f.fillTerm()
 
 				if !exactOnly && !f.ste.termExists {
-					panic("niy")
+					// We are on a sub-block, and caller wants us to
+					// position to the next term after the target, so we
+					// must recurse into the sub-frame(s):
+					ste := f.ste
+					if ste.currentFrame, err = ste.pushFrameAt(nil, ste.currentFrame.lastSubFP, termLen); err != nil {
+						return 0, err
+					}
+					if err = ste.currentFrame.loadBlock(); err != nil {
+						return 0, err
+					}
+					for ste.currentFrame.next() {
+						if ste.currentFrame, err = ste.pushFrameAt(nil, ste.currentFrame.lastSubFP, ste.term.Length()); err != nil {
+							return 0, err
+						}
+						if err = ste.currentFrame.loadBlock(); err != nil {
+							return 0, err
+						}
+					}
 				}
 
-				fmt.Println("        not found")
+				// fmt.Println("        not found")
 				return SEEK_STATUS_NOT_FOUND, nil
 			} else if stop {
 				// Exact match!
@@ -584,16 +676,10 @@ func (f *segmentTermsEnumFrame) scanToTermNonLeaf(target []byte,
 
 				assert(f.ste.termExists)
 				f.fillTerm()
-				fmt.Println("        found!")
+				// fmt.Println("        found!")
 				return SEEK_STATUS_FOUND, nil
 			}
 		}
-		if toNextTerm {
-			continue
-		}
-		if stopScan {
-			break
-		}
 	}
 
 	// It is possible (and OK) that terms index pointed us at this
@@ -603,7 +689,7 @@ func (f *segmentTermsEnumFrame) scanToTermNonLeaf(target []byte,
 	// E.g., target could be foozzz, and terms index pointed us to the
 	// foo* block, but the last term in this block was fooz (and, e.g.,
 	// first term in the next block will be fop).
-	fmt.Println("      block end")
+	// fmt.Println("      block end")
 	if exactOnly {
 		f.fillTerm()
 	}
@@ -449,7 +449,8 @@ func (w *TermsWriter) writeBlocks(prefixLength, count int) (err error) {
 	nextBlockStart := start
 	nextFloorLeadLabel := -1
 
-	for i, ent := range w.pending[start:] {
+	for i := start; i < end; i++ {
+		ent := w.pending[i]
 		var suffixLeadLabel int
 		if ent.isTerm() {
 			term := ent.(*PendingTerm)
@@ -468,7 +469,7 @@ func (w *TermsWriter) writeBlocks(prefixLength, count int) (err error) {
 		}
 
 		if suffixLeadLabel != lastSuffixLeadLabel {
-			if itemsInBlock := i + count - nextBlockStart; itemsInBlock >= w.owner.minItemsInBlock &&
+			if itemsInBlock := i - nextBlockStart; itemsInBlock >= w.owner.minItemsInBlock &&
 				end-nextBlockStart > w.owner.maxItemsInBlock {
 				// The count is too large for one block, so we must break
 				// it into "floor" blocks, where we record the leading
@@ -481,7 +482,7 @@ func (w *TermsWriter) writeBlocks(prefixLength, count int) (err error) {
 				isFloor := itemsInBlock < count
 				var block *PendingBlock
 				if block, err = w.writeBlock(prefixLength, isFloor,
-					nextFloorLeadLabel, nextBlockStart, i+count, hasTerms,
+					nextFloorLeadLabel, nextBlockStart, i, hasTerms,
 					hasSubBlocks); err != nil {
 					return
 				}
@@ -490,7 +491,7 @@ func (w *TermsWriter) writeBlocks(prefixLength, count int) (err error) {
 				hasTerms = false
 				hasSubBlocks = false
 				nextFloorLeadLabel = suffixLeadLabel
-				nextBlockStart = i + count
+				nextBlockStart = i
 			}
 
 			lastSuffixLeadLabel = suffixLeadLabel
@@ -182,7 +182,15 @@ func (r *CompressingStoredFieldsReader) readField(in util.DataInput,
 	visitor StoredFieldVisitor, info *model.FieldInfo, bits int) (err error) {
 	switch bits & TYPE_MASK {
 	case BYTE_ARR:
-		panic("not implemented yet")
+		var length int
+		if length, err = int32AsInt(in.ReadVInt()); err != nil {
+			return err
+		}
+		data := make([]byte, length)
+		if err = in.ReadBytes(data); err != nil {
+			return err
+		}
+		visitor.BinaryField(info, data)
 	case STRING:
 		var length int
 		if length, err = int32AsInt(in.ReadVInt()); err != nil {
@@ -198,7 +206,11 @@ func (r *CompressingStoredFieldsReader) readField(in util.DataInput,
 	case NUMERIC_FLOAT:
 		panic("not implemented yet")
 	case NUMERIC_LONG:
-		panic("not implemented yet")
+		var v int64
+		if v, err = in.ReadLong(); err != nil {
+			return err
+		}
+		visitor.LongField(info, v)
 	case NUMERIC_DOUBLE:
 		panic("not implemented yet")
 	default:
@@ -207,6 +219,23 @@ func (r *CompressingStoredFieldsReader) readField(in util.DataInput,
 	return nil
 }
 
+func (r *CompressingStoredFieldsReader) skipField(in util.DataInput, bits int) (err error) {
+	var length int
+	switch bits & TYPE_MASK {
+	case BYTE_ARR, STRING:
+		if length, err = int32AsInt(in.ReadVInt()); err != nil {
+			return err
+		}
+	case NUMERIC_INT, NUMERIC_FLOAT:
+		length = 4
+	case NUMERIC_LONG, NUMERIC_DOUBLE:
+		length = 8
+	default:
+		panic(fmt.Sprintf("Unknown type flag: %x", bits))
+	}
+	return in.ReadBytes(make([]byte, length))
+}
+
 func (r *CompressingStoredFieldsReader) VisitDocument(docID int, visitor StoredFieldVisitor) error {
 	err := r.fieldsStream.Seek(r.indexReader.startPointer(docID))
 	if err != nil {
@@ -253,7 +282,18 @@ func (r *CompressingStoredFieldsReader) VisitDocument(docID int, visitor StoredF
 			return errors.New(fmt.Sprintf("bitsPerStoredFields=%v (resource=%v)",
 				bitsPerStoredFields, r.fieldsStream))
 		} else {
-			panic("not implemented yet")
+			it := packed.ReaderIteratorNoHeader(
+				r.fieldsStream, packed.PackedFormat(packed.PACKED), r.packedIntsVersion,
+				chunkDocs, bitsPerStoredFields, 1)
+			for i := 0; i < chunkDocs; i++ {
+				n, err := it.Next()
+				if err != nil {
+					return err
+				}
+				if i == docID-docBase {
+					numStoredFields = int(n)
+				}
+			}
 		}
 
 		bitsPerLength, err := int32AsInt(r.fieldsStream.ReadVInt())
@@ -344,7 +384,9 @@ func (r *CompressingStoredFieldsReader) VisitDocument(docID int, visitor StoredF
 		case STORED_FIELD_VISITOR_STATUS_YES:
 			r.readField(documentInput, visitor, fieldInfo, bits)
 		case STORED_FIELD_VISITOR_STATUS_NO:
-			panic("not implemented yet")
+			if err = r.skipField(documentInput, bits); err != nil {
+				return err
+			}
 		case STORED_FIELD_VISITOR_STATUS_STOP:
 			return nil
 		}
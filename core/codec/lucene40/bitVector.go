@@ -34,6 +34,49 @@ func NewBitVector(n int) *BitVector {
 	}
 }
 
+/* Loads a BitVector from the file name in Directory d. */
+func NewBitVectorFrom(d store.Directory, name string, ctx store.IOContext) (bv *BitVector, err error) {
+	var input store.ChecksumIndexInput
+	if input, err = d.OpenChecksumInput(name, ctx); err != nil {
+		return nil, err
+	}
+	defer func() {
+		err = mergeError(err, input.Close())
+	}()
+
+	var firstInt int32
+	if firstInt, err = input.ReadInt(); err != nil {
+		return nil, err
+	}
+	if firstInt != -2 {
+		return nil, errors.New(fmt.Sprintf(
+			"unsupported BitVector format (resource=%v)", input))
+	}
+	// new format, with full header & version:
+	if _, err = codec.CheckHeader(input, CODEC, BV_VERSION_DGAPS_CLEARED, BV_VERSION_CURRENT); err != nil {
+		return nil, err
+	}
+	var size int32
+	if size, err = input.ReadInt(); err != nil {
+		return nil, err
+	}
+	bv = &BitVector{}
+	if size == -1 {
+		err = bv.readClearedDgaps(input)
+	} else {
+		bv.size = int(size)
+		err = bv.readBits(input)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, err = codec.CheckFooter(input); err != nil {
+		return nil, err
+	}
+	bv.assertCount()
+	return bv, nil
+}
+
 func numBytes(size int) int {
 	bytesLength := int(uint(size) >> 3)
 	if (size & 7) != 0 {
@@ -48,6 +91,12 @@ func (bv *BitVector) Clear(bit int) {
 	bv.count = -1
 }
 
+func (bv *BitVector) Clone() *BitVector {
+	bits := make([]byte, len(bv.bits))
+	copy(bits, bv.bits)
+	return &BitVector{bits, bv.size, bv.count}
+}
+
 func (bv *BitVector) At(bit int) bool {
 	assert2(bit >= 0 && bit < bv.size, "bit %v is out of bounds 0..%v", bit, bv.size-1)
 	return (bv.bits[bit>>3] & (1 << (uint(bit) & 7))) != 0
@@ -138,6 +187,16 @@ func (bv *BitVector) InvertAll() {
 		for idx, v := range bv.bits {
 			bv.bits[idx] = byte(^v)
 		}
+		bv.clearUnusedBits()
+	}
+}
+
+func (bv *BitVector) clearUnusedBits() {
+	// take care not to invert the "unused" bits in the last byte:
+	if len(bv.bits) > 0 {
+		if lastNBits := uint(bv.size & 7); lastNBits != 0 {
+			bv.bits[len(bv.bits)-1] &= byte(1<<lastNBits) - 1
+		}
 	}
 }
 
@@ -188,7 +247,83 @@ Indicates if the bit vector is sparse and should be saved as a d-gaps
 list, or dense, and should be saved as a bit set.
 */
 func (bv *BitVector) isSparse() bool {
-	panic("not implemented yet")
+	clearedCount := bv.size - bv.Count()
+	if clearedCount == 0 {
+		return true
+	}
+
+	avgGapLength := len(bv.bits) / clearedCount
+
+	// expected number of bytes for vint encoding of each gap
+	var expectedDGapBytes int
+	switch {
+	case avgGapLength <= (1 << 7):
+		expectedDGapBytes = 1
+	case avgGapLength <= (1 << 14):
+		expectedDGapBytes = 2
+	case avgGapLength <= (1 << 21):
+		expectedDGapBytes = 3
+	case avgGapLength <= (1 << 28):
+		expectedDGapBytes = 4
+	default:
+		expectedDGapBytes = 5
+	}
+
+	// +1 because we write the byte itself that contains the set bit
+	bytesPerSetBit := expectedDGapBytes + 1
+
+	// note: adding 32 because we start with int32(-1) to indicate
+	// d-gaps format.
+	expectedBits := int64(32 + 8*bytesPerSetBit*clearedCount)
+
+	// note: factor is for read/write of byte-arrays being faster than
+	// vints.
+	const factor = 10
+	return factor*expectedBits < int64(bv.size)
+}
+
+/* Read as a bit set */
+func (bv *BitVector) readBits(input store.IndexInput) error {
+	count, err := input.ReadInt()
+	if err != nil {
+		return err
+	}
+	bv.count = int(count)
+	bv.bits = make([]byte, numBytes(bv.size))
+	return input.ReadBytes(bv.bits)
+}
+
+/* Read as a d-gaps cleared bits list */
+func (bv *BitVector) readClearedDgaps(input store.IndexInput) error {
+	size, err := input.ReadInt()
+	if err != nil {
+		return err
+	}
+	count, err := input.ReadInt()
+	if err != nil {
+		return err
+	}
+	bv.size, bv.count = int(size), int(count)
+	bv.bits = make([]byte, numBytes(bv.size))
+	for i, _ := range bv.bits {
+		bv.bits[i] = 0xff
+	}
+	bv.clearUnusedBits()
+	last, numCleared := 0, bv.size-bv.count
+	for numCleared > 0 {
+		gap, err := input.ReadVInt()
+		if err != nil {
+			return err
+		}
+		last += int(gap)
+		if bv.bits[last], err = input.ReadByte(); err != nil {
+			return err
+		}
+		numCleared -= 8 - util.BitCount(bv.bits[last])
+		assert(numCleared >= 0 ||
+			last == len(bv.bits)-1 && numCleared == -(8-(bv.size&7)))
+	}
+	return nil
 }
 
 func (bv *BitVector) assertCount() {
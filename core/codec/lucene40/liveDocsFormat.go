@@ -61,6 +61,25 @@ func (format *Lucene40LiveDocsFormat) NewLiveDocs(size int) util.MutableBits {
 	return ans
 }
 
+func (format *Lucene40LiveDocsFormat) NewLiveDocsFrom(existing util.Bits) util.MutableBits {
+	return existing.(*BitVector).Clone()
+}
+
+func (format *Lucene40LiveDocsFormat) ReadLiveDocs(dir store.Directory,
+	info *SegmentCommitInfo, ctx store.IOContext) (util.Bits, error) {
+
+	filename := util.FileNameFromGeneration(info.Info.Name, DELETES_EXTENSION, info.DelGen())
+	liveDocs, err := NewBitVectorFrom(dir, filename, ctx)
+	if err != nil {
+		return nil, err
+	}
+	assert2(liveDocs.Count() == info.Info.DocCount()-info.DelCount(),
+		"liveDocs.count()=%v info.docCount=%v info.delCount=%v",
+		liveDocs.Count(), info.Info.DocCount(), info.DelCount())
+	assert(liveDocs.Length() == info.Info.DocCount())
+	return liveDocs, nil
+}
+
 func (format *Lucene40LiveDocsFormat) WriteLiveDocs(bits util.MutableBits,
 	dir store.Directory, info *SegmentCommitInfo, newDelCount int,
 	ctx store.IOContext) error {
@@ -299,7 +299,7 @@ type blockDocsEnum struct {
 
 	docBufferUpto int
 
-	// skipper Lucene41SkipReader
+	skipper *SkipReader
 	skipped bool
 
 	startDocIn store.IndexInput
@@ -346,7 +346,7 @@ func newBlockDocsEnum(owner *Lucene41PostingsReader,
 		docIn:                  nil,
 		indexHasFreq:           fieldInfo.IndexOptions() >= INDEX_OPT_DOCS_AND_FREQS,
 		indexHasPos:            fieldInfo.IndexOptions() >= INDEX_OPT_DOCS_AND_FREQS_AND_POSITIONS,
-		indexHasOffsets:        fieldInfo.IndexOptions() >= INDEX_OPT_DOCS_AND_FREQS_AND_POSITIONS,
+		indexHasOffsets:        fieldInfo.IndexOptions() >= INDEX_OPT_DOCS_AND_FREQS_AND_POSITIONS_AND_OFFSETS,
 		indexHasPayloads:       fieldInfo.HasPayloads(),
 		encoded:                make([]byte, MAX_ENCODED_SIZE),
 	}
@@ -410,8 +410,17 @@ func (de *blockDocsEnum) refillDocs() (err error) {
 	assert(left > 0)
 
 	if left >= LUCENE41_BLOCK_SIZE {
-		fmt.Println("    fill doc block from fp=", de.docIn.FilePointer())
-		panic("not implemented yet")
+		// fmt.Println("    fill doc block from fp=", de.docIn.FilePointer())
+		if err = de.forUtil.readBlock(de.docIn, de.encoded, de.docDeltaBuffer); err != nil {
+			return err
+		}
+		if de.indexHasFreq {
+			if de.needsFreq {
+				err = de.forUtil.readBlock(de.docIn, de.encoded, de.freqBuffer)
+			} else {
+				err = de.forUtil.skipBlock(de.docIn) // skip over freqs
+			}
+		}
 	} else if de.docFreq == 1 {
 		de.docDeltaBuffer[0] = de.singletonDocID
 		de.freqBuffer[0] = int(de.totalTermFreq)
@@ -460,14 +469,53 @@ func (de *blockDocsEnum) NextDoc() (n int, err error) {
 
 func (de *blockDocsEnum) Advance(target int) (int, error) {
 	// TODO: make frq block load lazy/skippable
-	fmt.Printf("  FPR.advance target=%v\n", target)
+	// fmt.Printf("  FPR.advance target=%v\n", target)
 
 	// current skip docID < docIDs generated from current buffer <= next
 	// skip docID, we don't need to skip if target is buffered already
 	if de.docFreq > LUCENE41_BLOCK_SIZE && target > de.nextSkipDoc {
-		fmt.Println("load skipper")
+		// fmt.Println("load skipper")
+
+		if de.skipper == nil {
+			// Lazy init: first time this enum has ever been used for skipping
+			de.skipper = NewSkipReader(de.docIn.Clone(), maxSkipLevels,
+				LUCENE41_BLOCK_SIZE, de.indexHasPos, de.indexHasOffsets, de.indexHasPayloads)
+		}
+
+		if !de.skipped {
+			assert(de.skipOffset != -1)
+			// This is the first time this enum has skipped since reset()
+			// was called; load the skip data:
+			de.skipper.Init(de.docTermStartFP+de.skipOffset, de.docTermStartFP, 0, 0, de.docFreq)
+			de.skipped = true
+		}
 
-		panic("not implemented yet")
+		// always plus one to fix the result, since skip position in
+		// SkipReader is a little different from MultiLevelSkipListReader
+		newDocUpto, err := de.skipper.SkipTo(target)
+		if err != nil {
+			return 0, err
+		}
+		newDocUpto++
+
+		if newDocUpto > de.docUpto {
+			// Skipper moved
+			// fmt.Printf("skipper moved to docUpto=%v vs current=%v; docID=%v fp=%v\n",
+			// 	newDocUpto, de.docUpto, de.skipper.Doc(), de.skipper.DocPointer())
+			assert(newDocUpto%LUCENE41_BLOCK_SIZE == 0)
+			de.docUpto = newDocUpto
+
+			// Force to read next block
+			de.docBufferUpto = LUCENE41_BLOCK_SIZE
+			de.accum = de.skipper.Doc()                  // actually, this is just lastSkipEntry
+			err = de.docIn.Seek(de.skipper.DocPointer()) // now point to the block we want to search
+			if err != nil {
+				return 0, err
+			}
+		}
+		// next time we call advance, this is used to foresee whether
+		// skipper is necessary.
+		de.nextSkipDoc = de.skipper.NextSkipDoc()
 	}
 	if de.docUpto == de.docFreq {
 		de.doc = NO_MORE_DOCS
@@ -476,13 +524,13 @@ func (de *blockDocsEnum) Advance(target int) (int, error) {
 	if de.docBufferUpto == LUCENE41_BLOCK_SIZE {
 		err := de.refillDocs()
 		if err != nil {
-			return 0, nil
+			return 0, err
 		}
 	}
 
 	// Now scan.. this is an inlined/pared down version of nextDoc():
 	for {
-		fmt.Printf("  scan doc=%v docBufferUpto=%v\n", de.accum, de.docBufferUpto)
+		// fmt.Printf("  scan doc=%v docBufferUpto=%v\n", de.accum, de.docBufferUpto)
 		de.accum += de.docDeltaBuffer[de.docBufferUpto]
 		de.docUpto++
 
@@ -497,14 +545,536 @@ func (de *blockDocsEnum) Advance(target int) (int, error) {
 	}
 
 	if de.liveDocs == nil || de.liveDocs.At(de.accum) {
-		fmt.Printf("  return doc=%v\n", de.accum)
+		// fmt.Printf("  return doc=%v\n", de.accum)
 		de.freq = de.freqBuffer[de.docBufferUpto]
 		de.docBufferUpto++
 		de.doc = de.accum
 		return de.doc, nil
 	} else {
-		fmt.Println("  now do nextDoc()")
+		// fmt.Println("  now do nextDoc()")
 		de.docBufferUpto++
 		return de.NextDoc()
 	}
 }
+
+func (r *Lucene41PostingsReader) DocsAndPositions(fieldInfo *FieldInfo,
+	termState *BlockTermState, liveDocs util.Bits,
+	reuse DocsAndPositionsEnum, flags int) (DocsAndPositionsEnum, error) {
+
+	var docsAndPositionsEnum *everythingEnum
+	if v, ok := reuse.(*everythingEnum); ok && v.canReuse(r.docIn, fieldInfo) {
+		docsAndPositionsEnum = v
+	} else {
+		docsAndPositionsEnum = newEverythingEnum(r, fieldInfo)
+	}
+	return docsAndPositionsEnum.reset(liveDocs, termState.Self.(*intBlockTermState), flags)
+}
+
+/*
+Also handles payloads + offsets.
+*/
+type everythingEnum struct {
+	*Lucene41PostingsReader // embedded struct
+
+	encoded []byte
+
+	docDeltaBuffer      []int
+	freqBuffer          []int
+	posDeltaBuffer      []int
+	payloadLengthBuffer []int
+
+	offsetStartDeltaBuffer []int
+	offsetLengthBuffer     []int
+
+	payloadBytes    []byte
+	payloadByteUpto int
+	payloadLength   int
+
+	lastStartOffset int
+	startOffset     int
+	endOffset       int
+
+	docBufferUpto int
+	posBufferUpto int
+
+	startDocIn store.IndexInput
+
+	docIn store.IndexInput
+	posIn store.IndexInput
+	payIn store.IndexInput
+
+	payload []byte
+
+	indexHasOffsets  bool
+	indexHasPayloads bool
+
+	docFreq       int
+	totalTermFreq int64
+	docUpto       int
+	doc           int
+	accum         int
+	freq          int
+	position      int
+
+	// how many positions "behind" we are; nextPosition must skip
+	// these to "catch up":
+	posPendingCount int
+
+	// Lazy pos seek: if != -1 then we must seek to this FP before
+	// reading positions:
+	posPendingFP int64
+
+	// Lazy pay seek: if != -1 then we must seek to this FP before
+	// reading payloads/offsets:
+	payPendingFP int64
+
+	// Where this term's postings start in the .doc file:
+	docTermStartFP int64
+
+	// Where this term's postings start in the .pos file:
+	posTermStartFP int64
+
+	// Where this term's payloads/offsets start in the .pay file:
+	payTermStartFP int64
+
+	// Where this term's skip data starts (after
+	// docTermStartFP) in the .doc file (or -1 if there is
+	// no skip data for this term):
+	skipOffset int64
+
+	// docID for next skip point, we won't use skipper if
+	// target docID is not larger than this
+	nextSkipDoc int
+
+	skipper *SkipReader
+	skipped bool
+
+	// File pointer where the last (vInt encoded) pos delta block is.
+	// We need this to know whether to bulk decode vs vInt decode the
+	// block:
+	lastPosBlockFP int64
+
+	liveDocs util.Bits
+
+	needsOffsets   bool // true if we actually need offsets
+	needsPayloads  bool // true if we actually need payloads
+	singletonDocID int
+}
+
+func newEverythingEnum(owner *Lucene41PostingsReader,
+	fieldInfo *FieldInfo) *everythingEnum {
+
+	indexHasOffsets := fieldInfo.IndexOptions() >= INDEX_OPT_DOCS_AND_FREQS_AND_POSITIONS_AND_OFFSETS
+	indexHasPayloads := fieldInfo.HasPayloads()
+	ans := &everythingEnum{
+		Lucene41PostingsReader: owner,
+		encoded:                make([]byte, MAX_ENCODED_SIZE),
+		docDeltaBuffer:         make([]int, MAX_DATA_SIZE),
+		freqBuffer:             make([]int, MAX_DATA_SIZE),
+		posDeltaBuffer:         make([]int, MAX_DATA_SIZE),
+		startDocIn:             owner.docIn,
+		posIn:                  owner.posIn.Clone(),
+		indexHasOffsets:        indexHasOffsets,
+		indexHasPayloads:       indexHasPayloads,
+		startOffset:            -1,
+		endOffset:              -1,
+	}
+	if indexHasOffsets || indexHasPayloads {
+		ans.payIn = owner.payIn.Clone()
+	}
+	if indexHasOffsets {
+		ans.offsetStartDeltaBuffer = make([]int, MAX_DATA_SIZE)
+		ans.offsetLengthBuffer = make([]int, MAX_DATA_SIZE)
+	}
+	if indexHasPayloads {
+		ans.payloadLengthBuffer = make([]int, MAX_DATA_SIZE)
+		ans.payloadBytes = make([]byte, 128)
+	}
+	return ans
+}
+
+func (e *everythingEnum) canReuse(docIn store.IndexInput, fieldInfo *FieldInfo) bool {
+	return docIn == e.startDocIn &&
+		e.indexHasOffsets == (fieldInfo.IndexOptions() >= INDEX_OPT_DOCS_AND_FREQS_AND_POSITIONS_AND_OFFSETS) &&
+		e.indexHasPayloads == fieldInfo.HasPayloads()
+}
+
+func (e *everythingEnum) reset(liveDocs util.Bits, termState *intBlockTermState, flags int) (DocsAndPositionsEnum, error) {
+	e.liveDocs = liveDocs
+	e.docFreq = termState.DocFreq
+	e.docTermStartFP = termState.docStartFP
+	e.posTermStartFP = termState.posStartFP
+	e.payTermStartFP = termState.payStartFP
+	e.skipOffset = termState.skipOffset
+	e.singletonDocID = termState.singletonDocID
+	if e.docFreq > 1 {
+		if e.docIn == nil {
+			// lazy init
+			e.docIn = e.startDocIn.Clone()
+		}
+		if err := e.docIn.Seek(e.docTermStartFP); err != nil {
+			return nil, err
+		}
+	}
+	e.posPendingFP = e.posTermStartFP
+	e.payPendingFP = e.payTermStartFP
+	e.posPendingCount = 0
+	e.totalTermFreq = termState.TotalTermFreq
+	if e.totalTermFreq < LUCENE41_BLOCK_SIZE {
+		e.lastPosBlockFP = e.posTermStartFP
+	} else if e.totalTermFreq == LUCENE41_BLOCK_SIZE {
+		e.lastPosBlockFP = -1
+	} else {
+		e.lastPosBlockFP = e.posTermStartFP + termState.lastPosBlockOffset
+	}
+
+	e.needsOffsets = (flags & DOCS_POSITIONS_ENUM_FLAG_OFF_SETS) != 0
+	e.needsPayloads = (flags & DOCS_POSITIONS_ENUM_FLAG_PAYLOADS) != 0
+
+	e.doc = -1
+	e.accum = 0
+	e.docUpto = 0
+	e.nextSkipDoc = LUCENE41_BLOCK_SIZE - 1
+	e.docBufferUpto = LUCENE41_BLOCK_SIZE
+	e.skipped = false
+	return e, nil
+}
+
+func (e *everythingEnum) Freq() (int, error) {
+	return e.freq, nil
+}
+
+func (e *everythingEnum) DocId() int {
+	return e.doc
+}
+
+func (e *everythingEnum) refillDocs() (err error) {
+	left := e.docFreq - e.docUpto
+	assert(left > 0)
+
+	if left >= LUCENE41_BLOCK_SIZE {
+		if err = e.forUtil.readBlock(e.docIn, e.encoded, e.docDeltaBuffer); err != nil {
+			return err
+		}
+		err = e.forUtil.readBlock(e.docIn, e.encoded, e.freqBuffer)
+	} else if e.docFreq == 1 {
+		e.docDeltaBuffer[0] = e.singletonDocID
+		e.freqBuffer[0] = int(e.totalTermFreq)
+	} else {
+		err = readVIntBlock(e.docIn, e.docDeltaBuffer, e.freqBuffer, left, true)
+	}
+	e.docBufferUpto = 0
+	return
+}
+
+func (e *everythingEnum) refillPositions() error {
+	if e.posIn.FilePointer() != e.lastPosBlockFP {
+		if err := e.forUtil.readBlock(e.posIn, e.encoded, e.posDeltaBuffer); err != nil {
+			return err
+		}
+		if e.indexHasPayloads {
+			if err := e.forUtil.readBlock(e.payIn, e.encoded, e.payloadLengthBuffer); err != nil {
+				return err
+			}
+			numBytes, err := asInt(e.payIn.ReadVInt())
+			if err != nil {
+				return err
+			}
+			if numBytes > len(e.payloadBytes) {
+				e.payloadBytes = util.GrowByteSlice(e.payloadBytes, numBytes)
+			}
+			if err = e.payIn.ReadBytes(e.payloadBytes[:numBytes]); err != nil {
+				return err
+			}
+			e.payloadByteUpto = 0
+		}
+		if e.indexHasOffsets {
+			if err := e.forUtil.readBlock(e.payIn, e.encoded, e.offsetStartDeltaBuffer); err != nil {
+				return err
+			}
+			return e.forUtil.readBlock(e.payIn, e.encoded, e.offsetLengthBuffer)
+		}
+		return nil
+	}
+
+	count := int(e.totalTermFreq % LUCENE41_BLOCK_SIZE)
+	payloadLength, offsetLength := 0, 0
+	e.payloadByteUpto = 0
+	for i := 0; i < count; i++ {
+		code, err := asInt(e.posIn.ReadVInt())
+		if err != nil {
+			return err
+		}
+		if e.indexHasPayloads {
+			if (code & 1) != 0 {
+				if payloadLength, err = asInt(e.posIn.ReadVInt()); err != nil {
+					return err
+				}
+			}
+			e.payloadLengthBuffer[i] = payloadLength
+			e.posDeltaBuffer[i] = int(uint(code) >> 1)
+			if payloadLength != 0 {
+				if e.payloadByteUpto+payloadLength > len(e.payloadBytes) {
+					e.payloadBytes = util.GrowByteSlice(e.payloadBytes, e.payloadByteUpto+payloadLength)
+				}
+				if err = e.posIn.ReadBytes(e.payloadBytes[e.payloadByteUpto : e.payloadByteUpto+payloadLength]); err != nil {
+					return err
+				}
+				e.payloadByteUpto += payloadLength
+			}
+		} else {
+			e.posDeltaBuffer[i] = code
+		}
+
+		if e.indexHasOffsets {
+			deltaCode, err := asInt(e.posIn.ReadVInt())
+			if err != nil {
+				return err
+			}
+			if (deltaCode & 1) != 0 {
+				if offsetLength, err = asInt(e.posIn.ReadVInt()); err != nil {
+					return err
+				}
+			}
+			e.offsetStartDeltaBuffer[i] = int(uint(deltaCode) >> 1)
+			e.offsetLengthBuffer[i] = offsetLength
+		}
+	}
+	e.payloadByteUpto = 0
+	return nil
+}
+
+func (e *everythingEnum) NextDoc() (int, error) {
+	for {
+		if e.docUpto == e.docFreq {
+			e.doc = NO_MORE_DOCS
+			return e.doc, nil
+		}
+		if e.docBufferUpto == LUCENE41_BLOCK_SIZE {
+			if err := e.refillDocs(); err != nil {
+				return 0, err
+			}
+		}
+
+		e.accum += e.docDeltaBuffer[e.docBufferUpto]
+		e.freq = e.freqBuffer[e.docBufferUpto]
+		e.posPendingCount += e.freq
+		e.docBufferUpto++
+		e.docUpto++
+
+		if e.liveDocs == nil || e.liveDocs.At(e.accum) {
+			e.doc = e.accum
+			e.position = 0
+			e.lastStartOffset = 0
+			return e.doc, nil
+		}
+	}
+}
+
+func (e *everythingEnum) Advance(target int) (int, error) {
+	// TODO: make frq block load lazy/skippable
+
+	if e.docFreq > LUCENE41_BLOCK_SIZE && target > e.nextSkipDoc {
+		if e.skipper == nil {
+			// Lazy init: first time this enum has ever been used for skipping
+			e.skipper = NewSkipReader(e.docIn.Clone(), maxSkipLevels,
+				LUCENE41_BLOCK_SIZE, true, e.indexHasOffsets, e.indexHasPayloads)
+		}
+
+		if !e.skipped {
+			assert(e.skipOffset != -1)
+			// This is the first time this enum has skipped since reset()
+			// was called; load the skip data:
+			e.skipper.Init(e.docTermStartFP+e.skipOffset, e.docTermStartFP,
+				e.posTermStartFP, e.payTermStartFP, e.docFreq)
+			e.skipped = true
+		}
+
+		newDocUpto, err := e.skipper.SkipTo(target)
+		if err != nil {
+			return 0, err
+		}
+		newDocUpto++
+
+		if newDocUpto > e.docUpto {
+			// Skipper moved
+			assert(newDocUpto%LUCENE41_BLOCK_SIZE == 0)
+			e.docUpto = newDocUpto
+
+			// Force to read next block
+			e.docBufferUpto = LUCENE41_BLOCK_SIZE
+			e.accum = e.skipper.Doc()
+			if err = e.docIn.Seek(e.skipper.DocPointer()); err != nil {
+				return 0, err
+			}
+			e.posPendingFP = e.skipper.PosPointer()
+			e.payPendingFP = e.skipper.PayPointer()
+			e.posPendingCount = e.skipper.PosBufferUpto()
+			e.lastStartOffset = 0 // new document
+			e.payloadByteUpto = e.skipper.PayloadByteUpto()
+		}
+		e.nextSkipDoc = e.skipper.NextSkipDoc()
+	}
+	if e.docUpto == e.docFreq {
+		e.doc = NO_MORE_DOCS
+		return e.doc, nil
+	}
+	if e.docBufferUpto == LUCENE41_BLOCK_SIZE {
+		if err := e.refillDocs(); err != nil {
+			return 0, err
+		}
+	}
+
+	// Now scan:
+	for {
+		e.accum += e.docDeltaBuffer[e.docBufferUpto]
+		e.freq = e.freqBuffer[e.docBufferUpto]
+		e.posPendingCount += e.freq
+		e.docBufferUpto++
+		e.docUpto++
+
+		if e.accum >= target {
+			break
+		}
+		if e.docUpto == e.docFreq {
+			e.doc = NO_MORE_DOCS
+			return e.doc, nil
+		}
+	}
+
+	if e.liveDocs == nil || e.liveDocs.At(e.accum) {
+		e.position = 0
+		e.lastStartOffset = 0
+		e.doc = e.accum
+		return e.doc, nil
+	}
+	return e.NextDoc()
+}
+
+/*
+Consumes the positions of the docs we stepped over without reading
+them, so that the next position read belongs to the current doc.
+*/
+func (e *everythingEnum) skipPositions() error {
+	toSkip := e.posPendingCount - e.freq
+	leftInBlock := LUCENE41_BLOCK_SIZE - e.posBufferUpto
+	if toSkip < leftInBlock {
+		end := e.posBufferUpto + toSkip
+		for e.posBufferUpto < end {
+			if e.indexHasPayloads {
+				e.payloadByteUpto += e.payloadLengthBuffer[e.posBufferUpto]
+			}
+			e.posBufferUpto++
+		}
+	} else {
+		toSkip -= leftInBlock
+		for toSkip >= LUCENE41_BLOCK_SIZE {
+			assert(e.posIn.FilePointer() != e.lastPosBlockFP)
+			if err := e.forUtil.skipBlock(e.posIn); err != nil {
+				return err
+			}
+			if e.indexHasPayloads {
+				// skip payloadLength block:
+				if err := e.forUtil.skipBlock(e.payIn); err != nil {
+					return err
+				}
+				// skip payloadBytes block:
+				numBytes, err := e.payIn.ReadVInt()
+				if err != nil {
+					return err
+				}
+				if err = e.payIn.Seek(e.payIn.FilePointer() + int64(numBytes)); err != nil {
+					return err
+				}
+			}
+			if e.indexHasOffsets {
+				if err := e.forUtil.skipBlock(e.payIn); err != nil {
+					return err
+				}
+				if err := e.forUtil.skipBlock(e.payIn); err != nil {
+					return err
+				}
+			}
+			toSkip -= LUCENE41_BLOCK_SIZE
+		}
+		if err := e.refillPositions(); err != nil {
+			return err
+		}
+		e.payloadByteUpto = 0
+		e.posBufferUpto = 0
+		for e.posBufferUpto < toSkip {
+			if e.indexHasPayloads {
+				e.payloadByteUpto += e.payloadLengthBuffer[e.posBufferUpto]
+			}
+			e.posBufferUpto++
+		}
+	}
+	e.position = 0
+	e.lastStartOffset = 0
+	return nil
+}
+
+func (e *everythingEnum) NextPosition() (int, error) {
+	if e.posPendingFP != -1 {
+		if err := e.posIn.Seek(e.posPendingFP); err != nil {
+			return 0, err
+		}
+		e.posPendingFP = -1
+
+		if e.payPendingFP != -1 && e.payIn != nil {
+			if err := e.payIn.Seek(e.payPendingFP); err != nil {
+				return 0, err
+			}
+			e.payPendingFP = -1
+		}
+
+		// Force buffer refill:
+		e.posBufferUpto = LUCENE41_BLOCK_SIZE
+	}
+
+	if e.posPendingCount > e.freq {
+		if err := e.skipPositions(); err != nil {
+			return 0, err
+		}
+		e.posPendingCount = e.freq
+	}
+
+	if e.posBufferUpto == LUCENE41_BLOCK_SIZE {
+		if err := e.refillPositions(); err != nil {
+			return 0, err
+		}
+		e.posBufferUpto = 0
+	}
+	e.position += e.posDeltaBuffer[e.posBufferUpto]
+
+	if e.indexHasPayloads {
+		e.payloadLength = e.payloadLengthBuffer[e.posBufferUpto]
+		e.payload = e.payloadBytes[e.payloadByteUpto : e.payloadByteUpto+e.payloadLength]
+		e.payloadByteUpto += e.payloadLength
+	}
+
+	if e.indexHasOffsets {
+		e.startOffset = e.lastStartOffset + e.offsetStartDeltaBuffer[e.posBufferUpto]
+		e.endOffset = e.startOffset + e.offsetLengthBuffer[e.posBufferUpto]
+		e.lastStartOffset = e.startOffset
+	}
+
+	e.posBufferUpto++
+	e.posPendingCount--
+	return e.position, nil
+}
+
+func (e *everythingEnum) StartOffset() (int, error) {
+	return e.startOffset, nil
+}
+
+func (e *everythingEnum) EndOffset() (int, error) {
+	return e.endOffset, nil
+}
+
+func (e *everythingEnum) Payload() ([]byte, error) {
+	if e.payloadLength == 0 {
+		return nil, nil
+	}
+	return e.payload, nil
+}
@@ -0,0 +1,204 @@
+package lucene41_test
+
+import (
+	"fmt"
+	std "github.com/balzaczyy/golucene/analysis/standard"
+	_ "github.com/balzaczyy/golucene/core/codec/lucene410"
+	docu "github.com/balzaczyy/golucene/core/document"
+	"github.com/balzaczyy/golucene/core/index"
+	. "github.com/balzaczyy/golucene/core/index/model"
+	_ "github.com/balzaczyy/golucene/core/search"
+	. "github.com/balzaczyy/golucene/core/search/model"
+	"github.com/balzaczyy/golucene/core/store"
+	"github.com/balzaczyy/golucene/core/util"
+	"strings"
+	"testing"
+)
+
+// enough docs for two skip levels: 5000/128 blocks > 8
+const numDocs = 5000
+
+/*
+Indexes numDocs docs in a single segment. Each doc holds "all" in a
+docs-only field, and "common" 1 to 4 times in a field with positions,
+and in one with offsets too.
+*/
+func newLargePostingsReader(t *testing.T) index.AtomicReader {
+	dir, err := store.OpenFSDirectory(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := index.NewIndexWriter(dir, index.NewIndexWriterConfig(util.VERSION_LATEST, std.NewStandardAnalyzer()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	offsets := docu.NewFieldTypeFrom(docu.TEXT_FIELD_TYPE_NOT_STORED)
+	offsets.SetIndexOptions(INDEX_OPT_DOCS_AND_FREQS_AND_POSITIONS_AND_OFFSETS)
+	for i := 0; i < numDocs; i++ {
+		words := make([]string, 0, 8)
+		for j := 0; j <= i%4; j++ {
+			words = append(words, "common", fmt.Sprintf("w%v", (i+j)%7))
+		}
+		text := strings.Join(words, " ")
+		doc := docu.NewDocument()
+		doc.Add(docu.NewStringField("docs", "all", docu.STORE_NO))
+		doc.Add(docu.NewTextFieldFromString("positions", text, docu.STORE_NO))
+		doc.Add(docu.NewFieldFromString("offsets", text, offsets))
+		if err = w.AddDocument(doc.Fields()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	r, err := index.OpenDirectoryReader(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { r.Close() })
+	if len(r.Leaves()) != 1 {
+		t.Fatalf("Expected a single segment, but was %v", len(r.Leaves()))
+	}
+	return r.Leaves()[0].Reader().(index.AtomicReader)
+}
+
+func seekTerm(t *testing.T, r index.AtomicReader, field, term string) TermsEnum {
+	te := r.Terms(field).Iterator(nil)
+	if ok, err := te.SeekExact([]byte(term)); err != nil || !ok {
+		t.Fatalf("Expected term %v:%v, but was %v (%v)", field, term, ok, err)
+	}
+	return te
+}
+
+// Describes the current doc of a postings enum, with its positions.
+func describe(t *testing.T, e DocsEnum, doc int) string {
+	freq, err := e.Freq()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := fmt.Sprintf("doc=%v freq=%v", doc, freq)
+	if pe, ok := e.(DocsAndPositionsEnum); ok {
+		for i := 0; i < freq; i++ {
+			pos, err := pe.NextPosition()
+			if err != nil {
+				t.Fatal(err)
+			}
+			start, _ := pe.StartOffset()
+			end, _ := pe.EndOffset()
+			s += fmt.Sprintf(" %v[%v,%v]", pos, start, end)
+		}
+	}
+	return s
+}
+
+func TestBlockPostingsAdvance(t *testing.T) {
+	r := newLargePostingsReader(t)
+
+	// every seventh doc is deleted
+	liveDocs := util.NewFixedBitSetOf(numDocs)
+	for doc := 0; doc < numDocs; doc++ {
+		if doc%7 != 3 {
+			liveDocs.Set(doc)
+		}
+	}
+
+	for _, c := range []struct {
+		field, term string
+		positions   bool
+	}{
+		{"docs", "all", false},
+		// docs only enums must still parse the skip data of positions
+		{"positions", "common", false},
+		{"positions", "common", true},
+		{"offsets", "common", false},
+		{"offsets", "common", true},
+	} {
+		for _, live := range []util.Bits{nil, liveDocs} {
+			te := seekTerm(t, r, c.field, c.term)
+			if df, err := te.DocFreq(); err != nil || df != numDocs {
+				t.Fatalf("%v: expected df %v, but was %v (%v)", c.field, numDocs, df, err)
+			}
+			newEnum := func() DocsEnum {
+				var e DocsEnum
+				var err error
+				if c.positions {
+					e, err = te.DocsAndPositions(live, nil)
+				} else {
+					e, err = te.Docs(live, nil)
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				return e
+			}
+
+			// the expected postings, by stepping through every doc
+			var expected []string
+			var docs []int
+			e := newEnum()
+			for {
+				doc, err := e.NextDoc()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if doc == NO_MORE_DOCS {
+					break
+				}
+				docs = append(docs, doc)
+				expected = append(expected, describe(t, e, doc))
+			}
+			// first posting at or after target
+			find := func(target int) int {
+				for i, doc := range docs {
+					if doc >= target {
+						return i
+					}
+				}
+				return len(docs)
+			}
+
+			check := func(e DocsEnum, target int) {
+				doc, err := e.Advance(target)
+				if err != nil {
+					t.Fatal(err)
+				}
+				i := find(target)
+				if i == len(docs) {
+					if doc != NO_MORE_DOCS {
+						t.Errorf("%v: advance(%v) expected NO_MORE_DOCS, but was %v", c, target, doc)
+					}
+					return
+				}
+				if s := describe(t, e, doc); s != expected[i] {
+					t.Errorf("%v: advance(%v) expected %v, but was %v", c, target, expected[i], s)
+				}
+			}
+
+			// a single advance far past several blocks, from a fresh enum
+			for _, target := range []int{1, 127, 128, 129, 1000, 1027, 1100, 3333, 4095, 4096, 4990, 4999, 5000} {
+				check(newEnum(), target)
+			}
+
+			// advances in steps from the same enum, with next docs in
+			// between to mix reading modes
+			for _, step := range []int{3, 129, 700, 1500} {
+				e := newEnum()
+				target := 0
+				for target < numDocs {
+					check(e, target)
+					doc, err := e.NextDoc()
+					if err != nil {
+						t.Fatal(err)
+					}
+					if doc == NO_MORE_DOCS {
+						break
+					}
+					if s, i := describe(t, e, doc), find(doc); s != expected[i] {
+						t.Errorf("%v: next after advance expected %v, but was %v", c, expected[i], s)
+					}
+					target = doc + step
+				}
+			}
+		}
+	}
+}
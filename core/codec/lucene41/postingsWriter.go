@@ -329,12 +329,21 @@ func (w *Lucene41PostingsWriter) AddPosition(position int,
 			// no paylaod
 			w.payloadLengthBuffer[w.posBufferUpto] = 0
 		} else {
-			panic("not implemented yet")
+			w.payloadLengthBuffer[w.posBufferUpto] = len(payload)
+			if w.payloadByteUpto+len(payload) > len(w.payloadBytes) {
+				w.payloadBytes = util.GrowByteSlice(w.payloadBytes, w.payloadByteUpto+len(payload))
+			}
+			copy(w.payloadBytes[w.payloadByteUpto:], payload)
+			w.payloadByteUpto += len(payload)
 		}
 	}
 
 	if w.fieldHasOffsets {
-		panic("not implemented yet")
+		assert(startOffset >= w.lastStartOffset)
+		assert(endOffset >= startOffset)
+		w.offsetStartDeltaBuffer[w.posBufferUpto] = startOffset - w.lastStartOffset
+		w.offsetLengthBuffer[w.posBufferUpto] = endOffset - startOffset
+		w.lastStartOffset = startOffset
 	}
 
 	w.posBufferUpto++
@@ -346,10 +355,24 @@ func (w *Lucene41PostingsWriter) AddPosition(position int,
 		}
 
 		if w.fieldHasPayloads {
-			panic("niy")
+			if err = w.forUtil.writeBlock(w.payloadLengthBuffer, w.encoded, w.payOut); err != nil {
+				return err
+			}
+			if err = w.payOut.WriteVInt(int32(w.payloadByteUpto)); err != nil {
+				return err
+			}
+			if err = w.payOut.WriteBytes(w.payloadBytes[:w.payloadByteUpto]); err != nil {
+				return err
+			}
+			w.payloadByteUpto = 0
 		}
 		if w.fieldHasOffsets {
-			panic("niy")
+			if err = w.forUtil.writeBlock(w.offsetStartDeltaBuffer, w.encoded, w.payOut); err != nil {
+				return err
+			}
+			if err = w.forUtil.writeBlock(w.offsetLengthBuffer, w.encoded, w.payOut); err != nil {
+				return err
+			}
 		}
 		w.posBufferUpto = 0
 	}
@@ -432,13 +455,29 @@ func (w *Lucene41PostingsWriter) FinishTerm(_state *BlockTermState) error {
 			// DF terms = vast vast majority)
 
 			// vInt encode the remaining positions/payloads/offsets:
-			// lastPayloadLength := -1 // force first payload length to be written
-			// lastOffsetLength := -1  // force first offset length to be written
+			lastPayloadLength := -1 // force first payload length to be written
+			lastOffsetLength := -1  // force first offset length to be written
 			payloadBytesReadUpto := 0
 			for i := 0; i < w.posBufferUpto; i++ {
 				posDelta := w.posDeltaBuffer[i]
 				if w.fieldHasPayloads {
-					panic("not implemented yet")
+					payloadLength := w.payloadLengthBuffer[i]
+					var err error
+					if payloadLength != lastPayloadLength {
+						lastPayloadLength = payloadLength
+						if err = w.posOut.WriteVInt(int32((posDelta << 1) | 1)); err == nil {
+							err = w.posOut.WriteVInt(int32(payloadLength))
+						}
+					} else {
+						err = w.posOut.WriteVInt(int32(posDelta << 1))
+					}
+					if err == nil && payloadLength != 0 {
+						err = w.posOut.WriteBytes(w.payloadBytes[payloadBytesReadUpto : payloadBytesReadUpto+payloadLength])
+						payloadBytesReadUpto += payloadLength
+					}
+					if err != nil {
+						return err
+					}
 				} else {
 					err := w.posOut.WriteVInt(int32(posDelta))
 					if err != nil {
@@ -447,7 +486,20 @@ func (w *Lucene41PostingsWriter) FinishTerm(_state *BlockTermState) error {
 				}
 
 				if w.fieldHasOffsets {
-					panic("not implemented yet")
+					delta := w.offsetStartDeltaBuffer[i]
+					length := w.offsetLengthBuffer[i]
+					var err error
+					if length == lastOffsetLength {
+						err = w.posOut.WriteVInt(int32(delta << 1))
+					} else {
+						if err = w.posOut.WriteVInt(int32(delta<<1 | 1)); err == nil {
+							err = w.posOut.WriteVInt(int32(length))
+						}
+						lastOffsetLength = length
+					}
+					if err != nil {
+						return err
+					}
 				}
 			}
 
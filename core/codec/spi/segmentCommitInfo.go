@@ -220,12 +220,7 @@ func (si *SegmentCommitInfo) StringOf(dir store.Directory, pendingDelCount int)
 }
 
 func (si *SegmentCommitInfo) String() string {
-	panic("not implemented yet")
-	s := si.Info.StringOf(si.Info.Dir, si.delCount)
-	if si.delGen != -1 {
-		s = fmt.Sprintf("%v:delGen=%v", s, si.delGen)
-	}
-	return s
+	return si.StringOf(si.Info.Dir, 0)
 }
 
 func (si *SegmentCommitInfo) Clone() *SegmentCommitInfo {
@@ -16,6 +16,22 @@ const FOOTER_MAGIC = ^CODEC_MAGIC
 
 const FOOTER_LENGTH = 16
 
+/*
+Signals that a file of the index is corrupt: its codec footer is
+missing or invalid, or its checksum doesn't match its content.
+*/
+type CorruptIndexError struct {
+	msg string
+}
+
+func newCorruptIndexError(msg string) *CorruptIndexError {
+	return &CorruptIndexError{msg}
+}
+
+func (err *CorruptIndexError) Error() string {
+	return err.msg
+}
+
 type DataOutput interface {
 	WriteInt(n int32) error
 	WriteString(s string) error
@@ -142,12 +158,17 @@ CodecFooter --> Magic,AlgorithmID,Checksum
 func WriteFooter(out IndexOutput) (err error) {
 	if err = out.WriteInt(FOOTER_MAGIC); err == nil {
 		if err = out.WriteInt(0); err == nil {
-			err = out.WriteLong(out.Checksum())
+			err = writeCRC(out)
 		}
 	}
 	return
 }
 
+/* Computes the length of a codec footer. */
+func FooterLength() int {
+	return FOOTER_LENGTH
+}
+
 type ChecksumIndexInput interface {
 	IndexInput
 	Checksum() int64
@@ -158,14 +179,14 @@ func CheckFooter(in ChecksumIndexInput) (cs int64, err error) {
 	if err = validateFooter(in); err == nil {
 		cs = in.Checksum()
 		var cs2 int64
-		if cs2, err = in.ReadLong(); err == nil {
+		if cs2, err = readCRC(in); err == nil {
 			if cs != cs2 {
-				return 0, errors.New(fmt.Sprintf(
+				return 0, newCorruptIndexError(fmt.Sprintf(
 					"checksum failed (hardware problem?): expected=%v actual=%v (resource=%v)",
 					util.ItoHex(cs2), util.ItoHex(cs), in))
 			}
 			if in.FilePointer() != in.Length() {
-				return 0, errors.New(fmt.Sprintf(
+				return 0, newCorruptIndexError(fmt.Sprintf(
 					"did not read all bytes from file: read %v vs size %v (resource: %v)",
 					in.FilePointer(), in.Length(), in))
 			}
@@ -176,6 +197,11 @@ func CheckFooter(in ChecksumIndexInput) (cs int64, err error) {
 
 /* Returns (but does not validate) the checksum previously written by CheckFooter. */
 func RetrieveChecksum(in IndexInput) (int64, error) {
+	if in.Length() < FOOTER_LENGTH {
+		return 0, newCorruptIndexError(fmt.Sprintf(
+			"misplaced codec footer (file truncated?): length=%v but footerLength==%v (resource=%v)",
+			in.Length(), FOOTER_LENGTH, in))
+	}
 	var err error
 	if err = in.Seek(in.Length() - FOOTER_LENGTH); err != nil {
 		return 0, err
@@ -183,7 +209,25 @@ func RetrieveChecksum(in IndexInput) (int64, error) {
 	if err = validateFooter(in); err != nil {
 		return 0, err
 	}
-	return in.ReadLong()
+	return readCRC(in)
+}
+
+/* Reads CRC32 value as a 64-bit long from the input, checking it fits in 32 bits. */
+func readCRC(in IndexInput) (int64, error) {
+	value, err := in.ReadLong()
+	if err == nil && uint64(value)&0xFFFFFFFF00000000 != 0 {
+		return 0, newCorruptIndexError(fmt.Sprintf("Illegal CRC-32 checksum: %v (resource=%v)", value, in))
+	}
+	return value, err
+}
+
+/* Writes CRC32 value as a 64-bit long to the output. */
+func writeCRC(out IndexOutput) error {
+	value := out.Checksum()
+	if uint64(value)&0xFFFFFFFF00000000 != 0 {
+		return errors.New(fmt.Sprintf("Illegal CRC-32 checksum: %v (resource=%v)", value, out))
+	}
+	return out.WriteLong(value)
 }
 
 func validateFooter(in IndexInput) error {
@@ -192,7 +236,7 @@ func validateFooter(in IndexInput) error {
 		return err
 	}
 	if magic != FOOTER_MAGIC {
-		return errors.New(fmt.Sprintf(
+		return newCorruptIndexError(fmt.Sprintf(
 			"codec footer mismatch: actual footer=%v vs expected footer=%v (resource: %v)",
 			magic, FOOTER_MAGIC, in))
 	}
@@ -202,7 +246,7 @@ func validateFooter(in IndexInput) error {
 		return err
 	}
 	if algorithmId != 0 {
-		return errors.New(fmt.Sprintf(
+		return newCorruptIndexError(fmt.Sprintf(
 			"codec footer mismatch: unknown algorithmID: %v",
 			algorithmId))
 	}
@@ -0,0 +1,117 @@
+package document
+
+import (
+	"fmt"
+	"time"
+)
+
+// document/DateTools.java#Resolution
+
+// Specifies the time granularity of dates.
+type DateResolution int
+
+const (
+	// Limit a date's resolution to year granularity.
+	RESOLUTION_YEAR DateResolution = iota
+	// Limit a date's resolution to month granularity.
+	RESOLUTION_MONTH
+	// Limit a date's resolution to day granularity.
+	RESOLUTION_DAY
+	// Limit a date's resolution to hour granularity.
+	RESOLUTION_HOUR
+	// Limit a date's resolution to minute granularity.
+	RESOLUTION_MINUTE
+	// Limit a date's resolution to second granularity.
+	RESOLUTION_SECOND
+	// Limit a date's resolution to millisecond granularity.
+	RESOLUTION_MILLISECOND
+)
+
+// Layouts of the dates formatted by TimeToString, per resolution.
+var resolutionLayouts = []string{
+	"2006", "200601", "20060102", "2006010215", "200601021504",
+	"20060102150405", "20060102150405.000",
+}
+
+func (r DateResolution) String() string {
+	switch r {
+	case RESOLUTION_YEAR:
+		return "YEAR"
+	case RESOLUTION_MONTH:
+		return "MONTH"
+	case RESOLUTION_DAY:
+		return "DAY"
+	case RESOLUTION_HOUR:
+		return "HOUR"
+	case RESOLUTION_MINUTE:
+		return "MINUTE"
+	case RESOLUTION_SECOND:
+		return "SECOND"
+	case RESOLUTION_MILLISECOND:
+		return "MILLISECOND"
+	}
+	panic(fmt.Sprintf("invalid date resolution: %v", int(r)))
+}
+
+// document/DateTools.java
+
+/*
+Limits the time to the resolution, in UTC, e.g. 2004-09-21 13:50:11
+rounded to RESOLUTION_MONTH is 2004-09-01 00:00:00.
+*/
+func RoundTime(t time.Time, resolution DateResolution) time.Time {
+	t = t.UTC()
+	year, month, day := t.Date()
+	hour, min, sec, nsec := t.Hour(), t.Minute(), t.Second(), t.Nanosecond()
+	switch resolution {
+	case RESOLUTION_YEAR:
+		month = time.January
+		fallthrough
+	case RESOLUTION_MONTH:
+		day = 1
+		fallthrough
+	case RESOLUTION_DAY:
+		hour = 0
+		fallthrough
+	case RESOLUTION_HOUR:
+		min = 0
+		fallthrough
+	case RESOLUTION_MINUTE:
+		sec = 0
+		fallthrough
+	case RESOLUTION_SECOND:
+		nsec = 0
+	case RESOLUTION_MILLISECOND:
+		nsec -= nsec % int(time.Millisecond)
+	default:
+		panic(fmt.Sprintf("unknown resolution %v", resolution))
+	}
+	return time.Date(year, month, day, hour, min, sec, nsec, time.UTC)
+}
+
+/*
+Converts a time to a string suitable for indexing, in UTC and limited
+to the resolution, e.g. "20040921" for RESOLUTION_DAY. Such strings
+sort as the times they represent.
+*/
+func TimeToString(t time.Time, resolution DateResolution) string {
+	return RoundTime(t, resolution).Format(resolutionLayouts[resolution])
+}
+
+/*
+Converts a string produced by TimeToString back to a time, whose
+resolution is implied by the length of the string.
+*/
+func StringToTime(s string) (time.Time, error) {
+	for _, layout := range resolutionLayouts {
+		if len(layout) == len(s) {
+			return time.Parse(layout, s)
+		}
+	}
+	return time.Time{}, fmt.Errorf("Input is not a valid date string: %v", s)
+}
+
+// Returns the milliseconds since January 1, 1970, 00:00:00 UTC of the time.
+func TimeToMillis(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
@@ -45,6 +45,51 @@ func (doc *Document) Add(field IndexableField) {
 	doc.fields = append(doc.fields, field)
 }
 
+/*
+Returns a field with the given name if any exist in this document, or
+nil. If multiple fields exists with this name, this method returns
+the first value added.
+*/
+func (doc *Document) Field(name string) IndexableField {
+	for _, field := range doc.fields {
+		if field.Name() == name {
+			return field
+		}
+	}
+	return nil
+}
+
+/*
+Returns a slice of byte slices for the binary fields with the
+specified name, in the order they were added.
+*/
+func (doc *Document) BinaryValues(name string) [][]byte {
+	var ans [][]byte
+	for _, field := range doc.fields {
+		if field.Name() == name {
+			if v := field.BinaryValue(); v != nil {
+				ans = append(ans, v)
+			}
+		}
+	}
+	return ans
+}
+
+/*
+Returns the binary value of the first field with the given name, or
+nil if no binary field exists with this name.
+*/
+func (doc *Document) BinaryValue(name string) []byte {
+	for _, field := range doc.fields {
+		if field.Name() == name {
+			if v := field.BinaryValue(); v != nil {
+				return v
+			}
+		}
+	}
+	return nil
+}
+
 /*
 Returns the string value of the field with the given name if any exist in
 this document, or null.  If multiple fields exist with this name, this
@@ -64,6 +109,23 @@ func (doc *Document) Get(name string) string {
 	return ""
 }
 
+/*
+Returns an array of values of the field specified as the method
+parameter. This method returns an empty array when there are no
+matching fields. It never returns nil. For IntField, LongField,
+FloatField and DoubleField it returns the string value of the
+number.
+*/
+func (doc *Document) Values(name string) []string {
+	ans := make([]string, 0)
+	for _, field := range doc.fields {
+		if field.Name() == name && field.StringValue() != "" {
+			ans = append(ans, field.StringValue())
+		}
+	}
+	return ans
+}
+
 // document/DocumentStoredFieldVisitor.java
 /*
 A StoredFieldVisitor that creates a Document containing all
@@ -86,9 +148,8 @@ func NewDocumentStoredFieldVisitor() *DocumentStoredFieldVisitor {
 }
 
 func (visitor *DocumentStoredFieldVisitor) BinaryField(fi *FieldInfo, value []byte) error {
-	panic("not implemented yet")
-	// visitor.doc.add(newStoredField(fieldInfo.name, value))
-	// return nil
+	visitor.doc.Add(NewStoredFieldFromBytes(fi.Name, value))
+	return nil
 }
 
 func (visitor *DocumentStoredFieldVisitor) StringField(fi *FieldInfo, value string) error {
@@ -106,7 +167,8 @@ func (visitor *DocumentStoredFieldVisitor) IntField(fi *FieldInfo, value int) er
 }
 
 func (visitor *DocumentStoredFieldVisitor) LongField(fi *FieldInfo, value int64) error {
-	panic("not implemented yet")
+	visitor.doc.Add(NewStoredFieldFromLong(fi.Name, value))
+	return nil
 }
 
 func (visitor *DocumentStoredFieldVisitor) FloatField(fi *FieldInfo, value float32) error {
@@ -53,6 +53,10 @@ func (f *Field) StringValue() string {
 		return f._data.(string)
 	case int:
 		return strconv.Itoa(f._data.(int))
+	case int32, int64, float32, float64:
+		return fmt.Sprintf("%v", f._data)
+	case []byte, io.RuneReader:
+		return ""
 	default:
 		log.Println("Unknown type", f._data)
 		panic("not implemented yet")
@@ -121,10 +125,16 @@ func (f *Field) TokenStream(analyzer analysis.Analyzer, reuse analysis.TokenStre
 
 	if !f.FieldType().Tokenized() {
 		assert2(f.StringValue() != "", "Non-Tokenized Fields must have a string value")
+		value := f.StringValue()
+		if normalizer := f.FieldType().(*FieldType).Normalizer(); normalizer != nil {
+			if value, err = normalizer.Normalize(f._name, value); err != nil {
+				return nil, err
+			}
+		}
 		if _, ok := reuse.(*StringTokenStream); !ok {
 			reuse = newStringTokenStream()
 		}
-		reuse.(*StringTokenStream).setValue(f.StringValue())
+		reuse.(*StringTokenStream).setValue(value)
 		return reuse, nil
 	}
 
@@ -174,6 +184,25 @@ func (ts *StringTokenStream) IncrementToken() (bool, error) {
 	return true, nil
 }
 
+func (ts *StringTokenStream) End() error {
+	if err := ts.TokenStreamImpl.End(); err != nil {
+		return err
+	}
+	finalOffset := len(ts.value)
+	ts.offsetAttribute.SetOffset(finalOffset, finalOffset)
+	return nil
+}
+
+func (ts *StringTokenStream) Reset() error {
+	ts.used = false
+	return nil
+}
+
+func (ts *StringTokenStream) Close() error {
+	ts.value = ""
+	return nil
+}
+
 /* Specifies whether and how a field should be stored. */
 type Store int
 
@@ -213,13 +242,30 @@ var STRING_FIELD_TYPE_STORED = func() *FieldType {
 	return ft
 }()
 
+/*
+Returns a frozen string field type whose values are normalized by
+normalizer before indexing. Fields of this type still index the
+entire value as a single token, but e.g. a lowercasing normalizer
+makes exact matches case insensitive. Create the type once and share
+it across fields.
+*/
+func NewNormalizedStringFieldType(stored Store, normalizer analysis.Normalizer) *FieldType {
+	ft := NewFieldTypeFrom(map[Store]*FieldType{
+		STORE_YES: STRING_FIELD_TYPE_STORED,
+		STORE_NO:  STRING_FIELD_TYPE_NOT_STORED,
+	}[stored])
+	ft.SetNormalizer(normalizer)
+	ft.frozen = true
+	return ft
+}
+
 /*
 Creates a new field that is indexed but not tokenized: the entire
 String value is indexed as a single token. For example, this might be
 used for a 'country' field or an 'id' field, or any field that you
 intend to use for sorting or access through the field cache.
 */
-func newStringField(name, value string, stored Store) *Field {
+func NewStringField(name, value string, stored Store) *Field {
 	return NewFieldFromString(name, value, map[Store]*FieldType{
 		STORE_YES: STRING_FIELD_TYPE_STORED,
 		STORE_NO:  STRING_FIELD_TYPE_NOT_STORED,
@@ -291,9 +337,22 @@ type StoredField struct {
 /*
 Create a stored-only field with the given binary value.
 
-NOTE: the provided byte[] is not copied so be sure
+NOTE: the provided []byte is not copied so be sure
 not to change it until you're done with this field.
 */
-// func newStoredField(name string, value []byte) *StoredField {
-// 	return &StoredField{newStringField(name, value, STORED_FIELD_TYPE)}
-// }
+func NewStoredFieldFromBytes(name string, value []byte) *StoredField {
+	assert2(name != "", "name cannot be empty")
+	assert2(value != nil, "value cannot be nil")
+	return &StoredField{&Field{_type: STORED_FIELD_TYPE, _name: name, _data: value, _boost: 1}}
+}
+
+// Create a stored-only field with the given string value.
+func NewStoredFieldFromString(name, value string) *StoredField {
+	return &StoredField{NewFieldFromString(name, value, STORED_FIELD_TYPE)}
+}
+
+// Create a stored-only field with the given int64 value.
+func NewStoredFieldFromLong(name string, value int64) *StoredField {
+	assert2(name != "", "name cannot be empty")
+	return &StoredField{&Field{_type: STORED_FIELD_TYPE, _name: name, _data: value, _boost: 1}}
+}
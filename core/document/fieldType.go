@@ -3,6 +3,7 @@ package document
 import (
 	"bytes"
 	"fmt"
+	"github.com/balzaczyy/golucene/core/analysis"
 	"github.com/balzaczyy/golucene/core/index/model"
 	"github.com/balzaczyy/golucene/core/util"
 )
@@ -34,6 +35,7 @@ type FieldType struct {
 	frozen                   bool
 	numericPrecisionStep     int
 	_docValueType            model.DocValuesType
+	normalizer               analysis.Normalizer
 }
 
 // Create a new mutable FieldType with all of the properties from <code>ref</code>
@@ -50,6 +52,7 @@ func NewFieldTypeFrom(ref *FieldType) *FieldType {
 	ft._indexOptions = ref._indexOptions
 	ft._docValueType = ref._docValueType
 	ft.numericType = ref.numericType
+	ft.normalizer = ref.normalizer
 	// Do not copy frozen!
 	return ft
 }
@@ -96,6 +99,28 @@ func (ft *FieldType) IndexOptions() model.IndexOptions  { return ft._indexOption
 func (ft *FieldType) NumericType() NumericType          { return ft.numericType }
 func (ft *FieldType) DocValueType() model.DocValuesType { return ft._docValueType }
 
+// Sets the indexing options for the field.
+func (ft *FieldType) SetIndexOptions(v model.IndexOptions) {
+	ft.checkIfFrozen()
+	ft._indexOptions = v
+}
+
+/*
+Returns the normalizer applied to the value of non-tokenized fields
+before indexing, or nil if the value is indexed as is.
+*/
+func (ft *FieldType) Normalizer() analysis.Normalizer { return ft.normalizer }
+
+/*
+Sets the normalizer applied to the value of non-tokenized fields, e.g.
+to lowercase keywords for case insensitive exact matching. The stored
+value is left unchanged.
+*/
+func (ft *FieldType) SetNormalizer(v analysis.Normalizer) {
+	ft.checkIfFrozen()
+	ft.normalizer = v
+}
+
 // Prints a Field for human consumption.
 func (ft *FieldType) String() string {
 	var buf bytes.Buffer
@@ -128,6 +153,9 @@ func (ft *FieldType) String() string {
 		if ft.IndexOptions() != model.INDEX_OPT_DOCS_AND_FREQS_AND_POSITIONS {
 			fmt.Fprintf(&buf, ",indexOptions=%v", ft.IndexOptions())
 		}
+		if !ft.Tokenized() && ft.normalizer != nil {
+			buf.WriteString(",normalized")
+		}
 		if ft.numericType != 0 {
 			fmt.Fprintf(&buf, ",numericType=%v,numericPrecisionStep=%v", ft.numericType, ft.numericPrecisionStep)
 		}
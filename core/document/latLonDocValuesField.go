@@ -0,0 +1,33 @@
+package document
+
+import (
+	"fmt"
+)
+
+// document/LatLonDocValuesField.java
+
+/*
+A per-document location field, whose value can be used to sort
+search results by distance, e.g. with search.NewLatLonDistanceSort().
+
+The point is quantized and packed into a single int64 with
+EncodeLatLon(). As the codecs cannot write doc values yet, the packed
+value is kept as a stored field, so it is read back from the stored
+fields of the hits.
+*/
+type LatLonDocValuesField struct {
+	*Field
+}
+
+// Creates a new LatLonDocValuesField with the specified latitude and
+// longitude.
+func NewLatLonDocValuesField(name string, latitude, longitude float64) *LatLonDocValuesField {
+	assert2(name != "", "name cannot be empty")
+	return &LatLonDocValuesField{&Field{_type: STORED_FIELD_TYPE, _name: name,
+		_data: EncodeLatLon(latitude, longitude), _boost: 1}}
+}
+
+func (f *LatLonDocValuesField) String() string {
+	lat, lon := DecodeLatLon(f._data.(int64))
+	return fmt.Sprintf("LatLonDocValuesField <%v:%v,%v>", f._name, lat, lon)
+}
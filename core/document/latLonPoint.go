@@ -0,0 +1,53 @@
+package document
+
+import (
+	"fmt"
+	"github.com/balzaczyy/golucene/core/geo"
+)
+
+// document/LatLonPoint.java
+
+/*
+An indexed location field, which can be filtered with
+search.NewLatLonBoxQuery(), search.NewLatLonDistanceQuery() and
+search.NewLatLonPolygonQuery().
+
+Like LatLonDocValuesField, the point is quantized and packed into a
+single int64 with EncodeLatLon(). As there is no points (BKD) format
+in the codecs yet, the value is kept as a stored field and the
+queries verify the location of each candidate document. Multiple
+values are allowed per document: a query matches if any of them
+matches.
+*/
+type LatLonPoint struct {
+	*Field
+}
+
+// Creates a new LatLonPoint with the specified latitude and longitude.
+func NewLatLonPoint(name string, latitude, longitude float64) *LatLonPoint {
+	assert2(name != "", "name cannot be empty")
+	return &LatLonPoint{&Field{_type: STORED_FIELD_TYPE, _name: name,
+		_data: EncodeLatLon(latitude, longitude), _boost: 1}}
+}
+
+func (f *LatLonPoint) String() string {
+	lat, lon := DecodeLatLon(f._data.(int64))
+	return fmt.Sprintf("LatLonPoint <%v:%v,%v>", f._name, lat, lon)
+}
+
+/*
+Quantizes a location with geo.EncodeLatitude() and geo.EncodeLongitude()
+and packs it into a single int64, latitude in the upper 32 bits.
+*/
+func EncodeLatLon(latitude, longitude float64) int64 {
+	return int64(geo.EncodeLatitude(latitude))<<32 |
+		int64(uint32(geo.EncodeLongitude(longitude)))
+}
+
+/*
+Decodes the latitude and longitude of a value of LatLonPoint or
+LatLonDocValuesField, as returned by NumericValue().
+*/
+func DecodeLatLon(value int64) (latitude, longitude float64) {
+	return geo.DecodeLatitude(int32(value >> 32)), geo.DecodeLongitude(int32(value))
+}
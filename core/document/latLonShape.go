@@ -0,0 +1,85 @@
+package document
+
+import (
+	"encoding/binary"
+	"fmt"
+	"github.com/balzaczyy/golucene/core/geo"
+)
+
+// document/LatLonShape.java
+
+/*
+A field of a shape, e.g. a polygon, a line or a point, which can be
+searched with search.NewLatLonShapeBoxQuery(),
+search.NewLatLonShapePolygonQuery() and search.NewLatLonShapeLineQuery().
+
+A shape is decomposed into triangles, each one a LatLonShapeTriangle:
+polygons are tessellated, lines are split into segments, which are
+stored as degenerate triangles, as are points. The vertices are
+quantized like the ones of LatLonPoint. As there is no points (BKD)
+format in the codecs yet, the encoded triangles are kept as stored
+fields, and read back by the queries.
+*/
+type LatLonShapeTriangle struct {
+	*Field
+}
+
+// Size in bytes of an encoded triangle: the encoded latitude and
+// longitude of its 3 vertices.
+const LAT_LON_SHAPE_TRIANGLE_BYTES = 6 * 4
+
+func newLatLonShapeTriangle(name string, aLat, aLon, bLat, bLon, cLat, cLon float64) *LatLonShapeTriangle {
+	data := make([]byte, LAT_LON_SHAPE_TRIANGLE_BYTES)
+	for i, v := range []int32{
+		geo.EncodeLatitude(aLat), geo.EncodeLongitude(aLon),
+		geo.EncodeLatitude(bLat), geo.EncodeLongitude(bLon),
+		geo.EncodeLatitude(cLat), geo.EncodeLongitude(cLon),
+	} {
+		binary.BigEndian.PutUint32(data[i*4:], uint32(v))
+	}
+	return &LatLonShapeTriangle{&Field{_type: STORED_FIELD_TYPE, _name: name, _data: data, _boost: 1}}
+}
+
+// Create indexable fields for polygon geometry.
+func NewLatLonShapePolygonFields(name string, polygon *geo.Polygon) []*LatLonShapeTriangle {
+	assert2(name != "", "name cannot be empty")
+	var ans []*LatLonShapeTriangle
+	for _, t := range geo.Tessellate(polygon) {
+		ans = append(ans, newLatLonShapeTriangle(name,
+			t.Lats[0], t.Lons[0], t.Lats[1], t.Lons[1], t.Lats[2], t.Lons[2]))
+	}
+	return ans
+}
+
+// Create indexable fields for line geometry.
+func NewLatLonShapeLineFields(name string, line *geo.Line) []*LatLonShapeTriangle {
+	assert2(name != "", "name cannot be empty")
+	ans := make([]*LatLonShapeTriangle, line.NumPoints()-1)
+	for i := range ans {
+		ans[i] = newLatLonShapeTriangle(name, line.Lat(i), line.Lon(i),
+			line.Lat(i+1), line.Lon(i+1), line.Lat(i), line.Lon(i))
+	}
+	return ans
+}
+
+// Create indexable fields for point geometry.
+func NewLatLonShapePointFields(name string, lat, lon float64) []*LatLonShapeTriangle {
+	assert2(name != "", "name cannot be empty")
+	return []*LatLonShapeTriangle{newLatLonShapeTriangle(name, lat, lon, lat, lon, lat, lon)}
+}
+
+// Decodes a triangle encoded by a LatLonShapeTriangle, as returned by
+// BinaryValue().
+func DecodeLatLonShapeTriangle(data []byte) *geo.Triangle {
+	assert2(len(data) == LAT_LON_SHAPE_TRIANGLE_BYTES, "invalid encoded triangle")
+	ans := new(geo.Triangle)
+	for i := 0; i < 3; i++ {
+		ans.Lats[i] = geo.DecodeLatitude(int32(binary.BigEndian.Uint32(data[i*8:])))
+		ans.Lons[i] = geo.DecodeLongitude(int32(binary.BigEndian.Uint32(data[i*8+4:])))
+	}
+	return ans
+}
+
+func (f *LatLonShapeTriangle) String() string {
+	return fmt.Sprintf("LatLonShapeTriangle <%v:%v>", f._name, DecodeLatLonShapeTriangle(f._data.([]byte)))
+}
@@ -0,0 +1,91 @@
+package document
+
+import (
+	"fmt"
+)
+
+// document/IntPoint.java
+
+/*
+An indexed int field for fast range filters, with up to 4 dimensions
+like IntRange. Finding all documents within an N-dimensional shape or
+range at search time is done with search.NewIntPointRangeQuery().
+
+A point is encoded as a range whose min and max values are the point,
+so that it is kept as a stored field like the range fields, as there
+is no points (BKD) format in the codecs yet. Multiple values for the
+same field in one document are supported.
+*/
+type IntPoint struct {
+	*Field
+}
+
+// Creates a new IntPoint, indexing the provided N-dimensional int point.
+func NewIntPoint(name string, point ...int32) *IntPoint {
+	assert2(name != "", "name cannot be empty")
+	return &IntPoint{&Field{_type: STORED_FIELD_TYPE, _name: name,
+		_data: EncodeIntRanges(point, point), _boost: 1}}
+}
+
+func (f *IntPoint) String() string {
+	return fmt.Sprintf("IntPoint <%v:%v>", f._name, PointString(f._data.([]byte)))
+}
+
+// document/LongPoint.java
+
+/*
+An indexed long field for fast range filters, with up to 4 dimensions
+like IntPoint. Dates are usually indexed as LongPoint of the
+milliseconds since the epoch, rounded with RoundTime() and converted
+with TimeToMillis().
+*/
+type LongPoint struct {
+	*Field
+}
+
+// Creates a new LongPoint, indexing the provided N-dimensional long point.
+func NewLongPoint(name string, point ...int64) *LongPoint {
+	assert2(name != "", "name cannot be empty")
+	return &LongPoint{&Field{_type: STORED_FIELD_TYPE, _name: name,
+		_data: EncodeLongRanges(point, point), _boost: 1}}
+}
+
+func (f *LongPoint) String() string {
+	return fmt.Sprintf("LongPoint <%v:%v>", f._name, PointString(f._data.([]byte)))
+}
+
+// document/DoublePoint.java
+
+/* An indexed double field for fast range filters, with up to 4 dimensions like IntPoint. */
+type DoublePoint struct {
+	*Field
+}
+
+// Creates a new DoublePoint, indexing the provided N-dimensional double point.
+func NewDoublePoint(name string, point ...float64) *DoublePoint {
+	assert2(name != "", "name cannot be empty")
+	return &DoublePoint{&Field{_type: STORED_FIELD_TYPE, _name: name,
+		_data: EncodeDoubleRanges(point, point), _boost: 1}}
+}
+
+func (f *DoublePoint) String() string {
+	return fmt.Sprintf("DoublePoint <%v:%v>", f._name, PointString(f._data.([]byte)))
+}
+
+/* Returns a string representation of an encoded point, e.g. "1,2". */
+func PointString(encoded []byte) string {
+	return rangesString(encoded, func(min, max interface{}) string {
+		return fmt.Sprint(min)
+	}, ",")
+}
+
+/*
+Returns a string representation of encoded ranges as the ranges of a
+point range query, e.g. "[1 TO 5],[2 TO 6]" for a 2 dimensional range
+from [1, 2] to [5, 6].
+*/
+func PointRangesString(encoded []byte) string {
+	return rangesString(encoded, func(min, max interface{}) string {
+		return fmt.Sprintf("[%v TO %v]", min, max)
+	}, ",")
+}
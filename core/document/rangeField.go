@@ -0,0 +1,246 @@
+package document
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"github.com/balzaczyy/golucene/core/util"
+	"math"
+)
+
+// Types of the values of the range fields, the first byte of their
+// encoding.
+const (
+	rangeTypeInt byte = iota
+	rangeTypeLong
+	rangeTypeDouble
+)
+
+// Maximum number of dimensions of a range field.
+const RANGE_MAX_DIMENSIONS = 4
+
+/*
+Encodes the min and max values of a range, whose values are given as
+sortable int64 and the type byte: the type, the number of dimensions,
+then the min values, followed by the max values, as big-endian
+sortable 8-byte values, so that encoded values compare as the
+original ones.
+*/
+func encodeRanges(typ byte, min, max []int64) []byte {
+	assert2(len(min) > 0, "range must have at least one dimension")
+	assert2(len(min) == len(max), "min/max ranges must agree")
+	assert2(len(min) <= RANGE_MAX_DIMENSIONS,
+		fmt.Sprintf("range cannot have more than %v dimensions", RANGE_MAX_DIMENSIONS))
+	ans := make([]byte, 2+16*len(min))
+	ans[0], ans[1] = typ, byte(len(min))
+	for d := range min {
+		assert2(min[d] <= max[d], "min value cannot be greater than max value for range field")
+		binary.BigEndian.PutUint64(ans[2+8*d:], uint64(min[d])^(1<<63))
+		binary.BigEndian.PutUint64(ans[2+8*(len(min)+d):], uint64(max[d])^(1<<63))
+	}
+	return ans
+}
+
+// Returns the encoded min value of dimension d of encoded ranges.
+func rangeMin(encoded []byte, d int) []byte {
+	return encoded[2+8*d : 2+8*(d+1)]
+}
+
+// Returns the encoded max value of dimension d of encoded ranges.
+func rangeMax(encoded []byte, d int) []byte {
+	numDims := int(encoded[1])
+	return encoded[2+8*(numDims+d) : 2+8*(numDims+d+1)]
+}
+
+func decodeRangeValue(typ byte, encoded []byte) interface{} {
+	v := int64(binary.BigEndian.Uint64(encoded) ^ (1 << 63))
+	switch typ {
+	case rangeTypeInt:
+		return int32(v)
+	case rangeTypeDouble:
+		return util.SortableLongToDouble(v)
+	}
+	return v
+}
+
+/*
+Returns a string representation of encoded ranges, e.g. "[1 : 5] [2 : 6]"
+for a 2 dimensional range from [1, 2] to [5, 6].
+*/
+func RangesString(encoded []byte) string {
+	return rangesString(encoded, func(min, max interface{}) string {
+		return fmt.Sprintf("[%v : %v]", min, max)
+	}, " ")
+}
+
+// Formats the min and max values of each dimension of encoded ranges,
+// separated by sep.
+func rangesString(encoded []byte, format func(min, max interface{}) string, sep string) string {
+	var buf bytes.Buffer
+	for d := 0; d < int(encoded[1]); d++ {
+		if d > 0 {
+			buf.WriteString(sep)
+		}
+		buf.WriteString(format(decodeRangeValue(encoded[0], rangeMin(encoded, d)),
+			decodeRangeValue(encoded[0], rangeMax(encoded, d))))
+	}
+	return buf.String()
+}
+
+/*
+Relates encoded ranges of a document to the ones of a query, returning
+(intersects, within, contains): whether the ranges of the document
+intersect with, are within, or contain the ones of the query, in all
+dimensions. Ranges of different types or number of dimensions never
+relate.
+*/
+func RelateRanges(doc, query []byte) (intersects, within, contains bool) {
+	if len(doc) != len(query) || len(doc) < 2 || doc[0] != query[0] {
+		return false, false, false
+	}
+	intersects, within, contains = true, true, true
+	for d := 0; d < int(doc[1]); d++ {
+		docMin, docMax := rangeMin(doc, d), rangeMax(doc, d)
+		qMin, qMax := rangeMin(query, d), rangeMax(query, d)
+		if bytes.Compare(docMin, qMax) > 0 || bytes.Compare(docMax, qMin) < 0 {
+			return false, false, false
+		}
+		within = within && bytes.Compare(docMin, qMin) >= 0 && bytes.Compare(docMax, qMax) <= 0
+		contains = contains && bytes.Compare(docMin, qMin) <= 0 && bytes.Compare(docMax, qMax) >= 0
+	}
+	return
+}
+
+// document/IntRange.java
+
+/*
+An indexed Integer Range field. This field indexes dimensional ranges
+defined as min/max pairs. It supports up to a maximum of 4 dimensions
+(indexed as 8 numeric values). With 1 dimension representing a single
+integer range, 2 dimensions representing a bounding box, 3 dimensions
+a bounding cube, and 4 dimensions a tesseract.
+
+Multiple values for the same field in one document are supported, and
+open ended ranges can be defined using math.MinInt32 and math.MaxInt32.
+
+As there is no points (BKD) format in the codecs yet, the encoded
+range is kept as a stored field, read back by the queries of
+search.NewIntRangeQuery().
+*/
+type IntRange struct {
+	*Field
+}
+
+// Create a new IntRange type, from min/max parallel arrays.
+func NewIntRange(name string, min, max []int32) *IntRange {
+	assert2(name != "", "name cannot be empty")
+	return &IntRange{&Field{_type: STORED_FIELD_TYPE, _name: name,
+		_data: EncodeIntRanges(min, max), _boost: 1}}
+}
+
+// Encodes min/max parallel arrays of an IntRange.
+func EncodeIntRanges(min, max []int32) []byte {
+	min64, max64 := make([]int64, len(min)), make([]int64, len(max))
+	for i, v := range min {
+		min64[i] = int64(v)
+	}
+	for i, v := range max {
+		max64[i] = int64(v)
+	}
+	return encodeRanges(rangeTypeInt, min64, max64)
+}
+
+// Get the min value for the given dimension.
+func (f *IntRange) Min(dimension int) int32 {
+	return decodeRangeValue(rangeTypeInt, rangeMin(f._data.([]byte), dimension)).(int32)
+}
+
+// Get the max value for the given dimension.
+func (f *IntRange) Max(dimension int) int32 {
+	return decodeRangeValue(rangeTypeInt, rangeMax(f._data.([]byte), dimension)).(int32)
+}
+
+func (f *IntRange) String() string {
+	return fmt.Sprintf("IntRange <%v: %v>", f._name, RangesString(f._data.([]byte)))
+}
+
+// document/LongRange.java
+
+/*
+An indexed Long Range field, with up to 4 dimensions like IntRange.
+Open ended ranges can be defined using math.MinInt64 and math.MaxInt64.
+*/
+type LongRange struct {
+	*Field
+}
+
+// Create a new LongRange type, from min/max parallel arrays.
+func NewLongRange(name string, min, max []int64) *LongRange {
+	assert2(name != "", "name cannot be empty")
+	return &LongRange{&Field{_type: STORED_FIELD_TYPE, _name: name,
+		_data: EncodeLongRanges(min, max), _boost: 1}}
+}
+
+// Encodes min/max parallel arrays of a LongRange.
+func EncodeLongRanges(min, max []int64) []byte {
+	return encodeRanges(rangeTypeLong, min, max)
+}
+
+// Get the min value for the given dimension.
+func (f *LongRange) Min(dimension int) int64 {
+	return decodeRangeValue(rangeTypeLong, rangeMin(f._data.([]byte), dimension)).(int64)
+}
+
+// Get the max value for the given dimension.
+func (f *LongRange) Max(dimension int) int64 {
+	return decodeRangeValue(rangeTypeLong, rangeMax(f._data.([]byte), dimension)).(int64)
+}
+
+func (f *LongRange) String() string {
+	return fmt.Sprintf("LongRange <%v: %v>", f._name, RangesString(f._data.([]byte)))
+}
+
+// document/DoubleRange.java
+
+/*
+An indexed Double Range field, with up to 4 dimensions like IntRange.
+Open ended ranges can be defined using math.Inf(-1) and math.Inf(1).
+*/
+type DoubleRange struct {
+	*Field
+}
+
+// Create a new DoubleRange type, from min/max parallel arrays.
+func NewDoubleRange(name string, min, max []float64) *DoubleRange {
+	assert2(name != "", "name cannot be empty")
+	return &DoubleRange{&Field{_type: STORED_FIELD_TYPE, _name: name,
+		_data: EncodeDoubleRanges(min, max), _boost: 1}}
+}
+
+// Encodes min/max parallel arrays of a DoubleRange.
+func EncodeDoubleRanges(min, max []float64) []byte {
+	min64, max64 := make([]int64, len(min)), make([]int64, len(max))
+	for i, v := range min {
+		assert2(!math.IsNaN(v), "invalid min value (NaN) in DoubleRange")
+		min64[i] = util.DoubleToSortableLong(v)
+	}
+	for i, v := range max {
+		assert2(!math.IsNaN(v), "invalid max value (NaN) in DoubleRange")
+		max64[i] = util.DoubleToSortableLong(v)
+	}
+	return encodeRanges(rangeTypeDouble, min64, max64)
+}
+
+// Get the min value for the given dimension.
+func (f *DoubleRange) Min(dimension int) float64 {
+	return decodeRangeValue(rangeTypeDouble, rangeMin(f._data.([]byte), dimension)).(float64)
+}
+
+// Get the max value for the given dimension.
+func (f *DoubleRange) Max(dimension int) float64 {
+	return decodeRangeValue(rangeTypeDouble, rangeMax(f._data.([]byte), dimension)).(float64)
+}
+
+func (f *DoubleRange) String() string {
+	return fmt.Sprintf("DoubleRange <%v: %v>", f._name, RangesString(f._data.([]byte)))
+}
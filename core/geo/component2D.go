@@ -0,0 +1,162 @@
+package geo
+
+import (
+	"math"
+)
+
+// index/PointValues.java#Relation
+
+// Describes the relationship between an indexed shape and a query
+// shape.
+type Relation int
+
+const (
+	// Return this if the indexed shape is fully contained by the query.
+	CELL_INSIDE_QUERY Relation = iota
+	// Return this if the indexed shape and query do not overlap.
+	CELL_OUTSIDE_QUERY
+	// Return this if the indexed shape partially overlaps the query.
+	CELL_CROSSES_QUERY
+)
+
+// geo/Component2D.java
+
+/*
+Relates a triangle to this polygon, holes excluded. Shared boundaries
+count as crossing.
+*/
+func (p *Polygon) RelateTriangle(t *Triangle) Relation {
+	if !p.boxIntersects(t) {
+		return CELL_OUTSIDE_QUERY
+	}
+	if p.crossesTriangle(t) {
+		return CELL_CROSSES_QUERY
+	}
+	for _, hole := range p.holes {
+		if hole.crossesTriangle(t) {
+			return CELL_CROSSES_QUERY
+		}
+	}
+	// no edges cross, so the triangle is either fully inside or fully
+	// outside the polygon, which may in turn be inside the triangle
+	if p.Contains(t.Lats[0], t.Lons[0]) {
+		for _, hole := range p.holes {
+			if t.Contains(hole.polyLats[0], hole.polyLons[0]) {
+				return CELL_CROSSES_QUERY
+			}
+		}
+		return CELL_INSIDE_QUERY
+	}
+	if t.Contains(p.polyLats[0], p.polyLons[0]) {
+		return CELL_CROSSES_QUERY
+	}
+	return CELL_OUTSIDE_QUERY
+}
+
+func (p *Polygon) boxIntersects(t *Triangle) bool {
+	minLat, maxLat, minLon, maxLon := t.bounds()
+	return !(maxLat < p.MinLat || minLat > p.MaxLat || maxLon < p.MinLon || minLon > p.MaxLon)
+}
+
+// Returns true if an edge of the ring, holes excluded, intersects an
+// edge of the triangle.
+func (p *Polygon) crossesTriangle(t *Triangle) bool {
+	for i := 1; i < len(p.polyLats); i++ {
+		if t.crossesSegment(p.polyLats[i-1], p.polyLons[i-1], p.polyLats[i], p.polyLons[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+Relates a triangle to this line. As a line has no area, it can only
+cross or be outside of a triangle.
+*/
+func (l *Line) RelateTriangle(t *Triangle) Relation {
+	minLat, maxLat, minLon, maxLon := t.bounds()
+	if maxLat < l.MinLat || minLat > l.MaxLat || maxLon < l.MinLon || minLon > l.MaxLon {
+		return CELL_OUTSIDE_QUERY
+	}
+	for i := 1; i < len(l.lats); i++ {
+		if t.crossesSegment(l.lats[i-1], l.lons[i-1], l.lats[i], l.lons[i]) {
+			return CELL_CROSSES_QUERY
+		}
+	}
+	if t.Contains(l.lats[0], l.lons[0]) {
+		return CELL_CROSSES_QUERY
+	}
+	return CELL_OUTSIDE_QUERY
+}
+
+func (t *Triangle) bounds() (minLat, maxLat, minLon, maxLon float64) {
+	minLat = math.Min(t.Lats[0], math.Min(t.Lats[1], t.Lats[2]))
+	maxLat = math.Max(t.Lats[0], math.Max(t.Lats[1], t.Lats[2]))
+	minLon = math.Min(t.Lons[0], math.Min(t.Lons[1], t.Lons[2]))
+	maxLon = math.Max(t.Lons[0], math.Max(t.Lons[1], t.Lons[2]))
+	return
+}
+
+/*
+Returns true if the point lies in the triangle, edges included. The
+point of a degenerate triangle must lie on one of its edges.
+*/
+func (t *Triangle) Contains(lat, lon float64) bool {
+	o1 := orient(t.Lons[0], t.Lats[0], t.Lons[1], t.Lats[1], lon, lat)
+	o2 := orient(t.Lons[1], t.Lats[1], t.Lons[2], t.Lats[2], lon, lat)
+	o3 := orient(t.Lons[2], t.Lats[2], t.Lons[0], t.Lats[0], lon, lat)
+	if orient(t.Lons[0], t.Lats[0], t.Lons[1], t.Lats[1], t.Lons[2], t.Lats[2]) == 0 {
+		for i := 0; i < 3; i++ {
+			j := (i + 1) % 3
+			if orient(t.Lons[i], t.Lats[i], t.Lons[j], t.Lats[j], lon, lat) == 0 &&
+				inBox(t.Lons[i], t.Lats[i], t.Lons[j], t.Lats[j], lon, lat) {
+				return true
+			}
+		}
+		return false
+	}
+	return (o1 >= 0 && o2 >= 0 && o3 >= 0) || (o1 <= 0 && o2 <= 0 && o3 <= 0)
+}
+
+// Returns true if the segment intersects an edge of the triangle.
+func (t *Triangle) crossesSegment(aLat, aLon, bLat, bLon float64) bool {
+	for i := 0; i < 3; i++ {
+		j := (i + 1) % 3
+		if segmentsIntersect(aLon, aLat, bLon, bLat, t.Lons[i], t.Lats[i], t.Lons[j], t.Lats[j]) {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the orientation of c relative to the directed line ab: 1
+// if counter-clockwise, -1 if clockwise, and 0 if collinear.
+func orient(ax, ay, bx, by, cx, cy float64) int {
+	v := (bx-ax)*(cy-ay) - (by-ay)*(cx-ax)
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	}
+	return 0
+}
+
+// Returns true if c lies in the bounding box of segment ab.
+func inBox(ax, ay, bx, by, cx, cy float64) bool {
+	return cx >= math.Min(ax, bx) && cx <= math.Max(ax, bx) &&
+		cy >= math.Min(ay, by) && cy <= math.Max(ay, by)
+}
+
+// Returns true if segments ab and cd intersect, touching included.
+func segmentsIntersect(ax, ay, bx, by, cx, cy, dx, dy float64) bool {
+	o1, o2 := orient(ax, ay, bx, by, cx, cy), orient(ax, ay, bx, by, dx, dy)
+	o3, o4 := orient(cx, cy, dx, dy, ax, ay), orient(cx, cy, dx, dy, bx, by)
+	if o1 != o2 && o3 != o4 {
+		return true
+	}
+	return o1 == 0 && inBox(ax, ay, bx, by, cx, cy) ||
+		o2 == 0 && inBox(ax, ay, bx, by, dx, dy) ||
+		o3 == 0 && inBox(cx, cy, dx, dy, ax, ay) ||
+		o4 == 0 && inBox(cx, cy, dx, dy, bx, by)
+}
@@ -0,0 +1,90 @@
+package geo
+
+import (
+	"fmt"
+	"math"
+)
+
+// geo/GeoUtils.java
+
+const (
+	// Minimum longitude value.
+	MIN_LON_INCL = -180.0
+	// Maximum longitude value.
+	MAX_LON_INCL = 180.0
+	// Minimum latitude value.
+	MIN_LAT_INCL = -90.0
+	// Maximum latitude value.
+	MAX_LAT_INCL = 90.0
+)
+
+// Validates latitude value is within standard +/-90 coordinate bounds.
+func CheckLatitude(latitude float64) {
+	if math.IsNaN(latitude) || latitude < MIN_LAT_INCL || latitude > MAX_LAT_INCL {
+		panic(fmt.Sprintf("invalid latitude %v; must be between %v and %v",
+			latitude, MIN_LAT_INCL, MAX_LAT_INCL))
+	}
+}
+
+// Validates longitude value is within standard +/-180 coordinate bounds.
+func CheckLongitude(longitude float64) {
+	if math.IsNaN(longitude) || longitude < MIN_LON_INCL || longitude > MAX_LON_INCL {
+		panic(fmt.Sprintf("invalid longitude %v; must be between %v and %v",
+			longitude, MIN_LON_INCL, MAX_LON_INCL))
+	}
+}
+
+// geo/GeoEncodingUtils.java
+
+const (
+	lat_scale  = float64(int64(1)<<32) / 180
+	lat_decode = 1 / lat_scale
+	lon_scale  = float64(int64(1)<<32) / 360
+	lon_decode = 1 / lon_scale
+)
+
+/*
+Quantizes double (64 bit) latitude into 32 bits (rounding down: in
+the direction of -90).
+*/
+func EncodeLatitude(latitude float64) int32 {
+	CheckLatitude(latitude)
+	// the maximum possible value cannot be encoded without overflow
+	if latitude == MAX_LAT_INCL {
+		latitude = math.Nextafter(latitude, 0)
+	}
+	return int32(math.Floor(latitude / lat_decode))
+}
+
+/*
+Quantizes double (64 bit) longitude into 32 bits (rounding down: in
+the direction of -180).
+*/
+func EncodeLongitude(longitude float64) int32 {
+	CheckLongitude(longitude)
+	// the maximum possible value cannot be encoded without overflow
+	if longitude == MAX_LON_INCL {
+		longitude = math.Nextafter(longitude, 0)
+	}
+	return int32(math.Floor(longitude / lon_decode))
+}
+
+// Turns quantized value from EncodeLatitude() back into a double.
+func DecodeLatitude(encoded int32) float64 {
+	ans := float64(encoded) * lat_decode
+	assert(ans >= MIN_LAT_INCL && ans < MAX_LAT_INCL)
+	return ans
+}
+
+// Turns quantized value from EncodeLongitude() back into a double.
+func DecodeLongitude(encoded int32) float64 {
+	ans := float64(encoded) * lon_decode
+	assert(ans >= MIN_LON_INCL && ans < MAX_LON_INCL)
+	return ans
+}
+
+func assert(ok bool) {
+	if !ok {
+		panic("assert fail")
+	}
+}
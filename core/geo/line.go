@@ -0,0 +1,90 @@
+package geo
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+)
+
+// geo/Line.java
+
+/*
+Represents a line on the earth's surface, constructed with float64
+latitude and longitude arrays in decimal degrees.
+*/
+type Line struct {
+	lats, lons []float64
+	// minimum latitude of this line's bounding box
+	MinLat float64
+	// maximum latitude of this line's bounding box
+	MaxLat float64
+	// minimum longitude of this line's bounding box
+	MinLon float64
+	// maximum longitude of this line's bounding box
+	MaxLon float64
+}
+
+// Creates a new Line from the supplied latitude/longitude array.
+func NewLine(lats, lons []float64) *Line {
+	if len(lats) != len(lons) {
+		panic("lats and lons must be equal length")
+	}
+	if len(lats) < 2 {
+		panic("at least 2 line points required")
+	}
+	ans := &Line{
+		lats:   append([]float64(nil), lats...),
+		lons:   append([]float64(nil), lons...),
+		MinLat: lats[0],
+		MaxLat: lats[0],
+		MinLon: lons[0],
+		MaxLon: lons[0],
+	}
+	for i := range lats {
+		CheckLatitude(lats[i])
+		CheckLongitude(lons[i])
+		ans.MinLat = math.Min(ans.MinLat, lats[i])
+		ans.MaxLat = math.Max(ans.MaxLat, lats[i])
+		ans.MinLon = math.Min(ans.MinLon, lons[i])
+		ans.MaxLon = math.Max(ans.MaxLon, lons[i])
+	}
+	return ans
+}
+
+// Returns the number of vertices.
+func (l *Line) NumPoints() int {
+	return len(l.lats)
+}
+
+// Returns latitude value at given index.
+func (l *Line) Lat(vertex int) float64 {
+	return l.lats[vertex]
+}
+
+// Returns longitude value at given index.
+func (l *Line) Lon(vertex int) float64 {
+	return l.lons[vertex]
+}
+
+// Returns a copy of the internal latitude array.
+func (l *Line) Lats() []float64 {
+	return append([]float64(nil), l.lats...)
+}
+
+// Returns a copy of the internal longitude array.
+func (l *Line) Lons() []float64 {
+	return append([]float64(nil), l.lons...)
+}
+
+func (l *Line) String() string {
+	var buf bytes.Buffer
+	buf.WriteString("LINE(")
+	for i := range l.lats {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		fmt.Fprintf(&buf, "[%v, %v]", l.lons[i], l.lats[i])
+	}
+	buf.WriteString(")")
+	return buf.String()
+}
@@ -0,0 +1,146 @@
+package geo
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+)
+
+// geo/Polygon.java
+
+/*
+Represents a closed polygon on the earth's surface, constructed with
+float64 latitude and longitude arrays.
+
+NOTES:
+ 1. The polygon must be closed: the first and last coordinates need
+    to have the same values.
+ 2. The polygon must not be self-crossing, otherwise may result in
+    unexpected behavior.
+ 3. All latitude/longitude values must be in decimal degrees.
+ 4. Polygons cannot cross the 180th meridian. Instead, use two
+    polygons: one on each side.
+*/
+type Polygon struct {
+	polyLats, polyLons []float64
+	holes              []*Polygon
+	// minimum latitude of this polygon's bounding box area
+	MinLat float64
+	// maximum latitude of this polygon's bounding box area
+	MaxLat float64
+	// minimum longitude of this polygon's bounding box area
+	MinLon float64
+	// maximum longitude of this polygon's bounding box area
+	MaxLon float64
+}
+
+/*
+Creates a new Polygon from the supplied latitude/longitude array,
+and optionally any holes.
+*/
+func NewPolygon(polyLats, polyLons []float64, holes ...*Polygon) *Polygon {
+	if len(polyLats) != len(polyLons) {
+		panic("polyLats and polyLons must be equal length")
+	}
+	if len(polyLats) < 4 {
+		panic("at least 4 polygon points required")
+	}
+	last := len(polyLats) - 1
+	if polyLats[0] != polyLats[last] {
+		panic(fmt.Sprintf("first and last points of the polygon must be the same (it must close itself): polyLats[0]=%v polyLats[%v]=%v",
+			polyLats[0], last, polyLats[last]))
+	}
+	if polyLons[0] != polyLons[last] {
+		panic(fmt.Sprintf("first and last points of the polygon must be the same (it must close itself): polyLons[0]=%v polyLons[%v]=%v",
+			polyLons[0], last, polyLons[last]))
+	}
+	for _, hole := range holes {
+		if hole == nil {
+			panic("holes must not contain nil")
+		}
+		if len(hole.holes) > 0 {
+			panic("holes may not contain holes: polygons may not nest.")
+		}
+	}
+
+	ans := &Polygon{
+		polyLats: append([]float64(nil), polyLats...),
+		polyLons: append([]float64(nil), polyLons...),
+		holes:    append([]*Polygon(nil), holes...),
+		MinLat:   polyLats[0],
+		MaxLat:   polyLats[0],
+		MinLon:   polyLons[0],
+		MaxLon:   polyLons[0],
+	}
+	for i := range polyLats {
+		CheckLatitude(polyLats[i])
+		CheckLongitude(polyLons[i])
+		ans.MinLat = math.Min(ans.MinLat, polyLats[i])
+		ans.MaxLat = math.Max(ans.MaxLat, polyLats[i])
+		ans.MinLon = math.Min(ans.MinLon, polyLons[i])
+		ans.MaxLon = math.Max(ans.MaxLon, polyLons[i])
+	}
+	return ans
+}
+
+// Returns a copy of the internal latitude array.
+func (p *Polygon) PolyLats() []float64 {
+	return append([]float64(nil), p.polyLats...)
+}
+
+// Returns a copy of the internal longitude array.
+func (p *Polygon) PolyLons() []float64 {
+	return append([]float64(nil), p.polyLons...)
+}
+
+// Returns a copy of the internal holes array.
+func (p *Polygon) Holes() []*Polygon {
+	return append([]*Polygon(nil), p.holes...)
+}
+
+/*
+Returns true if the point is inside this polygon and not inside any
+of its holes. Points exactly on an edge may be considered inside or
+outside.
+*/
+func (p *Polygon) Contains(latitude, longitude float64) bool {
+	if latitude < p.MinLat || latitude > p.MaxLat ||
+		longitude < p.MinLon || longitude > p.MaxLon {
+		return false
+	}
+	if !p.crossesOdd(latitude, longitude) {
+		return false
+	}
+	for _, hole := range p.holes {
+		if hole.Contains(latitude, longitude) {
+			return false
+		}
+	}
+	return true
+}
+
+// Casts a ray from the point eastwards, and returns true if it crosses
+// an odd number of edges.
+func (p *Polygon) crossesOdd(latitude, longitude float64) bool {
+	inside := false
+	for i, j := 0, len(p.polyLats)-1; i < len(p.polyLats); j, i = i, i+1 {
+		lat1, lon1 := p.polyLats[i], p.polyLons[i]
+		lat2, lon2 := p.polyLats[j], p.polyLons[j]
+		if (lat1 > latitude) != (lat2 > latitude) &&
+			longitude < (lon2-lon1)*(latitude-lat1)/(lat2-lat1)+lon1 {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+func (p *Polygon) String() string {
+	var buf bytes.Buffer
+	for i := range p.polyLats {
+		fmt.Fprintf(&buf, "[%v, %v] ", p.polyLats[i], p.polyLons[i])
+	}
+	if len(p.holes) > 0 {
+		fmt.Fprintf(&buf, ", holes=%v", p.holes)
+	}
+	return buf.String()
+}
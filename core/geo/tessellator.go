@@ -0,0 +1,522 @@
+package geo
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// geo/Tessellator.java
+
+/*
+A triangle of a tessellated shape. Its vertices are given in
+counter-clockwise order; degenerate triangles represent points (all
+vertices equal) and line segments (two vertices equal).
+*/
+type Triangle struct {
+	Lats, Lons [3]float64
+}
+
+func (t *Triangle) String() string {
+	return fmt.Sprintf("Triangle[[%v, %v], [%v, %v], [%v, %v]]",
+		t.Lons[0], t.Lats[0], t.Lons[1], t.Lats[1], t.Lons[2], t.Lats[2])
+}
+
+/*
+Computes a triangular mesh tessellation for a given polygon, holes
+included, using the ear clipping algorithm of the earcut library,
+without its z-order hashing.
+
+Holes are first bridged to the outer shell, each at its leftmost
+vertex, turning the polygon into a single ring. Ears, triangles
+formed by a convex vertex and its neighbours that contain no other
+vertex, are then clipped one at a time. If no ear can be found, the
+ring is filtered of collinear points, cured of local self
+intersections and eventually split in two along a valid diagonal,
+before giving up.
+
+Panics if the polygon cannot be tessellated, e.g. because it is
+self-crossing.
+*/
+func Tessellate(polygon *Polygon) []*Triangle {
+	outerNode := createDoublyLinkedList(polygon, true)
+	if outerNode == nil || outerNode.next == outerNode.prev {
+		panic("Malformed shape detected in Tessellator!")
+	}
+	if len(polygon.holes) > 0 {
+		outerNode = eliminateHoles(polygon, outerNode)
+	}
+	var result []*Triangle
+	earcutLinked(outerNode, &result, 0)
+	if len(result) == 0 {
+		panic("Unable to Tessellate shape. Possible malformed shape detected.")
+	}
+	return result
+}
+
+// A vertex of the ring, in a circular doubly linked list.
+type tessellatorNode struct {
+	// vertex index in the polygon, to tell apart the copies of the
+	// vertices created by splitPolygon
+	idx        int
+	x, y       float64 // longitude, latitude
+	prev, next *tessellatorNode
+	steiner    bool
+}
+
+/*
+Creates a circular doubly linked list of the vertices of polygon, in
+the requested winding order. The closing vertex is removed.
+*/
+func createDoublyLinkedList(polygon *Polygon, clockwise bool) *tessellatorNode {
+	lats, lons := polygon.polyLats, polygon.polyLons
+	var last *tessellatorNode
+	if clockwise == (signedArea(lats, lons) > 0) {
+		for i := range lats {
+			last = insertNode(i, lons[i], lats[i], last)
+		}
+	} else {
+		for i := len(lats) - 1; i >= 0; i-- {
+			last = insertNode(i, lons[i], lats[i], last)
+		}
+	}
+	if last != nil && isVertexEquals(last, last.next) {
+		removeNode(last)
+		last = last.next
+	}
+	return last
+}
+
+// Links every hole into the outer loop, producing a single-ring
+// polygon without holes.
+func eliminateHoles(polygon *Polygon, outerNode *tessellatorNode) *tessellatorNode {
+	holes := make([]*tessellatorNode, 0, len(polygon.holes))
+	offset := len(polygon.polyLats)
+	for _, hole := range polygon.holes {
+		list := createDoublyLinkedList(hole, false)
+		if list == nil {
+			continue
+		}
+		// give the hole vertices their own indexes
+		p := list
+		for {
+			p.idx += offset
+			if p = p.next; p == list {
+				break
+			}
+		}
+		offset += len(hole.polyLats)
+		if list == list.next {
+			list.steiner = true
+		}
+		holes = append(holes, fetchLeftmost(list))
+	}
+	// process holes from left to right
+	sort.SliceStable(holes, func(i, j int) bool {
+		return holes[i].x < holes[j].x
+	})
+	for _, hole := range holes {
+		outerNode = eliminateHole(hole, outerNode)
+		outerNode = filterPoints(outerNode, outerNode.next)
+	}
+	return outerNode
+}
+
+// Finds a bridge between vertices that connects the hole with the
+// outer ring, and links it.
+func eliminateHole(holeNode, outerNode *tessellatorNode) *tessellatorNode {
+	bridge := fetchHoleBridge(holeNode, outerNode)
+	if bridge == nil {
+		return outerNode
+	}
+	b := splitPolygon(bridge, holeNode)
+	filterPoints(b, b.next)
+	return bridge
+}
+
+// David Eberly's algorithm for finding a bridge between a hole and
+// the outer polygon.
+func fetchHoleBridge(holeNode, outerNode *tessellatorNode) *tessellatorNode {
+	p := outerNode
+	qx := math.Inf(-1)
+	hx, hy := holeNode.x, holeNode.y
+	var connection *tessellatorNode
+	// find a segment intersected by a ray from the hole's leftmost
+	// point to the left; segment's endpoint with lesser x will be the
+	// potential connection point
+	for {
+		if hy <= p.y && hy >= p.next.y && p.next.y != p.y {
+			x := p.x + (hy-p.y)*(p.next.x-p.x)/(p.next.y-p.y)
+			if x <= hx && x > qx {
+				qx = x
+				if x == hx {
+					if hy == p.y {
+						return p
+					}
+					if hy == p.next.y {
+						return p.next
+					}
+				}
+				if p.x < p.next.x {
+					connection = p
+				} else {
+					connection = p.next
+				}
+			}
+		}
+		if p = p.next; p == outerNode {
+			break
+		}
+	}
+	if connection == nil {
+		return nil
+	} else if hx == qx {
+		return connection.prev
+	}
+
+	// look for points inside the triangle of hole point, segment
+	// intersection and endpoint; if there are no points found, we have
+	// a valid connection; otherwise choose the point of the minimum
+	// angle with the ray as the connection point
+	stop := connection
+	mx, my := connection.x, connection.y
+	tanMin := math.Inf(1)
+	p = connection
+	for {
+		if hx >= p.x && p.x >= mx && hx != p.x {
+			var inside bool
+			if hy < my {
+				inside = pointInEar(p.x, p.y, hx, hy, mx, my, qx, hy)
+			} else {
+				inside = pointInEar(p.x, p.y, qx, hy, mx, my, hx, hy)
+			}
+			if inside {
+				tan := math.Abs(hy-p.y) / (hx - p.x) // tangential
+				if isLocallyInside(p, holeNode) &&
+					(tan < tanMin || (tan == tanMin && p.x > connection.x)) {
+					connection = p
+					tanMin = tan
+				}
+			}
+		}
+		if p = p.next; p == stop {
+			break
+		}
+	}
+	return connection
+}
+
+// Finds the leftmost node of a ring.
+func fetchLeftmost(start *tessellatorNode) *tessellatorNode {
+	node, leftMost := start, start
+	for {
+		if node.x < leftMost.x || (node.x == leftMost.x && node.y < leftMost.y) {
+			leftMost = node
+		}
+		if node = node.next; node == start {
+			break
+		}
+	}
+	return leftMost
+}
+
+// Main ear slicing loop which triangulates the vertices of a polygon,
+// provided as a doubly-linked list.
+func earcutLinked(currEar *tessellatorNode, result *[]*Triangle, state int) {
+	if currEar == nil {
+		return
+	}
+	stop := currEar
+	// iterate through remaining polygon vertices, slicing off ears one by one
+	for currEar.prev != currEar.next {
+		prevNode, nextNode := currEar.prev, currEar.next
+		if isEar(currEar) {
+			// compute the triangle and remove the ear
+			*result = append(*result, newTriangle(prevNode, currEar, nextNode))
+			removeNode(currEar)
+			// skipping to the next node leaves less slivers
+			currEar = nextNode.next
+			stop = nextNode.next
+			continue
+		}
+		currEar = nextNode
+		// if we loop through the whole remaining polygon and can't find
+		// any more ears
+		if currEar == stop {
+			switch state {
+			case 0:
+				// try filtering points and slicing again
+				earcutLinked(filterPoints(currEar, nil), result, 1)
+			case 1:
+				// if this didn't work, try curing all small self-intersections locally
+				currEar = cureLocalIntersections(filterPoints(currEar, nil), result)
+				earcutLinked(currEar, result, 2)
+			case 2:
+				// as a last resort, try splitting the remaining polygon
+				// into two
+				if !splitEarcut(currEar, result) {
+					panic("Unable to Tessellate shape. Possible malformed shape detected.")
+				}
+			}
+			return
+		}
+	}
+}
+
+// Determines whether a polygon node forms a valid ear with adjacent
+// nodes.
+func isEar(ear *tessellatorNode) bool {
+	a, b, c := ear.prev, ear, ear.next
+	if area(a, b, c) >= 0 {
+		return false // reflex, can't be an ear
+	}
+	// make sure we don't have other points inside the potential ear
+	for p := c.next; p != a; p = p.next {
+		if pointInEar(p.x, p.y, a.x, a.y, b.x, b.y, c.x, c.y) &&
+			area(p.prev, p, p.next) >= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Iterates over all polygon nodes and cures small local
+// self-intersections.
+func cureLocalIntersections(startNode *tessellatorNode, result *[]*Triangle) *tessellatorNode {
+	node := startNode
+	for {
+		a, b := node.prev, node.next.next
+		// a self-intersection where edge (v[i-1],v[i]) intersects
+		// (v[i+1],v[i+2])
+		if !isVertexEquals(a, b) && linesIntersect(a, node, node.next, b) &&
+			isLocallyInside(a, b) && isLocallyInside(b, a) {
+			// compute the triangle and remove the nodes
+			*result = append(*result, newTriangle(a, node, b))
+			removeNode(node)
+			removeNode(node.next)
+			node, startNode = b, b
+		}
+		if node = node.next; node == startNode {
+			break
+		}
+	}
+	return filterPoints(node, nil)
+}
+
+// Attempts to split a polygon along a valid diagonal and tessellate
+// both halves independently.
+func splitEarcut(start *tessellatorNode, result *[]*Triangle) bool {
+	// search for a valid diagonal that divides the polygon into two
+	searchNode := start
+	for {
+		for diagonal := searchNode.next.next; diagonal != searchNode.prev; diagonal = diagonal.next {
+			if searchNode.idx != diagonal.idx && isValidDiagonal(searchNode, diagonal) {
+				// split the polygon in two along the diagonal
+				splitNode := splitPolygon(searchNode, diagonal)
+				// filter collinear points around the cuts
+				searchNode = filterPoints(searchNode, searchNode.next)
+				splitNode = filterPoints(splitNode, splitNode.next)
+				// run earcut on each half
+				earcutLinked(searchNode, result, 0)
+				earcutLinked(splitNode, result, 0)
+				return true
+			}
+		}
+		if searchNode = searchNode.next; searchNode == start {
+			break
+		}
+	}
+	return false
+}
+
+// Determines whether a diagonal between two polygon nodes lies
+// within the polygon interior.
+func isValidDiagonal(a, b *tessellatorNode) bool {
+	return a.next.idx != b.idx && a.prev.idx != b.idx &&
+		!isIntersectingPolygon(a, b) &&
+		(isLocallyInside(a, b) && isLocallyInside(b, a) && middleInside(a, b) &&
+			// does not create opposite-facing sectors
+			(area(a.prev, a, b.prev) != 0 || area(a, b.prev, b) != 0) ||
+			// special zero-length case
+			isVertexEquals(a, b) && area(a.prev, a, a.next) > 0 && area(b.prev, b, b.next) > 0)
+}
+
+// Determines whether a polygon diagonal rests locally inside the
+// polygon.
+func isLocallyInside(a, b *tessellatorNode) bool {
+	if area(a.prev, a, a.next) < 0 {
+		return area(a, b, a.next) >= 0 && area(a, a.prev, b) >= 0
+	}
+	return area(a, b, a.prev) < 0 || area(a, a.next, b) < 0
+}
+
+// Determines whether the middle point of a polygon diagonal is
+// contained within the polygon.
+func middleInside(a, b *tessellatorNode) bool {
+	node := a
+	lIn := false
+	x, y := (a.x+b.x)/2, (a.y+b.y)/2
+	for {
+		if ((node.y > y) != (node.next.y > y)) && node.next.y != node.y &&
+			x < (node.next.x-node.x)*(y-node.y)/(node.next.y-node.y)+node.x {
+			lIn = !lIn
+		}
+		if node = node.next; node == a {
+			break
+		}
+	}
+	return lIn
+}
+
+// Determines if the diagonal of a polygon is intersecting with any
+// polygon elements.
+func isIntersectingPolygon(a, b *tessellatorNode) bool {
+	node := a
+	for {
+		if node.idx != a.idx && node.next.idx != a.idx &&
+			node.idx != b.idx && node.next.idx != b.idx &&
+			linesIntersect(node, node.next, a, b) {
+			return true
+		}
+		if node = node.next; node == a {
+			break
+		}
+	}
+	return false
+}
+
+// Determines whether two line segments intersect, touching included.
+func linesIntersect(p1, q1, p2, q2 *tessellatorNode) bool {
+	o1, o2 := sign(area(p1, q1, p2)), sign(area(p1, q1, q2))
+	o3, o4 := sign(area(p2, q2, p1)), sign(area(p2, q2, q1))
+	if o1 != o2 && o3 != o4 {
+		return true
+	}
+	return o1 == 0 && onSegment(p1, p2, q1) ||
+		o2 == 0 && onSegment(p1, q2, q1) ||
+		o3 == 0 && onSegment(p2, p1, q2) ||
+		o4 == 0 && onSegment(p2, q1, q2)
+}
+
+// For collinear p, q and r, returns true if q lies on segment pr.
+func onSegment(p, q, r *tessellatorNode) bool {
+	return q.x <= math.Max(p.x, r.x) && q.x >= math.Min(p.x, r.x) &&
+		q.y <= math.Max(p.y, r.y) && q.y >= math.Min(p.y, r.y)
+}
+
+func sign(v float64) int {
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	}
+	return 0
+}
+
+/*
+Links two polygon vertices using a bridge, splitting the ring in
+two. Returns the copy of b in the new ring.
+*/
+func splitPolygon(a, b *tessellatorNode) *tessellatorNode {
+	a2 := &tessellatorNode{idx: a.idx, x: a.x, y: a.y}
+	b2 := &tessellatorNode{idx: b.idx, x: b.x, y: b.y}
+	an, bp := a.next, b.prev
+
+	a.next, b.prev = b, a
+	a2.next, an.prev = an, a2
+	b2.next, a2.prev = a2, b2
+	bp.next, b2.prev = b2, bp
+	return b2
+}
+
+// Eliminates collinear and duplicate points of a ring.
+func filterPoints(start, end *tessellatorNode) *tessellatorNode {
+	if start == nil {
+		return start
+	}
+	if end == nil {
+		end = start
+	}
+	node := start
+	for {
+		again := false
+		if !node.steiner && (isVertexEquals(node, node.next) ||
+			area(node.prev, node, node.next) == 0) {
+			// remove the duplicate or collinear node
+			removeNode(node)
+			node, end = node.prev, node.prev
+			if node == node.next {
+				break
+			}
+			again = true
+		} else {
+			node = node.next
+		}
+		if !again && node == end {
+			break
+		}
+	}
+	return end
+}
+
+// Creates a node and optionally links it with a previous node in a
+// circular doubly-linked list.
+func insertNode(idx int, x, y float64, lastNode *tessellatorNode) *tessellatorNode {
+	node := &tessellatorNode{idx: idx, x: x, y: y}
+	if lastNode == nil {
+		node.prev, node.next = node, node
+	} else {
+		node.next = lastNode.next
+		node.prev = lastNode
+		lastNode.next.prev = node
+		lastNode.next = node
+	}
+	return node
+}
+
+// Removes a node from the doubly linked list.
+func removeNode(node *tessellatorNode) {
+	node.next.prev = node.prev
+	node.prev.next = node.next
+}
+
+// Determines if two point vertices are equal.
+func isVertexEquals(a, b *tessellatorNode) bool {
+	return a.x == b.x && a.y == b.y
+}
+
+// Computes the signed area of a triangle.
+func area(a, b, c *tessellatorNode) float64 {
+	return (b.y-a.y)*(c.x-b.x) - (b.x-a.x)*(c.y-b.y)
+}
+
+// Computes whether point (x, y) lies in the triangle (ax, ay),
+// (bx, by), (cx, cy), edges included.
+func pointInEar(x, y, ax, ay, bx, by, cx, cy float64) bool {
+	return (cx-x)*(ay-y)-(ax-x)*(cy-y) >= 0 &&
+		(ax-x)*(by-y)-(bx-x)*(ay-y) >= 0 &&
+		(bx-x)*(cy-y)-(cx-x)*(by-y) >= 0
+}
+
+// Computes the signed area of a ring, given as a closed polygon.
+func signedArea(lats, lons []float64) float64 {
+	var sum float64
+	for i, j := 0, len(lats)-1; i < len(lats); j, i = i, i+1 {
+		sum += (lons[j] - lons[i]) * (lats[i] + lats[j])
+	}
+	return sum
+}
+
+// Creates a triangle of three nodes, in counter-clockwise order.
+func newTriangle(a, b, c *tessellatorNode) *Triangle {
+	t := &Triangle{
+		Lats: [3]float64{a.y, b.y, c.y},
+		Lons: [3]float64{a.x, b.x, c.x},
+	}
+	if (b.x-a.x)*(c.y-a.y)-(b.y-a.y)*(c.x-a.x) < 0 {
+		t.Lats[1], t.Lats[2] = t.Lats[2], t.Lats[1]
+		t.Lons[1], t.Lons[2] = t.Lons[2], t.Lons[1]
+	}
+	return t
+}
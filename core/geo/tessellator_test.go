@@ -0,0 +1,66 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+func triangleArea(t *Triangle) float64 {
+	return math.Abs((t.Lons[1]-t.Lons[0])*(t.Lats[2]-t.Lats[0])-
+		(t.Lons[2]-t.Lons[0])*(t.Lats[1]-t.Lats[0])) / 2
+}
+
+func TestTessellate(t *testing.T) {
+	square := func(min, max float64, holes ...*Polygon) *Polygon {
+		return NewPolygon([]float64{min, min, max, max, min}, []float64{min, max, max, min, min}, holes...)
+	}
+	for _, test := range []struct {
+		polygon *Polygon
+		area    float64
+	}{
+		{square(0, 10), 100},
+		// clockwise
+		{NewPolygon([]float64{0, 10, 10, 0, 0}, []float64{0, 0, 10, 10, 0}), 100},
+		{square(0, 10, square(2, 4)), 96},
+		{square(0, 10, square(2, 4), square(6, 9)), 87},
+		// concave
+		{NewPolygon([]float64{0, 0, 10, 10, 5, 10, 10, 0}, []float64{0, 10, 10, 8, 5, 2, 0, 0}), 85},
+	} {
+		triangles := Tessellate(test.polygon)
+		var area float64
+		for _, tri := range triangles {
+			area += triangleArea(tri)
+			if orient(tri.Lons[0], tri.Lats[0], tri.Lons[1], tri.Lats[1], tri.Lons[2], tri.Lats[2]) < 0 {
+				t.Errorf("%v is not counter-clockwise", tri)
+			}
+		}
+		if math.Abs(area-test.area) > 1e-9 {
+			t.Errorf("Expected tessellation of %v to cover %v, but was %v", test.polygon, test.area, area)
+		}
+	}
+}
+
+func TestPolygonRelateTriangle(t *testing.T) {
+	hole := NewPolygon([]float64{4, 4, 6, 6, 4}, []float64{4, 6, 6, 4, 4})
+	polygon := NewPolygon([]float64{0, 0, 10, 10, 0}, []float64{0, 10, 10, 0, 0}, hole)
+	for _, test := range []struct {
+		tri      Triangle
+		relation Relation
+	}{
+		{Triangle{[3]float64{1, 1, 3}, [3]float64{1, 3, 2}}, CELL_INSIDE_QUERY},
+		{Triangle{[3]float64{4.5, 4.5, 5.5}, [3]float64{4.5, 5.5, 5}}, CELL_OUTSIDE_QUERY},
+		{Triangle{[3]float64{20, 20, 30}, [3]float64{20, 30, 25}}, CELL_OUTSIDE_QUERY},
+		{Triangle{[3]float64{5, 5, 15}, [3]float64{1, 20, 1}}, CELL_CROSSES_QUERY},
+		// contains the hole
+		{Triangle{[3]float64{1, 1, 9}, [3]float64{1, 9, 5}}, CELL_CROSSES_QUERY},
+		// contains the polygon
+		{Triangle{[3]float64{-50, -50, 80}, [3]float64{-50, 80, -50}}, CELL_CROSSES_QUERY},
+		// points
+		{Triangle{[3]float64{1, 1, 1}, [3]float64{1, 1, 1}}, CELL_INSIDE_QUERY},
+		{Triangle{[3]float64{5, 5, 5}, [3]float64{5, 5, 5}}, CELL_OUTSIDE_QUERY},
+	} {
+		if relation := polygon.RelateTriangle(&test.tri); relation != test.relation {
+			t.Errorf("Expected %v to relate as %v, but was %v", &test.tri, test.relation, relation)
+		}
+	}
+}
@@ -1,7 +1,7 @@
 package index
 
 import (
-	// "bytes"
+	"bytes"
 	"fmt"
 	"github.com/balzaczyy/golucene/core/util"
 	"math"
@@ -10,6 +10,12 @@ import (
 
 // index/BufferedUpdates.java
 
+/*
+Go map (amd64) consumes about 40 bytes for an extra entry, plus the
+two string headers of the key.
+*/
+const BYTES_PER_DEL_TERM = 40 + 4*util.NUM_BYTES_OBJECT_REF + util.NUM_BYTES_INT
+
 /* Go slice consumes two int for an extra doc ID, assuming 50% pre-allocation. */
 const BYTES_PER_DEL_DOCID = 2 * util.NUM_BYTES_INT
 
@@ -33,7 +39,7 @@ DocumentsWriterDeleteQueue
 type BufferedUpdates struct {
 	numTermDeletes int32 // atomic
 
-	terms   map[*Term]int
+	terms   map[termKey]int
 	queries map[interface{}]int
 	docIDs  []int
 
@@ -48,7 +54,7 @@ type BufferedUpdates struct {
 
 func newBufferedUpdates() *BufferedUpdates {
 	return &BufferedUpdates{
-		terms:          make(map[*Term]int),
+		terms:          make(map[termKey]int),
 		queries:        make(map[interface{}]int),
 		numericUpdates: make(map[string]map[*Term]*DocValuesUpdate),
 		binaryUpdates:  make(map[string]map[*Term]*DocValuesUpdate),
@@ -56,29 +62,52 @@ func newBufferedUpdates() *BufferedUpdates {
 }
 
 func (bd *BufferedUpdates) String() string {
-	panic("not implemented yet")
-	// if VERBOSE {
-	// 	return fmt.Sprintf(
-	// 		"BufferedUpdates[gen=%v, numTerms=%v, terms=%v, queries=%v, docIDs=%v, bytesUsed=%v]",
-	// 		bd.gen, atomic.LoadInt32(&bd.numTermDeletes), bd.terms, bd.queries, bd.docIDs, bd.bytesUsed)
-	// } else {
-	// 	var buf bytes.Buffer
-	// 	fmt.Fprintf(&buf, "BufferedUpdates[gen=%v", bd.gen)
-	// 	if n := atomic.LoadInt32(&bd.numTermDeletes); n != 0 {
-	// 		fmt.Fprintf(&buf, " %v deleted terms (unique count=%v)", n, len(bd.terms))
-	// 	}
-	// 	if len(bd.queries) > 0 {
-	// 		fmt.Fprintf(&buf, " %v deleted queries", len(bd.queries))
-	// 	}
-	// 	if len(bd.docIDs) > 0 {
-	// 		fmt.Fprintf(&buf, " %v deleted docIDs", len(bd.docIDs))
-	// 	}
-	// 	if n := atomic.LoadInt64(&bd.bytesUsed); n != 0 {
-	// 		fmt.Fprintf(&buf, " bytesUsed=%v", n)
-	// 	}
-	// 	buf.WriteRune(']')
-	// 	return buf.String()
-	// }
+	if VERBOSE {
+		return fmt.Sprintf(
+			"BufferedUpdates[gen=%v, numTerms=%v, terms=%v, queries=%v, docIDs=%v, bytesUsed=%v]",
+			bd.gen, atomic.LoadInt32(&bd.numTermDeletes), bd.terms, bd.queries, bd.docIDs, bd.bytesUsed)
+	} else {
+		var buf bytes.Buffer
+		fmt.Fprintf(&buf, "BufferedUpdates[gen=%v", bd.gen)
+		if n := atomic.LoadInt32(&bd.numTermDeletes); n != 0 {
+			fmt.Fprintf(&buf, " %v deleted terms (unique count=%v)", n, len(bd.terms))
+		}
+		if len(bd.queries) > 0 {
+			fmt.Fprintf(&buf, " %v deleted queries", len(bd.queries))
+		}
+		if len(bd.docIDs) > 0 {
+			fmt.Fprintf(&buf, " %v deleted docIDs", len(bd.docIDs))
+		}
+		if n := atomic.LoadInt64(&bd.bytesUsed); n != 0 {
+			fmt.Fprintf(&buf, " bytesUsed=%v", n)
+		}
+		buf.WriteRune(']')
+		return buf.String()
+	}
+}
+
+func (bd *BufferedUpdates) addTerm(term *Term, docIDUpto int) {
+	key := termKey{term.Field, string(term.Bytes)}
+	current, ok := bd.terms[key]
+	if ok && docIDUpto < current {
+		// Only record the new number if it's greater than the current
+		// one. This is important because if multiple goroutines are
+		// replacing the same doc at nearly the same time, it's possible
+		// that one goroutine that got a higher docID is scheduled before
+		// the other goroutines. If we blindly replace then we can
+		// incorrectly get both docs indexed.
+		return
+	}
+
+	bd.terms[key] = docIDUpto
+	// note that if current != nil then it means there's already a
+	// buffered delete on that term, therefore we seem to over-count.
+	// This over-counting is done to respect
+	// IndexWriterConfig.MaxBufferedDeleteTerms().
+	atomic.AddInt32(&bd.numTermDeletes, 1)
+	if !ok {
+		atomic.AddInt64(&bd.bytesUsed, int64(BYTES_PER_DEL_TERM+len(term.Bytes)+len(term.Field)))
+	}
 }
 
 func (bd *BufferedUpdates) addDocID(docID int) {
@@ -87,7 +116,7 @@ func (bd *BufferedUpdates) addDocID(docID int) {
 }
 
 func (bd *BufferedUpdates) clear() {
-	bd.terms = make(map[*Term]int)
+	bd.terms = make(map[termKey]int)
 	bd.queries = make(map[interface{}]int)
 	bd.docIDs = nil
 	atomic.StoreInt32(&bd.numTermDeletes, 0)
@@ -99,6 +128,19 @@ func (bd *BufferedUpdates) any() bool {
 		len(bd.numericUpdates) > 0 || len(bd.binaryUpdates) > 0
 }
 
+/*
+Term is not comparable, so buffered delete terms are keyed by their
+field and bytes instead.
+*/
+type termKey struct {
+	field string
+	bytes string
+}
+
+func (k termKey) term() *Term {
+	return NewTermFromBytes(k.field, []byte(k.bytes))
+}
+
 // index/FrozenBufferedUpdates.java
 
 /*
@@ -135,7 +177,7 @@ func freezeBufferedUpdates(deletes *BufferedUpdates, isPrivate bool) *FrozenBuff
 		"segment private package should only have del queries")
 	var termsArray []*Term
 	for k, _ := range deletes.terms {
-		termsArray = append(termsArray, k)
+		termsArray = append(termsArray, k.term())
 	}
 	util.TimSort(TermSorter(termsArray))
 	builder := newPrefixCodedTermsBuilder()
@@ -211,11 +253,25 @@ func assert2(ok bool, msg string, args ...interface{}) {
 }
 
 func (bd *FrozenBufferedUpdates) queries() []*QueryAndLimit {
-	panic("not implemented yet")
+	ans := make([]*QueryAndLimit, len(bd._queries))
+	for i, query := range bd._queries {
+		ans[i] = &QueryAndLimit{query, bd.queryLimits[i]}
+	}
+	return ans
 }
 
 func (bd *FrozenBufferedUpdates) String() string {
-	panic("not implemented yet")
+	var buf bytes.Buffer
+	if bd.numTermDeletes != 0 {
+		fmt.Fprintf(&buf, " %v deleted terms (unique count=%v)", bd.numTermDeletes, bd.termCount)
+	}
+	if len(bd._queries) != 0 {
+		fmt.Fprintf(&buf, " %v deleted queries", len(bd._queries))
+	}
+	if bd.bytesUsed != 0 {
+		fmt.Fprintf(&buf, " bytesUsed=%v", bd.bytesUsed)
+	}
+	return buf.String()
 }
 
 func (d *FrozenBufferedUpdates) any() bool {
@@ -97,5 +97,18 @@ func (r *ByteSliceReader) nextSlice() {
 }
 
 func (r *ByteSliceReader) ReadBytes(buf []byte) error {
-	panic("not implemented yet")
+	for len(buf) > 0 {
+		if numLeft := r.limit - r.upto; numLeft < len(buf) {
+			// read entire slice
+			copy(buf, r.buffer[r.upto:r.limit])
+			buf = buf[numLeft:]
+			r.nextSlice()
+		} else {
+			// this slice is the last one
+			copy(buf, r.buffer[r.upto:r.upto+len(buf)])
+			r.upto += len(buf)
+			break
+		}
+	}
+	return nil
 }
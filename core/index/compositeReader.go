@@ -5,6 +5,7 @@ import (
 	"container/list"
 	"fmt"
 	. "github.com/balzaczyy/golucene/core/codec/spi"
+	. "github.com/balzaczyy/golucene/core/index/model"
 	"reflect"
 )
 
@@ -126,15 +127,15 @@ type CompositeReaderContextBuilder struct {
 	leafDocBase int
 }
 
-func newCompositeReaderContextBuilder(r CompositeReader) CompositeReaderContextBuilder {
-	return CompositeReaderContextBuilder{reader: r, leaves: list.New()}
+func newCompositeReaderContextBuilder(r CompositeReader) *CompositeReaderContextBuilder {
+	return &CompositeReaderContextBuilder{reader: r, leaves: list.New()}
 }
 
-func (b CompositeReaderContextBuilder) build() *CompositeReaderContext {
+func (b *CompositeReaderContextBuilder) build() *CompositeReaderContext {
 	return b.build4(nil, b.reader, 0, 0).(*CompositeReaderContext)
 }
 
-func (b CompositeReaderContextBuilder) build4(parent *CompositeReaderContext,
+func (b *CompositeReaderContextBuilder) build4(parent *CompositeReaderContext,
 	reader IndexReader, ord, docBase int) IndexReaderContext {
 	// log.Printf("Building context from %v(parent: %v, %v-%v)", reader, parent, ord, docBase)
 	if ar, ok := reader.(AtomicReader); ok {
@@ -158,7 +159,7 @@ func (b CompositeReaderContextBuilder) build4(parent *CompositeReaderContext,
 	newDocBase := 0
 	for i, r := range sequentialSubReaders {
 		children[i] = b.build4(newParent, r, i, newDocBase)
-		newDocBase = r.MaxDoc()
+		newDocBase += r.MaxDoc()
 	}
 	// assert newDocBase == cr.maxDoc()
 	return newParent
@@ -219,11 +220,13 @@ func newBaseCompositeReader(spi BaseCompositeReaderSPI, readers []IndexReader) *
 	return ans
 }
 
-func (r *BaseCompositeReader) TermVectors(docID int) error {
-	panic("not implemented yet")
-	// r.ensureOpen()
-	// i := readerIndex(docID)
-	// return r.subReaders[i].TermVectors(docID - starts[i])
+func (r *BaseCompositeReader) TermVectors(docID int) (Fields, error) {
+	r.ensureOpen()
+	i := r.readerIndex(docID) // find subreader num
+	sub := r.subReaders[i].(interface {
+		TermVectors(int) (Fields, error)
+	})
+	return sub.TermVectors(docID - r.starts[i])
 }
 
 func (r *BaseCompositeReader) NumDocs() int {
@@ -243,7 +246,16 @@ func (r *BaseCompositeReader) VisitDocument(docID int, visitor StoredFieldVisito
 }
 
 func (r *BaseCompositeReader) DocFreq(term *Term) (int, error) {
-	panic("not implemented yet")
+	r.ensureOpen()
+	total := 0 // sum freqs in subreaders
+	for _, sub := range r.subReaders {
+		n, err := sub.DocFreq(term)
+		if err != nil {
+			return 0, err
+		}
+		total += n
+	}
+	return total, nil
 }
 
 func (r *BaseCompositeReader) TotalTermFreq(term *Term) int64 {
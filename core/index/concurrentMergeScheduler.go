@@ -2,7 +2,9 @@ package index
 
 import (
 	"fmt"
+	"github.com/balzaczyy/golucene/core/store"
 	"log"
+	"math"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -38,6 +40,10 @@ largest merges are paused until one of the smaller merges completes.
 If more than MaxMergeCount() merges are requested then this class
 will forcefully throttle the incoming goroutines by pausing until one
 or more merges complete.
+
+The writes of all merges can be limited to a maximum rate with
+SetMaxMergeMBPerSec(), so that large merges don't saturate the disk
+bandwidth and starve the searches.
 */
 type ConcurrentMergeScheduler struct {
 	sync.Locker
@@ -61,6 +67,9 @@ type ConcurrentMergeScheduler struct {
 
 	suppressErrors bool
 
+	// Shared by the outputs of all merges; unlimited by default.
+	mergeRateLimiter *store.SimpleRateLimiter
+
 	chRequest            chan *MergeJob
 	chSync               chan *sync.WaitGroup
 	concurrentMergeCount int32 // atomic
@@ -72,6 +81,8 @@ func NewConcurrentMergeScheduler() *ConcurrentMergeScheduler {
 		Locker:    &sync.Mutex{},
 		chRequest: make(chan *MergeJob),
 		chSync:    make(chan *sync.WaitGroup),
+
+		mergeRateLimiter: store.NewSimpleRateLimiter(math.Inf(1)),
 	}
 	cms.SetMaxMergesAndRoutines(DEFAULT_MAX_MERGE_COUNT, DEFAULT_MAX_ROUTINE_COUNT)
 	return cms
@@ -149,6 +160,31 @@ func (cms *ConcurrentMergeScheduler) SetMaxMergesAndRoutines(maxMergeCount, maxR
 	}
 }
 
+/*
+Sets the maximum (approx) MB/sec written by all running merges
+together. Pass a non-positive value to have no limit, the default. It
+applies to the outputs already opened by running merges too.
+*/
+func (cms *ConcurrentMergeScheduler) SetMaxMergeMBPerSec(mbPerSec float64) {
+	if mbPerSec <= 0 {
+		mbPerSec = math.Inf(1)
+	}
+	cms.mergeRateLimiter.SetMbPerSec(mbPerSec)
+}
+
+// Returns the maximum MB/sec written by merges, or 0 if unlimited.
+func (cms *ConcurrentMergeScheduler) MaxMergeMBPerSec() float64 {
+	if mbPerSec := cms.mergeRateLimiter.MbPerSec(); !math.IsInf(mbPerSec, 1) {
+		return mbPerSec
+	}
+	return 0
+}
+
+// Returns the rate limiter shared by the outputs of all merges.
+func (cms *ConcurrentMergeScheduler) MergeRateLimiter() store.RateLimiter {
+	return cms.mergeRateLimiter
+}
+
 /*
 Returns true if verbosing is enabled. This method is usually used in
 conjunction with message(), like that:
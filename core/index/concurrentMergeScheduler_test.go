@@ -0,0 +1,44 @@
+package index
+
+import (
+	"github.com/balzaczyy/golucene/core/store"
+	"testing"
+	"time"
+)
+
+func TestMergeRateLimiter(t *testing.T) {
+	cms := NewConcurrentMergeScheduler()
+	cms.SetMaxMergeMBPerSec(10)
+	if cms.MaxMergeMBPerSec() != 10 {
+		t.Errorf("Expected 10 MB/sec, but was %v", cms.MaxMergeMBPerSec())
+	}
+	d := store.NewRAMDirectory()
+	if addMergeRateLimiter(d, NewSerialMergeScheduler()) != d {
+		t.Error("Expected no rate limit without a RateLimitedMergeScheduler")
+	}
+	w := &IndexWriter{mergeDirectory: addMergeRateLimiter(d, cms)}
+
+	out, err := w.createMergeOutput(&OneMerge{maxNumSegments: -1}, "_0.merged")
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	chunk := make([]byte, 64<<10)
+	for i := 0; i < 16; i++ {
+		if err = out.WriteBytes(chunk); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = out.Close(); err != nil {
+		t.Fatal(err)
+	}
+	// 1 MB at 10 MB/sec, less the first pause check
+	if elapsed := time.Since(start); elapsed < 80*time.Millisecond {
+		t.Errorf("Merging 1 MB at 10 MB/sec took only %v", elapsed)
+	}
+
+	cms.SetMaxMergeMBPerSec(0)
+	if cms.MaxMergeMBPerSec() != 0 {
+		t.Errorf("Expected no limit, but was %v MB/sec", cms.MaxMergeMBPerSec())
+	}
+}
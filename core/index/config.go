@@ -116,6 +116,17 @@ func NewIndexWriterConfig(matchVersion util.Version, analyzer analysis.Analyzer)
 	}
 }
 
+/*
+Specifies OpenMode of the index.
+
+Only takes effect when IndexWriter is first created.
+*/
+func (conf *IndexWriterConfig) SetOpenMode(openMode OpenMode) *IndexWriterConfig {
+	assert2(openMode > 0, "openMode must not be 0")
+	conf.openMode = openMode
+	return conf
+}
+
 /*
 Expert: allows an optional IndexDeletionPolicy implementation to be
 specified. You can use this to control when prior commits are deleted
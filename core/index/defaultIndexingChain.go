@@ -355,13 +355,13 @@ func (c *DefaultIndexingChain) processField(field IndexableField,
 			fp.fieldGen = fieldGen
 		}
 	} else {
-		panic("not implemented yet")
+		verifyFieldType(fieldName, fieldType)
 	}
 
 	// Add stored fields:
 	if fieldType.Stored() {
 		if fp == nil {
-			panic("not implemented yet")
+			fp = c.getOrAddField(fieldName, fieldType, false)
 		}
 		if fieldType.Stored() {
 			if err := func() error {
@@ -393,6 +393,17 @@ func (c *DefaultIndexingChain) processField(field IndexableField,
 	return fieldCount, nil
 }
 
+func verifyFieldType(name string, ft IndexableFieldType) {
+	assert2(!ft.StoreTermVectors(),
+		"cannot store term vectors for a field that is not indexed (field='%v')", name)
+	assert2(!ft.StoreTermVectorPositions(),
+		"cannot store term vector positions for a field that is not indexed (field='%v')", name)
+	assert2(!ft.StoreTermVectorOffsets(),
+		"cannot store term vector offsets for a field that is not indexed (field='%v')", name)
+	assert2(!ft.StoreTermVectorPayloads(),
+		"cannot store term vector payloads for a field that is not indexed (field='%v')", name)
+}
+
 /*
 Returns a previously created PerField, or nil if this field name
 wasn't seen yet.
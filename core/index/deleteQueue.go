@@ -48,6 +48,7 @@ neither to the global deletes.
 */
 type DocumentsWriterDeleteQueue struct {
 	tail                  *Node // volatile
+	tailLock              sync.Locker
 	globalSlice           *DeleteSlice
 	globalBufferedUpdates *BufferedUpdates
 	globalBufferLock      sync.Locker
@@ -68,6 +69,7 @@ func newDocumentsWriterDeleteQueueWith(globalBufferedUpdates *BufferedUpdates, g
 	return &DocumentsWriterDeleteQueue{
 		globalBufferedUpdates: globalBufferedUpdates,
 		globalBufferLock:      &sync.Mutex{},
+		tailLock:              &sync.Mutex{},
 		generation:            generation,
 		// we use a sentinel instance as our initial tail. No slice will
 		// ever try to apply this tail since the head is always omitted.
@@ -78,7 +80,41 @@ func newDocumentsWriterDeleteQueueWith(globalBufferedUpdates *BufferedUpdates, g
 
 /* Invariant for document update */
 func (q *DocumentsWriterDeleteQueue) add(term *Term, slice *DeleteSlice) {
-	panic("not implemented yet")
+	termNode := newNode(term)
+	q.addNode(termNode)
+	// this is an update request where the term is the updated documents
+	// delTerm. In that case we need to guarantee that this insert is
+	// atomic with regards to the given delete slice. This means if two
+	// threads try to update the same document with in turn the same
+	// delTerm one of them must win. By taking the node we have created
+	// for our del term as the new tail it is guaranteed that if another
+	// thread adds the same right after us we will apply this delete
+	// next time we update our slice and one of the two competing
+	// updates wins!
+	slice.tail = termNode
+	assert2(slice.head != slice.tail, "slice head and tail must differ after add")
+	q.tryApplyGlobalSlice() // TODO doing this each time is not necessary maybe
+	// we need to check if the global slice is up-to-date since we
+	// don't lock on each add
+}
+
+func (q *DocumentsWriterDeleteQueue) addNode(item *Node) {
+	q.tailLock.Lock()
+	defer q.tailLock.Unlock()
+	q.tail.next = item
+	q.tail = item
+}
+
+func (q *DocumentsWriterDeleteQueue) tryApplyGlobalSlice() {
+	q.globalBufferLock.Lock()
+	defer q.globalBufferLock.Unlock()
+	// The global buffer must be locked but we don't need to update
+	// them if there is an update going on right now. It is sufficient
+	// to apply the deletes that have been added after the current in
+	// progress global slice update is finished.
+	if q.updateSlice(q.globalSlice) {
+		q.globalSlice.apply(q.globalBufferedUpdates, MAX_INT)
+	}
 }
 
 func (dq *DocumentsWriterDeleteQueue) freezeGlobalBuffer(callerSlice *DeleteSlice) *FrozenBufferedUpdates {
@@ -105,6 +141,10 @@ func (dq *DocumentsWriterDeleteQueue) freezeGlobalBuffer(callerSlice *DeleteSlic
 	return packet
 }
 
+func (dq *DocumentsWriterDeleteQueue) numGlobalTermDeletes() int {
+	return int(atomic.LoadInt32(&dq.globalBufferedUpdates.numTermDeletes))
+}
+
 func (dq *DocumentsWriterDeleteQueue) anyChanges() bool {
 	dq.globalBufferLock.Lock()
 	defer dq.globalBufferLock.Unlock()
@@ -203,6 +243,11 @@ func newNode(item interface{}) *Node {
 	return &Node{item: item}
 }
 
-func (node *Node) apply(BufferedUpdates *BufferedUpdates, docIDUpto int) {
-	panic("sentinel item must never be applied")
+func (node *Node) apply(bufferedUpdates *BufferedUpdates, docIDUpto int) {
+	switch item := node.item.(type) {
+	case *Term:
+		bufferedUpdates.addTerm(item, docIDUpto)
+	default:
+		panic("sentinel item must never be applied")
+	}
 }
@@ -4,9 +4,10 @@ import (
 	"errors"
 	"fmt"
 	. "github.com/balzaczyy/golucene/core/codec/spi"
+	. "github.com/balzaczyy/golucene/core/index/model"
+	smodel "github.com/balzaczyy/golucene/core/search/model"
 	"github.com/balzaczyy/golucene/core/store"
 	"github.com/balzaczyy/golucene/core/util"
-	"log"
 	"math"
 	"sort"
 	"sync"
@@ -36,12 +37,15 @@ func (a SegInfoByDelGen) Less(i, j int) bool { return a[i].BufferedUpdatesGen <
 type Query interface{}
 
 type QueryAndLimit struct {
+	query Query
+	limit int
 }
 
 // index/CoalescedUpdates.java
 
 type CoalescedUpdates struct {
 	_queries         map[Query]int
+	iterables        []*PrefixCodedTerms
 	numericDVUpdates []*DocValuesUpdate
 	binaryDVUpdates  []*DocValuesUpdate
 }
@@ -53,19 +57,48 @@ func newCoalescedUpdates() *CoalescedUpdates {
 }
 
 func (cd *CoalescedUpdates) String() string {
-	panic("not implemented yet")
+	// note: we could add/collect more debugging information
+	return fmt.Sprintf("CoalescedUpdates(termSets=%v,queries=%v,numericDVUpdates=%v,binaryDVUpdates=%v)",
+		len(cd.iterables), len(cd._queries), len(cd.numericDVUpdates), len(cd.binaryDVUpdates))
 }
 
 func (cd *CoalescedUpdates) update(in *FrozenBufferedUpdates) {
-	panic("not implemented yet")
+	cd.iterables = append(cd.iterables, in.terms)
+
+	for _, query := range in._queries {
+		cd._queries[query] = MAX_INT
+	}
+
+	// doc values updates are not supported yet, so frozen packets never
+	// carry any.
+	assert(len(in.numericDVUpdates) == 0 && len(in.binaryDVUpdates) == 0)
 }
 
-func (cd *CoalescedUpdates) terms() []*Term {
-	panic("not implemented yet")
+/* Returns the merged, sorted and de-duplicated terms of all coalesced packets. */
+func (cd *CoalescedUpdates) terms() ([]*Term, error) {
+	seen := make(map[termKey]bool)
+	var terms []*Term
+	for _, iterable := range cd.iterables {
+		if err := iterable.each(func(term *Term) error {
+			if key := (termKey{term.Field, string(term.Bytes)}); !seen[key] {
+				seen[key] = true
+				terms = append(terms, term)
+			}
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+	util.TimSort(TermSorter(terms))
+	return terms, nil
 }
 
 func (cd *CoalescedUpdates) queries() []*QueryAndLimit {
-	panic("not implemented yet")
+	ans := make([]*QueryAndLimit, 0, len(cd._queries))
+	for query, limit := range cd._queries {
+		ans = append(ans, &QueryAndLimit{query, limit})
+	}
+	return ans
 }
 
 /*
@@ -95,7 +128,7 @@ type BufferedUpdatesStream struct {
 
 	infoStream util.InfoStream
 	bytesUsed  int64 // atomic
-	numTerms   int32 // atomic
+	_numTerms  int32 // atomic
 }
 
 func newBufferedUpdatesStream(infoStream util.InfoStream) *BufferedUpdatesStream {
@@ -109,7 +142,32 @@ func newBufferedUpdatesStream(infoStream util.InfoStream) *BufferedUpdatesStream
 
 /* Appends a new packet of buffered deletes to the stream, setting its generation: */
 func (s *BufferedUpdatesStream) push(packet *FrozenBufferedUpdates) int64 {
-	panic("not implemented yet")
+	s.Lock()
+	defer s.Unlock()
+
+	packet.gen = s.nextGen
+	s.nextGen++
+	assert(packet.any())
+	s.assertDeleteStats()
+	assert(packet.gen < s.nextGen)
+	assert2(len(s.updates) == 0 || s.updates[len(s.updates)-1].gen < packet.gen,
+		"Delete packets must be in order")
+	s.updates = append(s.updates, packet)
+	atomic.AddInt32(&s._numTerms, int32(packet.numTermDeletes))
+	atomic.AddInt64(&s.bytesUsed, int64(packet.bytesUsed))
+	if s.infoStream.IsEnabled("BD") {
+		s.infoStream.Message("BD", "push deletes %v delGen=%v packetCount=%v totBytesUsed=%v",
+			packet, packet.gen, len(s.updates), atomic.LoadInt64(&s.bytesUsed))
+	}
+	s.assertDeleteStats()
+	return packet.gen
+}
+
+func (s *BufferedUpdatesStream) getNextGen() int64 {
+	s.Lock()
+	defer s.Unlock()
+	s.nextGen++
+	return s.nextGen - 1
 }
 
 func (ds *BufferedUpdatesStream) clear() {
@@ -118,7 +176,7 @@ func (ds *BufferedUpdatesStream) clear() {
 
 	ds.updates = nil
 	ds.nextGen = 1
-	atomic.StoreInt32(&ds.numTerms, 0)
+	atomic.StoreInt32(&ds._numTerms, 0)
 	atomic.StoreInt64(&ds.bytesUsed, 0)
 }
 
@@ -126,6 +184,10 @@ func (ds *BufferedUpdatesStream) any() bool {
 	return atomic.LoadInt64(&ds.bytesUsed) != 0
 }
 
+func (ds *BufferedUpdatesStream) numTerms() int {
+	return int(atomic.LoadInt32(&ds._numTerms))
+}
+
 func (ds *BufferedUpdatesStream) RamBytesUsed() int64 {
 	return atomic.LoadInt64(&ds.bytesUsed)
 }
@@ -173,7 +235,7 @@ func (ds *BufferedUpdatesStream) applyDeletesAndUpdates(readerPool *ReaderPool,
 	var allDeleted []*SegmentCommitInfo
 
 	for infosIDX >= 0 {
-		log.Printf("BD: cycle delIDX=%v infoIDX=%v", delIDX, infosIDX)
+		// log.Printf("BD: cycle delIDX=%v infoIDX=%v", delIDX, infosIDX)
 
 		var packet *FrozenBufferedUpdates
 		if delIDX >= 0 {
@@ -183,7 +245,7 @@ func (ds *BufferedUpdatesStream) applyDeletesAndUpdates(readerPool *ReaderPool,
 		segGen := info.BufferedUpdatesGen
 
 		if packet != nil && segGen < packet.gen {
-			log.Println("  coalesce")
+			// log.Println("  coalesce")
 			if coalescedUpdates == nil {
 				coalescedUpdates = newCoalescedUpdates()
 			}
@@ -203,7 +265,7 @@ func (ds *BufferedUpdatesStream) applyDeletesAndUpdates(readerPool *ReaderPool,
 			assertn(packet.isSegmentPrivate,
 				"Packet and Segments deletegen can only match on a segment private del packet gen=%v",
 				segGen)
-			log.Println("  eq")
+			// log.Println("  eq")
 
 			// Lockorder: IW -> BD -> RP
 			assert(readerPool.infoIsLive(info))
@@ -219,9 +281,9 @@ func (ds *BufferedUpdatesStream) applyDeletesAndUpdates(readerPool *ReaderPool,
 				}()
 				dvUpdates := newDocValuesFieldUpdatesContainer()
 				if coalescedUpdates != nil {
-					fmt.Println("    del coalesced")
+					// fmt.Println("    del coalesced")
 					var delta int64
-					delta, err = ds._applyTermDeletes(coalescedUpdates.terms(), rld, reader)
+					delta, err = ds.applyCoalescedTermDeletes(coalescedUpdates, rld, reader)
 					if err == nil {
 						delCount += delta
 						delta, err = applyQueryDeletes(coalescedUpdates.queries(), rld, reader)
@@ -237,7 +299,7 @@ func (ds *BufferedUpdatesStream) applyDeletesAndUpdates(readerPool *ReaderPool,
 						return
 					}
 				}
-				fmt.Println("    del exact")
+				// fmt.Println("    del exact")
 				// Don't delete by Term here; DWPT already did that on flush:
 				var delta int64
 				delta, err = applyQueryDeletes(packet.queries(), rld, reader)
@@ -289,7 +351,7 @@ func (ds *BufferedUpdatesStream) applyDeletesAndUpdates(readerPool *ReaderPool,
 			info.SetBufferedUpdatesGen(gen)
 
 		} else {
-			log.Println("  gt")
+			// log.Println("  gt")
 
 			if coalescedUpdates != nil {
 				// Lock order: IW -> BD -> RP
@@ -305,7 +367,7 @@ func (ds *BufferedUpdatesStream) applyDeletesAndUpdates(readerPool *ReaderPool,
 						err = mergeError(err, readerPool.release(rld))
 					}()
 					var delta int64
-					delta, err = ds._applyTermDeletes(coalescedUpdates.terms(), rld, reader)
+					delta, err = ds.applyCoalescedTermDeletes(coalescedUpdates, rld, reader)
 					if err == nil {
 						delCount += delta
 						delta, err = applyQueryDeletes(coalescedUpdates.queries(), rld, reader)
@@ -414,7 +476,7 @@ func (ds *BufferedUpdatesStream) pruneUpdates(count int) {
 		}
 		for delIDX := 0; delIDX < count; delIDX++ {
 			packet := ds.updates[delIDX]
-			n := atomic.AddInt32(&ds.numTerms, -int32(packet.numTermDeletes))
+			n := atomic.AddInt32(&ds._numTerms, -int32(packet.numTermDeletes))
 			assert(n >= 0)
 			n2 := atomic.AddInt64(&ds.bytesUsed, -int64(packet.bytesUsed))
 			assert(n2 >= 0)
@@ -424,25 +486,118 @@ func (ds *BufferedUpdatesStream) pruneUpdates(count int) {
 	}
 }
 
+func (ds *BufferedUpdatesStream) applyCoalescedTermDeletes(updates *CoalescedUpdates,
+	rld *ReadersAndUpdates, reader *SegmentReader) (int64, error) {
+	terms, err := updates.terms()
+	if err != nil {
+		return 0, err
+	}
+	return ds._applyTermDeletes(terms, rld, reader)
+}
+
 /* Delete by term */
 func (ds *BufferedUpdatesStream) _applyTermDeletes(terms []*Term,
-	rld *ReadersAndUpdates, reader *SegmentReader) (int64, error) {
-	panic("not implemented yet")
+	rld *ReadersAndUpdates, reader *SegmentReader) (delCount int64, err error) {
+
+	fields := reader.Fields()
+	if fields == nil {
+		// This reader has no postings
+		return 0, nil
+	}
+
+	var termsEnum TermsEnum
+	var currentField string
+	var fieldSeen bool
+	var docs DocsEnum
+
+	assert(ds.checkDeleteTerm(nil))
+
+	var any bool
+	for _, term := range terms {
+		// Since we visit terms sorted, we gain performance by re-using
+		// the same TermsEnum and seeking only forwards
+		if !fieldSeen || term.Field != currentField {
+			assert(!fieldSeen || currentField < term.Field)
+			currentField, fieldSeen = term.Field, true
+			if terms := fields.Terms(currentField); terms != nil {
+				termsEnum = terms.Iterator(termsEnum)
+			} else {
+				termsEnum = nil
+			}
+		}
+
+		if termsEnum == nil {
+			continue
+		}
+		assert(ds.checkDeleteTerm(term))
+
+		var ok bool
+		if ok, err = termsEnum.SeekExact(term.Bytes); err != nil {
+			return 0, err
+		}
+		if ok {
+			// we don't need term frequencies for this
+			if docs, err = termsEnum.DocsByFlags(rld.liveDocs(), docs, DOCS_ENUM_FLAG_NONE); err != nil {
+				return 0, err
+			}
+			if docs != nil {
+				for {
+					docID, err := docs.NextDoc()
+					if err != nil {
+						return 0, err
+					}
+					if docID == smodel.NO_MORE_DOCS {
+						break
+					}
+					if !any {
+						rld.initWritableLiveDocs()
+						any = true
+					}
+					// NOTE: there is no limit check on the docID when
+					// deleting by Term (unlike by Query) because on flush we
+					// apply all Term deletes to each segment. So all Term
+					// deleting here is against prior segments:
+					if rld.delete(docID) {
+						delCount++
+					}
+				}
+			}
+		}
+	}
+	return delCount, nil
 }
 
 /* DocValues updates */
 func (ds *BufferedUpdatesStream) applyDocValuesUpdates(updates []*DocValuesUpdate,
 	rld *ReadersAndUpdates, reader *SegmentReader,
 	dvUpdatesCntainer *DocValuesFieldUpdatesContainer) error {
+	if len(updates) == 0 {
+		return nil
+	}
 	panic("not implemented yet")
 }
 
 /* Delete by query */
 func applyQueryDeletes(queries []*QueryAndLimit,
 	rld *ReadersAndUpdates, reader *SegmentReader) (int64, error) {
+	if len(queries) == 0 {
+		return 0, nil
+	}
 	panic("not implemented yet")
 }
 
+/* used only by assert */
+func (ds *BufferedUpdatesStream) checkDeleteTerm(term *Term) bool {
+	if term != nil && ds.lastDeleteTerm != nil {
+		assertn(!TermSorter{term, ds.lastDeleteTerm}.Less(0, 1),
+			"lastTerm=%v vs term=%v", ds.lastDeleteTerm, term)
+	}
+	// TODO: we re-use term now in our merged iterable, but we shouldn't
+	// clone, instead copy for this assert
+	ds.lastDeleteTerm = term
+	return true
+}
+
 func (ds *BufferedUpdatesStream) assertDeleteStats() {
 	var numTerms2 int
 	var bytesUsed2 int64
@@ -450,7 +605,7 @@ func (ds *BufferedUpdatesStream) assertDeleteStats() {
 		numTerms2 += packet.numTermDeletes
 		bytesUsed2 += int64(packet.bytesUsed)
 	}
-	n1 := int(atomic.LoadInt32(&ds.numTerms))
+	n1 := int(atomic.LoadInt32(&ds._numTerms))
 	assertn(numTerms2 == n1, "numTerms2=%v vs %v", numTerms2, n1)
 	n2 := int64(atomic.LoadInt64(&ds.bytesUsed))
 	assertn(bytesUsed2 == n2, "bytesUsed2=%v vs %v", bytesUsed2, n2)
@@ -14,11 +14,13 @@ const DEFAULT_TERMS_INDEX_DIVISOR = 1
 
 type DirectoryReader interface {
 	IndexReader
-	// doOpenIfChanged() error
+	doOpenIfChanged() (DirectoryReader, error)
 	// doOpenIfChanged(c IndexCommit) error
 	// doOpenIfChanged(w IndexWriter, c IndexCommit) error
 	Version() int64
 	IsCurrent() bool
+	// Returns the directory this index resides in.
+	Directory() store.Directory
 }
 
 type DirectoryReaderImpl struct {
@@ -42,6 +44,36 @@ func OpenDirectoryReader(directory store.Directory) (r DirectoryReader, err erro
 	return openStandardDirectoryReader(directory, nil, DEFAULT_TERMS_INDEX_DIVISOR)
 }
 
+/*
+If the index has changed since the provided reader was opened, open
+and return a new reader; else, return nil. The new reader, if not
+nil, will be the same type of reader as the previous one.
+
+This method is typically far less costly than opening a fully new
+DirectoryReader as it shares resources (for example sub-readers)
+with the provided DirectoryReader, when possible.
+
+The provided reader is not closed (you are responsible for doing so);
+if a new reader is returned you also must eventually close it. Be
+sure to never close a reader while other goroutines are still using
+it; see SearcherManager to simplify managing this.
+*/
+func OpenDirectoryReaderIfChanged(oldReader DirectoryReader) (DirectoryReader, error) {
+	newReader, err := oldReader.doOpenIfChanged()
+	if err != nil {
+		return nil, err
+	}
+	assert(newReader != oldReader)
+	return newReader, nil
+}
+
+func (r *DirectoryReaderImpl) Directory() store.Directory {
+	// Don't ensureOpen here -- in certain cases, when a cloned/reopened
+	// reader needs to commit, it may call this method on the closed
+	// original reader
+	return r.directory
+}
+
 /*
 Returns true if an index likely exists at the specified directory. Note that
 if a corrupt index exists, or if an index in the process of committing
@@ -88,15 +120,16 @@ func IsIndexFileExists(files []string) bool {
 
 type StandardDirectoryReader struct {
 	*DirectoryReaderImpl
-	writer       *IndexWriter // NRT
-	segmentInfos *SegmentInfos
+	writer                *IndexWriter // NRT
+	segmentInfos          *SegmentInfos
+	termInfosIndexDivisor int
 }
 
 // TODO support IndexWriter
 func newStandardDirectoryReader(directory store.Directory, readers []AtomicReader,
 	sis *SegmentInfos, termInfosIndexDivisor int, applyAllDeletes bool) *StandardDirectoryReader {
 	// log.Printf("Initializing StandardDirectoryReader with %v sub readers...", len(readers))
-	ans := &StandardDirectoryReader{segmentInfos: sis}
+	ans := &StandardDirectoryReader{segmentInfos: sis, termInfosIndexDivisor: termInfosIndexDivisor}
 	ans.DirectoryReaderImpl = newDirectoryReader(ans, directory, readers)
 	return ans
 }
@@ -133,6 +166,83 @@ func openStandardDirectoryReader(directory store.Directory,
 	return obj.(*StandardDirectoryReader), err
 }
 
+/*
+This constructor is only used for doOpenIfChanged(): segment readers
+of oldReaders whose segment is unchanged in infos are shared (their
+refCount is incremented), the others are opened anew.
+*/
+func openStandardDirectoryReaderFrom(directory store.Directory, infos *SegmentInfos,
+	oldReaders []IndexReader, termInfosIndexDivisor int) (r *StandardDirectoryReader, err error) {
+
+	// we put the old SegmentReaders in a map, that allows us to lookup
+	// a reader using its segment name
+	segmentReaders := make(map[string]*SegmentReader)
+	for _, oldReader := range oldReaders {
+		sr := oldReader.(*SegmentReader)
+		segmentReaders[sr.si.Info.Name] = sr
+	}
+
+	newReaders := make([]AtomicReader, len(infos.Segments))
+	defer func() {
+		if err != nil {
+			// try to release the readers we opened or shared so far
+			for _, newReader := range newReaders {
+				if newReader != nil {
+					newReader.decRef()
+				}
+			}
+		}
+	}()
+
+	// remember which readers are shared between the old and the
+	// re-opened DirectoryReader - we have to incRef those readers
+	for i := len(infos.Segments) - 1; i >= 0; i-- {
+		commitInfo := infos.Segments[i]
+		// find SegmentReader for this segment
+		oldReader, ok := segmentReaders[commitInfo.Info.Name]
+		if ok && oldReader.si.Info.IsCompoundFile() == commitInfo.Info.IsCompoundFile() &&
+			oldReader.si.DelGen() == commitInfo.DelGen() &&
+			oldReader.si.FieldInfosGen() == commitInfo.FieldInfosGen() {
+
+			// No change; this reader will be shared between the old and
+			// the new one, so we must incRef it:
+			oldReader.incRef()
+			newReaders[i] = oldReader
+			continue
+		}
+		// this is a new reader; in case we hit an error we can close it
+		// safely
+		var sr *SegmentReader
+		if sr, err = NewSegmentReader(commitInfo, termInfosIndexDivisor, store.IO_CONTEXT_READ); err != nil {
+			return nil, err
+		}
+		newReaders[i] = sr
+	}
+	return newStandardDirectoryReader(directory, newReaders, infos, termInfosIndexDivisor, false), nil
+}
+
+func (r *StandardDirectoryReader) doOpenIfChanged() (DirectoryReader, error) {
+	r.ensureOpen()
+	// If we were obtained by writer.getReader(), re-ask the writer to
+	// get a new reader.
+	assert2(r.writer == nil, "re-opening NRT readers is not supported yet")
+	if r.IsCurrent() {
+		return nil, nil
+	}
+	obj, err := NewFindSegmentsFile(r.directory, func(segmentFileName string) (interface{}, error) {
+		infos := &SegmentInfos{}
+		if err := infos.Read(r.directory, segmentFileName); err != nil {
+			return nil, err
+		}
+		return openStandardDirectoryReaderFrom(r.directory, infos,
+			r.getSequentialSubReaders(), r.termInfosIndexDivisor)
+	}).run(nil)
+	if err != nil {
+		return nil, err
+	}
+	return obj.(*StandardDirectoryReader), nil
+}
+
 func (r *StandardDirectoryReader) String() string {
 	var buf bytes.Buffer
 	buf.WriteString("StandardDirectoryReader(")
@@ -1,20 +1,34 @@
 package index
 
+import (
+	"fmt"
+)
+
 /* Holds updates of a single DocValues field, for a set of documents. */
 type DocValuesFieldUpdates struct {
 }
 
+/*
+Keeps track of updates to DocValues fields, per field name and
+DocValues type.
+*/
 type DocValuesFieldUpdatesContainer struct {
+	numericDVUpdates map[string]*DocValuesFieldUpdates
+	binaryDVUpdates  map[string]*DocValuesFieldUpdates
 }
 
 func newDocValuesFieldUpdatesContainer() *DocValuesFieldUpdatesContainer {
-	panic("not implemented yte")
+	return &DocValuesFieldUpdatesContainer{
+		numericDVUpdates: make(map[string]*DocValuesFieldUpdates),
+		binaryDVUpdates:  make(map[string]*DocValuesFieldUpdates),
+	}
 }
 
 func (c *DocValuesFieldUpdatesContainer) any() bool {
-	panic("not implemented yet")
+	return len(c.numericDVUpdates) > 0 || len(c.binaryDVUpdates) > 0
 }
 
 func (c *DocValuesFieldUpdatesContainer) String() string {
-	panic("not implemented yet")
+	return fmt.Sprintf("numericDVUpdates=%v binaryDVUpdates=%v",
+		c.numericDVUpdates, c.binaryDVUpdates)
 }
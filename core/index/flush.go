@@ -156,7 +156,20 @@ func newFlushByRamOrCountsPolicy() *FlushByRamOrCountsPolicy {
 }
 
 func (p *FlushByRamOrCountsPolicy) onDelete(control *DocumentsWriterFlushControl, state *ThreadState) {
-	panic("not implemented yet")
+	if p.flushOnDeleteTerms() {
+		// flush this state by num del terms
+		if control.numGlobalTermDeletes() >= p.indexWriterConfig.MaxBufferedDeleteTerms() {
+			control.setApplyAllDeletes()
+		}
+	}
+	if p.flushOnRAM() &&
+		control.deleteBytesUsed() > int64(1024*1024*p.indexWriterConfig.RAMBufferSizeMB()) {
+		control.setApplyAllDeletes()
+		if p.infoStream.IsEnabled("FP") {
+			p.infoStream.Message("FP", "force apply deletes bytesUsed=%v vs ramBufferMB=%v",
+				control.deleteBytesUsed(), p.indexWriterConfig.RAMBufferSizeMB())
+		}
+	}
 }
 
 func (p *FlushByRamOrCountsPolicy) onInsert(control *DocumentsWriterFlushControl, state *ThreadState) {
@@ -183,6 +196,14 @@ func (p *FlushByRamOrCountsPolicy) markLargestWriterPending(control *DocumentsWr
 	control.setFlushPending(p.findLargestNonPendingWriter(control, perThreadState))
 }
 
+/*
+Returns true if this FlushPolicy flushes on
+IndexWriterConfig.MaxBufferedDeleteTerms(), otherwise false.
+*/
+func (p *FlushByRamOrCountsPolicy) flushOnDeleteTerms() bool {
+	return p.indexWriterConfig.MaxBufferedDeleteTerms() != DISABLE_AUTO_FLUSH
+}
+
 /* Returns true if this FLushPolicy flushes on IndexWriterConfig.MaxBufferedDocs(), otherwise false */
 func (p *FlushByRamOrCountsPolicy) flushOnDocCount() bool {
 	return p.indexWriterConfig.MaxBufferedDocs() != DISABLE_AUTO_FLUSH
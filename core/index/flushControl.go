@@ -384,6 +384,11 @@ func (fc *DocumentsWriterFlushControl) deleteBytesUsed() int64 {
 	return fc.documentsWriter.deleteQueue.RamBytesUsed() + fc.bufferedUpdatesStream.RamBytesUsed()
 }
 
+/* Returns the number of delete terms in the global pool */
+func (fc *DocumentsWriterFlushControl) numGlobalTermDeletes() int {
+	return fc.documentsWriter.deleteQueue.numGlobalTermDeletes() + fc.bufferedUpdatesStream.numTerms()
+}
+
 // L444
 
 func (fc *DocumentsWriterFlushControl) obtainAndLock() *ThreadState {
@@ -530,6 +535,10 @@ func (fc *DocumentsWriterFlushControl) addFlushableState(perThread *ThreadState)
 	}
 }
 
+func (fc *DocumentsWriterFlushControl) setApplyAllDeletes() {
+	atomic.StoreInt32(&fc.flushDeletes, 1)
+}
+
 func (fc *DocumentsWriterFlushControl) getAndResetApplyAllDeletes() bool {
 	return atomic.SwapInt32(&fc.flushDeletes, 0) == 1
 }
@@ -19,12 +19,14 @@ All the fields are either readonly or volatile.
 type LiveIndexWriterConfig interface {
 	TermIndexInterval() int
 	MaxBufferedDocs() int
+	MaxBufferedDeleteTerms() int
 	RAMBufferSizeMB() float64
 	Similarity() Similarity
 	Codec() Codec
 	MergePolicy() MergePolicy
 	indexingChain() IndexingChain
 	RAMPerThreadHardLimitMB() int
+	ReaderTermsIndexDivisor() int
 	flushPolicy() FlushPolicy
 	InfoStream() util.InfoStream
 	indexerThreadPool() *DocumentsWriterPerThreadPool
@@ -192,6 +194,14 @@ func (conf *LiveIndexWriterConfigImpl) MaxBufferedDocs() int {
 	return conf.maxBufferedDocs
 }
 
+/*
+Returns the number of buffered deleted terms that will trigger a
+flush of all buffered deletes if enabled.
+*/
+func (conf *LiveIndexWriterConfigImpl) MaxBufferedDeleteTerms() int {
+	return conf.maxBufferedDeleteTerms
+}
+
 /*
 Expert: MergePolicy is invoked whenver there are changes to the
 segments in the index. Its role is to select which merges to do, if
@@ -241,6 +251,11 @@ func (conf *LiveIndexWriterConfigImpl) SetReaderTermsIndexDivisor(divisor int) *
 	return conf
 }
 
+/* Returns the termInfosIndexDivisor. */
+func (conf *LiveIndexWriterConfigImpl) ReaderTermsIndexDivisor() int {
+	return conf.readerTermsIndexDivisor
+}
+
 func (conf *LiveIndexWriterConfigImpl) Similarity() Similarity {
 	return conf.similarity
 }
@@ -0,0 +1,647 @@
+package index
+
+import (
+	"errors"
+	"fmt"
+	"github.com/balzaczyy/golucene/core/analysis"
+	. "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	. "github.com/balzaczyy/golucene/core/codec/spi"
+	. "github.com/balzaczyy/golucene/core/index/model"
+	. "github.com/balzaczyy/golucene/core/search/model"
+	"github.com/balzaczyy/golucene/core/util"
+	"sort"
+)
+
+// memory/MemoryIndex.java
+
+/*
+High-performance single-document main memory index.
+
+This type is a replacement/substitute for a large subset of
+RAMDirectory functionality. It is designed to enable maximum
+efficiency for on-the-fly matchmaking combining structured and fuzzy
+fulltext search in realtime streaming applications such as Nux XQuery
+based XML message queues, publish-subscribe systems for Blogs/newsfeeds,
+text chat, data acquisition and distribution systems, application
+level routers, firewalls, classifiers, etc. Rather than targeting
+fulltext search of infrequent queries over huge persistent data
+archives (historic search), this type targets fulltext search of huge
+numbers of queries over comparatively small transient realtime data
+(prospective search).
+
+Each instance can hold at most one Lucene "document", with a document
+containing zero or more "fields", each field having a name and a
+fulltext value. The fulltext value is tokenized (split and
+transformed) into zero or more index terms (aka words) on AddField(),
+according to the policy implemented by an Analyzer. For example,
+Lucene analyzers can split on whitespace, normalize to lower case for
+case insensitivity, ignore common terms with little discriminatory
+value such as "he", "in", "and" (stop words), reduce the terms to
+their natural linguistic root form such as "fishing" being reduced to
+"fish" (stemming), resolve synonyms/inflexions/thesauri (upon
+indexing and/or querying), etc.
+
+The reader returned by CreateReader() gives an IndexReader-compatible
+view of the document, so that any Query can be scored (or
+highlighted) against it with an IndexSearcher, without touching a
+Directory:
+
+	index := NewMemoryIndex()
+	index.AddField("content", "Readings about Salmons and other select Alaska fishing Manuals", analyzer)
+	index.AddField("author", "Tales of James", analyzer)
+	searcher := search.NewIndexSearcher(index.CreateReader())
+	docs, err := searcher.SearchTop(query, 1)
+	if err == nil && docs.TotalHits > 0 {
+		fmt.Println("it's a match")
+	}
+
+No stored fields, doc values or deletions are supported; positions,
+and optionally offsets, are kept for each term so that phrase and
+span-like queries work. Norms are computed on the fly with the
+configured Similarity, which should be the same as the one of the
+IndexSearcher used for scoring.
+
+A MemoryIndex is not safe for concurrent modification.
+*/
+type MemoryIndex struct {
+	// info for each field
+	fields map[string]*memoryFieldInfo
+	// fields sorted ascending by name; nil if invalid
+	sortedFields []string
+	fieldInfos   map[string]*FieldInfo
+	storeOffsets bool
+	similarity   Similarity
+}
+
+// Constructs an empty instance.
+func NewMemoryIndex() *MemoryIndex {
+	return NewMemoryIndexWith(false)
+}
+
+/*
+Constructs an empty instance that can optionally store the start and
+end character offset of each token term in the text. This can be
+useful for highlighting of hit locations with the Lucene highlighter
+package. Set storeOffsets to false, to save memory, if you only need
+positions.
+*/
+func NewMemoryIndexWith(storeOffsets bool) *MemoryIndex {
+	return &MemoryIndex{
+		fields:       make(map[string]*memoryFieldInfo),
+		fieldInfos:   make(map[string]*FieldInfo),
+		storeOffsets: storeOffsets,
+	}
+}
+
+/*
+Convenience method; tokenizes the given field text and adds the
+resulting terms to the index; equivalent to adding an indexed
+non-keyword Lucene Field that is tokenized, not stored, termVectorStored
+with positions (or termVectorStored with positions and offsets).
+*/
+func (mi *MemoryIndex) AddField(fieldName, text string, analyzer analysis.Analyzer) error {
+	assert2(analyzer != nil, "analyzer must not be nil")
+	stream, err := analyzer.TokenStreamForString(fieldName, text)
+	if err != nil {
+		return err
+	}
+	return mi.AddFieldFromStreamWith(fieldName, stream, 1,
+		analyzer.PositionIncrementGap(fieldName), analyzer.OffsetGap(fieldName))
+}
+
+/*
+Iterates over the given token stream and adds the resulting terms to
+the index; equivalent to adding a tokenized, indexed, termVectorStored,
+unstored Lucene Field. Finally closes the token stream. Note that
+untokenized keywords can be added with this method via
+KeywordTokenStream(), the Lucene KeywordTokenizer or similar
+utilities.
+*/
+func (mi *MemoryIndex) AddFieldFromStream(fieldName string, stream analysis.TokenStream) error {
+	return mi.AddFieldFromStreamWith(fieldName, stream, 1, 0, 1)
+}
+
+/*
+Iterates over the given token stream and adds the resulting terms to
+the index; equivalent to adding a tokenized, indexed, termVectorStored,
+unstored Lucene Field. Finally closes the token stream.
+
+boost is the boost factor for hits for this field; positionIncrementGap
+is the position increment gap, and offsetGap the offset gap, applied
+if fields with the same name are added more than once.
+*/
+func (mi *MemoryIndex) AddFieldFromStreamWith(fieldName string, stream analysis.TokenStream,
+	boost float32, positionIncrementGap, offsetGap int) (err error) {
+
+	assert2(stream != nil, "token stream must not be nil")
+	defer func() {
+		if err2 := stream.Close(); err == nil {
+			err = err2
+		}
+	}()
+
+	if fieldName == "" {
+		return errors.New("fieldName must not be empty")
+	}
+	if boost <= 0 {
+		return errors.New(fmt.Sprintf("boost factor must be greater than 0.0 (got %v)", boost))
+	}
+
+	// work on copies, so that a failing stream leaves the index as is
+	pos, offset := -1, 0
+	info, ok := mi.fields[fieldName]
+	if ok {
+		pos = info.lastPosition + positionIncrementGap
+		offset = info.lastOffset + offsetGap
+		boost *= info.boost
+	} else {
+		info = &memoryFieldInfo{terms: make(map[string]*memoryPostings)}
+	}
+	numTokens, numOverlapTokens := info.numTokens, info.numOverlapTokens
+	added := make(map[string][]int)
+
+	if _, ok := mi.fieldInfos[fieldName]; !ok {
+		indexOptions := INDEX_OPT_DOCS_AND_FREQS_AND_POSITIONS
+		if mi.storeOffsets {
+			indexOptions = INDEX_OPT_DOCS_AND_FREQS_AND_POSITIONS_AND_OFFSETS
+		}
+		mi.fieldInfos[fieldName] = NewFieldInfo(fieldName, true, int32(len(mi.fieldInfos)),
+			false, false, false, indexOptions, 0, DOC_VALUES_TYPE_NUMERIC, -1, nil)
+	}
+
+	atts := stream.Attributes()
+	termAtt := atts.Get("TermToBytesRefAttribute").(TermToBytesRefAttribute)
+	posIncrAtt := atts.Add("PositionIncrementAttribute").(PositionIncrementAttribute)
+	offsetAtt := atts.Add("OffsetAttribute").(OffsetAttribute)
+
+	if err = stream.Reset(); err != nil {
+		return err
+	}
+	for {
+		ok, err := stream.IncrementToken()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			break
+		}
+		termAtt.FillBytesRef()
+		term := string(termAtt.BytesRef().ToBytes())
+		numTokens++
+		posIncr := posIncrAtt.PositionIncrement()
+		if posIncr == 0 {
+			numOverlapTokens++
+		}
+		pos += posIncr
+		if mi.storeOffsets {
+			added[term] = append(added[term], pos,
+				offset+offsetAtt.StartOffset(), offset+offsetAtt.EndOffset())
+		} else {
+			added[term] = append(added[term], pos)
+		}
+	}
+	if err = stream.End(); err != nil {
+		return err
+	}
+
+	// ensure info.numTokens > 0 invariant; needed for correct operation
+	// of terms()
+	if numTokens > 0 {
+		for term, positions := range added {
+			postings, ok := info.terms[term]
+			if !ok {
+				postings = &memoryPostings{}
+				info.terms[term] = postings
+				info.sortedTerms = nil // invalidate sorted view, if any
+			}
+			postings.positions = append(postings.positions, positions...)
+		}
+		info.numTokens = numTokens
+		info.numOverlapTokens = numOverlapTokens
+		info.boost = boost
+		info.lastPosition = pos
+		info.lastOffset = offsetAtt.EndOffset() + offset
+		// every token is one occurrence of a term
+		info.sumTotalTermFreq = int64(numTokens)
+		mi.fields[fieldName] = info
+		mi.sortedFields = nil // invalidate sorted view, if any
+	}
+	return nil
+}
+
+/*
+Convenience method; creates and returns a token stream that generates
+a token for each keyword in the given collection, "as is", without
+any transforming text analysis. The resulting token stream can be fed
+into AddFieldFromStream(), yielding the equivalent of adding an
+untokenized keyword field.
+*/
+func KeywordTokenStream(keywords []string) analysis.TokenStream {
+	ans := &keywordTokenStream{TokenStreamImpl: analysis.NewTokenStream(), keywords: keywords}
+	ans.termAtt = ans.Attributes().Add("CharTermAttribute").(CharTermAttribute)
+	ans.offsetAtt = ans.Attributes().Add("OffsetAttribute").(OffsetAttribute)
+	return ans
+}
+
+type keywordTokenStream struct {
+	*analysis.TokenStreamImpl
+	termAtt   CharTermAttribute
+	offsetAtt OffsetAttribute
+	keywords  []string
+	next      int
+	start     int
+}
+
+func (ts *keywordTokenStream) IncrementToken() (bool, error) {
+	if ts.next >= len(ts.keywords) {
+		return false, nil
+	}
+	term := ts.keywords[ts.next]
+	ts.next++
+	ts.Attributes().Clear()
+	ts.termAtt.AppendString(term)
+	ts.offsetAtt.SetOffset(ts.start, ts.start+len(term))
+	ts.start += len(term) + 1 // separate words by 1 (blank) character
+	return true, nil
+}
+
+func (ts *keywordTokenStream) Reset() error {
+	ts.next, ts.start = 0, 0
+	return nil
+}
+
+/*
+Sets the Similarity used to compute the norms of the fields; it
+should be the same as the one used for scoring. The default is
+DefaultSimilarity().
+*/
+func (mi *MemoryIndex) SetSimilarity(similarity Similarity) {
+	assert2(similarity != nil, "similarity must not be nil")
+	mi.similarity = similarity
+}
+
+func (mi *MemoryIndex) Similarity() Similarity {
+	if mi.similarity == nil {
+		return DefaultSimilarity()
+	}
+	return mi.similarity
+}
+
+/*
+Creates and returns a reader over the single document of this index.
+The reader sees later changes made to this index.
+*/
+func (mi *MemoryIndex) CreateReader() AtomicReader {
+	return newMemoryIndexReader(mi)
+}
+
+/*
+Resets the MemoryIndex to its initial state, and recycles internal
+buffers, so that it can be reused for the next document.
+*/
+func (mi *MemoryIndex) Reset() {
+	mi.fields = make(map[string]*memoryFieldInfo)
+	mi.fieldInfos = make(map[string]*FieldInfo)
+	mi.sortedFields = nil
+}
+
+// returns all field names, sorted ascending
+func (mi *MemoryIndex) sortFields() []string {
+	if mi.sortedFields == nil {
+		mi.sortedFields = make([]string, 0, len(mi.fields))
+		for name := range mi.fields {
+			mi.sortedFields = append(mi.sortedFields, name)
+		}
+		sort.Strings(mi.sortedFields)
+	}
+	return mi.sortedFields
+}
+
+func (mi *MemoryIndex) String() string {
+	return fmt.Sprintf("MemoryIndex(fields=%v)", mi.sortFields())
+}
+
+// Index data structure for a field; contains the tokenized term texts
+// and their positions.
+type memoryFieldInfo struct {
+	terms map[string]*memoryPostings
+	// terms sorted ascending by term text; nil if invalid
+	sortedTerms []string
+	// number of added tokens for this field
+	numTokens int
+	// number of overlapping tokens for this field
+	numOverlapTokens int
+	// boost factor for hits for this field
+	boost float32
+	// the last position (for position increment gap)
+	lastPosition int
+	// the last offset (for offset gap)
+	lastOffset       int
+	sumTotalTermFreq int64
+}
+
+/*
+Sorts hashed terms into ascending order, reusing memory along the
+way. Note that sorting is lazily delayed until required (often it's
+not required at all).
+*/
+func (info *memoryFieldInfo) sortTerms() []string {
+	if info.sortedTerms == nil {
+		info.sortedTerms = make([]string, 0, len(info.terms))
+		for term := range info.terms {
+			info.sortedTerms = append(info.sortedTerms, term)
+		}
+		sort.Strings(info.sortedTerms)
+	}
+	return info.sortedTerms
+}
+
+// the positions of a term, interleaved with start and end offsets if
+// offsets are stored
+type memoryPostings struct {
+	positions []int
+}
+
+// memory/MemoryIndex.java#MemoryIndexReader
+
+/*
+Search support for Lucene framework integration; implements all
+methods required by the search package.
+*/
+type memoryIndexReader struct {
+	*AtomicReaderImpl
+	index *MemoryIndex
+}
+
+func newMemoryIndexReader(index *MemoryIndex) *memoryIndexReader {
+	ans := &memoryIndexReader{index: index}
+	ans.AtomicReaderImpl = newAtomicReader(ans)
+	ans.ARFieldsReader = ans
+	return ans
+}
+
+func (r *memoryIndexReader) NumDocs() int {
+	return 1
+}
+
+func (r *memoryIndexReader) MaxDoc() int {
+	return 1
+}
+
+func (r *memoryIndexReader) VisitDocument(docID int, visitor StoredFieldVisitor) error {
+	return nil // no stored fields
+}
+
+func (r *memoryIndexReader) doClose() error {
+	return nil
+}
+
+func (r *memoryIndexReader) LiveDocs() util.Bits {
+	return nil
+}
+
+func (r *memoryIndexReader) Fields() Fields {
+	return &memoryFields{r.index}
+}
+
+func (r *memoryIndexReader) Terms(field string) Terms {
+	return r.Fields().Terms(field)
+}
+
+func (r *memoryIndexReader) NormValues(field string) (NumericDocValues, error) {
+	fieldInfo, ok := r.index.fieldInfos[field]
+	if !ok || fieldInfo.OmitsNorms() {
+		return nil, nil
+	}
+	numTokens, numOverlapTokens, boost := 0, 0, float32(1)
+	if info, ok := r.index.fields[field]; ok {
+		numTokens, numOverlapTokens, boost = info.numTokens, info.numOverlapTokens, info.boost
+	}
+	norm := r.index.Similarity().ComputeNorm(
+		NewFieldInvertState(field, 0, numTokens, numOverlapTokens, 0, boost))
+	return func(docID int) int64 {
+		assert2(docID == 0, "docID must be 0 (got %v)", docID)
+		return norm
+	}, nil
+}
+
+func (r *memoryIndexReader) NumericDocValues(field string) (NumericDocValues, error) {
+	return nil, nil
+}
+
+func (r *memoryIndexReader) SortedSetDocValues(field string) (SortedSetDocValues, error) {
+	return nil, nil
+}
+
+func (r *memoryIndexReader) FieldInfos() FieldInfos {
+	infos := make([]*FieldInfo, 0, len(r.index.fieldInfos))
+	for _, info := range r.index.fieldInfos {
+		infos = append(infos, info)
+	}
+	return NewFieldInfos(infos)
+}
+
+func (r *memoryIndexReader) TermVectors(docID int) (Fields, error) {
+	if docID == 0 {
+		return r.Fields(), nil
+	}
+	return nil, nil
+}
+
+func (r *memoryIndexReader) String() string {
+	return fmt.Sprintf("MemoryIndexReader(%v)", r.index)
+}
+
+type memoryFields struct {
+	index *MemoryIndex
+}
+
+func (fs *memoryFields) Terms(field string) Terms {
+	if info, ok := fs.index.fields[field]; ok {
+		return &memoryTerms{info, fs.index.storeOffsets}
+	}
+	return nil
+}
+
+type memoryTerms struct {
+	info         *memoryFieldInfo
+	storeOffsets bool
+}
+
+func (t *memoryTerms) Iterator(reuse TermsEnum) TermsEnum {
+	return newMemoryTermsEnum(t.info, t.storeOffsets)
+}
+
+func (t *memoryTerms) DocCount() int {
+	if len(t.info.terms) > 0 {
+		return 1
+	}
+	return 0
+}
+
+func (t *memoryTerms) SumTotalTermFreq() int64 {
+	return t.info.sumTotalTermFreq
+}
+
+func (t *memoryTerms) SumDocFreq() int64 {
+	// each term has df=1
+	return int64(len(t.info.terms))
+}
+
+type memoryTermsEnum struct {
+	*TermsEnumImpl
+	info         *memoryFieldInfo
+	storeOffsets bool
+	terms        []string
+	termUpto     int
+}
+
+func newMemoryTermsEnum(info *memoryFieldInfo, storeOffsets bool) *memoryTermsEnum {
+	ans := &memoryTermsEnum{
+		info:         info,
+		storeOffsets: storeOffsets,
+		terms:        info.sortTerms(),
+		termUpto:     -1,
+	}
+	ans.TermsEnumImpl = NewTermsEnumImpl(ans)
+	return ans
+}
+
+func (e *memoryTermsEnum) SeekCeil(text []byte) SeekStatus {
+	target := string(text)
+	e.termUpto = sort.SearchStrings(e.terms, target)
+	if e.termUpto >= len(e.terms) {
+		return SEEK_STATUS_END
+	}
+	if e.terms[e.termUpto] == target {
+		return SEEK_STATUS_FOUND
+	}
+	return SEEK_STATUS_NOT_FOUND
+}
+
+func (e *memoryTermsEnum) SeekExactByPosition(ord int64) error {
+	assert(ord >= 0 && ord < int64(len(e.terms)))
+	e.termUpto = int(ord)
+	return nil
+}
+
+func (e *memoryTermsEnum) Next() ([]byte, error) {
+	e.termUpto++
+	if e.termUpto >= len(e.terms) {
+		return nil, nil
+	}
+	return []byte(e.terms[e.termUpto]), nil
+}
+
+func (e *memoryTermsEnum) Term() []byte {
+	return []byte(e.terms[e.termUpto])
+}
+
+func (e *memoryTermsEnum) Ord() int64 {
+	return int64(e.termUpto)
+}
+
+func (e *memoryTermsEnum) DocFreq() (int, error) {
+	return 1, nil
+}
+
+func (e *memoryTermsEnum) TotalTermFreq() (int64, error) {
+	return int64(e.freq()), nil
+}
+
+func (e *memoryTermsEnum) freq() int {
+	n := len(e.postings())
+	if e.storeOffsets {
+		return n / 3
+	}
+	return n
+}
+
+func (e *memoryTermsEnum) postings() []int {
+	return e.info.terms[e.terms[e.termUpto]].positions
+}
+
+func (e *memoryTermsEnum) DocsByFlags(liveDocs util.Bits, reuse DocsEnum, flags int) (DocsEnum, error) {
+	return &memoryDocsEnum{liveDocs: liveDocs, doc: -1, freq: e.freq()}, nil
+}
+
+func (e *memoryTermsEnum) DocsAndPositionsByFlags(liveDocs util.Bits,
+	reuse DocsAndPositionsEnum, flags int) (DocsAndPositionsEnum, error) {
+
+	return &memoryDocsAndPositionsEnum{
+		memoryDocsEnum: &memoryDocsEnum{liveDocs: liveDocs, doc: -1, freq: e.freq()},
+		positions:      e.postings(),
+		storeOffsets:   e.storeOffsets,
+		posUpto:        -1,
+	}, nil
+}
+
+type memoryDocsEnum struct {
+	liveDocs util.Bits
+	doc      int
+	freq     int
+}
+
+func (de *memoryDocsEnum) DocId() int {
+	return de.doc
+}
+
+func (de *memoryDocsEnum) NextDoc() (int, error) {
+	if de.doc == -1 && (de.liveDocs == nil || de.liveDocs.At(0)) {
+		de.doc = 0
+	} else {
+		de.doc = NO_MORE_DOCS
+	}
+	return de.doc, nil
+}
+
+func (de *memoryDocsEnum) Advance(target int) (int, error) {
+	for de.doc < target {
+		if _, err := de.NextDoc(); err != nil {
+			return 0, err
+		}
+	}
+	return de.doc, nil
+}
+
+func (de *memoryDocsEnum) Freq() (int, error) {
+	return de.freq, nil
+}
+
+type memoryDocsAndPositionsEnum struct {
+	*memoryDocsEnum
+	positions    []int
+	storeOffsets bool
+	posUpto      int // for assert
+}
+
+func (de *memoryDocsAndPositionsEnum) stride() int {
+	if de.storeOffsets {
+		return 3
+	}
+	return 1
+}
+
+func (de *memoryDocsAndPositionsEnum) NextPosition() (int, error) {
+	de.posUpto++
+	assert2(de.posUpto < de.freq, "NextPosition() called more than Freq() times")
+	return de.positions[de.posUpto*de.stride()], nil
+}
+
+func (de *memoryDocsAndPositionsEnum) StartOffset() (int, error) {
+	if !de.storeOffsets {
+		return -1, nil
+	}
+	return de.positions[de.posUpto*3+1], nil
+}
+
+func (de *memoryDocsAndPositionsEnum) EndOffset() (int, error) {
+	if !de.storeOffsets {
+		return -1, nil
+	}
+	return de.positions[de.posUpto*3+2], nil
+}
+
+func (de *memoryDocsAndPositionsEnum) Payload() ([]byte, error) {
+	return nil, nil
+}
@@ -3,6 +3,7 @@ package index
 import (
 	"fmt"
 	. "github.com/balzaczyy/golucene/core/codec/spi"
+	"github.com/balzaczyy/golucene/core/store"
 	// "github.com/balzaczyy/golucene/core/util"
 	"io"
 	"math"
@@ -25,6 +26,16 @@ type MergeScheduler interface {
 	Merge(*IndexWriter, MergeTrigger, bool) error
 }
 
+/*
+A MergeScheduler limiting the rate merges write at. IndexWriter
+passes the outputs created with a MERGE IOContext through the rate
+limiter.
+*/
+type RateLimitedMergeScheduler interface {
+	MergeScheduler
+	MergeRateLimiter() store.RateLimiter
+}
+
 // index/MergeState.java
 
 // Recording units of work when merging segments.
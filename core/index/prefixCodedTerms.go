@@ -4,6 +4,8 @@ import (
 	"github.com/balzaczyy/golucene/core/store"
 )
 
+// index/PrefixCodedTerms.java
+
 /* Prefix codes term instances (prefixes are shared) */
 type PrefixCodedTerms struct {
 	buffer *store.RAMFile
@@ -17,22 +19,92 @@ func (terms *PrefixCodedTerms) RamBytesUsed() int64 {
 	return terms.buffer.RamBytesUsed()
 }
 
+/* Calls f for each term, in the order they were added. */
+func (terms *PrefixCodedTerms) each(f func(term *Term) error) error {
+	input, err := store.NewRAMInputStream("PrefixCodedTermsIterator", terms.buffer)
+	if err != nil {
+		return err
+	}
+	defer input.Close()
+
+	var field string
+	var bytes []byte
+	for input.FilePointer() < input.Length() {
+		code, err := input.ReadVInt()
+		if err != nil {
+			return err
+		}
+		if (code & 1) != 0 {
+			// new field
+			if field, err = input.ReadString(); err != nil {
+				return err
+			}
+		}
+		prefix := int(uint32(code) >> 1)
+		suffix, err := input.ReadVInt()
+		if err != nil {
+			return err
+		}
+		newBytes := make([]byte, prefix+int(suffix))
+		copy(newBytes, bytes[:prefix])
+		if err = input.ReadBytes(newBytes[prefix:]); err != nil {
+			return err
+		}
+		bytes = newBytes
+		if err = f(NewTermFromBytes(field, bytes)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 /* Builds a PrefixCodedTerms: call add repeatedly, then finish. */
 type PrefixCodedTermsBuilder struct {
-	buffer *store.RAMFile
-	output *store.RAMOutputStream
+	buffer   *store.RAMFile
+	output   *store.RAMOutputStream
+	lastTerm *Term
 }
 
 func newPrefixCodedTermsBuilder() *PrefixCodedTermsBuilder {
 	f := store.NewRAMFileBuffer()
 	return &PrefixCodedTermsBuilder{
-		buffer: f,
-		output: store.NewRAMOutputStream(f, false),
+		buffer:   f,
+		output:   store.NewRAMOutputStream(f, false),
+		lastTerm: NewEmptyTerm(""),
 	}
 }
 
+/* add a term */
 func (b *PrefixCodedTermsBuilder) add(term *Term) {
-	panic("not implemented yet")
+	prefix := sharedPrefix(b.lastTerm.Bytes, term.Bytes)
+	suffix := len(term.Bytes) - prefix
+	var err error
+	if term.Field == b.lastTerm.Field {
+		err = b.output.WriteVInt(int32(prefix << 1))
+	} else {
+		if err = b.output.WriteVInt(int32(prefix<<1 | 1)); err == nil {
+			err = b.output.WriteString(term.Field)
+		}
+	}
+	if err == nil {
+		if err = b.output.WriteVInt(int32(suffix)); err == nil {
+			err = b.output.WriteBytes(term.Bytes[prefix:])
+		}
+	}
+	if err != nil {
+		panic(err)
+	}
+	b.lastTerm = NewTermFromBytes(term.Field, append([]byte(nil), term.Bytes...))
+}
+
+func sharedPrefix(term1, term2 []byte) int {
+	pos := 0
+	for end := len(term1); pos < end && pos < len(term2); pos++ {
+		if term1[pos] != term2[pos] {
+			break
+		}
+	}
+	return pos
 }
 
 func (b *PrefixCodedTermsBuilder) finish() *PrefixCodedTerms {
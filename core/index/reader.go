@@ -17,6 +17,10 @@ import (
 type IndexReader interface {
 	io.Closer
 	decRef() error
+	IncRef()
+	TryIncRef() bool
+	DecRef() error
+	RefCount() int
 	ensureOpen()
 	registerParentReader(r IndexReader)
 	NumDocs() int
@@ -94,6 +98,77 @@ func newIndexReader(spi IndexReaderImplSPI) *IndexReaderImpl {
 	}
 }
 
+/*
+Expert: increments the refCount of this IndexReader instance.
+RefCounts are used to determine when a reader can be closed safely,
+i.e. as soon as there are no more references. Be sure to always call
+a corresponding decRef(), in a finally clause; otherwise the reader
+may never be closed.
+*/
+func (r *IndexReaderImpl) incRef() {
+	if !r.tryIncRef() {
+		r.ensureOpen()
+	}
+}
+
+/*
+Expert: increments the refCount of this IndexReader instance only if
+the IndexReader has not been closed yet and returns true iff the
+refCount was successfully incremented, otherwise false.
+*/
+func (r *IndexReaderImpl) tryIncRef() bool {
+	for count := atomic.LoadInt32(&r.refCount); count > 0; count = atomic.LoadInt32(&r.refCount) {
+		if atomic.CompareAndSwapInt32(&r.refCount, count, count+1) {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+Expert: returns the current refCount for this reader. A reader with a
+refCount of 0 is closed.
+*/
+func (r *IndexReaderImpl) RefCount() int {
+	// NOTE: don't ensureOpen, so that callers can see refCount is 0
+	// (reader is closed)
+	return int(atomic.LoadInt32(&r.refCount))
+}
+
+/*
+Expert: increments the refCount of this IndexReader instance.
+RefCounts are used to determine when a reader can be closed safely,
+i.e. as soon as there are no more references. Be sure to always call
+a corresponding DecRef(), e.g. in a defer; otherwise the reader may
+never be closed. Note that Close() simply calls DecRef(), which
+means that the IndexReader will not really be closed until DecRef()
+has been called for all outstanding references.
+*/
+func (r *IndexReaderImpl) IncRef() {
+	r.incRef()
+}
+
+/*
+Expert: increments the refCount of this IndexReader instance only if
+the IndexReader has not been closed yet and returns true iff the
+refCount was successfully incremented, otherwise false. If this
+method returns false the reader is either already closed or is
+currently being closed. Either way this reader instance shouldn't be
+used by an application unless true is returned.
+*/
+func (r *IndexReaderImpl) TryIncRef() bool {
+	return r.tryIncRef()
+}
+
+/*
+Expert: decreases the refCount of this IndexReader instance. If the
+refCount drops to 0, then this reader is closed. If an error is hit,
+the refCount is unchanged.
+*/
+func (r *IndexReaderImpl) DecRef() error {
+	return r.decRef()
+}
+
 func (r *IndexReaderImpl) decRef() error {
 	// only check refcount here (don't call ensureOpen()), so we can
 	// still close the reader if it was made invalid by a child:
@@ -244,6 +319,20 @@ type ARFieldsReader interface {
 	 *  were indexed. The returned instance should only be
 	 *  used by a single thread. */
 	NormValues(field string) (ndv NumericDocValues, err error)
+	// Returns NumericDocValues for this field, or nil if no
+	// NumericDocValues were indexed for this field. The returned
+	// instance should only be used by a single thread.
+	NumericDocValues(field string) (ndv NumericDocValues, err error)
+	// Returns SortedSetDocValues for this field, or nil if no
+	// SortedSetDocValues were indexed for this field. The returned
+	// instance should only be used by a single thread.
+	SortedSetDocValues(field string) (dv SortedSetDocValues, err error)
+	// Get the FieldInfos describing all fields in this reader.
+	FieldInfos() FieldInfos
+	// Retrieve term vectors for this document, or nil if term vectors
+	// were not indexed. The returned Fields instance acts like a
+	// single-document inverted index (the docID will be 0).
+	TermVectors(docID int) (fs Fields, err error)
 }
 
 type AtomicReader interface {
@@ -21,17 +21,60 @@ func newReaderPool(owner *IndexWriter) *ReaderPool {
 }
 
 func (pool *ReaderPool) infoIsLive(info *SegmentCommitInfo) bool {
-	panic("not implemented yet")
+	idx := pool.owner.segmentInfos.indexOf(info)
+	assertn(idx != -1, "info=%v isn't live", info)
+	assertn(pool.owner.segmentInfos.Segments[idx] == info,
+		"info=%v doesn't match live info in segmentInfos", info)
+	return true
 }
 
 func (pool *ReaderPool) drop(info *SegmentCommitInfo) error {
 	pool.Lock()
 	defer pool.Unlock()
-	panic("not implemented yet")
+	if rld, ok := pool.readerMap[info]; ok {
+		assert(info == rld.info)
+		delete(pool.readerMap, info)
+		return rld.dropReaders()
+	}
+	return nil
 }
 
 func (pool *ReaderPool) release(rld *ReadersAndUpdates) error {
-	panic("not implemented yet")
+	pool.Lock()
+	defer pool.Unlock()
+
+	// Matches incRef in get:
+	rld.decRef()
+
+	// Pool still holds a ref:
+	assert(rld.refCount() >= 1)
+
+	if !pool.owner.poolReaders && rld.refCount() == 1 {
+		// This is the last ref to this RLD, and we're not pooling, so
+		// remove it:
+		ok, err := rld.writeLiveDocs(pool.owner.directory)
+		if err != nil {
+			return err
+		}
+		if ok {
+			// Make sure we only write del docs for a live segment:
+			assert(pool.infoIsLive(rld.info))
+			// Must checkpoint because we just created new _X_N.del and
+			// field updates files; don't call IW.checkpoint because that
+			// also increments SIS.version, which we do not want to do
+			// here: it was done previously (after we invoked
+			// BDS.applyDeletes), whereas here all we did was move the
+			// state to disk:
+			if err = pool.owner._checkpointNoSIS(); err != nil {
+				return err
+			}
+		}
+		if err = rld.dropReaders(); err != nil {
+			return err
+		}
+		delete(pool.readerMap, rld.info)
+	}
+	return nil
 }
 
 func (pool *ReaderPool) Close() error {
@@ -61,7 +104,7 @@ func (pool *ReaderPool) dropAll(doSave bool) error {
 					// do here: it was done previously (after we
 					// invoked BDS.applyDeletes), whereas here all we
 					// did was move the state to disk:
-					err = pool.owner.checkpointNoSIS()
+					err = pool.owner._checkpointNoSIS()
 					if err != nil {
 						return err
 					}
@@ -114,7 +157,7 @@ func (pool *ReaderPool) commit(infos *SegmentInfos) error {
 				// here: it was doen previously (after we invoked
 				// BDS.applyDeletes), whereas here all we did was move the
 				// stats to disk:
-				err = pool.owner.checkpointNoSIS()
+				err = pool.owner._checkpointNoSIS()
 				if err != nil {
 					return err
 				}
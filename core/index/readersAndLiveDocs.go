@@ -1,6 +1,7 @@
 package index
 
 import (
+	"fmt"
 	. "github.com/balzaczyy/golucene/core/codec/spi"
 	"github.com/balzaczyy/golucene/core/store"
 	"github.com/balzaczyy/golucene/core/util"
@@ -72,7 +73,13 @@ type ReadersAndUpdates struct {
 }
 
 func newReadersAndUpdates(writer *IndexWriter, info *SegmentCommitInfo) *ReadersAndUpdates {
-	return &ReadersAndUpdates{info: info, writer: writer, liveDocsShared: true}
+	return &ReadersAndUpdates{
+		Locker:         &sync.Mutex{},
+		refCountMixin:  newRefCountMixin(),
+		info:           info,
+		writer:         writer,
+		liveDocsShared: true,
+	}
 }
 
 func (rld *ReadersAndUpdates) pendingDeleteCount() int {
@@ -85,11 +92,47 @@ func (rld *ReadersAndUpdates) pendingDeleteCount() int {
 Get reader for searching/deleting
 */
 func (rld *ReadersAndUpdates) reader(ctx store.IOContext) (*SegmentReader, error) {
-	panic("not implemented yet")
+	rld.Lock()
+	defer rld.Unlock()
+
+	if rld._reader == nil {
+		// We steal returned ref:
+		var err error
+		if rld._reader, err = NewSegmentReader(rld.info,
+			rld.writer.config.ReaderTermsIndexDivisor(), ctx); err != nil {
+			return nil, err
+		}
+		if rld._liveDocs == nil {
+			rld._liveDocs = rld._reader.LiveDocs()
+		}
+	}
+	// Ref for caller
+	rld._reader.incRef()
+	return rld._reader, nil
 }
 
 func (rld *ReadersAndUpdates) release(sr *SegmentReader) error {
-	panic("not implemented yet")
+	rld.Lock()
+	defer rld.Unlock()
+	assert(rld.info == sr.si)
+	return sr.decRef()
+}
+
+func (rld *ReadersAndUpdates) delete(docID int) bool {
+	rld.Lock()
+	defer rld.Unlock()
+
+	assert(rld._liveDocs != nil)
+	assert2(docID >= 0 && docID < rld._liveDocs.Length(),
+		"out of bounds: docid=%v liveDocsLength=%v seg=%v docCount=%v",
+		docID, rld._liveDocs.Length(), rld.info.Info.Name, rld.info.Info.DocCount())
+	assert(!rld.liveDocsShared)
+	didDelete := rld._liveDocs.At(docID)
+	if didDelete {
+		rld._liveDocs.(util.MutableBits).Clear(docID)
+		rld._pendingDeleteCount++
+	}
+	return didDelete
 }
 
 // NOTE: removes callers ref
@@ -127,6 +170,25 @@ func (rld *ReadersAndUpdates) dropReaders() error {
 	return nil
 }
 
+func (rld *ReadersAndUpdates) initWritableLiveDocs() {
+	rld.Lock()
+	defer rld.Unlock()
+
+	assert(rld.info.Info.DocCount() > 0)
+	if rld.liveDocsShared {
+		// Copy on write: this means we've cloned a SegmentReader
+		// sharing the current liveDocs instance; must now make a
+		// private clone so we can change it:
+		liveDocsFormat := rld.info.Info.Codec().(Codec).LiveDocsFormat()
+		if rld._liveDocs == nil {
+			rld._liveDocs = liveDocsFormat.NewLiveDocs(rld.info.Info.DocCount())
+		} else {
+			rld._liveDocs = liveDocsFormat.NewLiveDocsFrom(rld._liveDocs)
+		}
+		rld.liveDocsShared = false
+	}
+}
+
 func (rld *ReadersAndUpdates) liveDocs() util.Bits {
 	rld.Lock()
 	defer rld.Unlock()
@@ -139,11 +201,10 @@ new _X_N.del files) to the directory; returns true if it wrote any
 file and false if there were no new deletes or updates to write:
 */
 func (rld *ReadersAndUpdates) writeLiveDocs(dir store.Directory) (bool, error) {
-	panic("not implemented yet")
 	rld.Lock()
 	defer rld.Unlock()
 
-	log.Printf("rld.writeLiveDocs seg=%v pendingDelCount=%v", rld.info, rld._pendingDeleteCount)
+	// log.Printf("rld.writeLiveDocs seg=%v pendingDelCount=%v", rld.info, rld._pendingDeleteCount)
 	if rld._pendingDeleteCount != 0 {
 		// We have new deletes
 		assert(rld._liveDocs.Length() == rld.info.Info.DocCount())
@@ -196,5 +257,6 @@ func (r *ReadersAndUpdates) writeFieldUpdates(dir store.Directory,
 }
 
 func (rld *ReadersAndUpdates) String() string {
-	panic("not implemented yet")
+	return fmt.Sprintf("ReadersAndLiveDocs(seg=%v pendingDeleteCount=%v liveDocsShared=%v)",
+		rld.info, rld._pendingDeleteCount, rld.liveDocsShared)
 }
@@ -894,5 +894,24 @@ Remove the provided SegmentCommitInfo.
 WARNING: O(N) cost
 */
 func (sis *SegmentInfos) remove(si *SegmentCommitInfo) {
-	panic("not implemented yet")
+	if index := sis.indexOf(si); index >= 0 {
+		copy(sis.Segments[index:], sis.Segments[index+1:])
+		sis.Segments[len(sis.Segments)-1] = nil
+		sis.Segments = sis.Segments[:len(sis.Segments)-1]
+	}
+}
+
+/*
+Return the index of the provided SegmentCommitInfo, or -1 if it's not
+present.
+
+WARNING: O(N) cost
+*/
+func (sis *SegmentInfos) indexOf(si *SegmentCommitInfo) int {
+	for i, info := range sis.Segments {
+		if info == si {
+			return i
+		}
+	}
+	return -1
 }
@@ -2,6 +2,7 @@ package index
 
 import (
 	_ "github.com/balzaczyy/golucene/core/codec/lucene42"
+	. "github.com/balzaczyy/golucene/core/index/model"
 	"github.com/balzaczyy/golucene/core/store"
 	"testing"
 )
@@ -37,3 +38,61 @@ func TestInitTerm(t *testing.T) {
 		t.Error("SeekExact should return true.")
 	}
 }
+
+func TestNextAndSeekCeil(t *testing.T) {
+	d, err := store.OpenFSDirectory("../search/testdata/win8/belfrysample")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := OpenDirectoryReader(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	terms := r.Context().Leaves()[0].reader.Fields().Terms("content")
+
+	var all []string
+	termsEnum := terms.Iterator(nil)
+	for {
+		term, err := termsEnum.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if term == nil {
+			break
+		}
+		if n := len(all); n > 0 && all[n-1] >= string(term) {
+			t.Fatalf("terms out of order: %v >= %v", all[n-1], string(term))
+		}
+		all = append(all, string(term))
+	}
+	if len(all) == 0 {
+		t.Fatal("Should enumerate some terms.")
+	}
+
+	for _, target := range []string{"bat", "bas", "", all[len(all)/2], all[len(all)-1] + "z"} {
+		termsEnum = terms.Iterator(nil)
+		status := termsEnum.SeekCeil([]byte(target))
+		expected := ""
+		for _, v := range all {
+			if v >= target {
+				expected = v
+				break
+			}
+		}
+		switch {
+		case expected == "":
+			if status != SEEK_STATUS_END {
+				t.Errorf("SeekCeil(%q) should hit end, but was %v", target, status)
+			}
+		case expected == target:
+			if status != SEEK_STATUS_FOUND {
+				t.Errorf("SeekCeil(%q) should be found, but was %v", target, status)
+			}
+		default:
+			if status != SEEK_STATUS_NOT_FOUND || string(termsEnum.Term()) != expected {
+				t.Errorf("SeekCeil(%q) should land on %q, but was %v %q",
+					target, expected, status, termsEnum.Term())
+			}
+		}
+	}
+}
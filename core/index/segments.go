@@ -74,7 +74,11 @@ func NewSegmentReader(si *SegmentCommitInfo,
 
 	codec := si.Info.Codec().(Codec)
 	if si.HasDeletions() {
-		panic("not supported yet")
+		// NOTE: the bitvector is stored using the regular directory, not cfs
+		if r.liveDocs, err = codec.LiveDocsFormat().ReadLiveDocs(si.Info.Dir,
+			si, store.IO_CONTEXT_READONCE); err != nil {
+			return nil, err
+		}
 	} else {
 		assert(si.DelCount() == 0)
 	}
@@ -144,7 +148,6 @@ func (r *SegmentReader) LiveDocs() util.Bits {
 }
 
 func (r *SegmentReader) doClose() error {
-	panic("not implemented yet")
 	r.core.decRef()
 	return nil
 }
@@ -180,12 +183,19 @@ func (r *SegmentReader) MaxDoc() int {
 	return r.si.Info.DocCount()
 }
 
+// Expert: retrieve thread-private TermVectorsReader
 func (r *SegmentReader) TermVectorsReader() TermVectorsReader {
-	panic("not implemented yet")
+	r.ensureOpen()
+	return r.core.termVectorsLocal()
 }
 
 func (r *SegmentReader) TermVectors(docID int) (fs Fields, err error) {
-	panic("not implemented yet")
+	termVectorsReader := r.TermVectorsReader()
+	if termVectorsReader == nil {
+		return nil, nil
+	}
+	r.checkBounds(docID)
+	return termVectorsReader.Get(docID), nil
 }
 
 func (r *SegmentReader) checkBounds(docID int) {
@@ -281,6 +291,7 @@ type SegmentCoreReaders struct {
 	 TODO redesign when ported to goroutines
 	*/
 	fieldsReaderLocal func() StoredFieldsReader
+	termVectorsLocal  func() TermVectorsReader
 	normsLocal        func() map[string]interface{}
 
 	addListener    chan CoreClosedListener
@@ -304,6 +315,12 @@ func newSegmentCoreReaders(owner *SegmentReader, dir store.Directory, si *Segmen
 	self.fieldsReaderLocal = func() StoredFieldsReader {
 		return self.fieldsReaderOrig.Clone()
 	}
+	self.termVectorsLocal = func() TermVectorsReader {
+		if self.termVectorsReaderOrig == nil {
+			return nil
+		}
+		return self.termVectorsReaderOrig.Clone()
+	}
 
 	// fmt.Println("Initializing listeners...")
 	self.addListener = make(chan CoreClosedListener)
@@ -55,6 +55,43 @@ func (t *Term) String() string {
 	return fmt.Sprintf("%v:%v", t.Field, utf8ToString(t.Bytes))
 }
 
+/*
+A set of terms, which keeps the order in which they were first
+added. Terms are compared by field and bytes, since Term itself
+cannot be used as a map key.
+*/
+type TermSet struct {
+	seen  map[string]map[string]bool
+	Terms []*Term
+}
+
+func NewTermSet() *TermSet {
+	return &TermSet{seen: make(map[string]map[string]bool)}
+}
+
+// Adds the term to the set; returns false if it was already present.
+func (s *TermSet) Add(t *Term) bool {
+	texts, ok := s.seen[t.Field]
+	if !ok {
+		texts = make(map[string]bool)
+		s.seen[t.Field] = texts
+	}
+	if texts[string(t.Bytes)] {
+		return false
+	}
+	texts[string(t.Bytes)] = true
+	s.Terms = append(s.Terms, t)
+	return true
+}
+
+func (s *TermSet) Contains(t *Term) bool {
+	return s.seen[t.Field][string(t.Bytes)]
+}
+
+func (s *TermSet) Size() int {
+	return len(s.Terms)
+}
+
 // TermContext.java
 
 type TermContext struct {
@@ -125,13 +162,27 @@ func (tc *TermContext) register(state TermState, ord, docFreq int, totalTermFreq
 	assert2(state != nil, "state must not be nil")
 	assert(ord >= 0 && ord < len(tc.states))
 	assert2(tc.states[ord] == nil, "state for ord: %v already registered", ord)
+	tc.AccumulateStatistics(docFreq, totalTermFreq)
+	tc.states[ord] = state
+}
+
+/*
+Registers and associates a TermState with a leaf ordinal, without
+accumulating any statistics. Use AccumulateStatistics() to set the
+aggregated statistics, e.g. when blending terms across fields.
+*/
+func (tc *TermContext) Register(state TermState, ord int) {
+	tc.register(state, ord, 0, 0)
+}
+
+// Expert: accumulate term statistics.
+func (tc *TermContext) AccumulateStatistics(docFreq int, totalTermFreq int64) {
 	tc.DocFreq += docFreq
 	if tc.TotalTermFreq >= 0 && totalTermFreq >= 0 {
 		tc.TotalTermFreq += totalTermFreq
 	} else {
 		tc.TotalTermFreq = -1
 	}
-	tc.states[ord] = state
 }
 
 func (tc *TermContext) State(ord int) TermState {
@@ -77,7 +77,18 @@ func (c *TermVectorsConsumerPerField) start(field IndexableField, first bool) bo
 				field.Name())
 		}
 	} else {
-		panic("not implemented yet")
+		assert2(c.doVectors == t.StoreTermVectors(),
+			"all instances of a given field name must index term vectors or not; got storeTermVectors=%v for field='%v'",
+			t.StoreTermVectors(), field.Name())
+		assert2(c.doVectorPositions == t.StoreTermVectorPositions(),
+			"all instances of a given field name must index term vector positions or not; got storeTermVectorPositions=%v for field='%v'",
+			t.StoreTermVectorPositions(), field.Name())
+		assert2(c.doVectorOffsets == t.StoreTermVectorOffsets(),
+			"all instances of a given field name must index term vector offsets or not; got storeTermVectorOffsets=%v for field='%v'",
+			t.StoreTermVectorOffsets(), field.Name())
+		assert2(c.doVectorPayloads == t.StoreTermVectorPayloads(),
+			"all instances of a given field name must index term vector payloads or not; got storeTermVectorPayloads=%v for field='%v'",
+			t.StoreTermVectorPayloads(), field.Name())
 	}
 
 	if c.doVectors {
@@ -222,8 +233,7 @@ func newFreqProxTermsWriterPerField(invertState *FieldInvertState,
 func (w *FreqProxTermsWriterPerField) finish() error {
 	err := w.TermsHashPerFieldImpl.finish()
 	if err == nil && w.sawPayloads {
-		panic("not implemented yet")
-		// w.fieldInfo.SetStorePayloads()
+		w.fieldInfo.SetStorePayloads()
 	}
 	return err
 }
@@ -271,7 +281,10 @@ func (w *FreqProxTermsWriterPerField) writeProx(termId, proxCode int) {
 	} else {
 		payload := w.payloadAttribute.Payload()
 		if len(payload) > 0 {
-			panic("not implemented yet")
+			w.writeVInt(1, (proxCode<<1)|1)
+			w.writeVInt(1, len(payload))
+			w.writeBytes(1, payload)
+			w.sawPayloads = true
 		} else {
 			w.writeVInt(1, proxCode<<1)
 		}
@@ -282,7 +295,13 @@ func (w *FreqProxTermsWriterPerField) writeProx(termId, proxCode int) {
 }
 
 func (w *FreqProxTermsWriterPerField) writeOffsets(termId, offsetAccum int) {
-	panic("not implemented yet")
+	startOffset := offsetAccum + w.offsetAttribute.StartOffset()
+	endOffset := offsetAccum + w.offsetAttribute.EndOffset()
+	postings := w.freqProxPostingsArray
+	assert(startOffset-postings.lastOffsets[termId] >= 0)
+	w.writeVInt(1, startOffset-postings.lastOffsets[termId])
+	w.writeVInt(1, endOffset-startOffset)
+	postings.lastOffsets[termId] = startOffset
 }
 
 func (w *FreqProxTermsWriterPerField) newTerm(termId int) {
@@ -322,7 +341,15 @@ func (w *FreqProxTermsWriterPerField) addTerm(termId int) {
 	assert(!w.hasFreq || postings.termFreqs[termId] > 0)
 
 	if !w.hasFreq {
-		panic("not implemented yet")
+		assert(postings.termFreqs == nil)
+		if w.docState.docID != postings.lastDocIDs[termId] {
+			// New document; now encode docCode for previous doc:
+			assert(w.docState.docID > postings.lastDocIDs[termId])
+			w.writeVInt(0, postings.lastDocCodes[termId])
+			postings.lastDocCodes[termId] = w.docState.docID - postings.lastDocIDs[termId]
+			postings.lastDocIDs[termId] = w.docState.docID
+			w.fieldState.uniqueTermCount++
+		}
 	} else if w.docState.docID != postings.lastDocIDs[termId] {
 		assert2(w.docState.docID > postings.lastDocIDs[termId],
 			"id: %v postings ID: %v termID: %v",
@@ -348,7 +375,8 @@ func (w *FreqProxTermsWriterPerField) addTerm(termId int) {
 		if w.hasProx {
 			w.writeProx(termId, w.fieldState.position)
 			if w.hasOffsets {
-				panic("niy")
+				postings.lastOffsets[termId] = 0
+				w.writeOffsets(termId, w.fieldState.offset)
 			}
 		} else {
 			assert(!w.hasOffsets)
@@ -498,7 +526,7 @@ func (w *FreqProxTermsWriterPerField) flush(fieldName string,
 
 	assert(!writeOffsets || writePositions)
 
-	var segUpdates map[*Term]int
+	var segUpdates map[termKey]int
 	if state.SegUpdates != nil && len(state.SegUpdates.(*BufferedUpdates).terms) > 0 {
 		segUpdates = state.SegUpdates.(*BufferedUpdates).terms
 	}
@@ -509,12 +537,12 @@ func (w *FreqProxTermsWriterPerField) flush(fieldName string,
 	postings := w.freqProxPostingsArray
 	freq := newByteSliceReader()
 	prox := newByteSliceReader()
+	var payload []byte // reused buffer for the payloads read from prox
 
 	visitedDocs := util.NewFixedBitSetOf(state.SegmentInfo.DocCount())
 	sumTotalTermFreq := int64(0)
 	sumDocFreq := int64(0)
 
-	protoTerm := NewEmptyTerm(fieldName)
 	for i := 0; i < numTerms; i++ {
 		termId := termIDs[i]
 		// fmt.Printf("term=%v\n", termId)
@@ -539,8 +567,7 @@ func (w *FreqProxTermsWriterPerField) flush(fieldName string,
 
 		delDocLimit := 0
 		if segUpdates != nil {
-			protoTerm.Bytes = text.ToBytes()
-			if docIDUpto, ok := segUpdates[protoTerm]; ok {
+			if docIDUpto, ok := segUpdates[termKey{fieldName, string(text.ToBytes())}]; ok {
 				delDocLimit = docIDUpto
 			}
 		}
@@ -612,7 +639,18 @@ func (w *FreqProxTermsWriterPerField) flush(fieldName string,
 				return err
 			}
 			if docId < delDocLimit {
-				panic("not implemented yet")
+				// Mark it deleted. TODO: we could also skip writing its
+				// postings; this would be deterministic (just for this
+				// Term's docs).
+
+				// TODO: can we do this reach-around in a cleaner way????
+				if state.LiveDocs == nil {
+					state.LiveDocs = w.docState.docWriter.codec.LiveDocsFormat().NewLiveDocs(state.SegmentInfo.DocCount())
+				}
+				if state.LiveDocs.At(docId) {
+					state.DelCountOnFlush++
+					state.LiveDocs.Clear(docId)
+				}
 			}
 
 			totalTermFreq += int64(termFreq)
@@ -622,8 +660,7 @@ func (w *FreqProxTermsWriterPerField) flush(fieldName string,
 
 			if readPositions || readOffsets {
 				// we did record positions (& maybe payload) and/or offsets
-				position := 0
-				// offset := 0
+				position, offset := 0, 0
 				for j := 0; j < termFreq; j++ {
 					var thisPayload []byte
 
@@ -635,11 +672,43 @@ func (w *FreqProxTermsWriterPerField) flush(fieldName string,
 						position += int(uint(code) >> 1)
 
 						if (code & 1) != 0 {
-							panic("not implemented yet")
+							// this position has a payload
+							payloadLength, err := prox.ReadVInt()
+							if err != nil {
+								return err
+							}
+							if cap(payload) < int(payloadLength) {
+								payload = make([]byte, payloadLength)
+							}
+							thisPayload = payload[:payloadLength]
+							if err = prox.ReadBytes(thisPayload); err != nil {
+								return err
+							}
 						}
 
 						if readOffsets {
-							panic("not implemented yet")
+							code, err := prox.ReadVInt()
+							if err != nil {
+								return err
+							}
+							startOffset := offset + int(code)
+							if code, err = prox.ReadVInt(); err != nil {
+								return err
+							}
+							endOffset := startOffset + int(code)
+							if writePositions {
+								if writeOffsets {
+									assert2(startOffset >= 0 && endOffset >= startOffset,
+										"startOffset=%v,endOffset=%v,offset=%v", startOffset, endOffset, offset)
+									err = postingsConsumer.AddPosition(position, thisPayload, startOffset, endOffset)
+								} else {
+									err = postingsConsumer.AddPosition(position, thisPayload, -1, -1)
+								}
+								if err != nil {
+									return err
+								}
+							}
+							offset = startOffset
 						} else if writePositions {
 							err = postingsConsumer.AddPosition(position, thisPayload, -1, -1)
 							if err != nil {
@@ -212,6 +212,11 @@ type IndexWriter struct {
 
 	mergeScheduler  MergeScheduler
 	mergeExceptions []*OneMerge
+
+	// The directory merges write to, rate limited by the merge
+	// scheduler if it is a RateLimitedMergeScheduler.
+	mergeDirectory store.Directory
+
 	didMessageState bool
 
 	flushCount        int32 // atomic
@@ -314,6 +319,7 @@ func NewIndexWriter(d store.Directory, conf *IndexWriterConfig) (w *IndexWriter,
 
 		writeLock: d.MakeLock(WRITE_LOCK_NAME),
 	}
+	ans.mergeDirectory = addMergeRateLimiter(d, conf.mergeScheduler)
 	ans.readerPool = newReaderPool(ans)
 	ans.MergeControl = newMergeControl(conf.infoStream, ans.readerPool)
 
@@ -430,6 +436,19 @@ func NewIndexWriter(d store.Directory, conf *IndexWriterConfig) (w *IndexWriter,
 	return ans, nil
 }
 
+/*
+Wraps the directory so that the outputs of merges are rate limited by
+the merge scheduler, if it supports it.
+*/
+func addMergeRateLimiter(d store.Directory, ms MergeScheduler) store.Directory {
+	if rlms, ok := ms.(RateLimitedMergeScheduler); ok {
+		ans := store.NewRateLimitedDirectoryWrapper(d)
+		ans.SetRateLimiter(rlms.MergeRateLimiter(), store.IO_CONTEXT_TYPE_MERGE)
+		return ans
+	}
+	return d
+}
+
 // func (w *IndexWriter) fieldInfos(info *SegmentInfo) (infos FieldInfos, err error) {
 // 	var cfsDir store.Directory
 // 	if info.IsCompoundFile() {
@@ -945,6 +964,10 @@ segments file, but does NOT bump segmentInfos.version.
 func (w *IndexWriter) checkpointNoSIS() (err error) {
 	w.Lock() // synchronized
 	defer w.Unlock()
+	return w._checkpointNoSIS()
+}
+
+func (w *IndexWriter) _checkpointNoSIS() (err error) {
 	w.changeCount++
 	return w.deleter.checkpoint(w.segmentInfos, false)
 }
@@ -999,7 +1022,7 @@ func (w *IndexWriter) publishFlushedSegment(newSegment *SegmentCommitInfo,
 	} else {
 		// Since we don't have a delete packet to apply we can get a new
 		// generation right away
-		nextGen = w.bufferedUpdatesStream.nextGen
+		nextGen = w.bufferedUpdatesStream.getNextGen()
 	}
 	if w.infoStream.IsEnabled("IW") {
 		w.infoStream.Message("IW", "publish sets newSegment delGen=%v seg=%v", nextGen, w.readerPool.segmentToString(newSegment))
@@ -1361,7 +1384,7 @@ func (w *IndexWriter) _maybeApplyDeletes(applyAllDeletes bool) error {
 		return w._applyAllDeletesAndUpdates()
 	} else if w.infoStream.IsEnabled("IW") {
 		w.infoStream.Message("IW", "don't apply deletes now delTermCount=%v bytesUsed=%v",
-			atomic.LoadInt32(&w.bufferedUpdatesStream.numTerms),
+			atomic.LoadInt32(&w.bufferedUpdatesStream._numTerms),
 			atomic.LoadInt64(&w.bufferedUpdatesStream.bytesUsed))
 	}
 	return nil
@@ -1380,7 +1403,7 @@ func (w *IndexWriter) _applyAllDeletesAndUpdates() error {
 		return err
 	}
 	if result.anyDeletes {
-		err = w.checkpoint()
+		err = w._checkpoint()
 		if err != nil {
 			return err
 		}
@@ -1403,7 +1426,7 @@ func (w *IndexWriter) _applyAllDeletesAndUpdates() error {
 				}
 			}
 		}
-		err = w.checkpoint()
+		err = w._checkpoint()
 		if err != nil {
 			return err
 		}
@@ -1421,6 +1444,20 @@ func (w *IndexWriter) merge(merge *OneMerge) error {
 	panic("not implemented yet")
 }
 
+/*
+Creates an output for a file of the given merge in the merge
+directory, so that its writes are rate limited by the merge
+scheduler.
+*/
+func (w *IndexWriter) createMergeOutput(merge *OneMerge, name string) (store.IndexOutput, error) {
+	// the size of merges is not estimated yet
+	info := &store.MergeInfo{
+		TotalDocCount:       merge.totalDocCount,
+		MergeMaxNumSegments: merge.maxNumSegments,
+	}
+	return w.mergeDirectory.CreateOutput(name, store.NewIOContextForMerge(info))
+}
+
 /*
 Checks whether this merge involves any segments already participating
 in a merge. If not, this merge is "registered", meaning we record
@@ -0,0 +1,87 @@
+package search
+
+// search/similarities/AfterEffect.java
+
+/*
+This type acts as the base for the first normalization of information
+gain in the DFR framework, Inf2 = 1 - Prob2.
+*/
+type AfterEffect interface {
+	// Returns the aftereffect score.
+	Score(stats *BasicStats, tfn float32) float32
+	// Returns an explanation for the score.
+	Explain(stats *BasicStats, tfn float32) Explanation
+	// Subclasses must override this method to return the code of the
+	// after effect formula. Refer to the original paper for the list.
+	String() string
+}
+
+// Implementation used when there is no aftereffect.
+type NoAfterEffect struct{}
+
+func NewNoAfterEffect() *NoAfterEffect {
+	return &NoAfterEffect{}
+}
+
+func (ae *NoAfterEffect) Score(stats *BasicStats, tfn float32) float32 {
+	return 1
+}
+
+func (ae *NoAfterEffect) Explain(stats *BasicStats, tfn float32) Explanation {
+	return newExplanation(1, "no aftereffect")
+}
+
+func (ae *NoAfterEffect) String() string {
+	return ""
+}
+
+// search/similarities/AfterEffectB.java
+
+// Model of the information gain based on the ratio of two Bernoulli
+// processes.
+type AfterEffectB struct{}
+
+func NewAfterEffectB() *AfterEffectB {
+	return &AfterEffectB{}
+}
+
+func (ae *AfterEffectB) Score(stats *BasicStats, tfn float32) float32 {
+	F := float32(stats.TotalTermFreq() + 1)
+	n := float32(stats.DocFreq() + 1)
+	return (F + 1) / (n * (tfn + 1))
+}
+
+func (ae *AfterEffectB) Explain(stats *BasicStats, tfn float32) Explanation {
+	result := newExplanation(ae.Score(stats, tfn), "AfterEffectB, computed from: ")
+	result.addDetail(newExplanation(tfn, "tfn"))
+	result.addDetail(newExplanation(float32(stats.TotalTermFreq()), "totalTermFreq"))
+	result.addDetail(newExplanation(float32(stats.DocFreq()), "docFreq"))
+	return result
+}
+
+func (ae *AfterEffectB) String() string {
+	return "B"
+}
+
+// search/similarities/AfterEffectL.java
+
+// Model of the information gain based on Laplace's law of succession.
+type AfterEffectL struct{}
+
+func NewAfterEffectL() *AfterEffectL {
+	return &AfterEffectL{}
+}
+
+func (ae *AfterEffectL) Score(stats *BasicStats, tfn float32) float32 {
+	return 1 / (tfn + 1)
+}
+
+func (ae *AfterEffectL) Explain(stats *BasicStats, tfn float32) Explanation {
+	result := newExplanation(ae.Score(stats, tfn), "AfterEffectL, computed from: ")
+	result.addDetail(newExplanation(tfn, "tfn"))
+	return result
+}
+
+func (ae *AfterEffectL) String() string {
+	return "L"
+}
@@ -0,0 +1,251 @@
+package search
+
+import (
+	"math"
+)
+
+// search/similarities/BasicModel.java
+
+/*
+This type acts as the base for the basic model implementations used
+in the DFR framework. Basic models compute the informative content
+Inf1 = -log2Prob1.
+*/
+type BasicModel interface {
+	// Returns the informative content score.
+	Score(stats *BasicStats, tfn float32) float32
+	/*
+		Returns an explanation for the score. Most basic models use the
+		number of documents and the total term frequency to compute Inf1.
+		explainBasicModel() returns an explanation that includes both
+		of them; models that use other statistics must provide their own
+		explanation.
+	*/
+	Explain(stats *BasicStats, tfn float32) Explanation
+	// Subclasses must override this method to return the code of the
+	// basic model formula. Refer to the original paper for the list.
+	String() string
+}
+
+func explainBasicModel(name string, model BasicModel, stats *BasicStats, tfn float32) Explanation {
+	result := newExplanation(model.Score(stats, tfn), name+", computed from: ")
+	result.addDetail(newExplanation(tfn, "tfn"))
+	result.addDetail(newExplanation(float32(stats.NumberOfDocuments()), "numberOfDocuments"))
+	result.addDetail(newExplanation(float32(stats.TotalTermFreq()), "totalTermFreq"))
+	return result
+}
+
+// search/similarities/BasicModelBE.java
+
+/*
+Limiting form of the Bose-Einstein model. The formula used in Lucene
+differs slightly from the one in the original paper: F is increased
+by tfn+1 and N is increased by F.
+*/
+type BasicModelBE struct{}
+
+func NewBasicModelBE() *BasicModelBE {
+	return &BasicModelBE{}
+}
+
+func (m *BasicModelBE) Score(stats *BasicStats, tfn float32) float32 {
+	F := float64(stats.TotalTermFreq()) + 1 + float64(tfn)
+	// approximation only holds true when F << N, so we use N += F
+	N := F + float64(stats.NumberOfDocuments())
+	return float32(-log2((N-1)*math.E) +
+		m.f(N+F-1, N+F-float64(tfn)-2) - m.f(F, F-float64(tfn)))
+}
+
+// The f helper function defined for B_E.
+func (m *BasicModelBE) f(n, k float64) float64 {
+	return (k+0.5)*log2(n/k) + (n-k)*log2(n)
+}
+
+func (m *BasicModelBE) Explain(stats *BasicStats, tfn float32) Explanation {
+	return explainBasicModel("BasicModelBE", m, stats, tfn)
+}
+
+func (m *BasicModelBE) String() string {
+	return "Be"
+}
+
+// search/similarities/BasicModelD.java
+
+/*
+Implements the approximation of the binomial model with the
+divergence for DFR. The formula used in Lucene differs slightly from
+the one in the original paper: to avoid underflow for small values of
+N and F, N is increased by 1 and F is always increased by tfn+1.
+
+WARNING: for terms that do not meet the expected random distribution
+(e.g. stopwords), this model may give poor performance, such as
+abnormally high scores for low tf values.
+*/
+type BasicModelD struct{}
+
+func NewBasicModelD() *BasicModelD {
+	return &BasicModelD{}
+}
+
+func (m *BasicModelD) Score(stats *BasicStats, tfn float32) float32 {
+	// we have to ensure phi is always < 1 for tiny TTF values,
+	// otherwise nphi can go negative, resulting in NaN. cleanest way
+	// is to unconditionally always add tfn to totalTermFreq to create
+	// a 'normalized' F.
+	F := float64(stats.TotalTermFreq()) + 1 + float64(tfn)
+	phi := float64(tfn) / F
+	nphi := 1 - phi
+	p := 1.0 / float64(stats.NumberOfDocuments()+1)
+	D := phi*log2(phi/p) + nphi*log2(nphi/(1-p))
+	return float32(D*F + 0.5*log2(1+2*math.Pi*float64(tfn)*nphi))
+}
+
+func (m *BasicModelD) Explain(stats *BasicStats, tfn float32) Explanation {
+	return explainBasicModel("BasicModelD", m, stats, tfn)
+}
+
+func (m *BasicModelD) String() string {
+	return "D"
+}
+
+// search/similarities/BasicModelG.java
+
+/*
+Geometric as limiting form of the Bose-Einstein model. The formula
+used in Lucene differs slightly from the one in the original paper: F
+is increased by 1 and N is increased by F.
+*/
+type BasicModelG struct{}
+
+func NewBasicModelG() *BasicModelG {
+	return &BasicModelG{}
+}
+
+func (m *BasicModelG) Score(stats *BasicStats, tfn float32) float32 {
+	// just like in BE, approximation only holds true when F << N, so
+	// we use lambda = F / (N + F)
+	F := float64(stats.TotalTermFreq() + 1)
+	N := float64(stats.NumberOfDocuments())
+	lambda := F / (N + F)
+	// -log(1 / (lambda + 1)) -> log(lambda + 1)
+	return float32(log2(lambda+1) + float64(tfn)*log2((1+lambda)/lambda))
+}
+
+func (m *BasicModelG) Explain(stats *BasicStats, tfn float32) Explanation {
+	return explainBasicModel("BasicModelG", m, stats, tfn)
+}
+
+func (m *BasicModelG) String() string {
+	return "G"
+}
+
+// search/similarities/BasicModelIF.java
+
+// An approximation of the I(ne) model.
+type BasicModelIF struct{}
+
+func NewBasicModelIF() *BasicModelIF {
+	return &BasicModelIF{}
+}
+
+func (m *BasicModelIF) Score(stats *BasicStats, tfn float32) float32 {
+	N := float64(stats.NumberOfDocuments())
+	F := float64(stats.TotalTermFreq())
+	return tfn * float32(log2(1+(N+1)/(F+0.5)))
+}
+
+func (m *BasicModelIF) Explain(stats *BasicStats, tfn float32) Explanation {
+	return explainBasicModel("BasicModelIF", m, stats, tfn)
+}
+
+func (m *BasicModelIF) String() string {
+	return "I(F)"
+}
+
+// search/similarities/BasicModelIn.java
+
+// The basic tf-idf model of randomness.
+type BasicModelIn struct{}
+
+func NewBasicModelIn() *BasicModelIn {
+	return &BasicModelIn{}
+}
+
+func (m *BasicModelIn) Score(stats *BasicStats, tfn float32) float32 {
+	N := float64(stats.NumberOfDocuments())
+	n := float64(stats.DocFreq())
+	return tfn * float32(log2((N+1)/(n+0.5)))
+}
+
+func (m *BasicModelIn) Explain(stats *BasicStats, tfn float32) Explanation {
+	result := newExplanation(m.Score(stats, tfn), "BasicModelIn, computed from: ")
+	result.addDetail(newExplanation(tfn, "tfn"))
+	result.addDetail(newExplanation(float32(stats.NumberOfDocuments()), "numberOfDocuments"))
+	result.addDetail(newExplanation(float32(stats.DocFreq()), "docFreq"))
+	return result
+}
+
+func (m *BasicModelIn) String() string {
+	return "I(n)"
+}
+
+// search/similarities/BasicModelIne.java
+
+/*
+Tf-idf model of randomness, based on a mixture of Poisson and inverse
+document frequency.
+*/
+type BasicModelIne struct{}
+
+func NewBasicModelIne() *BasicModelIne {
+	return &BasicModelIne{}
+}
+
+func (m *BasicModelIne) Score(stats *BasicStats, tfn float32) float32 {
+	N := float64(stats.NumberOfDocuments())
+	F := float64(stats.TotalTermFreq())
+	ne := N * (1 - math.Pow((N-1)/N, F))
+	return tfn * float32(log2((N+1)/(ne+0.5)))
+}
+
+func (m *BasicModelIne) Explain(stats *BasicStats, tfn float32) Explanation {
+	return explainBasicModel("BasicModelIne", m, stats, tfn)
+}
+
+func (m *BasicModelIne) String() string {
+	return "I(ne)"
+}
+
+// search/similarities/BasicModelP.java
+
+/*
+Implements the Poisson approximation for the binomial model for DFR.
+
+WARNING: for terms that do not meet the expected random distribution
+(e.g. stopwords), this model may give poor performance, such as
+abnormally high scores for low tf values.
+*/
+type BasicModelP struct{}
+
+// log2(Math.E), precomputed.
+var LOG2_E = log2(math.E)
+
+func NewBasicModelP() *BasicModelP {
+	return &BasicModelP{}
+}
+
+func (m *BasicModelP) Score(stats *BasicStats, tfn float32) float32 {
+	lambda := float64(stats.TotalTermFreq()+1) / float64(stats.NumberOfDocuments()+1)
+	t := float64(tfn)
+	return float32(t*log2(t/lambda) +
+		(lambda+1/(12*t)-t)*LOG2_E +
+		0.5*log2(2*math.Pi*t))
+}
+
+func (m *BasicModelP) Explain(stats *BasicStats, tfn float32) Explanation {
+	return explainBasicModel("BasicModelP", m, stats, tfn)
+}
+
+func (m *BasicModelP) String() string {
+	return "P"
+}
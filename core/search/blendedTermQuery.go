@@ -0,0 +1,219 @@
+package search
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/balzaczyy/golucene/core/index"
+)
+
+// search/BlendedTermQuery.java
+
+/*
+A Query that blends index statistics across multiple terms. This is
+particularly useful when several fields are queried for the same
+text: as fields have different statistics, a rare term in a small
+field would otherwise outscore a common term in the field that
+matters, e.g. for "best fields" matching of multi-field query
+builders.
+
+The query is rewritten to one TermQuery per term, each using the
+same blended statistics: the maximum document frequency and the sum
+of total term frequencies of all terms. The term queries are then
+combined by a BlendedRewriteMethod, DISJUNCTION_MAX_REWRITE by
+default.
+*/
+type BlendedTermQuery struct {
+	*AbstractQuery
+	terms         []*index.Term
+	boosts        []float32
+	contexts      []*index.TermContext
+	rewriteMethod BlendedRewriteMethod
+}
+
+func NewBlendedTermQuery() *BlendedTermQuery {
+	ans := &BlendedTermQuery{rewriteMethod: DISJUNCTION_MAX_REWRITE}
+	ans.AbstractQuery = NewAbstractQuery(ans)
+	return ans
+}
+
+// Adds a term to the query, with a boost of 1.
+func (q *BlendedTermQuery) Add(term *index.Term) {
+	q.AddWithBoost(term, 1)
+}
+
+/*
+Adds a term with the given boost. The boost is applied to the term
+query the term is rewritten to, so it weighs this term against the
+others after statistics were blended.
+*/
+func (q *BlendedTermQuery) AddWithBoost(term *index.Term, boost float32) {
+	q.AddWithContext(term, boost, nil)
+}
+
+/*
+Expert: adds a term with the given boost and per-reader term states,
+which spares a lookup of the term if the states were built against
+the same top-level reader context the query is rewritten with.
+*/
+func (q *BlendedTermQuery) AddWithContext(term *index.Term, boost float32, context *index.TermContext) {
+	assert2(len(q.terms) < maxClauseCount, "too many terms")
+	q.terms = append(q.terms, term)
+	q.boosts = append(q.boosts, boost)
+	q.contexts = append(q.contexts, context)
+}
+
+/*
+Sets the BlendedRewriteMethod used to combine the term queries the
+terms are rewritten to.
+*/
+func (q *BlendedTermQuery) SetRewriteMethod(m BlendedRewriteMethod) {
+	assert(m != nil)
+	q.rewriteMethod = m
+}
+
+// Returns the terms of this query.
+func (q *BlendedTermQuery) Terms() []*index.Term {
+	return q.terms
+}
+
+func (q *BlendedTermQuery) Rewrite(reader index.IndexReader) Query {
+	ctx := reader.Context()
+	contexts := make([]*index.TermContext, len(q.contexts))
+	for i, c := range q.contexts {
+		if c == nil || c.TopReaderContext != ctx {
+			var err error
+			if c, err = index.NewTermContextFromTerm(ctx, q.terms[i]); err != nil {
+				panic(err) // Rewrite() has no error to return
+			}
+		}
+		contexts[i] = c
+	}
+
+	// compute aggregated doc freq and total term freq: df will be the
+	// max of all doc freqs, ttf will be the sum of all total term freqs
+	df, ttf := 0, int64(0)
+	for _, c := range contexts {
+		if c.DocFreq > df {
+			df = c.DocFreq
+		}
+		if c.TotalTermFreq == -1 {
+			ttf = -1
+		} else if ttf != -1 {
+			ttf += c.TotalTermFreq
+		}
+	}
+
+	termQueries := make([]Query, len(q.terms))
+	for i, term := range q.terms {
+		tq := NewTermQueryWithStates(term, adjustFrequencies(ctx, contexts[i], df, ttf))
+		tq.SetBoost(q.boosts[i])
+		termQueries[i] = tq
+	}
+	rewritten := q.rewriteMethod.Rewrite(termQueries)
+	rewritten.SetBoost(q.boost)
+	return rewritten
+}
+
+/*
+Returns a copy of the given TermContext whose statistics are replaced
+by the given artificial ones.
+*/
+func adjustFrequencies(readerContext index.IndexReaderContext,
+	ctx *index.TermContext, artificialDf int, artificialTtf int64) *index.TermContext {
+
+	n := 1
+	if leaves := readerContext.Leaves(); leaves != nil {
+		n = len(leaves)
+	}
+	newCtx := index.NewTermContext(readerContext)
+	for i := 0; i < n; i++ {
+		if termState := ctx.State(i); termState != nil {
+			newCtx.Register(termState, i)
+		}
+	}
+	newCtx.AccumulateStatistics(artificialDf, artificialTtf)
+	return newCtx
+}
+
+func (q *BlendedTermQuery) ExtractTerms(terms *index.TermSet) {
+	for _, t := range q.terms {
+		terms.Add(t)
+	}
+}
+
+func (q *BlendedTermQuery) Visit(visitor QueryVisitor) {
+	sub := visitor.SubVisitor(SHOULD, q)
+	if sub == nil {
+		return
+	}
+	for _, t := range q.terms {
+		if sub.AcceptField(t.Field) {
+			sub.ConsumeTerms(q, t)
+		}
+	}
+}
+
+func (q *BlendedTermQuery) ToString(field string) string {
+	var buf bytes.Buffer
+	buf.WriteString("Blended(")
+	for i, t := range q.terms {
+		if i > 0 {
+			buf.WriteRune(' ')
+		}
+		tq := NewTermQuery(t)
+		tq.SetBoost(q.boosts[i])
+		buf.WriteString(tq.ToString(field))
+	}
+	buf.WriteRune(')')
+	if q.boost != 1 {
+		fmt.Fprintf(&buf, "^%v", q.boost)
+	}
+	return buf.String()
+}
+
+/*
+A BlendedRewriteMethod defines how queries for individual terms
+should be merged.
+*/
+type BlendedRewriteMethod interface {
+	// Merges the given sub queries.
+	Rewrite(subQueries []Query) Query
+}
+
+/*
+A BlendedRewriteMethod that adds all sub queries to a BooleanQuery
+which has no coord factor. As a consequence, sub queries that match
+are summed up.
+*/
+var BOOLEAN_REWRITE = booleanRewrite{}
+
+type booleanRewrite struct{}
+
+func (r booleanRewrite) Rewrite(subQueries []Query) Query {
+	merged := NewBooleanQueryDisableCoord(true)
+	for _, q := range subQueries {
+		merged.Add(q, SHOULD)
+	}
+	return merged
+}
+
+/*
+A BlendedRewriteMethod that creates a DisjunctionMaxQuery out of the
+sub queries. This rewrite method is useful when having a few fields
+that match the same kind of content.
+*/
+type DisjunctionMaxRewrite struct {
+	tieBreakerMultiplier float32
+}
+
+// Creates a DisjunctionMaxRewrite with the given tie breaker multiplier.
+func NewDisjunctionMaxRewrite(tieBreakerMultiplier float32) *DisjunctionMaxRewrite {
+	return &DisjunctionMaxRewrite{tieBreakerMultiplier}
+}
+
+func (r *DisjunctionMaxRewrite) Rewrite(subQueries []Query) Query {
+	return NewDisjunctionMaxQuery(subQueries, r.tieBreakerMultiplier)
+}
+
+// DisjunctionMaxRewrite instance with a tie-breaker of 0.01.
+var DISJUNCTION_MAX_REWRITE = NewDisjunctionMaxRewrite(0.01)
@@ -0,0 +1,105 @@
+package search
+
+import (
+	std "github.com/balzaczyy/golucene/analysis/standard"
+	docu "github.com/balzaczyy/golucene/core/document"
+	"github.com/balzaczyy/golucene/core/index"
+	"github.com/balzaczyy/golucene/core/store"
+	"github.com/balzaczyy/golucene/core/util"
+	"math"
+	"testing"
+)
+
+func TestBlendedTermQuery(t *testing.T) {
+	dir, err := store.OpenFSDirectory(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := index.NewIndexWriter(dir, index.NewIndexWriterConfig(util.VERSION_LATEST, std.NewStandardAnalyzer()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	add := func(title, body string) {
+		doc := docu.NewDocument()
+		doc.Add(docu.NewTextFieldFromString("title", title, docu.STORE_NO))
+		doc.Add(docu.NewTextFieldFromString("body", body, docu.STORE_NO))
+		if err := w.AddDocument(doc.Fields()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// apple is rare in titles, but common in bodies
+	add("apple pie", "banana split")
+	add("banana split", "apple pie")
+	add("apple pie", "apple pie")
+	for i := 0; i < 10; i++ {
+		add("cherry tart", "apple tart")
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	r, err := index.OpenDirectoryReader(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	ss := NewIndexSearcher(r)
+
+	scores := func(q Query) map[int]float32 {
+		c := &freqCollector{freqs: make(map[int]int), scores: make(map[int]float32)}
+		if err := ss.SearchCollector(q, c); err != nil {
+			t.Fatal(err)
+		}
+		return c.scores
+	}
+	title, body := index.NewTerm("title", "apple"), index.NewTerm("body", "apple")
+
+	// without blending, the rare title match wins
+	dmq := NewDisjunctionMaxQuery([]Query{NewTermQuery(title), NewTermQuery(body)}, 0.1)
+	assertEquals(t, "(title:apple | body:apple)~0.1", dmq.String())
+	plain := scores(dmq)
+	assertEquals(t, 13, len(plain))
+	if plain[0] <= plain[1] {
+		t.Errorf("Expected title match to outscore body match, but was %v", plain)
+	}
+	// the max of both fields plus the tie breaker times the other
+	expected := plain[0] + 0.1*plain[1]
+	if math.Abs(float64(plain[2]-expected)) > 1e-6 {
+		t.Errorf("Expected score %v, but was %v", expected, plain[2])
+	}
+	exp, err := ss.Explain(dmq, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !exp.IsMatch() || math.Abs(float64(exp.Value()-plain[2])) > 1e-6 {
+		t.Errorf("Expected explanation of score %v, but was %v", plain[2], exp)
+	}
+
+	// blended statistics score both fields the same
+	q := NewBlendedTermQuery()
+	q.Add(title)
+	q.Add(body)
+	assertEquals(t, "Blended(title:apple body:apple)", q.String())
+	blended := scores(q)
+	assertEquals(t, 13, len(blended))
+	assertEquals(t, blended[0], blended[1])
+	expected = blended[0] * 1.01
+	if math.Abs(float64(blended[2]-expected)) > 1e-6 {
+		t.Errorf("Expected score %v, but was %v", expected, blended[2])
+	}
+
+	q.SetRewriteMethod(BOOLEAN_REWRITE)
+	summed := scores(q)
+	assertEquals(t, blended[0], summed[0])
+	if math.Abs(float64(summed[2]-2*summed[0])) > 1e-6 {
+		t.Errorf("Expected summed score %v, but was %v", 2*summed[0], summed[2])
+	}
+
+	// boosts weigh terms after blending
+	q = NewBlendedTermQuery()
+	q.AddWithBoost(title, 2)
+	q.Add(body)
+	boosted := scores(q)
+	if boosted[0] <= boosted[1] {
+		t.Errorf("Expected boosted title match to win, but was %v", boosted)
+	}
+}
@@ -0,0 +1,54 @@
+package search
+
+import (
+	"github.com/balzaczyy/golucene/core/index"
+	"github.com/balzaczyy/golucene/core/store"
+	"testing"
+)
+
+func TestBM25Search(t *testing.T) {
+	d, err := store.OpenFSDirectory("testdata/belfrysample")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := index.OpenDirectoryReader(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ss := NewIndexSearcher(r) // BM25 by default
+	q := NewTermQuery(index.NewTerm("content", "bat"))
+	docs, err := ss.SearchTop(q, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertEquals(t, 8, docs.TotalHits)
+	doc, err := r.Document(docs.ScoreDocs[0].Doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the longest doc mentions 'bat' most often, which now wins over
+	// the shorter 'Bat recycling'
+	assertEquals(t, "Feeding your bat", doc.Get("title"))
+
+	// idf(8, 8) * tfNorm(freq=15, fieldLength=256, avgFieldLength=191.625)
+	exp, err := ss.Explain(q, docs.ScoreDocs[0].Doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := exp.Value(); v != docs.ScoreDocs[0].Score || v < 0.1142 || v > 0.1144 {
+		t.Errorf("Expected score around 0.1143, but was %v (%v)", v, docs.ScoreDocs[0].Score)
+	}
+}
+
+func TestBM25NormCompatibility(t *testing.T) {
+	bm25, classic := NewBM25Similarity(), NewDefaultSimilarity()
+	for _, length := range []int{1, 4, 10, 100, 1000} {
+		state := index.NewFieldInvertState("f", 0, length, 0, 0, 1)
+		norm := bm25.ComputeNorm(state)
+		assertEquals(t, classic.ComputeNorm(state), norm)
+		if decoded := bm25.decodeNormValue(byte(norm)); decoded < float32(length)*0.7 || decoded > float32(length)*1.3 {
+			t.Errorf("Expected field length around %v, but was %v", length, decoded)
+		}
+	}
+}
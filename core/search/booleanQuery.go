@@ -2,6 +2,7 @@ package search
 
 import (
 	"bytes"
+	"fmt"
 	"github.com/balzaczyy/golucene/core/index"
 	"github.com/balzaczyy/golucene/core/util"
 )
@@ -36,6 +37,33 @@ func (q *BooleanQuery) AddClause(clause *BooleanClause) {
 	q.clauses = append(q.clauses, clause)
 }
 
+// Returns the list of clauses in this query.
+func (q *BooleanQuery) Clauses() []*BooleanClause {
+	return q.clauses
+}
+
+/*
+Specifies a minimum number of the optional BooleanClauses which must
+be satisfied.
+
+By default no optional clauses are necessary for a match (unless
+there are no required clauses). If this method is used, then the
+specified number of clauses is required.
+
+Use of this method is totally independent of specifying that any
+specific clauses are required (or prohibited). This number will only
+be compared against the number of matching optional clauses.
+*/
+func (q *BooleanQuery) SetMinimumNumberShouldMatch(min int) {
+	q.minNrShouldMatch = min
+}
+
+// Gets the minimum number of the optional BooleanClauses which must be
+// satisfied.
+func (q *BooleanQuery) MinimumNumberShouldMatch() int {
+	return q.minNrShouldMatch
+}
+
 type BooleanWeight struct {
 	owner        *BooleanQuery
 	similarity   Similarity
@@ -102,7 +130,10 @@ func (w *BooleanWeight) BulkScorer(context *index.AtomicReaderContext,
 	scoreDocsInOrder bool, acceptDocs util.Bits) (BulkScorer, error) {
 
 	if scoreDocsInOrder || w.owner.minNrShouldMatch > 1 {
-		panic("not implemented yet")
+		// TODO: (LUCENE-4872) in some cases BooleanScorer may be faster
+		// for minNrShouldMatch too, even if the BooleanScorer2 would
+		// score docs in order
+		return newWeightImpl(w).BulkScorer(context, scoreDocsInOrder, acceptDocs)
 	}
 
 	var prohibited, optional []BulkScorer
@@ -117,7 +148,9 @@ func (w *BooleanWeight) BulkScorer(context *index.AtomicReaderContext,
 				return nil, nil
 			}
 		} else if c.IsRequired() {
-			panic("not implemented yet")
+			// TODO: there are some cases where BooleanScorer would handle
+			// conjunctions faster than BooleanScorer2...
+			return newWeightImpl(w).BulkScorer(context, scoreDocsInOrder, acceptDocs)
 		} else if c.IsProhibited() {
 			prohibited = append(prohibited, subScorer)
 		} else {
@@ -128,6 +161,46 @@ func (w *BooleanWeight) BulkScorer(context *index.AtomicReaderContext,
 	return newBooleanScorer(w, w.disableCoord, w.owner.minNrShouldMatch, optional, prohibited, w.maxCoord), nil
 }
 
+func (w *BooleanWeight) Scorer(context *index.AtomicReaderContext,
+	acceptDocs util.Bits) (Scorer, error) {
+
+	var required, prohibited, optional []Scorer
+	for i, subWeight := range w.weights {
+		c := w.owner.clauses[i]
+		spi, ok := subWeight.(WeightImplSPI)
+		assert2(ok, "%v does not provide a Scorer", subWeight)
+		subScorer, err := spi.Scorer(context, acceptDocs)
+		if err != nil {
+			return nil, err
+		}
+		if subScorer == nil {
+			if c.IsRequired() {
+				return nil, nil
+			}
+		} else if c.IsRequired() {
+			required = append(required, subScorer)
+		} else if c.IsProhibited() {
+			prohibited = append(prohibited, subScorer)
+		} else {
+			optional = append(optional, subScorer)
+		}
+	}
+
+	if len(required) == 0 && len(optional) == 0 {
+		// no required and optional clauses.
+		return nil, nil
+	} else if len(optional) < w.owner.minNrShouldMatch {
+		// either >1 req scorer, or there are 0 req scorers and at least 1
+		// optional scorer. Therefore if there are not enough optional
+		// scorers no documents will be matched by the query
+		return nil, nil
+	}
+
+	// Return a BooleanScorer2
+	return newBooleanScorer2(w, w.disableCoord, w.owner.minNrShouldMatch,
+		required, prohibited, optional, w.maxCoord), nil
+}
+
 func (w *BooleanWeight) IsScoresDocsOutOfOrder() bool {
 	if w.owner.minNrShouldMatch > 1 {
 		// BS2 (in-order) will be used by scorer()
@@ -156,12 +229,26 @@ func (q *BooleanQuery) CreateWeight(searcher *IndexSearcher) (Weight, error) {
 }
 
 func (q *BooleanQuery) Rewrite(reader index.IndexReader) Query {
-	if q.minNrShouldMatch == 0 && len(q.clauses) == 1 {
-		panic("not implemented yet")
+	if q.minNrShouldMatch == 0 && len(q.clauses) == 1 { // optimize 1-clause queries
+		if c := q.clauses[0]; !c.IsProhibited() { // just return clause
+			query := c.query.Rewrite(reader) // rewrite first
+			if q.Boost() == 1 {
+				return query
+			}
+			// Since the BooleanQuery only has 1 clause, the BooleanQuery
+			// will be written out. Therefore the rewritten Query's boost
+			// must incorporate both the clause's boost, and the boost of
+			// the BooleanQuery itself. Queries can't be cloned yet, so
+			// this is only done when the clause rewrote to a new query.
+			if query != c.query {
+				query.SetBoost(q.Boost() * query.Boost())
+				return query
+			}
+		}
 	}
 
 	var clone *BooleanQuery // recursively rewrite
-	for _, c := range q.clauses {
+	for i, c := range q.clauses {
 		if query := c.query.Rewrite(reader); query != c.query {
 			// clause rewrote: must clone
 			if clone == nil {
@@ -169,9 +256,9 @@ func (q *BooleanQuery) Rewrite(reader index.IndexReader) Query {
 				// initialize it if a rewritten clause differs from the
 				// original clause (and hasn't been initialized already). If
 				// nothing difers, the clone isn't needlessly created
-				panic("not implemented yet")
+				clone = q.clone()
 			}
-			panic("not implemented yet")
+			clone.clauses[i] = NewBooleanClause(query, c.occur)
 		}
 	}
 	if clone != nil {
@@ -180,6 +267,27 @@ func (q *BooleanQuery) Rewrite(reader index.IndexReader) Query {
 	return q
 }
 
+func (q *BooleanQuery) clone() *BooleanQuery {
+	ans := NewBooleanQueryDisableCoord(q.disableCoord)
+	ans.SetBoost(q.Boost())
+	ans.minNrShouldMatch = q.minNrShouldMatch
+	ans.clauses = make([]*BooleanClause, len(q.clauses))
+	copy(ans.clauses, q.clauses)
+	return ans
+}
+
+func (q *BooleanQuery) ExtractTerms(terms *index.TermSet) {
+	for _, c := range q.clauses {
+		if c.occur != MUST_NOT {
+			c.query.ExtractTerms(terms)
+		}
+	}
+}
+
+func (q *BooleanQuery) Visit(visitor QueryVisitor) {
+	visitClauses(visitor, q, q.clauses)
+}
+
 func (q *BooleanQuery) ToString(field string) string {
 	var buf bytes.Buffer
 	needParens := q.Boost() != 1 || q.minNrShouldMatch > 0
@@ -216,11 +324,11 @@ func (q *BooleanQuery) ToString(field string) string {
 	}
 
 	if q.minNrShouldMatch > 0 {
-		panic("not implemented yet")
+		fmt.Fprintf(&buf, "~%v", q.minNrShouldMatch)
 	}
 
 	if q.Boost() != 1 {
-		panic("not implemented yet")
+		fmt.Fprintf(&buf, "^%v", q.Boost())
 	}
 
 	return buf.String()
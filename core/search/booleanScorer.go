@@ -1,6 +1,8 @@
 package search
 
 import (
+	"bytes"
+	"fmt"
 	"github.com/balzaczyy/golucene/core/index"
 )
 
@@ -168,8 +170,16 @@ func (s *BooleanScorer) ScoreAndCollectUpto(collector Collector, max int) (more
 			// check prohibited & required
 			if (s.current.bits & PROHIBITED_MASK) == 0 {
 
+				// NOTE: max is always NO_MORE_DOCS today, as we never
+				// embed a BooleanScorer inside another, but an outside
+				// app could pass a different max so we must check it:
 				if s.current.doc >= max {
-					panic("not implemented yet")
+					// requeue the bucket for the next call
+					tmp := s.current
+					s.current = s.current.next
+					tmp.next = s.bucketTable.first
+					s.bucketTable.first = tmp
+					continue
 				}
 
 				if s.current.coord >= s.minNrShouldMatch {
@@ -186,7 +196,9 @@ func (s *BooleanScorer) ScoreAndCollectUpto(collector Collector, max int) (more
 		}
 
 		if s.bucketTable.first != nil {
-			panic("not implemented yet")
+			// some buckets are beyond max; keep them all queued
+			s.current = s.bucketTable.first
+			return true, nil
 		}
 
 		// refill the queue
@@ -210,7 +222,13 @@ func (s *BooleanScorer) ScoreAndCollectUpto(collector Collector, max int) (more
 }
 
 func (s *BooleanScorer) String() string {
-	panic("not implemented yet")
+	var buf bytes.Buffer
+	buf.WriteString("boolean(")
+	for sub := s.scorers; sub != nil; sub = sub.next {
+		fmt.Fprintf(&buf, "%v ", sub.scorer)
+	}
+	buf.WriteString(")")
+	return buf.String()
 }
 
 type FakeScorer struct {
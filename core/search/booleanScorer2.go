@@ -0,0 +1,831 @@
+package search
+
+import (
+	"fmt"
+	. "github.com/balzaczyy/golucene/core/search/model"
+	"math"
+)
+
+// search/BooleanScorer2.java
+
+/*
+See the description in BooleanScorer comparing BooleanScorer and
+BooleanScorer2.
+
+An alternative to BooleanScorer that also allows a minimum number of
+optional scorers that should match.
+
+Implements skipTo(), and has no limitations on the numbers of added
+scorers.
+
+Uses ConjunctionScorer, DisjunctionSumScorer, ReqOptSumScorer and
+ReqExclScorer.
+*/
+type BooleanScorer2 struct {
+	*abstractScorer
+	requiredScorers   []Scorer
+	optionalScorers   []Scorer
+	prohibitedScorers []Scorer
+
+	coordinator       *coordinator
+	countingSumScorer Scorer // the scorer to which all scoring will be delegated
+	minNrShouldMatch  int    // the number of optional scorers that should match
+	doc               int
+}
+
+type coordinator struct {
+	coordFactors []float32
+	nrMatchers   int // to be increased by score() of match counting scorers.
+}
+
+/*
+Creates a BooleanScorer2 with the given lists of required, prohibited
+and optional scorers. In no required scorers are added, at least one
+of the optional scorers will have to match during the search.
+
+minNrShouldMatch is the minimum number of optional added scorers that
+should match during the search. In case no required scorers are
+added, at least one of the optional scorers will have to match during
+the search.
+*/
+func newBooleanScorer2(weight *BooleanWeight, disableCoord bool, minNrShouldMatch int,
+	required, prohibited, optional []Scorer, maxCoord int) *BooleanScorer2 {
+
+	assert2(minNrShouldMatch >= 0, "Minimum number of optional scorers should not be negative")
+	ans := &BooleanScorer2{
+		requiredScorers:   required,
+		optionalScorers:   optional,
+		prohibitedScorers: prohibited,
+		minNrShouldMatch:  minNrShouldMatch,
+		doc:               -1,
+	}
+	ans.abstractScorer = newScorer(ans, weight)
+	ans.coordinator = &coordinator{
+		coordFactors: make([]float32, len(optional)+len(required)+1),
+	}
+	for i := range ans.coordinator.coordFactors {
+		if disableCoord {
+			ans.coordinator.coordFactors[i] = 1
+		} else {
+			ans.coordinator.coordFactors[i] = weight.coord(i, maxCoord)
+		}
+	}
+	ans.countingSumScorer = ans.makeCountingSumScorer()
+	return ans
+}
+
+/* Count a scorer as a single match. */
+type singleMatchScorer struct {
+	Scorer
+	coordinator   *coordinator
+	lastScoredDoc int
+	// Save the score of lastScoredDoc, so that we don't compute it more
+	// than once in score().
+	lastDocScore float32
+}
+
+func newSingleMatchScorer(scorer Scorer, c *coordinator) *singleMatchScorer {
+	return &singleMatchScorer{
+		Scorer:        scorer,
+		coordinator:   c,
+		lastScoredDoc: -1,
+		lastDocScore:  float32(math.NaN()),
+	}
+}
+
+func (s *singleMatchScorer) Score() (float32, error) {
+	if doc := s.DocId(); doc >= s.lastScoredDoc {
+		if doc > s.lastScoredDoc {
+			score, err := s.Scorer.Score()
+			if err != nil {
+				return 0, err
+			}
+			s.lastDocScore = score
+			s.lastScoredDoc = doc
+		}
+		s.coordinator.nrMatchers++
+	}
+	return s.lastDocScore, nil
+}
+
+func (s *singleMatchScorer) Freq() (int, error) {
+	return 1, nil
+}
+
+/* A DisjunctionSumScorer whose matchers are counted by the coordinator. */
+type countingDisjunctionSumScorer struct {
+	*DisjunctionSumScorer
+	coordinator *coordinator
+}
+
+func (s *countingDisjunctionSumScorer) Score() (float32, error) {
+	score, nrMatchers, err := s.sum()
+	if err != nil {
+		return 0, err
+	}
+	s.coordinator.nrMatchers += nrMatchers
+	return float32(score), nil
+}
+
+/*
+A countingDisjunctionSumScorer that skips the documents matched by
+less than minNrShouldMatch of its sub scorers.
+*/
+type minShouldMatchSumScorer struct {
+	*countingDisjunctionSumScorer
+	minNrShouldMatch int
+}
+
+func (s *minShouldMatchSumScorer) NextDoc() (int, error) {
+	doc, err := s.DisjunctionSumScorer.NextDoc()
+	return s.nextMinMatch(doc, err)
+}
+
+func (s *minShouldMatchSumScorer) Advance(target int) (int, error) {
+	doc, err := s.DisjunctionSumScorer.Advance(target)
+	return s.nextMinMatch(doc, err)
+}
+
+// Advances from doc to the first doc with enough matching sub scorers.
+func (s *minShouldMatchSumScorer) nextMinMatch(doc int, err error) (int, error) {
+	for err == nil && doc != NO_MORE_DOCS {
+		nrMatchers := 0
+		for _, scorer := range s.subScorers[:s.numScorers] {
+			if scorer.DocId() == doc {
+				nrMatchers++
+			}
+		}
+		if nrMatchers >= s.minNrShouldMatch {
+			return doc, nil
+		}
+		doc, err = s.DisjunctionSumScorer.NextDoc()
+	}
+	return doc, err
+}
+
+/* A ConjunctionScorer whose matchers are counted by the coordinator. */
+type countingConjunctionSumScorer struct {
+	*ConjunctionScorer
+	coordinator        *coordinator
+	requiredNrMatchers int
+	lastScoredDoc      int
+	// Save the score of lastScoredDoc, so that we don't compute it more
+	// than once in score().
+	lastDocScore float32
+}
+
+func (s *countingConjunctionSumScorer) Score() (float32, error) {
+	if doc := s.DocId(); doc >= s.lastScoredDoc {
+		if doc > s.lastScoredDoc {
+			score, err := s.ConjunctionScorer.Score()
+			if err != nil {
+				return 0, err
+			}
+			s.lastDocScore = score
+			s.lastScoredDoc = doc
+		}
+		s.coordinator.nrMatchers += s.requiredNrMatchers
+	}
+	// All scorers match, so defaultSimilarity super.score() always has
+	// 1 as the coordination factor. Therefore the sum of the scores of
+	// the requiredScorers is used as score.
+	return s.lastDocScore, nil
+}
+
+func (s *BooleanScorer2) countingDisjunctionSumScorer(scorers []Scorer, minNrShouldMatch int) Scorer {
+	// each scorer from the list counted as a single matcher
+	// we pass nil for coord since we coordinate ourselves and override
+	// score()
+	ans := &countingDisjunctionSumScorer{
+		newDisjunctionSumScorer(s.weight, scorers, nil), s.coordinator}
+	if minNrShouldMatch > 1 {
+		return &minShouldMatchSumScorer{ans, minNrShouldMatch}
+	}
+	return ans
+}
+
+func (s *BooleanScorer2) countingConjunctionSumScorer(requiredScorers []Scorer) Scorer {
+	// each scorer from the list counted as a single matcher
+	return &countingConjunctionSumScorer{
+		ConjunctionScorer:  newConjunctionScorer(s.weight, requiredScorers),
+		coordinator:        s.coordinator,
+		requiredNrMatchers: len(requiredScorers),
+		lastScoredDoc:      -1,
+		lastDocScore:       float32(math.NaN()),
+	}
+}
+
+func (s *BooleanScorer2) dualConjunctionSumScorer(req1, req2 Scorer) Scorer { // non counting
+	return newConjunctionScorer(s.weight, []Scorer{req1, req2})
+	// All scorers match, so defaultSimilarity always has 1 as the
+	// coordination factor. Therefore the sum of the scores of two
+	// scorers is used as score.
+}
+
+/*
+Returns the scorer to be used for match counting and score summing.
+Uses requiredScorers, optionalScorers and prohibitedScorers.
+*/
+func (s *BooleanScorer2) makeCountingSumScorer() Scorer { // each scorer counted as a single matcher
+	if len(s.requiredScorers) == 0 {
+		return s.makeCountingSumScorerNoReq()
+	}
+	return s.makeCountingSumScorerSomeReq()
+}
+
+func (s *BooleanScorer2) makeCountingSumScorerNoReq() Scorer { // No required scorers
+	// minNrShouldMatch optional scorers are required, but at least 1
+	nrOptRequired := s.minNrShouldMatch
+	if nrOptRequired < 1 {
+		nrOptRequired = 1
+	}
+	var requiredCountingSumScorer Scorer
+	if len(s.optionalScorers) > nrOptRequired {
+		requiredCountingSumScorer = s.countingDisjunctionSumScorer(s.optionalScorers, nrOptRequired)
+	} else if len(s.optionalScorers) == 1 {
+		requiredCountingSumScorer = newSingleMatchScorer(s.optionalScorers[0], s.coordinator)
+	} else {
+		requiredCountingSumScorer = s.countingConjunctionSumScorer(s.optionalScorers)
+	}
+	return s.addProhibitedScorers(requiredCountingSumScorer)
+}
+
+func (s *BooleanScorer2) makeCountingSumScorerSomeReq() Scorer { // At least one required scorer.
+	if len(s.optionalScorers) == s.minNrShouldMatch { // all optional scorers also required.
+		allReq := make([]Scorer, 0, len(s.requiredScorers)+len(s.optionalScorers))
+		allReq = append(allReq, s.requiredScorers...)
+		allReq = append(allReq, s.optionalScorers...)
+		return s.addProhibitedScorers(s.countingConjunctionSumScorer(allReq))
+	}
+	// optionalScorers.size() > minNrShouldMatch, and at least one
+	// required scorer
+	var requiredCountingSumScorer Scorer
+	if len(s.requiredScorers) == 1 {
+		requiredCountingSumScorer = newSingleMatchScorer(s.requiredScorers[0], s.coordinator)
+	} else {
+		requiredCountingSumScorer = s.countingConjunctionSumScorer(s.requiredScorers)
+	}
+	if s.minNrShouldMatch > 0 { // use a required disjunction scorer over the optional scorers
+		return s.addProhibitedScorers(
+			s.dualConjunctionSumScorer( // non counting
+				requiredCountingSumScorer,
+				s.countingDisjunctionSumScorer(s.optionalScorers, s.minNrShouldMatch)))
+	}
+	// minNrShouldMatch == 0
+	var optScorer Scorer
+	if len(s.optionalScorers) == 1 {
+		optScorer = newSingleMatchScorer(s.optionalScorers[0], s.coordinator)
+	} else {
+		// require 1 in combined, optional scorer.
+		optScorer = s.countingDisjunctionSumScorer(s.optionalScorers, 1)
+	}
+	return newReqOptSumScorer(s.addProhibitedScorers(requiredCountingSumScorer), optScorer)
+}
+
+/*
+Returns the scorer to be used for match counting and score summing.
+Uses the given required scorer and the prohibitedScorers.
+*/
+func (s *BooleanScorer2) addProhibitedScorers(requiredCountingSumScorer Scorer) Scorer {
+	switch len(s.prohibitedScorers) {
+	case 0:
+		return requiredCountingSumScorer // no prohibited
+	case 1:
+		return newReqExclScorer(requiredCountingSumScorer, s.prohibitedScorers[0])
+	default:
+		return newReqExclScorer(requiredCountingSumScorer,
+			newDisjunctionSumScorer(s.weight, s.prohibitedScorers, nil))
+	}
+}
+
+func (s *BooleanScorer2) DocId() int {
+	return s.doc
+}
+
+func (s *BooleanScorer2) NextDoc() (doc int, err error) {
+	doc, err = s.countingSumScorer.NextDoc()
+	s.doc = doc
+	return
+}
+
+func (s *BooleanScorer2) Score() (float32, error) {
+	s.coordinator.nrMatchers = 0
+	sum, err := s.countingSumScorer.Score()
+	if err != nil {
+		return 0, err
+	}
+	return sum * s.coordinator.coordFactors[s.coordinator.nrMatchers], nil
+}
+
+func (s *BooleanScorer2) Freq() (int, error) {
+	return s.countingSumScorer.Freq()
+}
+
+func (s *BooleanScorer2) Advance(target int) (doc int, err error) {
+	doc, err = s.countingSumScorer.Advance(target)
+	s.doc = doc
+	return
+}
+
+func (s *BooleanScorer2) String() string {
+	return fmt.Sprintf("BooleanScorer2(%v)", s.countingSumScorer)
+}
+
+// search/ConjunctionScorer.java
+
+/* Scorer for conjunctions, sets of queries, all of which are required. */
+type ConjunctionScorer struct {
+	*abstractScorer
+	lastDoc  int
+	scorers  []Scorer
+	docs     []int // the current doc of each scorer
+	coordVal float32
+}
+
+func newConjunctionScorer(weight Weight, scorers []Scorer) *ConjunctionScorer {
+	return newConjunctionScorerWithCoord(weight, scorers, 1)
+}
+
+func newConjunctionScorerWithCoord(weight Weight, scorers []Scorer, coord float32) *ConjunctionScorer {
+	ans := &ConjunctionScorer{
+		lastDoc:  -1,
+		scorers:  scorers,
+		docs:     make([]int, len(scorers)),
+		coordVal: coord,
+	}
+	for i := range ans.docs {
+		ans.docs[i] = -1
+	}
+	ans.abstractScorer = newScorer(ans, weight)
+	return ans
+}
+
+func (s *ConjunctionScorer) doNext(doc int) (int, error) {
+	for {
+		// doc may already be NO_MORE_DOCS here, but we don't check
+		// explicitly since all scorers should advance to NO_MORE_DOCS,
+		// match, then return that value.
+		advanced := false
+		for i := 1; i < len(s.scorers) && !advanced; i++ {
+			// invariant: docs[i] <= doc at this point.
+
+			// docs[i] may already be equal to doc if we "broke advanceHead"
+			// on the previous iteration and the advance on the lead scorer
+			// exactly matched.
+			if s.docs[i] < doc {
+				next, err := s.scorers[i].Advance(doc)
+				if err != nil {
+					return 0, err
+				}
+				s.docs[i] = next
+				if next > doc {
+					// DocsEnum beyond the current doc - break and advance lead
+					// to the new highest doc.
+					doc = next
+					advanced = true
+				}
+			}
+		}
+		if !advanced {
+			// success - all DocsEnums are on the same doc
+			return doc, nil
+		}
+		// advance head for next iteration
+		next, err := s.scorers[0].Advance(doc)
+		if err != nil {
+			return 0, err
+		}
+		doc, s.docs[0] = next, next
+	}
+}
+
+func (s *ConjunctionScorer) Advance(target int) (doc int, err error) {
+	if s.docs[0], err = s.scorers[0].Advance(target); err != nil {
+		return 0, err
+	}
+	if doc, err = s.doNext(s.docs[0]); err == nil {
+		s.lastDoc = doc
+	}
+	return
+}
+
+func (s *ConjunctionScorer) DocId() int {
+	return s.lastDoc
+}
+
+func (s *ConjunctionScorer) NextDoc() (doc int, err error) {
+	if s.docs[0], err = s.scorers[0].NextDoc(); err != nil {
+		return 0, err
+	}
+	if doc, err = s.doNext(s.docs[0]); err == nil {
+		s.lastDoc = doc
+	}
+	return
+}
+
+func (s *ConjunctionScorer) Score() (float32, error) {
+	// TODO: sum into a float64 and cast to float32 if we ever send
+	// required clauses to BS1
+	var sum float64
+	for _, scorer := range s.scorers {
+		score, err := scorer.Score()
+		if err != nil {
+			return 0, err
+		}
+		sum += float64(score)
+	}
+	return s.coordVal * float32(sum), nil
+}
+
+func (s *ConjunctionScorer) Freq() (int, error) {
+	return len(s.scorers), nil
+}
+
+func (s *ConjunctionScorer) String() string {
+	return fmt.Sprintf("ConjunctionScorer(%v)", s.weight)
+}
+
+// search/DisjunctionSumScorer.java
+
+/*
+A Scorer for OR like queries, counterpart of ConjunctionScorer. This
+Scorer implements Advance() and uses Advance() on the given Scorers.
+*/
+type DisjunctionSumScorer struct {
+	*abstractScorer
+	// The scorers, organized in a heap by their current doc.
+	subScorers []Scorer
+	numScorers int
+	doc        int
+	coord      []float32
+}
+
+/*
+Construct a DisjunctionScorer. coord is indexed by the number of
+matching sub scorers, or nil if the caller coordinates itself.
+*/
+func newDisjunctionSumScorer(weight Weight, subScorers []Scorer, coord []float32) *DisjunctionSumScorer {
+	ans := &DisjunctionSumScorer{
+		subScorers: append([]Scorer(nil), subScorers...),
+		numScorers: len(subScorers),
+		doc:        -1,
+		coord:      coord,
+	}
+	ans.abstractScorer = newScorer(ans, weight)
+	ans.heapify()
+	return ans
+}
+
+/*
+Organize subScorers into a min heap with scorers generating the
+earliest document on top.
+*/
+func (s *DisjunctionSumScorer) heapify() {
+	for i := (s.numScorers >> 1) - 1; i >= 0; i-- {
+		s.heapAdjust(i)
+	}
+}
+
+/*
+The subtree of subScorers at root is a min heap except possibly for
+its root element. Bubble the root down as required to make the
+subtree a heap.
+*/
+func (s *DisjunctionSumScorer) heapAdjust(root int) {
+	scorer := s.subScorers[root]
+	doc := scorer.DocId()
+	i := root
+	for i <= (s.numScorers>>1)-1 {
+		lchild := (i << 1) + 1
+		lscorer := s.subScorers[lchild]
+		ldoc := lscorer.DocId()
+		rdoc, rchild := math.MaxInt32, (i<<1)+2
+		var rscorer Scorer
+		if rchild < s.numScorers {
+			rscorer = s.subScorers[rchild]
+			rdoc = rscorer.DocId()
+		}
+		if ldoc < doc {
+			if rdoc < ldoc {
+				s.subScorers[i] = rscorer
+				s.subScorers[rchild] = scorer
+				i = rchild
+			} else {
+				s.subScorers[i] = lscorer
+				s.subScorers[lchild] = scorer
+				i = lchild
+			}
+		} else if rdoc < doc {
+			s.subScorers[i] = rscorer
+			s.subScorers[rchild] = scorer
+			i = rchild
+		} else {
+			return
+		}
+	}
+}
+
+/* Remove the root Scorer from subScorers and re-establish it as a heap. */
+func (s *DisjunctionSumScorer) heapRemoveRoot() {
+	if s.numScorers == 1 {
+		s.subScorers[0] = nil
+		s.numScorers = 0
+	} else {
+		s.subScorers[0] = s.subScorers[s.numScorers-1]
+		s.subScorers[s.numScorers-1] = nil
+		s.numScorers--
+		s.heapAdjust(0)
+	}
+}
+
+func (s *DisjunctionSumScorer) DocId() int {
+	return s.doc
+}
+
+func (s *DisjunctionSumScorer) NextDoc() (int, error) {
+	assert(s.doc != NO_MORE_DOCS)
+	for {
+		doc, err := s.subScorers[0].NextDoc()
+		if err != nil {
+			return 0, err
+		}
+		if doc != NO_MORE_DOCS {
+			s.heapAdjust(0)
+		} else {
+			s.heapRemoveRoot()
+			if s.numScorers == 0 {
+				s.doc = NO_MORE_DOCS
+				return s.doc, nil
+			}
+		}
+		if docID := s.subScorers[0].DocId(); docID != s.doc {
+			s.doc = docID
+			return s.doc, nil
+		}
+	}
+}
+
+func (s *DisjunctionSumScorer) Advance(target int) (int, error) {
+	assert(s.doc != NO_MORE_DOCS)
+	for {
+		doc, err := s.subScorers[0].Advance(target)
+		if err != nil {
+			return 0, err
+		}
+		if doc != NO_MORE_DOCS {
+			s.heapAdjust(0)
+		} else {
+			s.heapRemoveRoot()
+			if s.numScorers == 0 {
+				s.doc = NO_MORE_DOCS
+				return s.doc, nil
+			}
+		}
+		if docID := s.subScorers[0].DocId(); docID >= target {
+			s.doc = docID
+			return s.doc, nil
+		}
+	}
+}
+
+/*
+Sums up the scores of all sub scorers positioned on the current doc,
+and counts them.
+*/
+func (s *DisjunctionSumScorer) sum() (score float64, nrMatchers int, err error) {
+	for _, scorer := range s.subScorers[:s.numScorers] {
+		if scorer.DocId() == s.doc {
+			sub, err := scorer.Score()
+			if err != nil {
+				return 0, 0, err
+			}
+			score += float64(sub)
+			nrMatchers++
+		}
+	}
+	return
+}
+
+func (s *DisjunctionSumScorer) Score() (float32, error) {
+	score, nrMatchers, err := s.sum()
+	if err != nil {
+		return 0, err
+	}
+	if s.coord == nil {
+		return float32(score), nil
+	}
+	return float32(score) * s.coord[nrMatchers], nil
+}
+
+func (s *DisjunctionSumScorer) Freq() (int, error) {
+	_, nrMatchers, err := s.sum()
+	return nrMatchers, err
+}
+
+func (s *DisjunctionSumScorer) String() string {
+	return fmt.Sprintf("DisjunctionSumScorer(%v)", s.weight)
+}
+
+// search/ReqExclScorer.java
+
+/*
+A Scorer for queries with a required subscorer and an excluding
+(prohibited) sub DocIdSetIterator.
+
+This Scorer implements Advance(), and it uses the Advance() on the
+given scorers.
+*/
+type ReqExclScorer struct {
+	*abstractScorer
+	reqScorer Scorer
+	exclDisi  DocIdSetIterator
+	doc       int
+}
+
+func newReqExclScorer(reqScorer Scorer, exclDisi DocIdSetIterator) *ReqExclScorer {
+	ans := &ReqExclScorer{
+		reqScorer: reqScorer,
+		exclDisi:  exclDisi,
+		doc:       -1,
+	}
+	ans.abstractScorer = newScorer(ans, nil)
+	return ans
+}
+
+func (s *ReqExclScorer) NextDoc() (doc int, err error) {
+	if s.reqScorer == nil {
+		return s.doc, nil
+	}
+	if s.doc, err = s.reqScorer.NextDoc(); err != nil {
+		return 0, err
+	}
+	if s.doc == NO_MORE_DOCS {
+		s.reqScorer = nil // exhausted, nothing left
+		return s.doc, nil
+	}
+	if s.exclDisi == nil {
+		return s.doc, nil
+	}
+	s.doc, err = s.toNonExcluded()
+	return s.doc, err
+}
+
+/*
+Advance to non excluded doc.
+
+On entry:
+  - reqScorer != nil,
+  - exclScorer != nil,
+  - reqScorer was advanced once via NextDoc() or Advance() and
+    reqScorer.DocId() may still be excluded.
+
+Advances reqScorer a non excluded required doc, if any.
+*/
+func (s *ReqExclScorer) toNonExcluded() (int, error) {
+	exclDoc := s.exclDisi.DocId()
+	reqDoc := s.reqScorer.DocId() // may be excluded
+	for {
+		if reqDoc < exclDoc {
+			return reqDoc, nil // reqScorer advanced to before exclScorer, ie. not excluded
+		} else if reqDoc > exclDoc {
+			var err error
+			if exclDoc, err = s.exclDisi.Advance(reqDoc); err != nil {
+				return 0, err
+			}
+			if exclDoc == NO_MORE_DOCS {
+				s.exclDisi = nil // exhausted, no more exclusions
+				return reqDoc, nil
+			}
+			if exclDoc > reqDoc {
+				return reqDoc, nil // not excluded
+			}
+		}
+		var err error
+		if reqDoc, err = s.reqScorer.NextDoc(); err != nil {
+			return 0, err
+		}
+		if reqDoc == NO_MORE_DOCS {
+			break
+		}
+	}
+	s.reqScorer = nil // exhausted, nothing left
+	return NO_MORE_DOCS, nil
+}
+
+func (s *ReqExclScorer) DocId() int {
+	return s.doc
+}
+
+/*
+Returns the score of the current document matching the query.
+Initially invalid, until NextDoc() is called the first time.
+*/
+func (s *ReqExclScorer) Score() (float32, error) {
+	return s.reqScorer.Score() // reqScorer may be nil when NextDoc() or Advance() already return false
+}
+
+func (s *ReqExclScorer) Freq() (int, error) {
+	return s.reqScorer.Freq()
+}
+
+func (s *ReqExclScorer) Advance(target int) (doc int, err error) {
+	if s.reqScorer == nil {
+		s.doc = NO_MORE_DOCS
+		return s.doc, nil
+	}
+	if s.exclDisi == nil {
+		s.doc, err = s.reqScorer.Advance(target)
+		return s.doc, err
+	}
+	if doc, err = s.reqScorer.Advance(target); err != nil {
+		return 0, err
+	}
+	if doc == NO_MORE_DOCS {
+		s.reqScorer = nil
+		s.doc = NO_MORE_DOCS
+		return s.doc, nil
+	}
+	s.doc, err = s.toNonExcluded()
+	return s.doc, err
+}
+
+// search/ReqOptSumScorer.java
+
+/*
+A Scorer for queries with a required part and an optional part.
+Delays Advance() on the optional part until a Score() is needed.
+
+This Scorer implements Advance().
+*/
+type ReqOptSumScorer struct {
+	*abstractScorer
+	// The scorers passed from the constructor. These are set to nil as
+	// soon as their NextDoc() or Advance() returns NO_MORE_DOCS.
+	reqScorer Scorer
+	optScorer Scorer
+}
+
+func newReqOptSumScorer(reqScorer, optScorer Scorer) *ReqOptSumScorer {
+	assert(reqScorer != nil)
+	assert(optScorer != nil)
+	ans := &ReqOptSumScorer{reqScorer: reqScorer, optScorer: optScorer}
+	ans.abstractScorer = newScorer(ans, nil)
+	return ans
+}
+
+func (s *ReqOptSumScorer) NextDoc() (int, error) {
+	return s.reqScorer.NextDoc()
+}
+
+func (s *ReqOptSumScorer) Advance(target int) (int, error) {
+	return s.reqScorer.Advance(target)
+}
+
+func (s *ReqOptSumScorer) DocId() int {
+	return s.reqScorer.DocId()
+}
+
+/*
+Returns the score of the current document matching the query.
+Initially invalid, until NextDoc() is called the first time.
+*/
+func (s *ReqOptSumScorer) Score() (float32, error) {
+	curDoc := s.reqScorer.DocId()
+	reqScore, err := s.reqScorer.Score()
+	if err != nil {
+		return 0, err
+	}
+	if s.optScorer == nil {
+		return reqScore, nil
+	}
+
+	optScorerDoc := s.optScorer.DocId()
+	if optScorerDoc < curDoc {
+		if optScorerDoc, err = s.optScorer.Advance(curDoc); err != nil {
+			return 0, err
+		}
+		if optScorerDoc == NO_MORE_DOCS {
+			s.optScorer = nil
+			return reqScore, nil
+		}
+	}
+	if optScorerDoc != curDoc {
+		return reqScore, nil
+	}
+	optScore, err := s.optScorer.Score()
+	if err != nil {
+		return 0, err
+	}
+	return reqScore + optScore, nil
+}
+
+func (s *ReqOptSumScorer) Freq() (int, error) {
+	// we might have deferred advance()
+	if _, err := s.Score(); err != nil {
+		return 0, err
+	}
+	if s.optScorer != nil && s.optScorer.DocId() == s.reqScorer.DocId() {
+		return 2, nil
+	}
+	return 1, nil
+}
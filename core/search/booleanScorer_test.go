@@ -0,0 +1,121 @@
+package search
+
+import (
+	std "github.com/balzaczyy/golucene/analysis/standard"
+	"github.com/balzaczyy/golucene/core/index"
+	. "github.com/balzaczyy/golucene/core/search/model"
+	"github.com/balzaczyy/golucene/core/store"
+	"github.com/balzaczyy/golucene/core/util"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestBooleanScorer(t *testing.T) {
+	dir, err := store.OpenFSDirectory(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := index.NewIndexWriter(dir, index.NewIndexWriterConfig(util.VERSION_LATEST, std.NewStandardAnalyzer()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	const numDocs = 5000
+	expected := make(map[int]int)
+	for i := 0; i < numDocs; i++ {
+		var words []string
+		for _, c := range []struct {
+			word string
+			mod  int
+		}{{"two", 2}, {"three", 3}, {"five", 5}, {"seven", 7}} {
+			if i%c.mod == 0 {
+				words = append(words, c.word)
+			}
+		}
+		if i%2 == 0 || i%3 == 0 || i%5 == 0 {
+			if i%7 != 0 {
+				expected[i] = len(words)
+			}
+		}
+		addDocument(t, w, strings.Join(append(words, "doc"), " "))
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	r, err := index.OpenDirectoryReader(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	assertEquals(t, 1, len(r.Leaves()))
+	ctx := r.Leaves()[0]
+
+	bq := NewBooleanQuery()
+	for _, word := range []string{"two", "three", "five"} {
+		bq.Add(NewTermQuery(index.NewTerm("title", word)), SHOULD)
+	}
+	bq.Add(NewTermQuery(index.NewTerm("title", "seven")), MUST_NOT)
+	ss := NewIndexSearcher(r)
+	weight, err := ss.CreateNormalizedWeight(bq)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !weight.IsScoresDocsOutOfOrder() {
+		t.Fatal("Expected disjunction to be scored out of order")
+	}
+	bs, err := weight.BulkScorer(ctx, false, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := bs.(*BooleanScorer); !ok {
+		t.Fatalf("Expected BooleanScorer, but was %v", bs)
+	}
+
+	// score up to a max within a window, then the rest
+	c := &freqCollector{freqs: make(map[int]int), scores: make(map[int]float32)}
+	more, err := bs.ScoreAndCollectUpto(c, 3000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !more {
+		t.Error("Expected more docs beyond max")
+	}
+	for doc := range c.freqs {
+		if doc >= 3000 {
+			t.Fatalf("Expected docs below max, but was %v", doc)
+		}
+	}
+	if more, err = bs.ScoreAndCollectUpto(c, NO_MORE_DOCS); err != nil {
+		t.Fatal(err)
+	}
+	if more {
+		t.Error("Expected no more docs")
+	}
+	assertEquals(t, len(expected), len(c.freqs))
+	for doc, freq := range expected {
+		if c.freqs[doc] != freq {
+			t.Errorf("Expected doc %v to match %v clauses, but was %v", doc, freq, c.freqs[doc])
+		}
+	}
+
+	// same scores as the doc-at-a-time BooleanScorer2
+	scorer, err := weight.(*BooleanWeight).Scorer(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := 0
+	for doc, err := scorer.NextDoc(); doc != NO_MORE_DOCS; doc, err = scorer.NextDoc() {
+		if err != nil {
+			t.Fatal(err)
+		}
+		score, err := scorer.Score()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(float64(score-c.scores[doc])) > 1e-5 {
+			t.Errorf("Expected doc %v to score %v, but was %v", doc, score, c.scores[doc])
+		}
+		n++
+	}
+	assertEquals(t, len(expected), n)
+}
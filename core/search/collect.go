@@ -16,6 +16,10 @@ type ScoreDoc struct {
 	Doc int
 	/** Only set by {@link TopDocs#merge} */
 	shardIndex int
+	/** The values which are used to sort the referenced document, one
+	 * per {@link SortField} of the {@link Sort}. Only set when hits are
+	 * sorted by field. */
+	Fields []interface{}
 }
 
 func newScoreDoc(doc int, score float32) *ScoreDoc {
@@ -23,7 +27,13 @@ func newScoreDoc(doc int, score float32) *ScoreDoc {
 }
 
 func newShardedScoreDoc(doc int, score float32, shardIndex int) *ScoreDoc {
-	return &ScoreDoc{score, doc, shardIndex}
+	return &ScoreDoc{Score: score, Doc: doc, shardIndex: shardIndex}
+}
+
+// Returns the index of the shard this hit came from, or -1 if it was
+// not merged from shards.
+func (d *ScoreDoc) ShardIndex() int {
+	return d.shardIndex
 }
 
 func (d *ScoreDoc) String() string {
@@ -56,6 +66,11 @@ type TopDocs struct {
 	maxScore  float64
 }
 
+// Returns the maximum score value encountered, or NaN if unknown.
+func (td TopDocs) MaxScore() float64 {
+	return td.maxScore
+}
+
 type Collector interface {
 	SetScorer(s Scorer)
 	Collect(doc int) error
@@ -344,3 +359,75 @@ func (c *OutOfOrderTopScoreDocCollector) Collect(doc int) (err error) {
 func (c *OutOfOrderTopScoreDocCollector) AcceptsDocsOutOfOrder() bool {
 	return true
 }
+
+// search/MultiCollector.java
+
+/*
+A Collector which allows running a search with several Collectors.
+It offers a static Wrap() method which accepts a list of collectors
+and wraps them with MultiCollector, while filtering out the nil
+ones.
+*/
+type MultiCollector struct {
+	collectors []Collector
+}
+
+/*
+Wraps a list of Collectors with a MultiCollector. This method works
+as follows:
+
+	- Filters out the nil collectors, so they are not used during
+	search time.
+	- If the input contains 1 real collector (i.e. non-nil), it is
+	returned.
+	- Otherwise the method returns a MultiCollector which wraps the
+	non-nil ones.
+
+It panics if either 0 collectors were input, or all collectors are
+nil.
+*/
+func WrapCollectors(collectors ...Collector) Collector {
+	var colls []Collector
+	for _, c := range collectors {
+		if c != nil {
+			colls = append(colls, c)
+		}
+	}
+	switch len(colls) {
+	case 0:
+		panic("At least 1 collector must not be nil")
+	case 1:
+		return colls[0]
+	}
+	return &MultiCollector{colls}
+}
+
+func (mc *MultiCollector) AcceptsDocsOutOfOrder() bool {
+	for _, c := range mc.collectors {
+		if !c.AcceptsDocsOutOfOrder() {
+			return false
+		}
+	}
+	return true
+}
+
+func (mc *MultiCollector) Collect(doc int) error {
+	for _, c := range mc.collectors {
+		if err := c.Collect(doc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (mc *MultiCollector) SetNextReader(ctx *index.AtomicReaderContext) {
+	for _, c := range mc.collectors {
+		c.SetNextReader(ctx)
+	}
+}
+
+func (mc *MultiCollector) SetScorer(s Scorer) {
+	for _, c := range mc.collectors {
+		c.SetScorer(s)
+	}
+}
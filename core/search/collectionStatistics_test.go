@@ -0,0 +1,69 @@
+package search
+
+import (
+	"github.com/balzaczyy/golucene/core/index"
+	"github.com/balzaczyy/golucene/core/store"
+	"math"
+	"testing"
+)
+
+// A shard of a distributed index, weighting queries with the global
+// statistics of a collection ten times as large as the local one.
+type shardSearcher struct {
+	*IndexSearcher
+}
+
+func (ss *shardSearcher) TermStatistics(term *index.Term, context *index.TermContext) TermStatistics {
+	local := ss.IndexSearcher.TermStatistics(term, context)
+	return NewTermStatistics(local.Term, local.DocFreq*10, local.TotalTermFreq*10)
+}
+
+func (ss *shardSearcher) CollectionStatistics(field string) CollectionStatistics {
+	local := ss.IndexSearcher.CollectionStatistics(field)
+	return NewCollectionStatistics(field, local.MaxDoc()*10, local.DocCount()*10,
+		local.SumTotalTermFreq()*10, local.SumDocFreq()*10)
+}
+
+func TestGlobalStatistics(t *testing.T) {
+	d, err := store.OpenFSDirectory("testdata/belfrysample")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := index.OpenDirectoryReader(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := NewTermQuery(index.NewTerm("content", "bat"))
+	local, err := NewIndexSearcher(r).SearchTop(q, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ss := &shardSearcher{NewIndexSearcher(r)}
+	ss.SetSPI(ss)
+	global, err := ss.SearchTop(q, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEquals(t, local.TotalHits, global.TotalHits)
+
+	// 'bat' occurs in 80 of the 80 docs of the collection, as it does
+	// in all 8 docs of the shard, and the average field length is the
+	// same, so only the idf of BM25 changes
+	localIdf := math.Log(1 + (8-8+0.5)/(8+0.5))
+	globalIdf := math.Log(1 + (80-80+0.5)/(80+0.5))
+	for i, hit := range global.ScoreDocs {
+		assertEquals(t, local.ScoreDocs[i].Doc, hit.Doc)
+		expected := float64(local.ScoreDocs[i].Score) * globalIdf / localIdf
+		if math.Abs(float64(hit.Score)-expected) > 1e-6 {
+			t.Errorf("Expected score %v for doc %v, but was %v", expected, hit.Doc, hit.Score)
+		}
+		exp, err := ss.Explain(q, hit.Doc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(float64(exp.Value()-hit.Score)) > 1e-6 {
+			t.Errorf("Score %v of doc %v differs from explanation %v", hit.Score, hit.Doc, exp)
+		}
+	}
+}
@@ -0,0 +1,274 @@
+package search
+
+import (
+	"errors"
+	"fmt"
+	"github.com/balzaczyy/golucene/core/index"
+	"math"
+	"sync"
+	"time"
+)
+
+// search/ControlledRealTimeReopenThread.java
+
+/*
+Utility goroutine that keeps re-opening a ReferenceManager, such as
+SearcherManager, with a max and min target staleness: when nobody
+waits for a specific generation, the manager is refreshed at least
+every targetMaxStale; when someone waits for a generation (see
+WaitForGeneration()), it is refreshed as soon as targetMinStale has
+passed since the last refresh.
+
+Every indexing operation of the TrackingIndexWriter returns a
+generation, and WaitForGeneration() of that generation blocks until a
+refreshed searcher includes the change, which gives read-your-writes
+semantics. Be sure to make all changes via the TrackingIndexWriter,
+and to create the manager from its IndexWriter (see
+NewSearcherManagerFromWriter()).
+*/
+type ControlledRealTimeReopenThread struct {
+	manager         *ReferenceManager
+	targetMaxStale  time.Duration
+	targetMinStale  time.Duration
+	writer          *index.TrackingIndexWriter
+	refreshListener RefreshListener
+	refreshStartGen int64 // guarded by lock
+	lastReopenStart time.Time
+	err             error // the first error hit while refreshing
+	finish          chan bool
+	done            chan bool // nil until started
+
+	// guards waitingGen; sending on reopenCond wakes the reopen
+	// goroutine up, so it can check whether it should reopen sooner
+	reopenLock sync.Mutex
+	reopenCond chan bool
+	waitingGen int64
+
+	// guards searchingGen; searchingGenChanged is closed, and replaced,
+	// each time searchingGen changes
+	lock                sync.Mutex
+	searchingGen        int64
+	searchingGenChanged chan bool
+}
+
+/*
+Create ControlledRealTimeReopenThread, to periodically reopen the
+ReferenceManager.
+
+targetMaxStale is the maximum time until a new reopen takes place
+when no caller is waiting for a specific generation;
+targetMinStale is the minimum time until a new reopen takes place
+when a caller is waiting for a specific generation. Call Start() to
+begin reopening.
+*/
+func NewControlledRealTimeReopenThread(writer *index.TrackingIndexWriter, manager *ReferenceManager,
+	targetMaxStale, targetMinStale time.Duration) (*ControlledRealTimeReopenThread, error) {
+
+	if targetMaxStale < targetMinStale {
+		return nil, errors.New(fmt.Sprintf(
+			"targetMaxStale (=%v) < targetMinStale (=%v)", targetMaxStale, targetMinStale))
+	}
+	ans := &ControlledRealTimeReopenThread{
+		manager:             manager,
+		targetMaxStale:      targetMaxStale,
+		targetMinStale:      targetMinStale,
+		writer:              writer,
+		finish:              make(chan bool),
+		reopenCond:          make(chan bool, 1),
+		searchingGenChanged: make(chan bool),
+	}
+	ans.refreshListener = &handleRefresh{ans}
+	manager.AddListener(ans.refreshListener)
+	return ans, nil
+}
+
+type handleRefresh struct {
+	owner *ControlledRealTimeReopenThread
+}
+
+func (h *handleRefresh) BeforeRefresh() error {
+	return nil
+}
+
+func (h *handleRefresh) AfterRefresh(didRefresh bool) error {
+	h.owner.refreshDone()
+	return nil
+}
+
+func (t *ControlledRealTimeReopenThread) refreshDone() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	// once closed, searchingGen stays maxed out so that all waiting
+	// search goroutines return
+	if t.searchingGen != math.MaxInt64 {
+		t.searchingGen = t.refreshStartGen
+	}
+	close(t.searchingGenChanged)
+	t.searchingGenChanged = make(chan bool)
+}
+
+// Starts the goroutine reopening the manager.
+func (t *ControlledRealTimeReopenThread) Start() {
+	assert2(t.done == nil, "already started")
+	t.done = make(chan bool)
+	go t.run()
+}
+
+/*
+Stops the reopen goroutine, and releases all goroutines waiting for a
+generation. Returns the first error hit while refreshing, if any.
+*/
+func (t *ControlledRealTimeReopenThread) Close() error {
+	t.manager.RemoveListener(t.refreshListener)
+	close(t.finish)
+	if t.done != nil {
+		<-t.done
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	// Max it out so any waiting search goroutines will return:
+	t.searchingGen = math.MaxInt64
+	close(t.searchingGenChanged)
+	t.searchingGenChanged = make(chan bool)
+	return t.err
+}
+
+/*
+Waits for the target generation to become visible in the searcher.
+If the current searcher is older than the target generation, this
+method will block until the searcher is reopened, by another
+goroutine via MaybeRefresh() or until the ReferenceManager is closed.
+*/
+func (t *ControlledRealTimeReopenThread) WaitForGeneration(targetGen int64) error {
+	_, err := t.WaitForGenerationWithin(targetGen, -1)
+	return err
+}
+
+/*
+Waits for the target generation to become visible in the searcher,
+up to a maximum specified wait time. If the current searcher is older
+than the target generation, this method will block until the searcher
+has been reopened by another goroutine via MaybeRefresh(), the given
+wait time has elapsed, or until the ReferenceManager is closed.
+
+NOTE: if the waiting time elapses before the requested target
+generation is available the current ReferenceManager needs to be
+refreshed before the target generation is visible.
+
+maxWait is the maximum time to wait, or a negative duration to wait
+indefinitely. Returns true if the targetGen is now available, or
+false if maxWait passed before it became available.
+*/
+func (t *ControlledRealTimeReopenThread) WaitForGenerationWithin(targetGen int64,
+	maxWait time.Duration) (bool, error) {
+
+	if curGen := t.writer.Generation(); targetGen > curGen {
+		return false, errors.New(fmt.Sprintf(
+			"targetGen=%v was never returned by the ReferenceManager instance (current gen=%v)",
+			targetGen, curGen))
+	}
+
+	searchingGen, changed := t.searchingGenAndChange()
+	if targetGen <= searchingGen {
+		return true, nil
+	}
+
+	// Notify the reopen goroutine that the waitingGen has changed, so
+	// it may wake up and realize it should not sleep for much or any
+	// longer before reopening:
+	t.reopenLock.Lock()
+	// Need to find waitingGen inside lock as it's used to determine
+	// how long to sleep
+	if targetGen > t.waitingGen {
+		t.waitingGen = targetGen
+	}
+	select {
+	case t.reopenCond <- true:
+	default:
+	}
+	t.reopenLock.Unlock()
+
+	var timeout <-chan time.Time
+	if maxWait >= 0 {
+		timer := time.NewTimer(maxWait)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	for targetGen > searchingGen {
+		select {
+		case <-changed:
+			searchingGen, changed = t.searchingGenAndChange()
+		case <-timeout:
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (t *ControlledRealTimeReopenThread) searchingGenAndChange() (int64, chan bool) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return t.searchingGen, t.searchingGenChanged
+}
+
+func (t *ControlledRealTimeReopenThread) run() {
+	defer close(t.done)
+	t.lastReopenStart = time.Now()
+	for {
+		// TODO: try to guestimate how long reopen might take based on
+		// past data?
+
+		// Loop until we've waiting long enough before the next reopen:
+		for {
+			// Need lock before finding out if has waiting
+			t.reopenLock.Lock()
+			searchingGen, _ := t.searchingGenAndChange()
+			// True if we have someone waiting for reopened searcher:
+			hasWaiting := t.waitingGen > searchingGen
+			t.reopenLock.Unlock()
+
+			var nextReopenStart time.Time
+			if hasWaiting {
+				nextReopenStart = t.lastReopenStart.Add(t.targetMinStale)
+			} else {
+				nextReopenStart = t.lastReopenStart.Add(t.targetMaxStale)
+			}
+			sleep := nextReopenStart.Sub(time.Now())
+			if sleep <= 0 {
+				break
+			}
+			select {
+			case <-t.finish:
+				return
+			case <-t.reopenCond:
+			case <-time.After(sleep):
+			}
+		}
+
+		select {
+		case <-t.finish:
+			return
+		default:
+		}
+
+		t.lastReopenStart = time.Now()
+		// Save the gen as of when we started the reopen; the listener
+		// (handleRefresh above) copies this to searchingGen once the
+		// reopen completes:
+		t.lock.Lock()
+		t.refreshStartGen = t.writer.GetAndIncrementGeneration()
+		t.lock.Unlock()
+		if err := t.manager.MaybeRefreshBlocking(); err != nil && t.err == nil {
+			t.err = err
+		}
+	}
+}
+
+// Returns which generation the current searcher is guaranteed to
+// include.
+func (t *ControlledRealTimeReopenThread) SearchingGen() int64 {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return t.searchingGen
+}
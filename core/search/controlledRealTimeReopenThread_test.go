@@ -0,0 +1,80 @@
+package search
+
+import (
+	"fmt"
+	std "github.com/balzaczyy/golucene/analysis/standard"
+	docu "github.com/balzaczyy/golucene/core/document"
+	"github.com/balzaczyy/golucene/core/index"
+	"github.com/balzaczyy/golucene/core/store"
+	"github.com/balzaczyy/golucene/core/util"
+	"testing"
+	"time"
+)
+
+func TestControlledRealTimeReopenThread(t *testing.T) {
+	dir, err := store.OpenFSDirectory(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := index.NewIndexWriter(dir, index.NewIndexWriterConfig(util.VERSION_LATEST, std.NewStandardAnalyzer()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	tw := index.NewTrackingIndexWriter(w)
+	m, err := NewSearcherManagerFromWriter(w, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+
+	if _, err = NewControlledRealTimeReopenThread(tw, m.ReferenceManager, time.Millisecond, time.Second); err == nil {
+		t.Error("Expected error when max staleness is below min staleness")
+	}
+	// only reopen when asked for a generation
+	reopener, err := NewControlledRealTimeReopenThread(tw, m.ReferenceManager, time.Hour, 10*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reopener.Start()
+
+	q := NewTermQuery(index.NewTerm("title", "doc"))
+	for i := 1; i <= 3; i++ {
+		doc := docu.NewDocument()
+		doc.Add(docu.NewTextFieldFromString("title", fmt.Sprintf("doc %v", i), docu.STORE_YES))
+		gen, err := tw.AddDocument(doc.Fields())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = reopener.WaitForGenerationWithin(gen+1, 0); err == nil {
+			t.Error("Expected error when waiting for a future generation")
+		}
+		if err = reopener.WaitForGeneration(gen); err != nil {
+			t.Fatal(err)
+		}
+		if reopener.SearchingGen() < gen {
+			t.Errorf("Expected searching gen >= %v, but was %v", gen, reopener.SearchingGen())
+		}
+
+		s, err := m.Acquire()
+		if err != nil {
+			t.Fatal(err)
+		}
+		docs, err := s.SearchTop(q, 10)
+		m.Release(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// read your writes
+		assertEquals(t, i, docs.TotalHits)
+	}
+
+	if err = reopener.Close(); err != nil {
+		t.Fatal(err)
+	}
+	// waiting never blocks after close
+	ok, err := reopener.WaitForGenerationWithin(tw.Generation(), time.Second)
+	if !ok || err != nil {
+		t.Errorf("Expected no wait after close, but was %v (%v)", ok, err)
+	}
+}
@@ -0,0 +1,119 @@
+package search
+
+import (
+	"fmt"
+)
+
+// search/similarities/DFRSimilarity.java
+
+/*
+Implements the divergence from randomness (DFR) framework introduced
+in Gianni Amati and Cornelis Joost Van Rijsbergen. 2002. Probabilistic
+models of information retrieval based on measuring the divergence
+from randomness. ACM Trans. Inf. Syst. 20, 4 (October 2002), 357-389.
+
+The DFR scoring formula is composed of three separate components: the
+basic model, the aftereffect and an additional normalization
+component, represented by the types BasicModel, AfterEffect and
+Normalization, respectively. The names of these types were chosen to
+match the names of their counterparts in the Terrier IR engine.
+
+To construct a DFRSimilarity, you must specify the implementations
+for all three components of DFR:
+
+BasicModel, the basic model of information content:
+
+  - BasicModelBE: Limiting form of Bose-Einstein
+  - BasicModelG: Geometric approximation of Bose-Einstein
+  - BasicModelP: Poisson approximation of the Binomial
+  - BasicModelD: Divergence approximation of the Binomial
+  - BasicModelIn: Inverse document frequency
+  - BasicModelIne: Inverse expected document frequency [mixture of Poisson and IDF]
+  - BasicModelIF: Inverse term frequency [approximation of I(ne)]
+
+AfterEffect, the first normalization of information gain:
+
+  - AfterEffectL: Laplace's law of succession
+  - AfterEffectB: Ratio of two Bernoulli processes
+  - NoAfterEffect: no first normalization
+
+Normalization, the second (length) normalization:
+
+  - NormalizationH1: Uniform distribution of term frequency
+  - NormalizationH2: term frequency density inversely related to length
+  - NormalizationH3: term frequency normalization provided by Dirichlet prior
+  - NormalizationZ: term frequency normalization provided by a Zipfian relation
+  - NoNormalization: no second normalization
+
+Note that qtf, the multiplicity of term-occurrence in the query, is
+not handled by this implementation.
+*/
+type DFRSimilarity struct {
+	*SimilarityBase
+	// The basic model for information content.
+	basicModel BasicModel
+	// The first normalization of the information content.
+	afterEffect AfterEffect
+	// The term frequency normalization.
+	normalization Normalization
+}
+
+/*
+Creates DFRSimilarity from the three components.
+
+Note that none of the parameters may be nil: you can use
+NewNoAfterEffect() and NewNoNormalization() to disable the after
+effect and the length normalization respectively.
+*/
+func NewDFRSimilarity(basicModel BasicModel, afterEffect AfterEffect,
+	normalization Normalization) *DFRSimilarity {
+
+	assert2(basicModel != nil && afterEffect != nil && normalization != nil,
+		"null parameters not allowed.")
+	ans := &DFRSimilarity{
+		basicModel:    basicModel,
+		afterEffect:   afterEffect,
+		normalization: normalization,
+	}
+	ans.SimilarityBase = NewSimilarityBase(ans)
+	return ans
+}
+
+func (sim *DFRSimilarity) Score(stats *BasicStats, freq, docLen float32) float32 {
+	tfn := sim.normalization.Tfn(stats, freq, docLen)
+	return stats.TotalBoost() *
+		sim.basicModel.Score(stats, tfn) * sim.afterEffect.Score(stats, tfn)
+}
+
+func (sim *DFRSimilarity) AddExplanation(expl *ExplanationImpl,
+	stats *BasicStats, doc int, freq, docLen float32) {
+
+	if stats.TotalBoost() != 1 {
+		expl.addDetail(newExplanation(stats.TotalBoost(), "boost"))
+	}
+
+	normExpl := sim.normalization.Explain(stats, freq, docLen)
+	tfn := normExpl.Value()
+	expl.addDetail(normExpl)
+	expl.addDetail(sim.basicModel.Explain(stats, tfn))
+	expl.addDetail(sim.afterEffect.Explain(stats, tfn))
+}
+
+func (sim *DFRSimilarity) String() string {
+	return fmt.Sprintf("DFR %v%v%v", sim.basicModel, sim.afterEffect, sim.normalization)
+}
+
+// Returns the basic model of information content
+func (sim *DFRSimilarity) BasicModel() BasicModel {
+	return sim.basicModel
+}
+
+// Returns the first normalization
+func (sim *DFRSimilarity) AfterEffect() AfterEffect {
+	return sim.afterEffect
+}
+
+// Returns the second normalization
+func (sim *DFRSimilarity) Normalization() Normalization {
+	return sim.normalization
+}
@@ -0,0 +1,332 @@
+package search
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/balzaczyy/golucene/core/index"
+	"github.com/balzaczyy/golucene/core/util"
+)
+
+// search/DisjunctionMaxQuery.java
+
+/*
+A query that generates the union of documents produced by its
+subqueries, and that scores each document with the maximum score for
+that document as produced by any subquery, plus a tie breaking
+increment for any additional matching subqueries.
+
+This is useful when searching for a word in multiple fields with
+different boost factors (so that the fields cannot be combined
+equivalently into a single search field). We want the primary score
+to be the one associated with the highest boost, not the sum of the
+field scores (as BooleanQuery would give).
+
+If the query is "albino elephant" this ensures that "albino"
+matching one field and "elephant" matching another gets a higher
+score than "albino" matching both fields. To get this result, use
+both BooleanQuery and DisjunctionMaxQuery: for each term a
+DisjunctionMaxQuery searches for it in each field, while the set of
+these DisjunctionMaxQuery's is combined into a BooleanQuery. The tie
+breaker capability allows results that include the same term in
+multiple fields to be judged better than results that include this
+term in only the best of those multiple fields, without confusing
+this with the better case of two different terms in the multiple
+fields.
+*/
+type DisjunctionMaxQuery struct {
+	*AbstractQuery
+	disjuncts []Query
+	// Multiple of the non-max disjunct scores added into our final score.
+	tieBreakerMultiplier float32
+}
+
+/*
+Creates a new DisjunctionMaxQuery. tieBreakerMultiplier is the score
+of each non-maximum disjunct for a document is multiplied by this
+weight and added into the final score. If non-zero, the value should
+be small, on the order of 0.1, which says that 10 occurrences of word
+in a lower-scored field that is also in a higher scored field is just
+as good as a unique word in the lower scored field (i.e., one that is
+not in any higher scored field).
+*/
+func NewDisjunctionMaxQuery(disjuncts []Query, tieBreakerMultiplier float32) *DisjunctionMaxQuery {
+	ans := &DisjunctionMaxQuery{
+		disjuncts:            append([]Query(nil), disjuncts...),
+		tieBreakerMultiplier: tieBreakerMultiplier,
+	}
+	ans.AbstractQuery = NewAbstractQuery(ans)
+	return ans
+}
+
+// Adds a subquery to this disjunction.
+func (q *DisjunctionMaxQuery) Add(query Query) {
+	q.disjuncts = append(q.disjuncts, query)
+}
+
+// Returns the disjuncts.
+func (q *DisjunctionMaxQuery) Disjuncts() []Query {
+	return q.disjuncts
+}
+
+// Returns the tie breaker value for multiple matches.
+func (q *DisjunctionMaxQuery) TieBreakerMultiplier() float32 {
+	return q.tieBreakerMultiplier
+}
+
+func (q *DisjunctionMaxQuery) CreateWeight(ss *IndexSearcher) (Weight, error) {
+	return newDisjunctionMaxWeight(q, ss)
+}
+
+/*
+Optimizes our representation and our subqueries representations.
+*/
+func (q *DisjunctionMaxQuery) Rewrite(reader index.IndexReader) Query {
+	if len(q.disjuncts) == 1 {
+		// optimize 1-clause queries
+		singleton := q.disjuncts[0]
+		result := singleton.Rewrite(reader)
+		if q.boost != 1 {
+			// queries can't be cloned yet, so only adjust the boost of a
+			// query that was rewritten to a new one
+			if result == singleton {
+				return q
+			}
+			result.SetBoost(q.boost * result.Boost())
+		}
+		return result
+	}
+
+	var clone *DisjunctionMaxQuery
+	for i, disjunct := range q.disjuncts {
+		if rewritten := disjunct.Rewrite(reader); rewritten != disjunct {
+			if clone == nil {
+				clone = NewDisjunctionMaxQuery(q.disjuncts, q.tieBreakerMultiplier)
+				clone.SetBoost(q.boost)
+			}
+			clone.disjuncts[i] = rewritten
+		}
+	}
+	if clone != nil {
+		return clone
+	}
+	return q
+}
+
+func (q *DisjunctionMaxQuery) ExtractTerms(terms *index.TermSet) {
+	for _, query := range q.disjuncts {
+		query.ExtractTerms(terms)
+	}
+}
+
+func (q *DisjunctionMaxQuery) Visit(visitor QueryVisitor) {
+	if sub := visitor.SubVisitor(SHOULD, q); sub != nil {
+		for _, query := range q.disjuncts {
+			query.Visit(sub)
+		}
+	}
+}
+
+func (q *DisjunctionMaxQuery) ToString(field string) string {
+	var buf bytes.Buffer
+	buf.WriteRune('(')
+	for i, query := range q.disjuncts {
+		if i > 0 {
+			buf.WriteString(" | ")
+		}
+		if _, ok := query.(*BooleanQuery); ok { // wrap sub-bools in parens
+			buf.WriteRune('(')
+			buf.WriteString(query.ToString(field))
+			buf.WriteRune(')')
+		} else {
+			buf.WriteString(query.ToString(field))
+		}
+	}
+	buf.WriteRune(')')
+	if q.tieBreakerMultiplier != 0 {
+		fmt.Fprintf(&buf, "~%v", q.tieBreakerMultiplier)
+	}
+	if q.boost != 1 {
+		fmt.Fprintf(&buf, "^%v", q.boost)
+	}
+	return buf.String()
+}
+
+/*
+Expert: the Weight for DisjunctionMaxQuery, used to normalize, score
+and explain these queries.
+*/
+type DisjunctionMaxWeight struct {
+	*WeightImpl
+	owner   *DisjunctionMaxQuery
+	weights []Weight // the Weights for our subqueries, in 1-1 correspondence with disjuncts
+}
+
+func newDisjunctionMaxWeight(owner *DisjunctionMaxQuery, ss *IndexSearcher) (*DisjunctionMaxWeight, error) {
+	ans := &DisjunctionMaxWeight{owner: owner}
+	for _, disjunct := range owner.disjuncts {
+		w, err := disjunct.CreateWeight(ss)
+		if err != nil {
+			return nil, err
+		}
+		ans.weights = append(ans.weights, w)
+	}
+	ans.WeightImpl = newWeightImpl(ans)
+	return ans, nil
+}
+
+func (w *DisjunctionMaxWeight) String() string {
+	return fmt.Sprintf("weight(%v)", w.owner)
+}
+
+// Computes the sum of squared weights of us and our subqueries.
+func (w *DisjunctionMaxWeight) ValueForNormalization() float32 {
+	var max, sum float32
+	for _, wt := range w.weights {
+		sub := wt.ValueForNormalization()
+		sum += sub
+		if sub > max {
+			max = sub
+		}
+	}
+	boost, tie := w.owner.boost, w.owner.tieBreakerMultiplier
+	return ((sum-max)*tie*tie + max) * boost * boost
+}
+
+// Applies the computed normalization factor to our subqueries.
+func (w *DisjunctionMaxWeight) Normalize(norm, topLevelBoost float32) {
+	topLevelBoost *= w.owner.boost // incorporate our boost
+	for _, wt := range w.weights {
+		wt.Normalize(norm, topLevelBoost)
+	}
+}
+
+func (w *DisjunctionMaxWeight) IsScoresDocsOutOfOrder() bool {
+	return false
+}
+
+// Creates the scorer used to score our associated DisjunctionMaxQuery.
+func (w *DisjunctionMaxWeight) Scorer(context *index.AtomicReaderContext,
+	acceptDocs util.Bits) (Scorer, error) {
+
+	var scorers []Scorer
+	for _, wt := range w.weights {
+		spi, ok := wt.(WeightImplSPI)
+		assert2(ok, "%v does not provide a Scorer", wt)
+		// we will advance() subscorers
+		subScorer, err := spi.Scorer(context, acceptDocs)
+		if err != nil {
+			return nil, err
+		}
+		if subScorer != nil {
+			scorers = append(scorers, subScorer)
+		}
+	}
+	switch len(scorers) {
+	case 0: // no sub-scorers had any documents
+		return nil, nil
+	case 1: // only one sub-scorer in this segment
+		return scorers[0], nil
+	}
+	return newDisjunctionMaxScorer(w, w.owner.tieBreakerMultiplier, scorers), nil
+}
+
+// Explains the score we computed for doc.
+func (w *DisjunctionMaxWeight) Explain(context *index.AtomicReaderContext, doc int) (Explanation, error) {
+	if len(w.weights) == 1 {
+		return w.weights[0].Explain(context, doc)
+	}
+	tie := w.owner.tieBreakerMultiplier
+	desc := "max of:"
+	if tie != 0 {
+		desc = fmt.Sprintf("max plus %v times others of:", tie)
+	}
+	result := newComplexExplanation(false, 0, desc)
+	var max, sum float32
+	for _, wt := range w.weights {
+		e, err := wt.Explain(context, doc)
+		if err != nil {
+			return nil, err
+		}
+		if e.IsMatch() {
+			result.match = true
+			result.addDetail(e)
+			sum += e.Value()
+			if e.Value() > max {
+				max = e.Value()
+			}
+		}
+	}
+	result.value = max + (sum-max)*tie
+	return result, nil
+}
+
+// search/DisjunctionMaxScorer.java
+
+/*
+The Scorer for DisjunctionMaxQuery. The union of all documents
+generated by the subquery scorers is generated in document number
+order. The score for each document is the maximum of the scores
+computed by the subquery scorers that generate that document, plus
+tieBreakerMultiplier times the sum of the scores for the other
+subqueries that generate the document.
+*/
+type DisjunctionMaxScorer struct {
+	*abstractScorer
+	disi *DisjunctionSumScorer
+	// Multiplier applied to non-maximum-scoring subqueries for a
+	// document as they are summed into the result.
+	tieBreakerMultiplier float32
+}
+
+func newDisjunctionMaxScorer(w Weight, tieBreakerMultiplier float32,
+	subScorers []Scorer) *DisjunctionMaxScorer {
+
+	ans := &DisjunctionMaxScorer{
+		disi:                 newDisjunctionSumScorer(w, subScorers, nil),
+		tieBreakerMultiplier: tieBreakerMultiplier,
+	}
+	ans.abstractScorer = newScorer(ans, w)
+	return ans
+}
+
+func (s *DisjunctionMaxScorer) DocId() int {
+	return s.disi.DocId()
+}
+
+func (s *DisjunctionMaxScorer) NextDoc() (int, error) {
+	return s.disi.NextDoc()
+}
+
+func (s *DisjunctionMaxScorer) Advance(target int) (int, error) {
+	return s.disi.Advance(target)
+}
+
+/*
+Determines the score of the current document as the maximum score of
+the matching sub scorers, plus the tie breaker times the others.
+*/
+func (s *DisjunctionMaxScorer) Score() (float32, error) {
+	var max, sum float32
+	doc := s.disi.DocId()
+	for _, sub := range s.disi.subScorers[:s.disi.numScorers] {
+		if sub.DocId() == doc {
+			score, err := sub.Score()
+			if err != nil {
+				return 0, err
+			}
+			sum += score
+			if score > max {
+				max = score
+			}
+		}
+	}
+	return max + (sum-max)*s.tieBreakerMultiplier, nil
+}
+
+func (s *DisjunctionMaxScorer) Freq() (int, error) {
+	return s.disi.Freq()
+}
+
+func (s *DisjunctionMaxScorer) String() string {
+	return fmt.Sprintf("DisjunctionMaxScorer(%v)", s.weight)
+}
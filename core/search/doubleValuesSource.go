@@ -0,0 +1,44 @@
+package search
+
+import (
+	"github.com/balzaczyy/golucene/core/index"
+)
+
+// search/DoubleValues.java
+
+// Per-segment, per-document double values, which can be calculated at
+// search-time.
+type DoubleValues interface {
+	// Get the double value for the current document.
+	DoubleValue() (float64, error)
+	/*
+		Advance this instance to the given document id, which is relative
+		to the segment. Returns true if there is a value for this document.
+	*/
+	AdvanceExact(doc int) (bool, error)
+}
+
+// search/DoubleValuesSource.java
+
+/*
+Base interface for producing DoubleValues.
+
+To obtain a DoubleValues object for a leaf reader, clients should
+call GetValues(). DoubleValuesSource objects can be used to sort
+search results, with NewDoubleValuesSortField().
+*/
+type DoubleValuesSource interface {
+	// Returns a DoubleValues instance for the passed-in segment.
+	GetValues(ctx *index.AtomicReaderContext) (DoubleValues, error)
+	String() string
+}
+
+/*
+Creates a SortField that sorts by the values of a DoubleValuesSource,
+in ascending order unless reverse is true. Documents without a value
+sort first.
+*/
+func NewDoubleValuesSortField(source DoubleValuesSource, reverse bool) *SortField {
+	assert(source != nil)
+	return &SortField{field: source.String(), typ: SORT_FIELD_CUSTOM, reverse: reverse, source: source}
+}
@@ -0,0 +1,96 @@
+package search
+
+import (
+	. "github.com/balzaczyy/golucene/core/index/model"
+)
+
+// search/ExactPhraseMatcher.java
+
+type postingsAndPosition struct {
+	postings        DocsAndPositionsEnum
+	offset          int
+	freq, upTo, pos int
+}
+
+/*
+Expert: find exact phrases. Each term is expected at its position in
+the phrase relative to the lead term, so positions of the other terms
+are only read up to the expected one, and the lead skips ahead as
+soon as a term is found beyond its expected position.
+*/
+type ExactPhraseMatcher struct {
+	postings []*postingsAndPosition
+}
+
+func newExactPhraseMatcher(postings []*postingsAndFreq) *ExactPhraseMatcher {
+	ans := &ExactPhraseMatcher{make([]*postingsAndPosition, len(postings))}
+	for i, p := range postings {
+		ans.postings[i] = &postingsAndPosition{postings: p.postings, offset: p.position}
+	}
+	return ans
+}
+
+func (m *ExactPhraseMatcher) reset() (err error) {
+	for _, p := range m.postings {
+		if p.freq, err = p.postings.Freq(); err != nil {
+			return err
+		}
+		p.pos, p.upTo = -1, 0
+	}
+	return nil
+}
+
+/*
+Advances the positions of the given postings to the first one on or
+after target. Returns false if its positions were exhausted before
+reaching target.
+*/
+func advancePosition(p *postingsAndPosition, target int) (ok bool, err error) {
+	for p.pos < target {
+		if p.upTo == p.freq {
+			return false, nil
+		}
+		if p.pos, err = p.postings.NextPosition(); err != nil {
+			return false, err
+		}
+		p.upTo++
+	}
+	return true, nil
+}
+
+func (m *ExactPhraseMatcher) nextMatch() (ok bool, err error) {
+	lead := m.postings[0]
+	if lead.upTo == lead.freq {
+		return false, nil
+	}
+	if lead.pos, err = lead.postings.NextPosition(); err != nil {
+		return false, err
+	}
+	lead.upTo++
+
+advanceHead:
+	for {
+		phrasePos := lead.pos - lead.offset
+		for _, p := range m.postings[1:] {
+			expectedPos := phrasePos + p.offset
+			// advance up to the same position as the lead
+			if ok, err = advancePosition(p, expectedPos); !ok || err != nil {
+				break advanceHead
+			}
+			if p.pos != expectedPos { // we advanced too far
+				if ok, err = advancePosition(lead, p.pos-p.offset+lead.offset); !ok || err != nil {
+					break advanceHead
+				}
+				continue advanceHead
+			}
+		}
+		return true, nil
+	}
+	// one of the terms has no more positions, so neither has the phrase
+	lead.upTo = lead.freq
+	return false, err
+}
+
+func (m *ExactPhraseMatcher) sloppyWeight(docScorer SimScorer) float32 {
+	return 1
+}
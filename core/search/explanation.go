@@ -37,6 +37,11 @@ func newExplanation(value float32, description string) *ExplanationImpl {
 	return ans
 }
 
+// Creates an Explanation with the given value and description.
+func NewExplanation(value float32, description string) *ExplanationImpl {
+	return newExplanation(value, description)
+}
+
 func (exp *ExplanationImpl) IsMatch() bool       { return exp.value > 0.0 }
 func (exp *ExplanationImpl) Value() float32      { return exp.value }
 func (exp *ExplanationImpl) Description() string { return exp.description }
@@ -55,6 +60,11 @@ func (exp *ExplanationImpl) addDetail(detail Explanation) {
 	exp.details = append(exp.details, detail)
 }
 
+// Adds a sub-node to this explanation node
+func (exp *ExplanationImpl) AddDetail(detail Explanation) {
+	exp.addDetail(detail)
+}
+
 // Render an explanation as text.
 func (exp *ExplanationImpl) String() string {
 	return explanationToString(exp.spi, 0)
@@ -102,6 +112,11 @@ func newComplexExplanation(match bool, value float32, desc string) *ComplexExpla
 	return ans
 }
 
+// Creates a ComplexExplanation with an explicit match status.
+func NewComplexExplanation(match bool, value float32, desc string) *ComplexExplanation {
+	return newComplexExplanation(match, value, desc)
+}
+
 /*
 Indicates whether or not this Explanation models a good match.
 
@@ -0,0 +1,26 @@
+package search
+
+import (
+	"testing"
+)
+
+func TestExplanation(t *testing.T) {
+	exp := NewComplexExplanation(true, 1.5, "sum of:")
+	exp.AddDetail(NewExplanation(1, "a"))
+	exp.AddDetail(NewExplanation(0.5, "b"))
+	if !exp.IsMatch() {
+		t.Error("Expected a match")
+	}
+	assertEquals(t, 2, len(exp.Details()))
+	assertEquals(t, "1.5 = (MATCH) sum of:\n  1 = a\n  0.5 = b\n", exp.String())
+
+	// the explicit match status wins over the value
+	exp = NewComplexExplanation(false, 1, "no match on required clause")
+	if exp.IsMatch() {
+		t.Error("Expected no match despite a positive value")
+	}
+	assertEquals(t, "1 = (NON_MATCH) no match on required clause\n", exp.String())
+	if NewExplanation(0, "zero").IsMatch() {
+		t.Error("Expected a zero value not to match")
+	}
+}
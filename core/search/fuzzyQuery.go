@@ -0,0 +1,192 @@
+package search
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/balzaczyy/golucene/core/index"
+	"sort"
+)
+
+// search/FuzzyQuery.java
+
+const (
+	FUZZY_DEFAULT_MAX_EDITS      = 2
+	FUZZY_DEFAULT_PREFIX_LENGTH  = 0
+	FUZZY_DEFAULT_MAX_EXPANSIONS = 50
+	FUZZY_DEFAULT_TRANSPOSITIONS = true
+
+	// the maximum supported edit distance
+	MAXIMUM_SUPPORTED_DISTANCE = 2
+)
+
+/*
+Implements the fuzzy search query. The similarity measurement is based
+on the Damerau-Levenshtein (optimal string alignment) algorithm.
+
+This query uses a boost-only rewrite: at most maxExpansions terms are
+kept, those with the smallest edit distance first, and each one is
+boosted by its similarity to the query term.
+
+At most, this query will match terms up to 2 edits. Higher distances
+are generally not useful and will match a significant amount of the
+term dictionary.
+*/
+type FuzzyQuery struct {
+	*MultiTermQuery
+	term           *index.Term
+	text           []rune
+	maxEdits       int
+	prefixLength   int
+	maxExpansions  int
+	transpositions bool
+}
+
+/* Creates a FuzzyQuery with the default settings. */
+func NewFuzzyQuery(term *index.Term) *FuzzyQuery {
+	return NewFuzzyQueryWith(term, FUZZY_DEFAULT_MAX_EDITS, FUZZY_DEFAULT_PREFIX_LENGTH,
+		FUZZY_DEFAULT_MAX_EXPANSIONS, FUZZY_DEFAULT_TRANSPOSITIONS)
+}
+
+/*
+Creates a FuzzyQuery that will match terms with an edit distance of
+at most maxEdits to term. If a prefixLength > 0 is specified, a
+common prefix of that length is also required.
+*/
+func NewFuzzyQueryWith(term *index.Term, maxEdits, prefixLength, maxExpansions int, transpositions bool) *FuzzyQuery {
+	assert2(maxEdits >= 0 && maxEdits <= MAXIMUM_SUPPORTED_DISTANCE,
+		"maxEdits must be between 0 and %v", MAXIMUM_SUPPORTED_DISTANCE)
+	assert2(prefixLength >= 0, "prefixLength cannot be negative.")
+	assert2(maxExpansions > 0, "maxExpansions must be positive.")
+	ans := &FuzzyQuery{
+		term:           term,
+		text:           []rune(string(term.Bytes)),
+		maxEdits:       maxEdits,
+		prefixLength:   prefixLength,
+		maxExpansions:  maxExpansions,
+		transpositions: transpositions,
+	}
+	ans.MultiTermQuery = NewMultiTermQuery(ans, term.Field)
+	return ans
+}
+
+// Returns the pattern term.
+func (q *FuzzyQuery) Term() *index.Term { return q.term }
+
+// Returns the maximum number of edit distances allowed for this query to match.
+func (q *FuzzyQuery) MaxEdits() int { return q.maxEdits }
+
+// Returns the non-fuzzy prefix length.
+func (q *FuzzyQuery) PrefixLength() int { return q.prefixLength }
+
+// Returns true if transpositions should be treated as a primitive edit operation.
+func (q *FuzzyQuery) Transpositions() bool { return q.transpositions }
+
+func (q *FuzzyQuery) AcceptTerm(term []byte) bool {
+	_, ok := q.distance([]rune(string(term)))
+	return ok
+}
+
+/* Returns the edit distance between the query term and candidate, if within maxEdits. */
+func (q *FuzzyQuery) distance(candidate []rune) (int, bool) {
+	if len(candidate) < q.prefixLength || len(q.text) < q.prefixLength {
+		if string(candidate) != string(q.text) {
+			return 0, false
+		}
+		return 0, true
+	}
+	if string(candidate[:q.prefixLength]) != string(q.text[:q.prefixLength]) {
+		return 0, false
+	}
+	a, b := q.text[q.prefixLength:], candidate[q.prefixLength:]
+	if diff := len(a) - len(b); diff > q.maxEdits || -diff > q.maxEdits {
+		return 0, false
+	}
+	d := editDistance(a, b, q.transpositions)
+	return d, d <= q.maxEdits
+}
+
+/* Computes the optimal string alignment distance between a and b. */
+func editDistance(a, b []rune, transpositions bool) int {
+	rows := make([][]int, len(a)+1)
+	for i := range rows {
+		rows[i] = make([]int, len(b)+1)
+		rows[i][0] = i
+	}
+	for j := range rows[0] {
+		rows[0][j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d := min(rows[i-1][j]+1, rows[i][j-1]+1, rows[i-1][j-1]+cost)
+			if transpositions && i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d = min(d, rows[i-2][j-2]+1)
+			}
+			rows[i][j] = d
+		}
+	}
+	return rows[len(a)][len(b)]
+}
+
+func (q *FuzzyQuery) Rewrite(reader index.IndexReader) Query {
+	type scoredTerm struct {
+		term  []byte
+		boost float32
+	}
+	var scored []scoredTerm
+	for _, term := range collectTerms(reader, q.field, q.AcceptTerm) {
+		candidate := []rune(string(term))
+		dist, _ := q.distance(candidate)
+		size := min(len(q.text), len(candidate))
+		boost := float32(1)
+		if size > 0 {
+			boost = 1 - float32(dist)/float32(size)
+		}
+		if boost < 0 {
+			boost = 0
+		}
+		scored = append(scored, scoredTerm{term, boost})
+	}
+	// keep the best terms, in term order for equal boosts
+	sort.SliceStable(scored, func(i, j int) bool { return scored[i].boost > scored[j].boost })
+	if len(scored) > q.maxExpansions {
+		scored = scored[:q.maxExpansions]
+	}
+	bq := NewBooleanQueryDisableCoord(true)
+	for _, st := range scored {
+		tq := NewTermQuery(index.NewTermFromBytes(q.field, st.term))
+		tq.SetBoost(q.boost * st.boost)
+		bq.Add(tq, SHOULD)
+	}
+	return bq
+}
+
+func (q *FuzzyQuery) ToString(field string) string {
+	var buf bytes.Buffer
+	if q.field != field {
+		buf.WriteString(q.field)
+		buf.WriteRune(':')
+	}
+	buf.Write(q.term.Bytes)
+	fmt.Fprintf(&buf, "~%v", q.maxEdits)
+	if q.boost != 1 {
+		fmt.Fprintf(&buf, "^%v", q.boost)
+	}
+	return buf.String()
+}
+
+/*
+Helper function to convert from deprecated "minimumSimilarity"
+fractions to raw edit distances.
+*/
+func FloatToEdits(minimumSimilarity float32, termLen int) int {
+	if minimumSimilarity >= 1 {
+		return min(int(minimumSimilarity), MAXIMUM_SUPPORTED_DISTANCE)
+	} else if minimumSimilarity == 0 {
+		return 0 // 0 means exact, not infinite # of edits!
+	}
+	return min(int((1-minimumSimilarity)*float32(termLen)), MAXIMUM_SUPPORTED_DISTANCE)
+}
@@ -0,0 +1,226 @@
+package search
+
+import (
+	"fmt"
+	"github.com/balzaczyy/golucene/core/index"
+	"github.com/balzaczyy/golucene/core/util"
+	"reflect"
+)
+
+// search/intervals/IntervalQuery.java
+
+/*
+A query that retrieves documents containing intervals of an
+IntervalsSource, e.g.
+
+	NewIntervalQuery("text", IntervalsMaxGaps(
+		IntervalsOrdered(IntervalsTerm("big"), IntervalsTerm("wolf")), 1))
+
+matches documents with "big" followed by "wolf", with at most one
+position between them. Like the ones of the span queries, the terms
+of the source are scored as a whole, the frequency of a document
+being the sum of the slop factors of the widths of its intervals, so
+that shorter intervals score higher.
+*/
+type IntervalQuery struct {
+	*AbstractQuery
+	field  string
+	source IntervalsSource
+}
+
+// Create an IntervalQuery of the source, over the field.
+func NewIntervalQuery(field string, source IntervalsSource) *IntervalQuery {
+	ans := &IntervalQuery{field: field, source: source}
+	ans.AbstractQuery = NewAbstractQuery(ans)
+	return ans
+}
+
+func (q *IntervalQuery) Field() string {
+	return q.field
+}
+
+func (q *IntervalQuery) IntervalsSource() IntervalsSource {
+	return q.source
+}
+
+func (q *IntervalQuery) CreateWeight(ss *IndexSearcher) (Weight, error) {
+	return newIntervalWeight(q, ss)
+}
+
+func (q *IntervalQuery) ExtractTerms(terms *index.TermSet) {
+	q.source.ExtractTerms(q.field, terms)
+}
+
+func (q *IntervalQuery) Visit(visitor QueryVisitor) {
+	q.source.Visit(q.field, q, visitor)
+}
+
+func (q *IntervalQuery) ToString(field string) string {
+	s := fmt.Sprintf("IntervalQuery(%v,%v)", q.field, q.source)
+	if q.boost != 1 {
+		s = fmt.Sprintf("%v^%v", s, q.boost)
+	}
+	return s
+}
+
+/* The Weight of an IntervalQuery, scoring its terms like a SpanWeight. */
+type intervalWeight struct {
+	*WeightImpl
+	query      *IntervalQuery
+	similarity Similarity
+	stats      SimWeight
+}
+
+func newIntervalWeight(query *IntervalQuery, ss *IndexSearcher) (*intervalWeight, error) {
+	terms := index.NewTermSet()
+	query.ExtractTerms(terms)
+	ctx := ss.TopReaderContext()
+	termStats := make([]TermStatistics, len(terms.Terms))
+	for i, term := range terms.Terms {
+		state, err := index.NewTermContextFromTerm(ctx, term)
+		if err != nil {
+			return nil, err
+		}
+		termStats[i] = ss.spi.TermStatistics(term, state)
+	}
+	ans := &intervalWeight{query: query, similarity: ss.similarity}
+	ans.stats = ans.similarity.computeWeight(query.Boost(),
+		ss.spi.CollectionStatistics(query.field), termStats...)
+	ans.WeightImpl = newWeightImpl(ans)
+	return ans, nil
+}
+
+func (w *intervalWeight) String() string {
+	return fmt.Sprintf("weight(%v)", w.query)
+}
+
+func (w *intervalWeight) ValueForNormalization() float32 {
+	return w.stats.ValueForNormalization()
+}
+
+func (w *intervalWeight) Normalize(norm float32, topLevelBoost float32) {
+	w.stats.Normalize(norm, topLevelBoost)
+}
+
+func (w *intervalWeight) IsScoresDocsOutOfOrder() bool {
+	return false
+}
+
+func (w *intervalWeight) Scorer(context *index.AtomicReaderContext,
+	acceptDocs util.Bits) (Scorer, error) {
+
+	scorer, err := w.intervalScorer(context, acceptDocs)
+	if scorer == nil || err != nil {
+		return nil, err
+	}
+	return scorer, nil
+}
+
+func (w *intervalWeight) intervalScorer(context *index.AtomicReaderContext,
+	acceptDocs util.Bits) (*intervalScorer, error) {
+
+	intervals, err := w.query.source.Intervals(w.query.field, context, acceptDocs)
+	if intervals == nil || err != nil {
+		return nil, err
+	}
+	docScorer, err := w.similarity.simScorer(w.stats, context)
+	if err != nil {
+		return nil, err
+	}
+	ans := &intervalScorer{intervals: intervals, docScorer: docScorer, lastScoredDoc: -1}
+	ans.abstractScorer = newScorer(ans, w)
+	return ans, nil
+}
+
+func (w *intervalWeight) Explain(context *index.AtomicReaderContext, doc int) (Explanation, error) {
+	scorer, err := w.intervalScorer(context, context.Reader().(index.AtomicReader).LiveDocs())
+	if err != nil {
+		return nil, err
+	}
+	if scorer != nil {
+		newDoc, err := scorer.Advance(doc)
+		if err != nil {
+			return nil, err
+		}
+		if newDoc == doc {
+			if err = scorer.ensureFreq(); err != nil {
+				return nil, err
+			}
+			scoreExplanation := scorer.docScorer.explain(doc,
+				newExplanation(scorer.freq, fmt.Sprintf("intervalFreq=%v", scorer.freq)))
+			ans := newComplexExplanation(true,
+				scoreExplanation.(*ExplanationImpl).value,
+				fmt.Sprintf("weight(%v in %v) [%v], result of:",
+					w.query, doc, reflect.TypeOf(w.similarity)))
+			ans.details = []Explanation{scoreExplanation}
+			return ans, nil
+		}
+	}
+	return newComplexExplanation(false, 0, "no matching intervals"), nil
+}
+
+/*
+A Scorer over an IntervalIterator. The frequency of a doc is the sum
+of the slop factors of the widths of all its intervals.
+*/
+type intervalScorer struct {
+	*abstractScorer
+	intervals     IntervalIterator
+	docScorer     SimScorer
+	freq          float32 // accumulated sloppy freq (computed in ensureFreq)
+	numMatches    int     // number of intervals (computed in ensureFreq)
+	lastScoredDoc int
+}
+
+func (s *intervalScorer) DocId() int {
+	return s.intervals.DocId()
+}
+
+func (s *intervalScorer) NextDoc() (int, error) {
+	return s.intervals.NextDoc()
+}
+
+func (s *intervalScorer) Advance(target int) (int, error) {
+	return s.intervals.Advance(target)
+}
+
+// Iterates over the intervals of the current doc to compute its
+// frequency, if not already done.
+func (s *intervalScorer) ensureFreq() error {
+	doc := s.intervals.DocId()
+	if s.lastScoredDoc == doc {
+		return nil
+	}
+	s.lastScoredDoc = doc
+	s.freq, s.numMatches = 0, 0
+	for {
+		start, err := s.intervals.NextInterval()
+		if err != nil {
+			return err
+		}
+		if start == NO_MORE_INTERVALS {
+			return nil
+		}
+		s.numMatches++
+		s.freq += s.docScorer.computeSlopFactor(s.intervals.End() - start)
+	}
+}
+
+func (s *intervalScorer) Score() (float32, error) {
+	if err := s.ensureFreq(); err != nil {
+		return 0, err
+	}
+	return s.docScorer.Score(s.intervals.DocId(), s.freq), nil
+}
+
+// Returns the number of intervals of current doc.
+func (s *intervalScorer) Freq() (int, error) {
+	if err := s.ensureFreq(); err != nil {
+		return 0, err
+	}
+	return s.numMatches, nil
+}
+
+func (s *intervalScorer) String() string {
+	return fmt.Sprintf("scorer(%v)", s.weight)
+}
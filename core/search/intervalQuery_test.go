@@ -0,0 +1,105 @@
+package search
+
+import (
+	"fmt"
+	std "github.com/balzaczyy/golucene/analysis/standard"
+	"github.com/balzaczyy/golucene/core/index"
+	"github.com/balzaczyy/golucene/core/store"
+	"github.com/balzaczyy/golucene/core/util"
+	"math"
+	"sort"
+	"testing"
+)
+
+func TestIntervalQuery(t *testing.T) {
+	dir, err := store.OpenFSDirectory(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := index.NewIndexWriter(dir, index.NewIndexWriterConfig(util.VERSION_LATEST, std.NewStandardAnalyzer()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, title := range []string{
+		"big bad wolf",
+		"wolf big",
+		"big wolf wolf",
+		"big red hungry wolf",
+		"red wolf big bad",
+		"big big wolf",
+		"big wolf and big wolf",
+	} {
+		addDocument(t, w, title)
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	r, err := index.OpenDirectoryReader(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	ss := NewIndexSearcher(r)
+
+	search := func(source IntervalsSource) *freqCollector {
+		c := &freqCollector{freqs: make(map[int]int), scores: make(map[int]float32)}
+		if err := ss.SearchCollector(NewIntervalQuery("title", source), c); err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+	docs := func(source IntervalsSource) string {
+		var ans []int
+		for doc := range search(source).freqs {
+			ans = append(ans, doc)
+		}
+		sort.Ints(ans)
+		return fmt.Sprint(ans)
+	}
+	big, wolf := IntervalsTerm("big"), IntervalsTerm("wolf")
+	red, bad, fox := IntervalsTerm("red"), IntervalsTerm("bad"), IntervalsTerm("fox")
+
+	assertEquals(t, "[0 2 3 5 6]", docs(IntervalsOrdered(big, wolf)))
+	assertEquals(t, "[0 2 5 6]", docs(IntervalsMaxGaps(IntervalsOrdered(big, wolf), 1)))
+	assertEquals(t, "[2 5 6]", docs(IntervalsMaxGaps(IntervalsOrdered(big, wolf), 0)))
+	assertEquals(t, "[5 6]", docs(IntervalsOrdered(big, big, wolf)))
+	assertEquals(t, "[2 6]", docs(IntervalsOrdered(wolf, wolf)))
+	assertEquals(t, "[0 1 2 3 4 5 6]", docs(IntervalsUnordered(big, wolf)))
+	assertEquals(t, "[1 2 4 5 6]", docs(IntervalsMaxGaps(IntervalsUnordered(big, wolf), 0)))
+	assertEquals(t, "[3]", docs(IntervalsContaining(IntervalsOrdered(big, wolf), red)))
+	assertEquals(t, "[0 2 5 6]", docs(IntervalsNotContaining(IntervalsOrdered(big, wolf), red)))
+	assertEquals(t, "[1 2 4 5 6]", docs(IntervalsNotContaining(
+		IntervalsMaxGaps(IntervalsUnordered(big, wolf), 1), bad)))
+	assertEquals(t, "[4]", docs(IntervalsContaining(IntervalsUnordered(red, big), wolf)))
+	// terms missing from the index
+	assertEquals(t, "[]", docs(IntervalsOrdered(big, fox)))
+	assertEquals(t, "[0 2 3 5 6]", docs(IntervalsNotContaining(IntervalsOrdered(big, wolf), fox)))
+
+	// minimal intervals only
+	c := search(IntervalsOrdered(big, wolf))
+	assertEquals(t, 1, c.freqs[5])
+	assertEquals(t, 2, c.freqs[6])
+	if c.scores[2] <= c.scores[3] {
+		t.Errorf("Expected shorter intervals to score higher, but was %v <= %v", c.scores[2], c.scores[3])
+	}
+
+	q := NewIntervalQuery("title", IntervalsNotContaining(
+		IntervalsMaxGaps(IntervalsOrdered(big, wolf), 1), red))
+	assertEquals(t, "IntervalQuery(title,NOT_CONTAINING(MAXGAPS/1(ORDERED(big,wolf)),red))", q.String())
+	terms := index.NewTermSet()
+	q.Visit(NewTermCollector(terms))
+	if terms.Size() != 2 || !terms.Contains(index.NewTerm("title", "big")) ||
+		terms.Contains(index.NewTerm("title", "red")) {
+		t.Errorf("Expected terms title:big and title:wolf, but was %v", terms.Terms)
+	}
+	exp, err := ss.Explain(q, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !exp.IsMatch() || math.Abs(float64(exp.Value()-search(q.IntervalsSource()).scores[0])) > 1e-6 {
+		t.Errorf("Unexpected explanation %v", exp)
+	}
+	if exp, err = ss.Explain(q, 3); err != nil || exp.IsMatch() {
+		t.Errorf("Expected no match for doc 3, but was %v (%v)", exp, err)
+	}
+}
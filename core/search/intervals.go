@@ -0,0 +1,923 @@
+package search
+
+import (
+	"bytes"
+	"container/heap"
+	"fmt"
+	"github.com/balzaczyy/golucene/core/index"
+	. "github.com/balzaczyy/golucene/core/index/model"
+	. "github.com/balzaczyy/golucene/core/search/model"
+	"github.com/balzaczyy/golucene/core/util"
+	"math"
+)
+
+// search/intervals/IntervalIterator.java
+
+const NO_MORE_INTERVALS = math.MaxInt32
+
+/*
+A DocIdSetIterator that also allows iteration over matching intervals
+in a document. Once the iterator is positioned on a document by
+NextDoc() or Advance(), the intervals of the document are iterated by
+NextInterval(), in order of increasing start, and then increasing end
+position. Positions are inclusive: a single term at position 3 is the
+interval [3, 3].
+
+Unlike Lucene's, the iterators here only stop on documents with at
+least one interval, so that a document an iterator is on always
+matches.
+*/
+type IntervalIterator interface {
+	DocIdSetIterator
+	/*
+		The start of the current interval: -1 if NextInterval() has not
+		yet been called on the current doc, NO_MORE_INTERVALS once the
+		intervals of the doc are exhausted.
+	*/
+	Start() int
+	// The end of the current interval, like Start().
+	End() int
+	/*
+		The number of gaps within the current interval, i.e. the number
+		of positions not covered by the sub intervals it was built from.
+	*/
+	Gaps() int
+	// Advances to the next interval and returns its start position.
+	NextInterval() (int, error)
+}
+
+// Returns the number of positions covered by the current interval.
+func intervalWidth(it IntervalIterator) int {
+	return it.End() - it.Start() + 1
+}
+
+// search/intervals/IntervalsSource.java
+
+/*
+A helper to build intervals from a segment, by creating an
+IntervalIterator for the field. Sources are composed with the
+functions of Intervals*, e.g.
+
+	IntervalsMaxGaps(IntervalsOrdered(IntervalsTerm("big"), IntervalsTerm("wolf")), 2)
+
+and searched with an IntervalQuery.
+*/
+type IntervalsSource interface {
+	/*
+		Creates an IntervalIterator of the field in the segment, or
+		returns nil if no documents of the segment can match.
+	*/
+	Intervals(field string, context *index.AtomicReaderContext, acceptDocs util.Bits) (IntervalIterator, error)
+	// Adds the terms of the source to terms.
+	ExtractTerms(field string, terms *index.TermSet)
+	// Visits the leaves of the source, as the ones of query.
+	Visit(field string, query Query, visitor QueryVisitor)
+	/*
+		Returns the minimum possible width of an interval returned by
+		the source.
+	*/
+	MinExtent() int
+	String() string
+}
+
+// Joins the string representations of the sources with ",".
+func sourcesString(name string, sources ...IntervalsSource) string {
+	var buf bytes.Buffer
+	buf.WriteString(name)
+	buf.WriteRune('(')
+	for i, source := range sources {
+		if i > 0 {
+			buf.WriteRune(',')
+		}
+		buf.WriteString(source.String())
+	}
+	buf.WriteRune(')')
+	return buf.String()
+}
+
+// search/intervals/TermIntervalsSource.java
+
+type termIntervalsSource struct {
+	term []byte
+}
+
+// Returns intervals for a single term.
+func IntervalsTerm(term string) IntervalsSource {
+	return &termIntervalsSource{[]byte(term)}
+}
+
+func (s *termIntervalsSource) Intervals(field string, context *index.AtomicReaderContext,
+	acceptDocs util.Bits) (IntervalIterator, error) {
+
+	terms := context.Reader().(index.AtomicReader).Terms(field)
+	if terms == nil {
+		return nil, nil
+	}
+	te := terms.Iterator(nil)
+	if ok, err := te.SeekExact(s.term); !ok || err != nil {
+		return nil, err
+	}
+	postings, err := te.DocsAndPositionsByFlags(acceptDocs, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	assert2(postings != nil,
+		"field \"%v\" was indexed without position data; cannot run IntervalQuery (term=%v)",
+		field, string(s.term))
+	return &termIntervalIterator{postings: postings, doc: -1, pos: -1}, nil
+}
+
+func (s *termIntervalsSource) ExtractTerms(field string, terms *index.TermSet) {
+	terms.Add(index.NewTermFromBytes(field, s.term))
+}
+
+func (s *termIntervalsSource) Visit(field string, query Query, visitor QueryVisitor) {
+	if visitor.AcceptField(field) {
+		visitor.ConsumeTerms(query, index.NewTermFromBytes(field, s.term))
+	}
+}
+
+func (s *termIntervalsSource) MinExtent() int {
+	return 1
+}
+
+func (s *termIntervalsSource) String() string {
+	return string(s.term)
+}
+
+// Iterates over the positions of a term, each an interval of width 1.
+type termIntervalIterator struct {
+	postings DocsAndPositionsEnum
+	doc      int
+	freq     int
+	upto     int
+	pos      int
+}
+
+func (it *termIntervalIterator) DocId() int {
+	return it.doc
+}
+
+func (it *termIntervalIterator) NextDoc() (int, error) {
+	doc, err := it.postings.NextDoc()
+	if err != nil {
+		return 0, err
+	}
+	return it.toDoc(doc)
+}
+
+func (it *termIntervalIterator) Advance(target int) (int, error) {
+	doc, err := it.postings.Advance(target)
+	if err != nil {
+		return 0, err
+	}
+	return it.toDoc(doc)
+}
+
+func (it *termIntervalIterator) toDoc(doc int) (int, error) {
+	it.doc, it.pos, it.upto = doc, -1, 0
+	if doc != NO_MORE_DOCS {
+		freq, err := it.postings.Freq()
+		if err != nil {
+			return 0, err
+		}
+		it.freq = freq
+	}
+	return doc, nil
+}
+
+func (it *termIntervalIterator) Start() int {
+	return it.pos
+}
+
+func (it *termIntervalIterator) End() int {
+	return it.pos
+}
+
+func (it *termIntervalIterator) Gaps() int {
+	return 0
+}
+
+func (it *termIntervalIterator) NextInterval() (int, error) {
+	if it.upto == it.freq {
+		it.pos = NO_MORE_INTERVALS
+		return it.pos, nil
+	}
+	it.upto++
+	pos, err := it.postings.NextPosition()
+	if err != nil {
+		return 0, err
+	}
+	it.pos = pos
+	return pos, nil
+}
+
+// search/intervals/ConjunctionIntervalIterator.java
+
+type conjunctionIntervalIteratorSPI interface {
+	// Resets the state for the current doc, which all subIterators are on.
+	reset() error
+	/*
+		Computes the next interval of the current doc from the
+		subIterators, setting start and end, and returns its start.
+	*/
+	computeNextInterval() (int, error)
+}
+
+/*
+Common super class for the iterators combining the intervals of sub
+iterators required in a document. Iterates over the conjunction of
+the sub iterators, and only stops on a doc once it computed a first
+interval of the doc, which the next call to NextInterval() returns.
+*/
+type conjunctionIntervalIterator struct {
+	spi          conjunctionIntervalIteratorSPI
+	subIterators []IntervalIterator
+	doc          int
+	start, end   int
+	atFirst      bool // the first interval of the doc is computed, but not returned yet
+}
+
+func newConjunctionIntervalIterator(spi conjunctionIntervalIteratorSPI,
+	subIterators []IntervalIterator) *conjunctionIntervalIterator {
+
+	return &conjunctionIntervalIterator{
+		spi:          spi,
+		subIterators: subIterators,
+		doc:          -1,
+		start:        -1,
+		end:          -1,
+	}
+}
+
+func (it *conjunctionIntervalIterator) DocId() int {
+	return it.doc
+}
+
+func (it *conjunctionIntervalIterator) NextDoc() (int, error) {
+	doc, err := it.subIterators[0].NextDoc()
+	if err != nil {
+		return 0, err
+	}
+	return it.toMatchDoc(doc)
+}
+
+func (it *conjunctionIntervalIterator) Advance(target int) (int, error) {
+	doc, err := it.subIterators[0].Advance(target)
+	if err != nil {
+		return 0, err
+	}
+	return it.toMatchDoc(doc)
+}
+
+/*
+Leap-frogs from the given doc of the first sub iterator to the next
+doc that contains all sub iterators and an interval of them.
+*/
+func (it *conjunctionIntervalIterator) toMatchDoc(doc int) (int, error) {
+	lead := it.subIterators[0]
+	for doc != NO_MORE_DOCS {
+		next, err := it.advanceOthers(doc)
+		if err != nil {
+			return 0, err
+		}
+		if next != doc {
+			if doc, err = lead.Advance(next); err != nil {
+				return 0, err
+			}
+			continue
+		}
+		it.doc = doc
+		if err = it.spi.reset(); err != nil {
+			return 0, err
+		}
+		start, err := it.spi.computeNextInterval()
+		if err != nil {
+			return 0, err
+		}
+		if start != NO_MORE_INTERVALS {
+			it.atFirst = true
+			return doc, nil
+		}
+		if doc, err = lead.NextDoc(); err != nil {
+			return 0, err
+		}
+	}
+	it.doc, it.atFirst = NO_MORE_DOCS, false
+	return NO_MORE_DOCS, nil
+}
+
+/*
+Advances the other sub iterators to doc, returning doc if all of them
+are on it, or else the first doc after it they may share.
+*/
+func (it *conjunctionIntervalIterator) advanceOthers(doc int) (int, error) {
+	for _, sub := range it.subIterators[1:] {
+		next := sub.DocId()
+		if next < doc {
+			var err error
+			if next, err = sub.Advance(doc); err != nil {
+				return 0, err
+			}
+		}
+		if next > doc {
+			return next, nil
+		}
+	}
+	return doc, nil
+}
+
+func (it *conjunctionIntervalIterator) Start() int {
+	if it.atFirst {
+		return -1
+	}
+	return it.start
+}
+
+func (it *conjunctionIntervalIterator) End() int {
+	if it.atFirst {
+		return -1
+	}
+	return it.end
+}
+
+func (it *conjunctionIntervalIterator) NextInterval() (int, error) {
+	if it.atFirst {
+		it.atFirst = false
+		return it.start, nil
+	}
+	return it.spi.computeNextInterval()
+}
+
+// search/intervals/OrderedIntervalsSource.java
+
+type orderedIntervalsSource struct {
+	sources []IntervalsSource
+}
+
+/*
+Create an ordered IntervalsSource: the minimal intervals that contain
+an interval of each of the sources, in order and not overlapping.
+*/
+func IntervalsOrdered(sources ...IntervalsSource) IntervalsSource {
+	assert2(len(sources) > 0, "ordered intervals need at least one source")
+	if len(sources) == 1 {
+		return sources[0]
+	}
+	return &orderedIntervalsSource{sources}
+}
+
+func (s *orderedIntervalsSource) Intervals(field string, context *index.AtomicReaderContext,
+	acceptDocs util.Bits) (IntervalIterator, error) {
+
+	subIterators, err := subIntervals(s.sources, field, context, acceptDocs)
+	if subIterators == nil || err != nil {
+		return nil, err
+	}
+	ans := &orderedIntervalIterator{}
+	ans.conjunctionIntervalIterator = newConjunctionIntervalIterator(ans, subIterators)
+	return ans, nil
+}
+
+func (s *orderedIntervalsSource) ExtractTerms(field string, terms *index.TermSet) {
+	for _, source := range s.sources {
+		source.ExtractTerms(field, terms)
+	}
+}
+
+func (s *orderedIntervalsSource) Visit(field string, query Query, visitor QueryVisitor) {
+	visitSources(field, query, visitor, MUST, s.sources...)
+}
+
+func (s *orderedIntervalsSource) MinExtent() int {
+	extent := 0
+	for _, source := range s.sources {
+		extent += source.MinExtent()
+	}
+	return extent
+}
+
+func (s *orderedIntervalsSource) String() string {
+	return sourcesString("ORDERED", s.sources...)
+}
+
+/*
+Returns the iterators of the sources, or nil if any of them has no
+matches in the segment.
+*/
+func subIntervals(sources []IntervalsSource, field string, context *index.AtomicReaderContext,
+	acceptDocs util.Bits) ([]IntervalIterator, error) {
+
+	ans := make([]IntervalIterator, len(sources))
+	for i, source := range sources {
+		it, err := source.Intervals(field, context, acceptDocs)
+		if it == nil || err != nil {
+			return nil, err // all required
+		}
+		ans[i] = it
+	}
+	return ans, nil
+}
+
+// Visits the sources with a sub visitor of the occur.
+func visitSources(field string, query Query, visitor QueryVisitor, occur Occur, sources ...IntervalsSource) {
+	if !visitor.AcceptField(field) {
+		return
+	}
+	if sub := visitor.SubVisitor(occur, query); sub != nil {
+		for _, source := range sources {
+			source.Visit(field, query, sub)
+		}
+	}
+}
+
+type orderedIntervalIterator struct {
+	*conjunctionIntervalIterator
+	slop int
+}
+
+func (it *orderedIntervalIterator) reset() error {
+	if _, err := it.subIterators[0].NextInterval(); err != nil {
+		return err
+	}
+	it.start, it.end, it.slop = -1, -1, -1
+	return nil
+}
+
+func (it *orderedIntervalIterator) computeNextInterval() (int, error) {
+	subs := it.subIterators
+	it.start, it.end, it.slop = NO_MORE_INTERVALS, NO_MORE_INTERVALS, NO_MORE_INTERVALS
+	lastStart := math.MaxInt32
+	minimizing := false
+	i := 1
+	for {
+		for {
+			if subs[i-1].End() >= lastStart {
+				return it.start, nil
+			}
+			if i == len(subs) || (minimizing && subs[i].Start() > subs[i-1].End()) {
+				break
+			}
+			for {
+				if subs[i].End() >= lastStart {
+					return it.start, nil
+				}
+				next, err := subs[i].NextInterval()
+				if err != nil {
+					return 0, err
+				}
+				if next == NO_MORE_INTERVALS {
+					return it.start, nil
+				}
+				if subs[i].Start() > subs[i-1].End() {
+					break
+				}
+			}
+			i++
+		}
+		it.start = subs[0].Start()
+		if it.start == NO_MORE_INTERVALS {
+			it.end = NO_MORE_INTERVALS
+			return it.start, nil
+		}
+		it.end = subs[len(subs)-1].End()
+		it.slop = it.end - it.start + 1
+		for _, sub := range subs {
+			it.slop -= intervalWidth(sub)
+		}
+		lastStart = subs[len(subs)-1].Start()
+		i = 1
+		next, err := subs[0].NextInterval()
+		if err != nil {
+			return 0, err
+		}
+		if next == NO_MORE_INTERVALS {
+			return it.start, nil
+		}
+		minimizing = true
+	}
+}
+
+func (it *orderedIntervalIterator) Gaps() int {
+	return it.slop
+}
+
+// search/intervals/UnorderedIntervalsSource.java
+
+type unorderedIntervalsSource struct {
+	sources []IntervalsSource
+}
+
+/*
+Create an unordered IntervalsSource: the minimal intervals that
+contain an interval of each of the sources, in any order. The
+intervals of the sources may overlap.
+*/
+func IntervalsUnordered(sources ...IntervalsSource) IntervalsSource {
+	assert2(len(sources) > 0, "unordered intervals need at least one source")
+	if len(sources) == 1 {
+		return sources[0]
+	}
+	return &unorderedIntervalsSource{sources}
+}
+
+func (s *unorderedIntervalsSource) Intervals(field string, context *index.AtomicReaderContext,
+	acceptDocs util.Bits) (IntervalIterator, error) {
+
+	subIterators, err := subIntervals(s.sources, field, context, acceptDocs)
+	if subIterators == nil || err != nil {
+		return nil, err
+	}
+	ans := &unorderedIntervalIterator{}
+	ans.conjunctionIntervalIterator = newConjunctionIntervalIterator(ans, subIterators)
+	return ans, nil
+}
+
+func (s *unorderedIntervalsSource) ExtractTerms(field string, terms *index.TermSet) {
+	for _, source := range s.sources {
+		source.ExtractTerms(field, terms)
+	}
+}
+
+func (s *unorderedIntervalsSource) Visit(field string, query Query, visitor QueryVisitor) {
+	visitSources(field, query, visitor, MUST, s.sources...)
+}
+
+func (s *unorderedIntervalsSource) MinExtent() int {
+	extent := 0
+	for _, source := range s.sources {
+		extent = max(extent, source.MinExtent())
+	}
+	return extent
+}
+
+func (s *unorderedIntervalsSource) String() string {
+	return sourcesString("UNORDERED", s.sources...)
+}
+
+/*
+A queue of the sub iterators, by increasing start, and then by
+decreasing end, so that the top is the interval to move to minimize
+the current one.
+*/
+type intervalQueue []IntervalIterator
+
+func (q intervalQueue) Len() int { return len(q) }
+func (q intervalQueue) Less(i, j int) bool {
+	return q[i].Start() < q[j].Start() || q[i].Start() == q[j].Start() && q[i].End() >= q[j].End()
+}
+func (q intervalQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *intervalQueue) Push(x interface{}) { *q = append(*q, x.(IntervalIterator)) }
+func (q *intervalQueue) Pop() interface{} {
+	n := len(*q)
+	ans := (*q)[n-1]
+	*q = (*q)[:n-1]
+	return ans
+}
+
+type unorderedIntervalIterator struct {
+	*conjunctionIntervalIterator
+	queue    intervalQueue
+	queueEnd int
+	slop     int
+}
+
+func (it *unorderedIntervalIterator) reset() error {
+	it.queueEnd, it.start, it.end, it.slop = -1, -1, -1, -1
+	it.queue = it.queue[:0]
+	for _, sub := range it.subIterators {
+		next, err := sub.NextInterval()
+		if err != nil {
+			return err
+		}
+		if next == NO_MORE_INTERVALS {
+			break
+		}
+		it.queue = append(it.queue, sub)
+		it.queueEnd = max(it.queueEnd, sub.End())
+	}
+	heap.Init(&it.queue)
+	return nil
+}
+
+// Moves the top of the queue to its next interval, dropping it if exhausted.
+func (it *unorderedIntervalIterator) advanceTop() error {
+	top := heap.Pop(&it.queue).(IntervalIterator)
+	next, err := top.NextInterval()
+	if err != nil {
+		return err
+	}
+	if next != NO_MORE_INTERVALS {
+		heap.Push(&it.queue, top)
+		it.queueEnd = max(it.queueEnd, top.End())
+	}
+	return nil
+}
+
+func (it *unorderedIntervalIterator) computeNextInterval() (int, error) {
+	n := len(it.subIterators)
+	// first, find a matching interval
+	for len(it.queue) == n && it.queue[0].Start() == it.start {
+		if err := it.advanceTop(); err != nil {
+			return 0, err
+		}
+	}
+	if len(it.queue) < n {
+		it.start, it.end = NO_MORE_INTERVALS, NO_MORE_INTERVALS
+		return it.start, nil
+	}
+	// then, minimize it
+	for {
+		it.start, it.end = it.queue[0].Start(), it.queueEnd
+		it.slop = it.end - it.start + 1
+		for _, sub := range it.subIterators {
+			it.slop -= intervalWidth(sub)
+		}
+		if it.queue[0].End() == it.end {
+			return it.start, nil
+		}
+		if err := it.advanceTop(); err != nil {
+			return 0, err
+		}
+		if len(it.queue) < n || it.end != it.queueEnd {
+			return it.start, nil
+		}
+	}
+}
+
+func (it *unorderedIntervalIterator) Gaps() int {
+	return it.slop
+}
+
+// search/intervals/FilteredIntervalsSource.java
+
+type maxGapsIntervalsSource struct {
+	in      IntervalsSource
+	maxGaps int
+}
+
+/*
+Create an IntervalsSource that filters a sub-source by the number of
+gaps in its intervals: only the intervals with at most maxGaps gaps
+are returned.
+*/
+func IntervalsMaxGaps(source IntervalsSource, maxGaps int) IntervalsSource {
+	return &maxGapsIntervalsSource{source, maxGaps}
+}
+
+func (s *maxGapsIntervalsSource) Intervals(field string, context *index.AtomicReaderContext,
+	acceptDocs util.Bits) (IntervalIterator, error) {
+
+	in, err := s.in.Intervals(field, context, acceptDocs)
+	if in == nil || err != nil {
+		return nil, err
+	}
+	ans := &maxGapsIntervalIterator{maxGaps: s.maxGaps}
+	ans.conjunctionIntervalIterator = newConjunctionIntervalIterator(ans, []IntervalIterator{in})
+	return ans, nil
+}
+
+func (s *maxGapsIntervalsSource) ExtractTerms(field string, terms *index.TermSet) {
+	s.in.ExtractTerms(field, terms)
+}
+
+func (s *maxGapsIntervalsSource) Visit(field string, query Query, visitor QueryVisitor) {
+	s.in.Visit(field, query, visitor)
+}
+
+func (s *maxGapsIntervalsSource) MinExtent() int {
+	return s.in.MinExtent()
+}
+
+func (s *maxGapsIntervalsSource) String() string {
+	return fmt.Sprintf("MAXGAPS/%v(%v)", s.maxGaps, s.in)
+}
+
+type maxGapsIntervalIterator struct {
+	*conjunctionIntervalIterator
+	maxGaps int
+}
+
+func (it *maxGapsIntervalIterator) reset() error {
+	it.start, it.end = -1, -1
+	return nil
+}
+
+func (it *maxGapsIntervalIterator) computeNextInterval() (int, error) {
+	in := it.subIterators[0]
+	for {
+		start, err := in.NextInterval()
+		if err != nil {
+			return 0, err
+		}
+		if start == NO_MORE_INTERVALS || in.Gaps() <= it.maxGaps {
+			it.start, it.end = start, in.End()
+			return start, nil
+		}
+	}
+}
+
+func (it *maxGapsIntervalIterator) Gaps() int {
+	return it.subIterators[0].Gaps()
+}
+
+// search/intervals/ContainingIntervalsSource.java
+
+type containingIntervalsSource struct {
+	big, small IntervalsSource
+}
+
+/*
+Returns intervals from the big source that contain at least one
+interval of the small source.
+*/
+func IntervalsContaining(big, small IntervalsSource) IntervalsSource {
+	return &containingIntervalsSource{big, small}
+}
+
+func (s *containingIntervalsSource) Intervals(field string, context *index.AtomicReaderContext,
+	acceptDocs util.Bits) (IntervalIterator, error) {
+
+	subIterators, err := subIntervals([]IntervalsSource{s.big, s.small}, field, context, acceptDocs)
+	if subIterators == nil || err != nil {
+		return nil, err
+	}
+	ans := &containingIntervalIterator{}
+	ans.conjunctionIntervalIterator = newConjunctionIntervalIterator(ans, subIterators)
+	return ans, nil
+}
+
+func (s *containingIntervalsSource) ExtractTerms(field string, terms *index.TermSet) {
+	s.big.ExtractTerms(field, terms)
+	s.small.ExtractTerms(field, terms)
+}
+
+func (s *containingIntervalsSource) Visit(field string, query Query, visitor QueryVisitor) {
+	visitSources(field, query, visitor, MUST, s.big, s.small)
+}
+
+func (s *containingIntervalsSource) MinExtent() int {
+	return s.big.MinExtent()
+}
+
+func (s *containingIntervalsSource) String() string {
+	return sourcesString("CONTAINING", s.big, s.small)
+}
+
+type containingIntervalIterator struct {
+	*conjunctionIntervalIterator
+	smallExhausted bool
+}
+
+func (it *containingIntervalIterator) reset() error {
+	it.start, it.end = -1, -1
+	next, err := it.subIterators[1].NextInterval()
+	it.smallExhausted = next == NO_MORE_INTERVALS
+	return err
+}
+
+func (it *containingIntervalIterator) computeNextInterval() (int, error) {
+	big, small := it.subIterators[0], it.subIterators[1]
+	it.start, it.end = NO_MORE_INTERVALS, NO_MORE_INTERVALS
+	for !it.smallExhausted {
+		start, err := big.NextInterval()
+		if err != nil {
+			return 0, err
+		}
+		if start == NO_MORE_INTERVALS {
+			break
+		}
+		if it.smallExhausted, err = skipIntervalsBefore(small, start); err != nil {
+			return 0, err
+		}
+		if !it.smallExhausted && small.End() <= big.End() {
+			it.start, it.end = start, big.End()
+			break
+		}
+	}
+	return it.start, nil
+}
+
+/*
+Moves the iterator to its first interval starting at or after start,
+returning true if there is none. As the intervals are minimal, the
+ends increase with the starts, so the interval it stops on is the one
+with the smallest end of the remaining ones.
+*/
+func skipIntervalsBefore(it IntervalIterator, start int) (bool, error) {
+	for it.Start() < start {
+		next, err := it.NextInterval()
+		if err != nil {
+			return false, err
+		}
+		if next == NO_MORE_INTERVALS {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (it *containingIntervalIterator) Gaps() int {
+	return it.subIterators[0].Gaps()
+}
+
+// search/intervals/DifferenceIntervalsSource.java
+
+type notContainingIntervalsSource struct {
+	minuend, subtrahend IntervalsSource
+}
+
+/*
+Create a not-containing IntervalsSource: the intervals from the
+minuend that do not contain any interval of the subtrahend.
+*/
+func IntervalsNotContaining(minuend, subtrahend IntervalsSource) IntervalsSource {
+	return &notContainingIntervalsSource{minuend, subtrahend}
+}
+
+func (s *notContainingIntervalsSource) Intervals(field string, context *index.AtomicReaderContext,
+	acceptDocs util.Bits) (IntervalIterator, error) {
+
+	minuend, err := s.minuend.Intervals(field, context, acceptDocs)
+	if minuend == nil || err != nil {
+		return nil, err
+	}
+	subtrahend, err := s.subtrahend.Intervals(field, context, acceptDocs)
+	if err != nil {
+		return nil, err
+	}
+	if subtrahend == nil {
+		return minuend, nil // nothing to subtract in this segment
+	}
+	ans := &notContainingIntervalIterator{subtrahend: subtrahend}
+	ans.conjunctionIntervalIterator = newConjunctionIntervalIterator(ans, []IntervalIterator{minuend})
+	return ans, nil
+}
+
+func (s *notContainingIntervalsSource) ExtractTerms(field string, terms *index.TermSet) {
+	s.minuend.ExtractTerms(field, terms) // the subtrahend is not scored
+}
+
+func (s *notContainingIntervalsSource) Visit(field string, query Query, visitor QueryVisitor) {
+	visitSources(field, query, visitor, MUST, s.minuend)
+	visitSources(field, query, visitor, MUST_NOT, s.subtrahend)
+}
+
+func (s *notContainingIntervalsSource) MinExtent() int {
+	return s.minuend.MinExtent()
+}
+
+func (s *notContainingIntervalsSource) String() string {
+	return sourcesString("NOT_CONTAINING", s.minuend, s.subtrahend)
+}
+
+/*
+Iterates over the intervals of the minuend, which is the only
+required iterator, positioning the subtrahend on its docs lazily.
+*/
+type notContainingIntervalIterator struct {
+	*conjunctionIntervalIterator
+	subtrahend          IntervalIterator
+	subtrahendExhausted bool
+}
+
+func (it *notContainingIntervalIterator) reset() error {
+	it.start, it.end = -1, -1
+	doc := it.doc
+	if it.subtrahend.DocId() < doc {
+		if _, err := it.subtrahend.Advance(doc); err != nil {
+			return err
+		}
+	}
+	if it.subtrahend.DocId() != doc {
+		it.subtrahendExhausted = true
+		return nil
+	}
+	next, err := it.subtrahend.NextInterval()
+	it.subtrahendExhausted = next == NO_MORE_INTERVALS
+	return err
+}
+
+func (it *notContainingIntervalIterator) computeNextInterval() (int, error) {
+	minuend := it.subIterators[0]
+	for {
+		start, err := minuend.NextInterval()
+		if err != nil {
+			return 0, err
+		}
+		it.start, it.end = start, minuend.End()
+		if start == NO_MORE_INTERVALS || it.subtrahendExhausted {
+			return start, nil
+		}
+		if it.subtrahendExhausted, err = skipIntervalsBefore(it.subtrahend, start); err != nil {
+			return 0, err
+		}
+		if it.subtrahendExhausted || it.subtrahend.End() > minuend.End() {
+			return start, nil
+		}
+	}
+}
+
+func (it *notContainingIntervalIterator) Gaps() int {
+	return it.subIterators[0].Gaps()
+}
@@ -0,0 +1,80 @@
+package search
+
+import (
+	"fmt"
+	"github.com/balzaczyy/golucene/core/geo"
+	"github.com/balzaczyy/golucene/core/index"
+	"github.com/balzaczyy/golucene/core/util"
+	"math"
+)
+
+// document/LatLonPointDistanceComparator.java
+
+/*
+A DoubleValuesSource computing, for each document, the haversine
+distance in meters between the point of a LatLonDocValuesField and a
+fixed origin.
+*/
+type latLonDistanceSource struct {
+	field               string
+	latitude, longitude float64
+}
+
+/*
+Creates a DoubleValuesSource whose values are the distances in meters
+from the location indexed in field, a LatLonDocValuesField, to the
+given origin; the closest one if the document has several locations.
+Documents without a location have no value.
+*/
+func NewLatLonDistanceSource(field string, latitude, longitude float64) DoubleValuesSource {
+	assert2(field != "", "field must not be empty")
+	geo.CheckLatitude(latitude)
+	geo.CheckLongitude(longitude)
+	return &latLonDistanceSource{field, latitude, longitude}
+}
+
+func (s *latLonDistanceSource) GetValues(ctx *index.AtomicReaderContext) (DoubleValues, error) {
+	return &latLonDistanceValues{owner: s, reader: ctx.Reader()}, nil
+}
+
+func (s *latLonDistanceSource) String() string {
+	return fmt.Sprintf("distance(%v,latitude=%v,longitude=%v)", s.field, s.latitude, s.longitude)
+}
+
+type latLonDistanceValues struct {
+	owner    *latLonDistanceSource
+	reader   index.IndexReader
+	distance float64
+}
+
+func (v *latLonDistanceValues) AdvanceExact(doc int) (bool, error) {
+	found := false
+	v.distance = math.Inf(1)
+	err := forEachStoredLatLon(v.reader, doc, v.owner.field, func(lat, lon float64) bool {
+		found = true
+		v.distance = math.Min(v.distance,
+			util.HaversinMeters(v.owner.latitude, v.owner.longitude, lat, lon))
+		return true
+	})
+	return found, err
+}
+
+func (v *latLonDistanceValues) DoubleValue() (float64, error) {
+	return v.distance, nil
+}
+
+// document/LatLonPointSortField.java
+
+/*
+Creates a SortField for sorting by distance from a location, nearest
+first. The field must be a LatLonDocValuesField. The sort values of
+the hits are their distances in meters (float64), so they can be
+displayed; documents without a location sort last, with a distance of
+math.Inf(1).
+*/
+func NewLatLonDistanceSort(field string, latitude, longitude float64) *SortField {
+	ans := NewDoubleValuesSortField(NewLatLonDistanceSource(field, latitude, longitude), false)
+	ans.field = field
+	ans.missingValue = math.Inf(1)
+	return ans
+}
@@ -0,0 +1,92 @@
+package search
+
+import (
+	std "github.com/balzaczyy/golucene/analysis/standard"
+	docu "github.com/balzaczyy/golucene/core/document"
+	"github.com/balzaczyy/golucene/core/index"
+	"github.com/balzaczyy/golucene/core/store"
+	"github.com/balzaczyy/golucene/core/util"
+	"math"
+	"testing"
+)
+
+type city struct {
+	title    string
+	lat, lon float64
+}
+
+var cities = []city{
+	{"london", 51.5072, -0.1276},
+	{"new york", 40.7128, -74.0060},
+	{"paris", 48.8566, 2.3522},
+	{"nowhere", 0, 0},
+	{"auckland", -36.8485, 174.7633},
+	{"honolulu", 21.3099, -157.8581},
+}
+
+// Indexes the cities, with a LatLonPoint and a LatLonDocValuesField of
+// field "location" unless they are nowhere.
+func newCitiesSearcher(t *testing.T) *IndexSearcher {
+	dir, err := store.OpenFSDirectory(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := index.NewIndexWriter(dir, index.NewIndexWriterConfig(util.VERSION_LATEST,
+		std.NewStandardAnalyzer()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, city := range cities {
+		doc := docu.NewDocument()
+		doc.Add(docu.NewTextFieldFromString("title", city.title, docu.STORE_YES))
+		if city.title != "nowhere" {
+			doc.Add(docu.NewLatLonPoint("location", city.lat, city.lon))
+			doc.Add(docu.NewLatLonDocValuesField("location", city.lat, city.lon))
+		}
+		if err = w.AddDocument(doc.Fields()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	r, err := index.OpenDirectoryReader(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { r.Close() })
+	return NewIndexSearcher(r)
+}
+
+func TestLatLonDistanceSort(t *testing.T) {
+	ss := newCitiesSearcher(t)
+	firstPass, err := ss.SearchTop(NewMatchAllDocsQuery(), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// from Brussels, nearest first
+	sort := NewSort(NewLatLonDistanceSort("location", 50.8503, 4.3517))
+	rescored, err := NewSortRescorer(sort).Rescore(ss, firstPass, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEquals(t, 6, len(rescored.ScoreDocs))
+	for i, exp := range []struct {
+		title    string
+		distance float64 // in km
+	}{
+		{"paris", 264}, {"london", 320}, {"new york", 5888},
+		{"honolulu", 11804}, {"auckland", 18282}, {"nowhere", math.Inf(1)},
+	} {
+		hit := rescored.ScoreDocs[i]
+		doc, err := ss.reader.Document(hit.Doc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEquals(t, exp.title, doc.Get("title"))
+		if distance := hit.Fields[0].(float64) / 1000; math.Abs(distance-exp.distance) > 1 {
+			t.Errorf("Expected %v to be %vkm away, but was %vkm", exp.title, exp.distance, distance)
+		}
+	}
+}
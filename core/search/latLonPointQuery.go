@@ -0,0 +1,192 @@
+package search
+
+import (
+	"fmt"
+	docu "github.com/balzaczyy/golucene/core/document"
+	"github.com/balzaczyy/golucene/core/geo"
+	"github.com/balzaczyy/golucene/core/index"
+	"github.com/balzaczyy/golucene/core/util"
+	"math"
+)
+
+/*
+Calls f with the locations of the LatLonPoint or LatLonDocValuesField
+field of the doc, relative to reader, until f returns false.
+*/
+func forEachStoredLatLon(reader index.IndexReader, doc int, field string,
+	f func(lat, lon float64) bool) error {
+
+	stored, err := reader.Document(doc)
+	if err != nil {
+		return err
+	}
+	for _, v := range stored.Fields() {
+		if v.Name() != field {
+			continue
+		}
+		if value, ok := v.NumericValue().(int64); ok {
+			if !f(docu.DecodeLatLon(value)) {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// Decides whether a location matches a LatLonPointQuery.
+type latLonPredicate interface {
+	matches(lat, lon float64) bool
+	String() string
+}
+
+/*
+A query matching the documents with a location of a LatLonPoint field
+that satisfies a geo predicate, e.g. being in a box or within some
+distance of a point. All matches get the same score, the query's
+boost.
+
+Without a points (BKD) format in the codecs, the locations are read
+back from the stored fields of every live document of the segment,
+which is only practical for small indexes or for filtering a small
+number of candidates.
+*/
+type LatLonPointQuery struct {
+	*AbstractQuery
+	field     string
+	predicate latLonPredicate
+}
+
+func newLatLonPointQuery(field string, predicate latLonPredicate) *LatLonPointQuery {
+	assert2(field != "", "field must not be empty")
+	ans := &LatLonPointQuery{field: field, predicate: predicate}
+	ans.AbstractQuery = NewAbstractQuery(ans)
+	return ans
+}
+
+// document/LatLonPoint.java
+
+type latLonBox struct {
+	minLat, maxLat, minLon, maxLon float64
+}
+
+func (b *latLonBox) matches(lat, lon float64) bool {
+	if lat < b.minLat || lat > b.maxLat {
+		return false
+	}
+	if b.minLon > b.maxLon { // crosses dateline
+		return lon >= b.minLon || lon <= b.maxLon
+	}
+	return lon >= b.minLon && lon <= b.maxLon
+}
+
+func (b *latLonBox) String() string {
+	return fmt.Sprintf("[%v TO %v],[%v TO %v]", b.minLat, b.maxLat, b.minLon, b.maxLon)
+}
+
+/*
+Create a query for matching a bounding box. The box may cross the
+dateline, with minLongitude greater than maxLongitude, in which case
+the locations on either side of it match.
+*/
+func NewLatLonBoxQuery(field string, minLatitude, maxLatitude,
+	minLongitude, maxLongitude float64) *LatLonPointQuery {
+
+	geo.CheckLatitude(minLatitude)
+	geo.CheckLatitude(maxLatitude)
+	geo.CheckLongitude(minLongitude)
+	geo.CheckLongitude(maxLongitude)
+	return newLatLonPointQuery(field, &latLonBox{minLatitude, maxLatitude, minLongitude, maxLongitude})
+}
+
+// document/LatLonPointDistanceQuery.java
+
+type latLonCircle struct {
+	latitude, longitude, radiusMeters float64
+}
+
+func (c *latLonCircle) matches(lat, lon float64) bool {
+	return util.HaversinMeters(c.latitude, c.longitude, lat, lon) <= c.radiusMeters
+}
+
+func (c *latLonCircle) String() string {
+	return fmt.Sprintf("%v,%v +/- %v meters", c.latitude, c.longitude, c.radiusMeters)
+}
+
+/*
+Create a query for matching the locations within radiusMeters of the
+given point, by haversine distance.
+*/
+func NewLatLonDistanceQuery(field string, latitude, longitude, radiusMeters float64) *LatLonPointQuery {
+	geo.CheckLatitude(latitude)
+	geo.CheckLongitude(longitude)
+	assert2(radiusMeters >= 0 && !math.IsInf(radiusMeters, 0) && !math.IsNaN(radiusMeters),
+		"radiusMeters: '%v' is invalid", radiusMeters)
+	return newLatLonPointQuery(field, &latLonCircle{latitude, longitude, radiusMeters})
+}
+
+// document/LatLonPointInPolygonQuery.java
+
+type latLonPolygons []*geo.Polygon
+
+func (ps latLonPolygons) matches(lat, lon float64) bool {
+	for _, p := range ps {
+		if p.Contains(lat, lon) {
+			return true
+		}
+	}
+	return false
+}
+
+func (ps latLonPolygons) String() string {
+	return fmt.Sprint([]*geo.Polygon(ps))
+}
+
+/*
+Create a query for matching the locations inside one or more
+polygons, excluding their holes.
+*/
+func NewLatLonPolygonQuery(field string, polygons ...*geo.Polygon) *LatLonPointQuery {
+	assert2(len(polygons) > 0, "polygons must not be empty")
+	for i, p := range polygons {
+		assert2(p != nil, "polygon[%v] must not be nil", i)
+	}
+	return newLatLonPointQuery(field, latLonPolygons(append([]*geo.Polygon(nil), polygons...)))
+}
+
+// Returns the field of the locations.
+func (q *LatLonPointQuery) Field() string {
+	return q.field
+}
+
+func (q *LatLonPointQuery) CreateWeight(ss *IndexSearcher) (Weight, error) {
+	return newVerifyingWeight(q), nil
+}
+
+func (q *LatLonPointQuery) ExtractTerms(terms *index.TermSet) {}
+
+func (q *LatLonPointQuery) Visit(visitor QueryVisitor) {
+	if visitor.AcceptField(q.field) {
+		visitor.VisitLeaf(q)
+	}
+}
+
+func (q *LatLonPointQuery) ToString(field string) string {
+	s := q.predicate.String()
+	if q.field != field {
+		s = fmt.Sprintf("%v:%v", q.field, s)
+	}
+	if q.boost != 1 {
+		s = fmt.Sprintf("%v^%v", s, q.boost)
+	}
+	return s
+}
+
+// Returns true if any location of doc, relative to reader, matches.
+func (q *LatLonPointQuery) matches(reader index.IndexReader, doc int) (bool, error) {
+	found := false
+	err := forEachStoredLatLon(reader, doc, q.field, func(lat, lon float64) bool {
+		found = q.predicate.matches(lat, lon)
+		return !found
+	})
+	return found, err
+}
@@ -0,0 +1,76 @@
+package search
+
+import (
+	"fmt"
+	"github.com/balzaczyy/golucene/core/geo"
+	"github.com/balzaczyy/golucene/core/index"
+	"sort"
+	"testing"
+)
+
+func TestLatLonPointQueries(t *testing.T) {
+	ss := newCitiesSearcher(t)
+	titles := func(q Query) []string {
+		hits, err := ss.SearchTop(q, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ans []string
+		for _, hit := range hits.ScoreDocs {
+			doc, err := ss.reader.Document(hit.Doc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ans = append(ans, doc.Get("title"))
+		}
+		sort.Strings(ans)
+		return ans
+	}
+
+	// western europe
+	q := NewLatLonBoxQuery("location", 45, 55, -5, 5)
+	assertEquals(t, "location:[45 TO 55],[-5 TO 5]", q.String())
+	assertEquals(t, "[london paris]", fmt.Sprint(titles(q)))
+	// pacific, across the dateline
+	assertEquals(t, "[auckland honolulu]",
+		fmt.Sprint(titles(NewLatLonBoxQuery("location", -40, 30, 170, -150))))
+	assertEquals(t, "[]", fmt.Sprint(titles(NewLatLonBoxQuery("location", -10, 10, -10, 10))))
+
+	// within 300km from Brussels
+	q = NewLatLonDistanceQuery("location", 50.8503, 4.3517, 300000)
+	assertEquals(t, "location:50.8503,4.3517 +/- 300000 meters", q.String())
+	assertEquals(t, "[paris]", fmt.Sprint(titles(q)))
+	assertEquals(t, "[london paris]",
+		fmt.Sprint(titles(NewLatLonDistanceQuery("location", 50.8503, 4.3517, 400000))))
+
+	// combined with other clauses
+	bq := NewBooleanQuery()
+	bq.Add(NewTermQuery(index.NewTerm("title", "london")), SHOULD)
+	bq.Add(NewLatLonDistanceQuery("location", 40, -74, 200000), SHOULD)
+	assertEquals(t, "[london new york]", fmt.Sprint(titles(bq)))
+}
+
+func TestLatLonPolygonQuery(t *testing.T) {
+	ss := newCitiesSearcher(t)
+	count := func(q Query) int {
+		hits, err := ss.SearchTop(q, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return hits.TotalHits
+	}
+
+	// western europe, with a hole around Paris
+	hole := geo.NewPolygon([]float64{48, 48, 49, 49, 48}, []float64{2, 3, 3, 2, 2})
+	europe := geo.NewPolygon([]float64{45, 45, 55, 55, 45}, []float64{-5, 5, 5, -5, -5}, hole)
+	assertEquals(t, 1, count(NewLatLonPolygonQuery("location", europe)))
+	assertEquals(t, 2, count(NewLatLonPolygonQuery("location",
+		geo.NewPolygon([]float64{45, 45, 55, 55, 45}, []float64{-5, 5, 5, -5, -5}))))
+
+	// a triangle around New York, plus Europe
+	ny := geo.NewPolygon([]float64{40, 42, 40, 40}, []float64{-75, -74, -73, -75})
+	assertEquals(t, 2, count(NewLatLonPolygonQuery("location", europe, ny)))
+	// a triangle close to, but not containing New York
+	assertEquals(t, 0, count(NewLatLonPolygonQuery("location",
+		geo.NewPolygon([]float64{40, 40.7, 40, 40}, []float64{-75, -74, -73, -75}))))
+}
@@ -0,0 +1,229 @@
+package search
+
+import (
+	"fmt"
+	docu "github.com/balzaczyy/golucene/core/document"
+	"github.com/balzaczyy/golucene/core/geo"
+	"github.com/balzaczyy/golucene/core/index"
+)
+
+// document/ShapeField.java#QueryRelation
+
+// Query relation between the indexed shapes and the query shape.
+type QueryRelation int
+
+const (
+	// used for INTERSECT queries
+	QUERY_RELATION_INTERSECTS QueryRelation = iota
+	// used for WITHIN queries
+	QUERY_RELATION_WITHIN
+	// used for DISJOINT queries
+	QUERY_RELATION_DISJOINT
+)
+
+func (r QueryRelation) String() string {
+	switch r {
+	case QUERY_RELATION_INTERSECTS:
+		return "INTERSECTS"
+	case QUERY_RELATION_WITHIN:
+		return "WITHIN"
+	case QUERY_RELATION_DISJOINT:
+		return "DISJOINT"
+	}
+	panic(fmt.Sprintf("invalid query relation: %v", int(r)))
+}
+
+// The query shape of a LatLonShapeQuery.
+type latLonShapeComponent interface {
+	relateTriangle(t *geo.Triangle) geo.Relation
+	String() string
+}
+
+type latLonShapePolygons []*geo.Polygon
+
+// A triangle is inside the polygons if it is inside any of them.
+func (ps latLonShapePolygons) relateTriangle(t *geo.Triangle) geo.Relation {
+	ans := geo.CELL_OUTSIDE_QUERY
+	for _, p := range ps {
+		switch p.RelateTriangle(t) {
+		case geo.CELL_INSIDE_QUERY:
+			return geo.CELL_INSIDE_QUERY
+		case geo.CELL_CROSSES_QUERY:
+			ans = geo.CELL_CROSSES_QUERY
+		}
+	}
+	return ans
+}
+
+func (ps latLonShapePolygons) String() string {
+	return fmt.Sprint([]*geo.Polygon(ps))
+}
+
+type latLonShapeLines []*geo.Line
+
+func (ls latLonShapeLines) relateTriangle(t *geo.Triangle) geo.Relation {
+	for _, l := range ls {
+		if l.RelateTriangle(t) != geo.CELL_OUTSIDE_QUERY {
+			return geo.CELL_CROSSES_QUERY
+		}
+	}
+	return geo.CELL_OUTSIDE_QUERY
+}
+
+func (ls latLonShapeLines) String() string {
+	return fmt.Sprint([]*geo.Line(ls))
+}
+
+// document/LatLonShapeQuery.java
+
+/*
+Finds all previously indexed shapes, the triangles of the
+LatLonShapeTriangle fields of a document, that comply with the given
+QueryRelation with the query shape:
+
+  - QUERY_RELATION_INTERSECTS: at least one triangle intersects the
+    query shape;
+  - QUERY_RELATION_WITHIN: all triangles are within the query shape;
+  - QUERY_RELATION_DISJOINT: the document has a shape, and none of its
+    triangles intersects the query shape.
+
+Shared boundaries count as intersecting. All matches get the same
+score, the query's boost. Like the LatLonPoint queries, the
+triangles are read back from the stored fields of every live document
+of the segment.
+*/
+type LatLonShapeQuery struct {
+	*AbstractQuery
+	field     string
+	relation  QueryRelation
+	component latLonShapeComponent
+}
+
+func newLatLonShapeQuery(field string, relation QueryRelation,
+	component latLonShapeComponent) *LatLonShapeQuery {
+
+	assert2(field != "", "field must not be empty")
+	ans := &LatLonShapeQuery{field: field, relation: relation, component: component}
+	ans.AbstractQuery = NewAbstractQuery(ans)
+	return ans
+}
+
+/*
+Create a query for matching a bounding box. The box may cross the
+dateline, with minLongitude greater than maxLongitude.
+*/
+func NewLatLonShapeBoxQuery(field string, relation QueryRelation,
+	minLatitude, maxLatitude, minLongitude, maxLongitude float64) *LatLonShapeQuery {
+
+	geo.CheckLatitude(minLatitude)
+	geo.CheckLatitude(maxLatitude)
+	geo.CheckLongitude(minLongitude)
+	geo.CheckLongitude(maxLongitude)
+	assert2(minLatitude <= maxLatitude, "minLatitude cannot be greater than maxLatitude")
+	box := func(minLon, maxLon float64) *geo.Polygon {
+		return geo.NewPolygon(
+			[]float64{minLatitude, minLatitude, maxLatitude, maxLatitude, minLatitude},
+			[]float64{minLon, maxLon, maxLon, minLon, minLon})
+	}
+	if minLongitude > maxLongitude { // crosses dateline
+		return newLatLonShapeQuery(field, relation, latLonShapePolygons{
+			box(minLongitude, geo.MAX_LON_INCL), box(geo.MIN_LON_INCL, maxLongitude)})
+	}
+	return newLatLonShapeQuery(field, relation, latLonShapePolygons{box(minLongitude, maxLongitude)})
+}
+
+// Create a query for matching one or more polygons, excluding their
+// holes.
+func NewLatLonShapePolygonQuery(field string, relation QueryRelation,
+	polygons ...*geo.Polygon) *LatLonShapeQuery {
+
+	assert2(len(polygons) > 0, "polygons must not be empty")
+	for i, p := range polygons {
+		assert2(p != nil, "polygon[%v] must not be nil", i)
+	}
+	return newLatLonShapeQuery(field, relation,
+		latLonShapePolygons(append([]*geo.Polygon(nil), polygons...)))
+}
+
+/*
+Create a query for matching one or more lines. As lines have no
+area, QUERY_RELATION_WITHIN is not supported.
+*/
+func NewLatLonShapeLineQuery(field string, relation QueryRelation,
+	lines ...*geo.Line) *LatLonShapeQuery {
+
+	assert2(relation != QUERY_RELATION_WITHIN,
+		"LatLonShapeLineQuery does not currently support WITHIN queries")
+	assert2(len(lines) > 0, "lines must not be empty")
+	for i, l := range lines {
+		assert2(l != nil, "line[%v] must not be nil", i)
+	}
+	return newLatLonShapeQuery(field, relation,
+		latLonShapeLines(append([]*geo.Line(nil), lines...)))
+}
+
+// Returns the field of the shapes.
+func (q *LatLonShapeQuery) Field() string {
+	return q.field
+}
+
+// Returns the relation of the indexed shapes with the query shape.
+func (q *LatLonShapeQuery) Relation() QueryRelation {
+	return q.relation
+}
+
+func (q *LatLonShapeQuery) CreateWeight(ss *IndexSearcher) (Weight, error) {
+	return newVerifyingWeight(q), nil
+}
+
+func (q *LatLonShapeQuery) ExtractTerms(terms *index.TermSet) {}
+
+func (q *LatLonShapeQuery) Visit(visitor QueryVisitor) {
+	if visitor.AcceptField(q.field) {
+		visitor.VisitLeaf(q)
+	}
+}
+
+func (q *LatLonShapeQuery) ToString(field string) string {
+	s := fmt.Sprintf("LatLonShapeQuery(%v %v)", q.relation, q.component)
+	if q.field != field {
+		s = fmt.Sprintf("%v:%v", q.field, s)
+	}
+	if q.boost != 1 {
+		s = fmt.Sprintf("%v^%v", s, q.boost)
+	}
+	return s
+}
+
+// Relates the triangles of the shape of doc, relative to reader, with
+// the query shape.
+func (q *LatLonShapeQuery) matches(reader index.IndexReader, doc int) (bool, error) {
+	stored, err := reader.Document(doc)
+	if err != nil {
+		return false, err
+	}
+	found := false
+	for _, f := range stored.Fields() {
+		data := f.BinaryValue()
+		if f.Name() != q.field || len(data) != docu.LAT_LON_SHAPE_TRIANGLE_BYTES {
+			continue
+		}
+		found = true
+		switch relation := q.component.relateTriangle(docu.DecodeLatLonShapeTriangle(data)); q.relation {
+		case QUERY_RELATION_INTERSECTS:
+			if relation != geo.CELL_OUTSIDE_QUERY {
+				return true, nil
+			}
+		case QUERY_RELATION_WITHIN:
+			if relation != geo.CELL_INSIDE_QUERY {
+				return false, nil
+			}
+		case QUERY_RELATION_DISJOINT:
+			if relation != geo.CELL_OUTSIDE_QUERY {
+				return false, nil
+			}
+		}
+	}
+	// every triangle was checked
+	return found && q.relation != QUERY_RELATION_INTERSECTS, nil
+}
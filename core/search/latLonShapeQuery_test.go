@@ -0,0 +1,98 @@
+package search
+
+import (
+	"fmt"
+	std "github.com/balzaczyy/golucene/analysis/standard"
+	docu "github.com/balzaczyy/golucene/core/document"
+	"github.com/balzaczyy/golucene/core/geo"
+	"github.com/balzaczyy/golucene/core/index"
+	"github.com/balzaczyy/golucene/core/store"
+	"github.com/balzaczyy/golucene/core/util"
+	"sort"
+	"testing"
+)
+
+func TestLatLonShapeQueries(t *testing.T) {
+	dir, err := store.OpenFSDirectory(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := index.NewIndexWriter(dir, index.NewIndexWriterConfig(util.VERSION_LATEST,
+		std.NewStandardAnalyzer()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	square := func(min, max float64, holes ...*geo.Polygon) *geo.Polygon {
+		return geo.NewPolygon([]float64{min, min, max, max, min}, []float64{min, max, max, min, min}, holes...)
+	}
+	for title, shape := range map[string][]*docu.LatLonShapeTriangle{
+		"park": docu.NewLatLonShapePolygonFields("shape", square(10, 20, square(14, 16))),
+		"road": docu.NewLatLonShapeLineFields("shape",
+			geo.NewLine([]float64{0, 5, 5}, []float64{0, 5, 25})),
+		"well": docu.NewLatLonShapePointFields("shape", 15, 15),
+		"lake": docu.NewLatLonShapePolygonFields("shape",
+			geo.NewPolygon([]float64{30, 30, 35, 30}, []float64{30, 35, 32, 30})),
+		"nothing": nil,
+	} {
+		doc := docu.NewDocument()
+		doc.Add(docu.NewTextFieldFromString("title", title, docu.STORE_YES))
+		for _, f := range shape {
+			doc.Add(f)
+		}
+		if err = w.AddDocument(doc.Fields()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	r, err := index.OpenDirectoryReader(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	ss := NewIndexSearcher(r)
+	titles := func(q Query) string {
+		hits, err := ss.SearchTop(q, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ans []string
+		for _, hit := range hits.ScoreDocs {
+			doc, err := r.Document(hit.Doc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ans = append(ans, doc.Get("title"))
+		}
+		sort.Strings(ans)
+		return fmt.Sprint(ans)
+	}
+
+	// inside the hole of the park
+	assertEquals(t, "[well]", titles(NewLatLonShapeBoxQuery("shape",
+		QUERY_RELATION_INTERSECTS, 14.5, 15.5, 14.5, 15.5)))
+	assertEquals(t, "[road]", titles(NewLatLonShapeBoxQuery("shape",
+		QUERY_RELATION_INTERSECTS, 4, 6, 15, 16)))
+	assertEquals(t, "[park well]", titles(NewLatLonShapeBoxQuery("shape",
+		QUERY_RELATION_WITHIN, 9, 21, 9, 21)))
+	assertEquals(t, "[lake road]", titles(NewLatLonShapeBoxQuery("shape",
+		QUERY_RELATION_DISJOINT, 9, 21, 9, 21)))
+	// across the dateline
+	assertEquals(t, "[]", titles(NewLatLonShapeBoxQuery("shape",
+		QUERY_RELATION_INTERSECTS, -10, 40, 170, -170)))
+
+	q := NewLatLonShapePolygonQuery("shape", QUERY_RELATION_WITHIN, square(9, 21, square(14.5, 15.5)))
+	assertEquals(t, "[park]", titles(q))
+	assertEquals(t, "[park well]", titles(NewLatLonShapePolygonQuery("shape",
+		QUERY_RELATION_INTERSECTS, square(9, 21))))
+	assertEquals(t, "[lake park road well]", titles(NewLatLonShapePolygonQuery("shape",
+		QUERY_RELATION_INTERSECTS, square(9, 21), square(-1, 1), square(31, 32))))
+
+	line := NewLatLonShapeLineQuery("shape", QUERY_RELATION_INTERSECTS,
+		geo.NewLine([]float64{12, 12}, []float64{0, 40}))
+	assertEquals(t, "shape:LatLonShapeQuery(INTERSECTS [LINE([0, 12], [40, 12])])", line.String())
+	assertEquals(t, "[park]", titles(line))
+	assertEquals(t, "[lake road well]", titles(NewLatLonShapeLineQuery("shape",
+		QUERY_RELATION_DISJOINT, geo.NewLine([]float64{12, 12}, []float64{0, 40}))))
+}
@@ -0,0 +1,136 @@
+package search
+
+import (
+	"sync"
+)
+
+// search/LiveFieldValues.java
+
+type LiveFieldValuesSPI interface {
+	/*
+		This is called when the id/value was already flushed and opened in
+		a searcher. You must implement this to go look up the value (e.g.
+		via stored fields). Returns nil if the id isn't in the index.
+	*/
+	LookupFromSearcher(s interface{}, id string) (interface{}, error)
+}
+
+// marks an id as deleted, so lookups don't fall back to the searcher
+type liveFieldValuesMissing struct{}
+
+var missingLiveFieldValue = &liveFieldValuesMissing{}
+
+/*
+Tracks live field values across refresh, so that recently indexed
+values can be read before the searcher exposing them is opened. This
+can be used for e.g. versioned updates and optimistic concurrency,
+where the latest value of a field must be known right after the
+document was indexed.
+
+Call Add() after successfully indexing a document, and Delete() after
+deleting one. Values buffered in RAM are dropped once a refresh of
+the ReferenceManager makes them visible; afterwards Get() falls back
+to LookupFromSearcher() of the provided spi.
+*/
+type LiveFieldValues struct {
+	spi LiveFieldValuesSPI
+	mgr *ReferenceManager
+
+	sync.RWMutex // guards current and old
+	current      map[string]interface{}
+	old          map[string]interface{}
+}
+
+func NewLiveFieldValues(spi LiveFieldValuesSPI, mgr *ReferenceManager) *LiveFieldValues {
+	ans := &LiveFieldValues{
+		spi:     spi,
+		mgr:     mgr,
+		current: make(map[string]interface{}),
+		old:     make(map[string]interface{}),
+	}
+	mgr.AddListener(ans)
+	return ans
+}
+
+func (v *LiveFieldValues) Close() error {
+	v.mgr.RemoveListener(v)
+	return nil
+}
+
+func (v *LiveFieldValues) BeforeRefresh() error {
+	v.Lock()
+	defer v.Unlock()
+	v.old = v.current
+	// Start sending all updates after this point to the new map. While
+	// reopen is running, any lookup will first try this new map, then
+	// fallback to old, then to the current searcher:
+	v.current = make(map[string]interface{})
+	return nil
+}
+
+func (v *LiveFieldValues) AfterRefresh(didRefresh bool) error {
+	v.Lock()
+	defer v.Unlock()
+	// Now drop all the old values because they are now visible via the
+	// searcher that was just opened; if didRefresh is false, it's
+	// possible old has some entries in it, which is fine: it means they
+	// were actually already included in the previously opened reader.
+	// So we can safely clear old here:
+	v.old = make(map[string]interface{})
+	return nil
+}
+
+/*
+Call this after you've successfully added a document to the index, to
+record what value you just set the field to.
+*/
+func (v *LiveFieldValues) Add(id string, value interface{}) {
+	assert2(value != nil, "value cannot be nil")
+	v.Lock()
+	defer v.Unlock()
+	v.current[id] = value
+}
+
+// Call this after you've successfully deleted a document from the index.
+func (v *LiveFieldValues) Delete(id string) {
+	v.Lock()
+	defer v.Unlock()
+	v.current[id] = missingLiveFieldValue
+}
+
+// Returns the [approximate] number of id/value pairs buffered in RAM.
+func (v *LiveFieldValues) Size() int {
+	v.RLock()
+	defer v.RUnlock()
+	return len(v.current) + len(v.old)
+}
+
+/*
+Returns the current value for this id, or nil if the id isn't in the
+index or was deleted.
+*/
+func (v *LiveFieldValues) Get(id string) (interface{}, error) {
+	v.RLock()
+	// First try to get the "live" value:
+	value, ok := v.current[id]
+	if !ok {
+		value, ok = v.old[id]
+	}
+	v.RUnlock()
+	if ok {
+		if value == missingLiveFieldValue {
+			return nil, nil
+		}
+		return value, nil
+	}
+
+	// It either does not exist in the index, or, it was already flushed
+	// and a searcher was opened on the segment, so fallback to current
+	// searcher:
+	s, err := v.mgr.acquire()
+	if err != nil {
+		return nil, err
+	}
+	defer v.mgr.release(s)
+	return v.spi.LookupFromSearcher(s, id)
+}
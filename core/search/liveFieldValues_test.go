@@ -0,0 +1,75 @@
+package search
+
+import (
+	"fmt"
+	std "github.com/balzaczyy/golucene/analysis/standard"
+	docu "github.com/balzaczyy/golucene/core/document"
+	"github.com/balzaczyy/golucene/core/index"
+	"github.com/balzaczyy/golucene/core/store"
+	"github.com/balzaczyy/golucene/core/util"
+	"testing"
+)
+
+type storedVersionLookup struct{}
+
+func (l storedVersionLookup) LookupFromSearcher(s interface{}, id string) (interface{}, error) {
+	searcher := s.(*IndexSearcher)
+	docs, err := searcher.SearchTop(NewTermQuery(index.NewTerm("id", id)), 1)
+	if err != nil || docs.TotalHits == 0 {
+		return nil, err
+	}
+	doc, err := searcher.IndexReader().Document(docs.ScoreDocs[0].Doc)
+	if err != nil {
+		return nil, err
+	}
+	return doc.Get("version"), nil
+}
+
+func TestLiveFieldValues(t *testing.T) {
+	dir, err := store.OpenFSDirectory(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := index.NewIndexWriter(dir, index.NewIndexWriterConfig(util.VERSION_LATEST, std.NewStandardAnalyzer()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	m, err := NewSearcherManagerFromWriter(w, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+	values := NewLiveFieldValues(storedVersionLookup{}, m.ReferenceManager)
+	defer values.Close()
+
+	for i, id := range []string{"a", "b"} {
+		doc := docu.NewDocument()
+		doc.Add(docu.NewStringField("id", id, docu.STORE_YES))
+		doc.Add(docu.NewStringField("version", fmt.Sprintf("%v", i+1), docu.STORE_YES))
+		if err = w.AddDocument(doc.Fields()); err != nil {
+			t.Fatal(err)
+		}
+		values.Add(id, fmt.Sprintf("%v", i+1))
+	}
+	values.Delete("c")
+	assertEquals(t, 3, values.Size())
+
+	// visible before refresh
+	for id, expected := range map[string]interface{}{"a": "1", "b": "2", "c": nil} {
+		if v, err := values.Get(id); v != expected || err != nil {
+			t.Errorf("Expected %v for %v, but was %v (%v)", expected, id, v, err)
+		}
+	}
+
+	if err = m.MaybeRefreshBlocking(); err != nil {
+		t.Fatal(err)
+	}
+	assertEquals(t, 0, values.Size())
+	// now looked up from the refreshed searcher
+	for id, expected := range map[string]interface{}{"a": "1", "b": "2", "c": nil} {
+		if v, err := values.Get(id); v != expected || err != nil {
+			t.Errorf("Expected %v for %v, but was %v (%v)", expected, id, v, err)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package search
+
+import (
+	"fmt"
+	"math"
+)
+
+// search/similarities/LMSimilarity.java
+
+/*
+Abstract superclass for language modeling Similarities. The following
+inner types are introduced:
+
+  - CollectionModel, which is a strategy interface for object that
+    compute the collection language model p(w|C);
+  - DefaultCollectionModel, an implementation of the former, that
+    computes the term probability as the number of occurrences of the
+    term in the collection, divided by the total number of tokens.
+
+The collection probability is derived from the BasicStats of the
+term, hence is available to the Score() of every subtype through
+collectionProbability().
+*/
+type LMSimilarity struct {
+	*SimilarityBase
+	// The collection model.
+	collectionModel CollectionModel
+}
+
+func newLMSimilarity(spi SimilarityBaseSPI, collectionModel CollectionModel) *LMSimilarity {
+	assert2(collectionModel != nil, "null parameters not allowed.")
+	return &LMSimilarity{NewSimilarityBase(spi), collectionModel}
+}
+
+// Returns the probability that the current term is generated by the
+// collection.
+func (sim *LMSimilarity) collectionProbability(stats *BasicStats) float32 {
+	return sim.collectionModel.ComputeProbability(stats)
+}
+
+func (sim *LMSimilarity) addCollectionExplanation(expl *ExplanationImpl, stats *BasicStats) {
+	expl.addDetail(newExplanation(sim.collectionProbability(stats), "collection probability"))
+}
+
+/*
+Returns the name of the LM method, along with the name of the
+collection model, if any.
+*/
+func (sim *LMSimilarity) lmString(name string) string {
+	if coll := sim.collectionModel.Name(); coll != "" {
+		return fmt.Sprintf("LM %v - %v", name, coll)
+	}
+	return fmt.Sprintf("LM %v", name)
+}
+
+// Returns the collection model.
+func (sim *LMSimilarity) CollectionModel() CollectionModel {
+	return sim.collectionModel
+}
+
+// A strategy for computing the collection language model.
+type CollectionModel interface {
+	// Computes the probability p(w|C) according to the language model
+	// strategy for the current term.
+	ComputeProbability(stats *BasicStats) float32
+	// The name of the collection model strategy, or "".
+	Name() string
+}
+
+/*
+Models p(w|C) as the number of occurrences of the term in the
+collection, divided by the total number of tokens + 1.
+*/
+type DefaultCollectionModel struct{}
+
+func NewDefaultCollectionModel() *DefaultCollectionModel {
+	return &DefaultCollectionModel{}
+}
+
+func (m *DefaultCollectionModel) ComputeProbability(stats *BasicStats) float32 {
+	return (float32(stats.TotalTermFreq()) + 1) / (float32(stats.NumberOfFieldTokens()) + 1)
+}
+
+func (m *DefaultCollectionModel) Name() string {
+	return ""
+}
+
+// search/similarities/LMDirichletSimilarity.java
+
+/*
+Bayesian smoothing using Dirichlet priors. From Chengxiang Zhai and
+John Lafferty. 2001. A study of smoothing methods for language models
+applied to Ad Hoc information retrieval. In Proceedings of the 24th
+annual international ACM SIGIR conference on Research and development
+in information retrieval (SIGIR '01). ACM, New York, NY, USA,
+334-342.
+
+The formula as defined the paper assigns a negative score to
+documents that contain the term, but with fewer occurrences than
+predicted by the collection language model. The Lucene implementation
+returns 0 for such documents.
+*/
+type LMDirichletSimilarity struct {
+	*LMSimilarity
+	// The mu parameter.
+	mu float32
+}
+
+// Instantiates the similarity with the default mu value of 2000.
+func NewLMDirichletSimilarity() *LMDirichletSimilarity {
+	return NewLMDirichletSimilarityWith(NewDefaultCollectionModel(), 2000)
+}
+
+// Instantiates the similarity with the provided collection model and
+// mu parameter.
+func NewLMDirichletSimilarityWith(collectionModel CollectionModel, mu float32) *LMDirichletSimilarity {
+	ans := &LMDirichletSimilarity{mu: mu}
+	ans.LMSimilarity = newLMSimilarity(ans, collectionModel)
+	return ans
+}
+
+func (sim *LMDirichletSimilarity) Score(stats *BasicStats, freq, docLen float32) float32 {
+	score := stats.TotalBoost() * float32(
+		math.Log(float64(1+freq/(sim.mu*sim.collectionProbability(stats))))+
+			math.Log(float64(sim.mu/(docLen+sim.mu))))
+	if score > 0 {
+		return score
+	}
+	return 0
+}
+
+func (sim *LMDirichletSimilarity) AddExplanation(expl *ExplanationImpl,
+	stats *BasicStats, doc int, freq, docLen float32) {
+
+	if stats.TotalBoost() != 1 {
+		expl.addDetail(newExplanation(stats.TotalBoost(), "boost"))
+	}
+
+	expl.addDetail(newExplanation(sim.mu, "mu"))
+	expl.addDetail(newExplanation(float32(math.Log(
+		float64(1+freq/(sim.mu*sim.collectionProbability(stats))))), "term weight"))
+	expl.addDetail(newExplanation(float32(math.Log(
+		float64(sim.mu/(docLen+sim.mu)))), "document norm"))
+	sim.addCollectionExplanation(expl, stats)
+}
+
+// Returns the mu parameter.
+func (sim *LMDirichletSimilarity) Mu() float32 {
+	return sim.mu
+}
+
+func (sim *LMDirichletSimilarity) String() string {
+	return sim.lmString(fmt.Sprintf("Dirichlet(%f)", sim.mu))
+}
+
+// search/similarities/LMJelinekMercerSimilarity.java
+
+/*
+Language model based on the Jelinek-Mercer smoothing method. From
+Chengxiang Zhai and John Lafferty. 2001. A study of smoothing methods
+for language models applied to Ad Hoc information retrieval. In
+Proceedings of the 24th annual international ACM SIGIR conference on
+Research and development in information retrieval (SIGIR '01). ACM,
+New York, NY, USA, 334-342.
+
+The model has a single parameter, lambda. According to said paper,
+the optimal value depends on both the collection and the query. The
+optimal value is around 0.1 for title queries and 0.7 for long
+queries.
+*/
+type LMJelinekMercerSimilarity struct {
+	*LMSimilarity
+	// The lambda parameter.
+	lambda float32
+}
+
+// Instantiates with the specified lambda parameter.
+func NewLMJelinekMercerSimilarity(lambda float32) *LMJelinekMercerSimilarity {
+	return NewLMJelinekMercerSimilarityWith(NewDefaultCollectionModel(), lambda)
+}
+
+// Instantiates with the specified collection model and lambda
+// parameter.
+func NewLMJelinekMercerSimilarityWith(collectionModel CollectionModel,
+	lambda float32) *LMJelinekMercerSimilarity {
+
+	ans := &LMJelinekMercerSimilarity{lambda: lambda}
+	ans.LMSimilarity = newLMSimilarity(ans, collectionModel)
+	return ans
+}
+
+func (sim *LMJelinekMercerSimilarity) Score(stats *BasicStats, freq, docLen float32) float32 {
+	return stats.TotalBoost() * float32(math.Log(float64(
+		1+((1-sim.lambda)*freq/docLen)/(sim.lambda*sim.collectionProbability(stats)))))
+}
+
+func (sim *LMJelinekMercerSimilarity) AddExplanation(expl *ExplanationImpl,
+	stats *BasicStats, doc int, freq, docLen float32) {
+
+	if stats.TotalBoost() != 1 {
+		expl.addDetail(newExplanation(stats.TotalBoost(), "boost"))
+	}
+	expl.addDetail(newExplanation(sim.lambda, "lambda"))
+	sim.addCollectionExplanation(expl, stats)
+}
+
+// Returns the lambda parameter.
+func (sim *LMJelinekMercerSimilarity) Lambda() float32 {
+	return sim.lambda
+}
+
+func (sim *LMJelinekMercerSimilarity) String() string {
+	return sim.lmString(fmt.Sprintf("Jelinek-Mercer(%f)", sim.lambda))
+}
@@ -0,0 +1,117 @@
+package search
+
+import (
+	"fmt"
+	"github.com/balzaczyy/golucene/core/index"
+	. "github.com/balzaczyy/golucene/core/search/model"
+	"github.com/balzaczyy/golucene/core/util"
+)
+
+// search/MatchAllDocsQuery.java
+
+/* A query that matches all documents. */
+type MatchAllDocsQuery struct {
+	*AbstractQuery
+}
+
+func NewMatchAllDocsQuery() *MatchAllDocsQuery {
+	ans := new(MatchAllDocsQuery)
+	ans.AbstractQuery = NewAbstractQuery(ans)
+	return ans
+}
+
+func (q *MatchAllDocsQuery) CreateWeight(ss *IndexSearcher) (Weight, error) {
+	return newMatchAllDocsWeight(q), nil
+}
+
+func (q *MatchAllDocsQuery) ExtractTerms(terms *index.TermSet) {}
+
+func (q *MatchAllDocsQuery) ToString(field string) string {
+	if q.boost != 1 {
+		return fmt.Sprintf("*:*^%v", q.boost)
+	}
+	return "*:*"
+}
+
+type MatchAllDocsWeight struct {
+	*WeightImpl
+	owner       *MatchAllDocsQuery
+	queryWeight float32
+	queryNorm   float32
+}
+
+func newMatchAllDocsWeight(owner *MatchAllDocsQuery) *MatchAllDocsWeight {
+	ans := &MatchAllDocsWeight{owner: owner}
+	ans.WeightImpl = newWeightImpl(ans)
+	return ans
+}
+
+func (w *MatchAllDocsWeight) String() string {
+	return fmt.Sprintf("weight(%v)", w.owner)
+}
+
+func (w *MatchAllDocsWeight) ValueForNormalization() float32 {
+	w.queryWeight = w.owner.boost
+	return w.queryWeight * w.queryWeight
+}
+
+func (w *MatchAllDocsWeight) Normalize(queryNorm, topLevelBoost float32) {
+	w.queryNorm = queryNorm * topLevelBoost
+	w.queryWeight *= w.queryNorm
+}
+
+func (w *MatchAllDocsWeight) IsScoresDocsOutOfOrder() bool {
+	return false
+}
+
+func (w *MatchAllDocsWeight) Scorer(ctx *index.AtomicReaderContext,
+	acceptDocs util.Bits) (Scorer, error) {
+	return newMatchAllScorer(w, ctx.Reader().MaxDoc(), acceptDocs, w.queryWeight), nil
+}
+
+func (w *MatchAllDocsWeight) Explain(ctx *index.AtomicReaderContext, doc int) (Explanation, error) {
+	// explain query weight
+	queryExpl := newComplexExplanation(true, w.queryWeight, "MatchAllDocsQuery, product of:")
+	if w.owner.boost != 1 {
+		queryExpl.addDetail(newExplanation(w.owner.boost, "boost"))
+	}
+	queryExpl.addDetail(newExplanation(w.queryNorm, "queryNorm"))
+	return queryExpl, nil
+}
+
+type MatchAllScorer struct {
+	*abstractScorer
+	score    float32
+	doc      int
+	maxDoc   int
+	liveDocs util.Bits
+}
+
+func newMatchAllScorer(w Weight, maxDoc int, liveDocs util.Bits, score float32) *MatchAllScorer {
+	ans := &MatchAllScorer{
+		doc:      -1,
+		maxDoc:   maxDoc,
+		liveDocs: liveDocs,
+		score:    score,
+	}
+	ans.abstractScorer = newScorer(ans, w)
+	return ans
+}
+
+func (s *MatchAllScorer) DocId() int              { return s.doc }
+func (s *MatchAllScorer) Freq() (int, error)      { return 1, nil }
+func (s *MatchAllScorer) Score() (float32, error) { return s.score, nil }
+
+func (s *MatchAllScorer) NextDoc() (int, error) {
+	return s.Advance(s.doc + 1)
+}
+
+func (s *MatchAllScorer) Advance(target int) (int, error) {
+	for s.doc = target; s.doc < s.maxDoc; s.doc++ {
+		if s.liveDocs == nil || s.liveDocs.At(s.doc) {
+			return s.doc, nil
+		}
+	}
+	s.doc = NO_MORE_DOCS
+	return s.doc, nil
+}
@@ -0,0 +1,103 @@
+package search
+
+import (
+	std "github.com/balzaczyy/golucene/analysis/standard"
+	docu "github.com/balzaczyy/golucene/core/document"
+	"github.com/balzaczyy/golucene/core/index"
+	. "github.com/balzaczyy/golucene/core/search/model"
+	"github.com/balzaczyy/golucene/core/store"
+	"github.com/balzaczyy/golucene/core/util"
+	"strconv"
+	"testing"
+)
+
+func TestMatchAllDocsQueryWithDeletions(t *testing.T) {
+	dir, err := store.OpenFSDirectory(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := index.NewIndexWriter(dir, index.NewIndexWriterConfig(util.VERSION_LATEST, std.NewStandardAnalyzer()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	newDoc := func(id int) *docu.Document {
+		doc := docu.NewDocument()
+		doc.Add(docu.NewStringField("id", strconv.Itoa(id), docu.STORE_YES))
+		return doc
+	}
+	for i := 0; i < 10; i++ {
+		if err = w.AddDocument(newDoc(i).Fields()); err != nil {
+			t.Fatal(err)
+		}
+		if i == 4 {
+			if err = w.Commit(); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	// deletes the first version of docs 2 and 7, one in each segment
+	for _, id := range []int{2, 7} {
+		if err = w.UpdateDocument(index.NewTerm("id", strconv.Itoa(id)), newDoc(id).Fields(), nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	r, err := index.OpenDirectoryReader(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	assertEquals(t, 12, r.MaxDoc())
+	assertEquals(t, 10, r.NumDocs())
+
+	ss := NewIndexSearcher(r)
+	docs, err := ss.SearchTop(NewMatchAllDocsQuery(), 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEquals(t, 10, docs.TotalHits)
+	seen := make(map[string]bool)
+	for _, hit := range docs.ScoreDocs {
+		doc, err := r.Document(hit.Doc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id := doc.Get("id"); seen[id] {
+			t.Errorf("Expected a single live version of doc %v", id)
+		} else {
+			seen[id] = true
+		}
+	}
+	assertEquals(t, 10, len(seen))
+
+	// the scorer skips the deleted docs of the first segment, 2
+	ctx := r.Leaves()[0]
+	w2, err := ss.CreateNormalizedWeight(NewMatchAllDocsQuery())
+	if err != nil {
+		t.Fatal(err)
+	}
+	scorer, err := w2.(*MatchAllDocsWeight).Scorer(ctx, ctx.Reader().(index.AtomicReader).LiveDocs())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEquals(t, 3, mustInt(scorer.Advance(2)))
+	assertEquals(t, 4, mustInt(scorer.NextDoc()))
+	assertEquals(t, NO_MORE_DOCS, mustInt(scorer.NextDoc()))
+
+	// a boosted explanation details the boost and queryNorm
+	q := NewMatchAllDocsQuery()
+	q.SetBoost(2)
+	if docs, err = ss.SearchTop(q, 1); err != nil {
+		t.Fatal(err)
+	}
+	exp, err := ss.Explain(q, docs.ScoreDocs[0].Doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !exp.IsMatch() || exp.Value() != docs.ScoreDocs[0].Score {
+		t.Errorf("Expected a match scoring %v, but was %v", docs.ScoreDocs[0].Score, exp)
+	}
+	assertEquals(t, 2, len(exp.(ExplanationSPI).Details()))
+}
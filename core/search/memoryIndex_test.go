@@ -0,0 +1,103 @@
+package search
+
+import (
+	std "github.com/balzaczyy/golucene/analysis/standard"
+	docu "github.com/balzaczyy/golucene/core/document"
+	"github.com/balzaczyy/golucene/core/index"
+	"github.com/balzaczyy/golucene/core/store"
+	"github.com/balzaczyy/golucene/core/util"
+	"strings"
+	"testing"
+)
+
+func TestMemoryIndex(t *testing.T) {
+	const text = "Readings about Salmons and other select Alaska fishing Manuals"
+	analyzer := std.NewStandardAnalyzer()
+	mi := index.NewMemoryIndexWith(true)
+	if err := mi.AddField("content", text, analyzer); err != nil {
+		t.Fatal(err)
+	}
+	if err := mi.AddFieldFromStream("tags", index.KeywordTokenStream([]string{"Alaska", "Fish"})); err != nil {
+		t.Fatal(err)
+	}
+	ss := NewIndexSearcher(mi.CreateReader())
+
+	score := func(q Query) float32 {
+		docs, err := ss.SearchTop(q, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if docs.TotalHits == 0 {
+			return 0
+		}
+		return docs.ScoreDocs[0].Score
+	}
+	if score(NewTermQuery(index.NewTerm("content", "alaska"))) <= 0 {
+		t.Error("Expected content:alaska to match")
+	}
+	if score(NewTermQuery(index.NewTerm("content", "salmon"))) != 0 {
+		t.Error("Expected content:salmon not to match")
+	}
+	if score(NewTermQuery(index.NewTerm("tags", "Fish"))) <= 0 {
+		t.Error("Expected keyword tags:Fish to match")
+	}
+	phrase := NewPhraseQuery()
+	phrase.Add(index.NewTerm("content", "alaska"))
+	phrase.Add(index.NewTerm("content", "fishing"))
+	if score(phrase) <= 0 {
+		t.Error("Expected content:\"alaska fishing\" to match")
+	}
+	phrase.SetSlop(1)
+	phrase.Add(index.NewTerm("content", "salmons"))
+	if score(phrase) != 0 {
+		t.Error("Expected content:\"alaska fishing salmons\"~1 not to match")
+	}
+
+	// scores the same as a one document index
+	dir, err := store.OpenFSDirectory(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := index.NewIndexWriter(dir, index.NewIndexWriterConfig(util.VERSION_LATEST, analyzer))
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := docu.NewDocument()
+	doc.Add(docu.NewTextFieldFromString("content", text, docu.STORE_NO))
+	if err = w.AddDocument(doc.Fields()); err != nil {
+		t.Fatal(err)
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	r, err := index.OpenDirectoryReader(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	q := NewTermQuery(index.NewTerm("content", "alaska"))
+	docs, err := NewIndexSearcher(r).SearchTop(q, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEquals(t, docs.ScoreDocs[0].Score, score(q))
+
+	// positions and offsets are kept for phrases and highlighting
+	terms := mi.CreateReader().Terms("content").Iterator(nil)
+	if ok, err := terms.SeekExact([]byte("alaska")); !ok || err != nil {
+		t.Fatalf("Expected term alaska, but was %v (%v)", ok, err)
+	}
+	postings, err := terms.DocsAndPositions(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEquals(t, 0, mustInt(postings.NextDoc()))
+	assertEquals(t, 6, mustInt(postings.NextPosition()))
+	assertEquals(t, strings.Index(text, "Alaska"), mustInt(postings.StartOffset()))
+	assertEquals(t, strings.Index(text, "Alaska")+len("Alaska"), mustInt(postings.EndOffset()))
+
+	mi.Reset()
+	if score(NewTermQuery(index.NewTerm("content", "alaska"))) != 0 {
+		t.Error("Expected no match after reset")
+	}
+}
@@ -0,0 +1,82 @@
+package search
+
+import (
+	"bytes"
+	"github.com/balzaczyy/golucene/core/index"
+	"sort"
+)
+
+// search/MultiTermQuery.java
+
+type MultiTermQuerySPI interface {
+	// Returns true if the term, of the field of the query, matches.
+	AcceptTerm(term []byte) bool
+}
+
+/*
+An abstract Query that matches documents containing a subset of terms
+provided by a term filter, like WildcardQuery or TermRangeQuery.
+
+The query is rewritten to a BooleanQuery of the matching terms of the
+index, each scored like a TermQuery. As the number of clauses of a
+BooleanQuery is limited, rewriting panics if more than 1024 terms
+match.
+*/
+type MultiTermQuery struct {
+	*AbstractQuery
+	spi   MultiTermQuerySPI
+	field string
+}
+
+/* Constructs a query matching terms of the given field accepted by spi. */
+func NewMultiTermQuery(spi MultiTermQuerySPI, field string) *MultiTermQuery {
+	return &MultiTermQuery{
+		AbstractQuery: NewAbstractQuery(spi),
+		spi:           spi,
+		field:         field,
+	}
+}
+
+// Returns the field name for this query.
+func (q *MultiTermQuery) Field() string {
+	return q.field
+}
+
+func (q *MultiTermQuery) Rewrite(reader index.IndexReader) Query {
+	terms := collectTerms(reader, q.field, q.spi.AcceptTerm)
+	assert2(len(terms) <= maxClauseCount, "too many terms: %v matched %v terms", q.spi, len(terms))
+	bq := NewBooleanQueryDisableCoord(true)
+	for _, term := range terms {
+		bq.Add(NewTermQuery(index.NewTermFromBytes(q.field, term)), SHOULD)
+	}
+	bq.SetBoost(q.boost)
+	return bq
+}
+
+/* Returns the sorted distinct terms of the field accepted in any leaf of the reader. */
+func collectTerms(reader index.IndexReader, field string, accept func([]byte) bool) [][]byte {
+	var ans [][]byte
+	seen := make(map[string]bool)
+	for _, leaf := range reader.Leaves() {
+		terms := leaf.Reader().(index.AtomicReader).Terms(field)
+		if terms == nil {
+			continue
+		}
+		termsEnum := terms.Iterator(nil)
+		for {
+			term, err := termsEnum.Next()
+			if err != nil {
+				panic(err) // Rewrite() has no error to return
+			}
+			if term == nil {
+				break
+			}
+			if !seen[string(term)] && accept(term) {
+				seen[string(term)] = true
+				ans = append(ans, append([]byte(nil), term...))
+			}
+		}
+	}
+	sort.Slice(ans, func(i, j int) bool { return bytes.Compare(ans[i], ans[j]) < 0 })
+	return ans
+}
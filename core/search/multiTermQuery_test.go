@@ -0,0 +1,69 @@
+package search
+
+import (
+	std "github.com/balzaczyy/golucene/analysis/standard"
+	"github.com/balzaczyy/golucene/core/index"
+	"github.com/balzaczyy/golucene/core/store"
+	"github.com/balzaczyy/golucene/core/util"
+	"strings"
+	"testing"
+)
+
+func TestMultiTermQueries(t *testing.T) {
+	dir, err := store.OpenFSDirectory(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := index.NewIndexWriter(dir, index.NewIndexWriterConfig(util.VERSION_LATEST, std.NewStandardAnalyzer()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, title := range []string{"apple pie", "apricot jam", "banana split", "applet code", "grape juice"} {
+		addDocument(t, w, title)
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	r, err := index.OpenDirectoryReader(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	ss := NewIndexSearcher(r)
+
+	upper := "b"
+	for _, c := range []struct {
+		query    Query
+		str      string
+		rewrites string
+		hits     int
+	}{
+		{NewPrefixQuery(index.NewTerm("title", "ap")), "ap*", "apple applet apricot", 3},
+		{NewWildcardQuery(index.NewTerm("title", "appl?")), "appl?", "apple", 1},
+		{NewWildcardQuery(index.NewTerm("title", "*e*t")), "*e*t", "applet", 1},
+		{NewRegexpQuery(index.NewTerm("title", "[bg].*")), "/[bg].*/", "banana grape", 2},
+		{NewTermRangeQueryFromStrings("title", nil, &upper, true, false), "[* TO b}", "apple applet apricot", 3},
+		{NewFuzzyQuery(index.NewTerm("title", "aple")), "aple~2", "apple applet pie", 2},
+	} {
+		assertEquals(t, c.str, c.query.ToString("title"))
+		rewritten := c.query.Rewrite(r)
+		var terms []string
+		for _, clause := range rewritten.(*BooleanQuery).Clauses() {
+			terms = append(terms, string(clause.Query().(*TermQuery).term.Bytes))
+		}
+		assertEquals(t, c.rewrites, strings.Join(terms, " "))
+		docs, err := ss.SearchTop(c.query, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEquals(t, c.hits, docs.TotalHits)
+	}
+
+	// exact matches rank first for fuzzy queries
+	fq := NewFuzzyQueryWith(index.NewTerm("title", "apple"), 1, 2, 50, true)
+	clauses := fq.Rewrite(r).(*BooleanQuery).Clauses()
+	assertEquals(t, 2, len(clauses))
+	assertEquals(t, "apple", clauses[0].Query().ToString("title"))
+	assertEquals(t, 1, FloatToEdits(0.5, 3))
+	assertEquals(t, 2, FloatToEdits(3, 5))
+}
@@ -0,0 +1,53 @@
+package search
+
+import (
+	"github.com/balzaczyy/golucene/core/index"
+)
+
+// search/NGramPhraseQuery.java
+
+/*
+This is a PhraseQuery which is optimized for n-gram phrase query.
+For example, when you query "ABCD" on a 2-gram field, you may want to
+use NGramPhraseQuery rather than PhraseQuery, because NGramPhraseQuery
+will Rewrite() the query to "AB/0 CD/2", while PhraseQuery will query
+"AB/0 BC/1 CD/2" (where term/position): the grams in between overlap
+the ones kept, so they don't need to be verified.
+*/
+type NGramPhraseQuery struct {
+	*PhraseQuery
+	n int
+}
+
+// Constructor that takes gram size.
+func NewNGramPhraseQuery(n int) *NGramPhraseQuery {
+	ans := &NGramPhraseQuery{PhraseQuery: NewPhraseQuery(), n: n}
+	ans.AbstractQuery = NewAbstractQuery(ans)
+	return ans
+}
+
+func (q *NGramPhraseQuery) Rewrite(reader index.IndexReader) Query {
+	if q.slop != 0 ||
+		q.n < 2 || // non-overlap n-gram cannot be optimized
+		len(q.terms) < 3 { // too short to optimize
+		return q.PhraseQuery.Rewrite(reader)
+	}
+
+	// check all posIncrement is 1, if not, cannot optimize
+	for i := 1; i < len(q.positions); i++ {
+		if q.positions[i-1]+1 != q.positions[i] {
+			return q.PhraseQuery.Rewrite(reader)
+		}
+	}
+
+	// now create the new optimized phrase query for n-gram
+	optimized := NewPhraseQuery()
+	optimized.SetBoost(q.boost)
+	lastPos := len(q.terms) - 1
+	for i, term := range q.terms {
+		if i%q.n == 0 || i >= lastPos {
+			optimized.AddAt(term, q.positions[i])
+		}
+	}
+	return optimized
+}
@@ -0,0 +1,72 @@
+package search
+
+import (
+	std "github.com/balzaczyy/golucene/analysis/standard"
+	"github.com/balzaczyy/golucene/core/index"
+	"github.com/balzaczyy/golucene/core/store"
+	"github.com/balzaczyy/golucene/core/util"
+	"testing"
+)
+
+func TestNGramPhraseQuery(t *testing.T) {
+	dir, err := store.OpenFSDirectory(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := index.NewIndexWriter(dir, index.NewIndexWriterConfig(util.VERSION_LATEST, std.NewStandardAnalyzer()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// bigrams of "abcde", "bcdef" and "abxde"
+	for _, title := range []string{"ab bc cd de", "bc cd de ef", "ab bx xd de"} {
+		addDocument(t, w, title)
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	r, err := index.OpenDirectoryReader(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	ss := NewIndexSearcher(r)
+
+	newQuery := func(grams ...string) *NGramPhraseQuery {
+		q := NewNGramPhraseQuery(2)
+		for _, gram := range grams {
+			q.Add(index.NewTerm("title", gram))
+		}
+		return q
+	}
+
+	// only every other gram, and the last one, is verified
+	q := newQuery("ab", "bc", "cd", "de")
+	assertEquals(t, `title:"ab bc cd de"`, q.String())
+	assertEquals(t, `title:"ab ? cd de"`, q.Rewrite(r).ToString(""))
+	docs, err := ss.SearchTop(q, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEquals(t, 1, docs.TotalHits)
+	assertEquals(t, 0, docs.ScoreDocs[0].Doc)
+
+	q = newQuery("bc", "cd", "de", "ef")
+	assertEquals(t, `title:"bc ? de ef"`, q.Rewrite(r).ToString(""))
+	if docs, err = ss.SearchTop(q, 10); err != nil {
+		t.Fatal(err)
+	}
+	assertEquals(t, 1, docs.TotalHits)
+	assertEquals(t, 1, docs.ScoreDocs[0].Doc)
+
+	// sloppy, short or gapped phrases are left alone
+	q = newQuery("ab", "bc", "cd")
+	q.SetSlop(1)
+	assertEquals(t, q.PhraseQuery, q.Rewrite(r))
+	short := newQuery("ab", "bc")
+	assertEquals(t, short.PhraseQuery, short.Rewrite(r))
+	q = NewNGramPhraseQuery(2)
+	q.Add(index.NewTerm("title", "ab"))
+	q.AddAt(index.NewTerm("title", "cd"), 2)
+	q.AddAt(index.NewTerm("title", "de"), 3)
+	assertEquals(t, q.PhraseQuery, q.Rewrite(r))
+}
@@ -0,0 +1,481 @@
+package search
+
+import (
+	"container/heap"
+	"fmt"
+	. "github.com/balzaczyy/golucene/core/search/model"
+)
+
+// search/spans/ConjunctionSpans.java
+
+type conjunctionSpansSPI interface {
+	/*
+		Returns true iff the current doc matches, with all subSpans
+		positioned on it.
+	*/
+	twoPhaseCurrentDocMatches() (bool, error)
+}
+
+/*
+Common super class for multiple sub spans required in a document.
+Iterates over the conjunction of the sub spans, and only returns a
+doc once twoPhaseCurrentDocMatches() confirmed it.
+*/
+type conjunctionSpans struct {
+	spi                      conjunctionSpansSPI
+	subSpans                 []Spans // in query order
+	doc                      int
+	atFirstInCurrentDoc      bool // a first start position is available in current doc for nextStartPosition
+	oneExhaustedInCurrentDoc bool // one subspans exhausted in current doc
+}
+
+func newConjunctionSpans(spi conjunctionSpansSPI, subSpans []Spans) *conjunctionSpans {
+	assert2(len(subSpans) >= 2, "Less than 2 subSpans.size():%v", len(subSpans))
+	return &conjunctionSpans{
+		spi:      spi,
+		subSpans: subSpans,
+		doc:      -1,
+	}
+}
+
+func (s *conjunctionSpans) DocId() int {
+	return s.doc
+}
+
+func (s *conjunctionSpans) NextDoc() (int, error) {
+	doc, err := s.subSpans[0].NextDoc()
+	if err != nil {
+		return 0, err
+	}
+	return s.toMatchDoc(doc)
+}
+
+func (s *conjunctionSpans) Advance(target int) (int, error) {
+	doc, err := s.subSpans[0].Advance(target)
+	if err != nil {
+		return 0, err
+	}
+	return s.toMatchDoc(doc)
+}
+
+/*
+Leap-frogs from the given doc of the first subSpans to the next doc
+that contains all subSpans and a match of them.
+*/
+func (s *conjunctionSpans) toMatchDoc(doc int) (int, error) {
+	lead := s.subSpans[0]
+	for doc != NO_MORE_DOCS {
+		next, err := s.advanceOthers(doc)
+		if err != nil {
+			return 0, err
+		}
+		if next != doc {
+			if doc, err = lead.Advance(next); err != nil {
+				return 0, err
+			}
+			continue
+		}
+
+		s.doc = doc
+		s.oneExhaustedInCurrentDoc = false
+		ok, err := s.spi.twoPhaseCurrentDocMatches()
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			return doc, nil
+		}
+		if doc, err = lead.NextDoc(); err != nil {
+			return 0, err
+		}
+	}
+	s.doc = NO_MORE_DOCS
+	return s.doc, nil
+}
+
+/*
+Positions the subSpans after the first on the given doc, and returns
+it if they all contain it, or the first doc beyond it that one of
+them is on otherwise.
+*/
+func (s *conjunctionSpans) advanceOthers(doc int) (int, error) {
+	for _, spans := range s.subSpans[1:] {
+		other := spans.DocId()
+		if other < doc {
+			var err error
+			if other, err = spans.Advance(doc); err != nil {
+				return 0, err
+			}
+		}
+		if other > doc {
+			return other, nil
+		}
+	}
+	return doc, nil
+}
+
+func (s *conjunctionSpans) Collect(collector SpanCollector) error {
+	for _, spans := range s.subSpans {
+		if err := spans.Collect(collector); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *conjunctionSpans) String() string {
+	return fmt.Sprintf("%v@%v", s.subSpans, s.doc)
+}
+
+// search/spans/NearSpansOrdered.java
+
+/*
+A Spans that is formed from the ordered subspans of a SpanNearQuery
+where the subspans do not overlap and have a maximum slop between
+them.
+
+The formed spans only contains minimum slop matches. The matching
+slop is computed from the distance(s) between the non overlapping
+matching Spans.
+
+Successive matches are always formed from the successive Spans of
+the SpanNearQuery.
+
+The formed spans may contain overlaps when the slop is at least 1.
+For example, when querying using "t1 t2 t3" with slop at least 1, the
+fragment "t1 t2 t1 t3 t2 t3" matches twice: "t1 t2 .. t3" and
+"t1 .. t2 t3".
+*/
+type nearSpansOrdered struct {
+	*conjunctionSpans
+	allowedSlop int
+	matchStart  int
+	matchEnd    int
+	matchWidth  int
+}
+
+func newNearSpansOrdered(allowedSlop int, subSpans []Spans) *nearSpansOrdered {
+	ans := &nearSpansOrdered{
+		allowedSlop: allowedSlop,
+		matchStart:  -1,
+		matchEnd:    -1,
+		matchWidth:  -1,
+	}
+	ans.conjunctionSpans = newConjunctionSpans(ans, subSpans)
+	return ans
+}
+
+func (s *nearSpansOrdered) twoPhaseCurrentDocMatches() (bool, error) {
+	s.atFirstInCurrentDoc = false
+	ok, err := s.nextMatch()
+	if err != nil {
+		return false, err
+	}
+	if ok {
+		s.atFirstInCurrentDoc = true
+	}
+	return ok, nil
+}
+
+// Positions the subSpans on the next match of current doc, if any.
+func (s *nearSpansOrdered) nextMatch() (bool, error) {
+	s.oneExhaustedInCurrentDoc = false
+	for !s.oneExhaustedInCurrentDoc {
+		start, err := s.subSpans[0].NextStartPosition()
+		if err != nil {
+			return false, err
+		}
+		if start == NO_MORE_POSITIONS {
+			break
+		}
+		ok, err := s.stretchToOrder()
+		if err != nil {
+			return false, err
+		}
+		if ok && s.matchWidth <= s.allowedSlop {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *nearSpansOrdered) NextStartPosition() (int, error) {
+	if s.atFirstInCurrentDoc {
+		s.atFirstInCurrentDoc = false
+		return s.matchStart, nil
+	}
+	ok, err := s.nextMatch()
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		s.matchStart, s.matchEnd = NO_MORE_POSITIONS, NO_MORE_POSITIONS
+	}
+	return s.matchStart, nil
+}
+
+/*
+Order the subSpans within the same document by using
+nextStartPosition on all subSpans after the first as little as
+necessary. Return true when the subSpans could be ordered in this
+way, otherwise at least one is exhausted in the current doc.
+*/
+func (s *nearSpansOrdered) stretchToOrder() (bool, error) {
+	prevSpans := s.subSpans[0]
+	s.matchStart = prevSpans.StartPosition()
+	assert2(prevSpans.StartPosition() != NO_MORE_POSITIONS, "prevSpans no start position %v", prevSpans)
+	assert(prevSpans.EndPosition() != NO_MORE_POSITIONS)
+	s.matchWidth = 0
+	for _, spans := range s.subSpans[1:] {
+		assert(spans.StartPosition() != NO_MORE_POSITIONS)
+		assert(spans.EndPosition() != NO_MORE_POSITIONS)
+		start, err := advanceSpansPosition(spans, prevSpans.EndPosition())
+		if err != nil {
+			return false, err
+		}
+		if start == NO_MORE_POSITIONS {
+			s.oneExhaustedInCurrentDoc = true
+			return false, nil
+		}
+		s.matchWidth += start - prevSpans.EndPosition()
+		prevSpans = spans
+	}
+	s.matchEnd = s.subSpans[len(s.subSpans)-1].EndPosition()
+	return true, nil // all subSpans ordered and non overlapping
+}
+
+// Advances the spans to its first start position at or after position.
+func advanceSpansPosition(spans Spans, position int) (int, error) {
+	for spans.StartPosition() < position {
+		if _, err := spans.NextStartPosition(); err != nil {
+			return 0, err
+		}
+	}
+	return spans.StartPosition(), nil
+}
+
+func (s *nearSpansOrdered) StartPosition() int {
+	if s.atFirstInCurrentDoc {
+		return -1
+	}
+	return s.matchStart
+}
+
+func (s *nearSpansOrdered) EndPosition() int {
+	if s.atFirstInCurrentDoc {
+		return -1
+	}
+	return s.matchEnd
+}
+
+func (s *nearSpansOrdered) Width() int {
+	return s.matchWidth
+}
+
+func (s *nearSpansOrdered) String() string {
+	return fmt.Sprintf("NearSpansOrdered(%v)", s.conjunctionSpans)
+}
+
+// search/spans/NearSpansUnordered.java
+
+/*
+Similar to nearSpansOrdered, but for the unordered case. Matches are
+formed by the sub spans in any order and they may overlap; the slop
+is computed from the distance between the first start and the last
+end position, minus the total length of the sub spans.
+*/
+type nearSpansUnordered struct {
+	*conjunctionSpans
+	allowedSlop        int
+	subSpanCells       []*spansCell // in query order
+	spanPositionQueue  spanPositionQueue
+	totalSpanLength    int
+	maxEndPositionCell *spansCell
+}
+
+func newNearSpansUnordered(allowedSlop int, subSpans []Spans) *nearSpansUnordered {
+	ans := &nearSpansUnordered{
+		allowedSlop:       allowedSlop,
+		subSpanCells:      make([]*spansCell, len(subSpans)),
+		spanPositionQueue: make(spanPositionQueue, 0, len(subSpans)),
+	}
+	for i, spans := range subSpans { // sub spans in query order
+		ans.subSpanCells[i] = &spansCell{owner: ans, in: spans, spanLength: -1}
+	}
+	ans.conjunctionSpans = newConjunctionSpans(ans, subSpans)
+	// -1 startPosition/endPosition also at doc -1
+	ans.maxEndPositionCell = ans.subSpanCells[0]
+	ans.spanPositionQueue.add(ans.maxEndPositionCell)
+	return ans
+}
+
+/*
+Maintains totalSpanLength and maxEndPositionCell of its owner while
+the wrapped spans are advanced.
+*/
+type spansCell struct {
+	owner      *nearSpansUnordered
+	in         Spans
+	spanLength int
+}
+
+func (c *spansCell) nextStartPosition() (int, error) {
+	res, err := c.in.NextStartPosition()
+	if err != nil {
+		return 0, err
+	}
+	if res != NO_MORE_POSITIONS {
+		c.adjustLength()
+	}
+	c.adjustMax() // also after last end position in current doc
+	return res, nil
+}
+
+func (c *spansCell) adjustLength() {
+	if c.spanLength != -1 {
+		// subtract old, possibly from a previous document
+		c.owner.totalSpanLength -= c.spanLength
+	}
+	assert(c.in.StartPosition() != NO_MORE_POSITIONS)
+	c.spanLength = c.in.EndPosition() - c.in.StartPosition()
+	assert(c.spanLength >= 0)
+	c.owner.totalSpanLength += c.spanLength // add new
+}
+
+func (c *spansCell) adjustMax() {
+	if c.owner.maxEndPositionCell.in.EndPosition() <= c.in.EndPosition() {
+		c.owner.maxEndPositionCell = c
+	}
+}
+
+type spanPositionQueue []*spansCell
+
+func (pq spanPositionQueue) Len() int      { return len(pq) }
+func (pq spanPositionQueue) Swap(i, j int) { pq[i], pq[j] = pq[j], pq[i] }
+
+func (pq spanPositionQueue) Less(i, j int) bool {
+	start1, start2 := pq[i].in.StartPosition(), pq[j].in.StartPosition()
+	if start1 == start2 {
+		return pq[i].in.EndPosition() < pq[j].in.EndPosition()
+	}
+	return start1 < start2
+}
+
+func (pq *spanPositionQueue) Push(x interface{}) {
+	*pq = append(*pq, x.(*spansCell))
+}
+
+func (pq *spanPositionQueue) Pop() interface{} {
+	old := *pq
+	n := len(old)
+	ans := old[n-1]
+	*pq = old[:n-1]
+	return ans
+}
+
+func (pq *spanPositionQueue) add(c *spansCell) { heap.Push(pq, c) }
+func (pq spanPositionQueue) top() *spansCell   { return pq[0] }
+func (pq *spanPositionQueue) updateTop()       { heap.Fix(pq, 0) }
+func (pq *spanPositionQueue) clear()           { *pq = (*pq)[:0] }
+
+func (s *nearSpansUnordered) minPositionCell() *spansCell {
+	return s.spanPositionQueue.top()
+}
+
+func (s *nearSpansUnordered) atMatch() bool {
+	assert(s.minPositionCell().in.DocId() == s.maxEndPositionCell.in.DocId())
+	return s.maxEndPositionCell.in.EndPosition()-s.minPositionCell().in.StartPosition()-s.totalSpanLength <= s.allowedSlop
+}
+
+// Used when all subSpans are positioned at the same doc.
+func (s *nearSpansUnordered) subSpanCellsToPositionQueue() error {
+	s.spanPositionQueue.clear()
+	for _, cell := range s.subSpanCells {
+		assert(cell.in.StartPosition() == -1)
+		if _, err := cell.nextStartPosition(); err != nil {
+			return err
+		}
+		assert(cell.in.StartPosition() != NO_MORE_POSITIONS)
+		s.spanPositionQueue.add(cell)
+	}
+	return nil
+}
+
+func (s *nearSpansUnordered) twoPhaseCurrentDocMatches() (bool, error) {
+	// at doc with all subSpans
+	if err := s.subSpanCellsToPositionQueue(); err != nil {
+		return false, err
+	}
+	for {
+		if s.atMatch() {
+			s.atFirstInCurrentDoc = true
+			s.oneExhaustedInCurrentDoc = false
+			return true, nil
+		}
+		assert(s.minPositionCell().in.StartPosition() != NO_MORE_POSITIONS)
+		start, err := s.minPositionCell().nextStartPosition()
+		if err != nil {
+			return false, err
+		}
+		if start == NO_MORE_POSITIONS { // exhausted a subSpan in current doc
+			return false, nil
+		}
+		s.spanPositionQueue.updateTop()
+	}
+}
+
+func (s *nearSpansUnordered) NextStartPosition() (int, error) {
+	if s.atFirstInCurrentDoc {
+		s.atFirstInCurrentDoc = false
+		return s.minPositionCell().in.StartPosition(), nil
+	}
+	for s.minPositionCell().in.StartPosition() == -1 { // initially at current doc
+		if _, err := s.minPositionCell().nextStartPosition(); err != nil {
+			return 0, err
+		}
+		s.spanPositionQueue.updateTop()
+	}
+	assert(s.minPositionCell().in.StartPosition() != NO_MORE_POSITIONS)
+	for {
+		start, err := s.minPositionCell().nextStartPosition()
+		if err != nil {
+			return 0, err
+		}
+		if start == NO_MORE_POSITIONS {
+			s.oneExhaustedInCurrentDoc = true
+			return NO_MORE_POSITIONS, nil
+		}
+		s.spanPositionQueue.updateTop()
+		if s.atMatch() {
+			return s.minPositionCell().in.StartPosition(), nil
+		}
+	}
+}
+
+func (s *nearSpansUnordered) StartPosition() int {
+	switch {
+	case s.atFirstInCurrentDoc:
+		return -1
+	case s.oneExhaustedInCurrentDoc:
+		return NO_MORE_POSITIONS
+	}
+	return s.minPositionCell().in.StartPosition()
+}
+
+func (s *nearSpansUnordered) EndPosition() int {
+	switch {
+	case s.atFirstInCurrentDoc:
+		return -1
+	case s.oneExhaustedInCurrentDoc:
+		return NO_MORE_POSITIONS
+	}
+	return s.maxEndPositionCell.in.EndPosition()
+}
+
+func (s *nearSpansUnordered) Width() int {
+	return s.maxEndPositionCell.in.StartPosition() - s.minPositionCell().in.StartPosition()
+}
+
+func (s *nearSpansUnordered) String() string {
+	return fmt.Sprintf("NearSpansUnordered(%v)", s.conjunctionSpans)
+}
@@ -0,0 +1,215 @@
+package search
+
+import (
+	"fmt"
+	"math"
+)
+
+// search/similarities/Normalization.java
+
+/*
+This type acts as the base for the second (length) normalization
+implementations in the DFR framework.
+*/
+type Normalization interface {
+	// Returns the normalized term frequency.
+	Tfn(stats *BasicStats, tf, length float32) float32
+	/*
+		Returns an explanation for the normalized term frequency.
+
+		The default normalization methods use the field length of the
+		document and the average field length to compute the normalized
+		term frequency. explainNormalization() returns an explanation
+		that includes both of them; normalizations that use other
+		statistics must provide their own explanation.
+	*/
+	Explain(stats *BasicStats, tf, length float32) Explanation
+	// Subclasses must override this method to return the code of the
+	// normalization formula. Refer to the original paper for the list.
+	String() string
+}
+
+func explainNormalization(name string, norm Normalization,
+	stats *BasicStats, tf, length float32) Explanation {
+
+	result := newExplanation(norm.Tfn(stats, tf, length), name+", computed from: ")
+	result.addDetail(newExplanation(stats.AvgFieldLength(), "avgFieldLength"))
+	result.addDetail(newExplanation(length, "len"))
+	return result
+}
+
+// Implementation used when there is no normalization.
+type NoNormalization struct{}
+
+func NewNoNormalization() *NoNormalization {
+	return &NoNormalization{}
+}
+
+func (n *NoNormalization) Tfn(stats *BasicStats, tf, length float32) float32 {
+	return tf
+}
+
+func (n *NoNormalization) Explain(stats *BasicStats, tf, length float32) Explanation {
+	return newExplanation(1, "no normalization")
+}
+
+func (n *NoNormalization) String() string {
+	return ""
+}
+
+// search/similarities/NormalizationH1.java
+
+/*
+Normalization model that assumes a uniform distribution of the term
+frequency.
+
+While this model is parameterless in the original article,
+information-based models introduced a multiplying factor. The default
+value for the c parameter is 1.
+*/
+type NormalizationH1 struct {
+	c float32
+}
+
+// Creates NormalizationH1 with the supplied parameter c.
+func NewNormalizationH1With(c float32) *NormalizationH1 {
+	return &NormalizationH1{c}
+}
+
+// Calls NewNormalizationH1With(1)
+func NewNormalizationH1() *NormalizationH1 {
+	return NewNormalizationH1With(1)
+}
+
+func (n *NormalizationH1) Tfn(stats *BasicStats, tf, length float32) float32 {
+	return tf * n.c * stats.AvgFieldLength() / length
+}
+
+func (n *NormalizationH1) Explain(stats *BasicStats, tf, length float32) Explanation {
+	return explainNormalization("NormalizationH1", n, stats, tf, length)
+}
+
+func (n *NormalizationH1) String() string {
+	return "1"
+}
+
+// Returns the c parameter.
+func (n *NormalizationH1) C() float32 {
+	return n.c
+}
+
+// search/similarities/NormalizationH2.java
+
+/*
+Normalization model in which the term frequency is inversely related
+to the length.
+
+While this model is parameterless in the original article, the
+thesis introduces the parameterized variant. The default value for
+the c parameter is 1.
+*/
+type NormalizationH2 struct {
+	c float32
+}
+
+// Creates NormalizationH2 with the supplied parameter c.
+func NewNormalizationH2With(c float32) *NormalizationH2 {
+	return &NormalizationH2{c}
+}
+
+// Calls NewNormalizationH2With(1)
+func NewNormalizationH2() *NormalizationH2 {
+	return NewNormalizationH2With(1)
+}
+
+func (n *NormalizationH2) Tfn(stats *BasicStats, tf, length float32) float32 {
+	return float32(float64(tf) * log2(1+float64(n.c*stats.AvgFieldLength()/length)))
+}
+
+func (n *NormalizationH2) Explain(stats *BasicStats, tf, length float32) Explanation {
+	return explainNormalization("NormalizationH2", n, stats, tf, length)
+}
+
+func (n *NormalizationH2) String() string {
+	return "2"
+}
+
+// Returns the c parameter.
+func (n *NormalizationH2) C() float32 {
+	return n.c
+}
+
+// search/similarities/NormalizationH3.java
+
+// Dirichlet Priors normalization
+type NormalizationH3 struct {
+	mu float32
+}
+
+// Creates NormalizationH3 with the supplied parameter mu.
+func NewNormalizationH3With(mu float32) *NormalizationH3 {
+	return &NormalizationH3{mu}
+}
+
+// Calls NewNormalizationH3With(800)
+func NewNormalizationH3() *NormalizationH3 {
+	return NewNormalizationH3With(800)
+}
+
+func (n *NormalizationH3) Tfn(stats *BasicStats, tf, length float32) float32 {
+	return (tf + n.mu*((float32(stats.TotalTermFreq())+1)/(float32(stats.NumberOfFieldTokens())+1))) /
+		(length + n.mu) * n.mu
+}
+
+func (n *NormalizationH3) Explain(stats *BasicStats, tf, length float32) Explanation {
+	return explainNormalization("NormalizationH3", n, stats, tf, length)
+}
+
+func (n *NormalizationH3) String() string {
+	return fmt.Sprintf("3(%v)", n.mu)
+}
+
+// Returns the parameter mu.
+func (n *NormalizationH3) Mu() float32 {
+	return n.mu
+}
+
+// search/similarities/NormalizationZ.java
+
+/*
+Pareto-Zipf Normalization. The default value for the z parameter is
+0.30.
+*/
+type NormalizationZ struct {
+	z float32
+}
+
+/*
+Creates NormalizationZ with the supplied parameter z. z represents
+A/(A+1) where A measures the specificity of the language.
+*/
+func NewNormalizationZWith(z float32) *NormalizationZ {
+	return &NormalizationZ{z}
+}
+
+// Calls NewNormalizationZWith(0.3)
+func NewNormalizationZ() *NormalizationZ {
+	return NewNormalizationZWith(0.30)
+}
+
+func (n *NormalizationZ) Tfn(stats *BasicStats, tf, length float32) float32 {
+	return float32(float64(tf) * math.Pow(float64(stats.AvgFieldLength()/length), float64(n.z)))
+}
+
+func (n *NormalizationZ) Explain(stats *BasicStats, tf, length float32) Explanation {
+	return explainNormalization("NormalizationZ", n, stats, tf, length)
+}
+
+func (n *NormalizationZ) String() string {
+	return fmt.Sprintf("Z(%v)", n.z)
+}
+
+// Returns the parameter z.
+func (n *NormalizationZ) Z() float32 {
+	return n.z
+}
@@ -0,0 +1,119 @@
+package search
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// search/payloads/PayloadFunction.java
+
+/*
+Defines a way for PayloadScoreQuery instances to transform the
+cumulative effects of payload scores for a document.
+*/
+type PayloadFunction interface {
+	/*
+		Calculate the score up to this point for this doc and field.
+		start and end are the span of the current match, numPayloadsSeen
+		the number of payloads seen so far, currentScore the current
+		score so far, and currentPayloadScore the score for the current
+		payload.
+	*/
+	CurrentScore(docId int, field string, start, end, numPayloadsSeen int,
+		currentScore, currentPayloadScore float32) float32
+	/*
+		Calculate the final score for all the payloads seen so far for
+		this doc/field.
+	*/
+	DocScore(docId int, field string, numPayloadsSeen int, payloadScore float32) float32
+	// Explains the final score of the payloads of a doc.
+	Explain(docId int, field string, numPayloadsSeen int, payloadScore float32) Explanation
+}
+
+func explainPayloadFunction(f PayloadFunction, docId int, field string,
+	numPayloadsSeen int, payloadScore float32) Explanation {
+
+	return newExplanation(f.DocScore(docId, field, numPayloadsSeen, payloadScore),
+		fmt.Sprintf("%v.docScore()", reflect.TypeOf(f).Name()))
+}
+
+// search/payloads/MaxPayloadFunction.java
+
+/*
+Returns the maximum payload score seen, else 1 if there are no
+payloads on the doc.
+
+Is thread safe and completely reusable.
+*/
+type MaxPayloadFunction struct{}
+
+func (f MaxPayloadFunction) CurrentScore(docId int, field string, start, end, numPayloadsSeen int,
+	currentScore, currentPayloadScore float32) float32 {
+
+	if numPayloadsSeen == 0 || currentPayloadScore > currentScore {
+		return currentPayloadScore
+	}
+	return currentScore
+}
+
+func (f MaxPayloadFunction) DocScore(docId int, field string, numPayloadsSeen int, payloadScore float32) float32 {
+	if numPayloadsSeen > 0 {
+		return payloadScore
+	}
+	return 1
+}
+
+func (f MaxPayloadFunction) Explain(docId int, field string, numPayloadsSeen int, payloadScore float32) Explanation {
+	return explainPayloadFunction(f, docId, field, numPayloadsSeen, payloadScore)
+}
+
+// search/payloads/MinPayloadFunction.java
+
+// Calculates the minimum payload seen.
+type MinPayloadFunction struct{}
+
+func (f MinPayloadFunction) CurrentScore(docId int, field string, start, end, numPayloadsSeen int,
+	currentScore, currentPayloadScore float32) float32 {
+
+	if numPayloadsSeen == 0 || currentPayloadScore < currentScore {
+		return currentPayloadScore
+	}
+	return currentScore
+}
+
+func (f MinPayloadFunction) DocScore(docId int, field string, numPayloadsSeen int, payloadScore float32) float32 {
+	if numPayloadsSeen > 0 {
+		return payloadScore
+	}
+	return 1
+}
+
+func (f MinPayloadFunction) Explain(docId int, field string, numPayloadsSeen int, payloadScore float32) Explanation {
+	return explainPayloadFunction(f, docId, field, numPayloadsSeen, payloadScore)
+}
+
+// search/payloads/AveragePayloadFunction.java
+
+/*
+Calculate the final score as the average score of all payloads seen.
+
+Is thread safe and completely reusable.
+*/
+type AveragePayloadFunction struct{}
+
+func (f AveragePayloadFunction) CurrentScore(docId int, field string, start, end, numPayloadsSeen int,
+	currentScore, currentPayloadScore float32) float32 {
+
+	return currentPayloadScore + currentScore
+}
+
+func (f AveragePayloadFunction) DocScore(docId int, field string, numPayloadsSeen int, payloadScore float32) float32 {
+	if numPayloadsSeen > 0 {
+		return payloadScore / float32(numPayloadsSeen)
+	}
+	return 1
+}
+
+func (f AveragePayloadFunction) Explain(docId int, field string, numPayloadsSeen int, payloadScore float32) Explanation {
+	return explainPayloadFunction(f, docId, field, numPayloadsSeen, payloadScore)
+}
@@ -0,0 +1,276 @@
+package search
+
+import (
+	"encoding/binary"
+	"fmt"
+	"github.com/balzaczyy/golucene/core/index"
+	. "github.com/balzaczyy/golucene/core/index/model"
+	"github.com/balzaczyy/golucene/core/util"
+	"math"
+	"reflect"
+)
+
+// search/payloads/PayloadDecoder.java
+
+// Defines a way of converting payloads to float values, for use by
+// PayloadScoreQuery.
+type PayloadDecoder interface {
+	// Compute a float value for the given payload, which is nil if the
+	// position has none.
+	ComputePayloadFactor(payload []byte) float32
+}
+
+/*
+A PayloadDecoder that interprets the first 4 bytes of a payload as a
+big-endian float. Positions without payload are given a factor of 1.
+*/
+var FLOAT_DECODER = floatDecoder{}
+
+type floatDecoder struct{}
+
+func (d floatDecoder) ComputePayloadFactor(payload []byte) float32 {
+	if payload == nil {
+		return 1
+	}
+	return math.Float32frombits(binary.BigEndian.Uint32(payload))
+}
+
+// search/payloads/PayloadScoreQuery.java
+
+/*
+A Query class that uses a PayloadFunction to modify the score of a
+wrapped SpanQuery. The payloads of all the terms of each match of the
+wrapped query are decoded by a PayloadDecoder, and the PayloadFunction
+combines them to a payload score per document.
+*/
+type PayloadScoreQuery struct {
+	*AbstractQuery
+	wrappedQuery     SpanQuery
+	function         PayloadFunction
+	decoder          PayloadDecoder
+	includeSpanScore bool
+}
+
+/*
+Creates a new PayloadScoreQuery, which multiplies the score of the
+wrapped query with the payload score.
+*/
+func NewPayloadScoreQuery(wrappedQuery SpanQuery, function PayloadFunction,
+	decoder PayloadDecoder) *PayloadScoreQuery {
+	return NewPayloadScoreQueryIncludeSpanScore(wrappedQuery, function, decoder, true)
+}
+
+/*
+Creates a new PayloadScoreQuery. If includeSpanScore is false, the
+score of a document is its payload score only.
+*/
+func NewPayloadScoreQueryIncludeSpanScore(wrappedQuery SpanQuery, function PayloadFunction,
+	decoder PayloadDecoder, includeSpanScore bool) *PayloadScoreQuery {
+
+	assert(wrappedQuery != nil)
+	assert(function != nil)
+	assert(decoder != nil)
+	ans := &PayloadScoreQuery{
+		wrappedQuery:     wrappedQuery,
+		function:         function,
+		decoder:          decoder,
+		includeSpanScore: includeSpanScore,
+	}
+	ans.AbstractQuery = NewAbstractQuery(ans)
+	return ans
+}
+
+// Returns the wrapped SpanQuery.
+func (q *PayloadScoreQuery) WrappedQuery() SpanQuery {
+	return q.wrappedQuery
+}
+
+func (q *PayloadScoreQuery) Field() string {
+	return q.wrappedQuery.Field()
+}
+
+func (q *PayloadScoreQuery) Rewrite(reader index.IndexReader) Query {
+	if matchRewritten := q.wrappedQuery.Rewrite(reader).(SpanQuery); matchRewritten != q.wrappedQuery {
+		ans := NewPayloadScoreQueryIncludeSpanScore(matchRewritten,
+			q.function, q.decoder, q.includeSpanScore)
+		ans.SetBoost(q.boost)
+		return ans
+	}
+	return q
+}
+
+func (q *PayloadScoreQuery) ExtractTerms(terms *index.TermSet) {
+	q.wrappedQuery.ExtractTerms(terms)
+}
+
+func (q *PayloadScoreQuery) Visit(visitor QueryVisitor) {
+	if sub := visitor.SubVisitor(MUST, q); sub != nil {
+		q.wrappedQuery.Visit(sub)
+	}
+}
+
+func (q *PayloadScoreQuery) spans(context *index.AtomicReaderContext, acceptDocs util.Bits,
+	termContexts map[termKey]*index.TermContext) (Spans, error) {
+	return q.wrappedQuery.spans(context, acceptDocs, termContexts)
+}
+
+func (q *PayloadScoreQuery) CreateWeight(ss *IndexSearcher) (Weight, error) {
+	innerWeight, err := newSpanWeight(q.wrappedQuery, ss)
+	if err != nil {
+		return nil, err
+	}
+	ans := &PayloadSpanWeight{owner: q, innerWeight: innerWeight}
+	ans.WeightImpl = newWeightImpl(ans)
+	return ans, nil
+}
+
+func (q *PayloadScoreQuery) ToString(field string) string {
+	s := fmt.Sprintf("PayloadScoreQuery(%v, function: %v, includeSpanScore: %v)",
+		q.wrappedQuery.ToString(field), reflect.TypeOf(q.function).Name(), q.includeSpanScore)
+	if q.boost != 1 {
+		s = fmt.Sprintf("%v^%v", s, q.boost)
+	}
+	return s
+}
+
+/*
+Expert: the Weight for PayloadScoreQuery, which scores the spans of
+the wrapped query like its own SpanWeight does, before the payload
+score is applied.
+*/
+type PayloadSpanWeight struct {
+	*WeightImpl
+	owner       *PayloadScoreQuery
+	innerWeight *SpanWeight
+}
+
+func (w *PayloadSpanWeight) String() string {
+	return fmt.Sprintf("weight(%v)", w.owner)
+}
+
+func (w *PayloadSpanWeight) ValueForNormalization() float32 {
+	boost := w.owner.boost
+	return w.innerWeight.ValueForNormalization() * boost * boost
+}
+
+func (w *PayloadSpanWeight) Normalize(norm float32, topLevelBoost float32) {
+	w.innerWeight.Normalize(norm, topLevelBoost*w.owner.boost)
+}
+
+func (w *PayloadSpanWeight) IsScoresDocsOutOfOrder() bool {
+	return false
+}
+
+func (w *PayloadSpanWeight) Scorer(context *index.AtomicReaderContext,
+	acceptDocs util.Bits) (Scorer, error) {
+
+	scorer, err := w.payloadScorer(context, acceptDocs)
+	if scorer == nil || err != nil {
+		return nil, err
+	}
+	return scorer, nil
+}
+
+func (w *PayloadSpanWeight) payloadScorer(context *index.AtomicReaderContext,
+	acceptDocs util.Bits) (*payloadSpanScorer, error) {
+
+	inner, err := w.innerWeight.spanScorer(context, acceptDocs)
+	if inner == nil || err != nil {
+		return nil, err
+	}
+	return newPayloadSpanScorer(w, inner), nil
+}
+
+func (w *PayloadSpanWeight) Explain(context *index.AtomicReaderContext, doc int) (Explanation, error) {
+	scorer, err := w.payloadScorer(context, context.Reader().(index.AtomicReader).LiveDocs())
+	if err != nil {
+		return nil, err
+	}
+	if scorer == nil {
+		return newComplexExplanation(false, 0, "No match"), nil
+	}
+	newDoc, err := scorer.Advance(doc)
+	if err != nil {
+		return nil, err
+	}
+	if newDoc != doc {
+		return newComplexExplanation(false, 0, "No match"), nil
+	}
+
+	score, err := scorer.Score() // force freq calculation
+	if err != nil {
+		return nil, err
+	}
+	payloadExpl := scorer.payloadExplanation()
+	if !w.owner.includeSpanScore {
+		return payloadExpl, nil
+	}
+	innerExpl, err := w.innerWeight.Explain(context, doc)
+	if err != nil {
+		return nil, err
+	}
+	ans := newComplexExplanation(true, score, "PayloadSpanQuery, product of:")
+	ans.addDetail(innerExpl)
+	ans.addDetail(payloadExpl)
+	return ans, nil
+}
+
+/*
+Scores the spans of the wrapped query, collecting the payloads of
+all of their terms as a SpanCollector.
+*/
+type payloadSpanScorer struct {
+	*SpanScorer
+	owner        *PayloadScoreQuery
+	payloadsSeen int
+	payloadScore float32
+}
+
+func newPayloadSpanScorer(w *PayloadSpanWeight, inner *SpanScorer) *payloadSpanScorer {
+	ans := &payloadSpanScorer{SpanScorer: inner, owner: w.owner}
+	ans.weight = w
+	ans.spi = ans
+	return ans
+}
+
+func (s *payloadSpanScorer) doStartCurrentDoc() {
+	s.payloadScore = 0
+	s.payloadsSeen = 0
+}
+
+func (s *payloadSpanScorer) doCurrentSpans() error {
+	return s.spans.Collect(s)
+}
+
+func (s *payloadSpanScorer) CollectLeaf(postings DocsAndPositionsEnum, position int, term *index.Term) error {
+	payload, err := postings.Payload()
+	if err != nil {
+		return err
+	}
+	payloadFactor := s.owner.decoder.ComputePayloadFactor(payload)
+	s.payloadScore = s.owner.function.CurrentScore(s.DocId(), s.owner.Field(),
+		s.spans.StartPosition(), s.spans.EndPosition(), s.payloadsSeen, s.payloadScore, payloadFactor)
+	s.payloadsSeen++
+	return nil
+}
+
+func (s *payloadSpanScorer) Reset() {}
+
+func (s *payloadSpanScorer) docPayloadScore() float32 {
+	return s.owner.function.DocScore(s.DocId(), s.owner.Field(), s.payloadsSeen, s.payloadScore)
+}
+
+func (s *payloadSpanScorer) payloadExplanation() Explanation {
+	return s.owner.function.Explain(s.DocId(), s.owner.Field(), s.payloadsSeen, s.payloadScore)
+}
+
+func (s *payloadSpanScorer) scoreCurrentDoc() (float32, error) {
+	if !s.owner.includeSpanScore {
+		return s.docPayloadScore(), nil
+	}
+	spanScore, err := s.SpanScorer.scoreCurrentDoc()
+	if err != nil {
+		return 0, err
+	}
+	return spanScore * s.docPayloadScore(), nil
+}
@@ -0,0 +1,195 @@
+package search
+
+import (
+	"encoding/binary"
+	std "github.com/balzaczyy/golucene/analysis/standard"
+	"github.com/balzaczyy/golucene/core/analysis"
+	"github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"github.com/balzaczyy/golucene/core/index"
+	"github.com/balzaczyy/golucene/core/store"
+	"github.com/balzaczyy/golucene/core/util"
+	"io"
+	"math"
+	"strings"
+	"testing"
+)
+
+type readerSetter func(io.RuneReader) error
+
+func (f readerSetter) SetReader(r io.RuneReader) error { return f(r) }
+
+// Tags the tokens of the StandardAnalyzer with float payloads.
+type payloadAnalyzer struct {
+	*std.StandardAnalyzer
+	payloads map[string]float32
+}
+
+func newPayloadAnalyzer(payloads map[string]float32) *payloadAnalyzer {
+	ans := &payloadAnalyzer{std.NewStandardAnalyzer(), payloads}
+	ans.Spi = ans
+	return ans
+}
+
+func (a *payloadAnalyzer) CreateComponents(fieldName string, reader io.RuneReader) *analysis.TokenStreamComponents {
+	components := a.StandardAnalyzer.CreateComponents(fieldName, reader)
+	return analysis.NewTokenStreamComponents(readerSetter(components.SetReader),
+		newPayloadFilter(components.TokenStream(), a.payloads))
+}
+
+type payloadFilter struct {
+	*analysis.TokenFilter
+	input      analysis.TokenStream
+	payloads   map[string]float32
+	termAtt    tokenattributes.CharTermAttribute
+	payloadAtt tokenattributes.PayloadAttribute
+}
+
+func newPayloadFilter(in analysis.TokenStream, payloads map[string]float32) *payloadFilter {
+	ans := &payloadFilter{
+		TokenFilter: analysis.NewTokenFilter(in),
+		input:       in,
+		payloads:    payloads,
+	}
+	ans.termAtt = ans.Attributes().Add("CharTermAttribute").(tokenattributes.CharTermAttribute)
+	ans.payloadAtt = ans.Attributes().Add("PayloadAttribute").(tokenattributes.PayloadAttribute)
+	return ans
+}
+
+func (f *payloadFilter) IncrementToken() (bool, error) {
+	ok, err := f.input.IncrementToken()
+	if !ok || err != nil {
+		return ok, err
+	}
+	if v, ok := f.payloads[string(f.termAtt.Buffer()[:f.termAtt.Length()])]; ok {
+		payload := make([]byte, 4)
+		binary.BigEndian.PutUint32(payload, math.Float32bits(v))
+		f.payloadAtt.SetPayload(payload)
+	} else {
+		f.payloadAtt.SetPayload(nil)
+	}
+	return true, nil
+}
+
+func TestPayloadScoreQuery(t *testing.T) {
+	dir, err := store.OpenFSDirectory(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	analyzer := newPayloadAnalyzer(map[string]float32{"quick": 2, "fox": 4})
+	w, err := index.NewIndexWriter(dir, index.NewIndexWriterConfig(util.VERSION_LATEST, analyzer))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, title := range []string{
+		"quick brown fox",
+		"quick fox",
+		"fox quick",
+		"slow brown fox",
+	} {
+		addDocument(t, w, title)
+	}
+	// enough positions to fill whole payload blocks
+	long := make([]string, 10)
+	for i := range long {
+		long[i] = strings.Repeat("quick fox ", 15)
+	}
+	addDocument(t, w, long...)
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	r, err := index.OpenDirectoryReader(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	ss := NewIndexSearcher(r)
+
+	search := func(q Query) *freqCollector {
+		c := &freqCollector{freqs: make(map[int]int), scores: make(map[int]float32)}
+		if err := ss.SearchCollector(q, c); err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+	assertScores := func(q Query, expected map[int]float32) {
+		c := search(q)
+		if len(c.scores) != len(expected) {
+			t.Errorf("Expected %v to match %v, but was %v", q, expected, c.scores)
+			return
+		}
+		for doc, score := range expected {
+			if actual, ok := c.scores[doc]; !ok || math.Abs(float64(actual-score)) > 1e-6 {
+				t.Errorf("Expected %v to score %v for doc %v, but was %v", q, score, doc, actual)
+			}
+		}
+	}
+	term := func(text string) *SpanTermQuery {
+		return NewSpanTermQuery(index.NewTerm("title", text))
+	}
+	near := func(slop int, inOrder bool) *SpanNearQuery {
+		return NewSpanNearQuery([]SpanQuery{term("quick"), term("fox")}, slop, inOrder)
+	}
+
+	// span queries match like their term and phrase counterparts
+	tq := search(NewTermQuery(index.NewTerm("title", "fox")))
+	assertScores(term("fox"), tq.scores)
+	c := search(near(0, true))
+	assertEquals(t, 2, len(c.freqs))
+	assertEquals(t, 150, c.freqs[4])
+	c = search(near(1, true))
+	assertEquals(t, 3, len(c.freqs))
+	assertEquals(t, 1, c.freqs[0])
+	c = search(near(0, false))
+	assertEquals(t, 3, len(c.freqs))
+	assertEquals(t, 0, c.freqs[0])
+	assertEquals(t, 1, c.freqs[2])
+	assertEquals(t, 299, c.freqs[4])
+	assertEquals(t, "spanNear([quick, fox], 1, true)", near(1, true).ToString("title"))
+
+	// the spans of a span or are merged by position
+	or := NewSpanOrQuery(term("quick"), term("slow"))
+	assertEquals(t, "spanOr([quick, slow])", or.ToString("title"))
+	c = search(or)
+	assertEquals(t, 5, len(c.freqs))
+	assertEquals(t, 150, c.freqs[4])
+	c = search(NewSpanNearQuery([]SpanQuery{or, term("brown"), term("fox")}, 0, true))
+	assertEquals(t, 2, len(c.freqs))
+	assertEquals(t, 1, c.freqs[3])
+	c = search(NewSpanOrQuery(term("brown"), near(0, true)))
+	assertEquals(t, 4, len(c.freqs))
+	assertEquals(t, 150, c.freqs[4])
+
+	payloadQuery := func(q SpanQuery, f PayloadFunction) Query {
+		return NewPayloadScoreQueryIncludeSpanScore(q, f, FLOAT_DECODER, false)
+	}
+	assertScores(payloadQuery(term("fox"), MaxPayloadFunction{}),
+		map[int]float32{0: 4, 1: 4, 2: 4, 3: 4, 4: 4})
+	// no payloads on the term
+	assertScores(payloadQuery(term("brown"), MaxPayloadFunction{}),
+		map[int]float32{0: 1, 3: 1})
+	assertScores(payloadQuery(near(1, true), MaxPayloadFunction{}),
+		map[int]float32{0: 4, 1: 4, 4: 4})
+	assertScores(payloadQuery(near(1, true), MinPayloadFunction{}),
+		map[int]float32{0: 2, 1: 2, 4: 2})
+	assertScores(payloadQuery(near(0, false), AveragePayloadFunction{}),
+		map[int]float32{1: 3, 2: 3, 4: 3})
+
+	// the payload score is multiplied with the span score by default
+	q := NewPayloadScoreQuery(near(0, true), MaxPayloadFunction{}, FLOAT_DECODER)
+	assertEquals(t, "PayloadScoreQuery(spanNear([quick, fox], 0, true), function: MaxPayloadFunction, includeSpanScore: true)",
+		q.ToString("title"))
+	spanScores := search(near(0, true)).scores
+	for doc, score := range spanScores {
+		spanScores[doc] = 4 * score
+	}
+	assertScores(q, spanScores)
+	for doc, score := range spanScores {
+		exp, err := ss.Explain(q, doc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !exp.IsMatch() || math.Abs(float64(exp.Value()-score)) > 1e-6 {
+			t.Errorf("Expected explanation of doc %v to match with %v, but was %v", doc, score, exp)
+		}
+	}
+}
@@ -0,0 +1,315 @@
+package search
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/balzaczyy/golucene/core/index"
+	"github.com/balzaczyy/golucene/core/util"
+	"reflect"
+	"sort"
+)
+
+// search/PhraseQuery.java
+
+/*
+A Query that matches documents containing a particular sequence of
+terms. A PhraseQuery is built by QueryParser for input like "new
+york".
+
+This query may be combined with other terms or queries with a
+BooleanQuery.
+*/
+type PhraseQuery struct {
+	*AbstractQuery
+	field     string
+	terms     []*index.Term
+	positions []int
+	maxPos    int
+	slop      int
+}
+
+// Constructs an empty phrase query.
+func NewPhraseQuery() *PhraseQuery {
+	ans := new(PhraseQuery)
+	ans.AbstractQuery = NewAbstractQuery(ans)
+	return ans
+}
+
+/*
+Sets the number of other words permitted between words in query
+phrase. If zero, then this is an exact phrase search. For larger
+values this works like a WITHIN or NEAR operator.
+
+The slop is in fact an edit-distance, where the units correspond to
+moves of terms in the query phrase out of position. For example, to
+switch the order of two words requires two moves (the first move
+places the words atop one another), so to permit re-orderings of
+phrases, the slop must be at least two.
+
+More exact matches are scored higher than sloppier matches, thus
+search results are sorted by exactness.
+
+The slop is zero by default, requiring exact matches.
+*/
+func (q *PhraseQuery) SetSlop(s int) {
+	assert2(s >= 0, "slop value cannot be negative")
+	q.slop = s
+}
+
+// Returns the slop.
+func (q *PhraseQuery) Slop() int {
+	return q.slop
+}
+
+/*
+Adds a term to the end of the query phrase. The relative position of
+the term is the one immediately after the last term added.
+*/
+func (q *PhraseQuery) Add(term *index.Term) {
+	position := 0
+	if len(q.positions) > 0 {
+		position = q.positions[len(q.positions)-1] + 1
+	}
+	q.AddAt(term, position)
+}
+
+/*
+Adds a term to the end of the query phrase. The relative position of
+the term within the phrase is specified explicitly. This allows e.g.
+phrases with more than one term at the same position or phrases with
+gaps (e.g. in connection with stopwords).
+*/
+func (q *PhraseQuery) AddAt(term *index.Term, position int) {
+	if len(q.terms) == 0 {
+		q.field = term.Field
+	} else {
+		assert2(term.Field == q.field,
+			"All phrase terms must be in the same field: %v", term)
+	}
+
+	q.terms = append(q.terms, term)
+	q.positions = append(q.positions, position)
+	if position > q.maxPos {
+		q.maxPos = position
+	}
+}
+
+// Returns the set of terms in this phrase.
+func (q *PhraseQuery) Terms() []*index.Term {
+	return q.terms
+}
+
+// Returns the relative positions of terms in this phrase.
+func (q *PhraseQuery) Positions() []int {
+	return q.positions
+}
+
+func (q *PhraseQuery) Rewrite(reader index.IndexReader) Query {
+	if len(q.terms) == 0 {
+		bq := NewBooleanQuery()
+		bq.SetBoost(q.boost)
+		return bq
+	} else if len(q.terms) == 1 {
+		tq := NewTermQuery(q.terms[0])
+		tq.SetBoost(q.boost)
+		return tq
+	}
+	return q
+}
+
+func (q *PhraseQuery) CreateWeight(ss *IndexSearcher) (Weight, error) {
+	return newPhraseWeight(q, ss)
+}
+
+func (q *PhraseQuery) ExtractTerms(terms *index.TermSet) {
+	for _, t := range q.terms {
+		terms.Add(t)
+	}
+}
+
+func (q *PhraseQuery) Visit(visitor QueryVisitor) {
+	if len(q.terms) == 0 {
+		visitor.VisitLeaf(q)
+		return
+	}
+	if !visitor.AcceptField(q.field) {
+		return
+	}
+	// a phrase only matches if all its terms do
+	if sub := visitor.SubVisitor(MUST, q); sub != nil {
+		sub.ConsumeTerms(q, q.terms...)
+	}
+}
+
+func (q *PhraseQuery) ToString(f string) string {
+	var buf bytes.Buffer
+	if q.field != "" && q.field != f {
+		buf.WriteString(q.field)
+		buf.WriteRune(':')
+	}
+
+	buf.WriteRune('"')
+	pieces := make([]string, q.maxPos+1)
+	for i, t := range q.terms {
+		pos := q.positions[i]
+		if s := pieces[pos]; s != "" {
+			pieces[pos] = s + "|" + string(t.Bytes)
+		} else {
+			pieces[pos] = string(t.Bytes)
+		}
+	}
+	for i, s := range pieces {
+		if i > 0 {
+			buf.WriteRune(' ')
+		}
+		if s == "" {
+			buf.WriteRune('?')
+		} else {
+			buf.WriteString(s)
+		}
+	}
+	buf.WriteRune('"')
+
+	if q.slop != 0 {
+		fmt.Fprintf(&buf, "~%v", q.slop)
+	}
+	if q.boost != 1 {
+		fmt.Fprintf(&buf, "^%v", q.boost)
+	}
+	return buf.String()
+}
+
+type PhraseWeight struct {
+	*WeightImpl
+	*PhraseQuery
+	similarity Similarity
+	stats      SimWeight
+	states     []*index.TermContext
+}
+
+func newPhraseWeight(owner *PhraseQuery, ss *IndexSearcher) (*PhraseWeight, error) {
+	ctx := ss.TopReaderContext()
+	states := make([]*index.TermContext, len(owner.terms))
+	termStats := make([]TermStatistics, len(owner.terms))
+	for i, term := range owner.terms {
+		state, err := index.NewTermContextFromTerm(ctx, term)
+		if err != nil {
+			return nil, err
+		}
+		states[i] = state
+		termStats[i] = ss.spi.TermStatistics(term, state)
+	}
+	sim := ss.similarity
+	ans := &PhraseWeight{
+		PhraseQuery: owner,
+		similarity:  sim,
+		stats: sim.computeWeight(
+			owner.boost,
+			ss.spi.CollectionStatistics(owner.field),
+			termStats...),
+		states: states,
+	}
+	ans.WeightImpl = newWeightImpl(ans)
+	return ans, nil
+}
+
+func (w *PhraseWeight) String() string {
+	return fmt.Sprintf("weight(%v)", w.PhraseQuery)
+}
+
+func (w *PhraseWeight) ValueForNormalization() float32 {
+	return w.stats.ValueForNormalization()
+}
+
+func (w *PhraseWeight) Normalize(norm float32, topLevelBoost float32) {
+	w.stats.Normalize(norm, topLevelBoost)
+}
+
+func (w *PhraseWeight) IsScoresDocsOutOfOrder() bool {
+	return false
+}
+
+func (w *PhraseWeight) Scorer(context *index.AtomicReaderContext,
+	acceptDocs util.Bits) (Scorer, error) {
+
+	assert(len(w.terms) > 0)
+	terms := context.Reader().(index.AtomicReader).Terms(w.field)
+	if terms == nil {
+		return nil, nil
+	}
+
+	// reuse a single TermsEnum below
+	te := terms.Iterator(nil)
+	postings := make([]*postingsAndFreq, len(w.terms))
+	for i, term := range w.terms {
+		state := w.states[i].State(context.Ord)
+		if state == nil { // term doesn't exist in this segment
+			return nil, nil
+		}
+		if err := te.SeekExactFromLast(term.Bytes, state); err != nil {
+			return nil, err
+		}
+		docs, err := te.DocsAndPositionsByFlags(acceptDocs, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		// positions are required but not indexed for this field
+		assert2(docs != nil,
+			"field \"%v\" was indexed without position data; cannot run PhraseQuery (term=%v)",
+			term.Field, string(term.Bytes))
+		docFreq, err := te.DocFreq()
+		if err != nil {
+			return nil, err
+		}
+		postings[i] = &postingsAndFreq{docs, docFreq, w.positions[i], term}
+	}
+
+	docScorer, err := w.similarity.simScorer(w.stats, context)
+	if err != nil {
+		return nil, err
+	}
+	var matcher PhraseMatcher
+	if w.slop == 0 {
+		matcher = newExactPhraseMatcher(postings)
+	} else {
+		matcher = newSloppyPhraseMatcher(postings, w.slop)
+	}
+	// sort by increasing docFreq order, so that the rarest term leads
+	// the conjunction
+	sorted := make([]*postingsAndFreq, len(postings))
+	copy(sorted, postings)
+	sort.Stable(postingsByDocFreq(sorted))
+	return newPhraseScorer(w, sorted, matcher, docScorer), nil
+}
+
+func (w *PhraseWeight) Explain(ctx *index.AtomicReaderContext, doc int) (Explanation, error) {
+	scorer, err := w.Scorer(ctx, ctx.Reader().(index.AtomicReader).LiveDocs())
+	if err != nil {
+		return nil, err
+	}
+	if scorer != nil {
+		newDoc, err := scorer.Advance(doc)
+		if err != nil {
+			return nil, err
+		}
+		if newDoc == doc {
+			freq, err := scorer.(*PhraseScorer).sloppyFreq()
+			if err != nil {
+				return nil, err
+			}
+			docScorer, err := w.similarity.simScorer(w.stats, ctx)
+			if err != nil {
+				return nil, err
+			}
+			scoreExplanation := docScorer.explain(doc,
+				newExplanation(freq, fmt.Sprintf("phraseFreq=%v", freq)))
+			ans := newComplexExplanation(true,
+				scoreExplanation.(*ExplanationImpl).value,
+				fmt.Sprintf("weight(%v in %v) [%v], result of:",
+					w.PhraseQuery, doc, reflect.TypeOf(w.similarity)))
+			ans.details = []Explanation{scoreExplanation}
+			return ans, nil
+		}
+	}
+	return newComplexExplanation(false, 0, "no matching term"), nil
+}
@@ -0,0 +1,130 @@
+package search
+
+import (
+	std "github.com/balzaczyy/golucene/analysis/standard"
+	"github.com/balzaczyy/golucene/core/index"
+	"github.com/balzaczyy/golucene/core/store"
+	"github.com/balzaczyy/golucene/core/util"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestPhraseQuery(t *testing.T) {
+	dir, err := store.OpenFSDirectory(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := index.NewIndexWriter(dir, index.NewIndexWriterConfig(util.VERSION_LATEST, std.NewStandardAnalyzer()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, title := range []string{
+		"quick brown fox",
+		"brown quick fox",
+		"quick red brown fox",
+		"x y x",
+		"x x y",
+		"y x z x",
+	} {
+		addDocument(t, w, title)
+	}
+	// several blocks of positions, over values of 40 positions each
+	long := make([]string, 10)
+	for i := range long {
+		long[i] = strings.Repeat("p q ", 20)
+	}
+	addDocument(t, w, long...)
+	// enough docs to span several postings blocks
+	const numFillers = 300
+	for i := 0; i < numFillers; i++ {
+		if i%3 == 0 {
+			addDocument(t, w, "filler quick brown")
+		} else {
+			addDocument(t, w, "filler quick green")
+		}
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	r, err := index.OpenDirectoryReader(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	assertEquals(t, 1, len(r.Leaves()))
+	ss := NewIndexSearcher(r)
+
+	search := func(slop int, words ...string) *freqCollector {
+		q := NewPhraseQuery()
+		for _, word := range words {
+			q.Add(index.NewTerm("title", word))
+		}
+		q.SetSlop(slop)
+		c := &freqCollector{freqs: make(map[int]int), scores: make(map[int]float32)}
+		if err := ss.SearchCollector(q, c); err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+	fillers := func(c *freqCollector) (n int) {
+		for doc := range c.freqs {
+			if doc >= 7 {
+				n++
+			}
+		}
+		return
+	}
+	for _, v := range []struct {
+		slop  int
+		words []string
+		freqs map[int]int
+	}{
+		{0, []string{"quick", "brown"}, map[int]int{0: 1}},
+		{1, []string{"quick", "brown"}, map[int]int{0: 1, 2: 1}},
+		{2, []string{"quick", "brown"}, map[int]int{0: 1, 1: 1, 2: 1}},
+		{0, []string{"brown", "fox"}, map[int]int{0: 1, 2: 1}},
+		{0, []string{"x", "y", "x"}, map[int]int{3: 1}},
+		{1, []string{"x", "y", "x"}, map[int]int{3: 1}},
+		{2, []string{"x", "y", "x"}, map[int]int{3: 1, 4: 1, 5: 1}},
+		{0, []string{"p", "q"}, map[int]int{6: 200}},
+		{0, []string{"q", "p"}, map[int]int{6: 199}},
+		{0, []string{"p", "q", "p", "q"}, map[int]int{6: 199}},
+		{0, []string{"fox", "quick"}, map[int]int{}},
+	} {
+		c := search(v.slop, v.words...)
+		if n := fillers(c); n > 0 {
+			if v.words[0] != "quick" || v.words[1] != "brown" || n != numFillers/3 {
+				t.Errorf("Unexpected %v filler hits for %v~%v", n, v.words, v.slop)
+			}
+		}
+		for doc, freq := range v.freqs {
+			if c.freqs[doc] != freq {
+				t.Errorf("Expected doc %v to match %v~%v %v times, but was %v",
+					doc, v.words, v.slop, freq, c.freqs[doc])
+			}
+		}
+		if len(c.freqs)-fillers(c) != len(v.freqs) {
+			t.Errorf("Expected %v~%v to match %v, but was %v", v.words, v.slop, v.freqs, c.freqs)
+		}
+	}
+
+	// exact matches score the same regardless of slop, closer
+	// matches score higher
+	exact, sloppy := search(0, "quick", "brown"), search(2, "quick", "brown")
+	assertEquals(t, exact.scores[0], sloppy.scores[0])
+	if sloppy.scores[2] >= sloppy.scores[0] || sloppy.scores[1] >= sloppy.scores[2] {
+		t.Errorf("Expected scores to decrease with distance, but was %v", sloppy.scores)
+	}
+
+	q := NewPhraseQuery()
+	q.Add(index.NewTerm("title", "quick"))
+	q.Add(index.NewTerm("title", "brown"))
+	exp, err := ss.Explain(q, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !exp.IsMatch() || math.Abs(float64(exp.Value()-exact.scores[0])) > 1e-6 {
+		t.Errorf("Expected explanation of score %v, but was %v", exact.scores[0], exp)
+	}
+}
@@ -0,0 +1,203 @@
+package search
+
+import (
+	"fmt"
+	"github.com/balzaczyy/golucene/core/index"
+	. "github.com/balzaczyy/golucene/core/index/model"
+	. "github.com/balzaczyy/golucene/core/search/model"
+)
+
+type postingsAndFreq struct {
+	postings DocsAndPositionsEnum
+	docFreq  int
+	position int
+	term     *index.Term
+}
+
+type postingsByDocFreq []*postingsAndFreq
+
+func (s postingsByDocFreq) Len() int           { return len(s) }
+func (s postingsByDocFreq) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s postingsByDocFreq) Less(i, j int) bool { return s[i].docFreq < s[j].docFreq }
+
+// search/PhraseMatcher.java
+
+/*
+Base interface for phrase matching. Phrase matching is performed in
+two phases: the PhraseScorer first finds documents that contain all
+the terms of the phrase, then the matcher checks the positions of
+each of these documents to find matches.
+*/
+type PhraseMatcher interface {
+	// Called after the postings have been positioned on a new doc.
+	reset() error
+	/*
+		Finds the next match of the phrase within the current doc, and
+		returns false once the doc has no more matches.
+	*/
+	nextMatch() (bool, error)
+	// The weight of the current match, summed to compute the frequency.
+	sloppyWeight(docScorer SimScorer) float32
+}
+
+// search/PhraseScorer.java
+
+/*
+Scorer for phrase queries. Iterates over the conjunction of the
+phrase terms, and only returns a doc once its matcher has found a
+first match. Remaining matches are only enumerated if the frequency
+is required, e.g. for scoring.
+*/
+type PhraseScorer struct {
+	*abstractScorer
+	postings  []*postingsAndFreq // sorted by docFreq, the lead first
+	matcher   PhraseMatcher
+	docScorer SimScorer
+	doc       int
+	freq      float32
+	numMatch  int
+	exhausted bool // whether all matches of current doc were counted
+}
+
+func newPhraseScorer(w Weight, postings []*postingsAndFreq,
+	matcher PhraseMatcher, docScorer SimScorer) *PhraseScorer {
+
+	ans := &PhraseScorer{
+		postings:  postings,
+		matcher:   matcher,
+		docScorer: docScorer,
+		doc:       -1,
+	}
+	ans.abstractScorer = newScorer(ans, w)
+	return ans
+}
+
+func (s *PhraseScorer) DocId() int {
+	return s.doc
+}
+
+func (s *PhraseScorer) NextDoc() (int, error) {
+	doc, err := s.postings[0].postings.NextDoc()
+	if err != nil {
+		return 0, err
+	}
+	return s.doNext(doc)
+}
+
+func (s *PhraseScorer) Advance(target int) (int, error) {
+	doc, err := s.postings[0].postings.Advance(target)
+	if err != nil {
+		return 0, err
+	}
+	return s.doNext(doc)
+}
+
+/*
+Leap-frogs from the given doc of the lead postings to the next doc
+that contains all terms and at least one match of the phrase.
+*/
+func (s *PhraseScorer) doNext(doc int) (int, error) {
+	lead := s.postings[0].postings
+	for doc != NO_MORE_DOCS {
+		next, err := s.advanceOthers(doc)
+		if err != nil {
+			return 0, err
+		}
+		if next != doc {
+			if doc, err = lead.Advance(next); err != nil {
+				return 0, err
+			}
+			continue
+		}
+
+		if err = s.matcher.reset(); err != nil {
+			return 0, err
+		}
+		ok, err := s.matcher.nextMatch()
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			s.doc = doc
+			s.freq = s.matcher.sloppyWeight(s.docScorer)
+			s.numMatch = 1
+			s.exhausted = false
+			return doc, nil
+		}
+		if doc, err = lead.NextDoc(); err != nil {
+			return 0, err
+		}
+	}
+	s.doc = NO_MORE_DOCS
+	return s.doc, nil
+}
+
+/*
+Positions the non-lead postings on the given doc, and returns it if
+they all contain it, or the first doc beyond it that one of them is
+on otherwise.
+*/
+func (s *PhraseScorer) advanceOthers(doc int) (int, error) {
+	for _, p := range s.postings[1:] {
+		other := p.postings.DocId()
+		if other < doc {
+			var err error
+			if other, err = p.postings.Advance(doc); err != nil {
+				return 0, err
+			}
+		}
+		if other > doc {
+			return other, nil
+		}
+	}
+	return doc, nil
+}
+
+// Counts the remaining matches of current doc.
+func (s *PhraseScorer) countMatches() error {
+	if s.exhausted {
+		return nil
+	}
+	for {
+		ok, err := s.matcher.nextMatch()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			break
+		}
+		s.freq += s.matcher.sloppyWeight(s.docScorer)
+		s.numMatch++
+	}
+	s.exhausted = true
+	return nil
+}
+
+// Returns the number of matches of the phrase in current doc.
+func (s *PhraseScorer) Freq() (int, error) {
+	if err := s.countMatches(); err != nil {
+		return 0, err
+	}
+	return s.numMatch, nil
+}
+
+// Returns the sum of sloppy weights of all matches in current doc.
+func (s *PhraseScorer) sloppyFreq() (float32, error) {
+	if err := s.countMatches(); err != nil {
+		return 0, err
+	}
+	return s.freq, nil
+}
+
+func (s *PhraseScorer) Score() (float32, error) {
+	assert(s.doc != NO_MORE_DOCS)
+	freq, err := s.sloppyFreq()
+	if err != nil {
+		return 0, err
+	}
+	return s.docScorer.Score(s.doc, freq), nil
+}
+
+func (s *PhraseScorer) String() string {
+	return fmt.Sprintf("scorer(%v)", s.weight)
+}
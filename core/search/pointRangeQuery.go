@@ -0,0 +1,93 @@
+package search
+
+import (
+	"fmt"
+	docu "github.com/balzaczyy/golucene/core/document"
+	"github.com/balzaczyy/golucene/core/index"
+)
+
+// search/PointRangeQuery.java
+
+/*
+Abstract class for range queries against single or multidimensional
+points such as IntPoint. A document matches if any of its points is
+within the range, in all dimensions; both bounds are inclusive.
+
+The range must have the type and number of dimensions of the indexed
+points. All matches get the same score, the query's boost. Like the
+RangeFieldQuery, the points are read back from the stored fields of
+every live document of the segment.
+*/
+type PointRangeQuery struct {
+	*AbstractQuery
+	field  string
+	ranges []byte // encoded like the range fields
+}
+
+func newPointRangeQuery(field string, ranges []byte) *PointRangeQuery {
+	assert2(field != "", "field must not be empty")
+	ans := &PointRangeQuery{field: field, ranges: ranges}
+	ans.AbstractQuery = NewAbstractQuery(ans)
+	return ans
+}
+
+// Create a range query for matching IntPoint fields of the given field.
+func NewIntPointRangeQuery(field string, lower, upper []int32) *PointRangeQuery {
+	return newPointRangeQuery(field, docu.EncodeIntRanges(lower, upper))
+}
+
+// Create a range query for matching LongPoint fields of the given field.
+func NewLongPointRangeQuery(field string, lower, upper []int64) *PointRangeQuery {
+	return newPointRangeQuery(field, docu.EncodeLongRanges(lower, upper))
+}
+
+// Create a range query for matching DoublePoint fields of the given field.
+func NewDoublePointRangeQuery(field string, lower, upper []float64) *PointRangeQuery {
+	return newPointRangeQuery(field, docu.EncodeDoubleRanges(lower, upper))
+}
+
+// Returns the field of the points.
+func (q *PointRangeQuery) Field() string {
+	return q.field
+}
+
+func (q *PointRangeQuery) CreateWeight(ss *IndexSearcher) (Weight, error) {
+	return newVerifyingWeight(q), nil
+}
+
+func (q *PointRangeQuery) ExtractTerms(terms *index.TermSet) {}
+
+func (q *PointRangeQuery) Visit(visitor QueryVisitor) {
+	if visitor.AcceptField(q.field) {
+		visitor.VisitLeaf(q)
+	}
+}
+
+func (q *PointRangeQuery) ToString(field string) string {
+	s := docu.PointRangesString(q.ranges)
+	if q.field != field {
+		s = fmt.Sprintf("%v:%v", q.field, s)
+	}
+	if q.boost != 1 {
+		s = fmt.Sprintf("%v^%v", s, q.boost)
+	}
+	return s
+}
+
+// Returns true if any point of doc, relative to reader, is within the
+// query range.
+func (q *PointRangeQuery) matches(reader index.IndexReader, doc int) (bool, error) {
+	stored, err := reader.Document(doc)
+	if err != nil {
+		return false, err
+	}
+	for _, f := range stored.Fields() {
+		if f.Name() != q.field {
+			continue
+		}
+		if _, within, _ := docu.RelateRanges(f.BinaryValue(), q.ranges); within {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,82 @@
+package search
+
+import (
+	"fmt"
+	std "github.com/balzaczyy/golucene/analysis/standard"
+	docu "github.com/balzaczyy/golucene/core/document"
+	"github.com/balzaczyy/golucene/core/index"
+	"github.com/balzaczyy/golucene/core/store"
+	"github.com/balzaczyy/golucene/core/util"
+	"math"
+	"sort"
+	"testing"
+)
+
+func TestPointRangeQueries(t *testing.T) {
+	dir, err := store.OpenFSDirectory(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := index.NewIndexWriter(dir, index.NewIndexWriterConfig(util.VERSION_LATEST,
+		std.NewStandardAnalyzer()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, product := range []struct {
+		title string
+		price float64
+		sizes []int32
+	}{
+		{"a", 9.5, []int32{1}}, {"b", 10, []int32{2, 3}}, {"c", 19.99, nil}, {"d", 25, []int32{4}},
+	} {
+		doc := docu.NewDocument()
+		doc.Add(docu.NewTextFieldFromString("title", product.title, docu.STORE_YES))
+		doc.Add(docu.NewDoublePoint("price", product.price))
+		for _, size := range product.sizes {
+			doc.Add(docu.NewIntPoint("size", size))
+		}
+		doc.Add(docu.NewLongPoint("dims", int64(product.price), int64(len(product.sizes))))
+		if err = w.AddDocument(doc.Fields()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	r, err := index.OpenDirectoryReader(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	ss := NewIndexSearcher(r)
+	titles := func(q Query) string {
+		hits, err := ss.SearchTop(q, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ans []string
+		for _, hit := range hits.ScoreDocs {
+			doc, err := r.Document(hit.Doc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ans = append(ans, doc.Get("title"))
+		}
+		sort.Strings(ans)
+		return fmt.Sprint(ans)
+	}
+
+	q := NewDoublePointRangeQuery("price", []float64{10}, []float64{20})
+	assertEquals(t, "price:[10 TO 20]", q.String())
+	assertEquals(t, "[b c]", titles(q))
+	assertEquals(t, "[a b c d]", titles(NewDoublePointRangeQuery("price",
+		[]float64{math.Inf(-1)}, []float64{math.Inf(1)})))
+	// any of the values matches
+	assertEquals(t, "[b d]", titles(NewIntPointRangeQuery("size", []int32{3}, []int32{10})))
+	// of another type
+	assertEquals(t, "[]", titles(NewLongPointRangeQuery("size", []int64{0}, []int64{10})))
+
+	q = NewLongPointRangeQuery("dims", []int64{0, 1}, []int64{20, 2})
+	assertEquals(t, "dims:[0 TO 20],[1 TO 2]", q.String())
+	assertEquals(t, "[a b]", titles(q))
+}
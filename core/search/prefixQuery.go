@@ -0,0 +1,45 @@
+package search
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/balzaczyy/golucene/core/index"
+)
+
+// search/PrefixQuery.java
+
+/* A Query that matches documents containing terms with a specified prefix. */
+type PrefixQuery struct {
+	*MultiTermQuery
+	prefix *index.Term
+}
+
+/* Constructs a query for terms starting with prefix. */
+func NewPrefixQuery(prefix *index.Term) *PrefixQuery {
+	ans := &PrefixQuery{prefix: prefix}
+	ans.MultiTermQuery = NewMultiTermQuery(ans, prefix.Field)
+	return ans
+}
+
+// Returns the prefix of this query.
+func (q *PrefixQuery) Prefix() *index.Term {
+	return q.prefix
+}
+
+func (q *PrefixQuery) AcceptTerm(term []byte) bool {
+	return bytes.HasPrefix(term, q.prefix.Bytes)
+}
+
+func (q *PrefixQuery) ToString(field string) string {
+	var buf bytes.Buffer
+	if q.field != field {
+		buf.WriteString(q.field)
+		buf.WriteRune(':')
+	}
+	buf.Write(q.prefix.Bytes)
+	buf.WriteRune('*')
+	if q.boost != 1 {
+		fmt.Fprintf(&buf, "^%v", q.boost)
+	}
+	return buf.String()
+}
@@ -16,6 +16,11 @@ type Query interface {
 	QuerySPI
 	CreateWeight(ss *IndexSearcher) (w Weight, err error)
 	Rewrite(r index.IndexReader) Query
+	// Expert: adds all terms occurring in this query to the terms set.
+	// Only works if this query is in its rewritten form.
+	ExtractTerms(terms *index.TermSet)
+	// Recurses through the query tree with a visitor.
+	Visit(visitor QueryVisitor)
 }
 
 type QuerySPI interface {
@@ -48,3 +53,12 @@ func (q *AbstractQuery) CreateWeight(ss *IndexSearcher) (w Weight, err error) {
 func (q *AbstractQuery) Rewrite(r index.IndexReader) Query {
 	return q.value
 }
+
+// By default, a query is visited as an opaque leaf.
+func (q *AbstractQuery) Visit(visitor QueryVisitor) {
+	visitor.VisitLeaf(q.value)
+}
+
+func (q *AbstractQuery) ExtractTerms(terms *index.TermSet) {
+	panic(fmt.Sprintf("Query %v does not implement extractTerms", q))
+}
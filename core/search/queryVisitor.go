@@ -0,0 +1,120 @@
+package search
+
+import (
+	"github.com/balzaczyy/golucene/core/index"
+	"github.com/balzaczyy/golucene/core/util/automaton"
+)
+
+// search/QueryVisitor.java
+
+/*
+Allows recursion through a query tree, e.g. to collect the terms of
+a query for highlighting, security filtering or presearching, without
+switching on the type of every query.
+
+Each query calls back the visitor with what it matches on: its terms,
+an automaton matching its terms, or, if neither applies, itself as an
+opaque leaf. Compound queries call SubVisitor() once per Occur and
+pass the returned visitor to the clauses of that occur.
+
+Implementations should embed QueryVisitorImpl, which provides no-op
+defaults, and override the methods of interest.
+*/
+type QueryVisitor interface {
+	// Called by leaf queries that match on specific terms.
+	ConsumeTerms(query Query, terms ...*index.Term)
+	/*
+		Called by leaf queries that match on a class of terms, e.g. a
+		wildcard or fuzzy query. The automaton is only built if the
+		visitor calls the supplier.
+	*/
+	ConsumeTermsMatching(query Query, field string, automaton func() *automaton.Automaton)
+	// Called by leaf queries that do not match on terms.
+	VisitLeaf(query Query)
+	/*
+		Whether or not terms from this field are of interest to the
+		visitor. Implement this to avoid collecting terms from heavy
+		queries on fields the visitor doesn't care about.
+	*/
+	AcceptField(field string) bool
+	/*
+		Pulls a visitor instance for visiting the sub-queries of parent
+		with the given Occur. Return nil to skip them entirely.
+	*/
+	SubVisitor(occur Occur, parent Query) QueryVisitor
+}
+
+type QueryVisitorImpl struct {
+	self QueryVisitor
+}
+
+func NewQueryVisitorImpl(self QueryVisitor) *QueryVisitorImpl {
+	return &QueryVisitorImpl{self}
+}
+
+func (v *QueryVisitorImpl) ConsumeTerms(query Query, terms ...*index.Term) {}
+
+func (v *QueryVisitorImpl) ConsumeTermsMatching(query Query, field string,
+	automaton func() *automaton.Automaton) {
+	v.self.VisitLeaf(query) // default implementation for backward compatibility
+}
+
+func (v *QueryVisitorImpl) VisitLeaf(query Query) {}
+
+func (v *QueryVisitorImpl) AcceptField(field string) bool { return true }
+
+/*
+By default, returns the visitor itself for required and optional
+clauses, and nil for prohibited ones, as their terms do not match
+the documents the parent matches.
+*/
+func (v *QueryVisitorImpl) SubVisitor(occur Occur, parent Query) QueryVisitor {
+	if occur == MUST_NOT {
+		return nil
+	}
+	return v.self
+}
+
+/*
+Visits the sub-queries of parent, grouped by Occur, in order of
+first appearance of each Occur.
+*/
+func visitClauses(visitor QueryVisitor, parent Query, clauses []*BooleanClause) {
+	var occurs []Occur
+	byOccur := make(map[Occur][]Query)
+	for _, c := range clauses {
+		if _, ok := byOccur[c.occur]; !ok {
+			occurs = append(occurs, c.occur)
+		}
+		byOccur[c.occur] = append(byOccur[c.occur], c.query)
+	}
+	for _, occur := range occurs {
+		if sub := visitor.SubVisitor(occur, parent); sub != nil {
+			for _, q := range byOccur[occur] {
+				q.Visit(sub)
+			}
+		}
+	}
+}
+
+type termCollector struct {
+	*QueryVisitorImpl
+	terms *index.TermSet
+}
+
+/*
+Returns a QueryVisitor that collects the terms of required and
+optional clauses into the given set, i.e. the terms ExtractTerms()
+would add.
+*/
+func NewTermCollector(terms *index.TermSet) QueryVisitor {
+	ans := &termCollector{terms: terms}
+	ans.QueryVisitorImpl = NewQueryVisitorImpl(ans)
+	return ans
+}
+
+func (v *termCollector) ConsumeTerms(query Query, terms ...*index.Term) {
+	for _, t := range terms {
+		v.terms.Add(t)
+	}
+}
@@ -0,0 +1,71 @@
+package search
+
+import (
+	"github.com/balzaczyy/golucene/core/index"
+	"strings"
+	"testing"
+)
+
+type leafCountingVisitor struct {
+	*QueryVisitorImpl
+	terms  map[Occur][]string
+	leaves *int
+	occur  Occur
+}
+
+func (v *leafCountingVisitor) ConsumeTerms(query Query, terms ...*index.Term) {
+	for _, t := range terms {
+		v.terms[v.occur] = append(v.terms[v.occur], t.String())
+	}
+}
+
+func (v *leafCountingVisitor) VisitLeaf(query Query) {
+	*v.leaves++
+}
+
+func (v *leafCountingVisitor) SubVisitor(occur Occur, parent Query) QueryVisitor {
+	ans := &leafCountingVisitor{terms: v.terms, leaves: v.leaves, occur: occur}
+	ans.QueryVisitorImpl = NewQueryVisitorImpl(ans)
+	return ans
+}
+
+func TestQueryVisitor(t *testing.T) {
+	phrase := NewPhraseQuery()
+	phrase.Add(index.NewTerm("f", "new"))
+	phrase.Add(index.NewTerm("f", "york"))
+	nested := NewBooleanQuery()
+	nested.Add(NewTermQuery(index.NewTerm("f", "c")), SHOULD)
+	nested.Add(NewMatchAllDocsQuery(), SHOULD)
+	bq := NewBooleanQuery()
+	bq.Add(NewTermQuery(index.NewTerm("f", "a")), MUST)
+	bq.Add(NewTermQuery(index.NewTerm("f", "b")), MUST_NOT)
+	bq.Add(nested, MUST)
+	bq.Add(phrase, SHOULD)
+
+	terms := index.NewTermSet()
+	bq.Visit(NewTermCollector(terms))
+	for _, text := range []string{"a", "c", "new", "york"} {
+		if !terms.Contains(index.NewTerm("f", text)) {
+			t.Errorf("Expected term f:%v to be collected", text)
+		}
+	}
+	if terms.Contains(index.NewTerm("f", "b")) || terms.Size() != 4 {
+		t.Errorf("Expected prohibited terms to be skipped, but was %v", terms.Size())
+	}
+
+	v := &leafCountingVisitor{terms: make(map[Occur][]string), leaves: new(int)}
+	v.QueryVisitorImpl = NewQueryVisitorImpl(v)
+	bq.Visit(v)
+	if got := strings.Join(v.terms[MUST], " "); got != "f:a f:new f:york" {
+		t.Errorf("Expected required terms [f:a f:new f:york], but was [%v]", got)
+	}
+	if got := strings.Join(v.terms[SHOULD], " "); got != "f:c" {
+		t.Errorf("Expected optional terms [f:c], but was [%v]", got)
+	}
+	if got := strings.Join(v.terms[MUST_NOT], " "); got != "f:b" {
+		t.Errorf("Expected prohibited terms [f:b], but was [%v]", got)
+	}
+	if *v.leaves != 1 {
+		t.Errorf("Expected MatchAllDocsQuery to be visited as leaf, but was %v", *v.leaves)
+	}
+}
@@ -0,0 +1,152 @@
+package search
+
+import (
+	"fmt"
+	docu "github.com/balzaczyy/golucene/core/document"
+	"github.com/balzaczyy/golucene/core/index"
+)
+
+// document/RangeFieldQuery.java#QueryType
+
+// Defines how the indexed ranges must relate to the range of a
+// RangeFieldQuery.
+type RangeQueryType int
+
+const (
+	// Use this for intersects queries.
+	RANGE_QUERY_INTERSECTS RangeQueryType = iota
+	// Use this for within queries.
+	RANGE_QUERY_WITHIN
+	// Use this for contains queries.
+	RANGE_QUERY_CONTAINS
+	// Use this for crosses queries.
+	RANGE_QUERY_CROSSES
+)
+
+func (t RangeQueryType) String() string {
+	switch t {
+	case RANGE_QUERY_INTERSECTS:
+		return "INTERSECTS"
+	case RANGE_QUERY_WITHIN:
+		return "WITHIN"
+	case RANGE_QUERY_CONTAINS:
+		return "CONTAINS"
+	case RANGE_QUERY_CROSSES:
+		return "CROSSES"
+	}
+	panic(fmt.Sprintf("invalid range query type: %v", int(t)))
+}
+
+// document/RangeFieldQuery.java
+
+/*
+Query class for searching the ranges of IntRange, LongRange and
+DoubleRange fields. A document matches if any of its ranges relates
+to the query range, in all dimensions, as defined by the query type:
+
+  - RANGE_QUERY_INTERSECTS: the ranges overlap;
+  - RANGE_QUERY_WITHIN: the document range is within the query range;
+  - RANGE_QUERY_CONTAINS: the document range contains the query range;
+  - RANGE_QUERY_CROSSES: the ranges overlap, but the document range is
+    not within the query range.
+
+The query range must have the type and number of dimensions of the
+indexed ranges. All matches get the same score, the query's boost.
+Like the LatLonPoint queries, the ranges are read back from the
+stored fields of every live document of the segment.
+*/
+type RangeFieldQuery struct {
+	*AbstractQuery
+	field     string
+	queryType RangeQueryType
+	ranges    []byte // encoded like the range fields
+}
+
+func newRangeFieldQuery(field string, queryType RangeQueryType, ranges []byte) *RangeFieldQuery {
+	assert2(field != "", "field must not be empty")
+	ans := &RangeFieldQuery{field: field, queryType: queryType, ranges: ranges}
+	ans.AbstractQuery = NewAbstractQuery(ans)
+	return ans
+}
+
+// Create a query for matching IntRange fields of the given field.
+func NewIntRangeQuery(field string, queryType RangeQueryType, min, max []int32) *RangeFieldQuery {
+	return newRangeFieldQuery(field, queryType, docu.EncodeIntRanges(min, max))
+}
+
+// Create a query for matching LongRange fields of the given field.
+func NewLongRangeQuery(field string, queryType RangeQueryType, min, max []int64) *RangeFieldQuery {
+	return newRangeFieldQuery(field, queryType, docu.EncodeLongRanges(min, max))
+}
+
+// Create a query for matching DoubleRange fields of the given field.
+func NewDoubleRangeQuery(field string, queryType RangeQueryType, min, max []float64) *RangeFieldQuery {
+	return newRangeFieldQuery(field, queryType, docu.EncodeDoubleRanges(min, max))
+}
+
+// Returns the field of the ranges.
+func (q *RangeFieldQuery) Field() string {
+	return q.field
+}
+
+// Returns how the indexed ranges must relate to the query range.
+func (q *RangeFieldQuery) QueryType() RangeQueryType {
+	return q.queryType
+}
+
+func (q *RangeFieldQuery) CreateWeight(ss *IndexSearcher) (Weight, error) {
+	return newVerifyingWeight(q), nil
+}
+
+func (q *RangeFieldQuery) ExtractTerms(terms *index.TermSet) {}
+
+func (q *RangeFieldQuery) Visit(visitor QueryVisitor) {
+	if visitor.AcceptField(q.field) {
+		visitor.VisitLeaf(q)
+	}
+}
+
+func (q *RangeFieldQuery) ToString(field string) string {
+	s := fmt.Sprintf("<ranges:%v> %v", docu.RangesString(q.ranges), q.queryType)
+	if q.field != field {
+		s = fmt.Sprintf("%v:%v", q.field, s)
+	}
+	if q.boost != 1 {
+		s = fmt.Sprintf("%v^%v", s, q.boost)
+	}
+	return s
+}
+
+// Returns true if any range of doc, relative to reader, relates to
+// the query range as required.
+func (q *RangeFieldQuery) matches(reader index.IndexReader, doc int) (bool, error) {
+	stored, err := reader.Document(doc)
+	if err != nil {
+		return false, err
+	}
+	for _, f := range stored.Fields() {
+		if f.Name() != q.field {
+			continue
+		}
+		intersects, within, contains := docu.RelateRanges(f.BinaryValue(), q.ranges)
+		switch q.queryType {
+		case RANGE_QUERY_INTERSECTS:
+			if intersects {
+				return true, nil
+			}
+		case RANGE_QUERY_WITHIN:
+			if within {
+				return true, nil
+			}
+		case RANGE_QUERY_CONTAINS:
+			if contains {
+				return true, nil
+			}
+		case RANGE_QUERY_CROSSES:
+			if intersects && !within {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,83 @@
+package search
+
+import (
+	"fmt"
+	std "github.com/balzaczyy/golucene/analysis/standard"
+	docu "github.com/balzaczyy/golucene/core/document"
+	"github.com/balzaczyy/golucene/core/index"
+	"github.com/balzaczyy/golucene/core/store"
+	"github.com/balzaczyy/golucene/core/util"
+	"math"
+	"sort"
+	"testing"
+)
+
+func TestRangeFieldQueries(t *testing.T) {
+	dir, err := store.OpenFSDirectory(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := index.NewIndexWriter(dir, index.NewIndexWriterConfig(util.VERSION_LATEST,
+		std.NewStandardAnalyzer()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, booking := range []struct {
+		title    string
+		from, to int32
+	}{
+		{"a", 1, 5}, {"b", 3, 8}, {"c", 10, 20}, {"d", -5, 30},
+	} {
+		doc := docu.NewDocument()
+		doc.Add(docu.NewTextFieldFromString("title", booking.title, docu.STORE_YES))
+		doc.Add(docu.NewIntRange("days", []int32{booking.from}, []int32{booking.to}))
+		doc.Add(docu.NewDoubleRange("box", []float64{float64(booking.from), -1},
+			[]float64{float64(booking.to), 1}))
+		if err = w.AddDocument(doc.Fields()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	r, err := index.OpenDirectoryReader(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	ss := NewIndexSearcher(r)
+	titles := func(q Query) string {
+		hits, err := ss.SearchTop(q, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ans []string
+		for _, hit := range hits.ScoreDocs {
+			doc, err := r.Document(hit.Doc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ans = append(ans, doc.Get("title"))
+		}
+		sort.Strings(ans)
+		return fmt.Sprint(ans)
+	}
+
+	q := NewIntRangeQuery("days", RANGE_QUERY_INTERSECTS, []int32{4}, []int32{9})
+	assertEquals(t, "days:<ranges:[4 : 9]> INTERSECTS", q.String())
+	assertEquals(t, "[a b d]", titles(q))
+	assertEquals(t, "[a b]", titles(NewIntRangeQuery("days", RANGE_QUERY_WITHIN, []int32{0}, []int32{9})))
+	assertEquals(t, "[c d]", titles(NewIntRangeQuery("days", RANGE_QUERY_CONTAINS, []int32{12}, []int32{15})))
+	assertEquals(t, "[a d]", titles(NewIntRangeQuery("days", RANGE_QUERY_CROSSES, []int32{0}, []int32{2})))
+	// bounds are inclusive
+	assertEquals(t, "[b c d]", titles(NewIntRangeQuery("days", RANGE_QUERY_INTERSECTS, []int32{8}, []int32{10})))
+	// of another type
+	assertEquals(t, "[]", titles(NewLongRangeQuery("days", RANGE_QUERY_INTERSECTS, []int64{4}, []int64{9})))
+
+	assertEquals(t, "[a b d]", titles(NewDoubleRangeQuery("box", RANGE_QUERY_INTERSECTS,
+		[]float64{4, 0}, []float64{9, 0.5})))
+	assertEquals(t, "[]", titles(NewDoubleRangeQuery("box", RANGE_QUERY_INTERSECTS,
+		[]float64{4, 2}, []float64{9, 3})))
+	assertEquals(t, "[a b c]", titles(NewDoubleRangeQuery("box", RANGE_QUERY_WITHIN,
+		[]float64{-2.5, math.Inf(-1)}, []float64{25, math.Inf(1)})))
+}
@@ -0,0 +1,282 @@
+package search
+
+import (
+	"errors"
+	"sync"
+)
+
+// search/ReferenceManager.java
+
+/*
+Use to receive notification when a refresh has finished. See
+ReferenceManager.AddListener().
+*/
+type RefreshListener interface {
+	// Called right before a refresh attempt starts.
+	BeforeRefresh() error
+	/*
+		Called after the attempted refresh; if the refresh did open a new
+		reference then didRefresh will be true and Acquire() is
+		guaranteed to return the new reference.
+	*/
+	AfterRefresh(didRefresh bool) error
+}
+
+// The operations a concrete ReferenceManager performs on its managed
+// references.
+type referenceManagerSPI interface {
+	// Decrement reference counting on the given reference.
+	decRef(ref interface{}) error
+	/*
+		Refresh the given reference if needed. Returns nil if no refresh
+		was needed, otherwise a new refreshed reference.
+	*/
+	refreshIfNeeded(referenceToRefresh interface{}) (interface{}, error)
+	/*
+		Try to increment reference counting on the given reference.
+		Return true if the operation was successful.
+	*/
+	tryIncRef(ref interface{}) bool
+	// Returns the current reference count of the given reference.
+	refCount(ref interface{}) int
+	// Called after Close(), so subclass can free any resources.
+	afterClose() error
+	/*
+		Called after a refresh was attempted, regardless of whether a new
+		reference was in fact created.
+	*/
+	afterMaybeRefresh() error
+}
+
+var ErrReferenceManagerClosed = errors.New("this ReferenceManager is closed")
+
+/*
+Utility type to safely share instances of a certain type across
+multiple goroutines, while periodically refreshing them. This type
+ensures each reference is closed only once all goroutines have
+finished using it. It is recommended to consult the documentation of
+ReferenceManager implementations for their MaybeRefresh() semantics.
+*/
+type ReferenceManager struct {
+	spi referenceManagerSPI
+
+	currentLock sync.RWMutex
+	current     interface{}
+
+	// a semaphore, so that MaybeRefresh() can skip a refresh already
+	// running in another goroutine
+	refreshLock chan bool
+
+	listenersLock    sync.Mutex
+	refreshListeners []RefreshListener
+}
+
+func newReferenceManager(spi referenceManagerSPI) *ReferenceManager {
+	return &ReferenceManager{
+		spi:         spi,
+		refreshLock: make(chan bool, 1),
+	}
+}
+
+func (m *ReferenceManager) currentRef() interface{} {
+	m.currentLock.RLock()
+	defer m.currentLock.RUnlock()
+	return m.current
+}
+
+func (m *ReferenceManager) swapReference(newReference interface{}) error {
+	m.currentLock.Lock()
+	oldReference := m.current
+	if oldReference == nil && newReference != nil {
+		m.currentLock.Unlock()
+		return ErrReferenceManagerClosed
+	}
+	m.current = newReference
+	m.currentLock.Unlock()
+	if oldReference != nil {
+		return m.release(oldReference)
+	}
+	return nil
+}
+
+// Obtain the current reference, which must be released by release().
+func (m *ReferenceManager) acquire() (interface{}, error) {
+	for {
+		ref := m.currentRef()
+		if ref == nil {
+			return nil, ErrReferenceManagerClosed
+		}
+		if m.spi.tryIncRef(ref) {
+			return ref, nil
+		}
+		if m.spi.refCount(ref) == 0 && m.currentRef() == ref {
+			assert(ref != nil)
+			// This shouldn't happen: the current reference has been
+			// released by too many calls to Release()
+			return nil, errors.New("too many Release() calls: the current reference has been closed")
+		}
+	}
+}
+
+/*
+Closes this ReferenceManager to prevent future acquiring. A reference
+manager should be closed if the reference to the managed resource
+should be disposed or the application using the ReferenceManager is
+shutting down. The managed resource might not be released
+immediately, if the ReferenceManager user is holding on to a
+previously acquired reference. The resource will be released once
+the last reference is released. Those references can still be used
+as if the manager was still active.
+
+Applications should not acquire new references from this manager
+once this method has been called. Acquiring a resource on a closed
+ReferenceManager will return ErrReferenceManagerClosed.
+*/
+func (m *ReferenceManager) Close() error {
+	m.currentLock.Lock()
+	oldReference := m.current
+	m.current = nil
+	m.currentLock.Unlock()
+	if oldReference == nil {
+		return nil
+	}
+	// make sure we can call this more than once
+	if err := m.release(oldReference); err != nil {
+		return err
+	}
+	return m.spi.afterClose()
+}
+
+func (m *ReferenceManager) doMaybeRefresh() (err error) {
+	// we get here from either MaybeRefresh() or MaybeRefreshBlocking(),
+	// after the refreshLock has already been obtained
+	refreshed := false
+	reference, err := m.acquire()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err2 := m.release(reference); err == nil {
+			err = err2
+		}
+		if err2 := m.notifyRefreshListenersRefreshed(refreshed); err == nil {
+			err = err2
+		}
+	}()
+
+	if err = m.notifyRefreshListenersBefore(); err != nil {
+		return err
+	}
+	newReference, err := m.spi.refreshIfNeeded(reference)
+	if err != nil {
+		return err
+	}
+	if newReference != nil {
+		assert2(newReference != reference, "refreshIfNeeded should return nil if refresh wasn't needed")
+		if err = m.swapReference(newReference); err != nil {
+			m.release(newReference)
+			return err
+		}
+		refreshed = true
+	}
+	return m.spi.afterMaybeRefresh()
+}
+
+/*
+You must call this (or MaybeRefreshBlocking()), periodically, if you
+want that Acquire() will return refreshed instances.
+
+Goroutine-safe: it's fine for more than one goroutine to call this
+at once. Only the first goroutine will attempt the refresh;
+subsequent goroutines will see that another goroutine is already
+handling refresh and will return immediately. Note that this means
+if another goroutine is already refreshing then subsequent goroutines
+will return right away without waiting for the refresh to complete.
+
+If this method returns true it means the calling goroutine either
+refreshed or that there were no changes to refresh. If it returns
+false it means another goroutine is currently refreshing.
+*/
+func (m *ReferenceManager) MaybeRefresh() (bool, error) {
+	if m.currentRef() == nil {
+		return false, ErrReferenceManagerClosed
+	}
+	select {
+	case m.refreshLock <- true:
+	default:
+		return false, nil
+	}
+	defer func() { <-m.refreshLock }()
+	return true, m.doMaybeRefresh()
+}
+
+/*
+You must call this (or MaybeRefresh()), periodically, if you want
+that Acquire() will return refreshed instances.
+
+Goroutine-safe: it's fine for more than one goroutine to call this
+at once. Only the first goroutine will attempt the refresh;
+subsequent goroutines will block until the refresh completes.
+
+This method differs from MaybeRefresh() in that it always waits for
+a refresh to complete before returning.
+*/
+func (m *ReferenceManager) MaybeRefreshBlocking() error {
+	if m.currentRef() == nil {
+		return ErrReferenceManagerClosed
+	}
+	// Ensure only 1 goroutine does refresh at once
+	m.refreshLock <- true
+	defer func() { <-m.refreshLock }()
+	return m.doMaybeRefresh()
+}
+
+// Release the reference previously obtained via acquire(). It's safe
+// to call this after Close().
+func (m *ReferenceManager) release(reference interface{}) error {
+	assert(reference != nil)
+	return m.spi.decRef(reference)
+}
+
+func (m *ReferenceManager) notifyRefreshListenersBefore() error {
+	m.listenersLock.Lock()
+	defer m.listenersLock.Unlock()
+	for _, listener := range m.refreshListeners {
+		if err := listener.BeforeRefresh(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *ReferenceManager) notifyRefreshListenersRefreshed(didRefresh bool) error {
+	m.listenersLock.Lock()
+	defer m.listenersLock.Unlock()
+	for _, listener := range m.refreshListeners {
+		if err := listener.AfterRefresh(didRefresh); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Adds a listener, to be notified when a reference is refreshed/swapped.
+func (m *ReferenceManager) AddListener(listener RefreshListener) {
+	assert2(listener != nil, "Listener cannot be nil")
+	m.listenersLock.Lock()
+	defer m.listenersLock.Unlock()
+	m.refreshListeners = append(m.refreshListeners, listener)
+}
+
+// Remove a listener added with AddListener().
+func (m *ReferenceManager) RemoveListener(listener RefreshListener) {
+	assert2(listener != nil, "Listener cannot be nil")
+	m.listenersLock.Lock()
+	defer m.listenersLock.Unlock()
+	for i, l := range m.refreshListeners {
+		if l == listener {
+			m.refreshListeners = append(m.refreshListeners[:i], m.refreshListeners[i+1:]...)
+			return
+		}
+	}
+}
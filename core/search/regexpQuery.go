@@ -0,0 +1,58 @@
+package search
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/balzaczyy/golucene/core/index"
+	"github.com/balzaczyy/golucene/core/util/automaton"
+)
+
+// search/RegexpQuery.java
+
+/*
+A fast regular expression query based on the automaton package.
+
+The supported syntax is documented in the RegExp type. Note this
+might be different than other regular expression implementations:
+the expression must match the whole term, and there are no anchors.
+
+It panics if the expression is invalid.
+*/
+type RegexpQuery struct {
+	*MultiTermQuery
+	term      *index.Term
+	automaton *automaton.CharacterRunAutomaton
+}
+
+/* Constructs a query for terms matching term. */
+func NewRegexpQuery(term *index.Term) *RegexpQuery {
+	ans := &RegexpQuery{term: term}
+	ans.MultiTermQuery = NewMultiTermQuery(ans, term.Field)
+	ans.automaton = automaton.NewCharacterRunAutomaton(
+		automaton.NewRegExp(string(term.Bytes)).ToAutomaton())
+	return ans
+}
+
+// Returns the regexp of this query wrapped in a Term.
+func (q *RegexpQuery) Regexp() *index.Term {
+	return q.term
+}
+
+func (q *RegexpQuery) AcceptTerm(term []byte) bool {
+	return q.automaton.Run(string(term))
+}
+
+func (q *RegexpQuery) ToString(field string) string {
+	var buf bytes.Buffer
+	if q.field != field {
+		buf.WriteString(q.field)
+		buf.WriteRune(':')
+	}
+	buf.WriteRune('/')
+	buf.Write(q.term.Bytes)
+	buf.WriteRune('/')
+	if q.boost != 1 {
+		fmt.Fprintf(&buf, "^%v", q.boost)
+	}
+	return buf.String()
+}
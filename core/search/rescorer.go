@@ -0,0 +1,422 @@
+package search
+
+import (
+	"fmt"
+	docu "github.com/balzaczyy/golucene/core/document"
+	"github.com/balzaczyy/golucene/core/index"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// search/Rescorer.java
+
+/*
+Re-scores the topN results (TopDocs) from an original query. See
+QueryRescorer for an actual implementation. Typically, you run a low-
+cost first-pass query across the entire index, collecting the top
+few hundred hits perhaps, and then use this type to apply a more
+costly second pass scoring. See RescoreWithQuery() for a simple
+static method to call to rescore using a 2nd pass Query.
+*/
+type Rescorer interface {
+	/*
+		Rescore an initial first-pass TopDocs.
+
+		searcher is the IndexSearcher used to produce the first pass
+		topDocs; firstPassTopDocs holds the hits from the first pass
+		search (it is very important that these are in fact the hits
+		produced by searcher, since the docIDs refer to it); topN is the
+		number of hits to return.
+	*/
+	Rescore(searcher *IndexSearcher, firstPassTopDocs TopDocs, topN int) (TopDocs, error)
+	/*
+		Explains how the score for the specified document was computed.
+	*/
+	Explain(searcher *IndexSearcher, firstPassExplanation Explanation, docID int) (Explanation, error)
+}
+
+// Returns a copy of the hits, sorted by ascending doc ID.
+func hitsByDocID(firstPassTopDocs TopDocs) []*ScoreDoc {
+	hits := make([]*ScoreDoc, len(firstPassTopDocs.ScoreDocs))
+	for i, hit := range firstPassTopDocs.ScoreDocs {
+		copied := *hit
+		hits[i] = &copied
+	}
+	sort.Sort(scoreDocsByDocID(hits))
+	return hits
+}
+
+type scoreDocsByDocID []*ScoreDoc
+
+func (a scoreDocsByDocID) Len() int           { return len(a) }
+func (a scoreDocsByDocID) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a scoreDocsByDocID) Less(i, j int) bool { return a[i].Doc < a[j].Doc }
+
+/*
+Merge sorts the docIDs of the hits, which must be sorted by doc ID,
+with the leaves of the searcher, calling onLeaf() each time a hit
+falls in a new leaf, and onHit() with the segment-relative doc ID of
+every hit.
+*/
+func forEachHitByLeaf(searcher *IndexSearcher, hits []*ScoreDoc,
+	onLeaf func(ctx *index.AtomicReaderContext) error,
+	onHit func(hit *ScoreDoc, doc int) error) error {
+
+	leaves := searcher.leafContexts
+	readerUpto, endDoc, docBase := -1, 0, 0
+	for _, hit := range hits {
+		var readerContext *index.AtomicReaderContext
+		for hit.Doc >= endDoc {
+			readerUpto++
+			readerContext = leaves[readerUpto]
+			endDoc = readerContext.DocBase + readerContext.Reader().MaxDoc()
+		}
+		if readerContext != nil {
+			// We advanced to another segment:
+			docBase = readerContext.DocBase
+			if err := onLeaf(readerContext); err != nil {
+				return err
+			}
+		}
+		if err := onHit(hit, hit.Doc-docBase); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// search/QueryRescorer.java
+
+// Implemented by concrete QueryRescorers to combine the scores of the
+// two passes.
+type QueryRescorerSPI interface {
+	/*
+		Implement this in a subclass to combine the first pass and
+		second pass scores. If secondPassMatches is false then the
+		second pass query failed to match a hit from the first pass
+		query, and you should ignore the secondPassScore.
+	*/
+	Combine(firstPassScore float32, secondPassMatches bool, secondPassScore float32) float32
+}
+
+/*
+A Rescorer that uses a provided Query to assign scores to the
+first-pass hits.
+*/
+type QueryRescorer struct {
+	spi   QueryRescorerSPI
+	query Query
+}
+
+/*
+Sole constructor, passing the 2nd pass query to assign scores to the
+1st pass hits, and the spi combining the scores of both passes.
+*/
+func NewQueryRescorer(spi QueryRescorerSPI, query Query) *QueryRescorer {
+	return &QueryRescorer{spi, query}
+}
+
+/*
+Creates a QueryRescorer that sums the first pass score multiplied by
+firstPassWeight and, if the second pass query matches, the second
+pass score multiplied by secondPassWeight.
+*/
+func NewLinearQueryRescorer(query Query, firstPassWeight, secondPassWeight float32) *QueryRescorer {
+	return NewQueryRescorer(&linearCombiner{firstPassWeight, secondPassWeight}, query)
+}
+
+// Returns the 2nd pass query.
+func (r *QueryRescorer) Query() Query {
+	return r.query
+}
+
+func (r *QueryRescorer) Rescore(searcher *IndexSearcher, firstPassTopDocs TopDocs, topN int) (TopDocs, error) {
+	hits := hitsByDocID(firstPassTopDocs)
+
+	weight, err := searcher.spi.CreateNormalizedWeight(r.query)
+	if err != nil {
+		return TopDocs{}, err
+	}
+
+	var scorer Scorer
+	err = forEachHitByLeaf(searcher, hits, func(ctx *index.AtomicReaderContext) (err error) {
+		scorer, err = weight.(WeightImplSPI).Scorer(ctx, nil)
+		return
+	}, func(hit *ScoreDoc, targetDoc int) error {
+		if scorer != nil {
+			actualDoc := scorer.DocId()
+			if actualDoc < targetDoc {
+				var err error
+				if actualDoc, err = scorer.Advance(targetDoc); err != nil {
+					return err
+				}
+			}
+			if actualDoc == targetDoc {
+				// Query did match this doc:
+				score, err := scorer.Score()
+				if err != nil {
+					return err
+				}
+				hit.Score = r.spi.Combine(hit.Score, true, score)
+				return nil
+			}
+		}
+		// Query did not match this doc:
+		assert(scorer == nil || scorer.DocId() > targetDoc)
+		hit.Score = r.spi.Combine(hit.Score, false, 0)
+		return nil
+	})
+	if err != nil {
+		return TopDocs{}, err
+	}
+
+	// TODO: we should do a partial sort (of only topN) instead, but
+	// typically the number of hits is smallish:
+	sort.Sort(scoreDocsByScore(hits))
+	if topN < len(hits) {
+		hits = hits[:topN]
+	}
+	maxScore := math.NaN()
+	if len(hits) > 0 {
+		maxScore = float64(hits[0].Score)
+	}
+	return TopDocs{firstPassTopDocs.TotalHits, hits, maxScore}, nil
+}
+
+func (r *QueryRescorer) Explain(searcher *IndexSearcher,
+	firstPassExplanation Explanation, docID int) (Explanation, error) {
+
+	secondPassExplanation, err := searcher.Explain(r.query, docID)
+	if err != nil {
+		return nil, err
+	}
+
+	secondPassMatches := secondPassExplanation.IsMatch()
+	var score float32
+	if secondPassMatches {
+		score = r.spi.Combine(firstPassExplanation.Value(), true, secondPassExplanation.Value())
+	} else {
+		score = r.spi.Combine(firstPassExplanation.Value(), false, 0)
+	}
+
+	result := newExplanation(score, fmt.Sprintf(
+		"combined first and second pass score using %v", reflect.TypeOf(r.spi)))
+
+	first := newExplanation(firstPassExplanation.Value(), "first pass score")
+	first.addDetail(firstPassExplanation)
+	result.addDetail(first)
+
+	var second *ExplanationImpl
+	if secondPassMatches {
+		second = newExplanation(secondPassExplanation.Value(), "second pass score")
+	} else {
+		second = newExplanation(0, "no second pass score")
+	}
+	second.addDetail(secondPassExplanation)
+	result.addDetail(second)
+
+	return result, nil
+}
+
+// Sorts hits by score descending, then by doc ID ascending.
+type scoreDocsByScore []*ScoreDoc
+
+func (a scoreDocsByScore) Len() int      { return len(a) }
+func (a scoreDocsByScore) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
+func (a scoreDocsByScore) Less(i, j int) bool {
+	if a[i].Score != a[j].Score {
+		return a[i].Score > a[j].Score
+	}
+	return a[i].Doc < a[j].Doc
+}
+
+// Sums the weighted scores of both passes.
+type linearCombiner struct {
+	firstPassWeight, secondPassWeight float32
+}
+
+func (c *linearCombiner) Combine(firstPassScore float32, secondPassMatches bool, secondPassScore float32) float32 {
+	score := c.firstPassWeight * firstPassScore
+	if secondPassMatches {
+		score += c.secondPassWeight * secondPassScore
+	}
+	return score
+}
+
+/*
+Convenience function to rescore the provided TopDocs using the
+provided query. This adds the score of the query, multiplied by the
+provided weight, to the score of every hit the query matches.
+*/
+func RescoreWithQuery(searcher *IndexSearcher, topDocs TopDocs,
+	query Query, weight float32, topN int) (TopDocs, error) {
+
+	return NewLinearQueryRescorer(query, 1, weight).Rescore(searcher, topDocs, topN)
+}
+
+// search/SortRescorer.java
+
+/*
+A Rescorer that re-sorts according to a provided Sort.
+
+Sort values of fields are read from the stored fields of the hits,
+so the fields must be stored; hits missing a value sort first. Sort
+values of SORT_FIELD_SCORE are the first pass scores, and those of
+SORT_FIELD_CUSTOM are computed by the DoubleValuesSource of the field.
+*/
+type SortRescorer struct {
+	sort *Sort
+}
+
+// Sole constructor.
+func NewSortRescorer(sort *Sort) *SortRescorer {
+	return &SortRescorer{sort}
+}
+
+func (r *SortRescorer) Rescore(searcher *IndexSearcher, firstPassTopDocs TopDocs, topN int) (TopDocs, error) {
+	hits := hitsByDocID(firstPassTopDocs)
+
+	var reader index.IndexReader
+	customValues := make([]DoubleValues, len(r.sort.fields))
+	err := forEachHitByLeaf(searcher, hits, func(ctx *index.AtomicReaderContext) (err error) {
+		reader = ctx.Reader()
+		for i, sortField := range r.sort.fields {
+			if sortField.typ == SORT_FIELD_CUSTOM {
+				if customValues[i], err = sortField.source.GetValues(ctx); err != nil {
+					return
+				}
+			}
+		}
+		return
+	}, func(hit *ScoreDoc, doc int) (err error) {
+		hit.Fields, err = r.sortValues(reader, customValues, hit, doc)
+		return
+	})
+	if err != nil {
+		return TopDocs{}, err
+	}
+
+	sort.Stable(&hitsBySort{r.sort, hits})
+	if topN < len(hits) {
+		hits = hits[:topN]
+	}
+	maxScore := math.NaN()
+	for _, hit := range hits {
+		if score := float64(hit.Score); math.IsNaN(maxScore) || score > maxScore {
+			maxScore = score
+		}
+	}
+	return TopDocs{firstPassTopDocs.TotalHits, hits, maxScore}, nil
+}
+
+/*
+Reads the values of the sort fields of the doc, relative to reader.
+customValues holds the values of the leaf of SORT_FIELD_CUSTOM fields.
+*/
+func (r *SortRescorer) sortValues(reader index.IndexReader, customValues []DoubleValues,
+	hit *ScoreDoc, doc int) ([]interface{}, error) {
+
+	var values []interface{}
+	var stored *docu.Document
+	for i, sortField := range r.sort.fields {
+		switch sortField.typ {
+		case SORT_FIELD_SCORE:
+			values = append(values, hit.Score)
+			continue
+		case SORT_FIELD_DOC:
+			values = append(values, hit.Doc)
+			continue
+		case SORT_FIELD_CUSTOM:
+			ok, err := customValues[i].AdvanceExact(doc)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				values = append(values, sortField.missingValue)
+				continue
+			}
+			value, err := customValues[i].DoubleValue()
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+			continue
+		}
+
+		var err error
+		if stored == nil {
+			if stored, err = reader.Document(doc); err != nil {
+				return nil, err
+			}
+		}
+		s := stored.Get(sortField.field)
+		if s == "" {
+			values = append(values, sortField.missingValue)
+			continue
+		}
+		var value interface{}
+		switch sortField.typ {
+		case SORT_FIELD_STRING:
+			value = s
+		case SORT_FIELD_INT:
+			value, err = strconv.Atoi(s)
+		case SORT_FIELD_LONG:
+			value, err = strconv.ParseInt(s, 10, 64)
+		case SORT_FIELD_FLOAT:
+			var f float64
+			f, err = strconv.ParseFloat(s, 32)
+			value = float32(f)
+		case SORT_FIELD_DOUBLE:
+			value, err = strconv.ParseFloat(s, 64)
+		}
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+func (r *SortRescorer) Explain(searcher *IndexSearcher,
+	firstPassExplanation Explanation, docID int) (Explanation, error) {
+
+	oneHit := TopDocs{1, []*ScoreDoc{newScoreDoc(docID, firstPassExplanation.Value())},
+		float64(firstPassExplanation.Value())}
+	hits, err := r.Rescore(searcher, oneHit, 1)
+	if err != nil {
+		return nil, err
+	}
+	assert(hits.TotalHits == 1)
+
+	result := newExplanation(0, fmt.Sprintf("sort field values for sort=%v", r.sort))
+
+	// Add first pass:
+	first := newExplanation(firstPassExplanation.Value(), "first pass score")
+	first.addDetail(firstPassExplanation)
+	result.addDetail(first)
+
+	fieldDoc := hits.ScoreDocs[0]
+	for i, sortField := range r.sort.fields {
+		result.addDetail(newExplanation(0, fmt.Sprintf("%v=%v", sortField, fieldDoc.Fields[i])))
+	}
+	return result, nil
+}
+
+// Sorts hits by their sort values, ties broken by doc ID.
+type hitsBySort struct {
+	sort *Sort
+	hits []*ScoreDoc
+}
+
+func (a *hitsBySort) Len() int      { return len(a.hits) }
+func (a *hitsBySort) Swap(i, j int) { a.hits[i], a.hits[j] = a.hits[j], a.hits[i] }
+func (a *hitsBySort) Less(i, j int) bool {
+	for k, sortField := range a.sort.fields {
+		if cmp := sortField.compareValues(a.hits[i].Fields[k], a.hits[j].Fields[k]); cmp != 0 {
+			return cmp < 0
+		}
+	}
+	return a.hits[i].Doc < a.hits[j].Doc
+}
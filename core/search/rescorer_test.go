@@ -0,0 +1,120 @@
+package search
+
+import (
+	"github.com/balzaczyy/golucene/core/index"
+	"github.com/balzaczyy/golucene/core/store"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestQueryRescorer(t *testing.T) {
+	d, err := store.OpenFSDirectory("testdata/belfrysample")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := index.OpenDirectoryReader(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ss := NewIndexSearcher(r)
+	firstPass, err := ss.SearchTop(NewTermQuery(index.NewTerm("content", "bat")), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := NewTermQuery(index.NewTerm("content", "food"))
+	secondPass, err := ss.SearchTop(q, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(secondPass.ScoreDocs) == 0 || len(secondPass.ScoreDocs) >= 8 {
+		t.Fatalf("Expected 'food' in some of the docs, but was %v", secondPass.TotalHits)
+	}
+	expected := make(map[int]float32)
+	for _, hit := range firstPass.ScoreDocs {
+		expected[hit.Doc] = hit.Score
+	}
+	topScore := firstPass.ScoreDocs[0].Score
+	for _, hit := range secondPass.ScoreDocs {
+		expected[hit.Doc] += 2 * hit.Score
+	}
+
+	rescored, err := RescoreWithQuery(ss, firstPass, q, 2, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEquals(t, 8, rescored.TotalHits)
+	assertEquals(t, 5, len(rescored.ScoreDocs))
+	assertEquals(t, secondPass.ScoreDocs[0].Doc, rescored.ScoreDocs[0].Doc)
+	assertEquals(t, float64(rescored.ScoreDocs[0].Score), rescored.MaxScore())
+	for i, hit := range rescored.ScoreDocs {
+		if math.Abs(float64(expected[hit.Doc]-hit.Score)) > 1e-6 {
+			t.Errorf("Expected score %v for doc %v, but was %v", expected[hit.Doc], hit.Doc, hit.Score)
+		}
+		if i > 0 && hit.Score > rescored.ScoreDocs[i-1].Score {
+			t.Errorf("Hits are not sorted by score: %v", rescored.ScoreDocs)
+		}
+	}
+	// the first pass hits are left untouched
+	assertEquals(t, topScore, firstPass.ScoreDocs[0].Score)
+
+	rescorer := NewLinearQueryRescorer(q, 1, 2)
+	for _, hit := range firstPass.ScoreDocs {
+		firstExp, err := ss.Explain(NewTermQuery(index.NewTerm("content", "bat")), hit.Doc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		exp, err := rescorer.Explain(ss, firstExp, hit.Doc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(float64(expected[hit.Doc]-exp.Value())) > 1e-6 {
+			t.Errorf("Expected explained score %v for doc %v, but was %v",
+				expected[hit.Doc], hit.Doc, exp)
+		}
+	}
+}
+
+func TestSortRescorer(t *testing.T) {
+	d, err := store.OpenFSDirectory("testdata/belfrysample")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := index.OpenDirectoryReader(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ss := NewIndexSearcher(r)
+	firstPass, err := ss.SearchTop(NewTermQuery(index.NewTerm("content", "bat")), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rescorer := NewSortRescorer(NewSort(NewSortField("title", SORT_FIELD_STRING, true), FIELD_SCORE))
+	rescored, err := rescorer.Rescore(ss, firstPass, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEquals(t, 8, len(rescored.ScoreDocs))
+	var last string
+	for i, hit := range rescored.ScoreDocs {
+		doc, err := r.Document(hit.Doc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		title := doc.Get("title")
+		assertEquals(t, title, hit.Fields[0])
+		if i > 0 && title > last {
+			t.Errorf("Expected titles in reverse order, but %v came after %v", title, last)
+		}
+		last = title
+	}
+
+	exp, err := rescorer.Explain(ss, NewExplanation(1, "first pass"), firstPass.ScoreDocs[0].Doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := exp.(*ExplanationImpl).String(); !strings.Contains(s, `<string: "title">!=Feeding your bat`) {
+		t.Errorf("Expected sort values in explanation, but was %v", s)
+	}
+}
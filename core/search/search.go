@@ -15,6 +15,8 @@ type IndexSearcherSPI interface {
 	Rewrite(Query) (Query, error)
 	WrapFilter(Query, Filter) Query
 	SearchLWC([]*index.AtomicReaderContext, Weight, Collector) error
+	TermStatistics(*index.Term, *index.TermContext) TermStatistics
+	CollectionStatistics(string) CollectionStatistics
 }
 
 // IndexSearcher
@@ -28,22 +30,41 @@ type IndexSearcher struct {
 
 func NewIndexSearcher(r index.IndexReader) *IndexSearcher {
 	// log.Print("Initializing IndexSearcher from IndexReader: ", r)
-	return NewIndexSearcherFromContext(r.Context())
+	ss := NewIndexSearcherFromContext(r.Context())
+	// keep the very reader, which context may only refer to its
+	// embedded composite reader
+	ss.reader = r
+	return ss
 }
 
 func NewIndexSearcherFromContext(context index.IndexReaderContext) *IndexSearcher {
 	// assert2(context.isTopLevel, "IndexSearcher's ReaderContext must be topLevel for reader %v", context.reader())
-	defaultSimilarity := NewDefaultSimilarity()
+	defaultSimilarity := NewBM25Similarity()
 	ss := &IndexSearcher{nil, context.Reader(), context, context.Leaves(), defaultSimilarity}
 	ss.spi = ss
 	return ss
 }
 
-/* Expert: set the similarity implementation used by this IndexSearcher. */
+/*
+Expert: set the similarity implementation used by this IndexSearcher.
+BM25Similarity is used by default; DefaultSimilarity restores the
+classic TF-IDF scoring.
+*/
 func (ss *IndexSearcher) SetSimilarity(similarity Similarity) {
 	ss.similarity = similarity
 }
 
+/*
+Expert: installs the service this IndexSearcher dispatches its
+overridable methods to. A type embedding *IndexSearcher can override
+some of them and install itself, e.g. to weight queries with term and
+collection statistics gathered across all the shards of a distributed
+index, instead of those of the local reader only.
+*/
+func (ss *IndexSearcher) SetSPI(spi IndexSearcherSPI) {
+	ss.spi = spi
+}
+
 func (ss *IndexSearcher) SearchTop(q Query, n int) (topDocs TopDocs, err error) {
 	return ss.Search(q, nil, n)
 }
@@ -56,6 +77,22 @@ func (ss *IndexSearcher) Search(q Query, f Filter, n int) (topDocs TopDocs, err
 	return ss.searchWSI(w, nil, n), nil
 }
 
+/*
+Lower-level search API.
+
+Collector.Collect() is called for every matching document. Applications
+should only use this if they need all of the matching documents. The
+high-level search API (SearchTop()) is usually more efficient, as it
+skips non-high-scoring hits.
+*/
+func (ss *IndexSearcher) SearchCollector(q Query, c Collector) error {
+	w, err := ss.spi.CreateNormalizedWeight(q)
+	if err != nil {
+		return err
+	}
+	return ss.spi.SearchLWC(ss.leafContexts, w, c)
+}
+
 /** Expert: Low-level search implementation.  Finds the top <code>n</code>
  * hits for <code>query</code>, applying <code>filter</code> if non-null.
  *
@@ -106,10 +143,12 @@ func (ss *IndexSearcher) SearchLWC(leaves []*index.AtomicReaderContext, w Weight
 			return err
 		}
 		if scorer != nil {
-			err = scorer.ScoreAndCollect(c)
+			if err = scorer.ScoreAndCollect(c); err != nil {
+				return err
+			}
 		} // TODO catch CollectionTerminatedException
 	}
-	return
+	return nil
 }
 
 func (ss *IndexSearcher) WrapFilter(q Query, f Filter) Query {
@@ -180,6 +219,11 @@ func (ss *IndexSearcher) Rewrite(q Query) (Query, error) {
 	return q, nil
 }
 
+// Returns the IndexReader this searches.
+func (ss *IndexSearcher) IndexReader() index.IndexReader {
+	return ss.reader
+}
+
 // Returns this searhcers the top-level IndexReaderContext
 func (ss *IndexSearcher) TopReaderContext() index.IndexReaderContext {
 	return ss.readerContext
@@ -189,10 +233,22 @@ func (ss *IndexSearcher) String() string {
 	return fmt.Sprintf("IndexSearcher(%v)", ss.reader)
 }
 
+/*
+Returns TermStatistics for a term.
+
+This can be overridden for example, to return a term's statistics
+across a distributed collection.
+*/
 func (ss *IndexSearcher) TermStatistics(term *index.Term, context *index.TermContext) TermStatistics {
 	return NewTermStatistics(term.Bytes, int64(context.DocFreq), context.TotalTermFreq)
 }
 
+/*
+Returns CollectionStatistics for a field.
+
+This can be overridden for example, to return a field's statistics
+across a distributed collection.
+*/
 func (ss *IndexSearcher) CollectionStatistics(field string) CollectionStatistics {
 	terms := index.GetMultiTerms(ss.reader, field)
 	if terms == nil {
@@ -225,6 +281,23 @@ func NewCollectionStatistics(field string, maxDoc, docCount, sumTotalTermFreq, s
 	return CollectionStatistics{field, maxDoc, docCount, sumTotalTermFreq, sumDocFreq}
 }
 
+// Returns the field name
+func (s CollectionStatistics) Field() string { return s.field }
+
+// Returns the total number of documents, regardless of whether they
+// all contain values for this field.
+func (s CollectionStatistics) MaxDoc() int64 { return s.maxDoc }
+
+// Returns the total number of documents that have at least one term
+// for this field.
+func (s CollectionStatistics) DocCount() int64 { return s.docCount }
+
+// Returns the total number of tokens for this field
+func (s CollectionStatistics) SumTotalTermFreq() int64 { return s.sumTotalTermFreq }
+
+// Returns the total number of postings for this field
+func (s CollectionStatistics) SumDocFreq() int64 { return s.sumDocFreq }
+
 /**
  * API for scoring "sloppy" queries such as {@link TermQuery},
  * {@link SpanQuery}, and {@link PhraseQuery}.
@@ -241,6 +314,9 @@ type SimScorer interface {
 	 * @return document's score
 	 */
 	Score(doc int, freq float32) float32
+	// Computes the amount of a sloppy phrase match, based on an edit
+	// distance.
+	computeSlopFactor(distance int) float32
 	// Explain the score for a single document
 	explain(int, Explanation) Explanation
 }
@@ -267,6 +343,17 @@ type ITFIDFSimilarity interface {
 	 * @return a score factor based on a term's within-document frequency
 	 */
 	tf(freq float32) float32
+	/*
+		Computes the amount of a sloppy phrase match, based on an edit
+		distance. This value is summed for each sloppy phrase match in a
+		document to form the frequency to be passed to tf().
+
+		A phrase match with a small edit distance to a document passage
+		more closely matches the document, so implementations of this
+		method usually return larger values when the edit distance is
+		small and smaller values when it is large.
+	*/
+	sloppyFreq(distance int) float32
 	/** Computes a score factor based on a term's document frequency (the number
 	 * of documents which contain the term).  This value is multiplied by the
 	 * {@link #tf(float)} factor for each term in the query and these products are
@@ -300,6 +387,11 @@ func newTFIDFSimilarity(spi ITFIDFSimilarity) *TFIDFSimilarity {
 	return &TFIDFSimilarity{spi}
 }
 
+// Computes a score factor based on a term's document frequency.
+func (ts *TFIDFSimilarity) Idf(docFreq, numDocs int64) float32 {
+	return ts.spi.idf(docFreq, numDocs)
+}
+
 func (ts *TFIDFSimilarity) idfExplainTerm(collectionStats CollectionStatistics, termStats TermStatistics) Explanation {
 	df, max := termStats.DocFreq, collectionStats.maxDoc
 	idf := ts.spi.idf(df, max)
@@ -360,6 +452,10 @@ func (ss *tfIDFSimScorer) Score(doc int, freq float32) float32 {
 	return raw * ss.owner.spi.decodeNormValue(ss.norms(doc)) // normalize for field
 }
 
+func (ss *tfIDFSimScorer) computeSlopFactor(distance int) float32 {
+	return ss.owner.spi.sloppyFreq(distance)
+}
+
 func (ss *tfIDFSimScorer) explain(doc int, freq Explanation) Explanation {
 	return ss.owner.explainScore(doc, freq, ss.stats, ss.norms)
 }
@@ -510,6 +606,11 @@ func (ds *DefaultSimilarity) tf(freq float32) float32 {
 	return float32(math.Sqrt(float64(freq)))
 }
 
+// Implemented as 1 / (distance + 1).
+func (ds *DefaultSimilarity) sloppyFreq(distance int) float32 {
+	return 1.0 / float32(distance+1)
+}
+
 func (ds *DefaultSimilarity) idf(docFreq int64, numDocs int64) float32 {
 	return float32(math.Log(float64(numDocs)/float64(docFreq+1))) + 1.0
 }
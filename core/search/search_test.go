@@ -1,7 +1,9 @@
 package search
 
 import (
+	_ "github.com/balzaczyy/golucene/core/codec/lucene410"
 	_ "github.com/balzaczyy/golucene/core/codec/lucene42"
+	docu "github.com/balzaczyy/golucene/core/document"
 	"github.com/balzaczyy/golucene/core/index"
 	"github.com/balzaczyy/golucene/core/store"
 	"testing"
@@ -40,6 +42,7 @@ func TestKeywordSearch(t *testing.T) {
 		t.Error("Should have one leaf.")
 	}
 	ss := NewIndexSearcher(r)
+	ss.SetSimilarity(NewDefaultSimilarity())
 	docs, err := ss.SearchTop(NewTermQuery(index.NewTerm("content", "bat")), 10)
 	if err != nil {
 		t.Error(err)
@@ -53,6 +56,24 @@ func TestKeywordSearch(t *testing.T) {
 	assertEquals(t, "Bat recycling", doc.Get("title"))
 }
 
+// Adds a doc with one "title" value per given title.
+func addDocument(t *testing.T, w *index.IndexWriter, titles ...string) {
+	doc := docu.NewDocument()
+	for _, title := range titles {
+		doc.Add(docu.NewTextFieldFromString("title", title, docu.STORE_YES))
+	}
+	if err := w.AddDocument(doc.Fields()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func mustInt(n int, err error) int {
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
 // func TestSingleSearch(t *testing.T) {
 // 	ss := NewSearcher()
 // 	ss.IncludeIndex("testdata/belfrysample")
@@ -75,3 +96,21 @@ func assertEquals(t *testing.T, a, b interface{}) {
 // 	ss.IncludeIndex("testdata/usingworldtimepro")
 // 	assertEquals(t, 17, ss.search("time"))
 // }
+
+type freqCollector struct {
+	scorer Scorer
+	freqs  map[int]int
+	scores map[int]float32
+}
+
+func (c *freqCollector) SetScorer(s Scorer)                           { c.scorer = s }
+func (c *freqCollector) SetNextReader(ctx *index.AtomicReaderContext) {}
+func (c *freqCollector) AcceptsDocsOutOfOrder() bool                  { return true }
+
+func (c *freqCollector) Collect(doc int) (err error) {
+	if c.freqs[doc], err = c.scorer.Freq(); err != nil {
+		return err
+	}
+	c.scores[doc], err = c.scorer.Score()
+	return err
+}
@@ -0,0 +1,263 @@
+package search
+
+import (
+	"errors"
+	"fmt"
+	"github.com/balzaczyy/golucene/core/index"
+	"sort"
+	"sync"
+	"time"
+)
+
+// search/SearcherLifetimeManager.java
+
+type searcherTracker struct {
+	searcher   *IndexSearcher
+	recordTime time.Time
+	version    int64
+}
+
+func newSearcherTracker(searcher *IndexSearcher) *searcherTracker {
+	version := searcher.IndexReader().(index.DirectoryReader).Version()
+	searcher.IndexReader().IncRef()
+	return &searcherTracker{searcher, time.Now(), version}
+}
+
+func (t *searcherTracker) Close() error {
+	return t.searcher.IndexReader().DecRef()
+}
+
+// Newer searchers are sort before older ones
+type searcherTrackersByAge []*searcherTracker
+
+func (a searcherTrackersByAge) Len() int           { return len(a) }
+func (a searcherTrackersByAge) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a searcherTrackersByAge) Less(i, j int) bool { return a[i].recordTime.After(a[j].recordTime) }
+
+var ErrSearcherLifetimeManagerClosed = errors.New("this SearcherLifetimeManager instance is closed")
+
+/*
+Keeps track of current plus old IndexSearchers, closing the old ones
+once they have timed out.
+
+Use it like this:
+
+	mgr := NewSearcherLifetimeManager()
+
+Per search-request, if it's a "new" search request, then obtain the
+latest searcher you have (for example, by using SearcherManager), and
+then record this searcher:
+
+	// Record the current searcher, and save the returned token into
+	// user's search results (eg as a hidden HTML form field):
+	token, err := mgr.Record(searcher)
+
+When a follow-up search arrives, for example the user clicks next
+page, drills down/up, etc., take the token that you saved from the
+previous search and:
+
+	// If possible, obtain the same searcher as the last search:
+	searcher, err := mgr.Acquire(token)
+	if err != nil {
+		return err
+	}
+	if searcher != nil {
+		// Searcher is still here
+		defer mgr.Release(searcher)
+		// Do searching...
+	} else {
+		// Searcher was pruned -- notify user session timed out, or, pull
+		// fresh searcher again
+	}
+
+Finally, in a separate goroutine, ideally the same goroutine that's
+periodically reopening your searchers, you should periodically prune
+old searchers:
+
+	mgr.Prune(NewPruneByAge(600 * time.Second))
+
+NOTE: keeping many searchers around means you'll use more resources
+(open files, RAM) than a single searcher. However, as long as you are
+using OpenDirectoryReaderIfChanged(), the searchers will usually
+share almost all segments and the added resource usage is
+contained. When a large merge has completed, and you reopen, because
+that is a large change, the new searcher will use higher additional
+RAM than other searchers; but large merges don't complete very often
+and it's unlikely you'll hit two of them in your expiration window.
+Still you should budget plenty of heap in the runtime to have a good
+safety margin.
+*/
+type SearcherLifetimeManager struct {
+	sync.Mutex // guards Prune() and Close()
+
+	lock      sync.RWMutex // guards closed and searchers
+	closed    bool
+	searchers map[int64]*searcherTracker
+}
+
+func NewSearcherLifetimeManager() *SearcherLifetimeManager {
+	return &SearcherLifetimeManager{searchers: make(map[int64]*searcherTracker)}
+}
+
+/*
+Records that you are now using this IndexSearcher. Always call this
+when you've obtained a possibly new IndexSearcher, for example from
+SearcherManager. It's fine if you already passed the same searcher to
+this method before.
+
+This returns the int64 token that you can later pass to Acquire() to
+retrieve the same IndexSearcher. You should record this token in the
+search results sent to your user, such that if the user performs a
+follow-on action (clicks next page, drills down, etc.) the token is
+returned.
+*/
+func (m *SearcherLifetimeManager) Record(searcher *IndexSearcher) (int64, error) {
+	// TODO: we don't have to use DirectoryReader.Version() to track;
+	// could be any int64 token... but it's good because it's more
+	// likely to be unique across searchers from different indexes
+	version := searcher.IndexReader().(index.DirectoryReader).Version()
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.closed {
+		return 0, ErrSearcherLifetimeManagerClosed
+	}
+	if tracker, ok := m.searchers[version]; !ok {
+		m.searchers[version] = newSearcherTracker(searcher)
+	} else if tracker.searcher != searcher {
+		return 0, errors.New(fmt.Sprintf(
+			"the provided searcher has the same underlying reader version yet the searcher instance differs from before (new=%v vs old=%v)",
+			searcher, tracker.searcher))
+	}
+	return version, nil
+}
+
+/*
+Retrieve a previously recorded IndexSearcher, if it has not yet been
+closed.
+
+NOTE: this may return nil when the requested searcher has already
+timed out. When this happens you should notify your user that their
+session timed out and that they'll have to restart their search.
+
+If this returns a non-nil result, you must match later call Release()
+on this searcher, best from a defer.
+*/
+func (m *SearcherLifetimeManager) Acquire(version int64) (*IndexSearcher, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	if m.closed {
+		return nil, ErrSearcherLifetimeManagerClosed
+	}
+	if tracker, ok := m.searchers[version]; ok && tracker.searcher.IndexReader().TryIncRef() {
+		return tracker.searcher, nil
+	}
+	return nil, nil
+}
+
+/*
+Release a searcher previously obtained from Acquire().
+
+NOTE: it's fine to call this after Close().
+*/
+func (m *SearcherLifetimeManager) Release(s *IndexSearcher) error {
+	return s.IndexReader().DecRef()
+}
+
+// See Prune().
+type Pruner interface {
+	/*
+		Return true if this searcher should be removed. age is how much
+		time has passed since this searcher was the current (live)
+		searcher.
+	*/
+	DoPrune(age time.Duration, searcher *IndexSearcher) bool
+}
+
+/*
+Simple pruner that drops any searcher older by more than the
+specified duration, than the newest searcher.
+*/
+type PruneByAge struct {
+	maxAge time.Duration
+}
+
+func NewPruneByAge(maxAge time.Duration) *PruneByAge {
+	assert2(maxAge >= 0, "maxAge must be > 0 (got %v)", maxAge)
+	return &PruneByAge{maxAge}
+}
+
+func (p *PruneByAge) DoPrune(age time.Duration, searcher *IndexSearcher) bool {
+	return age > p.maxAge
+}
+
+/*
+Calls provided Pruner to prune entries. The entries are passed to the
+Pruner in sorted (newest to oldest IndexSearcher) order.
+
+NOTE: you must periodically call this, ideally from the same
+background goroutine that opens new searchers.
+*/
+func (m *SearcherLifetimeManager) Prune(pruner Pruner) error {
+	m.Lock()
+	defer m.Unlock()
+
+	m.lock.RLock()
+	trackers := make([]*searcherTracker, 0, len(m.searchers))
+	for _, tracker := range m.searchers {
+		trackers = append(trackers, tracker)
+	}
+	m.lock.RUnlock()
+	sort.Sort(searcherTrackersByAge(trackers))
+
+	var lastRecordTime time.Time
+	now := time.Now()
+	for _, tracker := range trackers {
+		// First tracker is always age 0, since it's still "live"; second
+		// tracker's age (= time since it was "live") is now minus first
+		// tracker's recordTime, etc:
+		var age time.Duration
+		if !lastRecordTime.IsZero() {
+			age = now.Sub(lastRecordTime)
+		}
+		if pruner.DoPrune(age, tracker.searcher) {
+			m.lock.Lock()
+			delete(m.searchers, tracker.version)
+			m.lock.Unlock()
+			if err := tracker.Close(); err != nil {
+				return err
+			}
+		}
+		lastRecordTime = tracker.recordTime
+	}
+	return nil
+}
+
+/*
+Close this to future searching; any searches still in process in
+other goroutines won't be affected, and they should still call
+Release() after they are done.
+*/
+func (m *SearcherLifetimeManager) Close() error {
+	m.Lock()
+	defer m.Unlock()
+
+	m.lock.Lock()
+	m.closed = true
+	toClose := make([]*searcherTracker, 0, len(m.searchers))
+	// Remove up front in case of error below, so we don't over-decRef
+	// on double-close:
+	for version, tracker := range m.searchers {
+		toClose = append(toClose, tracker)
+		delete(m.searchers, version)
+	}
+	m.lock.Unlock()
+
+	var firstErr error
+	for _, tracker := range toClose {
+		if err := tracker.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
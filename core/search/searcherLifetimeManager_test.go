@@ -0,0 +1,94 @@
+package search
+
+import (
+	std "github.com/balzaczyy/golucene/analysis/standard"
+	"github.com/balzaczyy/golucene/core/index"
+	"github.com/balzaczyy/golucene/core/store"
+	"github.com/balzaczyy/golucene/core/util"
+	"testing"
+	"time"
+)
+
+func TestSearcherLifetimeManager(t *testing.T) {
+	dir, err := store.OpenFSDirectory(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := index.NewIndexWriter(dir, index.NewIndexWriterConfig(util.VERSION_LATEST, std.NewStandardAnalyzer()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	addDocument(t, w, "first doc")
+	m, err := NewSearcherManagerFromWriter(w, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+	mgr := NewSearcherLifetimeManager()
+
+	s1, err := m.Acquire()
+	if err != nil {
+		t.Fatal(err)
+	}
+	token1, err := mgr.Record(s1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token, err := mgr.Record(s1); token != token1 || err != nil {
+		t.Errorf("Expected the same token %v, but was %v (%v)", token1, token, err)
+	}
+	m.Release(s1)
+
+	addDocument(t, w, "second doc")
+	if err = m.MaybeRefreshBlocking(); err != nil {
+		t.Fatal(err)
+	}
+	s2, err := m.Acquire()
+	if err != nil {
+		t.Fatal(err)
+	}
+	token2, err := mgr.Record(s2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.Release(s2)
+	if token2 == token1 {
+		t.Fatalf("Expected a new token, but was %v", token2)
+	}
+
+	// follow-up page requests see the point-in-time searcher
+	s, err := mgr.Acquire(token1)
+	if err != nil || s != s1 {
+		t.Fatalf("Expected the first searcher, but was %v (%v)", s, err)
+	}
+	assertEquals(t, 1, s.IndexReader().NumDocs())
+	mgr.Release(s)
+	if s, _ = mgr.Acquire(token2); s != s2 {
+		t.Fatalf("Expected the second searcher, but was %v", s)
+	}
+	assertEquals(t, 2, s.IndexReader().NumDocs())
+	mgr.Release(s)
+
+	// the newest searcher is always kept
+	time.Sleep(10 * time.Millisecond)
+	if err = mgr.Prune(NewPruneByAge(time.Millisecond)); err != nil {
+		t.Fatal(err)
+	}
+	if s, _ = mgr.Acquire(token1); s != nil {
+		t.Error("Expected the first searcher to be pruned")
+	}
+	assertEquals(t, 0, s1.IndexReader().RefCount())
+	if s, _ = mgr.Acquire(token2); s != s2 {
+		t.Fatalf("Expected the second searcher, but was %v", s)
+	}
+	mgr.Release(s)
+
+	if err = mgr.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = mgr.Acquire(token2); err != ErrSearcherLifetimeManagerClosed {
+		t.Errorf("Expected closed error, but was %v", err)
+	}
+	assertEquals(t, 1, s2.IndexReader().RefCount())
+}
@@ -0,0 +1,203 @@
+package search
+
+import (
+	"errors"
+	"github.com/balzaczyy/golucene/core/index"
+	"github.com/balzaczyy/golucene/core/store"
+)
+
+// search/SearcherFactory.java
+
+/*
+Factory that creates a new IndexSearcher for SearcherManager. This
+can be used to apply common initialization, e.g. setting a custom
+Similarity, or to warm the new searcher before it is exposed to
+other goroutines, e.g. by running a few queries.
+
+NOTE: NewSearcher() must return an IndexSearcher wrapping exactly the
+provided reader.
+*/
+type SearcherFactory interface {
+	NewSearcher(reader index.IndexReader) (*IndexSearcher, error)
+}
+
+type defaultSearcherFactory struct{}
+
+// Returns a SearcherFactory creating plain IndexSearchers.
+func NewSearcherFactory() SearcherFactory {
+	return defaultSearcherFactory{}
+}
+
+func (f defaultSearcherFactory) NewSearcher(reader index.IndexReader) (*IndexSearcher, error) {
+	return NewIndexSearcher(reader), nil
+}
+
+// search/SearcherManager.java
+
+/*
+Utility type to safely share IndexSearcher instances across multiple
+goroutines, while periodically reopening. This type ensures each
+searcher is closed only once all goroutines have finished using it.
+
+Use Acquire() to obtain the current searcher, and Release() to
+release it, like this:
+
+	s, err := manager.Acquire()
+	if err != nil {
+		return err
+	}
+	defer manager.Release(s)
+	// Do searching, doc retrieval, etc. with s
+
+In addition you should periodically call MaybeRefresh(). While it's
+possible to call this just before running each query, this is
+discouraged since it penalizes the unlucky queries that do the
+reopen. It's better to use a separate background goroutine, that
+periodically calls MaybeRefresh(). Finally, be sure to call Close()
+once you are done.
+*/
+type SearcherManager struct {
+	*ReferenceManager
+	searcherFactory SearcherFactory
+	// if not nil, its changes are committed before each refresh
+	writer *index.IndexWriter
+}
+
+/*
+Creates and returns a new SearcherManager from the given Directory.
+searcherFactory is used to create new IndexSearchers; it may be nil,
+in which case NewSearcherFactory() is used.
+*/
+func NewSearcherManager(dir store.Directory, searcherFactory SearcherFactory) (*SearcherManager, error) {
+	if searcherFactory == nil {
+		searcherFactory = NewSearcherFactory()
+	}
+	reader, err := index.OpenDirectoryReader(dir)
+	if err != nil {
+		return nil, err
+	}
+	searcher, err := newManagedSearcher(searcherFactory, reader)
+	if err != nil {
+		return nil, err
+	}
+	ans := &SearcherManager{searcherFactory: searcherFactory}
+	ans.ReferenceManager = newReferenceManager(ans)
+	ans.current = searcher
+	return ans, nil
+}
+
+/*
+Creates and returns a new SearcherManager from the given IndexWriter,
+so that each refresh makes the latest changes of the writer visible.
+
+NOTE: as IndexWriter cannot open near-real-time readers yet, the
+pending changes of the writer are committed before the searcher is
+opened, and again before each refresh.
+*/
+func NewSearcherManagerFromWriter(writer *index.IndexWriter,
+	searcherFactory SearcherFactory) (*SearcherManager, error) {
+
+	if err := writer.Commit(); err != nil {
+		return nil, err
+	}
+	ans, err := NewSearcherManager(writer.Directory(), searcherFactory)
+	if err != nil {
+		return nil, err
+	}
+	ans.writer = writer
+	return ans, nil
+}
+
+/*
+Obtain the current IndexSearcher. You must match every call to
+Acquire() with one call to Release(); it's best to do so in a defer.
+You must not use the searcher after it has been released.
+*/
+func (m *SearcherManager) Acquire() (*IndexSearcher, error) {
+	ref, err := m.acquire()
+	if err != nil {
+		return nil, err
+	}
+	return ref.(*IndexSearcher), nil
+}
+
+/*
+Release the searcher previously obtained via Acquire().
+
+NOTE: it's safe to call this after Close().
+*/
+func (m *SearcherManager) Release(searcher *IndexSearcher) error {
+	return m.release(searcher)
+}
+
+/*
+Returns true if no changes have occurred since this searcher (i.e.
+its reader) was opened, otherwise false.
+*/
+func (m *SearcherManager) IsSearcherCurrent() (bool, error) {
+	searcher, err := m.Acquire()
+	if err != nil {
+		return false, err
+	}
+	defer m.Release(searcher)
+	r, ok := searcher.IndexReader().(index.DirectoryReader)
+	assert2(ok, "searcher's IndexReader should be a DirectoryReader, but got %v", searcher.IndexReader())
+	return r.IsCurrent(), nil
+}
+
+func (m *SearcherManager) decRef(ref interface{}) error {
+	return ref.(*IndexSearcher).IndexReader().DecRef()
+}
+
+func (m *SearcherManager) refreshIfNeeded(referenceToRefresh interface{}) (interface{}, error) {
+	r, ok := referenceToRefresh.(*IndexSearcher).IndexReader().(index.DirectoryReader)
+	assert2(ok, "searcher's IndexReader should be a DirectoryReader, but got %v",
+		referenceToRefresh.(*IndexSearcher).IndexReader())
+	if m.writer != nil {
+		if err := m.writer.Commit(); err != nil {
+			return nil, err
+		}
+	}
+	newReader, err := index.OpenDirectoryReaderIfChanged(r)
+	if err != nil || newReader == nil {
+		return nil, err
+	}
+	searcher, err := newManagedSearcher(m.searcherFactory, newReader)
+	if err != nil {
+		return nil, err
+	}
+	return searcher, nil
+}
+
+func (m *SearcherManager) tryIncRef(ref interface{}) bool {
+	return ref.(*IndexSearcher).IndexReader().TryIncRef()
+}
+
+func (m *SearcherManager) refCount(ref interface{}) int {
+	return ref.(*IndexSearcher).IndexReader().RefCount()
+}
+
+func (m *SearcherManager) afterClose() error {
+	return nil
+}
+
+func (m *SearcherManager) afterMaybeRefresh() error {
+	return nil
+}
+
+/*
+Expert: creates a searcher from the provided IndexReader using the
+provided SearcherFactory. The reader is released (its refCount is
+decremented) if an error is hit.
+*/
+func newManagedSearcher(searcherFactory SearcherFactory, reader index.IndexReader) (*IndexSearcher, error) {
+	searcher, err := searcherFactory.NewSearcher(reader)
+	if err == nil && searcher.IndexReader() != reader {
+		err = errors.New("SearcherFactory must wrap exactly the provided reader")
+	}
+	if err != nil {
+		reader.DecRef()
+		return nil, err
+	}
+	return searcher, nil
+}
@@ -0,0 +1,129 @@
+package search
+
+import (
+	std "github.com/balzaczyy/golucene/analysis/standard"
+	"github.com/balzaczyy/golucene/core/index"
+	"github.com/balzaczyy/golucene/core/store"
+	"github.com/balzaczyy/golucene/core/util"
+	"testing"
+)
+
+type countingRefreshListener struct {
+	before, refreshed, unchanged int
+}
+
+func (l *countingRefreshListener) BeforeRefresh() error {
+	l.before++
+	return nil
+}
+
+func (l *countingRefreshListener) AfterRefresh(didRefresh bool) error {
+	if didRefresh {
+		l.refreshed++
+	} else {
+		l.unchanged++
+	}
+	return nil
+}
+
+type bm25SearcherFactory struct{}
+
+func (f bm25SearcherFactory) NewSearcher(r index.IndexReader) (*IndexSearcher, error) {
+	ss := NewIndexSearcher(r)
+	ss.SetSimilarity(NewBM25SimilarityWith(2, 0.5))
+	return ss, nil
+}
+
+func TestSearcherManager(t *testing.T) {
+	dir, err := store.OpenFSDirectory(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := index.NewIndexWriter(dir, index.NewIndexWriterConfig(util.VERSION_LATEST, std.NewStandardAnalyzer()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	addDocument(t, w, "first doc")
+	if err = w.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := NewSearcherManager(dir, bm25SearcherFactory{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	listener := &countingRefreshListener{}
+	m.AddListener(listener)
+
+	s1, err := m.Acquire()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEquals(t, 1, s1.IndexReader().NumDocs())
+	assertEquals(t, 2, s1.IndexReader().RefCount())
+	if _, ok := s1.similarity.(*BM25Similarity); !ok {
+		t.Errorf("Expected searcher created by the factory, but was %v", s1.similarity)
+	}
+
+	// nothing changed yet
+	if ok, err := m.MaybeRefresh(); !ok || err != nil {
+		t.Fatalf("Expected refresh, but was %v (%v)", ok, err)
+	}
+	s2, err := m.Acquire()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s2 != s1 {
+		t.Error("Expected the same searcher when the index did not change")
+	}
+	m.Release(s2)
+
+	addDocument(t, w, "second doc")
+	if err = w.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if current, err := m.IsSearcherCurrent(); current || err != nil {
+		t.Errorf("Expected stale searcher after commit, but was %v (%v)", current, err)
+	}
+	if err = m.MaybeRefreshBlocking(); err != nil {
+		t.Fatal(err)
+	}
+	if current, err := m.IsSearcherCurrent(); !current || err != nil {
+		t.Errorf("Expected current searcher after refresh, but was %v (%v)", current, err)
+	}
+	assertEquals(t, 2, listener.before)
+	assertEquals(t, 1, listener.refreshed)
+	assertEquals(t, 1, listener.unchanged)
+
+	s3, err := m.Acquire()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEquals(t, 2, s3.IndexReader().NumDocs())
+	docs, err := s3.SearchTop(NewTermQuery(index.NewTerm("title", "doc")), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEquals(t, 2, docs.TotalHits)
+
+	// the old searcher is still usable until released
+	assertEquals(t, 1, s1.IndexReader().RefCount())
+	if docs, err = s1.SearchTop(NewTermQuery(index.NewTerm("title", "doc")), 10); err != nil {
+		t.Fatal(err)
+	}
+	assertEquals(t, 1, docs.TotalHits)
+	m.Release(s1)
+	assertEquals(t, 0, s1.IndexReader().RefCount())
+
+	if err = m.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = m.Acquire(); err != ErrReferenceManagerClosed {
+		t.Errorf("Expected closed manager, but was %v", err)
+	}
+	// still usable after the manager was closed
+	assertEquals(t, 1, s3.IndexReader().RefCount())
+	m.Release(s3)
+	assertEquals(t, 0, s3.IndexReader().RefCount())
+}
@@ -4,6 +4,13 @@ import (
 	"github.com/balzaczyy/golucene/core/index"
 )
 
+func init() {
+	// IndexWriterConfig picks up the same default as IndexSearcher
+	index.DefaultSimilarity = func() index.Similarity {
+		return NewBM25Similarity()
+	}
+}
+
 // search/similarities/Similarity.java
 
 /*
@@ -16,7 +23,7 @@ to implement an information retrieval model. If you are instead
 looking for a convenient way to alter Lucene's scoring, consider
 extending a high-level implementation such as TFIDFSimilarity, which
 implements the vector space model with this API, or just tweaking the
-default implementation: DefaultSimilarity.
+default implementation: BM25Similarity.
 
 Similarity determines how Lucene weights terms, and Lucene interacts
 with this class at both index-time and query-time.
@@ -104,6 +111,7 @@ type Similarity interface {
 // similarities/PerFieldSimilarityWrapper
 
 type PerFieldSimilarityWrapperSPI interface {
+	// Returns a Similarity for scoring a field.
 	Get(name string) Similarity
 }
 
@@ -113,6 +121,9 @@ fields.
 
 Subclasses should implement Get() to return an appropriate Similarity
 (for example, using field-specific parameter values) for the field.
+The same wrapper can be given to both IndexWriterConfig and
+IndexSearcher, so that norms are encoded and decoded by the same
+Similarity for every field.
 */
 type PerFieldSimilarityWrapper struct {
 	spi PerFieldSimilarityWrapperSPI
@@ -122,6 +133,14 @@ func NewPerFieldSimilarityWrapper(spi PerFieldSimilarityWrapperSPI) *PerFieldSim
 	return &PerFieldSimilarityWrapper{spi: spi}
 }
 
+func (wrapper *PerFieldSimilarityWrapper) Coord(overlap, maxOverlap int) float32 {
+	return 1
+}
+
+func (wrapper *PerFieldSimilarityWrapper) QueryNorm(valueForNormalization float32) float32 {
+	return 1
+}
+
 func (wrapper *PerFieldSimilarityWrapper) ComputeNorm(state *index.FieldInvertState) int64 {
 	return wrapper.spi.Get(state.Name()).ComputeNorm(state)
 }
@@ -133,7 +152,8 @@ func (wrapper *PerFieldSimilarityWrapper) computeWeight(queryBoost float32,
 }
 
 func (wrapper *PerFieldSimilarityWrapper) simScorer(w SimWeight, ctx *index.AtomicReaderContext) (ss SimScorer, err error) {
-	panic("not implemented yet")
+	perFieldWeight := w.(*PerFieldSimWeight)
+	return perFieldWeight.delegate.simScorer(perFieldWeight.delegateWeight, ctx)
 }
 
 type PerFieldSimWeight struct {
@@ -0,0 +1,206 @@
+package search
+
+import (
+	"github.com/balzaczyy/golucene/core/index"
+	"github.com/balzaczyy/golucene/core/store"
+	"math"
+	"strings"
+	"testing"
+)
+
+// Searches 'bat' in the belfry sample with the given similarity, and
+// checks each score against its explanation.
+func searchBat(t *testing.T, sim Similarity) []*ScoreDoc {
+	d, err := store.OpenFSDirectory("testdata/belfrysample")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := index.OpenDirectoryReader(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ss := NewIndexSearcher(r)
+	ss.SetSimilarity(sim)
+	q := NewTermQuery(index.NewTerm("content", "bat"))
+	docs, err := ss.SearchTop(q, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if docs.TotalHits != 8 {
+		t.Fatalf("%v: expected 8 hits, but was %v", sim, docs.TotalHits)
+	}
+	for _, sd := range docs.ScoreDocs {
+		if math.IsNaN(float64(sd.Score)) || math.IsInf(float64(sd.Score), 0) {
+			t.Errorf("%v: invalid score %v for doc %v", sim, sd.Score, sd.Doc)
+		}
+		exp, err := ss.Explain(q, sd.Doc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(float64(exp.Value()-sd.Score)) > 1e-6 {
+			t.Errorf("%v: score %v of doc %v differs from explanation %v",
+				sim, sd.Score, sd.Doc, exp)
+		}
+	}
+	return docs.ScoreDocs
+}
+
+func TestDFRSimilarity(t *testing.T) {
+	basicModels := []BasicModel{
+		NewBasicModelBE(), NewBasicModelD(), NewBasicModelG(), NewBasicModelIF(),
+		NewBasicModelIn(), NewBasicModelIne(), NewBasicModelP(),
+	}
+	afterEffects := []AfterEffect{
+		NewNoAfterEffect(), NewAfterEffectB(), NewAfterEffectL(),
+	}
+	normalizations := []Normalization{
+		NewNoNormalization(), NewNormalizationH1(), NewNormalizationH2(),
+		NewNormalizationH3(), NewNormalizationZ(),
+	}
+	for _, basicModel := range basicModels {
+		for _, afterEffect := range afterEffects {
+			for _, normalization := range normalizations {
+				searchBat(t, NewDFRSimilarity(basicModel, afterEffect, normalization))
+			}
+		}
+	}
+
+	sim := NewDFRSimilarity(NewBasicModelIn(), NewAfterEffectL(), NewNormalizationH1())
+	assertEquals(t, "DFR I(n)L1", sim.String())
+
+	// 'Feeding your bat' has 15 occurrences in 256 tokens, and every
+	// one of the 8 docs has 'bat', 191.625 tokens on average
+	tfn := 15 * 191.625 / 256.0
+	expected := tfn * log2(9/8.5) / (tfn + 1)
+	for _, hit := range searchBat(t, sim) {
+		if hit.Doc == 2 && math.Abs(float64(hit.Score)-expected) > 1e-6 {
+			t.Errorf("Expected score %v, but was %v", expected, hit)
+		}
+	}
+}
+
+func TestLMSimilarity(t *testing.T) {
+	dirichlet := NewLMDirichletSimilarity()
+	assertEquals(t, "LM Dirichlet(2000.000000)", dirichlet.String())
+	for _, hit := range searchBat(t, dirichlet) {
+		if hit.Score < 0 {
+			t.Errorf("Dirichlet score should not be negative: %v", hit)
+		}
+	}
+
+	d, err := store.OpenFSDirectory("testdata/belfrysample")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := index.OpenDirectoryReader(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tc, err := index.NewTermContextFromTerm(r.Context(), index.NewTerm("content", "bat"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jm := NewLMJelinekMercerSimilarity(0.7)
+	assertEquals(t, "LM Jelinek-Mercer(0.700000)", jm.String())
+	// 'Feeding your bat' has 15 occurrences in 256 tokens, out of the
+	// 1533 tokens of the collection
+	p := (float64(tc.TotalTermFreq) + 1) / (1533 + 1)
+	expected := math.Log(1 + (0.3*15/256.0)/(0.7*p))
+	for _, hit := range searchBat(t, jm) {
+		if hit.Doc == 2 && math.Abs(float64(hit.Score)-expected) > 1e-6 {
+			t.Errorf("Expected score %v, but was %v", expected, hit)
+		}
+	}
+}
+
+type titleAndContentSimilarity struct {
+	title, content Similarity
+}
+
+func (sim *titleAndContentSimilarity) Get(name string) Similarity {
+	if name == "title" {
+		return sim.title
+	}
+	return sim.content
+}
+
+func TestPerFieldSimilarityWrapper(t *testing.T) {
+	title := NewBM25SimilarityWith(1.2, 0) // short titles, ignore length
+	content := NewBM25SimilarityWith(2, 0.75)
+	wrapper := NewPerFieldSimilarityWrapper(&titleAndContentSimilarity{title, content})
+
+	// norms are computed by the similarity of the field
+	state := index.NewFieldInvertState("title", 0, 3, 0, 0, 2)
+	assertEquals(t, title.ComputeNorm(state), wrapper.ComputeNorm(state))
+
+	expected := make(map[int]float32)
+	for _, hit := range searchBat(t, content) {
+		expected[hit.Doc] = hit.Score
+	}
+	for _, hit := range searchBat(t, wrapper) {
+		if expected[hit.Doc] != hit.Score {
+			t.Errorf("Expected score %v for doc %v, but was %v",
+				expected[hit.Doc], hit.Doc, hit.Score)
+		}
+	}
+	if len(expected) != 8 {
+		t.Errorf("Expected 8 docs, but was %v", len(expected))
+	}
+}
+
+// Scores a doc by the share of the field taken by the term.
+type termShareSimilarity struct {
+	*SimilarityBase
+}
+
+func newTermShareSimilarity() *termShareSimilarity {
+	ans := &termShareSimilarity{}
+	ans.SimilarityBase = NewSimilarityBase(ans)
+	return ans
+}
+
+func (sim *termShareSimilarity) Score(stats *BasicStats, freq, docLen float32) float32 {
+	return stats.TotalBoost() * freq / docLen
+}
+
+func (sim *termShareSimilarity) String() string {
+	return "TermShare"
+}
+
+func TestSimilarityBase(t *testing.T) {
+	sim := newTermShareSimilarity()
+	for _, hit := range searchBat(t, sim) {
+		// 'Feeding your bat' has 15 occurrences in 256 tokens
+		if hit.Doc == 2 && hit.Score != 15.0/256 {
+			t.Errorf("Expected score %v, but was %v", 15.0/256, hit)
+		}
+	}
+
+	// norms are encoded like the default similarity
+	state := index.NewFieldInvertState("content", 0, 100, 0, 0, 1)
+	assertEquals(t, NewBM25Similarity().ComputeNorm(state), sim.ComputeNorm(state))
+
+	d, err := store.OpenFSDirectory("testdata/belfrysample")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := index.OpenDirectoryReader(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ss := NewIndexSearcher(r)
+	ss.SetSimilarity(sim)
+	q := NewTermQuery(index.NewTerm("content", "bat"))
+	q.SetBoost(2)
+	exp, err := ss.Explain(q, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp.Value() != 2*15.0/256 {
+		t.Errorf("Expected boosted score %v, but was %v", 2*15.0/256, exp)
+	}
+	if s := exp.(*ComplexExplanation).String(); !strings.Contains(s, "score(termShareSimilarity, doc=2, freq=15)") {
+		t.Errorf("Expected explanation of the similarity, but was %v", s)
+	}
+}
@@ -0,0 +1,395 @@
+package search
+
+import (
+	"fmt"
+	. "github.com/balzaczyy/golucene/core/codec/spi"
+	"github.com/balzaczyy/golucene/core/index"
+	"github.com/balzaczyy/golucene/core/util"
+	"math"
+	"reflect"
+)
+
+// search/similarities/SimilarityBase.java
+
+/*
+The scoring function plugged into SimilarityBase. Score() receives
+the statistics of the term, the (sloppy) frequency of the term in the
+document, and the length of the document decoded from the norms, or
+1 if the field omits norms.
+*/
+type SimilarityBaseSPI interface {
+	// Scores the document doc.
+	Score(stats *BasicStats, freq, docLen float32) float32
+	String() string
+}
+
+/*
+Optionally implemented by a SimilarityBaseSPI to explain the score.
+expl already contains the score, the name of the type and the doc id,
+as well as the term frequency and its explanation; implementations
+can add additional clauses to explain details of their scoring
+formulae.
+*/
+type SimilarityBaseExplainer interface {
+	AddExplanation(expl *ExplanationImpl, stats *BasicStats, doc int, freq, docLen float32)
+}
+
+/*
+A Similarity that provides a simplified API for its descendants.
+Descendants are only required to implement the Score() and String()
+methods of SimilarityBaseSPI, and to embed the SimilarityBase
+returned by NewSimilarityBase(). Implementing AddExplanation() is
+optional, inasmuch as SimilarityBase already provides a basic
+explanation of the score and the term frequency. However,
+implementers are encouraged to include as much detail about the
+scoring method as possible.
+
+SimilarityBase takes care of the rest: norms are encoded exactly as
+with BM25Similarity and DefaultSimilarity, the BasicStats of the term
+are collected and boosted by the query, and queries are not
+normalized.
+
+Note: multi-word queries such as phrase queries are scored in a
+different way than Lucene's default ranking algorithm: whereas it
+"fakes" an IDF value for the phrase as a whole (since it does not
+know it), this class instead scores phrases as a summation of the
+individual term scores.
+*/
+type SimilarityBase struct {
+	spi SimilarityBaseSPI
+	// True if overlap tokens (tokens with a position of increment of
+	// zero) are discounted from the document's length.
+	discountOverlaps bool
+}
+
+func NewSimilarityBase(spi SimilarityBaseSPI) *SimilarityBase {
+	return &SimilarityBase{spi: spi, discountOverlaps: true}
+}
+
+/*
+Determines whether overlap tokens (Tokens with 0 position increment)
+are ignored when computing norm. By default this is true, meaning
+overlap tokens do not count when computing norms.
+*/
+func (sim *SimilarityBase) SetDiscountOverlaps(v bool) {
+	sim.discountOverlaps = v
+}
+
+// Returns true if overlap tokens are discounted from the document's
+// length.
+func (sim *SimilarityBase) DiscountOverlaps() bool {
+	return sim.discountOverlaps
+}
+
+func (sim *SimilarityBase) Coord(overlap, maxOverlap int) float32 {
+	return 1
+}
+
+func (sim *SimilarityBase) QueryNorm(valueForNormalization float32) float32 {
+	return 1
+}
+
+func (sim *SimilarityBase) computeWeight(queryBoost float32,
+	collectionStats CollectionStatistics, termStats ...TermStatistics) SimWeight {
+
+	stats := make([]*BasicStats, len(termStats))
+	for i, termStat := range termStats {
+		stats[i] = newBasicStats(collectionStats.field, queryBoost)
+		sim.fillBasicStats(stats[i], collectionStats, termStat)
+	}
+	if len(stats) == 1 {
+		return stats[0]
+	}
+	return multiStats(stats)
+}
+
+// Fills all member fields defined in BasicStats in stats.
+func (sim *SimilarityBase) fillBasicStats(stats *BasicStats,
+	collectionStats CollectionStatistics, termStats TermStatistics) {
+
+	// #positions(field) must be >= #positions(term)
+	assert(collectionStats.sumTotalTermFreq == -1 ||
+		collectionStats.sumTotalTermFreq >= termStats.TotalTermFreq)
+	numberOfDocuments := collectionStats.maxDoc
+
+	docFreq := termStats.DocFreq
+	totalTermFreq := termStats.TotalTermFreq
+
+	// codec does not supply totalTermFreq: substitute docFreq
+	if totalTermFreq == -1 {
+		totalTermFreq = docFreq
+	}
+
+	var numberOfFieldTokens int64
+	var avgFieldLength float32
+
+	if sumTotalTermFreq := collectionStats.sumTotalTermFreq; sumTotalTermFreq <= 0 {
+		// field does not exist; or stat is unsupported by codec; fake
+		// as if the term was the only token
+		numberOfFieldTokens = docFreq
+		avgFieldLength = 1
+	} else {
+		numberOfFieldTokens = sumTotalTermFreq
+		avgFieldLength = float32(numberOfFieldTokens) / float32(numberOfDocuments)
+	}
+
+	// TODO: add sumDocFreq for field (numberOfFieldPostings)
+	stats.numberOfDocuments = numberOfDocuments
+	stats.numberOfFieldTokens = numberOfFieldTokens
+	stats.avgFieldLength = avgFieldLength
+	stats.docFreq = docFreq
+	stats.totalTermFreq = totalTermFreq
+}
+
+/*
+Explains the score. The implementation here provides a basic
+explanation in the format score(name-of-similarity, doc=doc-id,
+freq=term-frequency), computed from:, and attaches the score
+(computed via the Score() method) and the explanation for the term
+frequency. Implementations content with this format may add
+additional details in AddExplanation().
+*/
+func (sim *SimilarityBase) explain(stats *BasicStats, doc int, freq Explanation, docLen float32) Explanation {
+	name := reflect.TypeOf(sim.spi)
+	if name.Kind() == reflect.Ptr {
+		name = name.Elem()
+	}
+	result := newExplanation(sim.spi.Score(stats, freq.Value(), docLen),
+		fmt.Sprintf("score(%v, doc=%v, freq=%v), computed from:",
+			name.Name(), doc, freq.Value()))
+	result.addDetail(freq)
+	if explainer, ok := sim.spi.(SimilarityBaseExplainer); ok {
+		explainer.AddExplanation(result, stats, doc, freq.Value(), docLen)
+	}
+	return result
+}
+
+func (sim *SimilarityBase) simScorer(stats SimWeight, ctx *index.AtomicReaderContext) (SimScorer, error) {
+	reader := ctx.Reader().(index.AtomicReader)
+	if ms, ok := stats.(multiStats); ok {
+		// a multi term query (e.g. phrase). return the summation,
+		// scoring almost as if it were boolean query
+		subScorers := make([]SimScorer, len(ms))
+		for i, subStats := range ms {
+			norms, err := reader.NormValues(subStats.field)
+			if err != nil {
+				return nil, err
+			}
+			subScorers[i] = &basicSimScorer{sim, subStats, norms}
+		}
+		return multiSimScorer(subScorers), nil
+	}
+	basicStats := stats.(*BasicStats)
+	norms, err := reader.NormValues(basicStats.field)
+	if err != nil {
+		return nil, err
+	}
+	return &basicSimScorer{sim, basicStats, norms}, nil
+}
+
+/*
+Encodes the document length in the same way as TFIDFSimilarity and
+BM25Similarity.
+*/
+func (sim *SimilarityBase) ComputeNorm(state *index.FieldInvertState) int64 {
+	numTerms := state.Length()
+	if sim.discountOverlaps {
+		numTerms -= state.NumOverlap()
+	}
+	return sim.encodeNormValue(state.Boost(), float32(numTerms))
+}
+
+// Decodes a normalization factor (document length) stored in an index.
+func (sim *SimilarityBase) decodeNormValue(norm byte) float32 {
+	return SIMILARITY_BASE_NORM_TABLE[norm]
+}
+
+// Encodes the length to a byte via util.FloatToByte315().
+func (sim *SimilarityBase) encodeNormValue(boost, length float32) int64 {
+	return int64(util.FloatToByte315(boost / float32(math.Sqrt(float64(length)))))
+}
+
+// Norm -> document length map.
+var SIMILARITY_BASE_NORM_TABLE []float32 = buildSimilarityBaseNormTable()
+
+func buildSimilarityBaseNormTable() []float32 {
+	table := make([]float32, 256)
+	for i := range table {
+		floatNorm := util.Byte315ToFloat(byte(i))
+		table[i] = 1 / (floatNorm * floatNorm)
+	}
+	return table
+}
+
+// Returns the base two logarithm of x.
+func log2(x float64) float64 {
+	// Put this to a 'util' class if we need more of these.
+	return math.Log(x) / math.Ln2
+}
+
+// Delegates the Score() and explain() methods to SimilarityBase and
+// decodes document lengths from the norms.
+type basicSimScorer struct {
+	owner *SimilarityBase
+	stats *BasicStats
+	norms NumericDocValues
+}
+
+func (ss *basicSimScorer) docLen(doc int) float32 {
+	if ss.norms == nil {
+		return 1
+	}
+	return ss.owner.decodeNormValue(byte(ss.norms(doc)))
+}
+
+func (ss *basicSimScorer) Score(doc int, freq float32) float32 {
+	// We have to supply something in case norms are omitted
+	return ss.owner.spi.Score(ss.stats, freq, ss.docLen(doc))
+}
+
+func (ss *basicSimScorer) computeSlopFactor(distance int) float32 {
+	return 1.0 / float32(distance+1)
+}
+
+func (ss *basicSimScorer) explain(doc int, freq Explanation) Explanation {
+	return ss.owner.explain(ss.stats, doc, freq, ss.docLen(doc))
+}
+
+// search/similarities/MultiSimilarity.java
+
+// Sums the scores of the sub-scorers, one per term of a multi term
+// query.
+type multiSimScorer []SimScorer
+
+func (ss multiSimScorer) Score(doc int, freq float32) float32 {
+	var sum float32
+	for _, subScorer := range ss {
+		sum += subScorer.Score(doc, freq)
+	}
+	return sum
+}
+
+func (ss multiSimScorer) computeSlopFactor(distance int) float32 {
+	return ss[0].computeSlopFactor(distance)
+}
+
+func (ss multiSimScorer) explain(doc int, freq Explanation) Explanation {
+	expl := newExplanation(ss.Score(doc, freq.Value()), "sum of:")
+	for _, subScorer := range ss {
+		expl.addDetail(subScorer.explain(doc, freq))
+	}
+	return expl
+}
+
+type multiStats []*BasicStats
+
+func (stats multiStats) ValueForNormalization() float32 {
+	var sum float32
+	for _, stat := range stats {
+		sum += stat.ValueForNormalization()
+	}
+	return sum / float32(len(stats))
+}
+
+func (stats multiStats) Normalize(queryNorm, topLevelBoost float32) {
+	for _, stat := range stats {
+		stat.Normalize(queryNorm, topLevelBoost)
+	}
+}
+
+// search/similarities/BasicStats.java
+
+// Stores all statistics commonly used ranking methods.
+type BasicStats struct {
+	field string
+	// The number of documents.
+	numberOfDocuments int64
+	// The total number of tokens in the field.
+	numberOfFieldTokens int64
+	// The average field length.
+	avgFieldLength float32
+	// The document frequency.
+	docFreq int64
+	// The total number of occurrences of this term across all
+	// documents.
+	totalTermFreq int64
+
+	// Query's inner boost.
+	queryBoost float32
+	// Any outer query's boost.
+	topLevelBoost float32
+	// For most Similarities, the immediate and the top level query
+	// boosts are not handled differently. Hence, this field is just
+	// the product of the other two.
+	totalBoost float32
+}
+
+func newBasicStats(field string, queryBoost float32) *BasicStats {
+	return &BasicStats{
+		field:      field,
+		queryBoost: queryBoost,
+		totalBoost: queryBoost,
+	}
+}
+
+// Returns the number of documents.
+func (stats *BasicStats) NumberOfDocuments() int64 {
+	return stats.numberOfDocuments
+}
+
+/*
+Returns the total number of tokens in the field. See
+Terms.SumTotalTermFreq().
+*/
+func (stats *BasicStats) NumberOfFieldTokens() int64 {
+	return stats.numberOfFieldTokens
+}
+
+// Returns the average field length.
+func (stats *BasicStats) AvgFieldLength() float32 {
+	return stats.avgFieldLength
+}
+
+// Returns the document frequency.
+func (stats *BasicStats) DocFreq() int64 {
+	return stats.docFreq
+}
+
+// Returns the total number of occurrences of this term across all
+// documents.
+func (stats *BasicStats) TotalTermFreq() int64 {
+	return stats.totalTermFreq
+}
+
+/*
+The square of the raw normalization value. See
+rawNormalizationValue().
+*/
+func (stats *BasicStats) ValueForNormalization() float32 {
+	rawValue := stats.rawNormalizationValue()
+	return rawValue * rawValue
+}
+
+/*
+Computes the raw normalization value. This basic implementation
+returns the query boost. Subclasses may override this method to
+include other factors (such as idf), or to save the value for
+inclusion in normalize(), etc.
+*/
+func (stats *BasicStats) rawNormalizationValue() float32 {
+	return stats.queryBoost
+}
+
+/*
+No normalization is done. topLevelBoost is saved in the object,
+however.
+*/
+func (stats *BasicStats) Normalize(queryNorm, topLevelBoost float32) {
+	stats.topLevelBoost = topLevelBoost
+	stats.totalBoost = stats.queryBoost * topLevelBoost
+}
+
+// Returns the total boost.
+func (stats *BasicStats) TotalBoost() float32 {
+	return stats.totalBoost
+}
@@ -0,0 +1,483 @@
+package search
+
+import (
+	"container/heap"
+	"github.com/balzaczyy/golucene/core/index"
+	. "github.com/balzaczyy/golucene/core/index/model"
+	"math"
+	"sort"
+)
+
+// search/PhrasePositions.java
+
+// Position of a term in a document that takes into account the term
+// offset within the phrase.
+type phrasePositions struct {
+	position int // position in doc
+	count    int // remaining pos in this doc
+	offset   int // position in phrase
+	ord      int // unique across all phrasePositions instances
+	postings DocsAndPositionsEnum
+	rptGroup int // >=0 indicates that this is a repeating pp
+	rptInd   int // index in the rptGroup
+	term     *index.Term
+}
+
+func newPhrasePositions(postings DocsAndPositionsEnum, offset, ord int,
+	term *index.Term) *phrasePositions {
+	return &phrasePositions{
+		offset:   offset,
+		ord:      ord,
+		postings: postings,
+		rptGroup: -1,
+		term:     term,
+	}
+}
+
+func (pp *phrasePositions) firstPosition() (err error) {
+	if pp.count, err = pp.postings.Freq(); err != nil {
+		return err
+	}
+	_, err = pp.nextPosition() // read first pos
+	return err
+}
+
+/*
+Go to next location of this term current document, and set position
+as location - offset, so that a matching exact phrase is easily
+identified when all phrasePositions have exactly the same position.
+*/
+func (pp *phrasePositions) nextPosition() (bool, error) {
+	if pp.count <= 0 {
+		return false, nil
+	}
+	pp.count--
+	pos, err := pp.postings.NextPosition()
+	if err != nil {
+		return false, err
+	}
+	pp.position = pos - pp.offset
+	return true, nil
+}
+
+// search/PhraseQueue.java
+
+type phraseQueue []*phrasePositions
+
+func (pq phraseQueue) Len() int      { return len(pq) }
+func (pq phraseQueue) Swap(i, j int) { pq[i], pq[j] = pq[j], pq[i] }
+
+func (pq phraseQueue) Less(i, j int) bool {
+	pp1, pp2 := pq[i], pq[j]
+	if pp1.position == pp2.position {
+		// same doc and pp.position, so decide by actual term positions.
+		// rely on: pp.position == tp.position - offset.
+		if pp1.offset == pp2.offset {
+			return pp1.ord < pp2.ord
+		}
+		return pp1.offset < pp2.offset
+	}
+	return pp1.position < pp2.position
+}
+
+func (pq *phraseQueue) Push(x interface{}) {
+	*pq = append(*pq, x.(*phrasePositions))
+}
+
+func (pq *phraseQueue) Pop() interface{} {
+	old := *pq
+	n := len(old)
+	ans := old[n-1]
+	*pq = old[:n-1]
+	return ans
+}
+
+func (pq *phraseQueue) add(pp *phrasePositions) { heap.Push(pq, pp) }
+func (pq *phraseQueue) pop() *phrasePositions   { return heap.Pop(pq).(*phrasePositions) }
+func (pq phraseQueue) top() *phrasePositions    { return pq[0] }
+func (pq *phraseQueue) clear()                  { *pq = (*pq)[:0] }
+
+// search/SloppyPhraseMatcher.java
+
+/*
+Find all slop-valid position-combinations (matches) encountered
+while traversing/hopping the phrasePositions. The sloppy frequency
+contribution of a match depends on the distance:
+
+  - highest freq for distance=0 (exact match).
+  - freq gets lower as distance gets higher.
+
+Example: for query "a b"~2, a document "x a b a y" can be matched
+twice: once for "a b" (distance=0), and once for "b a" (distance=2).
+
+Possibly not all valid combinations are encountered, because for
+efficiency we always propagate the least phrasePosition. This allows
+to base on a priority queue and move forward faster. As result, for
+example, document "a b c b a" would score differently for queries
+"a b c"~4 and "c b a"~4, although they really are equivalent.
+Similarly, for doc "a b c b a f g", query "c b"~2 would get same
+score as "g f"~2, although "c b"~2 could be matched twice. We may
+want to fix this in the future (currently not, for performance
+reasons).
+*/
+type SloppyPhraseMatcher struct {
+	phrasePositions []*phrasePositions
+	slop            int
+	pq              phraseQueue // for advancing min position
+	end             int         // current largest phrase position
+
+	hasRpts     bool                 // there are repetitions (as checked in first candidate doc)
+	checkedRpts bool                 // only check for repetitions in first candidate doc
+	rptGroups   [][]*phrasePositions // pps that repeat each other, sorted by offset
+	rptStack    []*phrasePositions   // temporary stack for switching colliding repeating pps
+
+	positioned  bool
+	matchLength int
+}
+
+func newSloppyPhraseMatcher(postings []*postingsAndFreq, slop int) *SloppyPhraseMatcher {
+	ans := &SloppyPhraseMatcher{
+		phrasePositions: make([]*phrasePositions, len(postings)),
+		slop:            slop,
+		pq:              make(phraseQueue, 0, len(postings)),
+	}
+	for i, p := range postings {
+		ans.phrasePositions[i] = newPhrasePositions(p.postings, p.position, i, p.term)
+	}
+	return ans
+}
+
+func (m *SloppyPhraseMatcher) reset() (err error) {
+	m.positioned, err = m.initPhrasePositions()
+	m.matchLength = math.MaxInt32
+	return err
+}
+
+func (m *SloppyPhraseMatcher) sloppyWeight(docScorer SimScorer) float32 {
+	return docScorer.computeSlopFactor(m.matchLength)
+}
+
+func (m *SloppyPhraseMatcher) nextMatch() (bool, error) {
+	if !m.positioned {
+		return false, nil
+	}
+	pp := m.pq.pop()
+	m.matchLength = m.end - pp.position
+	next := m.pq.top().position
+	for {
+		ok, err := m.advancePP(pp)
+		if err != nil {
+			return false, err
+		}
+		if ok && m.hasRpts {
+			if ok, err = m.advanceRpts(pp); err != nil {
+				return false, err
+			}
+		}
+		if !ok { // pps exhausted
+			break
+		}
+		if pp.position > next { // done minimizing current match-length
+			m.pq.add(pp)
+			if m.matchLength <= m.slop {
+				return true, nil
+			}
+			pp = m.pq.pop()
+			next = m.pq.top().position
+			m.matchLength = m.end - pp.position
+		} else if matchLength2 := m.end - pp.position; matchLength2 < m.matchLength {
+			m.matchLength = matchLength2
+		}
+	}
+	m.positioned = false
+	return m.matchLength <= m.slop, nil
+}
+
+// Advances a phrasePositions and updates end; returns false if exhausted.
+func (m *SloppyPhraseMatcher) advancePP(pp *phrasePositions) (bool, error) {
+	ok, err := pp.nextPosition()
+	if !ok || err != nil {
+		return false, err
+	}
+	if pp.position > m.end {
+		m.end = pp.position
+	}
+	return true, nil
+}
+
+/*
+pp was just advanced. If that caused a repeater collision, resolve by
+advancing the lesser of the two colliding pps. Note that there can
+only be one collision, as by the initialization there were no
+collisions before pp was advanced.
+*/
+func (m *SloppyPhraseMatcher) advanceRpts(pp *phrasePositions) (bool, error) {
+	if pp.rptGroup < 0 {
+		return true, nil // not a repeater
+	}
+	group := pp.rptGroup
+	rg := m.rptGroups[group]
+	// pps to re-queue after collisions are resolved
+	requeue := make([]bool, len(rg))
+	numRequeue := 0
+	k0 := pp.rptInd
+	for k := m.collide(pp); k >= 0; k = m.collide(pp) {
+		// always advance the lesser of the (only) two colliding pps
+		pp = m.lesser(pp, rg[k])
+		ok, err := m.advancePP(pp)
+		if !ok || err != nil {
+			return false, err // exhausted
+		}
+		// careful: mark only those currently in the queue
+		if k != k0 && !requeue[k] {
+			requeue[k] = true
+			numRequeue++
+		}
+	}
+	// collisions resolved, now re-queue: empty (partially) the queue
+	// until seeing all pps advanced for resolving collisions
+	n := 0
+	for numRequeue > 0 {
+		pp2 := m.pq.pop()
+		m.rptStack[n] = pp2
+		n++
+		if pp2.rptGroup == group && requeue[pp2.rptInd] {
+			requeue[pp2.rptInd] = false
+			numRequeue--
+		}
+	}
+	// add back to queue
+	for i := n - 1; i >= 0; i-- {
+		m.pq.add(m.rptStack[i])
+	}
+	return true, nil
+}
+
+// Compares two pps, but only by position and offset.
+func (m *SloppyPhraseMatcher) lesser(pp, pp2 *phrasePositions) *phrasePositions {
+	if pp.position < pp2.position ||
+		(pp.position == pp2.position && pp.offset < pp2.offset) {
+		return pp
+	}
+	return pp2
+}
+
+// Index of a pp2 colliding with pp, or -1 if none.
+func (m *SloppyPhraseMatcher) collide(pp *phrasePositions) int {
+	tpPos := m.tpPos(pp)
+	for _, pp2 := range m.rptGroups[pp.rptGroup] {
+		if pp2 != pp && m.tpPos(pp2) == tpPos {
+			return pp2.rptInd
+		}
+	}
+	return -1
+}
+
+/*
+Initializes phrasePositions in place. A one time initialization for
+this scorer (on first doc matching all terms):
+
+  - Check if there are repetitions.
+  - If there are, find groups of repetitions.
+
+Examples:
+
+ 1. no repetitions: "ho my"~2
+ 2. repetitions: "ho my my"~2
+ 3. repetitions: "my ho my"~2
+
+Returns false if pps are exhausted (and so current doc will not be a
+match).
+*/
+func (m *SloppyPhraseMatcher) initPhrasePositions() (bool, error) {
+	m.end = math.MinInt32
+	if !m.checkedRpts {
+		return m.initFirstTime()
+	}
+	if !m.hasRpts {
+		return true, m.initSimple()
+	}
+	return m.initComplex()
+}
+
+/*
+No repeats: simplest case, and most common. It is important to keep
+this piece of the code simple and efficient.
+*/
+func (m *SloppyPhraseMatcher) initSimple() error {
+	m.pq.clear()
+	// position pps and build queue from list
+	for _, pp := range m.phrasePositions {
+		if err := pp.firstPosition(); err != nil {
+			return err
+		}
+		if pp.position > m.end {
+			m.end = pp.position
+		}
+		m.pq.add(pp)
+	}
+	return nil
+}
+
+// With repeats: not so simple.
+func (m *SloppyPhraseMatcher) initComplex() (bool, error) {
+	if err := m.placeFirstPositions(); err != nil {
+		return false, err
+	}
+	if ok, err := m.advanceRepeatGroups(); !ok || err != nil {
+		return false, err // pps exhausted
+	}
+	m.fillQueue()
+	return true, nil
+}
+
+// Moves all pps to their first position.
+func (m *SloppyPhraseMatcher) placeFirstPositions() error {
+	for _, pp := range m.phrasePositions {
+		if err := pp.firstPosition(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Fills the queue (all pps are already placed).
+func (m *SloppyPhraseMatcher) fillQueue() {
+	m.pq.clear()
+	for _, pp := range m.phrasePositions {
+		if pp.position > m.end {
+			m.end = pp.position
+		}
+		m.pq.add(pp)
+	}
+}
+
+/*
+At initialization (each doc), each repetition group is sorted by
+(query) offset. This provides the start condition: no collisions.
+
+It is sufficient to advance each pp in the group by one less than its
+group index. So lesser pp is not advanced, 2nd one advanced once, 3rd
+one advanced twice, etc.
+
+Returns false if pps are exhausted.
+*/
+func (m *SloppyPhraseMatcher) advanceRepeatGroups() (bool, error) {
+	for _, rg := range m.rptGroups {
+		for j := 1; j < len(rg); j++ {
+			for k := 0; k < j; k++ {
+				if ok, err := rg[j].nextPosition(); !ok || err != nil {
+					return false, err // pps exhausted
+				}
+			}
+		}
+	}
+	return true, nil
+}
+
+/*
+Initializes with checking for repeats. Heavy work, but done only for
+the first candidate doc. Once pps are placed in the first candidate
+doc, repeats (and groups) are visible.
+*/
+func (m *SloppyPhraseMatcher) initFirstTime() (bool, error) {
+	m.checkedRpts = true
+	if err := m.placeFirstPositions(); err != nil {
+		return false, err
+	}
+
+	rptTerms := m.repeatingTerms()
+	m.hasRpts = len(rptTerms) > 0
+
+	if m.hasRpts {
+		m.rptStack = make([]*phrasePositions, len(m.phrasePositions)) // needed with repetitions
+		m.sortRptGroups(m.gatherRptGroups(rptTerms))
+		if ok, err := m.advanceRepeatGroups(); !ok || err != nil {
+			return false, err // pps exhausted
+		}
+	}
+
+	m.fillQueue()
+	return true, nil
+}
+
+type phrasePositionsByOffset []*phrasePositions
+
+func (s phrasePositionsByOffset) Len() int           { return len(s) }
+func (s phrasePositionsByOffset) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s phrasePositionsByOffset) Less(i, j int) bool { return s[i].offset < s[j].offset }
+
+/*
+Sorts each repetition group by (query) offset. Done only once (at
+first doc) and allows to initialize faster for each doc.
+*/
+func (m *SloppyPhraseMatcher) sortRptGroups(rgs [][]*phrasePositions) {
+	m.rptGroups = rgs
+	for _, rg := range rgs {
+		sort.Stable(phrasePositionsByOffset(rg))
+		for j, pp := range rg {
+			pp.rptInd = j // we use this index for efficient re-queuing
+		}
+	}
+}
+
+// Detects repetition groups. Done once - for first doc.
+func (m *SloppyPhraseMatcher) gatherRptGroups(rptTerms map[string]bool) [][]*phrasePositions {
+	rpp := m.repeatingPPs(rptTerms)
+	var res [][]*phrasePositions
+	// no multi-terms, so we can base on positions in first doc
+	for i, pp := range rpp {
+		if pp.rptGroup >= 0 {
+			continue // already marked as a repetition
+		}
+		tpPos := m.tpPos(pp)
+		for _, pp2 := range rpp[i+1:] {
+			if pp2.rptGroup >= 0 || // already marked as a repetition
+				pp2.offset == pp.offset || // two pps are originally in same offset in the query
+				m.tpPos(pp2) != tpPos { // not a repetition
+				continue
+			}
+			// a repetition
+			g := pp.rptGroup
+			if g < 0 {
+				g = len(res)
+				pp.rptGroup = g
+				res = append(res, []*phrasePositions{pp})
+			}
+			pp2.rptGroup = g
+			res[g] = append(res[g], pp2)
+		}
+	}
+	return res
+}
+
+// Actual position in doc of a phrasePositions, relies on that
+// position = tpPos - offset.
+func (m *SloppyPhraseMatcher) tpPos(pp *phrasePositions) int {
+	return pp.position + pp.offset
+}
+
+// Finds repeating terms.
+func (m *SloppyPhraseMatcher) repeatingTerms() map[string]bool {
+	tcnt := make(map[string]int)
+	ans := make(map[string]bool)
+	for _, pp := range m.phrasePositions {
+		key := string(pp.term.Bytes)
+		tcnt[key]++
+		if tcnt[key] == 2 {
+			ans[key] = true
+		}
+	}
+	return ans
+}
+
+// Finds repeating pps, in query order.
+func (m *SloppyPhraseMatcher) repeatingPPs(rptTerms map[string]bool) []*phrasePositions {
+	var ans []*phrasePositions
+	for _, pp := range m.phrasePositions {
+		if rptTerms[string(pp.term.Bytes)] {
+			ans = append(ans, pp)
+		}
+	}
+	return ans
+}
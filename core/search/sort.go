@@ -0,0 +1,242 @@
+package search
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// search/SortField.java
+
+// Specifies the type of the terms to be sorted, or special types such
+// as relevancy or document index order.
+type SortFieldType int
+
+const (
+	// Sort by document score (relevance). Sort values are float32 and
+	// higher values are at the front.
+	SORT_FIELD_SCORE SortFieldType = iota
+	// Sort by document number (index order). Sort values are int and
+	// lower values are at the front.
+	SORT_FIELD_DOC
+	// Sort using term values as strings. Sort values are string and
+	// lower values are at the front.
+	SORT_FIELD_STRING
+	// Sort using term values as encoded integers. Sort values are int
+	// and lower values are at the front.
+	SORT_FIELD_INT
+	// Sort using term values as encoded longs. Sort values are int64
+	// and lower values are at the front.
+	SORT_FIELD_LONG
+	// Sort using term values as encoded floats. Sort values are float32
+	// and lower values are at the front.
+	SORT_FIELD_FLOAT
+	// Sort using term values as encoded doubles. Sort values are
+	// float64 and lower values are at the front.
+	SORT_FIELD_DOUBLE
+	// Sort using the values of a DoubleValuesSource. Sort values are
+	// float64 and lower values are at the front.
+	SORT_FIELD_CUSTOM
+)
+
+func (t SortFieldType) String() string {
+	switch t {
+	case SORT_FIELD_SCORE:
+		return "SCORE"
+	case SORT_FIELD_DOC:
+		return "DOC"
+	case SORT_FIELD_STRING:
+		return "STRING"
+	case SORT_FIELD_INT:
+		return "INT"
+	case SORT_FIELD_LONG:
+		return "LONG"
+	case SORT_FIELD_FLOAT:
+		return "FLOAT"
+	case SORT_FIELD_DOUBLE:
+		return "DOUBLE"
+	case SORT_FIELD_CUSTOM:
+		return "CUSTOM"
+	}
+	panic(fmt.Sprintf("invalid sort field type: %v", int(t)))
+}
+
+/*
+Stores information about how to sort documents by terms in an
+individual field. Fields must be indexed in order to sort by them.
+*/
+type SortField struct {
+	field   string
+	typ     SortFieldType
+	reverse bool
+	// values of SORT_FIELD_CUSTOM
+	source DoubleValuesSource
+	// value of the docs without one, nil by default
+	missingValue interface{}
+}
+
+// Represents sorting by document score (relevance).
+var FIELD_SCORE = NewSortField("", SORT_FIELD_SCORE, false)
+
+// Represents sorting by document number (index order).
+var FIELD_DOC = NewSortField("", SORT_FIELD_DOC, false)
+
+/*
+Creates a sort, possibly in reverse, by terms in the given field with
+the type of term values explicitly given. field can be "" if typ is
+SORT_FIELD_SCORE or SORT_FIELD_DOC.
+*/
+func NewSortField(field string, typ SortFieldType, reverse bool) *SortField {
+	assert2(field != "" || typ == SORT_FIELD_SCORE || typ == SORT_FIELD_DOC,
+		"field can only be empty when type is SCORE or DOC")
+	assert2(typ != SORT_FIELD_CUSTOM, "use NewDoubleValuesSortField() for custom sorts")
+	return &SortField{field: field, typ: typ, reverse: reverse}
+}
+
+// Returns the name of the field. Could return "" if the sort is by
+// SCORE or DOC.
+func (sf *SortField) Field() string {
+	return sf.field
+}
+
+// Returns the type of contents in the field.
+func (sf *SortField) Type() SortFieldType {
+	return sf.typ
+}
+
+// Returns whether the sort should be reversed.
+func (sf *SortField) Reverse() bool {
+	return sf.reverse
+}
+
+func (sf *SortField) String() string {
+	var buf bytes.Buffer
+	switch sf.typ {
+	case SORT_FIELD_SCORE:
+		buf.WriteString("<score>")
+	case SORT_FIELD_DOC:
+		buf.WriteString("<doc>")
+	case SORT_FIELD_CUSTOM:
+		fmt.Fprintf(&buf, "<custom:\"%v\": %v>", sf.field, sf.source)
+	default:
+		fmt.Fprintf(&buf, "<%v: \"%v\">", strings.ToLower(sf.typ.String()), sf.field)
+	}
+	if sf.reverse {
+		buf.WriteRune('!')
+	}
+	return buf.String()
+}
+
+/*
+Compares two sort values of this field, taking the reverse flag into
+account: returns a negative number if a sorts before b, a positive
+number if it sorts after, and 0 if they are equal. Missing (nil)
+values sort before any other value.
+*/
+func (sf *SortField) compareValues(a, b interface{}) int {
+	var cmp int
+	switch {
+	case a == nil && b == nil:
+		cmp = 0
+	case a == nil:
+		cmp = -1
+	case b == nil:
+		cmp = 1
+	default:
+		switch sf.typ {
+		case SORT_FIELD_SCORE:
+			// higher scores first
+			cmp = compareFloat64(float64(b.(float32)), float64(a.(float32)))
+		case SORT_FIELD_DOC:
+			cmp = a.(int) - b.(int)
+		case SORT_FIELD_STRING:
+			cmp = strings.Compare(a.(string), b.(string))
+		case SORT_FIELD_INT:
+			cmp = compareInt64(int64(a.(int)), int64(b.(int)))
+		case SORT_FIELD_LONG:
+			cmp = compareInt64(a.(int64), b.(int64))
+		case SORT_FIELD_FLOAT:
+			cmp = compareFloat64(float64(a.(float32)), float64(b.(float32)))
+		case SORT_FIELD_DOUBLE, SORT_FIELD_CUSTOM:
+			cmp = compareFloat64(a.(float64), b.(float64))
+		default:
+			panic(fmt.Sprintf("invalid sort field type: %v", sf.typ))
+		}
+	}
+	if sf.reverse {
+		return -cmp
+	}
+	return cmp
+}
+
+func compareInt64(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func compareFloat64(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// search/Sort.java
+
+/*
+Encapsulates sort criteria for returned hits.
+
+The fields used to determine sort order must be carefully chosen.
+Documents must contain a single term in such a field, and the value
+of the term should indicate the document's relative position in a
+given sort order.
+*/
+type Sort struct {
+	fields []*SortField
+}
+
+/*
+Represents sorting by computed relevance. Using this sort criteria
+returns the same results as calling IndexSearcher.Search() without a
+sort criteria, only with slightly more overhead.
+*/
+var SORT_RELEVANCE = NewSort(FIELD_SCORE)
+
+// Represents sorting by index order.
+var SORT_INDEXORDER = NewSort(FIELD_DOC)
+
+/*
+Sets the sort to the given criteria in succession: the first
+SortField is checked first, but if it produces a tie, then the
+second SortField is used to break the tie, etc. Finally, if there is
+still a tie after all SortFields are checked, the internal Lucene
+docid is used to break it.
+*/
+func NewSort(fields ...*SortField) *Sort {
+	assert2(len(fields) > 0, "There must be at least 1 sort field")
+	return &Sort{fields}
+}
+
+// Representation of the sort criteria.
+func (s *Sort) Fields() []*SortField {
+	return s.fields
+}
+
+func (s *Sort) String() string {
+	var buf bytes.Buffer
+	for i, field := range s.fields {
+		if i > 0 {
+			buf.WriteRune(',')
+		}
+		buf.WriteString(field.String())
+	}
+	return buf.String()
+}
@@ -0,0 +1,143 @@
+package search
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/balzaczyy/golucene/core/index"
+	"github.com/balzaczyy/golucene/core/util"
+)
+
+// search/spans/SpanNearQuery.java
+
+/*
+Matches spans which are near one another. One can specify slop, the
+maximum number of intervening unmatched positions, as well as whether
+matches are required to be in-order.
+*/
+type SpanNearQuery struct {
+	*AbstractQuery
+	clauses []SpanQuery
+	slop    int
+	inOrder bool
+	field   string
+}
+
+/*
+Construct a SpanNearQuery. Matches spans matching a span from each
+clause, with up to slop total unmatched positions between them. When
+inOrder is true, the spans from each clause must be in the same order
+as in clauses and must be non-overlapping. When inOrder is false, the
+spans from each clause need not be ordered and may overlap.
+*/
+func NewSpanNearQuery(clauses []SpanQuery, slop int, inOrder bool) *SpanNearQuery {
+	ans := &SpanNearQuery{
+		clauses: make([]SpanQuery, len(clauses)),
+		slop:    slop,
+		inOrder: inOrder,
+	}
+	for i, clause := range clauses {
+		if i == 0 {
+			ans.field = clause.Field()
+		} else if clause.Field() != "" && clause.Field() != ans.field {
+			panic("Clauses must have same field.")
+		}
+		ans.clauses[i] = clause
+	}
+	ans.AbstractQuery = NewAbstractQuery(ans)
+	return ans
+}
+
+// Return the clauses whose spans are matched.
+func (q *SpanNearQuery) Clauses() []SpanQuery {
+	return q.clauses
+}
+
+// Return the maximum number of intervening unmatched positions permitted.
+func (q *SpanNearQuery) Slop() int {
+	return q.slop
+}
+
+// Return true if matches are required to be in-order.
+func (q *SpanNearQuery) IsInOrder() bool {
+	return q.inOrder
+}
+
+func (q *SpanNearQuery) Field() string {
+	return q.field
+}
+
+func (q *SpanNearQuery) CreateWeight(ss *IndexSearcher) (Weight, error) {
+	return newSpanWeight(q, ss)
+}
+
+func (q *SpanNearQuery) Rewrite(reader index.IndexReader) Query {
+	var clone *SpanNearQuery
+	for i, clause := range q.clauses {
+		if query := clause.Rewrite(reader).(SpanQuery); query != clause {
+			if clone == nil {
+				clone = NewSpanNearQuery(q.clauses, q.slop, q.inOrder)
+				clone.SetBoost(q.boost)
+			}
+			clone.clauses[i] = query
+		}
+	}
+	if clone != nil {
+		return clone // some clauses rewrote
+	}
+	return q // no clauses rewrote
+}
+
+func (q *SpanNearQuery) ExtractTerms(terms *index.TermSet) {
+	for _, clause := range q.clauses {
+		clause.ExtractTerms(terms)
+	}
+}
+
+func (q *SpanNearQuery) Visit(visitor QueryVisitor) {
+	if !visitor.AcceptField(q.field) {
+		return
+	}
+	if sub := visitor.SubVisitor(MUST, q); sub != nil {
+		for _, clause := range q.clauses {
+			clause.Visit(sub)
+		}
+	}
+}
+
+func (q *SpanNearQuery) spans(context *index.AtomicReaderContext, acceptDocs util.Bits,
+	termContexts map[termKey]*index.TermContext) (Spans, error) {
+
+	subSpans := make([]Spans, len(q.clauses))
+	for i, clause := range q.clauses {
+		spans, err := clause.spans(context, acceptDocs, termContexts)
+		if spans == nil || err != nil {
+			return nil, err // all required
+		}
+		subSpans[i] = spans
+	}
+
+	// all NearSpans require at least two subSpans
+	if len(subSpans) == 1 {
+		return subSpans[0], nil
+	}
+	if q.inOrder {
+		return newNearSpansOrdered(q.slop, subSpans), nil
+	}
+	return newNearSpansUnordered(q.slop, subSpans), nil
+}
+
+func (q *SpanNearQuery) ToString(field string) string {
+	var buf bytes.Buffer
+	buf.WriteString("spanNear([")
+	for i, clause := range q.clauses {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(clause.ToString(field))
+	}
+	fmt.Fprintf(&buf, "], %v, %v)", q.slop, q.inOrder)
+	if q.boost != 1 {
+		fmt.Fprintf(&buf, "^%v", q.boost)
+	}
+	return buf.String()
+}
@@ -0,0 +1,261 @@
+package search
+
+import (
+	"bytes"
+	"container/heap"
+	"fmt"
+	"github.com/balzaczyy/golucene/core/index"
+	. "github.com/balzaczyy/golucene/core/search/model"
+	"github.com/balzaczyy/golucene/core/util"
+)
+
+// search/spans/SpanOrQuery.java
+
+/* Matches the union of its clauses. */
+type SpanOrQuery struct {
+	*AbstractQuery
+	clauses []SpanQuery
+	field   string
+}
+
+// Construct a SpanOrQuery merging the provided clauses. All clauses must have the same field.
+func NewSpanOrQuery(clauses ...SpanQuery) *SpanOrQuery {
+	ans := &SpanOrQuery{clauses: make([]SpanQuery, len(clauses))}
+	for i, clause := range clauses {
+		if i == 0 {
+			ans.field = clause.Field()
+		} else if clause.Field() != "" && clause.Field() != ans.field {
+			panic("Clauses must have same field.")
+		}
+		ans.clauses[i] = clause
+	}
+	ans.AbstractQuery = NewAbstractQuery(ans)
+	return ans
+}
+
+// Return the clauses whose spans are matched.
+func (q *SpanOrQuery) Clauses() []SpanQuery {
+	return q.clauses
+}
+
+func (q *SpanOrQuery) Field() string {
+	return q.field
+}
+
+func (q *SpanOrQuery) CreateWeight(ss *IndexSearcher) (Weight, error) {
+	return newSpanWeight(q, ss)
+}
+
+func (q *SpanOrQuery) Rewrite(reader index.IndexReader) Query {
+	var clone *SpanOrQuery
+	for i, clause := range q.clauses {
+		if query := clause.Rewrite(reader).(SpanQuery); query != clause {
+			if clone == nil {
+				clone = NewSpanOrQuery(q.clauses...)
+				clone.SetBoost(q.boost)
+			}
+			clone.clauses[i] = query
+		}
+	}
+	if clone != nil {
+		return clone // some clauses rewrote
+	}
+	return q // no clauses rewrote
+}
+
+func (q *SpanOrQuery) ExtractTerms(terms *index.TermSet) {
+	for _, clause := range q.clauses {
+		clause.ExtractTerms(terms)
+	}
+}
+
+func (q *SpanOrQuery) Visit(visitor QueryVisitor) {
+	if !visitor.AcceptField(q.field) {
+		return
+	}
+	if sub := visitor.SubVisitor(SHOULD, q); sub != nil {
+		for _, clause := range q.clauses {
+			clause.Visit(sub)
+		}
+	}
+}
+
+func (q *SpanOrQuery) spans(context *index.AtomicReaderContext, acceptDocs util.Bits,
+	termContexts map[termKey]*index.TermContext) (Spans, error) {
+
+	var subSpans []Spans
+	for _, clause := range q.clauses {
+		spans, err := clause.spans(context, acceptDocs, termContexts)
+		if err != nil {
+			return nil, err
+		}
+		if spans != nil {
+			subSpans = append(subSpans, spans)
+		}
+	}
+
+	switch len(subSpans) {
+	case 0:
+		return nil, nil
+	case 1:
+		return subSpans[0], nil
+	}
+	return newOrSpans(subSpans), nil
+}
+
+func (q *SpanOrQuery) ToString(field string) string {
+	var buf bytes.Buffer
+	buf.WriteString("spanOr([")
+	for i, clause := range q.clauses {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(clause.ToString(field))
+	}
+	buf.WriteString("])")
+	if q.boost != 1 {
+		fmt.Fprintf(&buf, "^%v", q.boost)
+	}
+	return buf.String()
+}
+
+/*
+The union of the sub-spans: the docs of any of them, and within a doc
+their positions merged by start then end position.
+*/
+type orSpans struct {
+	subSpans []Spans
+	doc      int
+	// the sub-spans positioned in the current doc, nil until
+	// NextStartPosition() is called on it
+	byPosition *spansByPosition
+}
+
+func newOrSpans(subSpans []Spans) *orSpans {
+	return &orSpans{subSpans: subSpans, doc: -1}
+}
+
+func (s *orSpans) DocId() int {
+	return s.doc
+}
+
+func (s *orSpans) NextDoc() (int, error) {
+	return s.toDoc(func(spans Spans) (int, error) {
+		if spans.DocId() > s.doc {
+			return spans.DocId(), nil
+		}
+		return spans.NextDoc()
+	})
+}
+
+func (s *orSpans) Advance(target int) (int, error) {
+	return s.toDoc(func(spans Spans) (int, error) {
+		if spans.DocId() >= target {
+			return spans.DocId(), nil
+		}
+		return spans.Advance(target)
+	})
+}
+
+/* Moves the sub-spans with advance, then goes to the smallest doc. */
+func (s *orSpans) toDoc(advance func(Spans) (int, error)) (int, error) {
+	doc := NO_MORE_DOCS
+	for _, spans := range s.subSpans {
+		d, err := advance(spans)
+		if err != nil {
+			return 0, err
+		}
+		doc = min(doc, d)
+	}
+	s.doc, s.byPosition = doc, nil
+	return doc, nil
+}
+
+func (s *orSpans) NextStartPosition() (int, error) {
+	if s.byPosition == nil {
+		s.byPosition = new(spansByPosition)
+		for _, spans := range s.subSpans {
+			if spans.DocId() != s.doc {
+				continue
+			}
+			if _, err := spans.NextStartPosition(); err != nil {
+				return 0, err
+			}
+			heap.Push(s.byPosition, spans)
+		}
+	} else if s.byPosition.Len() > 0 {
+		top := (*s.byPosition)[0]
+		position, err := top.NextStartPosition()
+		if err != nil {
+			return 0, err
+		}
+		if position == NO_MORE_POSITIONS {
+			heap.Pop(s.byPosition)
+		} else {
+			heap.Fix(s.byPosition, 0)
+		}
+	}
+	return s.StartPosition(), nil
+}
+
+/* Returns the sub-spans at the current position, or nil. */
+func (s *orSpans) current() Spans {
+	if s.byPosition == nil || s.byPosition.Len() == 0 {
+		return nil
+	}
+	return (*s.byPosition)[0]
+}
+
+func (s *orSpans) StartPosition() int {
+	if s.byPosition == nil {
+		return -1
+	} else if top := s.current(); top != nil {
+		return top.StartPosition()
+	}
+	return NO_MORE_POSITIONS
+}
+
+func (s *orSpans) EndPosition() int {
+	if s.byPosition == nil {
+		return -1
+	} else if top := s.current(); top != nil {
+		return top.EndPosition()
+	}
+	return NO_MORE_POSITIONS
+}
+
+func (s *orSpans) Width() int {
+	return s.current().Width()
+}
+
+func (s *orSpans) Collect(collector SpanCollector) error {
+	return s.current().Collect(collector)
+}
+
+func (s *orSpans) String() string {
+	return fmt.Sprintf("spanOr(%v)@%v: %v - %v", s.subSpans, s.doc, s.StartPosition(), s.EndPosition())
+}
+
+// Orders the sub-spans of a doc by start, then end position.
+type spansByPosition []Spans
+
+func (pq spansByPosition) Len() int      { return len(pq) }
+func (pq spansByPosition) Swap(i, j int) { pq[i], pq[j] = pq[j], pq[i] }
+
+func (pq spansByPosition) Less(i, j int) bool {
+	if pq[i].StartPosition() != pq[j].StartPosition() {
+		return pq[i].StartPosition() < pq[j].StartPosition()
+	}
+	return pq[i].EndPosition() < pq[j].EndPosition()
+}
+
+func (pq *spansByPosition) Push(x interface{}) {
+	*pq = append(*pq, x.(Spans))
+}
+
+func (pq *spansByPosition) Pop() interface{} {
+	n := len(*pq) - 1
+	ans := (*pq)[n]
+	*pq = (*pq)[:n]
+	return ans
+}
@@ -0,0 +1,294 @@
+package search
+
+import (
+	"fmt"
+	"github.com/balzaczyy/golucene/core/index"
+	"github.com/balzaczyy/golucene/core/util"
+	"reflect"
+)
+
+// search/spans/SpanQuery.java
+
+/*
+Base interface for span-based queries. A SpanQuery matches spans of
+term positions within a single field, so span queries can be nested
+to express proximity constraints, e.g. with SpanNearQuery.
+*/
+type SpanQuery interface {
+	Query
+	// Returns the name of the field matched by this query.
+	Field() string
+	/*
+		Expert: returns the matches of this query in the given segment,
+		or nil if there are none. termContexts holds the states of the
+		terms extracted from the top-level query.
+	*/
+	spans(context *index.AtomicReaderContext, acceptDocs util.Bits,
+		termContexts map[termKey]*index.TermContext) (Spans, error)
+}
+
+// Identifies a term by value, as Term itself cannot be used as map key.
+type termKey struct {
+	field, text string
+}
+
+func newTermKey(t *index.Term) termKey {
+	return termKey{t.Field, string(t.Bytes)}
+}
+
+// search/spans/SpanWeight.java
+
+/*
+Expert: the Weight for SpanQuery, used to normalize, score and
+explain these queries. The terms of the query are scored as a whole,
+like the ones of a phrase.
+*/
+type SpanWeight struct {
+	*WeightImpl
+	query        SpanQuery
+	similarity   Similarity
+	stats        SimWeight
+	termContexts map[termKey]*index.TermContext
+}
+
+func newSpanWeight(query SpanQuery, ss *IndexSearcher) (*SpanWeight, error) {
+	terms := index.NewTermSet()
+	query.ExtractTerms(terms)
+	ctx := ss.TopReaderContext()
+	termContexts := make(map[termKey]*index.TermContext)
+	termStats := make([]TermStatistics, len(terms.Terms))
+	for i, term := range terms.Terms {
+		state, err := index.NewTermContextFromTerm(ctx, term)
+		if err != nil {
+			return nil, err
+		}
+		termStats[i] = ss.spi.TermStatistics(term, state)
+		termContexts[newTermKey(term)] = state
+	}
+	ans := &SpanWeight{
+		query:        query,
+		similarity:   ss.similarity,
+		termContexts: termContexts,
+	}
+	if field := query.Field(); field != "" {
+		ans.stats = ans.similarity.computeWeight(query.Boost(),
+			ss.spi.CollectionStatistics(field), termStats...)
+	}
+	ans.WeightImpl = newWeightImpl(ans)
+	return ans, nil
+}
+
+func (w *SpanWeight) String() string {
+	return fmt.Sprintf("weight(%v)", w.query)
+}
+
+func (w *SpanWeight) ValueForNormalization() float32 {
+	if w.stats == nil {
+		return 1
+	}
+	return w.stats.ValueForNormalization()
+}
+
+func (w *SpanWeight) Normalize(norm float32, topLevelBoost float32) {
+	if w.stats != nil {
+		w.stats.Normalize(norm, topLevelBoost)
+	}
+}
+
+func (w *SpanWeight) IsScoresDocsOutOfOrder() bool {
+	return false
+}
+
+func (w *SpanWeight) Scorer(context *index.AtomicReaderContext,
+	acceptDocs util.Bits) (Scorer, error) {
+
+	scorer, err := w.spanScorer(context, acceptDocs)
+	if scorer == nil || err != nil {
+		return nil, err
+	}
+	return scorer, nil
+}
+
+func (w *SpanWeight) spanScorer(context *index.AtomicReaderContext,
+	acceptDocs util.Bits) (*SpanScorer, error) {
+
+	if w.stats == nil {
+		return nil, nil
+	}
+	spans, err := w.query.spans(context, acceptDocs, w.termContexts)
+	if spans == nil || err != nil {
+		return nil, err
+	}
+	docScorer, err := w.similarity.simScorer(w.stats, context)
+	if err != nil {
+		return nil, err
+	}
+	return newSpanScorer(w, spans, docScorer), nil
+}
+
+func (w *SpanWeight) Explain(context *index.AtomicReaderContext, doc int) (Explanation, error) {
+	scorer, err := w.spanScorer(context, context.Reader().(index.AtomicReader).LiveDocs())
+	if err != nil {
+		return nil, err
+	}
+	if scorer != nil {
+		newDoc, err := scorer.Advance(doc)
+		if err != nil {
+			return nil, err
+		}
+		if newDoc == doc {
+			freq, err := scorer.sloppyFreq()
+			if err != nil {
+				return nil, err
+			}
+			scoreExplanation := scorer.docScorer.explain(doc,
+				newExplanation(freq, fmt.Sprintf("phraseFreq=%v", freq)))
+			ans := newComplexExplanation(true,
+				scoreExplanation.(*ExplanationImpl).value,
+				fmt.Sprintf("weight(%v in %v) [%v], result of:",
+					w.query, doc, reflect.TypeOf(w.similarity)))
+			ans.details = []Explanation{scoreExplanation}
+			return ans, nil
+		}
+	}
+	return newComplexExplanation(false, 0, "no matching term"), nil
+}
+
+// search/spans/SpanScorer.java
+
+/*
+Hooks a scorer of span queries calls while enumerating the spans of
+current doc, so that extra information, e.g. payloads, can be
+collected from them.
+*/
+type spanScorerSPI interface {
+	// Called before the current doc's frequency is calculated.
+	doStartCurrentDoc()
+	// Called each time the scorer's Spans is advanced during frequency
+	// calculation.
+	doCurrentSpans() error
+	// Scores the current doc once its frequency was calculated.
+	scoreCurrentDoc() (float32, error)
+}
+
+/*
+A basic Scorer over Spans. The frequency of a doc is the sum of the
+slop factors of the widths of all its spans.
+*/
+type SpanScorer struct {
+	*abstractScorer
+	spi           spanScorerSPI
+	spans         Spans
+	docScorer     SimScorer
+	freq          float32 // accumulated sloppy freq (computed in setFreqCurrentDoc)
+	numMatches    int     // number of matches (computed in setFreqCurrentDoc)
+	lastScoredDoc int     // last doc we called setFreqCurrentDoc() for
+}
+
+func newSpanScorer(w Weight, spans Spans, docScorer SimScorer) *SpanScorer {
+	ans := &SpanScorer{
+		spans:         spans,
+		docScorer:     docScorer,
+		lastScoredDoc: -1,
+	}
+	ans.spi = ans
+	ans.abstractScorer = newScorer(ans, w)
+	return ans
+}
+
+func (s *SpanScorer) DocId() int {
+	return s.spans.DocId()
+}
+
+func (s *SpanScorer) NextDoc() (int, error) {
+	return s.spans.NextDoc()
+}
+
+func (s *SpanScorer) Advance(target int) (int, error) {
+	return s.spans.Advance(target)
+}
+
+// Ensures that setFreqCurrentDoc() is called, if not already called
+// for the current doc.
+func (s *SpanScorer) ensureFreq() error {
+	if doc := s.spans.DocId(); s.lastScoredDoc != doc {
+		if err := s.setFreqCurrentDoc(); err != nil {
+			return err
+		}
+		s.lastScoredDoc = doc
+	}
+	return nil
+}
+
+// Sets freq and numMatches for the current document.
+func (s *SpanScorer) setFreqCurrentDoc() error {
+	s.freq = 0
+	s.numMatches = 0
+
+	s.spi.doStartCurrentDoc()
+
+	assert2(s.spans.StartPosition() == -1, "incorrect initial start position, %v", s.spans)
+	assert2(s.spans.EndPosition() == -1, "incorrect initial end position, %v", s.spans)
+	prevStartPos, prevEndPos := -1, -1
+
+	startPos, err := s.spans.NextStartPosition()
+	if err != nil {
+		return err
+	}
+	assert2(startPos != NO_MORE_POSITIONS, "initial startPos NO_MORE_POSITIONS, %v", s.spans)
+	for startPos != NO_MORE_POSITIONS {
+		assert2(startPos >= prevStartPos, "decreasing start position %v", s.spans)
+		endPos := s.spans.EndPosition()
+		assert(endPos != NO_MORE_POSITIONS)
+		assert2(startPos != prevStartPos || endPos >= prevEndPos,
+			"decreasing end position %v", s.spans)
+		s.numMatches++
+		s.freq += s.docScorer.computeSlopFactor(s.spans.Width())
+		if err = s.spi.doCurrentSpans(); err != nil {
+			return err
+		}
+		prevStartPos, prevEndPos = startPos, endPos
+		if startPos, err = s.spans.NextStartPosition(); err != nil {
+			return err
+		}
+	}
+	assert2(s.spans.StartPosition() == NO_MORE_POSITIONS, "incorrect final start position, %v", s.spans)
+	assert2(s.spans.EndPosition() == NO_MORE_POSITIONS, "incorrect final end position, %v", s.spans)
+	return nil
+}
+
+func (s *SpanScorer) doStartCurrentDoc() {}
+
+func (s *SpanScorer) doCurrentSpans() error { return nil }
+
+func (s *SpanScorer) scoreCurrentDoc() (float32, error) {
+	assert2(s.docScorer != nil, "%v has a nil docScorer!", s)
+	return s.docScorer.Score(s.spans.DocId(), s.freq), nil
+}
+
+func (s *SpanScorer) Score() (float32, error) {
+	if err := s.ensureFreq(); err != nil {
+		return 0, err
+	}
+	return s.spi.scoreCurrentDoc()
+}
+
+// Returns the number of spans of current doc.
+func (s *SpanScorer) Freq() (int, error) {
+	if err := s.ensureFreq(); err != nil {
+		return 0, err
+	}
+	return s.numMatches, nil
+}
+
+// Returns the intermediate "sloppy freq" adjusted for edit distance.
+func (s *SpanScorer) sloppyFreq() (float32, error) {
+	if err := s.ensureFreq(); err != nil {
+		return 0, err
+	}
+	return s.freq, nil
+}
+
+func (s *SpanScorer) String() string {
+	return fmt.Sprintf("scorer(%v)", s.weight)
+}
@@ -0,0 +1,99 @@
+package search
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/balzaczyy/golucene/core/index"
+	. "github.com/balzaczyy/golucene/core/index/model"
+	"github.com/balzaczyy/golucene/core/util"
+)
+
+// search/spans/SpanTermQuery.java
+
+/*
+Matches spans containing a term. This should not be used for terms
+that are indexed at position NO_MORE_POSITIONS.
+*/
+type SpanTermQuery struct {
+	*AbstractQuery
+	term *index.Term
+}
+
+// Constructs a SpanTermQuery matching the named term's spans.
+func NewSpanTermQuery(term *index.Term) *SpanTermQuery {
+	ans := &SpanTermQuery{term: term}
+	ans.AbstractQuery = NewAbstractQuery(ans)
+	return ans
+}
+
+// Returns the term whose spans are matched.
+func (q *SpanTermQuery) Term() *index.Term {
+	return q.term
+}
+
+func (q *SpanTermQuery) Field() string {
+	return q.term.Field
+}
+
+func (q *SpanTermQuery) CreateWeight(ss *IndexSearcher) (Weight, error) {
+	return newSpanWeight(q, ss)
+}
+
+func (q *SpanTermQuery) ExtractTerms(terms *index.TermSet) {
+	terms.Add(q.term)
+}
+
+func (q *SpanTermQuery) Visit(visitor QueryVisitor) {
+	if visitor.AcceptField(q.term.Field) {
+		visitor.ConsumeTerms(q, q.term)
+	}
+}
+
+func (q *SpanTermQuery) spans(context *index.AtomicReaderContext, acceptDocs util.Bits,
+	termContexts map[termKey]*index.TermContext) (Spans, error) {
+
+	terms := context.Reader().(index.AtomicReader).Terms(q.term.Field)
+	if terms == nil {
+		return nil, nil
+	}
+	te := terms.Iterator(nil)
+	if termContext, ok := termContexts[newTermKey(q.term)]; ok {
+		state := termContext.State(context.Ord)
+		if state == nil { // term doesn't exist in this segment
+			return nil, nil
+		}
+		if err := te.SeekExactFromLast(q.term.Bytes, state); err != nil {
+			return nil, err
+		}
+	} else {
+		// the term was not extracted from the top-level query, so seek
+		// to it in this segment now
+		ok, err := te.SeekExact(q.term.Bytes)
+		if !ok || err != nil {
+			return nil, err
+		}
+	}
+
+	postings, err := te.DocsAndPositionsByFlags(acceptDocs, nil, DOCS_POSITIONS_ENUM_FLAG_PAYLOADS)
+	if err != nil {
+		return nil, err
+	}
+	// positions are required but not indexed for this field
+	assert2(postings != nil,
+		"field \"%v\" was indexed without position data; cannot run SpanTermQuery (term=%v)",
+		q.term.Field, string(q.term.Bytes))
+	return newTermSpans(postings, q.term), nil
+}
+
+func (q *SpanTermQuery) ToString(field string) string {
+	var buf bytes.Buffer
+	if q.term.Field != field {
+		buf.WriteString(q.term.Field)
+		buf.WriteRune(':')
+	}
+	buf.WriteString(string(q.term.Bytes))
+	if q.boost != 1 {
+		fmt.Fprintf(&buf, "^%v", q.boost)
+	}
+	return buf.String()
+}
@@ -0,0 +1,184 @@
+package search
+
+import (
+	"fmt"
+	"github.com/balzaczyy/golucene/core/index"
+	. "github.com/balzaczyy/golucene/core/index/model"
+	. "github.com/balzaczyy/golucene/core/search/model"
+	"math"
+)
+
+// search/spans/Spans.java
+
+const NO_MORE_POSITIONS = math.MaxInt32
+
+/*
+Iterates through combinations of start/end positions per-doc. Each
+start/end position represents a range of term positions within the
+current document. These are enumerated in order, by increasing
+document number, within that by increasing start position and
+finally by increasing end position.
+*/
+type Spans interface {
+	DocIdSetIterator
+	/*
+		Returns the next start position for the current doc. There is
+		always at least one start/end position per doc. After the last
+		start/end position at the current doc this returns
+		NO_MORE_POSITIONS.
+	*/
+	NextStartPosition() (int, error)
+	/*
+		Returns the start position in the current doc: -1 when
+		NextStartPosition() was not yet called on the current doc. After
+		the last start/end position at the current doc this returns
+		NO_MORE_POSITIONS.
+	*/
+	StartPosition() int
+	/*
+		Returns the end position for the current start position, or -1
+		when NextStartPosition() was not yet called on the current doc.
+		After the last start/end position at the current doc this
+		returns NO_MORE_POSITIONS.
+	*/
+	EndPosition() int
+	/*
+		Returns the width of the match, which is typically used to
+		compute the slop factor. It is only legal to call this method
+		when the iterator is on a valid doc ID and positioned.
+	*/
+	Width() int
+	/*
+		Collects postings data from the leaves of the current Spans. This
+		should only be called after NextStartPosition(), and before
+		NO_MORE_POSITIONS has been reached.
+	*/
+	Collect(collector SpanCollector) error
+}
+
+// search/spans/SpanCollector.java
+
+/*
+An interface defining the collection of postings information from
+the leaves of a Spans.
+*/
+type SpanCollector interface {
+	// Collects information from postings at the given position.
+	CollectLeaf(postings DocsAndPositionsEnum, position int, term *index.Term) error
+	// Calls to allow the collector to reset its state for a new position.
+	Reset()
+}
+
+// search/spans/TermSpans.java
+
+/*
+Expert: the Spans of a single term, iterating over its positions. This
+does not work correctly for terms indexed at position
+NO_MORE_POSITIONS.
+*/
+type TermSpans struct {
+	postings DocsAndPositionsEnum
+	term     *index.Term
+	doc      int
+	freq     int
+	count    int
+	position int
+}
+
+func newTermSpans(postings DocsAndPositionsEnum, term *index.Term) *TermSpans {
+	return &TermSpans{
+		postings: postings,
+		term:     term,
+		doc:      -1,
+		position: -1,
+	}
+}
+
+func (s *TermSpans) NextDoc() (int, error) {
+	doc, err := s.postings.NextDoc()
+	if err != nil {
+		return 0, err
+	}
+	return s.toDoc(doc)
+}
+
+func (s *TermSpans) Advance(target int) (int, error) {
+	assert2(target > s.doc, "target=%v, doc=%v", target, s.doc)
+	doc, err := s.postings.Advance(target)
+	if err != nil {
+		return 0, err
+	}
+	return s.toDoc(doc)
+}
+
+func (s *TermSpans) toDoc(doc int) (int, error) {
+	s.doc = doc
+	if doc != NO_MORE_DOCS {
+		freq, err := s.postings.Freq()
+		if err != nil {
+			return 0, err
+		}
+		assert(freq >= 1)
+		s.freq = freq
+		s.count = 0
+	}
+	s.position = -1
+	return doc, nil
+}
+
+func (s *TermSpans) DocId() int {
+	return s.doc
+}
+
+func (s *TermSpans) NextStartPosition() (int, error) {
+	if s.count == s.freq {
+		assert(s.position != NO_MORE_POSITIONS)
+		s.position = NO_MORE_POSITIONS
+		return s.position, nil
+	}
+	prevPosition := s.position
+	position, err := s.postings.NextPosition()
+	if err != nil {
+		return 0, err
+	}
+	assert2(position >= prevPosition, "prevPosition=%v > position=%v", prevPosition, position)
+	// position == NO_MORE_POSITIONS not really supported
+	assert(position != NO_MORE_POSITIONS)
+	s.position = position
+	s.count++
+	return s.position, nil
+}
+
+func (s *TermSpans) StartPosition() int {
+	return s.position
+}
+
+func (s *TermSpans) EndPosition() int {
+	switch s.position {
+	case -1:
+		return -1
+	case NO_MORE_POSITIONS:
+		return NO_MORE_POSITIONS
+	}
+	return s.position + 1
+}
+
+func (s *TermSpans) Width() int {
+	return 0
+}
+
+func (s *TermSpans) Collect(collector SpanCollector) error {
+	return collector.CollectLeaf(s.postings, s.position, s.term)
+}
+
+func (s *TermSpans) String() string {
+	var pos interface{} = s.position
+	if s.position == NO_MORE_POSITIONS {
+		pos = "ENDPOS"
+	}
+	var doc interface{} = s.doc
+	if s.doc == NO_MORE_DOCS {
+		doc = "ENDDOC"
+	}
+	return fmt.Sprintf("spans(%v)@%v - %v", s.term, doc, pos)
+}
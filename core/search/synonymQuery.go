@@ -0,0 +1,293 @@
+package search
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/balzaczyy/golucene/core/index"
+	. "github.com/balzaczyy/golucene/core/search/model"
+	"github.com/balzaczyy/golucene/core/util"
+	"reflect"
+	"sort"
+)
+
+// search/SynonymQuery.java
+
+/*
+A query that treats multiple terms as synonyms.
+
+For scoring purposes, this query tries to score the terms as if you
+had indexed them as one term: it will match any of the terms but
+only invoke the similarity a single time, scoring the sum of all
+term frequencies for the document, with the maximum document
+frequency of the terms as the document frequency, so that expanding
+a single token into synonyms at query time does not skew IDF in
+favor of the rarest variant.
+*/
+type SynonymQuery struct {
+	*AbstractQuery
+	terms []*index.Term
+}
+
+// Creates a new SynonymQuery, matching any of the supplied terms.
+// The terms must all have the same field.
+func NewSynonymQuery(terms ...*index.Term) *SynonymQuery {
+	ans := &SynonymQuery{terms: append([]*index.Term(nil), terms...)}
+	ans.AbstractQuery = NewAbstractQuery(ans)
+	for _, term := range terms {
+		assert2(term.Field == terms[0].Field,
+			"Synonyms must be across the same field")
+	}
+	sort.Sort(index.TermSorter(ans.terms))
+	return ans
+}
+
+// Returns the terms of this query, sorted.
+func (q *SynonymQuery) Terms() []*index.Term {
+	return q.terms
+}
+
+func (q *SynonymQuery) Rewrite(reader index.IndexReader) Query {
+	switch len(q.terms) {
+	case 0: // optimize zero and single term cases
+		bq := NewBooleanQuery()
+		bq.SetBoost(q.boost)
+		return bq
+	case 1:
+		tq := NewTermQuery(q.terms[0])
+		tq.SetBoost(q.boost)
+		return tq
+	}
+	return q
+}
+
+func (q *SynonymQuery) CreateWeight(ss *IndexSearcher) (Weight, error) {
+	return newSynonymWeight(q, ss)
+}
+
+func (q *SynonymQuery) ExtractTerms(terms *index.TermSet) {
+	for _, t := range q.terms {
+		terms.Add(t)
+	}
+}
+
+func (q *SynonymQuery) Visit(visitor QueryVisitor) {
+	if len(q.terms) == 0 {
+		visitor.VisitLeaf(q)
+		return
+	}
+	if !visitor.AcceptField(q.terms[0].Field) {
+		return
+	}
+	// any of the terms matches
+	if sub := visitor.SubVisitor(SHOULD, q); sub != nil {
+		sub.ConsumeTerms(q, q.terms...)
+	}
+}
+
+func (q *SynonymQuery) ToString(field string) string {
+	var buf bytes.Buffer
+	buf.WriteString("Synonym(")
+	for i, t := range q.terms {
+		if i > 0 {
+			buf.WriteRune(' ')
+		}
+		buf.WriteString(NewTermQuery(t).ToString(field))
+	}
+	buf.WriteRune(')')
+	if q.boost != 1 {
+		fmt.Fprintf(&buf, "^%v", q.boost)
+	}
+	return buf.String()
+}
+
+type SynonymWeight struct {
+	*WeightImpl
+	*SynonymQuery
+	similarity Similarity
+	stats      SimWeight
+	states     []*index.TermContext
+}
+
+func newSynonymWeight(owner *SynonymQuery, ss *IndexSearcher) (*SynonymWeight, error) {
+	ctx := ss.TopReaderContext()
+	states := make([]*index.TermContext, len(owner.terms))
+	var docFreq, totalTermFreq int64
+	for i, term := range owner.terms {
+		state, err := index.NewTermContextFromTerm(ctx, term)
+		if err != nil {
+			return nil, err
+		}
+		states[i] = state
+		termStats := ss.spi.TermStatistics(term, state)
+		if termStats.DocFreq > docFreq {
+			docFreq = termStats.DocFreq
+		}
+		if termStats.TotalTermFreq == -1 {
+			totalTermFreq = -1
+		} else if totalTermFreq != -1 {
+			totalTermFreq += termStats.TotalTermFreq
+		}
+	}
+	pseudoStats := NewTermStatistics(nil, docFreq, totalTermFreq)
+	sim := ss.similarity
+	ans := &SynonymWeight{
+		SynonymQuery: owner,
+		similarity:   sim,
+		stats: sim.computeWeight(
+			owner.boost,
+			ss.spi.CollectionStatistics(owner.terms[0].Field),
+			pseudoStats),
+		states: states,
+	}
+	ans.WeightImpl = newWeightImpl(ans)
+	return ans, nil
+}
+
+func (w *SynonymWeight) String() string {
+	return fmt.Sprintf("weight(%v)", w.SynonymQuery)
+}
+
+func (w *SynonymWeight) ValueForNormalization() float32 {
+	return w.stats.ValueForNormalization()
+}
+
+func (w *SynonymWeight) Normalize(norm float32, topLevelBoost float32) {
+	w.stats.Normalize(norm, topLevelBoost)
+}
+
+func (w *SynonymWeight) IsScoresDocsOutOfOrder() bool {
+	return false
+}
+
+func (w *SynonymWeight) Scorer(context *index.AtomicReaderContext,
+	acceptDocs util.Bits) (Scorer, error) {
+
+	terms := context.Reader().(index.AtomicReader).Terms(w.terms[0].Field)
+	if terms == nil {
+		return nil, nil
+	}
+
+	// reuse a single TermsEnum below
+	te := terms.Iterator(nil)
+	var subScorers []Scorer
+	for i, term := range w.terms {
+		state := w.states[i].State(context.Ord)
+		if state == nil { // term doesn't exist in this segment
+			continue
+		}
+		if err := te.SeekExactFromLast(term.Bytes, state); err != nil {
+			return nil, err
+		}
+		docs, err := te.Docs(acceptDocs, nil)
+		if err != nil {
+			return nil, err
+		}
+		// sub-scorers are only used for iteration and freqs
+		subScorers = append(subScorers, newTermScorer(w, docs, nil))
+	}
+	if len(subScorers) == 0 {
+		return nil, nil
+	}
+
+	docScorer, err := w.similarity.simScorer(w.stats, context)
+	if err != nil {
+		return nil, err
+	}
+	if len(subScorers) == 1 {
+		// we must optimize this case (term not in segment), disjunction
+		// scorer won't do it
+		return newTermScorer(w, subScorers[0].(*TermScorer).docsEnum, docScorer), nil
+	}
+	return newSynonymScorer(w, subScorers, docScorer), nil
+}
+
+func (w *SynonymWeight) Explain(ctx *index.AtomicReaderContext, doc int) (Explanation, error) {
+	scorer, err := w.Scorer(ctx, ctx.Reader().(index.AtomicReader).LiveDocs())
+	if err != nil {
+		return nil, err
+	}
+	if scorer != nil {
+		newDoc, err := scorer.Advance(doc)
+		if err != nil {
+			return nil, err
+		}
+		if newDoc == doc {
+			freq, err := scorer.Freq()
+			if err != nil {
+				return nil, err
+			}
+			docScorer, err := w.similarity.simScorer(w.stats, ctx)
+			if err != nil {
+				return nil, err
+			}
+			scoreExplanation := docScorer.explain(doc,
+				newExplanation(float32(freq), fmt.Sprintf("termFreq=%v", freq)))
+			ans := newComplexExplanation(true,
+				scoreExplanation.(*ExplanationImpl).value,
+				fmt.Sprintf("weight(%v in %v) [%v], result of:",
+					w.SynonymQuery, doc, reflect.TypeOf(w.similarity)))
+			ans.details = []Explanation{scoreExplanation}
+			return ans, nil
+		}
+	}
+	return newComplexExplanation(false, 0, "no matching term"), nil
+}
+
+/*
+Scores the disjunction of synonyms as a single term, whose frequency
+is the sum of the frequencies of the synonyms in the current doc.
+*/
+type synonymScorer struct {
+	*abstractScorer
+	disi      *DisjunctionSumScorer
+	docScorer SimScorer
+}
+
+func newSynonymScorer(w Weight, subScorers []Scorer, docScorer SimScorer) *synonymScorer {
+	ans := &synonymScorer{
+		disi:      newDisjunctionSumScorer(w, subScorers, nil),
+		docScorer: docScorer,
+	}
+	ans.abstractScorer = newScorer(ans, w)
+	return ans
+}
+
+func (s *synonymScorer) DocId() int {
+	return s.disi.DocId()
+}
+
+func (s *synonymScorer) NextDoc() (int, error) {
+	return s.disi.NextDoc()
+}
+
+func (s *synonymScorer) Advance(target int) (int, error) {
+	return s.disi.Advance(target)
+}
+
+// Returns the sum of the frequencies of the synonyms in current doc.
+func (s *synonymScorer) Freq() (freq int, err error) {
+	doc := s.disi.DocId()
+	for _, sub := range s.disi.subScorers[:s.disi.numScorers] {
+		if sub.DocId() == doc {
+			n, err := sub.Freq()
+			if err != nil {
+				return 0, err
+			}
+			freq += n
+		}
+	}
+	return freq, nil
+}
+
+func (s *synonymScorer) Score() (float32, error) {
+	assert(s.DocId() != NO_MORE_DOCS)
+	freq, err := s.Freq()
+	if err != nil {
+		return 0, err
+	}
+	return s.docScorer.Score(s.DocId(), float32(freq)), nil
+}
+
+func (s *synonymScorer) String() string {
+	return fmt.Sprintf("scorer(%v)", s.weight)
+}
@@ -0,0 +1,83 @@
+package search
+
+import (
+	std "github.com/balzaczyy/golucene/analysis/standard"
+	"github.com/balzaczyy/golucene/core/index"
+	"github.com/balzaczyy/golucene/core/store"
+	"github.com/balzaczyy/golucene/core/util"
+	"math"
+	"testing"
+)
+
+func TestSynonymQuery(t *testing.T) {
+	dir, err := store.OpenFSDirectory(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := index.NewIndexWriter(dir, index.NewIndexWriterConfig(util.VERSION_LATEST, std.NewStandardAnalyzer()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// sofa is common, couch is rare
+	for _, title := range []string{"red couch", "red sofa", "couch sofa sofa", "red chair"} {
+		addDocument(t, w, title)
+	}
+	for i := 0; i < 10; i++ {
+		addDocument(t, w, "blue sofa")
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	r, err := index.OpenDirectoryReader(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	ss := NewIndexSearcher(r)
+
+	q := NewSynonymQuery(index.NewTerm("title", "sofa"), index.NewTerm("title", "couch"))
+	assertEquals(t, "Synonym(title:couch title:sofa)", q.String())
+	assertEquals(t, "Synonym(couch sofa)", q.ToString("title"))
+
+	c := &freqCollector{freqs: make(map[int]int), scores: make(map[int]float32)}
+	if err = ss.SearchCollector(q, c); err != nil {
+		t.Fatal(err)
+	}
+	assertEquals(t, 13, len(c.freqs))
+	assertEquals(t, 1, c.freqs[0])
+	assertEquals(t, 1, c.freqs[1])
+	assertEquals(t, 3, c.freqs[2])
+	if _, ok := c.freqs[3]; ok {
+		t.Error("Expected doc 3 not to match")
+	}
+	// the rare variant doesn't outscore the common one
+	assertEquals(t, c.scores[0], c.scores[1])
+	if c.scores[2] <= c.scores[0] {
+		t.Errorf("Expected summed freqs to score higher, but was %v", c.scores)
+	}
+
+	// whereas a disjunction favors the rare variant
+	bq := NewBooleanQuery()
+	bq.Add(NewTermQuery(index.NewTerm("title", "sofa")), SHOULD)
+	bq.Add(NewTermQuery(index.NewTerm("title", "couch")), SHOULD)
+	docs, err := ss.SearchTop(bq, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if docs.ScoreDocs[0].Doc == 1 || docs.ScoreDocs[1].Doc == 1 {
+		t.Errorf("Expected couch to outscore sofa, but was %v", docs.ScoreDocs)
+	}
+
+	exp, err := ss.Explain(q, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !exp.IsMatch() || math.Abs(float64(exp.Value()-c.scores[2])) > 1e-6 {
+		t.Errorf("Expected explanation of score %v, but was %v", c.scores[2], exp)
+	}
+
+	// a single term is rewritten to a term query
+	if _, ok := NewSynonymQuery(index.NewTerm("title", "sofa")).Rewrite(r).(*TermQuery); !ok {
+		t.Error("Expected single synonym to rewrite to TermQuery")
+	}
+}
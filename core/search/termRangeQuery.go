@@ -0,0 +1,110 @@
+package search
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// search/TermRangeQuery.java
+
+/*
+A Query that matches documents within a range of terms.
+
+This query matches the documents looking for terms that fall into the
+supplied range according to byte order. It is not intended for
+numerical ranges.
+*/
+type TermRangeQuery struct {
+	*MultiTermQuery
+	lowerTerm, upperTerm       []byte
+	includeLower, includeUpper bool
+}
+
+/*
+Constructs a query selecting all terms greater/equal than lowerTerm
+but less/equal than upperTerm. If an endpoint is nil, it is said to
+be "open". Either or both endpoints may be open. Open endpoints may
+not be exclusive (you can't select all but the first or last term
+without explicitly specifying the term to exclude.)
+*/
+func NewTermRangeQuery(field string, lowerTerm, upperTerm []byte, includeLower, includeUpper bool) *TermRangeQuery {
+	ans := &TermRangeQuery{
+		lowerTerm:    lowerTerm,
+		upperTerm:    upperTerm,
+		includeLower: includeLower,
+		includeUpper: includeUpper,
+	}
+	ans.MultiTermQuery = NewMultiTermQuery(ans, field)
+	return ans
+}
+
+/* Factory that creates a new TermRangeQuery using strings for term text. */
+func NewTermRangeQueryFromStrings(field string, lowerTerm, upperTerm *string, includeLower, includeUpper bool) *TermRangeQuery {
+	var lower, upper []byte
+	if lowerTerm != nil {
+		lower = []byte(*lowerTerm)
+	}
+	if upperTerm != nil {
+		upper = []byte(*upperTerm)
+	}
+	return NewTermRangeQuery(field, lower, upper, includeLower, includeUpper)
+}
+
+// Returns the lower value of this range query
+func (q *TermRangeQuery) LowerTerm() []byte { return q.lowerTerm }
+
+// Returns the upper value of this range query
+func (q *TermRangeQuery) UpperTerm() []byte { return q.upperTerm }
+
+// Returns true if the lower endpoint is inclusive
+func (q *TermRangeQuery) IncludesLower() bool { return q.includeLower }
+
+// Returns true if the upper endpoint is inclusive
+func (q *TermRangeQuery) IncludesUpper() bool { return q.includeUpper }
+
+func (q *TermRangeQuery) AcceptTerm(term []byte) bool {
+	if q.lowerTerm != nil {
+		if cmp := bytes.Compare(term, q.lowerTerm); cmp < 0 || cmp == 0 && !q.includeLower {
+			return false
+		}
+	}
+	if q.upperTerm != nil {
+		if cmp := bytes.Compare(term, q.upperTerm); cmp > 0 || cmp == 0 && !q.includeUpper {
+			return false
+		}
+	}
+	return true
+}
+
+func (q *TermRangeQuery) ToString(field string) string {
+	var buf bytes.Buffer
+	if q.field != field {
+		buf.WriteString(q.field)
+		buf.WriteRune(':')
+	}
+	if q.includeLower {
+		buf.WriteRune('[')
+	} else {
+		buf.WriteRune('{')
+	}
+	if q.lowerTerm != nil {
+		buf.Write(q.lowerTerm)
+	} else {
+		buf.WriteRune('*')
+	}
+	buf.WriteString(" TO ")
+	if q.upperTerm != nil {
+		buf.Write(q.upperTerm)
+	} else {
+		buf.WriteRune('*')
+	}
+	if q.includeUpper {
+		buf.WriteRune(']')
+	} else {
+		buf.WriteRune('}')
+	}
+	if q.boost != 1 {
+		fmt.Fprintf(&buf, "^%v", q.boost)
+	}
+	return buf.String()
+}
@@ -21,6 +21,10 @@ func NewTermQuery(t *index.Term) *TermQuery {
 	return NewTermQueryWithDocFreq(t, -1)
 }
 
+/*
+Expert: constructs a TermQuery that will use the provided docFreq
+instead of looking up the docFreq against the searcher.
+*/
 func NewTermQueryWithDocFreq(t *index.Term, docFreq int) *TermQuery {
 	ans := &TermQuery{}
 	ans.AbstractQuery = NewAbstractQuery(ans)
@@ -51,6 +55,33 @@ func (q *TermQuery) CreateWeight(ss *IndexSearcher) (w Weight, err error) {
 	return NewTermWeight(q, ss, termState), nil
 }
 
+/*
+Expert: constructs a TermQuery that will use the provided per-reader
+term states instead of looking them up, as long as the searcher uses
+the same top-level reader context.
+*/
+func NewTermQueryWithStates(t *index.Term, states *index.TermContext) *TermQuery {
+	assert(states != nil)
+	ans := NewTermQueryWithDocFreq(t, states.DocFreq)
+	ans.perReaderTermState = states
+	return ans
+}
+
+// Returns the term of this query.
+func (q *TermQuery) Term() *index.Term {
+	return q.term
+}
+
+func (q *TermQuery) ExtractTerms(terms *index.TermSet) {
+	terms.Add(q.term)
+}
+
+func (q *TermQuery) Visit(visitor QueryVisitor) {
+	if visitor.AcceptField(q.term.Field) {
+		visitor.ConsumeTerms(q, q.term)
+	}
+}
+
 func (q *TermQuery) ToString(field string) string {
 	var buf bytes.Buffer
 	if q.term.Field != field {
@@ -80,8 +111,8 @@ func NewTermWeight(owner *TermQuery, ss *IndexSearcher, termStates *index.TermCo
 		similarity: sim,
 		stats: sim.computeWeight(
 			owner.boost,
-			ss.CollectionStatistics(owner.term.Field),
-			ss.TermStatistics(owner.term, termStates)),
+			ss.spi.CollectionStatistics(owner.term.Field),
+			ss.spi.TermStatistics(owner.term, termStates)),
 		termStates: termStates,
 	}
 	ans.WeightImpl = newWeightImpl(ans)
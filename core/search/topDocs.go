@@ -0,0 +1,136 @@
+package search
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// search/TopDocs.java
+
+// Refers to one hit of the TopDocs of one shard.
+type shardRef struct {
+	// Which shard (index into shardHits)
+	shardIndex int
+	// Which hit within the shard
+	hitIndex int
+}
+
+/*
+Returns true if first is < second, i.e. sorts before it. Ties are
+broken by shard index, then by doc ID.
+*/
+func mergeLessThan(sort *Sort, shardHits []TopDocs, first, second *shardRef) bool {
+	firstHit := shardHits[first.shardIndex].ScoreDocs[first.hitIndex]
+	secondHit := shardHits[second.shardIndex].ScoreDocs[second.hitIndex]
+	if sort == nil {
+		if firstHit.Score != secondHit.Score {
+			return firstHit.Score > secondHit.Score
+		}
+	} else {
+		for i, sortField := range sort.fields {
+			if cmp := sortField.compareValues(firstHit.Fields[i], secondHit.Fields[i]); cmp != 0 {
+				return cmp < 0
+			}
+		}
+	}
+	// Tie break: earlier shard wins
+	if first.shardIndex != second.shardIndex {
+		return first.shardIndex < second.shardIndex
+	}
+	// Tie break in same shard: resolve however the shard had resolved
+	// it, which for the default and field sorts is by doc ID.
+	if firstHit.Doc != secondHit.Doc {
+		return firstHit.Doc < secondHit.Doc
+	}
+	return first.hitIndex < second.hitIndex
+}
+
+/*
+Returns a new TopDocs, containing topN results across the provided
+TopDocs, sorting by the specified Sort. Each of the TopDocs must have
+been sorted by the same Sort, and sort field values must have been
+filled (i.e. each ScoreDoc carries one value in Fields per SortField).
+Pass a nil Sort to merge hits sorted by relevance.
+
+Ties are broken by shard index, then by doc ID. The ShardIndex() of
+every merged ScoreDoc is set to the index of its TopDocs in
+shardHits; hits of the shards are copied so shardHits is left
+untouched.
+*/
+func MergeTopDocs(sort *Sort, topN int, shardHits []TopDocs) (TopDocs, error) {
+	return MergeTopDocsFrom(sort, 0, topN, shardHits)
+}
+
+/*
+Same as MergeTopDocs() but also ignores the top start results, which
+is useful for deep paging across shards.
+*/
+func MergeTopDocsFrom(sort *Sort, start, size int, shardHits []TopDocs) (TopDocs, error) {
+	if start < 0 || size < 0 {
+		return TopDocs{}, errors.New(fmt.Sprintf(
+			"start and size must be >= 0, got start=%v, size=%v", start, size))
+	}
+
+	pq := &PriorityQueue{items: make([]interface{}, 0, len(shardHits))}
+	pq.less = func(i, j int) bool {
+		return mergeLessThan(sort, shardHits, pq.items[i].(*shardRef), pq.items[j].(*shardRef))
+	}
+
+	totalHitCount := 0
+	availHitCount := 0
+	maxScore := math.NaN()
+	for shardIdx, shard := range shardHits {
+		// totalHits can be non-zero even if no hits were collected, when
+		// searchAfter was used
+		totalHitCount += shard.TotalHits
+		if len(shard.ScoreDocs) == 0 {
+			continue
+		}
+		if sort != nil {
+			for _, hit := range shard.ScoreDocs {
+				if len(hit.Fields) != len(sort.fields) {
+					return TopDocs{}, errors.New(fmt.Sprintf(
+						"shard %v: hit %v has %v sort values but the sort has %v fields",
+						shardIdx, hit.Doc, len(hit.Fields), len(sort.fields)))
+				}
+			}
+		}
+		availHitCount += len(shard.ScoreDocs)
+		heap.Push(pq, &shardRef{shardIndex: shardIdx})
+		if score := shard.maxScore; math.IsNaN(maxScore) || score > maxScore {
+			maxScore = score
+		}
+	}
+
+	var hits []*ScoreDoc
+	if availHitCount > start {
+		requestedResultWindow := start + size
+		numIterOnHits := availHitCount
+		if requestedResultWindow < numIterOnHits {
+			numIterOnHits = requestedResultWindow
+		}
+		hits = make([]*ScoreDoc, 0, numIterOnHits-start)
+		for hitUpto := 0; hitUpto < numIterOnHits; hitUpto++ {
+			assert(pq.Len() > 0)
+			ref := pq.items[0].(*shardRef)
+			hit := shardHits[ref.shardIndex].ScoreDocs[ref.hitIndex]
+			if hitUpto >= start {
+				merged := *hit
+				merged.shardIndex = ref.shardIndex
+				hits = append(hits, &merged)
+			}
+
+			if ref.hitIndex++; ref.hitIndex < len(shardHits[ref.shardIndex].ScoreDocs) {
+				// Not done with these TopDocs yet:
+				pq.updateTop()
+			} else {
+				heap.Pop(pq)
+			}
+		}
+	} else {
+		hits = []*ScoreDoc{}
+	}
+	return TopDocs{totalHitCount, hits, maxScore}, nil
+}
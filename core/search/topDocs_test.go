@@ -0,0 +1,75 @@
+package search
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMergeTopDocs(t *testing.T) {
+	shards := []TopDocs{
+		{3, []*ScoreDoc{newScoreDoc(4, 3), newScoreDoc(1, 2), newScoreDoc(2, 2)}, 3},
+		{0, []*ScoreDoc{}, math.NaN()},
+		{2, []*ScoreDoc{newScoreDoc(0, 2.5), newScoreDoc(3, 2)}, 2.5},
+	}
+	merged, err := MergeTopDocs(nil, 4, shards)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEquals(t, 5, merged.TotalHits)
+	assertEquals(t, 3.0, merged.MaxScore())
+	// ties on score 2 are broken by shard, then by doc
+	expected := [][2]int{{0, 4}, {2, 0}, {0, 1}, {0, 2}}
+	assertEquals(t, len(expected), len(merged.ScoreDocs))
+	for i, hit := range merged.ScoreDocs {
+		if hit.ShardIndex() != expected[i][0] || hit.Doc != expected[i][1] {
+			t.Errorf("Expected shard %v doc %v at %v, but was %v",
+				expected[i][0], expected[i][1], i, hit)
+		}
+	}
+	assertEquals(t, -1, shards[0].ScoreDocs[0].ShardIndex())
+
+	page, err := MergeTopDocsFrom(nil, 3, 10, shards)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEquals(t, 2, len(page.ScoreDocs))
+	assertEquals(t, 2, page.ScoreDocs[1].ShardIndex())
+	assertEquals(t, 3, page.ScoreDocs[1].Doc)
+}
+
+func TestMergeTopFieldDocs(t *testing.T) {
+	hit := func(doc int, title string, price float64) *ScoreDoc {
+		sd := newScoreDoc(doc, float32(math.NaN()))
+		if title != "" {
+			sd.Fields = []interface{}{title, price}
+		} else {
+			sd.Fields = []interface{}{nil, price}
+		}
+		return sd
+	}
+	sort := NewSort(
+		NewSortField("title", SORT_FIELD_STRING, false),
+		NewSortField("price", SORT_FIELD_DOUBLE, true))
+	assertEquals(t, `<string: "title">,<double: "price">!`, sort.String())
+	shards := []TopDocs{
+		{2, []*ScoreDoc{hit(7, "apple", 1), hit(3, "pear", 5)}, math.NaN()},
+		{3, []*ScoreDoc{hit(5, "", 2), hit(1, "apple", 3), hit(2, "pear", 5)}, math.NaN()},
+	}
+	merged, err := MergeTopDocs(sort, 10, shards)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// missing titles first, then by title, then by price descending
+	expected := [][2]int{{1, 5}, {1, 1}, {0, 7}, {0, 3}, {1, 2}}
+	for i, hit := range merged.ScoreDocs {
+		if hit.ShardIndex() != expected[i][0] || hit.Doc != expected[i][1] {
+			t.Errorf("Expected shard %v doc %v at %v, but was %v",
+				expected[i][0], expected[i][1], i, hit)
+		}
+	}
+
+	shards[1].ScoreDocs[0].Fields = nil
+	if _, err = MergeTopDocs(sort, 10, shards); err == nil {
+		t.Error("Expected error on hits without sort values")
+	}
+}
@@ -0,0 +1,125 @@
+package search
+
+import (
+	"fmt"
+	"github.com/balzaczyy/golucene/core/index"
+	. "github.com/balzaczyy/golucene/core/search/model"
+	"github.com/balzaczyy/golucene/core/util"
+)
+
+/*
+Implemented by queries whose matches cannot be looked up in the
+index, but have to be verified document by document, e.g. against
+stored fields, like the LatLonPoint queries.
+*/
+type verifyingQuery interface {
+	Query
+	// Returns true if doc, relative to reader, matches.
+	matches(reader index.IndexReader, doc int) (bool, error)
+}
+
+/*
+The Weight of a verifyingQuery. All matches get the same score, the
+query's boost, as with MatchAllDocsQuery.
+*/
+type verifyingWeight struct {
+	*WeightImpl
+	owner       verifyingQuery
+	queryWeight float32
+	queryNorm   float32
+}
+
+func newVerifyingWeight(owner verifyingQuery) *verifyingWeight {
+	ans := &verifyingWeight{owner: owner}
+	ans.WeightImpl = newWeightImpl(ans)
+	return ans
+}
+
+func (w *verifyingWeight) String() string {
+	return fmt.Sprintf("weight(%v)", w.owner)
+}
+
+func (w *verifyingWeight) ValueForNormalization() float32 {
+	w.queryWeight = w.owner.Boost()
+	return w.queryWeight * w.queryWeight
+}
+
+func (w *verifyingWeight) Normalize(queryNorm, topLevelBoost float32) {
+	w.queryNorm = queryNorm * topLevelBoost
+	w.queryWeight *= w.queryNorm
+}
+
+func (w *verifyingWeight) IsScoresDocsOutOfOrder() bool {
+	return false
+}
+
+func (w *verifyingWeight) Scorer(ctx *index.AtomicReaderContext,
+	acceptDocs util.Bits) (Scorer, error) {
+
+	ans := &verifyingScorer{
+		owner:      w.owner,
+		reader:     ctx.Reader(),
+		doc:        -1,
+		maxDoc:     ctx.Reader().MaxDoc(),
+		acceptDocs: acceptDocs,
+		score:      w.queryWeight,
+	}
+	ans.abstractScorer = newScorer(ans, w)
+	return ans, nil
+}
+
+func (w *verifyingWeight) Explain(ctx *index.AtomicReaderContext, doc int) (Explanation, error) {
+	ok, err := w.owner.matches(ctx.Reader(), doc)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return newComplexExplanation(false, 0, "no match"), nil
+	}
+	queryExpl := newComplexExplanation(true, w.queryWeight,
+		fmt.Sprintf("%v, product of:", w.owner))
+	if w.owner.Boost() != 1 {
+		queryExpl.addDetail(newExplanation(w.owner.Boost(), "boost"))
+	}
+	queryExpl.addDetail(newExplanation(w.queryNorm, "queryNorm"))
+	return queryExpl, nil
+}
+
+/*
+Iterates over the accepted docs of a segment, stopping at the ones
+matched by a verifyingQuery.
+*/
+type verifyingScorer struct {
+	*abstractScorer
+	owner      verifyingQuery
+	reader     index.IndexReader
+	doc        int
+	maxDoc     int
+	acceptDocs util.Bits
+	score      float32
+}
+
+func (s *verifyingScorer) DocId() int              { return s.doc }
+func (s *verifyingScorer) Freq() (int, error)      { return 1, nil }
+func (s *verifyingScorer) Score() (float32, error) { return s.score, nil }
+
+func (s *verifyingScorer) NextDoc() (int, error) {
+	return s.Advance(s.doc + 1)
+}
+
+func (s *verifyingScorer) Advance(target int) (int, error) {
+	for s.doc = target; s.doc < s.maxDoc; s.doc++ {
+		if s.acceptDocs != nil && !s.acceptDocs.At(s.doc) {
+			continue
+		}
+		ok, err := s.owner.matches(s.reader, s.doc)
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			return s.doc, nil
+		}
+	}
+	s.doc = NO_MORE_DOCS
+	return s.doc, nil
+}
@@ -0,0 +1,102 @@
+package search
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/balzaczyy/golucene/core/index"
+)
+
+// search/WildcardQuery.java
+
+const (
+	WILDCARD_STRING = '*' // String equality with support for wildcards
+	WILDCARD_CHAR   = '?' // Char equality with support for wildcards
+	WILDCARD_ESCAPE = '\\'
+)
+
+/*
+Implements the wildcard search query. Supported wildcards are *,
+which matches any character sequence (including the empty one), and
+?, which matches any single character. '\' is the escape character.
+
+Note this query can be slow, as it needs to iterate over many terms.
+In order to prevent extremely slow WildcardQueries, a Wildcard term
+should not start with the wildcard *.
+*/
+type WildcardQuery struct {
+	*MultiTermQuery
+	term *index.Term
+	// the pattern, where -1 stands for * and -2 for ?
+	pattern []rune
+}
+
+/* Constructs a query for terms matching term. */
+func NewWildcardQuery(term *index.Term) *WildcardQuery {
+	ans := &WildcardQuery{term: term}
+	ans.MultiTermQuery = NewMultiTermQuery(ans, term.Field)
+	text := []rune(string(term.Bytes))
+	for i := 0; i < len(text); i++ {
+		switch c := text[i]; c {
+		case WILDCARD_STRING:
+			ans.pattern = append(ans.pattern, -1)
+		case WILDCARD_CHAR:
+			ans.pattern = append(ans.pattern, -2)
+		case WILDCARD_ESCAPE:
+			// add the next codepoint instead, if it exists
+			if i+1 < len(text) {
+				i++
+				c = text[i]
+			}
+			fallthrough
+		default:
+			ans.pattern = append(ans.pattern, c)
+		}
+	}
+	return ans
+}
+
+// Returns the pattern term.
+func (q *WildcardQuery) Term() *index.Term {
+	return q.term
+}
+
+func (q *WildcardQuery) AcceptTerm(term []byte) bool {
+	return wildcardMatch(q.pattern, []rune(string(term)))
+}
+
+func wildcardMatch(pattern, text []rune) bool {
+	// backtrack to the last * on mismatch
+	p, t, starP, starT := 0, 0, -1, 0
+	for t < len(text) {
+		switch {
+		case p < len(pattern) && pattern[p] == -1:
+			starP, starT = p, t
+			p++
+		case p < len(pattern) && (pattern[p] == -2 || pattern[p] == text[t]):
+			p++
+			t++
+		case starP >= 0:
+			starT++
+			p, t = starP+1, starT
+		default:
+			return false
+		}
+	}
+	for p < len(pattern) && pattern[p] == -1 {
+		p++
+	}
+	return p == len(pattern)
+}
+
+func (q *WildcardQuery) ToString(field string) string {
+	var buf bytes.Buffer
+	if q.field != field {
+		buf.WriteString(q.field)
+		buf.WriteRune(':')
+	}
+	buf.Write(q.term.Bytes)
+	if q.boost != 1 {
+		fmt.Fprintf(&buf, "^%v", q.boost)
+	}
+	return buf.String()
+}
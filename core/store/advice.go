@@ -0,0 +1,39 @@
+package store
+
+import (
+	"os"
+)
+
+// The way the files opened with an IOContext are expected to be read.
+type AccessPattern int
+
+const (
+	ACCESS_PATTERN_NORMAL AccessPattern = iota
+	ACCESS_PATTERN_SEQUENTIAL
+	ACCESS_PATTERN_RANDOM
+)
+
+/*
+Returns the access pattern hinted to the OS for the files opened with
+this context: merges and files read once are read sequentially, so
+read-ahead pays off; searches jump around the postings, so read-ahead
+would only thrash the page cache.
+*/
+func (ctx IOContext) AccessPattern() AccessPattern {
+	switch {
+	case ctx.context == IO_CONTEXT_TYPE_MERGE || ctx.readOnce:
+		return ACCESS_PATTERN_SEQUENTIAL
+	case ctx.context == IO_CONTEXT_TYPE_DEFAULT:
+		return ACCESS_PATTERN_NORMAL
+	}
+	return ACCESS_PATTERN_RANDOM
+}
+
+/*
+Hints the OS how the file opened with the given context will be read,
+where posix_fadvise(2) is supported. The hint is only advisory, so
+failing to apply it is ignored.
+*/
+func adviseFile(f *os.File, context IOContext) {
+	fadvise(f, context.AccessPattern())
+}
@@ -0,0 +1,55 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAccessPattern(t *testing.T) {
+	assertEquals(t, IO_CONTEXT_DEFAULT.AccessPattern(), ACCESS_PATTERN_NORMAL)
+	assertEquals(t, IO_CONTEXT_READ.AccessPattern(), ACCESS_PATTERN_RANDOM)
+	assertEquals(t, IO_CONTEXT_READONCE.AccessPattern(), ACCESS_PATTERN_SEQUENTIAL)
+	assertEquals(t, NewIOContextForMerge(&MergeInfo{}).AccessPattern(), ACCESS_PATTERN_SEQUENTIAL)
+}
+
+func TestFadvise(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "_0.cfs")
+	if err := os.WriteFile(path, make([]byte, 1<<16), 0644); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	for _, pattern := range []AccessPattern{ACCESS_PATTERN_NORMAL,
+		ACCESS_PATTERN_SEQUENTIAL, ACCESS_PATTERN_RANDOM} {
+		if err = fadvise(f, pattern); err != nil {
+			t.Errorf("fadvise(%v): %v", pattern, err)
+		}
+	}
+
+	// inputs opened for merges read the file as usual
+	for _, newDir := range []func(string) (Directory, error){
+		func(path string) (Directory, error) { return NewNIOFSDirectory(path) },
+		func(path string) (Directory, error) { return NewSimpleFSDirectory(path) },
+	} {
+		d, err := newDir(filepath.Dir(path))
+		if err != nil {
+			t.Fatal(err)
+		}
+		in, err := d.OpenInput("_0.cfs", NewIOContextForMerge(&MergeInfo{}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		buf := make([]byte, 1<<16)
+		if err = in.ReadBytes(buf); err != nil {
+			t.Fatal(err)
+		}
+		if err = in.Close(); err != nil {
+			t.Fatal(err)
+		}
+		d.Close()
+	}
+}
@@ -0,0 +1,132 @@
+package store
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Describes a blob of a BlobStore.
+type BlobInfo struct {
+	Key  string
+	Size int64
+	// Opaque identifier of the content of the blob, which changes each
+	// time the blob is written, like an ETag or a GCS generation.
+	Version string
+}
+
+/*
+A flat key-value store of immutable blobs, like S3 or GCS, on top of
+which ObjectStoreDirectory stores the files of an index.
+
+Missing blobs must be reported with an error for which os.IsNotExist()
+is true. All methods may be called by multiple goroutines at once.
+*/
+type BlobStore interface {
+	// Returns the blobs whose key starts with prefix, in any order.
+	List(prefix string) ([]BlobInfo, error)
+	// Returns the current size and version of a blob.
+	Stat(key string) (BlobInfo, error)
+	/*
+		Reads len(p) bytes of the given version of a blob, starting at
+		offset off. Stores keeping previous versions of their blobs can
+		serve them, others must return ErrBlobVersionChanged rather than
+		the bytes of another version.
+	*/
+	ReadAt(key, version string, p []byte, off int64) error
+	// Replaces the content of a blob with size bytes read from r, and
+	// returns the version of the new content.
+	Put(key string, r io.Reader, size int64) (version string, err error)
+	Delete(key string) error
+}
+
+// Returned when reading a version of a blob which is no longer available.
+var ErrBlobVersionChanged = errors.New("blob version changed")
+
+/*
+A BlobStore keeping the latest version of its blobs in memory, mostly
+useful for tests and as a reference implementation.
+*/
+type MemoryBlobStore struct {
+	sync.RWMutex
+	blobs       map[string]*memoryBlob // synchronized
+	lastVersion int64
+}
+
+type memoryBlob struct {
+	data    []byte
+	version string
+}
+
+func NewMemoryBlobStore() *MemoryBlobStore {
+	return &MemoryBlobStore{blobs: make(map[string]*memoryBlob)}
+}
+
+func (s *MemoryBlobStore) List(prefix string) ([]BlobInfo, error) {
+	s.RLock()
+	defer s.RUnlock()
+	var ans []BlobInfo
+	for key, blob := range s.blobs {
+		if strings.HasPrefix(key, prefix) {
+			ans = append(ans, BlobInfo{key, int64(len(blob.data)), blob.version})
+		}
+	}
+	sort.Slice(ans, func(i, j int) bool { return ans[i].Key < ans[j].Key })
+	return ans, nil
+}
+
+func (s *MemoryBlobStore) Stat(key string) (BlobInfo, error) {
+	s.RLock()
+	defer s.RUnlock()
+	blob, ok := s.blobs[key]
+	if !ok {
+		return BlobInfo{}, fileNotFound("stat", key)
+	}
+	return BlobInfo{key, int64(len(blob.data)), blob.version}, nil
+}
+
+func (s *MemoryBlobStore) ReadAt(key, version string, p []byte, off int64) error {
+	s.RLock()
+	defer s.RUnlock()
+	blob, ok := s.blobs[key]
+	if !ok {
+		return fileNotFound("read", key)
+	}
+	if blob.version != version {
+		return ErrBlobVersionChanged
+	}
+	if off < 0 || off+int64(len(p)) > int64(len(blob.data)) {
+		return fmt.Errorf("read past EOF: %v", key)
+	}
+	copy(p, blob.data[off:])
+	return nil
+}
+
+func (s *MemoryBlobStore) Put(key string, r io.Reader, size int64) (string, error) {
+	var buf bytes.Buffer
+	buf.Grow(int(size))
+	if _, err := io.Copy(&buf, r); err != nil {
+		return "", err
+	}
+	s.Lock()
+	defer s.Unlock()
+	s.lastVersion++
+	blob := &memoryBlob{buf.Bytes(), strconv.FormatInt(s.lastVersion, 10)}
+	s.blobs[key] = blob
+	return blob.version, nil
+}
+
+func (s *MemoryBlobStore) Delete(key string) error {
+	s.Lock()
+	defer s.Unlock()
+	if _, ok := s.blobs[key]; !ok {
+		return fileNotFound("delete", key)
+	}
+	delete(s.blobs, key)
+	return nil
+}
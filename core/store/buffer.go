@@ -36,6 +36,37 @@ func newBufferedIndexInputBySize(spi SeekReader, desc string, bufferSize int) *B
 	return ans
 }
 
+/*
+Changes the buffer size used by this input, keeping the buffered
+bytes which still fit in the new buffer.
+*/
+func (in *BufferedIndexInput) SetBufferSize(newSize int) {
+	assert2(in.buffer == nil || in.bufferSize == len(in.buffer),
+		"buffer=%v bufferSize=%v buffer.length=%v", in, in.bufferSize, len(in.buffer))
+	if newSize == in.bufferSize {
+		return
+	}
+	checkBufferSize(newSize)
+	in.bufferSize = newSize
+	if in.buffer != nil {
+		// Resize the existing buffer and carefully save as
+		// many bytes as possible starting from the current
+		// bufferPosition
+		newBuffer := make([]byte, newSize)
+		numToCopy := min(in.bufferLength-in.bufferPosition, newSize)
+		copy(newBuffer, in.buffer[in.bufferPosition:in.bufferPosition+numToCopy])
+		in.bufferStart += int64(in.bufferPosition)
+		in.bufferPosition = 0
+		in.bufferLength = numToCopy
+		in.newBuffer(newBuffer)
+	}
+}
+
+// Returns the buffer size used by this input.
+func (in *BufferedIndexInput) BufferSize() int {
+	return in.bufferSize
+}
+
 func (in *BufferedIndexInput) newBuffer(newBuffer []byte) {
 	// Subclasses can do something here
 	in.buffer = newBuffer
@@ -264,7 +295,9 @@ func (in *BufferedIndexInput) refill() error {
 		in.newBuffer(make([]byte, in.bufferSize)) // allocate buffer lazily
 		in.spi.seekInternal(int64(in.bufferStart))
 	}
-	in.spi.readInternal(in.buffer[0:newLength])
+	if err := in.spi.readInternal(in.buffer[0:newLength]); err != nil {
+		return err
+	}
 	in.bufferLength = newLength
 	in.bufferStart = start
 	in.bufferPosition = 0
@@ -287,6 +320,11 @@ func (in *BufferedIndexInput) Seek(pos int64) error {
 	}
 }
 
+/*
+Returns a clone positioned at the same file pointer, with the same
+buffer size but no buffer yet: it is cheap to create, and reads
+independently of this input.
+*/
 func (in *BufferedIndexInput) Clone() *BufferedIndexInput {
 	ans := &BufferedIndexInput{
 		bufferSize:     in.bufferSize,
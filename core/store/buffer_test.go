@@ -0,0 +1,97 @@
+package store
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+// Reads a byte slice, recording the length of each readInternal().
+type bytesBufferedInput struct {
+	*BufferedIndexInput
+	data  []byte
+	reads *[]int
+}
+
+func newBytesBufferedInput(data []byte, ctx IOContext) *bytesBufferedInput {
+	ans := &bytesBufferedInput{data: data, reads: new([]int)}
+	ans.BufferedIndexInput = newBufferedIndexInput(ans, "bytesBufferedInput", ctx)
+	return ans
+}
+
+func (in *bytesBufferedInput) Clone() IndexInput {
+	ans := &bytesBufferedInput{in.BufferedIndexInput.Clone(), in.data, in.reads}
+	ans.spi = ans
+	return ans
+}
+
+func (in *bytesBufferedInput) Close() error { return nil }
+
+func (in *bytesBufferedInput) Length() int64 { return int64(len(in.data)) }
+
+func (in *bytesBufferedInput) readInternal(buf []byte) error {
+	pos := in.FilePointer()
+	if pos+int64(len(buf)) > in.Length() {
+		return fmt.Errorf("read past EOF: %v", in)
+	}
+	*in.reads = append(*in.reads, len(buf))
+	copy(buf, in.data[pos:])
+	return nil
+}
+
+func (in *bytesBufferedInput) seekInternal(pos int64) error { return nil }
+
+func TestBufferSizePerContext(t *testing.T) {
+	assertEquals(t, bufferSize(IO_CONTEXT_READ), BUFFER_SIZE)
+	assertEquals(t, bufferSize(IO_CONTEXT_DEFAULT), BUFFER_SIZE)
+	assertEquals(t, bufferSize(IO_CONTEXT_READONCE), MERGE_BUFFER_SIZE)
+	assertEquals(t, bufferSize(NewIOContextForMerge(&MergeInfo{})), MERGE_BUFFER_SIZE)
+	ctx := IO_CONTEXT_READ.WithBufferSize(256)
+	assertEquals(t, bufferSize(ctx), 256)
+	assertEquals(t, bufferSize(IO_CONTEXT_READ), BUFFER_SIZE)
+}
+
+func TestBufferedIndexInputBulkReadAndClone(t *testing.T) {
+	data := make([]byte, 10000)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+	in := newBytesBufferedInput(data, IO_CONTEXT_READ.WithBufferSize(64))
+	assertEquals(t, in.BufferSize(), 64)
+
+	b, _ := in.ReadByte()
+	assertEquals(t, b, data[0])
+	// a large read bypasses the buffer: one read for the rest
+	buf := make([]byte, 5000)
+	if err := in.ReadBytes(buf); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf, data[1:5001]) {
+		t.Fatal("bulk read content differs")
+	}
+	reads := *in.reads
+	assertEquals(t, len(reads), 2)
+	assertEquals(t, reads[0], 64)
+	assertEquals(t, reads[1], 5000-63)
+
+	// clones are independent from the original
+	clone := in.Clone()
+	assertEquals(t, clone.FilePointer(), int64(5001))
+	clone.Seek(9000)
+	b, _ = clone.ReadByte()
+	assertEquals(t, b, data[9000])
+	assertEquals(t, in.FilePointer(), int64(5001))
+	b, _ = in.ReadByte()
+	assertEquals(t, b, data[5001])
+
+	// resizing keeps the buffered bytes
+	in.SetBufferSize(16)
+	assertEquals(t, in.BufferSize(), 16)
+	buf = make([]byte, 100)
+	if err := in.ReadBytes(buf); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf, data[5002:5102]) {
+		t.Error("content differs after resizing the buffer")
+	}
+}
@@ -38,6 +38,7 @@ func (bc *BufferedChecksum) Size() int {
 }
 
 func (bc *BufferedChecksum) Sum(p []byte) []byte {
+	bc.flush()
 	return bc.in.Sum(p)
 }
 
@@ -61,7 +62,7 @@ func (bc *BufferedChecksum) Write(p []byte) (int, error) {
 	if bc.upto+len(p) > len(bc.buffer) {
 		bc.flush()
 	}
-	copy(bc.buffer, p)
+	copy(bc.buffer[bc.upto:], p)
 	bc.upto += len(p)
 	return len(p), nil
 }
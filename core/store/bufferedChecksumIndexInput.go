@@ -19,7 +19,7 @@ type BufferedChecksumIndexInput struct {
 func newBufferedChecksumIndexInput(main IndexInput) *BufferedChecksumIndexInput {
 	ans := &BufferedChecksumIndexInput{
 		main:   main,
-		digest: crc32.NewIEEE(),
+		digest: newBufferedChecksum(crc32.NewIEEE()),
 	}
 	ans.ChecksumIndexInputImpl = NewChecksumIndexInput(
 		fmt.Sprintf("BufferedChecksumIndexInput(%v)", main), ans)
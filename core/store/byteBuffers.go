@@ -0,0 +1,296 @@
+package store
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/balzaczyy/golucene/core/util"
+	"io"
+	"math/bits"
+	"sync"
+	"sync/atomic"
+)
+
+// store/ByteBuffersDataOutput.java
+
+const (
+	// The default minimum size of a block, 1 KB.
+	DEFAULT_MIN_BITS_PER_BLOCK = 10
+	// The default maximum size of a block, 64 MB.
+	DEFAULT_MAX_BITS_PER_BLOCK = 26
+	// Once an output has that many blocks, it doubles the size of its
+	// blocks, unless they are already of the maximum size.
+	MAX_BLOCKS_BEFORE_BLOCK_EXPANSION = 100
+)
+
+/*
+A pool of byte blocks whose sizes are powers of 2, from which
+ByteBuffersDataOutput allocates its blocks, and to which it returns
+the blocks it no longer needs. A recycler can be shared by many
+outputs, and is safe for concurrent use.
+*/
+type ByteBlockRecycler struct {
+	pools [32]sync.Pool
+}
+
+func NewByteBlockRecycler() *ByteBlockRecycler {
+	return &ByteBlockRecycler{}
+}
+
+func (r *ByteBlockRecycler) allocate(blockBits uint) []byte {
+	if r != nil {
+		if block, ok := r.pools[blockBits].Get().([]byte); ok {
+			return block
+		}
+	}
+	return make([]byte, 1<<blockBits)
+}
+
+func (r *ByteBlockRecycler) recycle(block []byte) {
+	if r != nil {
+		r.pools[bits.Len(uint(cap(block)))-1].Put(block[:cap(block)])
+	}
+}
+
+/*
+A DataOutput storing its data in a list of blocks of the same size,
+a power of 2, instead of one contiguous array. Blocks start small,
+and grow up to the maximum size as the output grows, its data being
+copied to blocks of twice the size whenever it has too many.
+*/
+type ByteBuffersDataOutput struct {
+	*util.DataOutputImpl
+	minBits, maxBits uint
+	blockBits        uint
+	blocks           [][]byte
+	// position in the last block
+	pos          int
+	recycler     *ByteBlockRecycler
+	ramBytesUsed int64 // atomic
+}
+
+// Creates an output with the default block sizes, not reusing blocks.
+func NewByteBuffersDataOutput() *ByteBuffersDataOutput {
+	return NewByteBuffersDataOutputWith(DEFAULT_MIN_BITS_PER_BLOCK, DEFAULT_MAX_BITS_PER_BLOCK, nil)
+}
+
+/*
+Creates an output whose blocks are from 1<<minBits to 1<<maxBits
+bytes, allocated from and returned to the recycler, if not nil.
+*/
+func NewByteBuffersDataOutputWith(minBits, maxBits uint, recycler *ByteBlockRecycler) *ByteBuffersDataOutput {
+	assert2(minBits > 0 && minBits <= maxBits && maxBits < 32,
+		"Invalid block bits: min=%v, max=%v", minBits, maxBits)
+	ans := &ByteBuffersDataOutput{
+		minBits:   minBits,
+		maxBits:   maxBits,
+		blockBits: minBits,
+		recycler:  recycler,
+	}
+	ans.DataOutputImpl = util.NewDataOutput(ans)
+	return ans
+}
+
+func (out *ByteBuffersDataOutput) WriteByte(b byte) error {
+	if len(out.blocks) == 0 || out.pos == 1<<out.blockBits {
+		out.appendBlock()
+	}
+	out.blocks[len(out.blocks)-1][out.pos] = b
+	out.pos++
+	return nil
+}
+
+func (out *ByteBuffersDataOutput) WriteBytes(buf []byte) error {
+	for len(buf) > 0 {
+		if len(out.blocks) == 0 || out.pos == 1<<out.blockBits {
+			out.appendBlock()
+		}
+		n := copy(out.blocks[len(out.blocks)-1][out.pos:], buf)
+		out.pos += n
+		buf = buf[n:]
+	}
+	return nil
+}
+
+func (out *ByteBuffersDataOutput) appendBlock() {
+	if len(out.blocks) >= MAX_BLOCKS_BEFORE_BLOCK_EXPANSION && out.blockBits < out.maxBits {
+		out.rewriteToBlockSize(out.blockBits + 1)
+		if out.pos < 1<<out.blockBits {
+			return
+		}
+	}
+	out.blocks = append(out.blocks, out.recycler.allocate(out.blockBits))
+	out.pos = 0
+	atomic.AddInt64(&out.ramBytesUsed, 1<<out.blockBits)
+}
+
+// Copies the data to blocks of 1<<blockBits bytes, recycling the
+// current ones.
+func (out *ByteBuffersDataOutput) rewriteToBlockSize(blockBits uint) {
+	old, size := out.blocks, out.Size()
+	out.blocks, out.pos, out.blockBits = nil, 0, blockBits
+	atomic.StoreInt64(&out.ramBytesUsed, 0)
+	for _, block := range old {
+		n := int(min(int64(len(block)), size))
+		out.WriteBytes(block[:n])
+		size -= int64(n)
+		out.recycler.recycle(block)
+	}
+}
+
+// Returns the number of bytes written.
+func (out *ByteBuffersDataOutput) Size() int64 {
+	if len(out.blocks) == 0 {
+		return 0
+	}
+	return int64(len(out.blocks)-1)<<out.blockBits + int64(out.pos)
+}
+
+// Returns the bytes allocated for the blocks, used or not.
+func (out *ByteBuffersDataOutput) RamBytesUsed() int64 {
+	return atomic.LoadInt64(&out.ramBytesUsed)
+}
+
+// Returns a copy of the written bytes.
+func (out *ByteBuffersDataOutput) ToBytes() []byte {
+	ans := make([]byte, 0, out.Size())
+	for i, block := range out.blocks {
+		if i == len(out.blocks)-1 {
+			block = block[:out.pos]
+		}
+		ans = append(ans, block...)
+	}
+	return ans
+}
+
+/*
+Returns an IndexInput reading the written bytes, sharing the blocks
+of this output; it is invalidated by writing more or resetting.
+*/
+func (out *ByteBuffersDataOutput) ToIndexInput(desc string) *ByteBuffersIndexInput {
+	return newByteBuffersIndexInput(desc, out.blocks, out.blockBits, 0, out.Size())
+}
+
+/*
+Returns a reader of the written bytes, sharing the blocks of this
+output; it is invalidated by writing more or resetting.
+*/
+func (out *ByteBuffersDataOutput) Reader() io.Reader {
+	readers := make([]io.Reader, len(out.blocks))
+	for i, block := range out.blocks {
+		if i == len(out.blocks)-1 {
+			block = block[:out.pos]
+		}
+		readers[i] = bytes.NewReader(block)
+	}
+	return io.MultiReader(readers...)
+}
+
+// Discards the written bytes, recycling the blocks.
+func (out *ByteBuffersDataOutput) Reset() {
+	for _, block := range out.blocks {
+		out.recycler.recycle(block)
+	}
+	out.blocks, out.pos, out.blockBits = nil, 0, out.minBits
+	atomic.StoreInt64(&out.ramBytesUsed, 0)
+}
+
+// store/ByteBuffersIndexInput.java
+
+/*
+IndexInput over blocks of 1<<blockBits bytes, as written by a
+ByteBuffersDataOutput. Clones and slices share the blocks, so they
+are cheap to create. A slice reads the range [off, off+length) of the
+blocks.
+*/
+type ByteBuffersIndexInput struct {
+	*IndexInputImpl
+	blocks    [][]byte // nil once closed
+	blockBits uint
+	blockMask int64
+	// start offset: non-zero in the slice case
+	off    int64
+	length int64
+	// current position, relative to off
+	pos int64
+}
+
+func newByteBuffersIndexInput(desc string, blocks [][]byte, blockBits uint, off, length int64) *ByteBuffersIndexInput {
+	if blocks == nil {
+		// an empty file
+		blocks = [][]byte{}
+	}
+	ans := &ByteBuffersIndexInput{
+		blocks:    blocks,
+		blockBits: blockBits,
+		blockMask: int64(1)<<blockBits - 1,
+		off:       off,
+		length:    length,
+	}
+	ans.IndexInputImpl = NewIndexInputImpl(desc, ans)
+	return ans
+}
+
+func (in *ByteBuffersIndexInput) ReadByte() (byte, error) {
+	if in.blocks == nil {
+		return 0, fmt.Errorf("already closed: %v", in)
+	}
+	if in.pos >= in.length {
+		return 0, fmt.Errorf("read past EOF: %v", in)
+	}
+	p := in.off + in.pos
+	in.pos++
+	return in.blocks[p>>in.blockBits][p&in.blockMask], nil
+}
+
+func (in *ByteBuffersIndexInput) ReadBytes(buf []byte) error {
+	if in.blocks == nil {
+		return fmt.Errorf("already closed: %v", in)
+	}
+	if int64(len(buf)) > in.length-in.pos {
+		return fmt.Errorf("read past EOF: %v", in)
+	}
+	for offset := 0; offset < len(buf); {
+		p := in.off + in.pos
+		n := copy(buf[offset:], in.blocks[p>>in.blockBits][p&in.blockMask:])
+		offset += n
+		in.pos += int64(n)
+	}
+	return nil
+}
+
+func (in *ByteBuffersIndexInput) FilePointer() int64 {
+	return in.pos
+}
+
+func (in *ByteBuffersIndexInput) Seek(pos int64) error {
+	if pos < 0 || pos > in.length {
+		return fmt.Errorf("seek past EOF (pos=%v): %v", pos, in)
+	}
+	in.pos = pos
+	return nil
+}
+
+func (in *ByteBuffersIndexInput) Length() int64 {
+	return in.length
+}
+
+func (in *ByteBuffersIndexInput) Clone() IndexInput {
+	ans := newByteBuffersIndexInput(in.desc, in.blocks, in.blockBits, in.off, in.length)
+	ans.pos = in.pos
+	return ans
+}
+
+func (in *ByteBuffersIndexInput) Slice(desc string, offset, length int64) (IndexInput, error) {
+	if offset < 0 || length < 0 || offset+length > in.length {
+		return nil, fmt.Errorf("slice() %v out of bounds: offset=%v,length=%v,fileLength=%v: %v",
+			desc, offset, length, in.length, in)
+	}
+	return newByteBuffersIndexInput(fmt.Sprintf("%v [slice=%v]", in.desc, desc),
+		in.blocks, in.blockBits, in.off+offset, length), nil
+}
+
+// Closes this input only; its clones and slices can still be read.
+func (in *ByteBuffersIndexInput) Close() error {
+	in.blocks = nil
+	return nil
+}
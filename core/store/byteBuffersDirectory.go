@@ -0,0 +1,247 @@
+package store
+
+import (
+	"fmt"
+	"github.com/balzaczyy/golucene/core/util"
+	"hash"
+	"hash/crc32"
+	"os"
+	"sync"
+	"unsafe"
+)
+
+// store/ByteBuffersDirectory.java
+
+/*
+A heap-based Directory implementation, replacing RAMDirectory. Files
+are written through ByteBuffersDataOutput, so they are stored in
+blocks of growing sizes, up to DEFAULT_MAX_BITS_PER_BLOCK, instead of
+millions of 1 KB arrays or a few huge contiguous ones. The blocks
+freed while a file is written are recycled for the next files.
+
+Slicing and cloning IndexInputs is cheap, as they share the blocks of
+the file, and RamBytesUsed() accounts for the bytes actually
+allocated. A file can not be opened before its output is closed.
+
+This is intended for tests and small transient indexes; larger ones
+should be stored on disk with MMapDirectory.
+*/
+type ByteBuffersDirectory struct {
+	*DirectoryImpl
+	*BaseDirectory
+	minBits, maxBits uint
+	recycler         *ByteBlockRecycler
+	files            map[string]*byteBuffersFile // synchronized
+	filesLock        sync.RWMutex
+}
+
+// Creates an empty ByteBuffersDirectory with the default block sizes.
+func NewByteBuffersDirectory() *ByteBuffersDirectory {
+	return NewByteBuffersDirectoryWith(DEFAULT_MIN_BITS_PER_BLOCK, DEFAULT_MAX_BITS_PER_BLOCK)
+}
+
+/*
+Creates an empty ByteBuffersDirectory whose files are stored in
+blocks from 1<<minBits to 1<<maxBits bytes.
+*/
+func NewByteBuffersDirectoryWith(minBits, maxBits uint) *ByteBuffersDirectory {
+	ans := &ByteBuffersDirectory{
+		minBits:  minBits,
+		maxBits:  maxBits,
+		recycler: NewByteBlockRecycler(),
+		files:    make(map[string]*byteBuffersFile),
+	}
+	ans.DirectoryImpl = NewDirectoryImpl(ans)
+	ans.BaseDirectory = NewBaseDirectory(ans)
+	ans.SetLockFactory(newSingleInstanceLockFactory())
+	return ans
+}
+
+// A file of the directory, being written while output is not nil.
+type byteBuffersFile struct {
+	output *ByteBuffersDataOutput
+	// the content, set once the output is closed
+	blocks       [][]byte
+	blockBits    uint
+	length       int64
+	ramBytesUsed int64
+}
+
+func (f *byteBuffersFile) bytesUsed() int64 {
+	if f.output != nil {
+		return f.output.RamBytesUsed()
+	}
+	return f.ramBytesUsed
+}
+
+func fileNotFound(op, name string) error {
+	return &os.PathError{Op: op, Path: name, Err: os.ErrNotExist}
+}
+
+func (d *ByteBuffersDirectory) LockID() string {
+	return fmt.Sprintf("lucene-%v", util.ItoHex(int64(uintptr(unsafe.Pointer(d)))))
+}
+
+func (d *ByteBuffersDirectory) ListAll() (names []string, err error) {
+	d.EnsureOpen()
+	d.filesLock.RLock()
+	defer d.filesLock.RUnlock()
+	names = make([]string, 0, len(d.files))
+	for name := range d.files {
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// Returns true iff the named file exists in this directory
+func (d *ByteBuffersDirectory) FileExists(name string) bool {
+	d.EnsureOpen()
+	d.filesLock.RLock()
+	defer d.filesLock.RUnlock()
+	_, ok := d.files[name]
+	return ok
+}
+
+// Returns the length in bytes of a file in the directory, 0 while it
+// is still being written.
+func (d *ByteBuffersDirectory) FileLength(name string) (int64, error) {
+	d.EnsureOpen()
+	d.filesLock.RLock()
+	defer d.filesLock.RUnlock()
+	if file, ok := d.files[name]; ok {
+		return file.length, nil
+	}
+	return 0, fileNotFound("stat", name)
+}
+
+// Returns the number of bytes allocated for the files of the directory.
+func (d *ByteBuffersDirectory) RamBytesUsed() int64 {
+	d.EnsureOpen()
+	d.filesLock.RLock()
+	defer d.filesLock.RUnlock()
+	var ans int64
+	for _, file := range d.files {
+		ans += file.bytesUsed()
+	}
+	return ans
+}
+
+/*
+Removes an existing file in the directory. The IndexInputs already
+open on the file can still be read.
+*/
+func (d *ByteBuffersDirectory) DeleteFile(name string) error {
+	d.EnsureOpen()
+	d.filesLock.Lock()
+	defer d.filesLock.Unlock()
+	if _, ok := d.files[name]; !ok {
+		return fileNotFound("delete", name)
+	}
+	delete(d.files, name)
+	return nil
+}
+
+// Creates a new, empty file in the directory with the given name,
+// replacing any existing one. Returns a stream writing this file.
+func (d *ByteBuffersDirectory) CreateOutput(name string, ctx IOContext) (IndexOutput, error) {
+	d.EnsureOpen()
+	file := &byteBuffersFile{output: NewByteBuffersDataOutputWith(d.minBits, d.maxBits, d.recycler)}
+	d.filesLock.Lock()
+	defer d.filesLock.Unlock()
+	d.files[name] = file
+	return newByteBuffersIndexOutput(d, name, file), nil
+}
+
+func (d *ByteBuffersDirectory) Sync(names []string) error {
+	return nil
+}
+
+// Returns a stream reading an existing file.
+func (d *ByteBuffersDirectory) OpenInput(name string, ctx IOContext) (IndexInput, error) {
+	d.EnsureOpen()
+	d.filesLock.RLock()
+	defer d.filesLock.RUnlock()
+	file, ok := d.files[name]
+	if !ok {
+		return nil, fileNotFound("open", name)
+	}
+	if file.output != nil {
+		return nil, fmt.Errorf("file is still being written: %v", name)
+	}
+	return newByteBuffersIndexInput(fmt.Sprintf("ByteBuffersIndexInput(name=%v)", name),
+		file.blocks, file.blockBits, 0, file.length), nil
+}
+
+// Closes the store to future operations, releasing associated memory.
+func (d *ByteBuffersDirectory) Close() error {
+	d.IsOpen = false
+	d.filesLock.Lock()
+	defer d.filesLock.Unlock()
+	d.files = make(map[string]*byteBuffersFile)
+	return nil
+}
+
+func (d *ByteBuffersDirectory) String() string {
+	return fmt.Sprintf("ByteBuffersDirectory@%v", d.DirectoryImpl.String())
+}
+
+// The IndexOutput of a file of a ByteBuffersDirectory.
+type byteBuffersIndexOutput struct {
+	*IndexOutputImpl
+	directory *ByteBuffersDirectory
+	name      string
+	file      *byteBuffersFile
+	output    *ByteBuffersDataOutput
+	crc       hash.Hash32
+}
+
+func newByteBuffersIndexOutput(directory *ByteBuffersDirectory, name string,
+	file *byteBuffersFile) *byteBuffersIndexOutput {
+
+	ans := &byteBuffersIndexOutput{
+		directory: directory,
+		name:      name,
+		file:      file,
+		output:    file.output,
+		crc:       newBufferedChecksum(crc32.NewIEEE()),
+	}
+	ans.IndexOutputImpl = NewIndexOutput(ans)
+	return ans
+}
+
+func (out *byteBuffersIndexOutput) WriteByte(b byte) error {
+	out.crc.Write([]byte{b})
+	return out.output.WriteByte(b)
+}
+
+func (out *byteBuffersIndexOutput) WriteBytes(buf []byte) error {
+	out.crc.Write(buf)
+	return out.output.WriteBytes(buf)
+}
+
+func (out *byteBuffersIndexOutput) FilePointer() int64 {
+	return out.output.Size()
+}
+
+func (out *byteBuffersIndexOutput) Checksum() int64 {
+	return int64(out.crc.Sum32())
+}
+
+// Publishes the written content, so that the file can be opened.
+func (out *byteBuffersIndexOutput) Close() error {
+	out.directory.filesLock.Lock()
+	defer out.directory.filesLock.Unlock()
+	if out.file.output == nil {
+		return nil // already closed
+	}
+	out.file.blocks = out.output.blocks
+	out.file.blockBits = out.output.blockBits
+	out.file.length = out.output.Size()
+	out.file.ramBytesUsed = out.output.RamBytesUsed()
+	out.file.output = nil
+	return nil
+}
+
+func (out *byteBuffersIndexOutput) String() string {
+	return fmt.Sprintf("ByteBuffersIndexOutput(name=%v)", out.name)
+}
@@ -0,0 +1,101 @@
+package store
+
+import (
+	"os"
+	"testing"
+)
+
+func TestByteBuffersDataOutputBlockExpansion(t *testing.T) {
+	recycler := NewByteBlockRecycler()
+	out := NewByteBuffersDataOutputWith(4, 6, recycler)
+	data := make([]byte, 5000)
+	for i := range data {
+		data[i] = byte(i * 7)
+	}
+	for i := 0; i < len(data); i += 3 {
+		out.WriteBytes(data[i:min(i+3, len(data))])
+	}
+	assertEquals(t, out.Size(), int64(5000))
+	assertEquals(t, out.blockBits, uint(6))
+	assertEquals(t, out.RamBytesUsed(), int64(len(out.blocks)<<6))
+	got := out.ToBytes()
+	for i := range data {
+		if got[i] != data[i] {
+			t.Fatalf("byte %v: expected %v, got %v", i, data[i], got[i])
+		}
+	}
+
+	in := out.ToIndexInput("test")
+	if err := in.Seek(4999); err != nil {
+		t.Fatal(err)
+	}
+	b, err := in.ReadByte()
+	assertEquals(t, err, nil)
+	assertEquals(t, b, data[4999])
+
+	out.Reset()
+	assertEquals(t, out.Size(), int64(0))
+	assertEquals(t, out.RamBytesUsed(), int64(0))
+	out.WriteByte(42)
+	assertEquals(t, string(out.ToBytes()), "\x2a")
+}
+
+func TestByteBuffersDirectory(t *testing.T) {
+	dir := NewByteBuffersDirectoryWith(4, 8)
+	defer dir.Close()
+	out, err := dir.CreateOutput("a.bin", IO_CONTEXT_DEFAULT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 1000; i++ {
+		out.WriteInt(int32(i))
+	}
+	if _, err = dir.OpenInput("a.bin", IO_CONTEXT_DEFAULT); err == nil {
+		t.Error("opening a file being written should fail")
+	}
+	assertEquals(t, out.FilePointer(), int64(4000))
+	if err = out.Close(); err != nil {
+		t.Fatal(err)
+	}
+	n, err := dir.FileLength("a.bin")
+	assertEquals(t, n, int64(4000))
+	// 63 blocks of 64 bytes, after expanding from 16 and 32 bytes
+	assertEquals(t, dir.RamBytesUsed(), int64(4032))
+
+	in, err := dir.OpenInput("a.bin", IO_CONTEXT_DEFAULT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	slice, err := in.Slice("ints", 400, 800)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, _ := slice.ReadInt()
+	assertEquals(t, v, int32(100))
+	clone := slice.Clone()
+	clone.Seek(796)
+	v, _ = clone.ReadInt()
+	assertEquals(t, v, int32(299))
+	if _, err = clone.ReadByte(); err == nil {
+		t.Error("reading past the end of the slice should fail")
+	}
+
+	// open inputs survive the deletion of the file
+	if err = dir.DeleteFile("a.bin"); err != nil {
+		t.Fatal(err)
+	}
+	assertEquals(t, dir.FileExists("a.bin"), false)
+	assertEquals(t, dir.RamBytesUsed(), int64(0))
+	in.Seek(3996)
+	v, _ = in.ReadInt()
+	assertEquals(t, v, int32(999))
+	if _, err = dir.OpenInput("a.bin", IO_CONTEXT_DEFAULT); !os.IsNotExist(err) {
+		t.Errorf("expected a not exist error, got %v", err)
+	}
+
+	// the lock ID identifies the directory
+	assertEquals(t, dir.LockID(), dir.LockID())
+	if dir.LockID() == NewByteBuffersDirectory().LockID() {
+		t.Errorf("expected distinct lock IDs, but was %v", dir.LockID())
+	}
+}
@@ -0,0 +1,84 @@
+package store
+
+import (
+	"github.com/balzaczyy/golucene/core/codec"
+	"hash/crc32"
+	"testing"
+)
+
+func TestBufferedChecksumSmallWrites(t *testing.T) {
+	data := make([]byte, 1000)
+	for i := range data {
+		data[i] = byte(i * 7)
+	}
+	bc := newBufferedChecksum(crc32.NewIEEE())
+	for i := 0; i < len(data); i += 3 {
+		bc.Write(data[i:min(i+3, len(data))])
+	}
+	assertEquals(t, bc.Sum32(), crc32.ChecksumIEEE(data))
+}
+
+func TestVerifyChecksum(t *testing.T) {
+	d, err := OpenFSDirectory(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d.Close()
+
+	out, err := d.CreateOutput("_0.si", IO_CONTEXT_DEFAULT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	codec.WriteHeader(out, "test", 1)
+	for i := 0; i < 1000; i++ {
+		out.WriteByte(byte(i))
+	}
+	out.WriteString("hello world")
+	if err = codec.WriteFooter(out); err != nil {
+		t.Fatal(err)
+	}
+	checksum := out.Checksum()
+	if err = out.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	cs, err := VerifyChecksum(d, "_0.si")
+	if err != nil {
+		t.Fatal(err)
+	}
+	in, err := d.OpenInput("_0.si", IO_CONTEXT_READ)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer in.Close()
+	expected, err := codec.RetrieveChecksum(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEquals(t, cs, expected)
+	cs, err = ChecksumEntireFile(in)
+	assertEquals(t, err, nil)
+	assertEquals(t, cs, expected)
+	if checksum == expected {
+		t.Error("the checksum of the footer should not cover itself")
+	}
+
+	// corrupted content
+	out, _ = d.CreateOutput("_1.si", IO_CONTEXT_DEFAULT)
+	codec.WriteHeader(out, "test", 1)
+	out.WriteInt(codec.FOOTER_MAGIC)
+	out.WriteInt(0)
+	out.WriteLong(42)
+	out.Close()
+	if _, err = VerifyChecksum(d, "_1.si"); err == nil {
+		t.Error("a wrong checksum should fail verification")
+	}
+
+	// truncated file
+	out, _ = d.CreateOutput("_2.si", IO_CONTEXT_DEFAULT)
+	out.WriteInt(1)
+	out.Close()
+	if _, err = VerifyChecksum(d, "_2.si"); err == nil {
+		t.Error("a truncated file should fail verification")
+	}
+}
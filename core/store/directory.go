@@ -35,6 +35,8 @@ type IOContext struct {
 	MergeInfo *MergeInfo
 	FlushInfo *FlushInfo
 	readOnce  bool
+	// the buffer size of BufferedIndexInput, 0 for the default one
+	bufferSize int
 }
 
 func NewIOContextForFlush(flushInfo *FlushInfo) IOContext {
@@ -71,6 +73,18 @@ func NewIOContextForMerge(mergeInfo *MergeInfo) IOContext {
 	}
 }
 
+/*
+Returns a copy of this context, whose BufferedIndexInputs use buffers
+of the given size instead of the default one of the context: larger
+buffers speed sequential reads up, while smaller ones waste less on
+random reads.
+*/
+func (ctx IOContext) WithBufferSize(size int) IOContext {
+	checkBufferSize(size)
+	ctx.bufferSize = size
+	return ctx
+}
+
 func (ctx IOContext) String() string {
 	return fmt.Sprintf("IOContext [context=%v, mergeInfo=%v, flushInfo=%v, readOnce=%v",
 		ctx.context, ctx.MergeInfo, ctx.FlushInfo, ctx.readOnce)
@@ -133,6 +147,10 @@ func NewLockImpl(self Lock) *LockImpl {
 	return &LockImpl{self: self}
 }
 
+func (lock *LockImpl) lockImpl() *LockImpl {
+	return lock
+}
+
 func (lock *LockImpl) ObtainWithin(lockWaitTimeout int64) (locked bool, err error) {
 	lock.failureReason = nil
 	locked, err = lock.self.Obtain()
@@ -205,6 +223,10 @@ func (f *FSLockFactory) getLockDir() string {
 	return f.lockDir
 }
 
+func (f *FSLockFactory) fsLockFactory() *FSLockFactory {
+	return f
+}
+
 func (f *FSLockFactory) Clear(name string) error {
 	panic("invalid")
 }
@@ -232,6 +254,9 @@ type Directory interface {
 	// 	- Returns a value >=0 if the file exists, which specifies its
 	// length.
 	FileLength(name string) (n int64, err error)
+	// Returns the metadata of a file in the directory: its length,
+	// and the checksum of its codec footer if it has one.
+	FileMetadata(name string) (FileMetadata, error)
 	// Creates a new, empty file in the directory with the given name.
 	// Returns a stream writing this file.
 	CreateOutput(name string, ctx IOContext) (out IndexOutput, err error)
@@ -243,6 +268,9 @@ type Directory interface {
 	// again, so some impls might optimize for that. For other impls
 	// the operation can be a noop, for various reasons.
 	Sync(names []string) error
+	// Ensures that directory metadata, such as the files created,
+	// deleted or renamed so far, are moved to stable storage at once.
+	SyncMetadata() error
 	OpenInput(name string, context IOContext) (in IndexInput, err error)
 	// Returns a stream reading an existing file, computing checksum as it reads
 	OpenChecksumInput(name string, ctx IOContext) (ChecksumIndexInput, error)
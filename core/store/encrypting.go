@@ -0,0 +1,327 @@
+package store
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+	"github.com/balzaczyy/golucene/core/util"
+	"hash"
+	"hash/crc32"
+	"io"
+)
+
+// The length of the random IV written at the start of each encrypted file.
+const ENCRYPTION_IV_LENGTH = aes.BlockSize
+
+/*
+Provides the AES key of each file of an EncryptingDirectory. The key
+must be 16, 24 or 32 bytes long, selecting AES-128, AES-192 or
+AES-256, and must be the same when a file is read as when it was
+written.
+*/
+type KeyProvider interface {
+	Key(name string) ([]byte, error)
+}
+
+// Adapts a function to the KeyProvider interface.
+type KeyProviderFunc func(name string) ([]byte, error)
+
+func (f KeyProviderFunc) Key(name string) ([]byte, error) {
+	return f(name)
+}
+
+/*
+A Directory wrapper encrypting the files of the wrapped directory
+with AES in counter (CTR) mode, so that an index is encrypted at rest
+without any change to the codecs.
+
+Each file starts with a random IV of ENCRYPTION_IV_LENGTH bytes,
+followed by the encrypted content, which has the same length as the
+plain one. As CTR mode turns AES into a stream cipher whose key
+stream can be computed from any block, inputs can seek, be cloned and
+sliced as usual, decrypting only the bytes they read.
+
+The checksums of the outputs, and of the checksum inputs, are
+computed on the plain content, so that codec footers are written and
+verified as without encryption.
+
+NOTE: CTR mode provides confidentiality only; a modified file is
+detected by the checksum of its codec footer, if any, not by this
+directory.
+*/
+type EncryptingDirectory struct {
+	Directory
+	keys KeyProvider
+}
+
+func NewEncryptingDirectory(delegate Directory, keys KeyProvider) *EncryptingDirectory {
+	return &EncryptingDirectory{delegate, keys}
+}
+
+func (d *EncryptingDirectory) cipherBlock(name string) (cipher.Block, error) {
+	key, err := d.keys.Key(name)
+	if err != nil {
+		return nil, err
+	}
+	return aes.NewCipher(key)
+}
+
+// Returns the length of the plain content of a file.
+func (d *EncryptingDirectory) FileLength(name string) (int64, error) {
+	n, err := d.Directory.FileLength(name)
+	if err != nil {
+		return 0, err
+	}
+	if n < ENCRYPTION_IV_LENGTH {
+		return 0, fmt.Errorf("file is too short to be encrypted: %v (%v bytes)", name, n)
+	}
+	return n - ENCRYPTION_IV_LENGTH, nil
+}
+
+// Returns the metadata of the plain content of the file.
+func (d *EncryptingDirectory) FileMetadata(name string) (FileMetadata, error) {
+	in, err := d.OpenInput(name, IO_CONTEXT_READ)
+	if err != nil {
+		return FileMetadata{}, err
+	}
+	defer in.Close()
+	return readFileMetadata(name, in)
+}
+
+func (d *EncryptingDirectory) CreateOutput(name string, context IOContext) (out IndexOutput, err error) {
+	block, err := d.cipherBlock(name)
+	if err != nil {
+		return nil, err
+	}
+	iv := make([]byte, ENCRYPTION_IV_LENGTH)
+	if _, err = io.ReadFull(rand.Reader, iv); err != nil {
+		return nil, err
+	}
+	if out, err = d.Directory.CreateOutput(name, context); err != nil {
+		return nil, err
+	}
+	if err = out.WriteBytes(iv); err != nil {
+		util.CloseWhileSuppressingError(out)
+		return nil, err
+	}
+	return newEncryptingIndexOutput(out, cipher.NewCTR(block, iv)), nil
+}
+
+func (d *EncryptingDirectory) OpenInput(name string, context IOContext) (in IndexInput, err error) {
+	block, err := d.cipherBlock(name)
+	if err != nil {
+		return nil, err
+	}
+	if in, err = d.Directory.OpenInput(name, context); err != nil {
+		return nil, err
+	}
+	iv := make([]byte, ENCRYPTION_IV_LENGTH)
+	if in.Length() < ENCRYPTION_IV_LENGTH {
+		err = fmt.Errorf("file is too short to be encrypted: %v (%v bytes)", name, in.Length())
+	} else {
+		err = in.ReadBytes(iv)
+	}
+	if err != nil {
+		util.CloseWhileSuppressingError(in)
+		return nil, err
+	}
+	return newDecryptingIndexInput(fmt.Sprintf("DecryptingIndexInput(%v)", in),
+		in, block, iv, context), nil
+}
+
+// Opens the file decrypting it, computing the checksum of its plain
+// content as it is read.
+func (d *EncryptingDirectory) OpenChecksumInput(name string, context IOContext) (ChecksumIndexInput, error) {
+	in, err := d.OpenInput(name, context)
+	if err != nil {
+		return nil, err
+	}
+	return newBufferedChecksumIndexInput(in), nil
+}
+
+/*
+Copies the plain content of the file src to 'to' under the new file
+name dest, so that it is encrypted again if 'to' encrypts.
+*/
+func (d *EncryptingDirectory) Copy(to Directory, src, dest string, context IOContext) (err error) {
+	var out IndexOutput
+	var in IndexInput
+	if out, err = to.CreateOutput(dest, context); err != nil {
+		return err
+	}
+	if in, err = d.OpenInput(src, context); err == nil {
+		err = out.CopyBytes(in, in.Length())
+	}
+	if err2 := util.Close(out, in); err == nil {
+		err = err2
+	}
+	if err != nil {
+		to.DeleteFile(dest) // ignore error
+	}
+	return err
+}
+
+func (d *EncryptingDirectory) String() string {
+	return fmt.Sprintf("EncryptingDirectory(%v)", d.Directory)
+}
+
+// Encrypts the bytes written before passing them to the delegate.
+type encryptingIndexOutput struct {
+	*IndexOutputImpl
+	delegate IndexOutput
+	stream   cipher.Stream
+	crc      hash.Hash32 // of the plain content
+	buf      []byte
+}
+
+func newEncryptingIndexOutput(delegate IndexOutput, stream cipher.Stream) *encryptingIndexOutput {
+	ans := &encryptingIndexOutput{
+		delegate: delegate,
+		stream:   stream,
+		crc:      newBufferedChecksum(crc32.NewIEEE()),
+		buf:      make([]byte, BUFFER_SIZE),
+	}
+	ans.IndexOutputImpl = NewIndexOutput(ans)
+	return ans
+}
+
+func (out *encryptingIndexOutput) WriteByte(b byte) error {
+	return out.WriteBytes([]byte{b})
+}
+
+func (out *encryptingIndexOutput) WriteBytes(p []byte) error {
+	out.crc.Write(p)
+	for len(p) > 0 {
+		n := min(len(p), len(out.buf))
+		out.stream.XORKeyStream(out.buf[:n], p[:n])
+		if err := out.delegate.WriteBytes(out.buf[:n]); err != nil {
+			return err
+		}
+		p = p[n:]
+	}
+	return nil
+}
+
+func (out *encryptingIndexOutput) FilePointer() int64 {
+	return out.delegate.FilePointer() - ENCRYPTION_IV_LENGTH
+}
+
+func (out *encryptingIndexOutput) Checksum() int64 {
+	return int64(out.crc.Sum32())
+}
+
+func (out *encryptingIndexOutput) Close() error {
+	return out.delegate.Close()
+}
+
+func (out *encryptingIndexOutput) String() string {
+	return fmt.Sprintf("EncryptingIndexOutput(%v)", out.delegate)
+}
+
+/*
+Decrypts the bytes read from the delegate, which starts with the IV.
+The key stream is positioned at the offset of each read, so that the
+input can be read at random.
+*/
+type DecryptingIndexInput struct {
+	*BufferedIndexInput
+	delegate IndexInput
+	block    cipher.Block
+	iv       []byte
+	// is this instance a clone and hence does not own the delegate to close it
+	isClone bool
+	// start offset in the plain content: non-zero in the slice case
+	off int64
+	// end offset (start+length)
+	end int64
+}
+
+func newDecryptingIndexInput(desc string, delegate IndexInput,
+	block cipher.Block, iv []byte, ctx IOContext) *DecryptingIndexInput {
+
+	ans := &DecryptingIndexInput{
+		delegate: delegate,
+		block:    block,
+		iv:       iv,
+		end:      delegate.Length() - ENCRYPTION_IV_LENGTH,
+	}
+	ans.BufferedIndexInput = newBufferedIndexInput(ans, desc, ctx)
+	return ans
+}
+
+func (in *DecryptingIndexInput) Close() error {
+	if !in.isClone {
+		return in.delegate.Close()
+	}
+	return nil
+}
+
+func (in *DecryptingIndexInput) Clone() IndexInput {
+	ans := &DecryptingIndexInput{
+		in.BufferedIndexInput.Clone(),
+		in.delegate.Clone(),
+		in.block,
+		in.iv,
+		true,
+		in.off,
+		in.end,
+	}
+	ans.spi = ans
+	return ans
+}
+
+func (in *DecryptingIndexInput) Slice(desc string, offset, length int64) (IndexInput, error) {
+	assert2(offset >= 0 && length >= 0 && offset+length <= in.Length(),
+		"slice() %v out of bounds: %v", desc, in)
+	ans := &DecryptingIndexInput{
+		delegate: in.delegate.Clone(),
+		block:    in.block,
+		iv:       in.iv,
+		isClone:  true,
+		off:      in.off + offset,
+		end:      in.off + offset + length,
+	}
+	ans.BufferedIndexInput = newBufferedIndexInputBySize(ans, desc, in.bufferSize)
+	return ans, nil
+}
+
+func (in *DecryptingIndexInput) Length() int64 {
+	return in.end - in.off
+}
+
+func (in *DecryptingIndexInput) readInternal(buf []byte) error {
+	position := in.off + in.FilePointer()
+	if position+int64(len(buf)) > in.end {
+		return fmt.Errorf("read past EOF: %v", in)
+	}
+	if err := in.delegate.Seek(ENCRYPTION_IV_LENGTH + position); err != nil {
+		return err
+	}
+	if err := in.delegate.ReadBytes(buf); err != nil {
+		return err
+	}
+	stream := cipher.NewCTR(in.block, ctrCounter(in.iv, position/aes.BlockSize))
+	if skip := int(position % aes.BlockSize); skip > 0 {
+		var discard [aes.BlockSize]byte
+		stream.XORKeyStream(discard[:skip], discard[:skip])
+	}
+	stream.XORKeyStream(buf, buf)
+	return nil
+}
+
+func (in *DecryptingIndexInput) seekInternal(pos int64) error { return nil }
+
+// Returns the counter of the given block: the IV plus the block
+// index, as a 128 bits big endian number, as incremented by CTR mode.
+func ctrCounter(iv []byte, blockIndex int64) []byte {
+	ans := make([]byte, len(iv))
+	copy(ans, iv)
+	carry := uint64(blockIndex)
+	for i := len(ans) - 1; i >= 0 && carry != 0; i-- {
+		sum := uint64(ans[i]) + carry&0xff
+		ans[i] = byte(sum)
+		carry = carry>>8 + sum>>8
+	}
+	return ans
+}
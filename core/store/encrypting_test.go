@@ -0,0 +1,118 @@
+package store
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+)
+
+func TestEncryptingDirectory(t *testing.T) {
+	key := []byte("0123456789abcdef")
+	delegate := NewByteBuffersDirectory()
+	d := NewEncryptingDirectory(delegate, KeyProviderFunc(func(name string) ([]byte, error) {
+		return key, nil
+	}))
+
+	data := make([]byte, 5000)
+	rand.New(rand.NewSource(42)).Read(data)
+	out, err := d.CreateOutput("_0.fdt", IO_CONTEXT_DEFAULT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = out.WriteBytes(data); err != nil {
+		t.Fatal(err)
+	}
+	assertEquals(t, out.FilePointer(), int64(len(data)))
+	checksum := out.Checksum()
+	if err = out.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the delegate holds the IV and the encrypted content
+	n, _ := delegate.FileLength("_0.fdt")
+	assertEquals(t, n, int64(len(data)+ENCRYPTION_IV_LENGTH))
+	n, _ = d.FileLength("_0.fdt")
+	assertEquals(t, n, int64(len(data)))
+	raw, _ := delegate.OpenInput("_0.fdt", IO_CONTEXT_READ)
+	encrypted := make([]byte, raw.Length())
+	raw.ReadBytes(encrypted)
+	raw.Close()
+	if bytes.Contains(encrypted, data[:64]) {
+		t.Error("content should be encrypted")
+	}
+
+	in, err := d.OpenInput("_0.fdt", IO_CONTEXT_READ)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer in.Close()
+	buf := make([]byte, len(data))
+	if err = in.ReadBytes(buf); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf, data) {
+		t.Fatal("decrypted content differs")
+	}
+
+	// random access through seeks, clones and slices
+	clone := in.Clone()
+	for _, pos := range []int64{4999, 17, 0, 1023, 1024, 3333} {
+		if err = clone.Seek(pos); err != nil {
+			t.Fatal(err)
+		}
+		b, err := clone.ReadByte()
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEquals(t, b, data[pos])
+	}
+	slice, err := in.Slice("slice", 1001, 2000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = slice.Seek(999); err != nil {
+		t.Fatal(err)
+	}
+	buf = make([]byte, 100)
+	if err = slice.ReadBytes(buf); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf, data[2000:2100]) {
+		t.Error("slice content differs")
+	}
+
+	// checksums are computed on the plain content
+	cin, err := d.OpenChecksumInput("_0.fdt", IO_CONTEXT_READ)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf = make([]byte, len(data))
+	cin.ReadBytes(buf)
+	assertEquals(t, cin.Checksum(), checksum)
+	cin.Close()
+
+	// a wrong key does not decrypt
+	key = []byte("fedcba9876543210")
+	other, err := d.OpenInput("_0.fdt", IO_CONTEXT_READ)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other.ReadBytes(buf)
+	other.Close()
+	if bytes.Equal(buf, data) {
+		t.Error("content should not decrypt with another key")
+	}
+}
+
+func TestCTRCounter(t *testing.T) {
+	iv := make([]byte, ENCRYPTION_IV_LENGTH)
+	iv[15], iv[14] = 0xff, 0xff
+	ctr := ctrCounter(iv, 1)
+	if !bytes.Equal(ctr[12:], []byte{0, 1, 0, 0}) {
+		t.Errorf("carry not propagated: %v", ctr)
+	}
+	ctr = ctrCounter(iv, 0x1234)
+	if !bytes.Equal(ctr[12:], []byte{0, 1, 0x12, 0x33}) {
+		t.Errorf("wrong counter: %v", ctr)
+	}
+}
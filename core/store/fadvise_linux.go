@@ -0,0 +1,33 @@
+//go:build amd64 || arm64 || loong64 || riscv64
+
+package store
+
+import (
+	"os"
+	"syscall"
+)
+
+const (
+	_POSIX_FADV_NORMAL     = 0
+	_POSIX_FADV_RANDOM     = 1
+	_POSIX_FADV_SEQUENTIAL = 2
+)
+
+func posixFadvise(f *os.File, advice int) error {
+	// offset and length 0 advise the whole file
+	_, _, errno := syscall.Syscall6(syscall.SYS_FADVISE64, f.Fd(), 0, 0, uintptr(advice), 0, 0)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+func fadvise(f *os.File, pattern AccessPattern) error {
+	switch pattern {
+	case ACCESS_PATTERN_SEQUENTIAL:
+		return posixFadvise(f, _POSIX_FADV_SEQUENTIAL)
+	case ACCESS_PATTERN_RANDOM:
+		return posixFadvise(f, _POSIX_FADV_RANDOM)
+	}
+	return posixFadvise(f, _POSIX_FADV_NORMAL)
+}
@@ -0,0 +1,11 @@
+//go:build !linux || !(amd64 || arm64 || loong64 || riscv64)
+
+package store
+
+import (
+	"os"
+)
+
+func fadvise(f *os.File, pattern AccessPattern) error {
+	return nil
+}
@@ -0,0 +1,182 @@
+package store
+
+import (
+	"fmt"
+	"github.com/balzaczyy/golucene/core/util"
+	"sort"
+	"strings"
+)
+
+// store/FileSwitchDirectory.java
+
+/*
+Expert: A Directory instance that switches files between two other
+Directory instances.
+
+Files with the specified extensions are placed in the primary
+directory; others are placed in the secondary directory. The provided
+map is not copied, so you must not change it once passed to this
+class, and must allow multiple goroutines to call its get method.
+
+For example, the terms index and postings can be kept on a fast
+local disk while the stored fields go to a bigger, cheaper one:
+
+	primary := map[string]bool{"tip": true, "doc": true}
+	d := NewFileSwitchDirectory(primary, ssdDir, hddDir, true)
+
+Locking is delegated to the primary directory.
+*/
+type FileSwitchDirectory struct {
+	Directory
+	secondaryDir      Directory
+	primaryExtensions map[string]bool
+	doClose           bool
+}
+
+func NewFileSwitchDirectory(primaryExtensions map[string]bool,
+	primaryDir, secondaryDir Directory, doClose bool) *FileSwitchDirectory {
+	return &FileSwitchDirectory{
+		Directory:         primaryDir,
+		secondaryDir:      secondaryDir,
+		primaryExtensions: primaryExtensions,
+		doClose:           doClose,
+	}
+}
+
+// Return the primary directory
+func (d *FileSwitchDirectory) PrimaryDir() Directory {
+	return d.Directory
+}
+
+// Return the secondary directory
+func (d *FileSwitchDirectory) SecondaryDir() Directory {
+	return d.secondaryDir
+}
+
+// Closes both directories if doClose was set; does nothing otherwise.
+func (d *FileSwitchDirectory) Close() error {
+	if d.doClose {
+		return util.Close(d.Directory, d.secondaryDir)
+	}
+	return nil
+}
+
+func (d *FileSwitchDirectory) ListAll() ([]string, error) {
+	files := make(map[string]bool)
+	// LUCENE-3380: either or both of our dirs could be FSDirs, but if
+	// one underlying delegate is an FSDir and mkdirs() has not yet been
+	// called, because so far everything is written to the other, in
+	// this case, we don't want to return a NoSuchDirectoryError
+	var exc error
+	all, err := d.Directory.ListAll()
+	if err != nil {
+		if _, ok := err.(*NoSuchDirectoryError); !ok {
+			return nil, err
+		}
+		exc = err
+	}
+	for _, f := range all {
+		files[f] = true
+	}
+	if all, err = d.secondaryDir.ListAll(); err != nil {
+		if _, ok := err.(*NoSuchDirectoryError); !ok {
+			return nil, err
+		}
+		// however, if there are no files, then the directory truly
+		// does not "exist"
+		if exc != nil {
+			return nil, exc
+		}
+		if len(files) == 0 {
+			return nil, err
+		}
+	}
+	for _, f := range all {
+		files[f] = true
+	}
+	// we got NoSuchDirectoryError from the primary, and the secondary
+	// is empty.
+	if exc != nil && len(files) == 0 {
+		return nil, exc
+	}
+	all = make([]string, 0, len(files))
+	for f, _ := range files {
+		all = append(all, f)
+	}
+	sort.Strings(all)
+	return all, nil
+}
+
+// Utility method to return a file's extension.
+func fileExtension(name string) string {
+	if i := strings.LastIndex(name, "."); i != -1 {
+		return name[i+1:]
+	}
+	return ""
+}
+
+// Returns the directory the given file name should be stored in.
+func (d *FileSwitchDirectory) dir(name string) Directory {
+	if d.primaryExtensions[fileExtension(name)] {
+		return d.Directory
+	}
+	return d.secondaryDir
+}
+
+func (d *FileSwitchDirectory) FileExists(name string) bool {
+	return d.dir(name).FileExists(name)
+}
+
+func (d *FileSwitchDirectory) DeleteFile(name string) error {
+	return d.dir(name).DeleteFile(name)
+}
+
+func (d *FileSwitchDirectory) FileLength(name string) (int64, error) {
+	return d.dir(name).FileLength(name)
+}
+
+func (d *FileSwitchDirectory) FileMetadata(name string) (FileMetadata, error) {
+	return d.dir(name).FileMetadata(name)
+}
+
+func (d *FileSwitchDirectory) CreateOutput(name string, context IOContext) (IndexOutput, error) {
+	return d.dir(name).CreateOutput(name, context)
+}
+
+func (d *FileSwitchDirectory) Sync(names []string) error {
+	var primaryNames, secondaryNames []string
+	for _, name := range names {
+		if d.primaryExtensions[fileExtension(name)] {
+			primaryNames = append(primaryNames, name)
+		} else {
+			secondaryNames = append(secondaryNames, name)
+		}
+	}
+	if err := d.Directory.Sync(primaryNames); err != nil {
+		return err
+	}
+	return d.secondaryDir.Sync(secondaryNames)
+}
+
+func (d *FileSwitchDirectory) SyncMetadata() error {
+	if err := d.Directory.SyncMetadata(); err != nil {
+		return err
+	}
+	return d.secondaryDir.SyncMetadata()
+}
+
+func (d *FileSwitchDirectory) OpenInput(name string, context IOContext) (IndexInput, error) {
+	return d.dir(name).OpenInput(name, context)
+}
+
+func (d *FileSwitchDirectory) OpenChecksumInput(name string, context IOContext) (ChecksumIndexInput, error) {
+	return d.dir(name).OpenChecksumInput(name, context)
+}
+
+func (d *FileSwitchDirectory) Copy(to Directory, src, dest string, context IOContext) error {
+	return d.dir(src).Copy(to, src, dest, context)
+}
+
+func (d *FileSwitchDirectory) String() string {
+	return fmt.Sprintf("FileSwitchDirectory(primary=%v, secondary=%v)", d.Directory, d.secondaryDir)
+}
@@ -0,0 +1,50 @@
+package store
+
+import (
+	"testing"
+)
+
+func TestFileSwitchDirectory(t *testing.T) {
+	primary, secondary := NewByteBuffersDirectory(), NewByteBuffersDirectory()
+	d := NewFileSwitchDirectory(map[string]bool{"tip": true, "doc": true}, primary, secondary, true)
+
+	writeTestFile(t, d, "_0.tip", IO_CONTEXT_DEFAULT, 10)
+	writeTestFile(t, d, "_0.doc", IO_CONTEXT_DEFAULT, 20)
+	writeTestFile(t, d, "_0.fdt", IO_CONTEXT_DEFAULT, 30)
+	writeTestFile(t, d, "segments_1", IO_CONTEXT_DEFAULT, 40)
+
+	assertEquals(t, primary.FileExists("_0.tip"), true)
+	assertEquals(t, primary.FileExists("_0.doc"), true)
+	assertEquals(t, primary.FileExists("_0.fdt"), false)
+	assertEquals(t, secondary.FileExists("_0.fdt"), true)
+	assertEquals(t, secondary.FileExists("segments_1"), true)
+
+	all, err := d.ListAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEquals(t, len(all), 4)
+	assertEquals(t, all[0], "_0.doc")
+	assertEquals(t, all[3], "segments_1")
+
+	n, err := d.FileLength("_0.fdt")
+	assertEquals(t, err, nil)
+	assertEquals(t, n, int64(30))
+	in, err := d.OpenInput("_0.doc", IO_CONTEXT_READ)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEquals(t, in.Length(), int64(20))
+	in.Close()
+
+	if err = d.Sync(all); err != nil {
+		t.Fatal(err)
+	}
+	if err = d.DeleteFile("_0.tip"); err != nil {
+		t.Fatal(err)
+	}
+	assertEquals(t, primary.FileExists("_0.tip"), false)
+	if err = d.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
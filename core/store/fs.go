@@ -57,14 +57,21 @@ func newFSDirectory(spi FSDirectorySPI, path string) (d *FSDirectory, err error)
 		return d, newNoSuchDirectoryError(fmt.Sprintf("file '%v' exists but is not a directory", path))
 	}
 
-	// TODO default to native lock factory
-	d.SetLockFactory(NewSimpleFSLockFactory(path))
+	if NATIVE_LOCKS_SUPPORTED {
+		d.SetLockFactory(NewNativeFSLockFactory(path))
+	} else {
+		d.SetLockFactory(NewSimpleFSLockFactory(path))
+	}
 	return d, nil
 }
 
+/*
+Creates an FSDirectory instance, trying to pick the best
+implementation given the current environment. Currently it returns a
+NIOFSDirectory, whose inputs can be read concurrently.
+*/
 func OpenFSDirectory(path string) (d Directory, err error) {
-	// TODO support native implementations
-	super, err := NewSimpleFSDirectory(path)
+	super, err := NewNIOFSDirectory(path)
 	if err != nil {
 		return nil, err
 	}
@@ -75,9 +82,13 @@ func (d *FSDirectory) SetLockFactory(lockFactory LockFactory) {
 	d.BaseDirectory.SetLockFactory(lockFactory)
 
 	// for filesystem based LockFactory, delete the lockPrefix, if the locks are placed
-	// in index dir. If no index dir is given, set ourselves
+	// in index dir. If no index dir is given, set ourselves. Wrappers
+	// like SleepingLockFactory return the factory they wrap, or nil.
 	// TODO change FSDirectory to interface
-	if lf, ok := lockFactory.(*SimpleFSLockFactory); ok {
+	if lf, ok := lockFactory.(interface {
+		fsLockFactory() *FSLockFactory
+	}); ok && lf.fsLockFactory() != nil {
+		lf := lf.fsLockFactory()
 		if lf.lockDir == "" {
 			lf.lockDir = d.path
 			lf.lockPrefix = ""
@@ -44,8 +44,8 @@ func TestClone(t *testing.T) {
 	fmt.Println("Before clone", in)
 	clone := in.Clone()
 	fmt.Println("After clone", clone)
-	if _, ok := clone.(*SimpleFSIndexInput); !ok {
-		t.Error("Clone() should return *SimpleFSIndexInput.")
+	if _, ok := clone.(*NIOFSIndexInput); !ok {
+		t.Error("Clone() should return *NIOFSIndexInput.")
 	}
 	clone.Seek(indexStartFP)
 	fmt.Println("After clone.Seek()", clone)
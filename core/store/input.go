@@ -56,9 +56,18 @@ const (
 	MERGE_BUFFER_SIZE = 4096
 )
 
+/*
+Returns the buffer size of a BufferedIndexInput opened with the given
+context: the one set with IOContext.WithBufferSize() if any, otherwise
+MERGE_BUFFER_SIZE for the sequential reads of merges and of files read
+once, and BUFFER_SIZE for the random reads of searches.
+*/
 func bufferSize(context IOContext) int {
-	switch context.context {
-	case IO_CONTEXT_TYPE_MERGE:
+	if context.bufferSize > 0 {
+		return context.bufferSize
+	}
+	switch {
+	case context.context == IO_CONTEXT_TYPE_MERGE, context.readOnce:
 		// The normal read buffer size defaults to 1024, but
 		// increasing this during merging seems to yield
 		// performance gains.  However we don't want to increase
@@ -134,7 +143,7 @@ func (in *ByteArrayDataInput) ReadInt() (n int32, err error) {
 func (in *ByteArrayDataInput) ReadLong() (n int64, err error) {
 	i1, _ := in.ReadInt()
 	i2, _ := in.ReadInt()
-	return (int64(i1) << 32) | int64(i2), nil
+	return (int64(i1) << 32) | (int64(i2) & 0xFFFFFFFF), nil
 }
 
 func (in *ByteArrayDataInput) ReadVInt() (n int32, err error) {
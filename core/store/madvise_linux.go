@@ -0,0 +1,20 @@
+package store
+
+import (
+	"syscall"
+)
+
+// Hints the kernel how the mapping will be accessed.
+func madvise(mapping []byte, context IOContext) error {
+	return syscall.Madvise(mapping, advice(context))
+}
+
+func advice(context IOContext) int {
+	switch context.AccessPattern() {
+	case ACCESS_PATTERN_SEQUENTIAL:
+		return syscall.MADV_SEQUENTIAL
+	case ACCESS_PATTERN_NORMAL:
+		return syscall.MADV_NORMAL
+	}
+	return syscall.MADV_RANDOM
+}
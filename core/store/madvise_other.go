@@ -0,0 +1,9 @@
+//go:build unix && !linux
+
+package store
+
+// The syscall package only exposes madvise on Linux; mappings are
+// left to the default read-ahead of the OS elsewhere.
+func madvise(mapping []byte, context IOContext) error {
+	return nil
+}
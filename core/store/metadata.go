@@ -0,0 +1,74 @@
+package store
+
+import (
+	"github.com/balzaczyy/golucene/core/codec"
+	"os"
+	"runtime"
+)
+
+/*
+The metadata of a file of a Directory, which backup and replication
+tools compare to find the files which differ between two directories.
+*/
+type FileMetadata struct {
+	Name   string
+	Length int64
+	// The checksum recorded in the codec footer of the file, only set
+	// if HasChecksum is true.
+	Checksum    int64
+	HasChecksum bool
+}
+
+/*
+Returns the metadata of a file, read from the given input: the
+checksum is the one recorded in its codec footer, if any, so only the
+last FOOTER_LENGTH bytes of the file are read. A file without a valid
+footer has no checksum, but failing to read it is an error.
+*/
+func readFileMetadata(name string, in IndexInput) (FileMetadata, error) {
+	ans := FileMetadata{Name: name, Length: in.Length()}
+	cs, err := codec.RetrieveChecksum(in)
+	if _, ok := err.(*codec.CorruptIndexError); ok {
+		return ans, nil
+	} else if err != nil {
+		return FileMetadata{}, err
+	}
+	ans.Checksum, ans.HasChecksum = cs, true
+	return ans, nil
+}
+
+// Returns the metadata of a file, opening it with the directory.
+func (d *DirectoryImpl) FileMetadata(name string) (FileMetadata, error) {
+	in, err := d.spi.OpenInput(name, IO_CONTEXT_READ)
+	if err != nil {
+		return FileMetadata{}, err
+	}
+	defer in.Close()
+	return readFileMetadata(name, in)
+}
+
+// Does nothing, as the files are not stored in a file system.
+func (d *DirectoryImpl) SyncMetadata() error {
+	return nil
+}
+
+/*
+Fsyncs the directory itself, so that the files created, deleted or
+renamed in it so far survive a machine or OS crash. Windows can not
+open a directory for syncing, and makes its metadata durable anyway.
+*/
+func (d *FSDirectory) SyncMetadata() error {
+	d.EnsureOpen()
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+	f, err := os.Open(d.path)
+	if err != nil {
+		return err
+	}
+	err = f.Sync()
+	if err2 := f.Close(); err == nil {
+		err = err2
+	}
+	return err
+}
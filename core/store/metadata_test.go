@@ -0,0 +1,92 @@
+package store
+
+import (
+	"github.com/balzaczyy/golucene/core/codec"
+	"io"
+	"testing"
+)
+
+func writeFooterFile(t *testing.T, d Directory, name string) int64 {
+	out, err := d.CreateOutput(name, IO_CONTEXT_DEFAULT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	codec.WriteHeader(out, "test", 1)
+	out.WriteString("hello world")
+	if err = codec.WriteFooter(out); err != nil {
+		t.Fatal(err)
+	}
+	if err = out.Close(); err != nil {
+		t.Fatal(err)
+	}
+	cs, err := VerifyChecksum(d, name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cs
+}
+
+func TestFileMetadata(t *testing.T) {
+	fsDir, err := OpenFSDirectory(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fsDir.Close()
+	key := []byte("0123456789abcdef")
+	encrypted := NewEncryptingDirectory(NewByteBuffersDirectory(),
+		KeyProviderFunc(func(name string) ([]byte, error) { return key, nil }))
+	nrt := NewNRTCachingDirectory(NewByteBuffersDirectory(), 1, 1)
+
+	for _, d := range []Directory{fsDir, NewByteBuffersDirectory(), encrypted, nrt} {
+		cs := writeFooterFile(t, d, "_0.si")
+		writeTestFile(t, d, "_0.fdt", IO_CONTEXT_DEFAULT, 3)
+
+		md, err := d.FileMetadata("_0.si")
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEquals(t, md.Name, "_0.si")
+		n, _ := d.FileLength("_0.si")
+		assertEquals(t, md.Length, n)
+		assertEquals(t, md.HasChecksum, true)
+		assertEquals(t, md.Checksum, cs)
+
+		// files without a footer have no checksum
+		md, err = d.FileMetadata("_0.fdt")
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEquals(t, md.Length, int64(3))
+		assertEquals(t, md.HasChecksum, false)
+
+		if _, err = d.FileMetadata("_1.si"); err == nil {
+			t.Errorf("%v: metadata of a missing file should fail", d)
+		}
+		if err = d.SyncMetadata(); err != nil {
+			t.Errorf("%v: %v", d, err)
+		}
+	}
+}
+
+// An input failing to read its ints, like on a truncated read.
+type failingReadInput struct {
+	IndexInput
+}
+
+func (in failingReadInput) ReadInt() (int32, error) {
+	return 0, io.ErrUnexpectedEOF
+}
+
+func TestFileMetadataReadError(t *testing.T) {
+	d := NewByteBuffersDirectory()
+	writeFooterFile(t, d, "_0.si")
+	in, err := d.OpenInput("_0.si", IO_CONTEXT_READ)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer in.Close()
+	// only a missing or invalid footer means no checksum
+	if _, err = readFileMetadata("_0.si", failingReadInput{in}); err != io.ErrUnexpectedEOF {
+		t.Errorf("expected the read error, but was %v", err)
+	}
+}
@@ -0,0 +1,274 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+)
+
+// store/MMapDirectory.java
+
+/*
+The default maximum size of a single mapping: 1 GB on 64 bit
+platforms, 256 MB on 32 bit ones, where the address space is scarce.
+*/
+var DEFAULT_MAX_CHUNK_SIZE = defaultMaxChunkSize()
+
+func defaultMaxChunkSize() int {
+	if strconv.IntSize == 64 {
+		return 1 << 30
+	}
+	return 1 << 28
+}
+
+/*
+File-based Directory implementation that uses mmap for reading, and
+FSIndexOutput for writing.
+
+Files are mapped read-only into the address space in chunks of at
+most MaxChunkSize() bytes, so that files larger than 2 GB (or larger
+than any contiguous free range of the address space) can be mapped.
+Reading from a mapped file does not copy the bytes into a buffer
+first like SimpleFSDirectory, which saves a copy and the system calls
+for large indexes. Each mapping is advised to the OS according to
+the IOContext it was opened with: sequential for merges and files
+read once, random otherwise.
+
+The mappings of a file are released when the IndexInput returned by
+OpenInput() is closed; reading from it or any of its clones and
+slices afterwards returns an error instead of crashing the process.
+Closing waits for the reads in progress on other goroutines.
+
+Mapping is supported on unix platforms, where MMAP_SUPPORTED is
+true; OpenInput() fails elsewhere.
+*/
+type MMapDirectory struct {
+	*FSDirectory
+	chunkSizePower uint
+}
+
+// Create a new MMapDirectory for the named location, with the
+// default maximum chunk size.
+func NewMMapDirectory(path string) (*MMapDirectory, error) {
+	return NewMMapDirectoryWithChunkSize(path, DEFAULT_MAX_CHUNK_SIZE)
+}
+
+/*
+Create a new MMapDirectory for the named location, mapping files in
+chunks of at most maxChunkSize bytes, rounded down to a power of 2.
+Smaller chunks lower the need of contiguous address space, at the cost
+of more mappings per file.
+*/
+func NewMMapDirectoryWithChunkSize(path string, maxChunkSize int) (d *MMapDirectory, err error) {
+	assert2(maxChunkSize > 0, "Maximum chunk size for mmap must be >0")
+	d = &MMapDirectory{chunkSizePower: uint(bits.Len(uint(maxChunkSize)) - 1)}
+	d.FSDirectory, err = newFSDirectory(d, path)
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Returns the current mmap chunk size.
+func (d *MMapDirectory) MaxChunkSize() int {
+	return 1 << d.chunkSizePower
+}
+
+// Creates an IndexInput for the file with the given name.
+func (d *MMapDirectory) OpenInput(name string, context IOContext) (IndexInput, error) {
+	d.EnsureOpen()
+	fpath := filepath.Join(d.path, name)
+	f, err := os.Open(fpath)
+	if err != nil {
+		return nil, err
+	}
+	// the mappings stay valid once the file is closed
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	guard, err := d.mapChunks(f, fi.Size(), context)
+	if err != nil {
+		return nil, err
+	}
+	return newMMapIndexInput(fmt.Sprintf("MMapIndexInput(path='%v')", fpath),
+		guard, d.chunkSizePower, 0, fi.Size()), nil
+}
+
+// Maps the file in chunks of 1<<chunkSizePower bytes, the last one
+// being shorter.
+func (d *MMapDirectory) mapChunks(f *os.File, length int64, context IOContext) (*mmapGuard, error) {
+	chunkSize := int64(1) << d.chunkSizePower
+	n := int((length + chunkSize - 1) >> d.chunkSizePower)
+	if n == 0 {
+		// an empty slice stands for the empty file, which cannot be
+		// mapped, so that reading from it hits EOF
+		n = 1
+	}
+	guard := &mmapGuard{chunks: make([][]byte, n), mappings: make([][]byte, n)}
+	for i := range guard.chunks {
+		offset := int64(i) << d.chunkSizePower
+		size := min(chunkSize, length-offset)
+		if size <= 0 {
+			continue
+		}
+		var err error
+		guard.chunks[i], guard.mappings[i], err = mmap(f, offset, int(size), context)
+		if err != nil {
+			guard.unmap()
+			return nil, fmt.Errorf("mmap failed for %v at offset %v: %v", f.Name(), offset, err)
+		}
+	}
+	return guard, nil
+}
+
+func (d *MMapDirectory) String() string {
+	return fmt.Sprintf("MMapDirectory@%v", d.DirectoryImpl.String())
+}
+
+/*
+The mappings of a file, shared by the MMapIndexInput which opened it,
+and all its clones and slices. Reads hold the read lock, so that the
+mappings can not be released while in use.
+*/
+type mmapGuard struct {
+	sync.RWMutex
+	chunks   [][]byte // nil once unmapped
+	mappings [][]byte // page aligned mappings of the chunks
+}
+
+func (g *mmapGuard) unmap() (err error) {
+	g.Lock()
+	defer g.Unlock()
+	for _, mapping := range g.mappings {
+		if mapping != nil {
+			if err2 := munmap(mapping); err == nil {
+				err = err2
+			}
+		}
+	}
+	g.chunks, g.mappings = nil, nil
+	return
+}
+
+// store/ByteBufferIndexInput.java
+
+/*
+IndexInput over the memory mapped chunks of a file. A slice reads
+the range [off, off+length) of the same chunks.
+*/
+type MMapIndexInput struct {
+	*IndexInputImpl
+	guard *mmapGuard
+	// is this instance a clone and hence does not own the mappings
+	isClone        bool
+	chunkSizePower uint
+	chunkSizeMask  int64
+	// start offset: non-zero in the slice case
+	off    int64
+	length int64
+	// current position, relative to off
+	pos int64
+}
+
+func newMMapIndexInput(desc string, guard *mmapGuard, chunkSizePower uint, off, length int64) *MMapIndexInput {
+	ans := &MMapIndexInput{
+		guard:          guard,
+		chunkSizePower: chunkSizePower,
+		chunkSizeMask:  int64(1)<<chunkSizePower - 1,
+		off:            off,
+		length:         length,
+	}
+	ans.IndexInputImpl = NewIndexInputImpl(desc, ans)
+	return ans
+}
+
+var errMMapClosed = errors.New("already closed")
+
+func (in *MMapIndexInput) ReadByte() (byte, error) {
+	in.guard.RLock()
+	defer in.guard.RUnlock()
+	if in.guard.chunks == nil {
+		return 0, fmt.Errorf("%v: %v", errMMapClosed, in)
+	}
+	if in.pos >= in.length {
+		return 0, fmt.Errorf("read past EOF: %v", in)
+	}
+	p := in.off + in.pos
+	in.pos++
+	return in.guard.chunks[p>>in.chunkSizePower][p&in.chunkSizeMask], nil
+}
+
+func (in *MMapIndexInput) ReadBytes(buf []byte) error {
+	in.guard.RLock()
+	defer in.guard.RUnlock()
+	if in.guard.chunks == nil {
+		return fmt.Errorf("%v: %v", errMMapClosed, in)
+	}
+	if int64(len(buf)) > in.length-in.pos {
+		return fmt.Errorf("read past EOF: %v", in)
+	}
+	for offset := 0; offset < len(buf); {
+		p := in.off + in.pos
+		n := copy(buf[offset:], in.guard.chunks[p>>in.chunkSizePower][p&in.chunkSizeMask:])
+		offset += n
+		in.pos += int64(n)
+	}
+	return nil
+}
+
+func (in *MMapIndexInput) FilePointer() int64 {
+	return in.pos
+}
+
+func (in *MMapIndexInput) Seek(pos int64) error {
+	if pos < 0 || pos > in.length {
+		return fmt.Errorf("seek past EOF (pos=%v): %v", pos, in)
+	}
+	in.pos = pos
+	return nil
+}
+
+func (in *MMapIndexInput) Length() int64 {
+	return in.length
+}
+
+func (in *MMapIndexInput) Clone() IndexInput {
+	ans := newMMapIndexInput(in.desc, in.guard, in.chunkSizePower, in.off, in.length)
+	ans.isClone = true
+	ans.pos = in.pos
+	return ans
+}
+
+func (in *MMapIndexInput) Slice(desc string, offset, length int64) (IndexInput, error) {
+	in.guard.RLock()
+	defer in.guard.RUnlock()
+	if in.guard.chunks == nil {
+		return nil, fmt.Errorf("%v: %v", errMMapClosed, in)
+	}
+	if offset < 0 || length < 0 || offset+length > in.length {
+		return nil, fmt.Errorf("slice() %v out of bounds: offset=%v,length=%v,fileLength=%v: %v",
+			desc, offset, length, in.length, in)
+	}
+	ans := newMMapIndexInput(fmt.Sprintf("%v [slice=%v]", in.desc, desc),
+		in.guard, in.chunkSizePower, in.off+offset, length)
+	ans.isClone = true
+	return ans, nil
+}
+
+/*
+Releases the mappings of the file if this is the input returned by
+OpenInput(), after which reading from any of its clones and slices
+fails. Closing a clone or a slice does nothing.
+*/
+func (in *MMapIndexInput) Close() error {
+	if in.isClone {
+		return nil
+	}
+	return in.guard.unmap()
+}
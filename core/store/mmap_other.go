@@ -0,0 +1,21 @@
+//go:build !unix
+
+package store
+
+import (
+	"errors"
+	"os"
+)
+
+// True if MMapDirectory can map files on this platform.
+const MMAP_SUPPORTED = false
+
+var errMMapUnsupported = errors.New("mmap is not supported on this platform")
+
+func mmap(f *os.File, offset int64, length int, context IOContext) (chunk, mapping []byte, err error) {
+	return nil, nil, errMMapUnsupported
+}
+
+func munmap(mapping []byte) error {
+	return errMMapUnsupported
+}
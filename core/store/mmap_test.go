@@ -0,0 +1,158 @@
+package store
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestMMapDirectory(t *testing.T) {
+	if !MMAP_SUPPORTED {
+		t.Skip("mmap is not supported on this platform")
+	}
+	// a tiny chunk size, so that reads cross chunk boundaries
+	dir, err := NewMMapDirectoryWithChunkSize(t.TempDir(), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dir.Close()
+	assertEquals(t, dir.MaxChunkSize(), 8)
+
+	out, err := dir.CreateOutput("a.bin", IO_CONTEXT_DEFAULT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := make([]byte, 100)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	if err = out.WriteBytes(data); err != nil {
+		t.Fatal(err)
+	}
+	if err = out.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	in, err := dir.OpenInput("a.bin", IO_CONTEXT_READ)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEquals(t, in.Length(), int64(100))
+	buf := make([]byte, 30)
+	if err = in.ReadBytes(buf); err != nil {
+		t.Fatal(err)
+	}
+	assertEquals(t, buf[29], byte(29))
+	b, err := in.ReadByte()
+	assertEquals(t, b, byte(30))
+	assertEquals(t, in.FilePointer(), int64(31))
+
+	clone := in.Clone()
+	b, _ = clone.ReadByte()
+	assertEquals(t, b, byte(31))
+
+	slice, err := in.Slice("test", 50, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEquals(t, slice.Length(), int64(20))
+	if err = slice.Seek(15); err != nil {
+		t.Fatal(err)
+	}
+	b, _ = slice.ReadByte()
+	assertEquals(t, b, byte(65))
+	if err = slice.ReadBytes(make([]byte, 5)); err == nil {
+		t.Error("reading past the end of the slice should fail")
+	}
+	if _, err = slice.Slice("out", 10, 11); err == nil {
+		t.Error("slicing past the end should fail")
+	}
+
+	if err = in.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = clone.ReadByte(); err == nil {
+		t.Error("reading a clone of a closed input should fail")
+	}
+	if err = slice.ReadBytes(make([]byte, 1)); err == nil {
+		t.Error("reading a slice of a closed input should fail")
+	}
+	if _, err = in.Slice("closed", 0, 10); err == nil {
+		t.Error("slicing a closed input should fail")
+	}
+	if err = in.Close(); err != nil {
+		t.Errorf("closing twice should do nothing, but was %v", err)
+	}
+}
+
+func TestMMapDirectoryCloseWhileReading(t *testing.T) {
+	if !MMAP_SUPPORTED {
+		t.Skip("mmap is not supported on this platform")
+	}
+	dir, err := NewMMapDirectoryWithChunkSize(t.TempDir(), 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dir.Close()
+	out, err := dir.CreateOutput("a.bin", IO_CONTEXT_DEFAULT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = out.WriteBytes(make([]byte, 1000)); err != nil {
+		t.Fatal(err)
+	}
+	out.Close()
+
+	in, err := dir.OpenInput("a.bin", IO_CONTEXT_DEFAULT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		clone := in.Clone()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			buf := make([]byte, 100)
+			// reads either succeed or fail once closed, but never touch
+			// the released mappings
+			for {
+				if err := clone.Seek(0); err != nil {
+					t.Error(err)
+					return
+				}
+				if clone.ReadBytes(buf) != nil {
+					return
+				}
+			}
+		}()
+	}
+	if err = in.Close(); err != nil {
+		t.Fatal(err)
+	}
+	wg.Wait()
+}
+
+func TestMMapDirectoryEmptyFile(t *testing.T) {
+	if !MMAP_SUPPORTED {
+		t.Skip("mmap is not supported on this platform")
+	}
+	dir, err := NewMMapDirectory(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dir.Close()
+	out, err := dir.CreateOutput("empty", IO_CONTEXT_DEFAULT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out.Close()
+	in, err := dir.OpenInput("empty", IO_CONTEXT_READONCE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer in.Close()
+	assertEquals(t, in.Length(), int64(0))
+	if _, err = in.ReadByte(); err == nil {
+		t.Error("reading an empty file should fail")
+	}
+}
@@ -0,0 +1,34 @@
+//go:build unix
+
+package store
+
+import (
+	"os"
+	"syscall"
+)
+
+// True if MMapDirectory can map files on this platform.
+const MMAP_SUPPORTED = true
+
+/*
+Maps length bytes of the file from offset, advised for the context.
+As mappings must start at a page boundary, the whole mapping, to be
+passed to munmap(), may start before the requested chunk.
+*/
+func mmap(f *os.File, offset int64, length int, context IOContext) (chunk, mapping []byte, err error) {
+	delta := int(offset % int64(os.Getpagesize()))
+	mapping, err = syscall.Mmap(int(f.Fd()), offset-int64(delta), length+delta,
+		syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = madvise(mapping, context); err != nil {
+		syscall.Munmap(mapping)
+		return nil, nil, err
+	}
+	return mapping[delta:], mapping, nil
+}
+
+func munmap(mapping []byte) error {
+	return syscall.Munmap(mapping)
+}
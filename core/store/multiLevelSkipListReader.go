@@ -0,0 +1,352 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"github.com/balzaczyy/golucene/core/util"
+	"io"
+	"math"
+)
+
+type MultiLevelSkipListReaderSPI interface {
+	// Subclasses must implement the actual skip data encoding in this
+	// method, returning the doc delta of the entry read.
+	ReadSkipData(level int, skipStream IndexInput) (int, error)
+	// Seeks the skip entry on the given level.
+	SeekChild(level int) error
+	// Copies the values of the last read skip entry on this level.
+	SetLastSkipData(level int)
+}
+
+/*
+This abstract class reads skip lists with multiple levels.
+
+See MultiLevelSkipListWriter for the information about the encoding
+of the multi level skip lists.
+
+Subclasses must implement the abstract method ReadSkipData(), which
+defines the actual format of the skip data, and may override
+SeekChild() and SetLastSkipData() to keep track of their own values,
+calling the ones of MultiLevelSkipListReader.
+
+Note: this class was moved from package codec to store since it
+caused cyclic dependency (store<->codec).
+*/
+type MultiLevelSkipListReader struct {
+	spi MultiLevelSkipListReaderSPI
+	// the maximum number of skip levels possible for this index
+	MaxNumberOfSkipLevels int
+	// number of levels in this skip list
+	numberOfSkipLevels int
+	// Expert: defines the number of top skip levels to buffer in memory.
+	// Reducing this number results in less memory usage, but possibly
+	// slower performance due to more random I/Os.
+	// Please notice that the space each level occupies is limited by
+	// the skipInterval. The top level can not contain more than
+	// skipLevel entries, the second top level can not contain more
+	// than skipLevel^2 entries and so forth.
+	numberOfLevelsToBuffer int
+	docCount               int
+	haveSkipped            bool
+	// skipStream for each level
+	skipStream []IndexInput
+	// the start pointer of each skip level
+	skipPointer []int64
+	// skipInterval of each level
+	skipInterval []int
+	// number of docs skipped per level
+	numSkipped []int
+	// doc id of current skip entry per level
+	SkipDoc []int
+	// doc id of last read skip entry with docId <= target
+	lastDoc int
+	// child pointer of current skip entry per level
+	childPointer []int64
+	// childPointer of last read skip entry with docId <= target
+	lastChildPointer int64
+
+	skipMultiplier int
+}
+
+/* Creates a MultiLevelSkipListReader. */
+func NewMultiLevelSkipListReader(spi MultiLevelSkipListReaderSPI, skipStream IndexInput,
+	maxSkipLevels, skipInterval, skipMultiplier int) *MultiLevelSkipListReader {
+
+	ans := &MultiLevelSkipListReader{
+		spi:                    spi,
+		MaxNumberOfSkipLevels:  maxSkipLevels,
+		numberOfLevelsToBuffer: 1,
+		skipStream:             make([]IndexInput, maxSkipLevels),
+		skipPointer:            make([]int64, maxSkipLevels),
+		skipInterval:           make([]int, maxSkipLevels),
+		numSkipped:             make([]int, maxSkipLevels),
+		SkipDoc:                make([]int, maxSkipLevels),
+		childPointer:           make([]int64, maxSkipLevels),
+		skipMultiplier:         skipMultiplier,
+	}
+	ans.skipStream[0] = skipStream
+	ans.skipInterval[0] = skipInterval
+	for i := 1; i < maxSkipLevels; i++ {
+		// cache skip intervals
+		ans.skipInterval[i] = ans.skipInterval[i-1] * skipMultiplier
+	}
+	return ans
+}
+
+/*
+Returns the id of the doc to which the last call of SkipTo() has
+skipped.
+*/
+func (r *MultiLevelSkipListReader) Doc() int {
+	return r.lastDoc
+}
+
+/*
+Skips entries to the first beyond the current whose document number
+is greater than or equal to target. Returns the entry's documents
+skipped.
+*/
+func (r *MultiLevelSkipListReader) SkipTo(target int) (int, error) {
+	if !r.haveSkipped {
+		// first time, load skip levels
+		if err := r.loadSkipLevels(); err != nil {
+			return 0, err
+		}
+		r.haveSkipped = true
+	}
+
+	// walk up the levels until highest level is found that has a skip
+	// for this target
+	level := 0
+	for level < r.numberOfSkipLevels-1 && target > r.SkipDoc[level+1] {
+		level++
+	}
+
+	for level >= 0 {
+		if target > r.SkipDoc[level] {
+			ok, err := r.loadNextSkip(level)
+			if err != nil {
+				return 0, err
+			}
+			if !ok {
+				continue
+			}
+		} else {
+			// no more skips on this level, go down one level
+			if level > 0 && r.lastChildPointer > r.skipStream[level-1].FilePointer() {
+				if err := r.spi.SeekChild(level - 1); err != nil {
+					return 0, err
+				}
+			}
+			level--
+		}
+	}
+
+	return r.numSkipped[0] - r.skipInterval[0] - 1, nil
+}
+
+func (r *MultiLevelSkipListReader) loadNextSkip(level int) (bool, error) {
+	// we have to skip, the target document is greater than the current
+	// skip list entry
+	r.spi.SetLastSkipData(level)
+
+	r.numSkipped[level] += r.skipInterval[level]
+
+	if r.numSkipped[level] > r.docCount {
+		// this skip list is exhausted
+		r.SkipDoc[level] = math.MaxInt32
+		if r.numberOfSkipLevels > level {
+			r.numberOfSkipLevels = level
+		}
+		return false, nil
+	}
+
+	// read next skip entry
+	delta, err := r.spi.ReadSkipData(level, r.skipStream[level])
+	if err != nil {
+		return false, err
+	}
+	r.SkipDoc[level] += delta
+
+	if level != 0 {
+		// read the child pointer if we are not on the leaf level
+		n, err := r.skipStream[level].ReadVLong()
+		if err != nil {
+			return false, err
+		}
+		r.childPointer[level] = n + r.skipPointer[level-1]
+	}
+	return true, nil
+}
+
+/* Seeks the skip entry on the given level */
+func (r *MultiLevelSkipListReader) SeekChild(level int) (err error) {
+	if err = r.skipStream[level].Seek(r.lastChildPointer); err != nil {
+		return err
+	}
+	r.numSkipped[level] = r.numSkipped[level+1] - r.skipInterval[level+1]
+	r.SkipDoc[level] = r.lastDoc
+	if level > 0 {
+		var n int64
+		if n, err = r.skipStream[level].ReadVLong(); err != nil {
+			return err
+		}
+		r.childPointer[level] = n + r.skipPointer[level-1]
+	}
+	return nil
+}
+
+/* Closes the streams of the levels above the lowest one. */
+func (r *MultiLevelSkipListReader) Close() error {
+	var streams []io.Closer
+	for _, stream := range r.skipStream[1:] {
+		if stream != nil {
+			streams = append(streams, stream)
+		}
+	}
+	return util.Close(streams...)
+}
+
+/* Initializes the reader, for reuse on a new term. */
+func (r *MultiLevelSkipListReader) Init(skipPointer int64, df int) {
+	r.skipPointer[0] = skipPointer
+	r.docCount = df
+	assert(skipPointer >= 0 && skipPointer <= r.skipStream[0].Length())
+	for i := range r.SkipDoc {
+		r.SkipDoc[i] = 0
+		r.numSkipped[i] = 0
+		r.childPointer[i] = 0
+	}
+
+	r.haveSkipped = false
+	for i := 1; i < r.numberOfSkipLevels; i++ {
+		r.skipStream[i] = nil
+	}
+}
+
+/* Loads the skip levels */
+func (r *MultiLevelSkipListReader) loadSkipLevels() (err error) {
+	if r.docCount <= r.skipInterval[0] {
+		r.numberOfSkipLevels = 1
+	} else {
+		r.numberOfSkipLevels = 1 + util.Log(int64(r.docCount/r.skipInterval[0]), r.skipMultiplier)
+	}
+
+	if r.numberOfSkipLevels > r.MaxNumberOfSkipLevels {
+		r.numberOfSkipLevels = r.MaxNumberOfSkipLevels
+	}
+
+	if err = r.skipStream[0].Seek(r.skipPointer[0]); err != nil {
+		return err
+	}
+
+	toBuffer := r.numberOfLevelsToBuffer
+
+	for i := r.numberOfSkipLevels - 1; i > 0; i-- {
+		// the length of the current level
+		length, err := r.skipStream[0].ReadVLong()
+		if err != nil {
+			return err
+		}
+
+		// the start pointer of the current level
+		r.skipPointer[i] = r.skipStream[0].FilePointer()
+		if toBuffer > 0 {
+			// buffer this level
+			if r.skipStream[i], err = newSkipBuffer(r.skipStream[0], int(length)); err != nil {
+				return err
+			}
+			toBuffer--
+		} else {
+			// clone this stream, it is already at the start of the current level
+			r.skipStream[i] = r.skipStream[0].Clone()
+			if in, ok := r.skipStream[i].(interface {
+				SetBufferSize(int)
+			}); ok && length < BUFFER_SIZE {
+				in.SetBufferSize(int(length))
+			}
+
+			// move base stream beyond the current level
+			if err = r.skipStream[0].Seek(r.skipStream[0].FilePointer() + length); err != nil {
+				return err
+			}
+		}
+	}
+
+	// use base stream for the lowest level
+	r.skipPointer[0] = r.skipStream[0].FilePointer()
+	return nil
+}
+
+/* Copies the values of the last read skip entry on this level */
+func (r *MultiLevelSkipListReader) SetLastSkipData(level int) {
+	r.lastDoc = r.SkipDoc[level]
+	r.lastChildPointer = r.childPointer[level]
+}
+
+var errSkipBufferSlice = errors.New("slicing a skip buffer is not supported")
+
+/* used to buffer the top skip levels */
+type skipBuffer struct {
+	*IndexInputImpl
+	data    []byte
+	pointer int
+	// absolute position of data[0] in the original input
+	pointerOffset int64
+}
+
+func newSkipBuffer(input IndexInput, length int) (*skipBuffer, error) {
+	ans := &skipBuffer{
+		data:          make([]byte, length),
+		pointerOffset: input.FilePointer(),
+	}
+	ans.IndexInputImpl = NewIndexInputImpl(fmt.Sprintf("SkipBuffer on %v", input), ans)
+	if err := input.ReadBytes(ans.data); err != nil {
+		return nil, err
+	}
+	return ans, nil
+}
+
+func (in *skipBuffer) Close() error {
+	in.data = nil
+	return nil
+}
+
+func (in *skipBuffer) FilePointer() int64 {
+	return int64(in.pointer) + in.pointerOffset
+}
+
+func (in *skipBuffer) Length() int64 {
+	return int64(len(in.data))
+}
+
+func (in *skipBuffer) ReadByte() (byte, error) {
+	if in.pointer >= len(in.data) {
+		return 0, fmt.Errorf("read past EOF: %v", in)
+	}
+	in.pointer++
+	return in.data[in.pointer-1], nil
+}
+
+func (in *skipBuffer) ReadBytes(buf []byte) error {
+	if len(buf) > len(in.data)-in.pointer {
+		return fmt.Errorf("read past EOF: %v", in)
+	}
+	in.pointer += copy(buf, in.data[in.pointer:])
+	return nil
+}
+
+func (in *skipBuffer) Seek(pos int64) error {
+	in.pointer = int(pos - in.pointerOffset)
+	return nil
+}
+
+func (in *skipBuffer) Clone() IndexInput {
+	ans := &skipBuffer{data: in.data, pointer: in.pointer, pointerOffset: in.pointerOffset}
+	ans.IndexInputImpl = NewIndexInputImpl(in.desc, ans)
+	return ans
+}
+
+func (in *skipBuffer) Slice(desc string, offset, length int64) (IndexInput, error) {
+	return nil, errSkipBufferSlice
+}
@@ -0,0 +1,157 @@
+package store
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// store/NativeFSLockFactory.java
+
+/*
+The canonical paths of the lock files held by this process. OS level
+locks are held per process, or per file descriptor, which differs by
+platform; recording the held locks makes a second Obtain() of the same
+lock fail the same way everywhere.
+*/
+var (
+	locksHeld     = make(map[string]bool) // synchronized
+	locksHeldLock sync.Mutex
+)
+
+type NativeFSLock struct {
+	*LockImpl
+	dir, path string
+	file      *os.File // nil if not held
+}
+
+func newNativeFSLock(lockDir, lockFileName string) *NativeFSLock {
+	ans := &NativeFSLock{
+		dir:  lockDir,
+		path: filepath.Join(lockDir, lockFileName),
+	}
+	ans.LockImpl = NewLockImpl(ans)
+	return ans
+}
+
+func (lock *NativeFSLock) Obtain() (ok bool, err error) {
+	if lock.file != nil {
+		// Our instance is already locked:
+		return false, nil
+	}
+
+	// Ensure that lockDir exists and is a directory.
+	if err = os.MkdirAll(lock.dir, 0755); err != nil {
+		return false, fmt.Errorf("Cannot create directory: %v: %v", lock.dir, err)
+	}
+	canonical, err := filepath.Abs(lock.path)
+	if err != nil {
+		return false, err
+	}
+
+	locksHeldLock.Lock()
+	defer locksHeldLock.Unlock()
+	if locksHeld[canonical] {
+		// someone else in this process already holds the lock
+		return false, nil
+	}
+	f, err := os.OpenFile(lock.path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return false, err
+	}
+	if err = lockFile(f); err != nil {
+		// At least on OS X, we will sometimes get an intermittent "Permission
+		// Denied" error, which seems to simply mean "you failed to get the
+		// lock". But other errors could be indicative of a legitimate
+		// problem, so we save it as the failure reason for ObtainWithin().
+		lock.failureReason = err
+		f.Close()
+		return false, nil
+	}
+	lock.file = f
+	locksHeld[canonical] = true
+	return true, nil
+}
+
+/*
+Releases the lock if held by this instance. The lock file is not
+deleted, as another process could lock it between the release and the
+deletion, then lose it to a third process creating a new file.
+*/
+func (lock *NativeFSLock) Close() error {
+	if lock.file == nil {
+		return nil
+	}
+	err := unlockFile(lock.file)
+	if err2 := lock.file.Close(); err == nil {
+		err = err2
+	}
+	lock.file = nil
+	if canonical, err2 := filepath.Abs(lock.path); err2 == nil {
+		locksHeldLock.Lock()
+		defer locksHeldLock.Unlock()
+		delete(locksHeld, canonical)
+	}
+	return err
+}
+
+func (lock *NativeFSLock) IsLocked() bool {
+	// The test for is isLocked is not directly possible with native
+	// file locks, so we try to obtain the lock and release it again.
+	if lock.file != nil {
+		return true
+	}
+	ok, err := lock.Obtain()
+	if ok {
+		lock.Close()
+	}
+	return !ok || err != nil
+}
+
+func (lock *NativeFSLock) String() string {
+	return fmt.Sprintf("NativeFSLock@%v", lock.path)
+}
+
+/*
+Implements LockFactory using native OS file locks: flock(2) on Unix,
+LockFileEx on Windows. Contrary to SimpleFSLockFactory, the locks are
+released by the OS when the process exits, so a crash does not leave
+a stale write.lock behind; the lock files themselves are left in the
+directory, and do not mean the directory is locked.
+
+The locks are advisory, and only work on local file systems, as NFS
+and other network file systems may not support them. This is the
+default LockFactory of FSDirectory where NATIVE_LOCKS_SUPPORTED is
+true, SimpleFSLockFactory being used elsewhere.
+*/
+type NativeFSLockFactory struct {
+	*FSLockFactory
+}
+
+func NewNativeFSLockFactory(path string) *NativeFSLockFactory {
+	ans := &NativeFSLockFactory{}
+	ans.FSLockFactory = newFSLockFactory()
+	ans.setLockDir(path)
+	return ans
+}
+
+func (f *NativeFSLockFactory) Make(name string) Lock {
+	if f.lockPrefix != "" {
+		name = fmt.Sprintf("%v-%v", f.lockPrefix, name)
+	}
+	return newNativeFSLock(f.lockDir, name)
+}
+
+/*
+Does nothing: the lock of another process can not be broken, and it
+is released by the OS when that process exits anyway, while the locks
+of this process are released by closing them.
+*/
+func (f *NativeFSLockFactory) Clear(name string) error {
+	return nil
+}
+
+func (f *NativeFSLockFactory) String() string {
+	return fmt.Sprintf("NativeFSLockFactory@%v", f.lockDir)
+}
@@ -0,0 +1,21 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly || windows)
+
+package store
+
+import (
+	"errors"
+	"os"
+)
+
+// True if NativeFSLockFactory can lock files on this platform.
+const NATIVE_LOCKS_SUPPORTED = false
+
+var errNativeLocksUnsupported = errors.New("native file locks are not supported on this platform")
+
+func lockFile(f *os.File) error {
+	return errNativeLocksUnsupported
+}
+
+func unlockFile(f *os.File) error {
+	return errNativeLocksUnsupported
+}
@@ -0,0 +1,86 @@
+package store
+
+import (
+	"os"
+	"testing"
+)
+
+func TestNativeFSLockExcludesSecondObtain(t *testing.T) {
+	if !NATIVE_LOCKS_SUPPORTED {
+		t.Skip("native file locks are not supported")
+	}
+	path, err := os.MkdirTemp("", "nativefs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(path)
+
+	f := NewNativeFSLockFactory(path)
+	l1, l2 := f.Make("write.lock"), f.Make("write.lock")
+	ok, err := l1.Obtain()
+	if err != nil || !ok {
+		t.Fatalf("first obtain failed: %v %v", ok, err)
+	}
+	assertEquals(t, true, l2.IsLocked())
+	if ok, err = l2.Obtain(); err != nil || ok {
+		t.Fatalf("second obtain should fail while locked: %v %v", ok, err)
+	}
+	if err = l1.Close(); err != nil {
+		t.Fatal(err)
+	}
+	assertEquals(t, false, l2.IsLocked())
+	if ok, err = l2.Obtain(); err != nil || !ok {
+		t.Fatalf("obtain after release failed: %v %v", ok, err)
+	}
+	if err = l2.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSimpleFSLockExcludesSecondObtain(t *testing.T) {
+	path, err := os.MkdirTemp("", "simplefs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(path)
+
+	f := NewSimpleFSLockFactory(path)
+	l1, l2 := f.Make("write.lock"), f.Make("write.lock")
+	ok, err := l1.Obtain()
+	if err != nil || !ok {
+		t.Fatalf("first obtain failed: %v %v", ok, err)
+	}
+	if ok, err = l2.Obtain(); err != nil || ok {
+		t.Fatalf("second obtain should fail while locked: %v %v", ok, err)
+	}
+	if err = l1.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err = l2.Obtain(); err != nil || !ok {
+		t.Fatalf("obtain after release failed: %v %v", ok, err)
+	}
+	l2.Close()
+}
+
+func TestFSDirectoryDefaultLockFactory(t *testing.T) {
+	path, err := os.MkdirTemp("", "fslock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(path)
+
+	d, err := OpenFSDirectory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d.Close()
+	lf := d.LockFactory()
+	if NATIVE_LOCKS_SUPPORTED {
+		if _, ok := lf.(*NativeFSLockFactory); !ok {
+			t.Errorf("expected NativeFSLockFactory, got %v", lf)
+		}
+	} else if _, ok := lf.(*SimpleFSLockFactory); !ok {
+		t.Errorf("expected SimpleFSLockFactory, got %v", lf)
+	}
+	assertEquals(t, "", lf.LockPrefix())
+}
@@ -0,0 +1,42 @@
+package store
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// True if NativeFSLockFactory can lock files on this platform.
+const NATIVE_LOCKS_SUPPORTED = true
+
+const (
+	_LOCKFILE_FAIL_IMMEDIATELY = 0x1
+	_LOCKFILE_EXCLUSIVE_LOCK   = 0x2
+)
+
+var (
+	modkernel32      = syscall.NewLazyDLL("kernel32.dll")
+	procLockFileEx   = modkernel32.NewProc("LockFileEx")
+	procUnlockFileEx = modkernel32.NewProc("UnlockFileEx")
+)
+
+// Locks the first byte of the file, which is enough for an advisory lock.
+func lockFile(f *os.File) error {
+	var ol syscall.Overlapped
+	r, _, err := procLockFileEx.Call(f.Fd(),
+		_LOCKFILE_EXCLUSIVE_LOCK|_LOCKFILE_FAIL_IMMEDIATELY, 0, 1, 0,
+		uintptr(unsafe.Pointer(&ol)))
+	if r == 0 {
+		return err
+	}
+	return nil
+}
+
+func unlockFile(f *os.File) error {
+	var ol syscall.Overlapped
+	r, _, err := procUnlockFileEx.Call(f.Fd(), 0, 1, 0, uintptr(unsafe.Pointer(&ol)))
+	if r == 0 {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package store
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// store/NIOFSDirectory.java
+
+/*
+An FSDirectory implementation that uses positional reads (pread(2)
+through File.ReadAt()) when reading from a file, so that multiple
+goroutines can read from the same file without synchronizing.
+
+SimpleFSDirectory seeks the shared file before reading, under a lock
+shared by all clones and slices of an IndexInput; concurrent searches
+on the same segment thus serialize on that lock. NIOFSIndexInput keeps
+no file position at all, every read passing the absolute offset it
+reads from.
+*/
+type NIOFSDirectory struct {
+	*FSDirectory
+}
+
+// Create a new NIOFSDirectory for the named location.
+func NewNIOFSDirectory(path string) (d *NIOFSDirectory, err error) {
+	d = &NIOFSDirectory{}
+	d.FSDirectory, err = newFSDirectory(d, path)
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+// Creates an IndexInput for the file with the given name.
+func (d *NIOFSDirectory) OpenInput(name string, context IOContext) (IndexInput, error) {
+	d.EnsureOpen()
+	fpath := filepath.Join(d.path, name)
+	return newNIOFSIndexInput(fmt.Sprintf("NIOFSIndexInput(path='%v')", fpath), fpath, context)
+}
+
+func (d *NIOFSDirectory) String() string {
+	return fmt.Sprintf("NIOFSDirectory@%v", d.DirectoryImpl.String())
+}
+
+// Reads bytes with File.ReadAt()
+type NIOFSIndexInput struct {
+	*BufferedIndexInput
+	// the file we will read from
+	file *os.File
+	// is this instance a clone and hence does not own the file to close it
+	isClone bool
+	// start offset: non-zero in the slice case
+	off int64
+	// end offset (start+length)
+	end int64
+}
+
+func newNIOFSIndexInput(desc, path string, ctx IOContext) (*NIOFSIndexInput, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	fstat, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	adviseFile(f, ctx)
+	ans := &NIOFSIndexInput{file: f, end: fstat.Size()}
+	ans.BufferedIndexInput = newBufferedIndexInput(ans, desc, ctx)
+	return ans, nil
+}
+
+func newNIOFSIndexInputFromFileSlice(desc string, file *os.File, off, length int64, bufferSize int) *NIOFSIndexInput {
+	ans := &NIOFSIndexInput{file: file, isClone: true, off: off, end: off + length}
+	ans.BufferedIndexInput = newBufferedIndexInputBySize(ans, desc, bufferSize)
+	return ans
+}
+
+func (in *NIOFSIndexInput) Close() error {
+	if !in.isClone {
+		return in.file.Close()
+	}
+	return nil
+}
+
+func (in *NIOFSIndexInput) Clone() IndexInput {
+	ans := &NIOFSIndexInput{
+		in.BufferedIndexInput.Clone(),
+		in.file,
+		true,
+		in.off,
+		in.end,
+	}
+	ans.spi = ans
+	return ans
+}
+
+func (in *NIOFSIndexInput) Slice(desc string, offset, length int64) (IndexInput, error) {
+	assert2(offset >= 0 && length >= 0 && offset+length <= in.Length(),
+		"slice() %v out of bounds: %v", desc, in)
+	return newNIOFSIndexInputFromFileSlice(desc, in.file, in.off+offset, length, in.bufferSize), nil
+}
+
+func (in *NIOFSIndexInput) Length() int64 {
+	return in.end - in.off
+}
+
+func (in *NIOFSIndexInput) readInternal(buf []byte) error {
+	position := in.off + in.FilePointer()
+	if position+int64(len(buf)) > in.end {
+		return fmt.Errorf("read past EOF: %v", in)
+	}
+	// ReadAt() only returns less bytes than asked with an error
+	if _, err := in.file.ReadAt(buf, position); err != nil {
+		if err == io.EOF {
+			return fmt.Errorf("read past EOF: %v", in)
+		}
+		return fmt.Errorf("%v: %v", err, in)
+	}
+	return nil
+}
+
+func (in *NIOFSIndexInput) seekInternal(pos int64) error { return nil }
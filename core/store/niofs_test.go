@@ -0,0 +1,69 @@
+package store
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestNIOFSConcurrentClones(t *testing.T) {
+	dir, err := NewNIOFSDirectory(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dir.Close()
+	out, err := dir.CreateOutput("a.bin", IO_CONTEXT_DEFAULT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 10000; i++ {
+		if err = out.WriteInt(int32(i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = out.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	in, err := dir.OpenInput("a.bin", IO_CONTEXT_READ)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer in.Close()
+	slice, err := in.Slice("ints", 4000, 4000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			clone := slice.Clone()
+			start := int64(g * 100)
+			if err := clone.Seek(start * 4); err != nil {
+				errs <- err
+				return
+			}
+			for i := start; i < 1000; i++ {
+				v, err := clone.ReadInt()
+				if err != nil {
+					errs <- err
+					return
+				}
+				if int64(v) != 1000+i {
+					t.Errorf("expected %v, got %v", 1000+i, v)
+					return
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+	if err = slice.Clone().ReadBytes(make([]byte, 4001)); err == nil {
+		t.Error("reading past the end of the slice should fail")
+	}
+}
@@ -2,6 +2,7 @@ package store
 
 import (
 	"fmt"
+	"github.com/balzaczyy/golucene/core/util"
 	"log"
 	"sync"
 )
@@ -13,8 +14,8 @@ const NRT_VERBOSE = false
 // - rename to MergeCachingDir? NRTCachingDIR
 
 /*
-Wraps a RAMDirectory around any provided delegate directory, to be
-used during NRT search.
+Wraps a ByteBuffersDirectory around any provided delegate directory,
+to be used during NRT search.
 
 This class is likely only useful in a near-real-time context, where
 indexing rate is lowish but reopen rate is highish, resulting in many
@@ -41,7 +42,7 @@ type NRTCachingDirectory struct {
 	Directory
 	sync.Locker
 
-	cache             *RAMDirectory
+	cache             *ByteBuffersDirectory
 	maxMergeSizeBytes int64
 	maxCachedBytes    int64
 
@@ -58,13 +59,13 @@ func NewNRTCachingDirectory(delegate Directory, maxMergeSizeMB, maxCachedMB floa
 	nrt = &NRTCachingDirectory{
 		Directory:         delegate,
 		Locker:            &sync.Mutex{},
-		cache:             NewRAMDirectory(),
+		cache:             NewByteBuffersDirectory(),
 		maxMergeSizeBytes: int64(maxMergeSizeMB * 1024 * 1024),
 		maxCachedBytes:    int64(maxCachedMB * 1024 * 1024),
 		uncacheLock:       &sync.Mutex{},
 	}
 	// Subclass can override this to customize logic; return true if this
-	// file should be written to the RAMDirectory.
+	// file should be written to the cache.
 	nrt.doCacheWrite = func(name string, context IOContext) bool {
 		var bytes int64
 		if context.MergeInfo != nil {
@@ -85,8 +86,13 @@ func NewNRTCachingDirectory(delegate Directory, maxMergeSizeMB, maxCachedMB floa
 
 func (nrt *NRTCachingDirectory) String() string {
 	return fmt.Sprintf("NRTCachingDirectory(%v; maxCacheMB=%.2f maxMergeSizeMB=%.2f)",
-		nrt.Directory, float32(nrt.maxCachedBytes/1024/1024),
-		float32(nrt.maxMergeSizeBytes/1024/1024))
+		nrt.Directory, float64(nrt.maxCachedBytes)/1024/1024,
+		float64(nrt.maxMergeSizeBytes)/1024/1024)
+}
+
+// Returns the names of the files currently held in the cache.
+func (nrt *NRTCachingDirectory) ListCachedFiles() ([]string, error) {
+	return nrt.cache.ListAll()
 }
 
 func (nrt *NRTCachingDirectory) ListAll() (all []string, err error) {
@@ -100,7 +106,7 @@ func (nrt *NRTCachingDirectory) ListAll() (all []string, err error) {
 	for _, f := range all {
 		files[f] = true
 	}
-	// LUCENE-1468: our NRTCachingDirectory will actually exist (RAMDir!),
+	// LUCENE-1468: our NRTCachingDirectory will actually exist (cache!),
 	// but if the underlying delegate is an FSDir and mkdirs() has not
 	// yet been called, because so far everything is a cached write,
 	// in this case, we don't want to throw a NoSuchDirectoryException
@@ -129,10 +135,10 @@ func (nrt *NRTCachingDirectory) ListAll() (all []string, err error) {
 	return
 }
 
-// Returns how many bytes are being used by the RAMDirectory cache
-// func (nrt *NRTCachingDirectory) sizeInBytes() int64 {
-// 	return nrt.cache.sizeInBytes
-// }
+// Returns how many bytes are being used by the cache.
+func (nrt *NRTCachingDirectory) RamBytesUsed() int64 {
+	return nrt.cache.RamBytesUsed()
+}
 
 func (nrt *NRTCachingDirectory) FileExists(name string) bool {
 	nrt.Lock() // synchronized
@@ -175,6 +181,14 @@ func (nrt *NRTCachingDirectory) FileLength(name string) (length int64, err error
 	}
 }
 
+// Returns the metadata of the file, from the cache or the delegate.
+func (nrt *NRTCachingDirectory) FileMetadata(name string) (FileMetadata, error) {
+	if nrt.cache.FileExists(name) {
+		return nrt.cache.FileMetadata(name)
+	}
+	return nrt.Directory.FileMetadata(name)
+}
+
 func (nrt *NRTCachingDirectory) CreateOutput(name string, context IOContext) (out IndexOutput, err error) {
 	if NRT_VERBOSE {
 		log.Printf("nrtdir.createOutput name=%v", name)
@@ -218,6 +232,36 @@ func (nrt *NRTCachingDirectory) OpenInput(name string, context IOContext) (in In
 	return nrt.Directory.OpenInput(name, context)
 }
 
+// Opens the file from the cache, or the delegate, computing its
+// checksum as it is read.
+func (nrt *NRTCachingDirectory) OpenChecksumInput(name string, context IOContext) (ChecksumIndexInput, error) {
+	in, err := nrt.OpenInput(name, context)
+	if err != nil {
+		return nil, err
+	}
+	return newBufferedChecksumIndexInput(in), nil
+}
+
+// Copies the file src, from the cache or the delegate, to 'to' under
+// the new file name dest.
+func (nrt *NRTCachingDirectory) Copy(to Directory, src, dest string, context IOContext) (err error) {
+	var out IndexOutput
+	var in IndexInput
+	if out, err = to.CreateOutput(dest, context); err != nil {
+		return err
+	}
+	if in, err = nrt.OpenInput(src, context); err == nil {
+		err = out.CopyBytes(in, in.Length())
+	}
+	if err2 := util.Close(out, in); err == nil {
+		err = err2
+	}
+	if err != nil {
+		to.DeleteFile(dest) // ignore error
+	}
+	return err
+}
+
 // func (nrt *NRTCachingDirectory) CreateSlicer(name string, context IOContext) (slicer IndexInputSlicer, err error) {
 // 	nrt.EnsureOpen()
 // 	if NRT_VERBOSE {
@@ -245,7 +289,9 @@ func (nrt *NRTCachingDirectory) Close() error {
 		return err
 	}
 	for _, fileName := range all {
-		nrt.unCache(fileName)
+		if err = nrt.unCache(fileName); err != nil {
+			return err
+		}
 	}
 	err = nrt.cache.Close()
 	if err != nil {
@@ -260,7 +306,9 @@ func (nrt *NRTCachingDirectory) unCache(fileName string) (err error) {
 	nrt.uncacheLock.Lock()
 	defer nrt.uncacheLock.Unlock()
 
-	log.Printf("nrtdir.unCache name=%v", fileName)
+	if NRT_VERBOSE {
+		log.Printf("nrtdir.unCache name=%v", fileName)
+	}
 	if !nrt.cache.FileExists(fileName) {
 		// Another goroutine beat us...
 		return
@@ -271,14 +319,13 @@ func (nrt *NRTCachingDirectory) unCache(fileName string) (err error) {
 	if err != nil {
 		return
 	}
-	defer out.Close()
 	var in IndexInput
-	in, err = nrt.cache.OpenInput(fileName, context)
-	if err != nil {
-		return
+	if in, err = nrt.cache.OpenInput(fileName, context); err == nil {
+		err = out.CopyBytes(in, in.Length())
+	}
+	if err2 := util.Close(in, out); err == nil {
+		err = err2
 	}
-	defer in.Close()
-	err = out.CopyBytes(in, in.Length())
 	if err != nil {
 		return
 	}
@@ -0,0 +1,73 @@
+package store
+
+import (
+	"sort"
+	"testing"
+)
+
+func writeTestFile(t *testing.T, d Directory, name string, ctx IOContext, size int) {
+	out, err := d.CreateOutput(name, ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = out.WriteBytes(make([]byte, size)); err != nil {
+		t.Fatal(err)
+	}
+	if err = out.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNRTCachingDirectory(t *testing.T) {
+	delegate, err := NewNIOFSDirectory(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	nrt := NewNRTCachingDirectory(delegate, 1, 2)
+
+	// small flushed segments are cached, large merged ones are not
+	writeTestFile(t, nrt, "_0.cfs", NewIOContextForFlush(&FlushInfo{10, 100}), 100)
+	writeTestFile(t, nrt, "_1.cfs", NewIOContextForMerge(&MergeInfo{100, 4 << 20, false, -1}), 1000)
+	cached, _ := nrt.ListCachedFiles()
+	assertEquals(t, len(cached), 1)
+	assertEquals(t, cached[0], "_0.cfs")
+	assertEquals(t, delegate.FileExists("_0.cfs"), false)
+	assertEquals(t, delegate.FileExists("_1.cfs"), true)
+	all, _ := nrt.ListAll()
+	sort.Strings(all)
+	assertEquals(t, len(all), 2)
+	assertEquals(t, all[0], "_0.cfs")
+	if nrt.RamBytesUsed() < 100 {
+		t.Errorf("cache should hold at least 100 bytes, but %v", nrt.RamBytesUsed())
+	}
+
+	in, err := nrt.OpenChecksumInput("_0.cfs", IO_CONTEXT_READ)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEquals(t, in.Length(), int64(100))
+	in.Close()
+
+	// syncing pushes the cached files to the delegate
+	if err = nrt.Sync([]string{"_0.cfs"}); err != nil {
+		t.Fatal(err)
+	}
+	cached, _ = nrt.ListCachedFiles()
+	assertEquals(t, len(cached), 0)
+	assertEquals(t, nrt.RamBytesUsed(), int64(0))
+	n, err := delegate.FileLength("_0.cfs")
+	assertEquals(t, n, int64(100))
+
+	// cached files are flushed on close
+	writeTestFile(t, nrt, "_2.cfs", NewIOContextForFlush(&FlushInfo{10, 100}), 10)
+	path := delegate.path
+	if err = nrt.Close(); err != nil {
+		t.Fatal(err)
+	}
+	reopened, err := NewNIOFSDirectory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, err = reopened.FileLength("_2.cfs")
+	assertEquals(t, n, int64(10))
+}
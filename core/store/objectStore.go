@@ -0,0 +1,362 @@
+package store
+
+import (
+	"container/list"
+	"fmt"
+	"github.com/balzaczyy/golucene/core/util"
+	"hash"
+	"hash/crc32"
+	"strings"
+	"sync"
+	"unsafe"
+)
+
+const (
+	// The default size of the blocks read from the BlobStore, 1 MB.
+	DEFAULT_BLOB_BLOCK_SIZE = 1 << 20
+	// The default limit of the bytes cached by ObjectStoreDirectory, 64 MB.
+	DEFAULT_BLOB_CACHE_SIZE = 64 << 20
+)
+
+/*
+A Directory storing its files as the blobs of a BlobStore, like S3 or
+GCS, under a common key prefix, so that an index can live in object
+storage.
+
+Files are read by blocks of a fixed size, fetched with range reads and
+kept in a local LRU cache shared by all the inputs of the directory.
+An input reads the version of the file it was opened on, so that the
+cached blocks of an overwritten file are never mixed with the new
+ones.
+
+Files are written through: the output buffers the whole file in
+memory, and uploads it with a single Put() when it is closed, so the
+file is not visible until then. Sync() is thus a no-op. The directory
+only holds locks within this process; writers running elsewhere must
+be serialized by other means.
+*/
+type ObjectStoreDirectory struct {
+	*DirectoryImpl
+	*BaseDirectory
+	store  BlobStore
+	prefix string
+	cache  *blobBlockCache
+}
+
+// Creates an ObjectStoreDirectory with the default block and cache sizes.
+func NewObjectStoreDirectory(store BlobStore, prefix string) *ObjectStoreDirectory {
+	return NewObjectStoreDirectoryWith(store, prefix, DEFAULT_BLOB_BLOCK_SIZE, DEFAULT_BLOB_CACHE_SIZE)
+}
+
+/*
+Creates an ObjectStoreDirectory storing files under the given key
+prefix, which reads blocks of blockSize bytes and caches at most
+maxCachedBytes of them.
+*/
+func NewObjectStoreDirectoryWith(store BlobStore, prefix string,
+	blockSize int, maxCachedBytes int64) *ObjectStoreDirectory {
+
+	assert2(blockSize > 0, "blockSize must be positive (got %v)", blockSize)
+	ans := &ObjectStoreDirectory{
+		store:  store,
+		prefix: prefix,
+		cache:  newBlobBlockCache(int64(blockSize), maxCachedBytes),
+	}
+	ans.DirectoryImpl = NewDirectoryImpl(ans)
+	ans.BaseDirectory = NewBaseDirectory(ans)
+	ans.SetLockFactory(newSingleInstanceLockFactory())
+	return ans
+}
+
+func (d *ObjectStoreDirectory) LockID() string {
+	return fmt.Sprintf("lucene-%v", util.ItoHex(int64(uintptr(unsafe.Pointer(d)))))
+}
+
+// Returns the files stored under the prefix, ignoring nested keys.
+func (d *ObjectStoreDirectory) ListAll() ([]string, error) {
+	d.EnsureOpen()
+	blobs, err := d.store.List(d.prefix)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(blobs))
+	for _, blob := range blobs {
+		if name := blob.Key[len(d.prefix):]; name != "" && !strings.Contains(name, "/") {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+func (d *ObjectStoreDirectory) FileExists(name string) bool {
+	d.EnsureOpen()
+	_, err := d.store.Stat(d.prefix + name)
+	return err == nil
+}
+
+func (d *ObjectStoreDirectory) FileLength(name string) (int64, error) {
+	d.EnsureOpen()
+	info, err := d.store.Stat(d.prefix + name)
+	return info.Size, err
+}
+
+// Returns the current version of a file, as reported by the BlobStore.
+func (d *ObjectStoreDirectory) FileVersion(name string) (string, error) {
+	d.EnsureOpen()
+	info, err := d.store.Stat(d.prefix + name)
+	return info.Version, err
+}
+
+// Returns how many bytes of blocks are cached.
+func (d *ObjectStoreDirectory) CachedBytes() int64 {
+	return d.cache.size()
+}
+
+func (d *ObjectStoreDirectory) DeleteFile(name string) error {
+	d.EnsureOpen()
+	return d.store.Delete(d.prefix + name)
+}
+
+/*
+Creates a new, empty file in the directory with the given name,
+replacing any existing one once the returned output is closed.
+*/
+func (d *ObjectStoreDirectory) CreateOutput(name string, ctx IOContext) (IndexOutput, error) {
+	d.EnsureOpen()
+	return newObjectStoreIndexOutput(d, name), nil
+}
+
+// Files are durable as soon as their output is closed.
+func (d *ObjectStoreDirectory) Sync(names []string) error {
+	return nil
+}
+
+// Returns a stream reading the current version of an existing file.
+func (d *ObjectStoreDirectory) OpenInput(name string, ctx IOContext) (IndexInput, error) {
+	d.EnsureOpen()
+	info, err := d.store.Stat(d.prefix + name)
+	if err != nil {
+		return nil, err
+	}
+	return newObjectStoreIndexInput(fmt.Sprintf("ObjectStoreIndexInput(key=%v, version=%v)",
+		info.Key, info.Version), d, info, ctx), nil
+}
+
+// Closes the directory to future operations, dropping the cached blocks.
+func (d *ObjectStoreDirectory) Close() error {
+	d.IsOpen = false
+	d.cache.clear()
+	return nil
+}
+
+func (d *ObjectStoreDirectory) String() string {
+	return fmt.Sprintf("ObjectStoreDirectory(prefix=%v)@%v", d.prefix, d.DirectoryImpl.String())
+}
+
+// An LRU cache of the blocks of blobs.
+type blobBlockCache struct {
+	sync.Mutex
+	blockSize       int64
+	maxBytes, bytes int64
+	lru             *list.List // of *blobBlock, most recently used first
+	blocks          map[blobBlockKey]*list.Element
+}
+
+type blobBlockKey struct {
+	key, version string
+	index        int64
+}
+
+type blobBlock struct {
+	key  blobBlockKey
+	data []byte
+}
+
+func newBlobBlockCache(blockSize, maxBytes int64) *blobBlockCache {
+	return &blobBlockCache{
+		blockSize: blockSize,
+		maxBytes:  maxBytes,
+		lru:       list.New(),
+		blocks:    make(map[blobBlockKey]*list.Element),
+	}
+}
+
+/*
+Returns the block of the given blob containing the byte at pos,
+fetching it from the store if it is not cached. Blocks missed by
+several goroutines at once are fetched by each of them.
+*/
+func (c *blobBlockCache) block(store BlobStore, blob BlobInfo, pos int64) ([]byte, error) {
+	key := blobBlockKey{blob.Key, blob.Version, pos / c.blockSize}
+	c.Lock()
+	if elem, ok := c.blocks[key]; ok {
+		c.lru.MoveToFront(elem)
+		c.Unlock()
+		return elem.Value.(*blobBlock).data, nil
+	}
+	c.Unlock()
+
+	start := key.index * c.blockSize
+	data := make([]byte, min(c.blockSize, blob.Size-start))
+	if err := store.ReadAt(blob.Key, blob.Version, data, start); err != nil {
+		return nil, err
+	}
+
+	c.Lock()
+	defer c.Unlock()
+	if _, ok := c.blocks[key]; !ok {
+		c.blocks[key] = c.lru.PushFront(&blobBlock{key, data})
+		c.bytes += int64(len(data))
+		for c.bytes > c.maxBytes && c.lru.Len() > 0 {
+			evicted := c.lru.Remove(c.lru.Back()).(*blobBlock)
+			delete(c.blocks, evicted.key)
+			c.bytes -= int64(len(evicted.data))
+		}
+	}
+	return data, nil
+}
+
+func (c *blobBlockCache) size() int64 {
+	c.Lock()
+	defer c.Unlock()
+	return c.bytes
+}
+
+func (c *blobBlockCache) clear() {
+	c.Lock()
+	defer c.Unlock()
+	c.lru.Init()
+	c.blocks = make(map[blobBlockKey]*list.Element)
+	c.bytes = 0
+}
+
+// Reads a version of a blob through the block cache of its directory.
+type ObjectStoreIndexInput struct {
+	*BufferedIndexInput
+	directory *ObjectStoreDirectory
+	blob      BlobInfo
+	// start offset: non-zero in the slice case
+	off int64
+	// end offset (start+length)
+	end int64
+}
+
+func newObjectStoreIndexInput(desc string, directory *ObjectStoreDirectory,
+	blob BlobInfo, ctx IOContext) *ObjectStoreIndexInput {
+
+	ans := &ObjectStoreIndexInput{directory: directory, blob: blob, end: blob.Size}
+	ans.BufferedIndexInput = newBufferedIndexInput(ans, desc, ctx)
+	return ans
+}
+
+// Nothing to release: the blocks belong to the directory cache.
+func (in *ObjectStoreIndexInput) Close() error {
+	return nil
+}
+
+func (in *ObjectStoreIndexInput) Clone() IndexInput {
+	ans := &ObjectStoreIndexInput{
+		in.BufferedIndexInput.Clone(),
+		in.directory,
+		in.blob,
+		in.off,
+		in.end,
+	}
+	ans.spi = ans
+	return ans
+}
+
+func (in *ObjectStoreIndexInput) Slice(desc string, offset, length int64) (IndexInput, error) {
+	assert2(offset >= 0 && length >= 0 && offset+length <= in.Length(),
+		"slice() %v out of bounds: %v", desc, in)
+	ans := &ObjectStoreIndexInput{
+		directory: in.directory,
+		blob:      in.blob,
+		off:       in.off + offset,
+		end:       in.off + offset + length,
+	}
+	ans.BufferedIndexInput = newBufferedIndexInputBySize(ans, desc, in.bufferSize)
+	return ans, nil
+}
+
+func (in *ObjectStoreIndexInput) Length() int64 {
+	return in.end - in.off
+}
+
+func (in *ObjectStoreIndexInput) readInternal(buf []byte) error {
+	position := in.off + in.FilePointer()
+	if position+int64(len(buf)) > in.end {
+		return fmt.Errorf("read past EOF: %v", in)
+	}
+	cache := in.directory.cache
+	for len(buf) > 0 {
+		block, err := cache.block(in.directory.store, in.blob, position)
+		if err != nil {
+			return fmt.Errorf("%v: %v", err, in)
+		}
+		n := copy(buf, block[position%cache.blockSize:])
+		buf = buf[n:]
+		position += int64(n)
+	}
+	return nil
+}
+
+func (in *ObjectStoreIndexInput) seekInternal(pos int64) error { return nil }
+
+// Buffers a file in memory, and uploads it when closed.
+type objectStoreIndexOutput struct {
+	*IndexOutputImpl
+	directory *ObjectStoreDirectory
+	name      string
+	output    *ByteBuffersDataOutput // nil once closed
+	length    int64                  // set once closed
+	crc       hash.Hash32
+}
+
+func newObjectStoreIndexOutput(directory *ObjectStoreDirectory, name string) *objectStoreIndexOutput {
+	ans := &objectStoreIndexOutput{
+		directory: directory,
+		name:      name,
+		output:    NewByteBuffersDataOutput(),
+		crc:       newBufferedChecksum(crc32.NewIEEE()),
+	}
+	ans.IndexOutputImpl = NewIndexOutput(ans)
+	return ans
+}
+
+func (out *objectStoreIndexOutput) WriteByte(b byte) error {
+	out.crc.Write([]byte{b})
+	return out.output.WriteByte(b)
+}
+
+func (out *objectStoreIndexOutput) WriteBytes(buf []byte) error {
+	out.crc.Write(buf)
+	return out.output.WriteBytes(buf)
+}
+
+func (out *objectStoreIndexOutput) FilePointer() int64 {
+	if out.output == nil {
+		return out.length
+	}
+	return out.output.Size()
+}
+
+func (out *objectStoreIndexOutput) Checksum() int64 {
+	return int64(out.crc.Sum32())
+}
+
+// Uploads the written content, replacing any previous version of the file.
+func (out *objectStoreIndexOutput) Close() error {
+	if out.output == nil {
+		return nil // already closed
+	}
+	output := out.output
+	out.output, out.length = nil, output.Size()
+	defer output.Reset()
+	_, err := out.directory.store.Put(out.directory.prefix+out.name, output.Reader(), output.Size())
+	return err
+}
+
+func (out *objectStoreIndexOutput) String() string {
+	return fmt.Sprintf("ObjectStoreIndexOutput(name=%v)", out.name)
+}
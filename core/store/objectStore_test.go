@@ -0,0 +1,96 @@
+package store
+
+import (
+	"testing"
+)
+
+func TestObjectStoreDirectory(t *testing.T) {
+	store := NewMemoryBlobStore()
+	other := NewObjectStoreDirectory(store, "other/")
+	writeTestFile(t, other, "_0.cfs", IO_CONTEXT_DEFAULT, 10)
+
+	d := NewObjectStoreDirectoryWith(store, "index/", 16, 64)
+	out, err := d.CreateOutput("_0.cfs", IO_CONTEXT_DEFAULT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := make([]byte, 100)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	out.WriteBytes(data)
+	// not visible until closed
+	assertEquals(t, d.FileExists("_0.cfs"), false)
+	if err = out.Close(); err != nil {
+		t.Fatal(err)
+	}
+	assertEquals(t, out.FilePointer(), int64(100))
+
+	all, err := d.ListAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEquals(t, len(all), 1)
+	assertEquals(t, all[0], "_0.cfs")
+	n, err := d.FileLength("_0.cfs")
+	assertEquals(t, n, int64(100))
+
+	// reads span blocks, and the cache stays within its limit
+	in, err := d.OpenInput("_0.cfs", IO_CONTEXT_READ)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 100)
+	if err = in.ReadBytes(buf); err != nil {
+		t.Fatal(err)
+	}
+	for i, b := range buf {
+		if b != byte(i) {
+			t.Fatalf("byte %v: expected %v, got %v", i, i, b)
+		}
+	}
+	if cached := d.CachedBytes(); cached == 0 || cached > 64 {
+		t.Errorf("cached bytes should be in (0, 64], but %v", cached)
+	}
+	slice, err := in.Slice("slice", 30, 40)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = slice.Seek(5); err != nil {
+		t.Fatal(err)
+	}
+	b, err := slice.ReadByte()
+	assertEquals(t, b, byte(35))
+
+	// an input keeps reading the version it was opened on
+	v1, _ := d.FileVersion("_0.cfs")
+	in2, err := d.OpenInput("_0.cfs", IO_CONTEXT_READ)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, d, "_0.cfs", IO_CONTEXT_DEFAULT, 50)
+	v2, _ := d.FileVersion("_0.cfs")
+	if v1 == v2 {
+		t.Errorf("version should change when the file is overwritten: %v", v1)
+	}
+	if err = in2.Seek(0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = in2.ReadByte(); err == nil {
+		t.Error("reading a replaced version should fail")
+	}
+	in.Close()
+	in2.Close()
+
+	if err = d.DeleteFile("_0.cfs"); err != nil {
+		t.Fatal(err)
+	}
+	assertEquals(t, d.FileExists("_0.cfs"), false)
+	assertEquals(t, other.FileExists("_0.cfs"), true)
+	assertEquals(t, d.LockID(), d.LockID())
+	if d.LockID() == other.LockID() {
+		t.Errorf("expected distinct lock IDs, but was %v", d.LockID())
+	}
+	d.Close()
+	assertEquals(t, d.CachedBytes(), int64(0))
+}
@@ -21,7 +21,7 @@ type OutputStreamIndexOutput struct {
 /* Creates a new OutputStreamIndexOutput with the given buffer size. */
 func newOutputStreamIndexOutput(out io.WriteCloser, bufferSize int) *OutputStreamIndexOutput {
 	ans := &OutputStreamIndexOutput{
-		crc: crc32.NewIEEE(),
+		crc: newBufferedChecksum(crc32.NewIEEE()),
 		os:  out,
 	}
 	ans.IndexOutputImpl = NewIndexOutput(ans)
@@ -31,6 +31,9 @@ It is recommended to materialze large indexes on disk and use
 MMapDirectory, which is a high-performance directory implementation
 working diretly on the file system cache of the operating system, so
 copying dat to Java heap space is not useful.
+
+Deprecated: use ByteBuffersDirectory instead, which does not suffer
+from the problems above.
 */
 type RAMDirectory struct {
 	*DirectoryImpl
@@ -140,7 +143,7 @@ func (rd *RAMDirectory) Sync(names []string) error {
 func (rd *RAMDirectory) OpenInput(name string, context IOContext) (in IndexInput, err error) {
 	rd.EnsureOpen()
 	if file, ok := rd.fileMap[name]; ok {
-		return newRAMInputStream(name, file)
+		return NewRAMInputStream(name, file)
 	}
 	return nil, errors.New(name)
 }
@@ -356,7 +359,7 @@ type RAMInputStream struct {
 	bufferLength   int
 }
 
-func newRAMInputStream(name string, f *RAMFile) (in *RAMInputStream, err error) {
+func NewRAMInputStream(name string, f *RAMFile) (in *RAMInputStream, err error) {
 	if !(f.length/BUFFER_SIZE < math.MaxInt32) {
 		return nil, errors.New(fmt.Sprintf("RAMInputStream too large length=%v: %v", f.length, name))
 	}
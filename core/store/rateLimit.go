@@ -1,7 +1,10 @@
 package store
 
 import (
+	"fmt"
 	"math"
+	"sync"
+	"time"
 )
 
 // store/RateLimiter.java
@@ -20,64 +23,102 @@ type RateLimiter interface {
 	MbPerSec() float64
 	/*
 		Pause, if necessary, to keep the instantaneous IO rate at or below
-		the target.
+		the target, and returns the time paused, in nanoseconds.
 
 		Note: the implementation is thread-safe
 	*/
 	Pause(bytes int64) int64
+	// How many bytes caller should add up itself before invoking Pause().
+	MinPauseCheckBytes() int64
 }
 
+// The time Pause() should be called at most every.
+const MIN_PAUSE_CHECK_MSEC = 5
+
 // Simple class to rate limit IO
-// Ian: volatile is not supported
 type SimpleRateLimiter struct {
-	mbPerSec           float64 // volatile
-	minPauseCheckBytes int64   // volatile
+	sync.Locker
+	mbPerSec           float64
+	minPauseCheckBytes int64
 	lastNS             int64
 }
 
 // mbPerSec is the MB/sec max IO rate
-func newSimpleRateLimiter(mbPerSec float64) *SimpleRateLimiter {
-	ans := &SimpleRateLimiter{}
+func NewSimpleRateLimiter(mbPerSec float64) *SimpleRateLimiter {
+	ans := &SimpleRateLimiter{Locker: &sync.Mutex{}}
 	ans.SetMbPerSec(mbPerSec)
+	ans.lastNS = time.Now().UnixNano()
 	return ans
 }
 
+// Sets an updated mb per second rate limit.
 func (srl *SimpleRateLimiter) SetMbPerSec(mbPerSec float64) {
+	srl.Lock() // synchronized
+	defer srl.Unlock()
 	srl.mbPerSec = mbPerSec
-	panic("not implemented yet")
+	// an infinite rate never pauses
+	srl.minPauseCheckBytes = math.MaxInt64
+	if bytes := MIN_PAUSE_CHECK_MSEC / 1000.0 * mbPerSec * 1024 * 1024; bytes < math.MaxInt64 {
+		srl.minPauseCheckBytes = int64(bytes)
+	}
 }
 
 func (srl *SimpleRateLimiter) MbPerSec() float64 {
+	srl.Lock() // synchronized
+	defer srl.Unlock()
 	return srl.mbPerSec
 }
 
+func (srl *SimpleRateLimiter) MinPauseCheckBytes() int64 {
+	srl.Lock() // synchronized
+	defer srl.Unlock()
+	return srl.minPauseCheckBytes
+}
+
 /*
 Pause, if necessary, to keep the instantaneous IO rate at or below
 the target. Be sure to only call this method when bytes >
 minPauseCheckBytes(), otherwise it will pause way too long!
 */
 func (srl *SimpleRateLimiter) Pause(bytes int64) int64 {
-	panic("not implemented yet")
-	// if bytes == 1 {
-	// 	return 0
-	// }
-
-	// // TODO: this is purely instantaneous rate; maybe we
-	// // should also offer decayed recent history one?
-	// srl.lastNS += int64(float64(bytes) * srl.nsPerByte)
-	// targetNS := srl.lastNS
-	// startNS := time.Now().UnixNano()
-	// curNS := startNS
-	// if srl.lastNS < curNS {
-	// 	srl.lastNS = curNS
-	// }
-
-	// // While loop because sleep doesn't always sleep enough:
-	// for pauseNS := targetNS - curNS; pauseNS > 0; pauseNS = targetNS - curNS {
-	// 	time.Sleep(time.Duration(pauseNS * int64(time.Nanosecond)))
-	// 	curNS = time.Now().UnixNano()
-	// }
-	// return curNS - startNS
+	startNS := time.Now().UnixNano()
+	targetNS := srl.target(bytes, startNS)
+	if targetNS <= startNS {
+		return 0
+	}
+
+	// While loop because sleep doesn't always sleep enough:
+	curNS := startNS
+	for pauseNS := targetNS - curNS; pauseNS > 0; pauseNS = targetNS - curNS {
+		time.Sleep(time.Duration(pauseNS))
+		curNS = time.Now().UnixNano()
+	}
+	return curNS - startNS
+}
+
+// Returns the time writing bytes more should end at, from startNS.
+func (srl *SimpleRateLimiter) target(bytes, startNS int64) int64 {
+	srl.Lock() // synchronized
+	defer srl.Unlock()
+	// TODO: this is purely instantaneous rate; maybe we
+	// should also offer decayed recent history one?
+	secondsToPause := float64(bytes) / 1024 / 1024 / srl.mbPerSec
+	targetNS := srl.lastNS + int64(1e9*secondsToPause)
+	if startNS >= targetNS {
+		// OK, current time is already beyond the target sleep time,
+		// no pausing to do.
+
+		// Set to startNS, not targetNS, to enforce the instant rate,
+		// not the "averaged over all history" rate:
+		srl.lastNS = startNS
+		return startNS
+	}
+	srl.lastNS = targetNS
+	return targetNS
+}
+
+func (srl *SimpleRateLimiter) String() string {
+	return fmt.Sprintf("SimpleRateLimiter(mbPerSec=%v)", srl.MbPerSec())
 }
 
 // store/RateLimitedDirectoryWrapper.java
@@ -86,20 +127,15 @@ func (srl *SimpleRateLimiter) Pause(bytes int64) int64 {
 // IO context specific rate limiters.
 type RateLimitedDirectoryWrapper struct {
 	Directory
-	// we need to be volatile here to make sure we see all the values
-	// that are set / modified concurrently
-	// Ian: volatile is not supported
-	contextRateLimiters []RateLimiter // volatile
-	isOpen              bool
+	sync.Locker
+	contextRateLimiters [IO_CONTEXT_TYPE_DEFAULT]RateLimiter // synchronized
 }
 
 func NewRateLimitedDirectoryWrapper(wrapped Directory) *RateLimitedDirectoryWrapper {
-	panic("not implemented yet")
-	// return &RateLimitedDirectoryWrapper{
-	// 	Directory:           wrapped,
-	// 	contextRateLimiters: make([]RateLimiter, 4), // TODO magic number
-	// 	isOpen:              true,
-	// }
+	return &RateLimitedDirectoryWrapper{
+		Directory: wrapped,
+		Locker:    &sync.Mutex{},
+	}
 }
 
 func (w *RateLimitedDirectoryWrapper) CreateOutput(name string, ctx IOContext) (IndexOutput, error) {
@@ -107,23 +143,20 @@ func (w *RateLimitedDirectoryWrapper) CreateOutput(name string, ctx IOContext) (
 	output, err := w.Directory.CreateOutput(name, ctx)
 	if err == nil {
 		if limiter := w.rateLimiter(ctx.context); limiter != nil {
-			output = newRateLimitedIndexOutput(limiter, output)
+			output = NewRateLimitedIndexOutput(limiter, output)
 		}
 	}
 	return output, err
 }
 
-// func (w *RateLimitedDirectoryWrapper) Close() error {
-// 	w.isOpen = false
-// 	return w.Directory.Close()
-// }
-
-// func (w *RateLimitedDirectoryWrapper) String() string {
-// 	return fmt.Sprintf("RateLimitedDirectoryWrapper(%v)", w.Directory)
-// }
+func (w *RateLimitedDirectoryWrapper) String() string {
+	return fmt.Sprintf("RateLimitedDirectoryWrapper(%v)", w.Directory)
+}
 
 func (w *RateLimitedDirectoryWrapper) rateLimiter(ctx IOContextType) RateLimiter {
 	assert(int(ctx) != 0)
+	w.Lock() // synchronized
+	defer w.Unlock()
 	return w.contextRateLimiters[int(ctx)-1]
 }
 
@@ -140,67 +173,72 @@ NOTE: this is an optional operation and might not be respected by all
 Directory implementations. Currently only buffered Directory
 implementations use rate-limiting.
 */
-func (w *RateLimitedDirectoryWrapper) SetMaxWriteMBPerSec(mbPerSec float64, context int) {
-	if !w.isOpen {
-		panic("this Directory is closed")
-	}
-	if context == 0 {
-		panic("Context must not be nil")
-	}
-	ord := context - 1
-	limiter := w.contextRateLimiters[ord]
-	if mbPerSec <= 0 {
-		if limiter != nil {
-			limiter.SetMbPerSec(math.MaxFloat64)
-			w.contextRateLimiters[ord] = nil
-			// atomic.StorePointer(&(w.contextRateLimiters[ord]), nil)
-		}
+func (w *RateLimitedDirectoryWrapper) SetMaxWriteMBPerSec(mbPerSec float64, context IOContextType) {
+	w.EnsureOpen()
+	assert2(context != 0, "Context must not be nil")
+	w.Lock() // synchronized
+	defer w.Unlock()
+	ord := int(context) - 1
+	if limiter := w.contextRateLimiters[ord]; mbPerSec <= 0 {
+		w.contextRateLimiters[ord] = nil
 	} else if limiter != nil {
 		limiter.SetMbPerSec(mbPerSec)
-		// atomic.StorePointer(&(w.contextRateLimiters[ord]), limiter) // cross the mem barrier again
 	} else {
-		w.contextRateLimiters[ord] = newSimpleRateLimiter(mbPerSec)
-		// atomic.StorePointer(&(w.contextRateLimiters[ord]), newSimpleRateLimiter(mbPerSec))
+		w.contextRateLimiters[ord] = NewSimpleRateLimiter(mbPerSec)
 	}
 }
 
 /*
 Sets the rate limiter to be used to limit (approx) MB/sec allowed by
-all IO performed with the given context. Pass non-positive to have no
-limit.
+all IO performed with the given context. Pass nil to have no limit.
 
 Passing an instance of rate limiter compared to settng it using
-setMaxWriteMBPersec() allows to use the same limiter instance across
+SetMaxWriteMBPerSec() allows to use the same limiter instance across
 several directories globally limiting IO across them.
 */
-func (w *RateLimitedDirectoryWrapper) setRateLimiter(mergeWriteRateLimiter RateLimiter, context int) {
-	panic("not implemented yet")
+func (w *RateLimitedDirectoryWrapper) SetRateLimiter(mergeWriteRateLimiter RateLimiter, context IOContextType) {
+	w.EnsureOpen()
+	assert2(context != 0, "Context must not be nil")
+	w.Lock() // synchronized
+	defer w.Unlock()
+	w.contextRateLimiters[int(context)-1] = mergeWriteRateLimiter
 }
 
-func (w *RateLimitedDirectoryWrapper) MaxWriteMBPerSec(context int) {
-	panic("not implemented yet")
+// See SetMaxWriteMBPerSec(); returns 0 if there is no limit.
+func (w *RateLimitedDirectoryWrapper) MaxWriteMBPerSec(context IOContextType) float64 {
+	w.EnsureOpen()
+	assert2(context != 0, "Context must not be nil")
+	if limiter := w.rateLimiter(context); limiter != nil {
+		return limiter.MbPerSec()
+	}
+	return 0
 }
 
 // store/RateLimitedIndexOutput.java
 
-type flushBuffer interface {
-	FlushBuffer(buf []byte) error
-}
-
-/* A rate limiting IndexOutput */
+/*
+A rate limiting IndexOutput, pausing each time more than the
+MinPauseCheckBytes() of its RateLimiter have been written.
+*/
 type RateLimitedIndexOutput struct {
 	*IndexOutputImpl
 	delegate    IndexOutput
 	rateLimiter RateLimiter
+	// How many bytes we've written since we last called Pause().
+	bytesSinceLastPause int64
+	// Cached here to not always have to ask the rate limiter, as it
+	// may lock.
+	currentMinPauseCheckBytes int64
 }
 
-func newRateLimitedIndexOutput(rateLimiter RateLimiter, delegate IndexOutput) *RateLimitedIndexOutput {
-	panic("not implemented yet")
-	// ans := &RateLimitedIndexOutput{}
-	// ans.BufferedIndexOutput = NewBufferedIndexOutput(DEFAULT_BUFFER_SIZE, ans)
-	// ans.delegate = delegate
-	// ans.rateLimiter = rateLimiter
-	// return ans
+func NewRateLimitedIndexOutput(rateLimiter RateLimiter, delegate IndexOutput) *RateLimitedIndexOutput {
+	ans := &RateLimitedIndexOutput{
+		delegate:                  delegate,
+		rateLimiter:               rateLimiter,
+		currentMinPauseCheckBytes: rateLimiter.MinPauseCheckBytes(),
+	}
+	ans.IndexOutputImpl = NewIndexOutput(ans)
+	return ans
 }
 
 func (out *RateLimitedIndexOutput) Close() error {
@@ -208,7 +246,7 @@ func (out *RateLimitedIndexOutput) Close() error {
 }
 
 func (out *RateLimitedIndexOutput) FilePointer() int64 {
-	panic("not implementd yet")
+	return out.delegate.FilePointer()
 }
 
 func (out *RateLimitedIndexOutput) Checksum() int64 {
@@ -216,22 +254,25 @@ func (out *RateLimitedIndexOutput) Checksum() int64 {
 }
 
 func (out *RateLimitedIndexOutput) WriteByte(b byte) error {
-	panic("not implemented yet")
+	out.bytesSinceLastPause++
+	out.checkRate()
+	return out.delegate.WriteByte(b)
 }
 
 func (out *RateLimitedIndexOutput) WriteBytes(p []byte) error {
-	panic("not implemented yet")
+	out.bytesSinceLastPause += int64(len(p))
+	out.checkRate()
+	return out.delegate.WriteBytes(p)
 }
 
-// func (out *RateLimitedIndexOutput) FlushBuffer(buf []byte) error {
-// 	out.rateLimiter.Pause(int64(len(buf)))
-// 	if v, ok := out.delegate.(flushBuffer); ok {
-// 		return v.FlushBuffer(buf)
-// 	}
-// 	panic("double check if flushBuffer interface is satisfied")
-// 	return out.delegate.WriteBytes(buf)
-// }
+func (out *RateLimitedIndexOutput) checkRate() {
+	if out.bytesSinceLastPause > out.currentMinPauseCheckBytes {
+		out.rateLimiter.Pause(out.bytesSinceLastPause)
+		out.bytesSinceLastPause = 0
+		out.currentMinPauseCheckBytes = out.rateLimiter.MinPauseCheckBytes()
+	}
+}
 
-// func (out *RateLimitedIndexOutput) Length() (int64, error) {
-// 	return out.delegate.Length()
-// }
+func (out *RateLimitedIndexOutput) String() string {
+	return fmt.Sprintf("RateLimitedIndexOutput(%v)", out.delegate)
+}
@@ -0,0 +1,44 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateLimitedDirectoryWrapper(t *testing.T) {
+	dir := NewRateLimitedDirectoryWrapper(NewByteBuffersDirectory())
+	dir.SetMaxWriteMBPerSec(10, IO_CONTEXT_TYPE_MERGE)
+	assertEquals(t, dir.MaxWriteMBPerSec(IO_CONTEXT_TYPE_MERGE), 10.0)
+	assertEquals(t, dir.MaxWriteMBPerSec(IO_CONTEXT_TYPE_FLUSH), 0.0)
+
+	out, err := dir.CreateOutput("flushed", NewIOContextForFlush(&FlushInfo{1, 1}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := out.(*RateLimitedIndexOutput); ok {
+		t.Error("flushes should not be rate limited")
+	}
+	out.Close()
+
+	out, err = dir.CreateOutput("merged", NewIOContextForMerge(&MergeInfo{1, 1 << 20, false, -1}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	chunk := make([]byte, 64<<10)
+	for i := 0; i < 16; i++ {
+		if err = out.WriteBytes(chunk); err != nil {
+			t.Fatal(err)
+		}
+	}
+	out.Close()
+	// 1 MB at 10 MB/sec, less the first pause check
+	if elapsed := time.Since(start); elapsed < 80*time.Millisecond {
+		t.Errorf("writing 1 MB at 10 MB/sec took only %v", elapsed)
+	}
+	n, _ := dir.FileLength("merged")
+	assertEquals(t, n, int64(1<<20))
+
+	dir.SetMaxWriteMBPerSec(0, IO_CONTEXT_TYPE_MERGE)
+	assertEquals(t, dir.MaxWriteMBPerSec(IO_CONTEXT_TYPE_MERGE), 0.0)
+}
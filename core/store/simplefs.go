@@ -42,13 +42,13 @@ func (lock *SimpleFSLock) Obtain() (ok bool, err error) {
 		return
 	}
 	var f *os.File
-	if f, err = os.Create(lock.file); err == nil {
-		fmt.Printf("File '%v' is created.\n", f.Name())
-		ok = true
-		defer f.Close()
+	if f, err = os.OpenFile(lock.file, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644); err == nil {
+		return true, f.Close()
+	} else if os.IsExist(err) {
+		// someone else holds the lock
+		return false, nil
 	}
 	return
-
 }
 
 func (lock *SimpleFSLock) Close() error {
@@ -68,7 +68,7 @@ func (lock *SimpleFSLock) String() string {
 }
 
 /*
-Implements LockFactory using os.Create().
+Implements LockFactory by exclusively creating the lock file.
 
 NOTE: This API may has the same issue as the one in Lucene Java that
 the write lock may not be released when Go program exists abnormally.
@@ -193,8 +193,10 @@ func newSimpleFSIndexInput(desc, path string, ctx IOContext) (*SimpleFSIndexInpu
 	}
 	fstat, err := f.Stat()
 	if err != nil {
+		f.Close()
 		return nil, err
 	}
+	adviseFile(f, ctx)
 	ans := new(SimpleFSIndexInput)
 	ans.BufferedIndexInput = newBufferedIndexInput(ans, desc, ctx)
 	ans.file = f
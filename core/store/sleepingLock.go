@@ -0,0 +1,117 @@
+package store
+
+import (
+	"fmt"
+	"time"
+)
+
+// store/SleepingLockWrapper.java
+
+/*
+A LockFactory wrapper whose locks retry Obtain() until they succeed,
+or the lock wait timeout passes, polling every poll interval. Both are
+in milliseconds; a timeout of LOCK_OBTAIN_WAIT_FOREVER waits forever.
+
+This is useful when a writer may be started while another one is
+still closing, so it waits for the write lock to be released instead
+of failing immediately:
+
+	d.SetLockFactory(NewSleepingLockFactory(
+		NewNativeFSLockFactory(path), 5000, LOCK_POOL_INTERVAL))
+
+NOTE: it is better to not use this wrapper at all, and to properly
+serialize the writers instead.
+*/
+type SleepingLockFactory struct {
+	LockFactory
+	lockWaitTimeout int64
+	pollInterval    int64
+}
+
+/*
+Creates a new SleepingLockFactory wrapping the given factory, with
+the given lock wait timeout and poll interval, in milliseconds.
+*/
+func NewSleepingLockFactory(delegate LockFactory, lockWaitTimeout, pollInterval int64) *SleepingLockFactory {
+	assert2(delegate != nil, "delegate must not be nil")
+	assert2(lockWaitTimeout >= 0 || lockWaitTimeout == LOCK_OBTAIN_WAIT_FOREVER,
+		"lockWaitTimeout should be LOCK_OBTAIN_WAIT_FOREVER or a non-negative number (got %v)",
+		lockWaitTimeout)
+	assert2(pollInterval >= 0, "pollInterval must be a non-negative number (got %v)", pollInterval)
+	return &SleepingLockFactory{delegate, lockWaitTimeout, pollInterval}
+}
+
+func (f *SleepingLockFactory) Make(name string) Lock {
+	ans := &sleepingLock{
+		delegate:        f.LockFactory.Make(name),
+		lockWaitTimeout: f.lockWaitTimeout,
+		pollInterval:    f.pollInterval,
+	}
+	ans.LockImpl = NewLockImpl(ans)
+	return ans
+}
+
+/*
+Returns the wrapped FSLockFactory, if any, so that FSDirectory can set
+its lock dir and prefix like for an unwrapped factory.
+*/
+func (f *SleepingLockFactory) fsLockFactory() *FSLockFactory {
+	if lf, ok := f.LockFactory.(interface {
+		fsLockFactory() *FSLockFactory
+	}); ok {
+		return lf.fsLockFactory()
+	}
+	return nil
+}
+
+func (f *SleepingLockFactory) String() string {
+	return fmt.Sprintf("SleepingLockFactory(%v)", f.LockFactory)
+}
+
+type sleepingLock struct {
+	*LockImpl
+	delegate        Lock
+	lockWaitTimeout int64
+	pollInterval    int64
+}
+
+/*
+Attempts to obtain the lock, retrying every poll interval. Returns an
+error if the lock is still held by someone else once the timeout
+passed, wrapping the reason the wrapped lock recorded for its last
+failed attempt, if any.
+*/
+func (lock *sleepingLock) Obtain() (ok bool, err error) {
+	deadline := time.Now().Add(time.Duration(lock.lockWaitTimeout) * time.Millisecond)
+	impl, _ := lock.delegate.(interface {
+		lockImpl() *LockImpl
+	})
+	for {
+		if impl != nil {
+			impl.lockImpl().failureReason = nil
+		}
+		if ok, err = lock.delegate.Obtain(); ok || err != nil {
+			return
+		}
+		if lock.lockWaitTimeout != LOCK_OBTAIN_WAIT_FOREVER && !time.Now().Before(deadline) {
+			if impl != nil && impl.lockImpl().failureReason != nil {
+				return false, fmt.Errorf("Lock obtain timed out: %v: %w",
+					lock.delegate, impl.lockImpl().failureReason)
+			}
+			return false, fmt.Errorf("Lock obtain timed out: %v", lock.delegate)
+		}
+		time.Sleep(time.Duration(lock.pollInterval) * time.Millisecond)
+	}
+}
+
+func (lock *sleepingLock) Close() error {
+	return lock.delegate.Close()
+}
+
+func (lock *sleepingLock) IsLocked() bool {
+	return lock.delegate.IsLocked()
+}
+
+func (lock *sleepingLock) String() string {
+	return fmt.Sprintf("SleepingLock(%v)", lock.delegate)
+}
@@ -0,0 +1,107 @@
+package store
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSleepingLockWaitsForRelease(t *testing.T) {
+	path, err := os.MkdirTemp("", "sleepinglock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(path)
+
+	f := NewSimpleFSLockFactory(path)
+	holder := f.Make("write.lock")
+	if ok, err := holder.Obtain(); err != nil || !ok {
+		t.Fatalf("first obtain failed: %v %v", ok, err)
+	}
+
+	// times out while the lock is held
+	lock := NewSleepingLockFactory(f, 50, 10).Make("write.lock")
+	start := time.Now()
+	if ok, err := lock.Obtain(); ok || err == nil {
+		t.Fatalf("obtain should time out: %v %v", ok, err)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("gave up after %v, before the timeout", elapsed)
+	}
+	assertEquals(t, true, lock.IsLocked())
+
+	// succeeds once the holder releases the lock
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		holder.Close()
+	}()
+	lock = NewSleepingLockFactory(f, LOCK_OBTAIN_WAIT_FOREVER, 10).Make("write.lock")
+	if ok, err := lock.Obtain(); err != nil || !ok {
+		t.Fatalf("obtain after release failed: %v %v", ok, err)
+	}
+	if err := lock.Close(); err != nil {
+		t.Fatal(err)
+	}
+	assertEquals(t, false, lock.IsLocked())
+}
+
+// A lock always failing to obtain, recording the given reason.
+type failingLock struct {
+	*LockImpl
+	reason error
+}
+
+func (lock *failingLock) Obtain() (bool, error) {
+	lock.failureReason = lock.reason
+	return false, nil
+}
+
+func (lock *failingLock) Close() error   { return nil }
+func (lock *failingLock) IsLocked() bool { return true }
+
+type failingLockFactory struct {
+	*LockFactoryImpl
+	reason error
+}
+
+func (f *failingLockFactory) Make(name string) Lock {
+	ans := &failingLock{reason: f.reason}
+	ans.LockImpl = NewLockImpl(ans)
+	return ans
+}
+
+func (f *failingLockFactory) Clear(name string) error { return nil }
+
+func TestSleepingLockFailureReason(t *testing.T) {
+	reason := errors.New("permission denied")
+	f := &failingLockFactory{&LockFactoryImpl{}, reason}
+	_, err := NewSleepingLockFactory(f, 20, 5).Make("write.lock").Obtain()
+	if !errors.Is(err, reason) {
+		t.Errorf("expected the timeout error to wrap %v, but was %v", reason, err)
+	}
+}
+
+func TestSleepingLockFactoryInFSDirectory(t *testing.T) {
+	path := t.TempDir()
+	d, err := NewNIOFSDirectory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d.Close()
+	// the wrapped factory gets its lock dir from the directory, and no
+	// lock prefix as the locks are in the index dir
+	wrapped := NewNativeFSLockFactory("")
+	d.SetLockFactory(NewSleepingLockFactory(wrapped, 0, 10))
+	assertEquals(t, wrapped.LockPrefix(), "")
+
+	lock := d.MakeLock("write.lock")
+	if ok, err := lock.Obtain(); err != nil || !ok {
+		t.Fatalf("obtain failed: %v %v", ok, err)
+	}
+	defer lock.Close()
+	if _, err = os.Stat(filepath.Join(path, "write.lock")); err != nil {
+		t.Error(err)
+	}
+}
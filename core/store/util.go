@@ -1,6 +1,7 @@
 package store
 
 import (
+	"fmt"
 	"github.com/balzaczyy/golucene/core/codec"
 )
 
@@ -18,7 +19,35 @@ func ChecksumEntireFile(input IndexInput) (hash int64, err error) {
 	}
 	in := newBufferedChecksumIndexInput(clone)
 	assert(in.FilePointer() == 0)
-	if err = in.Seek(in.Length() - codec.FOOTER_LENGTH); err != nil {
+	return checkEntireFooter(in)
+}
+
+/*
+Opens the named file of the directory as a ChecksumIndexInput, reads
+all its bytes, and calls CheckFooter(), returning the checksum of the
+file. This verifies any file written with a codec footer, whatever
+its codec.
+*/
+func VerifyChecksum(d Directory, name string) (hash int64, err error) {
+	in, err := d.OpenChecksumInput(name, IO_CONTEXT_READONCE)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err2 := in.Close(); err == nil {
+			err = err2
+		}
+	}()
+	return checkEntireFooter(in)
+}
+
+func checkEntireFooter(in ChecksumIndexInput) (int64, error) {
+	if in.Length() < codec.FOOTER_LENGTH {
+		return 0, fmt.Errorf(
+			"misplaced codec footer (file truncated?): length=%v but footerLength==%v (resource=%v)",
+			in.Length(), codec.FOOTER_LENGTH, in)
+	}
+	if err := in.Seek(in.Length() - codec.FOOTER_LENGTH); err != nil {
 		return 0, err
 	}
 	return codec.CheckFooter(in)
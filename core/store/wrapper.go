@@ -4,11 +4,16 @@ package store
 
 import (
 	"fmt"
+	"sort"
 	"sync"
 )
 
 /*
-A delegating Directory that records which files were written to and deleted.
+A delegating Directory that records which files were written to and
+deleted. IndexWriter uses it to delete the files of a segment whose
+flush or merge was aborted; CreatedFiles() exposes the same set, e.g.
+to see which files a flush produced, or to copy only the new files of
+an index in an incremental backup.
 */
 type TrackingDirectoryWrapper struct {
 	Directory
@@ -34,12 +39,14 @@ func (w *TrackingDirectoryWrapper) DeleteFile(name string) error {
 }
 
 func (w *TrackingDirectoryWrapper) CreateOutput(name string, ctx IOContext) (IndexOutput, error) {
-	func() {
-		w.Lock()
-		defer w.Unlock()
-		w.createdFilenames[name] = true
-	}()
-	return w.Directory.CreateOutput(name, ctx)
+	out, err := w.Directory.CreateOutput(name, ctx)
+	if err != nil {
+		return nil, err
+	}
+	w.Lock()
+	defer w.Unlock()
+	w.createdFilenames[name] = true
+	return out, nil
 }
 
 func (w *TrackingDirectoryWrapper) String() string {
@@ -63,6 +70,18 @@ func (w *TrackingDirectoryWrapper) EachCreatedFiles(f func(name string)) {
 	}
 }
 
+// Returns the sorted names of the files created, and not deleted since.
+func (w *TrackingDirectoryWrapper) CreatedFiles() []string {
+	w.Lock()
+	defer w.Unlock()
+	ans := make([]string, 0, len(w.createdFilenames))
+	for name := range w.createdFilenames {
+		ans = append(ans, name)
+	}
+	sort.Strings(ans)
+	return ans
+}
+
 func (w *TrackingDirectoryWrapper) ContainsFile(name string) bool {
 	w.Lock()
 	defer w.Unlock()
@@ -0,0 +1,23 @@
+package store
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTrackingDirectoryWrapper(t *testing.T) {
+	dir := NewTrackingDirectoryWrapper(NewByteBuffersDirectory())
+	for _, name := range []string{"_0.fdt", "_0.fdx", "_0.si"} {
+		out, err := dir.CreateOutput(name, IO_CONTEXT_DEFAULT)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out.Close()
+	}
+	if err := dir.DeleteFile("_0.fdx"); err != nil {
+		t.Fatal(err)
+	}
+	assertEquals(t, strings.Join(dir.CreatedFiles(), ","), "_0.fdt,_0.si")
+	assertEquals(t, dir.ContainsFile("_0.fdx"), false)
+	assertEquals(t, dir.ContainsFile("_0.si"), true)
+}
@@ -1,7 +1,11 @@
 package util
 
 import (
+	"bytes"
+	"fmt"
 	"reflect"
+	"sort"
+	"sync"
 )
 
 // util/Attribute.java
@@ -28,6 +32,90 @@ type AttributeImpl interface {
 	CopyTo(target AttributeImpl)
 }
 
+// util/AttributeReflector.java
+
+/*
+This function is called for each key/value pair of an attribute in
+ReflectWith(). The attName is the name of the Attribute interface
+the key belongs to.
+*/
+type AttributeReflector func(attName, key string, value interface{})
+
+/*
+Optionally implemented by AttributeImpls to expose their values, e.g.
+for debugging or for consumers that serialize the token stream. An
+AttributeImpl that does not implement it is reflected as its Go
+value under its first Attribute interface.
+*/
+type AttributeReflectable interface {
+	ReflectWith(reflector AttributeReflector)
+}
+
+func reflectImpl(att AttributeImpl, reflector AttributeReflector) {
+	if r, ok := att.(AttributeReflectable); ok {
+		r.ReflectWith(reflector)
+	} else if names := att.Interfaces(); len(names) > 0 {
+		reflector(names[0], "value", att)
+	}
+}
+
+/*
+Registry of AttributeImpl constructors keyed by the names of the
+Attribute interfaces they implement. This replaces Java's lookup of
+the "Impl" class by reflection: any package can register its own
+attributes, usually in init(), and they can then be added to every
+AttributeSource using the default attribute factory.
+*/
+var attributeImpls = struct {
+	sync.RWMutex
+	ctors map[string]func() AttributeImpl
+}{ctors: make(map[string]func() AttributeImpl)}
+
+/*
+Registers the constructor of an AttributeImpl for each Attribute
+interface it implements. It panics if one of them is already
+registered.
+*/
+func RegisterAttributeImpl(ctor func() AttributeImpl) {
+	names := ctor().Interfaces()
+	assert2(len(names) > 0, "AttributeImpl must implement at least one Attribute")
+	attributeImpls.Lock()
+	defer attributeImpls.Unlock()
+	for _, name := range names {
+		_, ok := attributeImpls.ctors[name]
+		assert2(!ok, "Attribute '%v' is already registered", name)
+	}
+	for _, name := range names {
+		attributeImpls.ctors[name] = ctor
+	}
+}
+
+/*
+Returns a new instance of the AttributeImpl registered for the given
+Attribute interface, or nil if there is none.
+*/
+func NewAttributeImpl(name string) AttributeImpl {
+	attributeImpls.RLock()
+	ctor, ok := attributeImpls.ctors[name]
+	attributeImpls.RUnlock()
+	if !ok {
+		return nil
+	}
+	return ctor()
+}
+
+/* Returns the sorted names of all registered Attribute interfaces. */
+func RegisteredAttributes() []string {
+	attributeImpls.RLock()
+	defer attributeImpls.RUnlock()
+	ans := make([]string, 0, len(attributeImpls.ctors))
+	for name := range attributeImpls.ctors {
+		ans = append(ans, name)
+	}
+	sort.Strings(ans)
+	return ans
+}
+
 // util/AttributeFactory.java
 
 /* An AttributeFactory creates instances of AttributeImpls. */
@@ -127,6 +215,11 @@ func (as *AttributeSource) AddImpl(att AttributeImpl) {
 	}
 }
 
+/* Returns the used AttributeFactory. */
+func (as *AttributeSource) Factory() AttributeFactory {
+	return as.factory
+}
+
 /* Returns true, iff this AttributeSource has any attributes */
 func (as *AttributeSource) hasAny() bool {
 	return len(as.attributes) > 0
@@ -213,10 +306,96 @@ func (as *AttributeSource) Clear() {
 }
 
 /*
-Returns a string consisting of the class's simple name, the hex
-representation of the identity hash code, and the current reflection
-of all attributes.
+Returns the AttributeImpls of this AttributeSource, ordered by the
+names of the Attribute interfaces they were added for.
+*/
+func (as *AttributeSource) AttributeImpls() []AttributeImpl {
+	names := make([]string, 0, len(as.attributes))
+	for name := range as.attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	seen := make(map[AttributeImpl]bool)
+	ans := make([]AttributeImpl, 0, len(as.attributeImpls))
+	for _, name := range names {
+		if att := as.attributes[name]; !seen[att] {
+			seen[att] = true
+			ans = append(ans, att)
+		}
+	}
+	return ans
+}
+
+/*
+Performs a clone of all AttributeImpl instances returned in a new
+AttributeSource instance. This method can be used to e.g. create
+another TokenStream with exactly the same attributes (using
+NewTokenStreamWith()). You can also use it as a (non-performant)
+replacement for CaptureState(), if you need to look into / modify the
+captured state.
+*/
+func (as *AttributeSource) CloneAttributes() *AttributeSource {
+	clone := NewAttributeSourceWith(as.factory)
+	for _, att := range as.AttributeImpls() {
+		clone.AddImpl(att.Clone())
+	}
+	return clone
+}
+
+/*
+Copies the contents of this AttributeSource to the given target
+AttributeSource. The given instance has to provide all Attributes
+this AttributeSource contains. The actual attribute implementations
+must be identical in both AttributeSource instances; ideally both
+AttributeSource instances should use the same AttributeFactory.
+*/
+func (as *AttributeSource) CopyTo(target *AttributeSource) {
+	for typ, att := range as.attributeImpls {
+		targetImpl, ok := target.attributeImpls[typ]
+		assert2(ok,
+			"This AttributeSource contains AttributeImpl of type %v that is not in the target",
+			typ)
+		att.CopyTo(targetImpl)
+	}
+}
+
+/*
+This method is for introspection of attributes, it should simply add
+the key/values this AttributeSource holds to the given
+AttributeReflector.
+*/
+func (as *AttributeSource) ReflectWith(reflector AttributeReflector) {
+	for _, att := range as.AttributeImpls() {
+		reflectImpl(att, reflector)
+	}
+}
+
+/*
+This method returns the current attribute values as a string in the
+following format by calling ReflectWith():
+
+  - if prependAttClass=true: "AttributeClass#key=value,AttributeClass#key=value"
+  - if prependAttClass=false: "key=value,key=value"
+*/
+func (as *AttributeSource) ReflectAsString(prependAttClass bool) string {
+	var buf bytes.Buffer
+	as.ReflectWith(func(attName, key string, value interface{}) {
+		if buf.Len() > 0 {
+			buf.WriteByte(',')
+		}
+		if prependAttClass {
+			buf.WriteString(attName)
+			buf.WriteByte('#')
+		}
+		fmt.Fprintf(&buf, "%v=%v", key, value)
+	})
+	return buf.String()
+}
+
+/*
+Returns a string consisting of the type name and the current
+reflection of all attributes.
 */
 func (as *AttributeSource) String() string {
-	panic("not implemented yet")
+	return fmt.Sprintf("AttributeSource(%v)", as.ReflectAsString(false))
 }
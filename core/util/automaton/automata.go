@@ -8,8 +8,8 @@ import (
 
 // Returns a new (deterministic) automaton with the empty language.
 func MakeEmpty() *Automaton {
-	a := newEmptyAutomaton()
-	a.finishState()
+	a := NewAutomaton()
+	a.FinishState()
 	return a
 }
 
@@ -37,28 +37,28 @@ func makeCharRange(min, max int) *Automaton {
 		return MakeEmpty()
 	}
 
-	a := newEmptyAutomaton()
-	s1 := a.createState()
-	s2 := a.createState()
-	a.setAccept(s2, true)
-	a.addTransitionRange(s1, s2, min, max)
-	a.finishState()
+	a := NewAutomaton()
+	s1 := a.CreateState()
+	s2 := a.CreateState()
+	a.SetAccept(s2, true)
+	a.AddTransitionRange(s1, s2, min, max)
+	a.FinishState()
 	return a
 }
 
 // L237
 // Returns a new (deterministic) automaton that accepts the single given string
 func makeString(s string) *Automaton {
-	a := newEmptyAutomaton()
-	lastState := a.createState()
+	a := NewAutomaton()
+	lastState := a.CreateState()
 	for _, r := range s {
-		state := a.createState()
-		a.addTransitionRange(lastState, state, int(r), int(r))
+		state := a.CreateState()
+		a.AddTransitionRange(lastState, state, int(r), int(r))
 		lastState = state
 	}
 
-	a.setAccept(lastState, true)
-	a.finishState()
+	a.SetAccept(lastState, true)
+	a.FinishState()
 
 	assert(a.deterministic)
 	assert(!hasDeadStates(a))
@@ -66,6 +66,24 @@ func makeString(s string) *Automaton {
 	return a
 }
 
+/*
+Returns a new (deterministic) automaton that accepts the single given
+string from the specified unicode code points.
+*/
+func MakeStringFromInts(word []int) *Automaton {
+	a := NewAutomaton()
+	a.CreateState()
+	s := 0
+	for _, c := range word {
+		s2 := a.CreateState()
+		a.AddTransition(s, s2, c)
+		s = s2
+	}
+	a.SetAccept(s, true)
+	a.FinishState()
+	return a
+}
+
 // L271
 /*
 Returns a new (deterministic and minimal) automaton that accepts the
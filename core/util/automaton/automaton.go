@@ -30,7 +30,7 @@ type Automaton struct {
 	deterministic bool
 }
 
-func newEmptyAutomaton() *Automaton {
+func NewAutomaton() *Automaton {
 	return &Automaton{
 		deterministic: true,
 		curState:      -1,
@@ -44,15 +44,15 @@ func (a *Automaton) String() string {
 }
 
 /* Create a new state. */
-func (a *Automaton) createState() int {
+func (a *Automaton) CreateState() int {
 	state := len(a.states) / 2
 	a.states = append(a.states, -1, 0)
 	return state
 }
 
 /* Set or clear this state as an accept state. */
-func (a *Automaton) setAccept(state int, accept bool) {
-	assert2(state < a.numStates(), "state=%v is out of bounds (numStates=%v)", state, a.numStates())
+func (a *Automaton) SetAccept(state int, accept bool) {
+	assert2(state < a.NumStates(), "state=%v is out of bounds (numStates=%v)", state, a.NumStates())
 	if accept {
 		a.isAccept.Set(int64(state))
 	} else {
@@ -65,13 +65,13 @@ Sugar to get all transitions for all states. This is object-heavy;
 it's better to iterate state by state instead.
 */
 func (a *Automaton) sortedTransitions() [][]*Transition {
-	numStates := a.numStates()
+	numStates := a.NumStates()
 	transitions := make([][]*Transition, numStates)
 	for s := 0; s < numStates; s++ {
-		numTransitions := a.numTransitions(s)
+		numTransitions := a.NumTransitions(s)
 		transitions[s] = make([]*Transition, numTransitions)
 		for t := 0; t < numTransitions; t++ {
-			transition := newTransition()
+			transition := NewTransition()
 			a.transition(s, t, transition)
 			transitions[s][t] = transition
 		}
@@ -85,15 +85,15 @@ func (a *Automaton) IsAccept(state int) bool {
 }
 
 /* Add a new transition with min = max = label. */
-func (a *Automaton) addTransition(source, dest, label int) {
-	a.addTransitionRange(source, dest, label, label)
+func (a *Automaton) AddTransition(source, dest, label int) {
+	a.AddTransitionRange(source, dest, label, label)
 }
 
 /* Add a new transition with the specified source, dest, min, max. */
-func (a *Automaton) addTransitionRange(source, dest, min, max int) {
+func (a *Automaton) AddTransitionRange(source, dest, min, max int) {
 	assert(len(a.transitions)%3 == 0)
-	assert2(source < a.numStates(), "source=%v is out of bounds (maxState is %v)", source, a.numStates()-1)
-	assert2(dest < a.numStates(), "dest=%v is out of bounds (maxState is %v)", dest, a.numStates()-1)
+	assert2(source < a.NumStates(), "source=%v is out of bounds (maxState is %v)", source, a.NumStates()-1)
+	assert2(dest < a.NumStates(), "dest=%v is out of bounds (maxState is %v)", dest, a.NumStates()-1)
 
 	if a.curState != source {
 		if a.curState != -1 {
@@ -119,14 +119,14 @@ state must already have all transitions added because this method
 simply copies those same transitions over to source.
 */
 func (a *Automaton) addEpsilon(source, dest int) {
-	t := newTransition()
-	count := a.initTransition(dest, t)
+	t := NewTransition()
+	count := a.InitTransition(dest, t)
 	for i := 0; i < count; i++ {
-		a.nextTransition(t)
-		a.addTransitionRange(source, t.dest, t.min, t.max)
+		a.NextTransition(t)
+		a.AddTransitionRange(source, t.Dest, t.Min, t.Max)
 	}
 	if a.IsAccept(dest) {
-		a.setAccept(source, true)
+		a.SetAccept(source, true)
 	}
 }
 
@@ -136,7 +136,7 @@ sequentially assigned (appended).
 */
 func (a *Automaton) copy(other *Automaton) {
 	// bulk copy and then fixup the state pointers
-	stateOffset := a.numStates()
+	stateOffset := a.NumStates()
 	a.states = append(a.states, other.states...)
 	for i := 0; i < len(other.states); i += 2 {
 		if a.states[stateOffset*2+i] != -1 {
@@ -145,7 +145,7 @@ func (a *Automaton) copy(other *Automaton) {
 	}
 	otherAcceptState := other.isAccept
 	for state := otherAcceptState.NextSetBit(0); state != -1; state = otherAcceptState.NextSetBit(state + 1) {
-		a.setAccept(stateOffset+int(state), true)
+		a.SetAccept(stateOffset+int(state), true)
 	}
 
 	// bulk copy and then fixup dest for each transition
@@ -235,7 +235,7 @@ transitions for a state. This is automatically called if you start
 adding transitions to a new source state, but for the last state you
 add, you need to call this method yourself.
 */
-func (a *Automaton) finishState() {
+func (a *Automaton) FinishState() {
 	if a.curState != -1 {
 		a.finishCurrentState()
 		a.curState = -1
@@ -243,12 +243,12 @@ func (a *Automaton) finishState() {
 }
 
 /* How many states this automaton has. */
-func (a *Automaton) numStates() int {
+func (a *Automaton) NumStates() int {
 	return len(a.states) / 2
 }
 
 /* How many transitions this state has. */
-func (a *Automaton) numTransitions(state int) int {
+func (a *Automaton) NumTransitions(state int) int {
 	if count := a.states[2*state+1]; count != -1 {
 		return count
 	}
@@ -332,23 +332,23 @@ func (s minMaxDestSorter) Less(i, j int) bool {
 
 /*
 Initialize the provided Transition to iterate through all transitions
-leaving the specified state. You must call nextTransition() to get
+leaving the specified state. You must call NextTransition() to get
 each transition. Returns the number of transitions leaving this tate.
 */
-func (a *Automaton) initTransition(state int, t *Transition) int {
-	assert2(state < a.numStates(), "state=%v nextState=%v", state, a.numStates())
-	t.source = state
+func (a *Automaton) InitTransition(state int, t *Transition) int {
+	assert2(state < a.NumStates(), "state=%v nextState=%v", state, a.NumStates())
+	t.Source = state
 	t.transitionUpto = a.states[2*state]
-	return a.numTransitions(state)
+	return a.NumTransitions(state)
 }
 
 /* Iterate to the next transition after the provided one */
-func (a *Automaton) nextTransition(t *Transition) {
+func (a *Automaton) NextTransition(t *Transition) {
 	// make sure there is still a transition left
-	assert((t.transitionUpto + 3 - a.states[2*t.source]) <= 3*a.states[2*t.source+1])
-	t.dest = a.transitions[t.transitionUpto]
-	t.min = a.transitions[t.transitionUpto+1]
-	t.max = a.transitions[t.transitionUpto+2]
+	assert((t.transitionUpto + 3 - a.states[2*t.Source]) <= 3*a.states[2*t.Source+1])
+	t.Dest = a.transitions[t.transitionUpto]
+	t.Min = a.transitions[t.transitionUpto+1]
+	t.Max = a.transitions[t.transitionUpto+2]
 	t.transitionUpto += 3
 }
 
@@ -358,10 +358,10 @@ specified state.
 */
 func (a *Automaton) transition(state, index int, t *Transition) {
 	i := a.states[2*state] + 3*index
-	t.source = state
-	t.dest = a.transitions[i]
-	t.min = a.transitions[i+1]
-	t.max = a.transitions[i+2]
+	t.Source = state
+	t.Dest = a.transitions[i]
+	t.Min = a.transitions[i+1]
+	t.Max = a.transitions[i+2]
 }
 
 // L563
@@ -393,7 +393,7 @@ func (a *Automaton) startPoints() []int {
 }
 
 /* Performs lookup in transitions, assuming determinism. */
-func (a *Automaton) step(state, label int) int {
+func (a *Automaton) Step(state, label int) int {
 	assert(state >= 0)
 	assert(label >= 0)
 	if 2*state >= len(a.states) {
@@ -420,16 +420,39 @@ type AutomatonBuilder struct {
 	a           *Automaton
 }
 
-func newAutomatonBuilder() *AutomatonBuilder {
+func NewAutomatonBuilder() *AutomatonBuilder {
 	return &AutomatonBuilder{
-		a: newEmptyAutomaton(),
+		a: NewAutomaton(),
 	}
 }
 
-func (b *AutomatonBuilder) addTransitionRange(source, dest, min, max int) {
+/* Add a new transition with min = max = label. */
+func (b *AutomatonBuilder) AddTransition(source, dest, label int) {
+	b.AddTransitionRange(source, dest, label, label)
+}
+
+/* Add a new transition with the specified source, dest, min, max. */
+func (b *AutomatonBuilder) AddTransitionRange(source, dest, min, max int) {
 	b.transitions = append(b.transitions, source, dest, min, max)
 }
 
+/*
+Add a [virtual] epsilon transition between source and dest. Dest
+state must already have all transitions added because this method
+simply copies those same transitions over to source.
+*/
+func (b *AutomatonBuilder) AddEpsilon(source, dest int) {
+	for upto := 0; upto < len(b.transitions); upto += 4 {
+		if b.transitions[upto] == dest {
+			b.AddTransitionRange(source, b.transitions[upto+1],
+				b.transitions[upto+2], b.transitions[upto+3])
+		}
+	}
+	if b.isAccept(dest) {
+		b.SetAccept(source, true)
+	}
+}
+
 type srcMinMaxDestSorter []int
 
 func (s srcMinMaxDestSorter) Len() int {
@@ -472,12 +495,12 @@ func (s srcMinMaxDestSorter) Less(i, j int) bool {
 }
 
 /* Compiles all added states and transitions into a new Automaton and returns it. */
-func (b *AutomatonBuilder) finish() *Automaton {
+func (b *AutomatonBuilder) Finish() *Automaton {
 	// fmt.Printf("LA.Builder.finish: count=%v\n", len(b.transitions)/4)
 	// fmt.Println("finish pending")
 	util.NewInPlaceMergeSorter(srcMinMaxDestSorter(b.transitions)).Sort(0, len(b.transitions)/4)
 	for upto := 0; upto < len(b.transitions); upto += 4 {
-		b.a.addTransitionRange(
+		b.a.AddTransitionRange(
 			b.transitions[upto],
 			b.transitions[upto+1],
 			b.transitions[upto+2],
@@ -485,35 +508,43 @@ func (b *AutomatonBuilder) finish() *Automaton {
 		)
 	}
 
-	b.a.finishState()
+	b.a.FinishState()
 	return b.a
 }
 
-func (b *AutomatonBuilder) createState() int {
-	return b.a.createState()
+func (b *AutomatonBuilder) CreateState() int {
+	return b.a.CreateState()
 }
 
-func (b *AutomatonBuilder) setAccept(state int, accept bool) {
-	b.a.setAccept(state, accept)
+func (b *AutomatonBuilder) SetAccept(state int, accept bool) {
+	b.a.SetAccept(state, accept)
 }
 
 func (b *AutomatonBuilder) isAccept(state int) bool {
 	return b.a.IsAccept(state)
 }
 
+/* Copies over all states from other. */
+func (b *AutomatonBuilder) CopyStates(other *Automaton) {
+	for s, numStates := 0, other.NumStates(); s < numStates; s++ {
+		newState := b.CreateState()
+		b.SetAccept(newState, other.IsAccept(s))
+	}
+}
+
 func (b *AutomatonBuilder) copy(other *Automaton) {
-	offset := b.a.numStates()
-	otherNumStates := other.numStates()
+	offset := b.a.NumStates()
+	otherNumStates := other.NumStates()
 	for s := 0; s < otherNumStates; s++ {
-		newState := b.createState()
-		b.setAccept(newState, other.IsAccept(s))
+		newState := b.CreateState()
+		b.SetAccept(newState, other.IsAccept(s))
 	}
-	t := newTransition()
+	t := NewTransition()
 	for s := 0; s < otherNumStates; s++ {
-		count := other.initTransition(s, t)
+		count := other.InitTransition(s, t)
 		for i := 0; i < count; i++ {
-			other.nextTransition(t)
-			b.addTransitionRange(offset+s, offset+t.dest, t.min, t.max)
+			other.NextTransition(t)
+			b.AddTransitionRange(offset+s, offset+t.Dest, t.Min, t.Max)
 		}
 	}
 }
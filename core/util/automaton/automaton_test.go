@@ -14,7 +14,7 @@ func TestRegExpToAutomaton(t *testing.T) {
 	a := NewRegExp("[^ \t\r\n]+").ToAutomaton()
 	assert(a.deterministic)
 	assert(-1 == a.curState)
-	assert(2 == a.numStates())
+	assert(2 == a.NumStates())
 }
 
 func TestMinusSimple(t *testing.T) {
@@ -32,8 +32,8 @@ func TestDeterminizeSimple(t *testing.T) {
 	a2 := NewRegExpWithFlag("ݖ|+", NONE).ToAutomaton()
 	a := concatenate(a1, a2)
 	a = removeDeadStates(a)
-	a = determinize(a)
-	assert(a.numStates() == 4)
+	a = Determinize(a)
+	assert(a.NumStates() == 4)
 }
 
 // func TestStringUnion(t testing.T) {
@@ -214,11 +214,11 @@ func minimizeSimple(a *Automaton) *Automaton {
 }
 
 /*
-Simple original brics implementation of determinize()
+Simple original brics implementation of Determinize()
 Determinizes the given automaton using the given set of initial states.
 */
 func determinizeSimple(a *Automaton, initialset map[int]bool) *Automaton {
-	if a.numStates() == 0 {
+	if a.NumStates() == 0 {
 		return a
 	}
 	points := a.startPoints()
@@ -235,27 +235,27 @@ func determinizeSimple(a *Automaton, initialset map[int]bool) *Automaton {
 	newstate := make(map[string]int)
 	sets[hash(initialset)] = true
 	worklist.PushBack(initialset)
-	b := newAutomatonBuilder()
-	b.createState()
+	b := NewAutomatonBuilder()
+	b.CreateState()
 	newstate[hash(initialset)] = 0
-	t := newTransition()
+	t := NewTransition()
 	for worklist.Len() > 0 {
 		s := worklist.Remove(worklist.Front()).(map[int]bool)
 		r := newstate[hash(s)]
 		for q, _ := range s {
 			if a.IsAccept(q) {
-				b.setAccept(r, true)
+				b.SetAccept(r, true)
 				break
 			}
 		}
 		for n, point := range points {
 			p := make(map[int]bool)
 			for q, _ := range s {
-				count := a.initTransition(q, t)
+				count := a.InitTransition(q, t)
 				for i := 0; i < count; i++ {
-					a.nextTransition(t)
-					if t.min <= point && point <= t.max {
-						p[t.dest] = true
+					a.NextTransition(t)
+					if t.Min <= point && point <= t.Max {
+						p[t.Dest] = true
 					}
 				}
 			}
@@ -264,7 +264,7 @@ func determinizeSimple(a *Automaton, initialset map[int]bool) *Automaton {
 			if _, ok := sets[hashKey]; !ok {
 				sets[hashKey] = true
 				worklist.PushBack(p)
-				newstate[hashKey] = b.createState()
+				newstate[hashKey] = b.CreateState()
 			}
 			q := newstate[hashKey]
 			min := point
@@ -274,9 +274,9 @@ func determinizeSimple(a *Automaton, initialset map[int]bool) *Automaton {
 			} else {
 				max = unicode.MaxRune
 			}
-			b.addTransitionRange(r, q, min, max)
+			b.AddTransitionRange(r, q, min, max)
 		}
 	}
 
-	return removeDeadStates(b.finish())
+	return removeDeadStates(b.Finish())
 }
@@ -45,7 +45,7 @@ func buildDaciukMihovAutomaton(input [][]byte) *Automaton {
 	// 	builder.add(scratch)
 	// }
 
-	// a := newEmptyAutomaton()
+	// a := NewAutomaton()
 	// a.initial = convert(
 	// 	builder.complete(),
 	// 	make(map[*dfsaState]*State))
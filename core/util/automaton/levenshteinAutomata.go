@@ -0,0 +1,117 @@
+package automaton
+
+// util/automaton/LevenshteinAutomata.java
+
+// Maximum edit distance this class can generate an automaton for.
+const MAXIMUM_SUPPORTED_DISTANCE = 2
+
+/*
+Class to construct DFAs that match a word within some edit distance.
+
+Implements the algorithm described in: Schulz and Mihov: Fast String
+Correction with Levenshtein Automata.
+
+Rather than stepping through precomputed parametric descriptions,
+this builds the Levenshtein NFA of the word and determinizes it,
+which is cheap for the small distances supported.
+*/
+type LevenshteinAutomata struct {
+	// the input word
+	word []int
+	// the maximum symbol in the alphabet (e.g. 255 for UTF-8 or
+	// 0x10FFFF for UTF-32)
+	alphaMax int
+	// whether a transposition counts as a single edit
+	withTranspositions bool
+}
+
+/*
+Expert: specify a custom maximum possible symbol (alphaMax); default
+is unicode.MaxRune.
+*/
+func NewLevenshteinAutomata(word []int, alphaMax int, withTranspositions bool) *LevenshteinAutomata {
+	return &LevenshteinAutomata{word, alphaMax, withTranspositions}
+}
+
+/*
+Compute a DFA that accepts all strings within an edit distance of n.
+
+All automata have the following properties:
+
+  - They are deterministic (DFA).
+  - There are no transitions to dead states.
+  - They are not minimal (some transitions could be combined).
+
+Returns nil if n is greater than MAXIMUM_SUPPORTED_DISTANCE.
+*/
+func (l *LevenshteinAutomata) ToAutomaton(n int) *Automaton {
+	return l.ToAutomatonWithPrefix(n, "")
+}
+
+/*
+Compute a DFA that accepts all strings within an edit distance of n,
+matching the specified exact prefix.
+*/
+func (l *LevenshteinAutomata) ToAutomatonWithPrefix(n int, prefix string) *Automaton {
+	assert(n >= 0)
+	if n > MAXIMUM_SUPPORTED_DISTANCE {
+		return nil
+	}
+
+	b := NewAutomatonBuilder()
+
+	// the exact prefix leads to the initial state of the word:
+	last := b.CreateState()
+	for _, c := range prefix {
+		s := b.CreateState()
+		b.AddTransition(last, s, int(c))
+		last = s
+	}
+
+	// state (i, e) means i chars of the word were consumed with e
+	// edits
+	w := len(l.word)
+	states := make([][]int, w+1)
+	for i := range states {
+		states[i] = make([]int, n+1)
+		for e := range states[i] {
+			if i == 0 && e == 0 {
+				states[i][e] = last
+			} else {
+				states[i][e] = b.CreateState()
+			}
+		}
+	}
+
+	// Go backwards so the targets of deletions (epsilon transitions)
+	// already have all their transitions when we copy them:
+	for i := w; i >= 0; i-- {
+		for e := n; e >= 0; e-- {
+			s := states[i][e]
+			if i == w {
+				b.SetAccept(s, true)
+			} else {
+				// match
+				b.AddTransition(s, states[i+1][e], l.word[i])
+			}
+			if e == n {
+				continue
+			}
+			// insertion
+			b.AddTransitionRange(s, states[i][e+1], 0, l.alphaMax)
+			if i < w {
+				// substitution
+				b.AddTransitionRange(s, states[i+1][e+1], 0, l.alphaMax)
+				// deletion
+				b.AddEpsilon(s, states[i+1][e+1])
+			}
+			if l.withTranspositions && i+1 < w && l.word[i] != l.word[i+1] {
+				t := b.CreateState()
+				b.AddTransition(s, t, l.word[i+1])
+				b.AddTransition(t, states[i+2][e+1], l.word[i])
+			}
+		}
+	}
+
+	return Determinize(b.Finish())
+}
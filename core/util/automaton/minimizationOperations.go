@@ -17,16 +17,16 @@ func minimize(a *Automaton) *Automaton {
 
 // Minimizes the given automaton using Hopcroft's alforithm.
 func minimizeHopcroft(a *Automaton) *Automaton {
-	if a.numStates() == 0 || !a.IsAccept(0) && a.numTransitions(0) == 0 {
+	if a.NumStates() == 0 || !a.IsAccept(0) && a.NumTransitions(0) == 0 {
 		// fastmatch for common case
-		return newEmptyAutomaton()
+		return NewAutomaton()
 	}
-	a = determinize(a)
-	if a.numTransitions(0) == 1 {
-		t := newTransition()
+	a = Determinize(a)
+	if a.NumTransitions(0) == 1 {
+		t := NewTransition()
 		a.transition(0, 0, t)
-		if t.dest == 0 && t.min == MIN_CODE_POINT &&
-			t.max == unicode.MaxRune {
+		if t.Dest == 0 && t.Min == MIN_CODE_POINT &&
+			t.Max == unicode.MaxRune {
 			// accepts all strings
 			return a
 		}
@@ -35,7 +35,7 @@ func minimizeHopcroft(a *Automaton) *Automaton {
 
 	// initialize data structure
 	sigma := a.startPoints()
-	sigmaLen, statesLen := len(sigma), a.numStates()
+	sigmaLen, statesLen := len(sigma), a.NumStates()
 
 	reverse := make([][][]int, statesLen)
 	for i, _ := range reverse {
@@ -69,9 +69,9 @@ func minimizeHopcroft(a *Automaton) *Automaton {
 		partition[j][q] = true
 		block[q] = j
 		for x, v := range sigma {
-			n := a.step(q, v)
+			n := a.Step(q, v)
 			assert2(n >= 0 && n < len(reverse), "%v", n)
-			r := reverse[a.step(q, v)]
+			r := reverse[a.Step(q, v)]
 			r[x] = append(r[x], q)
 		}
 	}
@@ -156,15 +156,15 @@ func minimizeHopcroft(a *Automaton) *Automaton {
 		refine = util.NewOpenBitSet() // not quite efficient
 	}
 
-	ans := newEmptyAutomaton()
-	t := newTransition()
+	ans := NewAutomaton()
+	t := NewTransition()
 	// fmt.Printf("  k=%v\n", k)
 
 	// make a new state for each equivalence class, set initial state
 	stateMap := make([]int, statesLen)
 	stateRep := make([]int, k)
 
-	ans.createState()
+	ans.CreateState()
 
 	// fmt.Printf("min: k=%v\n", k)
 	for n := 0; n < k; n++ {
@@ -181,7 +181,7 @@ func minimizeHopcroft(a *Automaton) *Automaton {
 
 		newState := 0
 		if !isInitial {
-			newState = ans.createState()
+			newState = ans.CreateState()
 		}
 
 		// fmt.Printf("  newState=%v\n", newState)
@@ -189,22 +189,22 @@ func minimizeHopcroft(a *Automaton) *Automaton {
 		for q, _ := range partition[n] {
 			stateMap[q] = newState
 			// fmt.Printf("      q=%v isAccept?=%v\n", q, a.IsAccept(q))
-			ans.setAccept(newState, a.IsAccept(q))
+			ans.SetAccept(newState, a.IsAccept(q))
 			stateRep[newState] = q // select representative
 		}
 	}
 
 	// build transitions and set acceptance
 	for n := 0; n < k; n++ {
-		numTransitions := a.initTransition(stateRep[n], t)
+		numTransitions := a.InitTransition(stateRep[n], t)
 		for i := 0; i < numTransitions; i++ {
-			a.nextTransition(t)
+			a.NextTransition(t)
 			// fmt.Println("  add trans")
-			ans.addTransitionRange(n, stateMap[t.dest], t.min, t.max)
+			ans.AddTransitionRange(n, stateMap[t.Dest], t.Min, t.Max)
 		}
 	}
-	ans.finishState()
-	// fmt.Printf("%v states\n", ans.numStates())
+	ans.FinishState()
+	// fmt.Printf("%v states\n", ans.NumStates())
 
 	return removeDeadStates(ans)
 }
@@ -39,11 +39,11 @@ func TestMinimizeCase3(t *testing.T) {
 }
 
 func TestRemoveDeadStatesSimple(t *testing.T) {
-	a := newEmptyAutomaton()
-	a.createState()
-	assert(a.numStates() == 1)
+	a := NewAutomaton()
+	a.CreateState()
+	assert(a.NumStates() == 1)
 	a = removeDeadStates(a)
-	assert(a.numStates() == 0)
+	assert(a.NumStates() == 0)
 }
 
 // util/automaton/TestMinimize.java
@@ -54,7 +54,7 @@ func TestMinimize(t *testing.T) {
 	num := AtLeast(200)
 	for i := 0; i < num; i++ {
 		a := randomAutomaton(Random())
-		la := determinize(removeDeadStates(a))
+		la := Determinize(removeDeadStates(a))
 		lb := minimize(a)
 		It(t).Should("have same language for %v and %v from %v", la, lb, a).
 			Verify(sameLanguage(la, lb))
@@ -74,16 +74,16 @@ func TestAgainstBrzozowski(t *testing.T) {
 		b := minimize(a)
 		It(t).Should("have same language for %v and %v from %v", a, b, o).
 			Verify(sameLanguage(a, b))
-		It(t).Should("have same number of states (%v vs %v)", a.numStates(), b.numStates()).
-			Verify(a.numStates() == b.numStates())
+		It(t).Should("have same number of states (%v vs %v)", a.NumStates(), b.NumStates()).
+			Verify(a.NumStates() == b.NumStates())
 
 		sum1 := 0
-		for s := 0; s < a.numStates(); s++ {
-			sum1 += a.numTransitions(s)
+		for s := 0; s < a.NumStates(); s++ {
+			sum1 += a.NumTransitions(s)
 		}
 		sum2 := 0
-		for s := 0; s < b.numStates(); s++ {
-			sum2 += b.numTransitions(s)
+		for s := 0; s < b.NumStates(); s++ {
+			sum2 += b.NumTransitions(s)
 		}
 		It(t).Should("have same number of transitions (%v vs %v)", sum1, sum2).
 			Verify(sum1 == sum2)
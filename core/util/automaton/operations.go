@@ -26,26 +26,26 @@ languages of the given automata.
 Complexity: linear in total number of states.
 */
 func concatenateN(l []*Automaton) *Automaton {
-	ans := newEmptyAutomaton()
+	ans := NewAutomaton()
 
 	// first pass: create all states
 	for _, a := range l {
-		if a.numStates() == 0 {
-			ans.finishState()
+		if a.NumStates() == 0 {
+			ans.FinishState()
 			return ans
 		}
-		numStates := a.numStates()
+		numStates := a.NumStates()
 		for s := 0; s < numStates; s++ {
-			ans.createState()
+			ans.CreateState()
 		}
 	}
 
 	// second pass: add transitions, carefully linking accept
 	// states of A to init state of next A:
 	stateOffset := 0
-	t := newTransition()
+	t := NewTransition()
 	for i, a := range l {
-		numStates := a.numStates()
+		numStates := a.NumStates()
 
 		var nextA *Automaton
 		if i < len(l)-1 {
@@ -53,10 +53,10 @@ func concatenateN(l []*Automaton) *Automaton {
 		}
 
 		for s := 0; s < numStates; s++ {
-			numTransitions := a.initTransition(s, t)
+			numTransitions := a.InitTransition(s, t)
 			for j := 0; j < numTransitions; j++ {
-				a.nextTransition(t)
-				ans.addTransitionRange(stateOffset+s, stateOffset+t.dest, t.min, t.max)
+				a.NextTransition(t)
+				ans.AddTransitionRange(stateOffset+s, stateOffset+t.Dest, t.Min, t.Max)
 			}
 
 			if a.IsAccept(s) {
@@ -66,14 +66,14 @@ func concatenateN(l []*Automaton) *Automaton {
 				for {
 					if followA != nil {
 						// adds a "virtual" epsilon transition:
-						numTransitions = followA.initTransition(0, t)
+						numTransitions = followA.InitTransition(0, t)
 						for j := 0; j < numTransitions; j++ {
-							followA.nextTransition(t)
-							ans.addTransitionRange(stateOffset+s, followOffset+numStates+t.dest, t.min, t.max)
+							followA.NextTransition(t)
+							ans.AddTransitionRange(stateOffset+s, followOffset+numStates+t.Dest, t.Min, t.Max)
 						}
 						if followA.IsAccept(0) {
 							// keep chaning if followA accepts empty string
-							followOffset += followA.numStates()
+							followOffset += followA.NumStates()
 							if upto < len(l)-1 {
 								followA = l[upto+1]
 							} else {
@@ -84,7 +84,7 @@ func concatenateN(l []*Automaton) *Automaton {
 							break
 						}
 					} else {
-						ans.setAccept(stateOffset+s, true)
+						ans.SetAccept(stateOffset+s, true)
 						break
 					}
 				}
@@ -94,11 +94,11 @@ func concatenateN(l []*Automaton) *Automaton {
 		stateOffset += numStates
 	}
 
-	if ans.numStates() == 0 {
-		ans.createState()
+	if ans.NumStates() == 0 {
+		ans.CreateState()
 	}
 
-	ans.finishState()
+	ans.FinishState()
 	return ans
 }
 
@@ -109,14 +109,14 @@ the language of the given automaton.
 Complexity: linear in number of states.
 */
 func optional(a *Automaton) *Automaton {
-	ans := newEmptyAutomaton()
-	ans.createState()
-	ans.setAccept(0, true)
-	if a.numStates() > 0 {
+	ans := NewAutomaton()
+	ans.CreateState()
+	ans.SetAccept(0, true)
+	if a.NumStates() > 0 {
 		ans.copy(a)
 		ans.addEpsilon(0, 1)
 	}
-	ans.finishState()
+	ans.FinishState()
 	return ans
 }
 
@@ -132,30 +132,30 @@ func repeat(a *Automaton) *Automaton {
 		return a
 	}
 
-	b := newAutomatonBuilder()
-	b.createState()
-	b.setAccept(0, true)
+	b := NewAutomatonBuilder()
+	b.CreateState()
+	b.SetAccept(0, true)
 	b.copy(a)
 
-	t := newTransition()
-	count := a.initTransition(0, t)
+	t := NewTransition()
+	count := a.InitTransition(0, t)
 	for i := 0; i < count; i++ {
-		a.nextTransition(t)
-		b.addTransitionRange(0, t.dest+1, t.min, t.max)
+		a.NextTransition(t)
+		b.AddTransitionRange(0, t.Dest+1, t.Min, t.Max)
 	}
 
-	numStates := a.numStates()
+	numStates := a.NumStates()
 	for s := 0; s < numStates; s++ {
 		if a.IsAccept(s) {
-			count = a.initTransition(0, t)
+			count = a.InitTransition(0, t)
 			for i := 0; i < count; i++ {
-				a.nextTransition(t)
-				b.addTransitionRange(s+1, t.dest+1, t.min, t.max)
+				a.NextTransition(t)
+				b.AddTransitionRange(s+1, t.Dest+1, t.Min, t.Max)
 			}
 		}
 	}
 
-	return b.finish()
+	return b.Finish()
 }
 
 /*
@@ -184,10 +184,10 @@ the language of the given automaton.
 Complexity: linear in number of states (if already deterministic).
 */
 func complement(a *Automaton) *Automaton {
-	a = totalize(determinize(a))
-	numStates := a.numStates()
+	a = totalize(Determinize(a))
+	numStates := a.NumStates()
 	for p := 0; p < numStates; p++ {
-		a.setAccept(p, !a.IsAccept(p))
+		a.SetAccept(p, !a.IsAccept(p))
 	}
 	return removeDeadStates(a)
 }
@@ -220,17 +220,17 @@ of the given automata. Never modifies the input automata languages.
 Complexity: quadratic in number of states.
 */
 func intersection(a1, a2 *Automaton) *Automaton {
-	if a1 == a2 || a1.numStates() == 0 {
+	if a1 == a2 || a1.NumStates() == 0 {
 		return a1
 	}
-	if a2.numStates() == 0 {
+	if a2.NumStates() == 0 {
 		return a2
 	}
 
 	transitions1 := a1.sortedTransitions()
 	transitions2 := a2.sortedTransitions()
-	c := newEmptyAutomaton()
-	c.createState()
+	c := NewAutomaton()
+	c.CreateState()
 	worklist := list.New()
 	newstates := make(map[string]*StatePair)
 	hash := func(p *StatePair) string {
@@ -241,31 +241,31 @@ func intersection(a1, a2 *Automaton) *Automaton {
 	newstates[hash(p)] = p
 	for worklist.Len() > 0 {
 		p = worklist.Remove(worklist.Front()).(*StatePair)
-		c.setAccept(p.s, a1.IsAccept(p.s1) && a2.IsAccept(p.s2))
+		c.SetAccept(p.s, a1.IsAccept(p.s1) && a2.IsAccept(p.s2))
 		t1 := transitions1[p.s1]
 		t2 := transitions2[p.s2]
 		for n1, b2 := 0, 0; n1 < len(t1); n1++ {
-			for b2 < len(t2) && t2[b2].max < t1[n1].min {
+			for b2 < len(t2) && t2[b2].Max < t1[n1].Min {
 				b2++
 			}
-			for n2 := b2; n2 < len(t2) && t1[n1].max >= t2[n2].min; n2++ {
-				if t2[n2].max >= t1[n1].min {
-					q := &StatePair{-1, t1[n1].dest, t2[n2].dest}
+			for n2 := b2; n2 < len(t2) && t1[n1].Max >= t2[n2].Min; n2++ {
+				if t2[n2].Max >= t1[n1].Min {
+					q := &StatePair{-1, t1[n1].Dest, t2[n2].Dest}
 					r, ok := newstates[hash(q)]
 					if !ok {
-						q.s = c.createState()
+						q.s = c.CreateState()
 						worklist.PushBack(q)
 						newstates[hash(q)] = q
 						r = q
 					}
-					min := or(t1[n1].min > t2[n2].min, t1[n1].min, t2[n2].min).(int)
-					max := or(t1[n1].max < t2[n2].max, t1[n1].max, t2[n2].max).(int)
-					c.addTransitionRange(p.s, r.s, min, max)
+					min := or(t1[n1].Min > t2[n2].Min, t1[n1].Min, t2[n2].Min).(int)
+					max := or(t1[n1].Max < t2[n2].Max, t1[n1].Max, t2[n2].Max).(int)
+					c.AddTransitionRange(p.s, r.s, min, max)
 				}
 			}
 		}
 	}
-	c.finishState()
+	c.FinishState()
 	return removeDeadStates(c)
 }
 
@@ -289,7 +289,7 @@ Cost is O(numTransitions+numStates).
 func hasDeadStates(a *Automaton) bool {
 	liveStates := liveStates(a)
 	numLive := liveStates.Cardinality()
-	numStates := a.numStates()
+	numStates := a.NumStates()
 	assert2(numLive <= int64(numStates), "numLive=%v numStates=%v %v", numLive, numStates, liveStates)
 	return numLive < int64(numStates)
 }
@@ -311,10 +311,10 @@ func subsetOf(a1, a2 *Automaton) bool {
 	assert2(a2.deterministic, "a2 must be deterministic")
 	assert(!hasDeadStatesFromInitial(a1))
 	assert2(!hasDeadStatesFromInitial(a2), "%v", a2)
-	if a1.numStates() == 0 {
+	if a1.NumStates() == 0 {
 		// empty language is always a subset of any other language
 		return true
-	} else if a2.numStates() == 0 {
+	} else if a2.NumStates() == 0 {
 		return isEmpty(a1)
 	}
 
@@ -337,21 +337,21 @@ func subsetOf(a1, a2 *Automaton) bool {
 		t2 := transitions2[p.s2]
 		for n1, b2, t1Len := 0, 0, len(t1); n1 < t1Len; n1++ {
 			t2Len := len(t2)
-			for b2 < t2Len && t2[b2].max < t1[n1].min {
+			for b2 < t2Len && t2[b2].Max < t1[n1].Min {
 				b2++
 			}
-			min1, max1 := t1[n1].min, t1[n1].max
+			min1, max1 := t1[n1].Min, t1[n1].Max
 
-			for n2 := b2; n2 < t2Len && t1[n1].max >= t2[n2].min; n2++ {
-				if t2[n2].min > min1 {
+			for n2 := b2; n2 < t2Len && t1[n1].Max >= t2[n2].Min; n2++ {
+				if t2[n2].Min > min1 {
 					return false
 				}
-				if t2[n2].max < unicode.MaxRune {
-					min1 = t2[n2].max + 1
+				if t2[n2].Max < unicode.MaxRune {
+					min1 = t2[n2].Max + 1
 				} else {
 					min1, max1 = unicode.MaxRune, MIN_CODE_POINT
 				}
-				q := &StatePair{-1, t1[n1].dest, t2[n2].dest}
+				q := &StatePair{-1, t1[n1].Dest, t2[n2].Dest}
 				if _, ok := visited[hash(q)]; !ok {
 					worklist.PushBack(q)
 					visited[hash(q)] = q
@@ -372,7 +372,7 @@ given automta.
 Complexity: linear in number of states.
 */
 func union(a1, a2 *Automaton) *Automaton {
-	return unionN([]*Automaton{a1, a2})
+	return UnionN([]*Automaton{a1, a2})
 }
 
 /*
@@ -381,10 +381,10 @@ given automata.
 
 Complexity: linear in number of states.
 */
-func unionN(l []*Automaton) *Automaton {
-	ans := newEmptyAutomaton()
+func UnionN(l []*Automaton) *Automaton {
+	ans := NewAutomaton()
 	// create initial state
-	ans.createState()
+	ans.CreateState()
 	// copy over all automata
 	for _, a := range l {
 		ans.copy(a)
@@ -392,13 +392,13 @@ func unionN(l []*Automaton) *Automaton {
 	// add epsilon transition from new initial state
 	stateOffset := 1
 	for _, a := range l {
-		if a.numStates() == 0 {
+		if a.NumStates() == 0 {
 			continue
 		}
 		ans.addEpsilon(0, stateOffset)
-		stateOffset += a.numStates()
+		stateOffset += a.NumStates()
 	}
-	ans.finishState()
+	ans.FinishState()
 	return removeDeadStates(ans)
 }
 
@@ -408,7 +408,7 @@ type TransitionList struct {
 }
 
 func (l *TransitionList) add(t *Transition) {
-	l.transitions = append(l.transitions, t.dest, t.min, t.max)
+	l.transitions = append(l.transitions, t.Dest, t.Min, t.Max)
 }
 
 // Holds all transitions that start on this int point, or end at this
@@ -506,8 +506,8 @@ func (pts *PointTransitionSet) sort() {
 }
 
 func (pts *PointTransitionSet) add(t *Transition) {
-	pts.find(t.min).starts.add(t)
-	pts.find(1 + t.max).ends.add(t)
+	pts.find(t.Min).starts.add(t)
+	pts.find(1 + t.Max).ends.add(t)
 }
 
 func (pts *PointTransitionSet) String() string {
@@ -521,20 +521,20 @@ Split the code points in ranges, and merge overlapping states.
 
 Worst case complexity: exponential in number of states.
 */
-func determinize(a *Automaton) *Automaton {
-	if a.deterministic || a.numStates() <= 1 {
+func Determinize(a *Automaton) *Automaton {
+	if a.deterministic || a.NumStates() <= 1 {
 		return a
 	}
 
 	// subset construction
-	b := newAutomatonBuilder()
+	b := NewAutomatonBuilder()
 
 	// fmt.Println("DET:")
 
 	initialset := newFrozenIntSetOf(0, 0)
 
 	// craete state 0:
-	b.createState()
+	b.CreateState()
 
 	worklist := list.New()
 	newstate := make(map[string]int)
@@ -544,7 +544,7 @@ func determinize(a *Automaton) *Automaton {
 
 	worklist.PushBack(initialset)
 
-	b.setAccept(0, a.IsAccept(0))
+	b.SetAccept(0, a.IsAccept(0))
 	newstate[hash(initialset)] = 0
 
 	// like map[int]*PointTransitions
@@ -553,7 +553,7 @@ func determinize(a *Automaton) *Automaton {
 	// like sorted map[int]int
 	statesSet := newSortedIntSet(5)
 
-	t := newTransition()
+	t := NewTransition()
 
 	for worklist.Len() > 0 {
 		s := worklist.Remove(worklist.Front()).(*FrozenIntSet)
@@ -561,10 +561,10 @@ func determinize(a *Automaton) *Automaton {
 
 		// Collate all outgoing transitions by min/1+max
 		for _, s0 := range s.values {
-			numTransitions := a.numTransitions(s0)
-			a.initTransition(s0, t)
+			numTransitions := a.NumTransitions(s0)
+			a.InitTransition(s0, t)
 			for j := 0; j < numTransitions; j++ {
-				a.nextTransition(t)
+				a.NextTransition(t)
 				points.add(t)
 			}
 		}
@@ -590,11 +590,11 @@ func determinize(a *Automaton) *Automaton {
 
 				q, ok := newstate[hashKey]
 				if !ok {
-					q = b.createState()
+					q = b.CreateState()
 					p := statesSet.freeze(q)
 					// fmt.Printf("  make new state=%v -> %v accCount=%v\n", q, p, accCount)
 					worklist.PushBack(p)
-					b.setAccept(q, accCount > 0)
+					b.SetAccept(q, accCount > 0)
 					newstate[hash(p)] = q
 				} else {
 					assert2(b.isAccept(q) == (accCount > 0),
@@ -604,7 +604,7 @@ func determinize(a *Automaton) *Automaton {
 
 				// fmt.Printf("  add trans src=%v dest=%v min=%v max=%v\n",
 				// 	r, q, lastPoint, point-1)
-				b.addTransitionRange(r, q, lastPoint, point-1)
+				b.AddTransitionRange(r, q, lastPoint, point-1)
 			}
 
 			// process transitions that end on this point
@@ -635,7 +635,7 @@ func determinize(a *Automaton) *Automaton {
 		assert2(len(statesSet.values) == 0, "upto=%v", len(statesSet.values))
 	}
 
-	ans := b.finish()
+	ans := b.Finish()
 	assert(ans.deterministic)
 	return ans
 }
@@ -643,11 +643,11 @@ func determinize(a *Automaton) *Automaton {
 // // L779
 // Returns true if the given automaton accepts no strings.
 func isEmpty(a *Automaton) bool {
-	if a.numStates() == 0 {
+	if a.NumStates() == 0 {
 		// common case: no states
 		return true
 	}
-	if !a.IsAccept(0) && a.numTransitions(0) == 0 {
+	if !a.IsAccept(0) && a.NumTransitions(0) == 0 {
 		// common case: just one initial state
 		return true
 	}
@@ -661,18 +661,18 @@ func isEmpty(a *Automaton) bool {
 	workList.PushBack(0)
 	seen.Set(0)
 
-	t := newTransition()
+	t := NewTransition()
 	for workList.Len() > 0 {
 		state := workList.Remove(workList.Front()).(int)
 		if a.IsAccept(state) {
 			return false
 		}
-		count := a.initTransition(state, t)
+		count := a.InitTransition(state, t)
 		for i := 0; i < count; i++ {
-			a.nextTransition(t)
-			if !seen.Get(int64(t.dest)) {
-				workList.PushBack(t.dest)
-				seen.Set(int64(t.dest))
+			a.NextTransition(t)
+			if !seen.Get(int64(t.Dest)) {
+				workList.PushBack(t.Dest)
+				seen.Set(int64(t.Dest))
 			}
 		}
 	}
@@ -694,7 +694,7 @@ func isEmpty(a *Automaton) bool {
 // 	if a.deterministic {
 // 		p := a.initial
 // 		for _, ch := range s {
-// 			q := p.step(int(ch))
+// 			q := p.Step(int(ch))
 // 			if q == nil {
 // 				return false
 // 			}
@@ -718,7 +718,7 @@ func liveStates(a *Automaton) *util.OpenBitSet {
 
 /* Returns BitSet marking states reachable from the initial state. */
 func liveStatesFromInitial(a *Automaton) *util.OpenBitSet {
-	numStates := a.numStates()
+	numStates := a.NumStates()
 	live := util.NewOpenBitSet()
 	if numStates == 0 {
 		return live
@@ -727,15 +727,15 @@ func liveStatesFromInitial(a *Automaton) *util.OpenBitSet {
 	live.Set(0)
 	workList.PushBack(0)
 
-	t := newTransition()
+	t := NewTransition()
 	for workList.Len() > 0 {
 		s := workList.Remove(workList.Front()).(int)
-		count := a.initTransition(s, t)
+		count := a.InitTransition(s, t)
 		for i := 0; i < count; i++ {
-			a.nextTransition(t)
-			if !live.Get(int64(t.dest)) {
-				live.Set(int64(t.dest))
-				workList.PushBack(t.dest)
+			a.NextTransition(t)
+			if !live.Get(int64(t.Dest)) {
+				live.Set(int64(t.Dest))
+				workList.PushBack(t.Dest)
 			}
 		}
 	}
@@ -745,22 +745,22 @@ func liveStatesFromInitial(a *Automaton) *util.OpenBitSet {
 
 /* Returns BitSet marking states that can reach an accept state. */
 func liveStatesToAccept(a *Automaton) *util.OpenBitSet {
-	builder := newAutomatonBuilder()
+	builder := NewAutomatonBuilder()
 
 	// NOTE: not quite the same thing as what SpecialOperations.reverse does:
-	t := newTransition()
-	numStates := a.numStates()
+	t := NewTransition()
+	numStates := a.NumStates()
 	for s := 0; s < numStates; s++ {
-		builder.createState()
+		builder.CreateState()
 	}
 	for s := 0; s < numStates; s++ {
-		count := a.initTransition(s, t)
+		count := a.InitTransition(s, t)
 		for i := 0; i < count; i++ {
-			a.nextTransition(t)
-			builder.addTransitionRange(t.dest, s, t.min, t.max)
+			a.NextTransition(t)
+			builder.AddTransitionRange(t.Dest, s, t.Min, t.Max)
 		}
 	}
-	a2 := builder.finish()
+	a2 := builder.Finish()
 
 	workList := list.New()
 	live := util.NewOpenBitSet()
@@ -778,12 +778,12 @@ func liveStatesToAccept(a *Automaton) *util.OpenBitSet {
 
 	for workList.Len() > 0 {
 		s = workList.Remove(workList.Front()).(int)
-		count := a2.initTransition(s, t)
+		count := a2.InitTransition(s, t)
 		for i := 0; i < count; i++ {
-			a2.nextTransition(t)
-			if !live.Get(int64(t.dest)) {
-				live.Set(int64(t.dest))
-				workList.PushBack(t.dest)
+			a2.NextTransition(t)
+			if !live.Get(int64(t.Dest)) {
+				live.Set(int64(t.Dest))
+				workList.PushBack(t.Dest)
 			}
 		}
 	}
@@ -797,36 +797,36 @@ reachable from the initial state or no accept state is reachable from
 it.)
 */
 func removeDeadStates(a *Automaton) *Automaton {
-	numStates := a.numStates()
+	numStates := a.NumStates()
 	liveSet := liveStates(a)
 
 	m := make([]int, numStates)
 
-	ans := newEmptyAutomaton()
+	ans := NewAutomaton()
 	// fmt.Printf("liveSet: %v numStates=%v\n", liveSet, numStates)
 	for i := 0; i < numStates; i++ {
 		if liveSet.Get(int64(i)) {
-			m[i] = ans.createState()
-			ans.setAccept(m[i], a.IsAccept(i))
+			m[i] = ans.CreateState()
+			ans.SetAccept(m[i], a.IsAccept(i))
 		}
 	}
 
-	t := newTransition()
+	t := NewTransition()
 
 	for i := 0; i < numStates; i++ {
 		if liveSet.Get(int64(i)) {
-			numTransitions := a.initTransition(i, t)
+			numTransitions := a.InitTransition(i, t)
 			// filter out transitions to dead states:
 			for j := 0; j < numTransitions; j++ {
-				a.nextTransition(t)
-				if liveSet.Get(int64(t.dest)) {
-					ans.addTransitionRange(m[i], m[t.dest], t.min, t.max)
+				a.NextTransition(t)
+				if liveSet.Get(int64(t.Dest)) {
+					ans.AddTransitionRange(m[i], m[t.Dest], t.Min, t.Max)
 				}
 			}
 		}
 	}
 
-	ans.finishState()
+	ans.FinishState()
 	assert(!hasDeadStates(ans))
 	return ans
 }
@@ -853,34 +853,34 @@ func findIndex(c int, points []int) int {
 /* Returns an automaton accepting the reverse language. */
 func reverse(a *Automaton) (*Automaton, map[int]bool) {
 	if isEmpty(a) {
-		return newEmptyAutomaton(), nil
+		return NewAutomaton(), nil
 	}
 
-	numStates := a.numStates()
+	numStates := a.NumStates()
 
 	// build a new automaton with all edges reversed
-	b := newAutomatonBuilder()
+	b := NewAutomatonBuilder()
 
 	// initial node; we'll add epsilon transitions in the end:
-	b.createState()
+	b.CreateState()
 	for s := 0; s < numStates; s++ {
-		b.createState()
+		b.CreateState()
 	}
 
 	// old initial state becomes new accept state:
-	b.setAccept(1, true)
+	b.SetAccept(1, true)
 
-	t := newTransition()
+	t := NewTransition()
 	for s := 0; s < numStates; s++ {
-		numTransitions := a.numTransitions(s)
-		a.initTransition(s, t)
+		numTransitions := a.NumTransitions(s)
+		a.InitTransition(s, t)
 		for i := 0; i < numTransitions; i++ {
-			a.nextTransition(t)
-			b.addTransitionRange(t.dest+1, s+1, t.min, t.max)
+			a.NextTransition(t)
+			b.AddTransitionRange(t.Dest+1, s+1, t.Min, t.Max)
 		}
 	}
 
-	ans := b.finish()
+	ans := b.Finish()
 	initialStates := make(map[int]bool)
 
 	acceptStates := a.isAccept
@@ -889,7 +889,7 @@ func reverse(a *Automaton) (*Automaton, map[int]bool) {
 		initialStates[int(s+1)] = true
 	}
 
-	ans.finishState()
+	ans.FinishState()
 	return ans, initialStates
 }
 
@@ -899,36 +899,123 @@ transitions to a dead state so that from every state and every label
 there is a transition.
 */
 func totalize(a *Automaton) *Automaton {
-	ans := newEmptyAutomaton()
-	numStates := a.numStates()
+	ans := NewAutomaton()
+	numStates := a.NumStates()
 	for i := 0; i < numStates; i++ {
-		ans.createState()
-		ans.setAccept(i, a.IsAccept(i))
+		ans.CreateState()
+		ans.SetAccept(i, a.IsAccept(i))
 	}
 
-	deadState := ans.createState()
-	ans.addTransitionRange(deadState, deadState, MIN_CODE_POINT, unicode.MaxRune)
+	deadState := ans.CreateState()
+	ans.AddTransitionRange(deadState, deadState, MIN_CODE_POINT, unicode.MaxRune)
 
-	t := newTransition()
+	t := NewTransition()
 	for i := 0; i < numStates; i++ {
 		maxi := MIN_CODE_POINT
-		count := a.initTransition(i, t)
+		count := a.InitTransition(i, t)
 		for j := 0; j < count; j++ {
-			a.nextTransition(t)
-			ans.addTransitionRange(i, t.dest, t.min, t.max)
-			if t.min > maxi {
-				ans.addTransitionRange(i, deadState, maxi, t.min-1)
+			a.NextTransition(t)
+			ans.AddTransitionRange(i, t.Dest, t.Min, t.Max)
+			if t.Min > maxi {
+				ans.AddTransitionRange(i, deadState, maxi, t.Min-1)
 			}
-			if t.max+1 > maxi {
-				maxi = t.max + 1
+			if t.Max+1 > maxi {
+				maxi = t.Max + 1
 			}
 		}
 
 		if maxi <= unicode.MaxRune {
-			ans.addTransitionRange(i, deadState, maxi, unicode.MaxRune)
+			ans.AddTransitionRange(i, deadState, maxi, unicode.MaxRune)
 		}
 	}
 
-	ans.finishState()
+	ans.FinishState()
 	return ans
 }
+
+/*
+Returns the topological sort of all states reachable from the initial
+state. Behavior is undefined if this automaton has cycles. CPU cost
+is O(numTransitions), and the implementation is recursive so an
+automaton matching long strings may consume a lot of stack.
+*/
+func TopoSortStates(a *Automaton) []int {
+	numStates := a.NumStates()
+	if numStates == 0 {
+		return nil
+	}
+	states := make([]int, numStates)
+	visited := util.NewOpenBitSet()
+	visited.Set(0)
+	upto := topoSortStatesRecurse(a, visited, states, 0, 0)
+
+	if upto < len(states) {
+		// There were dead states
+		states = states[:upto]
+	}
+
+	// Reverse the order:
+	for i, j := 0, len(states)-1; i < j; i, j = i+1, j-1 {
+		states[i], states[j] = states[j], states[i]
+	}
+	return states
+}
+
+func topoSortStatesRecurse(a *Automaton, visited *util.OpenBitSet, states []int, upto, state int) int {
+	t := NewTransition()
+	count := a.InitTransition(state, t)
+	for i := 0; i < count; i++ {
+		a.NextTransition(t)
+		if !visited.Get(int64(t.Dest)) {
+			visited.Set(int64(t.Dest))
+			upto = topoSortStatesRecurse(a, visited, states, upto, t.Dest)
+		}
+	}
+	states[upto] = state
+	return upto + 1
+}
+
+/*
+Returns the set of accepted strings, assuming that at most limit
+strings are accepted. If more than limit strings are accepted, the
+first limit strings found are returned. If limit < 0, then the limit
+is infinite.
+
+The automaton must not have cycles, otherwise it panics.
+*/
+func FiniteStrings(a *Automaton, limit int) [][]int {
+	var results [][]int
+	if limit == 0 || a.NumStates() == 0 {
+		return results
+	}
+	pathStates := util.NewOpenBitSet()
+	finiteStringsRecurse(a, 0, pathStates, nil, &results, limit)
+	return results
+}
+
+func finiteStringsRecurse(a *Automaton, state int, pathStates *util.OpenBitSet,
+	path []int, results *[][]int, limit int) bool {
+
+	if a.IsAccept(state) {
+		*results = append(*results, append([]int(nil), path...))
+		if limit >= 0 && len(*results) == limit {
+			return false
+		}
+	}
+	pathStates.Set(int64(state))
+	t := NewTransition()
+	count := a.InitTransition(state, t)
+	for i := 0; i < count; i++ {
+		a.NextTransition(t)
+		if pathStates.Get(int64(t.Dest)) {
+			panic("automaton has cycles")
+		}
+		for label := t.Min; label <= t.Max; label++ {
+			if !finiteStringsRecurse(a, t.Dest, pathStates, append(path, label), results, limit) {
+				return false
+			}
+		}
+	}
+	pathStates.Clear(int64(state))
+	return true
+}
@@ -154,7 +154,7 @@ func (re *RegExp) toAutomaton(automata map[string]*Automaton,
 		list = make([]*Automaton, 0)
 		list = re.findLeaves(re.exp1, REGEXP_UNION, list, automata, provider)
 		list = re.findLeaves(re.exp2, REGEXP_UNION, list, automata, provider)
-		a = unionN(list)
+		a = UnionN(list)
 		a = minimize(a)
 	case REGEXP_CONCATENATION:
 		list = make([]*Automaton, 0)
@@ -22,6 +22,11 @@ func (ra *RunAutomaton) String() string {
 	panic("not implemented yet")
 }
 
+// Returns acceptance status for given state.
+func (ra *RunAutomaton) IsAccept(state int) bool {
+	return ra.accept[state]
+}
+
 // Gets character class of given codepoint
 func (ra *RunAutomaton) charClass(c int) int {
 	return findIndex(c, ra.points)
@@ -29,8 +34,8 @@ func (ra *RunAutomaton) charClass(c int) int {
 
 // Constructs a new RunAutomaton from a deterministic Automaton.
 func newRunAutomaton(a *Automaton, maxInterval int, tablesize bool) *RunAutomaton {
-	a = determinize(a)
-	size := a.numStates()
+	a = Determinize(a)
+	size := a.NumStates()
 	if size < 1 {
 		size = 1
 	}
@@ -51,7 +56,7 @@ func newRunAutomaton(a *Automaton, maxInterval int, tablesize bool) *RunAutomato
 	for n := 0; n < size; n++ {
 		ans.accept[n] = a.IsAccept(n)
 		for c, point := range ans.points {
-			dest := a.step(n, point)
+			dest := a.Step(n, point)
 			assert(dest == -1 || dest < size)
 			ans.transitions[n*nPoints+c] = dest
 		}
@@ -70,7 +75,7 @@ Automaton had no dead states, -1 is returned here if and only if a
 dead state is entered in an equivalent automaton with a total
 transition function.)
 */
-func (ra *RunAutomaton) step(state, c int) int {
+func (ra *RunAutomaton) Step(state, c int) int {
 	if ra.classmap == nil {
 		return ra.transitions[state*len(ra.points)+ra.charClass(c)]
 	} else {
@@ -88,3 +93,14 @@ func NewCharacterRunAutomaton(a *Automaton) *CharacterRunAutomaton {
 	ans.RunAutomaton = newRunAutomaton(a, unicode.MaxRune, false)
 	return ans
 }
+
+// Returns true if the given string is accepted by this automaton.
+func (ra *CharacterRunAutomaton) Run(s string) bool {
+	p := ra.initial
+	for _, c := range s {
+		if p = ra.Step(p, int(c)); p == -1 {
+			return false
+		}
+	}
+	return ra.accept[p]
+}
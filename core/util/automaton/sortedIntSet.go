@@ -15,7 +15,7 @@ const TREE_MAP_CUTOVER = 30
 
 /*
 Just holds a set of []int states, plus a corresponding []int count
-per state. Used by determinize().
+per state. Used by Determinize().
 
 I have to disable hashCode and use string key to mimic Lucene's
 custom hashing function here.
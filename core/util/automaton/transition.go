@@ -8,13 +8,13 @@ used temporarily when iterating through transitions by invoking
 {@link Automaton#initTransition} and {@link Automaton#getNextTransition}.
 */
 type Transition struct {
-	source, dest   int
-	min, max       int
+	Source, Dest   int
+	Min, Max       int
 	transitionUpto int
 }
 
 // Constructs a new singleton interval transition.
-func newTransition() *Transition {
+func NewTransition() *Transition {
 	return &Transition{
 		transitionUpto: -1,
 	}
@@ -23,10 +23,10 @@ func newTransition() *Transition {
 func (t *Transition) String() string {
 	panic("niy")
 	// var b bytes.Buffer
-	// appendCharString(t.min, &b)
-	// if t.min != t.max {
+	// appendCharString(t.Min, &b)
+	// if t.Min != t.Max {
 	// 	b.WriteString("-")
-	// 	appendCharString(t.max, &b)
+	// 	appendCharString(t.Max, &b)
 	// }
 	// fmt.Fprintf(&b, " -> %v", t.to.number)
 	// return b.String()
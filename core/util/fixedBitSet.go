@@ -68,12 +68,36 @@ func (b *FixedBitSet) Cardinality() int {
 }
 
 func (b *FixedBitSet) At(index int) bool {
-	panic("not implemented yet")
+	assert2(index >= 0 && index < b.numBits, "index=%v, numBits=%v", index, b.numBits)
+	i := index >> 6 // div 64
+	bitmask := int64(1 << uint(index&63))
+	return (b.bits[i] & bitmask) != 0
 }
 
 func (b *FixedBitSet) Set(index int) {
 	assert2(index >= 0 && index < b.numBits, "index=%v, numBits=%v", index, b.numBits)
 	wordNum := index >> 6 // div 64
-	bitmask := int64(1 << uint(index))
+	bitmask := int64(1 << uint(index&63))
 	b.bits[wordNum] |= bitmask
 }
+
+/*
+Returns the index of the first set bit starting at the index
+specified. -1 is returned if there are no more set bits.
+*/
+func (b *FixedBitSet) NextSetBit(index int) int {
+	assert2(index >= 0 && index < b.numBits, "index=%v, numBits=%v", index, b.numBits)
+	i := index >> 6
+	word := uint64(b.bits[i]) >> uint(index&63) // skip all the bits to the right of index
+
+	if word != 0 {
+		return index + int(NumberOfTrailingZeros(int64(word)))
+	}
+
+	for i++; i < b.numWords; i++ {
+		if word = uint64(b.bits[i]); word != 0 {
+			return (i << 6) + int(NumberOfTrailingZeros(int64(word)))
+		}
+	}
+	return -1
+}
@@ -47,20 +47,20 @@ func (e *FSTEnum) doNext() (err error) {
 	if e.upto == 0 {
 		// fmt.Println("  init")
 		e.upto = 1
-		if _, err = e.fst.readFirstTargetArc(e.Arc(0), e.Arc(1), e.fstReader); err != nil {
+		if _, err = e.fst.ReadFirstTargetArc(e.Arc(0), e.Arc(1), e.fstReader); err != nil {
 			return
 		}
 	} else {
 		// pop
 		// fmt.Printf("  check pop curArc target=%v label=%v isLast?=",
-		// e.arcs[e.upto].target, e.arcs[e.upto].Label, e.arcs[e.upto].isLast())
-		for e.arcs[e.upto].isLast() {
+		// e.arcs[e.upto].target, e.arcs[e.upto].Label, e.arcs[e.upto].IsLast())
+		for e.arcs[e.upto].IsLast() {
 			if e.upto--; e.upto == 0 {
 				// fmt.Println("  eof")
 				return nil
 			}
 		}
-		if _, err = e.fst.readNextArc(e.arcs[e.upto], e.fstReader); err != nil {
+		if _, err = e.fst.ReadNextArc(e.arcs[e.upto], e.fstReader); err != nil {
 			return
 		}
 	}
@@ -102,7 +102,7 @@ func (e *FSTEnum) pushFirst() (err error) {
 		e.incr()
 
 		nextArc := e.Arc(e.upto)
-		if _, err = e.fst.readFirstTargetArc(arc, nextArc, e.fstReader); err != nil {
+		if _, err = e.fst.ReadFirstTargetArc(arc, nextArc, e.fstReader); err != nil {
 			return
 		}
 		arc = nextArc
@@ -67,7 +67,7 @@ type Arc struct {
 	numArcs         int
 }
 
-func (arc *Arc) copyFrom(other *Arc) *Arc {
+func (arc *Arc) CopyFrom(other *Arc) *Arc {
 	arc.node = other.node
 	arc.Label = other.Label
 	arc.target = other.target
@@ -88,7 +88,7 @@ func (arc *Arc) flag(flag byte) bool {
 	return hasFlag(arc.flags, flag)
 }
 
-func (arc *Arc) isLast() bool {
+func (arc *Arc) IsLast() bool {
 	return arc.flag(FST_BIT_LAST_ARC)
 }
 
@@ -361,8 +361,8 @@ func (t *FST) readRootArcs(arcs []*Arc) (err error) {
 			if arc.Label >= len(t.cachedRootArcs) {
 				break
 			}
-			arcs[arc.Label] = (&Arc{}).copyFrom(arc)
-			if arc.isLast() {
+			arcs[arc.Label] = (&Arc{}).CopyFrom(arc)
+			if arc.IsLast() {
 				break
 			}
 			_, err = t.readNextRealArc(arc, in)
@@ -432,6 +432,11 @@ func equals(a, b interface{}) bool {
 			return false
 		}
 		return a.(int64) == b.(int64)
+	} else if p1, ok := a.(*Pair); ok {
+		if p2, ok := b.(*Pair); ok {
+			return equals(p1.Output1, p2.Output1) && equals(p1.Output2, p2.Output2)
+		}
+		return false
 	} else if a == nil && b == nil {
 		return true
 	} else if sameType && a == b {
@@ -444,6 +449,10 @@ func CompareFSTValue(a, b interface{}) bool {
 	return equals(a, b)
 }
 
+func (t *FST) Outputs() Outputs {
+	return t.outputs
+}
+
 func (t *FST) EmptyOutput() interface{} {
 	return t.emptyOutput
 }
@@ -549,9 +558,13 @@ func (t *FST) writeLabel(out util.DataOutput, v int) error {
 		assert2(v <= 255, "v=%v", v)
 		return out.WriteByte(byte(v))
 	} else if t.inputType == INPUT_TYPE_BYTE2 {
-		panic("not implemented yet")
+		assert2(v <= 65535, "v=%v", v)
+		if err := out.WriteByte(byte(v >> 8)); err != nil {
+			return err
+		}
+		return out.WriteByte(byte(v))
 	} else {
-		panic("not implemented yet")
+		return out.WriteVInt(int32(v))
 	}
 }
 
@@ -563,7 +576,7 @@ func (t *FST) readLabel(in util.DataInput) (v int, err error) {
 		}
 	case INPUT_TYPE_BYTE2: // Unsigned short
 		if s, err := in.ReadShort(); err == nil {
-			v = int(s)
+			v = int(uint16(s))
 		}
 	default:
 		v, err = AsInt(in.ReadVInt())
@@ -775,7 +788,11 @@ func AsInt64(n int32, err error) (n2 int64, err2 error) {
 	return int64(n), err
 }
 
-func (t *FST) readFirstTargetArc(follow, arc *Arc, in BytesReader) (*Arc, error) {
+/*
+Follow the follow arc and read the first arc of its target; this
+changes the provided arc (2nd arg) in-place and returns it.
+*/
+func (t *FST) ReadFirstTargetArc(follow, arc *Arc, in BytesReader) (*Arc, error) {
 	if follow.IsFinal() {
 		// insert "fake" final first arc:
 		arc.Label = FST_END_LABEL
@@ -829,10 +846,11 @@ func (t *FST) readFirstRealTargetArc(node int64, arc *Arc, in BytesReader) (ans
 	return t.readNextRealArc(arc, in)
 }
 
-func (t *FST) readNextArc(arc *Arc, in BytesReader) (*Arc, error) {
+/* In-place read; returns the arc. Never call this if arc.IsLast(). */
+func (t *FST) ReadNextArc(arc *Arc, in BytesReader) (*Arc, error) {
 	if arc.Label == FST_END_LABEL {
 		// this was a fake inserted "final" arc
-		assert2(arc.nextArc > 0, "cannot readNextArc when arc.isLast()=true")
+		assert2(arc.nextArc > 0, "cannot readNextArc when arc.IsLast()=true")
 		return t.readFirstRealTargetArc(arc.nextArc, arc, in)
 	} else {
 		return t.readNextRealArc(arc, in)
@@ -840,7 +858,7 @@ func (t *FST) readNextArc(arc *Arc, in BytesReader) (*Arc, error) {
 }
 
 /** Never returns null, but you should never call this if
- *  arc.isLast() is true. */
+ *  arc.IsLast() is true. */
 func (t *FST) readNextRealArc(arc *Arc, in BytesReader) (ans *Arc, err error) {
 	// TODO: can't assert this because we call from readFirstArc
 	// assert !flag(arc.flags, BIT_LAST_ARC);
@@ -959,7 +977,7 @@ func (t *FST) FindTargetArc(labelToMatch int, follow *Arc, arc *Arc, in BytesRea
 		// modified previously returned cached root-arcs:
 		t.assertRootArcs()
 		if result := t.cachedRootArcs[labelToMatch]; result != nil {
-			arc.copyFrom(result)
+			arc.CopyFrom(result)
 			return arc, nil
 		}
 		return nil, nil
@@ -997,7 +1015,7 @@ func (t *FST) FindTargetArc(labelToMatch int, follow *Arc, arc *Arc, in BytesRea
 			}
 		}
 		arc.posArcsStart = in.getPosition()
-		for low, high := 0, arc.numArcs-1; low < high; {
+		for low, high := 0, arc.numArcs-1; low <= high; {
 			// log.Println("    cycle")
 			mid := int(uint(low+high) / 2)
 			in.setPosition(arc.posArcsStart)
@@ -1037,7 +1055,7 @@ func (t *FST) FindTargetArc(labelToMatch int, follow *Arc, arc *Arc, in BytesRea
 			return arc, nil
 		} else if arc.Label > labelToMatch {
 			return nil, nil
-		} else if arc.isLast() {
+		} else if arc.IsLast() {
 			return nil, nil
 		} else {
 			if _, err = t.readNextRealArc(arc, in); err != nil {
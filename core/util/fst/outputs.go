@@ -223,6 +223,10 @@ func (out *ByteSequenceOutputs) Add(_prefix interface{}, _output interface{}) in
 
 func (o *ByteSequenceOutputs) Write(obj interface{}, out util.DataOutput) error {
 	assert(obj != nil)
+	if obj == NO_OUTPUT {
+		// only happens as one side of a pair
+		return out.WriteVInt(0)
+	}
 	prefix, ok := obj.([]byte)
 	assert(ok)
 	err := out.WriteVInt(int32(len(prefix)))
@@ -266,6 +270,201 @@ func (out *ByteSequenceOutputs) ramBytesUsed(output interface{}) int64 {
 	return BASE_NUM_BYTES + util.SizeOf(output.([]byte))
 }
 
+// fst/PositiveIntOutputs.java
+
+/*
+An FST Outputs implementation where each output is a non-negative
+int64 value. NO_OUTPUT stands for 0.
+*/
+type PositiveIntOutputs struct {
+	*abstractOutputs
+}
+
+var onePositiveIntOutputs *PositiveIntOutputs
+
+func PositiveIntOutputsSingleton() *PositiveIntOutputs {
+	if onePositiveIntOutputs == nil {
+		onePositiveIntOutputs = &PositiveIntOutputs{}
+		onePositiveIntOutputs.abstractOutputs = &abstractOutputs{onePositiveIntOutputs}
+	}
+	return onePositiveIntOutputs
+}
+
+func (out *PositiveIntOutputs) Common(output1, output2 interface{}) interface{} {
+	if output1 == NO_OUTPUT || output2 == NO_OUTPUT {
+		return NO_OUTPUT
+	}
+	n1, n2 := output1.(int64), output2.(int64)
+	assert(n1 > 0 && n2 > 0)
+	if n1 < n2 {
+		return n1
+	}
+	return n2
+}
+
+func (out *PositiveIntOutputs) Subtract(output, inc interface{}) interface{} {
+	if inc == NO_OUTPUT {
+		return output
+	}
+	n, m := output.(int64), inc.(int64)
+	assert2(n >= m, "%v vs %v", n, m)
+	if n == m {
+		return NO_OUTPUT
+	}
+	return n - m
+}
+
+func (out *PositiveIntOutputs) Add(prefix, output interface{}) interface{} {
+	if prefix == NO_OUTPUT {
+		return output
+	} else if output == NO_OUTPUT {
+		return prefix
+	}
+	return prefix.(int64) + output.(int64)
+}
+
+func (out *PositiveIntOutputs) Write(output interface{}, o util.DataOutput) error {
+	if output == NO_OUTPUT {
+		return o.WriteVLong(0)
+	}
+	n := output.(int64)
+	assert(n >= 0)
+	return o.WriteVLong(n)
+}
+
+func (out *PositiveIntOutputs) Read(in util.DataInput) (interface{}, error) {
+	n, err := in.ReadVLong()
+	if err != nil || n == 0 {
+		return NO_OUTPUT, err
+	}
+	return n, nil
+}
+
+func (out *PositiveIntOutputs) NoOutput() interface{} {
+	return NO_OUTPUT
+}
+
+func (out *PositiveIntOutputs) outputToString(output interface{}) string {
+	if output == NO_OUTPUT {
+		return "0"
+	}
+	return fmt.Sprintf("%v", output)
+}
+
+func (out *PositiveIntOutputs) String() string {
+	return "PositiveIntOutputs"
+}
+
+func (out *PositiveIntOutputs) ramBytesUsed(output interface{}) int64 {
+	return 8
+}
+
+// fst/PairOutputs.java
+
+// Holds a single pair of two outputs.
+type Pair struct {
+	Output1, Output2 interface{}
+}
+
+func (p *Pair) String() string {
+	return fmt.Sprintf("Pair(%v,%v)", p.Output1, p.Output2)
+}
+
+/*
+An FST Outputs implementation, holding two other outputs. A pair of
+which both sides are NO_OUTPUT is represented by NO_OUTPUT itself.
+*/
+type PairOutputs struct {
+	*abstractOutputs
+	outputs1, outputs2 Outputs
+}
+
+func NewPairOutputs(outputs1, outputs2 Outputs) *PairOutputs {
+	ans := &PairOutputs{outputs1: outputs1, outputs2: outputs2}
+	ans.abstractOutputs = &abstractOutputs{ans}
+	return ans
+}
+
+// Create a new Pair
+func (out *PairOutputs) NewPair(a, b interface{}) interface{} {
+	if a == NO_OUTPUT && b == NO_OUTPUT {
+		return NO_OUTPUT
+	}
+	return &Pair{a, b}
+}
+
+// Returns both sides of the output, which may be NO_OUTPUT.
+func (out *PairOutputs) Split(output interface{}) (interface{}, interface{}) {
+	if output == NO_OUTPUT {
+		return NO_OUTPUT, NO_OUTPUT
+	}
+	p := output.(*Pair)
+	return p.Output1, p.Output2
+}
+
+func (out *PairOutputs) Common(output1, output2 interface{}) interface{} {
+	a1, b1 := out.Split(output1)
+	a2, b2 := out.Split(output2)
+	return out.NewPair(out.outputs1.Common(a1, a2), out.outputs2.Common(b1, b2))
+}
+
+func (out *PairOutputs) Subtract(output, inc interface{}) interface{} {
+	a1, b1 := out.Split(output)
+	a2, b2 := out.Split(inc)
+	return out.NewPair(out.outputs1.Subtract(a1, a2), out.outputs2.Subtract(b1, b2))
+}
+
+func (out *PairOutputs) Add(prefix, output interface{}) interface{} {
+	a1, b1 := out.Split(prefix)
+	a2, b2 := out.Split(output)
+	return out.NewPair(out.outputs1.Add(a1, a2), out.outputs2.Add(b1, b2))
+}
+
+func (out *PairOutputs) Write(output interface{}, o util.DataOutput) error {
+	a, b := out.Split(output)
+	if err := out.outputs1.Write(a, o); err != nil {
+		return err
+	}
+	return out.outputs2.Write(b, o)
+}
+
+func (out *PairOutputs) Read(in util.DataInput) (interface{}, error) {
+	a, err := out.outputs1.Read(in)
+	if err != nil {
+		return nil, err
+	}
+	b, err := out.outputs2.Read(in)
+	if err != nil {
+		return nil, err
+	}
+	return out.NewPair(a, b), nil
+}
+
+func (out *PairOutputs) NoOutput() interface{} {
+	return NO_OUTPUT
+}
+
+func (out *PairOutputs) outputToString(output interface{}) string {
+	a, b := out.Split(output)
+	return fmt.Sprintf("<pair:%v,%v>", out.outputs1.outputToString(a), out.outputs2.outputToString(b))
+}
+
+func (out *PairOutputs) String() string {
+	return fmt.Sprintf("PairOutputs<%v,%v>", out.outputs1, out.outputs2)
+}
+
+func (out *PairOutputs) ramBytesUsed(output interface{}) int64 {
+	a, b := out.Split(output)
+	var n int64 = 16
+	if a != NO_OUTPUT {
+		n += out.outputs1.ramBytesUsed(a)
+	}
+	if b != NO_OUTPUT {
+		n += out.outputs2.ramBytesUsed(b)
+	}
+	return n
+}
+
 // util/fst/Util.java
 
 /** Looks up the output for this input, or null if the
@@ -2,6 +2,7 @@ package fst
 
 import (
 	"github.com/balzaczyy/golucene/core/util"
+	"sort"
 )
 
 // fst/Util.java
@@ -14,3 +15,308 @@ func ToIntsRef(input []byte, scratch *util.IntsRefBuilder) *util.IntsRef {
 	}
 	return scratch.Get()
 }
+
+/*
+Reads the first arc greater or equal that the given label into the
+provided arc in place and returns it iff found, otherwise return nil.
+*/
+func ReadCeilArc(label int, fst *FST, follow, arc *Arc, in BytesReader) (*Arc, error) {
+	if label == FST_END_LABEL {
+		if follow.IsFinal() {
+			if follow.target <= 0 {
+				arc.flags = FST_BIT_LAST_ARC
+			} else {
+				arc.flags = 0
+				// NOTE: nextArc is a node (not an address!) in this case:
+				arc.nextArc = follow.target
+				arc.node = follow.target
+			}
+			arc.Output = follow.NextFinalOutput
+			arc.Label = FST_END_LABEL
+			return arc, nil
+		}
+		return nil, nil
+	}
+
+	if !targetHasArcs(follow) {
+		return nil, nil
+	}
+	if _, err := fst.readFirstRealTargetArc(follow.target, arc, in); err != nil {
+		return nil, err
+	}
+	for {
+		if arc.Label >= label {
+			return arc, nil
+		} else if arc.IsLast() {
+			return nil, nil
+		}
+		if _, err := fst.readNextRealArc(arc, in); err != nil {
+			return nil, err
+		}
+	}
+}
+
+/* Represents a path in TopNSearcher. */
+type FSTPath struct {
+	Arc   *Arc
+	Cost  interface{}
+	Input []int
+}
+
+func newFSTPath(cost interface{}, arc *Arc, input []int) *FSTPath {
+	return &FSTPath{(&Arc{}).CopyFrom(arc), cost, input}
+}
+
+func compareInts(a, b []int) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return a[i] - b[i]
+		}
+	}
+	return len(a) - len(b)
+}
+
+/*
+Utility class to find top N shortest paths from start point(s).
+
+The comparator orders outputs (costs); ties are broken by the input.
+*/
+type TopNSearcher struct {
+	fst           *FST
+	bytesReader   BytesReader
+	topN          int
+	maxQueueDepth int
+	comparator    func(a, b interface{}) int
+	queue         []*FSTPath // sorted; nil once no longer needed
+
+	/*
+		Invoked for every complete path found; return false to reject
+		the result. Accepts all results by default.
+	*/
+	AcceptResult func(input []int, output interface{}) bool
+}
+
+/*
+Creates an unbounded TopNSearcher. maxQueueDepth is the maximum size
+of the queue of possible top entries; topN is the number of results
+to return.
+*/
+func NewTopNSearcher(fst *FST, topN, maxQueueDepth int,
+	comparator func(a, b interface{}) int) *TopNSearcher {
+
+	return &TopNSearcher{
+		fst:           fst,
+		bytesReader:   fst.BytesReader(),
+		topN:          topN,
+		maxQueueDepth: maxQueueDepth,
+		comparator:    comparator,
+		queue:         make([]*FSTPath, 0, maxQueueDepth+1),
+		AcceptResult: func(input []int, output interface{}) bool {
+			return true
+		},
+	}
+}
+
+func (s *TopNSearcher) compare(a, b *FSTPath) int {
+	if cmp := s.comparator(a.Cost, b.Cost); cmp != 0 {
+		return cmp
+	}
+	return compareInts(a.Input, b.Input)
+}
+
+/* If back plus this arc is competitive then add to queue: */
+func (s *TopNSearcher) addIfCompetitive(path *FSTPath) {
+	assert(s.queue != nil)
+
+	cost := s.fst.outputs.Add(path.Cost, path.Arc.Output)
+	if len(s.queue) == s.maxQueueDepth {
+		bottom := s.queue[len(s.queue)-1]
+		if comp := s.comparator(cost, bottom.Cost); comp > 0 {
+			// doesn't compete
+			return
+		} else if comp == 0 {
+			// tie break by alpha sort on the input:
+			input := append(append([]int{}, path.Input...), path.Arc.Label)
+			if compareInts(bottom.Input, input) < 0 {
+				// doesn't compete
+				return
+			}
+		}
+	}
+
+	// copy over the current input to the new input and add the
+	// arc.Label to the end
+	newInput := make([]int, len(path.Input), len(path.Input)+1)
+	copy(newInput, path.Input)
+	newPath := newFSTPath(cost, path.Arc, append(newInput, path.Arc.Label))
+
+	i := sort.Search(len(s.queue), func(i int) bool {
+		return s.compare(s.queue[i], newPath) > 0
+	})
+	s.queue = append(s.queue, nil)
+	copy(s.queue[i+1:], s.queue[i:])
+	s.queue[i] = newPath
+	if len(s.queue) == s.maxQueueDepth+1 {
+		s.queue = s.queue[:s.maxQueueDepth]
+	}
+}
+
+/*
+Adds all leaving arcs, including 'finished' arc, if the node is
+final, from this node into the queue.
+*/
+func (s *TopNSearcher) AddStartPaths(node *Arc, startOutput interface{},
+	allowEmptyString bool, input []int) error {
+
+	// de-dup NO_OUTPUT since it must be a singleton:
+	if equals(startOutput, s.fst.outputs.NoOutput()) {
+		startOutput = s.fst.outputs.NoOutput()
+	}
+
+	path := newFSTPath(startOutput, node, input)
+	if _, err := s.fst.ReadFirstTargetArc(node, path.Arc, s.bytesReader); err != nil {
+		return err
+	}
+
+	// bootstrap: find the min starting arc
+	for {
+		if allowEmptyString || path.Arc.Label != FST_END_LABEL {
+			s.addIfCompetitive(path)
+		}
+		if path.Arc.IsLast() {
+			break
+		}
+		if _, err := s.fst.ReadNextArc(path.Arc, s.bytesReader); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *TopNSearcher) Search() (*TopResults, error) {
+	var results []*Result
+	fstReader := s.fst.BytesReader()
+	NO_OUTPUT := s.fst.outputs.NoOutput()
+
+	// TODO: we could enable FST to sorting arcs by weight as it
+	// freezes... can easily do this on first pass (w/o requiring
+	// rewrite) then we can break if we find the first arc that
+	// is not competitive.
+	rejectCount := 0
+	for len(results) < s.topN {
+		if len(s.queue) == 0 {
+			// ran out of paths
+			break
+		}
+		// remove top path since we are now going to pursue it:
+		path := s.queue[0]
+		s.queue = s.queue[1:]
+
+		if path.Arc.Label == FST_END_LABEL {
+			// empty string!
+			path.Input = path.Input[:len(path.Input)-1]
+			results = append(results, &Result{path.Input, path.Cost})
+			continue
+		}
+
+		if len(results) == s.topN-1 && s.maxQueueDepth == s.topN {
+			// last path -- don't bother w/ queue anymore:
+			s.queue = nil
+		}
+
+		// We take path and find its "0 output completion", ie, just
+		// keep traversing the first arc with NO_OUTPUT that we can
+		// find, since this must lead to the minimum path that
+		// completes from path.Arc.
+
+		// For each input letter:
+		for {
+			// For each arc leaving this node:
+			if _, err := s.fst.ReadFirstTargetArc(path.Arc, path.Arc, fstReader); err != nil {
+				return nil, err
+			}
+			foundZero := false
+			var scratchArc Arc
+			for {
+				// tricky: instead of comparing output == 0, we must
+				// express it via the comparator compare(output, 0) == 0
+				if s.comparator(NO_OUTPUT, path.Arc.Output) == 0 {
+					if s.queue == nil {
+						foundZero = true
+						break
+					} else if !foundZero {
+						scratchArc.CopyFrom(path.Arc)
+						foundZero = true
+					} else {
+						s.addIfCompetitive(path)
+					}
+				} else if s.queue != nil {
+					s.addIfCompetitive(path)
+				}
+				if path.Arc.IsLast() {
+					break
+				}
+				if _, err := s.fst.ReadNextArc(path.Arc, fstReader); err != nil {
+					return nil, err
+				}
+			}
+
+			assert(foundZero)
+
+			if s.queue != nil {
+				path.Arc.CopyFrom(&scratchArc)
+			}
+
+			path.Cost = s.fst.outputs.Add(path.Cost, path.Arc.Output)
+			if path.Arc.Label == FST_END_LABEL {
+				// add final output:
+				if s.AcceptResult(path.Input, path.Cost) {
+					results = append(results, &Result{path.Input, path.Cost})
+				} else {
+					rejectCount++
+				}
+				break
+			}
+			path.Input = append(path.Input, path.Arc.Label)
+		}
+	}
+	return &TopResults{rejectCount+s.topN <= s.maxQueueDepth, results}, nil
+}
+
+/* Holds a single input (IntsRef) + output, returned by shortestPaths() */
+type Result struct {
+	Input  []int
+	Output interface{}
+}
+
+/* Holds the results for a top N search using TopNSearcher */
+type TopResults struct {
+	/*
+		true iff this is a complete result ie. if the specified queue
+		size was large enough to find the complete list of results.
+		This might be false if the TopNSearcher rejected too many
+		results.
+	*/
+	IsComplete bool
+	// The top results
+	TopN []*Result
+}
+
+/*
+Starting from node, find the top N min cost completions to a final
+node.
+*/
+func ShortestPaths(fst *FST, fromNode *Arc, startOutput interface{},
+	comparator func(a, b interface{}) int, topN int, allowEmptyString bool) (*TopResults, error) {
+
+	// All paths are kept, so we can pass topN for maxQueueDepth and
+	// the pruning is admissible:
+	searcher := NewTopNSearcher(fst, topN, topN, comparator)
+
+	// since this search is initialized with a single start node it
+	// is okay to start with an empty input path here
+	if err := searcher.AddStartPaths(fromNode, startOutput, allowEmptyString, nil); err != nil {
+		return nil, err
+	}
+	return searcher.Search()
+}
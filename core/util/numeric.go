@@ -1,5 +1,9 @@
 package util
 
+import (
+	"math"
+)
+
 // util/NumericUtils.java
 
 const (
@@ -7,3 +11,28 @@ const (
 	// NumericTokenStream, NumericRangeQuery, and NumericRangeFilter.
 	NUMERIC_PRECISION_STEP_DEFAULT = 16
 )
+
+/*
+Converts a float64 value to a sortable signed int64. The value is
+converted by getting their IEEE 754 floating-point "double format"
+bit layout and then some bits are swapped, to be able to compare the
+result as int64. By this the precision is not reduced, but the value
+can easily used as an int64. The sort order (including NaN) is
+defined by Java's Double.compareTo(); NaN is greater than positive
+infinity.
+*/
+func DoubleToSortableLong(val float64) int64 {
+	f := int64(math.Float64bits(val))
+	if f < 0 {
+		f ^= 0x7fffffffffffffff
+	}
+	return f
+}
+
+// Converts a sortable int64 back to a float64.
+func SortableLongToDouble(val int64) float64 {
+	if val < 0 {
+		val ^= 0x7fffffffffffffff
+	}
+	return math.Float64frombits(uint64(val))
+}
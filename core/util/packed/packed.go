@@ -229,6 +229,9 @@ type PackedIntsDecoder interface {
 	// Read 8 * iterations * blockCount() blocks from blocks, decodethem and write
 	// iterations * valueCount() values inot values.
 	decodeByteToLong(blocks []byte, values []int64, iterations int)
+	// Read iterations * blockCount() blocks from blocks, decode them and
+	// write iterations * valueCount() values into values.
+	DecodeByteToInt(blocks []byte, values []int, iterations int)
 }
 
 func GetPackedIntsEncoder(format PackedFormat, version int32, bitsPerValue uint32) PackedIntsEncoder {
@@ -89,7 +89,15 @@ func (p *Packed64) getBulk(index int, arr []int64) int {
 	// go to the next block where the value does not span across two blocks
 	offsetInBlocks := index % decoder.LongValueCount()
 	if offsetInBlocks != 0 {
-		panic("niy")
+		for i := offsetInBlocks; i < decoder.LongValueCount() && length > 0; i++ {
+			arr[off] = p.Get(index)
+			off++
+			index++
+			length--
+		}
+		if length == 0 {
+			return index - originalIndex
+		}
 	}
 
 	// bulk get
@@ -150,7 +158,15 @@ func (p *Packed64) setBulk(index int, arr []int64) int {
 	// go to the next block where the value does not span across two blocks
 	offsetInBlocks := index % encoder.LongValueCount()
 	if offsetInBlocks != 0 {
-		panic("niy")
+		for i := offsetInBlocks; i < encoder.LongValueCount() && length > 0; i++ {
+			p.Set(index, arr[off])
+			off++
+			index++
+			length--
+		}
+		if length == 0 {
+			return index - originalIndex
+		}
 	}
 
 	// bulk set
@@ -59,3 +59,30 @@ func TestUnsignedBitsRequired(t *testing.T) {
 		t.Errorf("-158146830731166066 -> 64bit (got %v)", n)
 	}
 }
+
+func TestDecodeByteToInt(t *testing.T) {
+	for _, format := range []PackedFormat{PACKED, PACKED_SINGLE_BLOCK} {
+		for bpv := 1; bpv <= 32; bpv++ {
+			if !format.IsSupported(bpv) {
+				continue
+			}
+			op := newBulkOperation(format, uint32(bpv))
+			iterations := (128 + op.ByteValueCount() - 1) / op.ByteValueCount()
+			values := make([]int, iterations*op.ByteValueCount())
+			for i := range values {
+				values[i] = int((int64(i)*2654435761 + 7) & ((int64(1) << uint(bpv)) - 1))
+			}
+			blocks := make([]byte, iterations*op.ByteBlockCount())
+			op.EncodeIntToByte(values, blocks, iterations)
+			decoded := make([]int, len(values))
+			op.DecodeByteToInt(blocks, decoded, iterations)
+			for i, v := range values {
+				if decoded[i] != v {
+					t.Errorf("format=%v bpv=%v: expected values[%v]=%v, but was %v",
+						format, bpv, i, v, decoded[i])
+					break
+				}
+			}
+		}
+	}
+}
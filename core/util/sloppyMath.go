@@ -0,0 +1,23 @@
+package util
+
+import (
+	"math"
+)
+
+// util/SloppyMath.java
+
+// mean earth radius, in meters, as defined by the WGS84 ellipsoid
+const EARTH_MEAN_RADIUS_METERS = 6371008.7714
+
+/*
+Returns the haversine distance in meters between two points specified
+in decimal degrees (latitude/longitude). Unlike the original, this is
+computed with the exact trigonometric functions of package math.
+*/
+func HaversinMeters(lat1, lon1, lat2, lon2 float64) float64 {
+	x1, x2 := lat1*math.Pi/180, lat2*math.Pi/180
+	h1 := math.Sin((x2 - x1) / 2)
+	h2 := math.Sin((lon2 - lon1) * math.Pi / 360)
+	h := h1*h1 + math.Cos(x1)*math.Cos(x2)*h2*h2
+	return 2 * EARTH_MEAN_RADIUS_METERS * math.Asin(math.Min(1, math.Sqrt(h)))
+}
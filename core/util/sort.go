@@ -317,16 +317,67 @@ func assert(ok bool) {
 	}
 }
 
+func (sorter *TimSorter) runLen(i int) int {
+	off := sorter.stackSize - i
+	return sorter.runEnds[off] - sorter.runEnds[off-1]
+}
+
+func (sorter *TimSorter) runBase(i int) int {
+	return sorter.runEnds[sorter.stackSize-i-1]
+}
+
+func (sorter *TimSorter) setRunEnd(i, runEnd int) {
+	sorter.runEnds[sorter.stackSize-i] = runEnd
+}
+
 func (sorter *TimSorter) ensureInvariants() {
 	for sorter.stackSize > 1 {
-		panic("not implemented yet")
+		runLen0 := sorter.runLen(0)
+		runLen1 := sorter.runLen(1)
+		if sorter.stackSize > 2 {
+			if runLen2 := sorter.runLen(2); runLen2 <= runLen1+runLen0 {
+				// merge the smaller of 0 and 2 with 1
+				if runLen2 < runLen0 {
+					sorter.mergeAt(1)
+				} else {
+					sorter.mergeAt(0)
+				}
+				continue
+			}
+		}
+		if runLen1 <= runLen0 {
+			sorter.mergeAt(0)
+			continue
+		}
+		break
 	}
 }
 
 func (sorter *TimSorter) exhaustStack() {
 	for sorter.stackSize > 1 {
-		panic("not implemented yet")
+		sorter.mergeAt(0)
+	}
+}
+
+// Merge run i with run i+1
+func (sorter *TimSorter) mergeAt(n int) {
+	assert(sorter.stackSize >= 2)
+	sorter.merge(sorter.runBase(n+1), sorter.runBase(n), sorter.runEnd(n))
+	for j := n + 1; j > 0; j-- {
+		sorter.setRunEnd(j, sorter.runEnd(j-1))
+	}
+	sorter.stackSize--
+}
+
+/*
+Merge runs [lo,mid) and [mid,hi). As sort.Interface cannot save
+slots, merges are always performed in-place.
+*/
+func (sorter *TimSorter) merge(lo, mid, hi int) {
+	if !sorter.Less(mid, mid-1) {
+		return
 	}
+	sorter.mergeInPlace(lo, mid, hi)
 }
 
 func (sorter *TimSorter) reset(from, to int) {
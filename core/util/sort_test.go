@@ -20,3 +20,17 @@ func TestInPlaceMergeSorter(t *testing.T) {
 		assert(data[i] == 25-i)
 	}
 }
+
+func TestTimSort(t *testing.T) {
+	for _, n := range []int{10, 65, 1003, 5000} {
+		data := make([]int, n)
+		for i := range data {
+			// a few runs, with duplicates
+			data[i] = (i * 7919) % (n/3 + 1)
+		}
+		TimSort(sort.IntSlice(data))
+		if !sort.IntsAreSorted(data) {
+			t.Errorf("Expected sorted data for n=%v, but was %v", n, data)
+		}
+	}
+}
@@ -0,0 +1,324 @@
+package facet
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/balzaczyy/golucene/core/index"
+	"github.com/balzaczyy/golucene/core/search"
+	"github.com/balzaczyy/golucene/core/util"
+)
+
+// facet/DrillDownQuery.java
+
+/*
+A Query for drill-down over facet categories. You should use Add()
+to add drill-down categories, one or more values per dimension.
+Multiple values of the same dimension are OR'd, while different
+dimensions are AND'd.
+
+The drill-down constraints never contribute to the score; matching
+documents are scored by the base query alone.
+*/
+type DrillDownQuery struct {
+	*search.AbstractQuery
+	config        *FacetsConfig
+	baseQuery     search.Query
+	dims          []string
+	dimQueries    []search.Query
+	drillDownDims map[string]int
+}
+
+/*
+Creates a DrillDownQuery over the given base query. Can be nil, in
+which case the result Query will be pure browsing, matching all
+documents.
+*/
+func NewDrillDownQuery(config *FacetsConfig, baseQuery search.Query) *DrillDownQuery {
+	ans := &DrillDownQuery{
+		config:        config,
+		baseQuery:     baseQuery,
+		drillDownDims: make(map[string]int),
+	}
+	ans.AbstractQuery = search.NewAbstractQuery(ans)
+	return ans
+}
+
+// Creates a drill-down term.
+func NewDrillDownTerm(field, dim string, path ...string) *index.Term {
+	return index.NewTerm(field, PathToString(dim, path...))
+}
+
+/*
+Adds one dimension of drill downs; if you pass the same dimension
+more than once it is OR'd with the previous constraints on that
+dimension, and all dimensions are AND'd against each other and the
+base query.
+*/
+func (q *DrillDownQuery) Add(dim string, path ...string) {
+	indexedField := q.config.DimConfig(dim).IndexFieldName
+	tq := search.NewTermQuery(NewDrillDownTerm(indexedField, dim, path...))
+	if i, ok := q.drillDownDims[dim]; ok {
+		bq, ok := q.dimQueries[i].(*search.BooleanQuery)
+		if !ok {
+			bq = search.NewBooleanQueryDisableCoord(true)
+			bq.Add(q.dimQueries[i], search.SHOULD)
+			q.dimQueries[i] = bq
+		}
+		bq.Add(tq, search.SHOULD)
+		return
+	}
+	q.addQuery(dim, tq)
+}
+
+/*
+Expert: add a custom drill-down subQuery. Use this when you have a
+separate way to drill-down on the dimension than the indexed facet
+labels.
+*/
+func (q *DrillDownQuery) AddQuery(dim string, subQuery search.Query) {
+	if _, ok := q.drillDownDims[dim]; ok {
+		panic(fmt.Sprintf("dimension \"%v\" already has a drill-down", dim))
+	}
+	q.addQuery(dim, subQuery)
+}
+
+func (q *DrillDownQuery) addQuery(dim string, subQuery search.Query) {
+	q.drillDownDims[dim] = len(q.dims)
+	q.dims = append(q.dims, dim)
+	q.dimQueries = append(q.dimQueries, subQuery)
+}
+
+// Returns the base query, or nil if this query is pure browsing.
+func (q *DrillDownQuery) BaseQuery() search.Query {
+	return q.baseQuery
+}
+
+// Returns the drill-down dimensions, in the order they were added.
+func (q *DrillDownQuery) Dims() []string {
+	return q.dims
+}
+
+func (q *DrillDownQuery) ExtractTerms(terms *index.TermSet) {
+	if q.baseQuery != nil {
+		q.baseQuery.ExtractTerms(terms)
+	}
+	for _, dq := range q.dimQueries {
+		dq.ExtractTerms(terms)
+	}
+}
+
+func (q *DrillDownQuery) Visit(visitor search.QueryVisitor) {
+	sub := visitor.SubVisitor(search.MUST, q)
+	if sub == nil {
+		return
+	}
+	if q.baseQuery != nil {
+		q.baseQuery.Visit(sub)
+	} else {
+		search.NewMatchAllDocsQuery().Visit(sub)
+	}
+	for _, dq := range q.dimQueries {
+		dq.Visit(sub)
+	}
+}
+
+func (q *DrillDownQuery) ToString(field string) string {
+	var buf bytes.Buffer
+	if q.baseQuery != nil {
+		buf.WriteString("+")
+		buf.WriteString(q.baseQuery.ToString(field))
+	} else {
+		buf.WriteString("+*:*")
+	}
+	for _, dq := range q.dimQueries {
+		buf.WriteString(" +")
+		if _, ok := dq.(*search.BooleanQuery); ok {
+			fmt.Fprintf(&buf, "(%v)", dq.ToString(field))
+		} else {
+			buf.WriteString(dq.ToString(field))
+		}
+	}
+	if q.Boost() != 1 {
+		fmt.Fprintf(&buf, "^%v", q.Boost())
+	}
+	return buf.String()
+}
+
+func (q *DrillDownQuery) CreateWeight(ss *search.IndexSearcher) (search.Weight, error) {
+	baseWeight, err := q.createBaseWeight(ss)
+	if err != nil {
+		return nil, err
+	}
+	dimWeights, err := q.createDimWeights(ss)
+	if err != nil {
+		return nil, err
+	}
+	return &drillDownWeight{q, baseWeight, dimWeights}, nil
+}
+
+func (q *DrillDownQuery) createBaseWeight(ss *search.IndexSearcher) (search.Weight, error) {
+	base := q.baseQuery
+	if base == nil {
+		base = search.NewMatchAllDocsQuery()
+	}
+	base, err := ss.Rewrite(base)
+	if err != nil {
+		return nil, err
+	}
+	return base.CreateWeight(ss)
+}
+
+/*
+Drill-down constraints are only used to filter, so each of them is
+weighted on its own and never takes part in the normalization of the
+base query.
+*/
+func (q *DrillDownQuery) createDimWeights(ss *search.IndexSearcher) ([]search.Weight, error) {
+	ans := make([]search.Weight, len(q.dimQueries))
+	for i, dq := range q.dimQueries {
+		w, err := ss.CreateNormalizedWeight(dq)
+		if err != nil {
+			return nil, err
+		}
+		ans[i] = w
+	}
+	return ans, nil
+}
+
+type drillDownWeight struct {
+	owner      *DrillDownQuery
+	baseWeight search.Weight
+	dimWeights []search.Weight
+}
+
+func (w *drillDownWeight) ValueForNormalization() float32 {
+	boost := w.owner.Boost()
+	return w.baseWeight.ValueForNormalization() * boost * boost
+}
+
+func (w *drillDownWeight) Normalize(norm, topLevelBoost float32) {
+	w.baseWeight.Normalize(norm, topLevelBoost*w.owner.Boost())
+}
+
+func (w *drillDownWeight) IsScoresDocsOutOfOrder() bool {
+	return w.baseWeight.IsScoresDocsOutOfOrder()
+}
+
+func (w *drillDownWeight) Explain(ctx *index.AtomicReaderContext, doc int) (search.Explanation, error) {
+	acceptDocs := ctx.Reader().(index.AtomicReader).LiveDocs()
+	dimBits, err := dimDocIdSets(w.dimWeights, ctx, acceptDocs)
+	if err != nil {
+		return nil, err
+	}
+	for i, bits := range dimBits {
+		if bits == nil || !bits.At(doc) {
+			return search.NewComplexExplanation(false, 0,
+				fmt.Sprintf("no match on required drill-down dimension %v", w.owner.dims[i])), nil
+		}
+	}
+	return w.baseWeight.Explain(ctx, doc)
+}
+
+func (w *drillDownWeight) BulkScorer(ctx *index.AtomicReaderContext,
+	scoreDocsInOrder bool, acceptDocs util.Bits) (search.BulkScorer, error) {
+
+	dimBits, err := dimDocIdSets(w.dimWeights, ctx, acceptDocs)
+	if err != nil {
+		return nil, err
+	}
+	for _, bits := range dimBits {
+		if bits == nil {
+			// one of the dimensions has no match in this segment
+			return nil, nil
+		}
+	}
+	bs, err := w.baseWeight.BulkScorer(ctx, scoreDocsInOrder, acceptDocs)
+	if err != nil || bs == nil {
+		return nil, err
+	}
+	return &drillDownBulkScorer{bs, dimBits}, nil
+}
+
+/*
+Scores the base query, only passing the documents which match every
+drill-down dimension through to the collector.
+*/
+type drillDownBulkScorer struct {
+	base    search.BulkScorer
+	dimBits []*util.FixedBitSet
+}
+
+func (s *drillDownBulkScorer) ScoreAndCollect(c search.Collector) error {
+	return s.base.ScoreAndCollect(s.wrap(c))
+}
+
+func (s *drillDownBulkScorer) ScoreAndCollectUpto(c search.Collector, max int) (bool, error) {
+	return s.base.ScoreAndCollectUpto(s.wrap(c), max)
+}
+
+func (s *drillDownBulkScorer) wrap(c search.Collector) search.Collector {
+	return &filteringCollector{c, func(doc int) bool {
+		for _, bits := range s.dimBits {
+			if !bits.At(doc) {
+				return false
+			}
+		}
+		return true
+	}}
+}
+
+/* Forwards only the documents accepted by the filter to the delegate. */
+type filteringCollector struct {
+	search.Collector
+	accept func(doc int) bool
+}
+
+func (c *filteringCollector) Collect(doc int) error {
+	if c.accept(doc) {
+		return c.Collector.Collect(doc)
+	}
+	return nil
+}
+
+/*
+Computes, for each dimension, the set of documents in the segment
+that match its drill-down constraint, or nil if no documents match.
+*/
+func dimDocIdSets(dimWeights []search.Weight, ctx *index.AtomicReaderContext,
+	acceptDocs util.Bits) ([]*util.FixedBitSet, error) {
+
+	ans := make([]*util.FixedBitSet, len(dimWeights))
+	for i, w := range dimWeights {
+		bs, err := w.BulkScorer(ctx, false, acceptDocs)
+		if err != nil {
+			return nil, err
+		}
+		if bs == nil {
+			continue
+		}
+		c := &bitSetCollector{bits: util.NewFixedBitSetOf(ctx.Reader().MaxDoc())}
+		if err = bs.ScoreAndCollect(c); err != nil {
+			return nil, err
+		}
+		if c.count > 0 {
+			ans[i] = c.bits
+		}
+	}
+	return ans, nil
+}
+
+/* Marks every collected document in a bit set, ignoring scores. */
+type bitSetCollector struct {
+	bits  *util.FixedBitSet
+	count int
+}
+
+func (c *bitSetCollector) SetScorer(s search.Scorer)                {}
+func (c *bitSetCollector) SetNextReader(*index.AtomicReaderContext) {}
+func (c *bitSetCollector) AcceptsDocsOutOfOrder() bool              { return true }
+
+func (c *bitSetCollector) Collect(doc int) error {
+	c.bits.Set(doc)
+	c.count++
+	return nil
+}
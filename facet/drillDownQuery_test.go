@@ -0,0 +1,176 @@
+package facet
+
+import (
+	"fmt"
+	std "github.com/balzaczyy/golucene/analysis/standard"
+	_ "github.com/balzaczyy/golucene/core/codec/lucene410"
+	docu "github.com/balzaczyy/golucene/core/document"
+	"github.com/balzaczyy/golucene/core/index"
+	"github.com/balzaczyy/golucene/core/search"
+	"github.com/balzaczyy/golucene/core/store"
+	"github.com/balzaczyy/golucene/core/util"
+	"sort"
+	"strings"
+	"testing"
+)
+
+/*
+Indexes six books over two segments; the first five contain "foo":
+
+	0: Bob, 2010     3: Susan, 2012
+	1: Lisa, 2010    4: Frank, 1999
+	2: Lisa, 2012    5: Bob, 2012 (contains "bar")
+*/
+func newBooksSearcher(t *testing.T) (*search.IndexSearcher, func()) {
+	dir, err := store.OpenFSDirectory(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := index.NewIndexWriter(dir, index.NewIndexWriterConfig(util.VERSION_LATEST, std.NewStandardAnalyzer()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, book := range [][3]string{
+		{"foo", "Bob", "2010"},
+		{"foo", "Lisa", "2010"},
+		{"foo", "Lisa", "2012"},
+		{"foo", "Susan", "2012"},
+		{"foo", "Frank", "1999"},
+		{"bar", "Bob", "2012"},
+	} {
+		doc := docu.NewDocument()
+		doc.Add(docu.NewTextFieldFromString("content", book[0], docu.STORE_NO))
+		doc.Add(docu.NewStringField(DEFAULT_INDEX_FIELD_NAME, PathToString("Author", book[1]), docu.STORE_NO))
+		doc.Add(docu.NewStringField(DEFAULT_INDEX_FIELD_NAME, PathToString("Publish Year", book[2]), docu.STORE_NO))
+		if err = w.AddDocument(doc.Fields()); err != nil {
+			t.Fatal(err)
+		}
+		if i == 2 {
+			if err = w.Commit(); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	r, err := index.OpenDirectoryReader(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Leaves()) != 2 {
+		t.Fatalf("Expected 2 segments, but was %v", len(r.Leaves()))
+	}
+	return search.NewIndexSearcher(r), func() { r.Close() }
+}
+
+func hitDocs(t *testing.T, ss *search.IndexSearcher, q search.Query) map[int]float32 {
+	docs, err := ss.SearchTop(q, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ans := make(map[int]float32)
+	for _, hit := range docs.ScoreDocs {
+		ans[hit.Doc] = hit.Score
+	}
+	if len(ans) != docs.TotalHits {
+		t.Fatalf("Expected %v hits, but was %v", docs.TotalHits, len(ans))
+	}
+	return ans
+}
+
+func sortedDocs(hits map[int]float32) string {
+	docs := make([]int, 0, len(hits))
+	for doc := range hits {
+		docs = append(docs, doc)
+	}
+	sort.Ints(docs)
+	return fmt.Sprint(docs)
+}
+
+func TestDrillDownQuery(t *testing.T) {
+	ss, closer := newBooksSearcher(t)
+	defer closer()
+	config := NewFacetsConfig()
+
+	// pure browsing: drill down on a single value
+	q := NewDrillDownQuery(config, nil)
+	q.Add("Author", "Lisa")
+	if docs := sortedDocs(hitDocs(t, ss, q)); docs != "[1 2]" {
+		t.Errorf("Expected [1 2], but was %v", docs)
+	}
+
+	// values of the same dimension are OR'd
+	q.Add("Author", "Bob")
+	if docs := sortedDocs(hitDocs(t, ss, q)); docs != "[0 1 2 5]" {
+		t.Errorf("Expected [0 1 2 5], but was %v", docs)
+	}
+
+	// dimensions are AND'd
+	q.Add("Publish Year", "2010")
+	if docs := sortedDocs(hitDocs(t, ss, q)); docs != "[0 1]" {
+		t.Errorf("Expected [0 1], but was %v", docs)
+	}
+	if dims := fmt.Sprint(q.Dims()); dims != "[Author Publish Year]" {
+		t.Errorf("Expected dims [Author Publish Year], but was %v", dims)
+	}
+
+	// and AND'd with the base query, which alone scores the hits
+	base := search.NewTermQuery(index.NewTerm("content", "foo"))
+	baseHits := hitDocs(t, ss, base)
+	q = NewDrillDownQuery(config, base)
+	q.Add("Author", "Bob")
+	q.Add("Author", "Susan")
+	q.Add("Publish Year", "2012")
+	hits := hitDocs(t, ss, q)
+	if docs := sortedDocs(hits); docs != "[3]" {
+		t.Errorf("Expected [3], but was %v", docs)
+	}
+	for doc, score := range hits {
+		if score != baseHits[doc] {
+			t.Errorf("Expected doc %v to score %v, but was %v", doc, baseHits[doc], score)
+		}
+	}
+
+	// no value matches a dimension
+	q = NewDrillDownQuery(config, base)
+	q.Add("Author", "Lisa")
+	q.Add("Publish Year", "1999")
+	if docs := sortedDocs(hitDocs(t, ss, q)); docs != "[]" {
+		t.Errorf("Expected no hits, but was %v", docs)
+	}
+}
+
+func TestDrillDownQueryExplain(t *testing.T) {
+	ss, closer := newBooksSearcher(t)
+	defer closer()
+
+	base := search.NewTermQuery(index.NewTerm("content", "foo"))
+	q := NewDrillDownQuery(NewFacetsConfig(), base)
+	q.Add("Author", "Lisa")
+	q.Add("Publish Year", "2010")
+
+	// doc 2 is by Lisa, but from 2012
+	exp, err := ss.Explain(q, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp.IsMatch() || exp.Value() != 0 {
+		t.Errorf("Expected doc 2 not to match, but was %v", exp)
+	}
+	if s := fmt.Sprint(exp); !strings.Contains(s, "drill-down dimension Publish Year") {
+		t.Errorf("Expected the failed dimension to be explained, but was %v", s)
+	}
+
+	// doc 1 matches both, and is explained by the base query
+	if exp, err = ss.Explain(q, 1); err != nil {
+		t.Fatal(err)
+	}
+	baseExp, err := ss.Explain(base, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !exp.IsMatch() || exp.Value() != baseExp.Value() {
+		t.Errorf("Expected doc 1 to match as %v, but was %v", baseExp, exp)
+	}
+}
@@ -0,0 +1,191 @@
+package facet
+
+import (
+	"github.com/balzaczyy/golucene/core/index"
+	"github.com/balzaczyy/golucene/core/search"
+	"github.com/balzaczyy/golucene/core/util"
+)
+
+// facet/DrillSideways.java
+
+/*
+Computes drill down and sideways counts for the provided
+DrillDownQuery. Drill sideways counts include alternative values/aggregates
+for the drill-down dimensions so that a dimension does not disappear
+after the user drills down into it.
+
+Use Search() to do the search, and then get the hits and the
+collectors to count facets over from the returned DrillSidewaysResult.
+
+NOTE: this allocates one FacetsCollector for each drill-down, plus
+one. If your index has high number of facet labels then this will
+multiply your memory usage.
+*/
+type DrillSideways struct {
+	// IndexSearcher passed to constructor.
+	searcher *search.IndexSearcher
+	// FacetsConfig passed to constructor.
+	config *FacetsConfig
+}
+
+func NewDrillSideways(searcher *search.IndexSearcher, config *FacetsConfig) *DrillSideways {
+	return &DrillSideways{searcher, config}
+}
+
+/*
+Result of a drill sideways search, including the hits and the
+collectors to compute facets from.
+*/
+type DrillSidewaysResult struct {
+	// Hits.
+	Hits search.TopDocs
+	// Documents matching the base query and all drill-down
+	// constraints; count the non drill-down dimensions over these.
+	DrillDowns *FacetsCollector
+	// For each drill-down dimension, the documents matching the base
+	// query and every other dimension's constraint, i.e. the
+	// documents to count that dimension over as if it were not
+	// constrained.
+	DrillSideways map[string]*FacetsCollector
+}
+
+/*
+Search, collecting hits with a Collector, and computing drill down
+and sideways counts.
+*/
+func (ds *DrillSideways) Search(query *DrillDownQuery, topN int) (*DrillSidewaysResult, error) {
+	limit := ds.searcher.TopReaderContext().Reader().MaxDoc()
+	if limit == 0 {
+		limit = 1 // the collector does not allow numHits = 0
+	}
+	if topN > limit {
+		topN = limit
+	}
+
+	drillDownCollector := NewFacetsCollector()
+
+	if len(query.dims) == 0 {
+		// There are no drill-down dims, so there is no drill-sideways
+		// to compute:
+		hits, err := FacetsSearch(ds.searcher, query, topN, drillDownCollector)
+		if err != nil {
+			return nil, err
+		}
+		return &DrillSidewaysResult{hits, drillDownCollector, nil}, nil
+	}
+
+	baseQuery := query.baseQuery
+	if baseQuery == nil {
+		// TODO: we could optimize this pure-browse case by making a
+		// custom scorer instead:
+		baseQuery = search.NewMatchAllDocsQuery()
+	}
+	baseWeight, err := ds.searcher.CreateNormalizedWeight(baseQuery)
+	if err != nil {
+		return nil, err
+	}
+	dimWeights, err := query.createDimWeights(ds.searcher)
+	if err != nil {
+		return nil, err
+	}
+
+	hitCollector := search.NewTopScoreDocCollector(topN, nil, !baseWeight.IsScoresDocsOutOfOrder())
+	drillSidewaysCollectors := make([]*FacetsCollector, len(query.dims))
+	for i, _ := range drillSidewaysCollectors {
+		drillSidewaysCollectors[i] = NewFacetsCollector()
+	}
+	c := &drillSidewaysCollector{
+		hitCollector:            hitCollector,
+		drillDownCollector:      drillDownCollector,
+		drillSidewaysCollectors: drillSidewaysCollectors,
+	}
+
+	for _, ctx := range ds.searcher.TopReaderContext().Leaves() {
+		acceptDocs := ctx.Reader().(index.AtomicReader).LiveDocs()
+		if c.dimBits, err = dimDocIdSets(dimWeights, ctx, acceptDocs); err != nil {
+			return nil, err
+		}
+		c.SetNextReader(ctx)
+
+		bs, err := baseWeight.BulkScorer(ctx, !c.AcceptsDocsOutOfOrder(), acceptDocs)
+		if err != nil {
+			return nil, err
+		}
+		if bs != nil {
+			if err = bs.ScoreAndCollect(c); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	sideways := make(map[string]*FacetsCollector)
+	for i, dim := range query.dims {
+		sideways[dim] = drillSidewaysCollectors[i]
+	}
+	return &DrillSidewaysResult{hitCollector.TopDocs(), drillDownCollector, sideways}, nil
+}
+
+/*
+Routes each hit of the base query: a document matching every
+drill-down dimension is a true hit and goes to every collector, while
+a document missing exactly one dimension is a "near miss" that only
+counts sideways for the dimension it missed. Documents missing two or
+more dimensions are dropped.
+*/
+type drillSidewaysCollector struct {
+	hitCollector            search.Collector
+	drillDownCollector      *FacetsCollector
+	drillSidewaysCollectors []*FacetsCollector
+	dimBits                 []*util.FixedBitSet
+}
+
+func (c *drillSidewaysCollector) Collect(doc int) error {
+	failedDim := -1
+	for i, bits := range c.dimBits {
+		if bits == nil || !bits.At(doc) {
+			if failedDim != -1 {
+				// more than one dim failed; this doc is not a hit nor a
+				// near miss
+				return nil
+			}
+			failedDim = i
+		}
+	}
+
+	if failedDim != -1 {
+		return c.drillSidewaysCollectors[failedDim].Collect(doc)
+	}
+
+	if err := c.hitCollector.Collect(doc); err != nil {
+		return err
+	}
+	if err := c.drillDownCollector.Collect(doc); err != nil {
+		return err
+	}
+	for _, sc := range c.drillSidewaysCollectors {
+		if err := sc.Collect(doc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *drillSidewaysCollector) SetScorer(s search.Scorer) {
+	c.hitCollector.SetScorer(s)
+	c.drillDownCollector.SetScorer(s)
+	for _, sc := range c.drillSidewaysCollectors {
+		sc.SetScorer(s)
+	}
+}
+
+func (c *drillSidewaysCollector) SetNextReader(ctx *index.AtomicReaderContext) {
+	c.hitCollector.SetNextReader(ctx)
+	c.drillDownCollector.SetNextReader(ctx)
+	for _, sc := range c.drillSidewaysCollectors {
+		sc.SetNextReader(ctx)
+	}
+}
+
+func (c *drillSidewaysCollector) AcceptsDocsOutOfOrder() bool {
+	return c.hitCollector.AcceptsDocsOutOfOrder()
+}
@@ -0,0 +1,100 @@
+package facet
+
+import (
+	"fmt"
+	"github.com/balzaczyy/golucene/core/index"
+	"github.com/balzaczyy/golucene/core/search"
+	"sort"
+	"testing"
+)
+
+// Returns the sorted global ids of the documents collected by fc.
+func collectedDocs(fc *FacetsCollector) string {
+	var docs []int
+	for _, md := range fc.MatchingDocs() {
+		for doc := md.Bits.NextSetBit(0); doc != -1; {
+			docs = append(docs, md.Context.DocBase+doc)
+			if doc++; doc >= md.Bits.Length() {
+				break
+			}
+			doc = md.Bits.NextSetBit(doc)
+		}
+	}
+	sort.Ints(docs)
+	return fmt.Sprint(docs)
+}
+
+func TestDrillSideways(t *testing.T) {
+	ss, closer := newBooksSearcher(t)
+	defer closer()
+	base := search.NewTermQuery(index.NewTerm("content", "foo"))
+	ds := NewDrillSideways(ss, NewFacetsConfig())
+
+	q := NewDrillDownQuery(NewFacetsConfig(), base)
+	q.Add("Author", "Lisa")
+	q.Add("Publish Year", "2010")
+	res, err := ds.Search(q, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Hits.TotalHits != 1 || res.Hits.ScoreDocs[0].Doc != 1 {
+		t.Errorf("Expected doc 1 as only hit, but was %v", res.Hits.ScoreDocs)
+	}
+	for _, c := range []struct {
+		name     string
+		fc       *FacetsCollector
+		expected string
+	}{
+		{"drill down", res.DrillDowns, "[1]"},
+		// doc 0 is a near miss by Bob, doc 2 a near miss from 2012;
+		// doc 3 misses both dimensions
+		{"sideways Author", res.DrillSideways["Author"], "[0 1]"},
+		{"sideways Publish Year", res.DrillSideways["Publish Year"], "[1 2]"},
+	} {
+		if docs := collectedDocs(c.fc); docs != c.expected {
+			t.Errorf("%v: expected %v, but was %v", c.name, c.expected, docs)
+		}
+	}
+
+	// several values on one dimension
+	q = NewDrillDownQuery(NewFacetsConfig(), base)
+	q.Add("Author", "Lisa")
+	q.Add("Author", "Bob")
+	q.Add("Publish Year", "2012")
+	if res, err = ds.Search(q, 10); err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []struct {
+		name     string
+		fc       *FacetsCollector
+		expected string
+	}{
+		// doc 5 by Bob from 2012 does not match the base query
+		{"drill down", res.DrillDowns, "[2]"},
+		{"sideways Author", res.DrillSideways["Author"], "[2 3]"},
+		{"sideways Publish Year", res.DrillSideways["Publish Year"], "[0 1 2]"},
+	} {
+		if docs := collectedDocs(c.fc); docs != c.expected {
+			t.Errorf("%v: expected %v, but was %v", c.name, c.expected, docs)
+		}
+	}
+}
+
+func TestDrillSidewaysWithoutDims(t *testing.T) {
+	ss, closer := newBooksSearcher(t)
+	defer closer()
+	q := NewDrillDownQuery(NewFacetsConfig(), search.NewTermQuery(index.NewTerm("content", "foo")))
+	res, err := NewDrillSideways(ss, NewFacetsConfig()).Search(q, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Hits.TotalHits != 5 {
+		t.Errorf("Expected 5 hits, but was %v", res.Hits.TotalHits)
+	}
+	if docs := collectedDocs(res.DrillDowns); docs != "[0 1 2 3 4]" {
+		t.Errorf("Expected [0 1 2 3 4], but was %v", docs)
+	}
+	if res.DrillSideways != nil {
+		t.Errorf("Expected no sideways counts, but was %v", res.DrillSideways)
+	}
+}
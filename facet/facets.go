@@ -0,0 +1,86 @@
+package facet
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// facet/Facets.java
+
+/* Common base class for all facets implementations. */
+type Facets interface {
+	// Returns the topN child labels under the specified path. Returns
+	// nil if the specified path doesn't exist or if this dimension
+	// was never seen.
+	TopChildren(topN int, dim string, path ...string) (*FacetResult, error)
+	// Return the count or value for a specific path. Returns -1 if
+	// this path doesn't exist, else the count.
+	SpecificValue(dim string, path ...string) (float64, error)
+	// Returns topN labels for any dimension that had hits, sorted by
+	// the number of hits that dimension matched; this is used for
+	// "sparse" faceting, where many different dimensions were
+	// indexed, for example depending on the type of document.
+	AllDims(topN int) ([]*FacetResult, error)
+}
+
+/*
+Source of the per-segment matching documents counted by Facets
+implementations, like LongRangeFacetCounts. FacetsCollector satisfies
+it, as does any collector sampling or filtering its hits, so that
+the Facets constructors don't depend on how the hits were collected.
+*/
+type MatchingDocsSource interface {
+	MatchingDocs() []*MatchingDocs
+}
+
+// facet/FacetResult.java
+
+/* Counts or aggregates for a single dimension. */
+type FacetResult struct {
+	// Dimension that was requested.
+	Dim string
+	// Path whose children were requested.
+	Path []string
+	// Total value for this path (sum of all child counts, or sum of
+	// all child values), even those not included in the topN.
+	Value float64
+	// How many child labels were encountered.
+	ChildCount int
+	// Child counts.
+	LabelValues []*LabelAndValue
+}
+
+func NewFacetResult(dim string, path []string, value float64,
+	labelValues []*LabelAndValue, childCount int) *FacetResult {
+	return &FacetResult{
+		Dim:         dim,
+		Path:        path,
+		Value:       value,
+		LabelValues: labelValues,
+		ChildCount:  childCount,
+	}
+}
+
+func (r *FacetResult) String() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "dim=%v path=%v value=%v childCount=%v\n",
+		r.Dim, r.Path, r.Value, r.ChildCount)
+	for _, lv := range r.LabelValues {
+		fmt.Fprintf(&buf, "  %v\n", lv)
+	}
+	return buf.String()
+}
+
+// facet/LabelAndValue.java
+
+/* Single label and its value, usually contained in a FacetResult. */
+type LabelAndValue struct {
+	// Facet's label.
+	Label string
+	// Value associated with this label.
+	Value float64
+}
+
+func (lv *LabelAndValue) String() string {
+	return fmt.Sprintf("%v (%v)", lv.Label, lv.Value)
+}
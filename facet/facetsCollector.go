@@ -0,0 +1,151 @@
+package facet
+
+import (
+	"github.com/balzaczyy/golucene/core/index"
+	"github.com/balzaczyy/golucene/core/search"
+	"github.com/balzaczyy/golucene/core/util"
+)
+
+// facet/FacetsCollector.java
+
+/*
+Collects hits for subsequent faceting. Once you've run a search and
+collect hits into this, instantiate one of the Facets subclasses to
+do the facet counting. Use the FacetsSearch() utility method to
+perform an "ordinary" search but also collect into a Collector.
+*/
+type FacetsCollector struct {
+	context      *index.AtomicReaderContext
+	scorer       search.Scorer
+	totalHits    int
+	scores       []float32
+	keepScores   bool
+	bits         *util.FixedBitSet
+	matchingDocs []*MatchingDocs
+}
+
+/*
+Holds the documents that were matched in the AtomicReaderContext. If
+scores were required, then Scores is not nil.
+*/
+type MatchingDocs struct {
+	// Context for this segment.
+	Context *index.AtomicReaderContext
+	// Which documents were seen.
+	Bits *util.FixedBitSet
+	// Non-sparse scores slice, nil if scores were not kept.
+	Scores []float32
+	// Total number of hits
+	TotalHits int
+}
+
+// Default constructor
+func NewFacetsCollector() *FacetsCollector {
+	return NewFacetsCollectorWithScores(false)
+}
+
+/*
+Create this; if keepScores is true then a []float32 is allocated to
+hold score of all hits.
+*/
+func NewFacetsCollectorWithScores(keepScores bool) *FacetsCollector {
+	return &FacetsCollector{keepScores: keepScores}
+}
+
+// True if scores were saved.
+func (fc *FacetsCollector) KeepScores() bool {
+	return fc.keepScores
+}
+
+/*
+Returns the documents matched by the query, one MatchingDocs per
+visited segment.
+*/
+func (fc *FacetsCollector) MatchingDocs() []*MatchingDocs {
+	if fc.bits != nil {
+		fc.finish()
+	}
+	return fc.matchingDocs
+}
+
+func (fc *FacetsCollector) AcceptsDocsOutOfOrder() bool {
+	// If we are keeping scores then we require in-order because we
+	// append each score to the []float32 for every hit:
+	return !fc.keepScores
+}
+
+func (fc *FacetsCollector) Collect(doc int) error {
+	fc.bits.Set(doc)
+	if fc.keepScores {
+		if fc.totalHits >= len(fc.scores) {
+			newScores := make([]float32, util.Oversize(fc.totalHits+1, 4))
+			copy(newScores, fc.scores)
+			fc.scores = newScores
+		}
+		score, err := fc.scorer.Score()
+		if err != nil {
+			return err
+		}
+		fc.scores[fc.totalHits] = score
+	}
+	fc.totalHits++
+	return nil
+}
+
+func (fc *FacetsCollector) SetScorer(scorer search.Scorer) {
+	fc.scorer = scorer
+}
+
+func (fc *FacetsCollector) SetNextReader(ctx *index.AtomicReaderContext) {
+	if fc.bits != nil {
+		fc.finish()
+	}
+	fc.bits = util.NewFixedBitSetOf(ctx.Reader().MaxDoc())
+	fc.totalHits = 0
+	if fc.keepScores {
+		fc.scores = make([]float32, 64) // some initial size
+	}
+	fc.context = ctx
+}
+
+func (fc *FacetsCollector) finish() {
+	if fc.bits != nil {
+		fc.matchingDocs = append(fc.matchingDocs, &MatchingDocs{
+			Context:   fc.context,
+			Bits:      fc.bits,
+			Scores:    fc.scores,
+			TotalHits: fc.totalHits,
+		})
+		fc.bits = nil
+		fc.scores = nil
+		fc.context = nil
+	}
+}
+
+/*
+Utility method, to search and also collect all hits into the provided
+Collector.
+*/
+func FacetsSearch(searcher *search.IndexSearcher, q search.Query, n int,
+	fc search.Collector) (topDocs search.TopDocs, err error) {
+
+	if n == 0 {
+		// no hits wanted, only facets
+		err = searcher.SearchCollector(q, fc)
+		return
+	}
+	limit := searcher.TopReaderContext().Reader().MaxDoc()
+	if limit == 0 {
+		limit = 1
+	}
+	if n > limit {
+		n = limit
+	}
+	// FacetsCollector keeping scores requires docs in order; keep it
+	// simple and always collect top docs in order here.
+	hitsCollector := search.NewTopScoreDocCollector(n, nil, true)
+	if err = searcher.SearchCollector(q, search.WrapCollectors(hitsCollector, fc)); err != nil {
+		return
+	}
+	return hitsCollector.TopDocs(), nil
+}
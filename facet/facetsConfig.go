@@ -0,0 +1,156 @@
+package facet
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// facet/FacetsConfig.java
+
+/*
+Records per-dimension configuration. By default a dimension is flat,
+single valued and does not require count for the dimension; use the
+setters in this class to change these settings for each dim.
+
+NOTE: this configuration is not saved into the index, but it's vital,
+and up to the application to ensure, that at search time the
+provided FacetsConfig matches what was used during indexing.
+*/
+type FacetsConfig struct {
+	sync.Mutex
+	fieldTypes map[string]*DimConfig
+}
+
+/* Holds the configuration for one dimension. */
+type DimConfig struct {
+	// True if this dimension is hierarchical.
+	Hierarchical bool
+	// True if this dimension is multi-valued.
+	MultiValued bool
+	// True if the count/aggregate for the entire dimension is
+	// required, which is unusual (default is false).
+	RequireDimCount bool
+	// Actual field where this dimension's facet labels should be
+	// indexed
+	IndexFieldName string
+}
+
+/*
+Default index field name under which facet labels are indexed as
+terms of the form dim + DELIM_CHAR + path component(s).
+*/
+const DEFAULT_INDEX_FIELD_NAME = "$facets"
+
+// Default per-dim configuration.
+var DEFAULT_DIM_CONFIG = DimConfig{IndexFieldName: DEFAULT_INDEX_FIELD_NAME}
+
+/*
+Delimiter between a dimension and its path components, and between
+the components themselves, in an indexed facet label. Dims and path
+components must not contain it.
+*/
+const DELIM_CHAR = '\u001F'
+
+func NewFacetsConfig() *FacetsConfig {
+	return &FacetsConfig{fieldTypes: make(map[string]*DimConfig)}
+}
+
+/*
+Get the current configuration for a dimension. If the dimension was
+never configured, a copy of DEFAULT_DIM_CONFIG is returned.
+*/
+func (c *FacetsConfig) DimConfig(dimName string) DimConfig {
+	c.Lock()
+	defer c.Unlock()
+	if ft, ok := c.fieldTypes[dimName]; ok {
+		return *ft
+	}
+	return DEFAULT_DIM_CONFIG
+}
+
+func (c *FacetsConfig) dimConfig(dimName string) *DimConfig {
+	ft, ok := c.fieldTypes[dimName]
+	if !ok {
+		ft = new(DimConfig)
+		*ft = DEFAULT_DIM_CONFIG
+		c.fieldTypes[dimName] = ft
+	}
+	return ft
+}
+
+// Pass true if this dimension is hierarchical (has depth > 1 paths).
+func (c *FacetsConfig) SetHierarchical(dimName string, v bool) {
+	c.Lock()
+	defer c.Unlock()
+	c.dimConfig(dimName).Hierarchical = v
+}
+
+// Pass true if this dimension may have more than one value per document.
+func (c *FacetsConfig) SetMultiValued(dimName string, v bool) {
+	c.Lock()
+	defer c.Unlock()
+	c.dimConfig(dimName).MultiValued = v
+}
+
+/*
+Pass true if at search time you require accurate counts of the
+dimension, i.e. how many hits have this dimension.
+*/
+func (c *FacetsConfig) SetRequireDimCount(dimName string, v bool) {
+	c.Lock()
+	defer c.Unlock()
+	c.dimConfig(dimName).RequireDimCount = v
+}
+
+// Specify which index field name should hold the labels for this dimension.
+func (c *FacetsConfig) SetIndexFieldName(dimName, indexFieldName string) {
+	c.Lock()
+	defer c.Unlock()
+	c.dimConfig(dimName).IndexFieldName = indexFieldName
+}
+
+// Returns map of field name to DimConfig.
+func (c *FacetsConfig) DimConfigs() map[string]DimConfig {
+	c.Lock()
+	defer c.Unlock()
+	ans := make(map[string]DimConfig)
+	for k, v := range c.fieldTypes {
+		ans[k] = *v
+	}
+	return ans
+}
+
+/*
+Turns a dim + path into an encoded string, which is also the indexed
+term text of the label.
+*/
+func PathToString(dim string, path ...string) string {
+	var buf bytes.Buffer
+	checkLabel(dim)
+	buf.WriteString(dim)
+	for _, p := range path {
+		checkLabel(p)
+		buf.WriteRune(DELIM_CHAR)
+		buf.WriteString(p)
+	}
+	return buf.String()
+}
+
+/* Turns an encoded string (from a previous call to PathToString()) back into the original []string. */
+func StringToPath(s string) []string {
+	if len(s) == 0 {
+		return nil
+	}
+	return strings.Split(s, string(DELIM_CHAR))
+}
+
+func checkLabel(label string) {
+	if len(label) == 0 {
+		panic("empty or nil components not allowed")
+	}
+	if strings.ContainsRune(label, DELIM_CHAR) {
+		panic(fmt.Sprintf("delimiter character \\u001F is not allowed in label %q", label))
+	}
+}
@@ -0,0 +1,249 @@
+package facet
+
+import (
+	"fmt"
+	"github.com/balzaczyy/golucene/core/util"
+)
+
+// facet/RandomSamplingFacetsCollector.java
+
+/*
+Collects hits for subsequent faceting, using sampling if needed.
+Once you've run a search and collect hits into this, instantiate one
+of the Facets subclasses to do the facet counting. Note that this
+collector does not collect the scores of matching docs (i.e.
+MatchingDocs.Scores) is nil.
+
+If you require the original number of hits, you can call
+TotalHits(). Note that TotalHits() will return the number of hits
+in the original result set, not the sampled one.
+
+The sampling is done by splitting the hits of each segment into bins
+of 1/samplingRate documents, and picking one random document from
+each bin. The random generator is seeded explicitly, so the same
+seed over the same index always produces the same sample.
+
+Counts computed over the sample can be scaled back to estimates over
+all hits with AmortizeFacetCounts().
+*/
+type RandomSamplingFacetsCollector struct {
+	*FacetsCollector
+
+	sampleSize   int
+	random       *xorShift64Random
+	samplingRate float64
+	sampledDocs  []*MatchingDocs
+	totalHits    int // -1 until computed
+	leftoverBin  int
+	leftoverIdx  int
+}
+
+// Default seed used when none is given, so sampling stays reproducible.
+const DEFAULT_SAMPLING_SEED = 0x7a1bd3e5
+
+/*
+Constructor with the given sample size and default seed.
+*/
+func NewRandomSamplingFacetsCollector(sampleSize int) *RandomSamplingFacetsCollector {
+	return NewRandomSamplingFacetsCollectorWithSeed(sampleSize, DEFAULT_SAMPLING_SEED)
+}
+
+/*
+Constructor with the given sample size and seed. sampleSize is the
+preferred sample size; if the number of hits is greater than the
+size, sampling will be done using a sample ratio of sampling size /
+totalN. For example: 1000 hits, sample size = 10 results in
+samplingRatio of 0.01. If the number of hits is lower, no sampling
+is done at all.
+*/
+func NewRandomSamplingFacetsCollectorWithSeed(sampleSize int, seed int64) *RandomSamplingFacetsCollector {
+	assert2(sampleSize > 0, "sampleSize must be positive, got %v", sampleSize)
+	return &RandomSamplingFacetsCollector{
+		FacetsCollector: NewFacetsCollector(),
+		sampleSize:      sampleSize,
+		random:          newXORShift64Random(seed),
+		totalHits:       -1,
+		leftoverBin:     -1,
+	}
+}
+
+/*
+Returns the total number of hits of the original (un-sampled)
+result set.
+*/
+func (c *RandomSamplingFacetsCollector) TotalHits() int {
+	if c.totalHits == -1 {
+		c.totalHits = 0
+		for _, md := range c.FacetsCollector.MatchingDocs() {
+			c.totalHits += md.TotalHits
+		}
+	}
+	return c.totalHits
+}
+
+/*
+Returns the sampled list of the matching documents. Note that a
+MatchingDocs instance is returned per segment, even if no hits from
+that segment are included in the sampled set.
+
+Note: One or more of the MatchingDocs might be empty (not containing
+any hits) as result of sampling.
+
+Note: MatchingDocs.TotalHits is copied from the original
+MatchingDocs, scores is set to nil.
+*/
+func (c *RandomSamplingFacetsCollector) MatchingDocs() []*MatchingDocs {
+	matchingDocs := c.FacetsCollector.MatchingDocs()
+
+	if c.TotalHits() <= c.sampleSize {
+		return matchingDocs
+	}
+
+	if c.sampledDocs == nil {
+		c.samplingRate = float64(c.sampleSize) / float64(c.totalHits)
+		c.sampledDocs = c.createSampledDocs(matchingDocs)
+	}
+	return c.sampledDocs
+}
+
+// Returns the original matching documents.
+func (c *RandomSamplingFacetsCollector) OriginalMatchingDocs() []*MatchingDocs {
+	return c.FacetsCollector.MatchingDocs()
+}
+
+/*
+Returns the sampling rate that was used, or 1 if no sampling was
+needed.
+*/
+func (c *RandomSamplingFacetsCollector) SamplingRate() float64 {
+	c.MatchingDocs()
+	if c.sampledDocs == nil {
+		return 1
+	}
+	return c.samplingRate
+}
+
+// Create a sampled copy of the matching documents list.
+func (c *RandomSamplingFacetsCollector) createSampledDocs(matchingDocsList []*MatchingDocs) []*MatchingDocs {
+	sampledDocsList := make([]*MatchingDocs, len(matchingDocsList))
+	for i, docs := range matchingDocsList {
+		sampledDocsList[i] = c.createSample(docs)
+	}
+	return sampledDocsList
+}
+
+// Create a sample of the given hits.
+func (c *RandomSamplingFacetsCollector) createSample(docs *MatchingDocs) *MatchingDocs {
+	maxdoc := docs.Context.Reader().MaxDoc()
+
+	sampleDocs := util.NewFixedBitSetOf(maxdoc)
+
+	binSize := int(1.0 / c.samplingRate)
+
+	counter, limit, randomIndex := 0, 0, 0
+	if c.leftoverBin != -1 {
+		limit = c.leftoverBin
+		// either -1 (bin already sampled) or the random index left
+		// over from the previous segment's bin
+		randomIndex = c.leftoverIdx
+	} else {
+		limit = binSize
+		randomIndex = c.random.nextInt(binSize)
+	}
+
+	for doc := 0; doc < maxdoc; doc++ {
+		if doc = docs.Bits.NextSetBit(doc); doc == -1 {
+			break
+		}
+		if counter == randomIndex {
+			sampleDocs.Set(doc)
+		}
+		counter++
+		if counter >= limit {
+			counter = 0
+			limit = binSize
+			randomIndex = c.random.nextInt(binSize)
+		}
+	}
+
+	if counter == 0 {
+		// we either exhausted the bin and the iterator at the same
+		// time, or this segment had no results
+		c.leftoverBin, c.leftoverIdx = -1, -1
+	} else {
+		c.leftoverBin = limit - counter
+		if randomIndex > counter {
+			// the random index is still ahead of us; carry it over
+			// into the next segment
+			c.leftoverIdx = randomIndex - counter
+		} else {
+			// we already sampled the bin; don't sample it again in
+			// the next segment
+			c.leftoverIdx = -1
+		}
+	}
+
+	return &MatchingDocs{
+		Context:   docs.Context,
+		Bits:      sampleDocs,
+		TotalHits: docs.TotalHits,
+	}
+}
+
+/*
+Note: if you use a counting Facets implementation, you can amortize
+the sampled counts by calling this method. Uses the Facets
+implementation's result and the sampling rate to estimate the counts
+over all hits.
+*/
+func (c *RandomSamplingFacetsCollector) AmortizeFacetCounts(res *FacetResult) *FacetResult {
+	if res == nil || c.TotalHits() <= c.sampleSize {
+		return res
+	}
+
+	samplingRate := c.SamplingRate()
+	fixedLabelValues := make([]*LabelAndValue, len(res.LabelValues))
+	for i, lv := range res.LabelValues {
+		fixedLabelValues[i] = &LabelAndValue{lv.Label, float64(int64(lv.Value / samplingRate))}
+	}
+
+	// Return the new FacetResult
+	return NewFacetResult(res.Dim, res.Path, float64(int64(res.Value/samplingRate)),
+		fixedLabelValues, res.ChildCount)
+}
+
+// util/XORShift64Random.java
+
+/*
+A pseudo-random generator based on the Xorshift algorithm; it is
+fast, small and, given the same seed, yields the same sequence on
+every platform.
+*/
+type xorShift64Random struct {
+	x uint64
+}
+
+func newXORShift64Random(seed int64) *xorShift64Random {
+	if seed == 0 {
+		seed = DEFAULT_SAMPLING_SEED
+	}
+	return &xorShift64Random{uint64(seed)}
+}
+
+func (r *xorShift64Random) nextLong() int64 {
+	r.x ^= r.x << 21
+	r.x ^= r.x >> 35
+	r.x ^= r.x << 4
+	return int64(r.x)
+}
+
+// Returns a uniformly distributed value in [0, n).
+func (r *xorShift64Random) nextInt(n int) int {
+	return int(uint64(r.nextLong()) % uint64(n))
+}
+
+func assert2(ok bool, msg string, args ...interface{}) {
+	if !ok {
+		panic(fmt.Sprintf(msg, args...))
+	}
+}
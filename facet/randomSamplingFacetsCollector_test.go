@@ -0,0 +1,174 @@
+package facet
+
+import (
+	std "github.com/balzaczyy/golucene/analysis/standard"
+	_ "github.com/balzaczyy/golucene/core/codec/lucene410"
+	docu "github.com/balzaczyy/golucene/core/document"
+	"github.com/balzaczyy/golucene/core/index"
+	"github.com/balzaczyy/golucene/core/search"
+	"github.com/balzaczyy/golucene/core/store"
+	"github.com/balzaczyy/golucene/core/util"
+	"strconv"
+	"testing"
+)
+
+/*
+Writes one segment per given size, each doc holding its global number
+in the "id" field, and opens a reader over them.
+*/
+func newSegmentsReader(t *testing.T, sizes ...int) index.IndexReader {
+	dir, err := store.OpenFSDirectory(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := index.NewIndexWriter(dir, index.NewIndexWriterConfig(util.VERSION_LATEST, std.NewStandardAnalyzer()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := 0
+	for _, size := range sizes {
+		for i := 0; i < size; i++ {
+			doc := docu.NewDocument()
+			doc.Add(docu.NewStringField("id", strconv.Itoa(id), docu.STORE_YES))
+			if err = w.AddDocument(doc.Fields()); err != nil {
+				t.Fatal(err)
+			}
+			id++
+		}
+		if err = w.Commit(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	r, err := index.OpenDirectoryReader(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Leaves()) != len(sizes) {
+		t.Fatalf("Expected %v segments, but was %v", len(sizes), len(r.Leaves()))
+	}
+	return r
+}
+
+func sampleAllDocs(t *testing.T, r index.IndexReader, c *RandomSamplingFacetsCollector) {
+	ss := search.NewIndexSearcher(r)
+	if _, err := FacetsSearch(ss, search.NewMatchAllDocsQuery(), 0, c); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRandomSamplingFacetsCollector(t *testing.T) {
+	// 30 bins of 10 docs; the bin after the first 130 docs of the
+	// second segment is left over, and ends in the third segment
+	sizes := []int{100, 137, 63}
+	r := newSegmentsReader(t, sizes...)
+	defer r.Close()
+
+	sample := func(seed int64) []*MatchingDocs {
+		c := NewRandomSamplingFacetsCollectorWithSeed(30, seed)
+		sampleAllDocs(t, r, c)
+		if c.TotalHits() != 300 {
+			t.Fatalf("Expected 300 hits, but was %v", c.TotalHits())
+		}
+		if rate := c.SamplingRate(); rate != 0.1 {
+			t.Fatalf("Expected sampling rate 0.1, but was %v", rate)
+		}
+		return c.MatchingDocs()
+	}
+	docs := sample(1234)
+
+	// same seed, same sample
+	for i, md := range sample(1234) {
+		for doc := 0; doc < md.Bits.Length(); doc++ {
+			if md.Bits.At(doc) != docs[i].Bits.At(doc) {
+				t.Errorf("Expected the same sample of segment %v for the same seed", i)
+				break
+			}
+		}
+	}
+
+	total := 0
+	for i, md := range docs {
+		if md.TotalHits != sizes[i] {
+			t.Errorf("Expected the original %v hits of segment %v, but was %v", sizes[i], i, md.TotalHits)
+		}
+		if md.Scores != nil {
+			t.Errorf("Expected no scores in the sample of segment %v", i)
+		}
+		// one doc per full bin, plus maybe the leftover one
+		n := md.Bits.Cardinality()
+		if lo := sizes[i] / 10; n < lo || n > lo+1 {
+			t.Errorf("Expected %v or %v docs sampled in segment %v, but was %v", lo, lo+1, i, n)
+		}
+		total += n
+	}
+	if total != 30 {
+		t.Errorf("Expected 30 docs sampled, but was %v", total)
+	}
+
+	// the bin spanning two segments is sampled exactly once
+	n := 0
+	for doc := 130; doc < 137; doc++ {
+		if docs[1].Bits.At(doc) {
+			n++
+		}
+	}
+	for doc := 0; doc < 3; doc++ {
+		if docs[2].Bits.At(doc) {
+			n++
+		}
+	}
+	if n != 1 {
+		t.Errorf("Expected the leftover bin to be sampled once, but was %v times", n)
+	}
+}
+
+func TestRandomSamplingAmortizeFacetCounts(t *testing.T) {
+	r := newSegmentsReader(t, 100, 137, 63)
+	defer r.Close()
+	c := NewRandomSamplingFacetsCollectorWithSeed(30, 1234)
+	sampleAllDocs(t, r, c)
+
+	res := NewFacetResult("dim", nil, 30, []*LabelAndValue{{"a", 12}, {"b", 18}}, 2)
+	amortized := c.AmortizeFacetCounts(res)
+	if amortized.Value != 300 {
+		t.Errorf("Expected value 300, but was %v", amortized.Value)
+	}
+	for i, expected := range []float64{120, 180} {
+		lv := amortized.LabelValues[i]
+		if lv.Label != res.LabelValues[i].Label || lv.Value != expected {
+			t.Errorf("Expected %v (%v), but was %v", res.LabelValues[i].Label, expected, lv)
+		}
+	}
+	if amortized.ChildCount != 2 || amortized.Dim != "dim" {
+		t.Errorf("Expected the dim and child count to be kept, but was %v", amortized)
+	}
+	if res.LabelValues[0].Value != 12 {
+		t.Errorf("Expected the original result to be unchanged, but was %v", res)
+	}
+}
+
+func TestRandomSamplingNotNeeded(t *testing.T) {
+	r := newSegmentsReader(t, 100, 137, 63)
+	defer r.Close()
+	for _, sampleSize := range []int{300, 1000} {
+		c := NewRandomSamplingFacetsCollectorWithSeed(sampleSize, 1234)
+		sampleAllDocs(t, r, c)
+
+		if rate := c.SamplingRate(); rate != 1 {
+			t.Errorf("%v: expected sampling rate 1, but was %v", sampleSize, rate)
+		}
+		original := c.OriginalMatchingDocs()
+		for i, md := range c.MatchingDocs() {
+			if md != original[i] || md.Bits.Cardinality() != md.TotalHits {
+				t.Errorf("%v: expected all the hits of segment %v", sampleSize, i)
+			}
+		}
+		res := NewFacetResult("dim", nil, 300, []*LabelAndValue{{"a", 120}}, 1)
+		if amortized := c.AmortizeFacetCounts(res); amortized != res {
+			t.Errorf("%v: expected unchanged counts, but was %v", sampleSize, amortized)
+		}
+	}
+}
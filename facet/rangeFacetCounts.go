@@ -0,0 +1,379 @@
+package facet
+
+import (
+	"fmt"
+	"github.com/balzaczyy/golucene/core/index"
+	"github.com/balzaczyy/golucene/core/util"
+	"math"
+	"sort"
+)
+
+// facet/range/Range.java
+
+/* Base class for a single labeled range. */
+type Range interface {
+	// Label that identifies this range.
+	Label() string
+}
+
+// facet/range/LongRange.java
+
+/* Represents a range over int64 values. */
+type LongRange struct {
+	label string
+	// Minimum (inclusive).
+	Min int64
+	// Maximum (inclusive).
+	Max int64
+}
+
+/*
+Create a LongRange. Both min and max are checked for the given
+inclusiveness; a range which cannot match any value panics.
+*/
+func NewLongRange(label string, minIn int64, minInclusive bool,
+	maxIn int64, maxInclusive bool) *LongRange {
+
+	if !minInclusive {
+		if minIn != math.MaxInt64 {
+			minIn++
+		} else {
+			panic(fmt.Sprintf("LongRange %v: min is MaxInt64 and exclusive", label))
+		}
+	}
+
+	if !maxInclusive {
+		if maxIn != math.MinInt64 {
+			maxIn--
+		} else {
+			panic(fmt.Sprintf("LongRange %v: max is MinInt64 and exclusive", label))
+		}
+	}
+
+	if minIn > maxIn {
+		panic(fmt.Sprintf("LongRange %v: range is empty", label))
+	}
+
+	return &LongRange{label: label, Min: minIn, Max: maxIn}
+}
+
+func (r *LongRange) Label() string { return r.label }
+
+// True if this range accepts the provided value.
+func (r *LongRange) Accept(value int64) bool {
+	return value >= r.Min && value <= r.Max
+}
+
+func (r *LongRange) String() string {
+	return fmt.Sprintf("LongRange(%v to %v)", r.Min, r.Max)
+}
+
+// facet/range/DoubleRange.java
+
+/* Represents a range over float64 values. */
+type DoubleRange struct {
+	label string
+	// Minimum (inclusive).
+	Min float64
+	// Maximum (inclusive).
+	Max float64
+}
+
+/*
+Create a DoubleRange. Exclusive bounds are translated to the
+next/previous representable float64, so the stored Min and Max are
+always inclusive.
+*/
+func NewDoubleRange(label string, minIn float64, minInclusive bool,
+	maxIn float64, maxInclusive bool) *DoubleRange {
+
+	if math.IsNaN(minIn) {
+		panic("min cannot be NaN")
+	}
+	if !minInclusive {
+		minIn = math.Nextafter(minIn, math.Inf(1))
+	}
+
+	if math.IsNaN(maxIn) {
+		panic("max cannot be NaN")
+	}
+	if !maxInclusive {
+		maxIn = math.Nextafter(maxIn, math.Inf(-1))
+	}
+
+	if minIn > maxIn {
+		panic(fmt.Sprintf("DoubleRange %v: range is empty", label))
+	}
+
+	return &DoubleRange{label: label, Min: minIn, Max: maxIn}
+}
+
+func (r *DoubleRange) Label() string { return r.label }
+
+// True if this range accepts the provided value.
+func (r *DoubleRange) Accept(value float64) bool {
+	return value >= r.Min && value <= r.Max
+}
+
+func (r *DoubleRange) String() string {
+	return fmt.Sprintf("DoubleRange(%v to %v)", r.Min, r.Max)
+}
+
+// Converts this to a LongRange over sortable int64 values.
+func (r *DoubleRange) toLongRange() *LongRange {
+	return NewLongRange(r.label,
+		util.DoubleToSortableLong(r.Min), true,
+		util.DoubleToSortableLong(r.Max), true)
+}
+
+// facet/range/RangeFacetCounts.java
+
+/* Base class for range faceting. */
+type rangeFacetCounts struct {
+	// Ranges passed to constructor.
+	ranges []Range
+	// Counts, initialized in by subclass.
+	counts []int
+	// Our field name.
+	field string
+	// Total number of hits in all ranges.
+	totCount int
+}
+
+func newRangeFacetCounts(field string, ranges []Range) *rangeFacetCounts {
+	return &rangeFacetCounts{
+		field:  field,
+		ranges: ranges,
+		counts: make([]int, len(ranges)),
+	}
+}
+
+func (c *rangeFacetCounts) TopChildren(topN int, dim string, path ...string) (*FacetResult, error) {
+	if dim != c.field {
+		return nil, fmt.Errorf("invalid dim \"%v\", should be \"%v\"", dim, c.field)
+	}
+	if len(path) != 0 {
+		return nil, fmt.Errorf("path.length should be 0")
+	}
+	labelValues := make([]*LabelAndValue, len(c.counts))
+	for i, count := range c.counts {
+		labelValues[i] = &LabelAndValue{c.ranges[i].Label(), float64(count)}
+	}
+	return NewFacetResult(dim, path, float64(c.totCount), labelValues, len(labelValues)), nil
+}
+
+func (c *rangeFacetCounts) SpecificValue(dim string, path ...string) (float64, error) {
+	// TODO: should we impl this?
+	return 0, fmt.Errorf("SpecificValue is not supported by range facets (dim \"%v\")", dim)
+}
+
+func (c *rangeFacetCounts) AllDims(topN int) ([]*FacetResult, error) {
+	res, err := c.TopChildren(topN, c.field)
+	if err != nil {
+		return nil, err
+	}
+	return []*FacetResult{res}, nil
+}
+
+func (c *rangeFacetCounts) String() string {
+	return fmt.Sprintf("RangeFacetCounts totCount=%v:\n%v", c.totCount, c.counts)
+}
+
+// facet/range/LongRangeFacetCounts.java
+
+/*
+Facets implementation that computes counts for dynamic long ranges
+from a provided numeric docvalues field, using the matching docs
+collected by FacetsCollector. Use this for dimensions that change in
+real-time (e.g. a relative time based dimension like "Past day",
+"Past 2 days", etc.) or that change for each request (e.g. distance
+from the user's location, "< 1 km", "< 2 km", etc.).
+*/
+type LongRangeFacetCounts struct {
+	*rangeFacetCounts
+}
+
+/*
+Create LongRangeFacetCounts, using the NumericDocValues of the
+provided field.
+*/
+func NewLongRangeFacetCounts(field string, hits MatchingDocsSource,
+	ranges ...*LongRange) (*LongRangeFacetCounts, error) {
+
+	rs := make([]Range, len(ranges))
+	for i, r := range ranges {
+		rs[i] = r
+	}
+	ans := &LongRangeFacetCounts{newRangeFacetCounts(field, rs)}
+	if err := ans.count(field, hits.MatchingDocs(), ranges, identityLong); err != nil {
+		return nil, err
+	}
+	return ans, nil
+}
+
+func identityLong(v int64) int64 { return v }
+
+/*
+Counts each matching doc's value, mapped through toSortable, against
+the given ranges.
+*/
+func (c *rangeFacetCounts) count(field string, matchingDocs []*MatchingDocs,
+	ranges []*LongRange, toSortable func(int64) int64) error {
+
+	counter := newLongRangeCounter(ranges)
+
+	missingCount := 0
+	for _, hits := range matchingDocs {
+		if hits.TotalHits == 0 {
+			continue
+		}
+		fv, err := hits.Context.Reader().(index.AtomicReader).NumericDocValues(field)
+		if err != nil {
+			return err
+		}
+		if fv == nil {
+			missingCount += hits.TotalHits
+			continue
+		}
+
+		length := hits.Bits.Length()
+		for doc := 0; doc < length; doc++ {
+			if doc = hits.Bits.NextSetBit(doc); doc == -1 {
+				break
+			}
+			counter.add(toSortable(fv(doc)))
+		}
+	}
+
+	missingCount += counter.fillCounts(c.counts)
+	c.totCount -= missingCount
+	for _, hits := range matchingDocs {
+		c.totCount += hits.TotalHits
+	}
+	return nil
+}
+
+// facet/range/DoubleRangeFacetCounts.java
+
+/*
+Facets implementation that computes counts for dynamic double ranges
+from a provided numeric docvalues field, where each value is the raw
+IEEE 754 bits of a float64 (as written by DoubleDocValuesField).
+
+If you are multi-valued per-document, or the values do not come from
+docvalues, you must compute the values yourself and use
+LongRangeFacetCounts instead.
+*/
+type DoubleRangeFacetCounts struct {
+	*rangeFacetCounts
+}
+
+/*
+Create DoubleRangeFacetCounts, using the NumericDocValues of the
+provided field.
+*/
+func NewDoubleRangeFacetCounts(field string, hits MatchingDocsSource,
+	ranges ...*DoubleRange) (*DoubleRangeFacetCounts, error) {
+
+	rs := make([]Range, len(ranges))
+	longRanges := make([]*LongRange, len(ranges))
+	for i, r := range ranges {
+		rs[i] = r
+		longRanges[i] = r.toLongRange()
+	}
+	ans := &DoubleRangeFacetCounts{newRangeFacetCounts(field, rs)}
+	if err := ans.count(field, hits.MatchingDocs(), longRanges, doubleBitsToSortable); err != nil {
+		return nil, err
+	}
+	return ans, nil
+}
+
+func doubleBitsToSortable(v int64) int64 {
+	return util.DoubleToSortableLong(math.Float64frombits(uint64(v)))
+}
+
+// facet/range/LongRangeCounter.java
+
+/*
+Counts how many times each range was seen; per-hit it's just a
+binary search (add()) against the elementary intervals, and in the
+end we roll up back to the original ranges.
+*/
+type longRangeCounter struct {
+	ranges []*LongRange
+	// Inclusive upper bound of each elementary interval.
+	boundaries   []int64
+	leafCounts   []int
+	missingCount int
+}
+
+func newLongRangeCounter(ranges []*LongRange) *longRangeCounter {
+	// Each elementary interval is (boundaries[i-1], boundaries[i]];
+	// every range is split at the value right before its min and at
+	// its max, so it always covers whole elementary intervals. This
+	// is effectively a 1D Venn diagram of all the ranges:
+	endsMap := map[int64]bool{math.MaxInt64: true}
+	for _, r := range ranges {
+		if r.Min != math.MinInt64 {
+			endsMap[r.Min-1] = true
+		}
+		endsMap[r.Max] = true
+	}
+
+	boundaries := make([]int64, 0, len(endsMap))
+	for v, _ := range endsMap {
+		boundaries = append(boundaries, v)
+	}
+	sort.Sort(int64Slice(boundaries))
+
+	return &longRangeCounter{
+		ranges:     ranges,
+		boundaries: boundaries,
+		leafCounts: make([]int, len(boundaries)),
+	}
+}
+
+func (c *longRangeCounter) add(v int64) {
+	// Binary search to find matched elementary range; we are
+	// guaranteed to find a match because the last boundary is
+	// MaxInt64:
+	i := sort.Search(len(c.boundaries), func(i int) bool {
+		return c.boundaries[i] >= v
+	})
+	c.leafCounts[i]++
+}
+
+/*
+Fills counts corresponding to the original input ranges, returning
+the missing count (how many hits didn't match any ranges).
+*/
+func (c *longRangeCounter) fillCounts(counts []int) int {
+	c.missingCount = 0
+	seen := make([]bool, len(c.leafCounts))
+	for i, r := range c.ranges {
+		// Every range starts and ends exactly on an elementary
+		// interval boundary:
+		lo := sort.Search(len(c.boundaries), func(i int) bool {
+			return c.boundaries[i] >= r.Min
+		})
+		for j := lo; j < len(c.boundaries); j++ {
+			counts[i] += c.leafCounts[j]
+			seen[j] = true
+			if c.boundaries[j] >= r.Max {
+				break
+			}
+		}
+	}
+	for i, count := range c.leafCounts {
+		if !seen[i] {
+			c.missingCount += count
+		}
+	}
+	return c.missingCount
+}
+
+type int64Slice []int64
+
+func (a int64Slice) Len() int           { return len(a) }
+func (a int64Slice) Less(i, j int) bool { return a[i] < a[j] }
+func (a int64Slice) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
@@ -0,0 +1,69 @@
+package facet
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLongRangeCounter(t *testing.T) {
+	ranges := []*LongRange{
+		NewLongRange("less than 10", 0, true, 10, false),
+		NewLongRange("less than or equal to 10", 0, true, 10, true),
+		NewLongRange("over 90", 90, false, 100, false),
+		NewLongRange("90 or above", 90, true, 100, false),
+		NewLongRange("over 1000", 1000, false, math.MaxInt64, true),
+	}
+	counter := newLongRangeCounter(ranges)
+	for v := int64(0); v < 100; v++ {
+		counter.add(v)
+	}
+	counts := make([]int, len(ranges))
+	missing := counter.fillCounts(counts)
+
+	expected := []int{10, 11, 9, 10, 0}
+	for i, n := range expected {
+		if counts[i] != n {
+			t.Errorf("%v: expected %v, but was %v", ranges[i].Label(), n, counts[i])
+		}
+	}
+	// values 11..89 don't match any range
+	if missing != 79 {
+		t.Errorf("Expected 79 missing, but was %v", missing)
+	}
+}
+
+func TestDoubleRangeExclusive(t *testing.T) {
+	r := NewDoubleRange("(1, 2)", 1, false, 2, false)
+	if r.Accept(1) || r.Accept(2) || !r.Accept(1.5) {
+		t.Errorf("Unexpected acceptance for %v", r)
+	}
+	lr := r.toLongRange()
+	counter := newLongRangeCounter([]*LongRange{lr})
+	for _, v := range []float64{0.5, 1, 1.25, 1.75, 2, -3} {
+		counter.add(doubleBitsToSortable(int64(math.Float64bits(v))))
+	}
+	counts := make([]int, 1)
+	if missing := counter.fillCounts(counts); missing != 4 || counts[0] != 2 {
+		t.Errorf("Expected 2 hits and 4 missing, but was %v and %v", counts[0], missing)
+	}
+}
+
+func TestLongRangeCounterFillCountsTwice(t *testing.T) {
+	counter := newLongRangeCounter([]*LongRange{NewLongRange("0-9", 0, true, 9, true)})
+	for v := int64(0); v < 20; v++ {
+		counter.add(v)
+	}
+	for i := 0; i < 2; i++ {
+		counts := make([]int, 1)
+		if missing := counter.fillCounts(counts); missing != 10 || counts[0] != 10 {
+			t.Errorf("#%v: expected 10 hits and 10 missing, but was %v and %v", i, counts[0], missing)
+		}
+	}
+}
+
+func TestRangeFacetCountsSpecificValue(t *testing.T) {
+	c := newRangeFacetCounts("field", []Range{NewLongRange("all", 0, true, 10, true)})
+	if _, err := c.SpecificValue("field"); err == nil {
+		t.Error("Expected an error from SpecificValue")
+	}
+}
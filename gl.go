@@ -14,9 +14,6 @@ import (
 
 func main() {
 	util.SetDefaultInfoStream(util.NewPrintStreamInfoStream(os.Stdout))
-	index.DefaultSimilarity = func() index.Similarity {
-		return search.NewDefaultSimilarity()
-	}
 
 	directory, _ := store.OpenFSDirectory("test_index")
 	analyzer := std.NewStandardAnalyzer()
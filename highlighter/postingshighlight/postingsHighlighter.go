@@ -0,0 +1,156 @@
+package postingshighlight
+
+import (
+	"fmt"
+	"github.com/balzaczyy/golucene/core/index"
+	. "github.com/balzaczyy/golucene/core/index/model"
+	"github.com/balzaczyy/golucene/core/search"
+	"github.com/balzaczyy/golucene/highlighter/uhighlight"
+)
+
+// postingshighlight/PostingsHighlighter.java
+
+/*
+Simple highlighter that does not analyze fields nor use term
+vectors. Instead it requires INDEX_OPT_DOCS_AND_FREQS_AND_POSITIONS_AND_OFFSETS,
+and reads the offsets of the query terms directly from the postings.
+This avoids both the cost of re-analyzing the text and the storage
+overhead of term vectors.
+
+Passages are found, scored and formatted the same way as the
+UnifiedHighlighter does, whose BreakIterator, PassageScorer and
+PassageFormatter can be plugged in here.
+
+The field being highlighted must be stored.
+*/
+type PostingsHighlighter struct {
+	maxLength     int
+	breakIterator func() uhighlight.BreakIterator
+	scorer        *uhighlight.PassageScorer
+	formatter     uhighlight.PassageFormatter
+}
+
+/*
+Default maximum content size to process. Typically snippets closer
+to the beginning of the document better summarize its content.
+*/
+const DEFAULT_MAX_LENGTH = uhighlight.DEFAULT_MAX_LENGTH
+
+// Creates a new highlighter with DEFAULT_MAX_LENGTH.
+func NewPostingsHighlighter() *PostingsHighlighter {
+	return NewPostingsHighlighterWithMaxLength(DEFAULT_MAX_LENGTH)
+}
+
+/*
+Creates a new highlighter, specifying maximum content length in
+runes; text beyond it is neither highlighted nor summarized.
+*/
+func NewPostingsHighlighterWithMaxLength(maxLength int) *PostingsHighlighter {
+	assert2(maxLength >= 0, "maxLength must be >= 0")
+	return &PostingsHighlighter{
+		maxLength: maxLength,
+		breakIterator: func() uhighlight.BreakIterator {
+			return uhighlight.NewSentenceBreakIterator()
+		},
+		scorer:    uhighlight.NewPassageScorer(),
+		formatter: uhighlight.NewDefaultPassageFormatter(),
+	}
+}
+
+// Sets the factory of the BreakIterator used to find passages.
+func (h *PostingsHighlighter) SetBreakIterator(f func() uhighlight.BreakIterator) {
+	h.breakIterator = f
+}
+
+// Sets the PassageScorer used to rank passages.
+func (h *PostingsHighlighter) SetScorer(scorer *uhighlight.PassageScorer) {
+	h.scorer = scorer
+}
+
+// Sets the PassageFormatter used to build snippets.
+func (h *PostingsHighlighter) SetFormatter(formatter uhighlight.PassageFormatter) {
+	h.formatter = formatter
+}
+
+/*
+Highlights the top passage from a single field. Returns one snippet
+per document in topDocs, or "" if the document has nothing to show.
+*/
+func (h *PostingsHighlighter) Highlight(field string, query search.Query,
+	searcher *search.IndexSearcher, topDocs search.TopDocs) ([]string, error) {
+
+	return h.HighlightN(field, query, searcher, topDocs, 1)
+}
+
+/*
+Highlights the top-N passages from a single field. Returns an error
+if the field was indexed without offsets.
+*/
+func (h *PostingsHighlighter) HighlightN(field string, query search.Query,
+	searcher *search.IndexSearcher, topDocs search.TopDocs, maxPassages int) ([]string, error) {
+
+	res, err := h.HighlightFields([]string{field}, query, searcher, topDocs, maxPassages)
+	if err != nil {
+		return nil, err
+	}
+	return res[field], nil
+}
+
+/*
+Highlights the top-N passages from multiple fields. Returns a map
+from field name to the snippets of each document in topDocs.
+*/
+func (h *PostingsHighlighter) HighlightFields(fields []string, query search.Query,
+	searcher *search.IndexSearcher, topDocs search.TopDocs, maxPassages int) (map[string][]string, error) {
+
+	docIDs := make([]int, len(topDocs.ScoreDocs))
+	for i, sd := range topDocs.ScoreDocs {
+		docIDs[i] = sd.Doc
+	}
+	maxPassagesPerField := make([]int, len(fields))
+	for i := range maxPassagesPerField {
+		maxPassagesPerField[i] = maxPassages
+	}
+	return h.HighlightFieldsForDocs(fields, query, searcher, docIDs, maxPassagesPerField)
+}
+
+/*
+Highlights the top-N passages from multiple fields, for the provided
+docIDs. Returns a map from field name to the snippets of each
+document, in the order of docIDs.
+*/
+func (h *PostingsHighlighter) HighlightFieldsForDocs(fields []string, query search.Query,
+	searcher *search.IndexSearcher, docIDs []int, maxPassagesPerField []int) (map[string][]string, error) {
+
+	leaves := searcher.TopReaderContext().Reader().Leaves()
+	for _, field := range fields {
+		if err := checkOffsets(field, leaves); err != nil {
+			return nil, err
+		}
+	}
+
+	uh := uhighlight.NewUnifiedHighlighter(searcher, nil)
+	uh.SetMaxLength(h.maxLength)
+	uh.SetBreakIterator(h.breakIterator)
+	uh.SetScorer(h.scorer)
+	uh.SetFormatter(h.formatter)
+	uh.SetOffsetSource(uhighlight.OFFSET_SOURCE_POSTINGS)
+	return uh.HighlightFields(fields, query, docIDs, maxPassagesPerField)
+}
+
+func checkOffsets(field string, leaves []*index.AtomicReaderContext) error {
+	for _, leaf := range leaves {
+		fi := leaf.Reader().(index.AtomicReader).FieldInfos().FieldInfoByName(field)
+		if fi != nil && fi.IsIndexed() &&
+			fi.IndexOptions() < INDEX_OPT_DOCS_AND_FREQS_AND_POSITIONS_AND_OFFSETS {
+			return fmt.Errorf("field '%v' was indexed without offsets, cannot highlight", field)
+		}
+	}
+	return nil
+}
+
+func assert2(ok bool, msg string, args ...interface{}) {
+	if !ok {
+		panic(fmt.Sprintf(msg, args...))
+	}
+}
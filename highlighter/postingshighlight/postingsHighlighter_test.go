@@ -0,0 +1,84 @@
+package postingshighlight
+
+import (
+	std "github.com/balzaczyy/golucene/analysis/standard"
+	_ "github.com/balzaczyy/golucene/core/codec/lucene410"
+	docu "github.com/balzaczyy/golucene/core/document"
+	"github.com/balzaczyy/golucene/core/index"
+	. "github.com/balzaczyy/golucene/core/index/model"
+	"github.com/balzaczyy/golucene/core/search"
+	"github.com/balzaczyy/golucene/core/store"
+	"github.com/balzaczyy/golucene/core/util"
+	"testing"
+)
+
+// one sentence per line, as StandardTokenizer can't handle full stops
+// yet
+const text = "This is a test\nJust a test highlighting from postings\n" +
+	"Highlighting the first term\nHope it works"
+
+func newSearcher(t *testing.T, indexOptions IndexOptions) *search.IndexSearcher {
+	index.DefaultSimilarity = func() index.Similarity {
+		return search.NewDefaultSimilarity()
+	}
+	dir, err := store.OpenFSDirectory(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	conf := index.NewIndexWriterConfig(util.VERSION_LATEST, std.NewStandardAnalyzer())
+	w, err := index.NewIndexWriter(dir, conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ft := docu.NewFieldTypeFrom(docu.TEXT_FIELD_TYPE_STORED)
+	ft.SetIndexOptions(indexOptions)
+	d := docu.NewDocument()
+	d.Add(docu.NewFieldFromString("body", text, ft))
+	if err = w.AddDocument(d.Fields()); err != nil {
+		t.Fatal(err)
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	r, err := index.OpenDirectoryReader(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return search.NewIndexSearcher(r)
+}
+
+func TestHighlightFromPostings(t *testing.T) {
+	searcher := newSearcher(t, INDEX_OPT_DOCS_AND_FREQS_AND_POSITIONS_AND_OFFSETS)
+	q := search.NewBooleanQuery()
+	q.Add(search.NewTermQuery(index.NewTerm("body", "highlighting")), search.SHOULD)
+	q.Add(search.NewTermQuery(index.NewTerm("body", "test")), search.SHOULD)
+	topDocs, err := searcher.Search(q, nil, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(topDocs.ScoreDocs) != 1 {
+		t.Fatalf("expected 1 hit, but was %v", len(topDocs.ScoreDocs))
+	}
+
+	h := NewPostingsHighlighter()
+	res, err := h.HighlightN("body", q, searcher, topDocs, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "This is a <b>test</b>\nJust a <b>test</b> <b>highlighting</b> from postings\n"
+	if len(res) != 1 || res[0] != expected {
+		t.Errorf("expected %q, but was %q", expected, res)
+	}
+}
+
+func TestFieldWithoutOffsets(t *testing.T) {
+	searcher := newSearcher(t, INDEX_OPT_DOCS_AND_FREQS_AND_POSITIONS)
+	q := search.NewTermQuery(index.NewTerm("body", "test"))
+	topDocs, err := searcher.Search(q, nil, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = NewPostingsHighlighter().Highlight("body", q, searcher, topDocs); err == nil {
+		t.Error("expected an error for a field indexed without offsets")
+	}
+}
@@ -0,0 +1,105 @@
+package uhighlight
+
+import (
+	"sort"
+	"unicode"
+)
+
+/*
+Locates boundaries in text, in the spirit of java.text.BreakIterator.
+Offsets are rune offsets into the text; 0 and len(text) are always
+boundaries.
+*/
+type BreakIterator interface {
+	SetText(text []rune)
+	// Returns the last boundary which is less than the offset, or 0.
+	Preceding(offset int) int
+	// Returns the first boundary greater than the offset, or len(text).
+	Following(offset int) int
+}
+
+/*
+A BreakIterator over precomputed boundaries, which must be sorted
+and include 0 and len(text).
+*/
+type boundaries []int
+
+func (b boundaries) Preceding(offset int) int {
+	i := sort.SearchInts(b, offset)
+	if i == 0 {
+		return 0
+	}
+	return b[i-1]
+}
+
+func (b boundaries) Following(offset int) int {
+	i := sort.SearchInts(b, offset+1)
+	if i == len(b) {
+		return b[len(b)-1]
+	}
+	return b[i]
+}
+
+/*
+Breaks text into sentences. A sentence ends after terminal
+punctuation ('.', '!' or '?', optionally followed by closing quotes or
+brackets) plus the whitespace after it, or after a line break.
+*/
+type SentenceBreakIterator struct {
+	boundaries
+}
+
+func NewSentenceBreakIterator() *SentenceBreakIterator {
+	return new(SentenceBreakIterator)
+}
+
+func (it *SentenceBreakIterator) SetText(text []rune) {
+	it.boundaries = boundaries{0}
+	for i := 0; i < len(text); i++ {
+		var j int
+		switch text[i] {
+		case '.', '!', '?':
+			for j = i + 1; j < len(text) && isClosing(text[j]); j++ {
+			}
+			if j < len(text) && !unicode.IsSpace(text[j]) {
+				continue // e.g. "3.14" or "e.g."
+			}
+		case '\n', '\r', '\u2028', '\u2029':
+			j = i + 1
+		default:
+			continue
+		}
+		for ; j < len(text) && unicode.IsSpace(text[j]); j++ {
+		}
+		if j < len(text) {
+			it.boundaries = append(it.boundaries, j)
+		}
+		i = j - 1
+	}
+	if len(text) > 0 {
+		it.boundaries = append(it.boundaries, len(text))
+	}
+}
+
+func isClosing(ch rune) bool {
+	switch ch {
+	case '"', '\'', ')', ']', '}', '”', '’':
+		return true
+	}
+	return false
+}
+
+// uhighlight/WholeBreakIterator.java
+
+// Just produces one single fragment for the entire text.
+type WholeBreakIterator struct {
+	boundaries
+}
+
+func NewWholeBreakIterator() *WholeBreakIterator {
+	return new(WholeBreakIterator)
+}
+
+func (it *WholeBreakIterator) SetText(text []rune) {
+	it.boundaries = boundaries{0, len(text)}
+}
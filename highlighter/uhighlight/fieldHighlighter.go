@@ -0,0 +1,168 @@
+package uhighlight
+
+import (
+	"github.com/balzaczyy/golucene/core/index"
+	"sort"
+)
+
+// uhighlight/FieldHighlighter.java
+
+/*
+Internal highlighter abstraction that operates on a per field basis.
+*/
+type fieldHighlighter struct {
+	field                  string
+	strategy               fieldOffsetStrategy
+	phraseHelper           *phraseHelper
+	breakIterator          BreakIterator
+	scorer                 *PassageScorer
+	formatter              PassageFormatter
+	maxPassages            int
+	maxNoHighlightPassages int
+	maxLength              int
+}
+
+/*
+The primary method -- highlight this doc, assuming a specific field
+and given this content. docID is relative to reader.
+*/
+func (fh *fieldHighlighter) highlightFieldForDoc(reader index.AtomicReader,
+	docID int, content string) (string, error) {
+
+	// TODO accept LeafReader instead?
+	// note: it'd be nice to accept a CharSequence for content, but we
+	// need a CharacterIterator impl for it.
+	if len(content) == 0 {
+		return "", nil // nothing to do
+	}
+	runes := []rune(content)
+	if len(runes) > fh.maxLength {
+		runes = runes[:fh.maxLength]
+	}
+
+	fh.breakIterator.SetText(runes)
+
+	enums, err := fh.strategy.offsetsEnums(reader, docID, string(runes))
+	if err != nil {
+		return "", err
+	}
+	enums = fh.phraseHelper.filter(enums)
+
+	passages := fh.highlightOffsetsEnums(enums, len(runes))
+	if len(passages) == 0 {
+		// no passages were returned, so ask for a default summary
+		passages = fh.summaryPassagesNoHighlight(len(runes))
+	}
+	if len(passages) == 0 {
+		return "", nil
+	}
+	return fh.formatter.Format(passages, runes), nil
+}
+
+/*
+Called to summarize a document when no highlights were found. By
+default this just returns the first maxNoHighlightPassages passages
+from the document.
+*/
+func (fh *fieldHighlighter) summaryPassagesNoHighlight(contentLength int) []*Passage {
+	var ans []*Passage
+	for pos := 0; len(ans) < fh.maxNoHighlightPassages && pos < contentLength; {
+		next := fh.breakIterator.Following(pos)
+		ans = append(ans, newPassage(pos, next))
+		pos = next
+	}
+	return ans
+}
+
+type match struct {
+	start, end int
+	term       string
+}
+
+type matchesByOffset []*match
+
+func (s matchesByOffset) Len() int      { return len(s) }
+func (s matchesByOffset) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s matchesByOffset) Less(i, j int) bool {
+	if s[i].start == s[j].start {
+		return s[i].end < s[j].end
+	}
+	return s[i].start < s[j].start
+}
+
+/*
+Coalesces the matches, in offset order, into passages delimited by
+the break iterator, and returns the top passages ordered by start
+offset.
+*/
+func (fh *fieldHighlighter) highlightOffsetsEnums(enums []*offsetsEnum, contentLength int) []*Passage {
+	weights := make(map[string]float32)
+	var matches []*match
+	for _, oe := range enums {
+		weights[oe.term] = fh.scorer.Weight(contentLength, oe.freq())
+		for i, start := range oe.starts {
+			matches = append(matches, &match{start, oe.ends[i], oe.term})
+		}
+	}
+	sort.Sort(matchesByOffset(matches))
+
+	var passages []*Passage
+	var passage *Passage
+	for _, m := range matches {
+		if m.start >= contentLength {
+			break // beyond maxLength
+		}
+		end := m.end
+		if end > contentLength {
+			end = contentLength
+		}
+		if passage == nil || m.start >= passage.EndOffset {
+			if passage != nil {
+				passages = append(passages, fh.score(passage, weights))
+			}
+			// advance breakIterator
+			passage = newPassage(fh.breakIterator.Preceding(m.start+1),
+				fh.breakIterator.Following(m.start))
+		}
+		if end > passage.EndOffset {
+			// a "term" from the analyzer could span a passage boundary
+			passage.EndOffset = fh.breakIterator.Following(end - 1)
+		}
+		passage.addMatch(m.start, end, m.term)
+	}
+	if passage != nil {
+		passages = append(passages, fh.score(passage, weights))
+	}
+
+	sort.Stable(passagesByScore(passages))
+	if len(passages) > fh.maxPassages {
+		passages = passages[:fh.maxPassages]
+	}
+	sort.Sort(passagesByOffset(passages))
+	return passages
+}
+
+func (fh *fieldHighlighter) score(passage *Passage, weights map[string]float32) *Passage {
+	freqs := make(map[string]int)
+	for _, term := range passage.MatchTerms {
+		freqs[term]++
+	}
+	var score float32
+	for term, freq := range freqs {
+		score += weights[term] * fh.scorer.Tf(freq, passage.Length())
+	}
+	passage.Score = score * fh.scorer.Norm(passage.StartOffset)
+	return passage
+}
+
+type passagesByScore []*Passage
+
+func (s passagesByScore) Len() int           { return len(s) }
+func (s passagesByScore) Less(i, j int) bool { return s[i].Score > s[j].Score }
+func (s passagesByScore) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+type passagesByOffset []*Passage
+
+func (s passagesByOffset) Len() int           { return len(s) }
+func (s passagesByOffset) Less(i, j int) bool { return s[i].StartOffset < s[j].StartOffset }
+func (s passagesByOffset) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
@@ -0,0 +1,248 @@
+package uhighlight
+
+import (
+	"github.com/balzaczyy/golucene/core/analysis"
+	ta "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"github.com/balzaczyy/golucene/core/index"
+	. "github.com/balzaczyy/golucene/core/index/model"
+)
+
+// uhighlight/UnifiedHighlighter.java#OffsetSource
+
+// Source of term offsets; essential for highlighting.
+type OffsetSource int
+
+const (
+	// Determine the source of offsets per field from the index.
+	OFFSET_SOURCE_AUTO = OffsetSource(iota)
+	// Read offsets indexed into the postings.
+	OFFSET_SOURCE_POSTINGS
+	// Read offsets stored in term vectors.
+	OFFSET_SOURCE_TERM_VECTORS
+	// Re-analyze the stored field content.
+	OFFSET_SOURCE_ANALYSIS
+	// The query has no terms in the field; nothing to highlight.
+	OFFSET_SOURCE_NONE_NEEDED
+)
+
+func (s OffsetSource) String() string {
+	switch s {
+	case OFFSET_SOURCE_AUTO:
+		return "AUTO"
+	case OFFSET_SOURCE_POSTINGS:
+		return "POSTINGS"
+	case OFFSET_SOURCE_TERM_VECTORS:
+		return "TERM_VECTORS"
+	case OFFSET_SOURCE_ANALYSIS:
+		return "ANALYSIS"
+	case OFFSET_SOURCE_NONE_NEEDED:
+		return "NONE_NEEDED"
+	}
+	panic("should not be here")
+}
+
+// uhighlight/OffsetsEnum.java
+
+/*
+The occurrences of one query term in the document, in increasing
+order of start offset. Positions are -1 when unknown.
+*/
+type offsetsEnum struct {
+	term      string
+	starts    []int
+	ends      []int
+	positions []int
+}
+
+func (e *offsetsEnum) add(start, end, position int) {
+	e.starts = append(e.starts, start)
+	e.ends = append(e.ends, end)
+	e.positions = append(e.positions, position)
+}
+
+func (e *offsetsEnum) freq() int {
+	return len(e.starts)
+}
+
+// uhighlight/FieldOffsetStrategy.java
+
+/*
+Ultimately returns the offsets of the query terms in a document of a
+field, each strategy sourcing them from a different place.
+*/
+type fieldOffsetStrategy interface {
+	offsetSource() OffsetSource
+	/*
+		Returns the offsets of each query term found in the document.
+		docID is relative to the leaf reader; content is the field's
+		text.
+	*/
+	offsetsEnums(reader index.AtomicReader, docID int, content string) ([]*offsetsEnum, error)
+}
+
+// uhighlight/NoOpOffsetStrategy.java
+
+type noOpOffsetStrategy struct{}
+
+func (s noOpOffsetStrategy) offsetSource() OffsetSource {
+	return OFFSET_SOURCE_NONE_NEEDED
+}
+
+func (s noOpOffsetStrategy) offsetsEnums(reader index.AtomicReader, docID int, content string) ([]*offsetsEnum, error) {
+	return nil, nil
+}
+
+// uhighlight/PostingsOffsetStrategy.java
+
+/* Uses offsets in postings. */
+type postingsOffsetStrategy struct {
+	field string
+	terms [][]byte
+}
+
+func (s *postingsOffsetStrategy) offsetSource() OffsetSource {
+	return OFFSET_SOURCE_POSTINGS
+}
+
+func (s *postingsOffsetStrategy) offsetsEnums(reader index.AtomicReader, docID int, content string) ([]*offsetsEnum, error) {
+	return termOffsetsEnums(reader.Terms(s.field), s.terms, docID)
+}
+
+// uhighlight/TermVectorOffsetStrategy.java
+
+/* Uses term vectors that contain offsets. */
+type termVectorOffsetStrategy struct {
+	field string
+	terms [][]byte
+}
+
+func (s *termVectorOffsetStrategy) offsetSource() OffsetSource {
+	return OFFSET_SOURCE_TERM_VECTORS
+}
+
+func (s *termVectorOffsetStrategy) offsetsEnums(reader index.AtomicReader, docID int, content string) ([]*offsetsEnum, error) {
+	tvFields, err := reader.TermVectors(docID)
+	if err != nil || tvFields == nil {
+		return nil, err
+	}
+	// term vectors are a single-document inverted index
+	return termOffsetsEnums(tvFields.Terms(s.field), s.terms, 0)
+}
+
+/*
+Reads the offsets of the given terms in the document from an inverted
+index, which has to have offsets indexed.
+*/
+func termOffsetsEnums(terms Terms, queryTerms [][]byte, docID int) ([]*offsetsEnum, error) {
+	if terms == nil {
+		return nil, nil
+	}
+	var ans []*offsetsEnum
+	var dpe DocsAndPositionsEnum
+	te := terms.Iterator(nil)
+	for _, term := range queryTerms {
+		ok, err := te.SeekExact(term)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		if dpe, err = te.DocsAndPositionsByFlags(nil, dpe, DOCS_POSITIONS_ENUM_FLAG_OFF_SETS); err != nil {
+			return nil, err
+		}
+		if dpe == nil {
+			// no positions available
+			continue
+		}
+		doc, err := dpe.Advance(docID)
+		if err != nil {
+			return nil, err
+		}
+		if doc != docID {
+			continue
+		}
+		freq, err := dpe.Freq()
+		if err != nil {
+			return nil, err
+		}
+		oe := &offsetsEnum{term: string(term)}
+		for i := 0; i < freq; i++ {
+			pos, err := dpe.NextPosition()
+			if err != nil {
+				return nil, err
+			}
+			start, err := dpe.StartOffset()
+			if err != nil {
+				return nil, err
+			}
+			end, err := dpe.EndOffset()
+			if err != nil {
+				return nil, err
+			}
+			oe.add(start, end, pos)
+		}
+		ans = append(ans, oe)
+	}
+	return ans, nil
+}
+
+// uhighlight/AnalysisOffsetStrategy.java
+
+/*
+Provides a base class for analysis based offset strategies to extend
+from. Re-analyzes the content of the field and keeps the tokens which
+are query terms.
+*/
+type analysisOffsetStrategy struct {
+	field    string
+	terms    map[string]bool
+	analyzer analysis.Analyzer
+}
+
+func (s *analysisOffsetStrategy) offsetSource() OffsetSource {
+	return OFFSET_SOURCE_ANALYSIS
+}
+
+func (s *analysisOffsetStrategy) offsetsEnums(reader index.AtomicReader, docID int, content string) (ans []*offsetsEnum, err error) {
+	ts, err := s.analyzer.TokenStreamForString(s.field, content)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if e := ts.Close(); err == nil {
+			err = e
+		}
+	}()
+	termAtt := ts.Attributes().Add("CharTermAttribute").(ta.CharTermAttribute)
+	offsetAtt := ts.Attributes().Add("OffsetAttribute").(ta.OffsetAttribute)
+	posIncAtt := ts.Attributes().Add("PositionIncrementAttribute").(ta.PositionIncrementAttribute)
+
+	if err = ts.Reset(); err != nil {
+		return nil, err
+	}
+	byTerm := make(map[string]*offsetsEnum)
+	position := -1
+	for {
+		ok, err := ts.IncrementToken()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			break
+		}
+		position += posIncAtt.PositionIncrement()
+		term := string(termAtt.Buffer()[:termAtt.Length()])
+		if !s.terms[term] {
+			continue
+		}
+		oe, ok := byTerm[term]
+		if !ok {
+			oe = &offsetsEnum{term: term}
+			byTerm[term] = oe
+			ans = append(ans, oe)
+		}
+		oe.add(offsetAtt.StartOffset(), offsetAtt.EndOffset(), position)
+	}
+	return ans, ts.End()
+}
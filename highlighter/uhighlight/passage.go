@@ -0,0 +1,56 @@
+package uhighlight
+
+import (
+	"fmt"
+)
+
+// uhighlight/Passage.java
+
+/*
+Represents a passage (typically a sentence of the document).
+
+A passage contains NumMatches() highlights from the query, and the
+offsets and query terms that correspond with each match. Offsets are
+rune offsets into the field's content.
+*/
+type Passage struct {
+	StartOffset int
+	EndOffset   int
+	Score       float32
+
+	MatchStarts []int
+	MatchEnds   []int
+	MatchTerms  []string
+}
+
+func newPassage(startOffset, endOffset int) *Passage {
+	return &Passage{StartOffset: startOffset, EndOffset: endOffset}
+}
+
+func (p *Passage) addMatch(startOffset, endOffset int, term string) {
+	assert2(startOffset >= p.StartOffset && startOffset <= p.EndOffset,
+		"match start %v is outside of passage [%v, %v)", startOffset, p.StartOffset, p.EndOffset)
+	p.MatchStarts = append(p.MatchStarts, startOffset)
+	p.MatchEnds = append(p.MatchEnds, endOffset)
+	p.MatchTerms = append(p.MatchTerms, term)
+}
+
+// Number of term matches available in MatchStarts, MatchEnds and MatchTerms.
+func (p *Passage) NumMatches() int {
+	return len(p.MatchStarts)
+}
+
+// Length of the passage, in runes.
+func (p *Passage) Length() int {
+	return p.EndOffset - p.StartOffset
+}
+
+func (p *Passage) String() string {
+	return fmt.Sprintf("[%v-%v]%v", p.StartOffset, p.EndOffset, p.MatchTerms)
+}
+
+func assert2(ok bool, msg string, args ...interface{}) {
+	if !ok {
+		panic(fmt.Sprintf(msg, args...))
+	}
+}
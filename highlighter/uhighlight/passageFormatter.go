@@ -0,0 +1,114 @@
+package uhighlight
+
+import (
+	"bytes"
+)
+
+// uhighlight/PassageFormatter.java
+
+/*
+Creates a formatted snippet from the top passages.
+*/
+type PassageFormatter interface {
+	/*
+		Formats the top passages from content into a human-readable
+		text snippet.
+
+		passages are the top-N passages for the field, sorted in
+		increasing order by start offset; content is the original text
+		of the field.
+	*/
+	Format(passages []*Passage, content []rune) string
+}
+
+// uhighlight/DefaultPassageFormatter.java
+
+/*
+Creates a formatted snippet from the top passages.
+
+The default implementation marks the query terms as bold, and places
+ellipses between unconnected passages.
+*/
+type DefaultPassageFormatter struct {
+	// text that will appear before highlighted terms
+	preTag string
+	// text that will appear after highlighted terms
+	postTag string
+	// text that will appear between two unconnected passages
+	ellipsis string
+	// true if we should escape for html
+	escape bool
+}
+
+// Creates a new DefaultPassageFormatter with the default tags.
+func NewDefaultPassageFormatter() *DefaultPassageFormatter {
+	return NewDefaultPassageFormatterWith("<b>", "</b>", "... ", false)
+}
+
+// Creates a new DefaultPassageFormatter with custom tags.
+func NewDefaultPassageFormatterWith(preTag, postTag, ellipsis string, escape bool) *DefaultPassageFormatter {
+	return &DefaultPassageFormatter{preTag, postTag, ellipsis, escape}
+}
+
+func (f *DefaultPassageFormatter) Format(passages []*Passage, content []rune) string {
+	var buf bytes.Buffer
+	pos := 0
+	for _, passage := range passages {
+		// don't add ellipsis if its the first one, or if its connected.
+		if passage.StartOffset > pos && pos > 0 {
+			buf.WriteString(f.ellipsis)
+		}
+		pos = passage.StartOffset
+		for i, start := range passage.MatchStarts {
+			end := passage.MatchEnds[i]
+			// its possible to have overlapping terms
+			if start > pos {
+				f.append(&buf, content, pos, start)
+			}
+			if end > pos {
+				buf.WriteString(f.preTag)
+				if start > pos {
+					pos = start
+				}
+				f.append(&buf, content, pos, end)
+				buf.WriteString(f.postTag)
+				pos = end
+			}
+		}
+		// its possible a "term" from the analyzer could span a sentence
+		// boundary.
+		if passage.EndOffset > pos {
+			f.append(&buf, content, pos, passage.EndOffset)
+			pos = passage.EndOffset
+		}
+	}
+	return buf.String()
+}
+
+/*
+Appends original text to the response.
+*/
+func (f *DefaultPassageFormatter) append(dest *bytes.Buffer, content []rune, start, end int) {
+	if !f.escape {
+		dest.WriteString(string(content[start:end]))
+		return
+	}
+	for _, ch := range content[start:end] {
+		switch ch {
+		case '&':
+			dest.WriteString("&amp;")
+		case '<':
+			dest.WriteString("&lt;")
+		case '>':
+			dest.WriteString("&gt;")
+		case '"':
+			dest.WriteString("&quot;")
+		case '\'':
+			dest.WriteString("&#x27;")
+		case '/':
+			dest.WriteString("&#x2F;")
+		default:
+			dest.WriteRune(ch)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package uhighlight
+
+import (
+	"math"
+)
+
+// uhighlight/PassageScorer.java
+
+/*
+Ranks passages found by UnifiedHighlighter.
+
+Each passage is scored as a miniature document within the document.
+The final score is computed as norm * ∑ (weight * tf). The default
+implementation is norm * BM25.
+*/
+type PassageScorer struct {
+	// TODO: this formula is completely made up. It might not provide
+	// relevant snippets!
+
+	// BM25 k1 parameter, controls term frequency normalization
+	k1 float32
+	// BM25 b parameter, controls length normalization.
+	b float32
+	// A pivot used for length normalization.
+	pivot float32
+}
+
+/*
+Creates PassageScorer with these default values:
+
+	- k1 = 1.2,
+	- b = 0.75.
+	- pivot = 87
+*/
+func NewPassageScorer() *PassageScorer {
+	// 1.2 and 0.75 are well-known bm25 defaults (but maybe not the
+	// best here). 87 is typical average english sentence length.
+	return NewPassageScorerWith(1.2, 0.75, 87)
+}
+
+func NewPassageScorerWith(k1, b, pivot float32) *PassageScorer {
+	return &PassageScorer{k1, b, pivot}
+}
+
+/*
+Computes term importance, given its in-document statistics.
+
+contentLength is the length of the document in runes, and
+totalTermFreq the number of times the term appears in the document.
+*/
+func (s *PassageScorer) Weight(contentLength, totalTermFreq int) float32 {
+	// approximate #docs from content length
+	numDocs := 1 + float64(contentLength)/float64(s.pivot)
+	// numDocs not numDocs - docFreq (ala DFR), since we approximate
+	// numDocs
+	return (s.k1 + 1) * float32(math.Log(1+(numDocs+0.5)/(float64(totalTermFreq)+0.5)))
+}
+
+/*
+Computes term weight, given the frequency within the passage and the
+passage's length.
+*/
+func (s *PassageScorer) Tf(freq, passageLen int) float32 {
+	norm := s.k1 * ((1 - s.b) + s.b*(float32(passageLen)/s.pivot))
+	return float32(freq) / (float32(freq) + norm)
+}
+
+/*
+Normalize a passage according to its position in the document.
+
+Typically passages towards the beginning of the document are more
+useful for summarizing the contents.
+*/
+func (s *PassageScorer) Norm(passageStart int) float32 {
+	return 1 + 1/float32(math.Log(float64(s.pivot)+float64(passageStart)))
+}
@@ -0,0 +1,182 @@
+package uhighlight
+
+import (
+	"github.com/balzaczyy/golucene/core/index"
+	"github.com/balzaczyy/golucene/core/search"
+)
+
+/*
+Implemented by position-sensitive queries, e.g. phrases, to expose
+their terms and the relative position of each term. Terms of such
+queries are only highlighted where the whole query matches; with a
+slop, each term may be up to slop positions away from where the
+exact phrase would have it.
+*/
+type PositionSensitiveQuery interface {
+	search.Query
+	Terms() []*index.Term
+	Positions() []int
+	Slop() int
+}
+
+// uhighlight/PhraseHelper.java
+
+type phrase struct {
+	terms     []string
+	positions []int
+	slop      int
+}
+
+/*
+Helps the FieldOffsetStrategy with strict position highlighting
+(e.g. highlight phrases correctly). Terms which are only queried as
+part of a phrase are dropped unless the phrase matches at their
+position.
+*/
+type phraseHelper struct {
+	phrases []*phrase
+	// terms which are queried regardless of their position
+	freeTerms map[string]bool
+}
+
+/*
+Walks the query, collecting the phrases for the field and the terms
+of every other query into terms.
+*/
+func newPhraseHelper(field string, query search.Query, terms *index.TermSet) *phraseHelper {
+	h := &phraseHelper{freeTerms: make(map[string]bool)}
+	h.collect(field, query, terms)
+	return h
+}
+
+func (h *phraseHelper) collect(field string, query search.Query, terms *index.TermSet) {
+	switch q := query.(type) {
+	case *search.BooleanQuery:
+		for _, c := range q.Clauses() {
+			if !c.IsProhibited() {
+				h.collect(field, c.Query(), terms)
+			}
+		}
+	case PositionSensitiveQuery:
+		p := &phrase{slop: q.Slop()}
+		for i, t := range q.Terms() {
+			if t.Field == field {
+				p.terms = append(p.terms, string(t.Bytes))
+				p.positions = append(p.positions, q.Positions()[i])
+			}
+			terms.Add(t)
+		}
+		if len(p.terms) > 0 {
+			h.phrases = append(h.phrases, p)
+		}
+	default:
+		free := index.NewTermSet()
+		q.ExtractTerms(free)
+		for _, t := range free.Terms {
+			if t.Field == field {
+				h.freeTerms[string(t.Bytes)] = true
+			}
+			terms.Add(t)
+		}
+	}
+}
+
+// Drops the occurrences of phrase terms where their phrase does not match.
+func (h *phraseHelper) filter(enums []*offsetsEnum) []*offsetsEnum {
+	if len(h.phrases) == 0 {
+		return enums
+	}
+	byTerm := make(map[string]*offsetsEnum)
+	for _, oe := range enums {
+		byTerm[oe.term] = oe
+	}
+	free := make(map[string]bool)
+	for t, _ := range h.freeTerms {
+		free[t] = true
+	}
+	allowed := make(map[string]map[int]bool)
+	for _, p := range h.phrases {
+		h.matchPhrase(p, byTerm, free, allowed)
+	}
+
+	var ans []*offsetsEnum
+	for _, oe := range enums {
+		if free[oe.term] {
+			ans = append(ans, oe)
+			continue
+		}
+		filtered := &offsetsEnum{term: oe.term}
+		for i, pos := range oe.positions {
+			if allowed[oe.term][pos] {
+				filtered.add(oe.starts[i], oe.ends[i], pos)
+			}
+		}
+		if filtered.freq() > 0 {
+			ans = append(ans, filtered)
+		}
+	}
+	return ans
+}
+
+func (h *phraseHelper) matchPhrase(p *phrase, byTerm map[string]*offsetsEnum,
+	free map[string]bool, allowed map[string]map[int]bool) {
+
+	for _, t := range p.terms {
+		oe, ok := byTerm[t]
+		if !ok {
+			return // the phrase can't match
+		}
+		for _, pos := range oe.positions {
+			if pos == -1 {
+				// positions weren't indexed; fall back to highlighting the
+				// terms wherever they occur
+				for _, t := range p.terms {
+					free[t] = true
+				}
+				return
+			}
+		}
+	}
+
+	matched := make([]int, len(p.terms))
+	for _, x := range byTerm[p.terms[0]].positions {
+		start := x - p.positions[0]
+		ok := true
+		for i, t := range p.terms {
+			if matched[i], ok = closestPosition(byTerm[t].positions, start+p.positions[i], p.slop); !ok {
+				break
+			}
+		}
+		if !ok {
+			continue
+		}
+		for i, t := range p.terms {
+			if allowed[t] == nil {
+				allowed[t] = make(map[int]bool)
+			}
+			allowed[t][matched[i]] = true
+		}
+	}
+}
+
+// Returns the position closest to target, if it is within slop of it.
+func closestPosition(positions []int, target, slop int) (int, bool) {
+	best, found := 0, false
+	for _, pos := range positions {
+		d := pos - target
+		if d < 0 {
+			d = -d
+		}
+		if d <= slop && (!found || d < abs(best-target)) {
+			best, found = pos, true
+		}
+	}
+	return best, found
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
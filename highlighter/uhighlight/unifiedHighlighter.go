@@ -0,0 +1,270 @@
+package uhighlight
+
+import (
+	"github.com/balzaczyy/golucene/core/analysis"
+	"github.com/balzaczyy/golucene/core/index"
+	. "github.com/balzaczyy/golucene/core/index/model"
+	"github.com/balzaczyy/golucene/core/search"
+	"sort"
+)
+
+// uhighlight/UnifiedHighlighter.java
+
+/*
+A Highlighter that can get offsets from either postings, term
+vectors, or analysis (re-analyzing text).
+
+This highlighter treats the single original document as the whole
+corpus, and then scores individual passages as if they were
+documents in this corpus. It uses a BreakIterator to find passages
+in the text; by default it breaks using sentences. It then iterates
+in parallel (merge sorting by offset) through the positions of all
+terms from the query, coalescing those hits that occur in a single
+passage into a Passage, and then scores each Passage using a
+separate PassageScorer. Passages are finally formatted into
+highlighted snippets with a PassageFormatter.
+
+The offset source is determined per field (see OffsetSource):
+
+	- postings, if the field was indexed with
+	  INDEX_OPT_DOCS_AND_FREQS_AND_POSITIONS_AND_OFFSETS;
+	- term vectors, if the field has term vectors;
+	- otherwise the stored text is re-analyzed with the analyzer.
+
+Terms of position-sensitive queries (see PositionSensitiveQuery) are
+only highlighted where the query matches.
+
+The field being highlighted must be stored.
+*/
+type UnifiedHighlighter struct {
+	searcher               *search.IndexSearcher
+	analyzer               analysis.Analyzer
+	maxLength              int
+	breakIterator          func() BreakIterator
+	scorer                 *PassageScorer
+	formatter              PassageFormatter
+	offsetSource           OffsetSource
+	maxNoHighlightPassages int
+}
+
+/*
+Default maximum content size to process. Typically snippets closer
+to the beginning of the document better summarize its content.
+*/
+const DEFAULT_MAX_LENGTH = 10000
+
+/*
+Constructs the highlighter with the given index searcher and
+analyzer. The searcher may be nil if only HighlightWithoutSearcher()
+is used; the analyzer may be nil if no field needs re-analysis.
+*/
+func NewUnifiedHighlighter(searcher *search.IndexSearcher, analyzer analysis.Analyzer) *UnifiedHighlighter {
+	return &UnifiedHighlighter{
+		searcher:  searcher,
+		analyzer:  analyzer,
+		maxLength: DEFAULT_MAX_LENGTH,
+		breakIterator: func() BreakIterator {
+			return NewSentenceBreakIterator()
+		},
+		scorer:                 NewPassageScorer(),
+		formatter:              NewDefaultPassageFormatter(),
+		maxNoHighlightPassages: -1,
+	}
+}
+
+/*
+Sets the maximum number of runes to process of each field value;
+text beyond it is neither highlighted nor summarized.
+*/
+func (h *UnifiedHighlighter) SetMaxLength(maxLength int) {
+	assert2(maxLength >= 0, "maxLength must be >= 0")
+	h.maxLength = maxLength
+}
+
+// Sets the factory of the BreakIterator used to find passages.
+func (h *UnifiedHighlighter) SetBreakIterator(f func() BreakIterator) {
+	h.breakIterator = f
+}
+
+// Sets the PassageScorer used to rank passages.
+func (h *UnifiedHighlighter) SetScorer(scorer *PassageScorer) {
+	h.scorer = scorer
+}
+
+// Sets the PassageFormatter used to build snippets.
+func (h *UnifiedHighlighter) SetFormatter(formatter PassageFormatter) {
+	h.formatter = formatter
+}
+
+/*
+Forces the source of offsets for every field, instead of determining
+it from the index (OFFSET_SOURCE_AUTO).
+*/
+func (h *UnifiedHighlighter) SetOffsetSource(source OffsetSource) {
+	h.offsetSource = source
+}
+
+/*
+Sets the number of leading passages returned as a summary when a
+field has no highlights; -1 (the default) means as many as requested
+for highlighting, 0 means no summary at all.
+*/
+func (h *UnifiedHighlighter) SetMaxNoHighlightPassages(n int) {
+	h.maxNoHighlightPassages = n
+}
+
+/*
+Highlights the top passages from a single field. Returns one snippet
+per document in topDocs, or "" if the document has nothing to show.
+*/
+func (h *UnifiedHighlighter) Highlight(field string, query search.Query,
+	topDocs search.TopDocs, maxPassages int) ([]string, error) {
+
+	docIDs := make([]int, len(topDocs.ScoreDocs))
+	for i, sd := range topDocs.ScoreDocs {
+		docIDs[i] = sd.Doc
+	}
+	res, err := h.HighlightFields([]string{field}, query, docIDs, []int{maxPassages})
+	if err != nil {
+		return nil, err
+	}
+	return res[field], nil
+}
+
+/*
+Highlights the top-N passages from multiple fields, for the provided
+int[] docIDs. Returns a map from field name to the snippets of each
+document, in the order of docIDs.
+*/
+func (h *UnifiedHighlighter) HighlightFields(fields []string, query search.Query,
+	docIDs []int, maxPassagesPerField []int) (map[string][]string, error) {
+
+	assert2(len(fields) == len(maxPassagesPerField),
+		"invalid number of maxPassagesPerField (%v) for %v fields",
+		len(maxPassagesPerField), len(fields))
+	assert2(h.searcher != nil, "This method requires that a searcher was "+
+		"passed in the constructor. Perhaps you mean to call HighlightWithoutSearcher?")
+
+	query, err := h.searcher.Rewrite(query)
+	if err != nil {
+		return nil, err
+	}
+	reader := h.searcher.TopReaderContext().Reader()
+	leaves := reader.Leaves()
+
+	fhs := make([]*fieldHighlighter, len(fields))
+	ans := make(map[string][]string)
+	for i, field := range fields {
+		fhs[i] = h.fieldHighlighter(field, query, maxPassagesPerField[i], leaves)
+		ans[field] = make([]string, len(docIDs))
+	}
+
+	for j, docID := range docIDs {
+		doc, err := reader.Document(docID)
+		if err != nil {
+			return nil, err
+		}
+		leaf := leaves[index.SubIndex(docID, leaves)]
+		for i, field := range fields {
+			if ans[field][j], err = fhs[i].highlightFieldForDoc(
+				leaf.Reader().(index.AtomicReader), docID-leaf.DocBase, doc.Get(field)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return ans, nil
+}
+
+/*
+Highlights text which was not indexed, e.g. computed on the fly, by
+re-analyzing it. The query is used as is, so it must already be in
+its rewritten form.
+*/
+func (h *UnifiedHighlighter) HighlightWithoutSearcher(field string, query search.Query,
+	content string, maxPassages int) (string, error) {
+
+	assert2(h.offsetSource == OFFSET_SOURCE_AUTO || h.offsetSource == OFFSET_SOURCE_ANALYSIS,
+		"offset source %v is not available without a searcher", h.offsetSource)
+	fh := h.fieldHighlighter(field, query, maxPassages, nil)
+	return fh.highlightFieldForDoc(nil, -1, content)
+}
+
+func (h *UnifiedHighlighter) fieldHighlighter(field string, query search.Query,
+	maxPassages int, leaves []*index.AtomicReaderContext) *fieldHighlighter {
+
+	allTerms := index.NewTermSet()
+	ph := newPhraseHelper(field, query, allTerms)
+	var terms [][]byte
+	termSet := make(map[string]bool)
+	for _, t := range allTerms.Terms {
+		if t.Field == field {
+			terms = append(terms, t.Bytes)
+			termSet[string(t.Bytes)] = true
+		}
+	}
+	sort.Sort(bytesSlice(terms)) // seek the terms enum forward only
+
+	var strategy fieldOffsetStrategy
+	switch source := h.offsetSourceFor(field, len(terms) == 0, leaves); source {
+	case OFFSET_SOURCE_POSTINGS:
+		strategy = &postingsOffsetStrategy{field, terms}
+	case OFFSET_SOURCE_TERM_VECTORS:
+		strategy = &termVectorOffsetStrategy{field, terms}
+	case OFFSET_SOURCE_ANALYSIS:
+		assert2(h.analyzer != nil, "field '%v' must be re-analyzed, but no analyzer was given", field)
+		strategy = &analysisOffsetStrategy{field, termSet, h.analyzer}
+	default:
+		strategy = noOpOffsetStrategy{}
+	}
+
+	maxNoHighlightPassages := h.maxNoHighlightPassages
+	if maxNoHighlightPassages < 0 {
+		maxNoHighlightPassages = maxPassages
+	}
+	return &fieldHighlighter{
+		field:                  field,
+		strategy:               strategy,
+		phraseHelper:           ph,
+		breakIterator:          h.breakIterator(),
+		scorer:                 h.scorer,
+		formatter:              h.formatter,
+		maxPassages:            maxPassages,
+		maxNoHighlightPassages: maxNoHighlightPassages,
+		maxLength:              h.maxLength,
+	}
+}
+
+/*
+Determines the offset source for the field from the first segment
+that has it, preferring postings over term vectors over analysis.
+*/
+func (h *UnifiedHighlighter) offsetSourceFor(field string, noTerms bool,
+	leaves []*index.AtomicReaderContext) OffsetSource {
+
+	if noTerms {
+		return OFFSET_SOURCE_NONE_NEEDED
+	}
+	if h.offsetSource != OFFSET_SOURCE_AUTO {
+		return h.offsetSource
+	}
+	for _, leaf := range leaves {
+		fi := leaf.Reader().(index.AtomicReader).FieldInfos().FieldInfoByName(field)
+		if fi == nil {
+			continue
+		}
+		if fi.IndexOptions() >= INDEX_OPT_DOCS_AND_FREQS_AND_POSITIONS_AND_OFFSETS {
+			return OFFSET_SOURCE_POSTINGS
+		}
+		if fi.HasVectors() {
+			return OFFSET_SOURCE_TERM_VECTORS
+		}
+		break
+	}
+	return OFFSET_SOURCE_ANALYSIS
+}
+
+type bytesSlice [][]byte
+
+func (s bytesSlice) Len() int           { return len(s) }
+func (s bytesSlice) Less(i, j int) bool { return string(s[i]) < string(s[j]) }
+func (s bytesSlice) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
@@ -0,0 +1,102 @@
+package uhighlight
+
+import (
+	std "github.com/balzaczyy/golucene/analysis/standard"
+	"github.com/balzaczyy/golucene/core/index"
+	"github.com/balzaczyy/golucene/core/search"
+	"testing"
+)
+
+// one sentence per line, as StandardTokenizer can't handle full stops
+// yet
+const text = "This is a test\nJust a test highlighting from postings\n" +
+	"Highlighting the first term\nHope it works"
+
+func highlight(t *testing.T, q search.Query, maxPassages int) string {
+	h := NewUnifiedHighlighter(nil, std.NewStandardAnalyzer())
+	res, err := h.HighlightWithoutSearcher("body", q, text, maxPassages)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
+
+func TestHighlightTerm(t *testing.T) {
+	q := search.NewTermQuery(index.NewTerm("body", "highlighting"))
+	expected := "Just a test <b>highlighting</b> from postings\n" +
+		"<b>Highlighting</b> the first term\n"
+	if res := highlight(t, q, 2); res != expected {
+		t.Errorf("expected %q, but was %q", expected, res)
+	}
+	// the shorter passage scores higher
+	expected = "<b>Highlighting</b> the first term\n"
+	if res := highlight(t, q, 1); res != expected {
+		t.Errorf("expected %q, but was %q", expected, res)
+	}
+}
+
+func TestHighlightBoolean(t *testing.T) {
+	q := search.NewBooleanQuery()
+	q.Add(search.NewTermQuery(index.NewTerm("body", "hope")), search.SHOULD)
+	q.Add(search.NewTermQuery(index.NewTerm("body", "test")), search.SHOULD)
+	q.Add(search.NewTermQuery(index.NewTerm("body", "works")), search.MUST_NOT)
+	expected := "This is a <b>test</b>\n... <b>Hope</b> it works"
+	if res := highlight(t, q, 2); res != expected {
+		t.Errorf("expected %q, but was %q", expected, res)
+	}
+}
+
+func TestNoHighlightSummary(t *testing.T) {
+	q := search.NewTermQuery(index.NewTerm("body", "missing"))
+	expected := "This is a test\n"
+	if res := highlight(t, q, 1); res != expected {
+		t.Errorf("expected %q, but was %q", expected, res)
+	}
+}
+
+type phraseQuery struct {
+	*search.AbstractQuery
+	terms []*index.Term
+}
+
+func newPhraseQuery(field string, words ...string) *phraseQuery {
+	ans := new(phraseQuery)
+	for _, w := range words {
+		ans.terms = append(ans.terms, index.NewTerm(field, w))
+	}
+	ans.AbstractQuery = search.NewAbstractQuery(ans)
+	return ans
+}
+
+func (q *phraseQuery) ToString(field string) string { return "phrase" }
+func (q *phraseQuery) Terms() []*index.Term         { return q.terms }
+func (q *phraseQuery) Slop() int                    { return 0 }
+
+func (q *phraseQuery) Positions() []int {
+	ans := make([]int, len(q.terms))
+	for i, _ := range ans {
+		ans[i] = i
+	}
+	return ans
+}
+
+func TestHighlightPhrase(t *testing.T) {
+	// "test" also occurs in the first sentence, but not followed by
+	// "highlighting"
+	q := newPhraseQuery("body", "test", "highlighting")
+	expected := "Just a <b>test</b> <b>highlighting</b> from postings\n"
+	if res := highlight(t, q, 1); res != expected {
+		t.Errorf("expected %q, but was %q", expected, res)
+	}
+}
+
+func TestFormatterEscape(t *testing.T) {
+	content := []rune("a <b> & c")
+	p := newPassage(0, len(content))
+	p.addMatch(8, 9, "c")
+	f := NewDefaultPassageFormatterWith("[", "]", "...", true)
+	expected := "a &lt;b&gt; &amp; [c]"
+	if res := f.Format([]*Passage{p}, content); res != expected {
+		t.Errorf("expected %q, but was %q", expected, res)
+	}
+}
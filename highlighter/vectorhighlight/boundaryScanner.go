@@ -0,0 +1,79 @@
+package vectorhighlight
+
+// vectorhighlight/BoundaryScanner.java
+
+/*
+Finds fragment boundaries: pluggable into BaseFragmentsBuilder.
+*/
+type BoundaryScanner interface {
+	// Scan backward to find end offset.
+	FindStartOffset(buffer []rune, start int) int
+	// Scan forward to find start offset.
+	FindEndOffset(buffer []rune, start int) int
+}
+
+// vectorhighlight/SimpleBoundaryScanner.java
+
+/*
+Simple boundary scanner implementation that divides fragments based
+on a set of separator characters.
+*/
+type SimpleBoundaryScanner struct {
+	maxScan       int
+	boundaryChars map[rune]bool
+}
+
+const DEFAULT_MAX_SCAN = 20
+
+var DEFAULT_BOUNDARY_CHARS = []rune{'.', ',', '!', '?', ' ', '\t', '\n'}
+
+func NewSimpleBoundaryScanner() *SimpleBoundaryScanner {
+	return NewSimpleBoundaryScannerWith(DEFAULT_MAX_SCAN, DEFAULT_BOUNDARY_CHARS)
+}
+
+func NewSimpleBoundaryScannerWith(maxScan int, boundaryChars []rune) *SimpleBoundaryScanner {
+	ans := &SimpleBoundaryScanner{maxScan, make(map[rune]bool)}
+	for _, ch := range boundaryChars {
+		ans.boundaryChars[ch] = true
+	}
+	return ans
+}
+
+func (bs *SimpleBoundaryScanner) FindStartOffset(buffer []rune, start int) int {
+	// avoid illegal start offset
+	if start > len(buffer) || start < 1 {
+		return start
+	}
+	offset := start
+	for count := bs.maxScan; offset > 0 && count > 0; count-- {
+		// found?
+		if bs.boundaryChars[buffer[offset-1]] {
+			return offset
+		}
+		offset--
+	}
+	// if we scanned up to the start of the text, return it, it's a
+	// "boundary"
+	if offset == 0 {
+		return 0
+	}
+	// not found
+	return start
+}
+
+func (bs *SimpleBoundaryScanner) FindEndOffset(buffer []rune, start int) int {
+	// avoid illegal start offset
+	if start > len(buffer) || start < 0 {
+		return start
+	}
+	offset := start
+	for count := bs.maxScan; offset < len(buffer) && count > 0; count-- {
+		// found?
+		if bs.boundaryChars[buffer[offset]] {
+			return offset
+		}
+		offset++
+	}
+	// not found
+	return start
+}
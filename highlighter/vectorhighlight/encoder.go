@@ -0,0 +1,60 @@
+package vectorhighlight
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// highlight/Encoder.java
+
+// Encodes original text. The Encoder works with the Formatter to
+// generate output.
+type Encoder interface {
+	EncodeText(originalText string) string
+}
+
+// highlight/DefaultEncoder.java
+
+// Simple Encoder implementation that does not modify the output.
+type DefaultEncoder struct{}
+
+func (e DefaultEncoder) EncodeText(originalText string) string {
+	return originalText
+}
+
+// highlight/SimpleHTMLEncoder.java
+
+// Simple Encoder implementation to escape text for HTML output.
+type SimpleHTMLEncoder struct{}
+
+func (e SimpleHTMLEncoder) EncodeText(originalText string) string {
+	return HTMLEncode(originalText)
+}
+
+// Encode string into HTML
+func HTMLEncode(plainText string) string {
+	var buf bytes.Buffer
+	for _, ch := range plainText {
+		switch ch {
+		case '"':
+			buf.WriteString("&quot;")
+		case '&':
+			buf.WriteString("&amp;")
+		case '<':
+			buf.WriteString("&lt;")
+		case '>':
+			buf.WriteString("&gt;")
+		case '\'':
+			buf.WriteString("&#x27;")
+		case '/':
+			buf.WriteString("&#x2F;")
+		default:
+			if ch < 128 {
+				buf.WriteRune(ch)
+			} else {
+				fmt.Fprintf(&buf, "&#%v;", int(ch))
+			}
+		}
+	}
+	return buf.String()
+}
@@ -0,0 +1,135 @@
+package vectorhighlight
+
+import (
+	"github.com/balzaczyy/golucene/core/index"
+	"github.com/balzaczyy/golucene/core/search"
+	"math"
+)
+
+// vectorhighlight/FastVectorHighlighter.java
+
+const (
+	DEFAULT_PHRASE_HIGHLIGHT = true
+	DEFAULT_FIELD_MATCH      = true
+)
+
+/*
+Another highlighter implementation, which reads the offsets of the
+query terms from term vectors; the highlighted field must be stored,
+and indexed with term vectors including positions and offsets.
+
+Phrases of the query are highlighted as a whole, only where they
+match, and each term or phrase of the query can be given its own tag
+(e.g. COLORED_PRE_TAGS).
+*/
+type FastVectorHighlighter struct {
+	phraseHighlight  bool
+	fieldMatch       bool
+	fragListBuilder  FragListBuilder
+	fragmentsBuilder FragmentsBuilder
+	phraseLimit      int
+}
+
+/*
+The default constructor, highlighting phrases and matching fields,
+with a SimpleFragListBuilder and a ScoreOrderFragmentsBuilder.
+*/
+func NewFastVectorHighlighter() *FastVectorHighlighter {
+	return NewFastVectorHighlighterWith(DEFAULT_PHRASE_HIGHLIGHT, DEFAULT_FIELD_MATCH,
+		NewSimpleFragListBuilder(), NewScoreOrderFragmentsBuilder())
+}
+
+/*
+A constructor. phraseHighlight enables the phrase highlighting
+feature; fieldMatch enables field matching, i.e. the query's terms
+are only highlighted in their own field.
+*/
+func NewFastVectorHighlighterWith(phraseHighlight, fieldMatch bool,
+	fragListBuilder FragListBuilder, fragmentsBuilder FragmentsBuilder) *FastVectorHighlighter {
+
+	return &FastVectorHighlighter{
+		phraseHighlight:  phraseHighlight,
+		fieldMatch:       fieldMatch,
+		fragListBuilder:  fragListBuilder,
+		fragmentsBuilder: fragmentsBuilder,
+		phraseLimit:      math.MaxInt32,
+	}
+}
+
+/*
+Create a FieldQuery object. reader is used to rewrite queries which
+are neither terms, phrases nor booleans.
+*/
+func (h *FastVectorHighlighter) FieldQuery(query search.Query, reader index.IndexReader) *FieldQuery {
+	return NewFieldQuery(query, reader, h.phraseHighlight, h.fieldMatch)
+}
+
+/*
+Return the best fragment, or "" if there is none. fragCharSize is the
+length of the fragment.
+*/
+func (h *FastVectorHighlighter) BestFragment(fieldQuery *FieldQuery, reader index.IndexReader,
+	docId int, fieldName string, fragCharSize int) (string, error) {
+
+	fieldFragList, err := h.fieldFragList(fieldQuery, reader, docId, fieldName, fragCharSize)
+	if err != nil {
+		return "", err
+	}
+	return h.fragmentsBuilder.CreateFragment(reader, docId, fieldName, fieldFragList)
+}
+
+// Return the best fragments.
+func (h *FastVectorHighlighter) BestFragments(fieldQuery *FieldQuery, reader index.IndexReader,
+	docId int, fieldName string, fragCharSize, maxNumFragments int) ([]string, error) {
+
+	fieldFragList, err := h.fieldFragList(fieldQuery, reader, docId, fieldName, fragCharSize)
+	if err != nil {
+		return nil, err
+	}
+	return h.fragmentsBuilder.CreateFragments(reader, docId, fieldName, fieldFragList, maxNumFragments)
+}
+
+/*
+Return the best fragments, using the given tags and encoder, e.g.
+COLORED_PRE_TAGS and SimpleHTMLEncoder.
+*/
+func (h *FastVectorHighlighter) BestFragmentsWithTags(fieldQuery *FieldQuery, reader index.IndexReader,
+	docId int, fieldName string, fragCharSize, maxNumFragments int,
+	preTags, postTags []string, encoder Encoder) ([]string, error) {
+
+	fieldFragList, err := h.fieldFragList(fieldQuery, reader, docId, fieldName, fragCharSize)
+	if err != nil {
+		return nil, err
+	}
+	return h.fragmentsBuilder.CreateFragmentsWithTags(reader, docId, fieldName,
+		fieldFragList, maxNumFragments, preTags, postTags, encoder)
+}
+
+func (h *FastVectorHighlighter) fieldFragList(fieldQuery *FieldQuery, reader index.IndexReader,
+	docId int, fieldName string, fragCharSize int) (*FieldFragList, error) {
+
+	fieldTermStack, err := NewFieldTermStack(reader, docId, fieldName, fieldQuery)
+	if err != nil {
+		return nil, err
+	}
+	fieldPhraseList := NewFieldPhraseListWithLimit(fieldTermStack, fieldQuery, h.phraseLimit)
+	return h.fragListBuilder.CreateFieldFragList(fieldPhraseList, fragCharSize), nil
+}
+
+// Returns whether phrase highlighting is enabled.
+func (h *FastVectorHighlighter) IsPhraseHighlight() bool {
+	return h.phraseHighlight
+}
+
+// Returns whether field matching is enabled.
+func (h *FastVectorHighlighter) IsFieldMatch() bool {
+	return h.fieldMatch
+}
+
+/*
+Sets the maximum number of phrases to analyze when searching for the
+highest-scoring phrase. The default is unlimited.
+*/
+func (h *FastVectorHighlighter) SetPhraseLimit(phraseLimit int) {
+	h.phraseLimit = phraseLimit
+}
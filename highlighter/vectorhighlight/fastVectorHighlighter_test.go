@@ -0,0 +1,103 @@
+package vectorhighlight
+
+import (
+	"github.com/balzaczyy/golucene/core/index"
+	"github.com/balzaczyy/golucene/core/search"
+	"strings"
+	"testing"
+	"unicode"
+)
+
+/*
+Builds the term stack of a whitespace-tokenized, lower-cased text,
+as the term vector of the field would have it.
+*/
+func newTestTermStack(fieldName, text string, fq *FieldQuery) *FieldTermStack {
+	stack := &FieldTermStack{fieldName: fieldName}
+	termSet := fq.termSet(fieldName)
+	runes := []rune(text)
+	pos := 0
+	for start := 0; start < len(runes); {
+		if unicode.IsSpace(runes[start]) {
+			start++
+			continue
+		}
+		end := start
+		for end < len(runes) && !unicode.IsSpace(runes[end]) {
+			end++
+		}
+		term := strings.ToLower(string(runes[start:end]))
+		if termSet[term] {
+			stack.termList = append(stack.termList, &TermInfo{term, start, end, pos, 1})
+		}
+		pos++
+		start = end
+	}
+	return stack
+}
+
+func highlight(q search.Query, text string, fragCharSize int, fb *BaseFragmentsBuilder) []string {
+	fq := NewFieldQuery(q, nil, true, true)
+	fpl := NewFieldPhraseList(newTestTermStack("f", text, fq), fq)
+	ffl := NewSimpleFragListBuilder().CreateFieldFragList(fpl, fragCharSize)
+	return fb.createFragments([]string{text}, ffl, 3, fb.preTags, fb.postTags, DefaultEncoder{})
+}
+
+func TestTermHighlight(t *testing.T) {
+	q := search.NewTermQuery(index.NewTerm("f", "lucene"))
+	res := highlight(q, "a b c d e Lucene f g h i j", 100, NewScoreOrderFragmentsBuilder().BaseFragmentsBuilder)
+	if len(res) != 1 || res[0] != "a b c d e <b>Lucene</b> f g h i j " {
+		t.Errorf("unexpected fragments %q", res)
+	}
+}
+
+func TestPhraseHighlight(t *testing.T) {
+	pq := search.NewPhraseQuery()
+	pq.Add(index.NewTerm("f", "search"))
+	pq.Add(index.NewTerm("f", "engine"))
+	text := "engine of a search engine search"
+	res := highlight(pq, text, 100, NewSimpleFragmentsBuilder().BaseFragmentsBuilder)
+	// only the consecutive terms are highlighted, as one span
+	if len(res) != 1 || res[0] != "engine of a <b>search engine</b> search " {
+		t.Errorf("unexpected fragments %q", res)
+	}
+}
+
+func TestColoredTags(t *testing.T) {
+	bq := search.NewBooleanQuery()
+	bq.Add(search.NewTermQuery(index.NewTerm("f", "a")), search.SHOULD)
+	bq.Add(search.NewTermQuery(index.NewTerm("f", "c")), search.SHOULD)
+	bq.Add(search.NewTermQuery(index.NewTerm("f", "d")), search.MUST_NOT)
+	fb := NewSimpleFragmentsBuilderWith(COLORED_PRE_TAGS, COLORED_POST_TAGS, NewSimpleBoundaryScanner())
+	res := highlight(bq, "a b c d", 100, fb.BaseFragmentsBuilder)
+	expected := `<b style="background:yellow">a</b> b <b style="background:lawngreen">c</b> d `
+	if len(res) != 1 || res[0] != expected {
+		t.Errorf("unexpected fragments %q", res)
+	}
+}
+
+func TestFragments(t *testing.T) {
+	q := search.NewTermQuery(index.NewTerm("f", "x"))
+	text := "x aaaaaaaaaa bbbbbbbbbb cccccccccc dddddddddd x eeeeeeeeee"
+	res := highlight(q, text, 20, NewSimpleFragmentsBuilder().BaseFragmentsBuilder)
+	expected := []string{"<b>x</b> aaaaaaaaaa bbbbbbbbbb", "dddddddddd <b>x</b> eeeeeeeeee"}
+	if len(res) != len(expected) {
+		t.Fatalf("unexpected fragments %q", res)
+	}
+	for i, frag := range expected {
+		if res[i] != frag {
+			t.Errorf("expected %q, but was %q", frag, res[i])
+		}
+	}
+}
+
+func TestSimpleBoundaryScanner(t *testing.T) {
+	text := []rune("Hello world. Goodbye")
+	bs := NewSimpleBoundaryScanner()
+	if n := bs.FindStartOffset(text, 15); n != 13 {
+		t.Errorf("expected start 13, but was %v", n)
+	}
+	if n := bs.FindEndOffset(text, 2); n != 5 {
+		t.Errorf("expected end 5, but was %v", n)
+	}
+}
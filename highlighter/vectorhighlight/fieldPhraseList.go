@@ -0,0 +1,150 @@
+package vectorhighlight
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+)
+
+// vectorhighlight/FieldPhraseList.java
+
+/*
+FieldPhraseList has a list of WeightedPhraseInfo that is used by
+FragListBuilder to create a FieldFragList object.
+*/
+type FieldPhraseList struct {
+	PhraseList []*WeightedPhraseInfo
+}
+
+// Creates a FieldPhraseList from the terms of the stack.
+func NewFieldPhraseList(fieldTermStack *FieldTermStack, fieldQuery *FieldQuery) *FieldPhraseList {
+	return NewFieldPhraseListWithLimit(fieldTermStack, fieldQuery, math.MaxInt32)
+}
+
+/*
+Creates a FieldPhraseList from the terms of the stack, stopping after
+phraseLimit phrases have been found.
+*/
+func NewFieldPhraseListWithLimit(fieldTermStack *FieldTermStack,
+	fieldQuery *FieldQuery, phraseLimit int) *FieldPhraseList {
+
+	ans := new(FieldPhraseList)
+	field := fieldTermStack.FieldName()
+
+	for !fieldTermStack.IsEmpty() && len(ans.PhraseList) < phraseLimit {
+		ti := fieldTermStack.Pop()
+		currMap := fieldQuery.fieldTermMap(field, ti.Text)
+
+		// if not found, discard top TermInfo from stack, then try next
+		// element
+		if currMap == nil {
+			continue
+		}
+
+		// if found, search the longest phrase
+		phraseCandidate := []*TermInfo{ti}
+		for {
+			ti = fieldTermStack.Pop()
+			var nextMap *QueryPhraseMap
+			if ti != nil {
+				nextMap = currMap.termMap(ti.Text)
+			}
+			if ti != nil && nextMap != nil {
+				phraseCandidate = append(phraseCandidate, ti)
+				currMap = nextMap
+				continue
+			}
+
+			if ti != nil {
+				fieldTermStack.Push(ti)
+			}
+			if currMap.isValidTermOrPhrase(phraseCandidate) {
+				ans.addIfNoOverlap(newWeightedPhraseInfo(phraseCandidate,
+					currMap.boost, currMap.termOrPhraseNumber))
+			} else {
+				for len(phraseCandidate) > 1 {
+					last := phraseCandidate[len(phraseCandidate)-1]
+					phraseCandidate = phraseCandidate[:len(phraseCandidate)-1]
+					fieldTermStack.Push(last)
+					if currMap = fieldQuery.searchPhrase(field, phraseCandidate); currMap != nil {
+						ans.addIfNoOverlap(newWeightedPhraseInfo(phraseCandidate,
+							currMap.boost, currMap.termOrPhraseNumber))
+						break
+					}
+				}
+			}
+			break
+		}
+	}
+	return ans
+}
+
+func (l *FieldPhraseList) addIfNoOverlap(wpi *WeightedPhraseInfo) {
+	for _, existWpi := range l.PhraseList {
+		if existWpi.isOffsetOverlap(wpi) {
+			return
+		}
+	}
+	l.PhraseList = append(l.PhraseList, wpi)
+}
+
+/*
+Represents a phrase (or a single term) of the query found in the
+field, with the offsets to highlight.
+*/
+type WeightedPhraseInfo struct {
+	Text         string // unnecessary member, just exists for debugging purpose
+	TermsOffsets []*Toffs
+	Boost        float32 // query boost
+	Seqnum       int
+}
+
+func newWeightedPhraseInfo(terms []*TermInfo, boost float32, seqnum int) *WeightedPhraseInfo {
+	ans := &WeightedPhraseInfo{Boost: boost, Seqnum: seqnum}
+
+	var text bytes.Buffer
+	ti := terms[0]
+	text.WriteString(ti.Text)
+	ans.TermsOffsets = []*Toffs{{ti.StartOffset, ti.EndOffset}}
+	pos := ti.Position
+	for _, ti := range terms[1:] {
+		text.WriteString(ti.Text)
+		if ti.Position-pos == 1 {
+			// consecutive terms are highlighted as one span
+			ans.TermsOffsets[len(ans.TermsOffsets)-1].EndOffset = ti.EndOffset
+		} else {
+			ans.TermsOffsets = append(ans.TermsOffsets, &Toffs{ti.StartOffset, ti.EndOffset})
+		}
+		pos = ti.Position
+	}
+	ans.Text = text.String()
+	return ans
+}
+
+func (wpi *WeightedPhraseInfo) StartOffset() int {
+	return wpi.TermsOffsets[0].StartOffset
+}
+
+func (wpi *WeightedPhraseInfo) EndOffset() int {
+	return wpi.TermsOffsets[len(wpi.TermsOffsets)-1].EndOffset
+}
+
+func (wpi *WeightedPhraseInfo) isOffsetOverlap(other *WeightedPhraseInfo) bool {
+	existStartOffset, existEndOffset := wpi.StartOffset(), wpi.EndOffset()
+	startOffset, endOffset := other.StartOffset(), other.EndOffset()
+	return !(endOffset <= existStartOffset || startOffset >= existEndOffset)
+}
+
+func (wpi *WeightedPhraseInfo) String() string {
+	return fmt.Sprintf("%v(%v)%v", wpi.Text, wpi.Boost, wpi.TermsOffsets)
+}
+
+// Term offsets (start + end).
+type Toffs struct {
+	StartOffset int
+	EndOffset   int
+}
+
+func (to *Toffs) String() string {
+	return fmt.Sprintf("(%v,%v)", to.StartOffset, to.EndOffset)
+}
@@ -0,0 +1,307 @@
+package vectorhighlight
+
+import (
+	"github.com/balzaczyy/golucene/core/index"
+	"github.com/balzaczyy/golucene/core/search"
+)
+
+// vectorhighlight/FieldQuery.java
+
+/*
+FieldQuery breaks down query object into terms/phrases and keeps them
+in a QueryPhraseMap structure.
+*/
+type FieldQuery struct {
+	fieldMatch bool
+
+	// fieldMatch==true,  Map<fieldName,QueryPhraseMap>
+	// fieldMatch==false, Map<"",QueryPhraseMap>
+	rootMaps map[string]*QueryPhraseMap
+
+	// fieldMatch==true,  Map<fieldName,setOfTermsInQueries>
+	// fieldMatch==false, Map<"",setOfTermsInQueries>
+	termSetMap map[string]map[string]bool
+
+	termOrPhraseNumber int // used for colored tag support
+}
+
+/*
+Creates a FieldQuery. reader is only used to rewrite queries which
+are neither terms, phrases nor booleans; it may be nil if there are
+none.
+*/
+func NewFieldQuery(query search.Query, reader index.IndexReader,
+	phraseHighlight, fieldMatch bool) *FieldQuery {
+
+	fq := &FieldQuery{
+		fieldMatch: fieldMatch,
+		rootMaps:   make(map[string]*QueryPhraseMap),
+		termSetMap: make(map[string]map[string]bool),
+	}
+	var flatQueries []search.Query
+	fq.flatten(query, reader, make(map[string]bool), &flatQueries)
+	fq.saveTerms(flatQueries)
+	for _, flatQuery := range flatQueries {
+		rootMap := fq.rootMap(flatQuery)
+		rootMap.add(flatQuery)
+		if pq, ok := flatQuery.(*search.PhraseQuery); ok && !phraseHighlight && len(pq.Terms()) > 1 {
+			for _, term := range pq.Terms() {
+				rootMap.addTerm(term, flatQuery.Boost())
+			}
+		}
+	}
+	return fq
+}
+
+func (fq *FieldQuery) flatten(sourceQuery search.Query, reader index.IndexReader,
+	seen map[string]bool, flatQueries *[]search.Query) {
+
+	switch q := sourceQuery.(type) {
+	case *search.BooleanQuery:
+		for _, clause := range q.Clauses() {
+			if !clause.IsProhibited() {
+				fq.flatten(clause.Query(), reader, seen, flatQueries)
+			}
+		}
+	case *search.TermQuery:
+		fq.addFlatQuery(q, seen, flatQueries)
+	case *search.PhraseQuery:
+		if terms := q.Terms(); len(terms) > 1 {
+			fq.addFlatQuery(q, seen, flatQueries)
+		} else if len(terms) == 1 {
+			tq := search.NewTermQuery(terms[0])
+			tq.SetBoost(q.Boost())
+			fq.addFlatQuery(tq, seen, flatQueries)
+		}
+	default:
+		if reader != nil {
+			if rewritten := sourceQuery.Rewrite(reader); rewritten != sourceQuery {
+				// only rewrite once and then flatten again - the rewritten
+				// query could have a special treatment if this method is
+				// overwritten in a subclass or above in the next recursion
+				fq.flatten(rewritten, reader, seen, flatQueries)
+			}
+		}
+		// if the query is already rewritten we discard it
+	}
+}
+
+func (fq *FieldQuery) addFlatQuery(q search.Query, seen map[string]bool, flatQueries *[]search.Query) {
+	key := q.ToString("")
+	if !seen[key] {
+		seen[key] = true
+		*flatQueries = append(*flatQueries, q)
+	}
+}
+
+func (fq *FieldQuery) rootMap(query search.Query) *QueryPhraseMap {
+	key := fq.key(query)
+	m, ok := fq.rootMaps[key]
+	if !ok {
+		m = newQueryPhraseMap(fq)
+		fq.rootMaps[key] = m
+	}
+	return m
+}
+
+/*
+Returns the field name of the query if fieldMatch is true, or "" if
+it is false.
+*/
+func (fq *FieldQuery) key(query search.Query) string {
+	if !fq.fieldMatch {
+		return ""
+	}
+	switch q := query.(type) {
+	case *search.TermQuery:
+		return q.Term().Field
+	case *search.PhraseQuery:
+		return q.Terms()[0].Field
+	}
+	panic("query should be TermQuery or PhraseQuery")
+}
+
+/*
+Save the set of terms in the queries to termSetMap.
+
+	ex1) q=name:john
+	     - fieldMatch==true
+	         termSetMap=Map<"name",Set<"john">>
+	     - fieldMatch==false
+	         termSetMap=Map<"",Set<"john">>
+
+	ex2) q=name:john title:manager
+	     - fieldMatch==true
+	         termSetMap=Map<"name",Set<"john">,
+	                        "title",Set<"manager">>
+	     - fieldMatch==false
+	         termSetMap=Map<"",Set<"john","manager">>
+
+	ex3) q=name:"john lennon"
+	     - fieldMatch==true
+	         termSetMap=Map<"name",Set<"john","lennon">>
+	     - fieldMatch==false
+	         termSetMap=Map<"",Set<"john","lennon">>
+*/
+func (fq *FieldQuery) saveTerms(flatQueries []search.Query) {
+	for _, query := range flatQueries {
+		key := fq.key(query)
+		termSet, ok := fq.termSetMap[key]
+		if !ok {
+			termSet = make(map[string]bool)
+			fq.termSetMap[key] = termSet
+		}
+		switch q := query.(type) {
+		case *search.TermQuery:
+			termSet[string(q.Term().Bytes)] = true
+		case *search.PhraseQuery:
+			for _, term := range q.Terms() {
+				termSet[string(term.Bytes)] = true
+			}
+		}
+	}
+}
+
+// Returns the set of terms to look for in the field.
+func (fq *FieldQuery) termSet(field string) map[string]bool {
+	if !fq.fieldMatch {
+		field = ""
+	}
+	return fq.termSetMap[field]
+}
+
+// Returns the QueryPhraseMap of a single term in the field, or nil.
+func (fq *FieldQuery) fieldTermMap(fieldName, term string) *QueryPhraseMap {
+	if rootMap := fq.rootMapByField(fieldName); rootMap != nil {
+		return rootMap.subMap[term]
+	}
+	return nil
+}
+
+/*
+Returns the QueryPhraseMap of the phrase candidate, if it is a valid
+term or phrase of the query; nil otherwise.
+*/
+func (fq *FieldQuery) searchPhrase(fieldName string, phraseCandidate []*TermInfo) *QueryPhraseMap {
+	if root := fq.rootMapByField(fieldName); root != nil {
+		return root.searchPhrase(phraseCandidate)
+	}
+	return nil
+}
+
+func (fq *FieldQuery) rootMapByField(fieldName string) *QueryPhraseMap {
+	if !fq.fieldMatch {
+		fieldName = ""
+	}
+	return fq.rootMaps[fieldName]
+}
+
+func (fq *FieldQuery) nextTermOrPhraseNumber() int {
+	ans := fq.termOrPhraseNumber
+	fq.termOrPhraseNumber++
+	return ans
+}
+
+/*
+A tree of the terms of the query, where the path from the root to a
+terminal node spells a term or a phrase of the query.
+*/
+type QueryPhraseMap struct {
+	terminal           bool
+	slop               int // valid if terminal == true and phraseHighlight == true
+	boost              float32
+	termOrPhraseNumber int // valid if terminal == true
+	fieldQuery         *FieldQuery
+	subMap             map[string]*QueryPhraseMap
+}
+
+func newQueryPhraseMap(fieldQuery *FieldQuery) *QueryPhraseMap {
+	return &QueryPhraseMap{
+		fieldQuery: fieldQuery,
+		subMap:     make(map[string]*QueryPhraseMap),
+	}
+}
+
+func (m *QueryPhraseMap) addTerm(term *index.Term, boost float32) {
+	termMap := m.getOrNewMap(string(term.Bytes))
+	termMap.markTerminal(0, boost)
+}
+
+func (m *QueryPhraseMap) getOrNewMap(term string) *QueryPhraseMap {
+	ans, ok := m.subMap[term]
+	if !ok {
+		ans = newQueryPhraseMap(m.fieldQuery)
+		m.subMap[term] = ans
+	}
+	return ans
+}
+
+func (m *QueryPhraseMap) add(query search.Query) {
+	switch q := query.(type) {
+	case *search.TermQuery:
+		m.addTerm(q.Term(), q.Boost())
+	case *search.PhraseQuery:
+		qpm := m
+		for _, term := range q.Terms() {
+			qpm = qpm.getOrNewMap(string(term.Bytes))
+		}
+		qpm.markTerminal(q.Slop(), q.Boost())
+	default:
+		panic("query should be TermQuery or PhraseQuery")
+	}
+}
+
+func (m *QueryPhraseMap) markTerminal(slop int, boost float32) {
+	m.terminal = true
+	m.slop = slop
+	m.boost = boost
+	m.termOrPhraseNumber = m.fieldQuery.nextTermOrPhraseNumber()
+}
+
+func (m *QueryPhraseMap) termMap(term string) *QueryPhraseMap {
+	return m.subMap[term]
+}
+
+func (m *QueryPhraseMap) searchPhrase(phraseCandidate []*TermInfo) *QueryPhraseMap {
+	currMap := m
+	for _, ti := range phraseCandidate {
+		if currMap = currMap.subMap[ti.Text]; currMap == nil {
+			return nil
+		}
+	}
+	if currMap.isValidTermOrPhrase(phraseCandidate) {
+		return currMap
+	}
+	return nil
+}
+
+func (m *QueryPhraseMap) isValidTermOrPhrase(phraseCandidate []*TermInfo) bool {
+	// check terminal
+	if !m.terminal {
+		return false
+	}
+
+	// if the candidate is a term, it is valid
+	if len(phraseCandidate) == 1 {
+		return true
+	}
+
+	// else check whether the candidate is valid phrase
+	// compare position-gaps between terms to slop
+	pos := phraseCandidate[0].Position
+	for _, ti := range phraseCandidate[1:] {
+		nextPos := ti.Position
+		if abs(nextPos-pos-1) > m.slop {
+			return false
+		}
+		pos = nextPos
+	}
+	return true
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,164 @@
+package vectorhighlight
+
+import (
+	"fmt"
+	"github.com/balzaczyy/golucene/core/index"
+	. "github.com/balzaczyy/golucene/core/index/model"
+	"math"
+	"sort"
+)
+
+// vectorhighlight/FieldTermStack.java
+
+/*
+FieldTermStack is a stack that keeps query terms in the specified
+field of the document to be highlighted.
+*/
+type FieldTermStack struct {
+	fieldName string
+	termList  []*TermInfo
+}
+
+/*
+A constructor. Reads the term vector of the document, which must
+have been indexed with positions and offsets.
+*/
+func NewFieldTermStack(reader index.IndexReader, docId int, fieldName string,
+	fieldQuery *FieldQuery) (*FieldTermStack, error) {
+
+	stack := &FieldTermStack{fieldName: fieldName}
+
+	termSet := fieldQuery.termSet(fieldName)
+	// just return to make null snippet if un-matched fieldName specified
+	// when fieldMatch == true
+	if termSet == nil {
+		return stack, nil
+	}
+
+	leaves := reader.Leaves()
+	leaf := leaves[index.SubIndex(docId, leaves)]
+	vectors, err := leaf.Reader().(index.AtomicReader).TermVectors(docId - leaf.DocBase)
+	if err != nil || vectors == nil {
+		// null snippet
+		return stack, err
+	}
+
+	vector := vectors.Terms(fieldName)
+	if vector == nil {
+		// null snippet
+		return stack, nil
+	}
+
+	termsEnum := vector.Iterator(nil)
+	var dpEnum DocsAndPositionsEnum
+	numDocs := reader.MaxDoc()
+
+	for {
+		text, err := termsEnum.Next()
+		if err != nil {
+			return nil, err
+		}
+		if text == nil {
+			break
+		}
+		term := string(text)
+		if !termSet[term] {
+			continue
+		}
+		if dpEnum, err = termsEnum.DocsAndPositions(nil, dpEnum); err != nil {
+			return nil, err
+		}
+		if dpEnum == nil {
+			// null snippet
+			return stack, nil
+		}
+
+		if _, err = dpEnum.NextDoc(); err != nil {
+			return nil, err
+		}
+
+		// For weight look here: http://lucene.apache.org/core/3_6_0/api/core/org/apache/lucene/search/DefaultSimilarity.html
+		docFreq, err := reader.DocFreq(index.NewTermFromBytes(fieldName, text))
+		if err != nil {
+			return nil, err
+		}
+		weight := float32(math.Log(float64(numDocs)/float64(docFreq+1)) + 1)
+
+		freq, err := dpEnum.Freq()
+		if err != nil {
+			return nil, err
+		}
+
+		for i := 0; i < freq; i++ {
+			pos, err := dpEnum.NextPosition()
+			if err != nil {
+				return nil, err
+			}
+			startOffset, err := dpEnum.StartOffset()
+			if err != nil {
+				return nil, err
+			}
+			if startOffset < 0 {
+				// null snippet
+				return &FieldTermStack{fieldName: fieldName}, nil
+			}
+			endOffset, err := dpEnum.EndOffset()
+			if err != nil {
+				return nil, err
+			}
+			stack.termList = append(stack.termList, &TermInfo{term, startOffset, endOffset, pos, weight})
+		}
+	}
+
+	// sort by position
+	sort.Stable(termInfosByPosition(stack.termList))
+	return stack, nil
+}
+
+// Returns the field name.
+func (s *FieldTermStack) FieldName() string {
+	return s.fieldName
+}
+
+// Returns the top TermInfo object of the stack, or nil if it is empty.
+func (s *FieldTermStack) Pop() *TermInfo {
+	if len(s.termList) == 0 {
+		return nil
+	}
+	ti := s.termList[0]
+	s.termList = s.termList[1:]
+	return ti
+}
+
+// Puts the TermInfo object back on top of the stack.
+func (s *FieldTermStack) Push(termInfo *TermInfo) {
+	s.termList = append([]*TermInfo{termInfo}, s.termList...)
+}
+
+// Returns true if the stack is empty.
+func (s *FieldTermStack) IsEmpty() bool {
+	return len(s.termList) == 0
+}
+
+/*
+Single term information: the term text, its offsets in the field
+and its position.
+*/
+type TermInfo struct {
+	Text        string
+	StartOffset int
+	EndOffset   int
+	Position    int
+	// IDF-weight of this term
+	Weight float32
+}
+
+func (ti *TermInfo) String() string {
+	return fmt.Sprintf("%v(%v,%v,%v)", ti.Text, ti.StartOffset, ti.EndOffset, ti.Position)
+}
+
+type termInfosByPosition []*TermInfo
+
+func (s termInfosByPosition) Len() int           { return len(s) }
+func (s termInfosByPosition) Less(i, j int) bool { return s[i].Position < s[j].Position }
+func (s termInfosByPosition) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
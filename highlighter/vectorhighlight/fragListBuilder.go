@@ -0,0 +1,207 @@
+package vectorhighlight
+
+import (
+	"fmt"
+	"math"
+)
+
+// vectorhighlight/FragListBuilder.java
+
+/*
+FragListBuilder is an interface for FieldFragList builder classes. A
+FragListBuilder class can be plugged in to the Highlighter.
+*/
+type FragListBuilder interface {
+	// Create a FieldFragList.
+	CreateFieldFragList(fieldPhraseList *FieldPhraseList, fragCharSize int) *FieldFragList
+}
+
+// vectorhighlight/FieldFragList.java
+
+/*
+FieldFragList has a list of "frag info" that is used by
+FragmentsBuilder class to create fragments (snippets).
+*/
+type FieldFragList struct {
+	FragInfos []*WeightedFragInfo
+}
+
+// Converts the list of phrases into a frag info, and adds it.
+func (l *FieldFragList) add(startOffset, endOffset int, phraseInfoList []*WeightedPhraseInfo) {
+	var totalBoost float32
+	subInfos := make([]*SubInfo, len(phraseInfoList))
+	for i, phraseInfo := range phraseInfoList {
+		subInfos[i] = &SubInfo{phraseInfo.Text, phraseInfo.TermsOffsets,
+			phraseInfo.Seqnum, phraseInfo.Boost}
+		totalBoost += phraseInfo.Boost
+	}
+	l.FragInfos = append(l.FragInfos, &WeightedFragInfo{startOffset, endOffset, totalBoost, subInfos})
+}
+
+/*
+Lists the highlighted phrases of one fragment, which covers
+[StartOffset, EndOffset) of the field's text.
+*/
+type WeightedFragInfo struct {
+	StartOffset int
+	EndOffset   int
+	TotalBoost  float32
+	SubInfos    []*SubInfo
+}
+
+func (info *WeightedFragInfo) String() string {
+	return fmt.Sprintf("subInfos=%v totalBoost=%v(%v,%v)",
+		info.SubInfos, info.TotalBoost, info.StartOffset, info.EndOffset)
+}
+
+// A phrase of a fragment.
+type SubInfo struct {
+	Text         string // unnecessary member, just exists for debugging purpose
+	TermsOffsets []*Toffs
+	Seqnum       int
+	Boost        float32 // used for scoring split WeightedPhraseInfos.
+}
+
+func (info *SubInfo) String() string {
+	return fmt.Sprintf("%v%v", info.Text, info.TermsOffsets)
+}
+
+// vectorhighlight/BaseFragListBuilder.java
+
+const (
+	MARGIN_DEFAULT            = 6
+	MIN_FRAG_CHAR_SIZE_FACTOR = 3
+)
+
+// vectorhighlight/SimpleFragListBuilder.java
+
+/*
+A simple implementation of FragListBuilder. Fragments are grown
+around the phrases, with a margin of context before the first one.
+*/
+type SimpleFragListBuilder struct {
+	margin          int
+	minFragCharSize int
+}
+
+func NewSimpleFragListBuilder() *SimpleFragListBuilder {
+	return NewSimpleFragListBuilderWithMargin(MARGIN_DEFAULT)
+}
+
+func NewSimpleFragListBuilderWithMargin(margin int) *SimpleFragListBuilder {
+	assert2(margin >= 0, "margin(%v) is too small. It must be 0 or higher.", margin)
+	minFragCharSize := margin * MIN_FRAG_CHAR_SIZE_FACTOR
+	if minFragCharSize < 1 {
+		minFragCharSize = 1
+	}
+	return &SimpleFragListBuilder{margin, minFragCharSize}
+}
+
+func (b *SimpleFragListBuilder) CreateFieldFragList(fieldPhraseList *FieldPhraseList,
+	fragCharSize int) *FieldFragList {
+
+	assert2(fragCharSize >= b.minFragCharSize,
+		"fragCharSize(%v) is too small. It must be %v or higher.", fragCharSize, b.minFragCharSize)
+
+	ans := new(FieldFragList)
+	queue := fieldPhraseList.PhraseList
+	startOffset := 0
+	for len(queue) > 0 {
+		phraseInfo := queue[0]
+		// if the phrase violates the border of previous fragment,
+		// discard it and try next phrase
+		if phraseInfo.StartOffset() < startOffset {
+			queue = queue[1:]
+			continue
+		}
+
+		var wpil []*WeightedPhraseInfo
+		currentPhraseStartOffset := phraseInfo.StartOffset()
+		currentPhraseEndOffset := phraseInfo.EndOffset()
+		spanStart := currentPhraseStartOffset - b.margin
+		if spanStart < startOffset {
+			spanStart = startOffset
+		}
+		spanEnd := spanStart + fragCharSize
+		if currentPhraseEndOffset > spanEnd {
+			spanEnd = currentPhraseEndOffset
+		}
+		queue = queue[1:]
+		if acceptPhrase(phraseInfo, currentPhraseEndOffset-currentPhraseStartOffset, fragCharSize) {
+			wpil = append(wpil, phraseInfo)
+		}
+		// pull until we crossed the current spanEnd
+		for len(queue) > 0 && queue[0].EndOffset() <= spanEnd {
+			phraseInfo = queue[0]
+			queue = queue[1:]
+			currentPhraseEndOffset = phraseInfo.EndOffset()
+			if acceptPhrase(phraseInfo, currentPhraseEndOffset-currentPhraseStartOffset, fragCharSize) {
+				wpil = append(wpil, phraseInfo)
+			}
+		}
+		if len(wpil) == 0 {
+			continue
+		}
+
+		matchLen := currentPhraseEndOffset - currentPhraseStartOffset
+		// now recalculate the start offset to center the highlight terms
+		newMargin := (fragCharSize - matchLen) / 2
+		if newMargin < 0 {
+			newMargin = 0
+		}
+		spanStart = currentPhraseStartOffset - newMargin
+		if spanStart < startOffset {
+			spanStart = startOffset
+		}
+		// whatever is bigger here we grow this out
+		if matchLen > fragCharSize {
+			spanEnd = spanStart + matchLen
+		} else {
+			spanEnd = spanStart + fragCharSize
+		}
+		startOffset = spanEnd
+		ans.add(spanStart, spanEnd, wpil)
+	}
+	return ans
+}
+
+/*
+A predicate to decide if the given WeightedPhraseInfo should be
+accepted as a highlighted phrase or if it should be discarded. The
+default implementation discards phrases that are composed of more
+than one term and where the matchLength exceeds the fragment
+character size.
+*/
+func acceptPhrase(info *WeightedPhraseInfo, matchLength, fragCharSize int) bool {
+	return len(info.TermsOffsets) <= 1 || matchLength <= fragCharSize
+}
+
+// vectorhighlight/SingleFragListBuilder.java
+
+/*
+An implementation class of FragListBuilder that generates one
+WeightedFragInfo object. Typical use case of this class is that you
+can get an entire field contents by using both of this class and
+SimpleFragmentsBuilder.
+*/
+type SingleFragListBuilder struct{}
+
+func NewSingleFragListBuilder() *SingleFragListBuilder {
+	return new(SingleFragListBuilder)
+}
+
+func (b *SingleFragListBuilder) CreateFieldFragList(fieldPhraseList *FieldPhraseList,
+	fragCharSize int) *FieldFragList {
+
+	ans := new(FieldFragList)
+	if len(fieldPhraseList.PhraseList) > 0 {
+		ans.add(0, math.MaxInt32, fieldPhraseList.PhraseList)
+	}
+	return ans
+}
+
+func assert2(ok bool, msg string, args ...interface{}) {
+	if !ok {
+		panic(fmt.Sprintf(msg, args...))
+	}
+}
@@ -0,0 +1,271 @@
+package vectorhighlight
+
+import (
+	"bytes"
+	"github.com/balzaczyy/golucene/core/index"
+	"sort"
+	"strings"
+)
+
+// vectorhighlight/FragmentsBuilder.java
+
+/*
+FragmentsBuilder is an interface for fragments (snippets) builder
+classes. A FragmentsBuilder class can be plugged in to
+FastVectorHighlighter.
+*/
+type FragmentsBuilder interface {
+	// Create a fragment.
+	CreateFragment(reader index.IndexReader, docId int, fieldName string,
+		fieldFragList *FieldFragList) (string, error)
+	// Create multiple fragments.
+	CreateFragments(reader index.IndexReader, docId int, fieldName string,
+		fieldFragList *FieldFragList, maxNumFragments int) ([]string, error)
+	// Create multiple fragments, with custom tags and encoder.
+	CreateFragmentsWithTags(reader index.IndexReader, docId int, fieldName string,
+		fieldFragList *FieldFragList, maxNumFragments int,
+		preTags, postTags []string, encoder Encoder) ([]string, error)
+}
+
+// vectorhighlight/BaseFragmentsBuilder.java
+
+var (
+	COLORED_PRE_TAGS = []string{
+		`<b style="background:yellow">`, `<b style="background:lawngreen">`, `<b style="background:aquamarine">`,
+		`<b style="background:magenta">`, `<b style="background:palegreen">`, `<b style="background:coral">`,
+		`<b style="background:wheat">`, `<b style="background:khaki">`, `<b style="background:lime">`,
+		`<b style="background:deepskyblue">`, `<b style="background:deeppink">`, `<b style="background:salmon">`,
+		`<b style="background:peachpuff">`, `<b style="background:violet">`, `<b style="background:mediumpurple">`,
+		`<b style="background:palegoldenrod">`, `<b style="background:darkkhaki">`, `<b style="background:springgreen">`,
+		`<b style="background:turquoise">`, `<b style="background:powderblue">`,
+	}
+	COLORED_POST_TAGS = []string{"</b>"}
+
+	DEFAULT_PRE_TAGS  = []string{"<b>"}
+	DEFAULT_POST_TAGS = []string{"</b>"}
+)
+
+type fragmentsBuilderSPI interface {
+	// Orders the frag infos; the first maxNumFragments are formatted.
+	WeightedFragInfoList(src []*WeightedFragInfo) []*WeightedFragInfo
+}
+
+/*
+Base FragmentsBuilder implementation that supports colored pre/post
+tags and multivalued fields. Uses BoundaryScanner to determine
+fragments.
+*/
+type BaseFragmentsBuilder struct {
+	spi                  fragmentsBuilderSPI
+	preTags              []string
+	postTags             []string
+	multiValuedSeparator rune
+	boundaryScanner      BoundaryScanner
+}
+
+func newBaseFragmentsBuilder(spi fragmentsBuilderSPI, preTags, postTags []string,
+	bs BoundaryScanner) *BaseFragmentsBuilder {
+
+	return &BaseFragmentsBuilder{
+		spi:                  spi,
+		preTags:              preTags,
+		postTags:             postTags,
+		multiValuedSeparator: ' ',
+		boundaryScanner:      bs,
+	}
+}
+
+/*
+Sets the separator inserted between the values of a multi-valued
+field; a space by default.
+*/
+func (b *BaseFragmentsBuilder) SetMultiValuedSeparator(separator rune) {
+	b.multiValuedSeparator = separator
+}
+
+func (b *BaseFragmentsBuilder) CreateFragment(reader index.IndexReader, docId int,
+	fieldName string, fieldFragList *FieldFragList) (string, error) {
+
+	fragments, err := b.CreateFragments(reader, docId, fieldName, fieldFragList, 1)
+	if err != nil || len(fragments) == 0 {
+		return "", err
+	}
+	return fragments[0], nil
+}
+
+func (b *BaseFragmentsBuilder) CreateFragments(reader index.IndexReader, docId int,
+	fieldName string, fieldFragList *FieldFragList, maxNumFragments int) ([]string, error) {
+
+	return b.CreateFragmentsWithTags(reader, docId, fieldName, fieldFragList,
+		maxNumFragments, b.preTags, b.postTags, DefaultEncoder{})
+}
+
+func (b *BaseFragmentsBuilder) CreateFragmentsWithTags(reader index.IndexReader, docId int,
+	fieldName string, fieldFragList *FieldFragList, maxNumFragments int,
+	preTags, postTags []string, encoder Encoder) ([]string, error) {
+
+	assert2(maxNumFragments >= 0, "maxNumFragments(%v) must be positive number.", maxNumFragments)
+
+	doc, err := reader.Document(docId)
+	if err != nil {
+		return nil, err
+	}
+	return b.createFragments(doc.Values(fieldName), fieldFragList,
+		maxNumFragments, preTags, postTags, encoder), nil
+}
+
+func (b *BaseFragmentsBuilder) createFragments(values []string, fieldFragList *FieldFragList,
+	maxNumFragments int, preTags, postTags []string, encoder Encoder) []string {
+
+	if len(values) == 0 {
+		return nil
+	}
+	fragInfos := b.spi.WeightedFragInfoList(fieldFragList.FragInfos)
+
+	sep := string(b.multiValuedSeparator)
+	buffer := []rune(strings.Join(values, sep) + sep)
+	var fragments []string
+	for n := 0; n < maxNumFragments && n < len(fragInfos); n++ {
+		fragments = append(fragments, b.makeFragment(buffer, fragInfos[n], preTags, postTags, encoder))
+	}
+	return fragments
+}
+
+func (b *BaseFragmentsBuilder) makeFragment(buffer []rune, fragInfo *WeightedFragInfo,
+	preTags, postTags []string, encoder Encoder) string {
+
+	src, modifiedStartOffset := b.fragmentSource(buffer, fragInfo.StartOffset, fragInfo.EndOffset)
+
+	var offsets []*taggedToffs
+	for _, subInfo := range fragInfo.SubInfos {
+		for _, to := range subInfo.TermsOffsets {
+			offsets = append(offsets, &taggedToffs{to, subInfo.Seqnum})
+		}
+	}
+	sort.Stable(taggedToffsByStart(offsets))
+
+	var fragment bytes.Buffer
+	srcIndex := 0
+	for _, to := range offsets {
+		start := to.StartOffset - modifiedStartOffset
+		end := to.EndOffset - modifiedStartOffset
+		if start < srcIndex || end > len(src) {
+			continue // overlaps the previous highlight, or the fragment's bounds
+		}
+		fragment.WriteString(encoder.EncodeText(string(src[srcIndex:start])))
+		fragment.WriteString(preTag(preTags, to.seqnum))
+		fragment.WriteString(encoder.EncodeText(string(src[start:end])))
+		fragment.WriteString(postTag(postTags, to.seqnum))
+		srcIndex = end
+	}
+	fragment.WriteString(encoder.EncodeText(string(src[srcIndex:])))
+	return fragment.String()
+}
+
+/*
+Returns the text of the fragment, with its bounds moved to the
+nearest boundaries, and the modified start offset.
+*/
+func (b *BaseFragmentsBuilder) fragmentSource(buffer []rune, startOffset, endOffset int) ([]rune, int) {
+	if endOffset > len(buffer) {
+		endOffset = len(buffer)
+	}
+	eo := b.boundaryScanner.FindEndOffset(buffer, endOffset)
+	if eo > len(buffer) {
+		eo = len(buffer)
+	}
+	modifiedStartOffset := b.boundaryScanner.FindStartOffset(buffer, startOffset)
+	return buffer[modifiedStartOffset:eo], modifiedStartOffset
+}
+
+func preTag(preTags []string, num int) string {
+	return preTags[num%len(preTags)]
+}
+
+func postTag(postTags []string, num int) string {
+	return postTags[num%len(postTags)]
+}
+
+type taggedToffs struct {
+	*Toffs
+	seqnum int
+}
+
+type taggedToffsByStart []*taggedToffs
+
+func (s taggedToffsByStart) Len() int           { return len(s) }
+func (s taggedToffsByStart) Less(i, j int) bool { return s[i].StartOffset < s[j].StartOffset }
+func (s taggedToffsByStart) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+// vectorhighlight/ScoreOrderFragmentsBuilder.java
+
+/*
+An implementation of FragmentsBuilder that outputs score-order
+fragments.
+*/
+type ScoreOrderFragmentsBuilder struct {
+	*BaseFragmentsBuilder
+}
+
+// A constructor.
+func NewScoreOrderFragmentsBuilder() *ScoreOrderFragmentsBuilder {
+	return NewScoreOrderFragmentsBuilderWith(DEFAULT_PRE_TAGS, DEFAULT_POST_TAGS, NewSimpleBoundaryScanner())
+}
+
+/*
+A constructor, with custom tags: the highlight of the n-th term or
+phrase of the query is surrounded by preTags[n % len(preTags)] and
+postTags[n % len(postTags)], e.g. COLORED_PRE_TAGS.
+*/
+func NewScoreOrderFragmentsBuilderWith(preTags, postTags []string, bs BoundaryScanner) *ScoreOrderFragmentsBuilder {
+	ans := new(ScoreOrderFragmentsBuilder)
+	ans.BaseFragmentsBuilder = newBaseFragmentsBuilder(ans, preTags, postTags, bs)
+	return ans
+}
+
+// Sort by score the list of WeightedFragInfo
+func (b *ScoreOrderFragmentsBuilder) WeightedFragInfoList(src []*WeightedFragInfo) []*WeightedFragInfo {
+	ans := make([]*WeightedFragInfo, len(src))
+	copy(ans, src)
+	sort.Stable(fragInfosByScore(ans))
+	return ans
+}
+
+// Comparator for WeightedFragInfo by boost, breaking ties by offset.
+type fragInfosByScore []*WeightedFragInfo
+
+func (s fragInfosByScore) Len() int      { return len(s) }
+func (s fragInfosByScore) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s fragInfosByScore) Less(i, j int) bool {
+	if s[i].TotalBoost != s[j].TotalBoost {
+		return s[i].TotalBoost > s[j].TotalBoost
+	}
+	return s[i].StartOffset < s[j].StartOffset
+}
+
+// vectorhighlight/SimpleFragmentsBuilder.java
+
+/*
+A simple implementation of FragmentsBuilder, which keeps the
+fragments in the order of the text.
+*/
+type SimpleFragmentsBuilder struct {
+	*BaseFragmentsBuilder
+}
+
+// A constructor.
+func NewSimpleFragmentsBuilder() *SimpleFragmentsBuilder {
+	return NewSimpleFragmentsBuilderWith(DEFAULT_PRE_TAGS, DEFAULT_POST_TAGS, NewSimpleBoundaryScanner())
+}
+
+// A constructor, with custom tags.
+func NewSimpleFragmentsBuilderWith(preTags, postTags []string, bs BoundaryScanner) *SimpleFragmentsBuilder {
+	ans := new(SimpleFragmentsBuilder)
+	ans.BaseFragmentsBuilder = newBaseFragmentsBuilder(ans, preTags, postTags, bs)
+	return ans
+}
+
+// do nothing. return the source list.
+func (b *SimpleFragmentsBuilder) WeightedFragInfoList(src []*WeightedFragInfo) []*WeightedFragInfo {
+	return src
+}
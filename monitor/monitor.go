@@ -0,0 +1,298 @@
+package monitor
+
+import (
+	"fmt"
+	"github.com/balzaczyy/golucene/core/analysis"
+	docu "github.com/balzaczyy/golucene/core/document"
+	"github.com/balzaczyy/golucene/core/index"
+	"github.com/balzaczyy/golucene/core/search"
+	"github.com/balzaczyy/golucene/core/store"
+	"github.com/balzaczyy/golucene/core/util"
+	"sync"
+)
+
+// monitor/MonitorQuery.java
+
+// Defines a query to be stored in a Monitor.
+type MonitorQuery struct {
+	id       string
+	query    search.Query
+	metadata map[string]string
+}
+
+/*
+Creates a new MonitorQuery, identified by id. metadata may be nil; it
+is kept with the query, and can be used by custom Presearchers.
+*/
+func NewMonitorQuery(id string, query search.Query, metadata map[string]string) *MonitorQuery {
+	assert2(id != "", "query id must not be empty")
+	assert2(query != nil, "query must not be nil")
+	return &MonitorQuery{id, query, metadata}
+}
+
+func (q *MonitorQuery) Id() string                  { return q.id }
+func (q *MonitorQuery) Query() search.Query         { return q.query }
+func (q *MonitorQuery) Metadata() map[string]string { return q.metadata }
+
+func (q *MonitorQuery) String() string {
+	return fmt.Sprintf("%v: %v", q.id, q.query)
+}
+
+// monitor/QueryMatch.java
+
+// Represents a match of a registered query against a document.
+type QueryMatch struct {
+	QueryId string
+	Score   float32
+}
+
+// monitor/MatchingQueries.java
+
+// The result of matching a document against the registered queries.
+type MatchingQueries struct {
+	// the matches, by query id
+	Matches map[string]*QueryMatch
+	// the number of candidate queries selected by the Presearcher and
+	// run against the document
+	QueriesRun int
+	// the errors hit while running queries, by query id
+	Errors map[string]error
+}
+
+// Returns the match for the given query id, or nil if it didn't match.
+func (m *MatchingQueries) Match(queryId string) *QueryMatch {
+	return m.Matches[queryId]
+}
+
+func (m *MatchingQueries) MatchCount() int {
+	return len(m.Matches)
+}
+
+// monitor/Monitor.java
+
+// The field used to store the id of a registered query.
+const QUERY_ID_FIELD = "_query_id"
+
+/*
+A Monitor contains a set of Query objects with associated ids, and
+matches them against a set of documents, i.e. it's a percolator:
+documents are matched against stored queries rather than queries
+against stored documents.
+
+Registered queries are indexed into a query index in the provided
+Directory, using the Presearcher to convert each of them into a
+document. An incoming document is indexed into a MemoryIndex; the
+Presearcher then turns its terms into a query over the query index
+which selects the candidate queries, and only the candidates are run
+against the document. This makes matching a document against many
+thousands of registered queries efficient.
+
+The queries themselves are kept in memory, so the query index must
+be private to the monitor and is rebuilt on creation.
+
+A Monitor is safe for concurrent use by multiple goroutines.
+*/
+type Monitor struct {
+	analyzer    analysis.Analyzer
+	presearcher Presearcher
+
+	// guards queries, and orders registrations against refreshes
+	sync.RWMutex
+	queries map[string]*MonitorQuery
+
+	writer  *index.IndexWriter
+	manager *search.SearcherManager
+}
+
+/*
+Creates a new Monitor, using the given Analyzer to analyze documents,
+and the Presearcher to select the queries to run against each of
+them. Existing content of dir is discarded.
+*/
+func NewMonitor(analyzer analysis.Analyzer, presearcher Presearcher, dir store.Directory) (*Monitor, error) {
+	writer, err := index.NewIndexWriter(dir, index.NewIndexWriterConfig(util.VERSION_LATEST, analyzer).
+		SetOpenMode(index.OPEN_MODE_CREATE))
+	if err != nil {
+		return nil, err
+	}
+	manager, err := search.NewSearcherManagerFromWriter(writer, nil)
+	if err != nil {
+		writer.Close()
+		return nil, err
+	}
+	return &Monitor{
+		analyzer:    analyzer,
+		presearcher: presearcher,
+		queries:     make(map[string]*MonitorQuery),
+		writer:      writer,
+		manager:     manager,
+	}, nil
+}
+
+/*
+Registers queries with the monitor. A query replaces any query
+previously registered with the same id. The queries are visible to
+Match() once this method returns.
+*/
+func (m *Monitor) Register(queries ...*MonitorQuery) error {
+	m.Lock()
+	defer m.Unlock()
+	for _, mq := range queries {
+		doc := m.presearcher.IndexQuery(mq.query, mq.metadata)
+		doc.Add(docu.NewStringField(QUERY_ID_FIELD, mq.id, docu.STORE_YES))
+		if err := m.writer.UpdateDocument(index.NewTerm(QUERY_ID_FIELD, mq.id),
+			doc.Fields(), m.analyzer); err != nil {
+			return err
+		}
+		m.queries[mq.id] = mq
+	}
+	return m.manager.MaybeRefreshBlocking()
+}
+
+/*
+Deletes queries from the monitor.
+
+NOTE: as IndexWriter cannot delete documents yet, the entries of
+deleted queries stay in the query index, and are only skipped when
+selected as candidates; they are purged when the id is registered
+again.
+*/
+func (m *Monitor) DeleteById(ids ...string) {
+	m.Lock()
+	defer m.Unlock()
+	for _, id := range ids {
+		delete(m.queries, id)
+	}
+}
+
+// Returns the query registered with the given id, or nil.
+func (m *Monitor) Query(id string) *MonitorQuery {
+	m.RLock()
+	defer m.RUnlock()
+	return m.queries[id]
+}
+
+// Returns the number of registered queries.
+func (m *Monitor) QueryCount() int {
+	m.RLock()
+	defer m.RUnlock()
+	return len(m.queries)
+}
+
+/*
+Matches a document against the registered queries, returning the
+queries that match. Errors hit while running a single query are
+collected in MatchingQueries.Errors rather than returned.
+*/
+func (m *Monitor) Match(doc *docu.Document) (*MatchingQueries, error) {
+	mi, err := m.memoryIndex(doc)
+	if err != nil {
+		return nil, err
+	}
+	reader := mi.CreateReader()
+
+	candidates, err := m.candidates(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	ans := &MatchingQueries{
+		Matches: make(map[string]*QueryMatch),
+		Errors:  make(map[string]error),
+	}
+	searcher := search.NewIndexSearcher(reader)
+	for _, mq := range candidates {
+		ans.QueriesRun++
+		hits, err := searcher.SearchTop(mq.query, 1)
+		if err != nil {
+			ans.Errors[mq.id] = err
+			continue
+		}
+		if hits.TotalHits > 0 {
+			ans.Matches[mq.id] = &QueryMatch{mq.id, hits.ScoreDocs[0].Score}
+		}
+	}
+	return ans, nil
+}
+
+// Indexes the indexed fields of the document into a MemoryIndex.
+func (m *Monitor) memoryIndex(doc *docu.Document) (*index.MemoryIndex, error) {
+	mi := index.NewMemoryIndex()
+	for _, field := range doc.Fields() {
+		if !field.FieldType().Indexed() {
+			continue
+		}
+		stream, err := field.TokenStream(m.analyzer, nil)
+		if err != nil {
+			return nil, err
+		}
+		positionIncrementGap, offsetGap := 0, 1
+		if field.FieldType().Tokenized() {
+			positionIncrementGap = m.analyzer.PositionIncrementGap(field.Name())
+			offsetGap = m.analyzer.OffsetGap(field.Name())
+		}
+		if err = mi.AddFieldFromStreamWith(field.Name(), stream, field.Boost(),
+			positionIncrementGap, offsetGap); err != nil {
+			return nil, err
+		}
+	}
+	return mi, nil
+}
+
+// Returns the registered queries the Presearcher selects for the
+// document in reader.
+func (m *Monitor) candidates(reader index.AtomicReader) ([]*MonitorQuery, error) {
+	query, err := m.presearcher.BuildQuery(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	searcher, err := m.manager.Acquire()
+	if err != nil {
+		return nil, err
+	}
+	defer m.manager.Release(searcher)
+
+	m.RLock()
+	defer m.RUnlock()
+	maxDoc := searcher.IndexReader().MaxDoc()
+	if maxDoc == 0 {
+		return nil, nil
+	}
+	hits, err := searcher.SearchTop(query, maxDoc)
+	if err != nil {
+		return nil, err
+	}
+	var ans []*MonitorQuery
+	seen := make(map[string]bool)
+	for _, hit := range hits.ScoreDocs {
+		doc, err := searcher.IndexReader().Document(hit.Doc)
+		if err != nil {
+			return nil, err
+		}
+		id := doc.Get(QUERY_ID_FIELD)
+		// skip replaced or deleted queries
+		if mq, ok := m.queries[id]; ok && !seen[id] {
+			seen[id] = true
+			ans = append(ans, mq)
+		}
+	}
+	return ans, nil
+}
+
+// Closes the monitor and its query index.
+func (m *Monitor) Close() error {
+	m.Lock()
+	defer m.Unlock()
+	err := m.manager.Close()
+	if err2 := m.writer.Close(); err == nil {
+		err = err2
+	}
+	return err
+}
+
+func assert2(ok bool, msg string, args ...interface{}) {
+	if !ok {
+		panic(fmt.Sprintf(msg, args...))
+	}
+}
@@ -0,0 +1,100 @@
+package monitor
+
+import (
+	"fmt"
+	std "github.com/balzaczyy/golucene/analysis/standard"
+	_ "github.com/balzaczyy/golucene/core/codec/lucene410"
+	docu "github.com/balzaczyy/golucene/core/document"
+	"github.com/balzaczyy/golucene/core/index"
+	"github.com/balzaczyy/golucene/core/search"
+	"github.com/balzaczyy/golucene/core/store"
+	"testing"
+)
+
+func termQuery(field, text string) search.Query {
+	return search.NewTermQuery(index.NewTerm(field, text))
+}
+
+func TestMonitor(t *testing.T) {
+	dir, err := store.OpenFSDirectory(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := NewMonitor(std.NewStandardAnalyzer(), NewTermFilteredPresearcher(), dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+
+	var queries []*MonitorQuery
+	for i := 0; i < 1000; i++ {
+		queries = append(queries, NewMonitorQuery(fmt.Sprintf("q%v", i),
+			termQuery("text", fmt.Sprintf("word%v", i)), nil))
+	}
+	both := search.NewBooleanQuery()
+	both.Add(termQuery("text", "salmon"), search.MUST)
+	both.Add(termQuery("text", "alaska"), search.MUST)
+	queries = append(queries,
+		NewMonitorQuery("both", both, nil),
+		NewMonitorQuery("keyword", termQuery("tag", "Fishing Trip"), nil),
+		NewMonitorQuery("all", search.NewMatchAllDocsQuery(), nil))
+	if err = m.Register(queries...); err != nil {
+		t.Fatal(err)
+	}
+	if n := m.QueryCount(); n != 1003 {
+		t.Fatalf("Expected 1003 queries, but was %v", n)
+	}
+
+	match := func(text, tag string) *MatchingQueries {
+		doc := docu.NewDocument()
+		doc.Add(docu.NewTextFieldFromString("text", text, docu.STORE_NO))
+		doc.Add(docu.NewStringField("tag", tag, docu.STORE_NO))
+		matches, err := m.Match(doc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(matches.Errors) > 0 {
+			t.Fatalf("Expected no errors, but was %v", matches.Errors)
+		}
+		return matches
+	}
+
+	matches := match("salmon word7 about word42", "Fishing Trip")
+	for _, id := range []string{"q7", "q42", "keyword", "all"} {
+		if matches.Match(id) == nil {
+			t.Errorf("Expected %v to match", id)
+		}
+	}
+	if n := matches.MatchCount(); n != 4 {
+		t.Errorf("Expected 4 matches, but was %v", n)
+	}
+	// only the candidates sharing a term are run, not the 1003 queries
+	if matches.QueriesRun != 5 {
+		t.Errorf("Expected 5 queries run, but was %v", matches.QueriesRun)
+	}
+
+	matches = match("salmon in alaska", "fishing trip")
+	if matches.Match("both") == nil || matches.MatchCount() != 2 {
+		t.Errorf("Expected both and all to match, but was %v", matches.Matches)
+	}
+
+	// replace and delete
+	if err = m.Register(NewMonitorQuery("q7", termQuery("text", "trout"), nil)); err != nil {
+		t.Fatal(err)
+	}
+	m.DeleteById("all", "q42")
+	assertMatches := func(matches *MatchingQueries, ids ...string) {
+		if len(ids) != matches.MatchCount() {
+			t.Errorf("Expected %v, but was %v", ids, matches.Matches)
+		}
+		for _, id := range ids {
+			if matches.Match(id) == nil {
+				t.Errorf("Expected %v to match", id)
+			}
+		}
+	}
+	assertMatches(match("word7 word42 trout", "Fishing Trip"), "q7", "keyword")
+	if m.Query("all") != nil || m.QueryCount() != 1001 {
+		t.Errorf("Expected deleted queries to be gone, but was %v", m.QueryCount())
+	}
+}
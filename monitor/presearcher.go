@@ -0,0 +1,116 @@
+package monitor
+
+import (
+	docu "github.com/balzaczyy/golucene/core/document"
+	"github.com/balzaczyy/golucene/core/index"
+	"github.com/balzaczyy/golucene/core/search"
+)
+
+// monitor/Presearcher.java
+
+/*
+A Presearcher is used by the Monitor to reduce the number of queries
+actually run against a document. It converts registered queries into
+documents of the query index, and incoming documents into queries
+selecting the registered queries that may match them.
+
+A Presearcher may select queries that turn out not to match (these
+are filtered out by running them against the document), but it must
+never leave out a query that matches.
+*/
+type Presearcher interface {
+	/*
+		Builds a query over the query index, selecting the registered
+		queries that may match the document in the given reader.
+	*/
+	BuildQuery(reader index.AtomicReader) (search.Query, error)
+	// Builds the document to index for a registered query.
+	IndexQuery(query search.Query, metadata map[string]string) *docu.Document
+}
+
+// monitor/TermFilteredPresearcher.java
+
+const (
+	// The field used to index queries that cannot be filtered by terms.
+	ANYTOKEN_FIELD = "__anytokenfield"
+	// The term used to index queries that cannot be filtered by terms.
+	ANYTOKEN = "__ANYTOKEN__"
+)
+
+/*
+Presearcher that indexes the terms extracted from each registered
+query under their own field, and selects the queries sharing at
+least one term with the document.
+
+Queries with parts that no terms can be extracted from (e.g.
+MatchAllDocsQuery) are also indexed with ANYTOKEN, which every
+document selects, so that they are always run.
+*/
+type TermFilteredPresearcher struct{}
+
+func NewTermFilteredPresearcher() *TermFilteredPresearcher {
+	return &TermFilteredPresearcher{}
+}
+
+func (p *TermFilteredPresearcher) BuildQuery(reader index.AtomicReader) (search.Query, error) {
+	bq := search.NewBooleanQueryDisableCoord(true)
+	for _, fi := range reader.FieldInfos().Values {
+		terms := reader.Terms(fi.Name)
+		if terms == nil {
+			continue
+		}
+		it := terms.Iterator(nil)
+		for {
+			term, err := it.Next()
+			if err != nil {
+				return nil, err
+			}
+			if term == nil {
+				break
+			}
+			bq.Add(search.NewTermQuery(index.NewTermFromBytes(fi.Name, term)), search.SHOULD)
+		}
+	}
+	bq.Add(search.NewTermQuery(index.NewTerm(ANYTOKEN_FIELD, ANYTOKEN)), search.SHOULD)
+	return bq, nil
+}
+
+func (p *TermFilteredPresearcher) IndexQuery(query search.Query, metadata map[string]string) *docu.Document {
+	doc := docu.NewDocument()
+	collector := newPresearcherTermCollector()
+	query.Visit(collector)
+	if collector.anyToken || collector.terms.Size() == 0 {
+		doc.Add(docu.NewStringField(ANYTOKEN_FIELD, ANYTOKEN, docu.STORE_NO))
+	}
+	for _, term := range collector.terms.Terms {
+		doc.Add(docu.NewStringField(term.Field, string(term.Bytes), docu.STORE_NO))
+	}
+	return doc
+}
+
+/*
+Collects the terms of a query. Any part of the query that is not
+filterable by terms (e.g. MatchAllDocsQuery, or a query matching an
+automaton) makes it match ANYTOKEN, so that it is always run.
+*/
+type presearcherTermCollector struct {
+	*search.QueryVisitorImpl
+	terms    *index.TermSet
+	anyToken bool
+}
+
+func newPresearcherTermCollector() *presearcherTermCollector {
+	ans := &presearcherTermCollector{terms: index.NewTermSet()}
+	ans.QueryVisitorImpl = search.NewQueryVisitorImpl(ans)
+	return ans
+}
+
+func (v *presearcherTermCollector) ConsumeTerms(query search.Query, terms ...*index.Term) {
+	for _, t := range terms {
+		v.terms.Add(t)
+	}
+}
+
+func (v *presearcherTermCollector) VisitLeaf(query search.Query) {
+	v.anyToken = true
+}
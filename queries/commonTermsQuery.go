@@ -0,0 +1,283 @@
+package queries
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/balzaczyy/golucene/core/index"
+	"github.com/balzaczyy/golucene/core/search"
+	"math"
+)
+
+// queries/CommonTermsQuery.java
+
+/*
+A query that executes high-frequency terms in an optional sub-query
+to prevent slow queries due to "common" terms like stopwords. This
+query builds 2 queries off the added terms: low-frequency terms are
+added to a required boolean clause and high-frequency terms are added
+to an optional boolean clause. The optional clause is only executed
+if the required "low-frequency" clause matches. Scores produced by
+this query will be slightly different than plain BooleanQuery scorer
+mainly due to differences in the number of leaf queries in the
+required boolean clause. In most cases, high-frequency terms are
+unlikely to significantly contribute to the document score unless at
+least one of the low-frequency terms are matched. This query can
+improve query execution times significantly if applicable.
+
+CommonTermsQuery has several advantages over stopword filtering at
+index or query time since a term can be "classified" based on the
+actual document frequency in the index and can prevent slow queries
+even across domains without specialized stopword files.
+
+Note: if the query only contains high-frequency terms the query is
+rewritten into a plain conjunction query ie. all high-frequency terms
+need to match in order to match a document.
+*/
+type CommonTermsQuery struct {
+	*search.AbstractQuery
+	terms                    []*index.Term
+	disableCoord             bool
+	maxTermFrequency         float32
+	lowFreqOccur             search.Occur
+	highFreqOccur            search.Occur
+	lowFreqBoost             float32
+	highFreqBoost            float32
+	lowFreqMinNrShouldMatch  float32
+	highFreqMinNrShouldMatch float32
+}
+
+/*
+Creates a new CommonTermsQuery. highFreqOccur is the Occur used for
+high frequency terms, lowFreqOccur the one used for low frequency
+terms. maxTermFrequency is a value in [0..1) (or absolute number
+>=1) representing the maximum threshold of a terms document frequency
+to be considered a low frequency term.
+*/
+func NewCommonTermsQuery(highFreqOccur, lowFreqOccur search.Occur,
+	maxTermFrequency float32) *CommonTermsQuery {
+	return NewCommonTermsQueryDisableCoord(highFreqOccur, lowFreqOccur, maxTermFrequency, false)
+}
+
+/*
+Creates a new CommonTermsQuery, which disables the coord factor of
+its boolean queries if disableCoord is true.
+*/
+func NewCommonTermsQueryDisableCoord(highFreqOccur, lowFreqOccur search.Occur,
+	maxTermFrequency float32, disableCoord bool) *CommonTermsQuery {
+
+	if highFreqOccur == search.MUST_NOT {
+		panic("highFreqOccur should be MUST or SHOULD but was MUST_NOT")
+	}
+	if lowFreqOccur == search.MUST_NOT {
+		panic("lowFreqOccur should be MUST or SHOULD but was MUST_NOT")
+	}
+	ans := &CommonTermsQuery{
+		disableCoord:     disableCoord,
+		highFreqOccur:    highFreqOccur,
+		lowFreqOccur:     lowFreqOccur,
+		maxTermFrequency: maxTermFrequency,
+		lowFreqBoost:     1,
+		highFreqBoost:    1,
+	}
+	ans.AbstractQuery = search.NewAbstractQuery(ans)
+	return ans
+}
+
+/*
+Adds a term to the CommonTermsQuery. All terms are looked up in their
+own field, though they usually share one.
+*/
+func (q *CommonTermsQuery) Add(term *index.Term) {
+	if term == nil {
+		panic("Term must not be nil")
+	}
+	q.terms = append(q.terms, term)
+}
+
+// Returns the terms of this query.
+func (q *CommonTermsQuery) Terms() []*index.Term {
+	return q.terms
+}
+
+// Returns true iff coord factor scoring is disabled in the
+// boolean queries this query rewrites to.
+func (q *CommonTermsQuery) IsCoordDisabled() bool {
+	return q.disableCoord
+}
+
+// Sets the boost used for the low frequency terms clause.
+func (q *CommonTermsQuery) SetLowFreqBoost(boost float32) {
+	q.lowFreqBoost = boost
+}
+
+// Sets the boost used for the high frequency terms clause.
+func (q *CommonTermsQuery) SetHighFreqBoost(boost float32) {
+	q.highFreqBoost = boost
+}
+
+/*
+Specifies a minimum number of the low frequent optional
+BooleanClauses which must be satisfied in order to produce a match on
+the low frequency terms query part. This method accepts a float value
+in the range [0..1) as a fraction of the actual query terms in the
+low frequent clause or a number >=1 as an absolute number of clauses
+that need to match.
+
+By default no optional clauses are necessary for a match (unless
+there are no required clauses). If this method is used, then the
+specified number of clauses is required.
+*/
+func (q *CommonTermsQuery) SetLowFreqMinimumNumberShouldMatch(min float32) {
+	q.lowFreqMinNrShouldMatch = min
+}
+
+// Gets the minimum number of the optional low frequent BooleanClauses
+// which must be satisfied.
+func (q *CommonTermsQuery) LowFreqMinimumNumberShouldMatch() float32 {
+	return q.lowFreqMinNrShouldMatch
+}
+
+/*
+Specifies a minimum number of the high frequent optional
+BooleanClauses which must be satisfied in order to produce a match on
+the high frequency terms query part. This method accepts a float
+value in the range [0..1) as a fraction of the actual query terms in
+the high frequent clause or a number >=1 as an absolute number of
+clauses that need to match.
+*/
+func (q *CommonTermsQuery) SetHighFreqMinimumNumberShouldMatch(min float32) {
+	q.highFreqMinNrShouldMatch = min
+}
+
+// Gets the minimum number of the optional high frequent BooleanClauses
+// which must be satisfied.
+func (q *CommonTermsQuery) HighFreqMinimumNumberShouldMatch() float32 {
+	return q.highFreqMinNrShouldMatch
+}
+
+func (q *CommonTermsQuery) Rewrite(reader index.IndexReader) search.Query {
+	switch len(q.terms) {
+	case 0:
+		return search.NewBooleanQuery()
+	case 1:
+		tq := search.NewTermQuery(q.terms[0])
+		tq.SetBoost(q.Boost())
+		return tq
+	}
+	contexts := make([]*index.TermContext, len(q.terms))
+	for i, term := range q.terms {
+		ctx, err := index.NewTermContextFromTerm(reader.Context(), term)
+		if err != nil {
+			panic(err) // Rewrite() has no error to return
+		}
+		if ctx.DocFreq > 0 {
+			contexts[i] = ctx
+		}
+	}
+	return q.buildQuery(reader.MaxDoc(), contexts)
+}
+
+func minNrShouldMatch(minNrShouldMatch float32, numOptional int) int {
+	if minNrShouldMatch >= 1 || minNrShouldMatch == 0 {
+		return int(minNrShouldMatch)
+	}
+	return int(math.Floor(float64(minNrShouldMatch)*float64(numOptional) + 0.5))
+}
+
+/*
+Splits the terms into the low and high frequency clauses, by their
+document frequency against maxDoc, and combines them. A term that
+doesn't exist in the index is added to the low frequency clause.
+*/
+func (q *CommonTermsQuery) buildQuery(maxDoc int, contexts []*index.TermContext) search.Query {
+	var lowFreq, highFreq []search.Query
+	for i, term := range q.terms {
+		ctx := contexts[i]
+		if ctx == nil {
+			lowFreq = append(lowFreq, search.NewTermQuery(term))
+		} else if (q.maxTermFrequency >= 1 && float32(ctx.DocFreq) > q.maxTermFrequency) ||
+			ctx.DocFreq > int(math.Ceil(float64(q.maxTermFrequency*float32(maxDoc)))) {
+			highFreq = append(highFreq, search.NewTermQueryWithStates(term, ctx))
+		} else {
+			lowFreq = append(lowFreq, search.NewTermQueryWithStates(term, ctx))
+		}
+	}
+
+	newBooleanQuery := func(queries []search.Query, occur search.Occur,
+		boost, minShouldMatch float32) *search.BooleanQuery {
+
+		bq := search.NewBooleanQueryDisableCoord(q.disableCoord)
+		bq.SetBoost(boost)
+		for _, query := range queries {
+			bq.Add(query, occur)
+		}
+		if occur == search.SHOULD && len(queries) > 0 {
+			bq.SetMinimumNumberShouldMatch(minNrShouldMatch(minShouldMatch, len(queries)))
+		}
+		return bq
+	}
+
+	if len(lowFreq) == 0 {
+		// if lowFreq is empty we rewrite the high freq terms in a
+		// conjunction to prevent slow queries.
+		occur := q.highFreqOccur
+		if minNrShouldMatch(q.highFreqMinNrShouldMatch, len(highFreq)) == 0 {
+			occur = search.MUST
+		}
+		return newBooleanQuery(highFreq, occur, q.Boost(), q.highFreqMinNrShouldMatch)
+	}
+	lowFreqQuery := newBooleanQuery(lowFreq, q.lowFreqOccur, q.lowFreqBoost, q.lowFreqMinNrShouldMatch)
+	if len(highFreq) == 0 {
+		// only do low freq terms - we don't have high freq terms
+		lowFreqQuery.SetBoost(q.Boost())
+		return lowFreqQuery
+	}
+	highFreqQuery := newBooleanQuery(highFreq, q.highFreqOccur, q.highFreqBoost, q.highFreqMinNrShouldMatch)
+	ans := search.NewBooleanQueryDisableCoord(true)
+	ans.Add(highFreqQuery, search.SHOULD)
+	ans.Add(lowFreqQuery, search.MUST)
+	ans.SetBoost(q.Boost())
+	return ans
+}
+
+func (q *CommonTermsQuery) ExtractTerms(terms *index.TermSet) {
+	for _, t := range q.terms {
+		terms.Add(t)
+	}
+}
+
+func (q *CommonTermsQuery) Visit(visitor search.QueryVisitor) {
+	sub := visitor.SubVisitor(search.SHOULD, q)
+	if sub == nil {
+		return
+	}
+	for _, t := range q.terms {
+		if sub.AcceptField(t.Field) {
+			sub.ConsumeTerms(q, t)
+		}
+	}
+}
+
+func (q *CommonTermsQuery) ToString(field string) string {
+	var buf bytes.Buffer
+	needParens := q.Boost() != 1 || q.lowFreqMinNrShouldMatch > 0
+	if needParens {
+		buf.WriteRune('(')
+	}
+	for i, t := range q.terms {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(search.NewTermQuery(t).ToString(field))
+	}
+	if needParens {
+		buf.WriteRune(')')
+	}
+	if q.lowFreqMinNrShouldMatch > 0 || q.highFreqMinNrShouldMatch > 0 {
+		fmt.Fprintf(&buf, "~(%v%v)", q.lowFreqMinNrShouldMatch, q.highFreqMinNrShouldMatch)
+	}
+	if q.Boost() != 1 {
+		fmt.Fprintf(&buf, "^%v", q.Boost())
+	}
+	return buf.String()
+}
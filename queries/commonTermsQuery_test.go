@@ -0,0 +1,119 @@
+package queries
+
+import (
+	std "github.com/balzaczyy/golucene/analysis/standard"
+	_ "github.com/balzaczyy/golucene/core/codec/lucene410"
+	docu "github.com/balzaczyy/golucene/core/document"
+	"github.com/balzaczyy/golucene/core/index"
+	"github.com/balzaczyy/golucene/core/search"
+	"github.com/balzaczyy/golucene/core/store"
+	"github.com/balzaczyy/golucene/core/util"
+	"testing"
+)
+
+func TestCommonTermsQuery(t *testing.T) {
+	dir, err := store.OpenFSDirectory(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := index.NewIndexWriter(dir, index.NewIndexWriterConfig(util.VERSION_LATEST, std.NewStandardAnalyzer()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, text := range []string{
+		"lucene search engine",
+		"lucene release notes",
+		"lucene golang port",
+		"golang search library",
+		"lucene index format",
+	} {
+		doc := docu.NewDocument()
+		doc.Add(docu.NewTextFieldFromString("text", text, docu.STORE_YES))
+		if err = w.AddDocument(doc.Fields()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	r, err := index.OpenDirectoryReader(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	ss := search.NewIndexSearcher(r)
+
+	newQuery := func(maxTermFrequency float32, words ...string) *CommonTermsQuery {
+		q := NewCommonTermsQuery(search.SHOULD, search.SHOULD, maxTermFrequency)
+		for _, word := range words {
+			q.Add(index.NewTerm("text", word))
+		}
+		return q
+	}
+	hits := func(q search.Query, expected ...int) {
+		docs, err := ss.SearchTop(q, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if docs.TotalHits != len(expected) {
+			t.Fatalf("Expected %v to match %v, but was %v", q, expected, docs.ScoreDocs)
+		}
+		for i, doc := range expected {
+			if docs.ScoreDocs[i].Doc != doc {
+				t.Errorf("Expected %v to rank %v, but was %v", q, expected, docs.ScoreDocs)
+			}
+		}
+	}
+
+	// lucene is common, so only optional
+	q := newQuery(0.5, "lucene", "search")
+	rewritten := q.Rewrite(r).(*search.BooleanQuery)
+	clauses := rewritten.Clauses()
+	if len(clauses) != 2 || clauses[0].Occur() != search.SHOULD || clauses[1].Occur() != search.MUST {
+		t.Fatalf("Expected optional high and required low freq clauses, but was %v", rewritten)
+	}
+	hits(q, 0, 3)
+
+	// only common terms are all required
+	q = newQuery(1, "lucene", "search")
+	rewritten = q.Rewrite(r).(*search.BooleanQuery)
+	for _, c := range rewritten.Clauses() {
+		if c.Occur() != search.MUST {
+			t.Errorf("Expected conjunction of high freq terms, but was %v", rewritten)
+		}
+	}
+	hits(q, 0)
+
+	// only rare terms, at least two of them
+	q = newQuery(0.5, "search", "engine", "golang")
+	q.SetLowFreqMinimumNumberShouldMatch(2)
+	assertEquals(t, "(text:search, text:engine, text:golang)~(20)", q.String())
+	docs, err := ss.SearchTop(q, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEquals(t, 2, docs.TotalHits)
+	for _, hit := range docs.ScoreDocs {
+		if hit.Doc != 0 && hit.Doc != 3 {
+			t.Errorf("Unexpected hit %v", hit)
+		}
+	}
+
+	// as a fraction of the optional terms
+	q.SetLowFreqMinimumNumberShouldMatch(0.9)
+	docs, err = ss.SearchTop(q, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEquals(t, 0, docs.TotalHits)
+
+	// a missing term is just rare
+	q = newQuery(0.5, "lucene", "missing")
+	hits(q)
+}
+
+func assertEquals(t *testing.T, a, b interface{}) {
+	if a != b {
+		t.Errorf("Expected '%v', but '%v'", a, b)
+	}
+}
@@ -0,0 +1,637 @@
+package mlt
+
+import (
+	"errors"
+	"fmt"
+	"github.com/balzaczyy/golucene/core/analysis"
+	ta "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"github.com/balzaczyy/golucene/core/index"
+	"github.com/balzaczyy/golucene/core/index/model"
+	"github.com/balzaczyy/golucene/core/search"
+	"math"
+	"sort"
+	"strings"
+)
+
+// queries/mlt/MoreLikeThis.java
+
+const (
+	// Default maximum number of tokens to parse in each example doc
+	// field that is not stored with TermVector support.
+	DEFAULT_MAX_NUM_TOKENS_PARSED = 5000
+	// Ignore terms with less than this frequency in the source doc.
+	DEFAULT_MIN_TERM_FREQ = 2
+	// Ignore words which do not occur in at least this many docs.
+	DEFAULT_MIN_DOC_FREQ = 5
+	// Ignore words which occur in more than this many docs.
+	DEFAULT_MAX_DOC_FREQ = math.MaxInt32
+	// Boost terms in query based on score.
+	DEFAULT_BOOST = false
+	// Ignore words less than this length or if 0 then this has no
+	// effect.
+	DEFAULT_MIN_WORD_LENGTH = 0
+	// Ignore words greater than this length or if 0 then this has no
+	// effect.
+	DEFAULT_MAX_WORD_LENGTH = 0
+	// Return a Query with no more than this many terms.
+	DEFAULT_MAX_QUERY_TERMS = 25
+)
+
+// Default field names. Null is used to specify that the field names
+// should be looked up at runtime from the provided reader.
+var DEFAULT_FIELD_NAMES = []string{"contents"}
+
+/*
+Generate "more like this" similarity queries. Based on this mail:
+
+	Lucene does let you access the document frequency of terms, with
+	IndexReader.DocFreq(). Term frequencies can be computed by
+	re-tokenizing the text, which, for a single document, is usually
+	fast enough. But looking up the DocFreq() of every term in the
+	document is probably too slow.
+
+	You can use some heuristics to prune the set of terms, to avoid
+	calling DocFreq() too much, or at all. Since you're trying to
+	maximize a tf*idf score, you're probably most interested in terms
+	with a high tf. Choosing a tf threshold even as low as two or three
+	will radically reduce the number of terms under consideration.
+	Another heuristic is that terms with a high idf (i.e., a low df)
+	tend to be longer. So you could threshold the terms by the number
+	of characters, not selecting anything less than, e.g., six or seven
+	characters. With these sorts of heuristics you can usually find
+	small set of, e.g., ten or fewer terms that do a pretty good job of
+	characterizing a document.
+
+	It all depends on what you're trying to do. If you're trying to eek
+	out that last percent of precision and recall regardless of
+	computational difficulty so that you can win a TREC competition,
+	then the techniques I mention above are useless. But if you're
+	trying to provide a "more like this" button on a search results
+	page that does a decent job and has good performance, such
+	techniques might be useful.
+
+Thus you:
+
+  - do your normal, Lucene-based search
+  - present the results
+  - when the user clicks on a document, call Like(docID) to generate
+    the query which finds documents similar to that one, and run it.
+
+Term vectors are used when they were stored for a field; otherwise
+the stored field values are re-analyzed with the Analyzer, which must
+then be set.
+*/
+type MoreLikeThis struct {
+	// IndexReader to use
+	ir index.IndexReader
+	// For idf() calculations.
+	similarity *search.TFIDFSimilarity
+	// Analyzer that will be used to parse the doc.
+	analyzer analysis.Analyzer
+
+	maxNumTokensParsed int
+	minTermFreq        int
+	minDocFreq         int
+	maxDocFreq         int
+	boost              bool
+	fieldNames         []string
+	minWordLen         int
+	maxWordLen         int
+	stopWords          map[string]bool
+	maxQueryTerms      int
+	// Boost factor to use when boosting the terms
+	boostFactor float32
+}
+
+// Constructor requiring an IndexReader.
+func NewMoreLikeThis(ir index.IndexReader) *MoreLikeThis {
+	return NewMoreLikeThisWith(ir, search.NewDefaultSimilarity().TFIDFSimilarity)
+}
+
+func NewMoreLikeThisWith(ir index.IndexReader, sim *search.TFIDFSimilarity) *MoreLikeThis {
+	return &MoreLikeThis{
+		ir:                 ir,
+		similarity:         sim,
+		maxNumTokensParsed: DEFAULT_MAX_NUM_TOKENS_PARSED,
+		minTermFreq:        DEFAULT_MIN_TERM_FREQ,
+		minDocFreq:         DEFAULT_MIN_DOC_FREQ,
+		maxDocFreq:         DEFAULT_MAX_DOC_FREQ,
+		boost:              DEFAULT_BOOST,
+		fieldNames:         DEFAULT_FIELD_NAMES,
+		minWordLen:         DEFAULT_MIN_WORD_LENGTH,
+		maxWordLen:         DEFAULT_MAX_WORD_LENGTH,
+		maxQueryTerms:      DEFAULT_MAX_QUERY_TERMS,
+		boostFactor:        1,
+	}
+}
+
+func (mlt *MoreLikeThis) Similarity() *search.TFIDFSimilarity {
+	return mlt.similarity
+}
+
+func (mlt *MoreLikeThis) SetSimilarity(sim *search.TFIDFSimilarity) *MoreLikeThis {
+	mlt.similarity = sim
+	return mlt
+}
+
+// Returns the analyzer that will be used to parse source doc with.
+func (mlt *MoreLikeThis) Analyzer() analysis.Analyzer {
+	return mlt.analyzer
+}
+
+/*
+Sets the analyzer to use. An analyzer is not required for generating
+a query with Like(docNum) when all fields have term vectors, but is
+required otherwise.
+*/
+func (mlt *MoreLikeThis) SetAnalyzer(analyzer analysis.Analyzer) *MoreLikeThis {
+	mlt.analyzer = analyzer
+	return mlt
+}
+
+/*
+Returns the frequency below which terms will be ignored in the
+source doc. The default frequency is DEFAULT_MIN_TERM_FREQ.
+*/
+func (mlt *MoreLikeThis) MinTermFreq() int {
+	return mlt.minTermFreq
+}
+
+func (mlt *MoreLikeThis) SetMinTermFreq(minTermFreq int) *MoreLikeThis {
+	mlt.minTermFreq = minTermFreq
+	return mlt
+}
+
+/*
+Returns the frequency at which words will be ignored which do not
+occur in at least this many docs. The default frequency is
+DEFAULT_MIN_DOC_FREQ.
+*/
+func (mlt *MoreLikeThis) MinDocFreq() int {
+	return mlt.minDocFreq
+}
+
+func (mlt *MoreLikeThis) SetMinDocFreq(minDocFreq int) *MoreLikeThis {
+	mlt.minDocFreq = minDocFreq
+	return mlt
+}
+
+/*
+Returns the maximum frequency in which words may still appear. Words
+that appear in more than this many docs will be ignored. The default
+frequency is DEFAULT_MAX_DOC_FREQ.
+*/
+func (mlt *MoreLikeThis) MaxDocFreq() int {
+	return mlt.maxDocFreq
+}
+
+func (mlt *MoreLikeThis) SetMaxDocFreq(maxFreq int) *MoreLikeThis {
+	mlt.maxDocFreq = maxFreq
+	return mlt
+}
+
+/*
+Sets the maximum percentage in which words may still appear. Words
+that appear in more than this many percent of all docs will be
+ignored.
+*/
+func (mlt *MoreLikeThis) SetMaxDocFreqPct(maxPercentage int) *MoreLikeThis {
+	mlt.maxDocFreq = maxPercentage * mlt.ir.NumDocs() / 100
+	return mlt
+}
+
+/*
+Returns whether to boost terms in query based on "score" or not. The
+default is DEFAULT_BOOST.
+*/
+func (mlt *MoreLikeThis) IsBoost() bool {
+	return mlt.boost
+}
+
+func (mlt *MoreLikeThis) SetBoost(boost bool) *MoreLikeThis {
+	mlt.boost = boost
+	return mlt
+}
+
+// Returns the boost factor used when boosting terms.
+func (mlt *MoreLikeThis) BoostFactor() float32 {
+	return mlt.boostFactor
+}
+
+func (mlt *MoreLikeThis) SetBoostFactor(boostFactor float32) *MoreLikeThis {
+	mlt.boostFactor = boostFactor
+	return mlt
+}
+
+/*
+Returns the field names that will be used when generating the 'More
+Like This' query. The default field names that will be used is
+DEFAULT_FIELD_NAMES.
+*/
+func (mlt *MoreLikeThis) FieldNames() []string {
+	return mlt.fieldNames
+}
+
+/*
+Sets the field names that will be used when generating the 'More
+Like This' query. Set this to nil for the field names to be
+determined at runtime from the IndexReader provided in the
+constructor.
+*/
+func (mlt *MoreLikeThis) SetFieldNames(fieldNames ...string) *MoreLikeThis {
+	mlt.fieldNames = fieldNames
+	return mlt
+}
+
+/*
+Returns the minimum word length below which words will be ignored.
+Set this to 0 for no minimum word length. The default is
+DEFAULT_MIN_WORD_LENGTH.
+*/
+func (mlt *MoreLikeThis) MinWordLen() int {
+	return mlt.minWordLen
+}
+
+func (mlt *MoreLikeThis) SetMinWordLen(minWordLen int) *MoreLikeThis {
+	mlt.minWordLen = minWordLen
+	return mlt
+}
+
+/*
+Returns the maximum word length above which words will be ignored.
+Set this to 0 for no maximum word length. The default is
+DEFAULT_MAX_WORD_LENGTH.
+*/
+func (mlt *MoreLikeThis) MaxWordLen() int {
+	return mlt.maxWordLen
+}
+
+func (mlt *MoreLikeThis) SetMaxWordLen(maxWordLen int) *MoreLikeThis {
+	mlt.maxWordLen = maxWordLen
+	return mlt
+}
+
+/*
+Sets the set of stopwords. Any word in this set is considered
+"uninteresting" and ignored. Even if your Analyzer allows stopwords,
+you might want to tell the MoreLikeThis code to ignore them, as for
+the purposes of document similarity it seems reasonable to assume
+that "a stop word is never interesting".
+*/
+func (mlt *MoreLikeThis) SetStopWords(stopWords map[string]bool) *MoreLikeThis {
+	mlt.stopWords = stopWords
+	return mlt
+}
+
+// Returns the current stop words being used.
+func (mlt *MoreLikeThis) StopWords() map[string]bool {
+	return mlt.stopWords
+}
+
+/*
+Returns the maximum number of query terms that will be included in
+any generated query. The default is DEFAULT_MAX_QUERY_TERMS.
+*/
+func (mlt *MoreLikeThis) MaxQueryTerms() int {
+	return mlt.maxQueryTerms
+}
+
+func (mlt *MoreLikeThis) SetMaxQueryTerms(maxQueryTerms int) *MoreLikeThis {
+	mlt.maxQueryTerms = maxQueryTerms
+	return mlt
+}
+
+/*
+Returns the maximum number of tokens to parse in each example doc
+field that is not stored with TermVector support. The default is
+DEFAULT_MAX_NUM_TOKENS_PARSED.
+*/
+func (mlt *MoreLikeThis) MaxNumTokensParsed() int {
+	return mlt.maxNumTokensParsed
+}
+
+func (mlt *MoreLikeThis) SetMaxNumTokensParsed(i int) *MoreLikeThis {
+	mlt.maxNumTokensParsed = i
+	return mlt
+}
+
+/*
+Return a query that will return docs like the passed lucene document
+ID.
+*/
+func (mlt *MoreLikeThis) Like(docNum int) (search.Query, error) {
+	if err := mlt.ensureFieldNames(); err != nil {
+		return nil, err
+	}
+	words, err := mlt.retrieveTerms(docNum)
+	if err != nil {
+		return nil, err
+	}
+	return mlt.createQuery(words), nil
+}
+
+/*
+Return a query that will return docs like the passed text, analyzed
+as if it were the content of the given field.
+*/
+func (mlt *MoreLikeThis) LikeText(text, fieldName string) (search.Query, error) {
+	words, err := mlt.retrieveTermsFromText(text, fieldName)
+	if err != nil {
+		return nil, err
+	}
+	return mlt.createQuery(words), nil
+}
+
+// Create the More like query from a priority queue
+func (mlt *MoreLikeThis) createQuery(words []*scoreTerm) search.Query {
+	query := search.NewBooleanQuery()
+	if len(words) == 0 {
+		return query
+	}
+	bestScore := words[0].score
+	for i, st := range words {
+		if i == maxClauseCount {
+			break
+		}
+		tq := search.NewTermQuery(index.NewTerm(st.topField, st.word))
+		if mlt.boost {
+			tq.SetBoost(mlt.boostFactor * st.score / bestScore)
+		}
+		query.Add(tq, search.SHOULD)
+	}
+	return query
+}
+
+// Same as search.BooleanQuery's limit on the number of clauses.
+const maxClauseCount = 1024
+
+/*
+Create a sorted list of words, best first, from a map of words to
+their term frequencies.
+*/
+func (mlt *MoreLikeThis) createQueue(words map[string]int) ([]*scoreTerm, error) {
+	// have collected all words in doc and their freqs
+	numDocs := int64(mlt.ir.NumDocs())
+	var ans []*scoreTerm
+
+	for word, tf := range words { // for every word
+		if mlt.minTermFreq > 0 && tf < mlt.minTermFreq {
+			continue // filter out words that don't occur enough times in the source
+		}
+
+		// go through all the fields and find the largest document frequency
+		topField := mlt.fieldNames[0]
+		docFreq := 0
+		for _, fieldName := range mlt.fieldNames {
+			freq, err := mlt.ir.DocFreq(index.NewTerm(fieldName, word))
+			if err != nil {
+				return nil, err
+			}
+			if freq > docFreq {
+				topField, docFreq = fieldName, freq
+			}
+		}
+
+		if mlt.minDocFreq > 0 && docFreq < mlt.minDocFreq {
+			continue // filter out words that don't occur in enough docs
+		}
+		if docFreq > mlt.maxDocFreq {
+			continue // filter out words that occur in too many docs
+		}
+		if docFreq == 0 {
+			continue // index update problem?
+		}
+
+		idf := mlt.similarity.Idf(int64(docFreq), numDocs)
+		ans = append(ans, &scoreTerm{
+			word:     word,
+			topField: topField,
+			score:    float32(tf) * idf,
+			idf:      idf,
+			docFreq:  docFreq,
+			tf:       tf,
+		})
+	}
+
+	sort.Sort(byScoreDesc(ans))
+	if len(ans) > mlt.maxQueryTerms {
+		ans = ans[:mlt.maxQueryTerms]
+	}
+	return ans, nil
+}
+
+// Describe the parameters that control how the "more like this"
+// query is formed.
+func (mlt *MoreLikeThis) DescribeParams() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "\tmaxQueryTerms  : %v\n", mlt.maxQueryTerms)
+	fmt.Fprintf(&b, "\tminWordLen     : %v\n", mlt.minWordLen)
+	fmt.Fprintf(&b, "\tmaxWordLen     : %v\n", mlt.maxWordLen)
+	fmt.Fprintf(&b, "\tfieldNames     : %v\n", strings.Join(mlt.fieldNames, ", "))
+	fmt.Fprintf(&b, "\tboost          : %v\n", mlt.boost)
+	fmt.Fprintf(&b, "\tminTermFreq    : %v\n", mlt.minTermFreq)
+	fmt.Fprintf(&b, "\tminDocFreq     : %v\n", mlt.minDocFreq)
+	return b.String()
+}
+
+/*
+Looks up the indexed field names from the reader when none were
+configured.
+*/
+func (mlt *MoreLikeThis) ensureFieldNames() error {
+	if mlt.fieldNames != nil {
+		return nil
+	}
+	seen := make(map[string]bool)
+	for _, leaf := range mlt.ir.Leaves() {
+		infos := leaf.Reader().(index.AtomicReader).FieldInfos()
+		for _, fi := range infos.Values {
+			if fi.IsIndexed() && !seen[fi.Name] {
+				seen[fi.Name] = true
+				mlt.fieldNames = append(mlt.fieldNames, fi.Name)
+			}
+		}
+	}
+	if len(mlt.fieldNames) == 0 {
+		return errors.New("no indexed fields found")
+	}
+	return nil
+}
+
+// Find words for a more-like-this query former.
+func (mlt *MoreLikeThis) retrieveTerms(docNum int) ([]*scoreTerm, error) {
+	termFreqMap := make(map[string]int)
+	leaves := mlt.ir.Leaves()
+	leaf := leaves[index.SubIndex(docNum, leaves)]
+	vectors, err := leaf.Reader().(index.AtomicReader).TermVectors(docNum - leaf.DocBase)
+	if err != nil {
+		return nil, err
+	}
+	for _, fieldName := range mlt.fieldNames {
+		var vector model.Terms
+		if vectors != nil {
+			vector = vectors.Terms(fieldName)
+		}
+
+		if vector != nil {
+			if err = mlt.addTermFrequencies(termFreqMap, vector); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		// field does not store term vector info
+		doc, err := mlt.ir.Document(docNum)
+		if err != nil {
+			return nil, err
+		}
+		for _, text := range doc.Values(fieldName) {
+			if err = mlt.addTermFrequenciesFromText(termFreqMap, text, fieldName); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return mlt.createQueue(termFreqMap)
+}
+
+// Adds terms and frequencies found in vector into the map.
+func (mlt *MoreLikeThis) addTermFrequencies(termFreqMap map[string]int, vector model.Terms) error {
+	termsEnum := vector.Iterator(nil)
+	for {
+		text, err := termsEnum.Next()
+		if err != nil {
+			return err
+		}
+		if text == nil {
+			break
+		}
+		term := string(text)
+		if mlt.isNoiseWord(term) {
+			continue
+		}
+		freq, err := termsEnum.TotalTermFreq()
+		if err != nil {
+			return err
+		}
+		// increment frequency
+		termFreqMap[term] += int(freq)
+	}
+	return nil
+}
+
+// Adds term frequencies found by tokenizing text from the reader
+// into the map.
+func (mlt *MoreLikeThis) addTermFrequenciesFromText(termFreqMap map[string]int,
+	text, fieldName string) (err error) {
+
+	if mlt.analyzer == nil {
+		return errors.New("to use MoreLikeThis without term vectors, you must provide an Analyzer")
+	}
+	ts, err := mlt.analyzer.TokenStreamForString(fieldName, text)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if e := ts.Close(); err == nil {
+			err = e
+		}
+	}()
+	termAtt := ts.Attributes().Add("CharTermAttribute").(ta.CharTermAttribute)
+
+	if err = ts.Reset(); err != nil {
+		return err
+	}
+	tokenCount := 0
+	for {
+		ok, err := ts.IncrementToken()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			break
+		}
+		word := string(termAtt.Buffer()[:termAtt.Length()])
+		if tokenCount++; tokenCount > mlt.maxNumTokensParsed {
+			break
+		}
+		if mlt.isNoiseWord(word) {
+			continue
+		}
+		// increment frequency
+		termFreqMap[word]++
+	}
+	return ts.End()
+}
+
+// Determines if the passed term is likely to be of interest in "more
+// like" comparisons.
+func (mlt *MoreLikeThis) isNoiseWord(term string) bool {
+	n := len([]rune(term))
+	if mlt.minWordLen > 0 && n < mlt.minWordLen {
+		return true
+	}
+	if mlt.maxWordLen > 0 && n > mlt.maxWordLen {
+		return true
+	}
+	return mlt.stopWords != nil && mlt.stopWords[term]
+}
+
+func (mlt *MoreLikeThis) retrieveTermsFromText(text, fieldName string) ([]*scoreTerm, error) {
+	words := make(map[string]int)
+	if err := mlt.addTermFrequenciesFromText(words, text, fieldName); err != nil {
+		return nil, err
+	}
+	return mlt.createQueue(words)
+}
+
+/*
+Convenience routine to make it easy to return the most interesting
+words in a document, best first.
+*/
+func (mlt *MoreLikeThis) RetrieveInterestingTerms(docNum int) ([]string, error) {
+	if err := mlt.ensureFieldNames(); err != nil {
+		return nil, err
+	}
+	words, err := mlt.retrieveTerms(docNum)
+	if err != nil {
+		return nil, err
+	}
+	return interestingTerms(words), nil
+}
+
+// Same as RetrieveInterestingTerms(), but for external text.
+func (mlt *MoreLikeThis) RetrieveInterestingTermsFromText(text, fieldName string) ([]string, error) {
+	words, err := mlt.retrieveTermsFromText(text, fieldName)
+	if err != nil {
+		return nil, err
+	}
+	return interestingTerms(words), nil
+}
+
+func interestingTerms(words []*scoreTerm) []string {
+	ans := make([]string, len(words))
+	for i, st := range words {
+		ans[i] = st.word
+	}
+	return ans
+}
+
+type scoreTerm struct {
+	word     string
+	topField string
+	score    float32
+	idf      float32
+	docFreq  int
+	tf       int
+}
+
+type byScoreDesc []*scoreTerm
+
+func (a byScoreDesc) Len() int      { return len(a) }
+func (a byScoreDesc) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
+func (a byScoreDesc) Less(i, j int) bool {
+	if a[i].score != a[j].score {
+		return a[i].score > a[j].score
+	}
+	return a[i].word < a[j].word
+}
@@ -0,0 +1,184 @@
+package mlt
+
+import (
+	std "github.com/balzaczyy/golucene/analysis/standard"
+	_ "github.com/balzaczyy/golucene/core/codec/lucene410"
+	docu "github.com/balzaczyy/golucene/core/document"
+	"github.com/balzaczyy/golucene/core/index"
+	"github.com/balzaczyy/golucene/core/search"
+	"github.com/balzaczyy/golucene/core/store"
+	"github.com/balzaczyy/golucene/core/util"
+	"strings"
+	"testing"
+)
+
+var texts = []string{
+	"lucene release lucene search",
+	"lucene search engine",
+	"apache lucene release notes",
+	"the apache software foundation",
+	"golang search library",
+}
+
+func newReader(t *testing.T) index.IndexReader {
+	index.DefaultSimilarity = func() index.Similarity {
+		return search.NewDefaultSimilarity()
+	}
+	dir, err := store.OpenFSDirectory(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := index.NewIndexWriter(dir, index.NewIndexWriterConfig(util.VERSION_LATEST, std.NewStandardAnalyzer()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, text := range texts {
+		doc := docu.NewDocument()
+		doc.Add(docu.NewTextFieldFromString("text", text, docu.STORE_YES))
+		if err = w.AddDocument(doc.Fields()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	r, err := index.OpenDirectoryReader(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { r.Close() })
+	return r
+}
+
+func newMoreLikeThis(ir index.IndexReader) *MoreLikeThis {
+	return NewMoreLikeThis(ir).
+		SetAnalyzer(std.NewStandardAnalyzer()).
+		SetFieldNames("text").
+		SetMinTermFreq(1).
+		SetMinDocFreq(1)
+}
+
+func TestInterestingTerms(t *testing.T) {
+	ir := newReader(t)
+	mlt := newMoreLikeThis(ir)
+
+	terms, err := mlt.RetrieveInterestingTerms(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 'lucene' occurs twice in the doc, so ranks first
+	if expected := "lucene release search"; strings.Join(terms, " ") != expected {
+		t.Errorf("expected [%v], but was %v", expected, terms)
+	}
+
+	mlt.SetMinTermFreq(2)
+	if terms, err = mlt.RetrieveInterestingTerms(0); err != nil {
+		t.Fatal(err)
+	}
+	if len(terms) != 1 || terms[0] != "lucene" {
+		t.Errorf("only 'lucene' occurs often enough: %v", terms)
+	}
+
+	mlt.SetMinTermFreq(1).SetMaxDocFreq(2)
+	if terms, err = mlt.RetrieveInterestingTerms(0); err != nil {
+		t.Fatal(err)
+	}
+	if len(terms) != 1 || terms[0] != "release" {
+		t.Errorf("'lucene' and 'search' occur in too many docs: %v", terms)
+	}
+
+	mlt.SetMaxDocFreq(DEFAULT_MAX_DOC_FREQ).SetMinWordLen(7)
+	if terms, err = mlt.RetrieveInterestingTerms(0); err != nil {
+		t.Fatal(err)
+	}
+	if len(terms) != 1 || terms[0] != "release" {
+		t.Errorf("only 'release' is long enough: %v", terms)
+	}
+}
+
+func TestInterestingTermsFromText(t *testing.T) {
+	ir := newReader(t)
+	mlt := newMoreLikeThis(ir).SetStopWords(map[string]bool{"search": true})
+
+	terms, err := mlt.RetrieveInterestingTermsFromText("apache search, unknown words", "text")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(terms) != 1 || terms[0] != "apache" {
+		t.Errorf("expected [apache], but was %v", terms)
+	}
+
+	if _, err = NewMoreLikeThis(ir).SetFieldNames("text").RetrieveInterestingTerms(0); err == nil {
+		t.Errorf("re-analysis without an analyzer should fail")
+	}
+}
+
+func TestBoostedQuery(t *testing.T) {
+	ir := newReader(t)
+	mlt := newMoreLikeThis(ir).SetBoost(true).SetBoostFactor(2)
+
+	q, err := mlt.Like(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clauses := q.(*search.BooleanQuery).Clauses()
+	if len(clauses) != 3 {
+		t.Fatalf("expected three clauses, but was %v", q)
+	}
+	for i, c := range clauses {
+		if c.Occur() != search.SHOULD {
+			t.Errorf("clause %v should be optional", c)
+		}
+		boost := c.Query().Boost()
+		if i == 0 && boost != 2 {
+			t.Errorf("best term should have the boost factor, but was %v", boost)
+		}
+		if boost <= 0 || boost > 2 {
+			t.Errorf("boost should be relative to the best term, but was %v", boost)
+		}
+	}
+
+	if q, err = mlt.SetMaxQueryTerms(1).Like(0); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(q.(*search.BooleanQuery).Clauses()); n != 1 {
+		t.Errorf("expected one clause, but was %v", n)
+	}
+}
+
+func TestLikeFindsSimilarDocs(t *testing.T) {
+	ir := newReader(t)
+	mlt := newMoreLikeThis(ir)
+
+	q, err := mlt.Like(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	topDocs, err := search.NewIndexSearcher(ir).Search(q, nil, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hits := make(map[int]bool)
+	for _, hit := range topDocs.ScoreDocs {
+		hits[hit.Doc] = true
+	}
+	// every doc but the one about the foundation shares a term
+	for doc, expected := range []bool{true, true, true, false, true} {
+		if hits[doc] != expected {
+			t.Errorf("doc %v: expected hit %v, but was %v", doc, expected, hits[doc])
+		}
+	}
+	if topDocs.ScoreDocs[0].Doc != 1 {
+		t.Errorf("the source doc should be most similar, but was %v", topDocs.ScoreDocs[0].Doc)
+	}
+
+	if q, err = mlt.LikeText("apache foundation", "text"); err != nil {
+		t.Fatal(err)
+	}
+	if topDocs, err = search.NewIndexSearcher(ir).Search(q, nil, 10); err != nil {
+		t.Fatal(err)
+	}
+	if topDocs.TotalHits != 2 || topDocs.ScoreDocs[0].Doc != 3 {
+		t.Errorf("expected docs 3 and 2, but was %v", topDocs.ScoreDocs)
+	}
+}
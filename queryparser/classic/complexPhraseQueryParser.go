@@ -0,0 +1,283 @@
+package classic
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/balzaczyy/golucene/core/analysis"
+	"github.com/balzaczyy/golucene/core/index"
+	"github.com/balzaczyy/golucene/core/search"
+	"github.com/balzaczyy/golucene/core/util"
+)
+
+// complexPhrase/ComplexPhraseQueryParser.java
+
+/*
+A QueryParser which permits complex phrase query syntax, e.g.
+"(john jon jonathan~) peters*".
+
+Performs potentially multiple passes over the query text to parse any
+nested logic in phrase queries:
+
+- First pass takes any phrase query content and stores it for
+subsequent parsing, and parses the rest of the query as usual.
+- Second pass parses any stored phrase query content, which may
+contain wildcards, fuzzy terms, ranges, regular expressions and
+nested OR groups.
+
+Each phrase is compiled to a SpanNearQuery over the span equivalent of
+its clauses when the query is rewritten. Prohibited clauses are not
+supported inside phrases, and all clauses of a phrase must be for the
+field of the phrase.
+*/
+type ComplexPhraseQueryParser struct {
+	*QueryParser
+	inOrder bool
+	// set during parsing of the stored phrase contents
+	isPass2ResolvingPhrases bool
+	complexPhrases          []*ComplexPhraseQuery
+}
+
+func NewComplexPhraseQueryParser(matchVersion util.Version, f string,
+	a analysis.Analyzer) *ComplexPhraseQueryParser {
+
+	ans := &ComplexPhraseQueryParser{
+		QueryParser: NewQueryParser(matchVersion, f, a),
+		inOrder:     true,
+	}
+	ans.spi = ans
+	return ans
+}
+
+/*
+When inOrder is true, the search terms must exist in the documents as
+the same order as in query. Default is true.
+*/
+func (qp *ComplexPhraseQueryParser) SetInOrder(inOrder bool) {
+	qp.inOrder = inOrder
+}
+
+func (qp *ComplexPhraseQueryParser) InOrder() bool {
+	return qp.inOrder
+}
+
+/*
+Parses a query string, returning a Query. The contents of the phrases
+are parsed in a second pass, and checked to only hold clauses which
+can be turned into span queries.
+*/
+func (qp *ComplexPhraseQueryParser) Parse(query string) (search.Query, error) {
+	if qp.isPass2ResolvingPhrases {
+		return qp.QueryParserBase.Parse(query)
+	}
+	qp.complexPhrases = nil
+	q, err := qp.QueryParserBase.Parse(query)
+	if err != nil {
+		return nil, err
+	}
+
+	qp.isPass2ResolvingPhrases = true
+	defer func() { qp.isPass2ResolvingPhrases = false }()
+	for _, cpq := range qp.complexPhrases {
+		if err = cpq.parsePhraseElements(qp); err != nil {
+			return nil, fmt.Errorf("Cannot parse '%v': %v", query, err)
+		}
+	}
+	return q, nil
+}
+
+/*
+In the first pass, stores the phrase to parse its contents later. In
+the second pass, phrases are not supported and parsed as usual.
+*/
+func (qp *ComplexPhraseQueryParser) fieldQueryWithSlop(field, queryText string, slop int) search.Query {
+	if qp.isPass2ResolvingPhrases {
+		return qp.QueryParser.fieldQueryWithSlop(field, queryText, slop)
+	}
+	cpq := NewComplexPhraseQuery(field, queryText, slop, qp.inOrder)
+	qp.complexPhrases = append(qp.complexPhrases, cpq) // add to list of phrases to be parsed once we are through with this pass
+	return cpq
+}
+
+// complexPhrase/ComplexPhraseQueryParser.java#ComplexPhraseQuery
+
+/*
+Used to handle the query content in between quotes and produce span
+based interpretations of the clauses. It must be rewritten before it
+is searched.
+*/
+type ComplexPhraseQuery struct {
+	*search.AbstractQuery
+	field                      string
+	phrasedQueryStringContents string
+	slop                       int
+	inOrder                    bool
+	contents                   search.Query
+}
+
+func NewComplexPhraseQuery(field, phrasedQueryStringContents string,
+	slop int, inOrder bool) *ComplexPhraseQuery {
+
+	ans := &ComplexPhraseQuery{
+		field:                      field,
+		phrasedQueryStringContents: phrasedQueryStringContents,
+		slop:                       slop,
+		inOrder:                    inOrder,
+	}
+	ans.AbstractQuery = search.NewAbstractQuery(ans)
+	return ans
+}
+
+func (q *ComplexPhraseQuery) Field() string {
+	return q.field
+}
+
+/*
+Called by ComplexPhraseQueryParser for each phrase after the main
+parse thread is through, with the phrase field as the default field.
+*/
+func (q *ComplexPhraseQuery) parsePhraseElements(qp *ComplexPhraseQueryParser) (err error) {
+	oldDefaultParserField := qp.field
+	defer func() { qp.field = oldDefaultParserField }()
+	qp.field = q.field
+	if q.contents, err = qp.Parse(q.phrasedQueryStringContents); err != nil {
+		return err
+	}
+	return q.checkPhraseClause(q.contents)
+}
+
+/*
+Checks the clauses of the phrase are for the same field, and can be
+turned into span queries.
+*/
+func (q *ComplexPhraseQuery) checkPhraseClause(query search.Query) error {
+	var field string
+	switch query := query.(type) {
+	case *search.BooleanQuery:
+		for _, clause := range query.Clauses() {
+			if clause.IsProhibited() {
+				return fmt.Errorf("Prohibited clause %v is not supported in phrase %v",
+					clause.Query(), q.phrasedQueryStringContents)
+			}
+			if err := q.checkPhraseClause(clause.Query()); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *search.TermQuery:
+		field = query.Term().Field
+	case *search.SynonymQuery:
+		field = query.Terms()[0].Field
+	case interface{ Field() string }: // multi-term queries
+		field = query.Field()
+	default:
+		return fmt.Errorf("Unknown query type %T found in phrase query string %v",
+			query, q.phrasedQueryStringContents)
+	}
+	if field != q.field {
+		return fmt.Errorf("Cannot have clause for field \"%v\" nested in phrase for field \"%v\"",
+			field, q.field)
+	}
+	return nil
+}
+
+func (q *ComplexPhraseQuery) CreateWeight(ss *search.IndexSearcher) (search.Weight, error) {
+	return nil, errors.New("ComplexPhraseQuery does not support CreateWeight, it must be rewritten first")
+}
+
+/*
+Rewrites the phrase into a SpanNearQuery: terms become SpanTermQuery
+and the expanded multi-term queries and OR groups become SpanOrQuery.
+*/
+func (q *ComplexPhraseQuery) Rewrite(reader index.IndexReader) search.Query {
+	if q.contents == nil {
+		// not parsed yet, or all terms were stop words
+		return search.NewBooleanQuery()
+	}
+	bq, ok := q.contents.(*search.BooleanQuery)
+	if !ok {
+		// a single term, or a multi-term query
+		ans := rewriteFully(q.contents, reader)
+		ans.SetBoost(q.Boost() * ans.Boost())
+		return ans
+	}
+
+	var allSpanClauses []search.SpanQuery
+	for _, clause := range bq.Clauses() {
+		span := q.toSpanQuery(rewriteFully(clause.Query(), reader))
+		if span == nil {
+			// Insert fake term e.g. phrase query was for "Fred Smithe*" and
+			// there were no "Smithe*" terms - need to prevent match on just
+			// "Fred".
+			span = search.NewSpanTermQuery(
+				index.NewTerm(q.field, "Dummy clause because no terms found - must match nothing"))
+		}
+		allSpanClauses = append(allSpanClauses, span)
+	}
+	if len(allSpanClauses) == 0 {
+		// all terms were stop words
+		return search.NewBooleanQuery()
+	}
+	ans := search.NewSpanNearQuery(allSpanClauses, q.slop, q.inOrder)
+	ans.SetBoost(q.Boost())
+	return ans
+}
+
+/* Rewrites the query until it does not change anymore. */
+func rewriteFully(query search.Query, reader index.IndexReader) search.Query {
+	for rewritten := query.Rewrite(reader); rewritten != query; rewritten = query.Rewrite(reader) {
+		query = rewritten
+	}
+	return query
+}
+
+/*
+Returns the span equivalent of a rewritten clause, or nil if it
+matches no term.
+*/
+func (q *ComplexPhraseQuery) toSpanQuery(query search.Query) search.SpanQuery {
+	switch query := query.(type) {
+	case *search.TermQuery:
+		return search.NewSpanTermQuery(query.Term())
+	case *search.SynonymQuery:
+		var clauses []search.SpanQuery
+		for _, term := range query.Terms() {
+			clauses = append(clauses, search.NewSpanTermQuery(term))
+		}
+		return newSpanOr(clauses)
+	case *search.BooleanQuery:
+		var clauses []search.SpanQuery
+		for _, clause := range query.Clauses() {
+			if span := q.toSpanQuery(clause.Query()); span != nil {
+				clauses = append(clauses, span)
+			}
+		}
+		return newSpanOr(clauses)
+	}
+	return nil
+}
+
+func newSpanOr(clauses []search.SpanQuery) search.SpanQuery {
+	switch len(clauses) {
+	case 0:
+		return nil
+	case 1:
+		return clauses[0]
+	}
+	return search.NewSpanOrQuery(clauses...)
+}
+
+func (q *ComplexPhraseQuery) ToString(field string) string {
+	var buf bytes.Buffer
+	if q.field != field {
+		fmt.Fprintf(&buf, "%v:", q.field)
+	}
+	fmt.Fprintf(&buf, "\"%v\"", q.phrasedQueryStringContents)
+	if q.slop != 0 {
+		fmt.Fprintf(&buf, "~%v", q.slop)
+	}
+	if q.Boost() != 1 {
+		fmt.Fprintf(&buf, "^%v", q.Boost())
+	}
+	return buf.String()
+}
@@ -0,0 +1,114 @@
+package classic
+
+import (
+	std "github.com/balzaczyy/golucene/analysis/standard"
+	_ "github.com/balzaczyy/golucene/core/codec/lucene410"
+	docu "github.com/balzaczyy/golucene/core/document"
+	"github.com/balzaczyy/golucene/core/index"
+	"github.com/balzaczyy/golucene/core/search"
+	"github.com/balzaczyy/golucene/core/store"
+	"github.com/balzaczyy/golucene/core/util"
+	"testing"
+)
+
+func TestComplexPhraseQueryParser(t *testing.T) {
+	dir, err := store.OpenFSDirectory(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := std.NewStandardAnalyzer()
+	w, err := index.NewIndexWriter(dir, index.NewIndexWriterConfig(util.VERSION_LATEST, a))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{
+		"john smith",
+		"johathon smith",
+		"john percival smyth",
+		"jackson waits tom",
+	} {
+		doc := docu.NewDocument()
+		doc.Add(docu.NewTextFieldFromString("name", name, docu.STORE_YES))
+		if err = w.AddDocument(doc.Fields()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	r, err := index.OpenDirectoryReader(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	ss := search.NewIndexSearcher(r)
+
+	qp := NewComplexPhraseQueryParser(util.VERSION_LATEST, "name", a)
+	hits := func(query string, expected ...int) {
+		q, err := qp.Parse(query)
+		if err != nil {
+			t.Fatalf("%v: %v", query, err)
+		}
+		docs, err := ss.SearchTop(q, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if docs.TotalHits != len(expected) {
+			t.Errorf("Expected %v to match %v, but was %v", query, expected, docs.ScoreDocs)
+			return
+		}
+		found := make(map[int]bool)
+		for _, scoreDoc := range docs.ScoreDocs {
+			found[scoreDoc.Doc] = true
+		}
+		for _, doc := range expected {
+			if !found[doc] {
+				t.Errorf("Expected %v to match %v, but was %v", query, expected, docs.ScoreDocs)
+			}
+		}
+	}
+
+	hits("\"john smith\"", 0)           // Simple multi-term still works
+	hits("\"j*   smyth~\"", 0, 1)       // wildcards and fuzzies are OK in phrases
+	hits("\"jo*  smyth\"~2", 2)         // position logic works
+	hits("\"jo* [sma TO smZ]\" ", 0, 1) // range queries supported
+	hits("\"john\"", 0, 2)              // Simple single-term still works
+	hits("\"(john OR johathon)  smith\"", 0, 1)
+	hits("\"(jo* OR smyth)  smith\"", 0, 1)
+	hits("\"jo* percival smith\"")
+	hits("\"nothing* smith\"") // the fake term prevents a match on just "smith"
+	hits("name:\"jackson tom\"~1 OR \"john\"", 0, 2, 3)
+
+	qp.SetInOrder(false)
+	hits("\"smith jo*\"", 0, 1)
+	hits("\"tom waits\"", 3)
+}
+
+func TestComplexPhraseQueryParserErrors(t *testing.T) {
+	qp := NewComplexPhraseQueryParser(util.VERSION_LATEST, "name", std.NewStandardAnalyzer())
+	for _, query := range []string{
+		"\"(jo* -john) smyth\"",
+		"\"jo* role:smyth\"",
+		"\"jo* smyth",
+	} {
+		if _, err := qp.Parse(query); err == nil {
+			t.Errorf("Expected %v to fail", query)
+		}
+	}
+}
+
+func TestComplexPhraseQueryRewrite(t *testing.T) {
+	qp := NewComplexPhraseQueryParser(util.VERSION_LATEST, "name", std.NewStandardAnalyzer())
+	q, err := qp.Parse("\"(john jon) smith\"~3^2 role:developer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, expected := q.ToString("name"), "\"(john jon) smith\"~3^2 role:developer"; got != expected {
+		t.Errorf("expected %v, but was %v", expected, got)
+	}
+	cpq := q.(*search.BooleanQuery).Clauses()[0].Query()
+	if got, expected := cpq.Rewrite(nil).ToString("name"),
+		"spanNear([spanOr([john, jon]), smith], 3, true)^2"; got != expected {
+		t.Errorf("expected %v, but was %v", expected, got)
+	}
+}
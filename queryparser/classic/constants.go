@@ -30,8 +30,52 @@ const (
 	RANGEEX_START
 	NUMBER
 	RANGE_TO
-	RANGEiN_END
-	RAGEEX_END
+	RANGEIN_END
+	RANGEEX_END
 	RANGE_QUOTED
 	RANGE_GOOP
 )
+
+// Lexical states.
+const (
+	Boost = iota
+	Range
+	DEFAULT
+)
+
+// Literal token values, used in error messages.
+var tokenImage = []string{
+	"<EOF>",
+	"<_NUM_CHAR>",
+	"<_ESCAPED_CHAR>",
+	"<_TERM_START_CHAR>",
+	"<_TERM_CHAR>",
+	"<_WHITESPACE>",
+	"<_QUOTED_CHAR>",
+	"<token of kind 7>",
+	"<AND>",
+	"<OR>",
+	"<NOT>",
+	"\"+\"",
+	"\"-\"",
+	"<BAREOPER>",
+	"\"(\"",
+	"\")\"",
+	"\":\"",
+	"\"*\"",
+	"\"^\"",
+	"<QUOTED>",
+	"<TERM>",
+	"<FUZZY_SLOP>",
+	"<PREFIXTERM>",
+	"<WILDTERM>",
+	"<REGEXPTERM>",
+	"\"[\"",
+	"\"{\"",
+	"<NUMBER>",
+	"\"TO\"",
+	"\"]\"",
+	"\"}\"",
+	"<RANGE_QUOTED>",
+	"<RANGE_GOOP>",
+}
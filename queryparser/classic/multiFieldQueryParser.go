@@ -0,0 +1,249 @@
+package classic
+
+import (
+	"errors"
+	"github.com/balzaczyy/golucene/core/analysis"
+	"github.com/balzaczyy/golucene/core/search"
+	"github.com/balzaczyy/golucene/core/util"
+)
+
+// classic/MultiFieldQueryParser.java
+
+// How the queries of an unqualified term over several fields are combined.
+type CombineMode int
+
+const (
+	// A BooleanQuery of optional clauses, one per field (the default).
+	COMBINE_BOOLEAN = CombineMode(iota)
+	// A DisjunctionMaxQuery, scoring with the best matching field.
+	COMBINE_DISMAX
+)
+
+/*
+A QueryParser which constructs queries to search multiple fields.
+
+Terms, phrases and multi-term queries (prefix, wildcard, fuzzy, regexp
+and range) without an explicit field are expanded into one query per
+field, which are combined according to the combine mode. Terms with an
+explicit field ("title:foo") are not expanded.
+*/
+type MultiFieldQueryParser struct {
+	*QueryParser
+	fields               []string
+	boosts               map[string]float32
+	combineMode          CombineMode
+	tieBreakerMultiplier float32
+}
+
+/*
+Creates a MultiFieldQueryParser. It will, when Parse(query) is called,
+construct a query like this (assuming the query consists of two terms
+and you specify the two fields title and body):
+
+	(title:term1 body:term1) (title:term2 body:term2)
+
+When SetDefaultOperator(OP_AND) is set, the result will be:
+
+	+(title:term1 body:term1) +(title:term2 body:term2)
+
+In other words, all the query's terms must appear, but it doesn't
+matter in what fields they appear.
+
+Allows passing of a map with term to boost, which is applied to the
+query of each field, like:
+
+	(title:term1^5 body:term1^10) (title:term2^5 body:term2^10)
+
+The boosts may be nil.
+*/
+func NewMultiFieldQueryParser(matchVersion util.Version, fields []string,
+	a analysis.Analyzer, boosts map[string]float32) *MultiFieldQueryParser {
+
+	ans := &MultiFieldQueryParser{
+		QueryParser: NewQueryParser(matchVersion, "", a),
+		fields:      fields,
+		boosts:      boosts,
+	}
+	ans.spi = ans
+	return ans
+}
+
+// Returns the fields the unqualified terms are expanded to.
+func (qp *MultiFieldQueryParser) Fields() []string {
+	return qp.fields
+}
+
+/*
+Sets how the per-field queries are combined: into a BooleanQuery of
+optional clauses (the default), or into a DisjunctionMaxQuery with
+the given tie breaker multiplier.
+*/
+func (qp *MultiFieldQueryParser) SetCombineMode(mode CombineMode, tieBreakerMultiplier float32) {
+	qp.combineMode = mode
+	qp.tieBreakerMultiplier = tieBreakerMultiplier
+}
+
+func (qp *MultiFieldQueryParser) CombineMode() CombineMode {
+	return qp.combineMode
+}
+
+/*
+Applies the boost of each field to its query, then combines the
+non-nil ones according to the combine mode. Returns nil if there is
+no query, e.g. when all terms were stop words.
+*/
+func (qp *MultiFieldQueryParser) combine(queries []search.Query) search.Query {
+	var disjuncts []search.Query
+	for i, q := range queries {
+		if q == nil {
+			continue
+		}
+		if boost, ok := qp.boosts[qp.fields[i]]; ok {
+			q.SetBoost(boost)
+		}
+		disjuncts = append(disjuncts, q)
+	}
+	if len(disjuncts) == 0 {
+		return nil
+	}
+	if qp.combineMode == COMBINE_DISMAX {
+		return search.NewDisjunctionMaxQuery(disjuncts, qp.tieBreakerMultiplier)
+	}
+	q := qp.newBooleanQuery(true)
+	for _, disjunct := range disjuncts {
+		q.Add(disjunct, search.SHOULD)
+	}
+	return q
+}
+
+/* Builds the query of each field with f and combines them. */
+func (qp *MultiFieldQueryParser) expand(f func(field string) (search.Query, error)) (search.Query, error) {
+	queries := make([]search.Query, len(qp.fields))
+	for i, field := range qp.fields {
+		var err error
+		if queries[i], err = f(field); err != nil {
+			return nil, err
+		}
+	}
+	return qp.combine(queries), nil
+}
+
+func (qp *MultiFieldQueryParser) fieldQuery(field, queryText string, quoted bool) search.Query {
+	if field != "" {
+		return qp.QueryParserBase.fieldQuery(field, queryText, quoted)
+	}
+	q, _ := qp.expand(func(field string) (search.Query, error) {
+		return qp.QueryParserBase.fieldQuery(field, queryText, quoted), nil
+	})
+	return q
+}
+
+func (qp *MultiFieldQueryParser) fieldQueryWithSlop(field, queryText string, slop int) search.Query {
+	if field != "" {
+		return qp.QueryParserBase.fieldQueryWithSlop(field, queryText, slop)
+	}
+	q, _ := qp.expand(func(field string) (search.Query, error) {
+		return qp.QueryParserBase.fieldQueryWithSlop(field, queryText, slop), nil
+	})
+	return q
+}
+
+func (qp *MultiFieldQueryParser) rangeQuery(field string, part1, part2 *string,
+	startInclusive, endInclusive bool) (search.Query, error) {
+
+	if field != "" {
+		return qp.QueryParserBase.rangeQuery(field, part1, part2, startInclusive, endInclusive)
+	}
+	return qp.expand(func(field string) (search.Query, error) {
+		return qp.QueryParserBase.rangeQuery(field, part1, part2, startInclusive, endInclusive)
+	})
+}
+
+func (qp *MultiFieldQueryParser) wildcardQuery(field, termStr string) (search.Query, error) {
+	if field != "" {
+		return qp.QueryParserBase.wildcardQuery(field, termStr)
+	}
+	return qp.expand(func(field string) (search.Query, error) {
+		return qp.QueryParserBase.wildcardQuery(field, termStr)
+	})
+}
+
+func (qp *MultiFieldQueryParser) regexpQuery(field, termStr string) (search.Query, error) {
+	if field != "" {
+		return qp.QueryParserBase.regexpQuery(field, termStr)
+	}
+	return qp.expand(func(field string) (search.Query, error) {
+		return qp.QueryParserBase.regexpQuery(field, termStr)
+	})
+}
+
+func (qp *MultiFieldQueryParser) prefixQuery(field, termStr string) (search.Query, error) {
+	if field != "" {
+		return qp.QueryParserBase.prefixQuery(field, termStr)
+	}
+	return qp.expand(func(field string) (search.Query, error) {
+		return qp.QueryParserBase.prefixQuery(field, termStr)
+	})
+}
+
+func (qp *MultiFieldQueryParser) fuzzyQuery(field, termStr string, minSimilarity float32) search.Query {
+	if field != "" {
+		return qp.QueryParserBase.fuzzyQuery(field, termStr, minSimilarity)
+	}
+	q, _ := qp.expand(func(field string) (search.Query, error) {
+		return qp.QueryParserBase.fuzzyQuery(field, termStr, minSimilarity), nil
+	})
+	return q
+}
+
+/*
+Parses a query which searches on the fields specified, where each of
+the queries is parsed against its own field, like:
+
+	(title:query1) (body:query2)
+
+It returns an error if the lengths of queries and fields differ.
+*/
+func ParseMultiField(matchVersion util.Version, queries, fields []string,
+	a analysis.Analyzer) (search.Query, error) {
+
+	flags := make([]search.Occur, len(fields))
+	for i := range flags {
+		flags[i] = search.SHOULD
+	}
+	return ParseMultiFieldWithFlags(matchVersion, queries, fields, flags, a)
+}
+
+/*
+Parses a query, searching on the fields specified, where each of the
+queries is parsed against its own field and added with its flag:
+
+	queries: {"query1", "query2", "query3"}
+	fields:  {"filename", "contents", "description"}
+	flags:   {SHOULD, MUST, MUST_NOT}
+
+gives
+
+	(filename:query1) +(contents:query2) -(description:query3)
+
+It returns an error if the lengths of queries, fields and flags
+differ.
+*/
+func ParseMultiFieldWithFlags(matchVersion util.Version, queries, fields []string,
+	flags []search.Occur, a analysis.Analyzer) (search.Query, error) {
+
+	if len(queries) != len(fields) || len(fields) != len(flags) {
+		return nil, errors.New("queries, fields, and flags array have have different length")
+	}
+	bq := search.NewBooleanQuery()
+	for i, field := range fields {
+		q, err := NewQueryParser(matchVersion, field, a).Parse(queries[i])
+		if err != nil {
+			return nil, err
+		}
+		if bq2, ok := q.(*search.BooleanQuery); !ok || len(bq2.Clauses()) > 0 {
+			bq.Add(q, flags[i])
+		}
+	}
+	return bq, nil
+}
@@ -0,0 +1,98 @@
+package classic
+
+import (
+	std "github.com/balzaczyy/golucene/analysis/standard"
+	"github.com/balzaczyy/golucene/core/search"
+	"github.com/balzaczyy/golucene/core/util"
+	"testing"
+)
+
+func assertMultiFieldQueryEquals(t *testing.T, qp *MultiFieldQueryParser, query, expected string) {
+	q, err := qp.Parse(query)
+	if err != nil {
+		t.Errorf("%v: %v", query, err)
+		return
+	}
+	if got := q.ToString(""); got != expected {
+		t.Errorf("Query /%v/ yielded /%v/, expecting /%v/", query, got, expected)
+	}
+}
+
+func TestMultiFieldSimple(t *testing.T) {
+	qp := NewMultiFieldQueryParser(util.VERSION_LATEST, []string{"b", "t"}, std.NewStandardAnalyzer(), nil)
+	for _, c := range [][2]string{
+		{"one", "b:one t:one"},
+		{"one two", "(b:one t:one) (b:two t:two)"},
+		{"+one +two", "+(b:one t:one) +(b:two t:two)"},
+		{"+one -two -three", "+(b:one t:one) -(b:two t:two) -(b:three t:three)"},
+		{"one^2 two", "((b:one t:one)^2) (b:two t:two)"},
+		{"one~ two", "(b:one~2 t:one~2) (b:two t:two)"},
+		{"one~0.8 two^2", "(b:one~0 t:one~0) ((b:two t:two)^2)"},
+		{"one* two*", "(b:one* t:one*) (b:two* t:two*)"},
+		{"[a TO c] two", "(b:[a TO c] t:[a TO c]) (b:two t:two)"},
+		{"w?ldcard", "b:w?ldcard t:w?ldcard"},
+		{"/wild.*/", "b:/wild.*/ t:/wild.*/"},
+		{"\"foo bar\"", "b:\"foo bar\" t:\"foo bar\""},
+		{"\"aa bb cc\" \"dd ee\"", "(b:\"aa bb cc\" t:\"aa bb cc\") (b:\"dd ee\" t:\"dd ee\")"},
+		{"\"foo bar\"~4", "b:\"foo bar\"~4 t:\"foo bar\"~4"},
+		{"b:\"foo bar\"~4", "b:\"foo bar\"~4"},
+		{"b:\"foo bar\"~4 c:foo", "b:\"foo bar\"~4 c:foo"},
+		{"(foo OR bar) AND z:zoo", "+((b:foo t:foo) (b:bar t:bar)) +z:zoo"},
+		{"the one", "(b:one t:one)"},
+		{"the", ""},
+	} {
+		assertMultiFieldQueryEquals(t, qp, c[0], c[1])
+	}
+
+	qp.SetDefaultOperator(OP_AND)
+	assertMultiFieldQueryEquals(t, qp, "one two", "+(b:one t:one) +(b:two t:two)")
+}
+
+func TestMultiFieldBoosts(t *testing.T) {
+	boosts := map[string]float32{"b": 5, "t": 10}
+	qp := NewMultiFieldQueryParser(util.VERSION_LATEST, []string{"b", "t"}, std.NewStandardAnalyzer(), boosts)
+	assertMultiFieldQueryEquals(t, qp, "one", "b:one^5 t:one^10")
+	assertMultiFieldQueryEquals(t, qp, "one two", "(b:one^5 t:one^10) (b:two^5 t:two^10)")
+	assertMultiFieldQueryEquals(t, qp, "one b:two", "(b:one^5 t:one^10) b:two")
+	assertMultiFieldQueryEquals(t, qp, "one^3", "(b:one^5 t:one^10)^3")
+	assertMultiFieldQueryEquals(t, qp, "\"one two\"", "b:\"one two\"^5 t:\"one two\"^10")
+}
+
+func TestMultiFieldDisjunctionMax(t *testing.T) {
+	qp := NewMultiFieldQueryParser(util.VERSION_LATEST, []string{"b", "t"}, std.NewStandardAnalyzer(),
+		map[string]float32{"t": 2})
+	qp.SetCombineMode(COMBINE_DISMAX, 0.1)
+	if qp.CombineMode() != COMBINE_DISMAX {
+		t.Error("expected COMBINE_DISMAX")
+	}
+	assertMultiFieldQueryEquals(t, qp, "one", "(b:one | t:one^2)~0.1")
+	assertMultiFieldQueryEquals(t, qp, "+one -two", "+(b:one | t:one^2)~0.1 -(b:two | t:two^2)~0.1")
+	assertMultiFieldQueryEquals(t, qp, "one*", "(b:one* | t:one*^2)~0.1")
+}
+
+func TestParseMultiField(t *testing.T) {
+	a := std.NewStandardAnalyzer()
+	q, err := ParseMultiField(util.VERSION_LATEST, []string{"one", "two"}, []string{"b", "t"}, a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, expected := q.ToString(""), "b:one t:two"; got != expected {
+		t.Errorf("expected %v, but was %v", expected, got)
+	}
+
+	q, err = ParseMultiFieldWithFlags(util.VERSION_LATEST, []string{"one", "the", "two three"},
+		[]string{"b", "t", "c"}, []search.Occur{search.MUST, search.SHOULD, search.MUST_NOT}, a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, expected := q.ToString(""), "+b:one -(c:two c:three)"; got != expected {
+		t.Errorf("expected %v, but was %v", expected, got)
+	}
+
+	if _, err = ParseMultiField(util.VERSION_LATEST, []string{"one"}, []string{"b", "t"}, a); err == nil {
+		t.Error("expected an error for queries and fields of different lengths")
+	}
+	if _, err = ParseMultiField(util.VERSION_LATEST, []string{"one", "two:"}, []string{"b", "t"}, a); err == nil {
+		t.Error("expected a parse error")
+	}
+}
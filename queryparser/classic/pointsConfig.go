@@ -0,0 +1,184 @@
+package classic
+
+import (
+	"fmt"
+	docu "github.com/balzaczyy/golucene/core/document"
+	"github.com/balzaczyy/golucene/core/search"
+	"math"
+	"strconv"
+	"time"
+)
+
+// The type of the points a field is indexed with.
+type PointType int
+
+const (
+	// The field is indexed with document.IntPoint.
+	POINT_INT PointType = iota
+	// The field is indexed with document.LongPoint.
+	POINT_LONG
+	// The field is indexed with document.DoublePoint.
+	POINT_DOUBLE
+)
+
+func (t PointType) String() string {
+	switch t {
+	case POINT_INT:
+		return "INT"
+	case POINT_LONG:
+		return "LONG"
+	case POINT_DOUBLE:
+		return "DOUBLE"
+	}
+	panic(fmt.Sprintf("invalid point type: %v", int(t)))
+}
+
+// standard/config/PointsConfig.java
+
+/*
+Holds how the range queries of a field indexed with points are built,
+instead of the TermRangeQuery built for the other fields. The bounds
+of the range are parsed as numbers of the point type, or, if a date
+layout is set, as dates in that layout, like time.Parse(). Dates are
+rounded to the resolution, in UTC, and searched as LongPoint of their
+milliseconds since the epoch, so they should be indexed the same way.
+*/
+type PointsConfig struct {
+	pointType  PointType
+	dateLayout string
+	resolution docu.DateResolution
+}
+
+// Constructs a config of a field indexed with points of the type.
+func NewPointsConfig(pointType PointType) *PointsConfig {
+	return &PointsConfig{pointType: pointType}
+}
+
+/*
+Constructs a config of a date field indexed with LongPoint, whose
+bounds are parsed with the layout, e.g. "2006-01-02", and rounded to
+the resolution.
+*/
+func NewDatePointsConfig(layout string, resolution docu.DateResolution) *PointsConfig {
+	return &PointsConfig{pointType: POINT_LONG, dateLayout: layout, resolution: resolution}
+}
+
+func (c *PointsConfig) PointType() PointType {
+	return c.pointType
+}
+
+// Returns the date layout, or "" if the field is not a date field.
+func (c *PointsConfig) DateLayout() string {
+	return c.dateLayout
+}
+
+func (c *PointsConfig) DateResolution() docu.DateResolution {
+	return c.resolution
+}
+
+/*
+Builds the point range query of the bounds, nil for open ends. As the
+point queries are inclusive, exclusive bounds are moved to the next
+value; an empty BooleanQuery is returned if there is no such value.
+*/
+func (c *PointsConfig) newRangeQuery(field string, part1, part2 *string,
+	startInclusive, endInclusive bool) (search.Query, error) {
+
+	switch c.pointType {
+	case POINT_INT, POINT_LONG:
+		lower, upper := int64(math.MinInt64), int64(math.MaxInt64)
+		if c.pointType == POINT_INT {
+			lower, upper = math.MinInt32, math.MaxInt32
+		}
+		min, max := lower, upper
+		if part1 != nil {
+			v, err := c.parseLong(field, *part1)
+			if err != nil {
+				return nil, err
+			}
+			if !startInclusive {
+				if v == upper {
+					return search.NewBooleanQuery(), nil
+				}
+				v++
+			}
+			min = v
+		}
+		if part2 != nil {
+			v, err := c.parseLong(field, *part2)
+			if err != nil {
+				return nil, err
+			}
+			if !endInclusive {
+				if v == lower {
+					return search.NewBooleanQuery(), nil
+				}
+				v--
+			}
+			max = v
+		}
+		if min > max {
+			return search.NewBooleanQuery(), nil
+		}
+		if c.pointType == POINT_INT {
+			return search.NewIntPointRangeQuery(field, []int32{int32(min)}, []int32{int32(max)}), nil
+		}
+		return search.NewLongPointRangeQuery(field, []int64{min}, []int64{max}), nil
+
+	case POINT_DOUBLE:
+		min, max := math.Inf(-1), math.Inf(1)
+		if part1 != nil {
+			v, err := c.parseDouble(field, *part1)
+			if err != nil {
+				return nil, err
+			}
+			if !startInclusive {
+				v = math.Nextafter(v, math.Inf(1))
+			}
+			min = v
+		}
+		if part2 != nil {
+			v, err := c.parseDouble(field, *part2)
+			if err != nil {
+				return nil, err
+			}
+			if !endInclusive {
+				v = math.Nextafter(v, math.Inf(-1))
+			}
+			max = v
+		}
+		if min > max {
+			return search.NewBooleanQuery(), nil
+		}
+		return search.NewDoublePointRangeQuery(field, []float64{min}, []float64{max}), nil
+	}
+	panic("not reachable")
+}
+
+/* Parses the bound as an integer of the point type, or as a date. */
+func (c *PointsConfig) parseLong(field, s string) (int64, error) {
+	if c.dateLayout != "" {
+		t, err := time.Parse(c.dateLayout, s)
+		if err != nil {
+			return 0, fmt.Errorf("Could not parse date '%v' of field %v with layout %v", s, field, c.dateLayout)
+		}
+		return docu.TimeToMillis(docu.RoundTime(t, c.resolution)), nil
+	}
+	bitSize := 64
+	if c.pointType == POINT_INT {
+		bitSize = 32
+	}
+	v, err := strconv.ParseInt(s, 10, bitSize)
+	if err != nil {
+		return 0, fmt.Errorf("Could not parse '%v' of field %v as %v point", s, field, c.pointType)
+	}
+	return v, nil
+}
+
+func (c *PointsConfig) parseDouble(field, s string) (float64, error) {
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(v) {
+		return 0, fmt.Errorf("Could not parse '%v' of field %v as %v point", s, field, c.pointType)
+	}
+	return v, nil
+}
@@ -8,6 +8,12 @@ import (
 	"github.com/balzaczyy/golucene/core/util"
 )
 
+// The maximum number of phrases a query with synonyms may be expanded to.
+const maxClauseCount = 1024
+
+// Reported when a query would need more than maxClauseCount clauses.
+type tooManyClauses struct{}
+
 type QueryBuilder struct {
 	analyzer                 analysis.Analyzer
 	enablePositionIncrements bool
@@ -19,6 +25,24 @@ func newQueryBuilder() *QueryBuilder {
 	}
 }
 
+// Returns true if position increments are enabled.
+func (qp *QueryBuilder) EnablePositionIncrements() bool {
+	return qp.enablePositionIncrements
+}
+
+/*
+Set to true to enable position increments in result query.
+
+When set, result phrase and multi-phrase queries will be aware of
+position increments. Useful when e.g. a StopFilter increases the
+position increment of the token that follows an omitted token.
+
+Default: true.
+*/
+func (qp *QueryBuilder) SetEnablePositionIncrements(enable bool) {
+	qp.enablePositionIncrements = enable
+}
+
 // L193
 func (qp *QueryBuilder) createFieldQuery(analyzer analysis.Analyzer,
 	operator search.Occur, field, queryText string, quoted bool, phraseSlop int) search.Query {
@@ -94,11 +118,22 @@ func (qp *QueryBuilder) createFieldQuery(analyzer analysis.Analyzer,
 				// no phrase query:
 
 				if positionCount == 1 {
-					panic("not implemented yet")
+					// only one position: synonyms of a single token
+					terms := make([]*index.Term, 0, numTokens)
+					for i := 0; i < numTokens; i++ {
+						hasNext, err := buffer.IncrementToken()
+						if err != nil {
+							continue // safe to ignore error, because we know the number of tokens
+						}
+						assert(hasNext)
+						termAtt.FillBytesRef()
+						terms = append(terms, index.NewTermFromBytes(field, util.DeepCopyOf(bytes).ToBytes()))
+					}
+					return qp.newSynonymQuery(terms)
 				} else {
 					// multiple positions
 					q := qp.newBooleanQuery(false)
-					var currentQuery search.Query
+					var currentQuery []*index.Term
 					for i := 0; i < numTokens; i++ {
 						hasNext, err := buffer.IncrementToken()
 						if err != nil {
@@ -107,25 +142,117 @@ func (qp *QueryBuilder) createFieldQuery(analyzer analysis.Analyzer,
 						assert(hasNext)
 						termAtt.FillBytesRef()
 
-						if posIncrAtt != nil && posIncrAtt.PositionIncrement() == 0 {
-							panic("not implemented yet")
-						} else {
-							if currentQuery != nil {
-								q.Add(currentQuery, operator)
-							}
-							currentQuery = qp.newTermQuery(index.NewTermFromBytes(field, util.DeepCopyOf(bytes).ToBytes()))
+						if posIncrAtt == nil || posIncrAtt.PositionIncrement() != 0 {
+							qp.add(q, currentQuery, operator)
+							currentQuery = nil
 						}
+						currentQuery = append(currentQuery, index.NewTermFromBytes(field, util.DeepCopyOf(bytes).ToBytes()))
 					}
-					q.Add(currentQuery, operator)
+					qp.add(q, currentQuery, operator)
 					return q
 				}
 			} else {
-				panic("not implemented yet")
+				// phrase query with several terms at some positions
+				var alternatives [][]*index.Term
+				var positions []int
+				position := -1
+				for i := 0; i < numTokens; i++ {
+					hasNext, err := buffer.IncrementToken()
+					if err != nil {
+						continue // safe to ignore error, because we know the number of tokens
+					}
+					assert(hasNext)
+					termAtt.FillBytesRef()
+					positionIncrement := 1
+					if posIncrAtt != nil {
+						positionIncrement = posIncrAtt.PositionIncrement()
+					}
+					term := index.NewTermFromBytes(field, util.DeepCopyOf(bytes).ToBytes())
+					if positionIncrement > 0 || len(alternatives) == 0 {
+						if qp.enablePositionIncrements {
+							position += positionIncrement
+						} else {
+							position++
+						}
+						alternatives = append(alternatives, []*index.Term{term})
+						positions = append(positions, position)
+					} else {
+						last := len(alternatives) - 1
+						alternatives[last] = append(alternatives[last], term)
+					}
+				}
+				return qp.newMultiPhraseQuery(alternatives, positions, phraseSlop)
 			}
 		} else {
-			panic("not implemented yet")
+			pq := qp.newPhraseQuery()
+			pq.SetSlop(phraseSlop)
+			position := -1
+			for i := 0; i < numTokens; i++ {
+				hasNext, err := buffer.IncrementToken()
+				if err != nil {
+					continue // safe to ignore error, because we know the number of tokens
+				}
+				assert(hasNext)
+				termAtt.FillBytesRef()
+				positionIncrement := 1
+				if posIncrAtt != nil {
+					positionIncrement = posIncrAtt.PositionIncrement()
+				}
+				term := index.NewTermFromBytes(field, util.DeepCopyOf(bytes).ToBytes())
+				if qp.enablePositionIncrements {
+					position += positionIncrement
+					pq.AddAt(term, position)
+				} else {
+					pq.Add(term)
+				}
+			}
+			return pq
 		}
-		panic("should not be here")
+	}
+}
+
+/*
+Builds the query matching any of the phrases made of one of the
+alternative terms at each position. As there is no MultiPhraseQuery,
+the phrases are enumerated in a BooleanQuery, whose number of clauses
+is limited.
+*/
+func (qp *QueryBuilder) newMultiPhraseQuery(alternatives [][]*index.Term, positions []int, slop int) search.Query {
+	count := 1
+	for _, terms := range alternatives {
+		if count *= len(terms); count > maxClauseCount {
+			panic(tooManyClauses{})
+		}
+	}
+	q := qp.newBooleanQuery(true)
+	choice := make([]int, len(alternatives))
+	for n := 0; n < count; n++ {
+		pq := qp.newPhraseQuery()
+		pq.SetSlop(slop)
+		for i, terms := range alternatives {
+			pq.AddAt(terms[choice[i]], positions[i])
+		}
+		q.Add(pq, search.SHOULD)
+		// next combination, the last position varying fastest
+		for i := len(choice) - 1; i >= 0; i-- {
+			if choice[i]++; choice[i] < len(alternatives[i]) {
+				break
+			}
+			choice[i] = 0
+		}
+	}
+	return q
+}
+
+// Adds a clause for the terms at one position to the boolean query.
+func (qp *QueryBuilder) add(q *search.BooleanQuery, current []*index.Term, operator search.Occur) {
+	switch len(current) {
+	case 0:
+		return
+	case 1:
+		q.Add(qp.newTermQuery(current[0]), operator)
+	default:
+		q.Add(qp.newSynonymQuery(current), operator)
 	}
 }
 
@@ -134,6 +261,16 @@ func (qp *QueryBuilder) newBooleanQuery(disableCoord bool) *search.BooleanQuery
 	return search.NewBooleanQueryDisableCoord(disableCoord)
 }
 
+// Builds a new PhraseQuery instance.
+func (qp *QueryBuilder) newPhraseQuery() *search.PhraseQuery {
+	return search.NewPhraseQuery()
+}
+
 func (qp *QueryBuilder) newTermQuery(term *index.Term) search.Query {
 	return search.NewTermQuery(term)
 }
+
+// Builds a new SynonymQuery instance for terms at the same position.
+func (qp *QueryBuilder) newSynonymQuery(terms []*index.Term) search.Query {
+	return search.NewSynonymQuery(terms...)
+}
@@ -1,14 +1,15 @@
 package classic
 
 import (
-	"errors"
-	// "fmt"
+	"fmt"
 	"github.com/balzaczyy/golucene/core/analysis"
 	"github.com/balzaczyy/golucene/core/search"
 	"github.com/balzaczyy/golucene/core/util"
 	"strings"
 )
 
+// classic/QueryParser.java
+
 type Operator int
 
 var (
@@ -16,28 +17,47 @@ var (
 	OP_AND = Operator(2)
 )
 
+/*
+This type is generated by JavaCC in Lucene, and is hand-written here.
+The most important method is Parse().
+
+The syntax for query strings is as follows: a Query is a series of
+clauses. A clause may be prefixed by:
+
+  - a plus (+) or a minus (-) sign, indicating that the clause is
+    required or prohibited respectively; or
+  - a term followed by a colon, indicating the field to be searched.
+    This enables one to construct queries which search multiple
+    fields.
+
+A clause may be either:
+
+  - a term, indicating all the documents that contain this term; or
+  - a nested query, enclosed in parentheses. Note that this may be
+    used with a +/- prefix to require any of a set of terms.
+
+Thus, in BNF, the query grammar is:
+
+	Query  ::= ( Clause )*
+	Clause ::= ["+", "-"] [<TERM> ":"] ( <TERM> | "(" Query ")" )
+
+Examples of appropriately formatted queries can be found in the
+query syntax documentation of Lucene.
+*/
 type QueryParser struct {
 	*QueryParserBase
 
-	token_source           *TokenManager
-	token                  *Token // current token
-	jj_nt                  *Token // next token
-	jj_ntk                 int
-	jj_scanpos, jj_lastpos *Token
-	jj_la                  int
-	jj_gen                 int
-	jj_la1                 []int
-
-	jj_2_rtns []*JJCalls
-	jj_rescan bool
-	jj_gc     int
+	token_source *TokenManager
+	token        *Token // current token
 }
 
+/*
+Create a query parser, where f is the default field for query terms,
+and a is the analyzer used to find terms in the query text.
+*/
 func NewQueryParser(matchVersion util.Version, f string, a analysis.Analyzer) *QueryParser {
 	qp := &QueryParser{
 		token_source: newTokenManager(newFastCharStream(strings.NewReader(""))),
-		jj_la1:       make([]int, 21),
-		jj_2_rtns:    make([]*JJCalls, 1),
 	}
 	qp.QueryParserBase = newQueryParserBase(qp)
 	qp.ReInit(newFastCharStream(strings.NewReader("")))
@@ -50,37 +70,46 @@ func NewQueryParser(matchVersion util.Version, f string, a analysis.Analyzer) *Q
 
 func (qp *QueryParser) conjunction() (int, error) {
 	ret := CONJ_NONE
-	if qp.jj_ntk == -1 {
-		qp.get_jj_ntk()
+	kind, err := qp.ntk()
+	if err != nil {
+		return 0, err
 	}
-	switch qp.jj_ntk {
-	case AND, OR:
-		panic("niy")
+	switch kind {
+	case AND:
+		ret = CONJ_AND
+	case OR:
+		ret = CONJ_OR
 	default:
-		qp.jj_la1[1] = qp.jj_gen
+		return ret, nil
 	}
-	return ret, nil
+	_, err = qp.consume(kind)
+	return ret, err
 }
 
-func (qp *QueryParser) modifiers() (ret int, err error) {
-	ret = MOD_NONE
-	if qp.jj_ntk == -1 {
-		qp.get_jj_ntk()
+func (qp *QueryParser) modifiers() (int, error) {
+	ret := MOD_NONE
+	kind, err := qp.ntk()
+	if err != nil {
+		return 0, err
 	}
-	switch qp.jj_ntk {
-	case NOT, PLUS, MINUS:
-		panic("not implemented yet")
+	switch kind {
+	case PLUS:
+		ret = MOD_REQ
+	case MINUS, NOT:
+		ret = MOD_NOT
 	default:
-		qp.jj_la1[3] = qp.jj_gen
+		return ret, nil
 	}
-	return
+	_, err = qp.consume(kind)
+	return ret, err
 }
 
+// This makes sure that there is no garbage after the query string
 func (qp *QueryParser) TopLevelQuery(field string) (q search.Query, err error) {
 	if q, err = qp.Query(field); err != nil {
 		return nil, err
 	}
-	_, err = qp.jj_consume_token(0)
+	_, err = qp.consume(EOF)
 	return q, err
 }
 
@@ -99,20 +128,19 @@ func (qp *QueryParser) Query(field string) (q search.Query, err error) {
 		firstQuery = q
 	}
 	for {
-		if qp.jj_ntk == -1 {
-			qp.get_jj_ntk()
+		kind, err := qp.ntk()
+		if err != nil {
+			return nil, err
 		}
-		var found = false
-		switch qp.jj_ntk {
+		switch kind {
 		case AND, OR, NOT, PLUS, MINUS, BAREOPER, LPAREN, STAR, QUOTED,
 			TERM, PREFIXTERM, WILDTERM, REGEXPTERM, RANGEIN_START,
 			RANGEEX_START, NUMBER:
 		default:
-			qp.jj_la1[4] = qp.jj_gen
-			found = true
-		}
-		if found {
-			break
+			if len(clauses) == 1 && firstQuery != nil {
+				return firstQuery, nil
+			}
+			return qp.booleanQuery(clauses)
 		}
 		if conj, err = qp.conjunction(); err != nil {
 			return nil, err
@@ -125,236 +153,260 @@ func (qp *QueryParser) Query(field string) (q search.Query, err error) {
 		}
 		clauses = qp.addClause(clauses, conj, mods, q)
 	}
-	if len(clauses) == 1 && firstQuery != nil {
-		return firstQuery, nil
-	} else {
-		return qp.booleanQuery(clauses)
-	}
 }
 
 func (qp *QueryParser) clause(field string) (q search.Query, err error) {
-	if qp.jj_2_1(2) {
-		panic("not implemented yet")
+	var fieldToken, boost *Token
+	// field name, using a lookahead of 2 tokens
+	if ok, err := qp.lookahead(TERM, COLON); err != nil {
+		return nil, err
+	} else if ok {
+		if fieldToken, err = qp.consume(TERM); err != nil {
+			return nil, err
+		}
+		if _, err = qp.consume(COLON); err != nil {
+			return nil, err
+		}
+		if field, err = qp.discardEscapeChar(fieldToken.image); err != nil {
+			return nil, err
+		}
+	} else if ok, err = qp.lookahead(STAR, COLON); err != nil {
+		return nil, err
+	} else if ok {
+		if _, err = qp.consume(STAR); err != nil {
+			return nil, err
+		}
+		if _, err = qp.consume(COLON); err != nil {
+			return nil, err
+		}
+		field = "*"
 	}
-	if qp.jj_ntk == -1 {
-		qp.get_jj_ntk()
+
+	kind, err := qp.ntk()
+	if err != nil {
+		return nil, err
 	}
-	var boost *Token
-	switch qp.jj_ntk {
+	switch kind {
 	case BAREOPER, STAR, QUOTED, TERM, PREFIXTERM, WILDTERM,
 		REGEXPTERM, RANGEIN_START, RANGEEX_START, NUMBER:
 		if q, err = qp.term(field); err != nil {
 			return nil, err
 		}
 	case LPAREN:
-		panic("not implemented yet")
-	default:
-		qp.jj_la1[7] = qp.jj_gen
-		if _, err = qp.jj_consume_token(-1); err != nil {
+		if _, err = qp.consume(LPAREN); err != nil {
+			return nil, err
+		}
+		if q, err = qp.Query(field); err != nil {
 			return nil, err
 		}
-		return nil, errors.New("parse error")
+		if _, err = qp.consume(RPAREN); err != nil {
+			return nil, err
+		}
+		if boost, err = qp.optionalBoost(); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, qp.parseError(BAREOPER, STAR, QUOTED, TERM, PREFIXTERM, WILDTERM,
+			REGEXPTERM, RANGEIN_START, RANGEEX_START, NUMBER, LPAREN)
 	}
 	return qp.handleBoost(q, boost), nil
 }
 
 func (qp *QueryParser) term(field string) (q search.Query, err error) {
-	var term, boost, fuzzySlop /*, goop1, goop2*/ *Token
-	var prefix, wildcard, fuzzy, regexp /*, startInc, endInc*/ bool
-	if qp.jj_ntk == -1 {
-		qp.get_jj_ntk()
+	var term, boost, fuzzySlop, goop1, goop2 *Token
+	var prefix, wildcard, fuzzy, regexp, startInc, endInc bool
+	kind, err := qp.ntk()
+	if err != nil {
+		return nil, err
 	}
-	switch qp.jj_ntk {
+	switch kind {
 	case BAREOPER, STAR, TERM, PREFIXTERM, WILDTERM, REGEXPTERM, NUMBER:
-		if qp.jj_ntk == -1 {
-			qp.get_jj_ntk()
+		if term, err = qp.consume(kind); err != nil {
+			return nil, err
 		}
-		switch qp.jj_ntk {
-		case TERM:
-			if term, err = qp.jj_consume_token(TERM); err != nil {
-				return nil, err
-			}
-		case STAR:
-			panic("not implemented yet")
+		switch kind {
+		case STAR, WILDTERM:
+			wildcard = true
 		case PREFIXTERM:
-			panic("not implemented yet")
-		case WILDTERM:
-			panic("not implemented yet")
+			prefix = true
 		case REGEXPTERM:
-			panic("not implemented yet")
-		case NUMBER:
-			panic("not implemented yet")
+			regexp = true
 		case BAREOPER:
-			panic("not implemented yet")
-		default:
-			panic("not implemented yet")
-		}
-		if qp.jj_ntk == -1 {
-			qp.get_jj_ntk()
+			term.image = term.image[:1]
 		}
-		switch qp.jj_ntk {
-		case FUZZY_SLOP:
-			panic("not implemented yet")
-		default:
-			qp.jj_la1[9] = qp.jj_gen
+		if fuzzySlop, err = qp.optional(FUZZY_SLOP); err != nil {
+			return nil, err
 		}
-		if qp.jj_ntk == -1 {
-			qp.get_jj_ntk()
+		fuzzy = fuzzySlop != nil
+		if boost, err = qp.optionalBoost(); err != nil {
+			return nil, err
 		}
-		switch qp.jj_ntk {
-		case CARAT:
-			panic("not implemented yet")
-		default:
-			qp.jj_la1[11] = qp.jj_gen
+		if boost != nil {
+			if slop, err := qp.optional(FUZZY_SLOP); err != nil {
+				return nil, err
+			} else if slop != nil {
+				fuzzySlop, fuzzy = slop, true
+			}
 		}
 		if q, err = qp.handleBareTokenQuery(field, term, fuzzySlop, prefix, wildcard, fuzzy, regexp); err != nil {
 			return nil, err
 		}
 
 	case RANGEIN_START, RANGEEX_START:
-		panic("not implemented yet")
+		if _, err = qp.consume(kind); err != nil {
+			return nil, err
+		}
+		startInc = kind == RANGEIN_START
+		if goop1, err = qp.consumeOneOf(RANGE_GOOP, RANGE_QUOTED); err != nil {
+			return nil, err
+		}
+		if _, err = qp.optional(RANGE_TO); err != nil {
+			return nil, err
+		}
+		if goop2, err = qp.consumeOneOf(RANGE_GOOP, RANGE_QUOTED); err != nil {
+			return nil, err
+		}
+		end, err := qp.consumeOneOf(RANGEIN_END, RANGEEX_END)
+		if err != nil {
+			return nil, err
+		}
+		endInc = end.kind == RANGEIN_END
+		if boost, err = qp.optionalBoost(); err != nil {
+			return nil, err
+		}
+		if q, err = qp.handleRangeQuery(field, goop1, goop2, startInc, endInc); err != nil {
+			return nil, err
+		}
+
 	case QUOTED:
-		panic("not implemented yet")
+		if term, err = qp.consume(QUOTED); err != nil {
+			return nil, err
+		}
+		if fuzzySlop, err = qp.optional(FUZZY_SLOP); err != nil {
+			return nil, err
+		}
+		if boost, err = qp.optionalBoost(); err != nil {
+			return nil, err
+		}
+		if q, err = qp.handleQuotedTerm(field, term, fuzzySlop); err != nil {
+			return nil, err
+		}
+
 	default:
-		panic("not implemented yet")
+		return nil, qp.parseError(BAREOPER, STAR, QUOTED, TERM, PREFIXTERM, WILDTERM,
+			REGEXPTERM, RANGEIN_START, RANGEEX_START, NUMBER)
 	}
 	return qp.handleBoost(q, boost), nil
 }
 
-// L473
-func (qp *QueryParser) jj_2_1(xla int) (ok bool) {
-	qp.jj_la = xla
-	qp.jj_lastpos = qp.token
-	qp.jj_scanpos = qp.token
-	defer func() {
-		// Disable following recover() to deal with dev panic
-		if err := recover(); err == lookAheadSuccess {
-			ok = true
-		}
-		qp.jj_save(0, xla)
-	}()
-	return !qp.jj_3_1()
-}
-
-func (qp *QueryParser) jj_3R_2() bool {
-	return qp.jj_scan_token(TERM) ||
-		qp.jj_scan_token(COLON)
-}
-
-func (qp *QueryParser) jj_3_1() bool {
-	xsp := qp.jj_scanpos
-	if qp.jj_3R_2() {
-		qp.jj_scanpos = xsp
-		if qp.jj_3R_3() {
-			return true
-		}
+/* Consumes "^" followed by a NUMBER if present, returning the number. */
+func (qp *QueryParser) optionalBoost() (*Token, error) {
+	if carat, err := qp.optional(CARAT); err != nil || carat == nil {
+		return nil, err
 	}
-	return false
-}
-
-func (qp *QueryParser) jj_3R_3() bool {
-	return qp.jj_scan_token(STAR) ||
-		qp.jj_scan_token(COLON)
+	return qp.consume(NUMBER)
 }
 
 // L540
+// Reinitialise.
 func (qp *QueryParser) ReInit(stream CharStream) {
 	qp.token_source.ReInit(stream)
 	qp.token = new(Token)
-	qp.jj_ntk = -1
-	qp.jj_gen = 0
-	for i, _ := range qp.jj_la1 {
-		qp.jj_la1[i] = -1
-	}
-	for i, _ := range qp.jj_2_rtns {
-		qp.jj_2_rtns[i] = new(JJCalls)
-	}
 }
 
-// L569
-func (qp *QueryParser) jj_consume_token(kind int) (*Token, error) {
-	oldToken := qp.token
-	if qp.token.next != nil {
-		qp.token = qp.token.next
-	} else {
-		qp.token.next = qp.token_source.nextToken()
-		qp.token = qp.token.next
-	}
-	qp.jj_ntk = -1
-	if qp.token.kind == kind {
-		qp.jj_gen++
-		if qp.jj_gc++; qp.jj_gc > 100 {
-			qp.jj_gc = 0
-			panic("not implemented yet")
-		}
-		return qp.token, nil
+/* Returns the next token without consuming it. */
+func (qp *QueryParser) peek(t *Token) (*Token, error) {
+	if t.next == nil {
+		next, err := qp.token_source.nextToken()
+		if err != nil {
+			return nil, err
+		}
+		t.next = next
 	}
-	qp.token = oldToken
-	panic("not implemented yet")
+	return t.next, nil
 }
 
-type LookAheadSuccess bool
-
-var lookAheadSuccess = LookAheadSuccess(true)
+/* Returns the kind of the next token. */
+func (qp *QueryParser) ntk() (int, error) {
+	t, err := qp.peek(qp.token)
+	if err != nil {
+		return 0, err
+	}
+	return t.kind, nil
+}
 
-func (qp *QueryParser) jj_scan_token(kind int) bool {
-	if qp.jj_scanpos == qp.jj_lastpos {
-		qp.jj_la--
-		if qp.jj_scanpos.next == nil {
-			nextToken := qp.token_source.nextToken()
-			qp.jj_scanpos.next = nextToken
-			qp.jj_scanpos = nextToken
-			qp.jj_lastpos = nextToken
-		} else {
-			qp.jj_scanpos = qp.jj_scanpos.next
-			qp.jj_lastpos = qp.jj_scanpos.next
+/* Returns true if the next tokens are of the given kinds. */
+func (qp *QueryParser) lookahead(kinds ...int) (bool, error) {
+	t := qp.token
+	for _, kind := range kinds {
+		var err error
+		if t, err = qp.peek(t); err != nil {
+			return false, err
+		}
+		if t.kind != kind {
+			return false, nil
+		}
+		if t.kind == EOF {
+			break
 		}
-	} else {
-		qp.jj_scanpos = qp.jj_scanpos.next
 	}
-	if qp.jj_rescan {
-		panic("niy")
+	return true, nil
+}
+
+func (qp *QueryParser) consume(kind int) (*Token, error) {
+	return qp.consumeOneOf(kind)
+}
+
+func (qp *QueryParser) consumeOneOf(kinds ...int) (*Token, error) {
+	t, err := qp.peek(qp.token)
+	if err != nil {
+		return nil, err
 	}
-	if qp.jj_scanpos.kind != kind {
-		return true
+	for _, kind := range kinds {
+		if t.kind == kind {
+			qp.token = t
+			return t, nil
+		}
 	}
-	if qp.jj_la == 0 && qp.jj_scanpos == qp.jj_lastpos {
-		panic(lookAheadSuccess)
+	return nil, qp.parseError(kinds...)
+}
+
+/* Consumes the next token if it is of the given kind, or returns nil. */
+func (qp *QueryParser) optional(kind int) (*Token, error) {
+	if ok, err := qp.lookahead(kind); err != nil || !ok {
+		return nil, err
 	}
-	return false
+	return qp.consume(kind)
 }
 
-// L636
-func (qp *QueryParser) get_jj_ntk() int {
-	if qp.jj_nt = qp.token.next; qp.jj_nt == nil {
-		qp.token.next = qp.token_source.nextToken()
-		qp.jj_ntk = qp.token.next.kind
-	} else {
-		qp.jj_ntk = qp.jj_nt.kind
+// Generate ParseError.
+func (qp *QueryParser) parseError(expected ...int) error {
+	t, err := qp.peek(qp.token)
+	if err != nil {
+		return err
+	}
+	images := make([]string, len(expected))
+	for i, kind := range expected {
+		images[i] = tokenImage[kind]
+	}
+	image := tokenImage[EOF]
+	if t.kind != EOF {
+		image = t.image
 	}
-	return qp.jj_ntk
+	return &ParseError{fmt.Sprintf("Encountered \"%v\" at line %v, column %v.\nWas expecting one of: %v",
+		image, t.beginLine, t.beginColumn, strings.Join(images, ", "))}
 }
 
-// L738
+// classic/ParseException.java
 
-func (qp *QueryParser) jj_save(index, xla int) {
-	p := qp.jj_2_rtns[index]
-	for p.gen > qp.jj_gen {
-		if p.next == nil {
-			p = new(JJCalls)
-			p.next = p
-			break
-		}
-		p = p.next
-	}
-	p.gen = qp.jj_gen + xla - qp.jj_la
-	p.first = qp.token
-	p.arg = xla
+/*
+This error is returned when parse errors are encountered. The message
+describes the token encountered and the tokens expected.
+*/
+type ParseError struct {
+	message string
 }
 
-type JJCalls struct {
-	gen   int
-	first *Token
-	arg   int
-	next  *JJCalls
+func (err *ParseError) Error() string {
+	return err.message
 }
@@ -4,10 +4,15 @@ import (
 	"errors"
 	"fmt"
 	"github.com/balzaczyy/golucene/core/analysis"
+	"github.com/balzaczyy/golucene/core/index"
 	"github.com/balzaczyy/golucene/core/search"
+	"strconv"
 	"strings"
+	"unicode/utf8"
 )
 
+// classic/QueryParserBase.java
+
 const (
 	CONJ_NONE = iota
 	CONJ_AND
@@ -20,11 +25,28 @@ const (
 	MOD_REQ  = 11
 )
 
+/*
+The methods a parser provides to the base. Besides the generated
+parsing methods, the factory methods of the queries can be overridden
+by types embedding a QueryParser, like MultiFieldQueryParser.
+*/
 type QueryParserBaseSPI interface {
 	ReInit(CharStream)
 	TopLevelQuery(string) (search.Query, error)
+
+	fieldQuery(field, queryText string, quoted bool) search.Query
+	fieldQueryWithSlop(field, queryText string, slop int) search.Query
+	rangeQuery(field string, part1, part2 *string, startInclusive, endInclusive bool) (search.Query, error)
+	wildcardQuery(field, termStr string) (search.Query, error)
+	regexpQuery(field, termStr string) (search.Query, error)
+	prefixQuery(field, termStr string) (search.Query, error)
+	fuzzyQuery(field, termStr string, minSimilarity float32) search.Query
 }
 
+/*
+This type is overridden by QueryParser in QueryParser.jj and acts to
+separate the majority of the code from the generated parser.
+*/
 type QueryParserBase struct {
 	*QueryBuilder
 
@@ -32,22 +54,44 @@ type QueryParserBase struct {
 
 	operator Operator
 
-	field      string
-	phraseSlop int
+	lowercaseExpandedTerms bool
+	allowLeadingWildcard   bool
+
+	field             string
+	phraseSlop        int
+	fuzzyMinSim       float32
+	fuzzyPrefixLength int
 
 	autoGeneratePhraseQueries bool
+
+	pointsConfigs map[string]*PointsConfig
 }
 
 func newQueryParserBase(spi QueryParserBaseSPI) *QueryParserBase {
 	return &QueryParserBase{
-		QueryBuilder: newQueryBuilder(),
-		spi:          spi,
-		operator:     OP_OR,
+		QueryBuilder:           newQueryBuilder(),
+		spi:                    spi,
+		operator:               OP_OR,
+		lowercaseExpandedTerms: true,
+		fuzzyMinSim:            search.FUZZY_DEFAULT_MAX_EDITS,
+		fuzzyPrefixLength:      search.FUZZY_DEFAULT_PREFIX_LENGTH,
 	}
 }
 
 // L116
+/*
+Parses a query string, returning a Query. The query is parsed against
+the default field, with the analyzer of the parser.
+*/
 func (qp *QueryParserBase) Parse(query string) (res search.Query, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			if _, ok := r.(tooManyClauses); !ok {
+				panic(r)
+			}
+			res, err = nil, fmt.Errorf("Cannot parse '%v': too many boolean clauses", query)
+		}
+	}()
 	qp.spi.ReInit(newFastCharStream(strings.NewReader(query)))
 	if res, err = qp.spi.TopLevelQuery(qp.field); err != nil {
 		return nil, errors.New(fmt.Sprintf("Cannot parse '%v': %v", query, err))
@@ -58,6 +102,135 @@ func (qp *QueryParserBase) Parse(query string) (res search.Query, err error) {
 	return qp.newBooleanQuery(false), nil
 }
 
+// Returns the default field.
+func (qp *QueryParserBase) Field() string {
+	return qp.field
+}
+
+// Returns the analyzer.
+func (qp *QueryParserBase) Analyzer() analysis.Analyzer {
+	return qp.analyzer
+}
+
+// Returns whether phrase queries are generated for unquoted text
+// analyzed into more than one term.
+func (qp *QueryParserBase) AutoGeneratePhraseQueries() bool {
+	return qp.autoGeneratePhraseQueries
+}
+
+/*
+Set to true if phrase queries will be automatically generated when
+the analyzer returns more than one term from whitespace delimited
+text. NOTE: this behavior may not be suitable for all languages.
+
+Set to false if phrase queries should only be generated when
+surrounded by double quotes.
+*/
+func (qp *QueryParserBase) SetAutoGeneratePhraseQueries(value bool) {
+	qp.autoGeneratePhraseQueries = value
+}
+
+// Get the minimal similarity for fuzzy queries.
+func (qp *QueryParserBase) FuzzyMinSim() float32 {
+	return qp.fuzzyMinSim
+}
+
+/*
+Set the minimum similarity for fuzzy queries. Values of 1 and more
+are edit distances, while fractions are the similarity relative to
+the length of the term. Default is 2.
+*/
+func (qp *QueryParserBase) SetFuzzyMinSim(fuzzyMinSim float32) {
+	qp.fuzzyMinSim = fuzzyMinSim
+}
+
+// Get the prefix length for fuzzy queries.
+func (qp *QueryParserBase) FuzzyPrefixLength() int {
+	return qp.fuzzyPrefixLength
+}
+
+// Set the prefix length for fuzzy queries. Default is 0.
+func (qp *QueryParserBase) SetFuzzyPrefixLength(fuzzyPrefixLength int) {
+	qp.fuzzyPrefixLength = fuzzyPrefixLength
+}
+
+// Sets the default slop for phrases. If zero, then exact phrase
+// matches are required. Default value is zero.
+func (qp *QueryParserBase) SetPhraseSlop(phraseSlop int) {
+	qp.phraseSlop = phraseSlop
+}
+
+// Gets the default slop for phrases.
+func (qp *QueryParserBase) PhraseSlop() int {
+	return qp.phraseSlop
+}
+
+/*
+Set to true to allow leading wildcard characters.
+
+When set, * or ? are allowed as the first character of a
+PrefixQuery and WildcardQuery. Note that this can produce very slow
+queries on big indexes. Default: false.
+*/
+func (qp *QueryParserBase) SetAllowLeadingWildcard(allowLeadingWildcard bool) {
+	qp.allowLeadingWildcard = allowLeadingWildcard
+}
+
+func (qp *QueryParserBase) AllowLeadingWildcard() bool {
+	return qp.allowLeadingWildcard
+}
+
+/*
+Sets the boolean operator of the QueryParser. In default mode
+(OP_OR) terms without any modifiers are considered optional: for
+example "capital of Hungary" is equal to "capital OR of OR Hungary".
+
+In OP_AND mode terms are considered to be in conjunction: the
+above mentioned query is parsed as "capital AND of AND Hungary".
+*/
+func (qp *QueryParserBase) SetDefaultOperator(op Operator) {
+	qp.operator = op
+}
+
+// Gets implicit operator setting, which will be either OP_AND or OP_OR.
+func (qp *QueryParserBase) DefaultOperator() Operator {
+	return qp.operator
+}
+
+/*
+Whether terms of wildcard, prefix, fuzzy and range queries are to be
+automatically lower-cased or not. Default is true.
+*/
+func (qp *QueryParserBase) SetLowercaseExpandedTerms(lowercaseExpandedTerms bool) {
+	qp.lowercaseExpandedTerms = lowercaseExpandedTerms
+}
+
+func (qp *QueryParserBase) LowercaseExpandedTerms() bool {
+	return qp.lowercaseExpandedTerms
+}
+
+/*
+Sets how the range queries of the field, indexed with points, are
+built: e.g. with NewPointsConfig(POINT_DOUBLE), price:[10 TO 20] is
+parsed into a point range query instead of a TermRangeQuery. A nil
+config reverts the field to term ranges.
+*/
+func (qp *QueryParserBase) SetPointsConfig(field string, config *PointsConfig) {
+	if config == nil {
+		delete(qp.pointsConfigs, field)
+		return
+	}
+	if qp.pointsConfigs == nil {
+		qp.pointsConfigs = make(map[string]*PointsConfig)
+	}
+	qp.pointsConfigs[field] = config
+}
+
+// Returns the points config of the field, or nil if it has none.
+func (qp *QueryParserBase) PointsConfig(field string) *PointsConfig {
+	return qp.pointsConfigs[field]
+}
+
 // L408
 func (qp *QueryParserBase) addClause(clauses []*search.BooleanClause,
 	conj, mods int, q search.Query) []*search.BooleanClause {
@@ -66,11 +239,21 @@ func (qp *QueryParserBase) addClause(clauses []*search.BooleanClause,
 	// If this term is introduced by AND, make the preceding term required,
 	// unless it's already prohibited
 	if len(clauses) > 0 && conj == CONJ_AND {
-		panic("not implemented yet")
+		c := clauses[len(clauses)-1]
+		if !c.IsProhibited() {
+			c.SetOccur(search.MUST)
+		}
 	}
 
 	if len(clauses) > 0 && qp.operator == OP_AND && conj == CONJ_OR {
-		panic("not implemented yet")
+		// If this term is introduced by OR, make the preceding term optional,
+		// unless it's prohibited (that means we leave -a OR b but +a OR b-->a OR b)
+		// notice if the input is a OR b, first term is parsed as required; without
+		// this modification a OR b would parsed as +a OR b
+		c := clauses[len(clauses)-1]
+		if !c.IsProhibited() {
+			c.SetOccur(search.SHOULD)
+		}
 	}
 
 	// We might have been passed an empty query; the term might have been
@@ -88,14 +271,17 @@ func (qp *QueryParserBase) addClause(clauses []*search.BooleanClause,
 			required = true
 		}
 	} else {
-		panic("not implemented yet")
+		// We set PROHIBITED if we're introduced by NOT or -; We set REQUIRED
+		// if not PROHIBITED and not introduced by OR
+		prohibited = (mods == MOD_NOT)
+		required = (!prohibited && conj != CONJ_OR)
 	}
 	if required {
-		panic("not implemented yet")
+		return append(clauses, qp.newBooleanClause(q, search.MUST))
 	} else if !prohibited {
 		return append(clauses, qp.newBooleanClause(q, search.SHOULD))
 	} else {
-		panic("not implemented yet")
+		return append(clauses, qp.newBooleanClause(q, search.MUST_NOT))
 	}
 }
 
@@ -107,14 +293,43 @@ func (qp *QueryParserBase) fieldQuery(field, queryText string, quoted bool) sear
 func (qp *QueryParserBase) newFieldQuery(analyzer analysis.Analyzer,
 	field, queryText string, quoted bool) search.Query {
 
-	var occur search.Occur
+	return qp.createFieldQuery(analyzer, qp.defaultOccur(), field, queryText,
+		quoted || qp.autoGeneratePhraseQueries, qp.phraseSlop)
+}
+
+func (qp *QueryParserBase) defaultOccur() search.Occur {
 	if qp.operator == OP_AND {
-		occur = search.MUST
-	} else {
-		occur = search.SHOULD
+		return search.MUST
 	}
-	return qp.createFieldQuery(analyzer, occur, field, queryText,
-		quoted || qp.autoGeneratePhraseQueries, qp.phraseSlop)
+	return search.SHOULD
+}
+
+/*
+Base implementation delegates to fieldQuery(), but with the given
+slop for the phrase queries which may be created.
+*/
+func (qp *QueryParserBase) fieldQueryWithSlop(field, queryText string, slop int) search.Query {
+	return qp.createFieldQuery(qp.analyzer, qp.defaultOccur(), field, queryText, true, slop)
+}
+
+// L497
+func (qp *QueryParserBase) rangeQuery(field string, part1, part2 *string,
+	startInclusive, endInclusive bool) (search.Query, error) {
+
+	if config, ok := qp.pointsConfigs[field]; ok {
+		return config.newRangeQuery(field, part1, part2, startInclusive, endInclusive)
+	}
+	if qp.lowercaseExpandedTerms {
+		if part1 != nil {
+			lower := strings.ToLower(*part1)
+			part1 = &lower
+		}
+		if part2 != nil {
+			lower := strings.ToLower(*part2)
+			part2 = &lower
+		}
+	}
+	return qp.newRangeQuery(field, part1, part2, startInclusive, endInclusive), nil
 }
 
 // L539
@@ -123,6 +338,46 @@ func (qp *QueryParserBase) newBooleanClause(q search.Query, occur search.Occur)
 	return search.NewBooleanClause(q, occur)
 }
 
+// Builds a new PrefixQuery instance.
+func (qp *QueryParserBase) newPrefixQuery(prefix *index.Term) search.Query {
+	return search.NewPrefixQuery(prefix)
+}
+
+// Builds a new RegexpQuery instance, or returns an error for an
+// invalid regular expression.
+func (qp *QueryParserBase) newRegexpQuery(regexp *index.Term) (q search.Query, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	return search.NewRegexpQuery(regexp), nil
+}
+
+// Builds a new FuzzyQuery instance.
+func (qp *QueryParserBase) newFuzzyQuery(term *index.Term, minimumSimilarity float32, prefixLength int) search.Query {
+	// FuzzyQuery doesn't yet allow constant score rewrite
+	numEdits := search.FloatToEdits(minimumSimilarity, utf8.RuneCount(term.Bytes))
+	return search.NewFuzzyQueryWith(term, numEdits, prefixLength,
+		search.FUZZY_DEFAULT_MAX_EXPANSIONS, search.FUZZY_DEFAULT_TRANSPOSITIONS)
+}
+
+// Builds a new TermRangeQuery instance.
+func (qp *QueryParserBase) newRangeQuery(field string, part1, part2 *string,
+	startInclusive, endInclusive bool) search.Query {
+	return search.NewTermRangeQueryFromStrings(field, part1, part2, startInclusive, endInclusive)
+}
+
+// Builds a new MatchAllDocsQuery instance.
+func (qp *QueryParserBase) newMatchAllDocsQuery() search.Query {
+	return search.NewMatchAllDocsQuery()
+}
+
+// Builds a new WildcardQuery instance.
+func (qp *QueryParserBase) newWildcardQuery(t *index.Term) search.Query {
+	return search.NewWildcardQuery(t)
+}
+
 // L676
 /*
 Factory method for generating query, given a set of clauses.
@@ -146,6 +401,73 @@ func (qp *QueryParserBase) booleanQueryDisableCoord(clauses []*search.BooleanCla
 	return query, nil
 }
 
+/*
+Factory method for generating a query. Called when parser parses an
+input term token that contains one or more wildcard characters (? and
+*), but is not a prefix term token (one that has just a single * char
+at the end).
+
+Depending on settings, prefix term may be lower-cased automatically.
+It will not go through the default Analyzer, however, since normal
+Analyzers are unlikely to work properly with wildcard templates.
+*/
+func (qp *QueryParserBase) wildcardQuery(field, termStr string) (search.Query, error) {
+	if field == "*" && termStr == "*" {
+		return qp.newMatchAllDocsQuery(), nil
+	}
+	if !qp.allowLeadingWildcard && (strings.HasPrefix(termStr, "*") || strings.HasPrefix(termStr, "?")) {
+		return nil, &ParseError{"'*' or '?' not allowed as first character in WildcardQuery"}
+	}
+	if qp.lowercaseExpandedTerms {
+		termStr = strings.ToLower(termStr)
+	}
+	return qp.newWildcardQuery(index.NewTerm(field, termStr)), nil
+}
+
+/*
+Factory method for generating a query. Called when parser parses an
+input term token that contains a regular expression query.
+
+Depending on settings, pattern term may be lower-cased automatically.
+It will not go through the default Analyzer, however, since normal
+Analyzers are unlikely to work properly with regular expression
+templates.
+*/
+func (qp *QueryParserBase) regexpQuery(field, termStr string) (search.Query, error) {
+	if qp.lowercaseExpandedTerms {
+		termStr = strings.ToLower(termStr)
+	}
+	return qp.newRegexpQuery(index.NewTerm(field, termStr))
+}
+
+/*
+Factory method for generating a query (similar to wildcardQuery).
+Called when parser parses an input term token that uses prefix
+notation; that is, contains a single '*' wildcard character as its
+last character.
+*/
+func (qp *QueryParserBase) prefixQuery(field, termStr string) (search.Query, error) {
+	if !qp.allowLeadingWildcard && strings.HasPrefix(termStr, "*") {
+		return nil, &ParseError{"'*' not allowed as first character in PrefixQuery"}
+	}
+	if qp.lowercaseExpandedTerms {
+		termStr = strings.ToLower(termStr)
+	}
+	return qp.newPrefixQuery(index.NewTerm(field, termStr)), nil
+}
+
+/*
+Factory method for generating a query (similar to wildcardQuery).
+Called when parser parses an input term token that has the fuzzy
+suffix (~) appended.
+*/
+func (qp *QueryParserBase) fuzzyQuery(field, termStr string, minSimilarity float32) search.Query {
+	if qp.lowercaseExpandedTerms {
+		termStr = strings.ToLower(termStr)
+	}
+	return qp.newFuzzyQuery(index.NewTerm(field, termStr), minSimilarity, qp.fuzzyPrefixLength)
+}
+
 // L827
 func (qp *QueryParserBase) handleBareTokenQuery(qField string,
 	term, fuzzySlop *Token, prefix, wildcard, fuzzy, regexp bool) (q search.Query, err error) {
@@ -155,58 +477,169 @@ func (qp *QueryParserBase) handleBareTokenQuery(qField string,
 		return nil, err
 	}
 	if wildcard {
-		panic("not implemented yet")
+		return qp.spi.wildcardQuery(qField, term.image)
 	} else if prefix {
-		panic("not implemented yet")
+		image, err := qp.discardEscapeChar(term.image[:len(term.image)-1])
+		if err != nil {
+			return nil, err
+		}
+		return qp.spi.prefixQuery(qField, image)
 	} else if regexp {
-		panic("not implemented yet")
+		return qp.spi.regexpQuery(qField, term.image[1:len(term.image)-1])
 	} else if fuzzy {
-		panic("not implemented yet")
+		return qp.handleBareFuzzy(qField, fuzzySlop, termImage)
 	} else {
-		return qp.fieldQuery(qField, termImage, false), nil
+		return qp.spi.fieldQuery(qField, termImage, false), nil
+	}
+}
+
+func (qp *QueryParserBase) handleBareFuzzy(qField string, fuzzySlop *Token, termImage string) (search.Query, error) {
+	fms := qp.fuzzyMinSim
+	if f, err := strconv.ParseFloat(fuzzySlop.image[1:], 32); err == nil {
+		fms = float32(f)
+	}
+	if fms < 0 {
+		return nil, &ParseError{"Minimum similarity for a FuzzyQuery has to be between 0.0f and 1.0f !"}
+	} else if fms >= 1 && fms != float32(int(fms)) {
+		return nil, &ParseError{"Fractional edit distances are not allowed!"}
+	}
+	return qp.spi.fuzzyQuery(qField, termImage, fms), nil
+}
+
+// extracted from the .jj grammar
+func (qp *QueryParserBase) handleQuotedTerm(qField string, term, fuzzySlop *Token) (search.Query, error) {
+	s := qp.phraseSlop // default
+	if fuzzySlop != nil {
+		if f, err := strconv.ParseFloat(fuzzySlop.image[1:], 32); err == nil {
+			s = int(f)
+		}
+	}
+	image, err := qp.discardEscapeChar(term.image[1 : len(term.image)-1])
+	if err != nil {
+		return nil, err
+	}
+	return qp.spi.fieldQueryWithSlop(qField, image, s), nil
+}
+
+// extracted from the .jj grammar
+func (qp *QueryParserBase) handleRangeQuery(qField string, goop1, goop2 *Token,
+	startInc, endInc bool) (search.Query, error) {
+
+	bound := func(goop *Token) (*string, error) {
+		image := goop.image
+		if goop.kind == RANGE_QUOTED {
+			image = image[1 : len(image)-1]
+		} else if image == "*" {
+			return nil, nil // open
+		}
+		s, err := qp.discardEscapeChar(image)
+		return &s, err
+	}
+	part1, err := bound(goop1)
+	if err != nil {
+		return nil, err
+	}
+	part2, err := bound(goop2)
+	if err != nil {
+		return nil, err
 	}
+	return qp.spi.rangeQuery(qField, part1, part2, startInc, endInc)
 }
 
 // L876
 func (qp *QueryParserBase) handleBoost(q search.Query, boost *Token) search.Query {
 	if boost != nil {
-		panic("not implemented yet")
+		f := float32(1)
+		if v, err := strconv.ParseFloat(boost.image, 32); err == nil {
+			f = float32(v)
+		}
+		// avoid boosting null queries, such as those caused by stop words
+		if q != nil {
+			q.SetBoost(f)
+		}
 	}
 	return q
 }
 
 // L906
-func (qp *QueryParserBase) discardEscapeChar(input string) (string, error) {
-	output := make([]rune, len(input))
+/*
+Returns a string where the escape char has been removed, or kept only
+once if there was a double escape.
 
-	length := 0
+Supports escaped unicode characters, e.g. translates \u0041 to A.
+*/
+func (qp *QueryParserBase) discardEscapeChar(input string) (string, error) {
+	output := make([]rune, 0, len(input))
 
 	lastCharWasEscapeChar := false
 
 	codePointMultiplier := 0
 
-	// codePoint := 0
+	codePoint := 0
 
 	for _, curChar := range input {
 		if codePointMultiplier > 0 {
-			panic("not implemented yet")
+			n, err := hexToInt(curChar)
+			if err != nil {
+				return "", err
+			}
+			codePoint += n * codePointMultiplier
+			if codePointMultiplier >>= 4; codePointMultiplier == 0 {
+				output = append(output, rune(codePoint))
+				codePoint = 0
+			}
 		} else if lastCharWasEscapeChar {
-			panic("not implemented yet")
+			if curChar == 'u' {
+				// found an escaped unicode character
+				codePointMultiplier = 16 * 16 * 16
+			} else {
+				// this character was escaped
+				output = append(output, curChar)
+			}
+			lastCharWasEscapeChar = false
 		} else {
 			if curChar == '\\' {
 				lastCharWasEscapeChar = true
 			} else {
-				output[length] = curChar
-				length++
+				output = append(output, curChar)
 			}
 		}
 	}
 
 	if codePointMultiplier > 0 {
-		return "", errors.New("Truncated unicode escape sequence.")
+		return "", &ParseError{"Truncated unicode escape sequence."}
 	}
 	if lastCharWasEscapeChar {
-		return "", errors.New("Term can not end with escape character.")
+		return "", &ParseError{"Term can not end with escape character."}
 	}
 	return string(output), nil
 }
+
+// Returns the numeric value of the hexadecimal character
+func hexToInt(c rune) (int, error) {
+	switch {
+	case '0' <= c && c <= '9':
+		return int(c - '0'), nil
+	case 'a' <= c && c <= 'f':
+		return int(c - 'a' + 10), nil
+	case 'A' <= c && c <= 'F':
+		return int(c - 'A' + 10), nil
+	}
+	return 0, &ParseError{fmt.Sprintf("Non-hex character in Unicode escape sequence: %c", c)}
+}
+
+/*
+Returns a string where those characters that QueryParser expects to
+be escaped are escaped by a preceding \.
+*/
+func Escape(s string) string {
+	var sb strings.Builder
+	for _, c := range s {
+		// These characters are part of the query syntax and must be escaped
+		if strings.ContainsRune("\\+-!():^[]\"{}~*?|&/", c) {
+			sb.WriteRune('\\')
+		}
+		sb.WriteRune(c)
+	}
+	return sb.String()
+}
@@ -0,0 +1,290 @@
+package classic
+
+import (
+	"github.com/balzaczyy/golucene/analysis/custom"
+	std "github.com/balzaczyy/golucene/analysis/standard"
+	docu "github.com/balzaczyy/golucene/core/document"
+	"github.com/balzaczyy/golucene/core/search"
+	"github.com/balzaczyy/golucene/core/util"
+	"strings"
+	"testing"
+)
+
+/* Returns a parser analyzing without stop words. */
+func newTestParser(t *testing.T) *QueryParser {
+	a, err := custom.NewCustomAnalyzerBuilder().
+		WithTokenizer("standard").
+		AddTokenFilter("lowercase").
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewQueryParser(util.VERSION_LATEST, "field", a)
+}
+
+func assertQueryEquals(t *testing.T, qp *QueryParser, query, expected string) {
+	q, err := qp.Parse(query)
+	if err != nil {
+		t.Errorf("%v: %v", query, err)
+		return
+	}
+	if got := q.ToString("field"); got != expected {
+		t.Errorf("Query /%v/ yielded /%v/, expecting /%v/", query, got, expected)
+	}
+}
+
+func TestSimple(t *testing.T) {
+	qp := newTestParser(t)
+	for _, c := range [][2]string{
+		{"term term term", "term term term"},
+		{"Türm term term", "türm term term"},
+		{"a AND b", "+a +b"},
+		{"(a AND b)", "+a +b"},
+		{"c OR (a AND b)", "c (+a +b)"},
+		{"a AND NOT b", "+a -b"},
+		{"a AND -b", "+a -b"},
+		{"a && b", "+a +b"},
+		{"a && !b", "+a -b"},
+		{"a OR b", "a b"},
+		{"a || b", "a b"},
+		{"a OR !b", "a -b"},
+		{"+term -term term", "+term -term term"},
+		{"foo:term AND field:anotherTerm", "+foo:term +anotherterm"},
+		{"term AND \"phrase phrase\"", "+term +\"phrase phrase\""},
+		{"\"hello there\"", "\"hello there\""},
+		{"germ term^2.0", "germ term^2"},
+		{"(term)^2.0", "term^2"},
+		{"(germ term)^2.0", "(germ term)^2"},
+		{"term^2.0", "term^2"},
+		{"term^2", "term^2"},
+		{"\"germ term\"^2.0", "\"germ term\"^2"},
+		{"\"term germ\"^2", "\"term germ\"^2"},
+		{"(foo OR bar) AND (baz OR boo)", "+(foo bar) +(baz boo)"},
+		{"((a OR b) AND NOT c) OR d", "(+(a b) -c) d"},
+		{"+(apple \"steve jobs\") -(foo bar baz)", "+(apple \"steve jobs\") -(foo bar baz)"},
+		{"+title:(dog OR cat) -author:\"bob dole\"", "+(title:dog title:cat) -author:\"bob dole\""},
+		{"a + b", "a b"},
+	} {
+		assertQueryEquals(t, qp, c[0], c[1])
+	}
+
+	// stop words are removed by the analyzer
+	qp = NewQueryParser(util.VERSION_LATEST, "field", std.NewStandardAnalyzer())
+	assertQueryEquals(t, qp, "the foo", "foo")
+	assertQueryEquals(t, qp, "the", "")
+	assertQueryEquals(t, qp, "(the)^2 foo", "foo")
+}
+
+func TestPhraseSlop(t *testing.T) {
+	qp := newTestParser(t)
+	assertQueryEquals(t, qp, "\"term germ\"~2", "\"term germ\"~2")
+	assertQueryEquals(t, qp, "\"term germ\"~2 flork", "\"term germ\"~2 flork")
+	assertQueryEquals(t, qp, "\"term\"~2", "term")
+	assertQueryEquals(t, qp, "\" \"~2 germ", "germ")
+	assertQueryEquals(t, qp, "\"term germ\"~2^2", "\"term germ\"~2^2")
+
+	qp.SetPhraseSlop(3)
+	assertQueryEquals(t, qp, "\"term germ\"", "\"term germ\"~3")
+	// stop words leave a gap, unless position increments are disabled
+	qp = NewQueryParser(util.VERSION_LATEST, "field", std.NewStandardAnalyzer())
+	qp.SetPhraseSlop(3)
+	assertQueryEquals(t, qp, "\"the term the germ\"", "\"? term ? germ\"~3")
+	qp.SetEnablePositionIncrements(false)
+	assertQueryEquals(t, qp, "\"the term the germ\"", "\"term germ\"~3")
+}
+
+func TestMultiPhrase(t *testing.T) {
+	a, err := custom.NewCustomAnalyzerBuilder().
+		WithTokenizer("standard").
+		AddTokenFilter("typeAsSynonym", "prefix", "_").
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	qp := NewQueryParser(util.VERSION_LATEST, "field", a)
+	assertQueryEquals(t, qp, "\"foo bar\"",
+		"\"foo bar\" \"foo _<ALPHANUM>\" \"_<ALPHANUM> bar\" \"_<ALPHANUM> _<ALPHANUM>\"")
+	assertQueryEquals(t, qp, "\"foo\"", "Synonym(_<ALPHANUM> foo)")
+}
+
+func TestWildcard(t *testing.T) {
+	qp := newTestParser(t)
+	for _, c := range [][2]string{
+		{"term*", "term*"},
+		{"term*^2", "term*^2"},
+		{"Term*", "term*"},
+		{"TERM*", "term*"},
+		{"t*", "t*"},
+		{"term~", "term~2"},
+		{"term~1", "term~1"},
+		{"term~0.7", "term~1"},
+		{"term~^3", "term~2^3"},
+		{"term^3~", "term~2^3"},
+		{"term*germ", "term*germ"},
+		{"term*germ^3", "term*germ^3"},
+		{"te?m", "te?m"},
+		{"Te?m", "te?m"},
+		{"TE?M", "te?m"},
+		{"Te?m*gerM", "te?m*germ"},
+		{"*:*", "*:*"},
+		{"/[a-z][123]/", "/[a-z][123]/"},
+		{"/[A-Z][123]/^0.5", "/[a-z][123]/^0.5"},
+		{"title:/fo+/", "title:/fo+/"},
+		{"\\a*", "a*"},
+	} {
+		assertQueryEquals(t, qp, c[0], c[1])
+	}
+
+	// leading wildcards are not allowed by default
+	for _, query := range []string{"*Term", "?Term"} {
+		if _, err := qp.Parse(query); err == nil {
+			t.Errorf("%v: expected a leading wildcard error", query)
+		}
+	}
+	qp.SetAllowLeadingWildcard(true)
+	assertQueryEquals(t, qp, "*Term", "*term")
+	assertQueryEquals(t, qp, "?Term", "?term")
+
+	qp.SetLowercaseExpandedTerms(false)
+	assertQueryEquals(t, qp, "Term*", "Term*")
+	assertQueryEquals(t, qp, "[A TO C]", "[A TO C]")
+
+	if q, err := qp.Parse("term~"); err != nil {
+		t.Error(err)
+	} else if fq := q.(*search.FuzzyQuery); fq.MaxEdits() != 2 || fq.PrefixLength() != 0 {
+		t.Errorf("unexpected fuzzy query settings: %v, %v", fq.MaxEdits(), fq.PrefixLength())
+	}
+	qp.SetFuzzyPrefixLength(2)
+	if q, err := qp.Parse("term~1"); err != nil {
+		t.Error(err)
+	} else if fq := q.(*search.FuzzyQuery); fq.MaxEdits() != 1 || fq.PrefixLength() != 2 {
+		t.Errorf("unexpected fuzzy query settings: %v, %v", fq.MaxEdits(), fq.PrefixLength())
+	}
+}
+
+func TestRange(t *testing.T) {
+	qp := newTestParser(t)
+	for _, c := range [][2]string{
+		{"[ a TO z]", "[a TO z]"},
+		{"[ a TO z }", "[a TO z}"},
+		{"{ a TO z]", "{a TO z]"},
+		{"[ a z]", "[a TO z]"},
+		{"[ * TO z]", "[* TO z]"},
+		{"{ a TO *}", "{a TO *}"},
+		{"[ A TO Z]^2.0", "[a TO z]^2"},
+		{"( field:[ a TO z ] )", "[a TO z]"},
+		{"gack ( bar blar { a TO z}) ", "gack (bar blar {a TO z})"},
+		{"[\"a b\" TO \"c d\"]", "[a b TO c d]"},
+		{"[\\* TO z]", "[* TO z]"},
+		{"title:[a TO z] foo", "title:[a TO z] foo"},
+	} {
+		assertQueryEquals(t, qp, c[0], c[1])
+	}
+}
+
+func TestPointRange(t *testing.T) {
+	qp := newTestParser(t)
+	qp.SetPointsConfig("price", NewPointsConfig(POINT_DOUBLE))
+	qp.SetPointsConfig("count", NewPointsConfig(POINT_INT))
+	qp.SetPointsConfig("date", NewDatePointsConfig("2006-01-02", docu.RESOLUTION_DAY))
+	qp.SetPointsConfig("month", NewDatePointsConfig("2006-01-02", docu.RESOLUTION_MONTH))
+	for _, c := range [][2]string{
+		{"price:[10 TO 20]", "price:[10 TO 20]"},
+		{"price:[1.5 TO *]^2", "price:[1.5 TO +Inf]^2"},
+		{"count:{10 TO 20}", "count:[11 TO 19]"},
+		{"count:[* TO 20}", "count:[-2147483648 TO 19]"},
+		{"count:{2147483647 TO *]", ""},
+		{"count:{1 TO 2}", ""},
+		{"date:[2021-01-01 TO 2021-02-01]", "date:[1609459200000 TO 1612137600000]"},
+		{"month:[2021-01-15 TO 2021-01-20]", "month:[1609459200000 TO 1609459200000]"},
+		{"title:[10 TO 20] AND price:[10 TO 20]", "+title:[10 TO 20] +price:[10 TO 20]"},
+	} {
+		assertQueryEquals(t, qp, c[0], c[1])
+	}
+	for _, c := range [][2]string{
+		{"count:[a TO 20]", "Cannot parse 'count:[a TO 20]': Could not parse 'a' of field count as INT point"},
+		{"count:[1 TO 3000000000]", "Cannot parse 'count:[1 TO 3000000000]': Could not parse '3000000000' of field count as INT point"},
+		{"date:[2021 TO *]", "Cannot parse 'date:[2021 TO *]': Could not parse date '2021' of field date with layout 2006-01-02"},
+	} {
+		if _, err := qp.Parse(c[0]); err == nil || err.Error() != c[1] {
+			t.Errorf("%v: expected error %v, but was %v", c[0], c[1], err)
+		}
+	}
+	qp.SetPointsConfig("price", nil)
+	assertQueryEquals(t, qp, "price:[10 TO 20]", "price:[10 TO 20]")
+	if _, ok := mustParse(t, qp, "price:[10 TO 20]").(*search.TermRangeQuery); !ok {
+		t.Error("expected a TermRangeQuery once the points config is removed")
+	}
+}
+
+func mustParse(t *testing.T, qp *QueryParser, query string) search.Query {
+	q, err := qp.Parse(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return q
+}
+
+func TestDefaultOperator(t *testing.T) {
+	qp := newTestParser(t)
+	qp.SetDefaultOperator(OP_AND)
+	if qp.DefaultOperator() != OP_AND {
+		t.Error("expected OP_AND")
+	}
+	for _, c := range [][2]string{
+		{"a b", "+a +b"},
+		{"a OR b", "a b"},
+		{"a b OR c", "+a b c"},
+		{"a -b", "+a -b"},
+		{"-a OR b", "-a b"},
+		{"(a b) c", "+(+a +b) +c"},
+	} {
+		assertQueryEquals(t, qp, c[0], c[1])
+	}
+}
+
+func TestEscaped(t *testing.T) {
+	qp := newTestParser(t)
+	qp.SetLowercaseExpandedTerms(false)
+	for _, c := range [][2]string{
+		{"a\\-b\\:c", "a b:c"},
+		{"a\\-b\\:c*", "a-b:c*"},
+		{"a\\-b\\:c~", "a-b:c~2"},
+		{"t\\u0069tle:foo", "title:foo"},
+		{"\\u0041*", "A*"},
+		{"\"a \\\"b\\\" c\"", "\"a b c\""},
+		{"[ a\\- TO a\\+ ]", "[a- TO a+]"},
+	} {
+		assertQueryEquals(t, qp, c[0], c[1])
+	}
+	if escaped := Escape("a+b:(c)\\d*"); escaped != "a\\+b\\:\\(c\\)\\\\d\\*" {
+		t.Errorf("unexpected escaped string %v", escaped)
+	}
+	for _, query := range []string{"XY\\", "a\\u004", "a\\u004g*"} {
+		if _, err := qp.Parse(query); err == nil {
+			t.Errorf("%v: expected an escaping error", query)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	qp := newTestParser(t)
+	for _, c := range [][2]string{
+		{"foo:", `Cannot parse 'foo:': Encountered "<EOF>" at line 1, column 4.`},
+		{"\"foo", `Cannot parse '"foo': Lexical error at line 1, column 5.  Encountered: <EOF> after : "\"foo"`},
+		{"foo)", `Cannot parse 'foo)': Encountered ")" at line 1, column 3.`},
+		{"foo^", `Cannot parse 'foo^': Encountered "<EOF>" at line 1, column 4.`},
+		{"foo^ 2", `Cannot parse 'foo^ 2': Lexical error at line 1, column 5.  Encountered: " " (32), after : ""`},
+		{"[a TO b", `Cannot parse '[a TO b': Encountered "<EOF>" at line 1, column 7.`},
+		{"foo~1.5", `Cannot parse 'foo~1.5': Fractional edit distances are not allowed!`},
+		{"/[a/", `Cannot parse '/[a/': `},
+		{"a ]", `Cannot parse 'a ]': Lexical error at line 1, column 3.  Encountered: "]" (93), after : ""`},
+	} {
+		_, err := qp.Parse(c[0])
+		if err == nil {
+			t.Errorf("%v: expected an error", c[0])
+		} else if !strings.HasPrefix(err.Error(), c[1]) {
+			t.Errorf("%v: expected error %v, but was %v", c[0], c[1], err)
+		}
+	}
+}
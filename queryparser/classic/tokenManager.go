@@ -1,576 +1,450 @@
 package classic
 
 import (
-// "fmt"
+	"io"
+	"strings"
 )
 
-var jjbitVec0 = []int64{1, 0, 0, 0}
-var jjbitVec4 = []uint64{
-	0xfffefffffffffffe, 0xffffffffffffffff, 0xffffffffffffffff, 0xffffffffffffffff,
-}
-
-var jjnextStates = []int{
-	37, 39, 40, 17, 18, 20, 42, 45, 31, 46, 43, 22, 23, 25, 26, 24,
-	25, 26, 45, 31, 46, 44, 47, 35, 22, 28, 29, 27, 27, 30, 30, 0,
-	1, 2, 4, 5,
-}
-
-var jjstrLiteralImages = map[int]string{
-	0: "", 11: "\u0053", 12: "\055",
-	14: "\050", 15: "\051", 16: "\072", 17: "\052", 18: "\136",
-	25: "\133", 26: "\173", 28: "\124\117", 29: "\135", 30: "\175",
-}
-
-var jjnewLexState = []int{
-	-1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, 0, -1, -1, -1, -1, -1, -1,
-	1, 1, 2, -1, 2, 2, -1, -1,
-}
-
-var jjtoToken = []int64{0x1ffffff01}
+// classic/QueryParserTokenManager.java
 
+/*
+Token Manager. It is a hand-written equivalent of the lexer generated
+by JavaCC from QueryParser.jj: in each lexical state, the longest
+possible token is matched, and ties are resolved by the order of
+declaration of the token kinds.
+*/
 type TokenManager struct {
 	curLexState     int
 	defaultLexState int
-	jjnewStateCnt   int
-	jjround         int
-	jjmatchedPos    int
-	jjmatchedKind   int
 
 	input_stream CharStream
-	jjrounds     []int
-	jjstateSet   []int
 	curChar      rune
+	eof          bool
 }
 
 func newTokenManager(stream CharStream) *TokenManager {
 	return &TokenManager{
-		curLexState:     2,
-		defaultLexState: 2,
+		curLexState:     DEFAULT,
+		defaultLexState: DEFAULT,
 		input_stream:    stream,
-		jjrounds:        make([]int, 49),
-		jjstateSet:      make([]int, 98),
 	}
 }
 
-// L41
+// Reinitialise parser.
+func (tm *TokenManager) ReInit(stream CharStream) {
+	tm.input_stream = stream
+	tm.curLexState = tm.defaultLexState
+	tm.eof = false
+}
 
-func (tm *TokenManager) jjMoveStringLiteralDfa0_2() int {
-	switch tm.curChar {
-	case 40:
-		panic("not implemented yet")
-	case 41:
-		panic("not implemented yet")
-	case 42:
-		panic("not implemented yet")
-	case 43:
-		panic("not implemented yet")
-	case 45:
-		panic("not implemented yet")
-	case 58:
-		panic("not implemented yet")
-	case 91:
-		panic("not implemented yet")
-	case 94:
-		panic("not implemented yet")
-	case 123:
-		panic("not implemented yet")
-	default:
-		return tm.jjMoveNfa_2(0, 0)
+// Switch to specified lex state.
+func (tm *TokenManager) SwitchTo(lexState int) {
+	if lexState < Boost || lexState > DEFAULT {
+		panic(newTokenMgrError(false, lexState, 0, 0, "", 0, INVALID_LEXICAL_STATE))
 	}
+	tm.curLexState = lexState
 }
 
-// L87
+/* Reads the next char into curChar, returning false at the end of the input. */
+func (tm *TokenManager) read() (bool, error) {
+	c, err := tm.input_stream.readChar()
+	if err == io.EOF {
+		tm.eof = true
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	tm.curChar = c
+	return true, nil
+}
 
-func (tm *TokenManager) jjMoveNfa_2(startState, curPos int) int {
-	startsAt := 0
-	tm.jjnewStateCnt = 49
-	i := 1
-	tm.jjstateSet[0] = startState
-	kind := 0x7fffffff
+/* Pushes back the last char which was read, unless the input was exhausted. */
+func (tm *TokenManager) unread() {
+	if tm.eof {
+		tm.eof = false
+	} else {
+		tm.input_stream.backup(1)
+	}
+}
+
+func isWhitespace(c rune) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '　'
+}
+
+func isTermStartChar(c rune) bool {
+	return !isWhitespace(c) && !strings.ContainsRune("+-!():^[]\"{}~*?\\/", c)
+}
+
+func isTermChar(c rune) bool {
+	return isTermStartChar(c) || c == '-' || c == '+'
+}
+
+func isNumChar(c rune) bool {
+	return c >= '0' && c <= '9'
+}
+
+// Get the next Token.
+func (tm *TokenManager) nextToken() (*Token, error) {
 	for {
-		if tm.jjround++; tm.jjround == 0x7fffffff {
-			tm.reInitRounds()
+		tm.eof = false
+		c, err := tm.input_stream.beginToken()
+		if err == io.EOF {
+			t := newToken(EOF, "")
+			t.beginLine, t.endLine = tm.input_stream.beginLine(), tm.input_stream.endLine()
+			t.beginColumn, t.endColumn = tm.input_stream.beginColumn(), tm.input_stream.endColumn()
+			return t, nil
+		} else if err != nil {
+			return nil, err
 		}
-		if tm.curChar < 64 {
-			l := int64(1 << uint(tm.curChar))
-			for {
-				i--
-				switch tm.jjstateSet[i] {
-				case 49, 33:
-					if (0xfbff7cf8ffffd9ff & uint64(l)) != 0 {
-						if kind > 23 {
-							kind = 23
-						}
-						tm.jjCheckNAddTwoStates(33, 34)
-					}
-				case 0:
-					if (0xfbff54f8ffffd9ff & uint64(l)) != 0 {
-						if kind > 23 {
-							kind = 23
-						}
-						tm.jjCheckNAddTwoStates(33, 34)
-					} else if (0x100002600 & l) != 0 {
-						if kind > 7 {
-							kind = 7
-						}
-					} else if (0x280200000000 & l) != 0 {
-						panic("not implemented yet")
-					} else if tm.curChar == 47 {
-						panic("not implemented yet")
-					} else if tm.curChar == 34 {
-						panic("not implemented yet")
-					}
-					if (0x7bff50f8ffffd9ff & l) != 0 {
-						if kind > 20 {
-							kind = 20
-						}
-						tm.jjCheckNAddStates(6, 10)
-					} else if tm.curChar == 42 {
-						panic("not implemented yet")
-					} else if tm.curChar == 33 {
-						panic("not implemented yet")
-					}
-					if tm.curChar == 38 {
-						panic("not implemented yet")
-					}
-
-				case 4:
-					panic("not implemented yet")
-				case 5:
-					panic("not implemented yet")
-				case 13:
-					panic("not implemented yet")
-				case 14:
-					panic("not implemented yet")
-				case 15:
-					panic("not implemented yet")
-				case 16:
-					panic("not implemented yet")
-				case 17:
-					panic("not implemented yet")
-				case 19:
-					panic("not implemented yet")
-				case 20:
-					panic("not implemented yet")
-				case 22:
-					panic("not implemented yet")
-				case 23:
-					panic("not implemented yet")
-				case 24:
-					panic("not implemented yet")
-				case 25:
-					panic("not implemented yet")
-				case 27:
-					panic("not implemented yet")
-				case 28:
-					panic("not implemented yet")
-				case 30:
-					panic("not implemented yet")
-				case 31:
-					if tm.curChar == 42 && kind > 22 {
-						kind = 22
-					}
-				case 32:
-					panic("not implemented yet")
-				case 35:
-					panic("not implemented yet")
-				case 36, 38:
-					panic("not implemented yet")
-				case 37:
-					panic("not implemented yet")
-				case 40:
-					panic("not implemented yet")
-				case 41:
-					panic("not implemented yet")
-				case 42:
-					if (0x7bff78f8ffffd9ff & l) != 0 {
-						if kind > 20 {
-							kind = 20
-						}
-						tm.jjCheckNAddTwoStates(42, 43)
-					}
-				case 44:
-					panic("not implemented yet")
-				case 45:
-					if (0x7bff78f8ffffd9ff & l) != 0 {
-						tm.jjCheckNAddStates(18, 20)
-					}
-				case 47:
-					panic("not implemented yet")
-				}
-				if i == startsAt {
-					break
-				}
-			}
-		} else if tm.curChar < 128 {
-			l := int64(1) << (uint(tm.curChar) & 077)
-			for {
-				i--
-				switch tm.jjstateSet[i] {
-				case 49:
-					panic("niy")
-				case 0:
-					if (0x97ffffff87ffffff & uint64(l)) != 0 {
-						if kind > 20 {
-							kind = 20
-						}
-						tm.jjCheckNAddStates(6, 10)
-					} else if tm.curChar == 92 {
-						panic("niy")
-					} else if tm.curChar == 126 {
-						panic("niy")
-					}
-					if (0x97ffffff87ffffff & uint64(l)) != 0 {
-						if kind > 23 {
-							kind = 23
-						}
-						tm.jjCheckNAddTwoStates(33, 34)
-					}
-					switch tm.curChar {
-					case 78:
-						panic("niy")
-					case 124:
-						panic("niy")
-					case 79:
-						panic("niy")
-					case 65:
-						panic("niy")
-					}
-				case 1:
-					panic("niy")
-				case 2:
-					panic("niy")
-				case 3:
-					panic("niy")
-				case 6:
-					panic("niy")
-				case 7:
-					panic("niy")
-				case 8:
-					panic("niy")
-				case 9:
-					panic("niy")
-				case 10:
-					panic("niy")
-				case 11:
-					panic("niy")
-				case 12:
-					panic("niy")
-				case 17:
-					panic("niy")
-				case 18:
-					panic("niy")
-				case 19:
-					panic("niy")
-				case 21:
-					panic("niy")
-				case 25:
-					panic("niy")
-				case 26:
-					panic("niy")
-				case 27:
-					panic("niy")
-				case 28:
-					panic("niy")
-				case 29:
-					panic("niy")
-				case 30:
-					panic("niy")
-				case 32:
-					panic("niy")
-				case 33:
-					if (0x97ffffff87ffffff & uint64(l)) != 0 {
-						if kind > 23 {
-							kind = 23
-						}
-						tm.jjCheckNAddTwoStates(33, 34)
-					}
-				case 34:
-					if tm.curChar == 92 {
-						tm.jjCheckNAddTwoStates(35, 35)
-					}
-				case 35:
-					panic("niy")
-				case 37:
-					panic("niy")
-				case 39:
-					panic("niy")
-				case 41:
-					panic("niy")
-				case 42:
-					if (0x97ffffff87ffffff & uint64(l)) != 0 {
-						if kind > 20 {
-							kind = 20
-						}
-						tm.jjCheckNAddTwoStates(42, 43)
-					}
-				case 43:
-					if tm.curChar == 92 {
-						tm.jjCheckNAddTwoStates(44, 44)
-					}
-				case 44:
-					panic("niy")
-				case 45:
-					if (0x97ffffff87ffffff & uint64(l)) != 0 {
-						tm.jjCheckNAddStates(18, 20)
-					}
-				case 46:
-					if tm.curChar == 92 {
-						tm.jjCheckNAddTwoStates(47, 47)
-					}
-				case 47:
-					panic("niy")
-				case 48:
-					panic("niy")
-				}
-				if i <= startsAt { // ==?
-					break
-				}
-			}
-		} else {
-			hiByte := int(tm.curChar >> 8)
-			i1 := hiByte >> 6
-			l1 := int64(1 << (uint64(hiByte) & 077))
-			i2 := int((tm.curChar & 0xff) >> 6)
-			l2 := int64(1 << uint64(tm.curChar&077))
-			for {
-				i--
-				switch tm.jjstateSet[i] {
-				case 49, 33:
-					if jjCanMove_2(hiByte, i1, i2, l1, l2) {
-						if kind > 23 {
-							kind = 23
-						}
-						tm.jjCheckNAddTwoStates(33, 34)
-					}
-				case 0:
-					if jjCanMove_0(hiByte, i1, i2, l1, l2) {
-						if kind > 7 {
-							kind = 7
-						}
-					}
-					if jjCanMove_2(hiByte, i1, i2, l1, l2) {
-						if kind > 23 {
-							kind = 23
-						}
-						tm.jjCheckNAddTwoStates(33, 34)
-					}
-					if jjCanMove_2(hiByte, i1, i2, l1, l2) {
-						if kind > 20 {
-							kind = 20
-						}
-						tm.jjCheckNAddStates(6, 10)
-					}
-				case 15:
-					panic("not implemented yet")
-				case 17, 19:
-					panic("not implemented yet")
-				case 25:
-					panic("not implemented yet")
-				case 27:
-					panic("not implemented yet")
-				case 28:
-					panic("not implemented yet")
-				case 30:
-					panic("not implemented yet")
-				case 32:
-					panic("not implemented yet")
-				case 35:
-					panic("not implemented yet")
-				case 37:
-					panic("not implemented yet")
-				case 41:
-					panic("not implemented yet")
-				case 42:
-					if jjCanMove_2(hiByte, i1, i2, l1, l2) {
-						if kind > 20 {
-							kind = 20
-						}
-						tm.jjCheckNAddTwoStates(42, 43)
-					}
-				case 44:
-					panic("not implemented yet")
-				case 45:
-					if jjCanMove_2(hiByte, i1, i2, l1, l2) {
-						tm.jjCheckNAddStates(18, 20)
-					}
-				case 47:
-					panic("not implemented yet")
-				}
-				if i == startsAt {
-					break
-				}
-			}
+		tm.curChar = c
+
+		if tm.curLexState != Boost && isWhitespace(c) {
+			continue // SKIP
 		}
-		if kind != 0x7fffffff {
-			tm.jjmatchedKind = kind
-			tm.jjmatchedPos = curPos
-			kind = 0x7fffffff
+
+		var kind int
+		switch tm.curLexState {
+		case Boost:
+			kind, err = tm.matchBoost()
+		case Range:
+			kind, err = tm.matchRange()
+		default:
+			kind, err = tm.matchDefault()
 		}
-		curPos++
-		i = tm.jjnewStateCnt
-		tm.jjnewStateCnt = startsAt
-		startsAt = 49 - tm.jjnewStateCnt
-		if i == startsAt {
-			return curPos
+		if err != nil {
+			return nil, err
 		}
-		var err error
-		if tm.curChar, err = tm.input_stream.readChar(); err != nil {
-			return curPos
+		if kind < 0 {
+			return nil, tm.lexicalError()
 		}
+		return tm.fillToken(kind), nil
 	}
-	panic("should not be here")
 }
 
-func jjCanMove_0(hiByte, i1, i2 int, l1, l2 int64) bool {
-	switch hiByte {
-	case 48:
-		return (jjbitVec0[i2] & 12) != 0
+func (tm *TokenManager) fillToken(kind int) *Token {
+	t := newToken(kind, tm.input_stream.image())
+	t.beginLine, t.endLine = tm.input_stream.beginLine(), tm.input_stream.endLine()
+	t.beginColumn, t.endColumn = tm.input_stream.beginColumn(), tm.input_stream.endColumn()
+
+	switch kind {
+	case CARAT:
+		tm.curLexState = Boost
+	case RANGEIN_START, RANGEEX_START:
+		tm.curLexState = Range
+	case NUMBER, RANGEIN_END, RANGEEX_END:
+		tm.curLexState = DEFAULT
 	}
-	return false
+	return t
 }
 
-func jjCanMove_2(hiByte, i1, i2 int, l1, l2 int64) bool {
-	switch hiByte {
-	case 0:
-		panic("not implemented yet")
-	case 48:
-		panic("not implemented yet")
+func (tm *TokenManager) lexicalError() error {
+	if !tm.eof {
+		// the offending char is not part of the text seen so far
+		tm.input_stream.backup(1)
 	}
-	return (jjbitVec4[i1] & uint64(l1)) != 0
+	after := tm.input_stream.image()
+	return newTokenMgrError(tm.eof, tm.curLexState, tm.input_stream.endLine(),
+		tm.input_stream.endColumn()+1, after, tm.curChar, LEXICAL_ERROR)
 }
 
-func (tm *TokenManager) ReInit(stream CharStream) {
-	tm.jjmatchedPos = 0
-	tm.jjnewStateCnt = 0
-	tm.curLexState = tm.defaultLexState
-	tm.input_stream = stream
-	tm.reInitRounds()
+/* Matches a token in the DEFAULT state, starting with curChar. Returns -1 if none matches. */
+func (tm *TokenManager) matchDefault() (int, error) {
+	switch c := tm.curChar; c {
+	case '(':
+		return LPAREN, nil
+	case ')':
+		return RPAREN, nil
+	case ':':
+		return COLON, nil
+	case '^':
+		return CARAT, nil
+	case '[':
+		return RANGEIN_START, nil
+	case '{':
+		return RANGEEX_START, nil
+	case '+', '-', '!':
+		ok, err := tm.read()
+		if err != nil {
+			return 0, err
+		}
+		if ok && isWhitespace(tm.curChar) {
+			return BAREOPER, nil
+		}
+		tm.unread()
+		switch c {
+		case '+':
+			return PLUS, nil
+		case '-':
+			return MINUS, nil
+		}
+		return NOT, nil
+	case '"':
+		return tm.matchQuoted()
+	case '~':
+		return FUZZY_SLOP, tm.matchNumber(false)
+	case '/':
+		return tm.matchRegexp()
+	}
+	return tm.matchTermLike()
 }
 
-func (tm *TokenManager) reInitRounds() {
-	tm.jjround = 0x80000001
-	for i := 48; i >= 0; i-- {
-		tm.jjrounds[i] = 0x80000000
+/* Matches a TERM, PREFIXTERM, STAR, WILDTERM or one of the AND, OR, NOT keywords. */
+func (tm *TokenManager) matchTermLike() (int, error) {
+	var length, wildcards int
+	var firstIsWild, lastIsStar, hasQuestion bool
+	for {
+		c := tm.curChar
+		switch {
+		case c == '\\':
+			ok, err := tm.read()
+			if err != nil {
+				return 0, err
+			}
+			if !ok {
+				// an escape char can not end a term
+				if length == 0 {
+					return -1, nil
+				}
+				tm.input_stream.backup(1)
+				return classifyTermLike(tm.input_stream.image(), wildcards, firstIsWild, lastIsStar, hasQuestion), nil
+			}
+			lastIsStar = false
+		case c == '*' || c == '?':
+			if length == 0 {
+				firstIsWild = true
+			}
+			wildcards++
+			lastIsStar = c == '*'
+			hasQuestion = hasQuestion || c == '?'
+		case length == 0 && isTermStartChar(c) || length > 0 && isTermChar(c):
+			lastIsStar = false
+		default:
+			if length == 0 {
+				return -1, nil
+			}
+			tm.unread()
+			return classifyTermLike(tm.input_stream.image(), wildcards, firstIsWild, lastIsStar, hasQuestion), nil
+		}
+		length++
+
+		ok, err := tm.read()
+		if err != nil {
+			return 0, err
+		}
+		if !ok {
+			return classifyTermLike(tm.input_stream.image(), wildcards, firstIsWild, lastIsStar, hasQuestion), nil
+		}
 	}
 }
 
-// L1027
+func classifyTermLike(image string, wildcards int, firstIsWild, lastIsStar, hasQuestion bool) int {
+	switch {
+	case image == "*":
+		return STAR
+	case wildcards == 0:
+		switch image {
+		case "AND", "&&":
+			return AND
+		case "OR", "||":
+			return OR
+		case "NOT":
+			return NOT
+		}
+		return TERM
+	case wildcards == 1 && lastIsStar && !firstIsWild && !hasQuestion:
+		return PREFIXTERM
+	}
+	return WILDTERM
+}
 
-func (tm *TokenManager) jjFillToken() *Token {
-	var curTokenImage string
-	if im, ok := jjstrLiteralImages[tm.jjmatchedKind]; ok {
-		curTokenImage = im
-	} else {
-		curTokenImage = tm.input_stream.image()
+/* Matches the rest of a QUOTED token. */
+func (tm *TokenManager) matchQuoted() (int, error) {
+	for {
+		ok, err := tm.read()
+		if err != nil || !ok {
+			return -1, err
+		}
+		switch tm.curChar {
+		case '"':
+			return QUOTED, nil
+		case '\\':
+			if ok, err = tm.read(); err != nil || !ok {
+				return -1, err
+			}
+		}
 	}
-	beginLine := tm.input_stream.beginLine()
-	beginColumn := tm.input_stream.beginColumn()
-	endLine := tm.input_stream.endLine()
-	endColumn := tm.input_stream.endColumn()
-	t := newToken(tm.jjmatchedKind, curTokenImage)
-
-	t.beginLine = beginLine
-	t.endLine = endLine
-	t.beginColumn = beginColumn
-	t.endColumn = endColumn
-	return t
 }
 
-func (tm *TokenManager) nextToken() (matchedToken *Token) {
-	curPos := 0
-	var err error
-	var eof = false
-	for !eof {
-		if tm.curChar, err = tm.input_stream.beginToken(); err != nil {
-			tm.jjmatchedKind = 0
-			matchedToken = tm.jjFillToken()
-			return
+/* Matches the rest of a REGEXPTERM token, where "\/" does not end the term. */
+func (tm *TokenManager) matchRegexp() (int, error) {
+	for {
+		ok, err := tm.read()
+		if err != nil || !ok {
+			return -1, err
 		}
+		switch tm.curChar {
+		case '/':
+			return REGEXPTERM, nil
+		case '\\':
+			if ok, err = tm.read(); err != nil {
+				return 0, err
+			} else if ok && tm.curChar != '/' {
+				tm.unread()
+			}
+		}
+	}
+}
 
-		switch tm.curLexState {
-		case 0:
-			panic("not implemented yet")
-		case 1:
-			panic("not implemented yet")
-		case 2:
-			tm.jjmatchedKind = 0x7fffffff
-			tm.jjmatchedPos = 0
-			curPos = tm.jjMoveStringLiteralDfa0_2()
+/*
+Matches digits followed by an optional fraction, as in a NUMBER or
+after the "~" of a FUZZY_SLOP. If required is false, there may be no
+digits at all.
+*/
+func (tm *TokenManager) matchNumber(required bool) error {
+	digits, err := tm.matchDigits()
+	if err != nil {
+		return err
+	}
+	if digits == 0 {
+		if required {
+			return tm.lexicalError()
 		}
+		return nil
+	}
+	ok, err := tm.read()
+	if err != nil || !ok {
+		return err
+	}
+	if tm.curChar != '.' {
+		tm.unread()
+		return nil
+	}
+	if digits, err = tm.matchDigits(); err != nil {
+		return err
+	}
+	if digits == 0 {
+		// the dot is not part of the number
+		tm.input_stream.backup(1)
+	}
+	return nil
+}
 
-		if tm.jjmatchedKind != 0x7fffffff {
-			if tm.jjmatchedPos+1 < curPos {
-				tm.input_stream.backup(curPos - tm.jjmatchedPos - 1)
-			}
-			if (jjtoToken[tm.jjmatchedKind>>6] & (int64(1) << uint(tm.jjmatchedKind&077))) != 0 {
-				matchedToken = tm.jjFillToken()
-				if jjnewLexState[tm.jjmatchedKind] != -1 {
-					panic("not implemented yet")
-				}
-				return matchedToken
-			} else {
-				if n := jjnewLexState[tm.jjmatchedKind]; n != -1 {
-					panic("niy")
-					// tm.curLexState = n
-				}
-				continue
-			}
+func (tm *TokenManager) matchDigits() (int, error) {
+	var n int
+	for {
+		ok, err := tm.read()
+		if err != nil {
+			return 0, err
 		}
-		error_line := tm.input_stream.endLine()
-		error_column := tm.input_stream.endColumn()
-		var error_after string
-		var eofSeen = false
-		if _, err = tm.input_stream.readChar(); err == nil {
-			tm.input_stream.backup(1)
-			if curPos > 1 {
-				error_after = tm.input_stream.image()
-			}
-		} else {
-			eofSeen = true
-			if curPos > 1 {
-				error_after = tm.input_stream.image()
-			}
-			if tm.curChar == '\n' || tm.curChar == '\r' {
-				error_line++
-				error_column = 0
-			} else {
-				error_column++
-			}
+		if !ok {
+			return n, nil
 		}
-		panic(newTokenMgrError(eofSeen, tm.curLexState, error_line,
-			error_column, error_after, tm.curChar, LEXICAL_ERROR))
+		if !isNumChar(tm.curChar) {
+			tm.unread()
+			return n, nil
+		}
+		n++
 	}
-	panic("should not be here")
 }
 
-// L1137
-func (tm *TokenManager) jjCheckNAdd(state int) {
-	if tm.jjrounds[state] != tm.jjround {
-		tm.jjstateSet[tm.jjnewStateCnt] = state
-		tm.jjnewStateCnt++
-		tm.jjrounds[state] = tm.jjround
+/* Matches a NUMBER in the Boost state. */
+func (tm *TokenManager) matchBoost() (int, error) {
+	if !isNumChar(tm.curChar) {
+		return -1, nil
 	}
+	tm.input_stream.backup(1)
+	return NUMBER, tm.matchNumber(true)
 }
 
-// L1151
+/* Matches a token in the Range state, starting with curChar. */
+func (tm *TokenManager) matchRange() (int, error) {
+	switch tm.curChar {
+	case ']':
+		return RANGEIN_END, nil
+	case '}':
+		return RANGEEX_END, nil
+	}
 
-func (tm *TokenManager) jjCheckNAddTwoStates(state1, state2 int) {
-	tm.jjCheckNAdd(state1)
-	tm.jjCheckNAdd(state2)
+	quotedLength := -1
+	if tm.curChar == '"' {
+		var err error
+		if quotedLength, err = tm.rangeQuotedLength(); err != nil {
+			return 0, err
+		}
+	}
+	goopLength, err := tm.rangeGoopLength()
+	if err != nil {
+		return 0, err
+	}
+	if quotedLength >= goopLength {
+		tm.input_stream.backup(goopLength - 1)
+		return RANGE_QUOTED, tm.skip(quotedLength - 1)
+	}
+	if tm.input_stream.image() == "TO" {
+		return RANGE_TO, nil
+	}
+	return RANGE_GOOP, nil
 }
 
-func (tm *TokenManager) jjCheckNAddStates(start, end int) {
-	assert(start < end)
-	assert(start >= 0)
-	assert(end <= len(jjnextStates))
+/* Returns the length of the RANGE_QUOTED token after the current quote, or -1, leaving the stream there. */
+func (tm *TokenManager) rangeQuotedLength() (int, error) {
+	length, n := -1, 1
 	for {
-		tm.jjCheckNAdd(jjnextStates[start])
-		start++
-		if start >= end {
+		ok, err := tm.read()
+		if err != nil {
+			return 0, err
+		}
+		if !ok {
+			break
+		}
+		n++
+		if tm.curChar == '"' {
+			if n > 2 {
+				length = n
+			}
 			break
 		}
+		if tm.curChar == '\\' {
+			if ok, err = tm.read(); err != nil {
+				return 0, err
+			} else if ok && tm.curChar != '"' {
+				tm.unread()
+			} else if ok {
+				n++
+			}
+		}
+	}
+	tm.input_stream.backup(n - 1)
+	tm.eof = false
+	return length, nil
+}
+
+/* Consumes the RANGE_GOOP token starting with the current char and returns its length. */
+func (tm *TokenManager) rangeGoopLength() (int, error) {
+	n := 1
+	for {
+		ok, err := tm.read()
+		if err != nil {
+			return 0, err
+		}
+		if !ok {
+			return n, nil
+		}
+		if c := tm.curChar; c == ' ' || c == ']' || c == '}' {
+			tm.unread()
+			return n, nil
+		}
+		n++
+	}
+}
+
+func (tm *TokenManager) skip(n int) error {
+	for ; n > 0; n-- {
+		if _, err := tm.read(); err != nil {
+			return err
+		}
 	}
+	return nil
 }
 
 func assert(ok bool) {
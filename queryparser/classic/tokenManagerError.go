@@ -1,5 +1,12 @@
 package classic
 
+import (
+	"fmt"
+	"strconv"
+)
+
+// classic/TokenMgrError.java
+
 const (
 	LEXICAL_ERROR = iota
 	STATIC_LEXER_ERROR
@@ -7,14 +14,33 @@ const (
 	LOOP_DETECTED
 )
 
+// Token Manager Error.
 type TokenManagerError struct {
+	errorCode int
+	message   string
 }
 
 func newTokenMgrError(eofSeen bool, lexState, errorLine, errorColumn int,
 	errorAfter string, curChar rune, reason int) *TokenManagerError {
-	panic("not implemented yet")
+	return &TokenManagerError{
+		errorCode: reason,
+		message:   lexicalError(eofSeen, lexState, errorLine, errorColumn, errorAfter, curChar),
+	}
+}
+
+/*
+Returns a detailed message for the error when it is thrown by the
+token manager to indicate a lexical error.
+*/
+func lexicalError(eofSeen bool, lexState, errorLine, errorColumn int, errorAfter string, curChar rune) string {
+	encountered := "<EOF> "
+	if !eofSeen {
+		encountered = fmt.Sprintf("%v (%v), ", strconv.Quote(string(curChar)), int(curChar))
+	}
+	return fmt.Sprintf("Lexical error at line %v, column %v.  Encountered: %vafter : %v",
+		errorLine, errorColumn, encountered, strconv.Quote(errorAfter))
 }
 
 func (err *TokenManagerError) Error() string {
-	panic("not implemented yet")
+	return err.message
 }
@@ -0,0 +1,377 @@
+package flexible
+
+import (
+	"fmt"
+	"github.com/balzaczyy/golucene/core/index"
+	"github.com/balzaczyy/golucene/core/search"
+	"reflect"
+	"unicode/utf8"
+)
+
+// flexible/core/builders/QueryBuilder.java
+
+/*
+This interface is used by implementors types that builds some kind of
+object from a query tree.
+*/
+type QueryBuilder interface {
+	// Builds some kind of object from a query tree. The queries of the
+	// children are found in their QUERY_TREE_BUILDER_TAGID tag.
+	Build(queryNode QueryNode) (search.Query, error)
+}
+
+/* A QueryBuilder function, like http.HandlerFunc. */
+type QueryBuilderFunc func(queryNode QueryNode) (search.Query, error)
+
+func (f QueryBuilderFunc) Build(queryNode QueryNode) (search.Query, error) {
+	return f(queryNode)
+}
+
+// flexible/core/builders/QueryTreeBuilder.java
+
+/*
+This tag is used to tag the nodes in a query tree with the built
+objects produced from their own associated builder.
+*/
+const QUERY_TREE_BUILDER_TAGID = "QueryTreeBuilder"
+
+/*
+This type should be used when there is a builder for each type of
+node contained in a query tree.
+
+It walks the query tree post-order, so the children are built first,
+and tags every node with the object its builder produced, under
+QUERY_TREE_BUILDER_TAGID. A builder for the field of a FieldableNode
+takes precedence over the builder of its node type, which makes it
+possible to build a different query for some field, like a points
+range query.
+*/
+type QueryTreeBuilder struct {
+	fieldNameBuilders map[string]QueryBuilder
+	queryNodeBuilders map[reflect.Type]QueryBuilder
+}
+
+func NewQueryTreeBuilder() *QueryTreeBuilder {
+	return &QueryTreeBuilder{
+		fieldNameBuilders: make(map[string]QueryBuilder),
+		queryNodeBuilders: make(map[reflect.Type]QueryBuilder),
+	}
+}
+
+/* Associates a field name with a builder. */
+func (b *QueryTreeBuilder) SetFieldBuilder(field string, builder QueryBuilder) {
+	b.fieldNameBuilders[field] = builder
+}
+
+/*
+Associates a node type with a builder, the type being the one of the
+given node, e.g. (*FieldQueryNode)(nil).
+*/
+func (b *QueryTreeBuilder) SetBuilder(node QueryNode, builder QueryBuilder) {
+	b.queryNodeBuilders[reflect.TypeOf(node)] = builder
+}
+
+func (b *QueryTreeBuilder) process(node QueryNode) error {
+	if node == nil {
+		return nil
+	}
+	for _, child := range node.Children() {
+		if err := b.process(child); err != nil {
+			return err
+		}
+	}
+	builder := b.builder(node)
+	if builder == nil {
+		return fmt.Errorf("No QueryBuilder found for node type %T: %v", node, node)
+	}
+	q, err := builder.Build(node)
+	if err != nil {
+		return err
+	}
+	node.SetTag(QUERY_TREE_BUILDER_TAGID, q)
+	return nil
+}
+
+func (b *QueryTreeBuilder) builder(node QueryNode) QueryBuilder {
+	if fieldNode, ok := node.(FieldableNode); ok {
+		if builder, ok := b.fieldNameBuilders[fieldNode.Field()]; ok {
+			return builder
+		}
+	}
+	return b.queryNodeBuilders[reflect.TypeOf(node)]
+}
+
+/*
+Builds some kind of object from a query tree. Each node in the query
+tree is built using a specific builder associated to it.
+*/
+func (b *QueryTreeBuilder) Build(queryNode QueryNode) (search.Query, error) {
+	if err := b.process(queryNode); err != nil {
+		return nil, err
+	}
+	return builtQuery(queryNode), nil
+}
+
+/* Returns the query built for the node, or nil. */
+func builtQuery(node QueryNode) search.Query {
+	if q, ok := node.Tag(QUERY_TREE_BUILDER_TAGID).(search.Query); ok {
+		return q
+	}
+	return nil
+}
+
+// flexible/standard/builders/StandardQueryTreeBuilder.java
+
+/*
+This query tree builder only defines the necessary map to build a
+search.Query object. It should be used to generate a Query object
+from a query node tree processed by a
+StandardQueryNodeProcessorPipeline.
+*/
+func NewStandardQueryTreeBuilder() *QueryTreeBuilder {
+	ans := NewQueryTreeBuilder()
+	ans.SetBuilder((*GroupQueryNode)(nil), QueryBuilderFunc(buildChild))
+	ans.SetBuilder((*FieldQueryNode)(nil), QueryBuilderFunc(buildFieldQuery))
+	ans.SetBuilder((*QuotedFieldQueryNode)(nil), QueryBuilderFunc(buildFieldQuery))
+	ans.SetBuilder((*BooleanQueryNode)(nil), QueryBuilderFunc(buildBooleanQuery))
+	ans.SetBuilder((*AndQueryNode)(nil), QueryBuilderFunc(buildBooleanQuery))
+	ans.SetBuilder((*OrQueryNode)(nil), QueryBuilderFunc(buildBooleanQuery))
+	ans.SetBuilder((*FuzzyQueryNode)(nil), QueryBuilderFunc(buildFuzzyQuery))
+	ans.SetBuilder((*BoostQueryNode)(nil), QueryBuilderFunc(buildBoostQuery))
+	ans.SetBuilder((*ModifierQueryNode)(nil), QueryBuilderFunc(buildChild))
+	ans.SetBuilder((*BooleanModifierNode)(nil), QueryBuilderFunc(buildChild))
+	ans.SetBuilder((*WildcardQueryNode)(nil), QueryBuilderFunc(buildWildcardQuery))
+	ans.SetBuilder((*TokenizedPhraseQueryNode)(nil), QueryBuilderFunc(buildPhraseQuery))
+	ans.SetBuilder((*MatchNoDocsQueryNode)(nil), QueryBuilderFunc(buildMatchNoDocsQuery))
+	ans.SetBuilder((*PrefixWildcardQueryNode)(nil), QueryBuilderFunc(buildPrefixQuery))
+	ans.SetBuilder((*TermRangeQueryNode)(nil), QueryBuilderFunc(buildTermRangeQuery))
+	ans.SetBuilder((*RegexpQueryNode)(nil), QueryBuilderFunc(buildRegexpQuery))
+	ans.SetBuilder((*SlopQueryNode)(nil), QueryBuilderFunc(buildSlopQuery))
+	ans.SetBuilder((*SynonymQueryNode)(nil), QueryBuilderFunc(buildSynonymQuery))
+	ans.SetBuilder((*MultiPhraseQueryNode)(nil), QueryBuilderFunc(buildMultiPhraseQuery))
+	ans.SetBuilder((*MatchAllDocsQueryNode)(nil), QueryBuilderFunc(buildMatchAllDocsQuery))
+	return ans
+}
+
+// flexible/standard/builders/GroupQueryNodeBuilder.java
+// flexible/standard/builders/ModifierQueryNodeBuilder.java
+
+/* Builds the query of the only child of the node. */
+func buildChild(queryNode QueryNode) (search.Query, error) {
+	return builtQuery(queryNode.Children()[0]), nil
+}
+
+// flexible/standard/builders/FieldQueryNodeBuilder.java
+
+func buildFieldQuery(queryNode QueryNode) (search.Query, error) {
+	fieldNode := queryNode.(TextableQueryNode)
+	return search.NewTermQuery(index.NewTerm(queryNode.(FieldableNode).Field(), fieldNode.Text())), nil
+}
+
+// flexible/standard/builders/BooleanQueryNodeBuilder.java
+
+/*
+Builds a BooleanQuery of the queries of the children, whose occur
+comes from their modifier.
+*/
+func buildBooleanQuery(queryNode QueryNode) (search.Query, error) {
+	children := queryNode.Children()
+	if len(children) > maxClauseCount {
+		return nil, fmt.Errorf("Too many boolean clauses, the maximum supported is %v: %v",
+			maxClauseCount, queryNode)
+	}
+	bq := search.NewBooleanQuery()
+	for _, child := range children {
+		if q := builtQuery(child); q != nil {
+			bq.Add(q, occurOf(child))
+		}
+	}
+	return bq, nil
+}
+
+func occurOf(node QueryNode) search.Occur {
+	mod, _ := modifierOf(node)
+	switch mod {
+	case MOD_NOT:
+		return search.MUST_NOT
+	case MOD_REQ:
+		return search.MUST
+	}
+	return search.SHOULD
+}
+
+// flexible/standard/builders/FuzzyQueryNodeBuilder.java
+
+func buildFuzzyQuery(queryNode QueryNode) (search.Query, error) {
+	fuzzyNode := queryNode.(*FuzzyQueryNode)
+	text := fuzzyNode.Text()
+	numEdits := search.FloatToEdits(fuzzyNode.Similarity(), utf8.RuneCountInString(text))
+	return search.NewFuzzyQueryWith(index.NewTerm(fuzzyNode.Field(), text), numEdits,
+		fuzzyNode.PrefixLength(), search.FUZZY_DEFAULT_MAX_EXPANSIONS, search.FUZZY_DEFAULT_TRANSPOSITIONS), nil
+}
+
+// flexible/standard/builders/BoostQueryNodeBuilder.java
+
+func buildBoostQuery(queryNode QueryNode) (search.Query, error) {
+	q := builtQuery(queryNode.Children()[0])
+	if q != nil {
+		q.SetBoost(queryNode.(*BoostQueryNode).Value())
+	}
+	return q, nil
+}
+
+// flexible/standard/builders/WildcardQueryNodeBuilder.java
+
+func buildWildcardQuery(queryNode QueryNode) (search.Query, error) {
+	wildcardNode := queryNode.(*WildcardQueryNode)
+	return search.NewWildcardQuery(index.NewTerm(wildcardNode.Field(), wildcardNode.Text())), nil
+}
+
+// flexible/standard/builders/PrefixWildcardQueryNodeBuilder.java
+
+func buildPrefixQuery(queryNode QueryNode) (search.Query, error) {
+	wildcardNode := queryNode.(*PrefixWildcardQueryNode)
+	text := wildcardNode.Text()
+	prefix, err := discardEscapeChar(text[:len(text)-1])
+	if err != nil {
+		return nil, err
+	}
+	return search.NewPrefixQuery(index.NewTerm(wildcardNode.Field(), prefix)), nil
+}
+
+// flexible/standard/builders/RegexpQueryNodeBuilder.java
+
+func buildRegexpQuery(queryNode QueryNode) (q search.Query, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			q, err = nil, fmt.Errorf("%v", r)
+		}
+	}()
+	regexpNode := queryNode.(*RegexpQueryNode)
+	return search.NewRegexpQuery(index.NewTerm(regexpNode.Field(), regexpNode.Text())), nil
+}
+
+// flexible/standard/builders/TermRangeQueryNodeBuilder.java
+
+func buildTermRangeQuery(queryNode QueryNode) (search.Query, error) {
+	rangeNode := queryNode.(*TermRangeQueryNode)
+	bound := func(s string) *string {
+		if s == "" {
+			return nil // open
+		}
+		return &s
+	}
+	return search.NewTermRangeQueryFromStrings(rangeNode.Field(),
+		bound(rangeNode.LowerBound()), bound(rangeNode.UpperBound()),
+		rangeNode.IsLowerInclusive(), rangeNode.IsUpperInclusive()), nil
+}
+
+// flexible/standard/builders/PhraseQueryNodeBuilder.java
+
+func buildPhraseQuery(queryNode QueryNode) (search.Query, error) {
+	pq := search.NewPhraseQuery()
+	for _, child := range queryNode.Children() {
+		termNode := child.(*FieldQueryNode)
+		pq.AddAt(index.NewTerm(termNode.Field(), termNode.Text()), termNode.PositionIncrement())
+	}
+	return pq, nil
+}
+
+// flexible/standard/builders/SlopQueryNodeBuilder.java
+
+/*
+Sets the slop of the phrase built for the child, or of every phrase
+enumerated for a multi-phrase.
+*/
+func buildSlopQuery(queryNode QueryNode) (search.Query, error) {
+	slop := queryNode.(*SlopQueryNode).Value()
+	switch q := builtQuery(queryNode.Children()[0]).(type) {
+	case *search.PhraseQuery:
+		q.SetSlop(slop)
+		return q, nil
+	case *search.BooleanQuery:
+		for _, clause := range q.Clauses() {
+			if pq, ok := clause.Query().(*search.PhraseQuery); ok {
+				pq.SetSlop(slop)
+			}
+		}
+		return q, nil
+	default:
+		return q, nil
+	}
+}
+
+// flexible/standard/builders/SynonymQueryNodeBuilder.java
+
+func buildSynonymQuery(queryNode QueryNode) (search.Query, error) {
+	children := queryNode.Children()
+	terms := make([]*index.Term, len(children))
+	for i, child := range children {
+		termNode := child.(*FieldQueryNode)
+		terms[i] = index.NewTerm(termNode.Field(), termNode.Text())
+	}
+	return search.NewSynonymQuery(terms...), nil
+}
+
+// The maximum number of phrases a multi-phrase may be expanded to.
+const maxClauseCount = 1024
+
+// flexible/standard/builders/MultiPhraseQueryNodeBuilder.java
+
+/*
+Builds the query matching any of the phrases made of one of the
+alternative terms at each position. As there is no MultiPhraseQuery,
+the phrases are enumerated in a BooleanQuery, whose number of clauses
+is limited.
+*/
+func buildMultiPhraseQuery(queryNode QueryNode) (search.Query, error) {
+	var alternatives [][]*index.Term
+	var positions []int
+	for _, child := range queryNode.Children() {
+		termNode := child.(*FieldQueryNode)
+		term := index.NewTerm(termNode.Field(), termNode.Text())
+		if last := len(positions) - 1; last >= 0 && positions[last] == termNode.PositionIncrement() {
+			alternatives[last] = append(alternatives[last], term)
+		} else {
+			alternatives = append(alternatives, []*index.Term{term})
+			positions = append(positions, termNode.PositionIncrement())
+		}
+	}
+
+	count := 1
+	for _, terms := range alternatives {
+		if count *= len(terms); count > maxClauseCount {
+			return nil, fmt.Errorf("Too many boolean clauses, the maximum supported is %v: %v",
+				maxClauseCount, queryNode)
+		}
+	}
+	q := search.NewBooleanQueryDisableCoord(true)
+	choice := make([]int, len(alternatives))
+	for n := 0; n < count; n++ {
+		pq := search.NewPhraseQuery()
+		for i, terms := range alternatives {
+			pq.AddAt(terms[choice[i]], positions[i])
+		}
+		q.Add(pq, search.SHOULD)
+		// next combination, the last position varying fastest
+		for i := len(choice) - 1; i >= 0; i-- {
+			if choice[i]++; choice[i] < len(alternatives[i]) {
+				break
+			}
+			choice[i] = 0
+		}
+	}
+	return q, nil
+}
+
+// flexible/standard/builders/MatchAllDocsQueryNodeBuilder.java
+
+func buildMatchAllDocsQuery(queryNode QueryNode) (search.Query, error) {
+	return search.NewMatchAllDocsQuery(), nil
+}
+
+// flexible/standard/builders/MatchNoDocsQueryNodeBuilder.java
+
+func buildMatchNoDocsQuery(queryNode QueryNode) (search.Query, error) {
+	return search.NewBooleanQuery(), nil
+}
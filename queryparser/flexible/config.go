@@ -0,0 +1,190 @@
+package flexible
+
+// flexible/core/config/ConfigurationKey.java
+
+/*
+An instance of this type represents a key that is used to retrieve a
+value from AbstractQueryConfig. Keys are compared by identity, so
+every key should be created once, like the ones of this package.
+*/
+type ConfigurationKey struct {
+	name string
+}
+
+func NewConfigurationKey(name string) *ConfigurationKey {
+	return &ConfigurationKey{name}
+}
+
+func (k *ConfigurationKey) String() string {
+	return k.name
+}
+
+// flexible/standard/config/StandardQueryConfigHandler.java
+
+var (
+	// Whether position increments are honored in phrase queries.
+	ENABLE_POSITION_INCREMENTS = NewConfigurationKey("enablePositionIncrements")
+	// Whether wildcard, prefix, fuzzy, regexp and range terms are lower-cased.
+	LOWERCASE_EXPANDED_TERMS = NewConfigurationKey("lowercaseExpandedTerms")
+	// Whether * and ? are allowed as the first character of a wildcard.
+	ALLOW_LEADING_WILDCARD = NewConfigurationKey("allowLeadingWildcard")
+	// The analysis.Analyzer used for terms and phrases.
+	ANALYZER = NewConfigurationKey("analyzer")
+	// The Operator used between clauses without an explicit one.
+	DEFAULT_OPERATOR = NewConfigurationKey("defaultOperator")
+	// The default slop of phrase queries.
+	PHRASE_SLOP = NewConfigurationKey("phraseSlop")
+	// The []string an unqualified term is expanded to.
+	MULTI_FIELDS = NewConfigurationKey("multiFields")
+	// The map[string]float32 of the boost of each field.
+	FIELD_BOOST_MAP = NewConfigurationKey("fieldBoostMap")
+	// The *FuzzyConfig of fuzzy queries.
+	FUZZY_CONFIG = NewConfigurationKey("fuzzyConfig")
+	// The float32 boost of a field, in a FieldConfig.
+	BOOST = NewConfigurationKey("boost")
+)
+
+// flexible/core/config/AbstractQueryConfig.java
+
+/* This type is the base of QueryConfigHandler and FieldConfig. */
+type AbstractQueryConfig struct {
+	configMap map[*ConfigurationKey]interface{}
+}
+
+func newAbstractQueryConfig() *AbstractQueryConfig {
+	return &AbstractQueryConfig{make(map[*ConfigurationKey]interface{})}
+}
+
+/* Returns the value held by the given key, or nil. */
+func (c *AbstractQueryConfig) Get(key *ConfigurationKey) interface{} {
+	return c.configMap[key]
+}
+
+/* Returns true if there is a value set with the given key. */
+func (c *AbstractQueryConfig) Has(key *ConfigurationKey) bool {
+	_, ok := c.configMap[key]
+	return ok
+}
+
+/* Sets a key and its value. A nil value unsets the key. */
+func (c *AbstractQueryConfig) Set(key *ConfigurationKey, value interface{}) {
+	if value == nil {
+		c.Unset(key)
+	} else {
+		c.configMap[key] = value
+	}
+}
+
+/* Unsets the given key and its value. */
+func (c *AbstractQueryConfig) Unset(key *ConfigurationKey) {
+	delete(c.configMap, key)
+}
+
+/* Returns the bool held by the key, or the default if unset. */
+func (c *AbstractQueryConfig) boolOr(key *ConfigurationKey, def bool) bool {
+	if v, ok := c.configMap[key].(bool); ok {
+		return v
+	}
+	return def
+}
+
+// flexible/core/config/FieldConfig.java
+
+/* This type represents a field configuration. */
+type FieldConfig struct {
+	*AbstractQueryConfig
+	field string
+}
+
+func NewFieldConfig(field string) *FieldConfig {
+	return &FieldConfig{newAbstractQueryConfig(), field}
+}
+
+func (c *FieldConfig) Field() string {
+	return c.field
+}
+
+// flexible/core/config/FieldConfigListener.java
+
+/*
+This interface should be implemented by types that want to be
+notified when a FieldConfig is requested from the QueryConfigHandler.
+*/
+type FieldConfigListener interface {
+	// This method is called every time a field configuration is requested.
+	BuildFieldConfig(fieldConfig *FieldConfig)
+}
+
+// flexible/core/config/QueryConfigHandler.java
+
+/*
+This type can be used to hold any query configuration and no field
+configuration. For field configuration, it creates an empty
+FieldConfig object and delegate it to field config listeners, these
+are responsible for setting up all the field configuration.
+*/
+type QueryConfigHandler struct {
+	*AbstractQueryConfig
+	listeners []FieldConfigListener
+}
+
+func NewQueryConfigHandler() *QueryConfigHandler {
+	return &QueryConfigHandler{AbstractQueryConfig: newAbstractQueryConfig()}
+}
+
+/*
+Returns an implementation of FieldConfig for a specific field name.
+The listeners are called to fill it, every time this method is called.
+*/
+func (h *QueryConfigHandler) FieldConfig(field string) *FieldConfig {
+	fieldConfig := NewFieldConfig(field)
+	for _, listener := range h.listeners {
+		listener.BuildFieldConfig(fieldConfig)
+	}
+	return fieldConfig
+}
+
+/* Adds a listener, called when a FieldConfig is requested. */
+func (h *QueryConfigHandler) AddFieldConfigListener(listener FieldConfigListener) {
+	h.listeners = append(h.listeners, listener)
+}
+
+// flexible/standard/config/FieldBoostMapFCListener.java
+
+/*
+This listener listens for every field configuration request and
+assign a BOOST to the equivalent FieldConfig based on a defined map:
+fieldName -> boostValue stored in FIELD_BOOST_MAP.
+*/
+type FieldBoostMapFCListener struct {
+	config *QueryConfigHandler
+}
+
+func NewFieldBoostMapFCListener(config *QueryConfigHandler) *FieldBoostMapFCListener {
+	return &FieldBoostMapFCListener{config}
+}
+
+func (l *FieldBoostMapFCListener) BuildFieldConfig(fieldConfig *FieldConfig) {
+	if boosts, ok := l.config.Get(FIELD_BOOST_MAP).(map[string]float32); ok {
+		if boost, ok := boosts[fieldConfig.Field()]; ok {
+			fieldConfig.Set(BOOST, boost)
+		}
+	}
+}
+
+// flexible/standard/config/FuzzyConfig.java
+
+/* Configuration parameters for FuzzyQuerys. */
+type FuzzyConfig struct {
+	PrefixLength  int
+	MinSimilarity float32
+}
+
+// flexible/standard/config/StandardQueryConfigHandler.java#Operator
+
+type Operator int
+
+const (
+	OP_OR = Operator(iota)
+	OP_AND
+)
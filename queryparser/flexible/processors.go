@@ -0,0 +1,161 @@
+package flexible
+
+// flexible/core/processors/QueryNodeProcessor.java
+
+/*
+A QueryNodeProcessor is an interface for types that process a
+QueryNode tree. The processor may change the tree, replacing, adding
+or removing nodes, and it should return the root of the processed
+tree. It can access the query configuration, set before the tree is
+processed.
+*/
+type QueryNodeProcessor interface {
+	// Processes a query node tree and returns the processed tree.
+	Process(queryTree QueryNode) (QueryNode, error)
+	// Sets the configuration used by the processor.
+	SetQueryConfigHandler(config *QueryConfigHandler)
+	// Returns the configuration used by the processor.
+	QueryConfigHandler() *QueryConfigHandler
+}
+
+/*
+The methods a processor embedding QueryNodeProcessorImpl provides to
+transform each node of the tree.
+*/
+type QueryNodeProcessorSPI interface {
+	// Called before the children of the node are processed. The
+	// returned node replaces it and its children are processed next.
+	PreProcessNode(node QueryNode) (QueryNode, error)
+	// Called after the children of the node are processed. The
+	// returned node replaces it in the tree.
+	PostProcessNode(node QueryNode) (QueryNode, error)
+	// Called before the children are processed, it can reorder them or
+	// drop some of them.
+	SetChildrenOrder(children []QueryNode) ([]QueryNode, error)
+}
+
+// flexible/core/processors/QueryNodeProcessorImpl.java
+
+/*
+This is a default implementation for the QueryNodeProcessor
+interface, it's an abstract type, so it should be embedded by
+processors that implement QueryNodeProcessorSPI.
+
+It walks the tree depth first: every node is pre-processed, then its
+children are ordered and processed, and at last the node is
+post-processed. A processor replaces nodes by returning another node
+from the SPI methods, e.g. mapping a field to a custom node:
+
+	func (p *MyProcessor) PostProcessNode(node QueryNode) (QueryNode, error) {
+		if n, ok := node.(*TermRangeQueryNode); ok && n.Field() == "price" {
+			return newPriceRangeNode(n), nil
+		}
+		return node, nil
+	}
+*/
+type QueryNodeProcessorImpl struct {
+	spi    QueryNodeProcessorSPI
+	config *QueryConfigHandler
+}
+
+func NewQueryNodeProcessorImpl(spi QueryNodeProcessorSPI) *QueryNodeProcessorImpl {
+	return &QueryNodeProcessorImpl{spi: spi}
+}
+
+func (p *QueryNodeProcessorImpl) Process(queryTree QueryNode) (QueryNode, error) {
+	return p.processIteration(queryTree)
+}
+
+func (p *QueryNodeProcessorImpl) processIteration(queryTree QueryNode) (QueryNode, error) {
+	queryTree, err := p.spi.PreProcessNode(queryTree)
+	if err != nil {
+		return nil, err
+	}
+	if err = p.processChildren(queryTree); err != nil {
+		return nil, err
+	}
+	return p.spi.PostProcessNode(queryTree)
+}
+
+/* Processes the children of the node and replaces them by the results. */
+func (p *QueryNodeProcessorImpl) processChildren(queryTree QueryNode) error {
+	children := queryTree.Children()
+	if len(children) == 0 {
+		return nil
+	}
+	children, err := p.spi.SetChildrenOrder(append([]QueryNode(nil), children...))
+	if err != nil {
+		return err
+	}
+	newChildren := make([]QueryNode, 0, len(children))
+	for _, child := range children {
+		if child, err = p.processIteration(child); err != nil {
+			return err
+		}
+		if child == nil {
+			panic("processor returned a nil node")
+		}
+		newChildren = append(newChildren, child)
+	}
+	queryTree.Set(newChildren)
+	return nil
+}
+
+func (p *QueryNodeProcessorImpl) SetQueryConfigHandler(config *QueryConfigHandler) {
+	p.config = config
+}
+
+func (p *QueryNodeProcessorImpl) QueryConfigHandler() *QueryConfigHandler {
+	return p.config
+}
+
+// flexible/core/processors/QueryNodeProcessorPipeline.java
+
+/*
+A QueryNodeProcessorPipeline type should be used to build a query
+node processor pipeline. When a query node tree is processed using
+this type, it passes the query node tree to each processor on the
+pipeline and the result from each processor is passed to the next one.
+The processors are applied in the order they were added.
+*/
+type QueryNodeProcessorPipeline struct {
+	processors []QueryNodeProcessor
+	config     *QueryConfigHandler
+}
+
+func NewQueryNodeProcessorPipeline(config *QueryConfigHandler) *QueryNodeProcessorPipeline {
+	return &QueryNodeProcessorPipeline{config: config}
+}
+
+/* Adds a processor at the end of the pipeline, sharing its configuration. */
+func (p *QueryNodeProcessorPipeline) Add(processor QueryNodeProcessor) {
+	processor.SetQueryConfigHandler(p.config)
+	p.processors = append(p.processors, processor)
+}
+
+/* Returns the processors of the pipeline, in order. */
+func (p *QueryNodeProcessorPipeline) Processors() []QueryNodeProcessor {
+	return p.processors
+}
+
+func (p *QueryNodeProcessorPipeline) Process(queryTree QueryNode) (QueryNode, error) {
+	var err error
+	for _, processor := range p.processors {
+		if queryTree, err = processor.Process(queryTree); err != nil {
+			return nil, err
+		}
+	}
+	return queryTree, nil
+}
+
+/* Sets the configuration of the pipeline and of all its processors. */
+func (p *QueryNodeProcessorPipeline) SetQueryConfigHandler(config *QueryConfigHandler) {
+	p.config = config
+	for _, processor := range p.processors {
+		processor.SetQueryConfigHandler(config)
+	}
+}
+
+func (p *QueryNodeProcessorPipeline) QueryConfigHandler() *QueryConfigHandler {
+	return p.config
+}
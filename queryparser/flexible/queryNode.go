@@ -0,0 +1,669 @@
+package flexible
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// flexible/core/nodes/QueryNode.java
+
+/*
+A QueryNode is an interface implemented by all nodes on a QueryNode
+tree. The syntax parser builds a tree of query nodes, the processors
+of the pipeline transform it, and the builders turn it into a Query.
+*/
+type QueryNode interface {
+	// Returns a list of the children of this node, nil for a leaf.
+	Children() []QueryNode
+	// Verify if a node is a Leaf node.
+	IsLeaf() bool
+	// Returns the parent node, or nil for the root.
+	Parent() QueryNode
+	// Adds a child to this node.
+	Add(child QueryNode)
+	// Replaces the children of this node.
+	Set(children []QueryNode)
+	// Returns the tag associated with the name, or nil.
+	Tag(name string) interface{}
+	// Associates a tag with the node, to share data between processors
+	// and builders.
+	SetTag(name string, value interface{})
+	// Removes the tag associated with the name.
+	UnsetTag(name string)
+	String() string
+
+	setParent(parent QueryNode)
+}
+
+// flexible/core/nodes/QueryNodeImpl.java
+
+/* A base implementation of QueryNode, to be embedded by the nodes. */
+type QueryNodeImpl struct {
+	self     QueryNode
+	leaf     bool
+	parent   QueryNode
+	children []QueryNode
+	tags     map[string]interface{}
+}
+
+/*
+Creates the base of a node, where self is the node embedding it. A
+leaf node can't have children.
+*/
+func NewQueryNodeImpl(self QueryNode, leaf bool) *QueryNodeImpl {
+	return &QueryNodeImpl{self: self, leaf: leaf}
+}
+
+func (n *QueryNodeImpl) Children() []QueryNode {
+	if n.leaf {
+		return nil
+	}
+	return n.children
+}
+
+func (n *QueryNodeImpl) IsLeaf() bool {
+	return n.leaf
+}
+
+func (n *QueryNodeImpl) Parent() QueryNode {
+	return n.parent
+}
+
+func (n *QueryNodeImpl) setParent(parent QueryNode) {
+	n.parent = parent
+}
+
+func (n *QueryNodeImpl) Add(child QueryNode) {
+	if n.leaf {
+		panic("Nodes of type leaf can't have children")
+	}
+	child.setParent(n.self)
+	n.children = append(n.children, child)
+}
+
+func (n *QueryNodeImpl) Set(children []QueryNode) {
+	if n.leaf {
+		panic("Nodes of type leaf can't have children")
+	}
+	n.children = nil
+	for _, child := range children {
+		n.Add(child)
+	}
+}
+
+func (n *QueryNodeImpl) Tag(name string) interface{} {
+	return n.tags[strings.ToLower(name)]
+}
+
+func (n *QueryNodeImpl) SetTag(name string, value interface{}) {
+	if n.tags == nil {
+		n.tags = make(map[string]interface{})
+	}
+	n.tags[strings.ToLower(name)] = value
+}
+
+func (n *QueryNodeImpl) UnsetTag(name string) {
+	delete(n.tags, strings.ToLower(name))
+}
+
+/* Returns the first child, or nil. */
+func (n *QueryNodeImpl) Child() QueryNode {
+	if len(n.children) == 0 {
+		return nil
+	}
+	return n.children[0]
+}
+
+/* Prints the node and its children, like <boolean><field .../></boolean>. */
+func (n *QueryNodeImpl) String() string {
+	name := strings.TrimSuffix(reflect.TypeOf(n.self).Elem().Name(), "QueryNode")
+	name = strings.ToLower(name[:1]) + name[1:]
+	if len(n.children) == 0 {
+		return fmt.Sprintf("<%v/>", name)
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "<%v>", name)
+	for _, child := range n.children {
+		buf.WriteString(child.String())
+	}
+	fmt.Fprintf(&buf, "</%v>", name)
+	return buf.String()
+}
+
+// flexible/core/nodes/FieldableNode.java
+
+/* A query node implements FieldableNode if it has a field associated with it. */
+type FieldableNode interface {
+	QueryNode
+	Field() string
+	SetField(field string)
+}
+
+// flexible/core/nodes/TextableQueryNode.java
+
+/* A query node implements TextableQueryNode if it has text associated with it. */
+type TextableQueryNode interface {
+	QueryNode
+	Text() string
+	SetText(text string)
+}
+
+// flexible/core/nodes/FieldQueryNode.java
+
+/*
+A FieldQueryNode represents an element that contains field/text
+tuple. An empty field means the default field, or the fields of
+MULTI_FIELDS.
+*/
+type FieldQueryNode struct {
+	*QueryNodeImpl
+	field string
+	text  string
+	// The term's position increment, or the position of the term in a
+	// phrase once analyzed.
+	positionIncrement int
+}
+
+func NewFieldQueryNode(field, text string) *FieldQueryNode {
+	ans := &FieldQueryNode{field: field, text: text}
+	ans.QueryNodeImpl = NewQueryNodeImpl(ans, true)
+	return ans
+}
+
+func (n *FieldQueryNode) Field() string               { return n.field }
+func (n *FieldQueryNode) SetField(field string)       { n.field = field }
+func (n *FieldQueryNode) Text() string                { return n.text }
+func (n *FieldQueryNode) SetText(text string)         { n.text = text }
+func (n *FieldQueryNode) PositionIncrement() int      { return n.positionIncrement }
+func (n *FieldQueryNode) SetPositionIncrement(pi int) { n.positionIncrement = pi }
+func (n *FieldQueryNode) String() string              { return n.describe("field") }
+func (n *FieldQueryNode) describe(name string) string {
+	return fmt.Sprintf("<%v field='%v' text='%v'/>", name, n.field, n.text)
+}
+
+// flexible/core/nodes/QuotedFieldQueryNode.java
+
+/* A QuotedFieldQueryNode represents phrase query, like "a b c". */
+type QuotedFieldQueryNode struct {
+	*FieldQueryNode
+}
+
+func NewQuotedFieldQueryNode(field, text string) *QuotedFieldQueryNode {
+	ans := &QuotedFieldQueryNode{&FieldQueryNode{field: field, text: text}}
+	ans.QueryNodeImpl = NewQueryNodeImpl(ans, true)
+	return ans
+}
+
+func (n *QuotedFieldQueryNode) String() string { return n.describe("quotedfield") }
+
+// flexible/core/nodes/FuzzyQueryNode.java
+
+/*
+A FuzzyQueryNode represents a element that contains field/text/similarity
+tuple. A negative similarity means the default of the FUZZY_CONFIG.
+*/
+type FuzzyQueryNode struct {
+	*FieldQueryNode
+	similarity   float32
+	prefixLength int
+}
+
+func NewFuzzyQueryNode(field, term string, minSimilarity float32) *FuzzyQueryNode {
+	ans := &FuzzyQueryNode{FieldQueryNode: &FieldQueryNode{field: field, text: term}, similarity: minSimilarity}
+	ans.QueryNodeImpl = NewQueryNodeImpl(ans, true)
+	return ans
+}
+
+func (n *FuzzyQueryNode) Similarity() float32        { return n.similarity }
+func (n *FuzzyQueryNode) SetSimilarity(sim float32)  { n.similarity = sim }
+func (n *FuzzyQueryNode) PrefixLength() int          { return n.prefixLength }
+func (n *FuzzyQueryNode) SetPrefixLength(length int) { n.prefixLength = length }
+func (n *FuzzyQueryNode) String() string {
+	return fmt.Sprintf("<fuzzy field='%v' similarity='%v' term='%v'/>", n.field, n.similarity, n.text)
+}
+
+// flexible/core/nodes/WildcardQueryNode.java
+
+/*
+A WildcardQueryNode represents wildcard query. The text keeps its
+escape chars, so that escaped wildcards are not expanded.
+*/
+type WildcardQueryNode struct {
+	*FieldQueryNode
+}
+
+func NewWildcardQueryNode(field, template string) *WildcardQueryNode {
+	ans := &WildcardQueryNode{&FieldQueryNode{field: field, text: template}}
+	ans.QueryNodeImpl = NewQueryNodeImpl(ans, true)
+	return ans
+}
+
+func (n *WildcardQueryNode) String() string { return n.describe("wildcard") }
+
+// flexible/standard/nodes/PrefixWildcardQueryNode.java
+
+/* A PrefixWildcardQueryNode represents wildcardquery that matches abc* or *. */
+type PrefixWildcardQueryNode struct {
+	*FieldQueryNode
+}
+
+func NewPrefixWildcardQueryNode(field, text string) *PrefixWildcardQueryNode {
+	ans := &PrefixWildcardQueryNode{&FieldQueryNode{field: field, text: text}}
+	ans.QueryNodeImpl = NewQueryNodeImpl(ans, true)
+	return ans
+}
+
+func (n *PrefixWildcardQueryNode) String() string { return n.describe("prefixWildcard") }
+
+// flexible/standard/nodes/RegexpQueryNode.java
+
+/* A RegexpQueryNode represents RegexpQuery query, like /[a-z]+/. */
+type RegexpQueryNode struct {
+	*FieldQueryNode
+}
+
+func NewRegexpQueryNode(field, text string) *RegexpQueryNode {
+	ans := &RegexpQueryNode{&FieldQueryNode{field: field, text: text}}
+	ans.QueryNodeImpl = NewQueryNodeImpl(ans, true)
+	return ans
+}
+
+func (n *RegexpQueryNode) String() string { return n.describe("regexp") }
+
+// flexible/standard/nodes/TermRangeQueryNode.java
+
+/*
+This query node represents a range query composed by the lower and
+upper bounds. An empty bound is open.
+*/
+type TermRangeQueryNode struct {
+	*QueryNodeImpl
+	field                          string
+	lower, upper                   string
+	lowerInclusive, upperInclusive bool
+}
+
+func NewTermRangeQueryNode(field, lower, upper string, lowerInclusive, upperInclusive bool) *TermRangeQueryNode {
+	ans := &TermRangeQueryNode{
+		field:          field,
+		lower:          lower,
+		upper:          upper,
+		lowerInclusive: lowerInclusive,
+		upperInclusive: upperInclusive,
+	}
+	ans.QueryNodeImpl = NewQueryNodeImpl(ans, true)
+	return ans
+}
+
+func (n *TermRangeQueryNode) Field() string                 { return n.field }
+func (n *TermRangeQueryNode) SetField(field string)         { n.field = field }
+func (n *TermRangeQueryNode) LowerBound() string            { return n.lower }
+func (n *TermRangeQueryNode) UpperBound() string            { return n.upper }
+func (n *TermRangeQueryNode) SetBounds(lower, upper string) { n.lower, n.upper = lower, upper }
+func (n *TermRangeQueryNode) IsLowerInclusive() bool        { return n.lowerInclusive }
+func (n *TermRangeQueryNode) IsUpperInclusive() bool        { return n.upperInclusive }
+func (n *TermRangeQueryNode) String() string {
+	return fmt.Sprintf("<termRange field='%v' lower='%v' upper='%v' lowerInclusive='%v' upperInclusive='%v'/>",
+		n.field, n.lower, n.upper, n.lowerInclusive, n.upperInclusive)
+}
+
+// flexible/core/nodes/BooleanQueryNode.java
+
+/*
+A BooleanQueryNode represents a list of elements which do not have an
+explicit boolean operator defined between them. It can be used to
+express a boolean query that intends to use the default boolean
+operator.
+*/
+type BooleanQueryNode struct {
+	*QueryNodeImpl
+}
+
+func NewBooleanQueryNode(clauses []QueryNode) *BooleanQueryNode {
+	ans := new(BooleanQueryNode)
+	ans.QueryNodeImpl = NewQueryNodeImpl(ans, false)
+	ans.Set(clauses)
+	return ans
+}
+
+// flexible/core/nodes/AndQueryNode.java
+
+/* A AndQueryNode represents an AND boolean operation performed on a list of nodes. */
+type AndQueryNode struct {
+	*BooleanQueryNode
+}
+
+func NewAndQueryNode(clauses []QueryNode) *AndQueryNode {
+	ans := &AndQueryNode{new(BooleanQueryNode)}
+	ans.QueryNodeImpl = NewQueryNodeImpl(ans, false)
+	ans.Set(clauses)
+	return ans
+}
+
+// flexible/core/nodes/OrQueryNode.java
+
+/* A OrQueryNode represents an OR boolean operation performed on a list of nodes. */
+type OrQueryNode struct {
+	*BooleanQueryNode
+}
+
+func NewOrQueryNode(clauses []QueryNode) *OrQueryNode {
+	ans := &OrQueryNode{new(BooleanQueryNode)}
+	ans.QueryNodeImpl = NewQueryNodeImpl(ans, false)
+	ans.Set(clauses)
+	return ans
+}
+
+/* Returns true for the BooleanQueryNode, AndQueryNode and OrQueryNode. */
+func isBooleanNode(node QueryNode) bool {
+	switch node.(type) {
+	case *BooleanQueryNode, *AndQueryNode, *OrQueryNode:
+		return true
+	}
+	return false
+}
+
+// flexible/core/nodes/ModifierQueryNode.java
+
+type Modifier int
+
+const (
+	MOD_NONE = Modifier(iota)
+	MOD_NOT
+	MOD_REQ
+)
+
+func (m Modifier) String() string {
+	switch m {
+	case MOD_NOT:
+		return "-"
+	case MOD_REQ:
+		return "+"
+	}
+	return ""
+}
+
+/*
+A ModifierQueryNode indicates the modifier value (+,-,?,NONE) for
+each term on the query string. For example "+t1 -t2 t3" will have a
+tree of:
+
+	<BooleanQueryNode>
+	<ModifierQueryNode modifier="MOD_REQ"> <t1/> </ModifierQueryNode>
+	<ModifierQueryNode modifier="MOD_NOT"> <t2/> </ModifierQueryNode>
+	<t3/>
+	</BooleanQueryNode>
+*/
+type ModifierQueryNode struct {
+	*QueryNodeImpl
+	modifier Modifier
+}
+
+func NewModifierQueryNode(query QueryNode, mod Modifier) *ModifierQueryNode {
+	ans := &ModifierQueryNode{modifier: mod}
+	ans.QueryNodeImpl = NewQueryNodeImpl(ans, false)
+	ans.Add(query)
+	return ans
+}
+
+func (n *ModifierQueryNode) Modifier() Modifier { return n.modifier }
+func (n *ModifierQueryNode) String() string {
+	return fmt.Sprintf("<modifier operation='%v'>%v</modifier>", n.modifier, n.Child())
+}
+
+// flexible/standard/nodes/BooleanModifierNode.java
+
+/*
+A BooleanModifierNode has the same behaviour as ModifierQueryNode, it
+only indicates that this modifier was added by the default operator
+processor and not by the user query.
+*/
+type BooleanModifierNode struct {
+	*ModifierQueryNode
+}
+
+func NewBooleanModifierNode(node QueryNode, mod Modifier) *BooleanModifierNode {
+	ans := &BooleanModifierNode{&ModifierQueryNode{modifier: mod}}
+	ans.QueryNodeImpl = NewQueryNodeImpl(ans, false)
+	ans.Add(node)
+	return ans
+}
+
+/* Returns the modifier of a ModifierQueryNode or BooleanModifierNode. */
+func modifierOf(node QueryNode) (Modifier, bool) {
+	switch n := node.(type) {
+	case *ModifierQueryNode:
+		return n.modifier, true
+	case *BooleanModifierNode:
+		return n.modifier, true
+	}
+	return MOD_NONE, false
+}
+
+// flexible/core/nodes/BoostQueryNode.java
+
+/* A BoostQueryNode boosts the QueryNode tree which is under this node. */
+type BoostQueryNode struct {
+	*QueryNodeImpl
+	value float32
+}
+
+func NewBoostQueryNode(query QueryNode, value float32) *BoostQueryNode {
+	ans := &BoostQueryNode{value: value}
+	ans.QueryNodeImpl = NewQueryNodeImpl(ans, false)
+	ans.Add(query)
+	return ans
+}
+
+func (n *BoostQueryNode) Value() float32 { return n.value }
+func (n *BoostQueryNode) String() string {
+	return fmt.Sprintf("<boost value='%v'>%v</boost>", n.value, n.Child())
+}
+
+// flexible/core/nodes/SlopQueryNode.java
+
+/*
+A SlopQueryNode represents phrase query with a slop, like "a b"~2.
+The slop is dropped if the child is not a phrase.
+*/
+type SlopQueryNode struct {
+	*QueryNodeImpl
+	value int
+}
+
+func NewSlopQueryNode(query QueryNode, value int) *SlopQueryNode {
+	ans := &SlopQueryNode{value: value}
+	ans.QueryNodeImpl = NewQueryNodeImpl(ans, false)
+	ans.Add(query)
+	return ans
+}
+
+func (n *SlopQueryNode) Value() int { return n.value }
+func (n *SlopQueryNode) String() string {
+	return fmt.Sprintf("<slop value='%v'>%v</slop>", n.value, n.Child())
+}
+
+// flexible/core/nodes/GroupQueryNode.java
+
+/* A GroupQueryNode represents a location where the original user typed real parenthesis on the query string. */
+type GroupQueryNode struct {
+	*QueryNodeImpl
+}
+
+func NewGroupQueryNode(query QueryNode) *GroupQueryNode {
+	ans := new(GroupQueryNode)
+	ans.QueryNodeImpl = NewQueryNodeImpl(ans, false)
+	ans.Add(query)
+	return ans
+}
+
+// flexible/core/nodes/MatchAllDocsQueryNode.java
+
+/* A MatchAllDocsQueryNode indicates that a query node tree or subtree will match all documents. */
+type MatchAllDocsQueryNode struct {
+	*QueryNodeImpl
+}
+
+func NewMatchAllDocsQueryNode() *MatchAllDocsQueryNode {
+	ans := new(MatchAllDocsQueryNode)
+	ans.QueryNodeImpl = NewQueryNodeImpl(ans, true)
+	return ans
+}
+
+// flexible/core/nodes/MatchNoDocsQueryNode.java
+
+/* A MatchNoDocsQueryNode indicates that a query node tree or subtree will not match any documents. */
+type MatchNoDocsQueryNode struct {
+	*QueryNodeImpl
+}
+
+func NewMatchNoDocsQueryNode() *MatchNoDocsQueryNode {
+	ans := new(MatchNoDocsQueryNode)
+	ans.QueryNodeImpl = NewQueryNodeImpl(ans, true)
+	return ans
+}
+
+// flexible/core/nodes/DeletedQueryNode.java
+
+/* A DeletedQueryNode represents a node that was deleted from the query node tree. */
+type DeletedQueryNode struct {
+	*QueryNodeImpl
+}
+
+func NewDeletedQueryNode() *DeletedQueryNode {
+	ans := new(DeletedQueryNode)
+	ans.QueryNodeImpl = NewQueryNodeImpl(ans, true)
+	return ans
+}
+
+// flexible/core/nodes/NoTokenFoundQueryNode.java
+
+/* A NoTokenFoundQueryNode is used if a term is converted into no tokens by the analyzer. */
+type NoTokenFoundQueryNode struct {
+	*QueryNodeImpl
+}
+
+func NewNoTokenFoundQueryNode() *NoTokenFoundQueryNode {
+	ans := new(NoTokenFoundQueryNode)
+	ans.QueryNodeImpl = NewQueryNodeImpl(ans, true)
+	return ans
+}
+
+/* Returns true for the DeletedQueryNode and its specializations. */
+func isDeletedNode(node QueryNode) bool {
+	switch node.(type) {
+	case *DeletedQueryNode, *NoTokenFoundQueryNode, *MatchNoDocsQueryNode:
+		return true
+	}
+	return false
+}
+
+// flexible/core/nodes/TokenizedPhraseQueryNode.java
+
+/*
+A TokenizedPhraseQueryNode represents a node created by a code that
+tokenizes/lemmatizes/analyzes. Its children are FieldQueryNodes,
+whose position increments hold their positions in the phrase.
+*/
+type TokenizedPhraseQueryNode struct {
+	*QueryNodeImpl
+}
+
+func NewTokenizedPhraseQueryNode() *TokenizedPhraseQueryNode {
+	ans := new(TokenizedPhraseQueryNode)
+	ans.QueryNodeImpl = NewQueryNodeImpl(ans, false)
+	return ans
+}
+
+func (n *TokenizedPhraseQueryNode) Field() string {
+	return fieldOfChildren(n.children)
+}
+
+func (n *TokenizedPhraseQueryNode) SetField(field string) {
+	setFieldOfChildren(n.children, field)
+}
+
+// flexible/standard/nodes/MultiPhraseQueryNode.java
+
+/*
+A MultiPhraseQueryNode indicates that its children should be used to
+build a phrase matching several terms at some positions. Children
+having the same position are alternatives.
+*/
+type MultiPhraseQueryNode struct {
+	*QueryNodeImpl
+}
+
+func NewMultiPhraseQueryNode() *MultiPhraseQueryNode {
+	ans := new(MultiPhraseQueryNode)
+	ans.QueryNodeImpl = NewQueryNodeImpl(ans, false)
+	return ans
+}
+
+func (n *MultiPhraseQueryNode) Field() string {
+	return fieldOfChildren(n.children)
+}
+
+func (n *MultiPhraseQueryNode) SetField(field string) {
+	setFieldOfChildren(n.children, field)
+}
+
+// flexible/standard/nodes/SynonymQueryNode.java
+
+/* A SynonymQueryNode holds the terms found by the analyzer at the same position. */
+type SynonymQueryNode struct {
+	*QueryNodeImpl
+}
+
+func NewSynonymQueryNode(terms []QueryNode) *SynonymQueryNode {
+	ans := new(SynonymQueryNode)
+	ans.QueryNodeImpl = NewQueryNodeImpl(ans, false)
+	ans.Set(terms)
+	return ans
+}
+
+func (n *SynonymQueryNode) Field() string {
+	return fieldOfChildren(n.children)
+}
+
+func (n *SynonymQueryNode) SetField(field string) {
+	setFieldOfChildren(n.children, field)
+}
+
+func fieldOfChildren(children []QueryNode) string {
+	if len(children) == 0 {
+		return ""
+	}
+	return children[0].(FieldableNode).Field()
+}
+
+func setFieldOfChildren(children []QueryNode, field string) {
+	for _, child := range children {
+		child.(FieldableNode).SetField(field)
+	}
+}
+
+/* Returns a copy of a fieldable leaf node, as produced by the syntax parser. */
+func cloneFieldableNode(node FieldableNode) (FieldableNode, error) {
+	switch n := node.(type) {
+	case *FieldQueryNode:
+		return NewFieldQueryNode(n.field, n.text), nil
+	case *QuotedFieldQueryNode:
+		return NewQuotedFieldQueryNode(n.field, n.text), nil
+	case *FuzzyQueryNode:
+		ans := NewFuzzyQueryNode(n.field, n.text, n.similarity)
+		ans.prefixLength = n.prefixLength
+		return ans, nil
+	case *WildcardQueryNode:
+		return NewWildcardQueryNode(n.field, n.text), nil
+	case *PrefixWildcardQueryNode:
+		return NewPrefixWildcardQueryNode(n.field, n.text), nil
+	case *RegexpQueryNode:
+		return NewRegexpQueryNode(n.field, n.text), nil
+	case *TermRangeQueryNode:
+		return NewTermRangeQueryNode(n.field, n.lower, n.upper, n.lowerInclusive, n.upperInclusive), nil
+	}
+	return nil, fmt.Errorf("%v can not be cloned", node)
+}
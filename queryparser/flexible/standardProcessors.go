@@ -0,0 +1,825 @@
+package flexible
+
+import (
+	"errors"
+	"fmt"
+	"github.com/balzaczyy/golucene/core/analysis"
+	ta "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"github.com/balzaczyy/golucene/core/search"
+	"strings"
+)
+
+// flexible/standard/processors/StandardQueryNodeProcessorPipeline.java
+
+/*
+This pipeline has all the processors needed to process a query node
+tree, generated by StandardSyntaxParser, already assembled. The order
+they are assembled affects the results.
+
+Wildcards are recognized by the syntax parser, which knows the escaped
+characters, so there is no processor turning terms into wildcard nodes.
+*/
+func NewStandardQueryNodeProcessorPipeline(config *QueryConfigHandler) *QueryNodeProcessorPipeline {
+	ans := NewQueryNodeProcessorPipeline(config)
+	ans.Add(NewMultiFieldQueryNodeProcessor())
+	ans.Add(NewFuzzyQueryNodeProcessor())
+	ans.Add(NewMatchAllDocsQueryNodeProcessor())
+	ans.Add(NewLowercaseExpandedTermsQueryNodeProcessor())
+	ans.Add(NewAllowLeadingWildcardProcessor())
+	ans.Add(NewAnalyzerQueryNodeProcessor())
+	ans.Add(NewPhraseSlopQueryNodeProcessor())
+	ans.Add(NewBooleanQuery2ModifierNodeProcessor())
+	ans.Add(NewNoChildOptimizationQueryNodeProcessor())
+	ans.Add(NewRemoveDeletedQueryNodesProcessor())
+	ans.Add(NewRemoveEmptyNonLeafQueryNodeProcessor())
+	ans.Add(NewBooleanSingleChildOptimizationQueryNodeProcessor())
+	ans.Add(NewDefaultPhraseSlopQueryNodeProcessor())
+	ans.Add(NewBoostQueryNodeProcessor())
+	return ans
+}
+
+// flexible/standard/processors/MultiFieldQueryNodeProcessor.java
+
+/*
+This processor is used to expand terms so the query looks for the
+same term in different fields. It also boosts a query based on its
+field.
+
+This processor looks for every FieldableNode contained in the query
+node tree. If a FieldableNode is found without a field, it checks if
+there is a MULTI_FIELDS defined in the QueryConfigHandler. If so, the
+node is replaced by a GroupQueryNode of an OrQueryNode with a copy of
+the node for each field.
+*/
+type MultiFieldQueryNodeProcessor struct {
+	*QueryNodeProcessorImpl
+}
+
+func NewMultiFieldQueryNodeProcessor() *MultiFieldQueryNodeProcessor {
+	ans := new(MultiFieldQueryNodeProcessor)
+	ans.QueryNodeProcessorImpl = NewQueryNodeProcessorImpl(ans)
+	return ans
+}
+
+func (p *MultiFieldQueryNodeProcessor) PreProcessNode(node QueryNode) (QueryNode, error) {
+	fieldNode, ok := node.(FieldableNode)
+	if !ok || fieldNode.Field() != "" {
+		return node, nil
+	}
+	fields, ok := p.QueryConfigHandler().Get(MULTI_FIELDS).([]string)
+	if !ok {
+		return nil, errors.New("MULTI_FIELDS should be set on the QueryConfigHandler")
+	}
+	switch len(fields) {
+	case 0:
+		return node, nil
+	case 1:
+		fieldNode.SetField(fields[0])
+		return node, nil
+	}
+	children := make([]QueryNode, len(fields))
+	for i, field := range fields {
+		child, err := cloneFieldableNode(fieldNode)
+		if err != nil {
+			return nil, err
+		}
+		child.SetField(field)
+		children[i] = child
+	}
+	return NewGroupQueryNode(NewOrQueryNode(children)), nil
+}
+
+func (p *MultiFieldQueryNodeProcessor) PostProcessNode(node QueryNode) (QueryNode, error) {
+	return node, nil
+}
+
+func (p *MultiFieldQueryNodeProcessor) SetChildrenOrder(children []QueryNode) ([]QueryNode, error) {
+	return children, nil
+}
+
+// flexible/standard/processors/FuzzyQueryNodeProcessor.java
+
+/*
+This processor iterates the query node tree looking for every
+FuzzyQueryNode. When this kind of node is found, it checks on the
+query configuration for FUZZY_CONFIG, gets the prefix length from it
+and the minimum similarity, if the node doesn't define one.
+*/
+type FuzzyQueryNodeProcessor struct {
+	*QueryNodeProcessorImpl
+}
+
+func NewFuzzyQueryNodeProcessor() *FuzzyQueryNodeProcessor {
+	ans := new(FuzzyQueryNodeProcessor)
+	ans.QueryNodeProcessorImpl = NewQueryNodeProcessorImpl(ans)
+	return ans
+}
+
+func (p *FuzzyQueryNodeProcessor) PreProcessNode(node QueryNode) (QueryNode, error) {
+	return node, nil
+}
+
+func (p *FuzzyQueryNodeProcessor) PostProcessNode(node QueryNode) (QueryNode, error) {
+	if fuzzyNode, ok := node.(*FuzzyQueryNode); ok {
+		if fuzzyConfig, ok := p.QueryConfigHandler().Get(FUZZY_CONFIG).(*FuzzyConfig); ok {
+			fuzzyNode.SetPrefixLength(fuzzyConfig.PrefixLength)
+			if fuzzyNode.Similarity() < 0 {
+				fuzzyNode.SetSimilarity(fuzzyConfig.MinSimilarity)
+			}
+		} else if fuzzyNode.Similarity() < 0 {
+			fuzzyNode.SetSimilarity(search.FUZZY_DEFAULT_MAX_EDITS)
+		}
+	}
+	return node, nil
+}
+
+func (p *FuzzyQueryNodeProcessor) SetChildrenOrder(children []QueryNode) ([]QueryNode, error) {
+	return children, nil
+}
+
+// flexible/standard/processors/MatchAllDocsQueryNodeProcessor.java
+
+/*
+This processor converts every field node that has its field and text
+set to "*" to a MatchAllDocsQueryNode.
+*/
+type MatchAllDocsQueryNodeProcessor struct {
+	*QueryNodeProcessorImpl
+}
+
+func NewMatchAllDocsQueryNodeProcessor() *MatchAllDocsQueryNodeProcessor {
+	ans := new(MatchAllDocsQueryNodeProcessor)
+	ans.QueryNodeProcessorImpl = NewQueryNodeProcessorImpl(ans)
+	return ans
+}
+
+func (p *MatchAllDocsQueryNodeProcessor) PreProcessNode(node QueryNode) (QueryNode, error) {
+	return node, nil
+}
+
+func (p *MatchAllDocsQueryNodeProcessor) PostProcessNode(node QueryNode) (QueryNode, error) {
+	switch n := node.(type) {
+	case *FieldQueryNode, *WildcardQueryNode, *PrefixWildcardQueryNode:
+		if fieldNode := n.(TextableQueryNode); fieldNode.Text() == "*" && n.(FieldableNode).Field() == "*" {
+			return NewMatchAllDocsQueryNode(), nil
+		}
+	}
+	return node, nil
+}
+
+func (p *MatchAllDocsQueryNodeProcessor) SetChildrenOrder(children []QueryNode) ([]QueryNode, error) {
+	return children, nil
+}
+
+// flexible/standard/processors/LowercaseExpandedTermsQueryNodeProcessor.java
+
+/*
+This processor verifies if LOWERCASE_EXPANDED_TERMS is defined in the
+QueryConfigHandler (true by default). If it is and the expanded terms
+should be lower-cased, it looks for every WildcardQueryNode,
+PrefixWildcardQueryNode, FuzzyQueryNode, RegexpQueryNode and
+TermRangeQueryNode and lower-case their terms.
+*/
+type LowercaseExpandedTermsQueryNodeProcessor struct {
+	*QueryNodeProcessorImpl
+}
+
+func NewLowercaseExpandedTermsQueryNodeProcessor() *LowercaseExpandedTermsQueryNodeProcessor {
+	ans := new(LowercaseExpandedTermsQueryNodeProcessor)
+	ans.QueryNodeProcessorImpl = NewQueryNodeProcessorImpl(ans)
+	return ans
+}
+
+func (p *LowercaseExpandedTermsQueryNodeProcessor) Process(queryTree QueryNode) (QueryNode, error) {
+	if !p.QueryConfigHandler().boolOr(LOWERCASE_EXPANDED_TERMS, true) {
+		return queryTree, nil
+	}
+	return p.QueryNodeProcessorImpl.Process(queryTree)
+}
+
+func (p *LowercaseExpandedTermsQueryNodeProcessor) PreProcessNode(node QueryNode) (QueryNode, error) {
+	return node, nil
+}
+
+func (p *LowercaseExpandedTermsQueryNodeProcessor) PostProcessNode(node QueryNode) (QueryNode, error) {
+	switch n := node.(type) {
+	case *WildcardQueryNode, *PrefixWildcardQueryNode, *FuzzyQueryNode, *RegexpQueryNode:
+		textNode := n.(TextableQueryNode)
+		textNode.SetText(strings.ToLower(textNode.Text()))
+	case *TermRangeQueryNode:
+		n.SetBounds(strings.ToLower(n.LowerBound()), strings.ToLower(n.UpperBound()))
+	}
+	return node, nil
+}
+
+func (p *LowercaseExpandedTermsQueryNodeProcessor) SetChildrenOrder(children []QueryNode) ([]QueryNode, error) {
+	return children, nil
+}
+
+// flexible/standard/processors/AllowLeadingWildcardProcessor.java
+
+/*
+This processor verifies if ALLOW_LEADING_WILDCARD is defined in the
+QueryConfigHandler. If it is and leading wildcard is not allowed, it
+looks for every WildcardQueryNode and PrefixWildcardQueryNode
+contained in the query node tree and returns an error if any of them
+has a leading wildcard ('*' or '?').
+*/
+type AllowLeadingWildcardProcessor struct {
+	*QueryNodeProcessorImpl
+}
+
+func NewAllowLeadingWildcardProcessor() *AllowLeadingWildcardProcessor {
+	ans := new(AllowLeadingWildcardProcessor)
+	ans.QueryNodeProcessorImpl = NewQueryNodeProcessorImpl(ans)
+	return ans
+}
+
+func (p *AllowLeadingWildcardProcessor) Process(queryTree QueryNode) (QueryNode, error) {
+	if p.QueryConfigHandler().boolOr(ALLOW_LEADING_WILDCARD, false) {
+		return queryTree, nil
+	}
+	return p.QueryNodeProcessorImpl.Process(queryTree)
+}
+
+func (p *AllowLeadingWildcardProcessor) PreProcessNode(node QueryNode) (QueryNode, error) {
+	return node, nil
+}
+
+func (p *AllowLeadingWildcardProcessor) PostProcessNode(node QueryNode) (QueryNode, error) {
+	switch n := node.(type) {
+	case *WildcardQueryNode, *PrefixWildcardQueryNode:
+		// the text keeps its escape chars, so an escaped wildcard is fine
+		if text := n.(TextableQueryNode).Text(); strings.HasPrefix(text, "*") || strings.HasPrefix(text, "?") {
+			return nil, fmt.Errorf("Leading wildcard is not allowed: %v", node)
+		}
+	}
+	return node, nil
+}
+
+func (p *AllowLeadingWildcardProcessor) SetChildrenOrder(children []QueryNode) ([]QueryNode, error) {
+	return children, nil
+}
+
+// flexible/standard/processors/AnalyzerQueryNodeProcessor.java
+
+/*
+This processor verifies if ANALYZER is defined in the
+QueryConfigHandler. If it is and the analyzer is not nil, it looks
+for every FieldQueryNode and QuotedFieldQueryNode in the query node
+tree, and applies the analyzer to their text.
+
+If the analyzer returns only one term, the node keeps it as its text.
+If it returns no term at all, the node is replaced by a
+NoTokenFoundQueryNode. Terms found at the same position become a
+SynonymQueryNode. A text giving terms at several positions becomes a
+GroupQueryNode of a BooleanQueryNode if it was not quoted, or a
+TokenizedPhraseQueryNode, or a MultiPhraseQueryNode when there are
+several terms at some position.
+*/
+type AnalyzerQueryNodeProcessor struct {
+	*QueryNodeProcessorImpl
+	analyzer                  analysis.Analyzer
+	positionIncrementsEnabled bool
+}
+
+func NewAnalyzerQueryNodeProcessor() *AnalyzerQueryNodeProcessor {
+	ans := new(AnalyzerQueryNodeProcessor)
+	ans.QueryNodeProcessorImpl = NewQueryNodeProcessorImpl(ans)
+	return ans
+}
+
+func (p *AnalyzerQueryNodeProcessor) Process(queryTree QueryNode) (QueryNode, error) {
+	config := p.QueryConfigHandler()
+	analyzer, ok := config.Get(ANALYZER).(analysis.Analyzer)
+	if !ok || analyzer == nil {
+		return queryTree, nil
+	}
+	p.analyzer = analyzer
+	p.positionIncrementsEnabled = config.boolOr(ENABLE_POSITION_INCREMENTS, false)
+	return p.QueryNodeProcessorImpl.Process(queryTree)
+}
+
+func (p *AnalyzerQueryNodeProcessor) PreProcessNode(node QueryNode) (QueryNode, error) {
+	return node, nil
+}
+
+/* A term produced by the analyzer, with its position increment. */
+type analyzedToken struct {
+	text              string
+	positionIncrement int
+}
+
+func (p *AnalyzerQueryNodeProcessor) analyze(field, text string) (tokens []analyzedToken, err error) {
+	ts, err := p.analyzer.TokenStreamForString(field, text)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if e := ts.Close(); err == nil {
+			err = e
+		}
+	}()
+	termAtt := ts.Attributes().Add("CharTermAttribute").(ta.CharTermAttribute)
+	posIncrAtt := ts.Attributes().Add("PositionIncrementAttribute").(ta.PositionIncrementAttribute)
+
+	if err = ts.Reset(); err != nil {
+		return nil, err
+	}
+	for {
+		ok, err := ts.IncrementToken()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			break
+		}
+		tokens = append(tokens, analyzedToken{
+			string(termAtt.Buffer()[:termAtt.Length()]),
+			posIncrAtt.PositionIncrement(),
+		})
+	}
+	return tokens, ts.End()
+}
+
+func (p *AnalyzerQueryNodeProcessor) PostProcessNode(node QueryNode) (QueryNode, error) {
+	var fieldNode *FieldQueryNode
+	var quoted bool
+	switch n := node.(type) {
+	case *FieldQueryNode:
+		fieldNode = n
+	case *QuotedFieldQueryNode:
+		fieldNode, quoted = n.FieldQueryNode, true
+	default:
+		return node, nil
+	}
+	field := fieldNode.Field()
+	tokens, err := p.analyze(field, fieldNode.Text())
+	if err != nil {
+		return nil, err
+	}
+
+	positionCount := 0
+	severalTokensAtSamePosition := false
+	for _, token := range tokens {
+		if token.positionIncrement != 0 {
+			positionCount += token.positionIncrement
+		} else {
+			severalTokensAtSamePosition = true
+		}
+	}
+
+	switch {
+	case len(tokens) == 0:
+		return NewNoTokenFoundQueryNode(), nil
+
+	case len(tokens) == 1:
+		fieldNode.SetText(tokens[0].text)
+		return node, nil
+
+	case severalTokensAtSamePosition && positionCount == 1:
+		// only one position: synonyms of a single token
+		children := make([]QueryNode, len(tokens))
+		for i, token := range tokens {
+			children[i] = NewFieldQueryNode(field, token.text)
+		}
+		return NewSynonymQueryNode(children), nil
+
+	case !quoted:
+		// multiple positions, one clause per position
+		var children, current []QueryNode
+		flush := func() {
+			switch len(current) {
+			case 0:
+			case 1:
+				children = append(children, current[0])
+			default:
+				children = append(children, NewSynonymQueryNode(current))
+			}
+			current = nil
+		}
+		for _, token := range tokens {
+			if token.positionIncrement != 0 {
+				flush()
+			}
+			current = append(current, NewFieldQueryNode(field, token.text))
+		}
+		flush()
+		return NewGroupQueryNode(NewBooleanQueryNode(children)), nil
+
+	case severalTokensAtSamePosition:
+		// phrase query with several terms at some positions
+		mpq := NewMultiPhraseQueryNode()
+		position := -1
+		for i, token := range tokens {
+			if token.positionIncrement > 0 || i == 0 {
+				if p.positionIncrementsEnabled {
+					position += token.positionIncrement
+				} else {
+					position++
+				}
+			}
+			newFieldNode := NewFieldQueryNode(field, token.text)
+			newFieldNode.SetPositionIncrement(position)
+			mpq.Add(newFieldNode)
+		}
+		return mpq, nil
+
+	default:
+		pq := NewTokenizedPhraseQueryNode()
+		position := -1
+		for _, token := range tokens {
+			if p.positionIncrementsEnabled {
+				position += token.positionIncrement
+			} else {
+				position++
+			}
+			newFieldNode := NewFieldQueryNode(field, token.text)
+			newFieldNode.SetPositionIncrement(position)
+			pq.Add(newFieldNode)
+		}
+		return pq, nil
+	}
+}
+
+func (p *AnalyzerQueryNodeProcessor) SetChildrenOrder(children []QueryNode) ([]QueryNode, error) {
+	return children, nil
+}
+
+// flexible/standard/processors/PhraseSlopQueryNodeProcessor.java
+
+/*
+This processor removes invalid SlopQueryNode objects in the query
+node tree. A SlopQueryNode is invalid if its child is neither a
+TokenizedPhraseQueryNode nor a MultiPhraseQueryNode.
+*/
+type PhraseSlopQueryNodeProcessor struct {
+	*QueryNodeProcessorImpl
+}
+
+func NewPhraseSlopQueryNodeProcessor() *PhraseSlopQueryNodeProcessor {
+	ans := new(PhraseSlopQueryNodeProcessor)
+	ans.QueryNodeProcessorImpl = NewQueryNodeProcessorImpl(ans)
+	return ans
+}
+
+func (p *PhraseSlopQueryNodeProcessor) PreProcessNode(node QueryNode) (QueryNode, error) {
+	return node, nil
+}
+
+func (p *PhraseSlopQueryNodeProcessor) PostProcessNode(node QueryNode) (QueryNode, error) {
+	if slopNode, ok := node.(*SlopQueryNode); ok {
+		switch child := slopNode.Child(); child.(type) {
+		case *TokenizedPhraseQueryNode, *MultiPhraseQueryNode:
+		default:
+			return child, nil
+		}
+	}
+	return node, nil
+}
+
+func (p *PhraseSlopQueryNodeProcessor) SetChildrenOrder(children []QueryNode) ([]QueryNode, error) {
+	return children, nil
+}
+
+// flexible/standard/processors/BooleanQuery2ModifierNodeProcessor.java
+
+/*
+This processor is used to apply the correct ModifierQueryNode to
+BooleanQueryNodes children. It works in conjunction with the
+DEFAULT_OPERATOR: with OP_AND, every child of a BooleanQueryNode
+without a modifier is required; with OP_OR, only the children of an
+AndQueryNode are. The modifiers added are BooleanModifierNodes.
+*/
+type BooleanQuery2ModifierNodeProcessor struct {
+	*QueryNodeProcessorImpl
+	usingAnd bool
+}
+
+func NewBooleanQuery2ModifierNodeProcessor() *BooleanQuery2ModifierNodeProcessor {
+	ans := new(BooleanQuery2ModifierNodeProcessor)
+	ans.QueryNodeProcessorImpl = NewQueryNodeProcessorImpl(ans)
+	return ans
+}
+
+func (p *BooleanQuery2ModifierNodeProcessor) Process(queryTree QueryNode) (QueryNode, error) {
+	op, ok := p.QueryConfigHandler().Get(DEFAULT_OPERATOR).(Operator)
+	if !ok {
+		return nil, errors.New("DEFAULT_OPERATOR should be set on the QueryConfigHandler")
+	}
+	p.usingAnd = op == OP_AND
+	return p.QueryNodeProcessorImpl.Process(queryTree)
+}
+
+func (p *BooleanQuery2ModifierNodeProcessor) PreProcessNode(node QueryNode) (QueryNode, error) {
+	return node, nil
+}
+
+func (p *BooleanQuery2ModifierNodeProcessor) PostProcessNode(node QueryNode) (QueryNode, error) {
+	switch node.(type) {
+	case *AndQueryNode:
+	case *BooleanQueryNode:
+		if !p.usingAnd {
+			return node, nil
+		}
+	default:
+		return node, nil
+	}
+	children := node.Children()
+	newChildren := make([]QueryNode, len(children))
+	for i, child := range children {
+		newChildren[i] = p.applyModifier(child)
+	}
+	node.Set(newChildren)
+	return node, nil
+}
+
+/* Makes the child required, unless it has an explicit modifier. */
+func (p *BooleanQuery2ModifierNodeProcessor) applyModifier(node QueryNode) QueryNode {
+	mod, ok := modifierOf(node)
+	if !ok {
+		return NewBooleanModifierNode(node, MOD_REQ)
+	}
+	if mod == MOD_NONE {
+		return NewBooleanModifierNode(node.Children()[0], MOD_REQ)
+	}
+	return node
+}
+
+func (p *BooleanQuery2ModifierNodeProcessor) SetChildrenOrder(children []QueryNode) ([]QueryNode, error) {
+	return children, nil
+}
+
+// flexible/core/processors/NoChildOptimizationQueryNodeProcessor.java
+
+/*
+A NoChildOptimizationQueryNodeProcessor removes every
+BooleanQueryNode, BoostQueryNode, TokenizedPhraseQueryNode or
+ModifierQueryNode that do not have a valid children, replacing it by
+a MatchNoDocsQueryNode.
+*/
+type NoChildOptimizationQueryNodeProcessor struct {
+	*QueryNodeProcessorImpl
+}
+
+func NewNoChildOptimizationQueryNodeProcessor() *NoChildOptimizationQueryNodeProcessor {
+	ans := new(NoChildOptimizationQueryNodeProcessor)
+	ans.QueryNodeProcessorImpl = NewQueryNodeProcessorImpl(ans)
+	return ans
+}
+
+func (p *NoChildOptimizationQueryNodeProcessor) PreProcessNode(node QueryNode) (QueryNode, error) {
+	return node, nil
+}
+
+func (p *NoChildOptimizationQueryNodeProcessor) PostProcessNode(node QueryNode) (QueryNode, error) {
+	switch node.(type) {
+	case *BooleanQueryNode, *AndQueryNode, *OrQueryNode, *BoostQueryNode,
+		*TokenizedPhraseQueryNode, *ModifierQueryNode, *BooleanModifierNode:
+		for _, child := range node.Children() {
+			if !isDeletedNode(child) {
+				return node, nil
+			}
+		}
+		return NewMatchNoDocsQueryNode(), nil
+	}
+	return node, nil
+}
+
+func (p *NoChildOptimizationQueryNodeProcessor) SetChildrenOrder(children []QueryNode) ([]QueryNode, error) {
+	return children, nil
+}
+
+// flexible/core/processors/RemoveDeletedQueryNodesProcessor.java
+
+/*
+A RemoveDeletedQueryNodesProcessor removes every DeletedQueryNode
+found in the query node tree. Non-leaf nodes left without children
+are deleted as well, and an empty tree becomes a MatchNoDocsQueryNode.
+*/
+type RemoveDeletedQueryNodesProcessor struct {
+	*QueryNodeProcessorImpl
+}
+
+func NewRemoveDeletedQueryNodesProcessor() *RemoveDeletedQueryNodesProcessor {
+	ans := new(RemoveDeletedQueryNodesProcessor)
+	ans.QueryNodeProcessorImpl = NewQueryNodeProcessorImpl(ans)
+	return ans
+}
+
+func (p *RemoveDeletedQueryNodesProcessor) Process(queryTree QueryNode) (QueryNode, error) {
+	queryTree, err := p.QueryNodeProcessorImpl.Process(queryTree)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := queryTree.(*MatchNoDocsQueryNode); !ok && isDeletedNode(queryTree) {
+		return NewMatchNoDocsQueryNode(), nil
+	}
+	return queryTree, nil
+}
+
+func (p *RemoveDeletedQueryNodesProcessor) PreProcessNode(node QueryNode) (QueryNode, error) {
+	return node, nil
+}
+
+func (p *RemoveDeletedQueryNodesProcessor) PostProcessNode(node QueryNode) (QueryNode, error) {
+	if node.IsLeaf() {
+		return node, nil
+	}
+	// children may have been deleted while processed
+	children, _ := p.SetChildrenOrder(append([]QueryNode(nil), node.Children()...))
+	if len(children) == 0 {
+		return NewDeletedQueryNode(), nil
+	}
+	node.Set(children)
+	return node, nil
+}
+
+func (p *RemoveDeletedQueryNodesProcessor) SetChildrenOrder(children []QueryNode) ([]QueryNode, error) {
+	ans := children[:0]
+	for _, child := range children {
+		if !isDeletedNode(child) {
+			ans = append(ans, child)
+		}
+	}
+	return ans, nil
+}
+
+// flexible/core/processors/RemoveEmptyNonLeafQueryNodeProcessor.java
+
+/*
+This processor removes every QueryNode that is not a leaf and has not
+children. If after processing the entire tree the root node is not a
+leaf and has no children, a MatchNoDocsQueryNode object is returned.
+*/
+type RemoveEmptyNonLeafQueryNodeProcessor struct {
+	*QueryNodeProcessorImpl
+}
+
+func NewRemoveEmptyNonLeafQueryNodeProcessor() *RemoveEmptyNonLeafQueryNodeProcessor {
+	ans := new(RemoveEmptyNonLeafQueryNodeProcessor)
+	ans.QueryNodeProcessorImpl = NewQueryNodeProcessorImpl(ans)
+	return ans
+}
+
+func (p *RemoveEmptyNonLeafQueryNodeProcessor) Process(queryTree QueryNode) (QueryNode, error) {
+	queryTree, err := p.QueryNodeProcessorImpl.Process(queryTree)
+	if err != nil {
+		return nil, err
+	}
+	if !queryTree.IsLeaf() && len(queryTree.Children()) == 0 {
+		return NewMatchNoDocsQueryNode(), nil
+	}
+	return queryTree, nil
+}
+
+func (p *RemoveEmptyNonLeafQueryNodeProcessor) PreProcessNode(node QueryNode) (QueryNode, error) {
+	return node, nil
+}
+
+func (p *RemoveEmptyNonLeafQueryNodeProcessor) PostProcessNode(node QueryNode) (QueryNode, error) {
+	return node, nil
+}
+
+func (p *RemoveEmptyNonLeafQueryNodeProcessor) SetChildrenOrder(children []QueryNode) ([]QueryNode, error) {
+	ans := children[:0]
+	for _, child := range children {
+		if child.IsLeaf() || len(child.Children()) > 0 {
+			ans = append(ans, child)
+		}
+	}
+	return ans, nil
+}
+
+// flexible/standard/processors/BooleanSingleChildOptimizationQueryNodeProcessor.java
+
+/*
+This processor removes every BooleanQueryNode that contains only one
+child and returns this child. If this child is ModifierQueryNode that
+was defined by the user, the BooleanQueryNode is kept.
+*/
+type BooleanSingleChildOptimizationQueryNodeProcessor struct {
+	*QueryNodeProcessorImpl
+}
+
+func NewBooleanSingleChildOptimizationQueryNodeProcessor() *BooleanSingleChildOptimizationQueryNodeProcessor {
+	ans := new(BooleanSingleChildOptimizationQueryNodeProcessor)
+	ans.QueryNodeProcessorImpl = NewQueryNodeProcessorImpl(ans)
+	return ans
+}
+
+func (p *BooleanSingleChildOptimizationQueryNodeProcessor) PreProcessNode(node QueryNode) (QueryNode, error) {
+	return node, nil
+}
+
+func (p *BooleanSingleChildOptimizationQueryNodeProcessor) PostProcessNode(node QueryNode) (QueryNode, error) {
+	if !isBooleanNode(node) {
+		return node, nil
+	}
+	children := node.Children()
+	if len(children) != 1 {
+		return node, nil
+	}
+	child := children[0]
+	switch n := child.(type) {
+	case *BooleanModifierNode:
+		return child, nil
+	case *ModifierQueryNode:
+		if n.Modifier() == MOD_NONE {
+			return child, nil
+		}
+		return node, nil
+	}
+	return child, nil
+}
+
+func (p *BooleanSingleChildOptimizationQueryNodeProcessor) SetChildrenOrder(children []QueryNode) ([]QueryNode, error) {
+	return children, nil
+}
+
+// flexible/standard/processors/DefaultPhraseSlopQueryNodeProcessor.java
+
+/*
+This processor verifies if PHRASE_SLOP is defined in the
+QueryConfigHandler. If it is, it looks for every
+TokenizedPhraseQueryNode and MultiPhraseQueryNode which is not under
+a SlopQueryNode, and wraps them in a SlopQueryNode with the default
+phrase slop.
+*/
+type DefaultPhraseSlopQueryNodeProcessor struct {
+	*QueryNodeProcessorImpl
+	defaultPhraseSlop int
+}
+
+func NewDefaultPhraseSlopQueryNodeProcessor() *DefaultPhraseSlopQueryNodeProcessor {
+	ans := new(DefaultPhraseSlopQueryNodeProcessor)
+	ans.QueryNodeProcessorImpl = NewQueryNodeProcessorImpl(ans)
+	return ans
+}
+
+func (p *DefaultPhraseSlopQueryNodeProcessor) Process(queryTree QueryNode) (QueryNode, error) {
+	slop, ok := p.QueryConfigHandler().Get(PHRASE_SLOP).(int)
+	if !ok {
+		return queryTree, nil
+	}
+	p.defaultPhraseSlop = slop
+	return p.QueryNodeProcessorImpl.Process(queryTree)
+}
+
+func (p *DefaultPhraseSlopQueryNodeProcessor) PreProcessNode(node QueryNode) (QueryNode, error) {
+	return node, nil
+}
+
+func (p *DefaultPhraseSlopQueryNodeProcessor) PostProcessNode(node QueryNode) (QueryNode, error) {
+	switch node.(type) {
+	case *TokenizedPhraseQueryNode, *MultiPhraseQueryNode:
+		if _, ok := node.Parent().(*SlopQueryNode); !ok {
+			return NewSlopQueryNode(node, p.defaultPhraseSlop), nil
+		}
+	}
+	return node, nil
+}
+
+func (p *DefaultPhraseSlopQueryNodeProcessor) SetChildrenOrder(children []QueryNode) ([]QueryNode, error) {
+	return children, nil
+}
+
+// flexible/standard/processors/BoostQueryNodeProcessor.java
+
+/*
+This processor iterates the query node tree looking for every
+FieldableNode that has BOOST in its config. If there is, the boost is
+applied to that FieldableNode.
+*/
+type BoostQueryNodeProcessor struct {
+	*QueryNodeProcessorImpl
+}
+
+func NewBoostQueryNodeProcessor() *BoostQueryNodeProcessor {
+	ans := new(BoostQueryNodeProcessor)
+	ans.QueryNodeProcessorImpl = NewQueryNodeProcessorImpl(ans)
+	return ans
+}
+
+func (p *BoostQueryNodeProcessor) PreProcessNode(node QueryNode) (QueryNode, error) {
+	return node, nil
+}
+
+func (p *BoostQueryNodeProcessor) PostProcessNode(node QueryNode) (QueryNode, error) {
+	fieldNode, ok := node.(FieldableNode)
+	if !ok {
+		return node, nil
+	}
+	if _, ok := node.Parent().(FieldableNode); ok {
+		return node, nil
+	}
+	fieldConfig := p.QueryConfigHandler().FieldConfig(fieldNode.Field())
+	if boost, ok := fieldConfig.Get(BOOST).(float32); ok {
+		return NewBoostQueryNode(node, boost), nil
+	}
+	return node, nil
+}
+
+func (p *BoostQueryNodeProcessor) SetChildrenOrder(children []QueryNode) ([]QueryNode, error) {
+	return children, nil
+}
@@ -0,0 +1,282 @@
+package flexible
+
+import (
+	"github.com/balzaczyy/golucene/core/analysis"
+	"github.com/balzaczyy/golucene/core/search"
+)
+
+// flexible/core/QueryParserHelper.java
+
+/*
+This type is a helper for the query parser framework, it does all the
+three query parser phases at once: text parsing, query processing and
+query building.
+
+It contains methods that allows the user to change the implementation
+used on the three phases, e.g. to add a processor mapping a field to a
+points range query without rewriting the grammar.
+*/
+type QueryParserHelper struct {
+	config       *QueryConfigHandler
+	syntaxParser SyntaxParser
+	processor    QueryNodeProcessor
+	builder      QueryBuilder
+}
+
+/*
+Creates a query parser helper object using the specified
+configuration, text parser, processor and builder. The processor may
+be nil, then the tree built by the syntax parser is built as is.
+*/
+func NewQueryParserHelper(config *QueryConfigHandler, syntaxParser SyntaxParser,
+	processor QueryNodeProcessor, builder QueryBuilder) *QueryParserHelper {
+
+	if processor != nil {
+		processor.SetQueryConfigHandler(config)
+	}
+	return &QueryParserHelper{config, syntaxParser, processor, builder}
+}
+
+/*
+Parses a query string to an object, usually some query object. It
+parses the string with the syntax parser, processes the tree with the
+processor and builds the query with the builder.
+*/
+func (h *QueryParserHelper) Parse(query, defaultField string) (search.Query, error) {
+	queryTree, err := h.syntaxParser.Parse(query, defaultField)
+	if err != nil {
+		return nil, err
+	}
+	if h.processor != nil {
+		if queryTree, err = h.processor.Process(queryTree); err != nil {
+			return nil, err
+		}
+	}
+	return h.builder.Build(queryTree)
+}
+
+func (h *QueryParserHelper) QueryConfigHandler() *QueryConfigHandler {
+	return h.config
+}
+
+/* Sets the configuration, which is also set on the processor. */
+func (h *QueryParserHelper) SetQueryConfigHandler(config *QueryConfigHandler) {
+	h.config = config
+	if h.processor != nil {
+		h.processor.SetQueryConfigHandler(config)
+	}
+}
+
+func (h *QueryParserHelper) SyntaxParser() SyntaxParser {
+	return h.syntaxParser
+}
+
+func (h *QueryParserHelper) SetSyntaxParser(syntaxParser SyntaxParser) {
+	h.syntaxParser = syntaxParser
+}
+
+/*
+Returns the processor, usually a QueryNodeProcessorPipeline to which
+custom processors can be added.
+*/
+func (h *QueryParserHelper) QueryNodeProcessor() QueryNodeProcessor {
+	return h.processor
+}
+
+/* Sets the processor, which uses the configuration of the helper. */
+func (h *QueryParserHelper) SetQueryNodeProcessor(processor QueryNodeProcessor) {
+	h.processor = processor
+	if processor != nil {
+		processor.SetQueryConfigHandler(h.config)
+	}
+}
+
+func (h *QueryParserHelper) QueryBuilder() QueryBuilder {
+	return h.builder
+}
+
+func (h *QueryParserHelper) SetQueryBuilder(builder QueryBuilder) {
+	h.builder = builder
+}
+
+// flexible/standard/StandardQueryParser.java
+
+/*
+This type is a helper that enables users to easily use the Lucene
+query parser, with the syntax of the classic QueryParser.
+
+To construct a Query object from a query string, use Parse:
+
+	qp := NewStandardQueryParser(analyzer)
+	q, err := qp.Parse("title:foo AND body:bar", "body")
+
+The query is parsed by a StandardSyntaxParser, processed by the
+StandardQueryNodeProcessorPipeline and built by the
+StandardQueryTreeBuilder. Each phase can be customized: processors may
+be added to the pipeline and builders set on the tree builder.
+*/
+type StandardQueryParser struct {
+	*QueryParserHelper
+}
+
+/* Constructs a StandardQueryParser object and sets an Analyzer to it. */
+func NewStandardQueryParser(analyzer analysis.Analyzer) *StandardQueryParser {
+	config := NewQueryConfigHandler()
+	config.AddFieldConfigListener(NewFieldBoostMapFCListener(config))
+	config.Set(ALLOW_LEADING_WILDCARD, false)
+	config.Set(ANALYZER, analyzer)
+	config.Set(PHRASE_SLOP, 0)
+	config.Set(LOWERCASE_EXPANDED_TERMS, true)
+	config.Set(FIELD_BOOST_MAP, make(map[string]float32))
+	config.Set(FUZZY_CONFIG, &FuzzyConfig{
+		PrefixLength:  search.FUZZY_DEFAULT_PREFIX_LENGTH,
+		MinSimilarity: search.FUZZY_DEFAULT_MAX_EDITS,
+	})
+	config.Set(DEFAULT_OPERATOR, OP_OR)
+	config.Set(ENABLE_POSITION_INCREMENTS, true)
+	return &StandardQueryParser{NewQueryParserHelper(config, NewStandardSyntaxParser(),
+		NewStandardQueryNodeProcessorPipeline(config), NewStandardQueryTreeBuilder())}
+}
+
+/*
+Returns the pipeline of the processors, to which custom processors
+can be added. It is nil if the processor was replaced by another type.
+*/
+func (qp *StandardQueryParser) Pipeline() *QueryNodeProcessorPipeline {
+	pipeline, _ := qp.QueryNodeProcessor().(*QueryNodeProcessorPipeline)
+	return pipeline
+}
+
+/*
+Returns the tree builder, on which custom builders can be set. It is
+nil if the builder was replaced by another type.
+*/
+func (qp *StandardQueryParser) TreeBuilder() *QueryTreeBuilder {
+	builder, _ := qp.QueryBuilder().(*QueryTreeBuilder)
+	return builder
+}
+
+/*
+Sets the boolean operator of the QueryParser. In default mode (OP_OR)
+terms without any modifiers are considered optional: for example
+"capital of Hungary" is equal to "capital OR of OR Hungary". In
+OP_AND mode terms are considered to be in conjunction: the
+above mentioned query is parsed as "capital AND of AND Hungary".
+*/
+func (qp *StandardQueryParser) SetDefaultOperator(operator Operator) {
+	qp.config.Set(DEFAULT_OPERATOR, operator)
+}
+
+func (qp *StandardQueryParser) DefaultOperator() Operator {
+	return qp.config.Get(DEFAULT_OPERATOR).(Operator)
+}
+
+/*
+Set to true to allow leading wildcard characters. When set, * or ?
+are allowed as the first character of a PrefixQuery and
+WildcardQuery. Note that this can produce very slow queries on big
+indexes. Default: false.
+*/
+func (qp *StandardQueryParser) SetAllowLeadingWildcard(allow bool) {
+	qp.config.Set(ALLOW_LEADING_WILDCARD, allow)
+}
+
+func (qp *StandardQueryParser) AllowLeadingWildcard() bool {
+	return qp.config.boolOr(ALLOW_LEADING_WILDCARD, false)
+}
+
+/*
+Whether terms of wildcard, prefix, fuzzy, regexp and range queries
+are to be automatically lower-cased or not. Default is true.
+*/
+func (qp *StandardQueryParser) SetLowercaseExpandedTerms(lowercase bool) {
+	qp.config.Set(LOWERCASE_EXPANDED_TERMS, lowercase)
+}
+
+func (qp *StandardQueryParser) LowercaseExpandedTerms() bool {
+	return qp.config.boolOr(LOWERCASE_EXPANDED_TERMS, true)
+}
+
+/*
+Set to true to enable position increments in result query. When set,
+result phrase and multi-phrase queries will be aware of position
+increments. Default: true.
+*/
+func (qp *StandardQueryParser) SetEnablePositionIncrements(enabled bool) {
+	qp.config.Set(ENABLE_POSITION_INCREMENTS, enabled)
+}
+
+func (qp *StandardQueryParser) EnablePositionIncrements() bool {
+	return qp.config.boolOr(ENABLE_POSITION_INCREMENTS, false)
+}
+
+/*
+Sets the fields an unqualified term is expanded to, when no default
+field is given to Parse. Each term is then searched in every field:
+
+	(title:term1 body:term1) (title:term2 body:term2)
+*/
+func (qp *StandardQueryParser) SetMultiFields(fields []string) {
+	qp.config.Set(MULTI_FIELDS, fields)
+}
+
+func (qp *StandardQueryParser) MultiFields() []string {
+	fields, _ := qp.config.Get(MULTI_FIELDS).([]string)
+	return fields
+}
+
+/* Sets the prefix length for fuzzy queries. Default is 0. */
+func (qp *StandardQueryParser) SetFuzzyPrefixLength(length int) {
+	qp.fuzzyConfig().PrefixLength = length
+}
+
+func (qp *StandardQueryParser) FuzzyPrefixLength() int {
+	return qp.fuzzyConfig().PrefixLength
+}
+
+/* Sets the minimal similarity, or edit distance, of fuzzy queries. Default is 2. */
+func (qp *StandardQueryParser) SetFuzzyMinSim(minSim float32) {
+	qp.fuzzyConfig().MinSimilarity = minSim
+}
+
+func (qp *StandardQueryParser) FuzzyMinSim() float32 {
+	return qp.fuzzyConfig().MinSimilarity
+}
+
+func (qp *StandardQueryParser) fuzzyConfig() *FuzzyConfig {
+	fuzzyConfig, ok := qp.config.Get(FUZZY_CONFIG).(*FuzzyConfig)
+	if !ok {
+		fuzzyConfig = new(FuzzyConfig)
+		qp.config.Set(FUZZY_CONFIG, fuzzyConfig)
+	}
+	return fuzzyConfig
+}
+
+/* Sets the default slop for phrases. If zero, then exact phrase matches are required. */
+func (qp *StandardQueryParser) SetPhraseSlop(slop int) {
+	qp.config.Set(PHRASE_SLOP, slop)
+}
+
+func (qp *StandardQueryParser) PhraseSlop() int {
+	slop, _ := qp.config.Get(PHRASE_SLOP).(int)
+	return slop
+}
+
+/* Sets the boost used for each field. */
+func (qp *StandardQueryParser) SetFieldsBoost(boosts map[string]float32) {
+	qp.config.Set(FIELD_BOOST_MAP, boosts)
+}
+
+func (qp *StandardQueryParser) FieldsBoost() map[string]float32 {
+	boosts, _ := qp.config.Get(FIELD_BOOST_MAP).(map[string]float32)
+	return boosts
+}
+
+func (qp *StandardQueryParser) SetAnalyzer(analyzer analysis.Analyzer) {
+	qp.config.Set(ANALYZER, analyzer)
+}
+
+func (qp *StandardQueryParser) Analyzer() analysis.Analyzer {
+	analyzer, _ := qp.config.Get(ANALYZER).(analysis.Analyzer)
+	return analyzer
+}
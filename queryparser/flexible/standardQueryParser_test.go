@@ -0,0 +1,309 @@
+package flexible
+
+import (
+	"github.com/balzaczyy/golucene/analysis/custom"
+	std "github.com/balzaczyy/golucene/analysis/standard"
+	"github.com/balzaczyy/golucene/core/search"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+/* Returns a parser analyzing without stop words. */
+func newTestParser(t *testing.T) *StandardQueryParser {
+	a, err := custom.NewCustomAnalyzerBuilder().
+		WithTokenizer("standard").
+		AddTokenFilter("lowercase").
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewStandardQueryParser(a)
+}
+
+func assertQueryEquals(t *testing.T, qp *StandardQueryParser, query, expected string) {
+	q, err := qp.Parse(query, "field")
+	if err != nil {
+		t.Errorf("%v: %v", query, err)
+		return
+	}
+	if got := q.ToString("field"); got != expected {
+		t.Errorf("Query /%v/ yielded /%v/, expecting /%v/", query, got, expected)
+	}
+}
+
+func TestSimple(t *testing.T) {
+	qp := newTestParser(t)
+	for _, c := range [][2]string{
+		{"term term term", "term term term"},
+		{"Türm term term", "türm term term"},
+		{"a AND b", "+a +b"},
+		{"(a AND b)", "+a +b"},
+		{"c OR (a AND b)", "c (+a +b)"},
+		{"a AND NOT b", "+a -b"},
+		{"a AND -b", "+a -b"},
+		{"a && !b", "+a -b"},
+		{"a OR b", "a b"},
+		{"a || b", "a b"},
+		{"a AND b OR c", "(+a +b) c"},
+		{"+term -term term", "+term -term term"},
+		{"foo:term AND field:anotherTerm", "+foo:term +anotherterm"},
+		{"term AND \"phrase phrase\"", "+term +\"phrase phrase\""},
+		{"\"hello there\"", "\"hello there\""},
+		{"germ term^2.0", "germ term^2"},
+		{"(term)^2.0", "term^2"},
+		{"(germ term)^2.0", "(germ term)^2"},
+		{"\"term germ\"^2", "\"term germ\"^2"},
+		{"(foo OR bar) AND (baz OR boo)", "+(foo bar) +(baz boo)"},
+		{"+title:(dog OR cat) -author:\"bob dole\"", "+(title:dog title:cat) -author:\"bob dole\""},
+		{"a + b", "a b"},
+		{"foo-bar", "foo bar"},
+		{"", ""},
+	} {
+		assertQueryEquals(t, qp, c[0], c[1])
+	}
+
+	// stop words are removed by the analyzer
+	qp = NewStandardQueryParser(std.NewStandardAnalyzer())
+	assertQueryEquals(t, qp, "the foo", "foo")
+	assertQueryEquals(t, qp, "the", "")
+	assertQueryEquals(t, qp, "(the)^2 foo", "foo")
+	assertQueryEquals(t, qp, "+the -foo", "-foo")
+}
+
+func TestPhrase(t *testing.T) {
+	qp := newTestParser(t)
+	assertQueryEquals(t, qp, "\"term germ\"~2", "\"term germ\"~2")
+	assertQueryEquals(t, qp, "\"term germ\"~2 flork", "\"term germ\"~2 flork")
+	assertQueryEquals(t, qp, "\"term\"~2", "term")
+	assertQueryEquals(t, qp, "\"term germ\"~2^2", "\"term germ\"~2^2")
+
+	qp.SetPhraseSlop(3)
+	assertQueryEquals(t, qp, "\"term germ\"", "\"term germ\"~3")
+	assertQueryEquals(t, qp, "\"term germ\"~1", "\"term germ\"~1")
+
+	qp = NewStandardQueryParser(std.NewStandardAnalyzer())
+	assertQueryEquals(t, qp, "\"the term the germ\"", "\"? term ? germ\"")
+	qp.SetEnablePositionIncrements(false)
+	assertQueryEquals(t, qp, "\"the term the germ\"", "\"term germ\"")
+
+	a, err := custom.NewCustomAnalyzerBuilder().
+		WithTokenizer("standard").
+		AddTokenFilter("typeAsSynonym", "prefix", "_").
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	qp = NewStandardQueryParser(a)
+	assertQueryEquals(t, qp, "\"foo bar\"",
+		"\"foo bar\" \"foo _<ALPHANUM>\" \"_<ALPHANUM> bar\" \"_<ALPHANUM> _<ALPHANUM>\"")
+	assertQueryEquals(t, qp, "\"foo bar\"~2",
+		"\"foo bar\"~2 \"foo _<ALPHANUM>\"~2 \"_<ALPHANUM> bar\"~2 \"_<ALPHANUM> _<ALPHANUM>\"~2")
+	assertQueryEquals(t, qp, "foo", "Synonym(_<ALPHANUM> foo)")
+}
+
+func TestWildcard(t *testing.T) {
+	qp := newTestParser(t)
+	for _, c := range [][2]string{
+		{"term*", "term*"},
+		{"term*^2", "term*^2"},
+		{"Term*", "term*"},
+		{"term~", "term~2"},
+		{"term~1", "term~1"},
+		{"term~0.7", "term~1"},
+		{"term~^3", "term~2^3"},
+		{"term^3~", "term~2^3"},
+		{"Te?m*gerM", "te?m*germ"},
+		{"*:*", "*:*"},
+		{"/[A-Z][123]/^0.5", "/[a-z][123]/^0.5"},
+		{"title:/fo+/", "title:/fo+/"},
+		{"\\a*", "a*"},
+	} {
+		assertQueryEquals(t, qp, c[0], c[1])
+	}
+
+	for _, query := range []string{"*Term", "?Term"} {
+		if _, err := qp.Parse(query, "field"); err == nil || !strings.HasPrefix(err.Error(), "Leading wildcard is not allowed") {
+			t.Errorf("%v: expected a leading wildcard error, but was %v", query, err)
+		}
+	}
+	qp.SetAllowLeadingWildcard(true)
+	assertQueryEquals(t, qp, "*Term", "*term")
+
+	qp.SetLowercaseExpandedTerms(false)
+	assertQueryEquals(t, qp, "Term*", "Term*")
+
+	qp.SetFuzzyPrefixLength(2)
+	qp.SetFuzzyMinSim(1)
+	if q, err := qp.Parse("term~", "field"); err != nil {
+		t.Error(err)
+	} else if fq := q.(*search.FuzzyQuery); fq.MaxEdits() != 1 || fq.PrefixLength() != 2 {
+		t.Errorf("unexpected fuzzy query settings: %v, %v", fq.MaxEdits(), fq.PrefixLength())
+	}
+}
+
+func TestRange(t *testing.T) {
+	qp := newTestParser(t)
+	for _, c := range [][2]string{
+		{"[ a TO z]", "[a TO z]"},
+		{"[ a TO z }", "[a TO z}"},
+		{"{ a TO z]", "{a TO z]"},
+		{"[ a z]", "[a TO z]"},
+		{"[ * TO z]", "[* TO z]"},
+		{"[ A TO Z]^2.0", "[a TO z]^2"},
+		{"gack ( bar blar { a TO z}) ", "gack (bar blar {a TO z})"},
+		{"[\"a b\" TO \"c d\"]", "[a b TO c d]"},
+		{"title:[a TO z] foo", "title:[a TO z] foo"},
+	} {
+		assertQueryEquals(t, qp, c[0], c[1])
+	}
+}
+
+func TestDefaultOperator(t *testing.T) {
+	qp := newTestParser(t)
+	qp.SetDefaultOperator(OP_AND)
+	if qp.DefaultOperator() != OP_AND {
+		t.Error("expected OP_AND")
+	}
+	for _, c := range [][2]string{
+		{"a b", "+a +b"},
+		{"a OR b", "a b"},
+		{"a -b", "+a -b"},
+		{"(a b) c", "+(+a +b) +c"},
+		{"foo-bar baz", "+(+foo +bar) +baz"},
+	} {
+		assertQueryEquals(t, qp, c[0], c[1])
+	}
+}
+
+func TestMultiFields(t *testing.T) {
+	qp := NewStandardQueryParser(std.NewStandardAnalyzer())
+	qp.SetMultiFields([]string{"b", "t"})
+	assertMultiFields := func(query, expected string) {
+		q, err := qp.Parse(query, "")
+		if err != nil {
+			t.Errorf("%v: %v", query, err)
+		} else if got := q.ToString(""); got != expected {
+			t.Errorf("Query /%v/ yielded /%v/, expecting /%v/", query, got, expected)
+		}
+	}
+	assertMultiFields("one", "b:one t:one")
+	assertMultiFields("one two", "(b:one t:one) (b:two t:two)")
+	assertMultiFields("+one -two", "+(b:one t:one) -(b:two t:two)")
+	assertMultiFields("one* z:two", "(b:one* t:one*) z:two")
+	assertMultiFields("\"foo bar\"", "b:\"foo bar\" t:\"foo bar\"")
+	assertMultiFields("the one", "b:one t:one")
+
+	qp.SetFieldsBoost(map[string]float32{"b": 5, "t": 10})
+	assertMultiFields("one", "b:one^5 t:one^10")
+	assertMultiFields("\"one two\"", "b:\"one two\"^5 t:\"one two\"^10")
+}
+
+func TestParseErrors(t *testing.T) {
+	qp := newTestParser(t)
+	for _, c := range [][2]string{
+		{"foo:", `Syntax Error, cannot parse foo:: Encountered "<EOF>" at column 5.`},
+		{"\"foo", `Syntax Error, cannot parse "foo: Lexical error at column 5. Encountered: <EOF>`},
+		{"foo)", `Syntax Error, cannot parse foo): Encountered ")" at column 4.`},
+		{"foo^", `Syntax Error, cannot parse foo^: Lexical error at column 5.`},
+		{"[a TO b", `Syntax Error, cannot parse [a TO b: Lexical error at column 8.`},
+		{"foo~1.5", `Syntax Error, cannot parse foo~1.5: Fractional edit distances are not allowed!`},
+		{"a ]", `Syntax Error, cannot parse a ]: Lexical error at column 3. Encountered: ']'`},
+		{"XY\\", `Syntax Error, cannot parse XY\: Lexical error at column 3.`},
+		{"a\\u004g", `Syntax Error, cannot parse a\u004g: Non-hex character`},
+	} {
+		_, err := qp.Parse(c[0], "field")
+		if err == nil {
+			t.Errorf("%v: expected an error", c[0])
+		} else if !strings.HasPrefix(err.Error(), c[1]) {
+			t.Errorf("%v: expected error %v, but was %v", c[0], c[1], err)
+		}
+	}
+	if _, err := qp.Parse("/[a/", "field"); err == nil {
+		t.Error("expected an error for an invalid regular expression")
+	}
+	if _, err := qp.Parse("foo", ""); err == nil {
+		t.Error("expected an error without default field nor multi fields")
+	}
+}
+
+/* Renames the fields of the nodes, like an alias. */
+type fieldAliasProcessor struct {
+	*QueryNodeProcessorImpl
+	aliases map[string]string
+}
+
+func newFieldAliasProcessor(aliases map[string]string) *fieldAliasProcessor {
+	ans := &fieldAliasProcessor{aliases: aliases}
+	ans.QueryNodeProcessorImpl = NewQueryNodeProcessorImpl(ans)
+	return ans
+}
+
+func (p *fieldAliasProcessor) PreProcessNode(node QueryNode) (QueryNode, error) {
+	if fieldNode, ok := node.(FieldableNode); ok {
+		if field, ok := p.aliases[fieldNode.Field()]; ok {
+			fieldNode.SetField(field)
+		}
+	}
+	return node, nil
+}
+
+func (p *fieldAliasProcessor) PostProcessNode(node QueryNode) (QueryNode, error) {
+	return node, nil
+}
+
+func (p *fieldAliasProcessor) SetChildrenOrder(children []QueryNode) ([]QueryNode, error) {
+	return children, nil
+}
+
+/* Builds a numeric range query of the bounds of a term range. */
+func buildPriceQuery(queryNode QueryNode) (search.Query, error) {
+	rangeNode := queryNode.(*TermRangeQueryNode)
+	bound := func(s string, open, exclusiveDelta int64) (int64, error) {
+		if s == "" {
+			return open, nil
+		}
+		n, err := strconv.ParseInt(s, 10, 64)
+		return n + exclusiveDelta, err
+	}
+	var lowerDelta, upperDelta int64
+	if !rangeNode.IsLowerInclusive() {
+		lowerDelta = 1
+	}
+	if !rangeNode.IsUpperInclusive() {
+		upperDelta = -1
+	}
+	min, err := bound(rangeNode.LowerBound(), math.MinInt64, lowerDelta)
+	if err != nil {
+		return nil, err
+	}
+	max, err := bound(rangeNode.UpperBound(), math.MaxInt64, upperDelta)
+	if err != nil {
+		return nil, err
+	}
+	return search.NewLongRangeQuery(rangeNode.Field(), search.RANGE_QUERY_WITHIN, []int64{min}, []int64{max}), nil
+}
+
+func TestCustomProcessorAndBuilder(t *testing.T) {
+	qp := newTestParser(t)
+	qp.Pipeline().Add(newFieldAliasProcessor(map[string]string{"cost": "price"}))
+	qp.TreeBuilder().SetFieldBuilder("price", QueryBuilderFunc(buildPriceQuery))
+
+	q, err := qp.Parse("foo AND cost:{10 TO 20]", "field")
+	if err != nil {
+		t.Fatal(err)
+	}
+	clauses := q.(*search.BooleanQuery).Clauses()
+	if len(clauses) != 2 {
+		t.Fatalf("expected 2 clauses, but was %v", q.ToString("field"))
+	}
+	rq, ok := clauses[1].Query().(*search.RangeFieldQuery)
+	if !ok || rq.Field() != "price" || rq.QueryType() != search.RANGE_QUERY_WITHIN || clauses[1].Occur() != search.MUST {
+		t.Errorf("expected a required range query on price, but was %v", q.ToString("field"))
+	}
+
+	if _, err = qp.Parse("price:[a TO 20]", "field"); err == nil {
+		t.Error("expected an error for an invalid price")
+	}
+}
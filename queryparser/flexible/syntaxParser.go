@@ -0,0 +1,696 @@
+package flexible
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// flexible/core/parser/SyntaxParser.java
+
+/* A parser of query strings into query node trees. */
+type SyntaxParser interface {
+	// Parses the query, unqualified terms being on the given field.
+	Parse(query, field string) (QueryNode, error)
+}
+
+// flexible/core/QueryNodeParseException.java
+
+/* This error is returned when a query string can't be parsed. */
+type QueryNodeParseError struct {
+	query   string
+	column  int
+	message string
+}
+
+func (e *QueryNodeParseError) Error() string {
+	return fmt.Sprintf("Syntax Error, cannot parse %v: %v", e.query, e.message)
+}
+
+/* Returns the 1-based column where the error was found. */
+func (e *QueryNodeParseError) Column() int {
+	return e.column
+}
+
+// flexible/standard/parser/StandardSyntaxParserConstants.java
+
+type tokenKind int
+
+const (
+	tokEOF = tokenKind(iota)
+	tokAnd
+	tokOr
+	tokNot
+	tokPlus
+	tokMinus
+	tokLParen
+	tokRParen
+	tokColon
+	tokCarat
+	tokQuoted
+	tokTerm
+	tokStar
+	tokPrefixTerm
+	tokWildTerm
+	tokRegexpTerm
+	tokFuzzySlop
+	tokRange
+)
+
+var tokenImage = [...]string{
+	"<EOF>", "<AND>", "<OR>", "<NOT>", "\"+\"", "\"-\"", "\"(\"", "\")\"", "\":\"", "\"^\"",
+	"<QUOTED>", "<TERM>", "\"*\"", "<PREFIXTERM>", "<WILDTERM>", "<REGEXPTERM>", "<FUZZY_SLOP>", "<RANGE>",
+}
+
+type token struct {
+	kind  tokenKind
+	image string
+	begin int // position of the first rune
+	// the bounds of a range, unescaped, and whether they are inclusive
+	lower, upper                   string
+	lowerInclusive, upperInclusive bool
+}
+
+// flexible/standard/parser/StandardSyntaxParser.java
+
+/*
+Parser for the standard Lucene syntax, producing a query node tree:
+
+	Query     ::= ( DisjQuery )*
+	DisjQuery ::= ConjQuery ( OR ConjQuery )*
+	ConjQuery ::= ModClause ( AND ModClause )*
+	ModClause ::= [ "+" | "-" | NOT ] Clause
+	Clause    ::= [ Term ":" ] ( Term | "(" Query ")" [ "^" Number ] )
+
+Unlike the classic QueryParser, AND binds tighter than OR: "a AND b OR
+c" is (a AND b) OR c. Terms are not analyzed, it is the job of the
+processors, but wildcards and escaped chars are recognized: the text of
+wildcard nodes keeps its escape chars.
+*/
+type StandardSyntaxParser struct {
+	input  []rune
+	pos    int
+	tokens []token // look ahead
+}
+
+func NewStandardSyntaxParser() *StandardSyntaxParser {
+	return new(StandardSyntaxParser)
+}
+
+/* Parses a query string, returning a query node tree. */
+func (p *StandardSyntaxParser) Parse(query, field string) (QueryNode, error) {
+	p.input, p.pos, p.tokens = []rune(query), 0, nil
+	q, err := p.topLevelQuery(field)
+	if err != nil {
+		if perr, ok := err.(*QueryNodeParseError); ok {
+			perr.query = query
+		}
+		return nil, err
+	}
+	return q, nil
+}
+
+func (p *StandardSyntaxParser) topLevelQuery(field string) (QueryNode, error) {
+	if t, err := p.peek(0); err != nil {
+		return nil, err
+	} else if t.kind == tokEOF {
+		return NewMatchNoDocsQueryNode(), nil
+	}
+	q, err := p.query(field)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = p.consume(tokEOF); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+func isClauseStart(kind tokenKind) bool {
+	switch kind {
+	case tokNot, tokPlus, tokMinus, tokLParen, tokQuoted, tokTerm, tokStar,
+		tokPrefixTerm, tokWildTerm, tokRegexpTerm, tokRange:
+		return true
+	}
+	return false
+}
+
+func (p *StandardSyntaxParser) query(field string) (QueryNode, error) {
+	var clauses []QueryNode
+	for {
+		q, err := p.disjQuery(field)
+		if err != nil {
+			return nil, err
+		}
+		clauses = append(clauses, q)
+		t, err := p.peek(0)
+		if err != nil {
+			return nil, err
+		}
+		if !isClauseStart(t.kind) {
+			break
+		}
+	}
+	if len(clauses) == 1 {
+		return clauses[0], nil
+	}
+	return NewBooleanQueryNode(clauses), nil
+}
+
+func (p *StandardSyntaxParser) disjQuery(field string) (QueryNode, error) {
+	clauses, err := p.operands(tokOr, func() (QueryNode, error) { return p.conjQuery(field) })
+	if err != nil || len(clauses) == 1 {
+		return first(clauses), err
+	}
+	return NewOrQueryNode(clauses), nil
+}
+
+func (p *StandardSyntaxParser) conjQuery(field string) (QueryNode, error) {
+	clauses, err := p.operands(tokAnd, func() (QueryNode, error) { return p.modClause(field) })
+	if err != nil || len(clauses) == 1 {
+		return first(clauses), err
+	}
+	return NewAndQueryNode(clauses), nil
+}
+
+func first(nodes []QueryNode) QueryNode {
+	if len(nodes) == 0 {
+		return nil
+	}
+	return nodes[0]
+}
+
+/* Parses operands separated by the operator. */
+func (p *StandardSyntaxParser) operands(operator tokenKind, operand func() (QueryNode, error)) ([]QueryNode, error) {
+	var ans []QueryNode
+	for {
+		q, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		ans = append(ans, q)
+		if ok, err := p.optional(operator); err != nil {
+			return nil, err
+		} else if !ok {
+			return ans, nil
+		}
+	}
+}
+
+func (p *StandardSyntaxParser) modClause(field string) (QueryNode, error) {
+	t, err := p.peek(0)
+	if err != nil {
+		return nil, err
+	}
+	mod := MOD_NONE
+	switch t.kind {
+	case tokPlus:
+		mod = MOD_REQ
+	case tokMinus, tokNot:
+		mod = MOD_NOT
+	}
+	if mod != MOD_NONE {
+		p.next()
+	}
+	q, err := p.clause(field)
+	if err != nil {
+		return nil, err
+	}
+	if mod != MOD_NONE {
+		return NewModifierQueryNode(q, mod), nil
+	}
+	return q, nil
+}
+
+func (p *StandardSyntaxParser) clause(field string) (QueryNode, error) {
+	t, err := p.peek(0)
+	if err != nil {
+		return nil, err
+	}
+	if t.kind == tokTerm || t.kind == tokStar {
+		if t2, err := p.peek(1); err != nil {
+			return nil, err
+		} else if t2.kind == tokColon {
+			p.next()
+			p.next()
+			if field, err = discardEscapeChar(t.image); err != nil {
+				return nil, p.errorAt(t, err.Error())
+			}
+		}
+	}
+
+	if ok, err := p.optional(tokLParen); err != nil {
+		return nil, err
+	} else if !ok {
+		return p.term(field)
+	}
+	q, err := p.query(field)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = p.consume(tokRParen); err != nil {
+		return nil, err
+	}
+	return p.optionalBoost(NewGroupQueryNode(q))
+}
+
+func (p *StandardSyntaxParser) term(field string) (QueryNode, error) {
+	t, err := p.consume(tokTerm, tokStar, tokPrefixTerm, tokWildTerm, tokRegexpTerm, tokRange, tokQuoted)
+	if err != nil {
+		return nil, err
+	}
+	switch t.kind {
+	case tokStar, tokWildTerm:
+		return p.optionalBoost(NewWildcardQueryNode(field, t.image))
+	case tokPrefixTerm:
+		return p.optionalBoost(NewPrefixWildcardQueryNode(field, t.image))
+	case tokRegexpTerm:
+		return p.optionalBoost(NewRegexpQueryNode(field, t.image[1:len(t.image)-1]))
+	case tokRange:
+		return p.optionalBoost(NewTermRangeQueryNode(field, t.lower, t.upper, t.lowerInclusive, t.upperInclusive))
+	case tokQuoted:
+		return p.quotedTerm(field, t)
+	}
+
+	// "term", "term~", "term^2", "term~^2", "term^2~"
+	text, err := discardEscapeChar(t.image)
+	if err != nil {
+		return nil, p.errorAt(t, err.Error())
+	}
+	fuzzySlop, err := p.optionalToken(tokFuzzySlop)
+	if err != nil {
+		return nil, err
+	}
+	boost, err := p.optionalToken(tokCarat)
+	if err != nil {
+		return nil, err
+	}
+	var boostValue float32
+	if boost != nil {
+		if boostValue, err = p.number(); err != nil {
+			return nil, err
+		}
+		if fuzzySlop == nil {
+			if fuzzySlop, err = p.optionalToken(tokFuzzySlop); err != nil {
+				return nil, err
+			}
+		}
+	}
+	var q QueryNode = NewFieldQueryNode(field, text)
+	if fuzzySlop != nil {
+		minSimilarity := float32(-1) // the default of the configuration
+		if len(fuzzySlop.image) > 1 {
+			f, _ := strconv.ParseFloat(fuzzySlop.image[1:], 32)
+			if minSimilarity = float32(f); minSimilarity >= 1 && minSimilarity != float32(int(minSimilarity)) {
+				return nil, p.errorAt(fuzzySlop, "Fractional edit distances are not allowed!")
+			}
+		}
+		q = NewFuzzyQueryNode(field, text, minSimilarity)
+	}
+	if boost != nil {
+		q = NewBoostQueryNode(q, boostValue)
+	}
+	return q, nil
+}
+
+func (p *StandardSyntaxParser) quotedTerm(field string, t *token) (QueryNode, error) {
+	text, err := discardEscapeChar(t.image[1 : len(t.image)-1])
+	if err != nil {
+		return nil, p.errorAt(t, err.Error())
+	}
+	var q QueryNode = NewQuotedFieldQueryNode(field, text)
+	fuzzySlop, err := p.optionalToken(tokFuzzySlop)
+	if err != nil {
+		return nil, err
+	}
+	if fuzzySlop != nil && len(fuzzySlop.image) > 1 {
+		f, _ := strconv.ParseFloat(fuzzySlop.image[1:], 32)
+		q = NewSlopQueryNode(q, int(f))
+	}
+	return p.optionalBoost(q)
+}
+
+func (p *StandardSyntaxParser) optionalBoost(q QueryNode) (QueryNode, error) {
+	if ok, err := p.optional(tokCarat); err != nil || !ok {
+		return q, err
+	}
+	boost, err := p.number()
+	if err != nil {
+		return nil, err
+	}
+	return NewBoostQueryNode(q, boost), nil
+}
+
+/* Parses the number which immediately follows a "^". */
+func (p *StandardSyntaxParser) number() (float32, error) {
+	begin := p.pos
+	end := p.scanNumber(begin)
+	if end == begin {
+		return 0, p.lexicalError(begin)
+	}
+	p.pos = end
+	f, _ := strconv.ParseFloat(string(p.input[begin:end]), 32)
+	return float32(f), nil
+}
+
+/* Returns the position after the digits and optional fraction starting at begin. */
+func (p *StandardSyntaxParser) scanNumber(begin int) int {
+	isDigit := func(i int) bool {
+		return i < len(p.input) && p.input[i] >= '0' && p.input[i] <= '9'
+	}
+	i := begin
+	for isDigit(i) {
+		i++
+	}
+	if i > begin && i < len(p.input) && p.input[i] == '.' && isDigit(i+1) {
+		for i++; isDigit(i); i++ {
+		}
+	}
+	return i
+}
+
+func (p *StandardSyntaxParser) peek(i int) (*token, error) {
+	for len(p.tokens) <= i {
+		t, err := p.nextToken()
+		if err != nil {
+			return nil, err
+		}
+		p.tokens = append(p.tokens, t)
+	}
+	return &p.tokens[i], nil
+}
+
+/* Consumes the peeked token. */
+func (p *StandardSyntaxParser) next() *token {
+	t := &p.tokens[0]
+	p.tokens = p.tokens[1:]
+	return t
+}
+
+/* Consumes the next token, which must be of one of the kinds. */
+func (p *StandardSyntaxParser) consume(kinds ...tokenKind) (*token, error) {
+	t, err := p.peek(0)
+	if err != nil {
+		return nil, err
+	}
+	for _, kind := range kinds {
+		if t.kind == kind {
+			return p.next(), nil
+		}
+	}
+	expected := make([]string, len(kinds))
+	for i, kind := range kinds {
+		expected[i] = tokenImage[kind]
+	}
+	image := t.image
+	if t.kind == tokEOF {
+		image = tokenImage[tokEOF]
+	}
+	return nil, p.errorAt(t, fmt.Sprintf("Encountered %q at column %v. Was expecting one of: %v",
+		image, t.begin+1, strings.Join(expected, ", ")))
+}
+
+/* Consumes the next token if it is of the kind. */
+func (p *StandardSyntaxParser) optionalToken(kind tokenKind) (*token, error) {
+	t, err := p.peek(0)
+	if err != nil || t.kind != kind {
+		return nil, err
+	}
+	return p.next(), nil
+}
+
+func (p *StandardSyntaxParser) optional(kind tokenKind) (bool, error) {
+	t, err := p.optionalToken(kind)
+	return t != nil, err
+}
+
+func (p *StandardSyntaxParser) errorAt(t *token, message string) error {
+	return &QueryNodeParseError{column: t.begin + 1, message: message}
+}
+
+func (p *StandardSyntaxParser) lexicalError(pos int) error {
+	encountered := "<EOF>"
+	if pos < len(p.input) {
+		encountered = strconv.QuoteRune(p.input[pos])
+	}
+	return &QueryNodeParseError{
+		column:  pos + 1,
+		message: fmt.Sprintf("Lexical error at column %v. Encountered: %v", pos+1, encountered),
+	}
+}
+
+// flexible/standard/parser/StandardSyntaxParserTokenManager.java
+
+func isWhitespace(c rune) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '　'
+}
+
+func isTermStartChar(c rune) bool {
+	return !isWhitespace(c) && !strings.ContainsRune("+-!():^[]\"{}~*?\\/", c)
+}
+
+func isTermChar(c rune) bool {
+	return isTermStartChar(c) || c == '-' || c == '+'
+}
+
+/*
+Reads the next token of the input. The tokens following a "^" are
+read by number(), as no whitespace is allowed there, and a range is a
+single token holding its bounds.
+*/
+func (p *StandardSyntaxParser) nextToken() (token, error) {
+	for p.pos < len(p.input) && isWhitespace(p.input[p.pos]) {
+		p.pos++
+	}
+	begin := p.pos
+	if begin == len(p.input) {
+		return token{kind: tokEOF, begin: begin}, nil
+	}
+	single := func(kind tokenKind) (token, error) {
+		p.pos++
+		return token{kind: kind, image: string(p.input[begin]), begin: begin}, nil
+	}
+	switch c := p.input[begin]; c {
+	case '(':
+		return single(tokLParen)
+	case ')':
+		return single(tokRParen)
+	case ':':
+		return single(tokColon)
+	case '^':
+		return single(tokCarat)
+	case '+', '-', '!':
+		if begin+1 < len(p.input) && isWhitespace(p.input[begin+1]) {
+			// a bare operator is a term, which the analyzer likely drops
+			return single(tokTerm)
+		}
+		return single(map[rune]tokenKind{'+': tokPlus, '-': tokMinus, '!': tokNot}[c])
+	case '"':
+		return p.scanDelimited(begin, '"', tokQuoted)
+	case '/':
+		return p.scanDelimited(begin, '/', tokRegexpTerm)
+	case '~':
+		p.pos = p.scanNumber(begin + 1)
+		return token{kind: tokFuzzySlop, image: string(p.input[begin:p.pos]), begin: begin}, nil
+	case '[', '{':
+		return p.scanRange(begin)
+	}
+	return p.scanTermLike(begin)
+}
+
+/* Reads a QUOTED or REGEXPTERM token, where escaped delimiters do not end it. */
+func (p *StandardSyntaxParser) scanDelimited(begin int, delimiter rune, kind tokenKind) (token, error) {
+	for i := begin + 1; i < len(p.input); i++ {
+		switch p.input[i] {
+		case delimiter:
+			p.pos = i + 1
+			return token{kind: kind, image: string(p.input[begin:p.pos]), begin: begin}, nil
+		case '\\':
+			i++
+		}
+	}
+	return token{}, p.lexicalError(len(p.input))
+}
+
+/* Reads a TERM, PREFIXTERM, STAR, WILDTERM or one of the AND, OR, NOT keywords. */
+func (p *StandardSyntaxParser) scanTermLike(begin int) (token, error) {
+	var wildcards int
+	var firstIsWild, lastIsStar, hasQuestion bool
+	i := begin
+	for ; i < len(p.input); i++ {
+		c := p.input[i]
+		if c == '\\' {
+			if i+1 == len(p.input) {
+				break // an escape char can not end a term
+			}
+			i++
+			lastIsStar = false
+		} else if c == '*' || c == '?' {
+			if i == begin {
+				firstIsWild = true
+			}
+			wildcards++
+			lastIsStar = c == '*'
+			hasQuestion = hasQuestion || c == '?'
+		} else if i == begin && isTermStartChar(c) || i > begin && isTermChar(c) {
+			lastIsStar = false
+		} else {
+			break
+		}
+	}
+	if i == begin {
+		return token{}, p.lexicalError(begin)
+	}
+	p.pos = i
+	image := string(p.input[begin:i])
+	kind := tokWildTerm
+	switch {
+	case image == "*":
+		kind = tokStar
+	case wildcards == 0:
+		switch image {
+		case "AND", "&&":
+			kind = tokAnd
+		case "OR", "||":
+			kind = tokOr
+		case "NOT":
+			kind = tokNot
+		default:
+			kind = tokTerm
+		}
+	case wildcards == 1 && lastIsStar && !firstIsWild && !hasQuestion:
+		kind = tokPrefixTerm
+	}
+	return token{kind: kind, image: image, begin: begin}, nil
+}
+
+/*
+Reads a range, like "[a TO z}", as a single token. A bound is either
+quoted or a sequence of chars other than whitespace, "]" and "}", and
+"*" is an open bound. The "TO" between the bounds may be omitted.
+*/
+func (p *StandardSyntaxParser) scanRange(begin int) (token, error) {
+	t := token{kind: tokRange, begin: begin, lowerInclusive: p.input[begin] == '['}
+	p.pos = begin + 1
+	bound := func() (string, bool, error) {
+		for p.pos < len(p.input) && isWhitespace(p.input[p.pos]) {
+			p.pos++
+		}
+		start := p.pos
+		if start == len(p.input) {
+			return "", false, p.lexicalError(start)
+		}
+		if p.input[start] == '"' {
+			quoted, err := p.scanDelimited(start, '"', tokQuoted)
+			if err != nil {
+				return "", false, err
+			}
+			s, err := discardEscapeChar(quoted.image[1 : len(quoted.image)-1])
+			return s, false, err
+		}
+		for p.pos < len(p.input) && !isWhitespace(p.input[p.pos]) &&
+			p.input[p.pos] != ']' && p.input[p.pos] != '}' {
+			p.pos++
+		}
+		if p.pos == start {
+			return "", false, p.lexicalError(start)
+		}
+		if goop := string(p.input[start:p.pos]); goop == "*" {
+			return "", false, nil // open
+		} else {
+			s, err := discardEscapeChar(goop)
+			return s, goop == "TO", err
+		}
+	}
+	var isTo bool
+	var err error
+	if t.lower, _, err = bound(); err != nil {
+		return token{}, err
+	}
+	if t.upper, isTo, err = bound(); err != nil {
+		return token{}, err
+	}
+	if isTo {
+		// "TO" unless it is the upper bound itself, as in "[a TO]"
+		save := p.pos
+		if upper, _, err := bound(); err == nil {
+			t.upper = upper
+		} else {
+			p.pos = save
+		}
+	}
+	for p.pos < len(p.input) && isWhitespace(p.input[p.pos]) {
+		p.pos++
+	}
+	if p.pos == len(p.input) || p.input[p.pos] != ']' && p.input[p.pos] != '}' {
+		return token{}, p.lexicalError(p.pos)
+	}
+	t.upperInclusive = p.input[p.pos] == ']'
+	p.pos++
+	t.image = string(p.input[begin:p.pos])
+	return t, nil
+}
+
+// flexible/standard/parser/EscapeQuerySyntaxImpl.java
+
+/*
+Returns a string where the escape char has been removed, or kept only
+once if there was a double escape.
+
+Supports escaped unicode characters, e.g. translates \u0041 to A.
+*/
+func discardEscapeChar(input string) (string, error) {
+	output := make([]rune, 0, len(input))
+	lastCharWasEscapeChar := false
+	codePointMultiplier := 0
+	codePoint := 0
+
+	for _, curChar := range input {
+		if codePointMultiplier > 0 {
+			n, err := hexToInt(curChar)
+			if err != nil {
+				return "", err
+			}
+			codePoint += n * codePointMultiplier
+			if codePointMultiplier >>= 4; codePointMultiplier == 0 {
+				output = append(output, rune(codePoint))
+				codePoint = 0
+			}
+		} else if lastCharWasEscapeChar {
+			if curChar == 'u' {
+				// found an escaped unicode character
+				codePointMultiplier = 16 * 16 * 16
+			} else {
+				// this character was escaped
+				output = append(output, curChar)
+			}
+			lastCharWasEscapeChar = false
+		} else if curChar == '\\' {
+			lastCharWasEscapeChar = true
+		} else {
+			output = append(output, curChar)
+		}
+	}
+
+	if codePointMultiplier > 0 {
+		return "", errors.New("Truncated unicode escape sequence.")
+	}
+	if lastCharWasEscapeChar {
+		return "", errors.New("Term can not end with escape character.")
+	}
+	return string(output), nil
+}
+
+// Returns the numeric value of the hexadecimal character
+func hexToInt(c rune) (int, error) {
+	switch {
+	case '0' <= c && c <= '9':
+		return int(c - '0'), nil
+	case 'a' <= c && c <= 'f':
+		return int(c - 'a' + 10), nil
+	case 'A' <= c && c <= 'F':
+		return int(c - 'A' + 10), nil
+	}
+	return 0, fmt.Errorf("Non-hex character in Unicode escape sequence: %c", c)
+}
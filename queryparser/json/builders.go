@@ -0,0 +1,384 @@
+package json
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/balzaczyy/golucene/core/analysis"
+	ta "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"github.com/balzaczyy/golucene/core/index"
+	"github.com/balzaczyy/golucene/core/search"
+	"strings"
+)
+
+/* Sets the boost, if any, on the query. */
+func boost(q search.Query, b *float32) search.Query {
+	if b != nil {
+		q.SetBoost(*b)
+	}
+	return q
+}
+
+/* Decodes the parameters of the node, rejecting unknown ones. */
+func decodeParams(nodeName string, params json.RawMessage, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(params))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return newParserError("[%v] query malformed: %v", nodeName, err)
+	}
+	return nil
+}
+
+/* Returns the field the body of the node is keyed by, and its parameters. */
+func fieldParams(nodeName string, body json.RawMessage) (string, json.RawMessage, error) {
+	field, params, err := singleKey(body)
+	if err != nil {
+		return "", nil, newParserError("[%v] query malformed: %v", nodeName, err)
+	}
+	return field, params, nil
+}
+
+/* Returns true if the JSON value is an object, rather than a scalar. */
+func isObject(data json.RawMessage) bool {
+	data = bytes.TrimSpace(data)
+	return len(data) > 0 && data[0] == '{'
+}
+
+/*
+Returns the text of the JSON scalar. Numbers and booleans are returned
+as written, so that they can be searched as the text they were indexed
+as.
+*/
+func scalarText(nodeName string, data json.RawMessage) (string, error) {
+	var v interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&v); err != nil {
+		return "", newParserError("[%v] query malformed: %v", nodeName, err)
+	}
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case bool:
+		if v {
+			return "true", nil
+		}
+		return "false", nil
+	}
+	return "", newParserError("[%v] query malformed: expected a value but found %v", nodeName, compact(data))
+}
+
+/* A term the analyzer produced, and its position. */
+type token struct {
+	term     string
+	position int
+}
+
+/* Returns the tokens the analyzer produces for the text of the field. */
+func analyzeTokens(analyzer analysis.Analyzer, field, text string) (tokens []token, err error) {
+	ts, err := analyzer.TokenStreamForString(field, text)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if e := ts.Close(); err == nil {
+			err = e
+		}
+	}()
+	termAtt := ts.Attributes().Add("CharTermAttribute").(ta.CharTermAttribute)
+	posIncrAtt := ts.Attributes().Add("PositionIncrementAttribute").(ta.PositionIncrementAttribute)
+	if err = ts.Reset(); err != nil {
+		return nil, err
+	}
+	position := -1
+	for {
+		ok, err := ts.IncrementToken()
+		if err != nil {
+			return nil, newParserError("IOException parsing value: %v", err)
+		}
+		if !ok {
+			break
+		}
+		position += posIncrAtt.PositionIncrement()
+		tokens = append(tokens, token{string(termAtt.Buffer()[:termAtt.Length()]), position})
+	}
+	return tokens, ts.End()
+}
+
+/*
+Returns the field and text of a match or match_phrase node, which is
+either the text itself, searched in the default field, or an object
+keyed by the field. The parameters of the field are decoded into
+params, unless only the text is given.
+*/
+func textParams(nodeName, defaultField string, body json.RawMessage,
+	params interface{}, query *json.RawMessage) (string, string, error) {
+
+	if !isObject(body) {
+		text, err := scalarText(nodeName, body)
+		return defaultField, text, err
+	}
+	field, fp, err := fieldParams(nodeName, body)
+	if err != nil {
+		return "", "", err
+	}
+	if isObject(fp) {
+		if err = decodeParams(nodeName, fp, params); err != nil {
+			return "", "", err
+		}
+		if *query == nil {
+			return "", "", newParserError("[%v] query malformed, no \"query\" for field %v", nodeName, field)
+		}
+		fp = *query
+	}
+	text, err := scalarText(nodeName, fp)
+	return field, text, err
+}
+
+/* Builds a TermQuery of the value, which is not analyzed. */
+func buildTermQuery(body json.RawMessage) (search.Query, error) {
+	field, fp, err := fieldParams("term", body)
+	if err != nil {
+		return nil, err
+	}
+	var params struct {
+		Value json.RawMessage `json:"value"`
+		Boost *float32        `json:"boost"`
+	}
+	if isObject(fp) {
+		if err = decodeParams("term", fp, &params); err != nil {
+			return nil, err
+		}
+		if params.Value == nil {
+			return nil, newParserError("[term] query malformed, no \"value\" for field %v", field)
+		}
+		fp = params.Value
+	}
+	value, err := scalarText("term", fp)
+	if err != nil {
+		return nil, err
+	}
+	return boost(search.NewTermQuery(index.NewTerm(field, value)), params.Boost), nil
+}
+
+/*
+Builds a query of the terms the analyzer produces from the text: a
+TermQuery of a single term, or else a BooleanQuery of the terms, which
+are all required with the "and" operator, and optional with the "or"
+operator, the default. Terms at the same position, e.g. synonyms, are
+grouped in a nested BooleanQuery of optional clauses.
+*/
+type MatchQueryBuilder struct {
+	defaultField string
+	analyzer     analysis.Analyzer
+}
+
+func (b *MatchQueryBuilder) GetQuery(body json.RawMessage) (search.Query, error) {
+	var params struct {
+		Query              json.RawMessage `json:"query"`
+		Operator           string          `json:"operator"`
+		MinimumShouldMatch int             `json:"minimum_should_match"`
+		Boost              *float32        `json:"boost"`
+	}
+	field, text, err := textParams("match", b.defaultField, body, &params, &params.Query)
+	if err != nil {
+		return nil, err
+	}
+	var occur search.Occur
+	switch strings.ToLower(params.Operator) {
+	case "", "or":
+		occur = search.SHOULD
+	case "and":
+		occur = search.MUST
+	default:
+		return nil, newParserError("[match] query does not support operator %v", params.Operator)
+	}
+	tokens, err := analyzeTokens(b.analyzer, field, text)
+	if err != nil {
+		return nil, err
+	}
+	var clauses []search.Query
+	for i := 0; i < len(tokens); {
+		j := i + 1
+		for j < len(tokens) && tokens[j].position == tokens[i].position {
+			j++
+		}
+		clauses = append(clauses, termsQuery(field, tokens[i:j]))
+		i = j
+	}
+	if len(clauses) == 1 {
+		return boost(clauses[0], params.Boost), nil
+	}
+	bq := search.NewBooleanQuery()
+	bq.SetMinimumNumberShouldMatch(params.MinimumShouldMatch)
+	for _, clause := range clauses {
+		bq.Add(clause, occur)
+	}
+	return boost(bq, params.Boost), nil
+}
+
+/* Returns a TermQuery of the single token, or a BooleanQuery of optional ones. */
+func termsQuery(field string, tokens []token) search.Query {
+	if len(tokens) == 1 {
+		return search.NewTermQuery(index.NewTerm(field, tokens[0].term))
+	}
+	bq := search.NewBooleanQueryDisableCoord(true)
+	for _, t := range tokens {
+		bq.Add(search.NewTermQuery(index.NewTerm(field, t.term)), search.SHOULD)
+	}
+	return bq
+}
+
+/*
+Builds a PhraseQuery of the terms the analyzer produces from the text,
+at the positions it produces them, with the optional slop.
+*/
+type MatchPhraseQueryBuilder struct {
+	defaultField string
+	analyzer     analysis.Analyzer
+}
+
+func (b *MatchPhraseQueryBuilder) GetQuery(body json.RawMessage) (search.Query, error) {
+	var params struct {
+		Query json.RawMessage `json:"query"`
+		Slop  int             `json:"slop"`
+		Boost *float32        `json:"boost"`
+	}
+	field, text, err := textParams("match_phrase", b.defaultField, body, &params, &params.Query)
+	if err != nil {
+		return nil, err
+	}
+	tokens, err := analyzeTokens(b.analyzer, field, text)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 1 {
+		return boost(search.NewTermQuery(index.NewTerm(field, tokens[0].term)), params.Boost), nil
+	}
+	if len(tokens) == 0 {
+		return boost(search.NewBooleanQuery(), params.Boost), nil
+	}
+	pq := search.NewPhraseQuery()
+	pq.SetSlop(params.Slop)
+	for _, t := range tokens {
+		pq.AddAt(index.NewTerm(field, t.term), t.position)
+	}
+	return boost(pq, params.Boost), nil
+}
+
+/*
+Builds a TermRangeQuery of the bounds gt, gte, lt and lte, any of which
+may be omitted to leave the range open ended.
+*/
+func buildRangeQuery(body json.RawMessage) (search.Query, error) {
+	field, fp, err := fieldParams("range", body)
+	if err != nil {
+		return nil, err
+	}
+	var params struct {
+		Gt    json.RawMessage `json:"gt"`
+		Gte   json.RawMessage `json:"gte"`
+		Lt    json.RawMessage `json:"lt"`
+		Lte   json.RawMessage `json:"lte"`
+		Boost *float32        `json:"boost"`
+	}
+	if err = decodeParams("range", fp, &params); err != nil {
+		return nil, err
+	}
+	lower, includeLower, err := rangeBound(field, params.Gt, params.Gte)
+	if err != nil {
+		return nil, err
+	}
+	upper, includeUpper, err := rangeBound(field, params.Lt, params.Lte)
+	if err != nil {
+		return nil, err
+	}
+	return boost(search.NewTermRangeQueryFromStrings(field, lower, upper, includeLower, includeUpper), params.Boost), nil
+}
+
+/* Returns the text of the exclusive or inclusive bound, or nil if neither is given. */
+func rangeBound(field string, exclusive, inclusive json.RawMessage) (*string, bool, error) {
+	bound, include := exclusive, false
+	if inclusive != nil {
+		if exclusive != nil {
+			return nil, false, newParserError("[range] query malformed, both inclusive and exclusive bound for field %v", field)
+		}
+		bound, include = inclusive, true
+	}
+	if bound == nil || string(bytes.TrimSpace(bound)) == "null" {
+		return nil, include, nil
+	}
+	text, err := scalarText("range", bound)
+	if err != nil {
+		return nil, false, err
+	}
+	return &text, include, nil
+}
+
+func buildMatchAllDocsQuery(body json.RawMessage) (search.Query, error) {
+	var params struct {
+		Boost *float32 `json:"boost"`
+	}
+	if err := decodeParams("match_all", body, &params); err != nil {
+		return nil, err
+	}
+	return boost(search.NewMatchAllDocsQuery(), params.Boost), nil
+}
+
+/*
+Builds a BooleanQuery of the nodes under must, should and must_not,
+each either a single query node or an array of them.
+*/
+type BooleanQueryBuilder struct {
+	factory *QueryBuilderFactory
+}
+
+func (b *BooleanQueryBuilder) GetQuery(body json.RawMessage) (search.Query, error) {
+	var params struct {
+		Must               json.RawMessage `json:"must"`
+		Should             json.RawMessage `json:"should"`
+		MustNot            json.RawMessage `json:"must_not"`
+		MinimumShouldMatch int             `json:"minimum_should_match"`
+		DisableCoord       bool            `json:"disable_coord"`
+		Boost              *float32        `json:"boost"`
+	}
+	if err := decodeParams("bool", body, &params); err != nil {
+		return nil, err
+	}
+	bq := search.NewBooleanQueryDisableCoord(params.DisableCoord)
+	bq.SetMinimumNumberShouldMatch(params.MinimumShouldMatch)
+	for _, clauses := range []struct {
+		nodes json.RawMessage
+		occur search.Occur
+	}{
+		{params.Must, search.MUST},
+		{params.Should, search.SHOULD},
+		{params.MustNot, search.MUST_NOT},
+	} {
+		if err := b.addClauses(bq, clauses.nodes, clauses.occur); err != nil {
+			return nil, err
+		}
+	}
+	return boost(bq, params.Boost), nil
+}
+
+func (b *BooleanQueryBuilder) addClauses(bq *search.BooleanQuery, nodes json.RawMessage, occur search.Occur) error {
+	if nodes == nil {
+		return nil
+	}
+	var list []json.RawMessage
+	if isObject(nodes) {
+		list = []json.RawMessage{nodes}
+	} else if err := json.Unmarshal(nodes, &list); err != nil {
+		return newParserError("[bool] query malformed, expected a query node or an array but found %v", compact(nodes))
+	}
+	for _, node := range list {
+		q, err := b.factory.GetQuery(node)
+		if err != nil {
+			return err
+		}
+		bq.Add(q, occur)
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+package json
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/balzaczyy/golucene/core/analysis"
+	"github.com/balzaczyy/golucene/core/search"
+	"io"
+	"sort"
+	"strings"
+)
+
+/* Error raised when the JSON document does not describe a valid query. */
+type ParserError struct {
+	msg string
+}
+
+func newParserError(format string, args ...interface{}) *ParserError {
+	return &ParserError{fmt.Sprintf(format, args...)}
+}
+
+func (err *ParserError) Error() string {
+	return err.msg
+}
+
+/*
+Implemented by objects that produce Lucene Query objects from the body
+of a JSON query node, i.e. the value keyed by the node name.
+*/
+type QueryBuilder interface {
+	GetQuery(body json.RawMessage) (search.Query, error)
+}
+
+/* Adapts a function to a QueryBuilder. */
+type QueryBuilderFunc func(body json.RawMessage) (search.Query, error)
+
+func (f QueryBuilderFunc) GetQuery(body json.RawMessage) (search.Query, error) {
+	return f(body)
+}
+
+/*
+Dispatches the building of a query node, a JSON object with a single
+key naming the node, to the builder of that name.
+*/
+type QueryBuilderFactory struct {
+	builders map[string]QueryBuilder
+}
+
+func NewQueryBuilderFactory() *QueryBuilderFactory {
+	return &QueryBuilderFactory{make(map[string]QueryBuilder)}
+}
+
+/* Builds the query of the node, a JSON object like {"term":{...}}. */
+func (f *QueryBuilderFactory) GetQuery(node json.RawMessage) (search.Query, error) {
+	name, body, err := singleKey(node)
+	if err != nil {
+		return nil, newParserError("Malformed query node: %v", err)
+	}
+	builder, ok := f.builders[name]
+	if !ok {
+		return nil, newParserError("No query builder defined for node %v", name)
+	}
+	return builder.GetQuery(body)
+}
+
+func (f *QueryBuilderFactory) AddBuilder(nodeName string, builder QueryBuilder) {
+	f.builders[nodeName] = builder
+}
+
+func (f *QueryBuilderFactory) QueryBuilder(nodeName string) QueryBuilder {
+	return f.builders[nodeName]
+}
+
+/*
+Builds queries from a JSON query DSL, which services can accept as the
+body of a search request:
+
+	{"bool": {
+		"must":     {"match": {"contents": "merger bank"}},
+		"must_not": {"term": {"contents": "sumitomo"}},
+		"should":   [{"match_phrase": {"title": {"query": "rate cut", "slop": 1}}}]
+	}}
+
+The nodes supported by default are bool, term, match, match_phrase
+(also phrase), range and match_all. Text of match and match_phrase is
+analyzed with the analyzer of the parser, and searched in the default
+field when no field is given, e.g. {"match": "merger bank"}. Further
+nodes can be registered with AddQueryBuilder.
+*/
+type QueryParser struct {
+	analyzer     analysis.Analyzer
+	queryFactory *QueryBuilderFactory
+}
+
+/*
+Constructs a JSON query parser that uses the given analyzer for the
+nodes whose text is analyzed, and defaultField for those without a
+field.
+*/
+func NewQueryParser(defaultField string, analyzer analysis.Analyzer) *QueryParser {
+	ans := &QueryParser{
+		analyzer:     analyzer,
+		queryFactory: NewQueryBuilderFactory(),
+	}
+	qf := ans.queryFactory
+	qf.AddBuilder("bool", &BooleanQueryBuilder{qf})
+	qf.AddBuilder("term", QueryBuilderFunc(buildTermQuery))
+	qf.AddBuilder("match", &MatchQueryBuilder{defaultField, analyzer})
+	phrase := &MatchPhraseQueryBuilder{defaultField, analyzer}
+	qf.AddBuilder("match_phrase", phrase)
+	qf.AddBuilder("phrase", phrase)
+	qf.AddBuilder("range", QueryBuilderFunc(buildRangeQuery))
+	qf.AddBuilder("match_all", QueryBuilderFunc(buildMatchAllDocsQuery))
+	return ans
+}
+
+/* Parses the JSON document into a query. */
+func (p *QueryParser) Parse(r io.Reader) (search.Query, error) {
+	var node json.RawMessage
+	decoder := json.NewDecoder(r)
+	if err := decoder.Decode(&node); err != nil {
+		if err == io.EOF {
+			return nil, newParserError("Missing query node")
+		}
+		return nil, newParserError("Error parsing JSON stream: %v", err)
+	}
+	if decoder.More() {
+		return nil, newParserError("Error parsing JSON stream: unexpected data after query node")
+	}
+	return p.queryFactory.GetQuery(node)
+}
+
+func (p *QueryParser) ParseBytes(data []byte) (search.Query, error) {
+	return p.Parse(bytes.NewReader(data))
+}
+
+func (p *QueryParser) ParseString(text string) (search.Query, error) {
+	return p.Parse(strings.NewReader(text))
+}
+
+func (p *QueryParser) Analyzer() analysis.Analyzer {
+	return p.analyzer
+}
+
+func (p *QueryParser) AddQueryBuilder(nodeName string, builder QueryBuilder) {
+	p.queryFactory.AddBuilder(nodeName, builder)
+}
+
+/* Returns the factory to build the nodes nested in custom builders. */
+func (p *QueryParser) QueryFactory() *QueryBuilderFactory {
+	return p.queryFactory
+}
+
+/* Returns the only key of the JSON object, and its value. */
+func singleKey(data json.RawMessage) (string, json.RawMessage, error) {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(data, &obj); err != nil || obj == nil {
+		return "", nil, fmt.Errorf("expected an object but found %v", compact(data))
+	}
+	if len(obj) != 1 {
+		keys := make([]string, 0, len(obj))
+		for key := range obj {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		return "", nil, fmt.Errorf("expected a single key but found %v", keys)
+	}
+	for key, value := range obj {
+		return key, value, nil
+	}
+	panic("not reachable")
+}
+
+/* Returns the JSON text without insignificant space, for error messages. */
+func compact(data json.RawMessage) string {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, data); err != nil {
+		return string(data)
+	}
+	return buf.String()
+}
@@ -0,0 +1,170 @@
+package json
+
+import (
+	"encoding/json"
+	std "github.com/balzaczyy/golucene/analysis/standard"
+	_ "github.com/balzaczyy/golucene/core/codec/lucene410"
+	docu "github.com/balzaczyy/golucene/core/document"
+	"github.com/balzaczyy/golucene/core/index"
+	"github.com/balzaczyy/golucene/core/search"
+	"github.com/balzaczyy/golucene/core/store"
+	"github.com/balzaczyy/golucene/core/util"
+	"strings"
+	"testing"
+)
+
+func TestQueryParser(t *testing.T) {
+	p := NewQueryParser("contents", std.NewStandardAnalyzer())
+	for _, c := range [][2]string{
+		{`{"term": {"contents": "sumitomo"}}`, "sumitomo"},
+		{`{"term": {"title": {"value": "Sumitomo", "boost": 2}}}`, "title:Sumitomo^2"},
+		{`{"term": {"year": 1987}}`, "year:1987"},
+		{`{"match": "Merger of the Banks"}`, "merger banks"},
+		{`{"match": {"title": "Merger"}}`, "title:merger"},
+		{`{"match": {"title": {"query": "merger banks", "operator": "and", "boost": 3}}}`,
+			"(+title:merger +title:banks)^3"},
+		{`{"match": {"contents": {"query": "merger banks rate", "minimum_should_match": 2}}}`,
+			"(merger banks rate)~2"},
+		{`{"match": "the"}`, ""},
+		{`{"match_phrase": "merger of banks"}`, "\"merger ? banks\""},
+		{`{"phrase": {"title": {"query": "merger banks", "slop": 2}}}`, "title:\"merger banks\"~2"},
+		{`{"match_phrase": {"title": "Merger"}}`, "title:merger"},
+		{`{"range": {"date": {"gte": 19870409, "lte": "19870412"}}}`, "date:[19870409 TO 19870412]"},
+		{`{"range": {"date": {"gt": "a", "lt": "b", "boost": 2}}}`, "date:{a TO b}^2"},
+		{`{"range": {"date": {"gte": "a"}}}`, "date:[a TO *}"},
+		{`{"match_all": {}}`, "*:*"},
+		{`{"match_all": {"boost": 2}}`, "*:*^2"},
+		{`{"bool": {
+			"should": {"term": {"contents": "merger"}},
+			"must_not": [{"term": {"contents": "sumitomo"}}],
+			"must": [{"term": {"title": "bank"}}, {"match": {"title": "rate"}}]
+		}}`, "+title:bank +title:rate merger -sumitomo"},
+		{`{"bool": {"boost": 3, "minimum_should_match": 1, "should": [
+			{"bool": {"must": {"term": {"contents": "nested"}}}},
+			{"term": {"contents": "merger"}}
+		]}}`, "((+nested) merger)~1^3"},
+	} {
+		assertJSONQueryEquals(t, p, c[0], c[1])
+	}
+}
+
+func assertJSONQueryEquals(t *testing.T, p *QueryParser, jsonText, expected string) {
+	q, err := p.ParseString(jsonText)
+	if err != nil {
+		t.Errorf("%v: %v", jsonText, err)
+		return
+	}
+	if got := q.ToString("contents"); got != expected {
+		t.Errorf("Query /%v/ yielded /%v/, expecting /%v/", jsonText, got, expected)
+	}
+}
+
+func TestQueryParserErrors(t *testing.T) {
+	p := NewQueryParser("contents", std.NewStandardAnalyzer())
+	for _, c := range [][2]string{
+		{`{"fuzzy": {"contents": "a"}}`, "No query builder defined for node fuzzy"},
+		{`{"term": {"a": "x"}, "match": "y"}`, "expected a single key but found [match term]"},
+		{`["term"]`, "expected an object but found [\"term\"]"},
+		{`{"term": {}}`, "[term] query malformed: expected a single key but found []"},
+		{`{"term": {"contents": {"boost": 2}}}`, `[term] query malformed, no "value" for field contents`},
+		{`{"term": {"contents": {"value": "a", "slop": 2}}}`, `[term] query malformed: json: unknown field "slop"`},
+		{`{"term": {"contents": ["a"]}}`, `[term] query malformed: expected a value but found ["a"]`},
+		{`{"match": {"contents": {"query": "a", "operator": "xor"}}}`, "[match] query does not support operator xor"},
+		{`{"match_phrase": {"contents": {"slop": 1}}}`, `[match_phrase] query malformed, no "query" for field contents`},
+		{`{"range": {"date": {"gt": "a", "gte": "b"}}}`, "both inclusive and exclusive bound for field date"},
+		{`{"range": {"date": "a"}}`, "[range] query malformed"},
+		{`{"bool": {"must": "a"}}`, `[bool] query malformed, expected a query node or an array but found "a"`},
+		{`{"bool": {"filter": []}}`, `[bool] query malformed: json: unknown field "filter"`},
+		{`{"bool": {"must": [{"foo": {}}]}}`, "No query builder defined for node foo"},
+		{`{"match_all": {}} {}`, "unexpected data after query node"},
+		{`{"match_all": {}`, "Error parsing JSON stream"},
+		{``, "Missing query node"},
+	} {
+		if _, err := p.ParseString(c[0]); err == nil {
+			t.Errorf("Expected %v to fail", c[0])
+		} else if _, ok := err.(*ParserError); !ok || !strings.Contains(err.Error(), c[1]) {
+			t.Errorf("Expected %v to fail with %v, but was %v", c[0], c[1], err)
+		}
+	}
+}
+
+func TestQueryParserCustomBuilder(t *testing.T) {
+	p := NewQueryParser("contents", std.NewStandardAnalyzer())
+	// a builder of a prefix query, nested in a core node
+	p.AddQueryBuilder("prefix", QueryBuilderFunc(func(body json.RawMessage) (search.Query, error) {
+		var params map[string]string
+		if err := json.Unmarshal(body, &params); err != nil {
+			return nil, err
+		}
+		bq := search.NewBooleanQuery()
+		for field, prefix := range params {
+			bq.Add(search.NewPrefixQuery(index.NewTerm(field, prefix)), search.SHOULD)
+		}
+		return bq, nil
+	}))
+	assertJSONQueryEquals(t, p, `{"bool": {"must": [
+		{"prefix": {"contents": "merg"}},
+		{"term": {"contents": "bank"}}
+	]}}`, "+(merg*) +bank")
+}
+
+func TestQueryParserSearch(t *testing.T) {
+	dir, err := store.OpenFSDirectory(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := std.NewStandardAnalyzer()
+	w, err := index.NewIndexWriter(dir, index.NewIndexWriterConfig(util.VERSION_LATEST, a))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range [][2]string{
+		{"the passenger was killed in the crash", "1987"},
+		{"the crew died at sea", "1990"},
+		{"no one was killed, the crew survived", "1995"},
+	} {
+		doc := docu.NewDocument()
+		doc.Add(docu.NewTextFieldFromString("contents", c[0], docu.STORE_YES))
+		doc.Add(docu.NewStringField("year", c[1], docu.STORE_YES))
+		if err = w.AddDocument(doc.Fields()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	r, err := index.OpenDirectoryReader(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	ss := search.NewIndexSearcher(r)
+
+	p := NewQueryParser("contents", a)
+	hits := func(jsonText string, expected int) {
+		q, err := p.ParseString(jsonText)
+		if err != nil {
+			t.Fatal(err)
+		}
+		docs, err := ss.SearchTop(q, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if docs.TotalHits != expected {
+			t.Errorf("Expected %v hits for %v, but was %v", expected, q, docs.TotalHits)
+		}
+	}
+	hits(`{"match": "killed crew"}`, 3)
+	hits(`{"match": {"contents": {"query": "killed crew", "operator": "and"}}}`, 1)
+	hits(`{"match_phrase": "crew died"}`, 1)
+	hits(`{"match_phrase": "passenger was killed"}`, 1)
+	hits(`{"match_phrase": "passenger killed"}`, 0)
+	hits(`{"match_phrase": {"contents": {"query": "passenger killed", "slop": 1}}}`, 1)
+	hits(`{"range": {"year": {"gt": 1987, "lte": 1995}}}`, 2)
+	hits(`{"bool": {
+		"must": {"match": "killed died"},
+		"must_not": {"match": "survived"}
+	}}`, 2)
+	hits(`{"match": "the"}`, 0)
+	hits(`{"match_all": {}}`, 3)
+}
@@ -0,0 +1,84 @@
+package surround
+
+import (
+	"fmt"
+	"github.com/balzaczyy/golucene/core/index"
+	"github.com/balzaczyy/golucene/core/search"
+	"sync"
+)
+
+// surround/query/TooManyBasicQueries.java
+
+/*
+Exception thrown when BasicQueryFactory would exceed the limit of
+query clauses.
+*/
+type TooManyBasicQueries struct {
+	MaxBasicQueries int
+}
+
+func (err *TooManyBasicQueries) Error() string {
+	return fmt.Sprintf("Exceeded maximum of %v basic queries.", err.MaxBasicQueries)
+}
+
+// surround/query/BasicQueryFactory.java
+
+const DEFAULT_MAX_BASIC_QUERIES = 1024
+
+/*
+Factory for creating basic term queries. It limits the number of
+queries made for the terms matching the prefixes and truncations of a
+surround query, so that a query is not expanded into too many clauses.
+*/
+type BasicQueryFactory struct {
+	sync.Mutex
+	maxBasicQueries int
+	queriesMade     int
+}
+
+func NewBasicQueryFactory() *BasicQueryFactory {
+	return NewBasicQueryFactoryWithMax(DEFAULT_MAX_BASIC_QUERIES)
+}
+
+func NewBasicQueryFactoryWithMax(maxBasicQueries int) *BasicQueryFactory {
+	return &BasicQueryFactory{maxBasicQueries: maxBasicQueries}
+}
+
+func (qf *BasicQueryFactory) MaxBasicQueries() int {
+	return qf.maxBasicQueries
+}
+
+func (qf *BasicQueryFactory) NrQueriesMade() int {
+	qf.Lock()
+	defer qf.Unlock()
+	return qf.queriesMade
+}
+
+func (qf *BasicQueryFactory) String() string {
+	return fmt.Sprintf("BasicQueryFactory(maxBasicQueries: %v, queriesMade: %v)",
+		qf.maxBasicQueries, qf.NrQueriesMade())
+}
+
+func (qf *BasicQueryFactory) checkMax() error {
+	qf.Lock()
+	defer qf.Unlock()
+	if qf.queriesMade >= qf.maxBasicQueries {
+		return &TooManyBasicQueries{qf.maxBasicQueries}
+	}
+	qf.queriesMade++
+	return nil
+}
+
+func (qf *BasicQueryFactory) NewTermQuery(term *index.Term) (*search.TermQuery, error) {
+	if err := qf.checkMax(); err != nil {
+		return nil, err
+	}
+	return search.NewTermQuery(term), nil
+}
+
+func (qf *BasicQueryFactory) NewSpanTermQuery(term *index.Term) (*search.SpanTermQuery, error) {
+	if err := qf.checkMax(); err != nil {
+		return nil, err
+	}
+	return search.NewSpanTermQuery(term), nil
+}
@@ -0,0 +1,190 @@
+package surround
+
+import (
+	"fmt"
+	"github.com/balzaczyy/golucene/core/index"
+	"github.com/balzaczyy/golucene/core/search"
+)
+
+// surround/query/DistanceSubQuery.java
+
+/* Interface for queries that can be nested as subqueries into a span near. */
+type distanceSubQuery interface {
+	/*
+		When distanceSubQueryNotAllowed() returns an empty string, the
+		query can be used as a subquery of a distance query, otherwise it
+		returns the reason why not.
+	*/
+	distanceSubQueryNotAllowed() string
+	addSpanQueries(sncf *spanNearClauseFactory) error
+}
+
+// surround/query/DistanceQuery.java
+
+/*
+Factory for NEAR queries, unordered (N) or ordered (W), compiled to a
+SpanNearQuery whose slop is the distance minus one.
+*/
+type DistanceQuery struct {
+	*ComposedQuery
+	opDistance int
+	ordered    bool
+}
+
+func NewDistanceQuery(queries []SrndQuery, infix bool, opDistance int,
+	opName string, ordered bool) *DistanceQuery {
+
+	ans := &DistanceQuery{opDistance: opDistance, ordered: ordered}
+	ans.ComposedQuery = newComposedQuery(ans, queries, infix, opName)
+	return ans
+}
+
+func (q *DistanceQuery) OpDistance() int {
+	return q.opDistance
+}
+
+func (q *DistanceQuery) SubQueriesOrdered() bool {
+	return q.ordered
+}
+
+func (q *DistanceQuery) distanceSubQueryNotAllowed() string {
+	for _, sub := range q.queries {
+		dsq, ok := sub.(distanceSubQuery)
+		if !ok {
+			return fmt.Sprintf("Operator %v does not allow subquery %v", q.opName, sub)
+		}
+		if m := dsq.distanceSubQueryNotAllowed(); m != "" {
+			return m
+		}
+	}
+	return "" // subqueries acceptable
+}
+
+func (q *DistanceQuery) addSpanQueries(sncf *spanNearClauseFactory) error {
+	snq, err := q.spanNearQuery(sncf.reader, sncf.fieldName, q.weight, sncf.qf)
+	if err == nil && snq != nil {
+		sncf.addSpanQuery(snq)
+	}
+	return err
+}
+
+/*
+Builds the span near query over the matching terms of the subqueries
+in the field, or returns nil if a subquery matches no term.
+*/
+func (q *DistanceQuery) spanNearQuery(reader index.IndexReader, fieldName string,
+	boost float32, qf *BasicQueryFactory) (search.SpanQuery, error) {
+
+	spanClauses := make([]search.SpanQuery, len(q.queries))
+	for i, sub := range q.queries {
+		sncf := newSpanNearClauseFactory(reader, fieldName, qf)
+		if err := sub.(distanceSubQuery).addSpanQueries(sncf); err != nil {
+			return nil, err
+		}
+		if sncf.size() == 0 { // distance operator requires all sub queries
+			for _, rest := range q.queries[i+1:] {
+				// produce evt. error messages but ignore results
+				if err := rest.(distanceSubQuery).addSpanQueries(sncf); err != nil {
+					return nil, err
+				}
+				sncf.clear()
+			}
+			return nil, nil
+		}
+		spanClauses[i] = sncf.makeSpanClause()
+	}
+	ans := search.NewSpanNearQuery(spanClauses, q.opDistance-1, q.ordered)
+	ans.SetBoost(boost)
+	return ans, nil
+}
+
+func (q *DistanceQuery) MakeLuceneQueryFieldNoBoost(fieldName string, qf *BasicQueryFactory) search.Query {
+	ans := new(DistanceRewriteQuery)
+	ans.rewriteQuery = newRewriteQuery(ans, "DistanceRewriteQuery", q, fieldName, qf)
+	return ans
+}
+
+// surround/query/DistanceRewriteQuery.java
+
+/* The query of a DistanceQuery, rewritten to a SpanNearQuery. */
+type DistanceRewriteQuery struct {
+	*rewriteQuery
+}
+
+func (q *DistanceRewriteQuery) Rewrite(reader index.IndexReader) search.Query {
+	snq, err := q.srndQuery.(*DistanceQuery).spanNearQuery(reader, q.fieldName, q.Boost(), q.qf)
+	if err != nil {
+		panic(err) // Rewrite() has no error to return
+	}
+	if snq == nil {
+		return search.NewBooleanQuery() // matches nothing
+	}
+	return snq
+}
+
+// surround/query/SpanNearClauseFactory.java
+
+/*
+Collects the span queries of the terms matching a subquery of a
+distance query, adding up the weights of a term matched several times.
+The collected queries are combined into a SpanOrQuery.
+*/
+type spanNearClauseFactory struct {
+	reader    index.IndexReader
+	fieldName string
+	qf        *BasicQueryFactory
+	queries   []search.SpanQuery
+	// position in queries, by their string form
+	positions map[string]int
+}
+
+func newSpanNearClauseFactory(reader index.IndexReader, fieldName string,
+	qf *BasicQueryFactory) *spanNearClauseFactory {
+
+	return &spanNearClauseFactory{
+		reader:    reader,
+		fieldName: fieldName,
+		qf:        qf,
+		positions: make(map[string]int),
+	}
+}
+
+func (sncf *spanNearClauseFactory) size() int {
+	return len(sncf.queries)
+}
+
+func (sncf *spanNearClauseFactory) clear() {
+	sncf.queries = nil
+	sncf.positions = make(map[string]int)
+}
+
+func (sncf *spanNearClauseFactory) addSpanQueryWeighted(sq search.SpanQuery, weight float32) {
+	key := sq.ToString(sncf.fieldName)
+	if i, ok := sncf.positions[key]; ok {
+		sncf.queries[i].SetBoost(sncf.queries[i].Boost() + weight)
+		return
+	}
+	sq.SetBoost(weight)
+	sncf.positions[key] = len(sncf.queries)
+	sncf.queries = append(sncf.queries, sq)
+}
+
+func (sncf *spanNearClauseFactory) addTermWeighted(term *index.Term, weight float32) error {
+	stq, err := sncf.qf.NewSpanTermQuery(term)
+	if err != nil {
+		return err
+	}
+	sncf.addSpanQueryWeighted(stq, weight)
+	return nil
+}
+
+func (sncf *spanNearClauseFactory) addSpanQuery(q search.SpanQuery) {
+	sncf.addSpanQueryWeighted(q, q.Boost())
+}
+
+func (sncf *spanNearClauseFactory) makeSpanClause() search.SpanQuery {
+	if len(sncf.queries) == 1 {
+		return sncf.queries[0]
+	}
+	return search.NewSpanOrQuery(sncf.queries...)
+}
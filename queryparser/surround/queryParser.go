@@ -0,0 +1,506 @@
+package surround
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// surround/parser/QueryParser.java
+
+/*
+Parses a query in the surround syntax:
+
+	a OR b, a AND b, a NOT b   boolean operators, also as OR(a, b, ...)
+	a W b, a 3W b, 2W(a, b, c) ordered distance, compiled to span near
+	a N b, a 3N b, N(a, b)     unordered distance
+	title: a, title: body: a   fields, applied to the following query
+	abc*, "abc"*, a?c*         prefix and truncated terms
+	"a b", a^2                 quoted terms and weights
+
+Operators are case insensitive, and the distances range from 2 to 99,
+which is the maximal number of positions between the terms; W and N
+alone stand for a distance of 1. Precedence increases from OR, AND,
+NOT, N to W, and parentheses group subqueries. Only terms, OR and
+distance queries are allowed as subqueries of a distance query.
+*/
+func Parse(query string) (SrndQuery, error) {
+	qp := &queryParser{tokens: newTokenizer(query)}
+	q, err := qp.fieldsQuery()
+	if err != nil {
+		return nil, err
+	}
+	if _, err = qp.consume(tkEOF); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+const (
+	MINIMUM_PREFIX_LENGTH  = 3
+	MINIMUM_CHARS_IN_TRUNC = 3
+
+	TRUNCATION_ERROR_MESSAGE = "Too unrestrictive truncation: "
+	BOOST_ERROR_MESSAGE      = "Cannot handle boost value: "
+
+	// CHECKME: These should be the same as for the tokenizer. How?
+	TRUNCATOR      = '*'
+	ANY_CHAR       = '?'
+	FIELD_OPERATOR = ':'
+)
+
+type tokenKind int
+
+const (
+	tkEOF = tokenKind(iota)
+	tkOR
+	tkAND
+	tkNOT
+	tkW
+	tkN
+	tkLPAREN
+	tkRPAREN
+	tkCOMMA
+	tkCOLON
+	tkCARAT
+	tkTRUNCQUOTED
+	tkQUOTED
+	tkSUFFIXTERM
+	tkTRUNCTERM
+	tkTERM
+	tkNUMBER
+)
+
+var tokenImages = []string{
+	"<EOF>", "<OR>", "<AND>", "<NOT>", "<W>", "<N>", "\"(\"", "\")\"",
+	"\",\"", "\":\"", "\"^\"", "<TRUNCQUOTED>", "<QUOTED>", "<SUFFIXTERM>",
+	"<TRUNCTERM>", "<TERM>", "<NUMBER>",
+}
+
+type token struct {
+	kind   tokenKind
+	image  string
+	column int
+}
+
+func isWhitespace(c rune) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '　'
+}
+
+func isTermChar(c rune) bool {
+	return !isWhitespace(c) && !strings.ContainsRune("*?(),\":^", c)
+}
+
+/* Returns whether the image is a distance operator like W, 3W or 12n. */
+func isDistanceOp(image string, op byte) bool {
+	n := len(image)
+	if n == 0 || unicode.ToUpper(rune(image[n-1])) != rune(op) {
+		return false
+	}
+	switch num := image[:n-1]; len(num) {
+	case 0:
+		return true
+	case 1:
+		return num[0] >= '2' && num[0] <= '9'
+	case 2:
+		return num[0] >= '1' && num[0] <= '9' && num[1] >= '0' && num[1] <= '9'
+	}
+	return false
+}
+
+/* Splits the query text into tokens, the longest match first. */
+type tokenizer struct {
+	input []rune
+	pos   int
+	boost bool // after a carat, a number is expected
+}
+
+func newTokenizer(query string) *tokenizer {
+	return &tokenizer{input: []rune(query)}
+}
+
+func (t *tokenizer) next() (*token, error) {
+	for t.pos < len(t.input) && isWhitespace(t.input[t.pos]) {
+		t.pos++
+	}
+	start := t.pos
+	if t.pos == len(t.input) {
+		return &token{tkEOF, "", start + 1}, nil
+	}
+	newToken := func(kind tokenKind) *token {
+		return &token{kind, string(t.input[start:t.pos]), start + 1}
+	}
+
+	if t.boost {
+		t.boost = false
+		for t.pos < len(t.input) && unicode.IsDigit(t.input[t.pos]) {
+			t.pos++
+		}
+		if t.pos > start && t.pos+1 < len(t.input) && t.input[t.pos] == '.' &&
+			unicode.IsDigit(t.input[t.pos+1]) {
+			for t.pos++; t.pos < len(t.input) && unicode.IsDigit(t.input[t.pos]); t.pos++ {
+			}
+		}
+		if t.pos == start {
+			return nil, t.lexicalError()
+		}
+		return newToken(tkNUMBER), nil
+	}
+
+	switch c := t.input[t.pos]; c {
+	case '(', ')', ',', ':', '^':
+		t.pos++
+		t.boost = c == '^'
+		return newToken(map[rune]tokenKind{
+			'(': tkLPAREN, ')': tkRPAREN, ',': tkCOMMA, ':': tkCOLON, '^': tkCARAT,
+		}[c]), nil
+	case '"':
+		for t.pos++; t.pos < len(t.input) && t.input[t.pos] != '"'; t.pos++ {
+		}
+		if t.pos == len(t.input) || t.pos == start+1 {
+			return nil, t.lexicalError()
+		}
+		t.pos++
+		if t.pos < len(t.input) && t.input[t.pos] == TRUNCATOR {
+			t.pos++
+			return newToken(tkTRUNCQUOTED), nil
+		}
+		return newToken(tkQUOTED), nil
+	}
+
+	for t.pos < len(t.input) && (isTermChar(t.input[t.pos]) ||
+		t.input[t.pos] == TRUNCATOR || t.input[t.pos] == ANY_CHAR) {
+		t.pos++
+	}
+	if t.pos == start {
+		return nil, t.lexicalError()
+	}
+	image := string(t.input[start:t.pos])
+	switch upper := strings.ToUpper(image); {
+	case upper == "OR":
+		return newToken(tkOR), nil
+	case upper == "AND":
+		return newToken(tkAND), nil
+	case upper == "NOT":
+		return newToken(tkNOT), nil
+	case isDistanceOp(image, 'W'):
+		return newToken(tkW), nil
+	case isDistanceOp(image, 'N'):
+		return newToken(tkN), nil
+	}
+	switch i := strings.IndexAny(image, "*?"); {
+	case i < 0:
+		return newToken(tkTERM), nil
+	case i == len(image)-1 && image[i] == TRUNCATOR:
+		return newToken(tkSUFFIXTERM), nil
+	}
+	return newToken(tkTRUNCTERM), nil
+}
+
+func (t *tokenizer) lexicalError() error {
+	after := ""
+	if t.pos < len(t.input) {
+		after = string(t.input[t.pos])
+	}
+	return fmt.Errorf("Lexical error at column %v. Encountered: %q", t.pos+1, after)
+}
+
+type queryParser struct {
+	tokens *tokenizer
+	// look ahead tokens
+	ahead []*token
+}
+
+func (qp *queryParser) peek(i int) (*token, error) {
+	for len(qp.ahead) <= i {
+		t, err := qp.tokens.next()
+		if err != nil {
+			return nil, err
+		}
+		qp.ahead = append(qp.ahead, t)
+	}
+	return qp.ahead[i], nil
+}
+
+/* Returns the next token if it is of one of the kinds, or nil. */
+func (qp *queryParser) optional(kinds ...tokenKind) (*token, error) {
+	t, err := qp.peek(0)
+	if err != nil {
+		return nil, err
+	}
+	for _, kind := range kinds {
+		if t.kind == kind {
+			qp.ahead = qp.ahead[1:]
+			return t, nil
+		}
+	}
+	return nil, nil
+}
+
+func (qp *queryParser) consume(kinds ...tokenKind) (*token, error) {
+	t, err := qp.optional(kinds...)
+	if err != nil || t != nil {
+		return t, err
+	}
+	t, _ = qp.peek(0)
+	var expected []string
+	for _, kind := range kinds {
+		expected = append(expected, tokenImages[kind])
+	}
+	image := tokenImages[t.kind]
+	if t.kind != tkEOF {
+		image = fmt.Sprintf("%q", t.image)
+	}
+	return nil, fmt.Errorf("Encountered %v at column %v. Was expecting one of: %v",
+		image, t.column, strings.Join(expected, ", "))
+}
+
+func (qp *queryParser) fieldsQuery() (SrndQuery, error) {
+	var fieldNames []string
+	for {
+		t0, err := qp.peek(0)
+		if err != nil {
+			return nil, err
+		}
+		t1, err := qp.peek(1)
+		if err != nil {
+			return nil, err
+		}
+		if t0.kind != tkTERM || t1.kind != tkCOLON {
+			break
+		}
+		qp.ahead = qp.ahead[2:]
+		fieldNames = append(fieldNames, t0.image)
+	}
+	q, err := qp.orQuery()
+	if err != nil || fieldNames == nil {
+		return q, err
+	}
+	return NewFieldsQuery(q, fieldNames, FIELD_OPERATOR), nil
+}
+
+/*
+Parses the infix operator of the kind over the operands parsed with
+operand, combining them left to right with combine when binary, or all
+at once otherwise.
+*/
+func (qp *queryParser) infixQuery(kind tokenKind, binary bool, operand func() (SrndQuery, error),
+	combine func(queries []SrndQuery, oprt *token) (SrndQuery, error)) (SrndQuery, error) {
+
+	q, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	queries := []SrndQuery{q}
+	var oprt *token
+	for {
+		t, err := qp.optional(kind)
+		if err != nil {
+			return nil, err
+		}
+		if t == nil {
+			break
+		}
+		oprt = t
+		if q, err = operand(); err != nil {
+			return nil, err
+		}
+		queries = append(queries, q)
+		if binary {
+			if q, err = combine(queries, oprt); err != nil {
+				return nil, err
+			}
+			queries = []SrndQuery{q}
+		}
+	}
+	if binary || len(queries) == 1 {
+		return queries[0], nil
+	}
+	return combine(queries, oprt)
+}
+
+func (qp *queryParser) orQuery() (SrndQuery, error) {
+	return qp.infixQuery(tkOR, false, qp.andQuery, func(queries []SrndQuery, oprt *token) (SrndQuery, error) {
+		return NewOrQuery(queries, true, oprt.image), nil
+	})
+}
+
+func (qp *queryParser) andQuery() (SrndQuery, error) {
+	return qp.infixQuery(tkAND, false, qp.notQuery, func(queries []SrndQuery, oprt *token) (SrndQuery, error) {
+		return NewAndQuery(queries, true, oprt.image), nil
+	})
+}
+
+func (qp *queryParser) notQuery() (SrndQuery, error) {
+	return qp.infixQuery(tkNOT, false, qp.nQuery, func(queries []SrndQuery, oprt *token) (SrndQuery, error) {
+		return NewNotQuery(queries, oprt.image), nil
+	})
+}
+
+func (qp *queryParser) nQuery() (SrndQuery, error) {
+	return qp.infixQuery(tkN, true, qp.wQuery, func(queries []SrndQuery, dt *token) (SrndQuery, error) {
+		return distanceQuery(queries, true, dt, false)
+	})
+}
+
+func (qp *queryParser) wQuery() (SrndQuery, error) {
+	return qp.infixQuery(tkW, true, qp.primaryQuery, func(queries []SrndQuery, dt *token) (SrndQuery, error) {
+		return distanceQuery(queries, true, dt, true)
+	})
+}
+
+func (qp *queryParser) primaryQuery() (q SrndQuery, err error) {
+	t, err := qp.peek(0)
+	if err != nil {
+		return nil, err
+	}
+	switch t.kind {
+	case tkLPAREN:
+		qp.ahead = qp.ahead[1:]
+		if q, err = qp.fieldsQuery(); err != nil {
+			return nil, err
+		}
+		if _, err = qp.consume(tkRPAREN); err != nil {
+			return nil, err
+		}
+	case tkOR, tkAND, tkW, tkN:
+		q, err = qp.prefixOperatorQuery()
+	default:
+		q, err = qp.simpleTerm()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return q, qp.optionalWeights(q)
+}
+
+func (qp *queryParser) prefixOperatorQuery() (SrndQuery, error) {
+	oprt, err := qp.consume(tkOR, tkAND, tkW, tkN)
+	if err != nil {
+		return nil, err
+	}
+	queries, err := qp.fieldsQueryList()
+	if err != nil {
+		return nil, err
+	}
+	switch oprt.kind {
+	case tkOR:
+		return NewOrQuery(queries, false, oprt.image), nil
+	case tkAND:
+		return NewAndQuery(queries, false, oprt.image), nil
+	}
+	return distanceQuery(queries, false, oprt, oprt.kind == tkW)
+}
+
+/* Parses at least two comma separated queries within parentheses. */
+func (qp *queryParser) fieldsQueryList() ([]SrndQuery, error) {
+	if _, err := qp.consume(tkLPAREN); err != nil {
+		return nil, err
+	}
+	var queries []SrndQuery
+	for {
+		q, err := qp.fieldsQuery()
+		if err != nil {
+			return nil, err
+		}
+		queries = append(queries, q)
+		if len(queries) == 1 {
+			if _, err = qp.consume(tkCOMMA); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		t, err := qp.consume(tkCOMMA, tkRPAREN)
+		if err != nil {
+			return nil, err
+		}
+		if t.kind == tkRPAREN {
+			return queries, nil
+		}
+	}
+}
+
+func (qp *queryParser) simpleTerm() (SrndQuery, error) {
+	t, err := qp.consume(tkTERM, tkQUOTED, tkSUFFIXTERM, tkTRUNCTERM, tkTRUNCQUOTED)
+	if err != nil {
+		return nil, err
+	}
+	image := []rune(t.image)
+	switch t.kind {
+	case tkQUOTED:
+		return NewSrndTermQuery(string(image[1:len(image)-1]), true), nil
+	case tkSUFFIXTERM:
+		// ending in *
+		if !allowedSuffix(image) {
+			return nil, fmt.Errorf("%v%v", TRUNCATION_ERROR_MESSAGE, t.image)
+		}
+		return NewSrndPrefixQuery(string(image[:len(image)-1]), false, TRUNCATOR), nil
+	case tkTRUNCTERM:
+		// with at least one * or ?
+		if !allowedTruncation(image) {
+			return nil, fmt.Errorf("%v%v", TRUNCATION_ERROR_MESSAGE, t.image)
+		}
+		return NewSrndTruncQuery(t.image, TRUNCATOR, ANY_CHAR), nil
+	case tkTRUNCQUOTED:
+		// eg. "9b-b,m"*
+		if len(image)-3 < MINIMUM_PREFIX_LENGTH {
+			return nil, fmt.Errorf("%v%v", TRUNCATION_ERROR_MESSAGE, t.image)
+		}
+		return NewSrndPrefixQuery(string(image[1:len(image)-2]), true, TRUNCATOR), nil
+	}
+	return NewSrndTermQuery(t.image, false), nil
+}
+
+func (qp *queryParser) optionalWeights(q SrndQuery) error {
+	for {
+		t, err := qp.optional(tkCARAT)
+		if err != nil || t == nil {
+			return err
+		}
+		weight, err := qp.consume(tkNUMBER)
+		if err != nil {
+			return err
+		}
+		f, err := strconv.ParseFloat(weight.image, 32)
+		if err != nil {
+			return fmt.Errorf("%v%v (%v)", BOOST_ERROR_MESSAGE, weight.image, err)
+		}
+		if f <= 0 {
+			return fmt.Errorf("%v%v", BOOST_ERROR_MESSAGE, weight.image)
+		}
+		q.SetWeight(float32(f) * q.Weight()) // left associative, fwiw
+	}
+}
+
+func allowedSuffix(suffixed []rune) bool {
+	return len(suffixed)-1 >= MINIMUM_PREFIX_LENGTH
+}
+
+func allowedTruncation(truncated []rune) bool {
+	// At least 3 normal characters needed.
+	nrNormalChars := 0
+	for _, c := range truncated {
+		if c != TRUNCATOR && c != ANY_CHAR {
+			nrNormalChars++
+		}
+	}
+	return nrNormalChars >= MINIMUM_CHARS_IN_TRUNC
+}
+
+func distanceQuery(queries []SrndQuery, infix bool, dToken *token, ordered bool) (SrndQuery, error) {
+	dq := NewDistanceQuery(queries, infix, opDistance(dToken.image), dToken.image, ordered)
+	if m := dq.distanceSubQueryNotAllowed(); m != "" {
+		return nil, fmt.Errorf("Operator %v: %v", dToken.image, m)
+	}
+	return dq, nil
+}
+
+/* W, 2W, 3W etc -> 1, 2 3, etc. Same for N, 2N ... */
+func opDistance(distanceOp string) int {
+	if len(distanceOp) == 1 {
+		return 1
+	}
+	n, _ := strconv.Atoi(distanceOp[:len(distanceOp)-1])
+	return n
+}
@@ -0,0 +1,198 @@
+package surround
+
+import (
+	std "github.com/balzaczyy/golucene/analysis/standard"
+	_ "github.com/balzaczyy/golucene/core/codec/lucene410"
+	docu "github.com/balzaczyy/golucene/core/document"
+	"github.com/balzaczyy/golucene/core/index"
+	"github.com/balzaczyy/golucene/core/search"
+	"github.com/balzaczyy/golucene/core/store"
+	"github.com/balzaczyy/golucene/core/util"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	for _, c := range [][2]string{
+		{"word1", "word1"},
+		{"word1 w word2", "(word1 w word2)"},
+		{"word1 3W word2 n word3", "((word1 3W word2) n word3)"},
+		{"3W(abc*, b)", "3W(abc*, b)"},
+		{"N(a, b, c)^2", "N(a, b, c)^2"},
+		{"a OR b AND c", "(a OR (b AND c))"},
+		{"a or b AND c NOT d", "(a or (b AND (c NOT d)))"},
+		{"a and b and c", "(a and b and c)"},
+		{"OR(a, b)", "OR(a, b)"},
+		{"title: body: x OR y", "(title:body:(x OR y))"},
+		{"(title: x) AND y", "((title:x) AND y)"},
+		{"\"quoted term\"^2", "\"quoted term\"^2"},
+		{"\"abc\"*", "\"abc\"*"},
+		{"ab?d*", "ab?d*"},
+		{"x^2^1.5", "x^3"},
+		{"1W", "1W"},
+	} {
+		q, err := Parse(c[0])
+		if err != nil {
+			t.Errorf("%v: %v", c[0], err)
+			continue
+		}
+		if got := q.String(); got != c[1] {
+			t.Errorf("Query /%v/ yielded /%v/, expecting /%v/", c[0], got, c[1])
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, c := range [][2]string{
+		{"ab*", TRUNCATION_ERROR_MESSAGE},
+		{"a?b", TRUNCATION_ERROR_MESSAGE},
+		{"\"ab\"*", TRUNCATION_ERROR_MESSAGE},
+		{"a^0", BOOST_ERROR_MESSAGE},
+		{"word1 w (word2 AND word3)", "Operator w: Operator w does not allow subquery (word2 AND word3)"},
+		{"N(word1, word2 OR NOT(a))", "Encountered"},
+		{"(a", "Encountered <EOF>"},
+		{"OR(a)", "Encountered \")\""},
+		{"a b", "Encountered \"b\""},
+		{"\"a", "Lexical error"},
+	} {
+		if _, err := Parse(c[0]); err == nil {
+			t.Errorf("Expected %v to fail", c[0])
+		} else if !strings.Contains(err.Error(), c[1]) {
+			t.Errorf("Expected %v to fail with %v, but was %v", c[0], c[1], err)
+		}
+	}
+}
+
+func TestSurroundQuery(t *testing.T) {
+	dir, err := store.OpenFSDirectory(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := index.NewIndexWriter(dir, index.NewIndexWriterConfig(util.VERSION_LATEST, std.NewStandardAnalyzer()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, text := range []string{
+		"word1 word2 word3",
+		"word4 word5",
+		"ord1 ord2 ord3",
+		"orda1 orda2 orda3 word2 worda3",
+	} {
+		doc := docu.NewDocument()
+		doc.Add(docu.NewTextFieldFromString("text", text, docu.STORE_YES))
+		if err = w.AddDocument(doc.Fields()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	r, err := index.OpenDirectoryReader(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	ss := search.NewIndexSearcher(r)
+
+	hits := func(query string, expected ...int) {
+		sq, err := Parse(query)
+		if err != nil {
+			t.Fatalf("%v: %v", query, err)
+		}
+		docs, err := ss.SearchTop(sq.MakeLuceneQueryField("text", NewBasicQueryFactory()), 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if docs.TotalHits != len(expected) {
+			t.Errorf("Expected %v to match %v, but was %v", query, expected, docs.ScoreDocs)
+			return
+		}
+		found := make(map[int]bool)
+		for _, scoreDoc := range docs.ScoreDocs {
+			found[scoreDoc.Doc] = true
+		}
+		for _, doc := range expected {
+			if !found[doc] {
+				t.Errorf("Expected %v to match %v, but was %v", query, expected, docs.ScoreDocs)
+			}
+		}
+	}
+
+	// boolean
+	hits("word1", 0)
+	hits("word1 AND word2", 0)
+	hits("word1 OR word4", 0, 1)
+	hits("word* NOT word1", 1, 3)
+	hits("word2 NOT word1", 3)
+	hits("AND(word2, orda1)", 3)
+	hits("wor?1", 0)
+	hits("\"word\"*", 0, 1, 3)
+	hits("kxork*")
+	hits("other: word1")
+	hits("other: text: word1", 0)
+
+	// distance
+	hits("word1 w word2", 0)
+	hits("word2 w word1")
+	hits("word2 n word1", 0)
+	hits("word1 W word3")
+	hits("word1 2W word3", 0)
+	hits("word3 2W word1")
+	hits("word3 2N word1", 0)
+	hits("word* w word2", 0)
+	hits("word2 w word*", 0, 3)
+	hits("(orda2 OR orda3) W word*", 3)
+	hits("ord* W word*", 3)
+	hits("kxork* w word2")
+	hits("W(orda1, orda2, orda3)", 3)
+	hits("3N(orda3, orda1)", 3)
+	hits("N(orda3, orda1)")
+	hits("(orda1 W orda2) 3W worda3", 3)
+	hits("(orda1 W orda2) W worda3")
+	hits("word1 w word2 OR orda1 w orda2", 0, 3)
+}
+
+func TestTooManyBasicQueries(t *testing.T) {
+	dir, err := store.OpenFSDirectory(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := index.NewIndexWriter(dir, index.NewIndexWriterConfig(util.VERSION_LATEST, std.NewStandardAnalyzer()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := docu.NewDocument()
+	doc.Add(docu.NewTextFieldFromString("text", "word1 word2 word3", docu.STORE_YES))
+	if err = w.AddDocument(doc.Fields()); err != nil {
+		t.Fatal(err)
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	r, err := index.OpenDirectoryReader(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	sq, err := Parse("word* W word2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	qf := NewBasicQueryFactoryWithMax(3)
+	q := sq.MakeLuceneQueryField("text", qf)
+	if got, expected := q.ToString(""),
+		"DistanceRewriteQuery(text, (word* W word2), BasicQueryFactory(maxBasicQueries: 3, queriesMade: 0))"; got != expected {
+		t.Errorf("expected %v, but was %v", expected, got)
+	}
+	defer func() {
+		err, ok := recover().(*TooManyBasicQueries)
+		if !ok {
+			t.Fatalf("expected TooManyBasicQueries, but was %v", err)
+		}
+		if qf.NrQueriesMade() != 3 {
+			t.Errorf("expected 3 queries made, but was %v", qf.NrQueriesMade())
+		}
+	}()
+	q.Rewrite(r)
+}
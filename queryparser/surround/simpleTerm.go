@@ -0,0 +1,283 @@
+package surround
+
+import (
+	"bytes"
+	"github.com/balzaczyy/golucene/core/index"
+	"github.com/balzaczyy/golucene/core/search"
+	"sort"
+	"strings"
+)
+
+// surround/query/SimpleTerm.java
+
+/* Base type for queries that expand to terms. */
+type SimpleTerm interface {
+	SrndQuery
+	IsQuoted() bool
+	/* Calls visit for each term of the field matching this query. */
+	VisitMatchingTerms(reader index.IndexReader, fieldName string, visit func(*index.Term) error) error
+}
+
+type simpleTermSPI interface {
+	SimpleTerm
+	toStringUnquoted() string
+	// appended after the quotes
+	suffix() string
+}
+
+type abstractSimpleTerm struct {
+	*abstractSrndQuery
+	spi    simpleTermSPI
+	quoted bool
+}
+
+func newAbstractSimpleTerm(spi simpleTermSPI, quoted bool) *abstractSimpleTerm {
+	return &abstractSimpleTerm{newAbstractSrndQuery(spi), spi, quoted}
+}
+
+func (q *abstractSimpleTerm) IsQuoted() bool {
+	return q.quoted
+}
+
+func (q *abstractSimpleTerm) suffix() string {
+	return ""
+}
+
+func (q *abstractSimpleTerm) String() string {
+	var buf bytes.Buffer
+	if q.quoted {
+		buf.WriteRune('"')
+	}
+	buf.WriteString(q.spi.toStringUnquoted())
+	if q.quoted {
+		buf.WriteRune('"')
+	}
+	buf.WriteString(q.spi.suffix())
+	q.weightToString(&buf)
+	return buf.String()
+}
+
+func (q *abstractSimpleTerm) distanceSubQueryNotAllowed() string {
+	return ""
+}
+
+func (q *abstractSimpleTerm) addSpanQueries(sncf *spanNearClauseFactory) error {
+	return q.spi.VisitMatchingTerms(sncf.reader, sncf.fieldName, func(term *index.Term) error {
+		return sncf.addTermWeighted(term, q.weight)
+	})
+}
+
+func (q *abstractSimpleTerm) MakeLuceneQueryFieldNoBoost(fieldName string, qf *BasicQueryFactory) search.Query {
+	ans := new(SimpleTermRewriteQuery)
+	ans.rewriteQuery = newRewriteQuery(ans, "SimpleTermRewriteQuery", q.spi, fieldName, qf)
+	return ans
+}
+
+/*
+Calls visit for the sorted distinct terms of the field accepted in any
+leaf of the reader.
+*/
+func visitTerms(reader index.IndexReader, fieldName string,
+	accept func([]byte) bool, visit func(*index.Term) error) error {
+
+	seen := make(map[string]bool)
+	var matching []string
+	for _, leaf := range reader.Leaves() {
+		terms := leaf.Reader().(index.AtomicReader).Terms(fieldName)
+		if terms == nil {
+			continue
+		}
+		termsEnum := terms.Iterator(nil)
+		for {
+			term, err := termsEnum.Next()
+			if err != nil {
+				return err
+			}
+			if term == nil {
+				break
+			}
+			if !seen[string(term)] && accept(term) {
+				seen[string(term)] = true
+				matching = append(matching, string(term))
+			}
+		}
+	}
+	sort.Strings(matching)
+	for _, text := range matching {
+		if err := visit(index.NewTerm(fieldName, text)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// surround/query/SimpleTermRewriteQuery.java
+
+/*
+The query of a SimpleTerm, rewritten to a TermQuery of its matching
+term, or a BooleanQuery of optional TermQuery when several terms
+match.
+*/
+type SimpleTermRewriteQuery struct {
+	*rewriteQuery
+}
+
+func (q *SimpleTermRewriteQuery) Rewrite(reader index.IndexReader) search.Query {
+	var subQueries []search.Query
+	if err := q.srndQuery.(SimpleTerm).VisitMatchingTerms(reader, q.fieldName, func(term *index.Term) error {
+		tq, err := q.qf.NewTermQuery(term)
+		if err == nil {
+			subQueries = append(subQueries, tq)
+		}
+		return err
+	}); err != nil {
+		panic(err) // Rewrite() has no error to return
+	}
+	var ans search.Query
+	switch len(subQueries) {
+	case 0:
+		ans = search.NewBooleanQuery() // matches nothing
+	case 1:
+		ans = subQueries[0]
+	default:
+		ans = makeBooleanQuery(subQueries, search.SHOULD)
+	}
+	ans.SetBoost(q.Boost() * ans.Boost())
+	return ans
+}
+
+// surround/query/SrndTermQuery.java
+
+/* Simple single-term clause. */
+type SrndTermQuery struct {
+	*abstractSimpleTerm
+	termText string
+}
+
+func NewSrndTermQuery(termText string, quoted bool) *SrndTermQuery {
+	ans := &SrndTermQuery{termText: termText}
+	ans.abstractSimpleTerm = newAbstractSimpleTerm(ans, quoted)
+	return ans
+}
+
+func (q *SrndTermQuery) TermText() string {
+	return q.termText
+}
+
+func (q *SrndTermQuery) toStringUnquoted() string {
+	return q.termText
+}
+
+func (q *SrndTermQuery) VisitMatchingTerms(reader index.IndexReader, fieldName string,
+	visit func(*index.Term) error) error {
+
+	// check term presence in index here for symmetry with other SimpleTerm's
+	term := index.NewTerm(fieldName, q.termText)
+	docFreq, err := reader.DocFreq(term)
+	if err != nil || docFreq == 0 {
+		return err
+	}
+	return visit(term)
+}
+
+// surround/query/SrndPrefixQuery.java
+
+/* Query that matches terms with a given prefix, like "abc*". */
+type SrndPrefixQuery struct {
+	*abstractSimpleTerm
+	prefix    string
+	truncator rune
+}
+
+func NewSrndPrefixQuery(prefix string, quoted bool, truncator rune) *SrndPrefixQuery {
+	ans := &SrndPrefixQuery{prefix: prefix, truncator: truncator}
+	ans.abstractSimpleTerm = newAbstractSimpleTerm(ans, quoted)
+	return ans
+}
+
+func (q *SrndPrefixQuery) Prefix() string {
+	return q.prefix
+}
+
+func (q *SrndPrefixQuery) toStringUnquoted() string {
+	return q.prefix
+}
+
+func (q *SrndPrefixQuery) suffix() string {
+	return string(q.truncator)
+}
+
+func (q *SrndPrefixQuery) VisitMatchingTerms(reader index.IndexReader, fieldName string,
+	visit func(*index.Term) error) error {
+
+	return visitTerms(reader, fieldName, func(term []byte) bool {
+		return bytes.HasPrefix(term, []byte(q.prefix))
+	}, visit)
+}
+
+// surround/query/SrndTruncQuery.java
+
+/*
+Query that matches terms with truncations: the unlimited truncation
+matches any sequence of characters, and the mask exactly one
+character, like "a?c*".
+*/
+type SrndTruncQuery struct {
+	*abstractSimpleTerm
+	truncated string
+	unlimited rune
+	mask      rune
+}
+
+func NewSrndTruncQuery(truncated string, unlimited, mask rune) *SrndTruncQuery {
+	ans := &SrndTruncQuery{truncated: truncated, unlimited: unlimited, mask: mask}
+	ans.abstractSimpleTerm = newAbstractSimpleTerm(ans, false) // not quoted
+	return ans
+}
+
+func (q *SrndTruncQuery) Truncated() string {
+	return q.truncated
+}
+
+func (q *SrndTruncQuery) toStringUnquoted() string {
+	return q.truncated
+}
+
+func (q *SrndTruncQuery) VisitMatchingTerms(reader index.IndexReader, fieldName string,
+	visit func(*index.Term) error) error {
+
+	prefix := q.truncated
+	if i := strings.IndexAny(prefix, string([]rune{q.unlimited, q.mask})); i >= 0 {
+		prefix = prefix[:i]
+	}
+	pattern := []rune(q.truncated)
+	return visitTerms(reader, fieldName, func(term []byte) bool {
+		return bytes.HasPrefix(term, []byte(prefix)) &&
+			q.matches(pattern, []rune(string(term)))
+	}, visit)
+}
+
+/* Returns whether the text matches the pattern of truncations. */
+func (q *SrndTruncQuery) matches(pattern, text []rune) bool {
+	// backtrack to the last unlimited truncation on mismatch
+	p, t, starP, starT := 0, 0, -1, 0
+	for t < len(text) {
+		switch {
+		case p < len(pattern) && pattern[p] == q.unlimited:
+			starP, starT = p, t
+			p++
+		case p < len(pattern) && (pattern[p] == q.mask || pattern[p] == text[t]):
+			p++
+			t++
+		case starP >= 0:
+			starT++
+			p, t = starP+1, starT
+		default:
+			return false
+		}
+	}
+	for p < len(pattern) && pattern[p] == q.unlimited {
+		p++
+	}
+	return p == len(pattern)
+}
@@ -0,0 +1,298 @@
+package surround
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/balzaczyy/golucene/core/search"
+)
+
+// surround/query/SrndQuery.java
+
+/* Lowest level base type for surround queries. */
+type SrndQuery interface {
+	SetWeight(w float32)
+	Weight() float32
+	IsWeighted() bool
+	/*
+		Builds the Lucene query searching the field, boosted by the
+		weight of this query.
+	*/
+	MakeLuceneQueryField(fieldName string, qf *BasicQueryFactory) search.Query
+	MakeLuceneQueryFieldNoBoost(fieldName string, qf *BasicQueryFactory) search.Query
+	String() string
+}
+
+type abstractSrndQuery struct {
+	spi      SrndQuery
+	weight   float32
+	weighted bool
+}
+
+func newAbstractSrndQuery(spi SrndQuery) *abstractSrndQuery {
+	return &abstractSrndQuery{spi: spi, weight: 1}
+}
+
+func (q *abstractSrndQuery) SetWeight(w float32) {
+	q.weight = w // as parsed from the query text
+	q.weighted = true
+}
+
+func (q *abstractSrndQuery) Weight() float32 {
+	return q.weight
+}
+
+func (q *abstractSrndQuery) IsWeighted() bool {
+	return q.weighted
+}
+
+func (q *abstractSrndQuery) weightToString(buf *bytes.Buffer) {
+	if q.weighted {
+		fmt.Fprintf(buf, "^%v", q.weight)
+	}
+}
+
+func (q *abstractSrndQuery) MakeLuceneQueryField(fieldName string, qf *BasicQueryFactory) search.Query {
+	ans := q.spi.MakeLuceneQueryFieldNoBoost(fieldName, qf)
+	if q.weighted {
+		ans.SetBoost(q.weight * ans.Boost()) // weight may be at any level in a SrndQuery
+	}
+	return ans
+}
+
+// surround/query/ComposedQuery.java
+
+/* Base type for composite queries, such as AND/OR/NOT and distance. */
+type ComposedQuery struct {
+	*abstractSrndQuery
+	queries []SrndQuery
+	infix   bool
+	opName  string
+}
+
+func newComposedQuery(spi SrndQuery, queries []SrndQuery, infix bool, opName string) *ComposedQuery {
+	if len(queries) < 2 {
+		panic(fmt.Sprintf("Too few subqueries: %v", len(queries)))
+	}
+	return &ComposedQuery{newAbstractSrndQuery(spi), queries, infix, opName}
+}
+
+func (q *ComposedQuery) SubQueries() []SrndQuery {
+	return q.queries
+}
+
+func (q *ComposedQuery) OperatorName() string {
+	return q.opName
+}
+
+func (q *ComposedQuery) IsOperatorInfix() bool {
+	return q.infix
+}
+
+func (q *ComposedQuery) makeLuceneSubQueriesField(fieldName string, qf *BasicQueryFactory) []search.Query {
+	ans := make([]search.Query, len(q.queries))
+	for i, sub := range q.queries {
+		ans[i] = sub.MakeLuceneQueryField(fieldName, qf)
+	}
+	return ans
+}
+
+func (q *ComposedQuery) String() string {
+	var buf bytes.Buffer
+	if q.infix {
+		// brackets are possibly redundant in the result
+		buf.WriteRune('(')
+		for i, sub := range q.queries {
+			if i > 0 {
+				fmt.Fprintf(&buf, " %v ", q.opName)
+			}
+			buf.WriteString(sub.String())
+		}
+		buf.WriteRune(')')
+	} else {
+		buf.WriteString(q.opName) // prefix operator
+		buf.WriteRune('(')
+		for i, sub := range q.queries {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			buf.WriteString(sub.String())
+		}
+		buf.WriteRune(')')
+	}
+	q.weightToString(&buf)
+	return buf.String()
+}
+
+// surround/query/SrndBooleanQuery.java
+
+func makeBooleanQuery(queries []search.Query, occur search.Occur) search.Query {
+	bq := search.NewBooleanQueryDisableCoord(true)
+	for _, q := range queries {
+		bq.Add(q, occur)
+	}
+	return bq
+}
+
+// surround/query/AndQuery.java
+
+/* Factory for conjunctions. */
+type AndQuery struct {
+	*ComposedQuery
+}
+
+func NewAndQuery(queries []SrndQuery, infix bool, opName string) *AndQuery {
+	ans := new(AndQuery)
+	ans.ComposedQuery = newComposedQuery(ans, queries, infix, opName)
+	return ans
+}
+
+func (q *AndQuery) MakeLuceneQueryFieldNoBoost(fieldName string, qf *BasicQueryFactory) search.Query {
+	return makeBooleanQuery(q.makeLuceneSubQueriesField(fieldName, qf), search.MUST)
+}
+
+// surround/query/OrQuery.java
+
+/* Factory for disjunctions. */
+type OrQuery struct {
+	*ComposedQuery
+}
+
+func NewOrQuery(queries []SrndQuery, infix bool, opName string) *OrQuery {
+	ans := new(OrQuery)
+	ans.ComposedQuery = newComposedQuery(ans, queries, infix, opName)
+	return ans
+}
+
+func (q *OrQuery) MakeLuceneQueryFieldNoBoost(fieldName string, qf *BasicQueryFactory) search.Query {
+	return makeBooleanQuery(q.makeLuceneSubQueriesField(fieldName, qf), search.SHOULD)
+}
+
+func (q *OrQuery) distanceSubQueryNotAllowed() string {
+	for _, sub := range q.queries {
+		dsq, ok := sub.(distanceSubQuery)
+		if !ok {
+			return fmt.Sprintf("subquery not allowed: %v", sub)
+		}
+		if m := dsq.distanceSubQueryNotAllowed(); m != "" {
+			return m
+		}
+	}
+	return ""
+}
+
+func (q *OrQuery) addSpanQueries(sncf *spanNearClauseFactory) error {
+	// the subqueries are all DistanceSubQuery as checked when parsed
+	for _, sub := range q.queries {
+		if err := sub.(distanceSubQuery).addSpanQueries(sncf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// surround/query/NotQuery.java
+
+/* Factory for prohibited clauses. */
+type NotQuery struct {
+	*ComposedQuery
+}
+
+func NewNotQuery(queries []SrndQuery, opName string) *NotQuery {
+	ans := new(NotQuery)
+	ans.ComposedQuery = newComposedQuery(ans, queries, true, opName)
+	return ans
+}
+
+func (q *NotQuery) MakeLuceneQueryFieldNoBoost(fieldName string, qf *BasicQueryFactory) search.Query {
+	subQueries := q.makeLuceneSubQueriesField(fieldName, qf)
+	bq := search.NewBooleanQuery()
+	bq.Add(subQueries[0], search.MUST)
+	for _, sub := range subQueries[1:] {
+		bq.Add(sub, search.MUST_NOT)
+	}
+	return bq
+}
+
+// surround/query/FieldsQuery.java
+
+/* Forwards to the query its fields, searching any of them. */
+type FieldsQuery struct {
+	*abstractSrndQuery
+	q          SrndQuery
+	fieldNames []string
+	fieldOp    rune
+}
+
+func NewFieldsQuery(q SrndQuery, fieldNames []string, fieldOp rune) *FieldsQuery {
+	ans := &FieldsQuery{q: q, fieldNames: fieldNames, fieldOp: fieldOp}
+	ans.abstractSrndQuery = newAbstractSrndQuery(ans)
+	return ans
+}
+
+func (q *FieldsQuery) FieldNames() []string {
+	return q.fieldNames
+}
+
+/* Builds the query of the fields of this query, the fieldName is ignored. */
+func (q *FieldsQuery) MakeLuceneQueryFieldNoBoost(fieldName string, qf *BasicQueryFactory) search.Query {
+	if len(q.fieldNames) == 1 { // single field name: no new queries needed
+		return q.q.MakeLuceneQueryFieldNoBoost(q.fieldNames[0], qf)
+	}
+	// OR query over the fields
+	queries := make([]search.Query, len(q.fieldNames))
+	for i, field := range q.fieldNames {
+		queries[i] = q.q.MakeLuceneQueryField(field, qf)
+	}
+	return makeBooleanQuery(queries, search.SHOULD)
+}
+
+func (q *FieldsQuery) String() string {
+	var buf bytes.Buffer
+	buf.WriteRune('(')
+	for _, field := range q.fieldNames {
+		buf.WriteString(field)
+		buf.WriteRune(q.fieldOp)
+	}
+	buf.WriteString(q.q.String())
+	buf.WriteRune(')')
+	return buf.String()
+}
+
+// surround/query/RewriteQuery.java
+
+/*
+A Lucene query deferring the building of the query of a surround
+query until it is rewritten, as the terms matching it depend on the
+index. As Rewrite() has no error to return, rewriting panics with a
+TooManyBasicQueries error if too many terms match.
+*/
+type rewriteQuery struct {
+	*search.AbstractQuery
+	name      string
+	srndQuery SrndQuery
+	fieldName string
+	qf        *BasicQueryFactory
+}
+
+func newRewriteQuery(spi search.QuerySPI, name string, srndQuery SrndQuery,
+	fieldName string, qf *BasicQueryFactory) *rewriteQuery {
+
+	return &rewriteQuery{search.NewAbstractQuery(spi), name, srndQuery, fieldName, qf}
+}
+
+func (q *rewriteQuery) CreateWeight(ss *search.IndexSearcher) (search.Weight, error) {
+	return nil, fmt.Errorf("%v does not support CreateWeight, it must be rewritten first", q.name)
+}
+
+func (q *rewriteQuery) ToString(field string) string {
+	var buf bytes.Buffer
+	buf.WriteString(q.name)
+	if field != "" {
+		fmt.Fprintf(&buf, "(unused: %v)", field)
+	}
+	fmt.Fprintf(&buf, "(%v, %v, %v)", q.fieldName, q.srndQuery, q.qf)
+	if q.Boost() != 1 {
+		fmt.Fprintf(&buf, "^%v", q.Boost())
+	}
+	return buf.String()
+}
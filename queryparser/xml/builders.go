@@ -0,0 +1,334 @@
+package xml
+
+import (
+	"github.com/balzaczyy/golucene/core/analysis"
+	ta "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	"github.com/balzaczyy/golucene/core/index"
+	"github.com/balzaczyy/golucene/core/search"
+	"github.com/balzaczyy/golucene/core/util"
+	"github.com/balzaczyy/golucene/queryparser/classic"
+)
+
+/* Sets the boost attribute of the element, if any, on the query. */
+func boost(q search.Query, e *Element) (search.Query, error) {
+	b, err := e.FloatAttribute("boost", 1)
+	if err != nil {
+		return nil, err
+	}
+	q.SetBoost(b)
+	return q, nil
+}
+
+/* Returns the terms the analyzer produces for the text of the field. */
+func analyzeTerms(analyzer analysis.Analyzer, field, text string) (terms []string, err error) {
+	ts, err := analyzer.TokenStreamForString(field, text)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if e := ts.Close(); err == nil {
+			err = e
+		}
+	}()
+	termAtt := ts.Attributes().Add("CharTermAttribute").(ta.CharTermAttribute)
+	if err = ts.Reset(); err != nil {
+		return nil, err
+	}
+	for {
+		ok, err := ts.IncrementToken()
+		if err != nil {
+			return nil, newParserError("IOException parsing value: %v", err)
+		}
+		if !ok {
+			break
+		}
+		terms = append(terms, string(termAtt.Buffer()[:termAtt.Length()]))
+	}
+	return terms, ts.End()
+}
+
+// xml/builders/TermQueryBuilder.java
+
+/* Builds a TermQuery of the text, which is not analyzed. */
+func buildTermQuery(e *Element) (search.Query, error) {
+	field, err := e.AttributeWithInheritanceOrFail("fieldName")
+	if err != nil {
+		return nil, err
+	}
+	value, err := e.NonBlankTextOrFail()
+	if err != nil {
+		return nil, err
+	}
+	return boost(search.NewTermQuery(index.NewTerm(field, value)), e)
+}
+
+// xml/builders/TermsQueryBuilder.java
+
+/*
+Builds a BooleanQuery of optional TermQuery clauses, one for each term
+the analyzer produces from the text.
+*/
+type TermsQueryBuilder struct {
+	analyzer analysis.Analyzer
+}
+
+func (b *TermsQueryBuilder) GetQuery(e *Element) (search.Query, error) {
+	field, err := e.AttributeWithInheritanceOrFail("fieldName")
+	if err != nil {
+		return nil, err
+	}
+	minimumNumberShouldMatch, err := e.IntAttribute("minimumNumberShouldMatch", 0)
+	if err != nil {
+		return nil, err
+	}
+	terms, err := analyzeTerms(b.analyzer, field, e.Text())
+	if err != nil {
+		return nil, err
+	}
+	bq := search.NewBooleanQueryDisableCoord(e.BoolAttribute("disableCoord", false))
+	bq.SetMinimumNumberShouldMatch(minimumNumberShouldMatch)
+	for _, term := range terms {
+		bq.Add(search.NewTermQuery(index.NewTerm(field, term)), search.SHOULD)
+	}
+	return boost(bq, e)
+}
+
+// xml/builders/MatchAllDocsQueryBuilder.java
+
+func buildMatchAllDocsQuery(e *Element) (search.Query, error) {
+	return boost(search.NewMatchAllDocsQuery(), e)
+}
+
+// xml/builders/BooleanQueryBuilder.java
+
+/*
+Builds a BooleanQuery of the Clause child elements, each holding a
+query and an "occurs" attribute of "must", "mustNot" or "should"
+(the default).
+*/
+type BooleanQueryBuilder struct {
+	factory QueryBuilder
+}
+
+func (b *BooleanQueryBuilder) GetQuery(e *Element) (search.Query, error) {
+	minimumNumberShouldMatch, err := e.IntAttribute("minimumNumberShouldMatch", 0)
+	if err != nil {
+		return nil, err
+	}
+	bq := search.NewBooleanQueryDisableCoord(e.BoolAttribute("disableCoord", false))
+	bq.SetMinimumNumberShouldMatch(minimumNumberShouldMatch)
+	for _, clause := range e.Children {
+		if clause.Name != "Clause" {
+			continue
+		}
+		occurs, err := occursOf(clause.Attribute("occurs", ""))
+		if err != nil {
+			return nil, err
+		}
+		child, err := clause.FirstChildOrFail()
+		if err != nil {
+			return nil, err
+		}
+		q, err := b.factory.GetQuery(child)
+		if err != nil {
+			return nil, err
+		}
+		bq.Add(q, occurs)
+	}
+	return boost(bq, e)
+}
+
+func occursOf(occs string) (search.Occur, error) {
+	switch occs {
+	case "", "should":
+		return search.SHOULD, nil
+	case "must":
+		return search.MUST, nil
+	case "mustNot":
+		return search.MUST_NOT, nil
+	}
+	return 0, newParserError("Invalid value for \"occurs\" attribute of clause:%v", occs)
+}
+
+// xml/builders/UserInputQueryBuilder.java
+
+/*
+Builds a query from the text with the classic QueryParser, searching
+the fieldName attribute, or the default field.
+*/
+type UserInputQueryBuilder struct {
+	defaultField string
+	analyzer     analysis.Analyzer
+}
+
+func (b *UserInputQueryBuilder) GetQuery(e *Element) (search.Query, error) {
+	text := e.Text()
+	field := e.Attribute("fieldName", b.defaultField)
+	q, err := classic.NewQueryParser(util.VERSION_LATEST, field, b.analyzer).Parse(text)
+	if err != nil {
+		return nil, newParserError("%v", err)
+	}
+	return boost(q, e)
+}
+
+// xml/builders/RangeQueryBuilder.java
+
+/* Builds a TermRangeQuery of the lowerTerm and upperTerm attributes. */
+func buildRangeQuery(e *Element) (search.Query, error) {
+	field, err := e.AttributeWithInheritanceOrFail("fieldName")
+	if err != nil {
+		return nil, err
+	}
+	lowerTerm, err := e.AttributeOrFail("lowerTerm")
+	if err != nil {
+		return nil, err
+	}
+	upperTerm, err := e.AttributeOrFail("upperTerm")
+	if err != nil {
+		return nil, err
+	}
+	return boost(search.NewTermRangeQueryFromStrings(field, &lowerTerm, &upperTerm,
+		e.BoolAttribute("includeLower", true), e.BoolAttribute("includeUpper", true)), e)
+}
+
+// xml/builders/DisjunctionMaxQueryBuilder.java
+
+/* Builds a DisjunctionMaxQuery of the query child elements. */
+type DisjunctionMaxQueryBuilder struct {
+	factory QueryBuilder
+}
+
+func (b *DisjunctionMaxQueryBuilder) GetQuery(e *Element) (search.Query, error) {
+	tieBreaker, err := e.FloatAttribute("tieBreaker", 0)
+	if err != nil {
+		return nil, err
+	}
+	var disjuncts []search.Query
+	for _, child := range e.Children {
+		q, err := b.factory.GetQuery(child)
+		if err != nil {
+			return nil, err
+		}
+		disjuncts = append(disjuncts, q)
+	}
+	return boost(search.NewDisjunctionMaxQuery(disjuncts, tieBreaker), e)
+}
+
+// xml/builders/SpanTermBuilder.java
+
+/* Builds a SpanTermQuery of the text, which is not analyzed. */
+type SpanTermBuilder struct{}
+
+func (b SpanTermBuilder) GetQuery(e *Element) (search.Query, error) {
+	return b.GetSpanQuery(e)
+}
+
+func (b SpanTermBuilder) GetSpanQuery(e *Element) (search.SpanQuery, error) {
+	field, err := e.AttributeWithInheritanceOrFail("fieldName")
+	if err != nil {
+		return nil, err
+	}
+	value, err := e.NonBlankTextOrFail()
+	if err != nil {
+		return nil, err
+	}
+	return boostSpan(search.NewSpanTermQuery(index.NewTerm(field, value)), e)
+}
+
+func boostSpan(q search.SpanQuery, e *Element) (search.SpanQuery, error) {
+	if _, err := boost(q, e); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// xml/builders/SpanOrTermsBuilder.java
+
+/* Builds a SpanOrQuery of the terms the analyzer produces from the text. */
+type SpanOrTermsBuilder struct {
+	analyzer analysis.Analyzer
+}
+
+func (b *SpanOrTermsBuilder) GetQuery(e *Element) (search.Query, error) {
+	return b.GetSpanQuery(e)
+}
+
+func (b *SpanOrTermsBuilder) GetSpanQuery(e *Element) (search.SpanQuery, error) {
+	field, err := e.AttributeWithInheritanceOrFail("fieldName")
+	if err != nil {
+		return nil, err
+	}
+	value, err := e.NonBlankTextOrFail()
+	if err != nil {
+		return nil, err
+	}
+	terms, err := analyzeTerms(b.analyzer, field, value)
+	if err != nil {
+		return nil, err
+	}
+	clauses := make([]search.SpanQuery, len(terms))
+	for i, term := range terms {
+		clauses[i] = search.NewSpanTermQuery(index.NewTerm(field, term))
+	}
+	return boostSpan(search.NewSpanOrQuery(clauses...), e)
+}
+
+// xml/builders/SpanOrBuilder.java
+
+/* Builds a SpanOrQuery of the span child elements. */
+type SpanOrBuilder struct {
+	factory *SpanQueryBuilderFactory
+}
+
+func (b *SpanOrBuilder) GetQuery(e *Element) (search.Query, error) {
+	return b.GetSpanQuery(e)
+}
+
+func (b *SpanOrBuilder) GetSpanQuery(e *Element) (search.SpanQuery, error) {
+	clauses, err := b.factory.spanChildren(e)
+	if err != nil {
+		return nil, err
+	}
+	return boostSpan(search.NewSpanOrQuery(clauses...), e)
+}
+
+// xml/builders/SpanNearBuilder.java
+
+/*
+Builds a SpanNearQuery of the span child elements, with the required
+slop attribute and the inOrder attribute, false by default.
+*/
+type SpanNearBuilder struct {
+	factory *SpanQueryBuilderFactory
+}
+
+func (b *SpanNearBuilder) GetQuery(e *Element) (search.Query, error) {
+	return b.GetSpanQuery(e)
+}
+
+func (b *SpanNearBuilder) GetSpanQuery(e *Element) (search.SpanQuery, error) {
+	if _, err := e.AttributeOrFail("slop"); err != nil {
+		return nil, err
+	}
+	slop, err := e.IntAttribute("slop", 0)
+	if err != nil {
+		return nil, err
+	}
+	clauses, err := b.factory.spanChildren(e)
+	if err != nil {
+		return nil, err
+	}
+	return boostSpan(search.NewSpanNearQuery(clauses, slop, e.BoolAttribute("inOrder", false)), e)
+}
+
+/* Builds the span queries of the child elements. */
+func (f *SpanQueryBuilderFactory) spanChildren(e *Element) ([]search.SpanQuery, error) {
+	clauses := make([]search.SpanQuery, len(e.Children))
+	for i, child := range e.Children {
+		var err error
+		if clauses[i], err = f.GetSpanQuery(child); err != nil {
+			return nil, err
+		}
+	}
+	return clauses, nil
+}
@@ -0,0 +1,189 @@
+package xml
+
+import (
+	"fmt"
+	"github.com/balzaczyy/golucene/core/analysis"
+	"github.com/balzaczyy/golucene/core/search"
+	"io"
+	"strings"
+)
+
+// xml/ParserException.java
+
+/* Error raised when the XML document does not describe a valid query. */
+type ParserError struct {
+	msg string
+}
+
+func newParserError(format string, args ...interface{}) *ParserError {
+	return &ParserError{fmt.Sprintf(format, args...)}
+}
+
+func (err *ParserError) Error() string {
+	return err.msg
+}
+
+// xml/QueryBuilder.java
+
+/* Implemented by objects that produce Lucene Query objects from XML elements. */
+type QueryBuilder interface {
+	GetQuery(e *Element) (search.Query, error)
+}
+
+/* Adapts a function to a QueryBuilder. */
+type QueryBuilderFunc func(e *Element) (search.Query, error)
+
+func (f QueryBuilderFunc) GetQuery(e *Element) (search.Query, error) {
+	return f(e)
+}
+
+// xml/QueryBuilderFactory.java
+
+/* Dispatches the building of an element to the builder of its name. */
+type QueryBuilderFactory struct {
+	builders map[string]QueryBuilder
+}
+
+func NewQueryBuilderFactory() *QueryBuilderFactory {
+	return &QueryBuilderFactory{make(map[string]QueryBuilder)}
+}
+
+func (f *QueryBuilderFactory) GetQuery(e *Element) (search.Query, error) {
+	builder, ok := f.builders[e.Name]
+	if !ok {
+		return nil, newParserError("No QueryObjectBuilder defined for node %v", e.Name)
+	}
+	return builder.GetQuery(e)
+}
+
+func (f *QueryBuilderFactory) AddBuilder(nodeName string, builder QueryBuilder) {
+	f.builders[nodeName] = builder
+}
+
+func (f *QueryBuilderFactory) QueryBuilder(nodeName string) QueryBuilder {
+	return f.builders[nodeName]
+}
+
+// xml/builders/SpanQueryBuilder.java
+
+/* Implemented by objects that produce span queries from XML elements. */
+type SpanQueryBuilder interface {
+	QueryBuilder
+	GetSpanQuery(e *Element) (search.SpanQuery, error)
+}
+
+// xml/builders/SpanQueryBuilderFactory.java
+
+/* Dispatches the building of a span element to the builder of its name. */
+type SpanQueryBuilderFactory struct {
+	builders map[string]SpanQueryBuilder
+}
+
+func NewSpanQueryBuilderFactory() *SpanQueryBuilderFactory {
+	return &SpanQueryBuilderFactory{make(map[string]SpanQueryBuilder)}
+}
+
+func (f *SpanQueryBuilderFactory) GetQuery(e *Element) (search.Query, error) {
+	return f.GetSpanQuery(e)
+}
+
+func (f *SpanQueryBuilderFactory) GetSpanQuery(e *Element) (search.SpanQuery, error) {
+	builder, ok := f.builders[e.Name]
+	if !ok {
+		return nil, newParserError("No SpanQueryObjectBuilder defined for node %v", e.Name)
+	}
+	return builder.GetSpanQuery(e)
+}
+
+func (f *SpanQueryBuilderFactory) AddBuilder(nodeName string, builder SpanQueryBuilder) {
+	f.builders[nodeName] = builder
+}
+
+// xml/CoreParser.java
+
+/*
+Assembles a QueryBuilder which uses only core Lucene Query objects,
+building a query from an XML document like:
+
+	<BooleanQuery fieldName="contents">
+		<Clause occurs="must"><TermQuery>merger</TermQuery></Clause>
+		<Clause occurs="mustNot"><TermsQuery>sumitomo bank</TermsQuery></Clause>
+	</BooleanQuery>
+
+Each element is built by the builder registered for its name, so the
+set of elements can be extended with AddQueryBuilder and
+AddSpanQueryBuilder. The fieldName attribute is inherited from the
+enclosing elements.
+*/
+type CoreParser struct {
+	analyzer     analysis.Analyzer
+	queryFactory *QueryBuilderFactory
+	spanFactory  *SpanQueryBuilderFactory
+}
+
+/*
+Constructs an XML parser that uses the given analyzer for the elements
+whose text is analyzed, and defaultField for the UserQuery elements
+without a fieldName attribute.
+*/
+func NewCoreParser(defaultField string, analyzer analysis.Analyzer) *CoreParser {
+	ans := &CoreParser{
+		analyzer:     analyzer,
+		queryFactory: NewQueryBuilderFactory(),
+		spanFactory:  NewSpanQueryBuilderFactory(),
+	}
+	qf := ans.queryFactory
+	qf.AddBuilder("TermQuery", QueryBuilderFunc(buildTermQuery))
+	qf.AddBuilder("TermsQuery", &TermsQueryBuilder{analyzer})
+	qf.AddBuilder("MatchAllDocsQuery", QueryBuilderFunc(buildMatchAllDocsQuery))
+	qf.AddBuilder("BooleanQuery", &BooleanQueryBuilder{qf})
+	qf.AddBuilder("UserQuery", &UserInputQueryBuilder{defaultField, analyzer})
+	qf.AddBuilder("RangeQuery", QueryBuilderFunc(buildRangeQuery))
+	qf.AddBuilder("DisjunctionMaxQuery", &DisjunctionMaxQueryBuilder{qf})
+
+	sf := ans.spanFactory
+	ans.AddSpanQueryBuilder("SpanNear", &SpanNearBuilder{sf})
+	ans.AddSpanQueryBuilder("SpanOr", &SpanOrBuilder{sf})
+	ans.AddSpanQueryBuilder("SpanTerm", SpanTermBuilder{})
+	ans.AddSpanQueryBuilder("SpanOrTerms", &SpanOrTermsBuilder{analyzer})
+	return ans
+}
+
+/* Parses the XML document into a query. */
+func (p *CoreParser) Parse(xmlStream io.Reader) (search.Query, error) {
+	e, err := parseDocument(xmlStream)
+	if err != nil {
+		if _, ok := err.(*ParserError); ok {
+			return nil, err
+		}
+		return nil, newParserError("Error parsing XML stream: %v", err)
+	}
+	return p.queryFactory.GetQuery(e)
+}
+
+func (p *CoreParser) ParseString(xmlText string) (search.Query, error) {
+	return p.Parse(strings.NewReader(xmlText))
+}
+
+func (p *CoreParser) Analyzer() analysis.Analyzer {
+	return p.analyzer
+}
+
+func (p *CoreParser) AddQueryBuilder(nodeName string, builder QueryBuilder) {
+	p.queryFactory.AddBuilder(nodeName, builder)
+}
+
+/* Adds a builder of span queries, also usable for the element as a query. */
+func (p *CoreParser) AddSpanQueryBuilder(nodeName string, builder SpanQueryBuilder) {
+	p.spanFactory.AddBuilder(nodeName, builder)
+	p.queryFactory.AddBuilder(nodeName, builder)
+}
+
+/* Returns the factory to build the elements nested in custom builders. */
+func (p *CoreParser) QueryFactory() *QueryBuilderFactory {
+	return p.queryFactory
+}
+
+func (p *CoreParser) SpanFactory() *SpanQueryBuilderFactory {
+	return p.spanFactory
+}
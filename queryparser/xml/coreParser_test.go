@@ -0,0 +1,163 @@
+package xml
+
+import (
+	std "github.com/balzaczyy/golucene/analysis/standard"
+	_ "github.com/balzaczyy/golucene/core/codec/lucene410"
+	docu "github.com/balzaczyy/golucene/core/document"
+	"github.com/balzaczyy/golucene/core/index"
+	"github.com/balzaczyy/golucene/core/search"
+	"github.com/balzaczyy/golucene/core/store"
+	"github.com/balzaczyy/golucene/core/util"
+	"strings"
+	"testing"
+)
+
+func assertXMLQueryEquals(t *testing.T, p *CoreParser, xmlText, expected string) {
+	q, err := p.ParseString(xmlText)
+	if err != nil {
+		t.Errorf("%v: %v", xmlText, err)
+		return
+	}
+	if got := q.ToString("contents"); got != expected {
+		t.Errorf("Query /%v/ yielded /%v/, expecting /%v/", xmlText, got, expected)
+	}
+}
+
+func TestCoreParser(t *testing.T) {
+	p := NewCoreParser("contents", std.NewStandardAnalyzer())
+	for _, c := range [][2]string{
+		{`<TermQuery fieldName="contents">sumitomo</TermQuery>`, "sumitomo"},
+		{`<TermQuery fieldName="title" boost="2">Sumitomo</TermQuery>`, "title:Sumitomo^2"},
+		{`<TermsQuery fieldName="contents">Merger of the Banks</TermsQuery>`, "merger banks"},
+		{`<TermsQuery fieldName="contents" minimumNumberShouldMatch="2">merger banks rate</TermsQuery>`,
+			"(merger banks rate)~2"},
+		{`<MatchAllDocsQuery/>`, "*:*"},
+		{`<BooleanQuery fieldName="contents">
+			<Clause occurs="should"><TermQuery>merger</TermQuery></Clause>
+			<Clause occurs="mustNot"><TermQuery>sumitomo</TermQuery></Clause>
+			<Clause occurs="must"><TermQuery fieldName="title">bank</TermQuery></Clause>
+		</BooleanQuery>`, "merger -sumitomo +title:bank"},
+		{`<BooleanQuery fieldName="contents" boost="3">
+			<Clause><BooleanQuery><Clause><TermQuery>nested</TermQuery></Clause></BooleanQuery></Clause>
+			<Clause><TermQuery>merger</TermQuery></Clause>
+		</BooleanQuery>`, "((nested) merger)^3"},
+		{`<UserQuery>"merger bank" OR title:rate^2</UserQuery>`, "\"merger bank\" title:rate^2"},
+		{`<UserQuery fieldName="title" boost="2">merger</UserQuery>`, "title:merger^2"},
+		{`<RangeQuery fieldName="date" lowerTerm="19870409" upperTerm="19870412"/>`, "date:[19870409 TO 19870412]"},
+		{`<RangeQuery fieldName="date" lowerTerm="a" upperTerm="b" includeUpper="false"/>`, "date:[a TO b}"},
+		{`<DisjunctionMaxQuery tieBreaker="0.1" fieldName="contents">
+			<TermQuery>merger</TermQuery>
+			<TermQuery fieldName="title">merger</TermQuery>
+		</DisjunctionMaxQuery>`, "(merger | title:merger)~0.1"},
+		{`<SpanNear slop="2" inOrder="true" fieldName="contents">
+			<SpanOr>
+				<SpanTerm>killed</SpanTerm>
+				<SpanTerm>died</SpanTerm>
+			</SpanOr>
+			<SpanOrTerms>Passenger Crew</SpanOrTerms>
+		</SpanNear>`, "spanNear([spanOr([killed, died]), spanOr([passenger, crew])], 2, true)"},
+		{`<SpanTerm fieldName="contents" boost="2">killed</SpanTerm>`, "killed^2"},
+	} {
+		assertXMLQueryEquals(t, p, c[0], c[1])
+	}
+}
+
+func TestCoreParserErrors(t *testing.T) {
+	p := NewCoreParser("contents", std.NewStandardAnalyzer())
+	for _, c := range [][2]string{
+		{`<TermQuery>sumitomo</TermQuery>`, `TermQuery missing "fieldName" attribute`},
+		{`<TermQuery fieldName="contents"> </TermQuery>`, `TermQuery missing "value" text`},
+		{`<TermQuery fieldName="contents" boost="x">a</TermQuery>`, `TermQuery has invalid "boost" attribute: x`},
+		{`<FooQuery/>`, "No QueryObjectBuilder defined for node FooQuery"},
+		{`<BooleanQuery><Clause occurs="maybe"><MatchAllDocsQuery/></Clause></BooleanQuery>`,
+			`Invalid value for "occurs" attribute of clause:maybe`},
+		{`<BooleanQuery><Clause/></BooleanQuery>`, "Clause does not contain a child element"},
+		{`<SpanNear fieldName="f"><SpanTerm>a</SpanTerm></SpanNear>`, `SpanNear missing "slop" attribute`},
+		{`<SpanOr><TermQuery fieldName="f">a</TermQuery></SpanOr>`, "No SpanQueryObjectBuilder defined for node TermQuery"},
+		{`<UserQuery>a AND</UserQuery>`, "Cannot parse 'a AND'"},
+		{`<TermQuery fieldName="contents">a</Term>`, "Error parsing XML stream"},
+		{``, "Missing root element"},
+	} {
+		if _, err := p.ParseString(c[0]); err == nil {
+			t.Errorf("Expected %v to fail", c[0])
+		} else if _, ok := err.(*ParserError); !ok || !strings.Contains(err.Error(), c[1]) {
+			t.Errorf("Expected %v to fail with %v, but was %v", c[0], c[1], err)
+		}
+	}
+}
+
+func TestCoreParserCustomBuilder(t *testing.T) {
+	p := NewCoreParser("contents", std.NewStandardAnalyzer())
+	// a builder of a prefix query, nested in a core element
+	p.AddQueryBuilder("PrefixQuery", QueryBuilderFunc(func(e *Element) (search.Query, error) {
+		field, err := e.AttributeWithInheritanceOrFail("fieldName")
+		if err != nil {
+			return nil, err
+		}
+		return search.NewPrefixQuery(index.NewTerm(field, e.Text())), nil
+	}))
+	assertXMLQueryEquals(t, p, `<BooleanQuery fieldName="contents">
+		<Clause occurs="must"><PrefixQuery>merg</PrefixQuery></Clause>
+		<Clause occurs="must"><TermQuery>bank</TermQuery></Clause>
+	</BooleanQuery>`, "+merg* +bank")
+}
+
+func TestCoreParserSearch(t *testing.T) {
+	dir, err := store.OpenFSDirectory(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := std.NewStandardAnalyzer()
+	w, err := index.NewIndexWriter(dir, index.NewIndexWriterConfig(util.VERSION_LATEST, a))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, text := range []string{
+		"the passenger was killed in the crash",
+		"the crew died at sea",
+		"no one was killed, the crew survived",
+	} {
+		doc := docu.NewDocument()
+		doc.Add(docu.NewTextFieldFromString("contents", text, docu.STORE_YES))
+		if err = w.AddDocument(doc.Fields()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	r, err := index.OpenDirectoryReader(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	ss := search.NewIndexSearcher(r)
+
+	p := NewCoreParser("contents", a)
+	hits := func(xmlText string, expected int) {
+		q, err := p.ParseString(xmlText)
+		if err != nil {
+			t.Fatal(err)
+		}
+		docs, err := ss.SearchTop(q, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if docs.TotalHits != expected {
+			t.Errorf("Expected %v hits for %v, but was %v", expected, q, docs.TotalHits)
+		}
+	}
+	hits(`<SpanNear slop="1" inOrder="false" fieldName="contents">
+		<SpanOrTerms>killed died</SpanOrTerms>
+		<SpanOrTerms>passenger crew</SpanOrTerms>
+	</SpanNear>`, 3)
+	hits(`<SpanNear slop="0" inOrder="false" fieldName="contents">
+		<SpanOrTerms>killed died</SpanOrTerms>
+		<SpanOrTerms>passenger crew</SpanOrTerms>
+	</SpanNear>`, 1)
+	hits(`<BooleanQuery fieldName="contents">
+		<Clause occurs="must"><TermsQuery>killed died</TermsQuery></Clause>
+		<Clause occurs="mustNot"><UserQuery>survived</UserQuery></Clause>
+	</BooleanQuery>`, 2)
+	hits(`<MatchAllDocsQuery/>`, 3)
+}
@@ -0,0 +1,159 @@
+package xml
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+)
+
+/*
+An element of the parsed XML document, with its attributes, child
+elements and text.
+*/
+type Element struct {
+	Name     string
+	Attrs    map[string]string
+	Children []*Element
+	Parent   *Element
+	text     strings.Builder
+}
+
+/* Parses the document, returning its root element. */
+func parseDocument(r io.Reader) (*Element, error) {
+	decoder := xml.NewDecoder(r)
+	var root, current *Element
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch token := token.(type) {
+		case xml.StartElement:
+			e := &Element{Name: token.Name.Local, Attrs: make(map[string]string), Parent: current}
+			for _, attr := range token.Attr {
+				e.Attrs[attr.Name.Local] = attr.Value
+			}
+			if current == nil {
+				root = e
+			} else {
+				current.Children = append(current.Children, e)
+			}
+			current = e
+		case xml.EndElement:
+			current = current.Parent
+		case xml.CharData:
+			if current != nil {
+				current.text.Write(token)
+			}
+		}
+	}
+	if root == nil {
+		return nil, newParserError("Missing root element")
+	}
+	return root, nil
+}
+
+// xml/DOMUtils.java
+
+/* Returns the text of the element, trimmed. */
+func (e *Element) Text() string {
+	return strings.TrimSpace(e.text.String())
+}
+
+func (e *Element) NonBlankTextOrFail() (string, error) {
+	if text := e.Text(); text != "" {
+		return text, nil
+	}
+	return "", newParserError("%v missing \"value\" text", e.Name)
+}
+
+/* Returns the attribute, or def if the element does not have it. */
+func (e *Element) Attribute(name, def string) string {
+	if value, ok := e.Attrs[name]; ok {
+		return value
+	}
+	return def
+}
+
+func (e *Element) AttributeOrFail(name string) (string, error) {
+	if value, ok := e.Attrs[name]; ok {
+		return value, nil
+	}
+	return "", newParserError("%v missing \"%v\" attribute", e.Name, name)
+}
+
+/* Returns the attribute of the element, or else of the closest ancestor. */
+func (e *Element) AttributeWithInheritance(name string) (string, bool) {
+	for ; e != nil; e = e.Parent {
+		if value, ok := e.Attrs[name]; ok {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+func (e *Element) AttributeWithInheritanceOrFail(name string) (string, error) {
+	if value, ok := e.AttributeWithInheritance(name); ok {
+		return value, nil
+	}
+	return "", newParserError("%v missing \"%v\" attribute", e.Name, name)
+}
+
+func (e *Element) FloatAttribute(name string, def float32) (float32, error) {
+	value, ok := e.Attrs[name]
+	if !ok {
+		return def, nil
+	}
+	f, err := strconv.ParseFloat(value, 32)
+	if err != nil {
+		return 0, newParserError("%v has invalid \"%v\" attribute: %v", e.Name, name, value)
+	}
+	return float32(f), nil
+}
+
+func (e *Element) IntAttribute(name string, def int) (int, error) {
+	value, ok := e.Attrs[name]
+	if !ok {
+		return def, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, newParserError("%v has invalid \"%v\" attribute: %v", e.Name, name, value)
+	}
+	return n, nil
+}
+
+func (e *Element) BoolAttribute(name string, def bool) bool {
+	if value, ok := e.Attrs[name]; ok {
+		return value == "true"
+	}
+	return def
+}
+
+/* Returns the first child element with the name, or nil. */
+func (e *Element) ChildByTagName(name string) *Element {
+	for _, child := range e.Children {
+		if child.Name == name {
+			return child
+		}
+	}
+	return nil
+}
+
+func (e *Element) ChildByTagNameOrFail(name string) (*Element, error) {
+	if child := e.ChildByTagName(name); child != nil {
+		return child, nil
+	}
+	return nil, newParserError("%v missing \"%v\" child element", e.Name, name)
+}
+
+func (e *Element) FirstChildOrFail() (*Element, error) {
+	if len(e.Children) > 0 {
+		return e.Children[0], nil
+	}
+	return nil, newParserError("%v does not contain a child element", e.Name)
+}
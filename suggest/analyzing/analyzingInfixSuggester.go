@@ -0,0 +1,565 @@
+package analyzing
+
+import (
+	"errors"
+	"github.com/balzaczyy/golucene/core/analysis"
+	ta "github.com/balzaczyy/golucene/core/analysis/tokenattributes"
+	docu "github.com/balzaczyy/golucene/core/document"
+	"github.com/balzaczyy/golucene/core/index"
+	"github.com/balzaczyy/golucene/core/search"
+	"github.com/balzaczyy/golucene/core/store"
+	"github.com/balzaczyy/golucene/core/util"
+	"github.com/balzaczyy/golucene/suggest"
+	"sort"
+	"strings"
+)
+
+// search/suggest/analyzing/AnalyzingInfixSuggester.java
+
+const (
+	// Field name used for the indexed text.
+	TEXT_FIELD_NAME = "text"
+	// Field name used for the indexed text, as a StringField, for
+	// exact lookup.
+	EXACT_TEXT_FIELD_NAME = "exacttext"
+	// Field name used for the indexed context, as a StringField and
+	// stored field, for filtering.
+	CONTEXTS_FIELD_NAME = "contexts"
+	// Field name used for the prefixes of each indexed token.
+	TEXTGRAMS_FIELD_NAME = "textgrams"
+	// Field name used for the stored weight.
+	WEIGHT_FIELD_NAME = "weight"
+	// Field name used for the stored payload.
+	PAYLOAD_FIELD_NAME = "payloads"
+)
+
+/*
+Analyzes the input text and then suggests matches based on prefix
+matches to any tokens in the indexed text. This also highlights the
+tokens that match.
+
+This suggester supports payloads and contexts. Matches are sorted
+only by the suggest weight; it would be nice to support blended
+score + weight sort in the future. This means this suggester best
+applies when there is a strong a-priori ranking of all the
+suggestions.
+
+This suggester maintains its own small index of the suggestions in
+the provided Directory, so new suggestions can be added or existing
+ones updated after the initial Build(). Call Refresh() to make such
+changes visible to Lookup().
+
+Since there is no PrefixQuery yet, every prefix of each indexed token
+is indexed into a separate field (TEXTGRAMS_FIELD_NAME), which is what
+the last token of a lookup key is matched against.
+
+NOTE: like the analyzers it uses, this suggester is not safe for
+concurrent use by multiple goroutines.
+*/
+type AnalyzingInfixSuggester struct {
+	// Analyzer used at search time
+	queryAnalyzer analysis.Analyzer
+	// Analyzer used at index time
+	indexAnalyzer analysis.Analyzer
+	dir           store.Directory
+
+	allTermsRequired bool
+	highlight        bool
+
+	// Used for ongoing NRT additions/updates.
+	writer *index.IndexWriter
+	// the reader and searcher over the last refreshed index
+	reader   index.DirectoryReader
+	searcher *search.IndexSearcher
+}
+
+/*
+Create a new instance, loading from a previously built
+AnalyzingInfixSuggester directory, if it exists. This directory must
+be private to the infix suggester (i.e., not an external Lucene
+index). Note that Close() will also close the provided directory.
+*/
+func NewAnalyzingInfixSuggester(dir store.Directory, analyzer analysis.Analyzer) (*AnalyzingInfixSuggester, error) {
+	return NewAnalyzingInfixSuggesterWith(dir, analyzer, analyzer, true, true)
+}
+
+/*
+Create a new instance, loading from a previously built
+AnalyzingInfixSuggester directory, if it exists.
+
+allTermsRequired tells whether all terms of the lookup key must
+match (default true), and highlight tells whether the returned
+suggestions are highlighted (default true). Both can be overridden
+per lookup with LookupWith().
+*/
+func NewAnalyzingInfixSuggesterWith(dir store.Directory,
+	indexAnalyzer, queryAnalyzer analysis.Analyzer,
+	allTermsRequired, highlight bool) (*AnalyzingInfixSuggester, error) {
+
+	ans := &AnalyzingInfixSuggester{
+		queryAnalyzer:    queryAnalyzer,
+		indexAnalyzer:    indexAnalyzer,
+		dir:              dir,
+		allTermsRequired: allTermsRequired,
+		highlight:        highlight,
+	}
+
+	ok, err := index.IsIndexExists(dir)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		// Already built; open it:
+		if ans.reader, err = index.OpenDirectoryReader(dir); err != nil {
+			return nil, err
+		}
+		ans.searcher = search.NewIndexSearcher(ans.reader)
+	}
+	return ans, nil
+}
+
+func (s *AnalyzingInfixSuggester) indexWriterConfig(openMode index.OpenMode) *index.IndexWriterConfig {
+	return index.NewIndexWriterConfig(util.VERSION_LATEST, s.indexAnalyzer).SetOpenMode(openMode)
+}
+
+func (s *AnalyzingInfixSuggester) Build(iter suggest.InputIterator) (err error) {
+	if s.writer != nil {
+		if err = s.writer.Close(); err != nil {
+			return err
+		}
+		s.writer = nil
+	}
+
+	// First pass: build a temporary normal Lucene index, just indexing
+	// the suggestions as they iterate:
+	if s.writer, err = index.NewIndexWriter(s.dir,
+		s.indexWriterConfig(index.OPEN_MODE_CREATE)); err != nil {
+		return err
+	}
+	for {
+		text, err := iter.Next()
+		if err != nil {
+			return err
+		}
+		if text == nil {
+			break
+		}
+		var payload []byte
+		if iter.HasPayloads() {
+			payload = iter.Payload()
+		}
+		var contexts []string
+		if iter.HasContexts() {
+			contexts = iter.Contexts()
+		}
+		doc, err := s.buildDocument(string(text), contexts, iter.Weight(), payload)
+		if err != nil {
+			return err
+		}
+		if err = s.writer.AddDocument(doc.Fields()); err != nil {
+			return err
+		}
+	}
+	return s.Refresh()
+}
+
+func (s *AnalyzingInfixSuggester) ensureOpen() error {
+	if s.writer == nil {
+		var err error
+		if s.writer, err = index.NewIndexWriter(s.dir,
+			s.indexWriterConfig(index.OPEN_MODE_CREATE_OR_APPEND)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/*
+Adds a new suggestion. Be sure to use Update() instead if you want
+to replace a previous suggestion. After adding or updating a batch of
+new suggestions, you must call Refresh() at the end in order to see
+the suggestions in Lookup().
+*/
+func (s *AnalyzingInfixSuggester) Add(text string, contexts []string, weight int64, payload []byte) error {
+	if err := s.ensureOpen(); err != nil {
+		return err
+	}
+	doc, err := s.buildDocument(text, contexts, weight, payload)
+	if err != nil {
+		return err
+	}
+	return s.writer.AddDocument(doc.Fields())
+}
+
+/*
+Updates a previous suggestion, matching the exact same text as
+before. Use this to change the weight or payload of an already added
+suggestion. If you know this text is not already present you can use
+Add() instead. After adding or updating a batch of new suggestions,
+you must call Refresh() at the end in order to see the suggestions in
+Lookup().
+*/
+func (s *AnalyzingInfixSuggester) Update(text string, contexts []string, weight int64, payload []byte) error {
+	if err := s.ensureOpen(); err != nil {
+		return err
+	}
+	doc, err := s.buildDocument(text, contexts, weight, payload)
+	if err != nil {
+		return err
+	}
+	return s.writer.UpdateDocument(index.NewTerm(EXACT_TEXT_FIELD_NAME, text),
+		doc.Fields(), s.indexAnalyzer)
+}
+
+func (s *AnalyzingInfixSuggester) buildDocument(text string, contexts []string,
+	weight int64, payload []byte) (*docu.Document, error) {
+
+	grams, err := s.prefixGrams(text)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := docu.NewDocument()
+	doc.Add(docu.NewTextFieldFromString(TEXT_FIELD_NAME, text, docu.STORE_YES))
+	doc.Add(docu.NewStringField(EXACT_TEXT_FIELD_NAME, text, docu.STORE_NO))
+	for _, gram := range grams {
+		doc.Add(docu.NewStringField(TEXTGRAMS_FIELD_NAME, gram, docu.STORE_NO))
+	}
+	doc.Add(docu.NewStoredFieldFromLong(WEIGHT_FIELD_NAME, weight))
+	if payload != nil {
+		doc.Add(docu.NewStoredFieldFromBytes(PAYLOAD_FIELD_NAME, payload))
+	}
+	for _, context := range contexts {
+		doc.Add(docu.NewStringField(CONTEXTS_FIELD_NAME, context, docu.STORE_YES))
+	}
+	return doc, nil
+}
+
+/*
+Returns the distinct prefixes of all tokens the index analyzer
+produces for the given text.
+*/
+func (s *AnalyzingInfixSuggester) prefixGrams(text string) (ans []string, err error) {
+	ts, err := s.indexAnalyzer.TokenStreamForString(TEXT_FIELD_NAME, text)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if e := ts.Close(); err == nil {
+			err = e
+		}
+	}()
+	termAtt := ts.Attributes().Add("CharTermAttribute").(ta.CharTermAttribute)
+
+	if err = ts.Reset(); err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	for {
+		ok, err := ts.IncrementToken()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			break
+		}
+		token := termAtt.Buffer()[:termAtt.Length()]
+		for i := 1; i <= len(token); i++ {
+			if gram := string(token[:i]); !seen[gram] {
+				seen[gram] = true
+				ans = append(ans, gram)
+			}
+		}
+	}
+	return ans, ts.End()
+}
+
+/*
+Reopens the underlying searcher; it's best to "batch up" many
+additions/updates, and then call Refresh() once in the end.
+*/
+func (s *AnalyzingInfixSuggester) Refresh() error {
+	if s.writer == nil {
+		return errors.New("suggester was not built")
+	}
+	if err := s.writer.Commit(); err != nil {
+		return err
+	}
+	reader, err := index.OpenDirectoryReader(s.dir)
+	if err != nil {
+		return err
+	}
+	if s.reader != nil {
+		if err = s.reader.Close(); err != nil {
+			return err
+		}
+	}
+	s.reader = reader
+	s.searcher = search.NewIndexSearcher(reader)
+	return nil
+}
+
+func (s *AnalyzingInfixSuggester) Lookup(key string, onlyMorePopular bool, num int) ([]*suggest.LookupResult, error) {
+	return s.LookupWith(key, nil, num, s.allTermsRequired, s.highlight)
+}
+
+/*
+Lookup, with context but without booleans. Context is nil if all
+contexts should be considered.
+*/
+func (s *AnalyzingInfixSuggester) LookupWithContexts(key string, contexts []string,
+	num int) ([]*suggest.LookupResult, error) {
+
+	return s.LookupWith(key, contexts, num, s.allTermsRequired, s.highlight)
+}
+
+/*
+Retrieve suggestions, specifying whether all terms must match
+(allTermsRequired) and whether the hits should be highlighted
+(doHighlight). If contexts is not nil, only suggestions indexed with
+at least one of the given contexts are returned.
+*/
+func (s *AnalyzingInfixSuggester) LookupWith(key string, contexts []string, num int,
+	allTermsRequired, doHighlight bool) (ans []*suggest.LookupResult, err error) {
+
+	if s.searcher == nil {
+		return nil, errors.New("suggester was not built")
+	}
+
+	occur := search.SHOULD
+	if allTermsRequired {
+		occur = search.MUST
+	}
+
+	// Don't highlight the tokens that were not part of the key
+	query, matchedTokens, prefixToken, err := s.buildQuery(key, occur)
+	if err != nil {
+		return nil, err
+	}
+	if contexts != nil {
+		sub := search.NewBooleanQuery()
+		for _, context := range contexts {
+			sub.Add(search.NewTermQuery(index.NewTerm(CONTEXTS_FIELD_NAME, context)), search.SHOULD)
+		}
+		query.Add(sub, search.MUST)
+	}
+
+	// TODO: we could allow blended sort here, combining weight w/
+	// score. Now we ignore score and sort only by weight:
+	topDocs, err := s.searcher.Search(query, nil, s.reader.MaxDoc()+1)
+	if err != nil {
+		return nil, err
+	}
+	for _, hit := range topDocs.ScoreDocs {
+		doc, err := s.reader.Document(hit.Doc)
+		if err != nil {
+			return nil, err
+		}
+		result := &suggest.LookupResult{
+			Key:     doc.Get(TEXT_FIELD_NAME),
+			Payload: doc.BinaryValue(PAYLOAD_FIELD_NAME),
+		}
+		if f := doc.Field(WEIGHT_FIELD_NAME); f != nil {
+			result.Value = f.NumericValue().(int64)
+		}
+		if values := doc.Values(CONTEXTS_FIELD_NAME); len(values) > 0 {
+			result.Contexts = values
+		}
+		if doHighlight {
+			if result.HighlightKey, err = s.highlightKey(result.Key, matchedTokens, prefixToken); err != nil {
+				return nil, err
+			}
+		}
+		ans = append(ans, result)
+	}
+	sort.Stable(byWeightDesc(ans))
+	if len(ans) > num {
+		ans = ans[:num]
+	}
+	return ans, nil
+}
+
+/*
+Analyzes the key with the query analyzer and builds the matching
+query. All tokens but the last one must match exactly; the last one
+is matched as a prefix, unless the key ends with discarded chars
+(e.g. whitespace).
+*/
+func (s *AnalyzingInfixSuggester) buildQuery(key string, occur search.Occur) (query *search.BooleanQuery,
+	matchedTokens map[string]bool, prefixToken string, err error) {
+
+	ts, err := s.queryAnalyzer.TokenStreamForString("", key)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	defer func() {
+		if e := ts.Close(); err == nil {
+			err = e
+		}
+	}()
+	termAtt := ts.Attributes().Add("CharTermAttribute").(ta.CharTermAttribute)
+	offsetAtt := ts.Attributes().Add("OffsetAttribute").(ta.OffsetAttribute)
+
+	if err = ts.Reset(); err != nil {
+		return nil, nil, "", err
+	}
+	query = search.NewBooleanQuery()
+	matchedTokens = make(map[string]bool)
+	var lastToken string
+	maxEndOffset := -1
+	for {
+		ok, err := ts.IncrementToken()
+		if err != nil {
+			return nil, nil, "", err
+		}
+		if !ok {
+			break
+		}
+		if lastToken != "" {
+			matchedTokens[lastToken] = true
+			query.Add(search.NewTermQuery(index.NewTerm(TEXT_FIELD_NAME, lastToken)), occur)
+		}
+		lastToken = string(termAtt.Buffer()[:termAtt.Length()])
+		if end := offsetAtt.EndOffset(); end > maxEndOffset {
+			maxEndOffset = end
+		}
+	}
+	if err = ts.End(); err != nil {
+		return nil, nil, "", err
+	}
+
+	if lastToken != "" {
+		if maxEndOffset == offsetAtt.EndOffset() {
+			// Use prefix matching when there was no trailing discarded
+			// chars in the string (e.g. whitespace), so that if query
+			// does not end with a space we show prefix matches for that
+			// token:
+			query.Add(search.NewTermQuery(index.NewTerm(TEXTGRAMS_FIELD_NAME, lastToken)), occur)
+			prefixToken = lastToken
+		} else {
+			// Use TermQuery for an exact match if there were trailing
+			// discarded chars (e.g. whitespace), so that if query ends
+			// with a space we only show exact matches for that term:
+			matchedTokens[lastToken] = true
+			query.Add(search.NewTermQuery(index.NewTerm(TEXT_FIELD_NAME, lastToken)), occur)
+		}
+	}
+	return query, matchedTokens, prefixToken, nil
+}
+
+/*
+Highlights the matched tokens of the given text, by re-analyzing it
+with the query analyzer. Whole matches and matched prefixes are
+wrapped in <b>..</b>.
+*/
+func (s *AnalyzingInfixSuggester) highlightKey(text string,
+	matchedTokens map[string]bool, prefixToken string) (ans string, err error) {
+
+	ts, err := s.queryAnalyzer.TokenStreamForString(TEXT_FIELD_NAME, text)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if e := ts.Close(); err == nil {
+			err = e
+		}
+	}()
+	termAtt := ts.Attributes().Add("CharTermAttribute").(ta.CharTermAttribute)
+	offsetAtt := ts.Attributes().Add("OffsetAttribute").(ta.OffsetAttribute)
+
+	if err = ts.Reset(); err != nil {
+		return "", err
+	}
+	runes := []rune(text)
+	var buf strings.Builder
+	upto := 0
+	for {
+		ok, err := ts.IncrementToken()
+		if err != nil {
+			return "", err
+		}
+		if !ok {
+			break
+		}
+		token := string(termAtt.Buffer()[:termAtt.Length()])
+		startOffset, endOffset := offsetAtt.StartOffset(), offsetAtt.EndOffset()
+		if upto < startOffset {
+			buf.WriteString(string(runes[upto:startOffset]))
+			upto = startOffset
+		} else if upto > startOffset {
+			continue
+		}
+
+		if matchedTokens[token] {
+			// Token matches.
+			addWholeMatch(&buf, string(runes[startOffset:endOffset]))
+			upto = endOffset
+		} else if prefixToken != "" && strings.HasPrefix(token, prefixToken) {
+			addPrefixMatch(&buf, runes[startOffset:endOffset], len([]rune(prefixToken)))
+			upto = endOffset
+		}
+	}
+	if err = ts.End(); err != nil {
+		return "", err
+	}
+	if upto < len(runes) {
+		buf.WriteString(string(runes[upto:]))
+	}
+	return buf.String(), nil
+}
+
+// Appends a whole matched token.
+func addWholeMatch(buf *strings.Builder, surface string) {
+	buf.WriteString("<b>")
+	buf.WriteString(surface)
+	buf.WriteString("</b>")
+}
+
+/*
+Appends a matched prefix token. Only the first prefixLen runes of
+the surface form are highlighted.
+*/
+func addPrefixMatch(buf *strings.Builder, surface []rune, prefixLen int) {
+	// TODO: apps can try to invert their analysis logic here, e.g.
+	// downcase the two before checking prefix:
+	if prefixLen >= len(surface) {
+		addWholeMatch(buf, string(surface))
+		return
+	}
+	buf.WriteString("<b>")
+	buf.WriteString(string(surface[:prefixLen]))
+	buf.WriteString("</b>")
+	buf.WriteString(string(surface[prefixLen:]))
+}
+
+func (s *AnalyzingInfixSuggester) Count() int64 {
+	if s.reader == nil {
+		return 0
+	}
+	return int64(s.reader.NumDocs())
+}
+
+/* Closes the writer and reader, if any, and the directory. */
+func (s *AnalyzingInfixSuggester) Close() error {
+	var err error
+	if s.writer != nil {
+		err = s.writer.Close()
+		s.writer = nil
+	}
+	if s.reader != nil {
+		if e := s.reader.Close(); err == nil {
+			err = e
+		}
+		s.reader, s.searcher = nil, nil
+	}
+	if e := s.dir.Close(); err == nil {
+		err = e
+	}
+	return err
+}
+
+type byWeightDesc []*suggest.LookupResult
+
+func (a byWeightDesc) Len() int           { return len(a) }
+func (a byWeightDesc) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a byWeightDesc) Less(i, j int) bool { return a[i].Value > a[j].Value }
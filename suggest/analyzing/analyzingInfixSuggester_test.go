@@ -0,0 +1,175 @@
+package analyzing
+
+import (
+	std "github.com/balzaczyy/golucene/analysis/standard"
+	_ "github.com/balzaczyy/golucene/core/codec/lucene410"
+	"github.com/balzaczyy/golucene/core/index"
+	"github.com/balzaczyy/golucene/core/search"
+	"github.com/balzaczyy/golucene/core/store"
+	"github.com/balzaczyy/golucene/suggest"
+	"testing"
+)
+
+func newInfixSuggester(t *testing.T, inputs ...*suggest.Input) *AnalyzingInfixSuggester {
+	index.DefaultSimilarity = func() index.Similarity {
+		return search.NewDefaultSimilarity()
+	}
+	dir, err := store.OpenFSDirectory(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewAnalyzingInfixSuggester(dir, std.NewStandardAnalyzer())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	if err = s.Build(suggest.NewInputArrayIterator(inputs...)); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestInfixBasic(t *testing.T) {
+	s := newInfixSuggester(t,
+		suggest.NewInputWithPayload("lend me your ear", 8, []byte("foobar")),
+		suggest.NewInputWithPayload("a penny saved is a penny earned", 10, []byte("foobaz")),
+	)
+	if s.Count() != 2 {
+		t.Errorf("expected 2 entries, but was %v", s.Count())
+	}
+
+	results, err := s.Lookup("ear", false, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertResults(t, results, "a penny saved is a penny earned/10", "lend me your ear/8")
+	if string(results[0].Payload) != "foobaz" || string(results[1].Payload) != "foobar" {
+		t.Errorf("unexpected payloads: %q, %q", results[0].Payload, results[1].Payload)
+	}
+	if h := results[0].HighlightKey; h != "a penny saved is a penny <b>ear</b>ned" {
+		t.Errorf("unexpected highlight: %v", h)
+	}
+	if h := results[1].HighlightKey; h != "lend me your <b>ear</b>" {
+		t.Errorf("unexpected highlight: %v", h)
+	}
+
+	results, err = s.Lookup("ear ", false, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertResults(t, results, "lend me your ear/8")
+
+	results, err = s.Lookup("pen", false, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertResults(t, results, "a penny saved is a penny earned/10")
+	if h := results[0].HighlightKey; h != "a <b>pen</b>ny saved is a <b>pen</b>ny earned" {
+		t.Errorf("unexpected highlight: %v", h)
+	}
+
+	results, err = s.Lookup("penny ea", false, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertResults(t, results, "a penny saved is a penny earned/10")
+	if h := results[0].HighlightKey; h != "a <b>penny</b> saved is a <b>penny</b> <b>ea</b>rned" {
+		t.Errorf("unexpected highlight: %v", h)
+	}
+
+	// all terms are required by default
+	results, err = s.Lookup("money penny", false, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertResults(t, results)
+
+	results, err = s.LookupWith("penny lend", nil, 10, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertResults(t, results, "a penny saved is a penny earned/10", "lend me your ear/8")
+	if results[0].HighlightKey != "" {
+		t.Errorf("expected no highlight, but was %v", results[0].HighlightKey)
+	}
+
+	results, err = s.Lookup("e", false, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertResults(t, results, "a penny saved is a penny earned/10")
+}
+
+func TestInfixContexts(t *testing.T) {
+	s := newInfixSuggester(t,
+		suggest.NewInputWithPayloadAndContexts("lend me your ear", 8, []byte("foobar"), "foo", "bar"),
+		suggest.NewInputWithPayloadAndContexts("a penny saved is a penny earned", 10, []byte("foobaz"), "foo", "baz"),
+	)
+
+	results, err := s.LookupWithContexts("ear", []string{"bar"}, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertResults(t, results, "lend me your ear/8")
+	if c := results[0].Contexts; len(c) != 2 || c[0] != "foo" || c[1] != "bar" {
+		t.Errorf("unexpected contexts: %v", c)
+	}
+
+	results, err = s.LookupWithContexts("ear", []string{"foo"}, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertResults(t, results, "a penny saved is a penny earned/10", "lend me your ear/8")
+
+	results, err = s.LookupWithContexts("ear", []string{"bar", "baz"}, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertResults(t, results, "a penny saved is a penny earned/10", "lend me your ear/8")
+
+	results, err = s.LookupWithContexts("ear", []string{"qux"}, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertResults(t, results)
+}
+
+func TestInfixAddUpdate(t *testing.T) {
+	s := newInfixSuggester(t,
+		suggest.NewInput("lend me your ear", 8),
+	)
+
+	if err := s.Add("a penny saved is a penny earned", nil, 10, nil); err != nil {
+		t.Fatal(err)
+	}
+	// not visible until refreshed
+	results, err := s.Lookup("ear", false, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertResults(t, results, "lend me your ear/8")
+
+	if err = s.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	results, err = s.Lookup("ear", false, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertResults(t, results, "a penny saved is a penny earned/10", "lend me your ear/8")
+
+	if err = s.Update("lend me your ear", nil, 12, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err = s.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	if s.Count() != 2 {
+		t.Errorf("expected 2 entries, but was %v", s.Count())
+	}
+	results, err = s.Lookup("ear", false, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertResults(t, results, "lend me your ear/12", "a penny saved is a penny earned/10")
+}
@@ -0,0 +1,686 @@
+package analyzing
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/balzaczyy/golucene/core/analysis"
+	"github.com/balzaczyy/golucene/core/util"
+	"github.com/balzaczyy/golucene/core/util/automaton"
+	"github.com/balzaczyy/golucene/core/util/fst"
+	"github.com/balzaczyy/golucene/core/util/packed"
+	"github.com/balzaczyy/golucene/suggest"
+	"math"
+	"sort"
+)
+
+// search/suggest/analyzing/AnalyzingSuggester.java
+
+const (
+	/*
+		Include this flag in the options parameter to
+		NewAnalyzingSuggesterWith() to always return the exact match
+		first, regardless of score. This has no performance impact
+		but could result in low-quality suggestions.
+	*/
+	EXACT_FIRST = 1
+	/*
+		Include this flag in the options parameter to
+		NewAnalyzingSuggesterWith() to preserve token separators when
+		matching.
+	*/
+	PRESERVE_SEP = 2
+
+	// Represents the separation between tokens, if PRESERVE_SEP was specified
+	SEP_LABEL = 0x1f
+	// Marks end of the analyzed input and start of dedup byte.
+	END_BYTE = 0x0
+	// Separates the surface form from the payload in the FST output.
+	PAYLOAD_SEP = 0x1f
+)
+
+/*
+Hooks overridden by FuzzySuggester to alter the automaton and the
+prefix paths used for lookups.
+*/
+type analyzingSuggesterSPI interface {
+	// Converts the automaton before finite strings are enumerated.
+	convertAutomaton(a *automaton.Automaton) *automaton.Automaton
+	// Returns all prefix paths to initialize the search.
+	fullPrefixPaths(prefixPaths []*Path, lookupAutomaton *automaton.Automaton, f *fst.FST) ([]*Path, error)
+}
+
+/*
+Suggester that first analyzes the surface form, adds the analyzed
+form to a weighted FST, and then does the same thing at lookup time.
+This means lookup is based on the analyzed form while suggestions are
+still the surface form(s).
+
+This can result in powerful suggester functionality. For example, if
+you use an analyzer removing stop words, then the partial text "ghost
+chr..." could see the suggestion "The Ghost of Christmas Past". Note
+that position increments MUST NOT be preserved for this example to
+work, so you should call the constructor with
+preservePositionIncrements set to false.
+
+If SynonymFilter is used to map wifi and wireless network to hotspot
+then the partial text "wirele..." could suggest "wifi router".
+Token normalization like stemmers, accent removal, etc., would allow
+suggestions to ignore such variations.
+
+When two matching suggestions have the same weight, they are tie-
+broken by the analyzed form. If their analyzed form is the same then
+the order is undefined.
+
+There are some limitations:
+
+  - A lookup from a query like "net" in English won't be any
+    different than "net " (ie, user added a trailing space) because
+    analyzers don't reflect when they've seen a token separator and
+    when they haven't.
+  - If you're using StopFilter, and the user will type "fast apple",
+    but so far all they've typed is "fast a", again because the
+    analyzer doesn't convey whether it's seen a token separator after
+    the "a", StopFilter will remove that "a" causing far more matches
+    than you'd expect.
+  - Lookups with the empty string return no results instead of all
+    results.
+*/
+type AnalyzingSuggester struct {
+	spi analyzingSuggesterSPI
+
+	/*
+		FST<Weight,Surface>: input is the analyzed form, with a null
+		byte between terms weights are encoded as costs:
+		(MaxInt32-weight) surface is the original, unanalyzed form.
+	*/
+	fst     *fst.FST
+	outputs *fst.PairOutputs
+
+	// Analyzer that will be used for analyzing suggestions at index time.
+	indexAnalyzer analysis.Analyzer
+	// Analyzer that will be used for analyzing suggestions at query time.
+	queryAnalyzer analysis.Analyzer
+
+	// True if exact match suggestions should always be returned first.
+	exactFirst bool
+	// True if separator between tokens should be preserved.
+	preserveSep bool
+
+	/*
+		Maximum number of dup surface forms (different surface forms
+		for the same analyzed form).
+	*/
+	maxSurfaceFormsPerAnalyzedForm int
+	/*
+		Maximum graph paths to index for a single analyzed surface
+		form. This only matters if your analyzer makes lots of
+		alternate paths (e.g. contains SynonymFilter).
+	*/
+	maxGraphExpansions int
+	/*
+		Highest number of analyzed paths we saw for any single input
+		surface form. For analyzers that never create graphs this will
+		always be 1.
+	*/
+	maxAnalyzedPathsForOneInput int
+
+	hasPayloads bool
+
+	// Whether position holes should appear in the automaton.
+	preservePositionIncrements bool
+
+	// Number of entries the lookup was built with
+	count int64
+}
+
+/*
+Calls NewAnalyzingSuggesterWith(analyzer, analyzer,
+EXACT_FIRST|PRESERVE_SEP, 256, -1, true).
+*/
+func NewAnalyzingSuggester(analyzer analysis.Analyzer) *AnalyzingSuggester {
+	return NewAnalyzingSuggesterWith(analyzer, analyzer, EXACT_FIRST|PRESERVE_SEP, 256, -1, true)
+}
+
+/*
+Creates a new suggester.
+
+indexAnalyzer analyzes the inputs at build time, and queryAnalyzer
+analyzes the lookup key. options are bitwise-or'd EXACT_FIRST and
+PRESERVE_SEP. maxSurfaceFormsPerAnalyzedForm is the maximum number
+of surface forms to keep for a single analyzed form; when there are
+too many surface forms we discard the lowest weighted ones.
+maxGraphExpansions is the maximum number of graph paths to expand
+from the analyzed form; set this to -1 for no limit.
+preservePositionIncrements tells whether position holes should
+appear in the automata.
+*/
+func NewAnalyzingSuggesterWith(indexAnalyzer, queryAnalyzer analysis.Analyzer,
+	options, maxSurfaceFormsPerAnalyzedForm, maxGraphExpansions int,
+	preservePositionIncrements bool) *AnalyzingSuggester {
+
+	assert2(options&^(EXACT_FIRST|PRESERVE_SEP) == 0,
+		"options should only contain EXACT_FIRST and PRESERVE_SEP; got %v", options)
+	// NOTE: this is just an implementation limitation; if really
+	// necessary we could allow more than 256 surface forms per
+	// analyzed form:
+	assert2(maxSurfaceFormsPerAnalyzedForm > 0 && maxSurfaceFormsPerAnalyzedForm <= 256,
+		"maxSurfaceFormsPerAnalyzedForm must be > 0 and <= 256 (got: %v)",
+		maxSurfaceFormsPerAnalyzedForm)
+	assert2(maxGraphExpansions >= 1 || maxGraphExpansions == -1,
+		"maxGraphExpansions must -1 (no limit) or > 0 (got: %v)", maxGraphExpansions)
+
+	ans := &AnalyzingSuggester{
+		outputs: fst.NewPairOutputs(
+			fst.PositiveIntOutputsSingleton(),
+			fst.ByteSequenceOutputsSingleton(),
+		),
+		indexAnalyzer:                  indexAnalyzer,
+		queryAnalyzer:                  queryAnalyzer,
+		exactFirst:                     options&EXACT_FIRST != 0,
+		preserveSep:                    options&PRESERVE_SEP != 0,
+		maxSurfaceFormsPerAnalyzedForm: maxSurfaceFormsPerAnalyzedForm,
+		maxGraphExpansions:             maxGraphExpansions,
+		preservePositionIncrements:     preservePositionIncrements,
+	}
+	ans.spi = ans
+	return ans
+}
+
+func (s *AnalyzingSuggester) convertAutomaton(a *automaton.Automaton) *automaton.Automaton {
+	return a
+}
+
+func (s *AnalyzingSuggester) fullPrefixPaths(prefixPaths []*Path,
+	lookupAutomaton *automaton.Automaton, f *fst.FST) ([]*Path, error) {
+	return prefixPaths, nil
+}
+
+/* Replaces POS_SEP with SEP_LABEL, or folds it away if !preserveSep. */
+func (s *AnalyzingSuggester) replaceSep(a *automaton.Automaton) *automaton.Automaton {
+	result := automaton.NewAutomatonBuilder()
+
+	// Copy all states over
+	result.CopyStates(a)
+
+	// Go in reverse topo sort so we know we only have to make one
+	// pass:
+	t := automaton.NewTransition()
+	topoSortStates := automaton.TopoSortStates(a)
+	for i := len(topoSortStates) - 1; i >= 0; i-- {
+		state := topoSortStates[i]
+		count := a.InitTransition(state, t)
+		for j := 0; j < count; j++ {
+			a.NextTransition(t)
+			switch t.Min {
+			case analysis.POS_SEP:
+				assert(t.Max == analysis.POS_SEP)
+				if s.preserveSep {
+					// Remap to SEP_LABEL:
+					result.AddTransition(state, t.Dest, SEP_LABEL)
+				} else {
+					result.AddEpsilon(state, t.Dest)
+				}
+			case analysis.HOLE:
+				assert(t.Max == analysis.HOLE)
+				// Just remove the hole: there will then be two SEP tokens
+				// next to each other, which will only match another hole
+				// at search time. Note that it will also match an
+				// empty-string token ... if that's somehow a problem we can
+				// always map HOLE to a dedicated byte (and escape it in the
+				// input).
+				result.AddEpsilon(state, t.Dest)
+			default:
+				result.AddTransitionRange(state, t.Dest, t.Min, t.Max)
+			}
+		}
+	}
+	return result.Finish()
+}
+
+func (s *AnalyzingSuggester) tokenStreamToAutomaton() *analysis.TokenStreamToAutomaton {
+	ts2a := analysis.NewTokenStreamToAutomaton()
+	if s.preserveSep {
+		// Escapes the SEP_LABEL byte so that it can't be confused with
+		// a token separator. When we're not preserving sep, we don't
+		// steal the byte, so we don't need to do any escaping.
+		ts2a.SetChangeToken(func(in []byte) []byte {
+			if bytes.IndexByte(in, SEP_LABEL) < 0 {
+				return in
+			}
+			var out []byte
+			for _, b := range in {
+				if b == SEP_LABEL {
+					out = append(out, SEP_LABEL)
+				}
+				out = append(out, b)
+			}
+			return out
+		})
+	}
+	ts2a.SetPreservePositionIncrements(s.preservePositionIncrements)
+	return ts2a
+}
+
+type analyzedInput struct {
+	analyzed []byte
+	cost     int64
+	surface  []byte
+	payload  []byte
+}
+
+/*
+Sorts the inputs by analyzed form, then cost, then surface form, the
+order in which they are added to the FST.
+*/
+type analyzedInputs []*analyzedInput
+
+func (a analyzedInputs) Len() int      { return len(a) }
+func (a analyzedInputs) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
+func (a analyzedInputs) Less(i, j int) bool {
+	if cmp := bytes.Compare(a[i].analyzed, a[j].analyzed); cmp != 0 {
+		return cmp < 0
+	}
+	if a[i].cost != a[j].cost {
+		return a[i].cost < a[j].cost
+	}
+	if cmp := bytes.Compare(a[i].surface, a[j].surface); cmp != 0 {
+		return cmp < 0
+	}
+	return bytes.Compare(a[i].payload, a[j].payload) < 0
+}
+
+func (s *AnalyzingSuggester) Build(iterator suggest.InputIterator) error {
+	if iterator.HasContexts() {
+		return errors.New("this suggester doesn't support contexts")
+	}
+	s.hasPayloads = iterator.HasPayloads()
+	s.count = 0
+	s.maxAnalyzedPathsForOneInput = 0
+
+	ts2a := s.tokenStreamToAutomaton()
+	var inputs analyzedInputs
+	for {
+		surfaceForm, err := iterator.Next()
+		if err != nil {
+			return err
+		}
+		if surfaceForm == nil {
+			break
+		}
+		cost, err := encodeWeight(iterator.Weight())
+		if err != nil {
+			return err
+		}
+		var payload []byte
+		if s.hasPayloads {
+			if bytes.IndexByte(surfaceForm, PAYLOAD_SEP) >= 0 {
+				return errors.New("surface form cannot contain unit separator character U+001F; this character is reserved")
+			}
+			payload = iterator.Payload()
+		}
+
+		paths, err := s.toFiniteStrings(string(surfaceForm), ts2a)
+		if err != nil {
+			return err
+		}
+		if len(paths) > s.maxAnalyzedPathsForOneInput {
+			s.maxAnalyzedPathsForOneInput = len(paths)
+		}
+		for _, path := range paths {
+			// length of the analyzed text (FST input)
+			if len(path) > math.MaxInt16-2 {
+				return fmt.Errorf("cannot handle analyzed forms > %v in length (got %v)",
+					math.MaxInt16-2, len(path))
+			}
+			inputs = append(inputs, &analyzedInput{
+				analyzed: path,
+				cost:     cost,
+				surface:  append([]byte(nil), surfaceForm...),
+				payload:  payload,
+			})
+		}
+		s.count++
+	}
+	sort.Sort(inputs)
+
+	builder := fst.NewBuilder(fst.INPUT_TYPE_BYTE1, 0, 0, true, true,
+		math.MaxInt32, s.outputs, false, packed.PackedInts.COMPACT, true, 15)
+	scratchInts := util.NewIntsRefBuilder()
+
+	// Used to remove duplicate surface forms (but we still index the
+	// highest-weight one). We clear this when we see a new analyzed
+	// form, so it cannot grow unbounded (at most 256 entries):
+	seenSurfaceForms := make(map[string]bool)
+
+	var previousAnalyzed []byte
+	dedup := 0
+	for _, input := range inputs {
+		if previousAnalyzed == nil {
+			previousAnalyzed = input.analyzed
+			seenSurfaceForms[string(input.surface)] = true
+		} else if bytes.Equal(input.analyzed, previousAnalyzed) {
+			dedup++
+			if dedup >= s.maxSurfaceFormsPerAnalyzedForm {
+				// More than maxSurfaceFormsPerAnalyzedForm dups: skip the
+				// rest:
+				continue
+			}
+			if seenSurfaceForms[string(input.surface)] {
+				continue
+			}
+			seenSurfaceForms[string(input.surface)] = true
+		} else {
+			dedup = 0
+			previousAnalyzed = input.analyzed
+			seenSurfaceForms = map[string]bool{string(input.surface): true}
+		}
+
+		// NOTE: must be byte 0 so we sort before whatever is next
+		analyzed := make([]byte, len(input.analyzed), len(input.analyzed)+2)
+		copy(analyzed, input.analyzed)
+		analyzed = append(analyzed, END_BYTE, byte(dedup))
+
+		output := input.surface
+		if s.hasPayloads {
+			output = make([]byte, 0, len(input.surface)+1+len(input.payload))
+			output = append(output, input.surface...)
+			output = append(output, PAYLOAD_SEP)
+			output = append(output, input.payload...)
+		}
+		var costOutput interface{} = fst.NO_OUTPUT
+		if input.cost != 0 {
+			costOutput = input.cost
+		}
+		var surfaceOutput interface{} = fst.NO_OUTPUT
+		if len(output) > 0 {
+			surfaceOutput = output
+		}
+		if err := builder.Add(fst.ToIntsRef(analyzed, scratchInts),
+			s.outputs.NewPair(costOutput, surfaceOutput)); err != nil {
+			return err
+		}
+	}
+
+	var err error
+	s.fst, err = builder.Finish()
+	return err
+}
+
+/*
+Returns all the analyzed forms of surfaceForm, as a set of byte
+strings.
+*/
+func (s *AnalyzingSuggester) toFiniteStrings(surfaceForm string,
+	ts2a *analysis.TokenStreamToAutomaton) ([][]byte, error) {
+
+	// Analyze surface form:
+	a, err := s.toAutomaton(s.indexAnalyzer, surfaceForm, ts2a)
+	if err != nil {
+		return nil, err
+	}
+	a = s.spi.convertAutomaton(s.replaceSep(a))
+
+	// Get all paths from the automaton (there can be more than one
+	// path, eg if the analyzer created a graph using SynFilter or
+	// WDF):
+	var ans [][]byte
+	seen := make(map[string]bool)
+	for _, path := range automaton.FiniteStrings(a, s.maxGraphExpansions) {
+		b := make([]byte, len(path))
+		for i, label := range path {
+			b[i] = byte(label)
+		}
+		if !seen[string(b)] {
+			seen[string(b)] = true
+			ans = append(ans, b)
+		}
+	}
+	return ans, nil
+}
+
+func (s *AnalyzingSuggester) toAutomaton(analyzer analysis.Analyzer, text string,
+	ts2a *analysis.TokenStreamToAutomaton) (a *automaton.Automaton, err error) {
+
+	ts, err := analyzer.TokenStreamForString("", text)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if e := ts.Close(); err == nil {
+			err = e
+		}
+	}()
+	// Create corresponding automaton: labels are bytes from each
+	// analyzed token, with byte 0 used as separator between tokens:
+	return ts2a.ToAutomaton(ts)
+}
+
+/* Returns the automaton of all analyzed forms of the lookup key. */
+func (s *AnalyzingSuggester) toLookupAutomaton(key string) (*automaton.Automaton, error) {
+	// Turn tokenstream into automaton:
+	a, err := s.toAutomaton(s.queryAnalyzer, key, s.tokenStreamToAutomaton())
+	if err != nil {
+		return nil, err
+	}
+	// TODO: we can optimize this somewhat by determinizing while we
+	// convert
+	return automaton.Determinize(s.replaceSep(a)), nil
+}
+
+func (s *AnalyzingSuggester) Lookup(key string, onlyMorePopular bool, num int) ([]*suggest.LookupResult, error) {
+	assert(num > 0)
+	if onlyMorePopular {
+		return nil, errors.New("this suggester only works with onlyMorePopular=false")
+	}
+	if s.fst == nil {
+		return nil, nil
+	}
+	for _, c := range key {
+		if c == analysis.HOLE {
+			return nil, errors.New("lookup key cannot contain HOLE character U+001E; this character is reserved")
+		}
+		if c == SEP_LABEL {
+			return nil, errors.New("lookup key cannot contain unit separator character U+001F; this character is reserved")
+		}
+	}
+	utf8Key := []byte(key)
+
+	lookupAutomaton, err := s.toLookupAutomaton(key)
+	if err != nil {
+		return nil, err
+	}
+
+	// Intersect automaton w/ suggest wFST and get all prefix starting
+	// nodes & their outputs:
+	bytesReader := s.fst.BytesReader()
+	scratchArc := &fst.Arc{}
+
+	var results []*suggest.LookupResult
+
+	prefixPaths, err := IntersectPrefixPaths(s.spi.convertAutomaton(lookupAutomaton), s.fst)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.exactFirst {
+		// This node has END_BYTE arc leaving, meaning it's an "exact"
+		// match:
+		var exactPaths []*Path
+		for _, path := range prefixPaths {
+			arc, err := s.fst.FindTargetArc(END_BYTE, path.FstNode, scratchArc, bytesReader)
+			if err != nil {
+				return nil, err
+			}
+			if arc != nil {
+				exactPaths = append(exactPaths, &Path{
+					path.State,
+					(&fst.Arc{}).CopyFrom(arc),
+					s.outputs.Add(path.Output, arc.Output),
+					path.Input,
+				})
+			}
+		}
+
+		if count := len(exactPaths); count > 0 {
+			// Searcher just to find the single exact only match, if
+			// present:
+			searcher := fst.NewTopNSearcher(s.fst,
+				count*s.maxSurfaceFormsPerAnalyzedForm,
+				count*s.maxSurfaceFormsPerAnalyzedForm,
+				s.weightComparator)
+
+			// NOTE: we could almost get away with only using the first
+			// start node. The only catch is if
+			// maxSurfaceFormsPerAnalyzedForm had kicked in and pruned our
+			// exact match from one of these nodes ...:
+			for _, path := range exactPaths {
+				if err = searcher.AddStartPaths(path.FstNode, path.Output, false, path.Input); err != nil {
+					return nil, err
+				}
+			}
+
+			completions, err := searcher.Search()
+			if err != nil {
+				return nil, err
+			}
+			assert(completions.IsComplete)
+
+			// NOTE: this is rather inefficient: we enumerate every
+			// matching "exactly the same analyzed form" path, and then do
+			// linear scan to see if one of these exactly matches the
+			// input. Still, it's bounded by how many prefix start nodes
+			// we have and the maxSurfaceFormsPerAnalyzedForm:
+			for _, completion := range completions.TopN {
+				if s.sameSurfaceForm(utf8Key, s.surface(completion.Output)) {
+					results = append(results, s.lookupResult(completion.Output))
+					break
+				}
+			}
+
+			if len(results) == num {
+				// That was quick:
+				return results, nil
+			}
+		}
+	}
+
+	searcher := fst.NewTopNSearcher(s.fst, num-len(results),
+		num*s.maxAnalyzedPathsForOneInput, s.weightComparator)
+	seen := make(map[string]bool)
+	searcher.AcceptResult = func(input []int, output interface{}) bool {
+		surface := s.surface(output)
+		// Dedup: when the input analyzes to a graph we can get
+		// duplicate surface forms:
+		if seen[string(surface)] {
+			return false
+		}
+		seen[string(surface)] = true
+
+		// In exactFirst mode, don't accept any paths matching the
+		// surface form since that will create duplicate results:
+		return !s.exactFirst || !s.sameSurfaceForm(utf8Key, surface)
+	}
+
+	if prefixPaths, err = s.spi.fullPrefixPaths(prefixPaths, lookupAutomaton, s.fst); err != nil {
+		return nil, err
+	}
+	for _, path := range prefixPaths {
+		if err = searcher.AddStartPaths(path.FstNode, path.Output, true, path.Input); err != nil {
+			return nil, err
+		}
+	}
+
+	completions, err := searcher.Search()
+	if err != nil {
+		return nil, err
+	}
+	assert(completions.IsComplete)
+
+	for _, completion := range completions.TopN {
+		results = append(results, s.lookupResult(completion.Output))
+		if len(results) == num {
+			// In the exactFirst=true case the search may produce one
+			// extra path
+			break
+		}
+	}
+	return results, nil
+}
+
+func (s *AnalyzingSuggester) Count() int64 {
+	return s.count
+}
+
+func (s *AnalyzingSuggester) weightComparator(a, b interface{}) int {
+	costA, costB := s.cost(a), s.cost(b)
+	if costA < costB {
+		return -1
+	} else if costA > costB {
+		return 1
+	}
+	return 0
+}
+
+func (s *AnalyzingSuggester) cost(output interface{}) int64 {
+	if cost, _ := s.outputs.Split(output); cost != fst.NO_OUTPUT {
+		return cost.(int64)
+	}
+	return 0
+}
+
+func (s *AnalyzingSuggester) surface(output interface{}) []byte {
+	if _, surface := s.outputs.Split(output); surface != fst.NO_OUTPUT {
+		return surface.([]byte)
+	}
+	return nil
+}
+
+func (s *AnalyzingSuggester) lookupResult(output interface{}) *suggest.LookupResult {
+	surface := s.surface(output)
+	weight := decodeWeight(s.cost(output))
+	if !s.hasPayloads {
+		return &suggest.LookupResult{Key: string(surface), Value: weight}
+	}
+	sepIndex := bytes.IndexByte(surface, PAYLOAD_SEP)
+	assert(sepIndex != -1)
+	return &suggest.LookupResult{
+		Key:     string(surface[:sepIndex]),
+		Value:   weight,
+		Payload: append([]byte(nil), surface[sepIndex+1:]...),
+	}
+}
+
+func (s *AnalyzingSuggester) sameSurfaceForm(key, output []byte) bool {
+	if !s.hasPayloads {
+		return bytes.Equal(key, output)
+	}
+	// output has at least PAYLOAD_SEP byte:
+	return len(key) < len(output) && bytes.HasPrefix(output, key) &&
+		output[len(key)] == PAYLOAD_SEP
+}
+
+/* cost -> weight */
+func decodeWeight(encoded int64) int64 {
+	return math.MaxInt32 - encoded
+}
+
+/* weight -> cost */
+func encodeWeight(value int64) (int64, error) {
+	if value < 0 || value > math.MaxInt32 {
+		return 0, fmt.Errorf("cannot handle weight %v", value)
+	}
+	return math.MaxInt32 - value, nil
+}
+
+func assert(ok bool) {
+	if !ok {
+		panic("assert fail")
+	}
+}
+
+func assert2(ok bool, msg string, args ...interface{}) {
+	if !ok {
+		panic(fmt.Sprintf(msg, args...))
+	}
+}
@@ -0,0 +1,150 @@
+package analyzing
+
+import (
+	std "github.com/balzaczyy/golucene/analysis/standard"
+	"github.com/balzaczyy/golucene/suggest"
+	"testing"
+)
+
+func assertResults(t *testing.T, results []*suggest.LookupResult, expected ...string) {
+	if len(results) != len(expected) {
+		t.Fatalf("expected %v results, but was %v", expected, results)
+	}
+	for i, r := range results {
+		if r.String() != expected[i] {
+			t.Errorf("result %v: expected %v, but was %v", i, expected[i], r)
+		}
+	}
+}
+
+func TestKeyword(t *testing.T) {
+	s := NewAnalyzingSuggester(std.NewStandardAnalyzer())
+	err := s.Build(suggest.NewInputArrayIterator(
+		suggest.NewInput("foo", 50),
+		suggest.NewInput("bar", 10),
+		suggest.NewInput("barbar", 12),
+		suggest.NewInput("barbara", 6),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Count() != 4 {
+		t.Errorf("expected 4 entries, but was %v", s.Count())
+	}
+
+	results, err := s.Lookup("f", false, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertResults(t, results, "foo/50")
+
+	// exact match first, despite its lower weight
+	results, err = s.Lookup("bar", false, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertResults(t, results, "bar/10", "barbar/12")
+
+	results, err = s.Lookup("barbara", false, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertResults(t, results, "barbara/6")
+
+	results, err = s.Lookup("bar", false, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertResults(t, results, "bar/10", "barbar/12", "barbara/6")
+}
+
+func TestExactFirstDisabled(t *testing.T) {
+	a := std.NewStandardAnalyzer()
+	s := NewAnalyzingSuggesterWith(a, a, PRESERVE_SEP, 256, -1, true)
+	err := s.Build(suggest.NewInputArrayIterator(
+		suggest.NewInput("bar", 10),
+		suggest.NewInput("barbar", 12),
+		suggest.NewInput("barbara", 6),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	results, err := s.Lookup("bar", false, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertResults(t, results, "barbar/12", "bar/10")
+}
+
+func TestMultiWord(t *testing.T) {
+	s := NewAnalyzingSuggester(std.NewStandardAnalyzer())
+	err := s.Build(suggest.NewInputArrayIterator(
+		suggest.NewInput("ghost chris", 40),
+		suggest.NewInput("ghostchris", 50),
+		suggest.NewInput("Ghost rider", 20),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// analyzed forms are lower-cased, but surface forms are returned
+	results, err := s.Lookup("ghost r", false, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertResults(t, results, "Ghost rider/20")
+
+	// separators are preserved, so "ghostchris" doesn't match
+	results, err = s.Lookup("ghost chr", false, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertResults(t, results, "ghost chris/40")
+
+	a := std.NewStandardAnalyzer()
+	s = NewAnalyzingSuggesterWith(a, a, EXACT_FIRST, 256, -1, true)
+	err = s.Build(suggest.NewInputArrayIterator(
+		suggest.NewInput("ghost chris", 40),
+		suggest.NewInput("ghostchris", 50),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	results, err = s.Lookup("ghost chr", false, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertResults(t, results, "ghostchris/50", "ghost chris/40")
+}
+
+func TestPayloads(t *testing.T) {
+	s := NewAnalyzingSuggester(std.NewStandardAnalyzer())
+	err := s.Build(suggest.NewInputArrayIterator(
+		suggest.NewInputWithPayload("foo", 50, []byte("hello")),
+		suggest.NewInputWithPayload("foobar", 10, []byte("goodbye")),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	results, err := s.Lookup("foo", false, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertResults(t, results, "foo/50", "foobar/10")
+	if string(results[0].Payload) != "hello" || string(results[1].Payload) != "goodbye" {
+		t.Errorf("unexpected payloads: %q, %q", results[0].Payload, results[1].Payload)
+	}
+}
+
+func TestInvalidLookup(t *testing.T) {
+	s := NewAnalyzingSuggester(std.NewStandardAnalyzer())
+	if err := s.Build(suggest.NewInputArrayIterator(suggest.NewInput("foo", 1))); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Lookup("foo", true, 2); err == nil {
+		t.Error("expected an error for onlyMorePopular=true")
+	}
+	if _, err := s.Lookup("fo\u001fo", false, 2); err == nil {
+		t.Error("expected an error for a reserved character")
+	}
+}
@@ -0,0 +1,94 @@
+package analyzing
+
+import (
+	"github.com/balzaczyy/golucene/core/util/automaton"
+	"github.com/balzaczyy/golucene/core/util/fst"
+)
+
+// search/suggest/analyzing/FSTUtil.java
+
+/* Holds a pair (automaton, fst) of states and accumulated output in the intersected machine. */
+type Path struct {
+	// Node in the automaton where path ends:
+	State int
+	// Node in the FST where path ends:
+	FstNode *fst.Arc
+	// Output of the path so far:
+	Output interface{}
+	// Input of the path so far:
+	Input []int
+}
+
+/*
+Enumerates all minimal prefix paths in the automaton that also
+intersect the FST, accumulating the FST end node and output for each
+path.
+*/
+func IntersectPrefixPaths(a *automaton.Automaton, f *fst.FST) ([]*Path, error) {
+	var endNodes []*Path
+	if a.NumStates() == 0 {
+		return endNodes, nil
+	}
+
+	queue := []*Path{{0, f.FirstArc(&fst.Arc{}), f.Outputs().NoOutput(), nil}}
+	scratchArc := &fst.Arc{}
+	fstReader := f.BytesReader()
+
+	t := automaton.NewTransition()
+	for len(queue) > 0 {
+		path := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+		if a.IsAccept(path.State) {
+			endNodes = append(endNodes, path)
+			// we can stop here if we accept this path, we accept all
+			// further paths too
+			continue
+		}
+
+		count := a.InitTransition(path.State, t)
+		for i := 0; i < count; i++ {
+			a.NextTransition(t)
+			if t.Min == t.Max {
+				nextArc, err := f.FindTargetArc(t.Min, path.FstNode, scratchArc, fstReader)
+				if err != nil {
+					return nil, err
+				}
+				if nextArc != nil {
+					queue = append(queue, &Path{
+						t.Dest,
+						(&fst.Arc{}).CopyFrom(nextArc),
+						f.Outputs().Add(path.Output, nextArc.Output),
+						appendInput(path.Input, t.Min),
+					})
+				}
+			} else {
+				nextArc, err := fst.ReadCeilArc(t.Min, f, path.FstNode, scratchArc, fstReader)
+				if err != nil {
+					return nil, err
+				}
+				for nextArc != nil && nextArc.Label <= t.Max {
+					assert(nextArc.Label >= t.Min)
+					queue = append(queue, &Path{
+						t.Dest,
+						(&fst.Arc{}).CopyFrom(nextArc),
+						f.Outputs().Add(path.Output, nextArc.Output),
+						appendInput(path.Input, nextArc.Label),
+					})
+					if nextArc.IsLast() {
+						break
+					}
+					if nextArc, err = f.ReadNextArc(nextArc, fstReader); err != nil {
+						return nil, err
+					}
+				}
+			}
+		}
+	}
+	return endNodes, nil
+}
+
+func appendInput(input []int, label int) []int {
+	ans := make([]int, len(input), len(input)+1)
+	copy(ans, input)
+	return append(ans, label)
+}
@@ -0,0 +1,144 @@
+package analyzing
+
+import (
+	"github.com/balzaczyy/golucene/core/analysis"
+	"github.com/balzaczyy/golucene/core/util/automaton"
+	"github.com/balzaczyy/golucene/core/util/fst"
+)
+
+// search/suggest/analyzing/FuzzySuggester.java
+
+const (
+	// The default minimum length of the key passed to Lookup before
+	// any edits are allowed.
+	DEFAULT_MIN_FUZZY_LENGTH = 3
+	// The default prefix length where edits are not allowed.
+	DEFAULT_NON_FUZZY_PREFIX = 1
+	// The default maximum number of edits for fuzzy suggestions.
+	DEFAULT_MAX_EDITS = 1
+	// The default transposition value passed to LevenshteinAutomata
+	DEFAULT_TRANSPOSITIONS = true
+)
+
+/*
+Implements a fuzzy AnalyzingSuggester. The similarity measurement is
+based on the Damerau-Levenshtein (optimal string alignment)
+algorithm, though you can explicitly choose classic Levenshtein by
+passing false for the transpositions parameter.
+
+At most, this query will match terms up to MAXIMUM_SUPPORTED_DISTANCE
+edits. Higher distances are not supported. Note that the fuzzy
+distance is measured in "byte space" on the bytes returned by the
+TokenStream's TermToBytesRefAttribute, usually UTF8. By default the
+analyzed bytes must be at least 3 (DEFAULT_MIN_FUZZY_LENGTH) bytes
+before any edits are considered. Furthermore, the first 1
+(DEFAULT_NON_FUZZY_PREFIX) byte is not allowed to be edited. We
+allow up to 1 (DEFAULT_MAX_EDITS) edit. Note that unlike the
+original Levenshtein algorithm, a transposition counts as one edit by
+default.
+
+NOTE: This suggester does not boost suggestions that required no
+edits over suggestions that did require edits. This is a known
+limitation.
+
+Note: complex query analyzers can have a significant impact on the
+lookup performance. It's recommended to not use analyzers that drop
+or inject terms like synonyms to keep the complexity of the prefix
+intersection low for good lookup performance. At index time, complex
+analyzers can safely be used.
+*/
+type FuzzySuggester struct {
+	*AnalyzingSuggester
+	maxEdits       int
+	transpositions bool
+	nonFuzzyPrefix int
+	minFuzzyLength int
+}
+
+/*
+Creates a FuzzySuggester using the default settings of
+AnalyzingSuggester and DEFAULT_MAX_EDITS, DEFAULT_TRANSPOSITIONS,
+DEFAULT_NON_FUZZY_PREFIX, DEFAULT_MIN_FUZZY_LENGTH.
+*/
+func NewFuzzySuggester(analyzer analysis.Analyzer) *FuzzySuggester {
+	return NewFuzzySuggesterWith(analyzer, analyzer, EXACT_FIRST|PRESERVE_SEP, 256, -1, true,
+		DEFAULT_MAX_EDITS, DEFAULT_TRANSPOSITIONS, DEFAULT_NON_FUZZY_PREFIX, DEFAULT_MIN_FUZZY_LENGTH)
+}
+
+/*
+Creates a FuzzySuggester instance.
+
+The first parameters are passed on to NewAnalyzingSuggesterWith().
+maxEdits must be >= 0 and <= MAXIMUM_SUPPORTED_DISTANCE.
+transpositions tells whether a transposition counts as a primitive
+edit. nonFuzzyPrefix is the length of the common non-fuzzy prefix
+match which must be matched exactly, and minFuzzyLength is the
+minimum length of the lookup key before any edits are allowed.
+*/
+func NewFuzzySuggesterWith(indexAnalyzer, queryAnalyzer analysis.Analyzer,
+	options, maxSurfaceFormsPerAnalyzedForm, maxGraphExpansions int,
+	preservePositionIncrements bool, maxEdits int, transpositions bool,
+	nonFuzzyPrefix, minFuzzyLength int) *FuzzySuggester {
+
+	assert2(maxEdits >= 0 && maxEdits <= automaton.MAXIMUM_SUPPORTED_DISTANCE,
+		"maxEdits must be between 0 and %v", automaton.MAXIMUM_SUPPORTED_DISTANCE)
+	assert2(nonFuzzyPrefix >= 0, "nonFuzzyPrefix must not be >= 0 (got %v)", nonFuzzyPrefix)
+	assert2(minFuzzyLength >= 0, "minFuzzyLength must not be >= 0 (got %v)", minFuzzyLength)
+
+	ans := &FuzzySuggester{
+		AnalyzingSuggester: NewAnalyzingSuggesterWith(indexAnalyzer, queryAnalyzer,
+			options, maxSurfaceFormsPerAnalyzedForm, maxGraphExpansions, preservePositionIncrements),
+		maxEdits:       maxEdits,
+		transpositions: transpositions,
+		nonFuzzyPrefix: nonFuzzyPrefix,
+		minFuzzyLength: minFuzzyLength,
+	}
+	ans.spi = ans
+	return ans
+}
+
+func (s *FuzzySuggester) fullPrefixPaths(prefixPaths []*Path,
+	lookupAutomaton *automaton.Automaton, f *fst.FST) ([]*Path, error) {
+
+	// TODO: right now there's no penalty for fuzzy/edits, ie a
+	// completion whose prefix matched exactly what the user typed
+	// gets no boost over completions that required an edit, which get
+	// no boost over completions requiring two edits.
+	levA := s.convertAutomaton(s.toLevenshteinAutomata(lookupAutomaton))
+	return IntersectPrefixPaths(levA, f)
+}
+
+func (s *FuzzySuggester) toLevenshteinAutomata(a *automaton.Automaton) *automaton.Automaton {
+	paths := automaton.FiniteStrings(a, -1)
+	subs := make([]*automaton.Automaton, len(paths))
+	for i, path := range paths {
+		if len(path) <= s.nonFuzzyPrefix || len(path) < s.minFuzzyLength {
+			subs[i] = automaton.MakeStringFromInts(path)
+		} else {
+			// TODO: maybe add alphaMin to LevenshteinAutomata, and pass
+			// 1 instead of 0? We probably don't want to allow the
+			// trailing dedup bytes to be edited... but then 0 byte is
+			// "in general" allowed on input (but not in UTF8).
+			lev := automaton.NewLevenshteinAutomata(path[s.nonFuzzyPrefix:], 255, s.transpositions)
+			prefix := make([]rune, s.nonFuzzyPrefix)
+			for j := range prefix {
+				prefix[j] = rune(path[j])
+			}
+			subs[i] = lev.ToAutomatonWithPrefix(s.maxEdits, string(prefix))
+		}
+	}
+
+	switch len(subs) {
+	case 0:
+		// automaton is empty, there is no accepted paths through it
+		return automaton.MakeEmpty() // matches nothing
+	case 1:
+		// no synonyms or anything: just a single path through the
+		// tokenstream
+		return subs[0]
+	default:
+		// multiple paths: this is really scary! is it slow? maybe we
+		// should not do this and return an error?
+		return automaton.Determinize(automaton.UnionN(subs))
+	}
+}
@@ -0,0 +1,83 @@
+package analyzing
+
+import (
+	std "github.com/balzaczyy/golucene/analysis/standard"
+	"github.com/balzaczyy/golucene/suggest"
+	"testing"
+)
+
+func TestFuzzyKeyword(t *testing.T) {
+	s := NewFuzzySuggester(std.NewStandardAnalyzer())
+	err := s.Build(suggest.NewInputArrayIterator(
+		suggest.NewInput("foo", 50),
+		suggest.NewInput("bar", 10),
+		suggest.NewInput("barbar", 12),
+		suggest.NewInput("barbara", 6),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, v := range []struct {
+		key      string
+		num      int
+		expected []string
+	}{
+		{"bariar", 2, []string{"barbar/12", "barbara/6"}}, // substitution
+		{"barbr", 2, []string{"barbar/12", "barbara/6"}},  // deletion
+		{"barbara", 2, []string{"barbara/6", "barbar/12"}},
+		{"barbar", 2, []string{"barbar/12", "barbara/6"}},
+		{"barbaa", 2, []string{"barbar/12", "barbara/6"}},
+		{"barbra", 2, []string{"barbar/12", "barbara/6"}}, // transposition
+		{"f", 2, []string{"foo/50"}},
+		{"bar", 1, []string{"bar/10"}},
+		{"b", 2, []string{"barbar/12", "bar/10"}},
+		{"ba", 3, []string{"barbar/12", "bar/10", "barbara/6"}},
+	} {
+		results, err := s.Lookup(v.key, false, v.num)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertResults(t, results, v.expected...)
+	}
+}
+
+func TestNonFuzzyPrefix(t *testing.T) {
+	s := NewFuzzySuggester(std.NewStandardAnalyzer())
+	err := s.Build(suggest.NewInputArrayIterator(
+		suggest.NewInput("barbar", 12),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the first byte can't be edited
+	results, err := s.Lookup("carbar", false, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertResults(t, results)
+
+	// too short to allow edits
+	results, err = s.Lookup("bo", false, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertResults(t, results)
+}
+
+func TestNoEdits(t *testing.T) {
+	a := std.NewStandardAnalyzer()
+	s := NewFuzzySuggesterWith(a, a, EXACT_FIRST|PRESERVE_SEP, 256, -1, true,
+		0, DEFAULT_TRANSPOSITIONS, DEFAULT_NON_FUZZY_PREFIX, DEFAULT_MIN_FUZZY_LENGTH)
+	err := s.Build(suggest.NewInputArrayIterator(
+		suggest.NewInput("barbar", 12),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	results, err := s.Lookup("bariar", false, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertResults(t, results)
+}
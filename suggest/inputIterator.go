@@ -0,0 +1,113 @@
+package suggest
+
+// search/suggest/InputIterator.java
+
+/*
+Interface for enumerating term, weight, payload triples for suggester
+consumption.
+*/
+type InputIterator interface {
+	/*
+		Increments the iteration to the next term, returning nil at the
+		end of the iteration.
+	*/
+	Next() ([]byte, error)
+	// A term's weight, higher numbers mean better suggestions.
+	Weight() int64
+	/*
+		An arbitrary byte slice to record per suggestion. See
+		LookupResult.Payload to retrieve the payload for each
+		suggestion.
+	*/
+	Payload() []byte
+	// Returns true if the iterator has payloads
+	HasPayloads() bool
+	/*
+		A term's contexts context can be used to filter suggestions.
+		May return nil, if suggest entries do not have any context.
+	*/
+	Contexts() []string
+	// Returns true if the iterator has contexts
+	HasContexts() bool
+}
+
+// search/suggest/Input.java
+
+/* Corresponds to one input term for the suggester. */
+type Input struct {
+	Term        string
+	Weight      int64
+	Payload     []byte
+	hasPayload  bool
+	Contexts    []string
+	hasContexts bool
+}
+
+func NewInput(term string, weight int64) *Input {
+	return &Input{Term: term, Weight: weight}
+}
+
+func NewInputWithPayload(term string, weight int64, payload []byte) *Input {
+	return &Input{Term: term, Weight: weight, Payload: payload, hasPayload: true}
+}
+
+func NewInputWithContexts(term string, weight int64, contexts ...string) *Input {
+	return &Input{Term: term, Weight: weight, Contexts: contexts, hasContexts: true}
+}
+
+func NewInputWithPayloadAndContexts(term string, weight int64, payload []byte, contexts ...string) *Input {
+	return &Input{term, weight, payload, true, contexts, true}
+}
+
+// search/suggest/InputArrayIterator.java
+
+/* An InputIterator over a slice of Input. */
+type InputArrayIterator struct {
+	inputs      []*Input
+	current     *Input
+	hasPayloads bool
+	hasContexts bool
+}
+
+func NewInputArrayIterator(inputs ...*Input) *InputArrayIterator {
+	ans := &InputArrayIterator{inputs: inputs}
+	if len(inputs) > 0 {
+		ans.hasPayloads = inputs[0].hasPayload
+		ans.hasContexts = inputs[0].hasContexts
+	}
+	return ans
+}
+
+func (it *InputArrayIterator) Next() ([]byte, error) {
+	if len(it.inputs) == 0 {
+		return nil, nil
+	}
+	it.current, it.inputs = it.inputs[0], it.inputs[1:]
+	return []byte(it.current.Term), nil
+}
+
+func (it *InputArrayIterator) Weight() int64 {
+	return it.current.Weight
+}
+
+func (it *InputArrayIterator) Payload() []byte {
+	if it.hasPayloads {
+		return it.current.Payload
+	}
+	return nil
+}
+
+func (it *InputArrayIterator) HasPayloads() bool {
+	return it.hasPayloads
+}
+
+func (it *InputArrayIterator) Contexts() []string {
+	if it.hasContexts {
+		return it.current.Contexts
+	}
+	return nil
+}
+
+func (it *InputArrayIterator) HasContexts() bool {
+	return it.hasContexts
+}
@@ -0,0 +1,46 @@
+package suggest
+
+import (
+	"fmt"
+)
+
+// search/suggest/Lookup.java
+
+/* Result of a lookup. */
+type LookupResult struct {
+	// the key's text
+	Key string
+	// the key's weight
+	Value int64
+	// the key's payload (nil if not present)
+	Payload []byte
+	// the key's contexts (nil if not present)
+	Contexts []string
+	// the highlighted key, if the suggester was asked to highlight
+	HighlightKey string
+}
+
+func (r *LookupResult) String() string {
+	return fmt.Sprintf("%v/%v", r.Key, r.Value)
+}
+
+/* Simple Lookup interface for string suggestions. */
+type Lookup interface {
+	/*
+		Builds up a new internal Lookup representation based on the
+		given InputIterator. The implementation might re-sort the data
+		internally.
+	*/
+	Build(iterator InputIterator) error
+	/*
+		Look up a key and return possible completion for this key.
+		If onlyMorePopular is true, return only more popular results;
+		num is the maximum number of results to return.
+	*/
+	Lookup(key string, onlyMorePopular bool, num int) ([]*LookupResult, error)
+	/*
+		Get the number of entries the lookup was built with. Returns
+		total number of suggester entries.
+	*/
+	Count() int64
+}
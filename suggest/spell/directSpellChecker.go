@@ -0,0 +1,523 @@
+package spell
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/balzaczyy/golucene/core/index"
+	"github.com/balzaczyy/golucene/core/index/model"
+	"github.com/balzaczyy/golucene/core/util/automaton"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// search/spell/DirectSpellChecker.java
+
+const (
+	// The default accuracy for suggestions, shared with Lucene's
+	// index-based SpellChecker.
+	DEFAULT_ACCURACY = 0.5
+)
+
+/*
+The StringDistance implementation used by DirectSpellChecker by
+default. Suggestions scored by it reuse the edit distance computed
+while enumerating the terms dictionary, so no extra work is done per
+candidate.
+*/
+var INTERNAL_LEVENSHTEIN StringDistance = NewLuceneLevenshteinDistance()
+
+/*
+Simple automaton-based spellchecker.
+
+Candidates are presented directly from the term dictionary, based on
+Levenshtein distance. This is an alternative to Lucene's
+index-based SpellChecker, which requires building an auxiliary index.
+
+A practical benefit of this spellchecker is that it requires no
+additional datastructures (neither in RAM nor on disk) to do its
+work.
+*/
+type DirectSpellChecker struct {
+	// maximum edit distance for candidate terms
+	maxEdits int
+	// minimum prefix for candidate terms
+	minPrefix int
+	// maximum number of top-N inspections per suggestion
+	maxInspections int
+	// minimum accuracy for a term to match
+	accuracy float32
+	// value in [0..1] (or absolute number >= 1) representing the
+	// minimum number of documents (of the total) where a term should
+	// appear.
+	thresholdFrequency float32
+	// minimum length of a query word to return suggestions
+	minQueryLength int
+	// value in [0..1] (or absolute number >= 1) representing the
+	// maximum number of documents (of the total) a query term can
+	// appear in to be corrected.
+	maxQueryFrequency float32
+	// true if the spellchecker should lowercase terms
+	lowerCaseTerms bool
+	// the string distance to use
+	distance StringDistance
+}
+
+// Creates a DirectSpellChecker with default configuration values
+func NewDirectSpellChecker() *DirectSpellChecker {
+	return &DirectSpellChecker{
+		maxEdits:          automaton.MAXIMUM_SUPPORTED_DISTANCE,
+		minPrefix:         1,
+		maxInspections:    5,
+		accuracy:          DEFAULT_ACCURACY,
+		minQueryLength:    4,
+		maxQueryFrequency: 0.01,
+		lowerCaseTerms:    true,
+		distance:          INTERNAL_LEVENSHTEIN,
+	}
+}
+
+// Get the maximum number of Levenshtein edit-distances to draw
+// candidate terms from.
+func (sc *DirectSpellChecker) MaxEdits() int {
+	return sc.maxEdits
+}
+
+/*
+Sets the maximum number of Levenshtein edit-distances to draw
+candidate terms from. This value can be 1 or 2. The default is 2.
+
+Note: a large number of spelling errors occur with an edit distance
+of 1, by setting this value to 1 you can increase both performance
+and precision at the cost of recall.
+*/
+func (sc *DirectSpellChecker) SetMaxEdits(maxEdits int) *DirectSpellChecker {
+	assert2(maxEdits >= 1 && maxEdits <= automaton.MAXIMUM_SUPPORTED_DISTANCE,
+		"Invalid maxEdits: %v", maxEdits)
+	sc.maxEdits = maxEdits
+	return sc
+}
+
+// Get the minimal number of characters that must match exactly
+func (sc *DirectSpellChecker) MinPrefix() int {
+	return sc.minPrefix
+}
+
+/*
+Sets the minimal number of initial characters (default: 1) that must
+match exactly.
+
+This can improve both performance and accuracy of results, as
+misspellings are commonly not the first character.
+*/
+func (sc *DirectSpellChecker) SetMinPrefix(minPrefix int) *DirectSpellChecker {
+	sc.minPrefix = minPrefix
+	return sc
+}
+
+// Get the maximum number of top-N inspections per suggestion
+func (sc *DirectSpellChecker) MaxInspections() int {
+	return sc.maxInspections
+}
+
+/*
+Set the maximum number of top-N inspections (default: 5) per
+suggestion.
+
+Increasing this number can improve the accuracy of results, at the
+cost of performance.
+*/
+func (sc *DirectSpellChecker) SetMaxInspections(maxInspections int) *DirectSpellChecker {
+	sc.maxInspections = maxInspections
+	return sc
+}
+
+// Get the minimal accuracy from the StringDistance for a match
+func (sc *DirectSpellChecker) Accuracy() float32 {
+	return sc.accuracy
+}
+
+/*
+Set the minimal accuracy required (default: 0.5) from the
+StringDistance for a suggestion match.
+*/
+func (sc *DirectSpellChecker) SetAccuracy(accuracy float32) *DirectSpellChecker {
+	sc.accuracy = accuracy
+	return sc
+}
+
+// Get the minimal threshold of documents a term must appear for a
+// match
+func (sc *DirectSpellChecker) ThresholdFrequency() float32 {
+	return sc.thresholdFrequency
+}
+
+/*
+Set the minimal threshold of documents a term must appear for a
+match.
+
+This can improve quality by only suggesting high-frequency terms.
+Note that very high values might decrease performance slightly, by
+forcing the spellchecker to draw more candidates from the term
+dictionary, but a practical value such as 1 can be very useful
+towards improving quality.
+
+This can be specified as a relative percentage of documents such as
+0.5, or it can be specified as an absolute whole document frequency,
+such as 4.0. Absolute document frequencies may not be fractional.
+*/
+func (sc *DirectSpellChecker) SetThresholdFrequency(thresholdFrequency float32) *DirectSpellChecker {
+	assert2(thresholdFrequency < 1 || thresholdFrequency == float32(math.Floor(float64(thresholdFrequency))),
+		"Fractional absolute document frequencies are not allowed")
+	sc.thresholdFrequency = thresholdFrequency
+	return sc
+}
+
+// Get the minimum length of a query term needed to return suggestions
+func (sc *DirectSpellChecker) MinQueryLength() int {
+	return sc.minQueryLength
+}
+
+/*
+Set the minimum length of a query term (default: 4) needed to return
+suggestions.
+
+Very short query terms will often cause only bad suggestions with any
+distance metric.
+*/
+func (sc *DirectSpellChecker) SetMinQueryLength(minQueryLength int) *DirectSpellChecker {
+	sc.minQueryLength = minQueryLength
+	return sc
+}
+
+// Get the maximum threshold of documents a query term can appear in
+// order to provide suggestions.
+func (sc *DirectSpellChecker) MaxQueryFrequency() float32 {
+	return sc.maxQueryFrequency
+}
+
+/*
+Set the maximum threshold (default: 0.01) of documents a query term
+can appear in order to provide suggestions.
+
+Very high-frequency terms are typically spelled correctly.
+Additionally, this can increase performance as it will do no work for
+the common case of correctly-spelled input terms.
+
+This can be specified as a relative percentage of documents such as
+0.5, or it can be specified as an absolute whole document frequency,
+such as 4.0. Absolute document frequencies may not be fractional.
+*/
+func (sc *DirectSpellChecker) SetMaxQueryFrequency(maxQueryFrequency float32) *DirectSpellChecker {
+	assert2(maxQueryFrequency < 1 || maxQueryFrequency == float32(math.Floor(float64(maxQueryFrequency))),
+		"Fractional absolute document frequencies are not allowed")
+	sc.maxQueryFrequency = maxQueryFrequency
+	return sc
+}
+
+// true if the spellchecker should lowercase terms
+func (sc *DirectSpellChecker) LowerCaseTerms() bool {
+	return sc.lowerCaseTerms
+}
+
+/*
+True if the spellchecker should lowercase terms (default: true)
+
+This is a convenience method, if your index field has more
+complicated analysis (such as StandardTokenizer removing
+punctuation), its probably better to turn this off, and instead run
+your query terms through your Analyzer first.
+
+If this option is not on, case differences count as an edit!
+*/
+func (sc *DirectSpellChecker) SetLowerCaseTerms(lowerCaseTerms bool) *DirectSpellChecker {
+	sc.lowerCaseTerms = lowerCaseTerms
+	return sc
+}
+
+// Get the string distance metric in use.
+func (sc *DirectSpellChecker) Distance() StringDistance {
+	return sc.distance
+}
+
+/*
+Set the string distance metric. The default is INTERNAL_LEVENSHTEIN.
+
+Note: because this spellchecker draws its candidates from the term
+dictionary using Damerau-Levenshtein, it works best with an
+edit-distance-like string metric. If you use a different metric than
+the default, you might want to consider increasing
+SetMaxInspections() to draw more candidates for your metric to rank.
+*/
+func (sc *DirectSpellChecker) SetDistance(distance StringDistance) *DirectSpellChecker {
+	sc.distance = distance
+	return sc
+}
+
+/*
+Suggest similar words, using the configured accuracy.
+
+The term's field is used to draw candidates from the index, numSug
+is the maximum number of suggestions to return, and mode controls
+whether and which suggestions are offered depending on the popularity
+of term itself.
+*/
+func (sc *DirectSpellChecker) SuggestSimilar(term *index.Term, numSug int,
+	ir index.IndexReader, mode SuggestMode) ([]*SuggestWord, error) {
+
+	return sc.SuggestSimilarWith(term, numSug, ir, mode, sc.accuracy)
+}
+
+/*
+Suggest similar words.
+
+Unlike Lucene's index-based SpellChecker, the mode here is just a
+"hint". This is because the SpellChecker's approach is a heuristic
+that would not scale well for modes other than
+SUGGEST_WHEN_NOT_IN_INDEX. DirectSpellChecker supports all modes
+equally, but the result for SUGGEST_MORE_POPULAR might differ from
+the index-based one.
+
+accuracy is the minimum return value of the StringDistance for a
+candidate to be suggested.
+*/
+func (sc *DirectSpellChecker) SuggestSimilarWith(term *index.Term, numSug int,
+	ir index.IndexReader, mode SuggestMode, accuracy float32) ([]*SuggestWord, error) {
+
+	text := string(term.Bytes)
+	if sc.minQueryLength > 0 && utf8.RuneCountInString(text) < sc.minQueryLength {
+		return nil, nil
+	}
+
+	if sc.lowerCaseTerms {
+		term = index.NewTerm(term.Field, strings.ToLower(text))
+	}
+
+	docfreq, err := ir.DocFreq(term)
+	if err != nil {
+		return nil, err
+	}
+
+	if mode == SUGGEST_WHEN_NOT_IN_INDEX && docfreq > 0 {
+		return nil, nil
+	}
+
+	maxDoc := ir.MaxDoc()
+
+	if sc.maxQueryFrequency >= 1 && float32(docfreq) > sc.maxQueryFrequency {
+		return nil, nil
+	} else if docfreq > int(math.Ceil(float64(sc.maxQueryFrequency*float32(maxDoc)))) {
+		return nil, nil
+	}
+
+	if mode != SUGGEST_MORE_POPULAR {
+		docfreq = 0
+	}
+
+	if sc.thresholdFrequency >= 1 {
+		docfreq = maxInt(docfreq, int(sc.thresholdFrequency))
+	} else if sc.thresholdFrequency > 0 {
+		docfreq = maxInt(docfreq, int(sc.thresholdFrequency*float32(maxDoc))-1)
+	}
+
+	inspections := numSug * sc.maxInspections
+
+	// try ed=1 first, in case we get lucky
+	terms, err := sc.suggestSimilar(term, inspections, ir, docfreq, 1, accuracy)
+	if err != nil {
+		return nil, err
+	}
+	if sc.maxEdits > 1 && len(terms) < inspections {
+		moreTerms, err := sc.suggestSimilar(term, inspections, ir, docfreq, sc.maxEdits, accuracy)
+		if err != nil {
+			return nil, err
+		}
+		seen := make(map[string]bool)
+		for _, st := range terms {
+			seen[st.term] = true
+		}
+		for _, st := range moreTerms {
+			if !seen[st.term] {
+				terms = append(terms, st)
+			}
+		}
+	}
+
+	// create the suggestword response, sort it, and trim it to size.
+	suggestions := make([]*SuggestWord, len(terms))
+	for i, st := range terms {
+		suggestions[i] = &SuggestWord{
+			String: st.term,
+			Score:  st.score,
+			Freq:   st.docfreq,
+		}
+	}
+	sort.Sort(byScoreThenFreq(suggestions))
+	if numSug < len(suggestions) {
+		suggestions = suggestions[:numSug]
+	}
+	return suggestions, nil
+}
+
+/*
+Provide spelling corrections based on several parameters.
+
+Candidates are drawn from every segment of the reader: the terms
+dictionary is positioned on the exact (non-fuzzy) prefix of the term,
+and every term sharing that prefix is run through Levenshtein
+automata to find the smallest edit distance accepting it. Returns at
+most numSug candidates, best first.
+*/
+func (sc *DirectSpellChecker) suggestSimilar(term *index.Term, numSug int,
+	ir index.IndexReader, docfreq, editDistance int, accuracy float32) ([]*scoreTerm, error) {
+
+	text := string(term.Bytes)
+	word := []rune(text)
+	termLength := len(word)
+
+	prefixLength := maxInt(sc.minPrefix, editDistance-1)
+	if prefixLength > termLength {
+		prefixLength = termLength
+	}
+	prefix := string(word[:prefixLength])
+	prefixBytes := []byte(prefix)
+
+	// matchers[ed] accepts everything within ed edits of the term
+	suffix := make([]int, termLength-prefixLength)
+	for i, c := range word[prefixLength:] {
+		suffix[i] = int(c)
+	}
+	lev := automaton.NewLevenshteinAutomata(suffix, unicode.MaxRune, true)
+	matchers := make([]*automaton.CharacterRunAutomaton, editDistance+1)
+	for i := range matchers {
+		matchers[i] = automaton.NewCharacterRunAutomaton(lev.ToAutomatonWithPrefix(i, prefix))
+	}
+
+	// docFreq and edits of all accepted candidates, summed across
+	// segments
+	edits := make(map[string]int)
+	freqs := make(map[string]int)
+	for _, ctx := range ir.Leaves() {
+		fields := ctx.Reader().(index.AtomicReader).Fields()
+		if fields == nil {
+			continue
+		}
+		terms := fields.Terms(term.Field)
+		if terms == nil {
+			continue
+		}
+		termsEnum := terms.Iterator(nil)
+		if termsEnum.SeekCeil(prefixBytes) == model.SEEK_STATUS_END {
+			continue
+		}
+		for candidate := termsEnum.Term(); candidate != nil &&
+			bytes.HasPrefix(candidate, prefixBytes); {
+
+			s := string(candidate)
+			// ignore exact match of the same term
+			if s != text {
+				ed, ok := edits[s]
+				if !ok {
+					ed = -1
+					for i, m := range matchers {
+						if m.Run(s) {
+							ed = i
+							break
+						}
+					}
+					edits[s] = ed
+				}
+				if ed >= 0 {
+					df, err := termsEnum.DocFreq()
+					if err != nil {
+						return nil, err
+					}
+					freqs[s] += df
+				}
+			}
+
+			var err error
+			if candidate, err = termsEnum.Next(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var stQueue []*scoreTerm
+	for s, df := range freqs {
+		// check docFreq if required
+		if df <= docfreq {
+			continue
+		}
+
+		ed := edits[s]
+		boost := 1 - float32(ed)/float32(minInt(utf8.RuneCountInString(s), termLength))
+		if boost <= 0 {
+			continue
+		}
+
+		var score float32
+		if sc.distance == INTERNAL_LEVENSHTEIN {
+			score = boost
+		} else {
+			score = sc.distance.Distance(text, s)
+		}
+		if score < accuracy {
+			continue
+		}
+
+		stQueue = append(stQueue, &scoreTerm{s, boost, df, score})
+	}
+	sort.Sort(byBoost(stQueue))
+	if len(stQueue) > numSug {
+		stQueue = stQueue[:numSug]
+	}
+	return stQueue, nil
+}
+
+// Holds a spelling correction for internal usage inside
+// DirectSpellChecker.
+type scoreTerm struct {
+	// The actual spellcheck correction.
+	term string
+	// The boost representing the similarity from the automaton
+	// enumeration.
+	boost float32
+	// The df of the spellcheck correction.
+	docfreq int
+	// The score for the correction.
+	score float32
+}
+
+type byBoost []*scoreTerm
+
+func (s byBoost) Len() int      { return len(s) }
+func (s byBoost) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s byBoost) Less(i, j int) bool {
+	if s[i].boost != s[j].boost {
+		return s[i].boost > s[j].boost
+	}
+	return s[i].term < s[j].term
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func assert2(ok bool, msg string, args ...interface{}) {
+	if !ok {
+		panic(fmt.Sprintf(msg, args...))
+	}
+}
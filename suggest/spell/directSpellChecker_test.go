@@ -0,0 +1,235 @@
+package spell
+
+import (
+	"fmt"
+	std "github.com/balzaczyy/golucene/analysis/standard"
+	_ "github.com/balzaczyy/golucene/core/codec/lucene410"
+	docu "github.com/balzaczyy/golucene/core/document"
+	"github.com/balzaczyy/golucene/core/index"
+	"github.com/balzaczyy/golucene/core/search"
+	"github.com/balzaczyy/golucene/core/store"
+	"github.com/balzaczyy/golucene/core/util"
+	"strings"
+	"testing"
+)
+
+var ones = []string{"zero", "one", "two", "three", "four", "five",
+	"six", "seven", "eight", "nine", "ten", "eleven", "twelve",
+	"thirteen", "fourteen", "fifteen", "sixteen", "seventeen",
+	"eighteen", "nineteen"}
+var tens = []string{"", "", "twenty", "thirty", "forty", "fifty",
+	"sixty", "seventy", "eighty", "ninety"}
+
+// Spells out 0 <= i < 10000 in English words.
+func intToEnglish(i int) string {
+	var words []string
+	if i >= 1000 {
+		words = append(words, ones[i/1000], "thousand")
+		if i %= 1000; i == 0 {
+			return strings.Join(words, " ")
+		}
+	}
+	if i >= 100 {
+		words = append(words, ones[i/100], "hundred")
+		if i %= 100; i == 0 {
+			return strings.Join(words, " ")
+		}
+	}
+	if i >= 20 {
+		words = append(words, tens[i/10])
+		if i %= 10; i == 0 {
+			return strings.Join(words, " ")
+		}
+	}
+	return strings.Join(append(words, ones[i]), " ")
+}
+
+// Opens a reader over one document per value, indexed as field.
+func newReader(t *testing.T, field string, values ...string) index.IndexReader {
+	docs := make([][]string, len(values))
+	for i, v := range values {
+		docs[i] = []string{v}
+	}
+	return newMultiValuedReader(t, field, docs...)
+}
+
+// Opens a reader over the given documents, each value of a document
+// being indexed as a separate instance of field.
+func newMultiValuedReader(t *testing.T, field string, docs ...[]string) index.IndexReader {
+	index.DefaultSimilarity = func() index.Similarity {
+		return search.NewDefaultSimilarity()
+	}
+	dir, err := store.OpenFSDirectory(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	analyzer := std.NewStandardAnalyzer()
+	w, err := index.NewIndexWriter(dir, index.NewIndexWriterConfig(util.VERSION_LATEST, analyzer))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, values := range docs {
+		doc := docu.NewDocument()
+		for _, v := range values {
+			doc.Add(docu.NewTextFieldFromString(field, v, docu.STORE_NO))
+		}
+		if err = w.AddDocument(doc.Fields()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	r, err := index.OpenDirectoryReader(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { r.Close() })
+	return r
+}
+
+func suggestSimilar(t *testing.T, sc *DirectSpellChecker, ir index.IndexReader,
+	text string, numSug int, mode SuggestMode) []*SuggestWord {
+
+	similar, err := sc.SuggestSimilar(index.NewTerm("numbers", text), numSug, ir, mode)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return similar
+}
+
+func TestDirectSpellCheckerSimpleExamples(t *testing.T) {
+	values := make([]string, 0, 20)
+	for i := 0; i < 20; i++ {
+		values = append(values, intToEnglish(i))
+	}
+	ir := newReader(t, "numbers", values...)
+
+	sc := NewDirectSpellChecker().SetMinQueryLength(0)
+
+	similar := suggestSimilar(t, sc, ir, "fvie", 2, SUGGEST_WHEN_NOT_IN_INDEX)
+	if len(similar) == 0 || similar[0].String != "five" {
+		t.Errorf("'fvie' should be corrected to 'five': %v", similar)
+	}
+
+	// don't suggest a word for itself
+	similar = suggestSimilar(t, sc, ir, "five", 2, SUGGEST_WHEN_NOT_IN_INDEX)
+	if len(similar) > 0 && similar[0].String == "five" {
+		t.Errorf("'five' should not be suggested for itself")
+	}
+
+	for _, text := range []string{"fiv", "fives", "fie"} {
+		similar = suggestSimilar(t, sc, ir, text, 2, SUGGEST_WHEN_NOT_IN_INDEX)
+		if len(similar) == 0 || similar[0].String != "five" {
+			t.Errorf("'%v' should be corrected to 'five': %v", text, similar)
+		}
+	}
+
+	// first letter is fixed by the default min prefix
+	similar = suggestSimilar(t, sc, ir, "ive", 2, SUGGEST_WHEN_NOT_IN_INDEX)
+	if len(similar) > 0 && similar[0].String == "five" {
+		t.Errorf("'ive' should not be corrected to 'five'")
+	}
+	similar = suggestSimilar(t, sc.SetMinPrefix(0), ir, "ive", 2, SUGGEST_WHEN_NOT_IN_INDEX)
+	if len(similar) == 0 || similar[0].String != "five" {
+		t.Errorf("'ive' should be corrected to 'five' without a prefix: %v", similar)
+	}
+	sc.SetMinPrefix(1)
+
+	// a term with too many edits is left alone
+	similar = suggestSimilar(t, sc, ir, "fxxxx", 2, SUGGEST_WHEN_NOT_IN_INDEX)
+	if len(similar) != 0 {
+		t.Errorf("'fxxxx' should have no suggestions: %v", similar)
+	}
+
+	// two edits away is only found with maxEdits=2
+	similar = suggestSimilar(t, sc, ir, "sevvem", 2, SUGGEST_WHEN_NOT_IN_INDEX)
+	if len(similar) == 0 || similar[0].String != "seven" {
+		t.Errorf("'sevvem' should be corrected to 'seven': %v", similar)
+	}
+	similar = suggestSimilar(t, sc.SetMaxEdits(1), ir, "sevvem", 2, SUGGEST_WHEN_NOT_IN_INDEX)
+	if len(similar) != 0 {
+		t.Errorf("'sevvem' should have no suggestion within one edit: %v", similar)
+	}
+}
+
+func TestDirectSpellCheckerOptions(t *testing.T) {
+	ir := newReader(t, "numbers", "foobar", "foobar", "foobar", "foobaz", "foobaz", "foobarz")
+
+	sc := NewDirectSpellChecker().SetMaxQueryFrequency(0)
+
+	similar := suggestSimilar(t, sc, ir, "FOBAR", 1, SUGGEST_WHEN_NOT_IN_INDEX)
+	if len(similar) != 1 || similar[0].String != "foobar" || similar[0].Freq != 3 {
+		t.Errorf("'FOBAR' should be corrected to 'foobar': %v", similar)
+	}
+	similar = suggestSimilar(t, sc.SetLowerCaseTerms(false), ir, "FOBAR", 1, SUGGEST_WHEN_NOT_IN_INDEX)
+	if len(similar) != 0 {
+		t.Errorf("case should count as edits: %v", similar)
+	}
+	sc.SetLowerCaseTerms(true)
+
+	// 'foobaz' is in the index, so nothing is suggested by default
+	similar = suggestSimilar(t, sc, ir, "foobaz", 2, SUGGEST_WHEN_NOT_IN_INDEX)
+	if len(similar) != 0 {
+		t.Errorf("'foobaz' exists, but got suggestions: %v", similar)
+	}
+	sc.SetMaxQueryFrequency(2)
+	similar = suggestSimilar(t, sc, ir, "foobaz", 2, SUGGEST_MORE_POPULAR)
+	if len(similar) != 1 || similar[0].String != "foobar" {
+		t.Errorf("'foobaz' should only suggest the more popular 'foobar': %v", similar)
+	}
+	similar = suggestSimilar(t, sc, ir, "foobaz", 3, SUGGEST_ALWAYS)
+	if len(similar) != 2 || similar[0].String != "foobar" || similar[1].String != "foobarz" {
+		t.Errorf("'foobaz' should suggest 'foobar' and 'foobarz': %v", similar)
+	}
+
+	// term is too frequent to be corrected
+	similar = suggestSimilar(t, sc.SetMaxQueryFrequency(1), ir, "foobaz", 2, SUGGEST_ALWAYS)
+	if len(similar) != 0 {
+		t.Errorf("'foobaz' is too frequent to be corrected: %v", similar)
+	}
+
+	// candidates must be frequent enough
+	sc.SetMaxQueryFrequency(0.01).SetThresholdFrequency(2)
+	similar = suggestSimilar(t, sc, ir, "foobax", 3, SUGGEST_WHEN_NOT_IN_INDEX)
+	if len(similar) != 1 || similar[0].String != "foobar" {
+		t.Errorf("only 'foobar' should pass the threshold: %v", similar)
+	}
+}
+
+func TestDirectSpellCheckerManyTerms(t *testing.T) {
+	// enough distinct terms to span several blocks of the terms
+	// dictionary
+	values := make([]string, 0, 2000)
+	for i := 0; i < 2000; i++ {
+		values = append(values, fmt.Sprintf("term%04d", i))
+	}
+	ir := newMultiValuedReader(t, "numbers", values)
+
+	sc := NewDirectSpellChecker()
+	for _, i := range []int{0, 100, 1234, 1999} {
+		text := fmt.Sprintf("tirm%04d", i)
+		similar := suggestSimilar(t, sc, ir, text, 1, SUGGEST_WHEN_NOT_IN_INDEX)
+		if expected := values[i]; len(similar) != 1 || similar[0].String != expected {
+			t.Errorf("'%v' should be corrected to '%v': %v", text, expected, similar)
+		}
+	}
+}
+
+func TestStringDistance(t *testing.T) {
+	lev := NewLevensteinDistance()
+	if d := lev.Distance("five", "five"); d != 1 {
+		t.Errorf("identical strings should have distance 1, but was %v", d)
+	}
+	if d := lev.Distance("five", "fvie"); d != 0.5 {
+		t.Errorf("a swap costs two edits with Levenstein, but was %v", d)
+	}
+
+	lucene := NewLuceneLevenshteinDistance()
+	if d := lucene.Distance("five", "fvie"); d != 0.75 {
+		t.Errorf("a swap costs one edit with transpositions, but was %v", d)
+	}
+	if d := lucene.Distance("fives", "five"); d != 0.75 {
+		t.Errorf("distance should be scaled by the shorter term, but was %v", d)
+	}
+}
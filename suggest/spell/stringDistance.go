@@ -0,0 +1,136 @@
+package spell
+
+// search/spell/StringDistance.java
+
+// Interface for string distances.
+type StringDistance interface {
+	/*
+		Returns a float between 0 and 1 based on how similar the
+		specified strings are to one another. Returning a value of 1
+		means the specified strings are identical and 0 means the string
+		are maximally different.
+	*/
+	Distance(s1, s2 string) float32
+}
+
+// search/spell/LevensteinDistance.java
+
+// Levenstein edit distance class.
+type LevensteinDistance struct{}
+
+func NewLevensteinDistance() *LevensteinDistance {
+	return &LevensteinDistance{}
+}
+
+func (d *LevensteinDistance) Distance(target, other string) float32 {
+	sa, ta := []rune(target), []rune(other)
+	n, m := len(sa), len(ta)
+	if n == 0 || m == 0 {
+		if n == m {
+			return 1
+		}
+		return 0
+	}
+
+	p := make([]int, n+1)  // 'previous' cost array, horizontally
+	dd := make([]int, n+1) // cost array, horizontally
+
+	for i := 0; i <= n; i++ {
+		p[i] = i
+	}
+
+	for j := 1; j <= m; j++ {
+		t_j := ta[j-1]
+		dd[0] = j
+
+		for i := 1; i <= n; i++ {
+			cost := 1
+			if sa[i-1] == t_j {
+				cost = 0
+			}
+			// minimum of cell to the left+1, to the top+1, diagonally
+			// left and up +cost
+			dd[i] = minInt(minInt(dd[i-1]+1, p[i]+1), p[i-1]+cost)
+		}
+
+		// copy current distance counts to 'previous row' distance counts
+		p, dd = dd, p
+	}
+
+	// our last action in the above loop was to switch d and p, so p
+	// now actually has the most recent cost counts
+	return 1 - float32(p[n])/float32(maxInt(m, n))
+}
+
+func (d *LevensteinDistance) String() string {
+	return "levenstein"
+}
+
+// search/spell/LuceneLevenshteinDistance.java
+
+/*
+Damerau-Levenshtein (optimal string alignment) implemented in a
+consistent way as Lucene's FuzzyTermsEnum with the transpositions
+option enabled.
+
+Notes:
+
+  - This metric treats full unicode codepoints as characters
+  - This metric scales raw edit distances into a floating point score
+    based upon the shortest of the two terms
+  - Transpositions of two adjacent codepoints are treated as primitive
+    edits.
+  - Edits are applied in parallel: for example, "ab" and "bca" have
+    distance 3.
+*/
+type LuceneLevenshteinDistance struct{}
+
+func NewLuceneLevenshteinDistance() *LuceneLevenshteinDistance {
+	return &LuceneLevenshteinDistance{}
+}
+
+func (d *LuceneLevenshteinDistance) Distance(target, other string) float32 {
+	targetPoints, otherPoints := []rune(target), []rune(other)
+	n, m := len(targetPoints), len(otherPoints)
+
+	if n == 0 || m == 0 {
+		if n == m {
+			return 0
+		}
+		return float32(maxInt(n, m))
+	}
+
+	dd := make([][]int, n+1)
+	for i := range dd {
+		dd[i] = make([]int, m+1)
+		dd[i][0] = i
+	}
+	for j := 0; j <= m; j++ {
+		dd[0][j] = j
+	}
+
+	for j := 1; j <= m; j++ {
+		t_j := otherPoints[j-1]
+		for i := 1; i <= n; i++ {
+			cost := 1
+			if targetPoints[i-1] == t_j {
+				cost = 0
+			}
+			// minimum of cell to the left+1, to the top+1, diagonally
+			// left and up +cost
+			dd[i][j] = minInt(minInt(dd[i-1][j]+1, dd[i][j-1]+1), dd[i-1][j-1]+cost)
+			// transposition
+			if i > 1 && j > 1 &&
+				targetPoints[i-1] == otherPoints[j-2] &&
+				targetPoints[i-2] == otherPoints[j-1] {
+				dd[i][j] = minInt(dd[i][j], dd[i-2][j-2]+cost)
+			}
+		}
+	}
+
+	return 1 - float32(dd[n][m])/float32(minInt(m, n))
+}
+
+func (d *LuceneLevenshteinDistance) String() string {
+	return "lucene-levenshtein"
+}
@@ -0,0 +1,70 @@
+package spell
+
+// search/spell/SuggestMode.java
+
+// Set of strategies for suggesting related terms
+type SuggestMode int
+
+const (
+	// Generate suggestions only for terms not in the index (default)
+	SUGGEST_WHEN_NOT_IN_INDEX = SuggestMode(iota)
+	// Return only suggested words that are as frequent or more frequent
+	// than the searched word
+	SUGGEST_MORE_POPULAR
+	// Always attempt to offer suggestions (however, other parameters
+	// may limit suggestions. For example, see
+	// DirectSpellChecker.SetMaxQueryFrequency()).
+	SUGGEST_ALWAYS
+)
+
+func (m SuggestMode) String() string {
+	switch m {
+	case SUGGEST_WHEN_NOT_IN_INDEX:
+		return "SUGGEST_WHEN_NOT_IN_INDEX"
+	case SUGGEST_MORE_POPULAR:
+		return "SUGGEST_MORE_POPULAR"
+	case SUGGEST_ALWAYS:
+		return "SUGGEST_ALWAYS"
+	}
+	panic("unknown suggest mode")
+}
+
+// search/spell/SuggestWord.java
+
+/*
+SuggestWord, used in SuggestSimilar method in DirectSpellChecker
+class.
+
+Default sort is first by score, then by frequency.
+*/
+type SuggestWord struct {
+	// the score of the word
+	Score float32
+	// The freq of the word
+	Freq int
+	// the suggested word
+	String string
+}
+
+// search/spell/SuggestWordScoreComparator.java
+
+/*
+Sorts SuggestWord instances by score first, then by frequency, and
+finally by the word itself, so that the best suggestion comes first.
+*/
+type byScoreThenFreq []*SuggestWord
+
+func (s byScoreThenFreq) Len() int      { return len(s) }
+func (s byScoreThenFreq) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s byScoreThenFreq) Less(i, j int) bool {
+	// first criteria: the distance
+	if s[i].Score != s[j].Score {
+		return s[i].Score > s[j].Score
+	}
+	// second criteria (if first criteria is equal): the popularity
+	if s[i].Freq != s[j].Freq {
+		return s[i].Freq > s[j].Freq
+	}
+	// third criteria: term text
+	return s[i].String < s[j].String
+}
@@ -0,0 +1,436 @@
+package spell
+
+import (
+	"github.com/balzaczyy/golucene/core/index"
+	"math"
+	"sort"
+	"unicode/utf8"
+)
+
+// search/spell/WordBreakSpellChecker.java
+
+/*
+Determines the order to list word break suggestions
+*/
+type BreakSuggestionSortMethod int
+
+const (
+	// Sort by Number of word breaks, then by the Sum of all the
+	// component term's frequencies
+	NUM_CHANGES_THEN_SUMMED_FREQUENCY = BreakSuggestionSortMethod(iota)
+	// Sort by Number of word breaks, then by the Maximum of all the
+	// component term's frequencies
+	NUM_CHANGES_THEN_MAX_FREQUENCY
+)
+
+/*
+Term that can be used to prohibit adjacent terms from being combined
+*/
+var SEPARATOR_TERM = index.NewTerm("", "")
+
+func isSeparator(t *index.Term) bool {
+	return t.Field == SEPARATOR_TERM.Field && len(t.Bytes) == 0
+}
+
+// search/spell/CombineSuggestion.java
+
+// A suggestion generated by combining one or more original query
+// terms
+type CombineSuggestion struct {
+	// The indexes from the passed-in array of terms used to make this
+	// word combination
+	OriginalTermIndexes []int
+	// The word combination suggestion
+	Suggestion *SuggestWord
+}
+
+/*
+A spell checker whose sole function is to offer suggestions by
+combining multiple terms into one word and/or breaking terms into
+multiple words.
+*/
+type WordBreakSpellChecker struct {
+	minSuggestionFrequency int
+	minBreakWordLength     int
+	maxCombineWordLength   int
+	maxChanges             int
+	maxEvaluations         int
+}
+
+func NewWordBreakSpellChecker() *WordBreakSpellChecker {
+	return &WordBreakSpellChecker{
+		minSuggestionFrequency: 1,
+		minBreakWordLength:     1,
+		maxCombineWordLength:   20,
+		maxChanges:             1,
+		maxEvaluations:         1000,
+	}
+}
+
+/*
+Generate suggestions by breaking the passed-in term into multiple
+words. The scores returned are equal to the number of word breaks
+needed so a lower score is generally preferred over a higher score.
+
+SUGGEST_WHEN_NOT_IN_INDEX returns nothing if term is already in the
+index; SUGGEST_MORE_POPULAR only returns words whose frequency is at
+least that of term. Each returned slice is one suggestion: the words
+term should be broken into, in order.
+*/
+func (sc *WordBreakSpellChecker) SuggestWordBreaks(term *index.Term,
+	maxSuggestions int, ir index.IndexReader, mode SuggestMode,
+	sortMethod BreakSuggestionSortMethod) ([][]*SuggestWord, error) {
+
+	if maxSuggestions < 1 {
+		return nil, nil
+	}
+
+	origFreq, err := ir.DocFreq(term)
+	if err != nil {
+		return nil, err
+	}
+	if origFreq > 0 && mode == SUGGEST_WHEN_NOT_IN_INDEX {
+		return nil, nil
+	}
+
+	useMinSuggestionFrequency := sc.minSuggestionFrequency
+	if mode == SUGGEST_MORE_POPULAR {
+		useMinSuggestionFrequency = maxInt(origFreq, 1)
+	}
+
+	var suggestions []*suggestWordArrayWrapper
+	if _, err = sc.generateBreakUpSuggestions(term, ir, 1,
+		useMinSuggestionFrequency, nil, &suggestions, 0); err != nil {
+		return nil, err
+	}
+
+	if sortMethod == NUM_CHANGES_THEN_MAX_FREQUENCY {
+		sort.Stable(lengthThenMaxFreq(suggestions))
+	} else {
+		sort.Stable(lengthThenSumFreq(suggestions))
+	}
+	if len(suggestions) > maxSuggestions {
+		suggestions = suggestions[:maxSuggestions]
+	}
+
+	ans := make([][]*SuggestWord, len(suggestions))
+	for i, s := range suggestions {
+		ans[i] = s.suggestWords
+	}
+	return ans, nil
+}
+
+/*
+Generate suggestions by combining one or more of the passed-in terms
+into single words. The returned CombineSuggestion contains both a
+SuggestWord and also an array detailing which passed-in terms were
+involved in creating this combination. The scores returned are equal
+to the number of word combinations needed, also one less than the
+length of the array OriginalTermIndexes. Generally, a suggestion
+with a lower score is preferred over a higher score.
+
+To prevent two adjacent terms from being combined (for instance, if
+one is mandatory and the other is prohibited), separate the two terms
+with SEPARATOR_TERM.
+
+When mode equals SUGGEST_WHEN_NOT_IN_INDEX, each suggestion will
+include at least one term not in the index.
+
+When mode equals SUGGEST_MORE_POPULAR, each suggestion will have the
+same, or better frequency than the most-popular included term.
+*/
+func (sc *WordBreakSpellChecker) SuggestWordCombinations(terms []*index.Term,
+	maxSuggestions int, ir index.IndexReader, mode SuggestMode) ([]*CombineSuggestion, error) {
+
+	if maxSuggestions < 1 {
+		return nil, nil
+	}
+
+	var origFreqs []int
+	if mode != SUGGEST_ALWAYS {
+		origFreqs = make([]int, len(terms))
+		for i, t := range terms {
+			n, err := ir.DocFreq(t)
+			if err != nil {
+				return nil, err
+			}
+			origFreqs[i] = n
+		}
+	}
+
+	var suggestions []*combineSuggestionWrapper
+
+	thisTimeEvaluations := 0
+	for i := 0; i < len(terms)-1; i++ {
+		if isSeparator(terms[i]) {
+			continue
+		}
+		leftTermText := string(terms[i].Bytes)
+		leftTermLength := utf8.RuneCountInString(leftTermText)
+		if leftTermLength > sc.maxCombineWordLength {
+			continue
+		}
+		maxFreq, minFreq := 0, math.MaxInt32
+		if origFreqs != nil {
+			maxFreq, minFreq = origFreqs[i], origFreqs[i]
+		}
+		combinedTermText := leftTermText
+		combinedLength := leftTermLength
+		for j := i + 1; j < len(terms) && j-i <= sc.maxChanges; j++ {
+			if isSeparator(terms[j]) {
+				break
+			}
+			rightTermText := string(terms[j].Bytes)
+			combinedTermText += rightTermText
+			combinedLength += utf8.RuneCountInString(rightTermText)
+			if combinedLength > sc.maxCombineWordLength {
+				break
+			}
+
+			if origFreqs != nil {
+				maxFreq = maxInt(maxFreq, origFreqs[j])
+				minFreq = minInt(minFreq, origFreqs[j])
+			}
+
+			combinedTerm := index.NewTerm(terms[0].Field, combinedTermText)
+			combinedTermFreq, err := ir.DocFreq(combinedTerm)
+			if err != nil {
+				return nil, err
+			}
+
+			if (mode != SUGGEST_MORE_POPULAR || combinedTermFreq >= maxFreq) &&
+				(mode != SUGGEST_WHEN_NOT_IN_INDEX || minFreq == 0) &&
+				combinedTermFreq >= sc.minSuggestionFrequency {
+
+				origIndexes := make([]int, j-i+1)
+				for k := range origIndexes {
+					origIndexes[k] = i + k
+				}
+				word := &SuggestWord{
+					Freq:   combinedTermFreq,
+					Score:  float32(len(origIndexes) - 1),
+					String: combinedTermText,
+				}
+				suggestions = append(suggestions, &combineSuggestionWrapper{
+					&CombineSuggestion{origIndexes, word}, len(origIndexes) - 1})
+			}
+
+			if thisTimeEvaluations++; thisTimeEvaluations == sc.maxEvaluations {
+				break
+			}
+		}
+	}
+
+	sort.Stable(combinationsThenFreq(suggestions))
+	if len(suggestions) > maxSuggestions {
+		suggestions = suggestions[:maxSuggestions]
+	}
+
+	ans := make([]*CombineSuggestion, len(suggestions))
+	for i, s := range suggestions {
+		ans[i] = s.combineSuggestion
+	}
+	return ans, nil
+}
+
+func (sc *WordBreakSpellChecker) generateBreakUpSuggestions(term *index.Term,
+	ir index.IndexReader, numberBreaks, useMinSuggestionFrequency int,
+	prefix []*SuggestWord, suggestions *[]*suggestWordArrayWrapper,
+	totalEvaluations int) (int, error) {
+
+	termText := []rune(string(term.Bytes))
+	termLength := len(termText)
+	useMinBreakWordLength := maxInt(sc.minBreakWordLength, 1)
+	if termLength < useMinBreakWordLength*2 {
+		return 0, nil
+	}
+
+	thisTimeEvaluations := 0
+	for i := useMinBreakWordLength; i <= termLength-useMinBreakWordLength; i++ {
+		leftText := string(termText[:i])
+		rightText := string(termText[i:])
+		leftWord, err := generateSuggestWord(ir, term.Field, leftText)
+		if err != nil {
+			return 0, err
+		}
+
+		if leftWord.Freq >= useMinSuggestionFrequency {
+			rightWord, err := generateSuggestWord(ir, term.Field, rightText)
+			if err != nil {
+				return 0, err
+			}
+			if rightWord.Freq >= useMinSuggestionFrequency {
+				*suggestions = append(*suggestions,
+					newSuggestWordArrayWrapper(newSuggestion(prefix, leftWord, rightWord)))
+			}
+
+			if newNumberBreaks := numberBreaks + 1; newNumberBreaks <= sc.maxChanges {
+				evaluations, err := sc.generateBreakUpSuggestions(
+					index.NewTerm(term.Field, rightWord.String), ir, newNumberBreaks,
+					useMinSuggestionFrequency, newPrefix(prefix, leftWord),
+					suggestions, totalEvaluations)
+				if err != nil {
+					return 0, err
+				}
+				totalEvaluations += evaluations
+			}
+		}
+
+		thisTimeEvaluations++
+		if totalEvaluations++; totalEvaluations >= sc.maxEvaluations {
+			break
+		}
+	}
+	return thisTimeEvaluations, nil
+}
+
+func newPrefix(oldPrefix []*SuggestWord, word *SuggestWord) []*SuggestWord {
+	ans := make([]*SuggestWord, len(oldPrefix)+1)
+	copy(ans, oldPrefix)
+	ans[len(oldPrefix)] = word
+	return ans
+}
+
+func newSuggestion(prefix []*SuggestWord, append1, append2 *SuggestWord) []*SuggestWord {
+	score := float32(len(prefix) + 1)
+	ans := make([]*SuggestWord, 0, len(prefix)+2)
+	for _, w := range prefix {
+		ans = append(ans, &SuggestWord{Score: score, Freq: w.Freq, String: w.String})
+	}
+	append1.Score = score
+	append2.Score = score
+	return append(ans, append1, append2)
+}
+
+func generateSuggestWord(ir index.IndexReader, fieldname, text string) (*SuggestWord, error) {
+	freq, err := ir.DocFreq(index.NewTerm(fieldname, text))
+	if err != nil {
+		return nil, err
+	}
+	return &SuggestWord{Freq: freq, Score: 1, String: text}, nil
+}
+
+// Returns the minimum frequency a term must have to be suggested
+func (sc *WordBreakSpellChecker) MinSuggestionFrequency() int {
+	return sc.minSuggestionFrequency
+}
+
+/*
+The minimum frequency a term must have to be included as part of a
+suggestion. Default=1. Not applicable when used with
+SUGGEST_MORE_POPULAR.
+*/
+func (sc *WordBreakSpellChecker) SetMinSuggestionFrequency(minSuggestionFrequency int) *WordBreakSpellChecker {
+	sc.minSuggestionFrequency = minSuggestionFrequency
+	return sc
+}
+
+// Returns the maximum length of a combined suggestion
+func (sc *WordBreakSpellChecker) MaxCombineWordLength() int {
+	return sc.maxCombineWordLength
+}
+
+/*
+The maximum length of a suggestion made by combining 1 or more
+original terms. Default=20.
+*/
+func (sc *WordBreakSpellChecker) SetMaxCombineWordLength(maxCombineWordLength int) *WordBreakSpellChecker {
+	sc.maxCombineWordLength = maxCombineWordLength
+	return sc
+}
+
+// Returns the minimum size of a broken word
+func (sc *WordBreakSpellChecker) MinBreakWordLength() int {
+	return sc.minBreakWordLength
+}
+
+// The minimum length to break words down to. Default=1.
+func (sc *WordBreakSpellChecker) SetMinBreakWordLength(minBreakWordLength int) *WordBreakSpellChecker {
+	sc.minBreakWordLength = minBreakWordLength
+	return sc
+}
+
+// Returns the maximum number of changes to make
+func (sc *WordBreakSpellChecker) MaxChanges() int {
+	return sc.maxChanges
+}
+
+/*
+The maximum numbers of changes (word breaks or combinations) to make
+on the original term(s). Default=1.
+*/
+func (sc *WordBreakSpellChecker) SetMaxChanges(maxChanges int) *WordBreakSpellChecker {
+	sc.maxChanges = maxChanges
+	return sc
+}
+
+// Returns the maximum number of word combinations to evaluate
+func (sc *WordBreakSpellChecker) MaxEvaluations() int {
+	return sc.maxEvaluations
+}
+
+/*
+The maximum number of word combinations to evaluate. Default=1000. A
+higher value might improve result quality. A lower value might improve
+performance.
+*/
+func (sc *WordBreakSpellChecker) SetMaxEvaluations(maxEvaluations int) *WordBreakSpellChecker {
+	sc.maxEvaluations = maxEvaluations
+	return sc
+}
+
+type suggestWordArrayWrapper struct {
+	suggestWords []*SuggestWord
+	freqMax      int
+	freqSum      int
+}
+
+func newSuggestWordArrayWrapper(suggestWords []*SuggestWord) *suggestWordArrayWrapper {
+	ans := &suggestWordArrayWrapper{suggestWords: suggestWords}
+	for _, w := range suggestWords {
+		ans.freqSum += w.Freq
+		ans.freqMax = maxInt(ans.freqMax, w.Freq)
+	}
+	return ans
+}
+
+// Fewest word breaks first, then highest maximum frequency
+type lengthThenMaxFreq []*suggestWordArrayWrapper
+
+func (s lengthThenMaxFreq) Len() int      { return len(s) }
+func (s lengthThenMaxFreq) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s lengthThenMaxFreq) Less(i, j int) bool {
+	if len(s[i].suggestWords) != len(s[j].suggestWords) {
+		return len(s[i].suggestWords) < len(s[j].suggestWords)
+	}
+	return s[i].freqMax > s[j].freqMax
+}
+
+// Fewest word breaks first, then highest summed frequency
+type lengthThenSumFreq []*suggestWordArrayWrapper
+
+func (s lengthThenSumFreq) Len() int      { return len(s) }
+func (s lengthThenSumFreq) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s lengthThenSumFreq) Less(i, j int) bool {
+	if len(s[i].suggestWords) != len(s[j].suggestWords) {
+		return len(s[i].suggestWords) < len(s[j].suggestWords)
+	}
+	return s[i].freqSum > s[j].freqSum
+}
+
+type combineSuggestionWrapper struct {
+	combineSuggestion *CombineSuggestion
+	numCombinations   int
+}
+
+// Fewest combinations first, then highest frequency
+type combinationsThenFreq []*combineSuggestionWrapper
+
+func (s combinationsThenFreq) Len() int      { return len(s) }
+func (s combinationsThenFreq) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s combinationsThenFreq) Less(i, j int) bool {
+	if s[i].numCombinations != s[j].numCombinations {
+		return s[i].numCombinations < s[j].numCombinations
+	}
+	return s[i].combineSuggestion.Suggestion.Freq > s[j].combineSuggestion.Suggestion.Freq
+}
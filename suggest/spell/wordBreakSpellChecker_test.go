@@ -0,0 +1,168 @@
+package spell
+
+import (
+	"github.com/balzaczyy/golucene/core/index"
+	"testing"
+)
+
+func newWordBreakReader(t *testing.T) index.IndexReader {
+	values := make([]string, 0, 1000)
+	for i := 900; i < 1000; i++ {
+		values = append(values, intToEnglish(i))
+	}
+	return newReader(t, "numbers", append(values,
+		"thou hast sand betwixt thy toes",
+		"hundredeight eightyeight yeight",
+		"tres y cinco")...)
+}
+
+func assertWords(t *testing.T, words []*SuggestWord, expected ...string) {
+	if len(words) != len(expected) {
+		t.Errorf("expected %v, but was %v", expected, words)
+		return
+	}
+	for i, w := range words {
+		if w.String != expected[i] {
+			t.Errorf("expected %v, but was %v at %v", expected[i], w.String, i)
+		}
+	}
+}
+
+func TestSuggestWordBreaks(t *testing.T) {
+	ir := newWordBreakReader(t)
+	wbsp := NewWordBreakSpellChecker()
+
+	sw, err := wbsp.SuggestWordBreaks(index.NewTerm("numbers", "ninetynine"),
+		10, ir, SUGGEST_WHEN_NOT_IN_INDEX, NUM_CHANGES_THEN_MAX_FREQUENCY)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sw) != 1 {
+		t.Fatalf("expected one suggestion, but was %v", sw)
+	}
+	assertWords(t, sw[0], "ninety", "nine")
+	if sw[0][0].Score != 1 || sw[0][1].Score != 1 {
+		t.Errorf("one break should score 1, but was %v", sw[0][0].Score)
+	}
+
+	sw, err = wbsp.SuggestWordBreaks(index.NewTerm("numbers", "onehundredeight"),
+		10, ir, SUGGEST_WHEN_NOT_IN_INDEX, NUM_CHANGES_THEN_MAX_FREQUENCY)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sw) != 1 {
+		t.Fatalf("expected one suggestion, but was %v", sw)
+	}
+	assertWords(t, sw[0], "one", "hundredeight")
+
+	wbsp.SetMaxChanges(2).SetMinSuggestionFrequency(1)
+	sw, err = wbsp.SuggestWordBreaks(index.NewTerm("numbers", "onehundredeight"),
+		10, ir, SUGGEST_WHEN_NOT_IN_INDEX, NUM_CHANGES_THEN_MAX_FREQUENCY)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sw) != 2 {
+		t.Fatalf("expected two suggestions, but was %v", sw)
+	}
+	assertWords(t, sw[0], "one", "hundredeight")
+	assertWords(t, sw[1], "one", "hundred", "eight")
+	if sw[1][0].Score != 2 {
+		t.Errorf("two breaks should score 2, but was %v", sw[1][0].Score)
+	}
+
+	// words must be long enough
+	wbsp.SetMinBreakWordLength(3)
+	sw, err = wbsp.SuggestWordBreaks(index.NewTerm("numbers", "ninetynine"),
+		10, ir, SUGGEST_WHEN_NOT_IN_INDEX, NUM_CHANGES_THEN_MAX_FREQUENCY)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sw) != 1 {
+		t.Fatalf("expected one suggestion, but was %v", sw)
+	}
+	assertWords(t, sw[0], "ninety", "nine")
+
+	// the term is in the index
+	sw, err = wbsp.SuggestWordBreaks(index.NewTerm("numbers", "eightyeight"),
+		10, ir, SUGGEST_WHEN_NOT_IN_INDEX, NUM_CHANGES_THEN_MAX_FREQUENCY)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sw) != 0 {
+		t.Errorf("no suggestion expected for an indexed term, but was %v", sw)
+	}
+	sw, err = wbsp.SuggestWordBreaks(index.NewTerm("numbers", "eightyeight"),
+		10, ir, SUGGEST_MORE_POPULAR, NUM_CHANGES_THEN_MAX_FREQUENCY)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sw) != 2 {
+		t.Fatalf("expected two suggestions, but was %v", sw)
+	}
+	assertWords(t, sw[0], "eighty", "eight")
+	assertWords(t, sw[1], "eight", "yeight")
+}
+
+func TestSuggestWordCombinations(t *testing.T) {
+	ir := newWordBreakReader(t)
+	wbsp := NewWordBreakSpellChecker()
+
+	terms := []*index.Term{
+		index.NewTerm("numbers", "one"),
+		index.NewTerm("numbers", "hundred"),
+		index.NewTerm("numbers", "eight"),
+		index.NewTerm("numbers", "y"),
+		index.NewTerm("numbers", "eight"),
+	}
+	wbsp.SetMaxChanges(3).SetMaxCombineWordLength(20).SetMinSuggestionFrequency(1)
+	cs, err := wbsp.SuggestWordCombinations(terms, 10, ir, SUGGEST_ALWAYS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cs) != 4 {
+		t.Fatalf("expected four suggestions, but was %v", cs)
+	}
+
+	// fewest combinations first, then most frequent
+	expected := []struct {
+		word    string
+		indexes []int
+	}{
+		{"eighty", []int{2, 3}},
+		{"hundredeight", []int{1, 2}},
+		{"yeight", []int{3, 4}},
+		{"eightyeight", []int{2, 3, 4}},
+	}
+	for i, e := range expected {
+		s := cs[i]
+		if s.Suggestion.String != e.word {
+			t.Errorf("expected %v, but was %v", e.word, s.Suggestion.String)
+		}
+		if s.Suggestion.Score != float32(len(e.indexes)-1) {
+			t.Errorf("%v should score %v, but was %v", e.word, len(e.indexes)-1, s.Suggestion.Score)
+		}
+		if len(s.OriginalTermIndexes) != len(e.indexes) {
+			t.Errorf("expected indexes %v, but was %v", e.indexes, s.OriginalTermIndexes)
+			continue
+		}
+		for j, v := range e.indexes {
+			if s.OriginalTermIndexes[j] != v {
+				t.Errorf("expected indexes %v, but was %v", e.indexes, s.OriginalTermIndexes)
+			}
+		}
+	}
+
+	// a separator prevents combining adjacent terms
+	terms = []*index.Term{
+		index.NewTerm("numbers", "thou"),
+		SEPARATOR_TERM,
+		index.NewTerm("numbers", "sand"),
+	}
+	cs, err = wbsp.SuggestWordCombinations(terms, 5, ir, SUGGEST_ALWAYS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cs) != 0 {
+		t.Errorf("no combination expected across a separator, but was %v", cs)
+	}
+}